@@ -0,0 +1,120 @@
+package agent
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+	"tailscale.com/types/netlogtype"
+
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// activityTracker keeps the last time each kind of activity was reported, so
+// that every report only contains activity that happened since the previous
+// one.
+type activityTracker struct {
+	mu       sync.Mutex
+	reported map[codersdk.AgentActivitySource]time.Time
+}
+
+func newActivityTracker() *activityTracker {
+	return &activityTracker{
+		reported: map[codersdk.AgentActivitySource]time.Time{},
+	}
+}
+
+// Report returns the activity that is newer than the last report of its
+// source, or nil if there is none.
+func (t *activityTracker) Report(observed map[codersdk.AgentActivitySource]time.Time) map[codersdk.AgentActivitySource]time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var activity map[codersdk.AgentActivitySource]time.Time
+	for source, at := range observed {
+		if at.IsZero() || !at.After(t.reported[source]) {
+			continue
+		}
+		if activity == nil {
+			activity = map[codersdk.AgentActivitySource]time.Time{}
+		}
+		activity[source] = at
+		t.reported[source] = at
+	}
+	return activity
+}
+
+// networkActivity classifies the connections that received traffic from a
+// client into app and port forward activity. Connections to ports used by
+// the agent itself are skipped, SSH and reconnecting PTY activity is based on
+// input instead.
+func networkActivity(networkStats map[netlogtype.Connection]netlogtype.Counts, manifest *agentsdk.Manifest, now time.Time) map[codersdk.AgentActivitySource]time.Time {
+	appPorts := map[uint16]struct{}{}
+	if manifest != nil {
+		for _, app := range manifest.Apps {
+			if port, ok := appPort(app); ok {
+				appPorts[port] = struct{}{}
+			}
+		}
+	}
+
+	activity := map[codersdk.AgentActivitySource]time.Time{}
+	for conn, counts := range networkStats {
+		// Src is the address of the agent for both directions.
+		port := conn.Src.Port()
+		if counts.RxBytes == 0 || port < codersdk.WorkspaceAgentMinimumListeningPort {
+			continue
+		}
+		if _, ok := appPorts[port]; ok {
+			activity[codersdk.AgentActivitySourceApp] = now
+		} else {
+			activity[codersdk.AgentActivitySourcePortForward] = now
+		}
+	}
+	return activity
+}
+
+// appPort returns the local port an app proxies to.
+func appPort(app codersdk.WorkspaceApp) (uint16, bool) {
+	if app.External || app.URL == "" {
+		return 0, false
+	}
+	u, err := url.Parse(app.URL)
+	if err != nil {
+		return 0, false
+	}
+	portStr := u.Port()
+	if portStr == "" {
+		switch u.Scheme {
+		case "http":
+			portStr = "80"
+		case "https":
+			portStr = "443"
+		default:
+			return 0, false
+		}
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return 0, false
+	}
+	return uint16(port), true
+}
+
+// inputConn records the time of every read with data into lastInput.
+type inputConn struct {
+	net.Conn
+	lastInput *atomic.Int64
+}
+
+func (c *inputConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 {
+		c.lastInput.Store(time.Now().UnixNano())
+	}
+	return n, err
+}
@@ -20,12 +20,14 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf16"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/afero"
 	"go.uber.org/atomic"
+	gossh "golang.org/x/crypto/ssh"
 	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
@@ -51,6 +53,10 @@ const (
 	ProtocolDial            = "dial"
 )
 
+// maxRetryAfter caps how long the agent waits when coderd asks it to come
+// back later, so a misbehaving server can't park agents indefinitely.
+const maxRetryAfter = 10 * time.Minute
+
 type Options struct {
 	Filesystem                   afero.Fs
 	LogDir                       string
@@ -66,8 +72,12 @@ type Options struct {
 	Subsystems                   []codersdk.AgentSubsystem
 	Addresses                    []netip.Prefix
 	PrometheusRegistry           *prometheus.Registry
+	PrometheusScrapeTargets      []string
 	ReportMetadataInterval       time.Duration
 	ServiceBannerRefreshInterval time.Duration
+	// AuthTokenRotated is called with the new auth token after the agent
+	// rotated it, so it can be persisted for restarts of the agent.
+	AuthTokenRotated func(authToken string)
 }
 
 type Client interface {
@@ -81,6 +91,8 @@ type Client interface {
 	PostMetadata(ctx context.Context, key string, req agentsdk.PostMetadataRequest) error
 	PatchLogs(ctx context.Context, req agentsdk.PatchLogs) error
 	GetServiceBanner(ctx context.Context) (codersdk.ServiceBannerConfig, error)
+	BinaryChecksum(ctx context.Context, goos, goarch string) (string, error)
+	RotateAuthToken(ctx context.Context) (agentsdk.RotateAuthTokenResponse, error)
 }
 
 type Agent interface {
@@ -138,14 +150,17 @@ func New(options Options) Agent {
 		lifecycleStates:              []agentsdk.PostLifecycleRequest{{State: codersdk.WorkspaceAgentLifecycleCreated}},
 		ignorePorts:                  options.IgnorePorts,
 		connStatsChan:                make(chan *agentsdk.Stats, 1),
+		activity:                     newActivityTracker(),
 		reportMetadataInterval:       options.ReportMetadataInterval,
 		serviceBannerRefreshInterval: options.ServiceBannerRefreshInterval,
 		sshMaxTimeout:                options.SSHMaxTimeout,
 		subsystems:                   options.Subsystems,
 		addresses:                    options.Addresses,
+		authTokenRotated:             options.AuthTokenRotated,
 
-		prometheusRegistry: prometheusRegistry,
-		metrics:            newAgentMetrics(prometheusRegistry),
+		prometheusRegistry:      prometheusRegistry,
+		prometheusScrapeTargets: options.PrometheusScrapeTargets,
+		metrics:                 newAgentMetrics(prometheusRegistry),
 	}
 	a.init(ctx)
 	return a
@@ -165,8 +180,11 @@ type agent struct {
 	ignorePorts map[int]string
 	subsystems  []codersdk.AgentSubsystem
 
-	reconnectingPTYs       sync.Map
-	reconnectingPTYTimeout time.Duration
+	reconnectingPTYs sync.Map
+	// reconnectingPTYSessions describes the sessions in reconnectingPTYs, so
+	// servers can tell whether a reconnect resumes a session.
+	reconnectingPTYSessions sync.Map // map[uuid.UUID]codersdk.WorkspaceAgentReconnectingPTYSession
+	reconnectingPTYTimeout  time.Duration
 
 	connCloseWait sync.WaitGroup
 	closeCancel   context.CancelFunc
@@ -180,6 +198,7 @@ type agent struct {
 	serviceBanner                atomic.Pointer[codersdk.ServiceBannerConfig] // serviceBanner is atomic because it is periodically updated.
 	serviceBannerRefreshInterval time.Duration
 	sessionToken                 atomic.Pointer[string]
+	authTokenRotated             func(authToken string)
 	sshServer                    *agentssh.Server
 	sshMaxTimeout                time.Duration
 
@@ -194,9 +213,14 @@ type agent struct {
 	latestStat    atomic.Pointer[agentsdk.Stats]
 
 	connCountReconnectingPTY atomic.Int64
-
-	prometheusRegistry *prometheus.Registry
-	metrics            *agentMetrics
+	// lastInputReconnectingPTY is the unix time in nanoseconds of the last
+	// input received from a reconnecting PTY connection.
+	lastInputReconnectingPTY atomic.Int64
+	activity                 *activityTracker
+
+	prometheusRegistry      *prometheus.Registry
+	prometheusScrapeTargets []string
+	metrics                 *agentMetrics
 }
 
 func (a *agent) TailnetConn() *tailnet.Conn {
@@ -217,6 +241,36 @@ func (a *agent) init(ctx context.Context) {
 	go a.runLoop(ctx)
 }
 
+// rotateAuthTokenLoop replaces the auth token of the agent every interval.
+// coderd keeps accepting the previous token for a while, so connections that
+// are established with it keep working until they reconnect.
+func (a *agent) rotateAuthTokenLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		resp, err := a.client.RotateAuthToken(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			a.logger.Warn(ctx, "rotate auth token", slog.Error(err))
+			continue
+		}
+		authToken := resp.AuthToken.String()
+		a.sessionToken.Store(&authToken)
+		if a.authTokenRotated != nil {
+			a.authTokenRotated(authToken)
+		}
+		a.logger.Info(ctx, "rotated auth token", slog.F("previous_auth_token_expires_at", resp.PreviousAuthTokenExpiresAt))
+	}
+}
+
 // runLoop attempts to start the agent in a retry loop.
 // Coder may be offline temporarily, a connection issue
 // may be happening, but regardless after the intermittent
@@ -245,6 +299,25 @@ func (a *agent) runLoop(ctx context.Context) {
 			a.logger.Info(ctx, "disconnected from coderd")
 			continue
 		}
+		var sdkErr *codersdk.Error
+		if errors.As(err, &sdkErr) && sdkErr.RetryAfter() > 0 {
+			// coderd is pacing reconnecting agents, so wait for the slot
+			// it assigned instead of hammering it with retries.
+			wait := sdkErr.RetryAfter()
+			if wait > maxRetryAfter {
+				wait = maxRetryAfter
+			}
+			a.logger.Info(ctx, "coderd asked to retry later", slog.F("retry_after", wait))
+			t := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return
+			case <-t.C:
+			}
+			retrier.Reset()
+			continue
+		}
 		a.logger.Warn(ctx, "run exited with error", slog.Error(err))
 	}
 }
@@ -295,10 +368,31 @@ func (a *agent) collectMetadata(ctx context.Context, md codersdk.WorkspaceAgentM
 	if err != nil {
 		result.Error = fmt.Sprintf("run cmd: %+v", err)
 	}
-	result.Value = out.String()
+	result.Value = decodeScriptOutput(out.Bytes())
 	return result
 }
 
+// decodeScriptOutput returns the output of a script as UTF-8. Windows tools
+// such as wmic and PowerShell's Out-File write UTF-16 with a byte order mark,
+// which would otherwise be reported with a NUL between every character.
+func decodeScriptOutput(b []byte) string {
+	var order binary.ByteOrder
+	switch {
+	case bytes.HasPrefix(b, []byte{0xff, 0xfe}):
+		order = binary.LittleEndian
+	case bytes.HasPrefix(b, []byte{0xfe, 0xff}):
+		order = binary.BigEndian
+	default:
+		return string(bytes.TrimPrefix(b, []byte{0xef, 0xbb, 0xbf}))
+	}
+	b = b[2:]
+	u16 := make([]uint16, len(b)/2)
+	for i := range u16 {
+		u16[i] = order.Uint16(b[i*2:])
+	}
+	return string(utf16.Decode(u16))
+}
+
 type metadataResultAndKey struct {
 	result *codersdk.WorkspaceAgentMetadataResult
 	key    string
@@ -587,7 +681,9 @@ func (a *agent) run(ctx context.Context) error {
 	if err != nil {
 		return xerrors.Errorf("fetch metadata: %w", err)
 	}
-	a.logger.Info(ctx, "fetched manifest", slog.F("manifest", manifest))
+	loggedManifest := manifest
+	loggedManifest.SSHHostKey = "" // Keep the private key out of the logs.
+	a.logger.Info(ctx, "fetched manifest", slog.F("manifest", loggedManifest))
 
 	if manifest.AgentID == uuid.Nil {
 		return xerrors.New("nil agentID returned by manifest")
@@ -606,17 +702,46 @@ func (a *agent) run(ctx context.Context) error {
 		Version:           buildinfo.Version(),
 		ExpandedDirectory: manifest.Directory,
 		Subsystems:        a.subsystems,
+		HostFacts:         hostFacts(),
 	})
 	if err != nil {
 		return xerrors.Errorf("update workspace agent version: %w", err)
 	}
 
+	// Older deployments don't persist host keys, the agent keeps its random
+	// key then.
+	if manifest.SSHHostKey != "" {
+		hostKey, err := gossh.ParsePrivateKey([]byte(manifest.SSHHostKey))
+		if err != nil {
+			a.logger.Warn(ctx, "parse ssh host key", slog.Error(err))
+		} else {
+			a.sshServer.SetHostKey(hostKey)
+			// The certificate lets clients that trust the certificate
+			// authority of the deployment verify the host key.
+			if manifest.SSHHostCertificate != "" {
+				certSigner, err := sshHostCertificateSigner(hostKey, manifest.SSHHostCertificate)
+				if err != nil {
+					a.logger.Warn(ctx, "parse ssh host certificate", slog.Error(err))
+				} else {
+					a.sshServer.SetHostKey(certSigner)
+				}
+			}
+		}
+	}
+
 	oldManifest := a.manifest.Swap(&manifest)
 
 	// The startup script should only execute on the first run!
 	if oldManifest == nil {
 		a.setLifecycle(ctx, codersdk.WorkspaceAgentLifecycleStarting)
 
+		// The startup script may need to resolve internal hosts.
+		a.applyDNSConfig(ctx, manifest)
+
+		if manifest.AuthTokenRotationInterval > 0 {
+			go a.rotateAuthTokenLoop(ctx, manifest.AuthTokenRotationInterval)
+		}
+
 		// Perform overrides early so that Git auth can work even if users
 		// connect to a workspace that is not yet ready. We don't run this
 		// concurrently with the startup script to avoid conflicts between
@@ -635,6 +760,13 @@ func (a *agent) run(ctx context.Context) error {
 		scriptDone := make(chan error, 1)
 		err = a.trackConnGoroutine(func() {
 			defer close(scriptDone)
+			if manifest.RequireBinaryVerification {
+				err := a.verifyBinary(ctx)
+				if err != nil {
+					scriptDone <- err
+					return
+				}
+			}
 			scriptDone <- a.runStartupScript(ctx, manifest.StartupScript)
 		})
 		if err != nil {
@@ -764,6 +896,10 @@ func (a *agent) createTailnet(ctx context.Context, agentID uuid.UUID, derpMap *t
 		Logger:         a.logger.Named("net.tailnet"),
 		ListenPort:     a.tailnetListenPort,
 		BlockEndpoints: disableDirectConnections,
+		ForwardTCPFilter: func(port uint16) bool {
+			allowed, _ := codersdk.CheckPortPolicies(port, a.portPolicies()...)
+			return allowed
+		},
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("create tailnet: %w", err)
@@ -1109,6 +1245,14 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		}
 		c <- rpty // Put it back for the next reconnect.
 	} else {
+		if msg.Resume {
+			// The session has ended. Starting a new one in its place would
+			// silently lose the state of the terminal.
+			connLogger.Debug(ctx, "reconnecting pty to resume not found")
+			a.reconnectingPTYs.Delete(msg.ID)
+			close(sendConnected)
+			return nil
+		}
 		connLogger.Debug(ctx, "creating new reconnecting pty")
 
 		connected := false
@@ -1131,10 +1275,17 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 			Metrics: a.metrics.reconnectingPTYErrors,
 		}, logger.With(slog.F("message_id", msg.ID)))
 
+		a.reconnectingPTYSessions.Store(msg.ID, codersdk.WorkspaceAgentReconnectingPTYSession{
+			ID:        msg.ID,
+			Command:   msg.Command,
+			CreatedAt: time.Now(),
+		})
 		if err = a.trackConnGoroutine(func() {
 			rpty.Wait()
+			a.reconnectingPTYSessions.Delete(msg.ID)
 			a.reconnectingPTYs.Delete(msg.ID)
 		}); err != nil {
+			a.reconnectingPTYSessions.Delete(msg.ID)
 			rpty.Close(err)
 			return xerrors.Errorf("start routine: %w", err)
 		}
@@ -1142,7 +1293,7 @@ func (a *agent) handleReconnectingPTY(ctx context.Context, logger slog.Logger, m
 		connected = true
 		sendConnected <- rpty
 	}
-	return rpty.Attach(ctx, connectionID, conn, msg.Height, msg.Width, connLogger)
+	return rpty.Attach(ctx, connectionID, &inputConn{Conn: conn, lastInput: &a.lastInputReconnectingPTY}, msg.Height, msg.Width, connLogger)
 }
 
 // startReportingConnectionStats runs the connection stats reporting goroutine.
@@ -1159,6 +1310,7 @@ func (a *agent) startReportingConnectionStats(ctx context.Context) {
 			stats.TxBytes += int64(counts.TxBytes)
 			stats.TxPackets += int64(counts.TxPackets)
 		}
+		stats.ConnectionBandwidth = connectionBandwidth(networkStats)
 
 		// The count of active sessions.
 		sshStats := a.sshServer.ConnStats()
@@ -1168,6 +1320,14 @@ func (a *agent) startReportingConnectionStats(ctx context.Context) {
 
 		stats.SessionCountReconnectingPTY = a.connCountReconnectingPTY.Load()
 
+		// Report the kinds of activity observed since the last report.
+		observed := networkActivity(networkStats, a.manifest.Load(), time.Now())
+		observed[codersdk.AgentActivitySourceSSH] = sshStats.LastInput
+		if lastInput := a.lastInputReconnectingPTY.Load(); lastInput != 0 {
+			observed[codersdk.AgentActivitySourceReconnectingPTY] = time.Unix(0, lastInput)
+		}
+		stats.Activity = a.activity.Report(observed)
+
 		// Compute the median connection latency!
 		var wg sync.WaitGroup
 		var mu sync.Mutex
@@ -1250,6 +1410,63 @@ func (a *agent) startReportingConnectionStats(ctx context.Context) {
 	}
 }
 
+// connectionBandwidth groups the traffic of connections by the service on
+// the agent they were made to and the peer they came from.
+func connectionBandwidth(networkStats map[netlogtype.Connection]netlogtype.Counts) []agentsdk.ConnectionBandwidth {
+	type bandwidthKey struct {
+		typ  agentsdk.ConnectionType
+		peer netip.Addr
+		port uint16
+	}
+	byKey := map[bandwidthKey]*agentsdk.ConnectionBandwidth{}
+	for conn, counts := range networkStats {
+		if counts.RxBytes == 0 && counts.TxBytes == 0 {
+			continue
+		}
+		// Virtual traffic is recorded from the perspective of the agent,
+		// so the source is the agent and the destination is the peer.
+		key := bandwidthKey{peer: conn.Dst.Addr()}
+		switch conn.Src.Port() {
+		case codersdk.WorkspaceAgentSSHPort:
+			key.typ = agentsdk.ConnectionTypeSSH
+		case codersdk.WorkspaceAgentReconnectingPTYPort:
+			key.typ = agentsdk.ConnectionTypeReconnectingPTY
+		case codersdk.WorkspaceAgentSpeedtestPort:
+			key.typ = agentsdk.ConnectionTypeSpeedtest
+		default:
+			key.typ = agentsdk.ConnectionTypePortForward
+			key.port = conn.Src.Port()
+		}
+		bandwidth, ok := byKey[key]
+		if !ok {
+			bandwidth = &agentsdk.ConnectionBandwidth{
+				Type: key.typ,
+				Peer: key.peer.String(),
+				Port: key.port,
+			}
+			byKey[key] = bandwidth
+		}
+		bandwidth.RxBytes += int64(counts.RxBytes)
+		bandwidth.TxBytes += int64(counts.TxBytes)
+	}
+
+	bandwidths := make([]agentsdk.ConnectionBandwidth, 0, len(byKey))
+	for _, bandwidth := range byKey {
+		bandwidths = append(bandwidths, *bandwidth)
+	}
+	sort.Slice(bandwidths, func(i, j int) bool {
+		a, b := bandwidths[i], bandwidths[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Peer != b.Peer {
+			return a.Peer < b.Peer
+		}
+		return a.Port < b.Port
+	})
+	return bandwidths
+}
+
 // isClosed returns whether the API is closed or not.
 func (a *agent) isClosed() bool {
 	select {
@@ -1410,6 +1627,20 @@ func userHomeDir() (string, error) {
 	return u.HomeDir, nil
 }
 
+// sshHostCertificateSigner returns a signer presenting the certificate in
+// the authorized_keys format for the host key.
+func sshHostCertificateSigner(hostKey gossh.Signer, rawCert string) (gossh.Signer, error) {
+	publicKey, _, _, _, err := gossh.ParseAuthorizedKey([]byte(rawCert))
+	if err != nil {
+		return nil, xerrors.Errorf("parse certificate: %w", err)
+	}
+	cert, ok := publicKey.(*gossh.Certificate)
+	if !ok {
+		return nil, xerrors.Errorf("expected certificate, got %s key", publicKey.Type())
+	}
+	return gossh.NewCertSigner(cert, hostKey)
+}
+
 // expandDirectory converts a directory path to an absolute path.
 // It primarily resolves the home directory and any environment
 // variables that may be set
@@ -3,6 +3,8 @@ package agent_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -123,6 +125,117 @@ func TestAgent_Stats_ReconnectingPTY(t *testing.T) {
 	)
 }
 
+func TestAgent_Stats_Activity(t *testing.T) {
+	t.Parallel()
+
+	// awaitActivity waits for reports of all the given kinds of activity.
+	awaitActivity := func(t *testing.T, stats <-chan *agentsdk.Stats, sources ...codersdk.AgentActivitySource) {
+		seen := map[codersdk.AgentActivitySource]time.Time{}
+		require.Eventuallyf(t, func() bool {
+			s, ok := <-stats
+			if !ok {
+				return false
+			}
+			for source, at := range s.Activity {
+				seen[source] = at
+			}
+			for _, source := range sources {
+				if seen[source].IsZero() {
+					return false
+				}
+			}
+			return true
+		}, testutil.WaitLong, testutil.IntervalFast,
+			"never saw %v activity: %+v", sources, seen,
+		)
+	}
+
+	t.Run("SSH", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		//nolint:dogsled
+		conn, _, stats, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		sshClient, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		defer sshClient.Close()
+		session, err := sshClient.NewSession()
+		require.NoError(t, err)
+		defer session.Close()
+		stdin, err := session.StdinPipe()
+		require.NoError(t, err)
+		err = session.Shell()
+		require.NoError(t, err)
+
+		_, err = stdin.Write([]byte("echo test\n"))
+		require.NoError(t, err)
+		awaitActivity(t, stats, codersdk.AgentActivitySourceSSH)
+		_ = stdin.Close()
+		err = session.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("ReconnectingPTY", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		//nolint:dogsled
+		conn, _, stats, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+		ptyConn, err := conn.ReconnectingPTY(ctx, uuid.New(), 128, 128, "bash")
+		require.NoError(t, err)
+		defer ptyConn.Close()
+
+		data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+			Data: "echo test\r\n",
+		})
+		require.NoError(t, err)
+		_, err = ptyConn.Write(data)
+		require.NoError(t, err)
+		awaitActivity(t, stats, codersdk.AgentActivitySourceReconnectingPTY)
+	})
+
+	t.Run("AppAndPortForward", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		listen := func() net.Listener {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = l.Close() })
+			go func() {
+				for {
+					c, err := l.Accept()
+					if err != nil {
+						return
+					}
+					go testAccept(t, c)
+				}
+			}()
+			return l
+		}
+		app := listen()
+		other := listen()
+
+		//nolint:dogsled
+		conn, _, stats, _, _ := setupAgent(t, agentsdk.Manifest{
+			Apps: []codersdk.WorkspaceApp{{
+				Slug: "app",
+				URL:  "http://" + app.Addr().String(),
+			}},
+		}, 0)
+		for _, l := range []net.Listener{app, other} {
+			c, err := conn.DialContext(ctx, l.Addr().Network(), l.Addr().String())
+			require.NoError(t, err)
+			defer c.Close()
+			testDial(t, c)
+		}
+		awaitActivity(t, stats, codersdk.AgentActivitySourceApp, codersdk.AgentActivitySourcePortForward)
+	})
+}
+
 func TestAgent_Stats_Magic(t *testing.T) {
 	t.Parallel()
 	t.Run("StripsEnvironmentVariable", func(t *testing.T) {
@@ -1202,6 +1315,34 @@ func TestAgent_Metadata(t *testing.T) {
 			t.Fatalf("expected metadata to be collected again")
 		}
 	})
+
+	t.Run("UTF16", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("The script relies on a POSIX shell")
+		}
+		//nolint:dogsled
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			Metadata: []codersdk.WorkspaceAgentMetadataDescription{
+				{
+					Key:      "greeting",
+					Interval: 0,
+					// "hi" in UTF-16LE with a byte order mark, as written by
+					// many Windows tools.
+					Script: `printf '\377\376h\000i\000'`,
+				},
+			},
+		}, 0, func(_ *agenttest.Client, opts *agent.Options) {
+			opts.ReportMetadataInterval = testutil.IntervalFast
+		})
+
+		var gotMd map[string]agentsdk.PostMetadataRequest
+		require.Eventually(t, func() bool {
+			gotMd = client.GetMetadata()
+			return len(gotMd) == 1
+		}, testutil.WaitShort, testutil.IntervalFast)
+		require.Equal(t, "hi", gotMd["greeting"].Value)
+	})
 }
 
 func TestAgentMetadata_Timing(t *testing.T) {
@@ -1349,6 +1490,74 @@ func TestAgent_Lifecycle(t *testing.T) {
 		require.Equal(t, want, got)
 	})
 
+	t.Run("BinaryVerificationFailed", func(t *testing.T) {
+		t.Parallel()
+
+		marker := filepath.Join(t.TempDir(), "ran")
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			StartupScript:             "touch " + marker,
+			StartupScriptTimeout:      30 * time.Second,
+			RequireBinaryVerification: true,
+		}, 0, func(c *agenttest.Client, _ *agent.Options) {
+			c.BinaryChecksumFunc = func(_, _ string) (string, error) {
+				return strings.Repeat("0", 64), nil
+			}
+		})
+
+		want := []codersdk.WorkspaceAgentLifecycle{
+			codersdk.WorkspaceAgentLifecycleStarting,
+			codersdk.WorkspaceAgentLifecycleStartError,
+		}
+
+		var got []codersdk.WorkspaceAgentLifecycle
+		assert.Eventually(t, func() bool {
+			got = client.GetLifecycleStates()
+			return slices.Contains(got, want[len(want)-1])
+		}, testutil.WaitShort, testutil.IntervalMedium)
+
+		require.Equal(t, want, got[:len(want)])
+		require.NoFileExists(t, marker, "startup script must not run")
+		logs := client.GetStartupLogs()
+		require.Len(t, logs, 1)
+		require.Equal(t, codersdk.LogLevelError, logs[0].Level)
+		require.Contains(t, logs[0].Output, "checksum")
+	})
+
+	t.Run("BinaryVerified", func(t *testing.T) {
+		t.Parallel()
+
+		executable, err := os.Executable()
+		require.NoError(t, err)
+		content, err := os.ReadFile(executable)
+		require.NoError(t, err)
+		checksum := sha256.Sum256(content)
+
+		_, client, _, _, _ := setupAgent(t, agentsdk.Manifest{
+			StartupScript:             "true",
+			StartupScriptTimeout:      30 * time.Second,
+			RequireBinaryVerification: true,
+		}, 0, func(c *agenttest.Client, _ *agent.Options) {
+			c.BinaryChecksumFunc = func(goos, goarch string) (string, error) {
+				assert.Equal(t, runtime.GOOS, goos)
+				assert.Equal(t, runtime.GOARCH, goarch)
+				return hex.EncodeToString(checksum[:]), nil
+			}
+		})
+
+		want := []codersdk.WorkspaceAgentLifecycle{
+			codersdk.WorkspaceAgentLifecycleStarting,
+			codersdk.WorkspaceAgentLifecycleReady,
+		}
+
+		var got []codersdk.WorkspaceAgentLifecycle
+		assert.Eventually(t, func() bool {
+			got = client.GetLifecycleStates()
+			return len(got) > 0 && got[len(got)-1] == want[len(want)-1]
+		}, testutil.WaitShort, testutil.IntervalMedium)
+
+		require.Equal(t, want, got)
+	})
+
 	t.Run("ShuttingDown", func(t *testing.T) {
 		t.Parallel()
 
@@ -1521,6 +1730,58 @@ func TestAgent_Lifecycle(t *testing.T) {
 	})
 }
 
+func TestAgent_DNSConfig(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS != "linux" {
+		t.Skip("DNS configuration is only applied on Linux")
+	}
+
+	t.Run("Applied", func(t *testing.T) {
+		t.Parallel()
+
+		_, client, _, fs, _ := setupAgent(t, agentsdk.Manifest{
+			StartupScript:        "true",
+			StartupScriptTimeout: 30 * time.Second,
+			DNSSearchDomains:     []string{"corp.example.com"},
+			DNSNameservers:       []string{"10.0.0.53", "10.0.1.53"},
+		}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			err := afero.WriteFile(o.Filesystem, "/etc/resolv.conf", []byte("# managed by docker\nsearch example.com\nnameserver 8.8.8.8\noptions ndots:0\n"), 0o644)
+			require.NoError(t, err)
+		})
+
+		require.Eventually(t, func() bool {
+			return slices.Contains(client.GetLifecycleStates(), codersdk.WorkspaceAgentLifecycleReady)
+		}, testutil.WaitShort, testutil.IntervalMedium)
+
+		content, err := afero.ReadFile(fs, "/etc/resolv.conf")
+		require.NoError(t, err)
+		require.Equal(t, "search corp.example.com example.com\nnameserver 10.0.0.53\nnameserver 10.0.1.53\n# managed by docker\noptions ndots:0\n", string(content))
+	})
+
+	t.Run("SearchDomainsOnly", func(t *testing.T) {
+		t.Parallel()
+
+		_, client, _, fs, _ := setupAgent(t, agentsdk.Manifest{
+			StartupScript:        "true",
+			StartupScriptTimeout: 30 * time.Second,
+			DNSSearchDomains:     []string{"corp.example.com"},
+		}, 0, func(_ *agenttest.Client, o *agent.Options) {
+			err := afero.WriteFile(o.Filesystem, "/etc/resolv.conf", []byte("nameserver 8.8.8.8\nsearch corp.example.com\n"), 0o644)
+			require.NoError(t, err)
+		})
+
+		require.Eventually(t, func() bool {
+			return slices.Contains(client.GetLifecycleStates(), codersdk.WorkspaceAgentLifecycleReady)
+		}, testutil.WaitShort, testutil.IntervalMedium)
+
+		// Existing nameservers are kept, and search domains aren't
+		// duplicated when the agent restarts.
+		content, err := afero.ReadFile(fs, "/etc/resolv.conf")
+		require.NoError(t, err)
+		require.Equal(t, "search corp.example.com\nnameserver 8.8.8.8\n", string(content))
+	})
+}
+
 func TestAgent_Startup(t *testing.T) {
 	t.Parallel()
 
@@ -1708,6 +1969,79 @@ func TestAgent_ReconnectingPTY(t *testing.T) {
 	}
 }
 
+func TestAgent_ReconnectingPTYResume(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("ConPTY appears to be inconsistent on Windows.")
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	//nolint:dogsled
+	conn, _, _, _, _ := setupAgent(t, agentsdk.Manifest{}, 0)
+	id := uuid.New()
+	hasSession := func() bool {
+		res, err := conn.ReconnectingPTYSessions(ctx)
+		if !assert.NoError(t, err) {
+			return false
+		}
+		for _, session := range res.Sessions {
+			if session.ID == id {
+				return assert.Equal(t, "bash", session.Command)
+			}
+		}
+		return false
+	}
+
+	// Sessions that were never started can't be resumed.
+	netConn1, err := conn.ResumeReconnectingPTY(ctx, id, 80, 80)
+	require.NoError(t, err)
+	defer netConn1.Close()
+	require.ErrorIs(t, testutil.ReadUntil(ctx, t, netConn1, nil), io.EOF)
+	require.False(t, hasSession())
+
+	netConn2, err := conn.ReconnectingPTY(ctx, id, 80, 80, "bash")
+	require.NoError(t, err)
+	defer netConn2.Close()
+	require.Eventually(t, hasSession, testutil.WaitShort, testutil.IntervalFast)
+
+	// Resuming attaches to the running session.
+	netConn3, err := conn.ResumeReconnectingPTY(ctx, id, 80, 80)
+	require.NoError(t, err)
+	defer netConn3.Close()
+
+	// Brief pause to reduce the likelihood that we send keystrokes while
+	// the shell is simultaneously sending a prompt.
+	time.Sleep(100 * time.Millisecond)
+
+	data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+		Data: "echo test\r\n",
+	})
+	require.NoError(t, err)
+	_, err = netConn3.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, testutil.ReadUntil(ctx, t, netConn2, func(line string) bool {
+		return strings.Contains(line, "test") && !strings.Contains(line, "echo")
+	}), "find echo output")
+
+	data, err = json.Marshal(codersdk.ReconnectingPTYRequest{
+		Data: "exit\r\n",
+	})
+	require.NoError(t, err)
+	_, err = netConn3.Write(data)
+	require.NoError(t, err)
+	require.ErrorIs(t, testutil.ReadUntil(ctx, t, netConn2, nil), io.EOF)
+	require.Eventually(t, func() bool {
+		return !hasSession()
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	// The session has ended, so resuming doesn't start a new one.
+	netConn4, err := conn.ResumeReconnectingPTY(ctx, id, 80, 80)
+	require.NoError(t, err)
+	defer netConn4.Close()
+	require.ErrorIs(t, testutil.ReadUntil(ctx, t, netConn4, nil), io.EOF)
+	require.False(t, hasSession())
+}
+
 func TestAgent_Dial(t *testing.T) {
 	t.Parallel()
 
@@ -1987,6 +2321,46 @@ func TestAgent_WriteVSCodeConfigs(t *testing.T) {
 	}, testutil.WaitShort, testutil.IntervalFast)
 }
 
+func TestAgent_RotateAuthToken(t *testing.T) {
+	t.Parallel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+	coordinator := tailnet.NewCoordinator(logger)
+	defer coordinator.Close()
+
+	client := agenttest.NewClient(t,
+		logger,
+		uuid.New(),
+		agentsdk.Manifest{
+			DERPMap:                   &tailcfg.DERPMap{},
+			AuthTokenRotationInterval: testutil.IntervalFast,
+		},
+		make(chan *agentsdk.Stats, 50),
+		coordinator,
+	)
+	rotated := make(chan string, 10)
+	closer := agent.New(agent.Options{
+		Client:     client,
+		Logger:     logger.Named("agent"),
+		Filesystem: afero.NewMemMapFs(),
+		AuthTokenRotated: func(authToken string) {
+			select {
+			case rotated <- authToken:
+			default:
+			}
+		},
+	})
+	defer closer.Close()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	var authToken string
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for auth token rotation")
+	case authToken = <-rotated:
+	}
+	require.Contains(t, client.GetAuthTokens(), uuid.MustParse(authToken))
+}
+
 func TestAgent_DebugServer(t *testing.T) {
 	t.Parallel()
 
@@ -2395,6 +2769,84 @@ func TestAgent_Metrics_SSH(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAgent_Metrics_ScrapeTargets(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	exporter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`# TYPE gpu_utilization gauge
+gpu_utilization{device="0"} 42
+# TYPE tests_total counter
+tests_total 7
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{le="+Inf"} 1
+request_duration_seconds_sum 0.5
+request_duration_seconds_count 1
+`))
+	}))
+	t.Cleanup(exporter.Close)
+
+	//nolint:dogsled
+	conn, _, stats, _, _ := setupAgent(t, agentsdk.Manifest{}, 0, func(_ *agenttest.Client, o *agent.Options) {
+		o.PrometheusScrapeTargets = []string{exporter.URL}
+	})
+
+	sshClient, err := conn.SSHClient(ctx)
+	require.NoError(t, err)
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	require.NoError(t, err)
+	defer session.Close()
+	stdin, err := session.StdinPipe()
+	require.NoError(t, err)
+	err = session.Shell()
+	require.NoError(t, err)
+
+	expected := []agentsdk.AgentMetric{
+		{
+			Name:  "workspace_gpu_utilization",
+			Type:  agentsdk.AgentMetricTypeGauge,
+			Value: 42,
+			Labels: []agentsdk.AgentMetricLabel{
+				{Name: "device", Value: "0"},
+				{Name: "scrape_target", Value: exporter.URL},
+			},
+		},
+		{
+			Name:  "workspace_tests_total",
+			Type:  agentsdk.AgentMetricTypeCounter,
+			Value: 7,
+			Labels: []agentsdk.AgentMetricLabel{
+				{Name: "scrape_target", Value: exporter.URL},
+			},
+		},
+	}
+
+	var scraped []agentsdk.AgentMetric
+	require.Eventuallyf(t, func() bool {
+		s, ok := <-stats
+		if !ok {
+			return false
+		}
+		scraped = nil
+		for _, m := range s.Metrics {
+			if strings.HasPrefix(m.Name, "workspace_") {
+				scraped = append(scraped, m)
+			}
+		}
+		return len(scraped) > 0
+	}, testutil.WaitLong, testutil.IntervalFast,
+		"never saw scraped metrics",
+	)
+	// Histograms are not forwarded.
+	require.Equal(t, expected, scraped)
+
+	_ = stdin.Close()
+	err = session.Wait()
+	require.NoError(t, err)
+}
+
 func verifyCollectedMetrics(t *testing.T, expected []agentsdk.AgentMetric, actual []*promgo.MetricFamily) bool {
 	t.Helper()
 
@@ -72,6 +72,9 @@ type Server struct {
 	connCountVSCode     atomic.Int64
 	connCountJetBrains  atomic.Int64
 	connCountSSHSession atomic.Int64
+	// lastInput is the unix time in nanoseconds of the last input received
+	// from a session.
+	lastInput atomic.Int64
 
 	metrics *sshServerMetrics
 }
@@ -129,7 +132,13 @@ func NewServer(ctx context.Context, logger slog.Logger, prometheusRegistry *prom
 		Handler:     s.sessionHandler,
 		HostSigners: []ssh.Signer{randomSigner},
 		LocalPortForwardingCallback: func(ctx ssh.Context, destinationHost string, destinationPort uint32) bool {
-			// Allow local port forwarding all!
+			if !s.localPortForwardAllowed(destinationHost, destinationPort) {
+				s.logger.Warn(ctx, "local port forward denied by port policy",
+					slog.F("destination_host", destinationHost),
+					slog.F("destination_port", destinationPort))
+				return false
+			}
+			// Allow local port forwarding all other ports!
 			s.logger.Debug(ctx, "local port forward",
 				slog.F("destination_host", destinationHost),
 				slog.F("destination_port", destinationPort))
@@ -180,14 +189,65 @@ type ConnStats struct {
 	Sessions  int64
 	VSCode    int64
 	JetBrains int64
+	// LastInput is the last time a session received input, or the zero
+	// time if none has.
+	LastInput time.Time
+}
+
+// SetHostKey makes the server present the host key to new connections,
+// replacing its key of the same type. Coderd persists an RSA key for every
+// workspace agent, which replaces the random key so that clients see the same
+// key across workspace rebuilds.
+func (s *Server) SetHostKey(signer gossh.Signer) {
+	s.srv.AddHostKey(signer)
 }
 
 func (s *Server) ConnStats() ConnStats {
-	return ConnStats{
+	stats := ConnStats{
 		Sessions:  s.connCountSSHSession.Load(),
 		VSCode:    s.connCountVSCode.Load(),
 		JetBrains: s.connCountJetBrains.Load(),
 	}
+	if lastInput := s.lastInput.Load(); lastInput != 0 {
+		stats.LastInput = time.Unix(0, lastInput)
+	}
+	return stats
+}
+
+// inputReader records the time of every read with data on the server, which
+// is how keystrokes of interactive sessions are detected.
+type inputReader struct {
+	io.Reader
+	s *Server
+}
+
+func (r inputReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.s.lastInput.Store(time.Now().UnixNano())
+	}
+	return n, err
+}
+
+// localPortForwardAllowed returns whether the port policies of the manifest
+// allow forwarding to the destination. They only restrict ports of the
+// agent's host.
+func (s *Server) localPortForwardAllowed(destinationHost string, destinationPort uint32) bool {
+	if destinationPort > 65535 || s.Manifest == nil {
+		return true
+	}
+	manifest := s.Manifest.Load()
+	if manifest == nil {
+		return true
+	}
+	if destinationHost != "localhost" {
+		ip := net.ParseIP(destinationHost)
+		if ip == nil || !(ip.IsLoopback() || ip.IsUnspecified()) {
+			return true
+		}
+	}
+	allowed, _ := codersdk.CheckPortPolicies(uint16(destinationPort), manifest.PortPolicies...)
+	return allowed
 }
 
 func (s *Server) sessionHandler(session ssh.Session) {
@@ -316,7 +376,7 @@ func (s *Server) startNonPTYSession(session ssh.Session, magicTypeLabel string,
 		return xerrors.Errorf("create stdin pipe: %w", err)
 	}
 	go func() {
-		_, err := io.Copy(stdinPipe, session)
+		_, err := io.Copy(stdinPipe, inputReader{Reader: session, s: s})
 		if err != nil {
 			s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "no", "stdin_io_copy").Add(1)
 		}
@@ -404,7 +464,7 @@ func (s *Server) startPTYSession(session ptySession, magicTypeLabel string, cmd
 	}()
 
 	go func() {
-		_, err := io.Copy(ptty.InputWriter(), session)
+		_, err := io.Copy(ptty.InputWriter(), inputReader{Reader: session, s: s})
 		if err != nil {
 			s.metrics.sessionErrors.WithLabelValues(magicTypeLabel, "yes", "input_io_copy").Add(1)
 		}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
@@ -51,11 +52,13 @@ type Client struct {
 	LastWorkspaceAgent   func()
 	PatchWorkspaceLogs   func() error
 	GetServiceBannerFunc func() (codersdk.ServiceBannerConfig, error)
+	BinaryChecksumFunc   func(goos, goarch string) (string, error)
 
 	mu              sync.Mutex // Protects following.
 	lifecycleStates []codersdk.WorkspaceAgentLifecycle
 	startup         agentsdk.PostStartupRequest
 	logs            []agentsdk.Log
+	authTokens      []uuid.UUID
 	derpMapUpdates  chan agentsdk.DERPMapUpdate
 }
 
@@ -195,6 +198,32 @@ func (c *Client) GetServiceBanner(ctx context.Context) (codersdk.ServiceBannerCo
 	return codersdk.ServiceBannerConfig{}, nil
 }
 
+func (c *Client) BinaryChecksum(ctx context.Context, goos, goarch string) (string, error) {
+	c.logger.Debug(ctx, "get binary checksum", slog.F("os", goos), slog.F("arch", goarch))
+	if c.BinaryChecksumFunc != nil {
+		return c.BinaryChecksumFunc(goos, goarch)
+	}
+	return "", xerrors.New("no binary checksum")
+}
+
+func (c *Client) GetAuthTokens() []uuid.UUID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.authTokens)
+}
+
+func (c *Client) RotateAuthToken(ctx context.Context) (agentsdk.RotateAuthTokenResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logger.Debug(ctx, "rotate auth token")
+	authToken := uuid.New()
+	c.authTokens = append(c.authTokens, authToken)
+	return agentsdk.RotateAuthTokenResponse{
+		AuthToken:                  authToken,
+		PreviousAuthTokenExpiresAt: time.Now().Add(time.Hour),
+	}, nil
+}
+
 func (c *Client) PushDERPMapUpdate(update agentsdk.DERPMapUpdate) error {
 	timer := time.NewTimer(testutil.WaitShort)
 	defer timer.Stop()
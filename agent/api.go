@@ -2,6 +2,7 @@ package agent
 
 import (
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -26,8 +27,10 @@ func (a *agent) apiHandler() http.Handler {
 		cpy[k] = b
 	}
 
-	lp := &listeningPortsHandler{ignorePorts: cpy}
+	lp := &listeningPortsHandler{ignorePorts: cpy, portPolicies: a.portPolicies}
 	r.Get("/api/v0/listening-ports", lp.handler)
+	r.Get("/api/v0/support-bundle", a.supportBundleHandler(lp))
+	r.Get("/api/v0/reconnecting-ptys", a.reconnectingPTYSessionsHandler)
 
 	return r
 }
@@ -37,6 +40,9 @@ type listeningPortsHandler struct {
 	ports       []codersdk.WorkspaceAgentListeningPort
 	mtime       time.Time
 	ignorePorts map[int]string
+	// portPolicies returns the port policies that listed ports must be
+	// allowed by.
+	portPolicies func() []codersdk.PortPolicy
 }
 
 // handler returns a list of listening ports. This is tested by coderd's
@@ -51,7 +57,47 @@ func (lp *listeningPortsHandler) handler(rw http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if lp.portPolicies != nil {
+		policies := lp.portPolicies()
+		allowedPorts := make([]codersdk.WorkspaceAgentListeningPort, 0, len(ports))
+		for _, port := range ports {
+			if allowed, _ := codersdk.CheckPortPolicies(port.Port, policies...); allowed {
+				allowedPorts = append(allowedPorts, port)
+			}
+		}
+		ports = allowedPorts
+	}
+
 	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceAgentListeningPortsResponse{
 		Ports: ports,
 	})
 }
+
+// portPolicies returns the port policies of the manifest. Ports are allowed
+// until the manifest was fetched.
+func (a *agent) portPolicies() []codersdk.PortPolicy {
+	manifest := a.manifest.Load()
+	if manifest == nil {
+		return nil
+	}
+	return manifest.PortPolicies
+}
+
+// reconnectingPTYSessionsHandler lists the reconnecting PTY sessions, oldest
+// first.
+func (a *agent) reconnectingPTYSessionsHandler(rw http.ResponseWriter, r *http.Request) {
+	sessions := []codersdk.WorkspaceAgentReconnectingPTYSession{}
+	a.reconnectingPTYSessions.Range(func(_, value interface{}) bool {
+		if session, ok := value.(codersdk.WorkspaceAgentReconnectingPTYSession); ok {
+			sessions = append(sessions, session)
+		}
+		return true
+	})
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.WorkspaceAgentReconnectingPTYSessionsResponse{
+		Sessions: sessions,
+	})
+}
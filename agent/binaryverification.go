@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// verifyBinary compares the checksum of the running agent binary with the one
+// the deployment serves for the platform, so agents that were downloaded from
+// a tampered mirror don't run the startup script. Failures are written to the
+// startup logs to explain why the agent failed to start.
+func (a *agent) verifyBinary(ctx context.Context) error {
+	err := a.checkBinaryChecksum(ctx)
+	if err != nil {
+		a.logger.Warn(ctx, "agent binary verification failed", slog.Error(err))
+		logErr := a.client.PatchLogs(ctx, agentsdk.PatchLogs{
+			Logs: []agentsdk.Log{{
+				CreatedAt: time.Now(),
+				Output:    fmt.Sprintf("The template requires the agent binary to be verified, not running the startup script: %s", err),
+				Level:     codersdk.LogLevelError,
+				Source:    codersdk.WorkspaceAgentLogSourceStartupScript,
+			}},
+		})
+		if logErr != nil {
+			a.logger.Warn(ctx, "write binary verification failure to startup logs", slog.Error(logErr))
+		}
+		return err
+	}
+	a.logger.Info(ctx, "agent binary verified")
+	return nil
+}
+
+func (a *agent) checkBinaryChecksum(ctx context.Context) error {
+	expected, err := a.client.BinaryChecksum(ctx, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return xerrors.Errorf("fetch binary checksum: %w", err)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return xerrors.Errorf("get executable: %w", err)
+	}
+	f, err := os.Open(executable)
+	if err != nil {
+		return xerrors.Errorf("open executable: %w", err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return xerrors.Errorf("hash executable: %w", err)
+	}
+	actual := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(actual, expected) {
+		return xerrors.Errorf("checksum of %q is %s, but the deployment serves %s", executable, actual, expected)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// resolvConfPath is the resolver configuration the DNS settings of the
+// deployment are written to.
+const resolvConfPath = "/etc/resolv.conf"
+
+// applyDNSConfig writes the DNS search domains and nameservers of the
+// deployment to the resolver configuration of the workspace. Failures don't
+// stop the agent, but are written to the startup logs since hosts that
+// are only resolvable with them won't be reachable.
+func (a *agent) applyDNSConfig(ctx context.Context, manifest agentsdk.Manifest) {
+	if len(manifest.DNSSearchDomains) == 0 && len(manifest.DNSNameservers) == 0 {
+		return
+	}
+
+	var err error
+	if runtime.GOOS != "linux" {
+		err = xerrors.Errorf("not supported on %s", runtime.GOOS)
+	} else {
+		err = updateResolvConf(a.filesystem, resolvConfPath, manifest.DNSSearchDomains, manifest.DNSNameservers)
+	}
+	if err != nil {
+		a.logger.Warn(ctx, "apply dns config", slog.Error(err))
+		logErr := a.client.PatchLogs(ctx, agentsdk.PatchLogs{
+			Logs: []agentsdk.Log{{
+				CreatedAt: time.Now(),
+				Output:    fmt.Sprintf("Unable to apply the DNS configuration of the deployment: %s", err),
+				Level:     codersdk.LogLevelWarn,
+				Source:    codersdk.WorkspaceAgentLogSourceStartupScript,
+			}},
+		})
+		if logErr != nil {
+			a.logger.Warn(ctx, "write dns config failure to startup logs", slog.Error(logErr))
+		}
+		return
+	}
+	a.logger.Info(ctx, "applied dns config",
+		slog.F("search_domains", manifest.DNSSearchDomains),
+		slog.F("nameservers", manifest.DNSNameservers),
+	)
+}
+
+// updateResolvConf prepends searchDomains to the search list of the resolver
+// configuration at path, and replaces its nameservers with nameservers if any
+// are given. Other settings are kept. Applying the same configuration twice
+// doesn't change the file, so it's safe to run on every start of the agent.
+func updateResolvConf(fs afero.Fs, path string, searchDomains, nameservers []string) error {
+	existing, err := afero.ReadFile(fs, path)
+	if err != nil && !xerrors.Is(err, os.ErrNotExist) {
+		return xerrors.Errorf("read %s: %w", path, err)
+	}
+
+	var (
+		search []string
+		kept   []string
+	)
+	search = append(search, searchDomains...)
+	scanner := bufio.NewScanner(bytes.NewReader(existing))
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			kept = append(kept, line)
+			continue
+		}
+		switch fields[0] {
+		case "search", "domain":
+			// The last search or domain line takes precedence, so only the
+			// domains of that one are used by the resolver.
+			search = append(search[:len(searchDomains)], fields[1:]...)
+			if len(searchDomains) > 0 {
+				continue
+			}
+		case "nameserver":
+			if len(nameservers) > 0 {
+				continue
+			}
+		}
+		kept = append(kept, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return xerrors.Errorf("scan %s: %w", path, err)
+	}
+
+	var buf bytes.Buffer
+	if len(searchDomains) > 0 {
+		unique := make([]string, 0, len(search))
+		for _, domain := range search {
+			if !slices.Contains(unique, domain) {
+				unique = append(unique, domain)
+			}
+		}
+		_, _ = fmt.Fprintf(&buf, "search %s\n", strings.Join(unique, " "))
+	}
+	for _, nameserver := range nameservers {
+		_, _ = fmt.Fprintf(&buf, "nameserver %s\n", nameserver)
+	}
+	for _, line := range kept {
+		_, _ = fmt.Fprintln(&buf, line)
+	}
+
+	// The file is written in place rather than renamed over, since container
+	// runtimes commonly bind mount it.
+	err = afero.WriteFile(fs, path, buf.Bytes(), 0o644)
+	if err != nil {
+		return xerrors.Errorf("write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package agent
+
+// hostFacts is only implemented on Windows, where the version of the host
+// varies the most between templates.
+func hostFacts() map[string]string {
+	return nil
+}
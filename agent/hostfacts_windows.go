@@ -0,0 +1,48 @@
+//go:build windows
+
+package agent
+
+import (
+	"fmt"
+	"strconv"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// hostFacts describes the version of Windows the agent runs on. Templates
+// often target a range of Windows images, and the version decides which
+// features, such as pseudo consoles, are available.
+func hostFacts() map[string]string {
+	vsn := windows.RtlGetVersion()
+	facts := map[string]string{
+		codersdk.AgentHostFactOSBuild: strconv.FormatUint(uint64(vsn.BuildNumber), 10),
+		// The pseudo console API was introduced in build 17763, see newPty.
+		codersdk.AgentHostFactConPTY: strconv.FormatBool(vsn.MajorVersion > 10 || (vsn.MajorVersion == 10 && vsn.BuildNumber >= 17763)),
+	}
+	if isService, err := svc.IsWindowsService(); err == nil {
+		facts[codersdk.AgentHostFactService] = strconv.FormatBool(isService)
+	}
+
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows NT\CurrentVersion`, registry.QUERY_VALUE)
+	if err != nil {
+		return facts
+	}
+	defer key.Close()
+	if name, _, err := key.GetStringValue("ProductName"); err == nil {
+		facts[codersdk.AgentHostFactOSName] = name
+	}
+	// DisplayVersion replaced ReleaseId in 20H2.
+	if version, _, err := key.GetStringValue("DisplayVersion"); err == nil {
+		facts[codersdk.AgentHostFactOSVersion] = version
+	} else if version, _, err := key.GetStringValue("ReleaseId"); err == nil {
+		facts[codersdk.AgentHostFactOSVersion] = version
+	}
+	if revision, _, err := key.GetIntegerValue("UBR"); err == nil {
+		facts[codersdk.AgentHostFactOSBuild] = fmt.Sprintf("%d.%d", vsn.BuildNumber, revision)
+	}
+	return facts
+}
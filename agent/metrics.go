@@ -3,10 +3,15 @@ package agent
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 	prompb "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"golang.org/x/exp/maps"
+	"golang.org/x/xerrors"
 	"tailscale.com/util/clientmetric"
 
 	"cdr.dev/slog"
@@ -14,6 +19,18 @@ import (
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 )
 
+const (
+	// scrapedMetricPrefix namespaces metrics scraped from in-workspace
+	// Prometheus endpoints, so they can't collide with metrics exported by
+	// the agent or coderd.
+	scrapedMetricPrefix = "workspace_"
+	// scrapeTargetLabel identifies the endpoint a scraped metric came from.
+	scrapeTargetLabel = "scrape_target"
+	// maxScrapedMetricsPerTarget caps the number of samples forwarded per
+	// endpoint, so a chatty exporter can't bloat the stats report.
+	maxScrapedMetricsPerTarget = 512
+)
+
 type agentMetrics struct {
 	connectionsTotal      prometheus.Counter
 	reconnectingPTYErrors *prometheus.CounterVec
@@ -58,6 +75,16 @@ func (a *agent) collectMetrics(ctx context.Context) []agentsdk.AgentMetric {
 		})
 	}
 
+	// Metrics from in-workspace Prometheus endpoints
+	for _, target := range a.prometheusScrapeTargets {
+		scraped, err := scrapePrometheusTarget(ctx, target)
+		if err != nil {
+			a.logger.Warn(ctx, "can't scrape prometheus target", slog.F("target", target), slog.Error(err))
+			continue
+		}
+		collected = append(collected, scraped...)
+	}
+
 	metricFamilies, err := a.prometheusRegistry.Gather()
 	if err != nil {
 		a.logger.Error(ctx, "can't gather agent metrics", slog.Error(err))
@@ -90,6 +117,79 @@ func (a *agent) collectMetrics(ctx context.Context) []agentsdk.AgentMetric {
 	return collected
 }
 
+// scrapePrometheusTarget fetches metrics in the Prometheus text format from
+// target. Counters, gauges and untyped metrics are supported, other types are
+// skipped.
+func scrapePrometheusTarget(ctx context.Context, target string) ([]agentsdk.AgentMetric, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", string(expfmt.FmtText))
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	var parser expfmt.TextParser
+	metricFamilies, err := parser.TextToMetricFamilies(res.Body)
+	if err != nil {
+		return nil, xerrors.Errorf("parse metrics: %w", err)
+	}
+
+	names := maps.Keys(metricFamilies)
+	sort.Strings(names)
+
+	var collected []agentsdk.AgentMetric
+	for _, name := range names {
+		for _, metric := range metricFamilies[name].GetMetric() {
+			if len(collected) >= maxScrapedMetricsPerTarget {
+				return collected, nil
+			}
+
+			labels := toAgentMetricLabels(metric.Label)
+			for i := range labels {
+				// Follow Prometheus' convention for conflicting target labels.
+				if labels[i].Name == scrapeTargetLabel {
+					labels[i].Name = "exported_" + scrapeTargetLabel
+				}
+			}
+			labels = append(labels, agentsdk.AgentMetricLabel{
+				Name:  scrapeTargetLabel,
+				Value: target,
+			})
+			switch {
+			case metric.Counter != nil:
+				collected = append(collected, agentsdk.AgentMetric{
+					Name:   scrapedMetricPrefix + name,
+					Type:   agentsdk.AgentMetricTypeCounter,
+					Value:  metric.Counter.GetValue(),
+					Labels: labels,
+				})
+			case metric.Gauge != nil:
+				collected = append(collected, agentsdk.AgentMetric{
+					Name:   scrapedMetricPrefix + name,
+					Type:   agentsdk.AgentMetricTypeGauge,
+					Value:  metric.Gauge.GetValue(),
+					Labels: labels,
+				})
+			case metric.Untyped != nil:
+				collected = append(collected, agentsdk.AgentMetric{
+					Name:   scrapedMetricPrefix + name,
+					Type:   agentsdk.AgentMetricTypeGauge,
+					Value:  metric.Untyped.GetValue(),
+					Labels: labels,
+				})
+			}
+		}
+	}
+	return collected, nil
+}
+
 func toAgentMetricLabels(metricLabels []*prompb.LabelPair) []agentsdk.AgentMetricLabel {
 	if len(metricLabels) == 0 {
 		return nil
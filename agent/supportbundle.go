@@ -0,0 +1,230 @@
+package agent
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+	"tailscale.com/net/netcheck"
+	"tailscale.com/net/portmapper"
+	tslogger "tailscale.com/types/logger"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+const (
+	// supportBundleLogSize is the size of the end of each log file that's
+	// included in support bundles.
+	supportBundleLogSize = 1 << 20
+	// supportBundleNetcheckTimeout bounds the time spent checking the
+	// connectivity of the agent to the DERP servers.
+	supportBundleNetcheckTimeout = 15 * time.Second
+)
+
+// supportBundleHandler writes a zip archive of local diagnostics for debugging
+// connectivity issues of the workspace. Secrets of the manifest are redacted.
+func (a *agent) supportBundleHandler(lp *listeningPortsHandler) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		files := a.supportBundleFiles(ctx, lp)
+
+		rw.Header().Set("Content-Type", "application/zip")
+		rw.Header().Set("Content-Disposition", `attachment; filename="support-bundle.zip"`)
+		rw.WriteHeader(http.StatusOK)
+		zw := zip.NewWriter(rw)
+		for _, file := range files {
+			w, err := zw.CreateHeader(&zip.FileHeader{
+				Name:     file.name,
+				Method:   zip.Deflate,
+				Modified: time.Now(),
+			})
+			if err != nil {
+				a.logger.Warn(ctx, "write support bundle", slog.F("file", file.name), slog.Error(err))
+				return
+			}
+			_, err = w.Write(file.data)
+			if err != nil {
+				a.logger.Warn(ctx, "write support bundle", slog.F("file", file.name), slog.Error(err))
+				return
+			}
+		}
+		err := zw.Close()
+		if err != nil {
+			a.logger.Warn(ctx, "close support bundle", slog.Error(err))
+		}
+	}
+}
+
+type supportBundleFile struct {
+	name string
+	data []byte
+}
+
+// supportBundleFiles gathers the files of a support bundle. Diagnostics that
+// can't be gathered are replaced by an error file, so a bundle is returned
+// even when the agent is partially broken.
+func (a *agent) supportBundleFiles(ctx context.Context, lp *listeningPortsHandler) []supportBundleFile {
+	var files []supportBundleFile
+	addJSON := func(name string, v interface{}, err error) {
+		if err != nil {
+			files = append(files, supportBundleFile{name: name + ".error.txt", data: []byte(err.Error())})
+			return
+		}
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			files = append(files, supportBundleFile{name: name + ".error.txt", data: []byte(err.Error())})
+			return
+		}
+		files = append(files, supportBundleFile{name: name + ".json", data: data})
+	}
+
+	a.lifecycleMu.RLock()
+	lifecycleStates := append([]agentsdk.PostLifecycleRequest(nil), a.lifecycleStates...)
+	a.lifecycleMu.RUnlock()
+	addJSON("agent", supportBundleAgent{
+		Version:         buildinfo.Version(),
+		OperatingSystem: runtime.GOOS,
+		Architecture:    runtime.GOARCH,
+		LogDir:          a.logDir,
+		Lifecycle:       lifecycleStates,
+	}, nil)
+
+	manifest := a.manifest.Load()
+	if manifest == nil {
+		addJSON("manifest", nil, xerrors.New("the agent hasn't received a manifest yet"))
+	} else {
+		addJSON("manifest", redactManifest(*manifest), nil)
+	}
+
+	ports, err := lp.getListeningPorts()
+	addJSON("listening_ports", ports, err)
+
+	a.closeMutex.Lock()
+	network := a.network
+	a.closeMutex.Unlock()
+	if network == nil {
+		addJSON("tailnet", nil, xerrors.New("network is not ready yet"))
+	} else {
+		addJSON("tailnet", network.Status(), nil)
+	}
+
+	if manifest == nil || manifest.DERPMap == nil {
+		addJSON("netcheck", nil, xerrors.New("the agent hasn't received a DERP map yet"))
+	} else {
+		addJSON("netcheck", runNetcheck(ctx, manifest), nil)
+	}
+
+	files = append(files, a.supportBundleLogs(ctx)...)
+	return files
+}
+
+// supportBundleAgent describes the agent in support bundles.
+type supportBundleAgent struct {
+	Version         string                          `json:"version"`
+	OperatingSystem string                          `json:"operating_system"`
+	Architecture    string                          `json:"architecture"`
+	LogDir          string                          `json:"log_dir"`
+	Lifecycle       []agentsdk.PostLifecycleRequest `json:"lifecycle"`
+}
+
+// supportBundleNetcheck is the result of checking the connectivity of the
+// agent to STUN and DERP servers.
+type supportBundleNetcheck struct {
+	Report *netcheck.Report `json:"report"`
+	Error  string           `json:"error,omitempty"`
+	Logs   []string         `json:"logs"`
+}
+
+func runNetcheck(ctx context.Context, manifest *agentsdk.Manifest) supportBundleNetcheck {
+	ctx, cancel := context.WithTimeout(ctx, supportBundleNetcheckTimeout)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		result supportBundleNetcheck
+	)
+	logf := func(format string, args ...interface{}) {
+		mu.Lock()
+		result.Logs = append(result.Logs, fmt.Sprintf(format, args...))
+		mu.Unlock()
+	}
+	client := &netcheck.Client{
+		PortMapper: portmapper.NewClient(tslogger.WithPrefix(logf, "portmap: "), nil, nil, nil),
+		Logf:       tslogger.WithPrefix(logf, "netcheck: "),
+	}
+	report, err := client.GetReport(ctx, manifest.DERPMap)
+	mu.Lock()
+	defer mu.Unlock()
+	result.Report = report
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// supportBundleLogs returns the end of the log files of the agent and its
+// scripts.
+func (a *agent) supportBundleLogs(ctx context.Context) []supportBundleFile {
+	paths, err := afero.Glob(a.filesystem, filepath.Join(a.logDir, "coder-*.log"))
+	if err != nil {
+		return []supportBundleFile{{name: "logs.error.txt", data: []byte(err.Error())}}
+	}
+	files := make([]supportBundleFile, 0, len(paths))
+	for _, path := range paths {
+		name := "logs/" + filepath.Base(path)
+		data, err := tailFile(a.filesystem, path, supportBundleLogSize)
+		if err != nil {
+			a.logger.Debug(ctx, "read log for support bundle", slog.F("path", path), slog.Error(err))
+			files = append(files, supportBundleFile{name: name + ".error.txt", data: []byte(err.Error())})
+			continue
+		}
+		files = append(files, supportBundleFile{name: name, data: data})
+	}
+	return files
+}
+
+// tailFile returns at most the last size bytes of the file.
+func tailFile(fs afero.Fs, path string, size int64) ([]byte, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > size {
+		_, err = f.Seek(info.Size()-size, io.SeekStart)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return io.ReadAll(io.LimitReader(f, size))
+}
+
+// redactManifest removes the secrets of the manifest, like the values of
+// environment variables, which may hold credentials.
+func redactManifest(manifest agentsdk.Manifest) agentsdk.Manifest {
+	env := make(map[string]string, len(manifest.EnvironmentVariables))
+	for key := range manifest.EnvironmentVariables {
+		env[key] = codersdk.RedactedValue
+	}
+	manifest.EnvironmentVariables = env
+	if manifest.SSHHostKey != "" {
+		manifest.SSHHostKey = codersdk.RedactedValue
+	}
+	return manifest
+}
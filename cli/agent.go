@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"net/http"
@@ -45,6 +46,7 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 		sshMaxTimeout       time.Duration
 		tailnetListenPort   int64
 		prometheusAddress   string
+		prometheusTargets   []string
 		debugAddress        string
 		slogHumanPath       string
 		slogJSONPath        string
@@ -55,6 +57,9 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 		Short: `Starts the Coder workspace agent.`,
 		// This command isn't useful to manually execute.
 		Hidden: true,
+		Children: []*clibase.Cmd{
+			r.workspaceAgentService(),
+		},
 		Handler: func(inv *clibase.Invocation) error {
 			ctx, cancel := context.WithCancel(inv.Context())
 			defer cancel()
@@ -161,6 +166,11 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			sinks = append(sinks, sloghuman.Sink(logWriter))
 			logger := slog.Make(sinks...).Leveled(slog.LevelDebug)
 
+			// Services are stopped by the service control manager rather
+			// than a signal.
+			ctx, stopService := notifyServiceStop(ctx)
+			defer stopService()
+
 			version := buildinfo.Version()
 			logger.Info(ctx, "agent is starting now",
 				slog.F("url", r.agentURL),
@@ -196,13 +206,28 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			// This is abstracted to allow for the same looping condition
 			// regardless of instance identity auth type.
 			var exchangeToken func(context.Context) (agentsdk.AuthenticateResponse, error)
+			var authTokenRotated func(authToken string)
 			switch auth {
 			case "token":
 				token, err := inv.ParsedFlags().GetString(varAgentToken)
 				if err != nil {
 					return xerrors.Errorf("CODER_AGENT_TOKEN must be set for token auth: %w", err)
 				}
+				// The provisioned token stops working a while after the agent
+				// rotated it, so the rotated token is kept for restarts. The
+				// file is named after the provisioned token, a rebuild
+				// provisions a new one.
+				tokenFile := filepath.Join(logDir, fmt.Sprintf("coder-agent-token-%x", sha256.Sum256([]byte(token))))
+				if rotated, err := os.ReadFile(tokenFile); err == nil {
+					token = strings.TrimSpace(string(rotated))
+				}
 				client.SetSessionToken(token)
+				authTokenRotated = func(authToken string) {
+					err := os.WriteFile(tokenFile, []byte(authToken), 0o600)
+					if err != nil {
+						logger.Warn(ctx, "persist rotated auth token", slog.Error(err))
+					}
+				}
 			case "google-instance-identity":
 				// This is *only* done for testing to mock client authentication.
 				// This will never be set in a production scenario.
@@ -267,6 +292,13 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				subsystems = append(subsystems, subsystem)
 			}
 
+			for _, target := range prometheusTargets {
+				u, err := url.Parse(target)
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return xerrors.Errorf("invalid prometheus scrape target %q: must be an http or https URL", target)
+				}
+			}
+
 			agnt := agent.New(agent.Options{
 				Client:            client,
 				Logger:            logger,
@@ -286,11 +318,13 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 				EnvironmentVariables: map[string]string{
 					"GIT_ASKPASS": executablePath,
 				},
-				IgnorePorts:   ignorePorts,
-				SSHMaxTimeout: sshMaxTimeout,
-				Subsystems:    subsystems,
+				IgnorePorts:      ignorePorts,
+				SSHMaxTimeout:    sshMaxTimeout,
+				Subsystems:       subsystems,
+				AuthTokenRotated: authTokenRotated,
 
-				PrometheusRegistry: prometheusRegistry,
+				PrometheusRegistry:      prometheusRegistry,
+				PrometheusScrapeTargets: prometheusTargets,
 			})
 
 			prometheusSrvClose := ServeHandler(ctx, logger, prometheusMetricsHandler(prometheusRegistry, logger), prometheusAddress, "prometheus")
@@ -355,6 +389,12 @@ func (r *RootCmd) workspaceAgent() *clibase.Cmd {
 			Value:       clibase.StringOf(&prometheusAddress),
 			Description: "The bind address to serve Prometheus metrics.",
 		},
+		{
+			Flag:        "prometheus-scrape-targets",
+			Env:         "CODER_AGENT_PROMETHEUS_SCRAPE_TARGETS",
+			Value:       clibase.StringArrayOf(&prometheusTargets),
+			Description: "URLs of Prometheus endpoints inside the workspace to scrape and forward to Coder. Forwarded metrics are prefixed with \"workspace_\".",
+		},
 		{
 			Flag:        "debug-address",
 			Default:     "127.0.0.1:2113",
@@ -281,4 +281,42 @@ func TestWorkspaceAgent(t *testing.T) {
 		require.Equal(t, codersdk.AgentSubsystemEnvbox, resources[0].Agents[0].Subsystems[0])
 		require.Equal(t, codersdk.AgentSubsystemExectrace, resources[0].Agents[0].Subsystems[1])
 	})
+
+	t.Run("ServiceInstall", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("Installing would register a service on the host")
+		}
+
+		authToken := uuid.NewString()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		// The token is checked before anything is installed.
+		inv, _ := clitest.New(t,
+			"agent", "service", "install",
+			"--agent-token", uuid.NewString(),
+			"--agent-url", client.URL.String(),
+		)
+		err := inv.Run()
+		require.ErrorContains(t, err, "fetch agent manifest")
+
+		inv, _ = clitest.New(t,
+			"agent", "service", "install",
+			"--agent-token", authToken,
+			"--agent-url", client.URL.String(),
+		)
+		err = inv.Run()
+		require.ErrorContains(t, err, "only supported on Windows")
+	})
 }
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// agentServiceName is the name of the Windows service of the agent.
+const agentServiceName = "CoderAgent"
+
+// agentServiceConfig is what the agent service is installed with. It's
+// persisted as the environment of the service, so the service runs the agent
+// the same way as the command that installed it.
+type agentServiceConfig struct {
+	Executable string
+	AgentURL   string
+	Auth       string
+	Token      string
+	LogDir     string
+}
+
+func (c agentServiceConfig) environ() []string {
+	env := []string{
+		"CODER_AGENT_URL=" + c.AgentURL,
+		"CODER_AGENT_AUTH=" + c.Auth,
+		"CODER_AGENT_LOG_DIR=" + c.LogDir,
+	}
+	if c.Token != "" {
+		env = append(env, fmt.Sprintf("%s=%s", envAgentToken, c.Token))
+	}
+	return env
+}
+
+func (r *RootCmd) workspaceAgentService() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   "service",
+		Short: "Manage the Windows service that runs the workspace agent.",
+		Handler: func(inv *clibase.Invocation) error {
+			return inv.Command.HelpHandler(inv)
+		},
+		Children: []*clibase.Cmd{
+			r.workspaceAgentServiceInstall(),
+			r.workspaceAgentServiceUninstall(),
+		},
+	}
+}
+
+func (r *RootCmd) workspaceAgentServiceInstall() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:   "install",
+		Short: "Install and start the agent as a Windows service that starts with the host.",
+		Long: "The service runs the agent with the URL, authentication and log directory given to this command. " +
+			"With token authentication, the token is checked by fetching the manifest of the agent before the service is installed.",
+		Middleware: clibase.RequireNArgs(0),
+		Handler: func(inv *clibase.Invocation) error {
+			ctx := inv.Context()
+			auth, err := inv.ParsedFlags().GetString("auth")
+			if err != nil {
+				return err
+			}
+			logDir, err := inv.ParsedFlags().GetString("log-dir")
+			if err != nil {
+				return err
+			}
+			executable, err := os.Executable()
+			if err != nil {
+				return xerrors.Errorf("get executable path: %w", err)
+			}
+			cfg := agentServiceConfig{
+				Executable: executable,
+				AgentURL:   r.agentURL.String(),
+				Auth:       auth,
+				LogDir:     logDir,
+			}
+
+			// A service with a bad token would restart forever, so fail
+			// early instead. Instance identity auth can only be checked on
+			// the instance at startup.
+			if auth == "token" {
+				if r.agentToken == "" {
+					return xerrors.Errorf("%s must be set for token auth", envAgentToken)
+				}
+				client := agentsdk.New(r.agentURL)
+				client.SetSessionToken(r.agentToken)
+				_, err := client.Manifest(ctx)
+				if err != nil {
+					return xerrors.Errorf("fetch agent manifest: %w", err)
+				}
+				cfg.Token = r.agentToken
+			}
+
+			err = installAgentService(cfg)
+			if err != nil {
+				return xerrors.Errorf("install agent service: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Installed and started the %s service.\n", agentServiceName)
+			return nil
+		},
+	}
+}
+
+func (*RootCmd) workspaceAgentServiceUninstall() *clibase.Cmd {
+	return &clibase.Cmd{
+		Use:        "uninstall",
+		Short:      "Stop and remove the Windows service of the agent.",
+		Middleware: clibase.RequireNArgs(0),
+		Handler: func(inv *clibase.Invocation) error {
+			err := uninstallAgentService()
+			if err != nil {
+				return xerrors.Errorf("uninstall agent service: %w", err)
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "Stopped and removed the %s service.\n", agentServiceName)
+			return nil
+		},
+	}
+}
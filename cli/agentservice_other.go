@@ -0,0 +1,24 @@
+//go:build !windows
+// +build !windows
+
+package cli
+
+import (
+	"context"
+
+	"golang.org/x/xerrors"
+)
+
+func installAgentService(agentServiceConfig) error {
+	return xerrors.New("agent services are only supported on Windows")
+}
+
+func uninstallAgentService() error {
+	return xerrors.New("agent services are only supported on Windows")
+}
+
+// notifyServiceStop is a no-op outside of Windows, where the agent is stopped
+// with a signal.
+func notifyServiceStop(ctx context.Context) (context.Context, func()) {
+	return ctx, func() {}
+}
@@ -0,0 +1,157 @@
+//go:build windows
+// +build windows
+
+package cli
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+	"golang.org/x/xerrors"
+)
+
+func installAgentService(cfg agentServiceConfig) (retErr error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return xerrors.Errorf("connect to service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	if s, err := m.OpenService(agentServiceName); err == nil {
+		_ = s.Close()
+		return xerrors.Errorf("service %q is already installed", agentServiceName)
+	}
+
+	s, err := m.CreateService(agentServiceName, cfg.Executable, mgr.Config{
+		DisplayName: "Coder Agent",
+		Description: "Connects the workspace to Coder.",
+		StartType:   mgr.StartAutomatic,
+	}, "agent")
+	if err != nil {
+		return xerrors.Errorf("create service: %w", err)
+	}
+	defer s.Close()
+	defer func() {
+		if retErr != nil {
+			_ = s.Delete()
+		}
+	}()
+
+	// Services read their environment from the registry, which keeps the
+	// token out of the command line.
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Services\`+agentServiceName, registry.SET_VALUE)
+	if err != nil {
+		return xerrors.Errorf("open service key: %w", err)
+	}
+	defer key.Close()
+	err = key.SetStringsValue("Environment", cfg.environ())
+	if err != nil {
+		return xerrors.Errorf("set service environment: %w", err)
+	}
+
+	// Restart the agent if it exits, the same as a supervisor would on
+	// Linux. The failure count resets after a day.
+	err = s.SetRecoveryActions([]mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: 5 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: 30 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: time.Minute},
+	}, uint32((24 * time.Hour).Seconds()))
+	if err != nil {
+		return xerrors.Errorf("set recovery actions: %w", err)
+	}
+
+	err = s.Start()
+	if err != nil {
+		return xerrors.Errorf("start service: %w", err)
+	}
+	return nil
+}
+
+func uninstallAgentService() error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return xerrors.Errorf("connect to service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(agentServiceName)
+	if err != nil {
+		return xerrors.Errorf("open service: %w", err)
+	}
+	defer s.Close()
+
+	status, err := s.Control(svc.Stop)
+	if err != nil && !xerrors.Is(err, windows.ERROR_SERVICE_NOT_ACTIVE) {
+		return xerrors.Errorf("stop service: %w", err)
+	}
+	// Deleting a running service only marks it for deletion, so wait for the
+	// agent to exit first.
+	for deadline := time.Now().Add(30 * time.Second); err == nil && status.State != svc.Stopped; {
+		if time.Now().After(deadline) {
+			return xerrors.New("timed out waiting for the service to stop")
+		}
+		time.Sleep(250 * time.Millisecond)
+		status, err = s.Query()
+		if err != nil {
+			return xerrors.Errorf("query service: %w", err)
+		}
+	}
+
+	err = s.Delete()
+	if err != nil {
+		return xerrors.Errorf("delete service: %w", err)
+	}
+	return nil
+}
+
+// notifyServiceStop returns a context that is canceled when the service
+// control manager stops the agent, if the agent runs as a service. The
+// returned function must be called when the agent has exited.
+func notifyServiceStop(ctx context.Context) (context.Context, func()) {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return ctx, func() {}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	handler := &agentServiceHandler{
+		stop: cancel,
+		done: make(chan struct{}),
+	}
+	go func() {
+		// Run blocks until Execute returns. If it fails, the service manager
+		// will kill the agent for not reporting that it started.
+		_ = svc.Run(agentServiceName, handler)
+	}()
+	return ctx, func() {
+		cancel()
+		close(handler.done)
+	}
+}
+
+type agentServiceHandler struct {
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+func (h *agentServiceHandler) Execute(_ []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				status <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				h.stop()
+			}
+		case <-h.done:
+			return false, 0
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,6 +25,7 @@ func (r *RootCmd) create() *clibase.Cmd {
 		startAt       string
 		stopAfter     time.Duration
 		workspaceName string
+		stateFile     string
 
 		parameterFlags workspaceParameterFlags
 	)
@@ -148,6 +151,24 @@ func (r *RootCmd) create() *clibase.Cmd {
 				return xerrors.Errorf("prepare build: %w", err)
 			}
 
+			var state []byte
+			if stateFile != "" {
+				state, err = os.ReadFile(stateFile)
+				if err != nil {
+					return xerrors.Errorf("read state file: %w", err)
+				}
+				analysis, err := client.TemplateVersionStateAnalysis(inv.Context(), template.ActiveVersionID, codersdk.TemplateVersionStateAnalysisRequest{
+					State: state,
+				})
+				if err != nil {
+					return xerrors.Errorf("analyze state: %w", err)
+				}
+				err = displayStateAnalysis(inv, analysis)
+				if err != nil {
+					return err
+				}
+			}
+
 			_, err = cliui.Prompt(inv, cliui.PromptOptions{
 				Text:      "Confirm create?",
 				IsConfirm: true,
@@ -167,6 +188,7 @@ func (r *RootCmd) create() *clibase.Cmd {
 				AutostartSchedule:   schedSpec,
 				TTLMillis:           ttlMillis,
 				RichParameterValues: richParameters,
+				ProvisionerState:    state,
 			})
 			if err != nil {
 				return xerrors.Errorf("create workspace: %w", err)
@@ -201,12 +223,46 @@ func (r *RootCmd) create() *clibase.Cmd {
 			Description: "Specify a duration after which the workspace should shut down (e.g. 8h).",
 			Value:       clibase.DurationOf(&stopAfter),
 		},
+		clibase.Option{
+			Flag:        "state-file",
+			Env:         "CODER_WORKSPACE_STATE_FILE",
+			Description: "Specify a Terraform state file with infrastructure for the workspace to adopt. Only template managers may provide state.",
+			Value:       clibase.StringOf(&stateFile),
+		},
 		cliui.SkipPromptOption(),
 	)
 	cmd.Options = append(cmd.Options, parameterFlags.cliParameters()...)
 	return cmd
 }
 
+type stateAnalysisRow struct {
+	Resource string `table:"resource,default_sort"`
+	Status   string `table:"status"`
+	Agents   string `table:"agents"`
+}
+
+// displayStateAnalysis shows which resources of a Terraform state the new
+// workspace adopts.
+func displayStateAnalysis(inv *clibase.Invocation, analysis codersdk.TemplateVersionStateAnalysis) error {
+	rows := make([]stateAnalysisRow, 0, len(analysis.Resources))
+	for _, resource := range analysis.Resources {
+		rows = append(rows, stateAnalysisRow{
+			Resource: resource.Type + "." + resource.Name,
+			Status:   string(resource.Status),
+			Agents:   strings.Join(resource.Agents, ", "),
+		})
+	}
+	out, err := cliui.DisplayTable(rows, "", nil)
+	if err != nil {
+		return xerrors.Errorf("render table: %w", err)
+	}
+	_, _ = fmt.Fprintln(inv.Stdout, out)
+	if len(analysis.Warnings) > 0 {
+		cliui.Warn(inv.Stderr, "Review the state before creating the workspace", analysis.Warnings...)
+	}
+	return nil
+}
+
 type prepWorkspaceBuildArgs struct {
 	Action           WorkspaceCLIAction
 	Template         codersdk.Template
@@ -55,6 +55,7 @@ func (r *RootCmd) ping() *clibase.Cmd {
 			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
 				Logger:         logger,
 				BlockEndpoints: r.disableDirect,
+				ClientType:     codersdk.WorkspaceAgentClientTypeCLI,
 			})
 			if err != nil {
 				return err
@@ -109,6 +109,7 @@ func (r *RootCmd) portForward() *clibase.Cmd {
 			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
 				Logger:         logger,
 				BlockEndpoints: r.disableDirect,
+				ClientType:     codersdk.WorkspaceAgentClientTypePortForward,
 			})
 			if err != nil {
 				return err
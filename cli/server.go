@@ -5,6 +5,7 @@ package cli
 import (
 	"context"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/tls"
@@ -20,6 +21,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/pprof"
+	"net/netip"
 	"net/url"
 	"os"
 	"os/signal"
@@ -33,6 +35,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/coreos/go-systemd/daemon"
 	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
@@ -46,6 +49,7 @@ import (
 	"golang.org/x/mod/semver"
 	"golang.org/x/oauth2"
 	xgithub "golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/google"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
 	"google.golang.org/api/idtoken"
@@ -79,12 +83,14 @@ import (
 	"github.com/coder/coder/v2/coderd/oauthpki"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/secretmanager"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/unhanger"
 	"github.com/coder/coder/v2/coderd/updatecheck"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/coderd/workspacehooks"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/provisioner/echo"
@@ -464,6 +470,44 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				)
 			}
 
+			err = workspacehooks.Validate(cfg.WorkspaceHooks.Value)
+			if err != nil {
+				return xerrors.Errorf("validate workspace hooks: %w", err)
+			}
+			err = cfg.PortForwardPolicy.Value.Validate()
+			if err != nil {
+				return xerrors.Errorf("validate port forward policy: %w", err)
+			}
+			for flag, levels := range map[string][]string{
+				"--app-identity-headers":          cfg.AppIdentityHeaders.Value(),
+				"--port-forward-identity-headers": cfg.PortForwardIdentityHeaders.Value(),
+			} {
+				for _, level := range levels {
+					switch codersdk.WorkspaceAppSharingLevel(level) {
+					case codersdk.WorkspaceAppSharingLevelOwner, codersdk.WorkspaceAppSharingLevelAuthenticated, codersdk.WorkspaceAppSharingLevelPublic:
+					default:
+						return xerrors.Errorf("%s: unknown sharing level %q", flag, level)
+					}
+				}
+			}
+			for _, nameserver := range cfg.AgentDNSNameservers.Value() {
+				if _, err := netip.ParseAddr(nameserver); err != nil {
+					return xerrors.Errorf("--agent-dns-nameservers: parse %q: %w", nameserver, err)
+				}
+			}
+			for _, domain := range cfg.AgentDNSSearchDomains.Value() {
+				if domain == "" || strings.ContainsAny(domain, " \t\n") {
+					return xerrors.Errorf("--agent-dns-search-domains: invalid search domain %q", domain)
+				}
+			}
+			keepaliveInterval := cfg.TailnetKeepalive.AgentInterval.Value()
+			if keepaliveInterval <= 0 {
+				return xerrors.Errorf("--agent-keepalive-interval must be positive")
+			}
+			if cfg.TailnetKeepalive.AgentDisconnectTimeout.Value() <= keepaliveInterval {
+				return xerrors.Errorf("--agent-disconnect-timeout must be longer than --agent-keepalive-interval")
+			}
+
 			realIPConfig, err := httpmw.ParseRealIPConfig(cfg.ProxyTrustedHeaders, cfg.ProxyTrustedOrigins)
 			if err != nil {
 				return xerrors.Errorf("parse real ip config: %w", err)
@@ -508,6 +552,10 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 			if httpServers.TLSConfig != nil {
 				options.TLSCertificates = httpServers.TLSConfig.Certificates
 			}
+			// coderd pings agents at the keepalive interval, which is also how
+			// often it refreshes their connection status.
+			options.AgentConnectionUpdateFrequency = cfg.TailnetKeepalive.AgentInterval.Value()
+			options.AgentInactiveDisconnectTimeout = cfg.TailnetKeepalive.AgentDisconnectTimeout.Value()
 
 			if cfg.StrictTransportSecurity > 0 {
 				options.StrictTransportSecurityCfg, err = httpmw.HSTSConfigOptions(
@@ -537,6 +585,11 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				}
 			}
 
+			options.SecretManager, err = configureSecretManager(ctx, cfg.SecretManager)
+			if err != nil {
+				return xerrors.Errorf("configure secret manager: %w", err)
+			}
+
 			if cfg.OAuth2.Github.ClientSecret != "" {
 				options.GithubOAuth2Config, err = configureGithubOAuth2(cfg.AccessURL.Value(),
 					cfg.OAuth2.Github.ClientID.String(),
@@ -743,6 +796,28 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 					return xerrors.Errorf("oauth signing key in database is empty")
 				}
 
+				// Read the SSH certificate authority key from the database. Like
+				// the other keys, generate a new one if it is invalid. Servers
+				// and clients that trust the old one must be updated then.
+				sshCAKeyStr, err := tx.GetSSHCAKey(ctx)
+				if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+					return xerrors.Errorf("get ssh ca key: %w", err)
+				}
+				sshCASeed, err := hex.DecodeString(sshCAKeyStr)
+				if err != nil || len(sshCASeed) != ed25519.SeedSize {
+					sshCASeed = make([]byte, ed25519.SeedSize)
+					_, err := rand.Read(sshCASeed)
+					if err != nil {
+						return xerrors.Errorf("generate fresh ssh ca key: %w", err)
+					}
+
+					err = tx.UpsertSSHCAKey(ctx, hex.EncodeToString(sshCASeed))
+					if err != nil {
+						return xerrors.Errorf("insert freshly generated ssh ca key to database: %w", err)
+					}
+				}
+				options.SSHCAKey = ed25519.NewKeyFromSeed(sshCASeed)
+
 				return nil
 			}, nil)
 			if err != nil {
@@ -803,6 +878,12 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 				}
 				defer closeWorkspacesFunc()
 
+				closeProvisionerJobQueueFunc, err := prometheusmetrics.ProvisionerJobQueue(ctx, logger, options.PrometheusRegistry, options.Database, 0)
+				if err != nil {
+					return xerrors.Errorf("register provisioner job queue prometheus metric: %w", err)
+				}
+				defer closeProvisionerJobQueueFunc()
+
 				if cfg.Prometheus.CollectAgentStats {
 					closeAgentStatsFunc, err := prometheusmetrics.AgentStats(ctx, logger, options.PrometheusRegistry, options.Database, time.Now(), 0)
 					if err != nil {
@@ -986,12 +1067,15 @@ func (r *RootCmd) Server(newAPI func(context.Context, *coderd.Options) (*coderd.
 
 			autobuildTicker := time.NewTicker(cfg.AutobuildPollInterval.Value())
 			defer autobuildTicker.Stop()
-			autobuildExecutor := autobuild.NewExecutor(ctx, options.Database, coderAPI.TemplateScheduleStore, logger, autobuildTicker.C)
+			autobuildExecutor := autobuild.NewExecutor(ctx, options.Database, options.Pubsub, coderAPI.TemplateScheduleStore, logger, autobuildTicker.C)
 			autobuildExecutor.Run()
 
 			hangDetectorTicker := time.NewTicker(cfg.JobHangDetectorInterval.Value())
 			defer hangDetectorTicker.Stop()
-			hangDetector := unhanger.New(ctx, options.Database, options.Pubsub, logger, hangDetectorTicker.C)
+			hangDetector := unhanger.New(ctx, options.Database, options.Pubsub, logger, hangDetectorTicker.C).
+				WithHungJobDuration(cfg.Provisioner.HungJobTimeout.Value()).
+				WithRequeueLimit(int32(cfg.Provisioner.HungJobRequeueLimit.Value())).
+				WithMetrics(options.PrometheusRegistry)
 			hangDetector.Start()
 			defer hangDetector.Close()
 
@@ -1555,6 +1639,40 @@ func configureCAPool(tlsClientCAFile string, tlsConfig *tls.Config) error {
 	return nil
 }
 
+// configureSecretManager returns a manager of the secret managers that are
+// configured, or nil if none are.
+func configureSecretManager(ctx context.Context, cfg codersdk.SecretManagerConfig) (*secretmanager.Manager, error) {
+	providers := map[string]secretmanager.Provider{}
+	if cfg.VaultAddress.String() != "" {
+		if cfg.VaultToken.String() == "" {
+			return nil, xerrors.New("secret-manager-vault-token must be set with secret-manager-vault-address")
+		}
+		providers["vault"] = secretmanager.NewVault(&http.Client{Timeout: time.Minute}, cfg.VaultAddress.Value(), cfg.VaultToken.String())
+	}
+	if cfg.AWSRegion.String() != "" {
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, xerrors.Errorf("load aws config: %w", err)
+		}
+		if awsCfg.Credentials == nil {
+			return nil, xerrors.New("no aws credentials found")
+		}
+		providers["aws"] = secretmanager.NewAWS(&http.Client{Timeout: time.Minute}, awsCfg.Credentials, cfg.AWSRegion.String(), "")
+	}
+	if cfg.GCP.Value() {
+		client, err := google.DefaultClient(ctx, "https://www.googleapis.com/auth/cloud-platform")
+		if err != nil {
+			return nil, xerrors.Errorf("find google application default credentials: %w", err)
+		}
+		client.Timeout = time.Minute
+		providers["gcp"] = secretmanager.NewGCP(client, secretmanager.GCPEndpoint)
+	}
+	if len(providers) == 0 {
+		return nil, nil
+	}
+	return secretmanager.New(providers), nil
+}
+
 //nolint:revive // Ignore flag-parameter: parameter 'allowEveryone' seems to be a control flag, avoid control coupling (revive)
 func configureGithubOAuth2(accessURL *url.URL, clientID, clientSecret string, allowSignups, allowEveryone bool, allowOrgs []string, rawTeams []string, enterpriseBaseURL string) (*coderd.GithubOAuth2Config, error) {
 	redirectURL, err := accessURL.Parse("/api/v2/users/oauth2/github/callback")
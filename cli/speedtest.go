@@ -60,7 +60,8 @@ func (r *RootCmd) speedtest() *clibase.Cmd {
 				_, _ = fmt.Fprintln(inv.Stderr, "Direct connections disabled.")
 			}
 			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
-				Logger: logger,
+				Logger:     logger,
+				ClientType: codersdk.WorkspaceAgentClientTypeCLI,
 			})
 			if err != nil {
 				return err
@@ -52,6 +52,7 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 		noWait         bool
 		logDirPath     string
 		remoteForward  string
+		clientType     string
 	)
 	client := new(codersdk.Client)
 	cmd := &clibase.Cmd{
@@ -201,6 +202,7 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			conn, err := client.DialWorkspaceAgent(ctx, workspaceAgent.ID, &codersdk.DialWorkspaceAgentOptions{
 				Logger:         logger,
 				BlockEndpoints: r.disableDirect,
+				ClientType:     codersdk.WorkspaceAgentClientType(clientType),
 			})
 			if err != nil {
 				return xerrors.Errorf("dial agent: %w", err)
@@ -454,6 +456,18 @@ func (r *RootCmd) ssh() *clibase.Cmd {
 			FlagShorthand: "R",
 			Value:         clibase.StringOf(&remoteForward),
 		},
+		{
+			Flag:        "client-type",
+			Env:         "CODER_SSH_CLIENT_TYPE",
+			Description: "Identifies the client invoking this command, e.g. an IDE connecting through it.",
+			Default:     string(codersdk.WorkspaceAgentClientTypeSSH),
+			Value: clibase.EnumOf(&clientType,
+				string(codersdk.WorkspaceAgentClientTypeSSH),
+				string(codersdk.WorkspaceAgentClientTypeJetBrains),
+				string(codersdk.WorkspaceAgentClientTypeVSCode),
+			),
+			Hidden: true,
+		},
 	}
 	return cmd
 }
@@ -330,14 +330,22 @@ func prettyDirectoryPath(dir string) string {
 	return pretty
 }
 
+// ParseProvisionerTags parses key=value tags. Each raw tag may hold several
+// comma separated tags, e.g. "cloud=aws, region=us-east".
 func ParseProvisionerTags(rawTags []string) (map[string]string, error) {
 	tags := map[string]string{}
 	for _, rawTag := range rawTags {
-		parts := strings.SplitN(rawTag, "=", 2)
-		if len(parts) < 2 {
-			return nil, xerrors.Errorf("invalid tag format for %q. must be key=value", rawTag)
+		for _, tag := range strings.Split(rawTag, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "" {
+				continue
+			}
+			parts := strings.SplitN(tag, "=", 2)
+			if len(parts) < 2 || strings.TrimSpace(parts[0]) == "" {
+				return nil, xerrors.Errorf("invalid tag format for %q. must be key=value", tag)
+			}
+			tags[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
 		}
-		tags[parts[0]] = parts[1]
 	}
 	return tags, nil
 }
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 	"strings"
 	"time"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/cli/cliui"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -28,6 +30,17 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 		allowUserCancelWorkspaceJobs bool
 		allowUserAutostart           bool
 		allowUserAutostop            bool
+		requireActiveVersion         bool
+		requireActiveVersionGrace    time.Duration
+		maxBuildDuration             time.Duration
+		provisionerMemoryLimit       int64
+		provisionerCPULimit          time.Duration
+		maxConcurrentJobs            int64
+		maxSessionIdle               time.Duration
+		requireWorkspaceApproval     bool
+		requireBinaryVerification    bool
+		requirePromotionApproval     bool
+		autostopActivitySources      []string
 	)
 	client := new(codersdk.Client)
 
@@ -109,6 +122,48 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 				AllowUserAutostart:           allowUserAutostart,
 				AllowUserAutostop:            allowUserAutostop,
 			}
+			if inv.ParsedFlags().Changed("require-active-version") {
+				req.RequireActiveVersion = &requireActiveVersion
+			}
+			if inv.ParsedFlags().Changed("require-active-version-grace-period") {
+				req.RequireActiveVersionGracePeriodMillis = ptr.Ref(requireActiveVersionGrace.Milliseconds())
+			}
+			if inv.ParsedFlags().Changed("max-build-duration") {
+				req.MaxBuildDurationMillis = ptr.Ref(maxBuildDuration.Milliseconds())
+			}
+			if inv.ParsedFlags().Changed("provisioner-memory-limit") {
+				req.ProvisionerMemoryLimitBytes = &provisionerMemoryLimit
+			}
+			if inv.ParsedFlags().Changed("provisioner-cpu-limit") {
+				req.ProvisionerCPULimitMillis = ptr.Ref(provisionerCPULimit.Milliseconds())
+			}
+			if inv.ParsedFlags().Changed("max-concurrent-provisioner-jobs") {
+				if maxConcurrentJobs < 0 || maxConcurrentJobs > math.MaxInt32 {
+					return xerrors.Errorf("--max-concurrent-provisioner-jobs must be between 0 and %d", math.MaxInt32)
+				}
+				req.MaxConcurrentProvisionerJobs = ptr.Ref(int32(maxConcurrentJobs))
+			}
+			if inv.ParsedFlags().Changed("max-session-idle") {
+				req.MaxSessionIdleMillis = ptr.Ref(maxSessionIdle.Milliseconds())
+			}
+			if inv.ParsedFlags().Changed("require-workspace-approval") {
+				req.RequireWorkspaceApproval = &requireWorkspaceApproval
+			}
+			if inv.ParsedFlags().Changed("require-agent-binary-verification") {
+				req.RequireAgentBinaryVerification = &requireBinaryVerification
+			}
+			if inv.ParsedFlags().Changed("require-promotion-approval") {
+				req.RequirePromotionApproval = &requirePromotionApproval
+			}
+			if inv.ParsedFlags().Changed("autostop-activity-sources") {
+				sources := []codersdk.AgentActivitySource{}
+				if !(len(autostopActivitySources) == 1 && autostopActivitySources[0] == "none") {
+					for _, source := range autostopActivitySources {
+						sources = append(sources, codersdk.AgentActivitySource(source))
+					}
+				}
+				req.AutostopActivitySources = &sources
+			}
 
 			_, err = client.UpdateTemplateMeta(inv.Context(), template.ID, req)
 			if err != nil {
@@ -204,6 +259,72 @@ func (r *RootCmd) templateEdit() *clibase.Cmd {
 			Default:     "true",
 			Value:       clibase.BoolOf(&allowUserAutostop),
 		},
+		{
+			Flag:        "require-active-version",
+			Description: "Require outdated workspaces to be started with the active template version once the grace period has passed. Template managers are exempt.",
+			Value:       clibase.BoolOf(&requireActiveVersion),
+		},
+		{
+			Flag:        "require-active-version-grace-period",
+			Description: "Edit how long outdated workspaces can still be started with their current template version after the active version changes.",
+			Value:       clibase.DurationOf(&requireActiveVersionGrace),
+		},
+		{
+			Flag:        "max-build-duration",
+			Description: "Edit the maximum duration of the template's builds. Builds running longer are canceled. 0 means no limit.",
+			Value:       clibase.DurationOf(&maxBuildDuration),
+		},
+		{
+			Flag:        "provisioner-memory-limit",
+			Description: "Edit the maximum memory in bytes the provisioner can use for the template's builds. 0 means no limit.",
+			Value:       clibase.Int64Of(&provisionerMemoryLimit),
+		},
+		{
+			Flag:        "provisioner-cpu-limit",
+			Description: "Edit the maximum CPU time the provisioner can use for the template's builds. 0 means no limit.",
+			Value:       clibase.DurationOf(&provisionerCPULimit),
+		},
+		{
+			Flag:        "max-concurrent-provisioner-jobs",
+			Description: "Edit how many provisioner jobs of the template can run at once. Further jobs wait in the queue. 0 means no limit.",
+			Value:       clibase.Int64Of(&maxConcurrentJobs),
+		},
+		{
+			Flag:        "max-session-idle",
+			Description: "Edit how long web terminal and app sessions of the template's workspaces may be idle before they are disconnected. 0 means sessions are never disconnected.",
+			Value:       clibase.DurationOf(&maxSessionIdle),
+		},
+		{
+			Flag:        "require-workspace-approval",
+			Description: "Edit whether the first build of new workspaces is held until another user approves it.",
+			Value:       clibase.BoolOf(&requireWorkspaceApproval),
+		},
+		{
+			Flag:        "require-agent-binary-verification",
+			Description: "Edit whether agents refuse to start when their binary doesn't match the checksum served by the deployment or isn't signed by the agent binary signing certificate.",
+			Value:       clibase.BoolOf(&requireBinaryVerification),
+		},
+		{
+			Flag:        "require-promotion-approval",
+			Description: "Edit whether new versions only become active once a template approver approves their promotion.",
+			Value:       clibase.BoolOf(&requirePromotionApproval),
+		},
+		{
+			Flag:        "autostop-activity-sources",
+			Description: "Edit the kinds of activity that postpone the autostop of workspaces, any of ssh, reconnecting_pty, app and port_forward. To postpone it on any connection, pass 'none'. This is an enterprise-only feature.",
+			Value: clibase.Validate(clibase.StringArrayOf(&autostopActivitySources), func(value *clibase.StringArray) error {
+				v := value.GetSlice()
+				if len(v) == 1 && v[0] == "none" {
+					return nil
+				}
+				for _, source := range v {
+					if !codersdk.AgentActivitySource(source).Valid() {
+						return xerrors.Errorf("invalid activity source %q", source)
+					}
+				}
+				return nil
+			}),
+		},
 		cliui.SkipPromptOption(),
 	}
 
@@ -140,6 +140,7 @@ func (r *RootCmd) vscodeSSH() *clibase.Cmd {
 			agentConn, err := client.DialWorkspaceAgent(ctx, agent.ID, &codersdk.DialWorkspaceAgentOptions{
 				Logger:         logger,
 				BlockEndpoints: r.disableDirect,
+				ClientType:     codersdk.WorkspaceAgentClientTypeVSCode,
 			})
 			if err != nil {
 				return xerrors.Errorf("dial workspace agent: %w", err)
@@ -11,8 +11,31 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
 )
 
+// statsPostponeAutostop returns whether an agent stats report contains
+// activity that postpones the autostop of the workspace. If the template
+// doesn't select kinds of activity, any connection does. Otherwise, only
+// activity of the selected kinds that happened within maxAge does. The
+// timestamps reported by the agent are corrected by its clock offset.
+func statsPostponeAutostop(stats agentsdk.Stats, sources []string, now time.Time, maxAge time.Duration) bool {
+	if len(sources) == 0 {
+		return stats.ConnectionCount > 0
+	}
+	offset := time.Duration(stats.ClockOffsetMS) * time.Millisecond
+	for source, at := range stats.Activity {
+		if !slice.Contains(sources, string(source)) {
+			continue
+		}
+		if now.Sub(at.Add(-offset)) <= maxAge {
+			return true
+		}
+	}
+	return false
+}
+
 // activityBumpWorkspace automatically bumps the workspace's auto-off timer
 // if it is set to expire soon.
 func activityBumpWorkspace(ctx context.Context, log slog.Logger, db database.Store, workspaceID uuid.UUID) {
@@ -2,6 +2,7 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -26,10 +27,12 @@ func TestWorkspaceActivityBump(t *testing.T) {
 
 	ctx := context.Background()
 
-	// deadline allows you to forcibly set a max_deadline on the build. This
-	// doesn't use template restart requirements and instead edits the
-	// max_deadline on the build directly in the database.
-	setupActivityTest := func(t *testing.T, deadline ...time.Duration) (client *codersdk.Client, workspace codersdk.Workspace, assertBumped func(want bool)) {
+	// sources are the kinds of activity that postpone autostop, any
+	// connection does if empty. deadline allows you to forcibly set a
+	// max_deadline on the build. This doesn't use template restart
+	// requirements and instead edits the max_deadline on the build directly
+	// in the database.
+	setupActivityTest := func(t *testing.T, sources []string, deadline ...time.Duration) (client *codersdk.Client, workspace codersdk.Workspace, assertBumped func(want bool)) {
 		const ttl = time.Minute
 		maxTTL := time.Duration(0)
 		if len(deadline) > 0 {
@@ -50,7 +53,8 @@ func TestWorkspaceActivityBump(t *testing.T) {
 						UserAutostopEnabled: true,
 						DefaultTTL:          ttl,
 						// We set max_deadline manually below.
-						RestartRequirement: schedule.TemplateRestartRequirement{},
+						RestartRequirement:      schedule.TemplateRestartRequirement{},
+						AutostopActivitySources: sources,
 					}, nil
 				},
 			},
@@ -174,7 +178,7 @@ func TestWorkspaceActivityBump(t *testing.T) {
 	t.Run("Dial", func(t *testing.T) {
 		t.Parallel()
 
-		client, workspace, assertBumped := setupActivityTest(t)
+		client, workspace, assertBumped := setupActivityTest(t, nil)
 
 		resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
 		conn, err := client.DialWorkspaceAgent(ctx, resources[0].Agents[0].ID, &codersdk.DialWorkspaceAgentOptions{
@@ -195,7 +199,7 @@ func TestWorkspaceActivityBump(t *testing.T) {
 	t.Run("NoBump", func(t *testing.T) {
 		t.Parallel()
 
-		client, workspace, assertBumped := setupActivityTest(t)
+		client, workspace, assertBumped := setupActivityTest(t, nil)
 
 		// Benign operations like retrieving workspace must not
 		// bump the deadline.
@@ -205,12 +209,47 @@ func TestWorkspaceActivityBump(t *testing.T) {
 		assertBumped(false)
 	})
 
+	t.Run("ActivitySources", func(t *testing.T) {
+		t.Parallel()
+
+		client, workspace, assertBumped := setupActivityTest(t, []string{string(codersdk.AgentActivitySourceReconnectingPTY)})
+
+		resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+		conn, err := client.DialWorkspaceAgent(ctx, resources[0].Agents[0].ID, &codersdk.DialWorkspaceAgentOptions{
+			Logger: slogtest.Make(t, nil),
+		})
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// Must send network traffic after a few seconds to surpass bump threshold.
+		time.Sleep(time.Second * 3)
+
+		// Connections without activity of the selected kinds must not bump
+		// the deadline.
+		sshConn, err := conn.SSHClient(ctx)
+		require.NoError(t, err)
+		_ = sshConn.Close()
+		assertBumped(false)
+
+		ptyConn, err := conn.ReconnectingPTY(ctx, uuid.New(), 80, 80, "/bin/sh")
+		require.NoError(t, err)
+		defer ptyConn.Close()
+		data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+			Data: "echo test\r\n",
+		})
+		require.NoError(t, err)
+		_, err = ptyConn.Write(data)
+		require.NoError(t, err)
+
+		assertBumped(true)
+	})
+
 	t.Run("NotExceedMaxDeadline", func(t *testing.T) {
 		t.Parallel()
 
 		// Set the max deadline to be in 61 seconds. We bump by 1 minute, so we
 		// should expect the deadline to match the max deadline exactly.
-		client, workspace, assertBumped := setupActivityTest(t, 61*time.Second)
+		client, workspace, assertBumped := setupActivityTest(t, nil, 61*time.Second)
 
 		// Bump by dialing the workspace and sending traffic.
 		resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
@@ -160,6 +160,23 @@ const docTemplate = `{
                 }
             }
         },
+        "/applications/jwks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Applications"
+                ],
+                "summary": "Get workspace app identity keys",
+                "operationId": "get-workspace-app-identity-keys",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
         "/applications/reconnecting-pty-signed-token": {
             "post": {
                 "security": [
@@ -255,6 +272,108 @@ const docTemplate = `{
                 }
             }
         },
+        "/audit/archivals": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "Returns the most recent audit log archival runs, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get audit log archivals",
+                "operationId": "get-audit-log-archivals",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.AuditLogArchival"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "Starts a run that archives audit logs older than the cutoff to\nthe configured destination and deletes them. The run continues\nin the background.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Create audit log archival",
+                "operationId": "create-audit-log-archival",
+                "parameters": [
+                    {
+                        "description": "Create audit log archival request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateAuditLogArchivalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.AuditLogArchival"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit/archivals/{archival}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get audit log archival by ID",
+                "operationId": "get-audit-log-archival-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Audit log archival ID",
+                        "name": "archival",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.AuditLogArchival"
+                        }
+                    }
+                }
+            }
+        },
         "/audit/testgenerate": {
             "post": {
                 "security": [
@@ -327,6 +446,62 @@ const docTemplate = `{
                 }
             }
         },
+        "/authcheck/effective": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "Explains which roles and ACL entries grant or deny an action\non an object to a user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorization"
+                ],
+                "summary": "Explain effective permissions",
+                "operationId": "explain-effective-permissions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID or username",
+                        "name": "user",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resource type, optionally followed by a colon and a resource ID",
+                        "name": "object",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "create",
+                            "read",
+                            "update",
+                            "delete",
+                            "push_version",
+                            "manage_acl"
+                        ],
+                        "type": "string",
+                        "description": "Action, defaults to read",
+                        "name": "action",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.EffectivePermissions"
+                        }
+                    }
+                }
+            }
+        },
         "/buildinfo": {
             "get": {
                 "produces": [
@@ -380,6 +555,31 @@ const docTemplate = `{
                 }
             }
         },
+        "/debug/connections": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debug"
+                ],
+                "summary": "Debug connections",
+                "operationId": "debug-connections",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DebugConnectionsReport"
+                        }
+                    }
+                }
+            }
+        },
         "/debug/coordinator": {
             "get": {
                 "security": [
@@ -388,7 +588,7 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "text/html"
+                    "application/json"
                 ],
                 "tags": [
                     "Debug"
@@ -397,7 +597,35 @@ const docTemplate = `{
                 "operationId": "debug-info-wireguard-coordinator",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/healthcheck.CoordinatorReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/debug/derp/usage": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Debug"
+                ],
+                "summary": "Debug DERP usage",
+                "operationId": "debug-derp-usage",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DERPUsageReport"
+                        }
                     }
                 }
             }
@@ -480,7 +708,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/deployment/ssh": {
+        "/deployment/config/diff": {
             "get": {
                 "security": [
                     {
@@ -493,19 +721,19 @@ const docTemplate = `{
                 "tags": [
                     "General"
                 ],
-                "summary": "SSH Config",
-                "operationId": "ssh-config",
+                "summary": "Get deployment config diff against defaults",
+                "operationId": "get-deployment-config-diff-against-defaults",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.SSHConfigResponse"
+                            "$ref": "#/definitions/codersdk.DeploymentConfigDiff"
                         }
                     }
                 }
             }
         },
-        "/deployment/stats": {
+        "/deployment/config/redacted": {
             "get": {
                 "security": [
                     {
@@ -518,63 +746,64 @@ const docTemplate = `{
                 "tags": [
                     "General"
                 ],
-                "summary": "Get deployment stats",
-                "operationId": "get-deployment-stats",
+                "summary": "Get redacted deployment config",
+                "operationId": "get-redacted-deployment-config",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.DeploymentStats"
+                            "$ref": "#/definitions/codersdk.DeploymentConfig"
                         }
                     }
                 }
             }
         },
-        "/derp-map": {
+        "/deployment/ssh": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "General"
                 ],
-                "summary": "Get DERP map updates",
-                "operationId": "get-derp-map-updates",
+                "summary": "SSH Config",
+                "operationId": "ssh-config",
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.SSHConfigResponse"
+                        }
                     }
                 }
             }
         },
-        "/entitlements": {
+        "/deployment/ssh/ca": {
             "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "General"
                 ],
-                "summary": "Get entitlements",
-                "operationId": "get-entitlements",
+                "summary": "Get SSH certificate authority",
+                "operationId": "get-ssh-certificate-authority",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Entitlements"
+                            "$ref": "#/definitions/codersdk.SSHCertificateAuthority"
                         }
                     }
                 }
             }
         },
-        "/experiments": {
+        "/deployment/stats": {
             "get": {
                 "security": [
                     {
@@ -587,68 +816,45 @@ const docTemplate = `{
                 "tags": [
                     "General"
                 ],
-                "summary": "Get experiments",
-                "operationId": "get-experiments",
+                "summary": "Get deployment stats",
+                "operationId": "get-deployment-stats",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Experiment"
-                            }
+                            "$ref": "#/definitions/codersdk.DeploymentStats"
                         }
                     }
                 }
             }
         },
-        "/files": {
-            "post": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "description": "Swagger notice: Swagger 2.0 doesn't support file upload with a ` + "`" + `content-type` + "`" + ` different than ` + "`" + `application/x-www-form-urlencoded` + "`" + `.",
-                "consumes": [
-                    "application/x-tar"
-                ],
+        "/deployment/status": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Files"
-                ],
-                "summary": "Upload file",
-                "operationId": "upload-file",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "default": "application/x-tar",
-                        "description": "Content-Type must be ` + "`" + `application/x-tar` + "`" + `",
-                        "name": "Content-Type",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "File to be uploaded",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
-                    }
+                    "General"
                 ],
+                "summary": "Get deployment status",
+                "operationId": "get-deployment-status",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UploadResponse"
+                            "$ref": "#/definitions/codersdk.DeploymentStatus"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DeploymentStatus"
                         }
                     }
                 }
             }
         },
-        "/files/{fileID}": {
+        "/derp-map": {
             "get": {
                 "security": [
                     {
@@ -656,28 +862,18 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "Files"
-                ],
-                "summary": "Get file by ID",
-                "operationId": "get-file-by-id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "File ID",
-                        "name": "fileID",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Agents"
                 ],
+                "summary": "Get DERP map updates",
+                "operationId": "get-derp-map-updates",
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "101": {
+                        "description": "Switching Protocols"
                     }
                 }
             }
         },
-        "/gitauth/{gitauth}": {
+        "/entitlements": {
             "get": {
                 "security": [
                     {
@@ -688,31 +884,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Git"
-                ],
-                "summary": "Get git auth by ID",
-                "operationId": "get-git-auth-by-id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "string",
-                        "description": "Git Provider ID",
-                        "name": "gitauth",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Enterprise"
                 ],
+                "summary": "Get entitlements",
+                "operationId": "get-entitlements",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GitAuth"
+                            "$ref": "#/definitions/codersdk.Entitlements"
                         }
                     }
                 }
             }
         },
-        "/gitauth/{gitauth}/device": {
+        "/entitlements/history": {
             "get": {
                 "security": [
                     {
@@ -723,119 +909,59 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Git"
-                ],
-                "summary": "Get git auth device by ID.",
-                "operationId": "get-git-auth-device-by-id",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "string",
-                        "description": "Git Provider ID",
-                        "name": "gitauth",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.GitAuthDevice"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "tags": [
-                    "Git"
+                    "Enterprise"
                 ],
-                "summary": "Post git auth device by ID",
-                "operationId": "post-git-auth-device-by-id",
+                "summary": "Get entitlements history",
+                "operationId": "get-entitlements-history",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "string",
-                        "description": "Git Provider ID",
-                        "name": "gitauth",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
-            }
-        },
-        "/groups/{group}": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Enterprise"
-                ],
-                "summary": "Get group by ID",
-                "operationId": "get-group-by-id",
-                "parameters": [
+                        "description": "First UTC day to return, formatted as YYYY-MM-DD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Group id",
-                        "name": "group",
-                        "in": "path",
-                        "required": true
+                        "description": "Last UTC day to return, formatted as YYYY-MM-DD",
+                        "name": "end_date",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Group"
+                            "$ref": "#/definitions/codersdk.EntitlementsHistory"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/environmentvariables/{environmentvariable}": {
             "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "Organizations"
                 ],
-                "summary": "Delete group by name",
-                "operationId": "delete-group-by-name",
+                "summary": "Delete environment variable",
+                "operationId": "delete-environment-variable",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group name",
-                        "name": "group",
+                        "format": "uuid",
+                        "description": "Environment variable ID",
+                        "name": "environmentvariable",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Group"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             },
@@ -852,25 +978,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Organizations"
                 ],
-                "summary": "Update group by name",
-                "operationId": "update-group-by-name",
+                "summary": "Update environment variable",
+                "operationId": "update-environment-variable",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Group name",
-                        "name": "group",
+                        "format": "uuid",
+                        "description": "Environment variable ID",
+                        "name": "environmentvariable",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Patch group request",
+                        "description": "Update environment variable request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.PatchGroupRequest"
+                            "$ref": "#/definitions/codersdk.UpdateManagedEnvironmentVariableRequest"
                         }
                     }
                 ],
@@ -878,13 +1005,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Group"
+                            "$ref": "#/definitions/codersdk.ManagedEnvironmentVariable"
                         }
                     }
                 }
             }
         },
-        "/insights/daus": {
+        "/experiments": {
             "get": {
                 "security": [
                     {
@@ -895,21 +1022,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Insights"
+                    "General"
                 ],
-                "summary": "Get deployment DAUs",
-                "operationId": "get-deployment-daus",
+                "summary": "Get experiments",
+                "operationId": "get-experiments",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.DAUsResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Experiment"
+                            }
                         }
                     }
                 }
             }
         },
-        "/insights/templates": {
+        "/experiments/config": {
             "get": {
                 "security": [
                     {
@@ -920,46 +1050,58 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Insights"
+                    "General"
                 ],
-                "summary": "Get insights about templates",
-                "operationId": "get-insights-about-templates",
+                "summary": "Get experiments config",
+                "operationId": "get-experiments-config",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateInsightsResponse"
+                            "$ref": "#/definitions/codersdk.ExperimentsConfig"
                         }
                     }
                 }
-            }
-        },
-        "/insights/user-latency": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Insights"
+                    "General"
+                ],
+                "summary": "Update runtime experiments",
+                "operationId": "update-runtime-experiments",
+                "parameters": [
+                    {
+                        "description": "Runtime experiments",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateRuntimeExperimentsRequest"
+                        }
+                    }
                 ],
-                "summary": "Get insights about user latency",
-                "operationId": "get-insights-about-user-latency",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UserLatencyInsightsResponse"
+                            "$ref": "#/definitions/codersdk.ExperimentsConfig"
                         }
                     }
                 }
             }
         },
-        "/licenses": {
+        "/feature-flags": {
             "get": {
                 "security": [
                     {
@@ -970,87 +1112,97 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "General"
                 ],
-                "summary": "Get licenses",
-                "operationId": "get-licenses",
+                "summary": "Get feature flags",
+                "operationId": "get-feature-flags",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.License"
-                            }
+                            "$ref": "#/definitions/codersdk.FeatureFlags"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/files": {
             "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Swagger notice: Swagger 2.0 doesn't support file upload with a ` + "`" + `content-type` + "`" + ` different than ` + "`" + `application/x-www-form-urlencoded` + "`" + `.",
                 "consumes": [
-                    "application/json"
+                    "application/x-tar"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organizations"
+                    "Files"
                 ],
-                "summary": "Add new license",
-                "operationId": "add-new-license",
+                "summary": "Upload file",
+                "operationId": "upload-file",
                 "parameters": [
                     {
-                        "description": "Add license request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.AddLicenseRequest"
-                        }
+                        "type": "string",
+                        "default": "application/x-tar",
+                        "description": "Content-Type must be ` + "`" + `application/x-tar` + "`" + `",
+                        "name": "Content-Type",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to be uploaded",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.License"
+                            "$ref": "#/definitions/codersdk.UploadResponse"
                         }
                     }
                 }
             }
         },
-        "/licenses/refresh-entitlements": {
-            "post": {
+        "/files/{fileID}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Organizations"
+                    "Files"
+                ],
+                "summary": "Get file by ID",
+                "operationId": "get-file-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "File ID",
+                        "name": "fileID",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Update license entitlements",
-                "operationId": "update-license-entitlements",
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
-                        }
+                    "200": {
+                        "description": "OK"
                     }
                 }
             }
         },
-        "/licenses/{id}": {
-            "delete": {
+        "/gitauth/{gitauth}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -1060,67 +1212,93 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Git"
                 ],
-                "summary": "Delete license",
-                "operationId": "delete-license",
+                "summary": "Get git auth by ID",
+                "operationId": "get-git-auth-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "number",
-                        "description": "License ID",
-                        "name": "id",
+                        "format": "string",
+                        "description": "Git Provider ID",
+                        "name": "gitauth",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GitAuth"
+                        }
                     }
                 }
             }
         },
-        "/organizations": {
-            "post": {
+        "/gitauth/{gitauth}/device": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Organizations"
+                    "Git"
                 ],
-                "summary": "Create organization",
-                "operationId": "create-organization",
+                "summary": "Get git auth device by ID.",
+                "operationId": "get-git-auth-device-by-id",
                 "parameters": [
                     {
-                        "description": "Create organization request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateOrganizationRequest"
-                        }
+                        "type": "string",
+                        "format": "string",
+                        "description": "Git Provider ID",
+                        "name": "gitauth",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Organization"
+                            "$ref": "#/definitions/codersdk.GitAuthDevice"
                         }
                     }
                 }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Git"
+                ],
+                "summary": "Post git auth device by ID",
+                "operationId": "post-git-auth-device-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "string",
+                        "description": "Git Provider ID",
+                        "name": "gitauth",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
             }
         },
-        "/organizations/{organization}": {
+        "/groups/{group}": {
             "get": {
                 "security": [
                     {
@@ -1131,16 +1309,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Organizations"
+                    "Enterprise"
                 ],
-                "summary": "Get organization by ID",
-                "operationId": "get-organization-by-id",
+                "summary": "Get group by ID",
+                "operationId": "get-group-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
+                        "description": "Group id",
+                        "name": "group",
                         "in": "path",
                         "required": true
                     }
@@ -1149,14 +1326,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Organization"
+                            "$ref": "#/definitions/codersdk.Group"
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}/groups": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -1168,14 +1343,13 @@ const docTemplate = `{
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "Get groups by organization",
-                "operationId": "get-groups-by-organization",
+                "summary": "Delete group by name",
+                "operationId": "delete-group-by-name",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
+                        "description": "Group name",
+                        "name": "group",
                         "in": "path",
                         "required": true
                     }
@@ -1184,15 +1358,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Group"
-                            }
+                            "$ref": "#/definitions/codersdk.Group"
                         }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -1207,29 +1378,35 @@ const docTemplate = `{
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "Create group for organization",
-                "operationId": "create-group-for-organization",
+                "summary": "Update group by name",
+                "operationId": "update-group-by-name",
                 "parameters": [
                     {
-                        "description": "Create group request",
+                        "type": "string",
+                        "description": "Group name",
+                        "name": "group",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version of the group the patch is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Patch group request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateGroupRequest"
+                            "$ref": "#/definitions/codersdk.PatchGroupRequest"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/codersdk.Group"
                         }
@@ -1237,35 +1414,78 @@ const docTemplate = `{
                 }
             }
         },
-        "/organizations/{organization}/groups/{groupName}": {
+        "/groups/{group}/avatar": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get group avatar",
+                "operationId": "get-group-avatar",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group id",
+                        "name": "group",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hash of the avatar, as in the avatar URL of the group",
+                        "name": "hash",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "Swagger notice: Swagger 2.0 doesn't support file upload with a ` + "`" + `content-type` + "`" + ` different than ` + "`" + `application/x-www-form-urlencoded` + "`" + `.",
+                "consumes": [
+                    "image/png"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "Get group by organization and group name",
-                "operationId": "get-group-by-organization-and-group-name",
+                "summary": "Upload group avatar",
+                "operationId": "upload-group-avatar",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
+                        "description": "Group id",
+                        "name": "group",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "Group name",
-                        "name": "groupName",
-                        "in": "path",
+                        "default": "image/png",
+                        "description": "Content-Type must be ` + "`" + `image/png` + "`" + `, ` + "`" + `image/jpeg` + "`" + `, ` + "`" + `image/gif` + "`" + ` or ` + "`" + `image/webp` + "`" + `",
+                        "name": "Content-Type",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Avatar to be uploaded",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
                     }
                 ],
@@ -1279,7 +1499,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/organizations/{organization}/members/roles": {
+        "/groups/{group}/default-templates": {
             "get": {
                 "security": [
                     {
@@ -1290,16 +1510,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Members"
+                    "Enterprise"
                 ],
-                "summary": "Get member roles by organization",
-                "operationId": "get-member-roles-by-organization",
+                "summary": "Get group default templates",
+                "operationId": "get-group-default-templates",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
+                        "description": "Group id",
+                        "name": "group",
                         "in": "path",
                         "required": true
                     }
@@ -1310,14 +1529,12 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                                "$ref": "#/definitions/codersdk.GroupDefaultTemplate"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}/members/{user}/roles": {
+            },
             "put": {
                 "security": [
                     {
@@ -1331,32 +1548,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Members"
+                    "Enterprise"
                 ],
-                "summary": "Assign role to organization member",
-                "operationId": "assign-role-to-organization-member",
+                "summary": "Update group default templates",
+                "operationId": "update-group-default-templates",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "description": "Group id",
+                        "name": "group",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update roles request",
+                        "description": "Update group default templates request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateRoles"
+                            "$ref": "#/definitions/codersdk.UpdateGroupDefaultTemplatesRequest"
                         }
                     }
                 ],
@@ -1364,67 +1574,41 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.OrganizationMember"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.GroupDefaultTemplate"
+                            }
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/members/{user}/workspaces": {
-            "post": {
+        "/insights/agent-first-connect": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
-                ],
-                "summary": "Create user workspace by organization",
-                "operationId": "create-user-workspace-by-organization",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Username, UUID, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Create workspace request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateWorkspaceRequest"
-                        }
-                    }
+                    "Insights"
                 ],
+                "summary": "Get insights about agent first connect times",
+                "operationId": "get-insights-about-agent-first-connect-times",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "$ref": "#/definitions/codersdk.AgentFirstConnectInsightsResponse"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/provisionerdaemons": {
+        "/insights/daus": {
             "get": {
                 "security": [
                     {
@@ -1435,63 +1619,71 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
-                ],
-                "summary": "Get provisioner daemons",
-                "operationId": "get-provisioner-daemons",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Insights"
                 ],
+                "summary": "Get deployment DAUs",
+                "operationId": "get-deployment-daus",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerDaemon"
-                            }
+                            "$ref": "#/definitions/codersdk.DAUsResponse"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/provisionerdaemons/serve": {
+        "/insights/netcheck": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Enterprise"
+                    "Insights"
                 ],
-                "summary": "Serve provisioner daemon",
-                "operationId": "serve-provisioner-daemon",
+                "summary": "Get insights about netcheck reports",
+                "operationId": "get-insights-about-netcheck-reports",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
+                        "format": "date-time",
+                        "description": "Start time",
+                        "name": "start_time",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "End time",
+                        "name": "end_time",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID or me",
+                        "name": "user_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.NetcheckInsightsResponse"
+                        }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates": {
+        "/insights/template-build-slos": {
             "get": {
                 "security": [
                     {
@@ -1502,78 +1694,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
-                ],
-                "summary": "Get templates by organization",
-                "operationId": "get-templates-by-organization",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Insights"
                 ],
+                "summary": "Get insights about template build SLOs",
+                "operationId": "get-insights-about-template-build-slos",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Template"
-                            }
+                            "$ref": "#/definitions/codersdk.TemplateBuildSLOInsightsResponse"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/insights/templates": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
-                ],
-                "summary": "Create template by organization",
-                "operationId": "create-template-by-organization",
-                "parameters": [
-                    {
-                        "description": "Request body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateRequest"
-                        }
-                    },
-                    {
-                        "type": "string",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Insights"
                 ],
+                "summary": "Get insights about templates",
+                "operationId": "get-insights-about-templates",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "$ref": "#/definitions/codersdk.TemplateInsightsResponse"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/examples": {
+        "/insights/user-bandwidth": {
             "get": {
                 "security": [
                     {
@@ -1584,34 +1744,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
-                ],
-                "summary": "Get template examples by organization",
-                "operationId": "get-template-examples-by-organization",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Insights"
                 ],
+                "summary": "Get insights about user bandwidth",
+                "operationId": "get-insights-about-user-bandwidth",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateExample"
-                            }
+                            "$ref": "#/definitions/codersdk.UserBandwidthInsightsResponse"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/{templatename}": {
+        "/insights/user-latency": {
             "get": {
                 "security": [
                     {
@@ -1622,38 +1769,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
-                ],
-                "summary": "Get templates by organization and template name",
-                "operationId": "get-templates-by-organization-and-template-name",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Insights"
                 ],
+                "summary": "Get insights about user latency",
+                "operationId": "get-insights-about-user-latency",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "$ref": "#/definitions/codersdk.UserLatencyInsightsResponse"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}": {
+        "/licenses": {
             "get": {
                 "security": [
                     {
@@ -1664,94 +1794,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
-                ],
-                "summary": "Get template version by organization, template, and name",
-                "operationId": "get-template-version-by-organization-template-and-name",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Enterprise"
                 ],
+                "summary": "Get licenses",
+                "operationId": "get-licenses",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.License"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}/previous": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Organizations"
                 ],
-                "summary": "Get previous template version by organization, template, and name",
-                "operationId": "get-previous-template-version-by-organization-template-and-name",
+                "summary": "Add new license",
+                "operationId": "add-new-license",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template name",
-                        "name": "templatename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
-                        "in": "path",
-                        "required": true
+                        "description": "Add license request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.AddLicenseRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/codersdk.License"
                         }
                     }
                 }
             }
         },
-        "/organizations/{organization}/templateversions": {
+        "/licenses/activate": {
             "post": {
                 "security": [
                     {
@@ -1765,26 +1862,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Organizations"
                 ],
-                "summary": "Create template version by organization",
-                "operationId": "create-template-version-by-organization",
+                "summary": "Activate license",
+                "operationId": "activate-license",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Organization ID",
-                        "name": "organization",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Create template version request",
+                        "description": "Activate license request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateVersionRequest"
+                            "$ref": "#/definitions/codersdk.ActivateLicenseRequest"
                         }
                     }
                 ],
@@ -1792,14 +1881,14 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/codersdk.License"
                         }
                     }
                 }
             }
         },
-        "/regions": {
-            "get": {
+        "/licenses/refresh-entitlements": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -1809,21 +1898,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "WorkspaceProxies"
+                    "Organizations"
                 ],
-                "summary": "Get site-wide regions for workspace connections",
-                "operationId": "get-site-wide-regions-for-workspace-connections",
+                "summary": "Update license entitlements",
+                "operationId": "update-license-entitlements",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_Region"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/replicas": {
+        "/licenses/usage": {
             "get": {
                 "security": [
                     {
@@ -1836,186 +1925,224 @@ const docTemplate = `{
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "Get active replicas",
-                "operationId": "get-active-replicas",
+                "summary": "Get license usage",
+                "operationId": "get-license-usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First UTC day to report, formatted as YYYY-MM-DD",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last UTC day to report, formatted as YYYY-MM-DD",
+                        "name": "end_date",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.Replica"
-                            }
+                            "$ref": "#/definitions/codersdk.LicenseUsage"
                         }
                     }
                 }
             }
         },
-        "/scim/v2/Users": {
-            "get": {
+        "/licenses/{id}": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "SCIM 2.0: Get users",
-                "operationId": "scim-get-users",
+                "summary": "Delete license",
+                "operationId": "delete-license",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "number",
+                        "description": "License ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK"
                     }
                 }
-            },
+            }
+        },
+        "/netcheck": {
             "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "General"
                 ],
-                "summary": "SCIM 2.0: Create new user",
-                "operationId": "scim-create-new-user",
+                "summary": "Post netcheck report",
+                "operationId": "post-netcheck-report",
                 "parameters": [
                     {
-                        "description": "New user",
+                        "description": "Netcheck report",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/codersdk.PostNetcheckReportRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
+                            "$ref": "#/definitions/codersdk.NetcheckReport"
                         }
                     }
                 }
             }
         },
-        "/scim/v2/Users/{id}": {
-            "get": {
+        "/organizations": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Organizations"
                 ],
-                "summary": "SCIM 2.0: Get user by ID",
-                "operationId": "scim-get-user-by-id",
+                "summary": "Create organization",
+                "operationId": "create-organization",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Create organization request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateOrganizationRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "404": {
-                        "description": "Not Found"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Organization"
+                        }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/organizations/{organization}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "produces": [
-                    "application/scim+json"
+                    "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Organizations"
                 ],
-                "summary": "SCIM 2.0: Update user account",
-                "operationId": "scim-update-user-status",
+                "summary": "Get organization by ID",
+                "operationId": "get-organization-by-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
-                        "name": "id",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update user request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/coderd.SCIMUser"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.Organization"
                         }
                     }
                 }
-            }
-        },
-        "/templates/{template}": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Organizations"
                 ],
-                "summary": "Get template metadata by ID",
-                "operationId": "get-template-metadata-by-id",
+                "summary": "Update organization",
+                "operationId": "update-organization",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update organization request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateOrganizationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "$ref": "#/definitions/codersdk.Organization"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/organizations/{organization}/environmentvariables": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2025,16 +2152,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Organizations"
                 ],
-                "summary": "Delete template by ID",
-                "operationId": "delete-template-by-id",
+                "summary": "Get organization environment variables",
+                "operationId": "get-organization-environment-variables",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
@@ -2043,46 +2170,62 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ManagedEnvironmentVariable"
+                            }
                         }
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Organization environment variables are injected into the agents\nof every workspace in the organization.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Organizations"
                 ],
-                "summary": "Update template metadata by ID",
-                "operationId": "update-template-metadata-by-id",
+                "summary": "Create organization environment variable",
+                "operationId": "create-organization-environment-variable",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Create environment variable request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateManagedEnvironmentVariableRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Template"
+                            "$ref": "#/definitions/codersdk.ManagedEnvironmentVariable"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/acl": {
+        "/organizations/{organization}/groups": {
             "get": {
                 "security": [
                     {
@@ -2095,14 +2238,14 @@ const docTemplate = `{
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "Get template ACLs",
-                "operationId": "get-template-acls",
+                "summary": "Get groups by organization",
+                "operationId": "get-groups-by-organization",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
@@ -2113,13 +2256,13 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.TemplateUser"
+                                "$ref": "#/definitions/codersdk.Group"
                             }
                         }
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2134,38 +2277,37 @@ const docTemplate = `{
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "Update template ACL",
-                "operationId": "update-template-acl",
+                "summary": "Create group for organization",
+                "operationId": "create-group-for-organization",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update template request",
+                        "description": "Create group request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateTemplateACL"
+                            "$ref": "#/definitions/codersdk.CreateGroupRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.Group"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/acl/available": {
+        "/organizations/{organization}/groups/{groupName}": {
             "get": {
                 "security": [
                     {
@@ -2178,14 +2320,21 @@ const docTemplate = `{
                 "tags": [
                     "Enterprise"
                 ],
-                "summary": "Get template available acl users/groups",
-                "operationId": "get-template-available-acl-usersgroups",
+                "summary": "Get group by organization and group name",
+                "operationId": "get-group-by-organization-and-group-name",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group name",
+                        "name": "groupName",
                         "in": "path",
                         "required": true
                     }
@@ -2194,16 +2343,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ACLAvailable"
-                            }
+                            "$ref": "#/definitions/codersdk.Group"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/daus": {
+        "/organizations/{organization}/members/roles": {
             "get": {
                 "security": [
                     {
@@ -2214,16 +2360,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "Get template DAUs by ID",
-                "operationId": "get-template-daus-by-id",
+                "summary": "Get member roles by organization",
+                "operationId": "get-member-roles-by-organization",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
@@ -2232,69 +2378,70 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.DAUsResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                            }
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/versions": {
-            "get": {
+        "/organizations/{organization}/members/{user}/roles": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Members"
                 ],
-                "summary": "List template versions by template ID",
-                "operationId": "list-template-versions-by-template-id",
+                "summary": "Assign role to organization member",
+                "operationId": "assign-role-to-organization-member",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "After ID",
-                        "name": "after_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Update roles request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateRoles"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersion"
-                            }
+                            "$ref": "#/definitions/codersdk.OrganizationMember"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/organizations/{organization}/members/{user}/workspaces": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2307,40 +2454,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Workspaces"
                 ],
-                "summary": "Update active template version by template ID",
-                "operationId": "update-active-template-version-by-template-id",
+                "summary": "Create user workspace by organization",
+                "operationId": "create-user-workspace-by-organization",
                 "parameters": [
                     {
-                        "description": "Modified template version",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateActiveTemplateVersion"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
+                        "description": "Username, UUID, or me",
+                        "name": "user",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Create workspace request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.Workspace"
                         }
                     }
                 }
             }
         },
-        "/templates/{template}/versions/{templateversionname}": {
+        "/organizations/{organization}/provisionerdaemons": {
             "get": {
                 "security": [
                     {
@@ -2351,23 +2505,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template version by template ID and name",
-                "operationId": "get-template-version-by-template-id-and-name",
+                "summary": "Get provisioner daemons",
+                "operationId": "get-provisioner-daemons",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template ID",
-                        "name": "template",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Template version name",
-                        "name": "templateversionname",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
@@ -2378,14 +2525,14 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersion"
+                                "$ref": "#/definitions/codersdk.ProvisionerDaemon"
                             }
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}": {
+        "/organizations/{organization}/provisionerdaemons/pending-jobs": {
             "get": {
                 "security": [
                     {
@@ -2396,16 +2543,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template version by ID",
-                "operationId": "get-template-version-by-id",
+                "summary": "Get pending provisioner jobs and matching daemons",
+                "operationId": "get-pending-provisioner-jobs-and-matching-daemons",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
@@ -2414,168 +2561,167 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.PendingProvisionerJob"
+                            }
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/organizations/{organization}/provisionerdaemons/queue": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Patch template version by ID",
-                "operationId": "patch-template-version-by-id",
+                "summary": "Get provisioner job queue stats",
+                "operationId": "get-provisioner-job-queue-stats",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Patch template version request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.PatchTemplateVersionRequest"
-                        }
+                        "enum": [
+                            "echo",
+                            "terraform"
+                        ],
+                        "type": "string",
+                        "description": "Provisioner type",
+                        "name": "provisioner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Provisioner daemon tags in the form key=value",
+                        "name": "tag",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TemplateVersion"
+                            "$ref": "#/definitions/codersdk.ProvisionerJobQueue"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/cancel": {
-            "patch": {
+        "/organizations/{organization}/provisionerdaemons/serve": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Cancel template version by ID",
-                "operationId": "cancel-template-version-by-id",
+                "summary": "Serve provisioner daemon",
+                "operationId": "serve-provisioner-daemon",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
-                        }
+                    "101": {
+                        "description": "Switching Protocols"
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/dry-run": {
-            "post": {
+        "/organizations/{organization}/templates": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Create template version dry-run",
-                "operationId": "create-template-version-dry-run",
-                "parameters": [
+                "summary": "Get templates by organization",
+                "operationId": "get-templates-by-organization",
+                "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Dry-run request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTemplateVersionDryRunRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.ProvisionerJob"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Template"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/templateversions/{templateversion}/dry-run/{jobID}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template version dry-run by job ID",
-                "operationId": "get-template-version-dry-run-by-job-id",
+                "summary": "Create template by organization",
+                "operationId": "create-template-by-organization",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
+                        "description": "Request body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTemplateRequest"
+                        }
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
@@ -2584,14 +2730,14 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.ProvisionerJob"
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/dry-run/{jobID}/cancel": {
-            "patch": {
+        "/organizations/{organization}/templates/examples": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2603,22 +2749,14 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Cancel template version dry-run by job ID",
-                "operationId": "cancel-template-version-dry-run-by-job-id",
+                "summary": "Get template examples by organization",
+                "operationId": "get-template-examples-by-organization",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     }
@@ -2627,13 +2765,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateExample"
+                            }
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/dry-run/{jobID}/logs": {
+        "/organizations/{organization}/templates/{templatename}": {
             "get": {
                 "security": [
                     {
@@ -2646,58 +2787,36 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template version dry-run logs by job ID",
-                "operationId": "get-template-version-dry-run-logs-by-job-id",
+                "summary": "Get templates by organization and template name",
+                "operationId": "get-templates-by-organization-and-template-name",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
+                        "description": "Template name",
+                        "name": "templatename",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Before Unix timestamp",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After Unix timestamp",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
-                            }
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/dry-run/{jobID}/resources": {
+        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}": {
             "get": {
                 "security": [
                     {
@@ -2710,22 +2829,28 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get template version dry-run resources by job ID",
-                "operationId": "get-template-version-dry-run-resources-by-job-id",
+                "summary": "Get template version by organization, template, and name",
+                "operationId": "get-template-version-by-organization-template-and-name",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "format": "uuid",
-                        "description": "Job ID",
-                        "name": "jobID",
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
                         "in": "path",
                         "required": true
                     }
@@ -2734,16 +2859,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceResource"
-                            }
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/gitauth": {
+        "/organizations/{organization}/templates/{templatename}/versions/{templateversionname}/previous": {
             "get": {
                 "security": [
                     {
@@ -2756,14 +2878,28 @@ const docTemplate = `{
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get git auth by template version",
-                "operationId": "get-git-auth-by-template-version",
+                "summary": "Get previous template version by organization, template, and name",
+                "operationId": "get-previous-template-version-by-organization-template-and-name",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Organization ID",
+                        "name": "organization",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template name",
+                        "name": "templatename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
                         "in": "path",
                         "required": true
                     }
@@ -2772,101 +2908,93 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersionGitAuth"
-                            }
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/logs": {
-            "get": {
+        "/organizations/{organization}/templateversions": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Templates"
                 ],
-                "summary": "Get logs by template version",
-                "operationId": "get-logs-by-template-version",
+                "summary": "Create template version by organization",
+                "operationId": "create-template-version-by-organization",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Organization ID",
+                        "name": "organization",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Before log id",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After log id",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
+                        "description": "Create template version request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTemplateVersionRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
-                            }
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/parameters": {
+        "/platform-events": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "text/event-stream"
+                ],
                 "tags": [
-                    "Templates"
+                    "General"
                 ],
-                "summary": "Removed: Get parameters by template version",
-                "operationId": "removed-get-parameters-by-template-version",
+                "summary": "Watch platform events",
+                "operationId": "watch-platform-events",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Only stream events after this cursor. Omit to replay every retained event.",
+                        "name": "after",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PlatformEvent"
+                        }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/resources": {
+        "/regions": {
             "get": {
                 "security": [
                     {
@@ -2877,102 +3005,89 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
-                ],
-                "summary": "Get resources by template version",
-                "operationId": "get-resources-by-template-version",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    }
+                    "WorkspaceProxies"
                 ],
+                "summary": "Get site-wide regions for workspace connections",
+                "operationId": "get-site-wide-regions-for-workspace-connections",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceResource"
-                            }
+                            "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_Region"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/rich-parameters": {
-            "get": {
+        "/regions/latency": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "WorkspaceProxies"
                 ],
-                "summary": "Get rich parameters by template version",
-                "operationId": "get-rich-parameters-by-template-version",
+                "summary": "Rank regions by client latency",
+                "operationId": "rank-regions-by-client-latency",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
+                        "description": "Client latencies",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.RankRegionsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersionParameter"
-                            }
+                            "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_RankedRegion"
                         }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/schema": {
+        "/replicas": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "tags": [
-                    "Templates"
+                "produces": [
+                    "application/json"
                 ],
-                "summary": "Removed: Get schema by template version",
-                "operationId": "removed-get-schema-by-template-version",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
-                        "in": "path",
-                        "required": true
-                    }
+                "tags": [
+                    "Enterprise"
                 ],
+                "summary": "Get active replicas",
+                "operationId": "get-active-replicas",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Replica"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/templateversions/{templateversion}/variables": {
-            "get": {
+        "/replicas/{replica}/drain": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -2982,16 +3097,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Templates"
+                    "Enterprise"
                 ],
-                "summary": "Get template variables by template version",
-                "operationId": "get-template-variables-by-template-version",
+                "summary": "Drain replica",
+                "operationId": "drain-replica",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Template version ID",
-                        "name": "templateversion",
+                        "description": "Replica ID",
+                        "name": "replica",
                         "in": "path",
                         "required": true
                     }
@@ -3000,36 +3115,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.TemplateVersionVariable"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/updatecheck": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "General"
-                ],
-                "summary": "Update check",
-                "operationId": "update-check",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateCheckResponse"
+                            "$ref": "#/definitions/codersdk.Replica"
                         }
                     }
                 }
             }
         },
-        "/users": {
+        "/scim/v2/Groups": {
             "get": {
                 "security": [
                     {
@@ -3040,34 +3132,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
                 ],
-                "summary": "Get users",
-                "operationId": "get-users",
+                "summary": "SCIM 2.0: Get groups",
+                "operationId": "scim-get-groups",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query",
-                        "name": "q",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "After ID",
-                        "name": "after_id",
+                        "description": "Filter, only 'displayName eq' is supported",
+                        "name": "filter",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
+                        "description": "1-based index of the first result",
+                        "name": "startIndex",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
+                        "description": "Maximum number of results",
+                        "name": "count",
                         "in": "query"
                     }
                 ],
@@ -3075,7 +3160,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GetUsersResponse"
+                            "$ref": "#/definitions/coderd.SCIMGroupList"
                         }
                     }
                 }
@@ -3093,18 +3178,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
                 ],
-                "summary": "Create new user",
-                "operationId": "create-new-user",
+                "summary": "SCIM 2.0: Create new group",
+                "operationId": "scim-create-new-group",
                 "parameters": [
                     {
-                        "description": "Create user request",
+                        "description": "New group",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateUserRequest"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 ],
@@ -3112,13 +3197,13 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
             }
         },
-        "/users/authmethods": {
+        "/scim/v2/Groups/{id}": {
             "get": {
                 "security": [
                     {
@@ -3129,45 +3214,57 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Get group by ID",
+                "operationId": "scim-get-group-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get authentication methods",
-                "operationId": "get-authentication-methods",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.AuthMethods"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
-            }
-        },
-        "/users/first": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Delete group",
+                "operationId": "scim-delete-group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Check initial user created",
-                "operationId": "check-initial-user-created",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3180,66 +3277,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
                 ],
-                "summary": "Create initial user",
-                "operationId": "create-initial-user",
+                "summary": "SCIM 2.0: Update group",
+                "operationId": "scim-update-group",
                 "parameters": [
                     {
-                        "description": "First user request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Patch group request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateFirstUserRequest"
+                            "$ref": "#/definitions/coderd.SCIMPatchRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateFirstUserResponse"
+                            "$ref": "#/definitions/coderd.SCIMGroup"
                         }
                     }
                 }
             }
         },
-        "/users/login": {
-            "post": {
-                "consumes": [
-                    "application/json"
+        "/scim/v2/Users": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Authorization"
-                ],
-                "summary": "Log in user",
-                "operationId": "log-in-user",
-                "parameters": [
-                    {
-                        "description": "Login request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.LoginWithPasswordRequest"
-                        }
-                    }
+                    "Enterprise"
                 ],
+                "summary": "SCIM 2.0: Get users",
+                "operationId": "scim-get-users",
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
-                        }
+                    "200": {
+                        "description": "OK"
                     }
                 }
-            }
-        },
-        "/users/logout": {
+            },
             "post": {
                 "security": [
                     {
@@ -3250,140 +3341,93 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
+                ],
+                "summary": "SCIM 2.0: Create new user",
+                "operationId": "scim-create-new-user",
+                "parameters": [
+                    {
+                        "description": "New user",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMUser"
+                        }
+                    }
                 ],
-                "summary": "Log out user",
-                "operationId": "log-out-user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/coderd.SCIMUser"
                         }
                     }
                 }
             }
         },
-        "/users/oauth2/github/callback": {
+        "/scim/v2/Users/{id}": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/scim+json"
+                ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
                 ],
-                "summary": "OAuth 2.0 GitHub Callback",
-                "operationId": "oauth-20-github-callback",
-                "responses": {
-                    "307": {
-                        "description": "Temporary Redirect"
-                    }
-                }
-            }
-        },
-        "/users/oidc/callback": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "OpenID Connect Callback",
-                "operationId": "openid-connect-callback",
-                "responses": {
-                    "307": {
-                        "description": "Temporary Redirect"
-                    }
-                }
-            }
-        },
-        "/users/roles": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Members"
-                ],
-                "summary": "Get site member roles",
-                "operationId": "get-site-member-roles",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.AssignableRoles"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "/users/{user}": {
-            "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "Get user by name",
-                "operationId": "get-user-by-name",
+                "summary": "SCIM 2.0: Get user by ID",
+                "operationId": "scim-get-user-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.User"
-                        }
+                    "404": {
+                        "description": "Not Found"
                     }
                 }
             },
-            "delete": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "application/scim+json"
                 ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
                 ],
-                "summary": "Delete user",
-                "operationId": "delete-user",
+                "summary": "SCIM 2.0: Update user account",
+                "operationId": "scim-update-user-status",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/coderd.SCIMUser"
+                        }
                     }
                 ],
                 "responses": {
@@ -3396,13 +3440,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{user}/convert-login": {
+        "/templateaccessrequests/{templateaccessrequest}/decision": {
             "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Approves or denies a pending access request. Approving grants\nthe requester the \"use\" role on the template.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3410,39 +3455,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authorization"
+                    "Enterprise"
                 ],
-                "summary": "Convert user from password to oauth authentication",
-                "operationId": "convert-user-from-password-to-oauth-authentication",
+                "summary": "Decide template access request",
+                "operationId": "decide-template-access-request",
                 "parameters": [
                     {
-                        "description": "Convert request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template access request ID",
+                        "name": "templateaccessrequest",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decide template access request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.ConvertLoginRequest"
+                            "$ref": "#/definitions/codersdk.DecideTemplateAccessRequest"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.OAuthConversionResponse"
+                            "$ref": "#/definitions/codersdk.TemplateAccessRequest"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/gitsshkey": {
+        "/templates/{template}": {
             "get": {
                 "security": [
                     {
@@ -3453,15 +3499,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get user Git SSH key",
-                "operationId": "get-user-git-ssh-key",
+                "summary": "Get template metadata by ID",
+                "operationId": "get-template-metadata-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -3470,12 +3517,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3485,15 +3532,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Regenerate user SSH key",
-                "operationId": "regenerate-user-ssh-key",
+                "summary": "Delete template by ID",
+                "operationId": "delete-template-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -3502,14 +3550,12 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
-            }
-        },
-        "/users/{user}/keys": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -3519,51 +3565,65 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Create new session key",
-                "operationId": "create-new-session-key",
+                "summary": "Update template metadata by ID",
+                "operationId": "update-template-metadata-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                            "$ref": "#/definitions/codersdk.Template"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/tokens": {
+        "/templates/{template}/access-requests": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Returns the access requests of the template, newest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
                 ],
-                "summary": "Get user tokens",
-                "operationId": "get-user-tokens",
+                "summary": "Get template access requests",
+                "operationId": "get-template-access-requests",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "denied"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -3572,7 +3632,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.APIKey"
+                                "$ref": "#/definitions/codersdk.TemplateAccessRequest"
                             }
                         }
                     }
@@ -3584,6 +3644,7 @@ const docTemplate = `{
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Asks the template admins for permission to use a template of\none of your organizations.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3591,25 +3652,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
                 ],
-                "summary": "Create token API key",
-                "operationId": "create-token-api-key",
+                "summary": "Request template access",
+                "operationId": "request-template-access",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Create token request",
+                        "description": "Create template access request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateTokenRequest"
+                            "$ref": "#/definitions/codersdk.CreateTemplateAccessRequest"
                         }
                     }
                 ],
@@ -3617,13 +3679,13 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                            "$ref": "#/definitions/codersdk.TemplateAccessRequest"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/tokens/tokenconfig": {
+        "/templates/{template}/acl": {
             "get": {
                 "security": [
                     {
@@ -3634,15 +3696,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "General"
+                    "Enterprise"
                 ],
-                "summary": "Get token config",
-                "operationId": "get-token-config",
+                "summary": "Get template ACLs",
+                "operationId": "get-template-acls",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -3651,55 +3714,61 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.TokenConfig"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateUser"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/users/{user}/keys/tokens/{keyname}": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
                 ],
-                "summary": "Get API key by token name",
-                "operationId": "get-api-key-by-token-name",
+                "summary": "Update template ACL",
+                "operationId": "update-template-acl",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "format": "string",
-                        "description": "Key Name",
-                        "name": "keyname",
-                        "in": "path",
-                        "required": true
+                        "description": "Update template request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateTemplateACL"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.APIKey"
+                            "$ref": "#/definitions/codersdk.UpdateTemplateACLResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/keys/{keyid}": {
+        "/templates/{template}/acl/available": {
             "get": {
                 "security": [
                     {
@@ -3710,23 +3779,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Enterprise"
                 ],
-                "summary": "Get API key by ID",
-                "operationId": "get-api-key-by-id",
+                "summary": "Get template available acl users/groups",
+                "operationId": "get-template-available-acl-usersgroups",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Key ID",
-                        "name": "keyid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -3735,47 +3797,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.APIKey"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ACLAvailable"
+                            }
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "Delete API key",
-                "operationId": "delete-api-key",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Key ID",
-                        "name": "keyid",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
             }
         },
-        "/users/{user}/login-type": {
+        "/templates/{template}/daus": {
             "get": {
                 "security": [
                     {
@@ -3786,15 +3817,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get user login type",
-                "operationId": "get-user-login-type",
+                "summary": "Get template DAUs by ID",
+                "operationId": "get-template-daus-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -3803,13 +3835,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UserLoginType"
+                            "$ref": "#/definitions/codersdk.DAUsResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/organizations": {
+        "/templates/{template}/environmentvariables": {
             "get": {
                 "security": [
                     {
@@ -3820,15 +3852,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get organizations by user",
-                "operationId": "get-organizations-by-user",
+                "summary": "Get template environment variables",
+                "operationId": "get-template-environment-variables",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     }
@@ -3839,141 +3872,131 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.Organization"
+                                "$ref": "#/definitions/codersdk.ManagedEnvironmentVariable"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/users/{user}/organizations/{organizationname}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Template environment variables are injected into the agents of\nevery workspace of the template, and override organization\nenvironment variables with the same name.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get organization by user and organization name",
-                "operationId": "get-organization-by-user-and-organization-name",
+                "summary": "Create template environment variable",
+                "operationId": "create-template-environment-variable",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Organization name",
-                        "name": "organizationname",
-                        "in": "path",
-                        "required": true
+                        "description": "Create environment variable request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateManagedEnvironmentVariableRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Organization"
+                            "$ref": "#/definitions/codersdk.ManagedEnvironmentVariable"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/password": {
-            "put": {
+        "/templates/{template}/insights/builds": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
+                "description": "Build durations, failures by provisioner error code and the\nchange to the previous version, for each version of the\ntemplate. The builds are rolled up periodically.",
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Insights"
                 ],
-                "summary": "Update user password",
-                "operationId": "update-user-password",
+                "summary": "Get template build insights",
+                "operationId": "get-template-build-insights",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update password request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserPasswordRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TemplateBuildInsightsResponse"
+                        }
                     }
                 }
             }
         },
-        "/users/{user}/profile": {
-            "put": {
+        "/templates/{template}/notify-outdated": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Update user profile",
-                "operationId": "update-user-profile",
+                "summary": "Notify owners of outdated workspaces by template ID",
+                "operationId": "notify-owners-of-outdated-workspaces-by-template-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated profile",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserProfileRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.NotifyOutdatedWorkspacesResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/quiet-hours": {
+        "/templates/{template}/versions": {
             "get": {
                 "security": [
                     {
@@ -3984,18 +4007,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "Get user quiet hours schedule",
-                "operationId": "get-user-quiet-hours-schedule",
+                "summary": "List template versions by template ID",
+                "operationId": "list-template-versions-by-template-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "User ID",
-                        "name": "user",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "After ID",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -4004,13 +4046,13 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
+                                "$ref": "#/definitions/codersdk.TemplateVersion"
                             }
                         }
                     }
                 }
             },
-            "put": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -4023,43 +4065,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "Update user quiet hours schedule",
-                "operationId": "update-user-quiet-hours-schedule",
+                "summary": "Update active template version by template ID",
+                "operationId": "update-active-template-version-by-template-id",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "User ID",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update schedule request",
+                        "description": "Modified template version",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateUserQuietHoursScheduleRequest"
+                            "$ref": "#/definitions/codersdk.UpdateActiveTemplateVersion"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
-                            }
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/roles": {
+        "/templates/{template}/versions/{templateversionname}": {
             "get": {
                 "security": [
                     {
@@ -4070,15 +4109,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Get user roles",
-                "operationId": "get-user-roles",
-                "parameters": [
+                "summary": "Get template version by template ID and name",
+                "operationId": "get-template-version-by-template-id-and-name",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Template version name",
+                        "name": "templateversionname",
                         "in": "path",
                         "required": true
                     }
@@ -4087,17 +4134,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersion"
+                            }
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/templates/{template}/versions/{templateversion}/promote": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Requests the template version to become the active version of\nthe template. The version becomes active once a template\napprover approves the promotion.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4105,154 +4158,184 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Assign role to user",
-                "operationId": "assign-role-to-user",
+                "summary": "Promote template version",
+                "operationId": "promote-template-version",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template ID",
+                        "name": "template",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Update roles request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create template version promotion request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateRoles"
+                            "$ref": "#/definitions/codersdk.CreateTemplateVersionPromotionRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.TemplateVersionPromotion"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/status/activate": {
-            "put": {
+        "/templateversionpromotions": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Returns the template version promotions visible to the user,\nnewest first. Pass status=pending to list the promotions\nawaiting approval.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Activate user account",
-                "operationId": "activate-user-account",
+                "summary": "Get template version promotions",
+                "operationId": "get-template-version-promotions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
+                        "format": "uuid",
+                        "description": "Filter by template ID",
+                        "name": "template_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "description": "Filter by status",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionPromotion"
+                            }
                         }
                     }
                 }
             }
         },
-        "/users/{user}/status/suspend": {
-            "put": {
+        "/templateversionpromotions/{templateversionpromotion}/decision": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Approves or rejects a pending promotion. Approving makes the\ntemplate version the active version of the template. Only\ntemplate approvers can decide promotions, and users can't\ndecide their own.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Templates"
                 ],
-                "summary": "Suspend user account",
-                "operationId": "suspend-user-account",
+                "summary": "Decide template version promotion",
+                "operationId": "decide-template-version-promotion",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version promotion ID",
+                        "name": "templateversionpromotion",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Decide template version promotion request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DecideTemplateVersionPromotionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.User"
+                            "$ref": "#/definitions/codersdk.TemplateVersionPromotion"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/workspace/{workspacename}": {
-            "get": {
+        "/templateversions/validate": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Workspaces"
+                    "Templates"
                 ],
-                "summary": "Get workspace metadata by user and workspace name",
-                "operationId": "get-workspace-metadata-by-user-and-workspace-name",
+                "summary": "Validate template version archive",
+                "operationId": "validate-template-version-archive",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workspace name",
-                        "name": "workspacename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
-                        "name": "include_deleted",
-                        "in": "query"
+                        "description": "Validate template version request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ValidateTemplateVersionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "$ref": "#/definitions/codersdk.ValidateTemplateVersionResponse"
                         }
                     }
                 }
             }
         },
-        "/users/{user}/workspace/{workspacename}/builds/{buildnumber}": {
+        "/templateversions/{templateversion}": {
             "get": {
                 "security": [
                     {
@@ -4263,30 +4346,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Templates"
                 ],
-                "summary": "Get workspace build by user, workspace name, and build number",
-                "operationId": "get-workspace-build-by-user-workspace-name-and-build-number",
+                "summary": "Get template version by ID",
+                "operationId": "get-template-version-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Workspace name",
-                        "name": "workspacename",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "format": "number",
-                        "description": "Build number",
-                        "name": "buildnumber",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
@@ -4295,86 +4364,93 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
-            }
-        },
-        "/workspace-quota/{user}": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Templates"
                 ],
-                "summary": "Get workspace quota by user",
-                "operationId": "get-workspace-quota-by-user",
+                "summary": "Patch template version by ID",
+                "operationId": "patch-template-version-by-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID, name, or me",
-                        "name": "user",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Patch template version request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PatchTemplateVersionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceQuota"
+                            "$ref": "#/definitions/codersdk.TemplateVersion"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/aws-instance-identity": {
-            "post": {
+        "/templateversions/{templateversion}/cancel": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Templates"
                 ],
-                "summary": "Authenticate agent on AWS instance",
-                "operationId": "authenticate-agent-on-aws-instance",
+                "summary": "Cancel template version by ID",
+                "operationId": "cancel-template-version-by-id",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.AWSInstanceIdentityToken"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/azure-instance-identity": {
+        "/templateversions/{templateversion}/dry-run": {
             "post": {
                 "security": [
                     {
@@ -4388,32 +4464,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Templates"
                 ],
-                "summary": "Authenticate agent on Azure instance",
-                "operationId": "authenticate-agent-on-azure-instance",
+                "summary": "Create template version dry-run",
+                "operationId": "create-template-version-dry-run",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dry-run request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AzureInstanceIdentityToken"
+                            "$ref": "#/definitions/codersdk.CreateTemplateVersionDryRunRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.ProvisionerJob"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/connection": {
+        "/templateversions/{templateversion}/dry-run/{jobID}": {
             "get": {
                 "security": [
                     {
@@ -4424,119 +4508,82 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Templates"
                 ],
-                "summary": "Get connection info for workspace agent generic",
-                "operationId": "get-connection-info-for-workspace-agent-generic",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
-                        }
-                    }
-                },
-                "x-apidocgen": {
-                    "skip": true
-                }
-            }
-        },
-        "/workspaceagents/google-instance-identity": {
-            "post": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Agents"
-                ],
-                "summary": "Authenticate agent on Google Cloud instance",
-                "operationId": "authenticate-agent-on-google-cloud-instance",
+                "summary": "Get template version dry-run by job ID",
+                "operationId": "get-template-version-dry-run-by-job-id",
                 "parameters": [
                     {
-                        "description": "Instance identity token",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.GoogleInstanceIdentityToken"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                            "$ref": "#/definitions/codersdk.ProvisionerJob"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/app-health": {
-            "post": {
+        "/templateversions/{templateversion}/dry-run/{jobID}/cancel": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Templates"
                 ],
-                "summary": "Submit workspace agent application health",
-                "operationId": "submit-workspace-agent-application-health",
+                "summary": "Cancel template version dry-run by job ID",
+                "operationId": "cancel-template-version-dry-run-by-job-id",
                 "parameters": [
                     {
-                        "description": "Application health request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostAppHealthsRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK"
-                    }
-                }
-            }
-        },
-        "/workspaceagents/me/coordinate": {
-            "get": {
-                "security": [
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    },
                     {
-                        "CoderSessionToken": []
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
                     }
                 ],
-                "description": "It accepts a WebSocket connection to an agent that listens to\nincoming connections and publishes node updates.",
-                "tags": [
-                    "Agents"
-                ],
-                "summary": "Coordinate workspace agent via Tailnet",
-                "operationId": "coordinate-workspace-agent-via-tailnet",
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
                     }
                 }
             }
         },
-        "/workspaceagents/me/gitauth": {
+        "/templateversions/{templateversion}/dry-run/{jobID}/logs": {
             "get": {
                 "security": [
                     {
@@ -4547,23 +4594,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Templates"
                 ],
-                "summary": "Get workspace agent Git auth",
-                "operationId": "get-workspace-agent-git-auth",
+                "summary": "Get template version dry-run logs by job ID",
+                "operationId": "get-template-version-dry-run-logs-by-job-id",
                 "parameters": [
                     {
                         "type": "string",
-                        "format": "uri",
-                        "description": "Git URL",
-                        "name": "url",
-                        "in": "query",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "Before Unix timestamp",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After Unix timestamp",
+                        "name": "after",
+                        "in": "query"
+                    },
                     {
                         "type": "boolean",
-                        "description": "Wait for a new token to be issued",
-                        "name": "listen",
+                        "description": "Follow log stream",
+                        "name": "follow",
                         "in": "query"
                     }
                 ],
@@ -4571,13 +4638,16 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.GitAuthResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/gitsshkey": {
+        "/templateversions/{templateversion}/dry-run/{jobID}/resources": {
             "get": {
                 "security": [
                     {
@@ -4588,60 +4658,80 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Templates"
+                ],
+                "summary": "Get template version dry-run resources by job ID",
+                "operationId": "get-template-version-dry-run-resources-by-job-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Job ID",
+                        "name": "jobID",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get workspace agent Git SSH key",
-                "operationId": "get-workspace-agent-git-ssh-key",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.GitSSHKey"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceResource"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/logs": {
-            "patch": {
+        "/templateversions/{templateversion}/gitauth": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Templates"
                 ],
-                "summary": "Patch workspace agent logs",
-                "operationId": "patch-workspace-agent-logs",
+                "summary": "Get git auth by template version",
+                "operationId": "get-git-auth-by-template-version",
                 "parameters": [
                     {
-                        "description": "logs",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PatchLogs"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionGitAuth"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/manifest": {
+        "/templateversions/{templateversion}/logs": {
             "get": {
                 "security": [
                     {
@@ -4652,185 +4742,193 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Templates"
+                ],
+                "summary": "Get logs by template version",
+                "operationId": "get-logs-by-template-version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before log id",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After log id",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get authorized workspace agent manifest",
-                "operationId": "get-authorized-workspace-agent-manifest",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.Manifest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/metadata/{key}": {
-            "post": {
+        "/templateversions/{templateversion}/parameters": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Agents"
+                    "Templates"
                 ],
-                "summary": "Submit workspace agent metadata",
-                "operationId": "submit-workspace-agent-metadata",
+                "summary": "Removed: Get parameters by template version",
+                "operationId": "removed-get-parameters-by-template-version",
                 "parameters": [
-                    {
-                        "description": "Workspace agent metadata request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostMetadataRequest"
-                        }
-                    },
                     {
                         "type": "string",
-                        "format": "string",
-                        "description": "metadata key",
-                        "name": "key",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Success"
+                    "200": {
+                        "description": "OK"
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/me/report-lifecycle": {
-            "post": {
+        "/templateversions/{templateversion}/resources": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Templates"
                 ],
-                "summary": "Submit workspace agent lifecycle state",
-                "operationId": "submit-workspace-agent-lifecycle-state",
+                "summary": "Get resources by template version",
+                "operationId": "get-resources-by-template-version",
                 "parameters": [
                     {
-                        "description": "Workspace agent lifecycle request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostLifecycleRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Success"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceResource"
+                            }
+                        }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/me/report-stats": {
-            "post": {
+        "/templateversions/{templateversion}/rich-parameters": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Templates"
                 ],
-                "summary": "Submit workspace agent stats",
-                "operationId": "submit-workspace-agent-stats",
+                "summary": "Get rich parameters by template version",
+                "operationId": "get-rich-parameters-by-template-version",
                 "parameters": [
                     {
-                        "description": "Stats request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.Stats"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.StatsResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionParameter"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/me/startup": {
-            "post": {
+        "/templateversions/{templateversion}/schema": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Agents"
+                    "Templates"
                 ],
-                "summary": "Submit workspace agent startup",
-                "operationId": "submit-workspace-agent-startup",
+                "summary": "Removed: Get schema by template version",
+                "operationId": "removed-get-schema-by-template-version",
                 "parameters": [
                     {
-                        "description": "Startup request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/agentsdk.PostStartupRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK"
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceagents/me/startup-logs": {
-            "patch": {
+        "/templateversions/{templateversion}/state-analysis": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Maps the resources of an existing Terraform state to the\nresources of the template version, to check which resources\na workspace created with the state adopts.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4838,18 +4936,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Templates"
                 ],
-                "summary": "Removed: Patch workspace agent logs",
-                "operationId": "removed-patch-workspace-agent-logs",
+                "summary": "Analyze Terraform state for template version",
+                "operationId": "analyze-terraform-state-for-template-version",
                 "parameters": [
                     {
-                        "description": "logs",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Template version ID",
+                        "name": "templateversion",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Terraform state",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/agentsdk.PatchLogs"
+                            "$ref": "#/definitions/codersdk.TemplateVersionStateAnalysisRequest"
                         }
                     }
                 ],
@@ -4857,13 +4963,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.TemplateVersionStateAnalysis"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}": {
+        "/templateversions/{templateversion}/variables": {
             "get": {
                 "security": [
                     {
@@ -4874,16 +4980,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Templates"
                 ],
-                "summary": "Get workspace agent by ID",
-                "operationId": "get-workspace-agent-by-id",
+                "summary": "Get template variables by template version",
+                "operationId": "get-template-variables-by-template-version",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
+                        "description": "Template version ID",
+                        "name": "templateversion",
                         "in": "path",
                         "required": true
                     }
@@ -4892,150 +4998,3220 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgent"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.TemplateVersionVariable"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/connection": {
+        "/updatecheck": {
             "get": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
-                ],
-                "summary": "Get connection info for workspace agent",
-                "operationId": "get-connection-info-for-workspace-agent",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
-                        "required": true
-                    }
+                    "General"
                 ],
+                "summary": "Update check",
+                "operationId": "update-check",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                            "$ref": "#/definitions/codersdk.UpdateCheckResponse"
                         }
                     }
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/coordinate": {
+        "/users": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "Users"
                 ],
-                "summary": "Coordinate workspace agent",
-                "operationId": "coordinate-workspace-agent",
+                "summary": "Get users",
+                "operationId": "get-users",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query",
+                        "name": "q",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
-                        "required": true
+                        "description": "After ID",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GetUsersResponse"
+                        }
                     }
                 }
-            }
-        },
-        "/workspaceagents/{workspaceagent}/legacy": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Users"
                 ],
-                "summary": "Agent is legacy",
-                "operationId": "agent-is-legacy",
+                "summary": "Create new user",
+                "operationId": "create-new-user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace Agent ID",
+                        "description": "Create user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/authmethods": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get authentication methods",
+                "operationId": "get-authentication-methods",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.AuthMethods"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/first": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Check initial user created",
+                "operationId": "check-initial-user-created",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create initial user",
+                "operationId": "create-initial-user",
+                "parameters": [
+                    {
+                        "description": "First user request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateFirstUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateFirstUserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorization"
+                ],
+                "summary": "Log in user",
+                "operationId": "log-in-user",
+                "parameters": [
+                    {
+                        "description": "Login request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.LoginWithPasswordResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Log out user",
+                "operationId": "log-out-user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/oauth2/github/callback": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "OAuth 2.0 GitHub Callback",
+                "operationId": "oauth-20-github-callback",
+                "responses": {
+                    "307": {
+                        "description": "Temporary Redirect"
+                    }
+                }
+            }
+        },
+        "/users/oidc/callback": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "OpenID Connect Callback",
+                "operationId": "openid-connect-callback",
+                "responses": {
+                    "307": {
+                        "description": "Temporary Redirect"
+                    }
+                }
+            }
+        },
+        "/users/roles": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Members"
+                ],
+                "summary": "Get site member roles",
+                "operationId": "get-site-member-roles",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.AssignableRoles"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by name",
+                "operationId": "get-user-by-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user",
+                "operationId": "delete-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/connection-log": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user connection log",
+                "operationId": "get-user-connection-log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only return connections established after this time",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of connections to return",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.UserConnectionLogEntry"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/convert-login": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authorization"
+                ],
+                "summary": "Convert user from password to oauth authentication",
+                "operationId": "convert-user-from-password-to-oauth-authentication",
+                "parameters": [
+                    {
+                        "description": "Convert request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.ConvertLoginRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.OAuthConversionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/gitsshkey": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user Git SSH key",
+                "operationId": "get-user-git-ssh-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Regenerate user SSH key",
+                "operationId": "regenerate-user-ssh-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GitSSHKey"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/keys": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create new session key",
+                "operationId": "create-new-session-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/keys/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user tokens",
+                "operationId": "get-user-tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.APIKey"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create token API key",
+                "operationId": "create-token-api-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create token request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.GenerateAPIKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/keys/tokens/tokenconfig": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "General"
+                ],
+                "summary": "Get token config",
+                "operationId": "get-token-config",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.TokenConfig"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/keys/tokens/{keyname}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get API key by token name",
+                "operationId": "get-api-key-by-token-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "string",
+                        "description": "Key Name",
+                        "name": "keyname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.APIKey"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/keys/{keyid}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get API key by ID",
+                "operationId": "get-api-key-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Key ID",
+                        "name": "keyid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.APIKey"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete API key",
+                "operationId": "delete-api-key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Key ID",
+                        "name": "keyid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users/{user}/login-type": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user login type",
+                "operationId": "get-user-login-type",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UserLoginType"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/organizations": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get organizations by user",
+                "operationId": "get-organizations-by-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.Organization"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/organizations/{organizationname}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get organization by user and organization name",
+                "operationId": "get-organization-by-user-and-organization-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Organization name",
+                        "name": "organizationname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Organization"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/password": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user password",
+                "operationId": "update-user-password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update password request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateUserPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users/{user}/profile": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user profile",
+                "operationId": "update-user-profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated profile",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateUserProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/pty-session-override": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update web terminal session limit override by user",
+                "operationId": "update-web-terminal-session-limit-override-by-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update web terminal session limit override request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateUserPTYSessionOverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete web terminal session limit override by user",
+                "operationId": "delete-web-terminal-session-limit-override-by-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users/{user}/quiet-hours": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get user quiet hours schedule",
+                "operationId": "get-user-quiet-hours-schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Update user quiet hours schedule",
+                "operationId": "update-user-quiet-hours-schedule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "User ID",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update schedule request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateUserQuietHoursScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.UserQuietHoursScheduleResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/roles": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user roles",
+                "operationId": "get-user-roles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Assign role to user",
+                "operationId": "assign-role-to-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update roles request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateRoles"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/ssh/certificate": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create SSH certificate for user",
+                "operationId": "create-ssh-certificate-for-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create SSH certificate request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateSSHCertificateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.SSHCertificate"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/status/activate": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Activate user account",
+                "operationId": "activate-user-account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/status/suspend": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Suspend user account",
+                "operationId": "suspend-user-account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/workspace/{workspacename}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace metadata by user and workspace name",
+                "operationId": "get-workspace-metadata-by-user-and-workspace-name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace name",
+                        "name": "workspacename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Workspace"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user}/workspace/{workspacename}/builds/{buildnumber}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace build by user, workspace name, and build number",
+                "operationId": "get-workspace-build-by-user-workspace-name-and-build-number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace name",
+                        "name": "workspacename",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "number",
+                        "description": "Build number",
+                        "name": "buildnumber",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspace-quota/{user}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get workspace quota by user",
+                "operationId": "get-workspace-quota-by-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceQuota"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspace-quota/{user}/breakdown": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get workspace quota breakdown by user",
+                "operationId": "get-workspace-quota-breakdown-by-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceQuotaBreakdown"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspace-quota/{user}/override": {
+            "put": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Update workspace quota override by user",
+                "operationId": "update-workspace-quota-override-by-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update workspace quota override request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceQuotaOverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Delete workspace quota override by user",
+                "operationId": "delete-workspace-quota-override-by-user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, name, or me",
+                        "name": "user",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/aws-instance-identity": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Authenticate agent on AWS instance",
+                "operationId": "authenticate-agent-on-aws-instance",
+                "parameters": [
+                    {
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AWSInstanceIdentityToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/azure-instance-identity": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Authenticate agent on Azure instance",
+                "operationId": "authenticate-agent-on-azure-instance",
+                "parameters": [
+                    {
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AzureInstanceIdentityToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/connection": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get connection info for workspace agent generic",
+                "operationId": "get-connection-info-for-workspace-agent-generic",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                        }
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/google-instance-identity": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Authenticate agent on Google Cloud instance",
+                "operationId": "authenticate-agent-on-google-cloud-instance",
+                "parameters": [
+                    {
+                        "description": "Instance identity token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.GoogleInstanceIdentityToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.AuthenticateResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/app-health": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent application health",
+                "operationId": "submit-workspace-agent-application-health",
+                "parameters": [
+                    {
+                        "description": "Application health request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostAppHealthsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/coordinate": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "It accepts a WebSocket connection to an agent that listens to\nincoming connections and publishes node updates.",
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Coordinate workspace agent via Tailnet",
+                "operationId": "coordinate-workspace-agent-via-tailnet",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/feature-flags": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent feature flags",
+                "operationId": "get-workspace-agent-feature-flags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.FeatureFlags"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/gitauth": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent Git auth",
+                "operationId": "get-workspace-agent-git-auth",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uri",
+                        "description": "Git URL",
+                        "name": "url",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Wait for a new token to be issued",
+                        "name": "listen",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.GitAuthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/gitsshkey": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent Git SSH key",
+                "operationId": "get-workspace-agent-git-ssh-key",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.GitSSHKey"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/logs": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Patch workspace agent logs",
+                "operationId": "patch-workspace-agent-logs",
+                "parameters": [
+                    {
+                        "description": "logs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PatchLogs"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/manifest": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get authorized workspace agent manifest",
+                "operationId": "get-authorized-workspace-agent-manifest",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.Manifest"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/metadata/{key}": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent metadata",
+                "operationId": "submit-workspace-agent-metadata",
+                "parameters": [
+                    {
+                        "description": "Workspace agent metadata request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostMetadataRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "format": "string",
+                        "description": "metadata key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/report-lifecycle": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent lifecycle state",
+                "operationId": "submit-workspace-agent-lifecycle-state",
+                "parameters": [
+                    {
+                        "description": "Workspace agent lifecycle request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostLifecycleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/report-stats": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent stats",
+                "operationId": "submit-workspace-agent-stats",
+                "parameters": [
+                    {
+                        "description": "Stats request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.Stats"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.StatsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/me/rotate-token": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Rotate workspace agent auth token",
+                "operationId": "rotate-workspace-agent-auth-token",
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.RotateAuthTokenResponse"
+                        }
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/startup": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Submit workspace agent startup",
+                "operationId": "submit-workspace-agent-startup",
+                "parameters": [
+                    {
+                        "description": "Startup request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PostStartupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/me/startup-logs": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Removed: Patch workspace agent logs",
+                "operationId": "removed-patch-workspace-agent-logs",
+                "parameters": [
+                    {
+                        "description": "logs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/agentsdk.PatchLogs"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent by ID",
+                "operationId": "get-workspace-agent-by-id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgent"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/connection": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get connection info for workspace agent",
+                "operationId": "get-connection-info-for-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentConnectionInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/connections": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent client connections",
+                "operationId": "get-workspace-agent-client-connections",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only return connections established after this time",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of connections to return",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentClientConnection"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/coordinate": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Coordinate workspace agent",
+                "operationId": "coordinate-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of the connecting client",
+                        "name": "client_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Version of the connecting client",
+                        "name": "client_version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/legacy": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Agent is legacy",
+                "operationId": "agent-is-legacy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace Agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.AgentIsLegacyResponse"
+                        }
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/listening-ports": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get listening ports for workspace agent",
+                "operationId": "get-listening-ports-for-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentListeningPortsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get logs by workspace agent",
+                "operationId": "get-logs-by-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before log id",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After log id",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Disable compression for WebSocket connection",
+                        "name": "no_compression",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentLog"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/metadata/history": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get workspace agent metadata history",
+                "operationId": "get-workspace-agent-metadata-history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Metadata key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Only return values collected after this time",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of values to return",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentMetadataResult"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/pty": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Open PTY to workspace agent",
+                "operationId": "open-pty-to-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/revoke-token": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Revoke workspace agent auth token",
+                "operationId": "revoke-workspace-agent-auth-token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/startup-logs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Removed: Get logs by workspace agent",
+                "operationId": "removed-get-logs-by-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before log id",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After log id",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Disable compression for WebSocket connection",
+                        "name": "no_compression",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceAgentLog"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/support-bundle": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Get support bundle for workspace agent",
+                "operationId": "get-support-bundle-for-workspace-agent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
+                        "name": "workspaceagent",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceagents/{workspaceagent}/watch-metadata": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "tags": [
+                    "Agents"
+                ],
+                "summary": "Watch for workspace agent metadata updates",
+                "operationId": "watch-for-workspace-agent-metadata-updates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace agent ID",
                         "name": "workspaceagent",
                         "in": "path",
                         "required": true
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "Success"
+                    }
+                },
+                "x-apidocgen": {
+                    "skip": true
+                }
+            }
+        },
+        "/workspaceapprovals/{workspaceapproval}/callback": {
+            "post": {
+                "description": "Records the decision of the system behind the workspace approval webhook\nof the deployment. Requests aren't authenticated with a session token, but\nmust be signed with the webhook secret using the Coder-Webhook-Timestamp\nand Coder-Webhook-Signature headers. Requests can only be decided once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Decide workspace approval from the approval webhook",
+                "operationId": "decide-workspace-approval-from-the-approval-webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace approval ID",
+                        "name": "workspaceapproval",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decide workspace approval request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DecideWorkspaceApprovalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceApproval"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceapprovals/{workspaceapproval}/decision": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "description": "Approves or rejects a pending approval request. Approved builds\nare queued for provisioners, rejected builds fail.\nUsers can't decide their own requests.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Decide workspace approval",
+                "operationId": "decide-workspace-approval",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace approval ID",
+                        "name": "workspaceapproval",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Decide workspace approval request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.DecideWorkspaceApprovalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceApproval"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace build",
+                "operationId": "get-workspace-build",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}/cancel": {
+            "patch": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Cancel workspace build",
+                "operationId": "cancel-workspace-build",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace build logs",
+                "operationId": "get-workspace-build-logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Before Unix timestamp",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "After Unix timestamp",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Follow log stream",
+                        "name": "follow",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/wsproxysdk.AgentIsLegacyResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}/parameters": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get build parameters for workspace build",
+                "operationId": "get-build-parameters-for-workspace-build",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}/resources": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace resources for workspace build",
+                "operationId": "get-workspace-resources-for-workspace-build",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceResource"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}/state": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get provisioner state for workspace build",
+                "operationId": "get-provisioner-state-for-workspace-build",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspacebuilds/{workspacebuild}/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Builds"
+                ],
+                "summary": "Get workspace build timeline",
+                "operationId": "get-workspace-build-timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Workspace build ID",
+                        "name": "workspacebuild",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuildTimeline"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceproxies": {
+            "get": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Get workspace proxies",
+                "operationId": "get-workspace-proxies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_WorkspaceProxy"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Create workspace proxy",
+                "operationId": "create-workspace-proxy",
+                "parameters": [
+                    {
+                        "description": "Create workspace proxy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceProxyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                        }
+                    }
+                }
+            }
+        },
+        "/workspaceproxies/me/access-events": {
+            "post": {
+                "security": [
+                    {
+                        "CoderSessionToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enterprise"
+                ],
+                "summary": "Report workspace access events",
+                "operationId": "report-workspace-access-events",
+                "parameters": [
+                    {
+                        "description": "Report access events request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.ReportAccessEventsRequest"
                         }
                     }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
                 },
                 "x-apidocgen": {
                     "skip": true
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/listening-ports": {
-            "get": {
+        "/workspaceproxies/me/app-stats": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Enterprise"
                 ],
-                "summary": "Get listening ports for workspace agent",
-                "operationId": "get-listening-ports-for-workspace-agent",
+                "summary": "Report workspace app stats",
+                "operationId": "report-workspace-app-stats",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
-                        "required": true
+                        "description": "Report app stats request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.ReportAppStatsRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceAgentListeningPortsResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/logs": {
+        "/workspaceproxies/me/config/watch": {
             "get": {
                 "security": [
                     {
@@ -5043,61 +8219,27 @@ const docTemplate = `{
                     }
                 ],
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "Agents"
-                ],
-                "summary": "Get logs by workspace agent",
-                "operationId": "get-logs-by-workspace-agent",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Before log id",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After log id",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Disable compression for WebSocket connection",
-                        "name": "no_compression",
-                        "in": "query"
-                    }
+                    "Enterprise"
                 ],
+                "summary": "Watch workspace proxy config",
+                "operationId": "watch-workspace-proxy-config",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceAgentLog"
-                            }
+                            "$ref": "#/definitions/wsproxysdk.ProxyConfig"
                         }
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/pty": {
+        "/workspaceproxies/me/coordinate": {
             "get": {
                 "security": [
                     {
@@ -5105,114 +8247,91 @@ const docTemplate = `{
                     }
                 ],
                 "tags": [
-                    "Agents"
-                ],
-                "summary": "Open PTY to workspace agent",
-                "operationId": "open-pty-to-workspace-agent",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Enterprise"
                 ],
+                "summary": "Workspace Proxy Coordinate",
+                "operationId": "workspace-proxy-coordinate",
                 "responses": {
                     "101": {
                         "description": "Switching Protocols"
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/startup-logs": {
-            "get": {
+        "/workspaceproxies/me/deregister": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "produces": [
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Agents"
+                    "Enterprise"
                 ],
-                "summary": "Removed: Get logs by workspace agent",
-                "operationId": "removed-get-logs-by-workspace-agent",
+                "summary": "Deregister workspace proxy",
+                "operationId": "deregister-workspace-proxy",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Before log id",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After log id",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Disable compression for WebSocket connection",
-                        "name": "no_compression",
-                        "in": "query"
+                        "description": "Deregister workspace proxy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.DeregisterWorkspaceProxyRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceAgentLog"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/workspaceagents/{workspaceagent}/watch-metadata": {
-            "get": {
+        "/workspaceproxies/me/issue-signed-app-token": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Agents"
+                    "Enterprise"
                 ],
-                "summary": "Watch for workspace agent metadata updates",
-                "operationId": "watch-for-workspace-agent-metadata-updates",
+                "summary": "Issue signed workspace app token",
+                "operationId": "issue-signed-workspace-app-token",
                 "parameters": [
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "Workspace agent ID",
-                        "name": "workspaceagent",
-                        "in": "path",
-                        "required": true
+                        "description": "Issue signed app token request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/workspaceapps.IssueTokenRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Success"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.IssueSignedAppTokenResponse"
+                        }
                     }
                 },
                 "x-apidocgen": {
@@ -5220,7 +8339,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspacebuilds/{workspacebuild}": {
+        "/workspaceproxies/me/jwks": {
             "get": {
                 "security": [
                     {
@@ -5231,64 +8350,63 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
-                ],
-                "summary": "Get workspace build",
-                "operationId": "get-workspace-build",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Enterprise"
                 ],
+                "summary": "Get workspace proxy app token keys",
+                "operationId": "get-workspace-proxy-app-token-keys",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
-                        }
+                        "description": "OK"
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/workspacebuilds/{workspacebuild}/cancel": {
-            "patch": {
+        "/workspaceproxies/me/register": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Enterprise"
                 ],
-                "summary": "Cancel workspace build",
-                "operationId": "cancel-workspace-build",
+                "summary": "Register workspace proxy",
+                "operationId": "register-workspace-proxy",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
-                        "in": "path",
-                        "required": true
+                        "description": "Register workspace proxy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/wsproxysdk.RegisterWorkspaceProxyRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/wsproxysdk.RegisterWorkspaceProxyResponse"
                         }
                     }
+                },
+                "x-apidocgen": {
+                    "skip": true
                 }
             }
         },
-        "/workspacebuilds/{workspacebuild}/logs": {
+        "/workspaceproxies/{workspaceproxy}": {
             "get": {
                 "security": [
                     {
@@ -5299,52 +8417,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Enterprise"
                 ],
-                "summary": "Get workspace build logs",
-                "operationId": "get-workspace-build-logs",
+                "summary": "Get workspace proxy",
+                "operationId": "get-workspace-proxy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "format": "uuid",
+                        "description": "Proxy ID or name",
+                        "name": "workspaceproxy",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Before Unix timestamp",
-                        "name": "before",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "After Unix timestamp",
-                        "name": "after",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Follow log stream",
-                        "name": "follow",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.ProvisionerJobLog"
-                            }
+                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
                         }
                     }
                 }
-            }
-        },
-        "/workspacebuilds/{workspacebuild}/parameters": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5354,15 +8450,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Enterprise"
                 ],
-                "summary": "Get build parameters for workspace build",
-                "operationId": "get-build-parameters-for-workspace-build",
+                "summary": "Delete workspace proxy",
+                "operationId": "delete-workspace-proxy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "format": "uuid",
+                        "description": "Proxy ID or name",
+                        "name": "workspaceproxy",
                         "in": "path",
                         "required": true
                     }
@@ -5371,87 +8468,106 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
-                            }
+                            "$ref": "#/definitions/codersdk.Response"
                         }
                     }
                 }
-            }
-        },
-        "/workspacebuilds/{workspacebuild}/resources": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Enterprise"
                 ],
-                "summary": "Get workspace resources for workspace build",
-                "operationId": "get-workspace-resources-for-workspace-build",
+                "summary": "Update workspace proxy",
+                "operationId": "update-workspace-proxy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "format": "uuid",
+                        "description": "Proxy ID or name",
+                        "name": "workspaceproxy",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update workspace proxy request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.PatchWorkspaceProxy"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceResource"
-                            }
+                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
                         }
                     }
                 }
             }
         },
-        "/workspacebuilds/{workspacebuild}/state": {
-            "get": {
+        "/workspaceproxies/{workspaceproxy}/acl": {
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "description": "Limits the workspace proxy to the members of the given\norganizations and groups. The proxy is only listed in their\nregions and its DERP region is only added to their DERP maps.\nLeave both lists empty to make the proxy available to everyone.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Enterprise"
                 ],
-                "summary": "Get provisioner state for workspace build",
-                "operationId": "get-provisioner-state-for-workspace-build",
+                "summary": "Update workspace proxy ACL",
+                "operationId": "update-workspace-proxy-acl",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Workspace build ID",
-                        "name": "workspacebuild",
+                        "format": "uuid",
+                        "description": "Proxy ID or name",
+                        "name": "workspaceproxy",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Update workspace proxy ACL request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceProxyACL"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
                         }
                     }
                 }
             }
         },
-        "/workspaceproxies": {
+        "/workspaces": {
             "get": {
                 "security": [
                     {
@@ -5462,62 +8578,81 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Workspaces"
+                ],
+                "summary": "List workspaces",
+                "operationId": "list-workspaces",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query in the format ` + "`" + `key:value` + "`" + `. Available keys are: owner, template, name, status, has-agent, deleting_by.",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get workspace proxies",
-                "operationId": "get-workspace-proxies",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.RegionsResponse-codersdk_WorkspaceProxy"
-                            }
+                            "$ref": "#/definitions/codersdk.WorkspacesResponse"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/workspaces/{workspace}": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Workspaces"
                 ],
-                "summary": "Create workspace proxy",
-                "operationId": "create-workspace-proxy",
+                "summary": "Get workspace metadata by ID",
+                "operationId": "get-workspace-metadata-by-id",
                 "parameters": [
                     {
-                        "description": "Create workspace proxy request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.CreateWorkspaceProxyRequest"
-                        }
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
+                        "name": "include_deleted",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                            "$ref": "#/definitions/codersdk.Workspace"
                         }
                     }
                 }
-            }
-        },
-        "/workspaceproxies/me/app-stats": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5527,18 +8662,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Workspaces"
                 ],
-                "summary": "Report workspace app stats",
-                "operationId": "report-workspace-app-stats",
+                "summary": "Update workspace metadata by ID",
+                "operationId": "update-workspace-metadata-by-id",
                 "parameters": [
                     {
-                        "description": "Report app stats request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Metadata update request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/wsproxysdk.ReportAppStatsRequest"
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceRequest"
                         }
                     }
                 ],
@@ -5546,72 +8689,47 @@ const docTemplate = `{
                     "204": {
                         "description": "No Content"
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceproxies/me/coordinate": {
+        "/workspaces/{workspace}/approval": {
             "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "tags": [
-                    "Enterprise"
-                ],
-                "summary": "Workspace Proxy Coordinate",
-                "operationId": "workspace-proxy-coordinate",
-                "responses": {
-                    "101": {
-                        "description": "Switching Protocols"
-                    }
-                },
-                "x-apidocgen": {
-                    "skip": true
-                }
-            }
-        },
-        "/workspaceproxies/me/deregister": {
-            "post": {
-                "security": [
-                    {
-                        "CoderSessionToken": []
-                    }
-                ],
-                "consumes": [
+                "description": "Returns the most recent approval request of the workspace.",
+                "produces": [
                     "application/json"
-                ],
-                "tags": [
-                    "Enterprise"
-                ],
-                "summary": "Deregister workspace proxy",
-                "operationId": "deregister-workspace-proxy",
-                "parameters": [
-                    {
-                        "description": "Deregister workspace proxy request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/wsproxysdk.DeregisterWorkspaceProxyRequest"
-                        }
+                ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Get workspace approval",
+                "operationId": "get-workspace-approval",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.WorkspaceApproval"
+                        }
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceproxies/me/issue-signed-app-token": {
-            "post": {
+        "/workspaces/{workspace}/autostart": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5620,40 +8738,39 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "Workspaces"
                 ],
-                "summary": "Issue signed workspace app token",
-                "operationId": "issue-signed-workspace-app-token",
+                "summary": "Update workspace autostart schedule by ID",
+                "operationId": "update-workspace-autostart-schedule-by-id",
                 "parameters": [
                     {
-                        "description": "Issue signed app token request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule update request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/workspaceapps.IssueTokenRequest"
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceAutostartRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/wsproxysdk.IssueSignedAppTokenResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceproxies/me/register": {
-            "post": {
+        "/workspaces/{workspace}/autoupdates": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5662,39 +8779,38 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Enterprise"
+                    "Workspaces"
                 ],
-                "summary": "Register workspace proxy",
-                "operationId": "register-workspace-proxy",
+                "summary": "Update workspace automatic updates by ID",
+                "operationId": "update-workspace-automatic-updates-by-id",
                 "parameters": [
                     {
-                        "description": "Register workspace proxy request",
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Automatic updates request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/wsproxysdk.RegisterWorkspaceProxyRequest"
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceAutomaticUpdatesRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/wsproxysdk.RegisterWorkspaceProxyResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
-                },
-                "x-apidocgen": {
-                    "skip": true
                 }
             }
         },
-        "/workspaceproxies/{workspaceproxy}": {
+        "/workspaces/{workspace}/builds": {
             "get": {
                 "security": [
                     {
@@ -5705,154 +8821,218 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Builds"
                 ],
-                "summary": "Get workspace proxy",
-                "operationId": "get-workspace-proxy",
+                "summary": "Get workspace builds by workspace ID",
+                "operationId": "get-workspace-builds-by-workspace-id",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Proxy ID or name",
-                        "name": "workspaceproxy",
+                        "description": "Workspace ID",
+                        "name": "workspace",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "After ID",
+                        "name": "after_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "date-time",
+                        "description": "Since timestamp",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "initiator",
+                            "autostart",
+                            "autostop",
+                            "autolock",
+                            "failedstop",
+                            "autodelete",
+                            "bulk"
+                        ],
+                        "type": "string",
+                        "description": "Build reason",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Initiator ID",
+                        "name": "initiator_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                            }
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Builds"
                 ],
-                "summary": "Delete workspace proxy",
-                "operationId": "delete-workspace-proxy",
+                "summary": "Create workspace build",
+                "operationId": "create-workspace-build",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Proxy ID or name",
-                        "name": "workspaceproxy",
+                        "description": "Workspace ID",
+                        "name": "workspace",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Create workspace build request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceBuildRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.QueuedWorkspaceBuild"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/workspaces/{workspace}/builds/queue": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Enterprise"
+                    "Builds"
                 ],
-                "summary": "Update workspace proxy",
-                "operationId": "update-workspace-proxy",
+                "summary": "Get queued workspace builds",
+                "operationId": "get-queued-workspace-builds",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Proxy ID or name",
-                        "name": "workspaceproxy",
+                        "description": "Workspace ID",
+                        "name": "workspace",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Update workspace proxy request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.PatchWorkspaceProxy"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceProxy"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.QueuedWorkspaceBuild"
+                            }
                         }
                     }
                 }
             }
         },
-        "/workspaces": {
-            "get": {
+        "/workspaces/{workspace}/clone": {
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "List workspaces",
-                "operationId": "list-workspaces",
+                "summary": "Clone workspace",
+                "operationId": "clone-workspace",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query in the format ` + "`" + `key:value` + "`" + `. Available keys are: owner, template, name, status, has-agent, deleting_by.",
-                        "name": "q",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
-                        "in": "query"
+                        "format": "uuid",
+                        "description": "Workspace ID",
+                        "name": "workspace",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "Clone workspace request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CloneWorkspaceRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspacesResponse"
+                            "$ref": "#/definitions/codersdk.Workspace"
                         }
                     }
                 }
             }
         },
-        "/workspaces/{workspace}": {
+        "/workspaces/{workspace}/connections": {
             "get": {
                 "security": [
                     {
@@ -5865,8 +9045,8 @@ const docTemplate = `{
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Get workspace metadata by ID",
-                "operationId": "get-workspace-metadata-by-id",
+                "summary": "Get workspace connections",
+                "operationId": "get-workspace-connections",
                 "parameters": [
                     {
                         "type": "string",
@@ -5875,24 +9055,23 @@ const docTemplate = `{
                         "name": "workspace",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Return data instead of HTTP 404 if the workspace is deleted",
-                        "name": "include_deleted",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceConnection"
+                            }
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/workspaces/{workspace}/extend": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -5901,11 +9080,14 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Update workspace metadata by ID",
-                "operationId": "update-workspace-metadata-by-id",
+                "summary": "Extend workspace deadline by ID",
+                "operationId": "extend-workspace-deadline-by-id",
                 "parameters": [
                     {
                         "type": "string",
@@ -5916,23 +9098,26 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Metadata update request",
+                        "description": "Extend deadline update request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateWorkspaceRequest"
+                            "$ref": "#/definitions/codersdk.PutExtendWorkspaceRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.Response"
+                        }
                     }
                 }
             }
         },
-        "/workspaces/{workspace}/autostart": {
+        "/workspaces/{workspace}/external-metadata": {
             "put": {
                 "security": [
                     {
@@ -5945,8 +9130,8 @@ const docTemplate = `{
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Update workspace autostart schedule by ID",
-                "operationId": "update-workspace-autostart-schedule-by-id",
+                "summary": "Put workspace external metadata by ID",
+                "operationId": "put-workspace-external-metadata-by-id",
                 "parameters": [
                     {
                         "type": "string",
@@ -5957,12 +9142,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Schedule update request",
+                        "description": "External metadata request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateWorkspaceAutostartRequest"
+                            "$ref": "#/definitions/codersdk.PutWorkspaceExternalMetadataRequest"
                         }
                     }
                 ],
@@ -5973,21 +9158,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspace}/builds": {
-            "get": {
+        "/workspaces/{workspace}/lock": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Builds"
+                    "Workspaces"
                 ],
-                "summary": "Get workspace builds by workspace ID",
-                "operationId": "get-workspace-builds-by-workspace-id",
+                "summary": "Update workspace lock by id.",
+                "operationId": "update-workspace-lock-by-id",
                 "parameters": [
                     {
                         "type": "string",
@@ -5998,45 +9186,27 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "format": "uuid",
-                        "description": "After ID",
-                        "name": "after_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page offset",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "format": "date-time",
-                        "description": "Since timestamp",
-                        "name": "since",
-                        "in": "query"
+                        "description": "Lock or unlock a workspace",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceLock"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/codersdk.WorkspaceBuild"
-                            }
+                            "$ref": "#/definitions/codersdk.Workspace"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/workspaces/{workspace}/ttl": {
+            "put": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -6045,14 +9215,11 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Builds"
+                    "Workspaces"
                 ],
-                "summary": "Create workspace build",
-                "operationId": "create-workspace-build",
+                "summary": "Update workspace TTL by ID",
+                "operationId": "update-workspace-ttl-by-id",
                 "parameters": [
                     {
                         "type": "string",
@@ -6063,43 +9230,37 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Create workspace build request",
+                        "description": "Workspace TTL update request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.CreateWorkspaceBuildRequest"
+                            "$ref": "#/definitions/codersdk.UpdateWorkspaceTTLRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.WorkspaceBuild"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/workspaces/{workspace}/extend": {
-            "put": {
+        "/workspaces/{workspace}/watch": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Extend workspace deadline by ID",
-                "operationId": "extend-workspace-deadline-by-id",
+                "summary": "Watch workspace by ID",
+                "operationId": "watch-workspace-by-id",
                 "parameters": [
                     {
                         "type": "string",
@@ -6108,15 +9269,6 @@ const docTemplate = `{
                         "name": "workspace",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Extend deadline update request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.PutExtendWorkspaceRequest"
-                        }
                     }
                 ],
                 "responses": {
@@ -6129,24 +9281,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/workspaces/{workspace}/lock": {
-            "put": {
+        "/workspaces/{workspace}/webhooks": {
+            "get": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Update workspace lock by id.",
-                "operationId": "update-workspace-lock-by-id",
+                "summary": "Get workspace webhooks",
+                "operationId": "get-workspace-webhooks",
                 "parameters": [
                     {
                         "type": "string",
@@ -6155,29 +9304,21 @@ const docTemplate = `{
                         "name": "workspace",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Lock or unlock a workspace",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateWorkspaceLock"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Workspace"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/codersdk.WorkspaceWebhook"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/workspaces/{workspace}/ttl": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "CoderSessionToken": []
@@ -6186,11 +9327,14 @@ const docTemplate = `{
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Update workspace TTL by ID",
-                "operationId": "update-workspace-ttl-by-id",
+                "summary": "Create workspace webhook",
+                "operationId": "create-workspace-webhook",
                 "parameters": [
                     {
                         "type": "string",
@@ -6201,52 +9345,80 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Workspace TTL update request",
+                        "description": "Create workspace webhook request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/codersdk.UpdateWorkspaceTTLRequest"
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceWebhookRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/codersdk.CreateWorkspaceWebhookResponse"
+                        }
                     }
                 }
             }
         },
-        "/workspaces/{workspace}/watch": {
-            "get": {
+        "/workspacewebhooks/{workspacewebhook}": {
+            "delete": {
                 "security": [
                     {
                         "CoderSessionToken": []
                     }
                 ],
+                "tags": [
+                    "Workspaces"
+                ],
+                "summary": "Delete workspace webhook",
+                "operationId": "delete-workspace-webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "format": "uuid",
+                        "description": "Workspace webhook ID",
+                        "name": "workspacewebhook",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/workspacewebhooks/{workspacewebhook}/trigger": {
+            "post": {
+                "description": "Runs the action of the webhook. Requests aren't authenticated with a session\ntoken, but must be signed with the webhook secret using the\nCoder-Webhook-Timestamp and Coder-Webhook-Signature headers. Each request\nmust have a later timestamp than the last accepted one.",
                 "produces": [
-                    "text/event-stream"
+                    "application/json"
                 ],
                 "tags": [
                     "Workspaces"
                 ],
-                "summary": "Watch workspace by ID",
-                "operationId": "watch-workspace-by-id",
+                "summary": "Trigger workspace webhook",
+                "operationId": "trigger-workspace-webhook",
                 "parameters": [
                     {
                         "type": "string",
                         "format": "uuid",
-                        "description": "Workspace ID",
-                        "name": "workspace",
+                        "description": "Workspace webhook ID",
+                        "name": "workspacewebhook",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/codersdk.Response"
+                            "$ref": "#/definitions/codersdk.TriggerWorkspaceWebhookResponse"
                         }
                     }
                 }
@@ -6351,6 +9523,53 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.ConnectionBandwidth": {
+            "type": "object",
+            "properties": {
+                "peer": {
+                    "description": "Peer is the tailnet IP address of the remote end of the connections.",
+                    "type": "string"
+                },
+                "port": {
+                    "description": "Port is the agent port the connections were made to. It's only set\nfor port forwarded connections.",
+                    "type": "integer"
+                },
+                "rx_bytes": {
+                    "type": "integer"
+                },
+                "tx_bytes": {
+                    "type": "integer"
+                },
+                "type": {
+                    "enum": [
+                        "ssh",
+                        "reconnecting_pty",
+                        "speedtest",
+                        "port_forward"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/agentsdk.ConnectionType"
+                        }
+                    ]
+                }
+            }
+        },
+        "agentsdk.ConnectionType": {
+            "type": "string",
+            "enum": [
+                "ssh",
+                "reconnecting_pty",
+                "speedtest",
+                "port_forward"
+            ],
+            "x-enum-varnames": [
+                "ConnectionTypeSSH",
+                "ConnectionTypeReconnectingPTY",
+                "ConnectionTypeSpeedtest",
+                "ConnectionTypePortForward"
+            ]
+        },
         "agentsdk.GitAuthResponse": {
             "type": "object",
             "properties": {
@@ -6416,6 +9635,10 @@ const docTemplate = `{
                         "$ref": "#/definitions/codersdk.WorkspaceApp"
                     }
                 },
+                "auth_token_rotation_interval": {
+                    "description": "AuthTokenRotationInterval is how often the agent replaces its auth\ntoken with RotateAuthToken. Rotation is disabled if zero.",
+                    "type": "integer"
+                },
                 "derpmap": {
                     "$ref": "#/definitions/tailcfg.DERPMap"
                 },
@@ -6425,6 +9648,22 @@ const docTemplate = `{
                 "disable_direct_connections": {
                     "type": "boolean"
                 },
+                "disconnect_timeout": {
+                    "type": "integer"
+                },
+                "dns_nameservers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dns_search_domains": {
+                    "description": "DNSSearchDomains are prepended to the search list of the resolver\nconfiguration of the agent, and DNSNameservers replace its\nnameservers.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "environment_variables": {
                     "type": "object",
                     "additionalProperties": {
@@ -6435,6 +9674,10 @@ const docTemplate = `{
                     "description": "GitAuthConfigs stores the number of Git configurations\nthe Coder deployment has. If this number is \u003e0, we\nset up special configuration in the workspace.",
                     "type": "integer"
                 },
+                "keepalive_interval": {
+                    "description": "KeepaliveInterval is how often the agent pings its websockets to\ncoderd, and DisconnectTimeout how long it waits for a pong before it\nreconnects. Zero values keep the defaults.",
+                    "type": "integer"
+                },
                 "metadata": {
                     "type": "array",
                     "items": {
@@ -6444,12 +9687,31 @@ const docTemplate = `{
                 "motd_file": {
                     "type": "string"
                 },
+                "port_policies": {
+                    "description": "PortPolicies restrict the ports that the agent lists and forwards. A\nport must be allowed by all of them.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.PortPolicy"
+                    }
+                },
+                "require_binary_verification": {
+                    "description": "RequireBinaryVerification stops the agent from running the startup\nscript if its binary doesn't match the checksum served by the\ndeployment.",
+                    "type": "boolean"
+                },
                 "shutdown_script": {
                     "type": "string"
                 },
                 "shutdown_script_timeout": {
                     "type": "integer"
                 },
+                "ssh_host_certificate": {
+                    "description": "SSHHostCertificate is the certificate of the host key signed by the\nSSH certificate authority of the deployment, in the authorized_keys\nformat.",
+                    "type": "string"
+                },
+                "ssh_host_key": {
+                    "description": "SSHHostKey is the PEM encoded private host key of the SSH server. It\nis persisted for the workspace and agent name, so it doesn't change\nwhen the workspace is rebuilt.",
+                    "type": "string"
+                },
                 "startup_script": {
                     "type": "string"
                 },
@@ -6520,6 +9782,13 @@ const docTemplate = `{
                 "expanded_directory": {
                     "type": "string"
                 },
+                "host_facts": {
+                    "description": "HostFacts describe the host the agent runs on. The keys are the\ncodersdk.AgentHostFact constants.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "subsystems": {
                     "type": "array",
                     "items": {
@@ -6531,9 +9800,41 @@ const docTemplate = `{
                 }
             }
         },
+        "agentsdk.RotateAuthTokenResponse": {
+            "type": "object",
+            "properties": {
+                "auth_token": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "previous_auth_token_expires_at": {
+                    "description": "PreviousAuthTokenExpiresAt is when the token used for the request\nstops being accepted.",
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
         "agentsdk.Stats": {
             "type": "object",
             "properties": {
+                "activity": {
+                    "description": "Activity is the last time each kind of user activity was observed\nsince the previous report, in the agent clock. Kinds without activity\nare omitted.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "clock_offset_ms": {
+                    "description": "ClockOffsetMS is the offset of the agent clock from the clock of\ncoderd in milliseconds, as measured by the previous report. Positive\nvalues mean the agent clock is ahead.",
+                    "type": "integer"
+                },
+                "connection_bandwidth": {
+                    "description": "ConnectionBandwidth attributes the transferred bytes to the type of\nconnection and the peer on the other end.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/agentsdk.ConnectionBandwidth"
+                    }
+                },
                 "connection_count": {
                     "description": "ConnectionCount is the number of connections received by an agent.",
                     "type": "integer"
@@ -6596,6 +9897,11 @@ const docTemplate = `{
                 "report_interval": {
                     "description": "ReportInterval is the duration after which the agent should send stats\nagain.",
                     "type": "integer"
+                },
+                "server_time": {
+                    "description": "ServerTime is the time the report was received, which agents use to\nmeasure the offset of their clock.",
+                    "type": "string",
+                    "format": "date-time"
                 }
             }
         },
@@ -6725,6 +10031,25 @@ const docTemplate = `{
                 }
             }
         },
+        "clibase.Struct-array_codersdk_WorkspaceHookConfig": {
+            "type": "object",
+            "properties": {
+                "value": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceHookConfig"
+                    }
+                }
+            }
+        },
+        "clibase.Struct-codersdk_PortPolicy": {
+            "type": "object",
+            "properties": {
+                "value": {
+                    "$ref": "#/definitions/codersdk.PortPolicy"
+                }
+            }
+        },
         "clibase.URL": {
             "type": "object",
             "properties": {
@@ -6794,6 +10119,106 @@ const docTemplate = `{
                 "ValueSourceDefault"
             ]
         },
+        "coderd.SCIMGroup": {
+            "type": "object",
+            "properties": {
+                "displayName": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coderd.SCIMGroupMember"
+                    }
+                },
+                "meta": {
+                    "type": "object",
+                    "properties": {
+                        "resourceType": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "coderd.SCIMGroupList": {
+            "type": "object",
+            "properties": {
+                "Resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coderd.SCIMGroup"
+                    }
+                },
+                "itemsPerPage": {
+                    "type": "integer"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "startIndex": {
+                    "type": "integer"
+                },
+                "totalResults": {
+                    "type": "integer"
+                }
+            }
+        },
+        "coderd.SCIMGroupMember": {
+            "type": "object",
+            "properties": {
+                "display": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "coderd.SCIMPatchOperation": {
+            "type": "object",
+            "properties": {
+                "op": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "object"
+                }
+            }
+        },
+        "coderd.SCIMPatchRequest": {
+            "type": "object",
+            "properties": {
+                "Operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/coderd.SCIMPatchOperation"
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "coderd.SCIMUser": {
             "type": "object",
             "properties": {
@@ -6965,6 +10390,17 @@ const docTemplate = `{
                 "APIKeyScopeApplicationConnect"
             ]
         },
+        "codersdk.ActivateLicenseRequest": {
+            "type": "object",
+            "required": [
+                "activation_key"
+            ],
+            "properties": {
+                "activation_key": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.AddLicenseRequest": {
             "type": "object",
             "required": [
@@ -6976,6 +10412,66 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.AgentActivitySource": {
+            "type": "string",
+            "enum": [
+                "ssh",
+                "reconnecting_pty",
+                "app",
+                "port_forward"
+            ],
+            "x-enum-varnames": [
+                "AgentActivitySourceSSH",
+                "AgentActivitySourceReconnectingPTY",
+                "AgentActivitySourceApp",
+                "AgentActivitySourcePortForward"
+            ]
+        },
+        "codersdk.AgentConnectionCacheConfig": {
+            "type": "object",
+            "properties": {
+                "max_age": {
+                    "type": "integer"
+                },
+                "max_size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.AgentFirstConnectInsightsReport": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "template_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateAgentFirstConnect"
+                    }
+                }
+            }
+        },
+        "codersdk.AgentFirstConnectInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/codersdk.AgentFirstConnectInsightsReport"
+                }
+            }
+        },
         "codersdk.AgentSubsystem": {
             "type": "string",
             "enum": [
@@ -7039,7 +10535,9 @@ const docTemplate = `{
                 "stop",
                 "login",
                 "logout",
-                "register"
+                "register",
+                "open",
+                "connect"
             ],
             "x-enum-varnames": [
                 "AuditActionCreate",
@@ -7049,9 +10547,22 @@ const docTemplate = `{
                 "AuditActionStop",
                 "AuditActionLogin",
                 "AuditActionLogout",
-                "AuditActionRegister"
+                "AuditActionRegister",
+                "AuditActionOpen",
+                "AuditActionConnect"
             ]
         },
+        "codersdk.AuditArchivalConfig": {
+            "type": "object",
+            "properties": {
+                "destination": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "retention": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.AuditDiff": {
             "type": "object",
             "additionalProperties": {
@@ -7136,6 +10647,67 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.AuditLogArchival": {
+            "type": "object",
+            "properties": {
+                "archived_count": {
+                    "type": "integer"
+                },
+                "cutoff": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "destination": {
+                    "description": "Destination is empty when audit logs were deleted without being\narchived.",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "initiator_id": {
+                    "description": "InitiatorID is the user that triggered the run. It's empty for runs\nstarted by the retention policy.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "status": {
+                    "enum": [
+                        "running",
+                        "succeeded",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AuditLogArchivalStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.AuditLogArchivalStatus": {
+            "type": "string",
+            "enum": [
+                "running",
+                "succeeded",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "AuditLogArchivalStatusRunning",
+                "AuditLogArchivalStatusSucceeded",
+                "AuditLogArchivalStatusFailed"
+            ]
+        },
         "codersdk.AuditLogResponse": {
             "type": "object",
             "properties": {
@@ -7150,6 +10722,26 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.AuditSinksConfig": {
+            "type": "object",
+            "properties": {
+                "kafka_rest_proxy_url": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "kafka_topic": {
+                    "type": "string"
+                },
+                "splunk_hec_token": {
+                    "type": "string"
+                },
+                "splunk_hec_url": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "syslog_address": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.AuthMethod": {
             "type": "object",
             "properties": {
@@ -7182,7 +10774,9 @@ const docTemplate = `{
                         "create",
                         "read",
                         "update",
-                        "delete"
+                        "delete",
+                        "push_version",
+                        "manage_acl"
                     ]
                 },
                 "object": {
@@ -7225,7 +10819,7 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "checks": {
-                    "description": "Checks is a map keyed with an arbitrary string to a permission check.\nThe key can be any string that is helpful to the caller, and allows\nmultiple permission checks to be run in a single request.\nThe key ensures that each permission check has the same key in the\nresponse.",
+                    "description": "Checks is a map keyed with an arbitrary string to a permission check.\nThe key can be any string that is helpful to the caller, and allows\nmultiple permission checks to be run in a single request.\nThe key ensures that each permission check has the same key in the\nresponse.\nA request can hold up to 1000 checks, which can reference up to 100\ndistinct objects by resource ID.",
                     "type": "object",
                     "additionalProperties": {
                         "$ref": "#/definitions/codersdk.AuthorizationCheck"
@@ -7239,6 +10833,30 @@ const docTemplate = `{
                 "type": "boolean"
             }
         },
+        "codersdk.AutomaticUpdates": {
+            "type": "string",
+            "enum": [
+                "always",
+                "never"
+            ],
+            "x-enum-varnames": [
+                "AutomaticUpdatesAlways",
+                "AutomaticUpdatesNever"
+            ]
+        },
+        "codersdk.BuildHookPhase": {
+            "type": "string",
+            "enum": [
+                "pre",
+                "post",
+                "clone"
+            ],
+            "x-enum-varnames": [
+                "BuildHookPhasePre",
+                "BuildHookPhasePost",
+                "BuildHookPhaseClone"
+            ]
+        },
         "codersdk.BuildInfoResponse": {
             "type": "object",
             "properties": {
@@ -7264,14 +10882,39 @@ const docTemplate = `{
             "enum": [
                 "initiator",
                 "autostart",
-                "autostop"
+                "autostop",
+                "autolock",
+                "failedstop",
+                "autodelete",
+                "bulk",
+                "webhook"
             ],
             "x-enum-varnames": [
                 "BuildReasonInitiator",
                 "BuildReasonAutostart",
-                "BuildReasonAutostop"
+                "BuildReasonAutostop",
+                "BuildReasonAutolock",
+                "BuildReasonFailedStop",
+                "BuildReasonAutodelete",
+                "BuildReasonBulk",
+                "BuildReasonWebhook"
             ]
         },
+        "codersdk.CloneWorkspaceRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "copy_data": {
+                    "description": "CopyData runs the clone hooks of the template in the first build of the\nclone, to copy the data of the workspace like its persistent volumes.",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.ConnectionLatency": {
             "type": "object",
             "properties": {
@@ -7305,6 +10948,16 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateAuditLogArchivalRequest": {
+            "type": "object",
+            "properties": {
+                "cutoff": {
+                    "description": "Cutoff archives audit logs older than this time. It defaults to the\nconfigured audit log retention.",
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
         "codersdk.CreateFirstUserRequest": {
             "type": "object",
             "required": [
@@ -7349,6 +11002,13 @@ const docTemplate = `{
                 "display_name": {
                     "type": "string"
                 },
+                "metadata": {
+                    "description": "Metadata is arbitrary key-value metadata of the group, such as the\nteam or cost center it represents.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "name": {
                     "type": "string"
                 },
@@ -7357,6 +11017,24 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateManagedEnvironmentVariableRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret variables are still injected into agents, but their value is\nnever returned by the API.",
+                    "type": "boolean"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.CreateOrganizationRequest": {
             "type": "object",
             "required": [
@@ -7368,6 +11046,27 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateSSHCertificateRequest": {
+            "type": "object",
+            "required": [
+                "public_key"
+            ],
+            "properties": {
+                "public_key": {
+                    "description": "PublicKey is the SSH public key to sign in the authorized_keys format.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.CreateTemplateAccessRequest": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "Message is an optional explanation of why access is needed.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.CreateTemplateRequest": {
             "type": "object",
             "required": [
@@ -7462,6 +11161,15 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateTemplateVersionPromotionRequest": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "Message is an optional description of the changes in the version for\nthe approvers.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.CreateTemplateVersionRequest": {
             "type": "object",
             "required": [
@@ -7645,6 +11353,19 @@ const docTemplate = `{
                 "dry_run": {
                     "type": "boolean"
                 },
+                "if_active": {
+                    "description": "IfActive decides what happens if the workspace already has an active\nbuild (\"fail\" if empty). Queued builds are answered with 202 Accepted\nand a QueuedWorkspaceBuild.",
+                    "enum": [
+                        "fail",
+                        "queue",
+                        "supersede"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceBuildConflictPolicy"
+                        }
+                    ]
+                },
                 "log_level": {
                     "description": "Log level changes the default logging verbosity of a provider (\"info\" if empty).",
                     "enum": [
@@ -7660,6 +11381,18 @@ const docTemplate = `{
                     "description": "Orphan may be set for the Destroy transition.",
                     "type": "boolean"
                 },
+                "reason": {
+                    "description": "Reason is recorded on the build to explain why it was started\n(\"initiator\" if empty). The other reasons are reserved for builds that\nCoder starts on its own.",
+                    "enum": [
+                        "initiator",
+                        "bulk"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.BuildReason"
+                        }
+                    ]
+                },
                 "rich_parameter_values": {
                     "description": "ParameterValues are optional. It will write params to the 'workspace' scope.\nThis will overwrite any existing parameters with the same name.\nThis will not delete old params not included in this list.",
                     "type": "array",
@@ -7716,6 +11449,18 @@ const docTemplate = `{
                 "template_id"
             ],
             "properties": {
+                "automatic_updates": {
+                    "description": "AutomaticUpdates defaults to never.",
+                    "enum": [
+                        "always",
+                        "never"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AutomaticUpdates"
+                        }
+                    ]
+                },
                 "autostart_schedule": {
                     "type": "string"
                 },
@@ -7729,6 +11474,13 @@ const docTemplate = `{
                         "$ref": "#/definitions/codersdk.WorkspaceBuildParameter"
                     }
                 },
+                "state": {
+                    "description": "ProvisionerState is an existing Terraform state the first build starts\nfrom, to adopt infrastructure that was created outside of Coder. Only\ntemplate managers may provide it.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "template_id": {
                     "type": "string",
                     "format": "uuid"
@@ -7738,6 +11490,50 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.CreateWorkspaceWebhookRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "name"
+            ],
+            "properties": {
+                "action": {
+                    "enum": [
+                        "start",
+                        "stop",
+                        "update",
+                        "exec"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceWebhookAction"
+                        }
+                    ]
+                },
+                "agent_name": {
+                    "description": "AgentName is the agent that runs the command of an exec webhook. It\nmay be omitted if the workspace has a single agent.",
+                    "type": "string"
+                },
+                "command": {
+                    "description": "Command is required for exec webhooks, and must be empty otherwise.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.CreateWorkspaceWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "webhook": {
+                    "$ref": "#/definitions/codersdk.WorkspaceWebhook"
+                }
+            }
+        },
         "codersdk.DAUEntry": {
             "type": "object",
             "properties": {
@@ -7775,6 +11571,27 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.DERPClientUsage": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "connections": {
+                    "type": "integer"
+                },
+                "last_seen_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "received_bytes": {
+                    "type": "integer"
+                },
+                "sent_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.DERPConfig": {
             "type": "object",
             "properties": {
@@ -7789,6 +11606,20 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.DERPMeshUsage": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "received_bytes": {
+                    "type": "integer"
+                },
+                "sent_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.DERPRegion": {
             "type": "object",
             "properties": {
@@ -7826,6 +11657,39 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.DERPUsageReport": {
+            "type": "object",
+            "properties": {
+                "clients": {
+                    "description": "Clients is the traffic per client address, largest first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.DERPClientUsage"
+                    }
+                },
+                "connections": {
+                    "description": "Connections is the number of open client connections.",
+                    "type": "integer"
+                },
+                "mesh": {
+                    "description": "Mesh is the traffic exchanged with other DERP servers of the region.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.DERPMeshUsage"
+                    }
+                },
+                "received_bytes": {
+                    "description": "ReceivedBytes and SentBytes are the bytes exchanged with clients since\nthe replica started.",
+                    "type": "integer"
+                },
+                "region_id": {
+                    "type": "integer"
+                },
+                "sent_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.DangerousConfig": {
             "type": "object",
             "properties": {
@@ -7840,17 +11704,132 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.DebugConnectionsReport": {
+            "type": "object",
+            "properties": {
+                "expected_replicas": {
+                    "description": "ExpectedReplicas is the number of replicas that were asked for their\nconnections. Replicas that didn't respond in time are missing from\nReplicas.",
+                    "type": "integer"
+                },
+                "replicas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.ReplicaConnections"
+                    }
+                }
+            }
+        },
+        "codersdk.DecideTemplateAccessRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "Reason is an optional explanation of the decision.",
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "approved",
+                        "denied"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateAccessRequestStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.DecideTemplateVersionPromotionRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "Reason is an optional explanation of the decision, such as the number\nof the change ticket that approved it.",
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "approved",
+                        "rejected"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateVersionPromotionStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.DecideWorkspaceApprovalRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "Reason is an optional explanation of the decision, such as the number\nof the change ticket that approved it.",
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "approved",
+                        "rejected"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceApprovalStatus"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.DeploymentConfig": {
             "type": "object",
             "properties": {
                 "config": {
                     "$ref": "#/definitions/codersdk.DeploymentValues"
                 },
-                "options": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/clibase.Option"
-                    }
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/clibase.Option"
+                    }
+                }
+            }
+        },
+        "codersdk.DeploymentConfigDiff": {
+            "type": "object",
+            "properties": {
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.DeploymentOptionDiff"
+                    }
+                }
+            }
+        },
+        "codersdk.DeploymentOptionDiff": {
+            "type": "object",
+            "properties": {
+                "default": {},
+                "env": {
+                    "type": "string"
+                },
+                "flag": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "value": {},
+                "yaml": {
+                    "description": "YAML is the full path of the option in the YAML config.",
+                    "type": "string"
                 }
             }
         },
@@ -7880,6 +11859,47 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.DeploymentStatus": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "database": {
+                    "$ref": "#/definitions/codersdk.DeploymentStatusDatabase"
+                },
+                "healthy": {
+                    "description": "Healthy is true when the database is reachable.",
+                    "type": "boolean"
+                },
+                "proxies": {
+                    "$ref": "#/definitions/codersdk.DeploymentStatusProxies"
+                }
+            }
+        },
+        "codersdk.DeploymentStatusDatabase": {
+            "type": "object",
+            "properties": {
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "reachable": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "codersdk.DeploymentStatusProxies": {
+            "type": "object",
+            "properties": {
+                "healthy": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.DeploymentValues": {
             "type": "object",
             "properties": {
@@ -7894,12 +11914,58 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "agent_admission_rate": {
+                    "description": "AgentAdmissionRate is the number of agents per second each replica\nadmits when they (re)connect.",
+                    "type": "integer"
+                },
+                "agent_binary_signing_certificate": {
+                    "description": "AgentBinarySigningCertificate is the PEM-encoded certificate whose key\nsigns the agent binaries. Bootstrap scripts of templates that require\nagent binary verification check the detached signatures against it.",
+                    "type": "string"
+                },
+                "agent_connection_cache": {
+                    "description": "AgentConnectionCache bounds the cached agent connections of workspaces\nthat don't support the server tailnet.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AgentConnectionCacheConfig"
+                        }
+                    ]
+                },
+                "agent_dns_nameservers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "agent_dns_search_domains": {
+                    "description": "AgentDNSSearchDomains and AgentDNSNameservers are written to the\nresolver configuration of workspace agents.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "agent_fallback_troubleshooting_url": {
                     "$ref": "#/definitions/clibase.URL"
                 },
                 "agent_stat_refresh_interval": {
                     "type": "integer"
                 },
+                "agent_token_rotation_interval": {
+                    "description": "AgentTokenRotationInterval is how often workspace agents replace their\nauth token. Rotation is disabled if zero.",
+                    "type": "integer"
+                },
+                "app_identity_headers": {
+                    "description": "AppIdentityHeaders and PortForwardIdentityHeaders are the sharing\nlevels of declared apps and port-forwarded apps whose requests carry\nthe identity of the requesting user.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "audit_archival": {
+                    "$ref": "#/definitions/codersdk.AuditArchivalConfig"
+                },
+                "audit_sinks": {
+                    "$ref": "#/definitions/codersdk.AuditSinksConfig"
+                },
                 "autobuild_poll_interval": {
                     "type": "integer"
                 },
@@ -7939,12 +12005,24 @@ const docTemplate = `{
                 "enable_terraform_debug_mode": {
                     "type": "boolean"
                 },
+                "entitlements_refresh_jitter": {
+                    "type": "integer"
+                },
                 "experiments": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
+                "external_authz": {
+                    "$ref": "#/definitions/codersdk.ExternalAuthzConfig"
+                },
+                "feature_flags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "git_auth": {
                     "$ref": "#/definitions/clibase.Struct-array_codersdk_GitAuthConfig"
                 },
@@ -7958,9 +12036,19 @@ const docTemplate = `{
                 "job_hang_detector_interval": {
                     "type": "integer"
                 },
+                "license_activation_url": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
                 "logging": {
                     "$ref": "#/definitions/codersdk.LoggingConfig"
                 },
+                "max_pty_sessions_per_user": {
+                    "description": "MaxPTYSessionsPerUser and MaxPTYSessionsPerWorkspace limit concurrent\nweb terminal sessions. Zero means no limit.",
+                    "type": "integer"
+                },
+                "max_pty_sessions_per_workspace": {
+                    "type": "integer"
+                },
                 "max_session_expiry": {
                     "type": "integer"
                 },
@@ -7979,6 +12067,20 @@ const docTemplate = `{
                 "pg_connection_url": {
                     "type": "string"
                 },
+                "port_forward_identity_headers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "port_forward_policy": {
+                    "description": "PortForwardPolicy restricts which ports of workspace agents can be\nforwarded. Templates can restrict them further.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/clibase.Struct-codersdk_PortPolicy"
+                        }
+                    ]
+                },
                 "pprof": {
                     "$ref": "#/definitions/codersdk.PprofConfig"
                 },
@@ -7988,6 +12090,12 @@ const docTemplate = `{
                 "provisioner": {
                     "$ref": "#/definitions/codersdk.ProvisionerConfig"
                 },
+                "proxy_geo_rules": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "proxy_health_status_interval": {
                     "type": "integer"
                 },
@@ -8003,6 +12111,9 @@ const docTemplate = `{
                         "type": "string"
                     }
                 },
+                "proxy_version_mismatch_policy": {
+                    "type": "string"
+                },
                 "rate_limit": {
                     "$ref": "#/definitions/codersdk.RateLimitConfig"
                 },
@@ -8012,12 +12123,29 @@ const docTemplate = `{
                 "scim_api_key": {
                     "type": "string"
                 },
+                "scim_deprovision_delete_after": {
+                    "type": "integer"
+                },
+                "scim_deprovision_stop_workspaces": {
+                    "type": "boolean"
+                },
+                "secret_manager": {
+                    "description": "SecretManager configures the secret managers that secret:// references\nin template variables are read from.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.SecretManagerConfig"
+                        }
+                    ]
+                },
                 "secure_auth_cookie": {
                     "type": "boolean"
                 },
                 "ssh_keygen_algorithm": {
                     "type": "string"
                 },
+                "status_require_auth": {
+                    "type": "boolean"
+                },
                 "strict_transport_security": {
                     "type": "integer"
                 },
@@ -8033,6 +12161,14 @@ const docTemplate = `{
                 "swagger": {
                     "$ref": "#/definitions/codersdk.SwaggerConfig"
                 },
+                "tailnet_keepalive": {
+                    "description": "TailnetKeepalive tunes how often agent and coordinator connections are\nchecked and how long they may be silent before they're considered lost.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TailnetKeepaliveConfig"
+                        }
+                    ]
+                },
                 "telemetry": {
                     "$ref": "#/definitions/codersdk.TelemetryConfig"
                 },
@@ -8057,11 +12193,119 @@ const docTemplate = `{
                 "wildcard_access_url": {
                     "$ref": "#/definitions/clibase.URL"
                 },
+                "workspace_approval_webhook": {
+                    "description": "WorkspaceApprovalWebhook is called when a workspace build awaits\napproval. See WorkspaceApprovalWebhookPayload.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceApprovalWebhookConfig"
+                        }
+                    ]
+                },
+                "workspace_hooks": {
+                    "description": "WorkspaceHooks are called around workspace start and stop builds.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/clibase.Struct-array_codersdk_WorkspaceHookConfig"
+                        }
+                    ]
+                },
                 "write_config": {
                     "type": "boolean"
                 }
             }
         },
+        "codersdk.EffectivePermissionEntry": {
+            "type": "object",
+            "properties": {
+                "allow": {
+                    "description": "Allow is false for permissions that deny the action.",
+                    "type": "boolean"
+                },
+                "group_id": {
+                    "description": "GroupID and GroupName are set for group ACL entries.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "group_name": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "Role is the name of the role for role permissions.",
+                    "type": "string"
+                },
+                "source": {
+                    "enum": [
+                        "site",
+                        "org",
+                        "owner",
+                        "user_acl",
+                        "group_acl"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.EffectivePermissionSource"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.EffectivePermissionSource": {
+            "type": "string",
+            "enum": [
+                "site",
+                "org",
+                "owner",
+                "user_acl",
+                "group_acl"
+            ],
+            "x-enum-varnames": [
+                "EffectivePermissionSourceSite",
+                "EffectivePermissionSourceOrg",
+                "EffectivePermissionSourceOwner",
+                "EffectivePermissionSourceUserACL",
+                "EffectivePermissionSourceGroupACL"
+            ]
+        },
+        "codersdk.EffectivePermissions": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "read",
+                        "update",
+                        "delete",
+                        "push_version",
+                        "manage_acl"
+                    ]
+                },
+                "allowed": {
+                    "description": "Allowed is the decision of the authorizer.",
+                    "type": "boolean"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.EffectivePermissionEntry"
+                    }
+                },
+                "object": {
+                    "$ref": "#/definitions/codersdk.AuthorizationObject"
+                },
+                "organization_member": {
+                    "description": "OrganizationMember is false if the object belongs to an organization\nthe user is not a member of. Owner permissions and group ACL entries\ndon't apply to non-members.",
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.Entitlement": {
             "type": "string",
             "enum": [
@@ -8075,6 +12319,55 @@ const docTemplate = `{
                 "EntitlementNotEntitled"
             ]
         },
+        "codersdk.EntitlementSnapshot": {
+            "type": "object",
+            "properties": {
+                "active_user_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "date": {
+                    "description": "Date is the start of the UTC day the snapshot was taken on.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "features": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/codersdk.Feature"
+                    }
+                },
+                "has_license": {
+                    "type": "boolean"
+                },
+                "replica_count": {
+                    "description": "ReplicaCount is the number of primary replicas of the deployment.",
+                    "type": "integer"
+                },
+                "trial": {
+                    "type": "boolean"
+                },
+                "user_count": {
+                    "description": "UserCount is the number of users that aren't deleted, including\nsuspended users.",
+                    "type": "integer"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "codersdk.Entitlements": {
             "type": "object",
             "properties": {
@@ -8090,6 +12383,11 @@ const docTemplate = `{
                         "$ref": "#/definitions/codersdk.Feature"
                     }
                 },
+                "grace_period_ends_at": {
+                    "description": "GracePeriodEndsAt is the earliest time an enabled feature stops working\nbecause the grace period of its expired license ends.",
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "has_license": {
                     "type": "boolean"
                 },
@@ -8100,17 +12398,61 @@ const docTemplate = `{
                 "require_telemetry": {
                     "type": "boolean"
                 },
+                "severity": {
+                    "enum": [
+                        "none",
+                        "warning",
+                        "error"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.EntitlementsSeverity"
+                        }
+                    ]
+                },
                 "trial": {
                     "type": "boolean"
                 },
-                "warnings": {
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "codersdk.EntitlementsHistory": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "snapshots": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/codersdk.EntitlementSnapshot"
                     }
+                },
+                "start_date": {
+                    "type": "string",
+                    "format": "date-time"
                 }
             }
         },
+        "codersdk.EntitlementsSeverity": {
+            "type": "string",
+            "enum": [
+                "error",
+                "none",
+                "warning"
+            ],
+            "x-enum-varnames": [
+                "EntitlementsSeverityError",
+                "EntitlementsSeverityNone",
+                "EntitlementsSeverityWarning"
+            ]
+        },
         "codersdk.Experiment": {
             "type": "string",
             "enum": [
@@ -8132,6 +12474,41 @@ const docTemplate = `{
                 "ExperimentWorkspacesBatchActions"
             ]
         },
+        "codersdk.ExperimentsConfig": {
+            "type": "object",
+            "properties": {
+                "deployment": {
+                    "description": "Deployment experiments are enabled for everyone with --experiments.\nThey can only be changed by restarting the deployment.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.Experiment"
+                    }
+                },
+                "runtime": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.RuntimeExperiment"
+                    }
+                }
+            }
+        },
+        "codersdk.ExternalAuthzConfig": {
+            "type": "object",
+            "properties": {
+                "cache_ttl": {
+                    "type": "integer"
+                },
+                "fallback": {
+                    "type": "string"
+                },
+                "policy_dir": {
+                    "type": "string"
+                },
+                "url": {
+                    "$ref": "#/definitions/clibase.URL"
+                }
+            }
+        },
         "codersdk.Feature": {
             "type": "object",
             "properties": {
@@ -8144,11 +12521,79 @@ const docTemplate = `{
                 "entitlement": {
                     "$ref": "#/definitions/codersdk.Entitlement"
                 },
+                "grace_period_ends_at": {
+                    "description": "GracePeriodEndsAt is when the feature stops working if its entitlement\nis in the grace period of an expired license.",
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "limit": {
                     "type": "integer"
                 }
             }
         },
+        "codersdk.FeatureFlags": {
+            "type": "object",
+            "properties": {
+                "flags": {
+                    "description": "Flags maps each configured flag to whether it's enabled for the\ncaller. Flags rolled out to a percentage of the deployment are\nenabled consistently for the same user or agent.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                }
+            }
+        },
+        "codersdk.FeatureName": {
+            "type": "string",
+            "enum": [
+                "user_limit",
+                "audit_log",
+                "browser_only",
+                "scim",
+                "template_rbac",
+                "user_role_management",
+                "high_availability",
+                "multiple_git_auth",
+                "external_provisioner_daemons",
+                "appearance",
+                "advanced_template_scheduling",
+                "template_restart_requirement",
+                "workspace_proxy"
+            ],
+            "x-enum-varnames": [
+                "FeatureUserLimit",
+                "FeatureAuditLog",
+                "FeatureBrowserOnly",
+                "FeatureSCIM",
+                "FeatureTemplateRBAC",
+                "FeatureUserRoleManagement",
+                "FeatureHighAvailability",
+                "FeatureMultipleGitAuth",
+                "FeatureExternalProvisionerDaemons",
+                "FeatureAppearance",
+                "FeatureAdvancedTemplateScheduling",
+                "FeatureTemplateRestartRequirement",
+                "FeatureWorkspaceProxy"
+            ]
+        },
+        "codersdk.FeatureUnavailable": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "entitlement": {
+                    "$ref": "#/definitions/codersdk.Entitlement"
+                },
+                "feature": {
+                    "$ref": "#/definitions/codersdk.FeatureName"
+                },
+                "guidance": {
+                    "description": "Guidance explains how to get access to the feature, e.g. by upgrading\nthe license or changing the deployment configuration.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.GenerateAPIKeyResponse": {
             "type": "object",
             "properties": {
@@ -8360,6 +12805,12 @@ const docTemplate = `{
                         "$ref": "#/definitions/codersdk.User"
                     }
                 },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "name": {
                     "type": "string"
                 },
@@ -8370,8 +12821,28 @@ const docTemplate = `{
                 "quota_allowance": {
                     "type": "integer"
                 },
+                "quota_override": {
+                    "description": "QuotaOverride replaces the summed quota allowances of members of the\ngroup when set.",
+                    "type": "integer"
+                },
                 "source": {
                     "$ref": "#/definitions/codersdk.GroupSource"
+                },
+                "version": {
+                    "description": "Version changes whenever the group or its members change. Pass it to\nPatchGroupIfMatch to only patch the group if it's unchanged.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.GroupDefaultTemplate": {
+            "type": "object",
+            "properties": {
+                "create_workspace": {
+                    "type": "boolean"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
                 }
             }
         },
@@ -8441,11 +12912,15 @@ const docTemplate = `{
             "type": "string",
             "enum": [
                 "MISSING_TEMPLATE_PARAMETER",
-                "REQUIRED_TEMPLATE_VARIABLES"
+                "REQUIRED_TEMPLATE_VARIABLES",
+                "BUILD_TIMEOUT",
+                "RESOURCE_LIMIT_EXCEEDED"
             ],
             "x-enum-varnames": [
                 "MissingTemplateParameter",
-                "RequiredTemplateVariables"
+                "RequiredTemplateVariables",
+                "BuildTimeout",
+                "ResourceLimitExceeded"
             ]
         },
         "codersdk.License": {
@@ -8469,6 +12944,78 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.LicenseFeatureUsage": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "description": "Actual and Limit are the current usage and licensed limit, as reported\nby the entitlements.",
+                    "type": "integer"
+                },
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.LicenseUsageDay"
+                    }
+                },
+                "days_exceeded": {
+                    "description": "DaysExceeded is the number of days in the requested range on which\nusage exceeded the licensed limit.",
+                    "type": "integer"
+                },
+                "feature": {
+                    "$ref": "#/definitions/codersdk.FeatureName"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "peak_actual": {
+                    "description": "PeakActual is the highest usage recorded in the requested range.",
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.LicenseUsage": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "features": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.LicenseFeatureUsage"
+                    }
+                },
+                "start_date": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.LicenseUsageDay": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "description": "Date is the start of the UTC day.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "exceeded_at": {
+                    "description": "ExceededAt is the first time during the day that usage exceeded the\nlicensed limit.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "latest_actual": {
+                    "type": "integer"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "peak_actual": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.LinkConfig": {
             "type": "object",
             "properties": {
@@ -8566,34 +13113,258 @@ const docTemplate = `{
                 }
             }
         },
-        "codersdk.LoginWithPasswordResponse": {
+        "codersdk.LoginWithPasswordResponse": {
+            "type": "object",
+            "required": [
+                "session_token"
+            ],
+            "properties": {
+                "session_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.ManagedEnvironmentVariable": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "organization_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "secret": {
+                    "type": "boolean"
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "value": {
+                    "description": "Value is omitted for secret variables.",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.MinimalUser": {
+            "type": "object",
+            "required": [
+                "id",
+                "username"
+            ],
+            "properties": {
+                "avatar_url": {
+                    "type": "string",
+                    "format": "uri"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.NATType": {
+            "type": "string",
+            "enum": [
+                "unknown",
+                "udp_blocked",
+                "easy",
+                "hard"
+            ],
+            "x-enum-varnames": [
+                "NATTypeUnknown",
+                "NATTypeUDPBlocked",
+                "NATTypeEasy",
+                "NATTypeHard"
+            ]
+        },
+        "codersdk.NetcheckBlockedPortInsight": {
+            "type": "object",
+            "properties": {
+                "port": {
+                    "type": "string",
+                    "example": "udp/3478"
+                },
+                "reports": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "users": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "codersdk.NetcheckInsightsReport": {
+            "type": "object",
+            "properties": {
+                "blocked_ports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.NetcheckBlockedPortInsight"
+                    }
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "nat_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.NetcheckNATTypeInsight"
+                    }
+                },
+                "reports": {
+                    "description": "Reports holds the most recent preflights, newest first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.NetcheckReport"
+                    }
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "total_reports": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "user_id": {
+                    "description": "UserID is set if the report is limited to the preflights of a user.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.NetcheckInsightsResponse": {
             "type": "object",
-            "required": [
-                "session_token"
-            ],
             "properties": {
-                "session_token": {
-                    "type": "string"
+                "report": {
+                    "$ref": "#/definitions/codersdk.NetcheckInsightsReport"
                 }
             }
         },
-        "codersdk.MinimalUser": {
+        "codersdk.NetcheckNATTypeInsight": {
             "type": "object",
-            "required": [
-                "id",
-                "username"
-            ],
             "properties": {
-                "avatar_url": {
+                "nat_type": {
+                    "enum": [
+                        "unknown",
+                        "udp_blocked",
+                        "easy",
+                        "hard"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.NATType"
+                        }
+                    ]
+                },
+                "reports": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "users": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "codersdk.NetcheckReport": {
+            "type": "object",
+            "properties": {
+                "blocked_ports": {
+                    "description": "BlockedPorts lists the ports of STUN and DERP servers the client\ncouldn't reach, e.g. \"udp/3478\".",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "client_os": {
+                    "type": "string"
+                },
+                "client_version": {
+                    "type": "string"
+                },
+                "created_at": {
                     "type": "string",
-                    "format": "uri"
+                    "format": "date-time"
+                },
+                "hair_pinning": {
+                    "type": "boolean"
                 },
                 "id": {
                     "type": "string",
                     "format": "uuid"
                 },
-                "username": {
-                    "type": "string"
+                "ipv4": {
+                    "type": "boolean"
+                },
+                "ipv6": {
+                    "type": "boolean"
+                },
+                "mapping_varies_by_dest_ip": {
+                    "description": "MappingVariesByDestIP and HairPinning are omitted when the client\ncouldn't tell.",
+                    "type": "boolean"
+                },
+                "nat_type": {
+                    "enum": [
+                        "unknown",
+                        "udp_blocked",
+                        "easy",
+                        "hard"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.NATType"
+                        }
+                    ]
+                },
+                "preferred_derp": {
+                    "description": "PreferredDERP is the ID of the DERP region with the lowest latency, or\nzero if no region was reached.",
+                    "type": "integer"
+                },
+                "region_latency_ms": {
+                    "description": "RegionLatencyMS holds the latencies to the DERP regions the client\nreached, keyed by region ID.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "udp": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.NotifyOutdatedWorkspacesResponse": {
+            "type": "object",
+            "properties": {
+                "workspace_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
                 }
             }
         },
@@ -8768,6 +13539,10 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "max_concurrent_provisioner_jobs": {
+                    "description": "MaxConcurrentProvisionerJobs limits how many provisioner jobs of the\norganization run at once. Further jobs wait in the queue. 0 means no\nlimit.",
+                    "type": "integer"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -8819,12 +13594,23 @@ const docTemplate = `{
                 "display_name": {
                     "type": "string"
                 },
+                "metadata": {
+                    "description": "Metadata replaces the metadata of the group when set. An empty map\nremoves all metadata.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "name": {
                     "type": "string"
                 },
                 "quota_allowance": {
                     "type": "integer"
                 },
+                "quota_override": {
+                    "description": "QuotaOverride sets the quota override of the group. A negative value\nremoves the override.",
+                    "type": "integer"
+                },
                 "remove_users": {
                     "type": "array",
                     "items": {
@@ -8871,6 +13657,231 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.PendingProvisionerJob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "matching_daemons": {
+                    "description": "MatchingDaemons are the provisioner daemons that may acquire the job.\nIf it's empty, the job won't start until a daemon with matching tags\nconnects.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.ProvisionerDaemon"
+                    }
+                },
+                "provisioner": {
+                    "enum": [
+                        "echo",
+                        "terraform"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProvisionerType"
+                        }
+                    ]
+                },
+                "tags": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "codersdk.PlatformEvent": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "data": {
+                    "type": "object"
+                },
+                "id": {
+                    "description": "ID is a monotonically increasing cursor. Pass the ID of the last event\nprocessed as \"after\" to resume the stream.",
+                    "type": "integer"
+                },
+                "resource_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "type": {
+                    "$ref": "#/definitions/codersdk.PlatformEventType"
+                }
+            }
+        },
+        "codersdk.PlatformEventType": {
+            "type": "string",
+            "enum": [
+                "user.created",
+                "user.status_changed",
+                "user.deleted",
+                "workspace.created",
+                "workspace.deleted",
+                "workspace_build.started",
+                "workspace_build.succeeded",
+                "workspace_build.failed",
+                "workspace_build.canceled",
+                "workspace_approval.requested",
+                "workspace_approval.decided",
+                "template_access_request.created",
+                "template_access_request.decided",
+                "template_version_promotion.requested",
+                "template_version_promotion.decided",
+                "entitlements.changed"
+            ],
+            "x-enum-varnames": [
+                "PlatformEventTypeUserCreated",
+                "PlatformEventTypeUserStatusChanged",
+                "PlatformEventTypeUserDeleted",
+                "PlatformEventTypeWorkspaceCreated",
+                "PlatformEventTypeWorkspaceDeleted",
+                "PlatformEventTypeWorkspaceBuildStarted",
+                "PlatformEventTypeWorkspaceBuildSucceeded",
+                "PlatformEventTypeWorkspaceBuildFailed",
+                "PlatformEventTypeWorkspaceBuildCanceled",
+                "PlatformEventTypeWorkspaceApprovalRequested",
+                "PlatformEventTypeWorkspaceApprovalDecided",
+                "PlatformEventTypeTemplateAccessRequestCreated",
+                "PlatformEventTypeTemplateAccessRequestDecided",
+                "PlatformEventTypeTemplateVersionPromotionRequested",
+                "PlatformEventTypeTemplateVersionPromotionDecided",
+                "PlatformEventTypeEntitlementsChanged"
+            ]
+        },
+        "codersdk.PortPolicy": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "description": "Default is the action for ports that no rule contains. Defaults to\n\"allow\".",
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.PortPolicyAction"
+                        }
+                    ]
+                },
+                "rules": {
+                    "description": "Rules are matched in order. The first rule that contains a port\ndecides whether it is allowed.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.PortPolicyRule"
+                    }
+                }
+            }
+        },
+        "codersdk.PortPolicyAction": {
+            "type": "string",
+            "enum": [
+                "allow",
+                "deny"
+            ],
+            "x-enum-varnames": [
+                "PortPolicyActionAllow",
+                "PortPolicyActionDeny"
+            ]
+        },
+        "codersdk.PortPolicyRule": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "enum": [
+                        "allow",
+                        "deny"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.PortPolicyAction"
+                        }
+                    ]
+                },
+                "max_sharing_level": {
+                    "description": "MaxSharingLevel caps the sharing level of apps served from allowed\nports of the rule. Defaults to no cap.",
+                    "enum": [
+                        "owner",
+                        "authenticated",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAppSharingLevel"
+                        }
+                    ]
+                },
+                "ports": {
+                    "description": "Ports is a single port like \"8080\" or an inclusive range like\n\"8000-8999\".",
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.PostNetcheckReportRequest": {
+            "type": "object",
+            "properties": {
+                "blocked_ports": {
+                    "description": "BlockedPorts lists the ports of STUN and DERP servers the client\ncouldn't reach, e.g. \"udp/3478\".",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "client_os": {
+                    "type": "string"
+                },
+                "client_version": {
+                    "type": "string"
+                },
+                "hair_pinning": {
+                    "type": "boolean"
+                },
+                "ipv4": {
+                    "type": "boolean"
+                },
+                "ipv6": {
+                    "type": "boolean"
+                },
+                "mapping_varies_by_dest_ip": {
+                    "description": "MappingVariesByDestIP and HairPinning are omitted when the client\ncouldn't tell.",
+                    "type": "boolean"
+                },
+                "nat_type": {
+                    "enum": [
+                        "unknown",
+                        "udp_blocked",
+                        "easy",
+                        "hard"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.NATType"
+                        }
+                    ]
+                },
+                "preferred_derp": {
+                    "description": "PreferredDERP is the ID of the DERP region with the lowest latency, or\nzero if no region was reached.",
+                    "type": "integer"
+                },
+                "region_latency_ms": {
+                    "description": "RegionLatencyMS holds the latencies to the DERP regions the client\nreached, keyed by region ID.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "udp": {
+                    "type": "boolean"
+                }
+            }
+        },
         "codersdk.PprofConfig": {
             "type": "object",
             "properties": {
@@ -8919,6 +13930,12 @@ const docTemplate = `{
                 },
                 "force_cancel_interval": {
                     "type": "integer"
+                },
+                "hung_job_requeue_limit": {
+                    "type": "integer"
+                },
+                "hung_job_timeout": {
+                    "type": "integer"
                 }
             }
         },
@@ -8961,6 +13978,18 @@ const docTemplate = `{
         "codersdk.ProvisionerJob": {
             "type": "object",
             "properties": {
+                "cancel_outcome": {
+                    "description": "CancelOutcome is set once a canceled job has stopped.",
+                    "enum": [
+                        "graceful",
+                        "forced"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProvisionerJobCancelOutcome"
+                        }
+                    ]
+                },
                 "canceled_at": {
                     "type": "string",
                     "format": "date-time"
@@ -8979,7 +14008,9 @@ const docTemplate = `{
                 "error_code": {
                     "enum": [
                         "MISSING_TEMPLATE_PARAMETER",
-                        "REQUIRED_TEMPLATE_VARIABLES"
+                        "REQUIRED_TEMPLATE_VARIABLES",
+                        "BUILD_TIMEOUT",
+                        "RESOURCE_LIMIT_EXCEEDED"
                     ],
                     "allOf": [
                         {
@@ -8995,6 +14026,18 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "queue_limited_by": {
+                    "description": "QueueLimitedBy is set while a pending job waits for a concurrency limit\nof its template or organization.",
+                    "enum": [
+                        "template",
+                        "organization"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProvisionerJobQueueLimit"
+                        }
+                    ]
+                },
                 "queue_position": {
                     "type": "integer"
                 },
@@ -9032,6 +14075,17 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.ProvisionerJobCancelOutcome": {
+            "type": "string",
+            "enum": [
+                "graceful",
+                "forced"
+            ],
+            "x-enum-varnames": [
+                "ProvisionerJobCancelOutcomeGraceful",
+                "ProvisionerJobCancelOutcomeForced"
+            ]
+        },
         "codersdk.ProvisionerJobLog": {
             "type": "object",
             "properties": {
@@ -9067,6 +14121,62 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.ProvisionerJobQueue": {
+            "type": "object",
+            "properties": {
+                "average_wait_seconds": {
+                    "description": "AverageWaitSeconds is the average time the jobs started within the\nwait window waited to be acquired.",
+                    "type": "number"
+                },
+                "depth": {
+                    "description": "Depth is the number of jobs waiting to be acquired.",
+                    "type": "integer"
+                },
+                "job_age_seconds": {
+                    "description": "JobAgeSeconds are percentiles of how long the pending jobs have been\nwaiting.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProvisionerJobQueuePercentiles"
+                        }
+                    ]
+                },
+                "oldest_job_age_seconds": {
+                    "description": "OldestJobAgeSeconds is how long the oldest pending job has been waiting.",
+                    "type": "number"
+                },
+                "started_jobs": {
+                    "type": "integer"
+                },
+                "wait_window_seconds": {
+                    "type": "number"
+                }
+            }
+        },
+        "codersdk.ProvisionerJobQueueLimit": {
+            "type": "string",
+            "enum": [
+                "template",
+                "organization"
+            ],
+            "x-enum-varnames": [
+                "ProvisionerJobQueueLimitTemplate",
+                "ProvisionerJobQueueLimitOrganization"
+            ]
+        },
+        "codersdk.ProvisionerJobQueuePercentiles": {
+            "type": "object",
+            "properties": {
+                "p50": {
+                    "type": "number"
+                },
+                "p90": {
+                    "type": "number"
+                },
+                "p99": {
+                    "type": "number"
+                }
+            }
+        },
         "codersdk.ProvisionerJobStatus": {
             "type": "string",
             "enum": [
@@ -9129,13 +14239,15 @@ const docTemplate = `{
                 "ok",
                 "unreachable",
                 "unhealthy",
-                "unregistered"
+                "unregistered",
+                "version_mismatch"
             ],
             "x-enum-varnames": [
                 "ProxyHealthy",
                 "ProxyUnreachable",
                 "ProxyUnhealthy",
-                "ProxyUnregistered"
+                "ProxyUnregistered",
+                "ProxyVersionMismatch"
             ]
         },
         "codersdk.PutExtendWorkspaceRequest": {
@@ -9150,6 +14262,72 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.PutWorkspaceExternalMetadataRequest": {
+            "type": "object",
+            "required": [
+                "namespace"
+            ],
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "enum": [
+                        "public",
+                        "private"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceExternalMetadataVisibility"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.QueuedWorkspaceBuild": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "initiator_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "position": {
+                    "description": "Position is the position of the build in the queue of the workspace,\nstarting at 1 for the build that starts next.",
+                    "type": "integer"
+                },
+                "template_version_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "transition": {
+                    "enum": [
+                        "start",
+                        "stop",
+                        "delete"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceTransition"
+                        }
+                    ]
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.RBACResource": {
             "type": "string",
             "enum": [
@@ -9159,6 +14337,7 @@ const docTemplate = `{
                 "application_connect",
                 "audit_log",
                 "template",
+                "template_version_promotion",
                 "group",
                 "file",
                 "provisioner_daemon",
@@ -9174,6 +14353,7 @@ const docTemplate = `{
                 "deployment_stats",
                 "replicas",
                 "debug_info",
+                "platform_event",
                 "system"
             ],
             "x-enum-varnames": [
@@ -9183,6 +14363,7 @@ const docTemplate = `{
                 "ResourceWorkspaceApplicationConnect",
                 "ResourceAuditLog",
                 "ResourceTemplate",
+                "ResourceTemplateVersionPromotion",
                 "ResourceGroup",
                 "ResourceFile",
                 "ResourceProvisionerDaemon",
@@ -9198,9 +14379,58 @@ const docTemplate = `{
                 "ResourceDeploymentStats",
                 "ResourceReplicas",
                 "ResourceDebugInfo",
+                "ResourcePlatformEvent",
                 "ResourceSystem"
             ]
         },
+        "codersdk.RankRegionsRequest": {
+            "type": "object",
+            "properties": {
+                "latencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.RegionLatency"
+                    }
+                }
+            }
+        },
+        "codersdk.RankedRegion": {
+            "type": "object",
+            "properties": {
+                "client_latency_ms": {
+                    "description": "ClientLatencyMS is the latency reported by the client for the region.\nIt is omitted if the client did not report a latency for the region.",
+                    "type": "number"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "health_latency_ms": {
+                    "description": "HealthLatencyMS is the round trip time in milliseconds of the latest\nhealth check made by the primary to the region. It is zero for the\nprimary and for regions that have not been reached yet.",
+                    "type": "number"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "path_app_url": {
+                    "description": "PathAppURL is the URL to the base path for path apps. Optional\nunless wildcard_hostname is set.\nE.g. https://us.example.com",
+                    "type": "string"
+                },
+                "wildcard_hostname": {
+                    "description": "WildcardHostname is the wildcard hostname for subdomain apps.\nE.g. *.us.example.com\nE.g. *--suffix.au.example.com\nOptional. Does not need to be on the same domain as PathAppURL.",
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.RateLimitConfig": {
             "type": "object",
             "properties": {
@@ -9218,6 +14448,10 @@ const docTemplate = `{
                 "display_name": {
                     "type": "string"
                 },
+                "health_latency_ms": {
+                    "description": "HealthLatencyMS is the round trip time in milliseconds of the latest\nhealth check made by the primary to the region. It is zero for the\nprimary and for regions that have not been reached yet.",
+                    "type": "number"
+                },
                 "healthy": {
                     "type": "boolean"
                 },
@@ -9241,9 +14475,48 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.RegionLatency": {
+            "type": "object",
+            "properties": {
+                "latency_ms": {
+                    "type": "number"
+                },
+                "region_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.RegionsResponse-codersdk_RankedRegion": {
+            "type": "object",
+            "properties": {
+                "preferred_region_ids": {
+                    "description": "PreferredRegionIDs orders the healthy regions from the most to the\nleast preferred for the requesting user. Clients should connect\nthrough the first region unless the user selected another one.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "regions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.RankedRegion"
+                    }
+                }
+            }
+        },
         "codersdk.RegionsResponse-codersdk_Region": {
             "type": "object",
             "properties": {
+                "preferred_region_ids": {
+                    "description": "PreferredRegionIDs orders the healthy regions from the most to the\nleast preferred for the requesting user. Clients should connect\nthrough the first region unless the user selected another one.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
                 "regions": {
                     "type": "array",
                     "items": {
@@ -9255,6 +14528,14 @@ const docTemplate = `{
         "codersdk.RegionsResponse-codersdk_WorkspaceProxy": {
             "type": "object",
             "properties": {
+                "preferred_region_ids": {
+                    "description": "PreferredRegionIDs orders the healthy regions from the most to the\nleast preferred for the requesting user. Clients should connect\nthrough the first region unless the user selected another one.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
                 "regions": {
                     "type": "array",
                     "items": {
@@ -9275,6 +14556,11 @@ const docTemplate = `{
                     "description": "DatabaseLatency is the latency in microseconds to the database.",
                     "type": "integer"
                 },
+                "draining_at": {
+                    "description": "DrainingAt is when the replica started draining. A draining replica\ndoesn't accept new tailnet connections or provisioner jobs.",
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "error": {
                     "description": "Error is the replica error.",
                     "type": "string"
@@ -9298,6 +14584,33 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.ReplicaConnections": {
+            "type": "object",
+            "properties": {
+                "agents": {
+                    "description": "Agents and Clients are the workspace agents and clients coordinating\nthrough the replica.",
+                    "type": "integer"
+                },
+                "clients": {
+                    "type": "integer"
+                },
+                "connection_cache_entries": {
+                    "description": "ConnectionCacheEntries is the number of cached agent connections of\nagents that don't support the server tailnet.",
+                    "type": "integer"
+                },
+                "hostname": {
+                    "type": "string"
+                },
+                "replica_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "server_tailnet_agents": {
+                    "description": "ServerTailnetAgents is the number of agents the replica keeps a\nconnection to for proxying workspace apps.",
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.ResourceType": {
             "type": "string",
             "enum": [
@@ -9312,7 +14625,13 @@ const docTemplate = `{
                 "license",
                 "convert_login",
                 "workspace_proxy",
-                "organization"
+                "organization",
+                "workspace_webhook",
+                "workspace_approval",
+                "environment_variable",
+                "template_access_request",
+                "template_version_promotion",
+                "workspace_agent_auth_token"
             ],
             "x-enum-varnames": [
                 "ResourceTypeTemplate",
@@ -9326,7 +14645,13 @@ const docTemplate = `{
                 "ResourceTypeLicense",
                 "ResourceTypeConvertLogin",
                 "ResourceTypeWorkspaceProxy",
-                "ResourceTypeOrganization"
+                "ResourceTypeOrganization",
+                "ResourceTypeWorkspaceWebhook",
+                "ResourceTypeWorkspaceApproval",
+                "ResourceTypeEnvironmentVariable",
+                "ResourceTypeTemplateAccessRequest",
+                "ResourceTypeTemplateVersionPromotion",
+                "ResourceTypeWorkspaceAgentAuthToken"
             ]
         },
         "codersdk.Response": {
@@ -9336,6 +14661,14 @@ const docTemplate = `{
                     "description": "Detail is a debug message that provides further insight into why the\naction failed. This information can be technical and a regular golang\nerr.Error() text.\n- \"database: too many open connections\"\n- \"stat: too many open files\"",
                     "type": "string"
                 },
+                "feature_unavailable": {
+                    "description": "FeatureUnavailable is set when the request needs a feature the\ndeployment isn't entitled to, or hasn't enabled.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.FeatureUnavailable"
+                        }
+                    ]
+                },
                 "message": {
                     "description": "Message is an actionable message that depicts actions the request took.\nThese messages should be fully formed sentences with proper punctuation.\nExamples:\n- \"A user has been created.\"\n- \"Failed to create a user.\"",
                     "type": "string"
@@ -9349,13 +14682,78 @@ const docTemplate = `{
                 }
             }
         },
-        "codersdk.Role": {
+        "codersdk.Role": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.RuntimeExperiment": {
+            "type": "object",
+            "properties": {
+                "everyone": {
+                    "description": "Everyone enables the experiment for every user of the deployment.",
+                    "type": "boolean"
+                },
+                "experiment": {
+                    "$ref": "#/definitions/codersdk.Experiment"
+                },
+                "group_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "organization_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                }
+            }
+        },
+        "codersdk.SSHCertificate": {
+            "type": "object",
+            "properties": {
+                "certificate": {
+                    "description": "Certificate is in the authorized_keys format, ssh picks it up when it's\nstored next to the private key with the \"-cert.pub\" suffix.",
+                    "type": "string"
+                },
+                "principals": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "valid_after": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "valid_before": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.SSHCertificateAuthority": {
             "type": "object",
             "properties": {
-                "display_name": {
-                    "type": "string"
-                },
-                "name": {
+                "public_key": {
+                    "description": "PublicKey is in the authorized_keys format, so it can be used for\nTrustedUserCAKeys of sshd and @cert-authority entries in known_hosts.",
                     "type": "string"
                 }
             }
@@ -9390,6 +14788,23 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.SecretManagerConfig": {
+            "type": "object",
+            "properties": {
+                "aws_region": {
+                    "type": "string"
+                },
+                "gcp": {
+                    "type": "boolean"
+                },
+                "vault_address": {
+                    "$ref": "#/definitions/clibase.URL"
+                },
+                "vault_token": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.ServiceBannerConfig": {
             "type": "object",
             "properties": {
@@ -9478,6 +14893,23 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TailnetKeepaliveConfig": {
+            "type": "object",
+            "properties": {
+                "agent_disconnect_timeout": {
+                    "type": "integer"
+                },
+                "agent_interval": {
+                    "type": "integer"
+                },
+                "coordinator_heartbeat_interval": {
+                    "type": "integer"
+                },
+                "coordinator_missed_heartbeats": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.TelemetryConfig": {
             "type": "object",
             "properties": {
@@ -9513,6 +14945,13 @@ const docTemplate = `{
                 "allow_user_cancel_workspace_jobs": {
                     "type": "boolean"
                 },
+                "autostop_activity_sources": {
+                    "description": "AutostopActivitySources is enterprise-only. It lists the kinds of\nactivity that postpone the autostop of workspaces. When empty, any\nconnection to a workspace postpones it.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.AgentActivitySource"
+                    }
+                },
                 "build_time_stats": {
                     "$ref": "#/definitions/codersdk.TemplateBuildTimeStats"
                 },
@@ -9553,6 +14992,18 @@ const docTemplate = `{
                 "locked_ttl_ms": {
                     "type": "integer"
                 },
+                "max_build_duration_ms": {
+                    "description": "MaxBuildDurationMillis, ProvisionerMemoryLimitBytes and\nProvisionerCPULimitMillis limit the provisioner jobs of the template.\nJobs exceeding them are canceled. 0 means no limit.",
+                    "type": "integer"
+                },
+                "max_concurrent_provisioner_jobs": {
+                    "description": "MaxConcurrentProvisionerJobs limits how many provisioner jobs of the\ntemplate run at once. Further jobs wait in the queue. 0 means no limit.",
+                    "type": "integer"
+                },
+                "max_session_idle_ms": {
+                    "description": "MaxSessionIdleMillis disconnects web terminal and app sessions of the\nworkspaces of the template once they have been idle for this long.\nUsers are warned before they are disconnected. 0 means sessions are\nnever disconnected.",
+                    "type": "integer"
+                },
                 "max_ttl_ms": {
                     "description": "TODO(@dean): remove max_ttl once restart_requirement is matured",
                     "type": "integer"
@@ -9564,12 +15015,45 @@ const docTemplate = `{
                     "type": "string",
                     "format": "uuid"
                 },
+                "port_policy": {
+                    "description": "PortPolicy restricts which ports of the agents of the template's\nworkspaces can be forwarded, on top of the deployment's port policy.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.PortPolicy"
+                        }
+                    ]
+                },
                 "provisioner": {
                     "type": "string",
                     "enum": [
                         "terraform"
                     ]
                 },
+                "provisioner_cpu_limit_ms": {
+                    "type": "integer"
+                },
+                "provisioner_memory_limit_bytes": {
+                    "type": "integer"
+                },
+                "require_active_version": {
+                    "description": "RequireActiveVersion forces outdated workspaces to be started with the\nactive version once RequireActiveVersionGracePeriodMillis has passed\nsince the active version changed.",
+                    "type": "boolean"
+                },
+                "require_active_version_grace_period_ms": {
+                    "type": "integer"
+                },
+                "require_agent_binary_verification": {
+                    "description": "RequireAgentBinaryVerification stops agents from starting when their\nbinary doesn't match the checksum served by the deployment or the\nsignature of the agent binary signing certificate.",
+                    "type": "boolean"
+                },
+                "require_promotion_approval": {
+                    "description": "RequirePromotionApproval only lets new versions become active through\na promotion approved by a template approver. See\nTemplateVersionPromotion.",
+                    "type": "boolean"
+                },
+                "require_workspace_approval": {
+                    "description": "RequireWorkspaceApproval holds the first build of new workspaces until\nit is approved. See WorkspaceApproval.",
+                    "type": "boolean"
+                },
                 "restart_requirement": {
                     "description": "RestartRequirement is an enterprise feature. Its value is only used if\nyour license is entitled to use the advanced template scheduling feature.",
                     "allOf": [
@@ -9584,6 +15068,157 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateACLChange": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "new_role": {
+                    "description": "NewRole is empty if the user or group was removed.",
+                    "enum": [
+                        "admin",
+                        "settings",
+                        "push",
+                        "use",
+                        ""
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateRole"
+                        }
+                    ]
+                },
+                "old_role": {
+                    "description": "OldRole is empty if the user or group was added.",
+                    "enum": [
+                        "admin",
+                        "settings",
+                        "push",
+                        "use",
+                        ""
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateRole"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.TemplateACLDiff": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateACLChange"
+                    }
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateACLChange"
+                    }
+                }
+            }
+        },
+        "codersdk.TemplateAccessRequest": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "decided_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "decided_by": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "denied"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateAccessRequestStatus"
+                        }
+                    ]
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.TemplateAccessRequestStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "denied"
+            ],
+            "x-enum-varnames": [
+                "TemplateAccessRequestStatusPending",
+                "TemplateAccessRequestStatusApproved",
+                "TemplateAccessRequestStatusDenied"
+            ]
+        },
+        "codersdk.TemplateAgentFirstConnect": {
+            "type": "object",
+            "properties": {
+                "connected_agents": {
+                    "type": "integer",
+                    "example": 37
+                },
+                "first_connect_p50_seconds": {
+                    "description": "The percentiles are -1 when no agent connected.",
+                    "type": "number",
+                    "example": 12.4
+                },
+                "first_connect_p95_seconds": {
+                    "type": "number",
+                    "example": 48.1
+                },
+                "first_connect_p99_seconds": {
+                    "type": "number",
+                    "example": 95.7
+                },
+                "never_connected_agents": {
+                    "description": "NeverConnectedAgents counts the agents that didn't connect before their\nconnection timeout, which usually points to a broken startup script or\nimage.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "total_agents": {
+                    "type": "integer",
+                    "example": 40
+                }
+            }
+        },
         "codersdk.TemplateAppUsage": {
             "type": "object",
             "properties": {
@@ -9630,6 +15265,147 @@ const docTemplate = `{
                 "TemplateAppsTypeApp"
             ]
         },
+        "codersdk.TemplateBuildFailureInsight": {
+            "type": "object",
+            "properties": {
+                "builds": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "error_code": {
+                    "description": "ErrorCode is empty for failures that the provisioner didn't classify.",
+                    "enum": [
+                        "MISSING_TEMPLATE_PARAMETER",
+                        "REQUIRED_TEMPLATE_VARIABLES",
+                        "BUILD_TIMEOUT",
+                        "RESOURCE_LIMIT_EXCEEDED"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.JobErrorCode"
+                        }
+                    ]
+                },
+                "rate": {
+                    "description": "Rate is the fraction of all builds of the version that failed with the\nerror code.",
+                    "type": "number",
+                    "example": 0.033
+                }
+            }
+        },
+        "codersdk.TemplateBuildInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "versions": {
+                    "description": "Versions are ordered from the newest to the oldest version. Versions\nwithout completed builds are omitted.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionBuildInsights"
+                    }
+                }
+            }
+        },
+        "codersdk.TemplateBuildSLO": {
+            "type": "object",
+            "properties": {
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "windows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateBuildSLOWindow"
+                    }
+                }
+            }
+        },
+        "codersdk.TemplateBuildSLOInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "duration_objective_seconds": {
+                    "type": "number",
+                    "example": 600
+                },
+                "generated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "success_objective": {
+                    "type": "number",
+                    "example": 0.99
+                },
+                "templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateBuildSLO"
+                    }
+                }
+            }
+        },
+        "codersdk.TemplateBuildSLOWindow": {
+            "type": "object",
+            "properties": {
+                "build_duration_p50_seconds": {
+                    "description": "BuildDurationP50Seconds and BuildDurationP95Seconds are -1 when there\nwere no builds in the window.",
+                    "type": "number",
+                    "example": 42.5
+                },
+                "build_duration_p95_seconds": {
+                    "type": "number",
+                    "example": 310.2
+                },
+                "builds_within_duration_objective": {
+                    "type": "integer",
+                    "example": 110
+                },
+                "duration_burn_rate": {
+                    "type": "number",
+                    "example": 8.33
+                },
+                "duration_objective_compliance": {
+                    "type": "number",
+                    "example": 0.916
+                },
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "failed_builds": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "success_burn_rate": {
+                    "type": "number",
+                    "example": 1.67
+                },
+                "success_rate": {
+                    "description": "SuccessRate and DurationObjectiveCompliance are 1 when there were no\nbuilds in the window.",
+                    "type": "number",
+                    "example": 0.983
+                },
+                "successful_builds": {
+                    "type": "integer",
+                    "example": 118
+                },
+                "total_builds": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "window_seconds": {
+                    "type": "integer",
+                    "example": 3600
+                }
+            }
+        },
         "codersdk.TemplateBuildTimeStats": {
             "type": "object",
             "additionalProperties": {
@@ -9818,13 +15594,17 @@ const docTemplate = `{
         "codersdk.TemplateRole": {
             "type": "string",
             "enum": [
-                "admin",
                 "use",
+                "push",
+                "settings",
+                "admin",
                 ""
             ],
             "x-enum-varnames": [
-                "TemplateRoleAdmin",
                 "TemplateRoleUse",
+                "TemplateRolePush",
+                "TemplateRoleSettings",
+                "TemplateRoleAdmin",
                 "TemplateRoleDeleted"
             ]
         },
@@ -9870,6 +15650,8 @@ const docTemplate = `{
                 "role": {
                     "enum": [
                         "admin",
+                        "settings",
+                        "push",
                         "use"
                     ],
                     "allOf": [
@@ -9949,6 +15731,129 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateVersionBuildComparison": {
+            "type": "object",
+            "properties": {
+                "duration_p50_change_seconds": {
+                    "description": "DurationP50ChangeSeconds and DurationP95ChangeSeconds are 0 when either\nversion has no successful builds.",
+                    "type": "number",
+                    "example": -3.5
+                },
+                "duration_p95_change_seconds": {
+                    "type": "number",
+                    "example": 12.1
+                },
+                "failure_rate_change": {
+                    "type": "number",
+                    "example": -0.02
+                },
+                "previous_template_version_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "previous_template_version_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.TemplateVersionBuildInsights": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "comparison": {
+                    "description": "Comparison is omitted for the oldest version with completed builds.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateVersionBuildComparison"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "duration_p50_seconds": {
+                    "description": "DurationP50Seconds and DurationP95Seconds are computed over the\nsuccessful builds, they are -1 when there were none.",
+                    "type": "number",
+                    "example": 42.5
+                },
+                "duration_p95_seconds": {
+                    "type": "number",
+                    "example": 310.2
+                },
+                "failed_builds": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "failure_rate": {
+                    "type": "number",
+                    "example": 0.05
+                },
+                "failures": {
+                    "description": "Failures are ordered from the most to the least frequent error code.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateBuildFailureInsight"
+                    }
+                },
+                "template_version_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "template_version_name": {
+                    "type": "string"
+                },
+                "total_builds": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.TemplateVersionDiagnostic": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "severity": {
+                    "enum": [
+                        "error",
+                        "warning"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateVersionDiagnosticSeverity"
+                        }
+                    ]
+                },
+                "summary": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.TemplateVersionDiagnosticSeverity": {
+            "type": "string",
+            "enum": [
+                "error",
+                "warning"
+            ],
+            "x-enum-varnames": [
+                "TemplateVersionDiagnosticSeverityError",
+                "TemplateVersionDiagnosticSeverityWarning"
+            ]
+        },
         "codersdk.TemplateVersionGitAuth": {
             "type": "object",
             "properties": {
@@ -9972,6 +15877,13 @@ const docTemplate = `{
                 "default_value": {
                     "type": "string"
                 },
+                "dependencies": {
+                    "description": "Dependencies make the parameter applicable only when one of them\nmatches. A parameter without dependencies is always applicable.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionParameterDependency"
+                    }
+                },
                 "description": {
                     "type": "string"
                 },
@@ -9981,6 +15893,10 @@ const docTemplate = `{
                 "display_name": {
                     "type": "string"
                 },
+                "dynamic_options": {
+                    "description": "DynamicOptions is true if the options are fetched from a\ntemplate-defined endpoint whenever the parameter is resolved.",
+                    "type": "boolean"
+                },
                 "ephemeral": {
                     "type": "boolean"
                 },
@@ -10036,6 +15952,29 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateVersionParameterDependency": {
+            "type": "object",
+            "properties": {
+                "options": {
+                    "description": "Options restricts the option values that can be chosen while the\ndependency matches. An empty list allows all options.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "parameter": {
+                    "description": "Parameter is the name of the parameter this one depends on.",
+                    "type": "string"
+                },
+                "values": {
+                    "description": "Values lists the values of Parameter for which the dependency matches.\nAn empty list matches any value.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "codersdk.TemplateVersionParameterOption": {
             "type": "object",
             "properties": {
@@ -10053,6 +15992,149 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TemplateVersionPromotion": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "decided_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "decided_by": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateVersionPromotionStatus"
+                        }
+                    ]
+                },
+                "template_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "template_version_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.TemplateVersionPromotionStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "TemplateVersionPromotionStatusPending",
+                "TemplateVersionPromotionStatusApproved",
+                "TemplateVersionPromotionStatusRejected"
+            ]
+        },
+        "codersdk.TemplateVersionStateAnalysis": {
+            "type": "object",
+            "properties": {
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionStateResource"
+                    }
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "codersdk.TemplateVersionStateAnalysisRequest": {
+            "type": "object",
+            "required": [
+                "state"
+            ],
+            "properties": {
+                "state": {
+                    "description": "State is the content of a state file as written by \"terraform state\npull\".",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "codersdk.TemplateVersionStateResource": {
+            "type": "object",
+            "properties": {
+                "agents": {
+                    "description": "Agents are the names of the agents the template runs on the resource.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "instances": {
+                    "description": "Instances is the number of instances of the resource in the state.",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "matched",
+                        "missing",
+                        "unmanaged"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.TemplateVersionStateResourceStatus"
+                        }
+                    ]
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.TemplateVersionStateResourceStatus": {
+            "type": "string",
+            "enum": [
+                "matched",
+                "missing",
+                "unmanaged"
+            ],
+            "x-enum-varnames": [
+                "TemplateVersionStateResourceMatched",
+                "TemplateVersionStateResourceMissing",
+                "TemplateVersionStateResourceUnmanaged"
+            ]
+        },
         "codersdk.TemplateVersionVariable": {
             "type": "object",
             "properties": {
@@ -10128,6 +16210,16 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.TriggerWorkspaceWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "workspace_build_id": {
+                    "description": "WorkspaceBuildID is the build started by start, stop and update\nwebhooks.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.UpdateActiveTemplateVersion": {
             "type": "object",
             "required": [
@@ -10168,6 +16260,36 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateGroupDefaultTemplatesRequest": {
+            "type": "object",
+            "properties": {
+                "default_templates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.GroupDefaultTemplate"
+                    }
+                }
+            }
+        },
+        "codersdk.UpdateManagedEnvironmentVariableRequest": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "boolean"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.UpdateOrganizationRequest": {
+            "type": "object",
+            "properties": {
+                "max_concurrent_provisioner_jobs": {
+                    "type": "integer"
+                }
+            }
+        },
         "codersdk.UpdateRoles": {
             "type": "object",
             "properties": {
@@ -10179,6 +16301,17 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateRuntimeExperimentsRequest": {
+            "type": "object",
+            "properties": {
+                "runtime": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.RuntimeExperiment"
+                    }
+                }
+            }
+        },
         "codersdk.UpdateTemplateACL": {
             "type": "object",
             "properties": {
@@ -10206,6 +16339,27 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateTemplateACLResponse": {
+            "type": "object",
+            "properties": {
+                "diff": {
+                    "$ref": "#/definitions/codersdk.TemplateACLDiff"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.UpdateUserPTYSessionOverrideRequest": {
+            "type": "object",
+            "properties": {
+                "max_pty_sessions": {
+                    "description": "MaxPTYSessions is the maximum number of concurrent web terminal\nsessions of the user. 0 means the user has no limit.",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
         "codersdk.UpdateUserPasswordRequest": {
             "type": "object",
             "required": [
@@ -10243,6 +16397,25 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateWorkspaceAutomaticUpdatesRequest": {
+            "type": "object",
+            "required": [
+                "automatic_updates"
+            ],
+            "properties": {
+                "automatic_updates": {
+                    "enum": [
+                        "always",
+                        "never"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AutomaticUpdates"
+                        }
+                    ]
+                }
+            }
+        },
         "codersdk.UpdateWorkspaceAutostartRequest": {
             "type": "object",
             "properties": {
@@ -10259,6 +16432,34 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UpdateWorkspaceProxyACL": {
+            "type": "object",
+            "properties": {
+                "group_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "organization_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                }
+            }
+        },
+        "codersdk.UpdateWorkspaceQuotaOverrideRequest": {
+            "type": "object",
+            "properties": {
+                "quota_allowance": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
         "codersdk.UpdateWorkspaceRequest": {
             "type": "object",
             "properties": {
@@ -10345,6 +16546,124 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.UserBandwidth": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string",
+                    "format": "uri"
+                },
+                "connection_type": {
+                    "type": "string",
+                    "enum": [
+                        "ssh",
+                        "reconnecting_pty",
+                        "speedtest",
+                        "port_forward"
+                    ]
+                },
+                "peers": {
+                    "description": "Peers is the number of distinct tailnet peers the traffic was\nexchanged with.",
+                    "type": "integer"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "rx_bytes": {
+                    "type": "integer"
+                },
+                "template_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "tx_bytes": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.UserBandwidthInsightsReport": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "start_time": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "template_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.UserBandwidth"
+                    }
+                }
+            }
+        },
+        "codersdk.UserBandwidthInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "report": {
+                    "$ref": "#/definitions/codersdk.UserBandwidthInsightsReport"
+                }
+            }
+        },
+        "codersdk.UserConnectionLogEntry": {
+            "type": "object",
+            "properties": {
+                "agent_name": {
+                    "type": "string"
+                },
+                "client_type": {
+                    "$ref": "#/definitions/codersdk.WorkspaceAgentClientType"
+                },
+                "client_version": {
+                    "type": "string"
+                },
+                "connected_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "disconnected_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_name": {
+                    "type": "string"
+                },
+                "workspace_owner_username": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.UserLatency": {
             "type": "object",
             "properties": {
@@ -10459,6 +16778,32 @@ const docTemplate = `{
                 "UserStatusSuspended"
             ]
         },
+        "codersdk.ValidateTemplateVersionRequest": {
+            "type": "object",
+            "required": [
+                "file_id"
+            ],
+            "properties": {
+                "file_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.ValidateTemplateVersionResponse": {
+            "type": "object",
+            "properties": {
+                "diagnostics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.TemplateVersionDiagnostic"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
         "codersdk.ValidationError": {
             "type": "object",
             "required": [
@@ -10499,6 +16844,18 @@ const docTemplate = `{
         "codersdk.Workspace": {
             "type": "object",
             "properties": {
+                "automatic_updates": {
+                    "description": "AutomaticUpdates determines if the workspace is started with the\nactive template version when it is outdated.",
+                    "enum": [
+                        "always",
+                        "never"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AutomaticUpdates"
+                        }
+                    ]
+                },
                 "autostart_schedule": {
                     "type": "string"
                 },
@@ -10511,6 +16868,13 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
+                "external_metadata": {
+                    "description": "ExternalMetadata is the metadata attached to the workspace by external\nintegrations that the caller is allowed to see, sorted by namespace.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceExternalMetadata"
+                    }
+                },
                 "health": {
                     "description": "Health shows the health of the workspace and information about\nwhat is causing an unhealthy status.",
                     "allOf": [
@@ -10552,6 +16916,13 @@ const docTemplate = `{
                 "owner_name": {
                     "type": "string"
                 },
+                "previous_names": {
+                    "description": "PreviousNames are the names the workspace had before it was renamed,\nmost recent first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspacePreviousName"
+                    }
+                },
                 "template_allow_user_cancel_workspace_jobs": {
                     "type": "boolean"
                 },
@@ -10571,6 +16942,11 @@ const docTemplate = `{
                 "ttl_ms": {
                     "type": "integer"
                 },
+                "update_deadline": {
+                    "description": "UpdateDeadline is set when the workspace is outdated and its template\nrequires the active version. From this time on, the workspace can only\nbe started with the active version of the template.",
+                    "type": "string",
+                    "format": "date-time"
+                },
                 "updated_at": {
                     "type": "string",
                     "format": "date-time"
@@ -10589,6 +16965,10 @@ const docTemplate = `{
                 "architecture": {
                     "type": "string"
                 },
+                "clock_offset_ms": {
+                    "description": "ClockOffsetMS is the offset of the agent clock from the clock of\ncoderd in milliseconds, as last reported by the agent. Positive values\nmean the agent clock is ahead. Agents with a large offset are\nunhealthy.",
+                    "type": "integer"
+                },
                 "connection_timeout_seconds": {
                     "type": "integer"
                 },
@@ -10603,6 +16983,10 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
+                "display_order": {
+                    "description": "DisplayOrder is the position of the agent among the agents of its\nresource, as defined by the template. Agents are sorted by it.",
+                    "type": "integer"
+                },
                 "environment_variables": {
                     "type": "object",
                     "additionalProperties": {
@@ -10624,6 +17008,13 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "host_facts": {
+                    "description": "HostFacts are reported by the agent on startup. See the\nAgentHostFact constants for the keys reported on Windows.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "id": {
                     "type": "string",
                     "format": "uuid"
@@ -10710,6 +17101,53 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceAgentClientConnection": {
+            "type": "object",
+            "properties": {
+                "client_type": {
+                    "$ref": "#/definitions/codersdk.WorkspaceAgentClientType"
+                },
+                "client_version": {
+                    "type": "string"
+                },
+                "connected_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "disconnected_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "user_id": {
+                    "description": "UserID is nil if the client is a workspace proxy.",
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentClientType": {
+            "type": "string",
+            "enum": [
+                "ssh",
+                "vscode",
+                "jetbrains",
+                "port_forward",
+                "cli",
+                "unknown"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceAgentClientTypeSSH",
+                "WorkspaceAgentClientTypeVSCode",
+                "WorkspaceAgentClientTypeJetBrains",
+                "WorkspaceAgentClientTypePortForward",
+                "WorkspaceAgentClientTypeCLI",
+                "WorkspaceAgentClientTypeUnknown"
+            ]
+        },
         "codersdk.WorkspaceAgentConnectionInfo": {
             "type": "object",
             "properties": {
@@ -10835,14 +17273,33 @@ const docTemplate = `{
                 "interval": {
                     "type": "integer"
                 },
-                "key": {
+                "key": {
+                    "type": "string"
+                },
+                "script": {
+                    "type": "string"
+                },
+                "timeout": {
+                    "type": "integer"
+                }
+            }
+        },
+        "codersdk.WorkspaceAgentMetadataResult": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "description": "Age is the number of seconds since the metadata was collected.\nIt is provided in addition to CollectedAt to protect against clock skew.",
+                    "type": "integer"
+                },
+                "collected_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "error": {
                     "type": "string"
                 },
-                "script": {
+                "value": {
                     "type": "string"
-                },
-                "timeout": {
-                    "type": "integer"
                 }
             }
         },
@@ -10882,6 +17339,10 @@ const docTemplate = `{
                     "description": "DisplayName is a friendly name for the app.",
                     "type": "string"
                 },
+                "display_order": {
+                    "description": "DisplayOrder is the position of the app among the apps of its agent,\nas defined by the template. Apps are sorted by it.",
+                    "type": "integer"
+                },
                 "external": {
                     "description": "External specifies whether the URL should be opened externally on\nthe client or not.",
                     "type": "boolean"
@@ -10897,6 +17358,10 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "hidden": {
+                    "description": "Hidden apps should not be displayed by clients.",
+                    "type": "boolean"
+                },
                 "icon": {
                     "description": "Icon is a relative path or external URL that specifies\nan icon to be displayed in the dashboard.",
                     "type": "string"
@@ -10959,6 +17424,78 @@ const docTemplate = `{
                 "WorkspaceAppSharingLevelPublic"
             ]
         },
+        "codersdk.WorkspaceApproval": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "decided_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "decided_by": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "requested_by": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "status": {
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceApprovalStatus"
+                        }
+                    ]
+                },
+                "workspace_build_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WorkspaceApprovalStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceApprovalStatusPending",
+                "WorkspaceApprovalStatusApproved",
+                "WorkspaceApprovalStatusRejected"
+            ]
+        },
+        "codersdk.WorkspaceApprovalWebhookConfig": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "$ref": "#/definitions/clibase.URL"
+                }
+            }
+        },
         "codersdk.WorkspaceBuild": {
             "type": "object",
             "properties": {
@@ -10987,6 +17524,10 @@ const docTemplate = `{
                 "initiator_name": {
                     "type": "string"
                 },
+                "initiator_token_name": {
+                    "description": "InitiatorTokenName is the name of the API token the initiator used to\nstart the build, if any.",
+                    "type": "string"
+                },
                 "job": {
                     "$ref": "#/definitions/codersdk.ProvisionerJob"
                 },
@@ -10998,7 +17539,12 @@ const docTemplate = `{
                     "enum": [
                         "initiator",
                         "autostart",
-                        "autostop"
+                        "autostop",
+                        "autolock",
+                        "failedstop",
+                        "autodelete",
+                        "bulk",
+                        "webhook"
                     ],
                     "allOf": [
                         {
@@ -11070,6 +17616,56 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceBuildConflictPolicy": {
+            "type": "string",
+            "enum": [
+                "fail",
+                "queue",
+                "supersede"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceBuildConflictPolicyFail",
+                "WorkspaceBuildConflictPolicyQueue",
+                "WorkspaceBuildConflictPolicySupersede"
+            ]
+        },
+        "codersdk.WorkspaceBuildHook": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "completed_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "exit_code": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phase": {
+                    "enum": [
+                        "pre",
+                        "post",
+                        "clone"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.BuildHookPhase"
+                        }
+                    ]
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
         "codersdk.WorkspaceBuildParameter": {
             "type": "object",
             "properties": {
@@ -11081,6 +17677,105 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceBuildTimeline": {
+            "type": "object",
+            "properties": {
+                "hooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildHook"
+                    }
+                },
+                "stages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceBuildTimelineStage"
+                    }
+                }
+            }
+        },
+        "codersdk.WorkspaceBuildTimelineStage": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string",
+                    "format": "date-time"
+                }
+            }
+        },
+        "codersdk.WorkspaceConnection": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "agent_name": {
+                    "type": "string"
+                },
+                "client_type": {
+                    "description": "ClientType, ClientVersion and UserID are recorded when clients other\nthan servers start coordinating with the agent.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceAgentClientType"
+                        }
+                    ]
+                },
+                "client_version": {
+                    "type": "string"
+                },
+                "derp_latency_ms": {
+                    "description": "DERPLatencyMS is the round trip time from the client to its preferred\nDERP region, or -1 if the client didn't report it.",
+                    "type": "number"
+                },
+                "derp_region_id": {
+                    "description": "DERPRegionID is the preferred DERP region of the client, which relays\nits traffic when the connection isn't direct.",
+                    "type": "integer"
+                },
+                "derp_region_name": {
+                    "type": "string"
+                },
+                "direct": {
+                    "description": "Direct is whether both the client and the agent advertise endpoints,\nso they can connect peer-to-peer. Otherwise, traffic is relayed through\nDERP.",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "ip": {
+                    "description": "IP is the tailnet IP address of the client.",
+                    "type": "string"
+                },
+                "rx_bytes": {
+                    "description": "RxBytes and TxBytes are the bytes received and sent by the agent on\nconnections from the client in the last hour, as reported by the agent.",
+                    "type": "integer"
+                },
+                "server": {
+                    "description": "Server is whether the client is coderd or a workspace proxy, which\nconnect on behalf of users for workspace apps and the web terminal.",
+                    "type": "boolean"
+                },
+                "tx_bytes": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "UpdatedAt is when the client last sent its node to the coordinator.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "user_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
         "codersdk.WorkspaceConnectionLatencyMS": {
             "type": "object",
             "properties": {
@@ -11121,6 +17816,43 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceExternalMetadata": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "visibility": {
+                    "enum": [
+                        "public",
+                        "private"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceExternalMetadataVisibility"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.WorkspaceExternalMetadataVisibility": {
+            "type": "string",
+            "enum": [
+                "public",
+                "private"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceExternalMetadataVisibilityPublic",
+                "WorkspaceExternalMetadataVisibilityPrivate"
+            ]
+        },
         "codersdk.WorkspaceHealth": {
             "type": "object",
             "properties": {
@@ -11132,10 +17864,85 @@ const docTemplate = `{
                         "format": "uuid"
                     }
                 },
-                "healthy": {
-                    "description": "Healthy is true if the workspace is healthy.",
-                    "type": "boolean",
-                    "example": false
+                "healthy": {
+                    "description": "Healthy is true if the workspace is healthy.",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "codersdk.WorkspaceHookConfig": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceHookEvent"
+                    }
+                },
+                "failure_policy": {
+                    "description": "FailurePolicy decides whether builds fail when a pre hook fails.\nDefaults to \"fail\". Post hooks run after the build completed, so their\nfailures are always logged and ignored.",
+                    "enum": [
+                        "fail",
+                        "ignore"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceHookFailurePolicy"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "timeout": {
+                    "description": "Timeout is how long a call may take. Defaults to 10 seconds.",
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "codersdk.WorkspaceHookEvent": {
+            "type": "string",
+            "enum": [
+                "pre_start",
+                "post_start",
+                "pre_stop",
+                "post_stop"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceHookEventPreStart",
+                "WorkspaceHookEventPostStart",
+                "WorkspaceHookEventPreStop",
+                "WorkspaceHookEventPostStop"
+            ]
+        },
+        "codersdk.WorkspaceHookFailurePolicy": {
+            "type": "string",
+            "enum": [
+                "fail",
+                "ignore"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceHookFailurePolicyFail",
+                "WorkspaceHookFailurePolicyIgnore"
+            ]
+        },
+        "codersdk.WorkspacePreviousName": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "redirect_expires_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "renamed_at": {
+                    "type": "string",
+                    "format": "date-time"
                 }
             }
         },
@@ -11158,6 +17965,17 @@ const docTemplate = `{
                 "display_name": {
                     "type": "string"
                 },
+                "group_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
+                "health_latency_ms": {
+                    "description": "HealthLatencyMS is the round trip time in milliseconds of the latest\nhealth check made by the primary to the region. It is zero for the\nprimary and for regions that have not been reached yet.",
+                    "type": "number"
+                },
                 "healthy": {
                     "type": "boolean"
                 },
@@ -11171,6 +17989,14 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "organization_ids": {
+                    "description": "OrganizationIDs and GroupIDs limit the proxy to the members of these\norganizations and groups. The proxy is available to everyone when both\nare empty.",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "format": "uuid"
+                    }
+                },
                 "path_app_url": {
                     "description": "PathAppURL is the URL to the base path for path apps. Optional\nunless wildcard_hostname is set.\nE.g. https://us.example.com",
                     "type": "string"
@@ -11200,6 +18026,18 @@ const docTemplate = `{
                     "type": "string",
                     "format": "date-time"
                 },
+                "derp_error": {
+                    "description": "DERPError explains why the DERP server of the proxy is not healthy.",
+                    "type": "string"
+                },
+                "derp_status": {
+                    "description": "DERPStatus is the health of the DERP server of the proxy, which is\nchecked separately from its HTTP endpoints. Proxies are only added to\nthe DERP map while their DERP server is healthy. It is empty for\nproxies without DERP and proxies that could not be reached.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.ProxyHealthStatus"
+                        }
+                    ]
+                },
                 "report": {
                     "description": "Report provides more information about the health of the workspace proxy.",
                     "allOf": [
@@ -11210,6 +18048,10 @@ const docTemplate = `{
                 },
                 "status": {
                     "$ref": "#/definitions/codersdk.ProxyHealthStatus"
+                },
+                "version": {
+                    "description": "Version is the Coder version the proxy reported in its last health\ncheck. It is empty if the proxy could not be reached.",
+                    "type": "string"
                 }
             }
         },
@@ -11224,6 +18066,92 @@ const docTemplate = `{
                 }
             }
         },
+        "codersdk.WorkspaceQuotaBreakdown": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "type": "integer"
+                },
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceQuotaBudget"
+                    }
+                },
+                "credits_consumed": {
+                    "type": "integer"
+                },
+                "workspaces": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/codersdk.WorkspaceQuotaConsumer"
+                    }
+                }
+            }
+        },
+        "codersdk.WorkspaceQuotaBudget": {
+            "type": "object",
+            "properties": {
+                "allowance": {
+                    "type": "integer"
+                },
+                "applied": {
+                    "description": "Applied is true if the budget counts towards the user's quota.",
+                    "type": "boolean"
+                },
+                "group_id": {
+                    "description": "GroupID is the group the budget comes from. It's unset for user\noverrides.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "group_name": {
+                    "type": "string"
+                },
+                "source": {
+                    "enum": [
+                        "organization_default",
+                        "group",
+                        "group_override",
+                        "user_override"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceQuotaBudgetSource"
+                        }
+                    ]
+                }
+            }
+        },
+        "codersdk.WorkspaceQuotaBudgetSource": {
+            "type": "string",
+            "enum": [
+                "organization_default",
+                "group",
+                "group_override",
+                "user_override"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceQuotaBudgetSourceOrganizationDefault",
+                "WorkspaceQuotaBudgetSourceGroup",
+                "WorkspaceQuotaBudgetSourceGroupOverride",
+                "WorkspaceQuotaBudgetSourceUserOverride"
+            ]
+        },
+        "codersdk.WorkspaceQuotaConsumer": {
+            "type": "object",
+            "properties": {
+                "daily_cost": {
+                    "type": "integer"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "workspace_name": {
+                    "type": "string"
+                }
+            }
+        },
         "codersdk.WorkspaceResource": {
             "type": "object",
             "properties": {
@@ -11240,6 +18168,10 @@ const docTemplate = `{
                 "daily_cost": {
                     "type": "integer"
                 },
+                "display_order": {
+                    "description": "DisplayOrder is the position of the resource among the resources of\nthe build, as defined by the template. Resources are sorted by it.",
+                    "type": "integer"
+                },
                 "hide": {
                     "type": "boolean"
                 },
@@ -11334,6 +18266,72 @@ const docTemplate = `{
                 "WorkspaceTransitionDelete"
             ]
         },
+        "codersdk.WorkspaceWebhook": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "enum": [
+                        "start",
+                        "stop",
+                        "update",
+                        "exec"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.WorkspaceWebhookAction"
+                        }
+                    ]
+                },
+                "agent_name": {
+                    "type": "string"
+                },
+                "command": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "created_by": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "id": {
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "last_triggered_at": {
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL is where trigger requests are sent.",
+                    "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string",
+                    "format": "uuid"
+                }
+            }
+        },
+        "codersdk.WorkspaceWebhookAction": {
+            "type": "string",
+            "enum": [
+                "start",
+                "stop",
+                "update",
+                "exec"
+            ],
+            "x-enum-varnames": [
+                "WorkspaceWebhookActionStart",
+                "WorkspaceWebhookActionStop",
+                "WorkspaceWebhookActionUpdate",
+                "WorkspaceWebhookActionExec"
+            ]
+        },
         "codersdk.WorkspacesResponse": {
             "type": "object",
             "properties": {
@@ -11384,6 +18382,64 @@ const docTemplate = `{
                 }
             }
         },
+        "healthcheck.CoordinatorReport": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "healthy": {
+                    "type": "boolean"
+                },
+                "high_availability": {
+                    "type": "boolean"
+                },
+                "last_failover_at": {
+                    "description": "LastFailoverAt is the last time the replica fell back to the in-memory\ncoordinator.",
+                    "type": "string",
+                    "format": "date-time"
+                },
+                "last_failover_error": {
+                    "type": "string"
+                },
+                "pubsub_latency": {
+                    "type": "string"
+                },
+                "pubsub_latency_ms": {
+                    "type": "integer"
+                },
+                "replica_id": {
+                    "description": "ReplicaID is the replica that generated the report.",
+                    "type": "string",
+                    "format": "uuid"
+                },
+                "type": {
+                    "enum": [
+                        "memory",
+                        "ha",
+                        "pg"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/healthcheck.CoordinatorType"
+                        }
+                    ]
+                }
+            }
+        },
+        "healthcheck.CoordinatorType": {
+            "type": "string",
+            "enum": [
+                "memory",
+                "ha",
+                "pg"
+            ],
+            "x-enum-varnames": [
+                "CoordinatorTypeMemory",
+                "CoordinatorTypeHA",
+                "CoordinatorTypePG"
+            ]
+        },
         "healthcheck.DERPNodeReport": {
             "type": "object",
             "properties": {
@@ -11527,6 +18583,9 @@ const docTemplate = `{
                     "description": "The Coder version of the server that the report was generated on.",
                     "type": "string"
                 },
+                "coordinator": {
+                    "$ref": "#/definitions/healthcheck.CoordinatorReport"
+                },
                 "database": {
                     "$ref": "#/definitions/healthcheck.DatabaseReport"
                 },
@@ -11800,6 +18859,39 @@ const docTemplate = `{
         "url.Userinfo": {
             "type": "object"
         },
+        "workspaceapps.AccessEvent": {
+            "type": "object",
+            "properties": {
+                "access_method": {
+                    "$ref": "#/definitions/workspaceapps.AccessMethod"
+                },
+                "agent_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID uniquely identifies the event. It's used as the audit log ID so that\nevents delivered more than once are only recorded once.",
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "slug_or_port": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string"
+                }
+            }
+        },
         "workspaceapps.AccessMethod": {
             "type": "string",
             "enum": [
@@ -11929,6 +19021,27 @@ const docTemplate = `{
                 }
             }
         },
+        "wsproxysdk.ProxyConfig": {
+            "type": "object",
+            "properties": {
+                "appearance": {
+                    "description": "Appearance is the deployment's appearance config, which proxies serve\nto clients loading the dashboard through them.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/codersdk.AppearanceConfig"
+                        }
+                    ]
+                },
+                "disable_path_apps": {
+                    "description": "DisablePathApps is true if path-based apps are disabled on the primary.\nProxies refuse path-based apps if either they or the primary disable\nthem.",
+                    "type": "boolean"
+                },
+                "session_duration": {
+                    "description": "SessionDuration is the lifetime of session tokens issued by the\nprimary. Proxies expire app session cookies after it.",
+                    "type": "integer"
+                }
+            }
+        },
         "wsproxysdk.RegisterWorkspaceProxyRequest": {
             "type": "object",
             "properties": {
@@ -11976,12 +19089,24 @@ const docTemplate = `{
                 "app_security_key": {
                     "type": "string"
                 },
+                "config": {
+                    "description": "Config is the primary's current configuration for proxies. Changes are\nalso pushed to proxies that watch it with WatchWorkspaceProxyConfig.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/wsproxysdk.ProxyConfig"
+                        }
+                    ]
+                },
                 "derp_mesh_key": {
                     "type": "string"
                 },
                 "derp_region_id": {
                     "type": "integer"
                 },
+                "proxy_id": {
+                    "description": "ProxyID is the ID of the registered proxy. Signed app tokens issued to\nthe proxy use it as their audience.",
+                    "type": "string"
+                },
                 "sibling_replicas": {
                     "description": "SiblingReplicas is a list of all other replicas of the proxy that have\nnot timed out.",
                     "type": "array",
@@ -11991,6 +19116,17 @@ const docTemplate = `{
                 }
             }
         },
+        "wsproxysdk.ReportAccessEventsRequest": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/workspaceapps.AccessEvent"
+                    }
+                }
+            }
+        },
         "wsproxysdk.ReportAppStatsRequest": {
             "type": "object",
             "properties": {
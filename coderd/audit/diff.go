@@ -18,7 +18,13 @@ type Auditable interface {
 		database.AuditableGroup |
 		database.License |
 		database.WorkspaceProxy |
-		database.AuditOAuthConvertState
+		database.AuditOAuthConvertState |
+		database.WorkspaceWebhook |
+		database.WorkspaceApproval |
+		database.ManagedEnvironmentVariable |
+		database.TemplateAccessRequest |
+		database.TemplateVersionPromotion |
+		database.WorkspaceAgentAuthTokenRotation
 }
 
 // Map is a map of changed fields in an audited resource. It maps field names to
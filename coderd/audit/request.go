@@ -86,6 +86,18 @@ func ResourceTarget[T Auditable](tgt T) string {
 		return typed.Name
 	case database.AuditOAuthConvertState:
 		return string(typed.ToLoginType)
+	case database.WorkspaceWebhook:
+		return typed.Name
+	case database.WorkspaceApproval:
+		return typed.ID.String()
+	case database.ManagedEnvironmentVariable:
+		return typed.Name
+	case database.TemplateAccessRequest:
+		return typed.ID.String()
+	case database.TemplateVersionPromotion:
+		return typed.ID.String()
+	case database.WorkspaceAgentAuthTokenRotation:
+		return typed.AgentID.String()
 	default:
 		panic(fmt.Sprintf("unknown resource %T", tgt))
 	}
@@ -116,6 +128,18 @@ func ResourceID[T Auditable](tgt T) uuid.UUID {
 	case database.AuditOAuthConvertState:
 		// The merge state is for the given user
 		return typed.UserID
+	case database.WorkspaceWebhook:
+		return typed.ID
+	case database.WorkspaceApproval:
+		return typed.ID
+	case database.ManagedEnvironmentVariable:
+		return typed.ID
+	case database.TemplateAccessRequest:
+		return typed.ID
+	case database.TemplateVersionPromotion:
+		return typed.ID
+	case database.WorkspaceAgentAuthTokenRotation:
+		return typed.ID
 	default:
 		panic(fmt.Sprintf("unknown resource %T", tgt))
 	}
@@ -145,6 +169,18 @@ func ResourceType[T Auditable](tgt T) database.ResourceType {
 		return database.ResourceTypeWorkspaceProxy
 	case database.AuditOAuthConvertState:
 		return database.ResourceTypeConvertLogin
+	case database.WorkspaceWebhook:
+		return database.ResourceTypeWorkspaceWebhook
+	case database.WorkspaceApproval:
+		return database.ResourceTypeWorkspaceApproval
+	case database.ManagedEnvironmentVariable:
+		return database.ResourceTypeEnvironmentVariable
+	case database.TemplateAccessRequest:
+		return database.ResourceTypeTemplateAccessRequest
+	case database.TemplateVersionPromotion:
+		return database.ResourceTypeTemplateVersionPromotion
+	case database.WorkspaceAgentAuthTokenRotation:
+		return database.ResourceTypeWorkspaceAgentAuthToken
 	default:
 		panic(fmt.Sprintf("unknown resource %T", typed))
 	}
@@ -1,13 +1,20 @@
 package coderd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -105,6 +112,15 @@ func (h *HTTPAuthorizer) AuthorizeSQLFilter(r *http.Request, action rbac.Action,
 	return prepared, nil
 }
 
+const (
+	// maxAuthorizationChecks is the maximum number of checks in a single
+	// authorization request.
+	maxAuthorizationChecks = 1000
+	// maxAuthorizationFetches is the maximum number of distinct objects an
+	// authorization request can reference by "resource_id".
+	maxAuthorizationFetches = 100
+)
+
 // checkAuthorization returns if the current API key can use the given
 // permissions, factoring in the current user's roles and the API key scopes.
 //
@@ -134,37 +150,86 @@ func (api *API) checkAuthorization(rw http.ResponseWriter, r *http.Request) {
 		slog.F("scope", auth.Actor.SafeScopeName()),
 	)
 
-	response := make(codersdk.AuthorizationResponse)
-	// Prevent using too many resources by ID. This prevents database abuse
-	// from this endpoint. This also prevents misuse of this endpoint, as
-	// resource_id should be used for single objects, not for a list of them.
-	var (
-		idFetch  int
-		maxFetch = 10
-	)
-	for _, v := range params.Checks {
-		if v.Object.ResourceID != "" {
-			idFetch++
-		}
-	}
-	if idFetch > maxFetch {
+	if len(params.Checks) > maxAuthorizationChecks {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: fmt.Sprintf(
-				"Endpoint only supports using \"resource_id\" field %d times, found %d usages. Remove %d objects with this field set.",
-				maxFetch, idFetch, idFetch-maxFetch,
+				"Endpoint only supports %d checks per request, found %d. Split the checks into multiple requests.",
+				maxAuthorizationChecks, len(params.Checks),
 			),
 		})
 		return
 	}
 
+	// Objects referenced by "resource_id" are fetched once, no matter how
+	// many checks use them. The number of distinct objects is limited to
+	// prevent database abuse from this endpoint.
+	type fetchKey struct {
+		resourceType string
+		id           uuid.UUID
+	}
+	type fetchResult struct {
+		obj       rbac.Object
+		supported bool
+		err       error
+	}
+	fetches := map[fetchKey]*fetchResult{}
+	keys := make([]string, 0, len(params.Checks))
 	for k, v := range params.Checks {
+		keys = append(keys, k)
 		if v.Object.ResourceType == "" {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 				Message: fmt.Sprintf("Object's \"resource_type\" field must be defined for key %q.", k),
 			})
 			return
 		}
+		if v.Object.ResourceID == "" {
+			continue
+		}
+		id, err := uuid.Parse(v.Object.ResourceID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     fmt.Sprintf("Object %q id is not a valid uuid.", v.Object.ResourceID),
+				Validations: []codersdk.ValidationError{{Field: "resource_id", Detail: err.Error()}},
+			})
+			return
+		}
+		fetches[fetchKey{resourceType: v.Object.ResourceType.String(), id: id}] = &fetchResult{}
+	}
+	if len(fetches) > maxAuthorizationFetches {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf(
+				"Endpoint only supports using \"resource_id\" field with %d distinct objects, found %d. Remove %d objects with this field set.",
+				maxAuthorizationFetches, len(fetches), len(fetches)-maxAuthorizationFetches,
+			),
+		})
+		return
+	}
 
+	var eg errgroup.Group
+	eg.SetLimit(10)
+	for key, result := range fetches {
+		key, result := key, result
+		eg.Go(func() error {
+			result.obj, result.supported, result.err = api.fetchRBACObject(ctx, key.resourceType, key.id)
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	// Checks are evaluated in a stable order so the same request always
+	// reports the same error.
+	sort.Strings(keys)
+	// Checks of the same action and resource type share a prepared
+	// authorizer, which is much faster than authorizing each check from
+	// scratch.
+	type preparedKey struct {
+		action       rbac.Action
+		resourceType string
+	}
+	prepared := map[preparedKey]rbac.PreparedAuthorized{}
+	response := make(codersdk.AuthorizationResponse, len(params.Checks))
+	for _, k := range keys {
+		v := params.Checks[k]
 		obj := rbac.Object{
 			Owner: v.Object.OwnerID,
 			OrgID: v.Object.OrganizationID,
@@ -174,36 +239,10 @@ func (api *API) checkAuthorization(rw http.ResponseWriter, r *http.Request) {
 			obj.Owner = auth.Actor.ID
 		}
 
-		// If a resource ID is specified, fetch that specific resource.
+		// If a resource ID is specified, use the fetched resource.
 		if v.Object.ResourceID != "" {
-			id, err := uuid.Parse(v.Object.ResourceID)
-			if err != nil {
-				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-					Message:     fmt.Sprintf("Object %q id is not a valid uuid.", v.Object.ResourceID),
-					Validations: []codersdk.ValidationError{{Field: "resource_id", Detail: err.Error()}},
-				})
-				return
-			}
-
-			var dbObj rbac.Objecter
-			var dbErr error
-			// Only support referencing some resources by ID.
-			switch v.Object.ResourceType.String() {
-			case rbac.ResourceWorkspaceExecution.Type:
-				wrkSpace, err := api.Database.GetWorkspaceByID(ctx, id)
-				if err == nil {
-					dbObj = wrkSpace.ExecutionRBAC()
-				}
-				dbErr = err
-			case rbac.ResourceWorkspace.Type:
-				dbObj, dbErr = api.Database.GetWorkspaceByID(ctx, id)
-			case rbac.ResourceTemplate.Type:
-				dbObj, dbErr = api.Database.GetTemplateByID(ctx, id)
-			case rbac.ResourceUser.Type:
-				dbObj, dbErr = api.Database.GetUserByID(ctx, id)
-			case rbac.ResourceGroup.Type:
-				dbObj, dbErr = api.Database.GetGroupByID(ctx, id)
-			default:
+			result := fetches[fetchKey{resourceType: obj.Type, id: uuid.MustParse(v.Object.ResourceID)}]
+			if !result.supported {
 				msg := fmt.Sprintf("Object type %q does not support \"resource_id\" field.", v.Object.ResourceType)
 				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 					Message:     msg,
@@ -211,16 +250,231 @@ func (api *API) checkAuthorization(rw http.ResponseWriter, r *http.Request) {
 				})
 				return
 			}
-			if dbErr != nil {
+			if result.err != nil {
 				// 404 or unauthorized is false
 				response[k] = false
 				continue
 			}
-			obj = dbObj.RBACObject()
+			obj = result.obj
+		}
+
+		pk := preparedKey{action: rbac.Action(v.Action), resourceType: obj.Type}
+		authorizer, ok := prepared[pk]
+		if !ok {
+			var err error
+			authorizer, err = api.Authorizer.Prepare(ctx, auth.Actor, pk.action, pk.resourceType)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error preparing authorization.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			prepared[pk] = authorizer
+		}
+		response[k] = authorizer.Authorize(ctx, obj) == nil
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, response)
+}
+
+// fetchRBACObject fetches a resource by ID to authorize against it. Only some
+// resource types can be referenced by ID, supported is false for the others.
+func (api *API) fetchRBACObject(ctx context.Context, resourceType string, id uuid.UUID) (obj rbac.Object, supported bool, err error) {
+	var dbObj rbac.Objecter
+	switch resourceType {
+	case rbac.ResourceWorkspaceExecution.Type:
+		var workspace database.Workspace
+		workspace, err = api.Database.GetWorkspaceByID(ctx, id)
+		dbObj = workspace.ExecutionRBAC()
+	case rbac.ResourceWorkspace.Type:
+		dbObj, err = api.Database.GetWorkspaceByID(ctx, id)
+	case rbac.ResourceTemplate.Type:
+		dbObj, err = api.Database.GetTemplateByID(ctx, id)
+	case rbac.ResourceUser.Type:
+		dbObj, err = api.Database.GetUserByID(ctx, id)
+	case rbac.ResourceGroup.Type:
+		dbObj, err = api.Database.GetGroupByID(ctx, id)
+	default:
+		return rbac.Object{}, false, nil
+	}
+	if err != nil {
+		return rbac.Object{}, true, err
+	}
+	return dbObj.RBACObject(), true, nil
+}
+
+// effectivePermissions explains which roles and ACL entries of a user grant
+// or deny an action on an object. The decision comes from the authorizer, the
+// entries from rbac.Explain.
+//
+// @Summary Explain effective permissions
+// @Description Explains which roles and ACL entries grant or deny an action
+// @Description on an object to a user.
+// @ID explain-effective-permissions
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Authorization
+// @Param user query string true "User ID or username"
+// @Param object query string true "Resource type, optionally followed by a colon and a resource ID"
+// @Param action query string false "Action, defaults to read" Enums(create,read,update,delete,push_version,manage_acl)
+// @Success 200 {object} codersdk.EffectivePermissions
+// @Router /authcheck/effective [get]
+func (api *API) effectivePermissions(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceDebugInfo) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	p := httpapi.NewQueryParamParser().
+		Required("user").
+		Required("object")
+	vals := r.URL.Query()
+	var (
+		userQuery   = p.String(vals, "", "user")
+		objectQuery = p.String(vals, "", "object")
+		action      = httpapi.ParseCustom(p, vals, rbac.ActionRead, "action", func(v string) (rbac.Action, error) {
+			if !slices.Contains(rbac.AllActions(), rbac.Action(v)) {
+				return "", xerrors.Errorf("%q is not a valid action", v)
+			}
+			return rbac.Action(v), nil
+		})
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	var (
+		user database.User
+		err  error
+	)
+	if userQuery == "me" {
+		user, err = api.Database.GetUserByID(ctx, httpmw.APIKey(r).UserID)
+	} else if userID, parseErr := uuid.Parse(userQuery); parseErr == nil {
+		user, err = api.Database.GetUserByID(ctx, userID)
+	} else {
+		user, err = api.Database.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
+			Username: userQuery,
+		})
+	}
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     fmt.Sprintf("User %q does not exist.", userQuery),
+			Validations: []codersdk.ValidationError{{Field: "user", Detail: "User not found."}},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resourceType, resourceID, hasID := strings.Cut(objectQuery, ":")
+	if !slices.ContainsFunc(rbac.AllResources(), func(o rbac.Object) bool { return o.Type == resourceType }) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     fmt.Sprintf("Object type %q does not exist.", resourceType),
+			Validations: []codersdk.ValidationError{{Field: "object", Detail: "Unknown resource type."}},
+		})
+		return
+	}
+	obj := rbac.Object{Type: resourceType}
+	if hasID {
+		id, err := uuid.Parse(resourceID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     fmt.Sprintf("Object %q id is not a valid uuid.", resourceID),
+				Validations: []codersdk.ValidationError{{Field: "object", Detail: err.Error()}},
+			})
+			return
+		}
+		var supported bool
+		obj, supported, err = api.fetchRBACObject(ctx, resourceType, id)
+		if !supported {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     fmt.Sprintf("Object type %q can't be referenced by ID.", resourceType),
+				Validations: []codersdk.ValidationError{{Field: "object", Detail: "Only workspace, workspace_execution, template, user and group objects support an ID."}},
+			})
+			return
+		}
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
 		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching object.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
+	//nolint:gocritic // Reading the roles of other users requires the system.
+	roles, err := api.Database.GetAuthorizationUserRoles(dbauthz.AsSystemRestricted(ctx), user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	subject := rbac.Subject{
+		ID:     user.ID.String(),
+		Roles:  rbac.RoleNames(roles.Roles),
+		Groups: roles.Groups,
+		Scope:  rbac.ScopeAll,
+	}
+	explanation, err := rbac.Explain(subject, action, obj)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error explaining permissions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
 
-		err := api.Authorizer.Authorize(ctx, auth.Actor, rbac.Action(v.Action), obj)
-		response[k] = err == nil
+	response := codersdk.EffectivePermissions{
+		UserID:   user.ID,
+		Username: user.Username,
+		Object: codersdk.AuthorizationObject{
+			ResourceType:   codersdk.RBACResource(obj.Type),
+			OwnerID:        obj.Owner,
+			OrganizationID: obj.OrgID,
+			ResourceID:     obj.ID,
+		},
+		Action:             string(action),
+		Allowed:            api.Authorizer.Authorize(ctx, subject, action, obj) == nil,
+		OrganizationMember: explanation.OrgMember,
+		Entries:            make([]codersdk.EffectivePermissionEntry, 0, len(explanation.Entries)),
+	}
+	for _, entry := range explanation.Entries {
+		apiEntry := codersdk.EffectivePermissionEntry{
+			Source: codersdk.EffectivePermissionSource(entry.Source),
+			Role:   entry.Role,
+			Allow:  entry.Allow,
+		}
+		if groupID, err := uuid.Parse(entry.Group); err == nil {
+			group, err := api.Database.GetGroupByID(ctx, groupID)
+			if err != nil {
+				httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+					Message: "Internal error fetching group.",
+					Detail:  err.Error(),
+				})
+				return
+			}
+			apiEntry.GroupID = &groupID
+			apiEntry.GroupName = group.Name
+		}
+		response.Entries = append(response.Entries, apiEntry)
 	}
 
 	httpapi.Write(ctx, rw, http.StatusOK, response)
@@ -2,6 +2,8 @@ package coderd_test
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/google/uuid"
@@ -139,3 +141,187 @@ func TestCheckPermissions(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckPermissionsBatch(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	t.Run("Many", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Every check of the same object shares a single fetch.
+		checks := map[string]codersdk.AuthorizationCheck{}
+		for i := 0; i < 500; i++ {
+			checks[fmt.Sprintf("read-template-%d", i)] = codersdk.AuthorizationCheck{
+				Object: codersdk.AuthorizationObject{
+					ResourceType: codersdk.ResourceTemplate,
+					ResourceID:   template.ID.String(),
+				},
+				Action: "read",
+			}
+			checks[fmt.Sprintf("update-template-%d", i)] = codersdk.AuthorizationCheck{
+				Object: codersdk.AuthorizationObject{
+					ResourceType: codersdk.ResourceTemplate,
+					ResourceID:   template.ID.String(),
+				},
+				Action: "update",
+			}
+		}
+
+		resp, err := member.AuthCheck(ctx, codersdk.AuthorizationRequest{Checks: checks})
+		require.NoError(t, err)
+		require.Len(t, resp, len(checks))
+		for i := 0; i < 500; i++ {
+			require.True(t, resp[fmt.Sprintf("read-template-%d", i)])
+			require.False(t, resp[fmt.Sprintf("update-template-%d", i)])
+		}
+	})
+
+	t.Run("TooManyChecks", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		checks := map[string]codersdk.AuthorizationCheck{}
+		for i := 0; i < 1001; i++ {
+			checks[fmt.Sprintf("read-users-%d", i)] = codersdk.AuthorizationCheck{
+				Object: codersdk.AuthorizationObject{ResourceType: codersdk.ResourceUser},
+				Action: "read",
+			}
+		}
+		_, err := member.AuthCheck(ctx, codersdk.AuthorizationRequest{Checks: checks})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("TooManyObjects", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		checks := map[string]codersdk.AuthorizationCheck{}
+		for i := 0; i < 101; i++ {
+			checks[fmt.Sprintf("read-workspace-%d", i)] = codersdk.AuthorizationCheck{
+				Object: codersdk.AuthorizationObject{
+					ResourceType: codersdk.ResourceWorkspace,
+					ResourceID:   uuid.NewString(),
+				},
+				Action: "read",
+			}
+		}
+		_, err := member.AuthCheck(ctx, codersdk.AuthorizationRequest{Checks: checks})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func TestEffectivePermissions(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	member, memberUser := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, nil)
+	template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+
+	t.Run("GroupACL", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		permissions, err := client.EffectivePermissions(ctx, codersdk.EffectivePermissionsRequest{
+			User:   memberUser.Username,
+			Object: "template:" + template.ID.String(),
+		})
+		require.NoError(t, err)
+		require.Equal(t, memberUser.ID, permissions.UserID)
+		require.Equal(t, "read", permissions.Action)
+		require.Equal(t, template.ID.String(), permissions.Object.ResourceID)
+		require.True(t, permissions.Allowed)
+		require.True(t, permissions.OrganizationMember)
+
+		// Members can use templates through the group of all users.
+		var everyone *codersdk.EffectivePermissionEntry
+		for i, entry := range permissions.Entries {
+			if entry.Source == codersdk.EffectivePermissionSourceGroupACL {
+				everyone = &permissions.Entries[i]
+			}
+		}
+		require.NotNil(t, everyone)
+		require.Equal(t, owner.OrganizationID, *everyone.GroupID)
+		require.Equal(t, "Everyone", everyone.GroupName)
+		require.True(t, everyone.Allow)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		permissions, err := client.EffectivePermissions(ctx, codersdk.EffectivePermissionsRequest{
+			User:   memberUser.ID.String(),
+			Object: "template:" + template.ID.String(),
+			Action: "delete",
+		})
+		require.NoError(t, err)
+		require.False(t, permissions.Allowed)
+		for _, entry := range permissions.Entries {
+			require.False(t, entry.Allow, "unexpected entry allowing the action: %+v", entry)
+		}
+	})
+
+	t.Run("Owner", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		permissions, err := client.EffectivePermissions(ctx, codersdk.EffectivePermissionsRequest{
+			User:   codersdk.Me,
+			Object: "template",
+			Action: "delete",
+		})
+		require.NoError(t, err)
+		require.True(t, permissions.Allowed)
+		require.Contains(t, permissions.Entries, codersdk.EffectivePermissionEntry{
+			Source: codersdk.EffectivePermissionSourceSite,
+			Role:   rbac.RoleOwner(),
+			Allow:  true,
+		})
+	})
+
+	t.Run("InvalidObject", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.EffectivePermissions(ctx, codersdk.EffectivePermissionsRequest{
+			User:   memberUser.Username,
+			Object: "nonexistent",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.EffectivePermissions(ctx, codersdk.EffectivePermissionsRequest{
+			User:   memberUser.Username,
+			Object: "template:" + uuid.NewString(),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.EffectivePermissions(ctx, codersdk.EffectivePermissionsRequest{
+			User:   codersdk.Me,
+			Object: "template:" + template.ID.String(),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
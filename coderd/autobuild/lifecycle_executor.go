@@ -15,6 +15,8 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/codersdk"
@@ -24,6 +26,7 @@ import (
 type Executor struct {
 	ctx                   context.Context
 	db                    database.Store
+	ps                    pubsub.Pubsub
 	templateScheduleStore *atomic.Pointer[schedule.TemplateScheduleStore]
 	log                   slog.Logger
 	tick                  <-chan time.Time
@@ -38,11 +41,12 @@ type Stats struct {
 }
 
 // New returns a new wsactions executor.
-func NewExecutor(ctx context.Context, db database.Store, tss *atomic.Pointer[schedule.TemplateScheduleStore], log slog.Logger, tick <-chan time.Time) *Executor {
+func NewExecutor(ctx context.Context, db database.Store, ps pubsub.Pubsub, tss *atomic.Pointer[schedule.TemplateScheduleStore], log slog.Logger, tick <-chan time.Time) *Executor {
 	le := &Executor{
 		//nolint:gocritic // Autostart has a limited set of permissions.
 		ctx:                   dbauthz.AsAutostart(ctx),
 		db:                    db,
+		ps:                    ps,
 		templateScheduleStore: tss,
 		tick:                  tick,
 		log:                   log.Named("autobuild"),
@@ -127,7 +131,12 @@ func (e *Executor) runOnce(t time.Time) Stats {
 		log := e.log.With(slog.F("workspace_id", wsID))
 
 		eg.Go(func() error {
+			var job *database.ProvisionerJob
 			err := e.db.InTx(func(tx database.Store) error {
+				// The transaction may be retried, so only the job of the
+				// attempt that commits is posted.
+				job = nil
+
 				// Re-check eligibility since the first check was outside the
 				// transaction and the workspace settings may have changed.
 				ws, err := tx.GetWorkspaceByID(e.ctx, wsID)
@@ -165,14 +174,21 @@ func (e *Executor) runOnce(t time.Time) Stats {
 						SetLastWorkspaceBuildInTx(&latestBuild).
 						SetLastWorkspaceBuildJobInTx(&latestJob).
 						Reason(reason)
+					// Workspaces that opted into automatic updates are
+					// started with the active version of their template.
+					if nextTransition == database.WorkspaceTransitionStart && ws.AutomaticUpdates == database.AutomaticUpdatesAlways {
+						builder = builder.ActiveVersion()
+					}
 
-					if _, _, err := builder.Build(e.ctx, tx, nil); err != nil {
+					_, builtJob, err := builder.Build(e.ctx, tx, nil)
+					if err != nil {
 						log.Error(e.ctx, "unable to transition workspace",
 							slog.F("transition", nextTransition),
 							slog.Error(err),
 						)
 						return nil
 					}
+					job = builtJob
 				}
 
 				// Lock the workspace if it has breached the template's
@@ -227,6 +243,13 @@ func (e *Executor) runOnce(t time.Time) Stats {
 			}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
 			if err != nil {
 				log.Error(e.ctx, "workspace scheduling failed", slog.Error(err))
+				return nil
+			}
+			if job != nil {
+				err = provisionerdserver.PostJob(e.ps, *job)
+				if err != nil {
+					log.Error(e.ctx, "post provisioner job to pubsub", slog.Error(err))
+				}
 			}
 			return nil
 		})
@@ -264,7 +287,7 @@ func getNextTransition(
 	case isEligibleForAutostart(ws, latestBuild, latestJob, templateSchedule, currentTick):
 		return database.WorkspaceTransitionStart, database.BuildReasonAutostart, nil
 	case isEligibleForFailedStop(latestBuild, latestJob, templateSchedule, currentTick):
-		return database.WorkspaceTransitionStop, database.BuildReasonAutostop, nil
+		return database.WorkspaceTransitionStop, database.BuildReasonFailedstop, nil
 	case isEligibleForLockedStop(ws, templateSchedule, currentTick):
 		// Only stop started workspaces.
 		if latestBuild.Transition == database.WorkspaceTransitionStart {
@@ -109,6 +109,58 @@ func TestExecutorAutostartTemplateUpdated(t *testing.T) {
 	assert.Equal(t, workspace.LatestBuild.TemplateVersionID, ws.LatestBuild.TemplateVersionID, "expected workspace build to be using the old template version")
 }
 
+func TestExecutorAutostartAutomaticUpdates(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sched   = mustSchedule(t, "CRON_TZ=UTC 0 * * * *")
+		ctx     = context.Background()
+		err     error
+		tickCh  = make(chan time.Time)
+		statsCh = make(chan autobuild.Stats)
+		client  = coderdtest.New(t, &coderdtest.Options{
+			AutobuildTicker:          tickCh,
+			IncludeProvisionerDaemon: true,
+			AutobuildStats:           statsCh,
+		})
+		// Given: we have a user with a workspace that has autostart and
+		// automatic updates enabled
+		workspace = mustProvisionWorkspace(t, client, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.AutostartSchedule = ptr.Ref(sched.String())
+			cwr.AutomaticUpdates = codersdk.AutomaticUpdatesAlways
+		})
+	)
+	require.Equal(t, codersdk.AutomaticUpdatesAlways, workspace.AutomaticUpdates)
+	// Given: workspace is stopped
+	workspace = coderdtest.MustTransitionWorkspace(t, client, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+
+	// Given: the workspace template has been updated
+	orgs, err := client.OrganizationsByUser(ctx, workspace.OwnerID.String())
+	require.NoError(t, err)
+	require.Len(t, orgs, 1)
+
+	newVersion := coderdtest.UpdateTemplateVersion(t, client, orgs[0].ID, nil, workspace.TemplateID)
+	coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+	require.NoError(t, client.UpdateActiveTemplateVersion(ctx, workspace.TemplateID, codersdk.UpdateActiveTemplateVersion{
+		ID: newVersion.ID,
+	}))
+
+	// When: the autobuild executor ticks after the scheduled time
+	go func() {
+		tickCh <- sched.Next(workspace.LatestBuild.CreatedAt)
+		close(tickCh)
+	}()
+
+	// Then: the workspace should be started using the updated template version.
+	stats := <-statsCh
+	assert.NoError(t, stats.Error)
+	assert.Len(t, stats.Transitions, 1)
+	assert.Contains(t, stats.Transitions, workspace.ID)
+	assert.Equal(t, database.WorkspaceTransitionStart, stats.Transitions[workspace.ID])
+	ws := coderdtest.MustWorkspace(t, client, workspace.ID)
+	assert.Equal(t, newVersion.ID, ws.LatestBuild.TemplateVersionID, "expected workspace build to be using the updated template version")
+}
+
 func TestExecutorAutostartAlreadyRunning(t *testing.T) {
 	t.Parallel()
 
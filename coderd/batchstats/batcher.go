@@ -35,7 +35,10 @@ type Batcher struct {
 	// NOTE: we batch this separately as it's a jsonb field and
 	// pq.Array + unnest doesn't play nicely with this.
 	connectionsByProto []map[string]int64
-	batchSize          int
+	// bandwidth holds the per-connection traffic of the buffered stats. It's
+	// inserted into its own table, so it has its own buffer.
+	bandwidth *database.InsertWorkspaceAgentBandwidthStatsParams
+	batchSize int
 
 	// tickCh is used to periodically flush the buffer.
 	tickCh   <-chan time.Time
@@ -105,6 +108,8 @@ func New(ctx context.Context, opts ...Option) (*Batcher, func(), error) {
 		b.tickCh = b.ticker.C
 	}
 
+	b.initBuf(b.batchSize)
+
 	cancelCtx, cancelFunc := context.WithCancel(ctx)
 	done := make(chan struct{})
 	go func() {
@@ -159,10 +164,29 @@ func (b *Batcher) Add(
 	b.buf.SessionCountSSH = append(b.buf.SessionCountSSH, st.SessionCountSSH)
 	b.buf.ConnectionMedianLatencyMS = append(b.buf.ConnectionMedianLatencyMS, st.ConnectionMedianLatencyMS)
 
+	for _, bw := range st.ConnectionBandwidth {
+		b.bandwidth.ID = append(b.bandwidth.ID, uuid.New())
+		b.bandwidth.CreatedAt = append(b.bandwidth.CreatedAt, now)
+		b.bandwidth.UserID = append(b.bandwidth.UserID, userID)
+		b.bandwidth.WorkspaceID = append(b.bandwidth.WorkspaceID, workspaceID)
+		b.bandwidth.TemplateID = append(b.bandwidth.TemplateID, templateID)
+		b.bandwidth.AgentID = append(b.bandwidth.AgentID, agentID)
+		b.bandwidth.ConnectionType = append(b.bandwidth.ConnectionType, string(bw.Type))
+		b.bandwidth.Peer = append(b.bandwidth.Peer, bw.Peer)
+		b.bandwidth.Port = append(b.bandwidth.Port, int32(bw.Port))
+		b.bandwidth.RxBytes = append(b.bandwidth.RxBytes, bw.RxBytes)
+		b.bandwidth.TxBytes = append(b.bandwidth.TxBytes, bw.TxBytes)
+	}
+
 	// If the buffer is over 80% full, signal the flusher to flush immediately.
 	// We want to trigger flushes early to reduce the likelihood of
-	// accidentally growing the buffer over batchSize.
-	filled := float64(len(b.buf.ID)) / float64(b.batchSize)
+	// accidentally growing the buffer over batchSize. A single stat can add
+	// several bandwidth rows, so whichever buffer is fuller counts.
+	buffered := len(b.buf.ID)
+	if len(b.bandwidth.ID) > buffered {
+		buffered = len(b.bandwidth.ID)
+	}
+	filled := float64(buffered) / float64(b.batchSize)
 	if filled >= 0.8 && !b.flushForced.Load() {
 		b.flushLever <- struct{}{}
 		b.flushForced.Store(true)
@@ -172,7 +196,6 @@ func (b *Batcher) Add(
 
 // Run runs the batcher.
 func (b *Batcher) run(ctx context.Context) {
-	b.initBuf(b.batchSize)
 	// nolint:gocritic // This is only ever used for one thing - inserting agent stats.
 	authCtx := dbauthz.AsSystemRestricted(ctx)
 	for {
@@ -239,6 +262,15 @@ func (b *Batcher) flush(ctx context.Context, forced bool, reason string) {
 		return
 	}
 
+	if len(b.bandwidth.ID) > 0 {
+		// The agent stats are already stored, so bandwidth stats are
+		// dropped rather than retried to avoid inserting duplicates.
+		err = b.store.InsertWorkspaceAgentBandwidthStats(ctx, *b.bandwidth)
+		if err != nil {
+			b.log.Error(ctx, "error inserting workspace agent bandwidth stats, dropping data", slog.Error(err))
+		}
+	}
+
 	b.resetBuf()
 }
 
@@ -265,6 +297,20 @@ func (b *Batcher) initBuf(size int) {
 	}
 
 	b.connectionsByProto = make([]map[string]int64, 0, size)
+
+	b.bandwidth = &database.InsertWorkspaceAgentBandwidthStatsParams{
+		ID:             make([]uuid.UUID, 0, b.batchSize),
+		CreatedAt:      make([]time.Time, 0, b.batchSize),
+		UserID:         make([]uuid.UUID, 0, b.batchSize),
+		WorkspaceID:    make([]uuid.UUID, 0, b.batchSize),
+		TemplateID:     make([]uuid.UUID, 0, b.batchSize),
+		AgentID:        make([]uuid.UUID, 0, b.batchSize),
+		ConnectionType: make([]string, 0, b.batchSize),
+		Peer:           make([]string, 0, b.batchSize),
+		Port:           make([]int32, 0, b.batchSize),
+		RxBytes:        make([]int64, 0, b.batchSize),
+		TxBytes:        make([]int64, 0, b.batchSize),
+	}
 }
 
 func (b *Batcher) resetBuf() {
@@ -286,4 +332,16 @@ func (b *Batcher) resetBuf() {
 	b.buf.SessionCountSSH = b.buf.SessionCountSSH[:0]
 	b.buf.ConnectionMedianLatencyMS = b.buf.ConnectionMedianLatencyMS[:0]
 	b.connectionsByProto = b.connectionsByProto[:0]
+
+	b.bandwidth.ID = b.bandwidth.ID[:0]
+	b.bandwidth.CreatedAt = b.bandwidth.CreatedAt[:0]
+	b.bandwidth.UserID = b.bandwidth.UserID[:0]
+	b.bandwidth.WorkspaceID = b.bandwidth.WorkspaceID[:0]
+	b.bandwidth.TemplateID = b.bandwidth.TemplateID[:0]
+	b.bandwidth.AgentID = b.bandwidth.AgentID[:0]
+	b.bandwidth.ConnectionType = b.bandwidth.ConnectionType[:0]
+	b.bandwidth.Peer = b.bandwidth.Peer[:0]
+	b.bandwidth.Port = b.bandwidth.Port[:0]
+	b.bandwidth.RxBytes = b.bandwidth.RxBytes[:0]
+	b.bandwidth.TxBytes = b.bandwidth.TxBytes[:0]
 }
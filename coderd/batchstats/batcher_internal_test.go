@@ -127,6 +127,78 @@ func TestBatchStats(t *testing.T) {
 	require.Equal(t, defaultBufferSize, cap(b.buf.ID), "buffer grew beyond expected capacity")
 }
 
+func TestBatchStatsBandwidth(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	log := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Leveled(slog.LevelDebug)
+	store, _ := dbtestutil.NewDB(t)
+	deps := setupDeps(t, store)
+	tick := make(chan time.Time)
+	flushed := make(chan int)
+
+	b, closer, err := New(ctx,
+		WithStore(store),
+		WithLogger(log),
+		func(b *Batcher) {
+			b.tickCh = tick
+			b.flushed = flushed
+		},
+	)
+	require.NoError(t, err)
+	t.Cleanup(closer)
+
+	// Given: a stat with traffic of two connection types
+	t1 := database.Now()
+	require.NoError(t, b.Add(t1.Add(time.Millisecond), deps.Agent.ID, deps.Template.ID, deps.User.ID, deps.Workspace.ID, randAgentSDKStats(t, func(s *agentsdk.Stats) {
+		s.ConnectionBandwidth = []agentsdk.ConnectionBandwidth{
+			{Type: agentsdk.ConnectionTypeSSH, Peer: "fd7a:115c:a1e0::1", RxBytes: 10, TxBytes: 20},
+			{Type: agentsdk.ConnectionTypePortForward, Peer: "fd7a:115c:a1e0::1", Port: 8080, RxBytes: 30, TxBytes: 40},
+			{Type: agentsdk.ConnectionTypePortForward, Peer: "fd7a:115c:a1e0::2", Port: 8080, RxBytes: 50, TxBytes: 60},
+		}
+	})))
+
+	// When: it becomes time to report stats
+	tick <- t1
+	f := <-flushed
+	require.Equal(t, 1, f, "expected one stat to be flushed")
+
+	// Then: the traffic is stored by connection type
+	stats, err := store.GetWorkspaceAgentBandwidthStatsAndLabels(ctx, t1)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	byType := map[string]database.GetWorkspaceAgentBandwidthStatsAndLabelsRow{}
+	for _, stat := range stats {
+		byType[stat.ConnectionType] = stat
+	}
+	require.EqualValues(t, 10, byType["ssh"].RxBytes)
+	require.EqualValues(t, 20, byType["ssh"].TxBytes)
+	require.EqualValues(t, 80, byType["port_forward"].RxBytes)
+	require.EqualValues(t, 100, byType["port_forward"].TxBytes)
+	require.Equal(t, deps.User.Username, byType["ssh"].Username)
+
+	// And: the buffer is reset after flushing
+	require.Empty(t, b.bandwidth.ID)
+
+	// Given: a few stats with many connections each
+	t2 := t1.Add(time.Second)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.Add(t2.Add(time.Millisecond), deps.Agent.ID, deps.Template.ID, deps.User.ID, deps.Workspace.ID, randAgentSDKStats(t, func(s *agentsdk.Stats) {
+			s.ConnectionBandwidth = make([]agentsdk.ConnectionBandwidth, 0, defaultBufferSize/3)
+			for port := 0; port < defaultBufferSize/3; port++ {
+				s.ConnectionBandwidth = append(s.ConnectionBandwidth, agentsdk.ConnectionBandwidth{
+					Type: agentsdk.ConnectionTypePortForward, Peer: "fd7a:115c:a1e0::1", Port: uint16(port + 1), RxBytes: 1, TxBytes: 1,
+				})
+			}
+		})))
+	}
+
+	// Then: the bandwidth rows alone force a flush
+	f = <-flushed
+	require.Equal(t, 3, f, "expected a forced flush of three stats")
+}
+
 // randAgentSDKStats returns a random agentsdk.Stats
 func randAgentSDKStats(t *testing.T, opts ...func(*agentsdk.Stats)) agentsdk.Stats {
 	t.Helper()
@@ -2,6 +2,7 @@ package coderd
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -26,6 +27,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 	"go.opentelemetry.io/otel/trace"
+	gossh "golang.org/x/crypto/ssh"
 	"golang.org/x/xerrors"
 	"google.golang.org/api/idtoken"
 	"storj.io/drpc/drpcmux"
@@ -53,9 +55,12 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/metricscache"
+	"github.com/coder/coder/v2/coderd/parameteroptions"
+	"github.com/coder/coder/v2/coderd/platformevents"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/secretmanager"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/updatecheck"
@@ -142,6 +147,10 @@ type Options struct {
 	// related to OAuth. This is a symmetric secret key using hmac to sign payloads.
 	// So this secret should **never** be exposed to the client.
 	OAuthSigningKey [32]byte
+	// SSHCAKey is the key of the certificate authority that signs SSH
+	// certificates of users and workspace agents. A random key is generated
+	// if it's nil.
+	SSHCAKey ed25519.PrivateKey
 
 	// APIRateLimit is the minutely throughput rate limit per user or ip.
 	// Setting a rate limit <0 will disable the rate limiter across the entire
@@ -164,6 +173,9 @@ type Options struct {
 	StatsBatcher       *batchstats.Batcher
 
 	WorkspaceAppsStatsCollectorOptions workspaceapps.StatsCollectorOptions
+
+	// SecretManager resolves secret:// references in template variables.
+	SecretManager *secretmanager.Manager
 }
 
 // @title Coder API
@@ -307,6 +319,11 @@ func New(options *Options) *API {
 		panic(xerrors.Errorf("read site bin failed: %w", err))
 	}
 
+	sshCA, err := newSSHCertificateAuthority(options.SSHCAKey)
+	if err != nil {
+		panic(xerrors.Errorf("create ssh certificate authority: %w", err))
+	}
+
 	metricsCache := metricscache.New(
 		options.Database,
 		options.Logger.Named("metrics_cache"),
@@ -363,11 +380,17 @@ func New(options *Options) *API {
 			options.AppSecurityKey,
 		),
 		metricsCache:                metricsCache,
+		sshCA:                       sshCA,
 		Auditor:                     atomic.Pointer[audit.Auditor]{},
 		TemplateScheduleStore:       options.TemplateScheduleStore,
 		UserQuietHoursScheduleStore: options.UserQuietHoursScheduleStore,
 		Experiments:                 experiments,
+		featureFlags:                ReadFeatureFlags(options.Logger, options.DeploymentValues.FeatureFlags.Value()),
 		healthCheckGroup:            &singleflight.Group[string, *healthcheck.Report]{},
+		deploymentStatusGroup:       &singleflight.Group[string, codersdk.DeploymentStatus]{},
+		agentAdmission:              newAgentAdmission(options.DeploymentValues.AgentAdmissionRate.Value()),
+		parameterOptions:            parameteroptions.New(options.HTTPClient),
+		PlatformEvents:              platformevents.New(options.Logger.Named("platform_events"), options.Database, options.Pubsub),
 	}
 	if options.UpdateCheckOptions != nil {
 		api.updateChecker = updatecheck.New(
@@ -383,6 +406,10 @@ func New(options *Options) *API {
 				AccessURL: options.AccessURL,
 				DERPMap:   api.DERPMap(),
 				APIKey:    apiKey,
+
+				ReplicaID:         api.ID,
+				Pubsub:            options.Pubsub,
+				CoordinatorStatus: *api.TailnetCoordinatorStatus.Load(),
 			})
 		}
 	}
@@ -400,6 +427,14 @@ func New(options *Options) *API {
 
 	api.Auditor.Store(&options.Auditor)
 	api.TailnetCoordinator.Store(&options.TailnetCoordinator)
+	api.TailnetCoordinatorStatus.Store(&healthcheck.CoordinatorStatus{
+		Type: healthcheck.CoordinatorTypeMemory,
+	})
+	agentConnCacheOptions := wsconncache.Options{
+		MaxSize:    int(options.DeploymentValues.AgentConnectionCache.MaxSize.Value()),
+		MaxAge:     options.DeploymentValues.AgentConnectionCache.MaxAge.Value(),
+		Registerer: options.PrometheusRegistry,
+	}
 	if api.Experiments.Enabled(codersdk.ExperimentSingleTailnet) {
 		api.agentProvider, err = NewServerTailnet(api.ctx,
 			options.Logger,
@@ -408,7 +443,7 @@ func New(options *Options) *API {
 			func(context.Context) (tailnet.MultiAgentConn, error) {
 				return (*api.TailnetCoordinator.Load()).ServeMultiAgent(uuid.New()), nil
 			},
-			wsconncache.New(api._dialWorkspaceAgentTailnet, 0),
+			wsconncache.NewWithOptions(api._dialWorkspaceAgentTailnet, agentConnCacheOptions),
 			api.TracerProvider,
 		)
 		if err != nil {
@@ -416,7 +451,7 @@ func New(options *Options) *API {
 		}
 	} else {
 		api.agentProvider = &wsconncache.AgentProvider{
-			Cache: wsconncache.New(api._dialWorkspaceAgentTailnet, 0),
+			Cache: wsconncache.NewWithOptions(api._dialWorkspaceAgentTailnet, agentConnCacheOptions),
 		}
 	}
 
@@ -442,6 +477,7 @@ func New(options *Options) *API {
 		AgentProvider:       api.agentProvider,
 		AppSecurityKey:      options.AppSecurityKey,
 		StatsCollector:      workspaceapps.NewStatsCollector(options.WorkspaceAppsStatsCollectorOptions),
+		AccessAuditor:       primaryAccessAuditor{api: api},
 
 		DisablePathApps:  options.DeploymentValues.DisablePathApps.Value(),
 		SecureAuthCookie: options.DeploymentValues.SecureAuthCookie.Value(),
@@ -478,8 +514,19 @@ func New(options *Options) *API {
 	// replicas or instances of this middleware.
 	apiRateLimiter := httpmw.RateLimit(options.APIRateLimit, time.Minute)
 
+	api.DERPUsage = tailnet.NewDERPUsage(int(options.DeploymentValues.DERP.Server.RegionID.Value()))
+	err = options.PrometheusRegistry.Register(api.DERPUsage)
+	if err != nil {
+		api.Logger.Warn(ctx, "register DERP usage metrics", slog.Error(err))
+	}
+	api.agentFirstConnectMetrics = newAgentFirstConnectMetrics()
+	err = api.agentFirstConnectMetrics.register(options.PrometheusRegistry)
+	if err != nil {
+		api.Logger.Warn(ctx, "register agent first connect metrics", slog.Error(err))
+	}
 	derpHandler := derphttp.Handler(api.DERPServer)
 	derpHandler, api.derpCloseFunc = tailnet.WithWebsocketSupport(api.DERPServer, derpHandler)
+	derpHandler = api.DERPUsage.Handler(derpHandler)
 	cors := httpmw.Cors(options.DeploymentValues.Dangerous.AllowAllCors.Value())
 	prometheusMW := httpmw.Prometheus(options.PrometheusRegistry)
 
@@ -572,20 +619,55 @@ func New(options *Options) *API {
 		r.Group(func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Get("/regions", api.regions)
+			r.Post("/regions/latency", api.rankRegions)
 		})
 		r.Route("/derp-map", func(r chi.Router) {
-			// r.Use(apiKeyMiddleware)
+			// Both users and agents subscribe to DERP map updates. Neither is
+			// required. Users only get the workspace proxies they may use,
+			// agents get every proxy.
+			r.Use(
+				apiKeyMiddlewareOptional,
+				httpmw.ExtractWorkspaceAgent(httpmw.ExtractWorkspaceAgentConfig{
+					DB:       options.Database,
+					Optional: true,
+				}),
+			)
 			r.Get("/", api.derpMapUpdates)
 		})
-		r.Route("/deployment", func(r chi.Router) {
+		r.Route("/netcheck", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
-			r.Get("/config", api.deploymentValues)
-			r.Get("/stats", api.deploymentStats)
-			r.Get("/ssh", api.sshConfig)
+			r.Post("/", api.postNetcheckReport)
+		})
+		r.Route("/deployment", func(r chi.Router) {
+			r.Group(func(r chi.Router) {
+				r.Use(apiKeyMiddleware)
+				r.Get("/config", api.deploymentValues)
+				r.Get("/config/redacted", api.deploymentValuesRedacted)
+				r.Get("/config/diff", api.deploymentValuesDiff)
+				r.Get("/stats", api.deploymentStats)
+				r.Get("/ssh", api.sshConfig)
+			})
+			// The certificate authority is public, so workspaces can trust it
+			// without a session token.
+			r.Get("/ssh/ca", api.sshCertificateAuthority)
+			r.Group(func(r chi.Router) {
+				// The status is public by default so it can be used by
+				// external status pages and load balancers.
+				if options.DeploymentValues.StatusRequireAuth.Value() {
+					r.Use(apiKeyMiddleware)
+				}
+				r.Get("/status", api.deploymentStatus)
+			})
 		})
 		r.Route("/experiments", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Get("/", api.handleExperimentsGet)
+			r.Get("/config", api.experimentsConfig)
+			r.Put("/config", api.putRuntimeExperiments)
+		})
+		r.Route("/feature-flags", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.userFeatureFlags)
 		})
 		r.Get("/updatecheck", api.updateCheck)
 		r.Route("/audit", func(r chi.Router) {
@@ -623,6 +705,7 @@ func New(options *Options) *API {
 					httpmw.ExtractOrganizationParam(options.Database),
 				)
 				r.Get("/", api.organization)
+				r.Patch("/", api.patchOrganization)
 				r.Post("/templateversions", api.postTemplateVersionsByOrganization)
 				r.Route("/templates", func(r chi.Router) {
 					r.Post("/", api.postTemplateByOrganization)
@@ -636,6 +719,10 @@ func New(options *Options) *API {
 						})
 					})
 				})
+				r.Route("/environmentvariables", func(r chi.Router) {
+					r.Get("/", api.organizationEnvironmentVariables)
+					r.Post("/", api.postOrganizationEnvironmentVariable)
+				})
 				r.Route("/members", func(r chi.Router) {
 					r.Get("/roles", api.assignableOrgRoles)
 					r.Route("/{user}", func(r chi.Router) {
@@ -658,12 +745,25 @@ func New(options *Options) *API {
 			r.Get("/", api.template)
 			r.Delete("/", api.deleteTemplate)
 			r.Patch("/", api.patchTemplateMeta)
+			r.Post("/notify-outdated", api.postNotifyOutdatedWorkspaces)
+			r.Get("/insights/builds", api.templateBuildInsights)
+			r.Route("/environmentvariables", func(r chi.Router) {
+				r.Get("/", api.templateEnvironmentVariables)
+				r.Post("/", api.postTemplateEnvironmentVariable)
+			})
 			r.Route("/versions", func(r chi.Router) {
 				r.Get("/", api.templateVersionsByTemplate)
 				r.Patch("/", api.patchActiveTemplateVersion)
 				r.Get("/{templateversionname}", api.templateVersionByName)
+				r.Post("/{templateversion}/promote", api.postTemplateVersionPromotion)
 			})
 		})
+		r.Route("/templateversionpromotions", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.templateVersionPromotions)
+			r.Post("/{templateversionpromotion}/decision", api.postTemplateVersionPromotionDecision)
+		})
+		r.With(apiKeyMiddleware).Post("/templateversions/validate", api.postValidateTemplateVersion)
 		r.Route("/templateversions/{templateversion}", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
@@ -680,6 +780,7 @@ func New(options *Options) *API {
 			r.Get("/gitauth", api.templateVersionGitAuth)
 			r.Get("/variables", api.templateVersionVariables)
 			r.Get("/resources", api.templateVersionResources)
+			r.Post("/state-analysis", api.postTemplateVersionStateAnalysis)
 			r.Get("/logs", api.templateVersionLogs)
 			r.Route("/dry-run", func(r chi.Router) {
 				r.Post("/", api.postTemplateVersionDryRun)
@@ -735,6 +836,10 @@ func New(options *Options) *API {
 					r.Get("/", api.userByName)
 					r.Get("/login-type", api.userLoginType)
 					r.Put("/profile", api.putUserProfile)
+					r.Route("/pty-session-override", func(r chi.Router) {
+						r.Put("/", api.putUserPTYSessionOverride)
+						r.Delete("/", api.deleteUserPTYSessionOverride)
+					})
 					r.Route("/status", func(r chi.Router) {
 						r.Put("/suspend", api.putSuspendUserAccount())
 						r.Put("/activate", api.putActivateUserAccount())
@@ -770,8 +875,10 @@ func New(options *Options) *API {
 						r.Get("/", api.workspaceByOwnerAndName)
 						r.Get("/builds/{buildnumber}", api.workspaceBuildByBuildNumber)
 					})
+					r.Get("/connection-log", api.userConnectionLog)
 					r.Get("/gitsshkey", api.gitSSHKey)
 					r.Put("/gitsshkey", api.regenerateGitSSHKey)
+					r.Post("/ssh/certificate", api.postUserSSHCertificate)
 				})
 			})
 		})
@@ -793,6 +900,7 @@ func New(options *Options) *API {
 					Optional: false,
 				}))
 				r.Get("/manifest", api.workspaceAgentManifest)
+				r.Get("/feature-flags", api.workspaceAgentFeatureFlags)
 				// This route is deprecated and will be removed in a future release.
 				// New agents will use /me/manifest instead.
 				r.Get("/metadata", api.workspaceAgentManifest)
@@ -806,6 +914,7 @@ func New(options *Options) *API {
 				r.Post("/report-stats", api.workspaceAgentReportStats)
 				r.Post("/report-lifecycle", api.workspaceAgentReportLifecycle)
 				r.Post("/metadata/{key}", api.workspaceAgentPostMetadata)
+				r.Post("/rotate-token", api.postWorkspaceAgentRotateAuthToken)
 			})
 			r.Route("/{workspaceagent}", func(r chi.Router) {
 				r.Use(
@@ -822,11 +931,16 @@ func New(options *Options) *API {
 				)
 				r.Get("/", api.workspaceAgent)
 				r.Get("/watch-metadata", api.watchWorkspaceAgentMetadata)
+				r.Get("/metadata/history", api.workspaceAgentMetadataHistory)
 				r.Get("/startup-logs", api.workspaceAgentLogsDeprecated)
 				r.Get("/logs", api.workspaceAgentLogs)
 				r.Get("/listening-ports", api.workspaceAgentListeningPorts)
+				r.With(apiKeyMiddleware).Get("/support-bundle", api.workspaceAgentSupportBundle)
 				r.Get("/connection", api.workspaceAgentConnection)
 				r.Get("/coordinate", api.workspaceAgentClientCoordinate)
+				r.Get("/connections", api.workspaceAgentClientConnections)
+				// Workspace proxies can't revoke tokens, only users.
+				r.With(apiKeyMiddleware).Post("/revoke-token", api.postWorkspaceAgentRevokeAuthToken)
 
 				// PTY is part of workspaceAppServer.
 			})
@@ -845,6 +959,7 @@ func New(options *Options) *API {
 				r.Route("/builds", func(r chi.Router) {
 					r.Get("/", api.workspaceBuilds)
 					r.Post("/", api.postWorkspaceBuilds)
+					r.Get("/queue", api.workspaceBuildQueue)
 				})
 				r.Route("/autostart", func(r chi.Router) {
 					r.Put("/", api.putWorkspaceAutostart)
@@ -852,6 +967,15 @@ func New(options *Options) *API {
 				r.Route("/ttl", func(r chi.Router) {
 					r.Put("/", api.putWorkspaceTTL)
 				})
+				r.Put("/autoupdates", api.putWorkspaceAutoupdates)
+				r.Get("/connections", api.workspaceConnections)
+				r.Put("/external-metadata", api.putWorkspaceExternalMetadata)
+				r.Route("/webhooks", func(r chi.Router) {
+					r.Get("/", api.workspaceWebhooks)
+					r.Post("/", api.postWorkspaceWebhook)
+				})
+				r.Post("/clone", api.postWorkspaceClone)
+				r.Get("/approval", api.workspaceApproval)
 				r.Get("/watch", api.watchWorkspace)
 				r.Put("/extend", api.putExtendWorkspace)
 				r.Put("/lock", api.putWorkspaceLock)
@@ -869,10 +993,29 @@ func New(options *Options) *API {
 			r.Get("/parameters", api.workspaceBuildParameters)
 			r.Get("/resources", api.workspaceBuildResources)
 			r.Get("/state", api.workspaceBuildState)
+			r.Get("/timeline", api.workspaceBuildTimeline)
+		})
+		r.Route("/workspaceapprovals/{workspaceapproval}", func(r chi.Router) {
+			r.With(apiKeyMiddleware).Post("/decision", api.postWorkspaceApprovalDecision)
+			// Callbacks from the approval webhook are authenticated by their
+			// signature.
+			r.Post("/callback", api.postWorkspaceApprovalCallback)
+		})
+		r.Route("/environmentvariables/{environmentvariable}", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Patch("/", api.patchEnvironmentVariable)
+			r.Delete("/", api.deleteEnvironmentVariable)
+		})
+		r.Route("/workspacewebhooks/{workspacewebhook}", func(r chi.Router) {
+			r.With(apiKeyMiddleware).Delete("/", api.deleteWorkspaceWebhook)
+			// Trigger requests are authenticated by their signature instead of
+			// a session token.
+			r.Post("/trigger", api.triggerWorkspaceWebhook)
 		})
 		r.Route("/authcheck", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Post("/", api.checkAuthorization)
+			r.Get("/effective", api.effectivePermissions)
 		})
 		r.Route("/applications", func(r chi.Router) {
 			r.Route("/host", func(r chi.Router) {
@@ -888,12 +1031,23 @@ func New(options *Options) *API {
 				// handler and the login page.
 				r.Get("/", api.workspaceApplicationAuth)
 			})
+			// Apps verify the identity headers of requests with these keys,
+			// so they are public.
+			r.Get("/jwks", api.workspaceApplicationIdentityKeys)
 		})
 		r.Route("/insights", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Get("/daus", api.deploymentDAUs)
 			r.Get("/user-latency", api.insightsUserLatency)
+			r.Get("/user-bandwidth", api.insightsUserBandwidth)
 			r.Get("/templates", api.insightsTemplates)
+			r.Get("/template-build-slos", api.insightsTemplateBuildSLOs)
+			r.Get("/agent-first-connect", api.insightsAgentFirstConnect)
+			r.Get("/netcheck", api.insightsNetcheck)
+		})
+		r.Route("/platform-events", func(r chi.Router) {
+			r.Use(apiKeyMiddleware)
+			r.Get("/", api.watchPlatformEvents)
 		})
 		r.Route("/debug", func(r chi.Router) {
 			r.Use(
@@ -911,7 +1065,9 @@ func New(options *Options) *API {
 				},
 			)
 
+			r.Get("/connections", api.debugConnections)
 			r.Get("/coordinator", api.debugCoordinator)
+			r.Get("/derp/usage", api.debugDERPUsage)
 			r.Get("/health", api.debugDeploymentHealth)
 			r.Get("/ws", (&healthcheck.WebsocketEchoServer{}).ServeHTTP)
 		})
@@ -955,6 +1111,21 @@ func New(options *Options) *API {
 	rootRouter.Mount("/", r)
 	api.RootHandler = rootRouter
 
+	api.workspaceBuildQueueNotify = make(chan uuid.UUID, 64)
+	api.workspaceBuildQueueDone = make(chan struct{})
+	go api.runWorkspaceBuildQueue()
+
+	api.agentFirstConnectMonitorDone = make(chan struct{})
+	go api.runAgentFirstConnectMonitor()
+
+	api.templateBuildInsightsRollupDone = make(chan struct{})
+	go api.runTemplateBuildInsightsRollup(options.MetricsCacheRefreshInterval)
+
+	api.debugConnectionsCancel, err = api.Pubsub.Subscribe(debugConnectionsRequestChannel, api.handleDebugConnectionsRequest)
+	if err != nil {
+		api.Logger.Error(api.ctx, "subscribe to debug connections requests", slog.Error(err))
+	}
+
 	return api
 }
 
@@ -977,17 +1148,33 @@ type API struct {
 	WorkspaceClientCoordinateOverride atomic.Pointer[func(rw http.ResponseWriter) bool]
 	TailnetCoordinator                atomic.Pointer[tailnet.Coordinator]
 	QuotaCommitter                    atomic.Pointer[proto.QuotaCommitter]
+	// TailnetCoordinatorStatus describes the active TailnetCoordinator and
+	// whether it had to fall back to the in-memory coordinator.
+	TailnetCoordinatorStatus atomic.Pointer[healthcheck.CoordinatorStatus]
+	// Draining is set by Enterprise code when the replica is being drained.
+	// A draining replica doesn't accept new tailnet connections or hand out
+	// provisioner jobs, but existing connections are allowed to finish.
+	Draining atomic.Bool
 	// WorkspaceProxyHostsFn returns the hosts of healthy workspace proxies
 	// for header reasons.
 	WorkspaceProxyHostsFn atomic.Pointer[func() []string]
+	// WorkspaceProxiesStatusFn returns the number of total and healthy
+	// workspace proxies for the deployment status.
+	WorkspaceProxiesStatusFn atomic.Pointer[func() codersdk.DeploymentStatusProxies]
+	// ReplicaCountFn returns the number of replicas of the deployment. It's
+	// set by Enterprise code, AGPL deployments have one replica.
+	ReplicaCountFn atomic.Pointer[func() int]
 	// TemplateScheduleStore is a pointer to an atomic pointer because this is
 	// passed to another struct, and we want them all to be the same reference.
 	TemplateScheduleStore *atomic.Pointer[schedule.TemplateScheduleStore]
 	// UserQuietHoursScheduleStore is a pointer to an atomic pointer for the
 	// same reason as TemplateScheduleStore.
 	UserQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
-	// DERPMapper mutates the DERPMap to include workspace proxies.
-	DERPMapper atomic.Pointer[func(derpMap *tailcfg.DERPMap) *tailcfg.DERPMap]
+	// DERPMapper mutates the DERPMap to include workspace proxies. Proxies the
+	// actor in the context may not use are left out.
+	DERPMapper atomic.Pointer[func(ctx context.Context, derpMap *tailcfg.DERPMap) *tailcfg.DERPMap]
+	// PlatformEvents records domain events for the platform event stream.
+	PlatformEvents *platformevents.Publisher
 
 	HTTPAuth *HTTPAuthorizer
 
@@ -1002,6 +1189,8 @@ type API struct {
 	WebsocketWaitMutex sync.Mutex
 	WebsocketWaitGroup sync.WaitGroup
 	derpCloseFunc      func()
+	// DERPUsage accounts for the traffic relayed by the embedded DERP server.
+	DERPUsage *tailnet.DERPUsage
 
 	metricsCache          *metricscache.Cache
 	updateChecker         *updatecheck.Checker
@@ -1009,14 +1198,54 @@ type API struct {
 	workspaceAppServer    *workspaceapps.Server
 	agentProvider         workspaceapps.AgentProvider
 
+	// sshCA signs SSH certificates of users and workspace agents.
+	sshCA gossh.Signer
+
 	// Experiments contains the list of experiments currently enabled.
 	// This is used to gate features that are not yet ready for production.
 	Experiments codersdk.Experiments
+	// featureFlags maps client and agent feature flags to the percentage of
+	// users and agents they're rolled out to.
+	featureFlags map[string]int
 
 	healthCheckGroup *singleflight.Group[string, *healthcheck.Report]
 	healthCheckCache atomic.Pointer[healthcheck.Report]
 
+	// deploymentStatusCache holds the last deployment status, so the public
+	// status endpoint doesn't ping the database on every request.
+	deploymentStatusGroup *singleflight.Group[string, codersdk.DeploymentStatus]
+	deploymentStatusCache atomic.Pointer[codersdk.DeploymentStatus]
+
+	// agentAdmission paces agents fetching their manifest or connecting to
+	// the coordinator, so a reconnect storm doesn't overload the database.
+	agentAdmission *agentAdmission
+	// agentFirstConnectMetrics tracks how long agents take to connect after
+	// their build completed.
+	agentFirstConnectMetrics     *agentFirstConnectMetrics
+	agentFirstConnectMonitorDone chan struct{}
+
+	// templateBuildInsightsRollupDone is closed once the periodic rollup of
+	// template version builds stopped.
+	templateBuildInsightsRollupDone chan struct{}
+
+	// parameterOptions fetches the options of template parameters that are
+	// served by template-defined endpoints.
+	parameterOptions *parameteroptions.Fetcher
+
 	statsBatcher *batchstats.Batcher
+
+	// workspaceBuildQueueNotify receives the IDs of workspaces whose queued
+	// builds may be able to start.
+	workspaceBuildQueueNotify chan uuid.UUID
+	workspaceBuildQueueDone   chan struct{}
+
+	// connectedAgents and connectedClients count the agents and clients
+	// coordinating through this replica.
+	connectedAgents  atomic.Int64
+	connectedClients atomic.Int64
+	// debugConnectionsCancel stops answering requests of other replicas for
+	// the connections of this replica.
+	debugConnectionsCancel func()
 }
 
 // Close waits for all WebSocket connections to drain before returning.
@@ -1028,6 +1257,12 @@ func (api *API) Close() error {
 	api.WebsocketWaitGroup.Wait()
 	api.WebsocketWaitMutex.Unlock()
 
+	<-api.workspaceBuildQueueDone
+	<-api.agentFirstConnectMonitorDone
+	<-api.templateBuildInsightsRollupDone
+	if api.debugConnectionsCancel != nil {
+		api.debugConnectionsCancel()
+	}
 	api.metricsCache.Close()
 	if api.updateChecker != nil {
 		api.updateChecker.Close()
@@ -1108,11 +1343,13 @@ func (api *API) CreateInMemoryProvisionerDaemon(ctx context.Context, debounce ti
 		Pubsub:                      api.Pubsub,
 		Provisioners:                daemon.Provisioners,
 		GitAuthConfigs:              api.GitAuthConfigs,
+		SecretManager:               api.SecretManager,
 		Telemetry:                   api.Telemetry,
 		Tracer:                      tracer,
 		Tags:                        tags,
 		QuotaCommitter:              &api.QuotaCommitter,
 		Auditor:                     &api.Auditor,
+		Draining:                    &api.Draining,
 		TemplateScheduleStore:       api.TemplateScheduleStore,
 		UserQuietHoursScheduleStore: api.UserQuietHoursScheduleStore,
 		AcquireJobDebounce:          debounce,
@@ -1145,10 +1382,18 @@ func (api *API) CreateInMemoryProvisionerDaemon(ctx context.Context, debounce ti
 	return proto.NewDRPCProvisionerDaemonClient(clientSession), nil
 }
 
+// DERPMap returns the DERP map with every workspace proxy.
 func (api *API) DERPMap() *tailcfg.DERPMap {
+	return api.ActorDERPMap(context.Background())
+}
+
+// ActorDERPMap returns the DERP map for the actor in the context, leaving out
+// the workspace proxies they may not use. Every proxy is included when the
+// context has no actor.
+func (api *API) ActorDERPMap(ctx context.Context) *tailcfg.DERPMap {
 	fn := api.DERPMapper.Load()
 	if fn != nil {
-		return (*fn)(api.Options.BaseDERPMap)
+		return (*fn)(ctx, api.Options.BaseDERPMap)
 	}
 
 	return api.Options.BaseDERPMap
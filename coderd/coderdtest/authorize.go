@@ -432,6 +432,7 @@ func randomRBACType() string {
 		rbac.ResourceDeploymentValues.Type,
 		rbac.ResourceReplicas.Type,
 		rbac.ResourceDebugInfo.Type,
+		rbac.ResourcePlatformEvent.Type,
 	}
 	return all[must(cryptorand.Intn(len(all)))]
 }
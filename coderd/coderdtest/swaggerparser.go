@@ -302,8 +302,13 @@ func assertPathParametersDefined(t *testing.T, comment SwaggerComment) {
 func assertSecurityDefined(t *testing.T, comment SwaggerComment) {
 	if comment.router == "/updatecheck" ||
 		comment.router == "/buildinfo" ||
+		comment.router == "/deployment/status" ||
+		comment.router == "/deployment/ssh/ca" ||
 		comment.router == "/" ||
-		comment.router == "/users/login" {
+		comment.router == "/users/login" ||
+		comment.router == "/workspacewebhooks/{workspacewebhook}/trigger" ||
+		comment.router == "/workspaceapprovals/{workspaceapproval}/callback" ||
+		comment.router == "/applications/jwks" {
 		return // endpoints do not require authorization
 	}
 	assert.Equal(t, "CoderSessionToken", comment.security, "@Security must be equal CoderSessionToken")
@@ -333,7 +338,7 @@ func assertAccept(t *testing.T, comment SwaggerComment) {
 	}
 }
 
-var allowedProduceTypes = []string{"json", "text/event-stream", "text/html"}
+var allowedProduceTypes = []string{"json", "text/event-stream", "text/html", "application/zip"}
 
 func assertProduce(t *testing.T, comment SwaggerComment) {
 	var hasResponseModel bool
@@ -352,7 +357,8 @@ func assertProduce(t *testing.T, comment SwaggerComment) {
 			(comment.router == "/workspaceagents/me/startup" && comment.method == "post") ||
 			(comment.router == "/workspaceagents/me/startup/logs" && comment.method == "patch") ||
 			(comment.router == "/licenses/{id}" && comment.method == "delete") ||
-			(comment.router == "/debug/coordinator" && comment.method == "get") {
+			(comment.router == "/workspaceproxies/me/jwks" && comment.method == "get") ||
+			(comment.router == "/applications/jwks" && comment.method == "get") {
 			return // Exception: HTTP 200 is returned without response entity
 		}
 
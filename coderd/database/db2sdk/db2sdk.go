@@ -35,6 +35,11 @@ func TemplateVersionParameter(param database.TemplateVersionParameter) (codersdk
 		return codersdk.TemplateVersionParameter{}, err
 	}
 
+	dependencies, err := templateVersionParameterDependencies(param.Dependencies)
+	if err != nil {
+		return codersdk.TemplateVersionParameter{}, err
+	}
+
 	descriptionPlaintext, err := parameter.Plaintext(param.Description)
 	if err != nil {
 		return codersdk.TemplateVersionParameter{}, err
@@ -67,6 +72,8 @@ func TemplateVersionParameter(param database.TemplateVersionParameter) (codersdk
 		ValidationMonotonic:  codersdk.ValidationMonotonicOrder(param.ValidationMonotonic),
 		Required:             param.Required,
 		Ephemeral:            param.Ephemeral,
+		Dependencies:         dependencies,
+		DynamicOptions:       param.OptionsURL != "",
 	}, nil
 }
 
@@ -83,7 +90,9 @@ func ProvisionerJobStatus(provisionerJob database.ProvisionerJob) codersdk.Provi
 			return codersdk.ProvisionerJobCanceled
 		}
 		return codersdk.ProvisionerJobFailed
-	case !provisionerJob.StartedAt.Valid:
+	case !provisionerJob.StartedAt.Valid && !provisionerJob.CompletedAt.Valid:
+		// Jobs can be completed without being started, for example when
+		// their workspace approval is rejected.
 		return codersdk.ProvisionerJobPending
 	case provisionerJob.CompletedAt.Valid:
 		if provisionerJob.Error.String == "" {
@@ -164,6 +173,20 @@ func TemplateInsightsParameters(parameterRows []database.GetTemplateParameterIns
 	return parametersUsage, nil
 }
 
+// PortPolicy decodes the port policy of a template. Templates without one
+// allow all ports.
+func PortPolicy(rawPolicy json.RawMessage) (codersdk.PortPolicy, error) {
+	var policy codersdk.PortPolicy
+	if len(rawPolicy) == 0 {
+		return policy, nil
+	}
+	err := json.Unmarshal(rawPolicy, &policy)
+	if err != nil {
+		return codersdk.PortPolicy{}, err
+	}
+	return policy, nil
+}
+
 func templateVersionParameterOptions(rawOptions json.RawMessage) ([]codersdk.TemplateVersionParameterOption, error) {
 	var protoOptions []*proto.RichParameterOption
 	err := json.Unmarshal(rawOptions, &protoOptions)
@@ -181,3 +204,23 @@ func templateVersionParameterOptions(rawOptions json.RawMessage) ([]codersdk.Tem
 	}
 	return options, nil
 }
+
+func templateVersionParameterDependencies(rawDependencies json.RawMessage) ([]codersdk.TemplateVersionParameterDependency, error) {
+	if len(rawDependencies) == 0 {
+		return nil, nil
+	}
+	var protoDependencies []*proto.RichParameterDependency
+	err := json.Unmarshal(rawDependencies, &protoDependencies)
+	if err != nil {
+		return nil, err
+	}
+	var dependencies []codersdk.TemplateVersionParameterDependency
+	for _, dependency := range protoDependencies {
+		dependencies = append(dependencies, codersdk.TemplateVersionParameterDependency{
+			Parameter: dependency.Parameter,
+			Values:    dependency.Values,
+			Options:   dependency.Options,
+		})
+	}
+	return dependencies, nil
+}
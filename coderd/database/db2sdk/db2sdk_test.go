@@ -67,6 +67,17 @@ func TestProvisionerJobStatus(t *testing.T) {
 			job:    database.ProvisionerJob{},
 			status: codersdk.ProvisionerJobPending,
 		},
+		{
+			name: "failed_without_starting",
+			job: database.ProvisionerJob{
+				CompletedAt: sql.NullTime{
+					Time:  database.Now().Add(-30 * time.Second),
+					Valid: true,
+				},
+				Error: sql.NullString{String: "rejected", Valid: true},
+			},
+			status: codersdk.ProvisionerJobFailed,
+		},
 		{
 			name: "succeeded",
 			job: database.ProvisionerJob{
@@ -134,10 +145,20 @@ func TestTemplateVersionParameter_OK(t *testing.T) {
 	}
 	ob, err := json.Marshal(&options)
 	req.NoError(err)
+	dependencies := []*proto.RichParameterDependency{
+		{
+			Parameter: "album",
+			Values:    []string{"Ziggy Stardust"},
+			Options:   []string{"baz"},
+		},
+	}
+	depb, err := json.Marshal(&dependencies)
+	req.NoError(err)
 
 	db := database.TemplateVersionParameter{
-		Options:     json.RawMessage(ob),
-		Description: "_The Rise and Fall of **Ziggy Stardust** and the Spiders from Mars_",
+		Options:      json.RawMessage(ob),
+		Dependencies: json.RawMessage(depb),
+		Description:  "_The Rise and Fall of **Ziggy Stardust** and the Spiders from Mars_",
 	}
 	sdk, err := db2sdk.TemplateVersionParameter(db)
 	req.NoError(err)
@@ -146,6 +167,10 @@ func TestTemplateVersionParameter_OK(t *testing.T) {
 	req.Equal("bar", sdk.Options[0].Description)
 	req.Equal("baz", sdk.Options[0].Value)
 	req.Equal("David Bowie", sdk.Options[0].Icon)
+	req.Len(sdk.Dependencies, 1)
+	req.Equal("album", sdk.Dependencies[0].Parameter)
+	req.Equal([]string{"Ziggy Stardust"}, sdk.Dependencies[0].Values)
+	req.Equal([]string{"baz"}, sdk.Dependencies[0].Options)
 	req.Equal("The Rise and Fall of Ziggy Stardust and the Spiders from Mars", sdk.DescriptionPlaintext)
 }
 
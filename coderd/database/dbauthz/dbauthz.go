@@ -197,6 +197,42 @@ var (
 		Scope: rbac.ScopeAll,
 	}.WithCachedASTValue()
 
+	// See the template version promotion decision endpoint.
+	subjectTemplatePromoter = rbac.Subject{
+		ID: uuid.Nil.String(),
+		Roles: rbac.Roles([]rbac.Role{
+			{
+				Name:        "templatepromoter",
+				DisplayName: "Template Promoter",
+				Site: rbac.Permissions(map[string][]rbac.Action{
+					rbac.ResourceTemplate.Type: {rbac.ActionRead, rbac.ActionPushVersion},
+				}),
+				Org:  map[string][]rbac.Permission{},
+				User: []rbac.Permission{},
+			},
+		}),
+		Scope: rbac.ScopeAll,
+	}.WithCachedASTValue()
+
+	subjectWorkspaceApprover = rbac.Subject{
+		ID: uuid.Nil.String(),
+		Roles: rbac.Roles([]rbac.Role{
+			{
+				Name:        "workspaceapprover",
+				DisplayName: "Workspace Approver",
+				Site: rbac.Permissions(map[string][]rbac.Action{
+					rbac.ResourceWildcard.Type: {rbac.ActionRead},
+					// Deciding an approval request is limited to those who may
+					// update the template of the workspace.
+					rbac.ResourceTemplate.Type: {rbac.ActionRead, rbac.ActionUpdate},
+				}),
+				Org:  map[string][]rbac.Permission{},
+				User: []rbac.Permission{},
+			},
+		}),
+		Scope: rbac.ScopeAll,
+	}.WithCachedASTValue()
+
 	subjectSystemRestricted = rbac.Subject{
 		ID: uuid.Nil.String(),
 		Roles: rbac.Roles([]rbac.Role{
@@ -206,7 +242,8 @@ var (
 				Site: rbac.Permissions(map[string][]rbac.Action{
 					rbac.ResourceWildcard.Type:           {rbac.ActionRead},
 					rbac.ResourceAPIKey.Type:             {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
-					rbac.ResourceGroup.Type:              {rbac.ActionCreate, rbac.ActionUpdate},
+					rbac.ResourceGroup.Type:              {rbac.ActionCreate, rbac.ActionUpdate, rbac.ActionDelete},
+					rbac.ResourceLicense.Type:            {rbac.ActionCreate, rbac.ActionDelete},
 					rbac.ResourceRoleAssignment.Type:     {rbac.ActionCreate, rbac.ActionDelete},
 					rbac.ResourceSystem.Type:             {rbac.WildcardSymbol},
 					rbac.ResourceOrganization.Type:       {rbac.ActionCreate},
@@ -245,6 +282,18 @@ func AsHangDetector(ctx context.Context) context.Context {
 	return context.WithValue(ctx, authContextKey{}, subjectHangDetector)
 }
 
+// AsTemplatePromoter returns a context with an actor that has permissions
+// required to activate a template version once its promotion is approved.
+func AsTemplatePromoter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authContextKey{}, subjectTemplatePromoter)
+}
+
+// AsWorkspaceApprover returns a context with an actor that has permissions
+// required to decide workspace approval requests from a signed callback.
+func AsWorkspaceApprover(ctx context.Context) context.Context {
+	return context.WithValue(ctx, authContextKey{}, subjectWorkspaceApprover)
+}
+
 // AsSystemRestricted returns a context with an actor that has permissions
 // required for various system operations (login, logout, metrics cache).
 func AsSystemRestricted(ctx context.Context) context.Context {
@@ -635,6 +684,46 @@ func authorizedTemplateVersionFromJob(ctx context.Context, q *querier, job datab
 	}
 }
 
+// getWorkspaceExternalMetadatum returns the metadata stored under the namespace
+// of the workspace without authorizing it, if there is any.
+func (q *querier) getWorkspaceExternalMetadatum(ctx context.Context, workspaceID uuid.UUID, namespace string) (database.GetWorkspaceExternalMetadataByWorkspaceIDsRow, bool, error) {
+	metadata, err := q.db.GetWorkspaceExternalMetadataByWorkspaceIDs(ctx, []uuid.UUID{workspaceID})
+	if err != nil {
+		return database.GetWorkspaceExternalMetadataByWorkspaceIDsRow{}, false, err
+	}
+	for _, metadatum := range metadata {
+		if metadatum.Namespace == namespace {
+			return metadatum, true, nil
+		}
+	}
+	return database.GetWorkspaceExternalMetadataByWorkspaceIDsRow{}, false, nil
+}
+
+// authorizeWorkspaceWebhook authorizes access to the webhooks of a workspace.
+// Webhooks hold the secret that lets anyone change the state of the workspace,
+// so even reading them requires permission to update it.
+func (q *querier) authorizeWorkspaceWebhook(ctx context.Context, workspaceID uuid.UUID) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	return q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+}
+
+// authorizeManagedEnvironmentVariable authorizes access to the managed
+// environment variables of a template or, if templateID is null, of an
+// organization.
+func (q *querier) authorizeManagedEnvironmentVariable(ctx context.Context, action rbac.Action, organizationID uuid.UUID, templateID uuid.NullUUID) error {
+	if !templateID.Valid {
+		return q.authorizeContext(ctx, action, database.Organization{ID: organizationID})
+	}
+	template, err := q.db.GetTemplateByID(ctx, templateID.UUID)
+	if err != nil {
+		return err
+	}
+	return q.authorizeContext(ctx, action, template)
+}
+
 func (q *querier) AcquireLock(ctx context.Context, id int64) error {
 	return q.db.AcquireLock(ctx, id)
 }
@@ -678,6 +767,13 @@ func (q *querier) DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, u
 	return q.db.DeleteApplicationConnectAPIKeysByUserID(ctx, userID)
 }
 
+func (q *querier) DeleteAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteAuditLogsByIDs(ctx, ids)
+}
+
 func (q *querier) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceTailnetCoordinator); err != nil {
 		return err
@@ -693,6 +789,14 @@ func (q *querier) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
 	return deleteQ(q.log, q.auth, q.db.GetGroupByID, q.db.DeleteGroupByID)(ctx, id)
 }
 
+func (q *querier) DeleteGroupDefaultTemplates(ctx context.Context, groupID uuid.UUID) error {
+	// Changing the default templates of a group counts as updating the group.
+	fetch := func(ctx context.Context, groupID uuid.UUID) (database.Group, error) {
+		return q.db.GetGroupByID(ctx, groupID)
+	}
+	return update(q.log, q.auth, fetch, q.db.DeleteGroupDefaultTemplates)(ctx, groupID)
+}
+
 func (q *querier) DeleteGroupMemberFromGroup(ctx context.Context, arg database.DeleteGroupMemberFromGroupParams) error {
 	// Deleting a group member counts as updating a group.
 	fetch := func(ctx context.Context, arg database.DeleteGroupMemberFromGroupParams) (database.Group, error) {
@@ -722,6 +826,52 @@ func (q *querier) DeleteLicense(ctx context.Context, id int32) (int32, error) {
 	return id, nil
 }
 
+func (q *querier) DeleteManagedEnvironmentVariableByID(ctx context.Context, id uuid.UUID) error {
+	variable, err := q.db.GetManagedEnvironmentVariableByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeManagedEnvironmentVariable(ctx, rbac.ActionUpdate, variable.OrganizationID, variable.TemplateID); err != nil {
+		return err
+	}
+	return q.db.DeleteManagedEnvironmentVariableByID(ctx, id)
+}
+
+func (q *querier) DeleteOldNetcheckReports(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldNetcheckReports(ctx)
+}
+
+func (q *querier) DeleteOldPlatformEvents(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldPlatformEvents(ctx)
+}
+
+func (q *querier) DeleteOldWorkspaceAgentAuthTokenRotations(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceAgentAuthTokenRotations(ctx)
+}
+
+func (q *querier) DeleteOldWorkspaceAgentBandwidthStats(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceAgentBandwidthStats(ctx)
+}
+
+func (q *querier) DeleteOldWorkspaceAgentClientConnections(ctx context.Context) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteOldWorkspaceAgentClientConnections(ctx)
+}
+
 func (q *querier) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
 		return err
@@ -757,6 +907,102 @@ func (q *querier) DeleteTailnetClient(ctx context.Context, arg database.DeleteTa
 	return q.db.DeleteTailnetClient(ctx, arg)
 }
 
+func (q *querier) DeleteUserDeprovision(ctx context.Context, userID uuid.UUID) error {
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteUserDeprovision(ctx, userID)
+}
+
+func (q *querier) DeleteUserPTYSessionOverride(ctx context.Context, userID uuid.UUID) error {
+	err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserObject(userID))
+	if err != nil {
+		return err
+	}
+	return q.db.DeleteUserPTYSessionOverride(ctx, userID)
+}
+
+func (q *querier) DeleteUserQuotaOverride(ctx context.Context, userID uuid.UUID) error {
+	err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserObject(userID))
+	if err != nil {
+		return err
+	}
+	return q.db.DeleteUserQuotaOverride(ctx, userID)
+}
+
+func (q *querier) DeleteWorkspaceBuildQueueEntriesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceBuildQueueEntriesByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) DeleteWorkspaceBuildQueueEntryByID(ctx context.Context, id uuid.UUID) error {
+	// Entries are only removed by the system once their build is created.
+	if err := q.authorizeContext(ctx, rbac.ActionDelete, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceBuildQueueEntryByID(ctx, id)
+}
+
+func (q *querier) DeleteWorkspaceExternalMetadatum(ctx context.Context, arg database.DeleteWorkspaceExternalMetadatumParams) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	object := workspace.RBACObject()
+	existing, ok, err := q.getWorkspaceExternalMetadatum(ctx, arg.WorkspaceID, arg.Namespace)
+	if err != nil {
+		return err
+	}
+	if ok {
+		object = existing.RBACObject()
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, object); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceExternalMetadatum(ctx, arg)
+}
+
+func (q *querier) DeleteWorkspacePreviousName(ctx context.Context, arg database.DeleteWorkspacePreviousNameParams) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspacePreviousName(ctx, arg)
+}
+
+func (q *querier) DeleteWorkspaceWebhookByID(ctx context.Context, id uuid.UUID) error {
+	webhook, err := q.db.GetWorkspaceWebhookByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeWorkspaceWebhook(ctx, webhook.WorkspaceID); err != nil {
+		return err
+	}
+	return q.db.DeleteWorkspaceWebhookByID(ctx, id)
+}
+
+func (q *querier) ExpireWorkspaceAgentAuthTokenRotations(ctx context.Context, arg database.ExpireWorkspaceAgentAuthTokenRotationsParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.ExpireWorkspaceAgentAuthTokenRotations(ctx, arg)
+}
+
 func (q *querier) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	return fetch(q.log, q.auth, q.db.GetAPIKeyByID)(ctx, id)
 }
@@ -811,6 +1057,20 @@ func (q *querier) GetAppSecurityKey(ctx context.Context) (string, error) {
 	return q.db.GetAppSecurityKey(ctx)
 }
 
+func (q *querier) GetAuditLogArchivalByID(ctx context.Context, id uuid.UUID) (database.AuditLogArchival, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return database.AuditLogArchival{}, err
+	}
+	return q.db.GetAuditLogArchivalByID(ctx, id)
+}
+
+func (q *querier) GetAuditLogArchivals(ctx context.Context, rowLimit int32) ([]database.AuditLogArchival, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogArchivals(ctx, rowLimit)
+}
+
 func (q *querier) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	// To optimize audit logs, we only check the global audit log permission once.
 	// This is because we expect a large unbounded set of audit logs, and applying a SQL
@@ -821,6 +1081,13 @@ func (q *querier) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditL
 	return q.db.GetAuditLogsOffset(ctx, arg)
 }
 
+func (q *querier) GetAuditLogsOlderThan(ctx context.Context, arg database.GetAuditLogsOlderThanParams) ([]database.AuditLog, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceAuditLog); err != nil {
+		return nil, err
+	}
+	return q.db.GetAuditLogsOlderThan(ctx, arg)
+}
+
 func (q *querier) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (database.GetAuthorizationUserRolesRow, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return database.GetAuthorizationUserRolesRow{}, err
@@ -861,6 +1128,13 @@ func (q *querier) GetDeploymentWorkspaceStats(ctx context.Context) (database.Get
 	return q.db.GetDeploymentWorkspaceStats(ctx)
 }
 
+func (q *querier) GetEntitlementSnapshots(ctx context.Context, arg database.GetEntitlementSnapshotsParams) ([]database.EntitlementSnapshot, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceLicense); err != nil {
+		return nil, err
+	}
+	return q.db.GetEntitlementSnapshots(ctx, arg)
+}
+
 func (q *querier) GetFileByHashAndCreator(ctx context.Context, arg database.GetFileByHashAndCreatorParams) (database.File, error) {
 	file, err := q.db.GetFileByHashAndCreator(ctx, arg)
 	if err != nil {
@@ -916,6 +1190,13 @@ func (q *querier) GetGroupByOrgAndName(ctx context.Context, arg database.GetGrou
 	return fetch(q.log, q.auth, q.db.GetGroupByOrgAndName)(ctx, arg)
 }
 
+func (q *querier) GetGroupDefaultTemplates(ctx context.Context, groupID uuid.UUID) ([]database.GroupDefaultTemplate, error) {
+	if _, err := q.GetGroupByID(ctx, groupID); err != nil { // AuthZ check
+		return nil, err
+	}
+	return q.db.GetGroupDefaultTemplates(ctx, groupID)
+}
+
 func (q *querier) GetGroupMembers(ctx context.Context, id uuid.UUID) ([]database.User, error) {
 	if _, err := q.GetGroupByID(ctx, id); err != nil { // AuthZ check
 		return nil, err
@@ -923,6 +1204,13 @@ func (q *querier) GetGroupMembers(ctx context.Context, id uuid.UUID) ([]database
 	return q.db.GetGroupMembers(ctx, id)
 }
 
+func (q *querier) GetGroupMembersAnyStatus(ctx context.Context, groupID uuid.UUID) ([]database.User, error) {
+	if _, err := q.GetGroupByID(ctx, groupID); err != nil { // AuthZ check
+		return nil, err
+	}
+	return q.db.GetGroupMembersAnyStatus(ctx, groupID)
+}
+
 func (q *querier) GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.Group, error) {
 	return fetchWithPostFilter(q.auth, q.db.GetGroupsByOrganizationID)(ctx, organizationID)
 }
@@ -942,6 +1230,13 @@ func (q *querier) GetLastUpdateCheck(ctx context.Context) (string, error) {
 	return q.db.GetLastUpdateCheck(ctx)
 }
 
+func (q *querier) GetLatestWorkspaceApprovalByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceApproval, error) {
+	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+	return q.db.GetLatestWorkspaceApprovalByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
 	if _, err := q.GetWorkspaceByID(ctx, workspaceID); err != nil {
 		return database.WorkspaceBuild{}, err
@@ -973,6 +1268,13 @@ func (q *querier) GetLicenseByID(ctx context.Context, id int32) (database.Licens
 	return fetch(q.log, q.auth, q.db.GetLicenseByID)(ctx, id)
 }
 
+func (q *querier) GetLicenseUsage(ctx context.Context, arg database.GetLicenseUsageParams) ([]database.LicenseUsage, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceLicense); err != nil {
+		return nil, err
+	}
+	return q.db.GetLicenseUsage(ctx, arg)
+}
+
 func (q *querier) GetLicenses(ctx context.Context) ([]database.License, error) {
 	fetch := func(ctx context.Context, _ interface{}) ([]database.License, error) {
 		return q.db.GetLicenses(ctx)
@@ -985,6 +1287,47 @@ func (q *querier) GetLogoURL(ctx context.Context) (string, error) {
 	return q.db.GetLogoURL(ctx)
 }
 
+func (q *querier) GetManagedEnvironmentVariableByID(ctx context.Context, id uuid.UUID) (database.ManagedEnvironmentVariable, error) {
+	variable, err := q.db.GetManagedEnvironmentVariableByID(ctx, id)
+	if err != nil {
+		return database.ManagedEnvironmentVariable{}, err
+	}
+	if err := q.authorizeManagedEnvironmentVariable(ctx, rbac.ActionRead, variable.OrganizationID, variable.TemplateID); err != nil {
+		return database.ManagedEnvironmentVariable{}, err
+	}
+	return variable, nil
+}
+
+func (q *querier) GetManagedEnvironmentVariablesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.ManagedEnvironmentVariable, error) {
+	if err := q.authorizeManagedEnvironmentVariable(ctx, rbac.ActionRead, organizationID, uuid.NullUUID{}); err != nil {
+		return nil, err
+	}
+	return q.db.GetManagedEnvironmentVariablesByOrganizationID(ctx, organizationID)
+}
+
+func (q *querier) GetManagedEnvironmentVariablesByTemplateID(ctx context.Context, templateID uuid.NullUUID) ([]database.ManagedEnvironmentVariable, error) {
+	if templateID.Valid {
+		// Authorized fetch
+		if _, err := q.GetTemplateByID(ctx, templateID.UUID); err != nil {
+			return nil, err
+		}
+	}
+	return q.db.GetManagedEnvironmentVariablesByTemplateID(ctx, templateID)
+}
+
+func (q *querier) GetNetcheckReports(ctx context.Context, arg database.GetNetcheckReportsParams) ([]database.NetcheckReport, error) {
+	// Users may read their own reports, reading the reports of every user
+	// requires access to the deployment stats.
+	object := rbac.ResourceDeploymentStats
+	if arg.UserID != uuid.Nil {
+		object = rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionRead, object); err != nil {
+		return nil, err
+	}
+	return q.db.GetNetcheckReports(ctx, arg)
+}
+
 func (q *querier) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return "", err
@@ -1025,6 +1368,10 @@ func (q *querier) GetOrganizationsByUserID(ctx context.Context, userID uuid.UUID
 	return fetchWithPostFilter(q.auth, q.db.GetOrganizationsByUserID)(ctx, userID)
 }
 
+func (q *querier) GetOutdatedWorkspacesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.Workspace, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetOutdatedWorkspacesByTemplateID)(ctx, templateID)
+}
+
 func (q *querier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	version, err := q.db.GetTemplateVersionByJobID(ctx, jobID)
 	if err != nil {
@@ -1046,6 +1393,30 @@ func (q *querier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUI
 	return q.db.GetParameterSchemasByJobID(ctx, jobID)
 }
 
+func (q *querier) GetPendingGroupDefaultTemplateWorkspaces(ctx context.Context) ([]database.GetPendingGroupDefaultTemplateWorkspacesRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetPendingGroupDefaultTemplateWorkspaces(ctx)
+}
+
+func (q *querier) GetPendingProvisionerJobs(ctx context.Context) ([]database.ProvisionerJob, error) {
+	// Matching pending jobs to daemons is part of operating provisioner
+	// daemons. All users can see the daemons, but the jobs belong to other
+	// users, so only those who can manage the daemons may inspect them.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceProvisionerDaemon); err != nil {
+		return nil, err
+	}
+	return q.db.GetPendingProvisionerJobs(ctx)
+}
+
+func (q *querier) GetPlatformEventsAfterID(ctx context.Context, arg database.GetPlatformEventsAfterIDParams) ([]database.PlatformEvent, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourcePlatformEvent); err != nil {
+		return nil, err
+	}
+	return q.db.GetPlatformEventsAfterID(ctx, arg)
+}
+
 func (q *querier) GetPreviousTemplateVersion(ctx context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	// An actor can read the previous template version if they can read the related template.
 	// If no linked template exists, we check if the actor can read *a* template.
@@ -1067,6 +1438,15 @@ func (q *querier) GetProvisionerDaemons(ctx context.Context) ([]database.Provisi
 	return fetchWithPostFilter(q.auth, fetch)(ctx, nil)
 }
 
+func (q *querier) GetProvisionerJobBuildHooksByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobBuildHook, error) {
+	// Authorized read on job lets the actor also read its hooks.
+	_, err := q.GetProvisionerJobByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobBuildHooksByJobID(ctx, jobID)
+}
+
 func (q *querier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (database.ProvisionerJob, error) {
 	job, err := q.db.GetProvisionerJobByID(ctx, id)
 	if err != nil {
@@ -1095,6 +1475,15 @@ func (q *querier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (data
 }
 
 // TODO: we need to add a provisioner job resource
+func (q *querier) GetProvisionerJobConcurrencyLimit(ctx context.Context, id uuid.UUID) (string, error) {
+	// Counts the running jobs of every user, so only provisioner daemons may
+	// check it while acquiring a job.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return "", err
+	}
+	return q.db.GetProvisionerJobConcurrencyLimit(ctx, id)
+}
+
 func (q *querier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.ProvisionerJob, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 	// 	return nil, err
@@ -1115,6 +1504,15 @@ func (q *querier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt
 	return q.db.GetProvisionerJobsCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetProvisionerJobsStartedAfter(ctx context.Context, startedAfter time.Time) ([]database.ProvisionerJob, error) {
+	// Like pending jobs, the jobs belong to other users, so only those who
+	// can manage provisioner daemons may measure the queue.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceProvisionerDaemon); err != nil {
+		return nil, err
+	}
+	return q.db.GetProvisionerJobsStartedAfter(ctx, startedAfter)
+}
+
 func (q *querier) GetProvisionerLogsAfterID(ctx context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	// Authorized read on job lets the actor also read the logs.
 	_, err := q.GetProvisionerJobByID(ctx, arg.JobID)
@@ -1124,12 +1522,12 @@ func (q *querier) GetProvisionerLogsAfterID(ctx context.Context, arg database.Ge
 	return q.db.GetProvisionerLogsAfterID(ctx, arg)
 }
 
-func (q *querier) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+func (q *querier) GetQuotaBudgetsForUser(ctx context.Context, userID uuid.UUID) ([]database.Group, error) {
 	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(userID))
 	if err != nil {
-		return -1, err
+		return nil, err
 	}
-	return q.db.GetQuotaAllowanceForUser(ctx, userID)
+	return q.db.GetQuotaBudgetsForUser(ctx, userID)
 }
 
 func (q *querier) GetQuotaConsumedForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
@@ -1140,6 +1538,14 @@ func (q *querier) GetQuotaConsumedForUser(ctx context.Context, userID uuid.UUID)
 	return q.db.GetQuotaConsumedForUser(ctx, userID)
 }
 
+func (q *querier) GetQuotaConsumedWorkspacesForUser(ctx context.Context, ownerID uuid.UUID) ([]database.GetQuotaConsumedWorkspacesForUserRow, error) {
+	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(ownerID))
+	if err != nil {
+		return nil, err
+	}
+	return q.db.GetQuotaConsumedWorkspacesForUser(ctx, ownerID)
+}
+
 func (q *querier) GetReplicaByID(ctx context.Context, id uuid.UUID) (database.Replica, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return database.Replica{}, err
@@ -1154,6 +1560,18 @@ func (q *querier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Ti
 	return q.db.GetReplicasUpdatedAfter(ctx, updatedAt)
 }
 
+func (q *querier) GetRuntimeExperiments(ctx context.Context) (string, error) {
+	// No authz checks, every user evaluates the experiments targeted at them.
+	return q.db.GetRuntimeExperiments(ctx)
+}
+
+func (q *querier) GetSSHCAKey(ctx context.Context) (string, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return "", err
+	}
+	return q.db.GetSSHCAKey(ctx)
+}
+
 func (q *querier) GetServiceBanner(ctx context.Context) (string, error) {
 	// No authz checks
 	return q.db.GetServiceBanner(ctx)
@@ -1173,6 +1591,25 @@ func (q *querier) GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UU
 	return q.db.GetTailnetClientsForAgent(ctx, agentID)
 }
 
+func (q *querier) GetTemplateAgentFirstConnectInsights(ctx context.Context, arg database.GetTemplateAgentFirstConnectInsightsParams) ([]database.GetTemplateAgentFirstConnectInsightsRow, error) {
+	for _, templateID := range arg.TemplateIDs {
+		template, err := q.db.GetTemplateByID(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+			return nil, err
+		}
+	}
+	if len(arg.TemplateIDs) == 0 {
+		if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceTemplate.All()); err != nil {
+			return nil, err
+		}
+	}
+	return q.db.GetTemplateAgentFirstConnectInsights(ctx, arg)
+}
+
 func (q *querier) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	for _, templateID := range arg.TemplateIDs {
 		template, err := q.db.GetTemplateByID(ctx, templateID)
@@ -1193,6 +1630,33 @@ func (q *querier) GetTemplateAppInsights(ctx context.Context, arg database.GetTe
 }
 
 // Only used by metrics cache.
+func (q *querier) GetTemplateAccessRequestByID(ctx context.Context, id uuid.UUID) (database.TemplateAccessRequest, error) {
+	request, err := q.db.GetTemplateAccessRequestByID(ctx, id)
+	if err != nil {
+		return database.TemplateAccessRequest{}, err
+	}
+	template, err := q.db.GetTemplateByID(ctx, request.TemplateID)
+	if err != nil {
+		return database.TemplateAccessRequest{}, err
+	}
+	// Access requests are decided by the users that manage the template ACL.
+	if err := q.authorizeContext(ctx, rbac.ActionManageACL, template); err != nil {
+		return database.TemplateAccessRequest{}, err
+	}
+	return request, nil
+}
+
+func (q *querier) GetTemplateAccessRequestsByTemplateID(ctx context.Context, arg database.GetTemplateAccessRequestsByTemplateIDParams) ([]database.TemplateAccessRequest, error) {
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionManageACL, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateAccessRequestsByTemplateID(ctx, arg)
+}
+
 func (q *querier) GetTemplateAverageBuildTime(ctx context.Context, arg database.GetTemplateAverageBuildTimeParams) (database.GetTemplateAverageBuildTimeRow, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
 		return database.GetTemplateAverageBuildTimeRow{}, err
@@ -1200,6 +1664,25 @@ func (q *querier) GetTemplateAverageBuildTime(ctx context.Context, arg database.
 	return q.db.GetTemplateAverageBuildTime(ctx, arg)
 }
 
+func (q *querier) GetTemplateBuildSLOInsights(ctx context.Context, arg database.GetTemplateBuildSLOInsightsParams) ([]database.GetTemplateBuildSLOInsightsRow, error) {
+	for _, templateID := range arg.TemplateIDs {
+		template, err := q.db.GetTemplateByID(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+			return nil, err
+		}
+	}
+	if len(arg.TemplateIDs) == 0 {
+		if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceTemplate.All()); err != nil {
+			return nil, err
+		}
+	}
+	return q.db.GetTemplateBuildSLOInsights(ctx, arg)
+}
+
 func (q *querier) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	return fetch(q.log, q.auth, q.db.GetTemplateByID)(ctx, id)
 }
@@ -1273,6 +1756,17 @@ func (q *querier) GetTemplateParameterInsights(ctx context.Context, arg database
 	return q.db.GetTemplateParameterInsights(ctx, arg)
 }
 
+func (q *querier) GetTemplateVersionBuildInsights(ctx context.Context, templateID uuid.UUID) ([]database.GetTemplateVersionBuildInsightsRow, error) {
+	template, err := q.db.GetTemplateByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return nil, err
+	}
+	return q.db.GetTemplateVersionBuildInsights(ctx, templateID)
+}
+
 func (q *querier) GetTemplateVersionByID(ctx context.Context, tvid uuid.UUID) (database.TemplateVersion, error) {
 	tv, err := q.db.GetTemplateVersionByID(ctx, tvid)
 	if err != nil {
@@ -1348,6 +1842,14 @@ func (q *querier) GetTemplateVersionParameters(ctx context.Context, templateVers
 	return q.db.GetTemplateVersionParameters(ctx, templateVersionID)
 }
 
+func (q *querier) GetTemplateVersionPromotionByID(ctx context.Context, id uuid.UUID) (database.TemplateVersionPromotion, error) {
+	return fetch(q.log, q.auth, q.db.GetTemplateVersionPromotionByID)(ctx, id)
+}
+
+func (q *querier) GetTemplateVersionPromotions(ctx context.Context, arg database.GetTemplateVersionPromotionsParams) ([]database.TemplateVersionPromotion, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetTemplateVersionPromotions)(ctx, arg)
+}
+
 func (q *querier) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	tv, err := q.db.GetTemplateVersionByID(ctx, templateVersionID)
 	if err != nil {
@@ -1424,6 +1926,25 @@ func (q *querier) GetUnexpiredLicenses(ctx context.Context) ([]database.License,
 	return q.db.GetUnexpiredLicenses(ctx)
 }
 
+func (q *querier) GetUserBandwidthInsights(ctx context.Context, arg database.GetUserBandwidthInsightsParams) ([]database.GetUserBandwidthInsightsRow, error) {
+	for _, templateID := range arg.TemplateIDs {
+		template, err := q.db.GetTemplateByID(ctx, templateID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+			return nil, err
+		}
+	}
+	if len(arg.TemplateIDs) == 0 {
+		if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceTemplate.All()); err != nil {
+			return nil, err
+		}
+	}
+	return q.db.GetUserBandwidthInsights(ctx, arg)
+}
+
 func (q *querier) GetUserByEmailOrUsername(ctx context.Context, arg database.GetUserByEmailOrUsernameParams) (database.User, error) {
 	return fetch(q.log, q.auth, q.db.GetUserByEmailOrUsername)(ctx, arg)
 }
@@ -1439,6 +1960,13 @@ func (q *querier) GetUserCount(ctx context.Context) (int64, error) {
 	return q.db.GetUserCount(ctx)
 }
 
+func (q *querier) GetUserDeprovisions(ctx context.Context) ([]database.UserDeprovision, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserDeprovisions(ctx)
+}
+
 func (q *querier) GetUserLatencyInsights(ctx context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	for _, templateID := range arg.TemplateIDs {
 		template, err := q.db.GetTemplateByID(ctx, templateID)
@@ -1472,6 +2000,29 @@ func (q *querier) GetUserLinkByUserIDLoginType(ctx context.Context, arg database
 	return q.db.GetUserLinkByUserIDLoginType(ctx, arg)
 }
 
+func (q *querier) GetUserPTYSessionOverride(ctx context.Context, userID uuid.UUID) (database.UserPTYSessionOverride, error) {
+	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(userID))
+	if err != nil {
+		return database.UserPTYSessionOverride{}, err
+	}
+	return q.db.GetUserPTYSessionOverride(ctx, userID)
+}
+
+func (q *querier) GetUserQuotaOverride(ctx context.Context, userID uuid.UUID) (database.UserQuotaOverride, error) {
+	err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserObject(userID))
+	if err != nil {
+		return database.UserQuotaOverride{}, err
+	}
+	return q.db.GetUserQuotaOverride(ctx, userID)
+}
+
+func (q *querier) GetUserRegionLatencies(ctx context.Context, arg database.GetUserRegionLatenciesParams) ([]database.UserRegionLatency, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetUserRegionLatencies(ctx, arg)
+}
+
 func (q *querier) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	// This does the filtering in SQL.
 	prep, err := prepareSQLFilter(ctx, q.auth, rbac.ActionRead, rbac.ResourceUser.Type)
@@ -1501,6 +2052,20 @@ func (q *querier) GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, auth
 	return q.db.GetWorkspaceAgentAndOwnerByAuthToken(ctx, authToken)
 }
 
+func (q *querier) GetWorkspaceAgentBandwidthStatsAndLabels(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentBandwidthStatsAndLabelsRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentBandwidthStatsAndLabels(ctx, createdAt)
+}
+
+func (q *querier) GetWorkspaceAgentBandwidthStatsByPeer(ctx context.Context, arg database.GetWorkspaceAgentBandwidthStatsByPeerParams) ([]database.GetWorkspaceAgentBandwidthStatsByPeerRow, error) {
+	if _, err := q.GetWorkspaceByID(ctx, arg.WorkspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentBandwidthStatsByPeer(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgent, error) {
 	if _, err := q.GetWorkspaceByAgentID(ctx, id); err != nil {
 		return database.WorkspaceAgent{}, err
@@ -1524,6 +2089,29 @@ func (q *querier) GetWorkspaceAgentByInstanceID(ctx context.Context, authInstanc
 	return agent, nil
 }
 
+func (q *querier) GetWorkspaceAgentClientConnections(ctx context.Context, arg database.GetWorkspaceAgentClientConnectionsParams) ([]database.WorkspaceAgentClientConnection, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionRead, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceAgentClientConnections(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentClientConnectionsByUserID(ctx context.Context, arg database.GetWorkspaceAgentClientConnectionsByUserIDParams) ([]database.GetWorkspaceAgentClientConnectionsByUserIDRow, error) {
+	// Connections made by a user are their own data, regardless of whose
+	// workspace they connected to.
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceUserData.WithOwner(arg.UserID.UUID.String()).WithID(arg.UserID.UUID)); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentClientConnectionsByUserID(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
 	_, err := q.GetWorkspaceAgentByID(ctx, id)
 	if err != nil {
@@ -1554,6 +2142,27 @@ func (q *querier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAgentI
 	return q.db.GetWorkspaceAgentMetadata(ctx, workspaceAgentID)
 }
 
+func (q *querier) GetWorkspaceAgentMetadataHistory(ctx context.Context, arg database.GetWorkspaceAgentMetadataHistoryParams) ([]database.WorkspaceAgentMetadataHistory, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionRead, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.db.GetWorkspaceAgentMetadataHistory(ctx, arg)
+}
+
+func (q *querier) GetWorkspaceAgentSSHHostKey(ctx context.Context, arg database.GetWorkspaceAgentSSHHostKeyParams) (database.WorkspaceAgentSSHHostKey, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceAgentSSHHostKey{}, err
+	}
+	return q.db.GetWorkspaceAgentSSHHostKey(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAgentStats(ctx context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	return q.db.GetWorkspaceAgentStats(ctx, createdAfter)
 }
@@ -1587,6 +2196,13 @@ func (q *querier) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Conte
 	return q.db.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
 }
 
+func (q *querier) GetWorkspaceAgentsNeverConnected(ctx context.Context, arg database.GetWorkspaceAgentsNeverConnectedParams) ([]database.GetWorkspaceAgentsNeverConnectedRow, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceAgentsNeverConnected(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg database.GetWorkspaceAppByAgentIDAndSlugParams) (database.WorkspaceApp, error) {
 	// If we can fetch the workspace, we can fetch the apps. Use the authorized call.
 	if _, err := q.GetWorkspaceByAgentID(ctx, arg.AgentID); err != nil {
@@ -1596,6 +2212,32 @@ func (q *querier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg datab
 	return q.db.GetWorkspaceAppByAgentIDAndSlug(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceApprovalByID(ctx context.Context, id uuid.UUID) (database.WorkspaceApproval, error) {
+	approval, err := q.db.GetWorkspaceApprovalByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+	// Authorized fetch
+	_, err = q.GetWorkspaceByID(ctx, approval.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+	return approval, nil
+}
+
+func (q *querier) GetWorkspaceApprovalByJobID(ctx context.Context, jobID uuid.UUID) (database.WorkspaceApproval, error) {
+	approval, err := q.db.GetWorkspaceApprovalByJobID(ctx, jobID)
+	if err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+	// Authorized fetch
+	_, err = q.GetWorkspaceByID(ctx, approval.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+	return approval, nil
+}
+
 func (q *querier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceApp, error) {
 	if _, err := q.GetWorkspaceByAgentID(ctx, agentID); err != nil {
 		return nil, err
@@ -1658,7 +2300,23 @@ func (q *querier) GetWorkspaceBuildParameters(ctx context.Context, workspaceBuil
 		return nil, err
 	}
 
-	return q.db.GetWorkspaceBuildParameters(ctx, workspaceBuildID)
+	return q.db.GetWorkspaceBuildParameters(ctx, workspaceBuildID)
+}
+
+func (q *querier) GetWorkspaceBuildQueueEntriesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceBuildQueueEntry, error) {
+	// Authorized fetch
+	_, err := q.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceBuildQueueEntriesByWorkspaceID(ctx, workspaceID)
+}
+
+func (q *querier) GetWorkspaceBuildQueueWorkspaceIDs(ctx context.Context) ([]uuid.UUID, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionRead, rbac.ResourceSystem); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceBuildQueueWorkspaceIDs(ctx)
 }
 
 func (q *querier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg database.GetWorkspaceBuildsByWorkspaceIDParams) ([]database.WorkspaceBuild, error) {
@@ -1690,10 +2348,22 @@ func (q *querier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg database
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByOwnerIDAndName)(ctx, arg)
 }
 
+func (q *querier) GetWorkspaceByOwnerIDAndPreviousName(ctx context.Context, arg database.GetWorkspaceByOwnerIDAndPreviousNameParams) (database.Workspace, error) {
+	return fetch(q.log, q.auth, q.db.GetWorkspaceByOwnerIDAndPreviousName)(ctx, arg)
+}
+
 func (q *querier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (database.Workspace, error) {
 	return fetch(q.log, q.auth, q.db.GetWorkspaceByWorkspaceAppID)(ctx, workspaceAppID)
 }
 
+func (q *querier) GetWorkspaceExternalMetadataByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetWorkspaceExternalMetadataByWorkspaceIDsRow, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetWorkspaceExternalMetadataByWorkspaceIDs)(ctx, ids)
+}
+
+func (q *querier) GetWorkspacePreviousNamesByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetWorkspacePreviousNamesByWorkspaceIDsRow, error) {
+	return fetchWithPostFilter(q.auth, q.db.GetWorkspacePreviousNamesByWorkspaceIDs)(ctx, ids)
+}
+
 func (q *querier) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	return fetchWithPostFilter(q.auth, func(ctx context.Context, _ interface{}) ([]database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxies(ctx)
@@ -1808,6 +2478,24 @@ func (q *querier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, created
 	return q.db.GetWorkspaceResourcesCreatedAfter(ctx, createdAt)
 }
 
+func (q *querier) GetWorkspaceWebhookByID(ctx context.Context, id uuid.UUID) (database.WorkspaceWebhook, error) {
+	webhook, err := q.db.GetWorkspaceWebhookByID(ctx, id)
+	if err != nil {
+		return database.WorkspaceWebhook{}, err
+	}
+	if err := q.authorizeWorkspaceWebhook(ctx, webhook.WorkspaceID); err != nil {
+		return database.WorkspaceWebhook{}, err
+	}
+	return webhook, nil
+}
+
+func (q *querier) GetWorkspaceWebhooksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceWebhook, error) {
+	if err := q.authorizeWorkspaceWebhook(ctx, workspaceID); err != nil {
+		return nil, err
+	}
+	return q.db.GetWorkspaceWebhooksByWorkspaceID(ctx, workspaceID)
+}
+
 func (q *querier) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
 	prep, err := prepareSQLFilter(ctx, q.auth, rbac.ActionRead, rbac.ResourceWorkspace.Type)
 	if err != nil {
@@ -1835,6 +2523,13 @@ func (q *querier) InsertAuditLog(ctx context.Context, arg database.InsertAuditLo
 	return insert(q.log, q.auth, rbac.ResourceAuditLog, q.db.InsertAuditLog)(ctx, arg)
 }
 
+func (q *querier) InsertAuditLogArchival(ctx context.Context, arg database.InsertAuditLogArchivalParams) (database.AuditLogArchival, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.AuditLogArchival{}, err
+	}
+	return q.db.InsertAuditLogArchival(ctx, arg)
+}
+
 func (q *querier) InsertDERPMeshKey(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return err
@@ -1849,6 +2544,13 @@ func (q *querier) InsertDeploymentID(ctx context.Context, value string) error {
 	return q.db.InsertDeploymentID(ctx, value)
 }
 
+func (q *querier) InsertEntitlementSnapshot(ctx context.Context, arg database.InsertEntitlementSnapshotParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertEntitlementSnapshot(ctx, arg)
+}
+
 func (q *querier) InsertFile(ctx context.Context, arg database.InsertFileParams) (database.File, error) {
 	return insert(q.log, q.auth, rbac.ResourceFile.WithOwner(arg.CreatedBy.String()), q.db.InsertFile)(ctx, arg)
 }
@@ -1865,6 +2567,25 @@ func (q *querier) InsertGroup(ctx context.Context, arg database.InsertGroupParam
 	return insert(q.log, q.auth, rbac.ResourceGroup.InOrg(arg.OrganizationID), q.db.InsertGroup)(ctx, arg)
 }
 
+func (q *querier) InsertGroupDefaultTemplate(ctx context.Context, arg database.InsertGroupDefaultTemplateParams) (database.GroupDefaultTemplate, error) {
+	// Changing the default templates of a group counts as updating the group.
+	group, err := q.db.GetGroupByID(ctx, arg.GroupID)
+	if err != nil {
+		return database.GroupDefaultTemplate{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, group); err != nil {
+		return database.GroupDefaultTemplate{}, err
+	}
+	return q.db.InsertGroupDefaultTemplate(ctx, arg)
+}
+
+func (q *querier) InsertGroupDefaultTemplateWorkspace(ctx context.Context, arg database.InsertGroupDefaultTemplateWorkspaceParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertGroupDefaultTemplateWorkspace(ctx, arg)
+}
+
 func (q *querier) InsertGroupMember(ctx context.Context, arg database.InsertGroupMemberParams) error {
 	fetch := func(ctx context.Context, arg database.InsertGroupMemberParams) (database.Group, error) {
 		return q.db.GetGroupByID(ctx, arg.GroupID)
@@ -1879,6 +2600,13 @@ func (q *querier) InsertLicense(ctx context.Context, arg database.InsertLicenseP
 	return q.db.InsertLicense(ctx, arg)
 }
 
+func (q *querier) InsertManagedEnvironmentVariable(ctx context.Context, arg database.InsertManagedEnvironmentVariableParams) (database.ManagedEnvironmentVariable, error) {
+	if err := q.authorizeManagedEnvironmentVariable(ctx, rbac.ActionUpdate, arg.OrganizationID, arg.TemplateID); err != nil {
+		return database.ManagedEnvironmentVariable{}, err
+	}
+	return q.db.InsertManagedEnvironmentVariable(ctx, arg)
+}
+
 func (q *querier) InsertMissingGroups(ctx context.Context, arg database.InsertMissingGroupsParams) ([]database.Group, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return nil, err
@@ -1886,6 +2614,13 @@ func (q *querier) InsertMissingGroups(ctx context.Context, arg database.InsertMi
 	return q.db.InsertMissingGroups(ctx, arg)
 }
 
+func (q *querier) InsertNetcheckReport(ctx context.Context, arg database.InsertNetcheckReportParams) (database.NetcheckReport, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return database.NetcheckReport{}, err
+	}
+	return q.db.InsertNetcheckReport(ctx, arg)
+}
+
 func (q *querier) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	return insert(q.log, q.auth, rbac.ResourceOrganization, q.db.InsertOrganization)(ctx, arg)
 }
@@ -1903,6 +2638,13 @@ func (q *querier) InsertOrganizationMember(ctx context.Context, arg database.Ins
 }
 
 // TODO: We need to create a ProvisionerDaemon resource type
+func (q *querier) InsertPlatformEvent(ctx context.Context, arg database.InsertPlatformEventParams) (database.PlatformEvent, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.PlatformEvent{}, err
+	}
+	return q.db.InsertPlatformEvent(ctx, arg)
+}
+
 func (q *querier) InsertProvisionerDaemon(ctx context.Context, arg database.InsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 	// return database.ProvisionerDaemon{}, err
@@ -1918,6 +2660,13 @@ func (q *querier) InsertProvisionerJob(ctx context.Context, arg database.InsertP
 	return q.db.InsertProvisionerJob(ctx, arg)
 }
 
+func (q *querier) InsertProvisionerJobBuildHook(ctx context.Context, arg database.InsertProvisionerJobBuildHookParams) (database.ProvisionerJobBuildHook, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.ProvisionerJobBuildHook{}, err
+	}
+	return q.db.InsertProvisionerJobBuildHook(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) InsertProvisionerJobLogs(ctx context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	// if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
@@ -1941,6 +2690,15 @@ func (q *querier) InsertTemplate(ctx context.Context, arg database.InsertTemplat
 	return q.db.InsertTemplate(ctx, arg)
 }
 
+func (q *querier) InsertTemplateAccessRequest(ctx context.Context, arg database.InsertTemplateAccessRequestParams) (database.TemplateAccessRequest, error) {
+	// Requesters can't read the template yet, so requests are authorized
+	// against the requester instead.
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.RequestedBy.String()).WithID(arg.RequestedBy)); err != nil {
+		return database.TemplateAccessRequest{}, err
+	}
+	return q.db.InsertTemplateAccessRequest(ctx, arg)
+}
+
 func (q *querier) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	if !arg.TemplateID.Valid {
 		// Making a new template version is the same permission as creating a new template.
@@ -1954,8 +2712,8 @@ func (q *querier) InsertTemplateVersion(ctx context.Context, arg database.Insert
 		if err != nil {
 			return err
 		}
-		// Check the create permission on the template.
-		err = q.authorizeContext(ctx, rbac.ActionCreate, tpl)
+		// Check the push permission on the template.
+		err = q.authorizeContext(ctx, rbac.ActionPushVersion, tpl)
 		if err != nil {
 			return err
 		}
@@ -1971,6 +2729,19 @@ func (q *querier) InsertTemplateVersionParameter(ctx context.Context, arg databa
 	return q.db.InsertTemplateVersionParameter(ctx, arg)
 }
 
+func (q *querier) InsertTemplateVersionPromotion(ctx context.Context, arg database.InsertTemplateVersionPromotionParams) (database.TemplateVersionPromotion, error) {
+	// Promotions are requested by the users that could otherwise update the
+	// active version of the template.
+	template, err := q.db.GetTemplateByID(ctx, arg.TemplateID)
+	if err != nil {
+		return database.TemplateVersionPromotion{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionPushVersion, template); err != nil {
+		return database.TemplateVersionPromotion{}, err
+	}
+	return q.db.InsertTemplateVersionPromotion(ctx, arg)
+}
+
 func (q *querier) InsertTemplateVersionVariable(ctx context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
 		return database.TemplateVersionVariable{}, err
@@ -1989,6 +2760,13 @@ func (q *querier) InsertUser(ctx context.Context, arg database.InsertUserParams)
 	return insert(q.log, q.auth, obj, q.db.InsertUser)(ctx, arg)
 }
 
+func (q *querier) InsertUserDeprovision(ctx context.Context, arg database.InsertUserDeprovisionParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.InsertUserDeprovision(ctx, arg)
+}
+
 func (q *querier) InsertUserGroupsByName(ctx context.Context, arg database.InsertUserGroupsByNameParams) error {
 	// This will add the user to all named groups. This counts as updating a group.
 	// NOTE: instead of checking if the user has permission to update each group, we instead
@@ -2021,6 +2799,34 @@ func (q *querier) InsertWorkspaceAgent(ctx context.Context, arg database.InsertW
 	return q.db.InsertWorkspaceAgent(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentAuthTokenRotation(ctx context.Context, arg database.InsertWorkspaceAgentAuthTokenRotationParams) (database.WorkspaceAgentAuthTokenRotation, error) {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.AgentID)
+	if err != nil {
+		return database.WorkspaceAgentAuthTokenRotation{}, err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceAgentAuthTokenRotation{}, err
+	}
+
+	return q.db.InsertWorkspaceAgentAuthTokenRotation(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceAgentBandwidthStats(ctx context.Context, arg database.InsertWorkspaceAgentBandwidthStatsParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+
+	return q.db.InsertWorkspaceAgentBandwidthStats(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceAgentClientConnection(ctx context.Context, arg database.InsertWorkspaceAgentClientConnectionParams) (database.WorkspaceAgentClientConnection, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceAgentClientConnection{}, err
+	}
+	return q.db.InsertWorkspaceAgentClientConnection(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentLogs(ctx context.Context, arg database.InsertWorkspaceAgentLogsParams) ([]database.WorkspaceAgentLog, error) {
 	return q.db.InsertWorkspaceAgentLogs(ctx, arg)
 }
@@ -2035,6 +2841,27 @@ func (q *querier) InsertWorkspaceAgentMetadata(ctx context.Context, arg database
 	return q.db.InsertWorkspaceAgentMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg database.InsertWorkspaceAgentMetadataHistoryParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.WorkspaceAgentID)
+	if err != nil {
+		return err
+	}
+
+	err = q.authorizeContext(ctx, rbac.ActionUpdate, workspace)
+	if err != nil {
+		return err
+	}
+
+	return q.db.InsertWorkspaceAgentMetadataHistory(ctx, arg)
+}
+
+func (q *querier) InsertWorkspaceAgentSSHHostKey(ctx context.Context, arg database.InsertWorkspaceAgentSSHHostKeyParams) (database.WorkspaceAgentSSHHostKey, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceAgentSSHHostKey{}, err
+	}
+	return q.db.InsertWorkspaceAgentSSHHostKey(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceAgentStat(ctx context.Context, arg database.InsertWorkspaceAgentStatParams) (database.WorkspaceAgentStat, error) {
 	// TODO: This is a workspace agent operation. Should users be able to query this?
 	// Not really sure what this is for.
@@ -2071,6 +2898,17 @@ func (q *querier) InsertWorkspaceAppStats(ctx context.Context, arg database.Inse
 	return q.db.InsertWorkspaceAppStats(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceApproval(ctx context.Context, arg database.InsertWorkspaceApprovalParams) (database.WorkspaceApproval, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+	return q.db.InsertWorkspaceApproval(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceBuild(ctx context.Context, arg database.InsertWorkspaceBuildParams) error {
 	w, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
 	if err != nil {
@@ -2109,6 +2947,17 @@ func (q *querier) InsertWorkspaceBuildParameters(ctx context.Context, arg databa
 	return q.db.InsertWorkspaceBuildParameters(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceBuildQueueEntry(ctx context.Context, arg database.InsertWorkspaceBuildQueueEntryParams) (database.WorkspaceBuildQueueEntry, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceBuildQueueEntry{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return database.WorkspaceBuildQueueEntry{}, err
+	}
+	return q.db.InsertWorkspaceBuildQueueEntry(ctx, arg)
+}
+
 func (q *querier) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	return insert(q.log, q.auth, rbac.ResourceWorkspaceProxy, q.db.InsertWorkspaceProxy)(ctx, arg)
 }
@@ -2127,6 +2976,13 @@ func (q *querier) InsertWorkspaceResourceMetadata(ctx context.Context, arg datab
 	return q.db.InsertWorkspaceResourceMetadata(ctx, arg)
 }
 
+func (q *querier) InsertWorkspaceWebhook(ctx context.Context, arg database.InsertWorkspaceWebhookParams) (database.WorkspaceWebhook, error) {
+	if err := q.authorizeWorkspaceWebhook(ctx, arg.WorkspaceID); err != nil {
+		return database.WorkspaceWebhook{}, err
+	}
+	return q.db.InsertWorkspaceWebhook(ctx, arg)
+}
+
 func (q *querier) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	fetch := func(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -2145,6 +3001,13 @@ func (q *querier) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateAPIKe
 	return update(q.log, q.auth, fetch, q.db.UpdateAPIKeyByID)(ctx, arg)
 }
 
+func (q *querier) UpdateAuditLogArchivalByID(ctx context.Context, arg database.UpdateAuditLogArchivalByIDParams) (database.AuditLogArchival, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.AuditLogArchival{}, err
+	}
+	return q.db.UpdateAuditLogArchivalByID(ctx, arg)
+}
+
 func (q *querier) UpdateGitAuthLink(ctx context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	fetch := func(ctx context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 		return q.db.GetGitAuthLink(ctx, database.GetGitAuthLinkParams{UserID: arg.UserID, ProviderID: arg.ProviderID})
@@ -2159,6 +3022,13 @@ func (q *querier) UpdateGitSSHKey(ctx context.Context, arg database.UpdateGitSSH
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateGitSSHKey)(ctx, arg)
 }
 
+func (q *querier) UpdateGroupAvatarByID(ctx context.Context, arg database.UpdateGroupAvatarByIDParams) (database.Group, error) {
+	fetch := func(ctx context.Context, arg database.UpdateGroupAvatarByIDParams) (database.Group, error) {
+		return q.db.GetGroupByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateGroupAvatarByID)(ctx, arg)
+}
+
 func (q *querier) UpdateGroupByID(ctx context.Context, arg database.UpdateGroupByIDParams) (database.Group, error) {
 	fetch := func(ctx context.Context, arg database.UpdateGroupByIDParams) (database.Group, error) {
 		return q.db.GetGroupByID(ctx, arg.ID)
@@ -2173,6 +3043,17 @@ func (q *querier) UpdateInactiveUsersToDormant(ctx context.Context, lastSeenAfte
 	return q.db.UpdateInactiveUsersToDormant(ctx, lastSeenAfter)
 }
 
+func (q *querier) UpdateManagedEnvironmentVariableByID(ctx context.Context, arg database.UpdateManagedEnvironmentVariableByIDParams) (database.ManagedEnvironmentVariable, error) {
+	variable, err := q.db.GetManagedEnvironmentVariableByID(ctx, arg.ID)
+	if err != nil {
+		return database.ManagedEnvironmentVariable{}, err
+	}
+	if err := q.authorizeManagedEnvironmentVariable(ctx, rbac.ActionUpdate, variable.OrganizationID, variable.TemplateID); err != nil {
+		return database.ManagedEnvironmentVariable{}, err
+	}
+	return q.db.UpdateManagedEnvironmentVariableByID(ctx, arg)
+}
+
 func (q *querier) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	// Authorized fetch will check that the actor has read access to the org member since the org member is returned.
 	member, err := q.GetOrganizationMemberByUserID(ctx, database.GetOrganizationMemberByUserIDParams{
@@ -2194,6 +3075,13 @@ func (q *querier) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemb
 	return q.db.UpdateMemberRoles(ctx, arg)
 }
 
+func (q *querier) UpdateOrganizationProvisionerLimits(ctx context.Context, arg database.UpdateOrganizationProvisionerLimitsParams) (database.Organization, error) {
+	fetch := func(ctx context.Context, arg database.UpdateOrganizationProvisionerLimitsParams) (database.Organization, error) {
+		return q.db.GetOrganizationByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateOrganizationProvisionerLimits)(ctx, arg)
+}
+
 // TODO: We need to create a ProvisionerJob resource type
 func (q *querier) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	// if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
@@ -2253,7 +3141,7 @@ func (q *querier) UpdateProvisionerJobWithCancelByID(ctx context.Context, arg da
 			if err != nil {
 				return err
 			}
-			err = q.authorizeContext(ctx, rbac.ActionUpdate, templateVersion.RBACObject(template))
+			err = q.authorizeContext(ctx, rbac.ActionPushVersion, templateVersion.RBACObject(template))
 			if err != nil {
 				return err
 			}
@@ -2277,6 +3165,13 @@ func (q *querier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg
 	return q.db.UpdateProvisionerJobWithCompleteByID(ctx, arg)
 }
 
+func (q *querier) UpdateProvisionerJobWithRequeueByID(ctx context.Context, arg database.UpdateProvisionerJobWithRequeueByIDParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateProvisionerJobWithRequeueByID(ctx, arg)
+}
+
 func (q *querier) UpdateReplica(ctx context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return database.Replica{}, err
@@ -2284,20 +3179,42 @@ func (q *querier) UpdateReplica(ctx context.Context, arg database.UpdateReplicaP
 	return q.db.UpdateReplica(ctx, arg)
 }
 
+func (q *querier) UpdateReplicaDrainingAt(ctx context.Context, arg database.UpdateReplicaDrainingAtParams) (database.Replica, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.Replica{}, err
+	}
+	return q.db.UpdateReplicaDrainingAt(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateACLByID(ctx context.Context, arg database.UpdateTemplateACLByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateACLByIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.ID)
 	}
-	// UpdateTemplateACL uses the ActionCreate action. Only users that can create the template
-	// may update the ACL.
-	return fetchAndExec(q.log, q.auth, rbac.ActionCreate, fetch, q.db.UpdateTemplateACLByID)(ctx, arg)
+	// Users allowed to push versions or change settings cannot change who has
+	// access to the template.
+	return fetchAndExec(q.log, q.auth, rbac.ActionManageACL, fetch, q.db.UpdateTemplateACLByID)(ctx, arg)
+}
+
+func (q *querier) UpdateTemplateAccessRequestStatusByID(ctx context.Context, arg database.UpdateTemplateAccessRequestStatusByIDParams) (database.TemplateAccessRequest, error) {
+	request, err := q.db.GetTemplateAccessRequestByID(ctx, arg.ID)
+	if err != nil {
+		return database.TemplateAccessRequest{}, err
+	}
+	template, err := q.db.GetTemplateByID(ctx, request.TemplateID)
+	if err != nil {
+		return database.TemplateAccessRequest{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionManageACL, template); err != nil {
+		return database.TemplateAccessRequest{}, err
+	}
+	return q.db.UpdateTemplateAccessRequestStatusByID(ctx, arg)
 }
 
 func (q *querier) UpdateTemplateActiveVersionByID(ctx context.Context, arg database.UpdateTemplateActiveVersionByIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateActiveVersionByIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.ID)
 	}
-	return update(q.log, q.auth, fetch, q.db.UpdateTemplateActiveVersionByID)(ctx, arg)
+	return fetchAndExec(q.log, q.auth, rbac.ActionPushVersion, fetch, q.db.UpdateTemplateActiveVersionByID)(ctx, arg)
 }
 
 // Deprecated: use SoftDeleteTemplateByID instead.
@@ -2320,11 +3237,14 @@ func (q *querier) UpdateTemplateScheduleByID(ctx context.Context, arg database.U
 }
 
 func (q *querier) UpdateTemplateVersionByID(ctx context.Context, arg database.UpdateTemplateVersionByIDParams) error {
-	// An actor is allowed to update the template version if they are authorized to update the template.
+	// An actor is allowed to update the template version if they are authorized to push versions
+	// to the template.
 	tv, err := q.db.GetTemplateVersionByID(ctx, arg.ID)
 	if err != nil {
 		return err
 	}
+	// Versions without a template are authorized like creating a template.
+	action := rbac.ActionCreate
 	var obj rbac.Objecter
 	if !tv.TemplateID.Valid {
 		obj = rbac.ResourceTemplate.InOrg(tv.OrganizationID)
@@ -2334,8 +3254,9 @@ func (q *querier) UpdateTemplateVersionByID(ctx context.Context, arg database.Up
 			return err
 		}
 		obj = tpl
+		action = rbac.ActionPushVersion
 	}
-	if err := q.authorizeContext(ctx, rbac.ActionUpdate, obj); err != nil {
+	if err := q.authorizeContext(ctx, action, obj); err != nil {
 		return err
 	}
 	return q.db.UpdateTemplateVersionByID(ctx, arg)
@@ -2385,6 +3306,13 @@ func (q *querier) UpdateTemplateVersionGitAuthProvidersByJobID(ctx context.Conte
 	return q.db.UpdateTemplateVersionGitAuthProvidersByJobID(ctx, arg)
 }
 
+func (q *querier) UpdateTemplateVersionPromotionStatusByID(ctx context.Context, arg database.UpdateTemplateVersionPromotionStatusByIDParams) (database.TemplateVersionPromotion, error) {
+	fetch := func(ctx context.Context, arg database.UpdateTemplateVersionPromotionStatusByIDParams) (database.TemplateVersionPromotion, error) {
+		return q.db.GetTemplateVersionPromotionByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateTemplateVersionPromotionStatusByID)(ctx, arg)
+}
+
 func (q *querier) UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.TemplateID)
@@ -2509,6 +3437,39 @@ func (q *querier) UpdateWorkspace(ctx context.Context, arg database.UpdateWorksp
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspace)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceAgentAuthTokenByID(ctx context.Context, arg database.UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.UpdateWorkspaceAgentAuthTokenByID(ctx, arg)
+}
+
+func (q *querier) UpdateWorkspaceAgentClientConnectionDisconnectedAt(ctx context.Context, arg database.UpdateWorkspaceAgentClientConnectionDisconnectedAtParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpdateWorkspaceAgentClientConnectionDisconnectedAt(ctx, arg)
+}
+
+func (q *querier) UpdateWorkspaceAgentClockOffsetByID(ctx context.Context, arg database.UpdateWorkspaceAgentClockOffsetByIDParams) error {
+	workspace, err := q.db.GetWorkspaceByAgentID(ctx, arg.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+
+	return q.db.UpdateWorkspaceAgentClockOffsetByID(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
 		return err
@@ -2593,6 +3554,35 @@ func (q *querier) UpdateWorkspaceAppHealthByID(ctx context.Context, arg database
 	return q.db.UpdateWorkspaceAppHealthByID(ctx, arg)
 }
 
+// UpdateWorkspaceApprovalStatusByID requires permission to update the template
+// of the workspace, as approvers are the admins of the template rather than
+// the owner of the workspace.
+func (q *querier) UpdateWorkspaceApprovalStatusByID(ctx context.Context, arg database.UpdateWorkspaceApprovalStatusByIDParams) (database.WorkspaceApproval, error) {
+	approval, err := q.db.GetWorkspaceApprovalByID(ctx, arg.ID)
+	if err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+	workspace, err := q.db.GetWorkspaceByID(ctx, approval.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+	template, err := q.db.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, template); err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+	return q.db.UpdateWorkspaceApprovalStatusByID(ctx, arg)
+}
+
+func (q *querier) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) (database.Workspace, error) {
+		return q.db.GetWorkspaceByID(ctx, arg.ID)
+	}
+	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceAutomaticUpdates)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceAutostart(ctx context.Context, arg database.UpdateWorkspaceAutostartParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceAutostartParams) (database.Workspace, error) {
 		return q.db.GetWorkspaceByID(ctx, arg.ID)
@@ -2657,6 +3647,13 @@ func (q *querier) UpdateWorkspaceProxy(ctx context.Context, arg database.UpdateW
 	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspaceProxy)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceProxyACL(ctx context.Context, arg database.UpdateWorkspaceProxyACLParams) (database.WorkspaceProxy, error) {
+	fetch := func(ctx context.Context, arg database.UpdateWorkspaceProxyACLParams) (database.WorkspaceProxy, error) {
+		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
+	}
+	return updateWithReturn(q.log, q.auth, fetch, q.db.UpdateWorkspaceProxyACL)(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg database.UpdateWorkspaceProxyDeletedParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspaceProxyDeletedParams) (database.WorkspaceProxy, error) {
 		return q.db.GetWorkspaceProxyByID(ctx, arg.ID)
@@ -2671,6 +3668,13 @@ func (q *querier) UpdateWorkspaceTTL(ctx context.Context, arg database.UpdateWor
 	return update(q.log, q.auth, fetch, q.db.UpdateWorkspaceTTL)(ctx, arg)
 }
 
+func (q *querier) UpdateWorkspaceWebhookLastTriggeredAt(ctx context.Context, arg database.UpdateWorkspaceWebhookLastTriggeredAtParams) (database.WorkspaceWebhook, error) {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return database.WorkspaceWebhook{}, err
+	}
+	return q.db.UpdateWorkspaceWebhookLastTriggeredAt(ctx, arg)
+}
+
 func (q *querier) UpdateWorkspacesLockedDeletingAtByTemplateID(ctx context.Context, arg database.UpdateWorkspacesLockedDeletingAtByTemplateIDParams) error {
 	fetch := func(ctx context.Context, arg database.UpdateWorkspacesLockedDeletingAtByTemplateIDParams) (database.Template, error) {
 		return q.db.GetTemplateByID(ctx, arg.TemplateID)
@@ -2698,6 +3702,13 @@ func (q *querier) UpsertLastUpdateCheck(ctx context.Context, value string) error
 	return q.db.UpsertLastUpdateCheck(ctx, value)
 }
 
+func (q *querier) UpsertLicenseUsage(ctx context.Context, arg database.UpsertLicenseUsageParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertLicenseUsage(ctx, arg)
+}
+
 func (q *querier) UpsertLogoURL(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceDeploymentValues); err != nil {
 		return err
@@ -2712,6 +3723,20 @@ func (q *querier) UpsertOAuthSigningKey(ctx context.Context, value string) error
 	return q.db.UpsertOAuthSigningKey(ctx, value)
 }
 
+func (q *querier) UpsertRuntimeExperiments(ctx context.Context, value string) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceDeploymentValues); err != nil {
+		return err
+	}
+	return q.db.UpsertRuntimeExperiments(ctx, value)
+}
+
+func (q *querier) UpsertSSHCAKey(ctx context.Context, value string) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertSSHCAKey(ctx, value)
+}
+
 func (q *querier) UpsertServiceBanner(ctx context.Context, value string) error {
 	if err := q.authorizeContext(ctx, rbac.ActionCreate, rbac.ResourceDeploymentValues); err != nil {
 		return err
@@ -2740,6 +3765,69 @@ func (q *querier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (d
 	return q.db.UpsertTailnetCoordinator(ctx, id)
 }
 
+func (q *querier) UpsertTemplateVersionBuildInsights(ctx context.Context, arg database.UpsertTemplateVersionBuildInsightsParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceSystem); err != nil {
+		return err
+	}
+	return q.db.UpsertTemplateVersionBuildInsights(ctx, arg)
+}
+
+func (q *querier) UpsertUserPTYSessionOverride(ctx context.Context, arg database.UpsertUserPTYSessionOverrideParams) (database.UserPTYSessionOverride, error) {
+	err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserObject(arg.UserID))
+	if err != nil {
+		return database.UserPTYSessionOverride{}, err
+	}
+	return q.db.UpsertUserPTYSessionOverride(ctx, arg)
+}
+
+func (q *querier) UpsertUserQuotaOverride(ctx context.Context, arg database.UpsertUserQuotaOverrideParams) (database.UserQuotaOverride, error) {
+	err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserObject(arg.UserID))
+	if err != nil {
+		return database.UserQuotaOverride{}, err
+	}
+	return q.db.UpsertUserQuotaOverride(ctx, arg)
+}
+
+func (q *querier) UpsertUserRegionLatency(ctx context.Context, arg database.UpsertUserRegionLatencyParams) error {
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, rbac.ResourceUserData.WithOwner(arg.UserID.String()).WithID(arg.UserID)); err != nil {
+		return err
+	}
+	return q.db.UpsertUserRegionLatency(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspaceExternalMetadatum(ctx context.Context, arg database.UpsertWorkspaceExternalMetadatumParams) (database.WorkspaceExternalMetadatum, error) {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return database.WorkspaceExternalMetadatum{}, err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace.ExternalMetadataRBAC(arg.Visibility)); err != nil {
+		return database.WorkspaceExternalMetadatum{}, err
+	}
+	// Changing the visibility of existing metadata requires access to it as
+	// well, so private metadata can't be overwritten by making it public.
+	existing, ok, err := q.getWorkspaceExternalMetadatum(ctx, arg.WorkspaceID, arg.Namespace)
+	if err != nil {
+		return database.WorkspaceExternalMetadatum{}, err
+	}
+	if ok {
+		if err := q.authorizeContext(ctx, rbac.ActionUpdate, existing.RBACObject()); err != nil {
+			return database.WorkspaceExternalMetadatum{}, err
+		}
+	}
+	return q.db.UpsertWorkspaceExternalMetadatum(ctx, arg)
+}
+
+func (q *querier) UpsertWorkspacePreviousName(ctx context.Context, arg database.UpsertWorkspacePreviousNameParams) error {
+	workspace, err := q.db.GetWorkspaceByID(ctx, arg.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if err := q.authorizeContext(ctx, rbac.ActionUpdate, workspace); err != nil {
+		return err
+	}
+	return q.db.UpsertWorkspacePreviousName(ctx, arg)
+}
+
 func (q *querier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, _ rbac.PreparedAuthorized) ([]database.Template, error) {
 	// TODO Delete this function, all GetTemplates should be authorized. For now just call getTemplates on the authz querier.
 	return q.GetTemplatesWithFilter(ctx, arg)
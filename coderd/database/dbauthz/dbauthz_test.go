@@ -237,6 +237,70 @@ func (s *MethodTestSuite) TestAuditLogs() {
 			Limit: 10,
 		}).Asserts(rbac.ResourceAuditLog, rbac.ActionRead)
 	}))
+	s.Run("GetAuditLogsOlderThan", s.Subtest(func(db database.Store, check *expects) {
+		_ = dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args(database.GetAuditLogsOlderThanParams{
+			Before:   time.Now(),
+			RowLimit: 10,
+		}).Asserts(rbac.ResourceAuditLog, rbac.ActionRead)
+	}))
+	s.Run("DeleteAuditLogsByIDs", s.Subtest(func(db database.Store, check *expects) {
+		a := dbgen.AuditLog(s.T(), db, database.AuditLog{})
+		check.Args([]uuid.UUID{a.ID}).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("InsertAuditLogArchival", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertAuditLogArchivalParams{
+			ID:        uuid.New(),
+			Cutoff:    time.Now(),
+			StartedAt: time.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("UpdateAuditLogArchivalByID", s.Subtest(func(db database.Store, check *expects) {
+		a, err := db.InsertAuditLogArchival(context.Background(), database.InsertAuditLogArchivalParams{
+			ID:        uuid.New(),
+			Cutoff:    time.Now(),
+			StartedAt: time.Now(),
+		})
+		s.NoError(err)
+		check.Args(database.UpdateAuditLogArchivalByIDParams{
+			ID:     a.ID,
+			Status: database.AuditLogArchivalStatusSucceeded,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetAuditLogArchivalByID", s.Subtest(func(db database.Store, check *expects) {
+		a, err := db.InsertAuditLogArchival(context.Background(), database.InsertAuditLogArchivalParams{
+			ID:        uuid.New(),
+			Cutoff:    time.Now(),
+			StartedAt: time.Now(),
+		})
+		s.NoError(err)
+		check.Args(a.ID).Asserts(rbac.ResourceAuditLog, rbac.ActionRead).Returns(a)
+	}))
+	s.Run("GetAuditLogArchivals", s.Subtest(func(db database.Store, check *expects) {
+		a, err := db.InsertAuditLogArchival(context.Background(), database.InsertAuditLogArchivalParams{
+			ID:        uuid.New(),
+			Cutoff:    time.Now(),
+			StartedAt: time.Now(),
+		})
+		s.NoError(err)
+		check.Args(int32(10)).Asserts(rbac.ResourceAuditLog, rbac.ActionRead).Returns([]database.AuditLogArchival{a})
+	}))
+}
+
+func (s *MethodTestSuite) TestPlatformEvents() {
+	s.Run("InsertPlatformEvent", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertPlatformEventParams{
+			CreatedAt:  database.Now(),
+			Type:       "user.created",
+			ResourceID: uuid.New(),
+			Data:       []byte("{}"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetPlatformEventsAfterID", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetPlatformEventsAfterIDParams{
+			LimitOpt: 10,
+		}).Asserts(rbac.ResourcePlatformEvent, rbac.ActionRead)
+	}))
 }
 
 func (s *MethodTestSuite) TestFile() {
@@ -285,11 +349,31 @@ func (s *MethodTestSuite) TestGroup() {
 			Name:           g.Name,
 		}).Asserts(g, rbac.ActionRead).Returns(g)
 	}))
+	s.Run("DeleteGroupDefaultTemplates", s.Subtest(func(db database.Store, check *expects) {
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(g.ID).Asserts(g, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetGroupDefaultTemplates", s.Subtest(func(db database.Store, check *expects) {
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(g.ID).Asserts(g, rbac.ActionRead)
+	}))
+	s.Run("InsertGroupDefaultTemplate", s.Subtest(func(db database.Store, check *expects) {
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(database.InsertGroupDefaultTemplateParams{
+			GroupID:    g.ID,
+			TemplateID: uuid.New(),
+		}).Asserts(g, rbac.ActionUpdate)
+	}))
 	s.Run("GetGroupMembers", s.Subtest(func(db database.Store, check *expects) {
 		g := dbgen.Group(s.T(), db, database.Group{})
 		_ = dbgen.GroupMember(s.T(), db, database.GroupMember{})
 		check.Args(g.ID).Asserts(g, rbac.ActionRead)
 	}))
+	s.Run("GetGroupMembersAnyStatus", s.Subtest(func(db database.Store, check *expects) {
+		g := dbgen.Group(s.T(), db, database.Group{})
+		_ = dbgen.GroupMember(s.T(), db, database.GroupMember{GroupID: g.ID})
+		check.Args(g.ID).Asserts(g, rbac.ActionRead)
+	}))
 	s.Run("InsertAllUsersGroup", s.Subtest(func(db database.Store, check *expects) {
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		check.Args(o.ID).Asserts(rbac.ResourceGroup.InOrg(o.ID), rbac.ActionCreate)
@@ -338,6 +422,12 @@ func (s *MethodTestSuite) TestGroup() {
 			ID: g.ID,
 		}).Asserts(g, rbac.ActionUpdate)
 	}))
+	s.Run("UpdateGroupAvatarByID", s.Subtest(func(db database.Store, check *expects) {
+		g := dbgen.Group(s.T(), db, database.Group{})
+		check.Args(database.UpdateGroupAvatarByIDParams{
+			ID: g.ID,
+		}).Asserts(g, rbac.ActionUpdate)
+	}))
 }
 
 func (s *MethodTestSuite) TestProvsionerJob() {
@@ -401,7 +491,7 @@ func (s *MethodTestSuite) TestProvsionerJob() {
 			JobID:      j.ID,
 		})
 		check.Args(database.UpdateProvisionerJobWithCancelByIDParams{ID: j.ID}).
-			Asserts(v.RBACObject(tpl), []rbac.Action{rbac.ActionRead, rbac.ActionUpdate}).Returns()
+			Asserts(v.RBACObject(tpl), []rbac.Action{rbac.ActionRead, rbac.ActionPushVersion}).Returns()
 	}))
 	s.Run("TemplateVersionNoTemplate/UpdateProvisionerJobWithCancelByID", s.Subtest(func(db database.Store, check *expects) {
 		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{
@@ -426,7 +516,7 @@ func (s *MethodTestSuite) TestProvsionerJob() {
 			}{TemplateVersionID: v.ID})),
 		})
 		check.Args(database.UpdateProvisionerJobWithCancelByIDParams{ID: j.ID}).
-			Asserts(v.RBACObject(tpl), []rbac.Action{rbac.ActionRead, rbac.ActionUpdate}).Returns()
+			Asserts(v.RBACObject(tpl), []rbac.Action{rbac.ActionRead, rbac.ActionPushVersion}).Returns()
 	}))
 	s.Run("GetProvisionerJobsByIDs", s.Subtest(func(db database.Store, check *expects) {
 		a := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
@@ -443,6 +533,14 @@ func (s *MethodTestSuite) TestProvsionerJob() {
 			JobID: j.ID,
 		}).Asserts(w, rbac.ActionRead).Returns([]database.ProvisionerJobLog{})
 	}))
+	s.Run("GetProvisionerJobBuildHooksByJobID", s.Subtest(func(db database.Store, check *expects) {
+		w := dbgen.Workspace(s.T(), db, database.Workspace{})
+		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{
+			Type: database.ProvisionerJobTypeWorkspaceBuild,
+		})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{JobID: j.ID, WorkspaceID: w.ID})
+		check.Args(j.ID).Asserts(w, rbac.ActionRead).Returns([]database.ProvisionerJobBuildHook{})
+	}))
 }
 
 func (s *MethodTestSuite) TestLicense() {
@@ -458,12 +556,40 @@ func (s *MethodTestSuite) TestLicense() {
 		check.Args(database.InsertLicenseParams{}).
 			Asserts(rbac.ResourceLicense, rbac.ActionCreate)
 	}))
+	s.Run("UpsertLicenseUsage", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpsertLicenseUsageParams{
+			Feature: "user_limit",
+			Date:    time.Now(),
+			Actual:  1,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
+	s.Run("GetLicenseUsage", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetLicenseUsageParams{
+			StartDate: time.Now().AddDate(0, 0, -1),
+			EndDate:   time.Now(),
+		}).Asserts(rbac.ResourceLicense, rbac.ActionRead).Returns([]database.LicenseUsage{})
+	}))
+	s.Run("InsertEntitlementSnapshot", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertEntitlementSnapshotParams{
+			Date:     time.Now(),
+			Features: json.RawMessage("{}"),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetEntitlementSnapshots", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetEntitlementSnapshotsParams{
+			StartDate: time.Now().AddDate(0, 0, -1),
+			EndDate:   time.Now(),
+		}).Asserts(rbac.ResourceLicense, rbac.ActionRead).Returns([]database.EntitlementSnapshot{})
+	}))
 	s.Run("UpsertLogoURL", s.Subtest(func(db database.Store, check *expects) {
 		check.Args("value").Asserts(rbac.ResourceDeploymentValues, rbac.ActionCreate)
 	}))
 	s.Run("UpsertServiceBanner", s.Subtest(func(db database.Store, check *expects) {
 		check.Args("value").Asserts(rbac.ResourceDeploymentValues, rbac.ActionCreate)
 	}))
+	s.Run("UpsertRuntimeExperiments", s.Subtest(func(db database.Store, check *expects) {
+		check.Args("value").Asserts(rbac.ResourceDeploymentValues, rbac.ActionUpdate)
+	}))
 	s.Run("GetLicenseByID", s.Subtest(func(db database.Store, check *expects) {
 		l, err := db.InsertLicense(context.Background(), database.InsertLicenseParams{
 			UUID: uuid.New(),
@@ -497,6 +623,11 @@ func (s *MethodTestSuite) TestLicense() {
 		require.NoError(s.T(), err)
 		check.Args().Asserts().Returns("value")
 	}))
+	s.Run("GetRuntimeExperiments", s.Subtest(func(db database.Store, check *expects) {
+		err := db.UpsertRuntimeExperiments(context.Background(), "value")
+		require.NoError(s.T(), err)
+		check.Args().Asserts().Returns("value")
+	}))
 }
 
 func (s *MethodTestSuite) TestOrganization() {
@@ -567,6 +698,16 @@ func (s *MethodTestSuite) TestOrganization() {
 			rbac.ResourceRoleAssignment.InOrg(o.ID), rbac.ActionCreate,
 			rbac.ResourceOrganizationMember.InOrg(o.ID).WithID(u.ID), rbac.ActionCreate)
 	}))
+	s.Run("UpdateOrganizationProvisionerLimits", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		out := o
+		out.MaxConcurrentProvisionerJobs = 2
+		check.Args(database.UpdateOrganizationProvisionerLimitsParams{
+			ID:                           o.ID,
+			UpdatedAt:                    o.UpdatedAt,
+			MaxConcurrentProvisionerJobs: 2,
+		}).Asserts(o, rbac.ActionUpdate).Returns(out)
+	}))
 	s.Run("UpdateMemberRoles", s.Subtest(func(db database.Store, check *expects) {
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		u := dbgen.User(s.T(), db, database.User{})
@@ -606,6 +747,14 @@ func (s *MethodTestSuite) TestWorkspaceProxy() {
 		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
 		check.Args(p.ID).Asserts(p, rbac.ActionRead).Returns(p)
 	}))
+	s.Run("UpdateWorkspaceProxyACL", s.Subtest(func(db database.Store, check *expects) {
+		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
+		check.Args(database.UpdateWorkspaceProxyACLParams{
+			ID:              p.ID,
+			OrganizationIDs: []uuid.UUID{uuid.New()},
+			GroupIDs:        []uuid.UUID{},
+		}).Asserts(p, rbac.ActionUpdate)
+	}))
 	s.Run("UpdateWorkspaceProxyDeleted", s.Subtest(func(db database.Store, check *expects) {
 		p, _ := dbgen.WorkspaceProxy(s.T(), db, database.WorkspaceProxy{})
 		check.Args(database.UpdateWorkspaceProxyDeletedParams{
@@ -648,10 +797,32 @@ func (s *MethodTestSuite) TestTemplate() {
 			TemplateID:     uuid.NullUUID{UUID: t1.ID, Valid: true},
 		}).Asserts(t1, rbac.ActionRead).Returns(b)
 	}))
+	s.Run("GetTemplateAgentFirstConnectInsights", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.GetTemplateAgentFirstConnectInsightsParams{
+			TemplateIDs: []uuid.UUID{t1.ID},
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("GetTemplateBuildSLOInsights", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.GetTemplateBuildSLOInsightsParams{
+			TemplateIDs: []uuid.UUID{t1.ID},
+		}).Asserts(t1, rbac.ActionUpdate)
+	}))
 	s.Run("GetTemplateByID", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		check.Args(t1.ID).Asserts(t1, rbac.ActionRead).Returns(t1)
 	}))
+	s.Run("GetTemplateVersionBuildInsights", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(t1.ID).Asserts(t1, rbac.ActionUpdate)
+	}))
+	s.Run("UpsertTemplateVersionBuildInsights", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpsertTemplateVersionBuildInsightsParams{
+			Since:     time.Now(),
+			UpdatedAt: time.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 	s.Run("GetTemplateByOrganizationAndName", s.Subtest(func(db database.Store, check *expects) {
 		o1 := dbgen.Organization(s.T(), db, database.Organization{})
 		t1 := dbgen.Template(s.T(), db, database.Template{
@@ -762,7 +933,7 @@ func (s *MethodTestSuite) TestTemplate() {
 		check.Args(database.InsertTemplateVersionParams{
 			TemplateID:     uuid.NullUUID{UUID: t1.ID, Valid: true},
 			OrganizationID: t1.OrganizationID,
-		}).Asserts(t1, rbac.ActionRead, t1, rbac.ActionCreate)
+		}).Asserts(t1, rbac.ActionRead, t1, rbac.ActionPushVersion)
 	}))
 	s.Run("SoftDeleteTemplateByID", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
@@ -772,7 +943,64 @@ func (s *MethodTestSuite) TestTemplate() {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
 		check.Args(database.UpdateTemplateACLByIDParams{
 			ID: t1.ID,
-		}).Asserts(t1, rbac.ActionCreate)
+		}).Asserts(t1, rbac.ActionManageACL)
+	}))
+	s.Run("GetTemplateAccessRequestByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		r := dbgen.TemplateAccessRequest(s.T(), db, database.TemplateAccessRequest{TemplateID: t1.ID})
+		check.Args(r.ID).Asserts(t1, rbac.ActionManageACL).Returns(r)
+	}))
+	s.Run("GetTemplateAccessRequestsByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		r := dbgen.TemplateAccessRequest(s.T(), db, database.TemplateAccessRequest{TemplateID: t1.ID})
+		check.Args(database.GetTemplateAccessRequestsByTemplateIDParams{
+			TemplateID: t1.ID,
+		}).Asserts(t1, rbac.ActionManageACL).Returns([]database.TemplateAccessRequest{r})
+	}))
+	s.Run("InsertTemplateAccessRequest", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.InsertTemplateAccessRequestParams{
+			ID:          uuid.New(),
+			TemplateID:  t1.ID,
+			RequestedBy: u.ID,
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionUpdate)
+	}))
+	s.Run("UpdateTemplateAccessRequestStatusByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		r := dbgen.TemplateAccessRequest(s.T(), db, database.TemplateAccessRequest{TemplateID: t1.ID})
+		check.Args(database.UpdateTemplateAccessRequestStatusByIDParams{
+			ID:     r.ID,
+			Status: database.TemplateAccessRequestStatusApproved,
+		}).Asserts(t1, rbac.ActionManageACL)
+	}))
+	s.Run("GetTemplateVersionPromotionByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		p := dbgen.TemplateVersionPromotion(s.T(), db, database.TemplateVersionPromotion{OrganizationID: t1.OrganizationID, TemplateID: t1.ID})
+		check.Args(p.ID).Asserts(p, rbac.ActionRead).Returns(p)
+	}))
+	s.Run("GetTemplateVersionPromotions", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		p := dbgen.TemplateVersionPromotion(s.T(), db, database.TemplateVersionPromotion{OrganizationID: t1.OrganizationID, TemplateID: t1.ID})
+		check.Args(database.GetTemplateVersionPromotionsParams{
+			TemplateID: t1.ID,
+		}).Asserts(p, rbac.ActionRead).Returns([]database.TemplateVersionPromotion{p})
+	}))
+	s.Run("InsertTemplateVersionPromotion", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.InsertTemplateVersionPromotionParams{
+			ID:             uuid.New(),
+			OrganizationID: t1.OrganizationID,
+			TemplateID:     t1.ID,
+		}).Asserts(t1, rbac.ActionPushVersion)
+	}))
+	s.Run("UpdateTemplateVersionPromotionStatusByID", s.Subtest(func(db database.Store, check *expects) {
+		t1 := dbgen.Template(s.T(), db, database.Template{})
+		p := dbgen.TemplateVersionPromotion(s.T(), db, database.TemplateVersionPromotion{OrganizationID: t1.OrganizationID, TemplateID: t1.ID})
+		check.Args(database.UpdateTemplateVersionPromotionStatusByIDParams{
+			ID:     p.ID,
+			Status: database.TemplateVersionPromotionStatusApproved,
+		}).Asserts(p, rbac.ActionUpdate)
 	}))
 	s.Run("UpdateTemplateActiveVersionByID", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{
@@ -785,7 +1013,7 @@ func (s *MethodTestSuite) TestTemplate() {
 		check.Args(database.UpdateTemplateActiveVersionByIDParams{
 			ID:              t1.ID,
 			ActiveVersionID: tv.ID,
-		}).Asserts(t1, rbac.ActionUpdate).Returns()
+		}).Asserts(t1, rbac.ActionPushVersion).Returns()
 	}))
 	s.Run("UpdateTemplateDeletedByID", s.Subtest(func(db database.Store, check *expects) {
 		t1 := dbgen.Template(s.T(), db, database.Template{})
@@ -810,7 +1038,7 @@ func (s *MethodTestSuite) TestTemplate() {
 			TemplateID: uuid.NullUUID{UUID: t1.ID, Valid: true},
 			Name:       tv.Name,
 			UpdatedAt:  tv.UpdatedAt,
-		}).Asserts(t1, rbac.ActionUpdate)
+		}).Asserts(t1, rbac.ActionPushVersion)
 	}))
 	s.Run("UpdateTemplateVersionDescriptionByJobID", s.Subtest(func(db database.Store, check *expects) {
 		jobID := uuid.New()
@@ -843,14 +1071,99 @@ func (s *MethodTestSuite) TestUser() {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(u.ID).Asserts(rbac.ResourceAPIKey.WithOwner(u.ID.String()), rbac.ActionDelete).Returns()
 	}))
-	s.Run("GetQuotaAllowanceForUser", s.Subtest(func(db database.Store, check *expects) {
+	s.Run("GetQuotaBudgetsForUser", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
-		check.Args(u.ID).Asserts(u, rbac.ActionRead).Returns(int64(0))
+		check.Args(u.ID).Asserts(u, rbac.ActionRead)
 	}))
 	s.Run("GetQuotaConsumedForUser", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(u.ID).Asserts(u, rbac.ActionRead).Returns(int64(0))
 	}))
+	s.Run("GetQuotaConsumedWorkspacesForUser", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(u, rbac.ActionRead)
+	}))
+	s.Run("GetUserQuotaOverride", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o, err := db.UpsertUserQuotaOverride(context.Background(), database.UpsertUserQuotaOverrideParams{
+			UserID:         u.ID,
+			QuotaAllowance: 10,
+			CreatedAt:      database.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(u.ID).Asserts(u, rbac.ActionRead).Returns(o)
+	}))
+	s.Run("UpsertUserQuotaOverride", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserQuotaOverrideParams{
+			UserID:         u.ID,
+			QuotaAllowance: 10,
+			CreatedAt:      database.Now(),
+		}).Asserts(u, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteUserQuotaOverride", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(u, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetUserPTYSessionOverride", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		o, err := db.UpsertUserPTYSessionOverride(context.Background(), database.UpsertUserPTYSessionOverrideParams{
+			UserID:         u.ID,
+			MaxPTYSessions: 10,
+			CreatedAt:      database.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(u.ID).Asserts(u, rbac.ActionRead).Returns(o)
+	}))
+	s.Run("UpsertUserPTYSessionOverride", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserPTYSessionOverrideParams{
+			UserID:         u.ID,
+			MaxPTYSessions: 10,
+			CreatedAt:      database.Now(),
+		}).Asserts(u, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteUserPTYSessionOverride", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(u, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetUserRegionLatencies", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetUserRegionLatenciesParams{
+			UserID: u.ID,
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead)
+	}))
+	s.Run("GetNetcheckReports", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetNetcheckReportsParams{
+			EndTime: database.Now(),
+		}).Asserts(rbac.ResourceDeploymentStats, rbac.ActionRead)
+	}))
+	s.Run("User/GetNetcheckReports", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetNetcheckReportsParams{
+			UserID:  u.ID,
+			EndTime: database.Now(),
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead)
+	}))
+	s.Run("InsertNetcheckReport", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertNetcheckReportParams{
+			ID:              uuid.New(),
+			UserID:          u.ID,
+			CreatedAt:       database.Now(),
+			NATType:         "easy",
+			RegionLatencies: []byte("{}"),
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionUpdate)
+	}))
+	s.Run("UpsertUserRegionLatency", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.UpsertUserRegionLatencyParams{
+			UserID:    u.ID,
+			RegionID:  uuid.New(),
+			LatencyMS: 10,
+			UpdatedAt: database.Now(),
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionUpdate).Returns()
+	}))
 	s.Run("GetUserByEmailOrUsername", s.Subtest(func(db database.Store, check *expects) {
 		u := dbgen.User(s.T(), db, database.User{})
 		check.Args(database.GetUserByEmailOrUsernameParams{
@@ -1007,6 +1320,61 @@ func (s *MethodTestSuite) TestWorkspace() {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		check.Args(ws.ID).Asserts(ws, rbac.ActionRead)
 	}))
+	s.Run("GetWorkspaceAgentBandwidthStatsByPeer", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.GetWorkspaceAgentBandwidthStatsByPeerParams{
+			WorkspaceID:  ws.ID,
+			CreatedAfter: time.Now().Add(-time.Hour),
+		}).Asserts(ws, rbac.ActionRead).Returns([]database.GetWorkspaceAgentBandwidthStatsByPeerRow{})
+	}))
+	s.Run("GetWorkspaceExternalMetadataByWorkspaceIDs", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		public, err := db.UpsertWorkspaceExternalMetadatum(context.Background(), database.UpsertWorkspaceExternalMetadatumParams{
+			WorkspaceID: ws.ID,
+			Namespace:   "a",
+			Visibility:  database.WorkspaceExternalMetadataVisibilityPublic,
+			Data:        json.RawMessage(`{}`),
+		})
+		require.NoError(s.T(), err)
+		private, err := db.UpsertWorkspaceExternalMetadatum(context.Background(), database.UpsertWorkspaceExternalMetadatumParams{
+			WorkspaceID: ws.ID,
+			Namespace:   "b",
+			Visibility:  database.WorkspaceExternalMetadataVisibilityPrivate,
+			Data:        json.RawMessage(`{}`),
+		})
+		require.NoError(s.T(), err)
+		check.Args([]uuid.UUID{ws.ID}).
+			Asserts(ws.ExternalMetadataRBAC(public.Visibility), rbac.ActionRead, ws.ExternalMetadataRBAC(private.Visibility), rbac.ActionRead)
+	}))
+	s.Run("UpsertWorkspaceExternalMetadatum", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpsertWorkspaceExternalMetadatumParams{
+			WorkspaceID: ws.ID,
+			Namespace:   "a",
+			Visibility:  database.WorkspaceExternalMetadataVisibilityPrivate,
+			Data:        json.RawMessage(`{}`),
+		}).Asserts(ws.ExternalMetadataRBAC(database.WorkspaceExternalMetadataVisibilityPrivate), rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspaceExternalMetadatum", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		_, err := db.UpsertWorkspaceExternalMetadatum(context.Background(), database.UpsertWorkspaceExternalMetadatumParams{
+			WorkspaceID: ws.ID,
+			Namespace:   "a",
+			Visibility:  database.WorkspaceExternalMetadataVisibilityPrivate,
+			Data:        json.RawMessage(`{}`),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.DeleteWorkspaceExternalMetadatumParams{
+			WorkspaceID: ws.ID,
+			Namespace:   "a",
+		}).Asserts(ws.ExternalMetadataRBAC(database.WorkspaceExternalMetadataVisibilityPrivate), rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetOutdatedWorkspacesByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{TemplateID: tpl.ID})
+		_ = dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		check.Args(tpl.ID).Asserts(ws, rbac.ActionRead).Returns([]database.Workspace{ws})
+	}))
 	s.Run("GetWorkspaces", s.Subtest(func(db database.Store, check *expects) {
 		_ = dbgen.Workspace(s.T(), db, database.Workspace{})
 		_ = dbgen.Workspace(s.T(), db, database.Workspace{})
@@ -1038,6 +1406,57 @@ func (s *MethodTestSuite) TestWorkspace() {
 		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
 		check.Args(agt.AuthInstanceID.String).Asserts(ws, rbac.ActionRead).Returns(agt)
 	}))
+	s.Run("InsertWorkspaceAgentMetadataHistory", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.InsertWorkspaceAgentMetadataHistoryParams{
+			WorkspaceAgentID: agt.ID,
+			Key:              "cpu",
+			Value:            "42",
+			CollectedAt:      database.Now(),
+			MaxEntries:       10,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("GetWorkspaceAgentMetadataHistory", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.GetWorkspaceAgentMetadataHistoryParams{
+			WorkspaceAgentID: agt.ID,
+			Key:              "cpu",
+			LimitOpt:         10,
+		}).Asserts(ws, rbac.ActionRead).Returns([]database.WorkspaceAgentMetadataHistory{})
+	}))
+	s.Run("GetWorkspaceAgentClientConnections", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.GetWorkspaceAgentClientConnectionsParams{
+			WorkspaceAgentID: agt.ID,
+			LimitOpt:         10,
+		}).Asserts(ws, rbac.ActionRead).Returns([]database.WorkspaceAgentClientConnection{})
+	}))
+	s.Run("GetWorkspaceAgentClientConnectionsByUserID", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.GetWorkspaceAgentClientConnectionsByUserIDParams{
+			UserID:   uuid.NullUUID{UUID: u.ID, Valid: true},
+			LimitOpt: 10,
+		}).Asserts(rbac.ResourceUserData.WithOwner(u.ID.String()).WithID(u.ID), rbac.ActionRead).Returns([]database.GetWorkspaceAgentClientConnectionsByUserIDRow{})
+	}))
+	s.Run("UpdateWorkspaceAgentClockOffsetByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.UpdateWorkspaceAgentClockOffsetByIDParams{
+			ID:            agt.ID,
+			ClockOffsetMS: 45000,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceAgentLifecycleStateByID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1048,6 +1467,40 @@ func (s *MethodTestSuite) TestWorkspace() {
 			LifecycleState: database.WorkspaceAgentLifecycleStateCreated,
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("UpdateWorkspaceAgentAuthTokenByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.UpdateWorkspaceAgentAuthTokenByIDParams{
+			ID:        agt.ID,
+			AuthToken: uuid.New(),
+			UpdatedAt: database.Now(),
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+	s.Run("InsertWorkspaceAgentAuthTokenRotation", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.InsertWorkspaceAgentAuthTokenRotationParams{
+			ID:                         uuid.New(),
+			AgentID:                    agt.ID,
+			PreviousAuthToken:          agt.AuthToken,
+			PreviousAuthTokenExpiresAt: database.Now(),
+			CreatedAt:                  database.Now(),
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("ExpireWorkspaceAgentAuthTokenRotations", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.ExpireWorkspaceAgentAuthTokenRotationsParams{
+			Now:     database.Now(),
+			AgentID: agt.ID,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceAgentLogOverflowByID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1147,6 +1600,53 @@ func (s *MethodTestSuite) TestWorkspace() {
 			Name:    ws.Name,
 		}).Asserts(ws, rbac.ActionRead).Returns(ws)
 	}))
+	s.Run("GetWorkspaceByOwnerIDAndPreviousName", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		err := db.UpsertWorkspacePreviousName(context.Background(), database.UpsertWorkspacePreviousNameParams{
+			WorkspaceID:       ws.ID,
+			Name:              "previous",
+			RenamedAt:         time.Now(),
+			RedirectExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetWorkspaceByOwnerIDAndPreviousNameParams{
+			OwnerID: ws.OwnerID,
+			Name:    "previous",
+		}).Asserts(ws, rbac.ActionRead).Returns(ws)
+	}))
+	s.Run("GetWorkspacePreviousNamesByWorkspaceIDs", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		renamedAt := time.Now()
+		err := db.UpsertWorkspacePreviousName(context.Background(), database.UpsertWorkspacePreviousNameParams{
+			WorkspaceID:       ws.ID,
+			Name:              "previous",
+			RenamedAt:         renamedAt,
+			RedirectExpiresAt: renamedAt.Add(time.Hour),
+		})
+		require.NoError(s.T(), err)
+		check.Args([]uuid.UUID{ws.ID}).Asserts(ws, rbac.ActionRead).Returns([]database.GetWorkspacePreviousNamesByWorkspaceIDsRow{{
+			WorkspaceID:       ws.ID,
+			Name:              "previous",
+			RenamedAt:         renamedAt,
+			RedirectExpiresAt: renamedAt.Add(time.Hour),
+			OwnerID:           ws.OwnerID,
+			OrganizationID:    ws.OrganizationID,
+		}})
+	}))
+	s.Run("UpsertWorkspacePreviousName", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpsertWorkspacePreviousNameParams{
+			WorkspaceID: ws.ID,
+			Name:        "previous",
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspacePreviousName", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.DeleteWorkspacePreviousNameParams{
+			WorkspaceID: ws.ID,
+			Name:        "previous",
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
 	s.Run("GetWorkspaceResourceByID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1170,9 +1670,10 @@ func (s *MethodTestSuite) TestWorkspace() {
 		u := dbgen.User(s.T(), db, database.User{})
 		o := dbgen.Organization(s.T(), db, database.Organization{})
 		check.Args(database.InsertWorkspaceParams{
-			ID:             uuid.New(),
-			OwnerID:        u.ID,
-			OrganizationID: o.ID,
+			ID:               uuid.New(),
+			OwnerID:          u.ID,
+			OrganizationID:   o.ID,
+			AutomaticUpdates: database.AutomaticUpdatesNever,
 		}).Asserts(rbac.ResourceWorkspace.WithOwner(u.ID.String()).InOrg(o.ID), rbac.ActionCreate)
 	}))
 	s.Run("Start/InsertWorkspaceBuild", s.Subtest(func(db database.Store, check *expects) {
@@ -1225,6 +1726,13 @@ func (s *MethodTestSuite) TestWorkspace() {
 			Health: database.WorkspaceAppHealthDisabled,
 		}).Asserts(ws, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("UpdateWorkspaceAutomaticUpdates", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.UpdateWorkspaceAutomaticUpdatesParams{
+			ID:               ws.ID,
+			AutomaticUpdates: database.AutomaticUpdatesAlways,
+		}).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("UpdateWorkspaceAutostart", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		check.Args(database.UpdateWorkspaceAutostartParams{
@@ -1275,6 +1783,158 @@ func (s *MethodTestSuite) TestWorkspace() {
 	}))
 }
 
+func (s *MethodTestSuite) TestWorkspaceWebhooks() {
+	s.Run("GetWorkspaceWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		webhook := dbgen.WorkspaceWebhook(s.T(), db, database.WorkspaceWebhook{WorkspaceID: ws.ID})
+		check.Args(webhook.ID).Asserts(ws, rbac.ActionUpdate).Returns(webhook)
+	}))
+	s.Run("GetWorkspaceWebhooksByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		webhook := dbgen.WorkspaceWebhook(s.T(), db, database.WorkspaceWebhook{WorkspaceID: ws.ID})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionUpdate).Returns([]database.WorkspaceWebhook{webhook})
+	}))
+	s.Run("InsertWorkspaceWebhook", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.InsertWorkspaceWebhookParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			Name:        "ci",
+			Action:      database.WorkspaceWebhookActionStart,
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWorkspaceWebhookLastTriggeredAt", s.Subtest(func(db database.Store, check *expects) {
+		webhook := dbgen.WorkspaceWebhook(s.T(), db, database.WorkspaceWebhook{})
+		webhook.LastTriggeredAt = sql.NullTime{Time: database.Now(), Valid: true}
+		check.Args(database.UpdateWorkspaceWebhookLastTriggeredAtParams{
+			ID:              webhook.ID,
+			LastTriggeredAt: webhook.LastTriggeredAt,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns(webhook)
+	}))
+	s.Run("DeleteWorkspaceWebhookByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		webhook := dbgen.WorkspaceWebhook(s.T(), db, database.WorkspaceWebhook{WorkspaceID: ws.ID})
+		check.Args(webhook.ID).Asserts(ws, rbac.ActionUpdate).Returns()
+	}))
+}
+
+func (s *MethodTestSuite) TestWorkspaceBuildQueue() {
+	s.Run("GetWorkspaceBuildQueueEntriesByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		entry := dbgen.WorkspaceBuildQueueEntry(s.T(), db, database.WorkspaceBuildQueueEntry{WorkspaceID: ws.ID})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead).Returns([]database.WorkspaceBuildQueueEntry{entry})
+	}))
+	s.Run("GetWorkspaceBuildQueueWorkspaceIDs", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		_ = dbgen.WorkspaceBuildQueueEntry(s.T(), db, database.WorkspaceBuildQueueEntry{WorkspaceID: ws.ID})
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns([]uuid.UUID{ws.ID})
+	}))
+	s.Run("InsertWorkspaceBuildQueueEntry", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.InsertWorkspaceBuildQueueEntryParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			InitiatorID: uuid.New(),
+			Request:     []byte(`{"transition":"start"}`),
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteWorkspaceBuildQueueEntryByID", s.Subtest(func(db database.Store, check *expects) {
+		entry := dbgen.WorkspaceBuildQueueEntry(s.T(), db, database.WorkspaceBuildQueueEntry{})
+		check.Args(entry.ID).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("DeleteWorkspaceBuildQueueEntriesByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		_ = dbgen.WorkspaceBuildQueueEntry(s.T(), db, database.WorkspaceBuildQueueEntry{WorkspaceID: ws.ID})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionUpdate)
+	}))
+}
+
+func (s *MethodTestSuite) TestWorkspaceApprovals() {
+	s.Run("GetWorkspaceApprovalByID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		approval := dbgen.WorkspaceApproval(s.T(), db, database.WorkspaceApproval{WorkspaceID: ws.ID})
+		check.Args(approval.ID).Asserts(ws, rbac.ActionRead).Returns(approval)
+	}))
+	s.Run("GetWorkspaceApprovalByJobID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		approval := dbgen.WorkspaceApproval(s.T(), db, database.WorkspaceApproval{WorkspaceID: ws.ID})
+		check.Args(approval.JobID).Asserts(ws, rbac.ActionRead).Returns(approval)
+	}))
+	s.Run("GetLatestWorkspaceApprovalByWorkspaceID", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		approval := dbgen.WorkspaceApproval(s.T(), db, database.WorkspaceApproval{WorkspaceID: ws.ID})
+		check.Args(ws.ID).Asserts(ws, rbac.ActionRead).Returns(approval)
+	}))
+	s.Run("InsertWorkspaceApproval", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.InsertWorkspaceApprovalParams{
+			ID:          uuid.New(),
+			WorkspaceID: ws.ID,
+			JobID:       uuid.New(),
+		}).Asserts(ws, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateWorkspaceApprovalStatusByID", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{TemplateID: tpl.ID})
+		approval := dbgen.WorkspaceApproval(s.T(), db, database.WorkspaceApproval{WorkspaceID: ws.ID})
+		check.Args(database.UpdateWorkspaceApprovalStatusByIDParams{
+			ID:     approval.ID,
+			Status: database.WorkspaceApprovalStatusApproved,
+		}).Asserts(tpl, rbac.ActionUpdate)
+	}))
+}
+
+func (s *MethodTestSuite) TestManagedEnvironmentVariables() {
+	s.Run("Organization/GetManagedEnvironmentVariableByID", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		v := dbgen.ManagedEnvironmentVariable(s.T(), db, database.ManagedEnvironmentVariable{OrganizationID: o.ID})
+		check.Args(v.ID).Asserts(o, rbac.ActionRead).Returns(v)
+	}))
+	s.Run("Template/GetManagedEnvironmentVariableByID", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		v := dbgen.ManagedEnvironmentVariable(s.T(), db, database.ManagedEnvironmentVariable{
+			OrganizationID: tpl.OrganizationID,
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+		})
+		check.Args(v.ID).Asserts(tpl, rbac.ActionRead).Returns(v)
+	}))
+	s.Run("GetManagedEnvironmentVariablesByOrganizationID", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		v := dbgen.ManagedEnvironmentVariable(s.T(), db, database.ManagedEnvironmentVariable{OrganizationID: o.ID})
+		check.Args(o.ID).Asserts(o, rbac.ActionRead).Returns([]database.ManagedEnvironmentVariable{v})
+	}))
+	s.Run("GetManagedEnvironmentVariablesByTemplateID", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		templateID := uuid.NullUUID{UUID: tpl.ID, Valid: true}
+		v := dbgen.ManagedEnvironmentVariable(s.T(), db, database.ManagedEnvironmentVariable{
+			OrganizationID: tpl.OrganizationID,
+			TemplateID:     templateID,
+		})
+		check.Args(templateID).Asserts(tpl, rbac.ActionRead).Returns([]database.ManagedEnvironmentVariable{v})
+	}))
+	s.Run("InsertManagedEnvironmentVariable", s.Subtest(func(db database.Store, check *expects) {
+		tpl := dbgen.Template(s.T(), db, database.Template{})
+		check.Args(database.InsertManagedEnvironmentVariableParams{
+			ID:             uuid.New(),
+			OrganizationID: tpl.OrganizationID,
+			TemplateID:     uuid.NullUUID{UUID: tpl.ID, Valid: true},
+			Name:           "HTTPS_PROXY",
+		}).Asserts(tpl, rbac.ActionUpdate)
+	}))
+	s.Run("UpdateManagedEnvironmentVariableByID", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		v := dbgen.ManagedEnvironmentVariable(s.T(), db, database.ManagedEnvironmentVariable{OrganizationID: o.ID})
+		check.Args(database.UpdateManagedEnvironmentVariableByIDParams{
+			ID: v.ID,
+		}).Asserts(o, rbac.ActionUpdate)
+	}))
+	s.Run("DeleteManagedEnvironmentVariableByID", s.Subtest(func(db database.Store, check *expects) {
+		o := dbgen.Organization(s.T(), db, database.Organization{})
+		v := dbgen.ManagedEnvironmentVariable(s.T(), db, database.ManagedEnvironmentVariable{OrganizationID: o.ID})
+		check.Args(v.ID).Asserts(o, rbac.ActionUpdate).Returns()
+	}))
+}
+
 func (s *MethodTestSuite) TestExtraMethods() {
 	s.Run("GetProvisionerDaemons", s.Subtest(func(db database.Store, check *expects) {
 		d, err := db.InsertProvisionerDaemon(context.Background(), database.InsertProvisionerDaemonParams{
@@ -1283,6 +1943,16 @@ func (s *MethodTestSuite) TestExtraMethods() {
 		s.NoError(err, "insert provisioner daemon")
 		check.Args().Asserts(d, rbac.ActionRead)
 	}))
+	s.Run("GetPendingProvisionerJobs", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
+		check.Args().Asserts(rbac.ResourceProvisionerDaemon, rbac.ActionUpdate).Returns([]database.ProvisionerJob{j})
+	}))
+	s.Run("GetProvisionerJobsStartedAfter", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{
+			StartedAt: sql.NullTime{Time: database.Now(), Valid: true},
+		})
+		check.Args(database.Now().Add(-time.Hour)).Asserts(rbac.ResourceProvisionerDaemon, rbac.ActionUpdate).Returns([]database.ProvisionerJob{j})
+	}))
 }
 
 func (s *MethodTestSuite) TestSystemFunctions() {
@@ -1303,6 +1973,14 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("UpsertDefaultProxy", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.UpsertDefaultProxyParams{}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns()
 	}))
+	s.Run("GetSSHCAKey", s.Subtest(func(db database.Store, check *expects) {
+		err := db.UpsertSSHCAKey(context.Background(), "key")
+		require.NoError(s.T(), err)
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns("key")
+	}))
+	s.Run("UpsertSSHCAKey", s.Subtest(func(db database.Store, check *expects) {
+		check.Args("key").Asserts(rbac.ResourceSystem, rbac.ActionUpdate).Returns()
+	}))
 	s.Run("GetUserLinkByLinkedID", s.Subtest(func(db database.Store, check *expects) {
 		l := dbgen.UserLink(s.T(), db, database.UserLink{})
 		check.Args(l.LinkedID).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(l)
@@ -1351,6 +2029,14 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			DatabaseLatency: 100,
 		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
 	}))
+	s.Run("UpdateReplicaDrainingAt", s.Subtest(func(db database.Store, check *expects) {
+		replica, err := db.InsertReplica(context.Background(), database.InsertReplicaParams{ID: uuid.New()})
+		require.NoError(s.T(), err)
+		check.Args(database.UpdateReplicaDrainingAtParams{
+			ID:         replica.ID,
+			DrainingAt: sql.NullTime{Time: time.Now(), Valid: true},
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 	s.Run("DeleteReplicasUpdatedBefore", s.Subtest(func(db database.Store, check *expects) {
 		_, err := db.InsertReplica(context.Background(), database.InsertReplicaParams{ID: uuid.New(), UpdatedAt: time.Now()})
 		require.NoError(s.T(), err)
@@ -1364,6 +2050,30 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 	s.Run("GetUserCount", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(int64(0))
 	}))
+	s.Run("InsertUserDeprovision", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(database.InsertUserDeprovisionParams{
+			UserID:          u.ID,
+			DeprovisionedAt: time.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetPendingGroupDefaultTemplateWorkspaces", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("InsertGroupDefaultTemplateWorkspace", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertGroupDefaultTemplateWorkspaceParams{
+			TemplateID: uuid.New(),
+			UserID:     uuid.New(),
+			GroupID:    uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetUserDeprovisions", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("DeleteUserDeprovision", s.Subtest(func(db database.Store, check *expects) {
+		u := dbgen.User(s.T(), db, database.User{})
+		check.Args(u.ID).Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
 	s.Run("GetTemplates", s.Subtest(func(db database.Store, check *expects) {
 		_ = dbgen.Template(s.T(), db, database.Template{})
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionRead)
@@ -1393,6 +2103,35 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		_ = dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{CreatedAt: time.Now().Add(-time.Hour)})
 		check.Args(time.Now()).Asserts(rbac.ResourceSystem, rbac.ActionRead)
 	}))
+	s.Run("GetWorkspaceAgentSSHHostKey", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		key, err := db.InsertWorkspaceAgentSSHHostKey(context.Background(), database.InsertWorkspaceAgentSSHHostKeyParams{
+			WorkspaceID:         ws.ID,
+			AgentName:           "main",
+			EncryptedPrivateKey: "encrypted",
+			CreatedAt:           time.Now(),
+		})
+		require.NoError(s.T(), err)
+		check.Args(database.GetWorkspaceAgentSSHHostKeyParams{
+			WorkspaceID: ws.ID,
+			AgentName:   "main",
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead).Returns(key)
+	}))
+	s.Run("InsertWorkspaceAgentSSHHostKey", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		check.Args(database.InsertWorkspaceAgentSSHHostKeyParams{
+			WorkspaceID:         ws.ID,
+			AgentName:           "main",
+			EncryptedPrivateKey: "encrypted",
+			CreatedAt:           time.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetWorkspaceAgentsNeverConnected", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetWorkspaceAgentsNeverConnectedParams{
+			DeadlineAfter:  time.Now().Add(-time.Hour),
+			DeadlineBefore: time.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
 	s.Run("GetWorkspaceAppsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		_ = dbgen.WorkspaceApp(s.T(), db, database.WorkspaceApp{CreatedAt: time.Now().Add(-time.Hour)})
 		check.Args(time.Now()).Asserts(rbac.ResourceSystem, rbac.ActionRead)
@@ -1405,9 +2144,33 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 		_ = dbgen.WorkspaceResourceMetadatums(s.T(), db, database.WorkspaceResourceMetadatum{})
 		check.Args(time.Now()).Asserts(rbac.ResourceSystem, rbac.ActionRead)
 	}))
+	s.Run("DeleteOldPlatformEvents", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("DeleteOldNetcheckReports", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("DeleteOldWorkspaceAgentClientConnections", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("DeleteOldWorkspaceAgentAuthTokenRotations", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
+	s.Run("DeleteOldWorkspaceAgentBandwidthStats", s.Subtest(func(db database.Store, check *expects) {
+		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
+	}))
 	s.Run("DeleteOldWorkspaceAgentStats", s.Subtest(func(db database.Store, check *expects) {
 		check.Args().Asserts(rbac.ResourceSystem, rbac.ActionDelete)
 	}))
+	s.Run("InsertWorkspaceAgentBandwidthStats", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.InsertWorkspaceAgentBandwidthStatsParams{}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("GetWorkspaceAgentBandwidthStatsAndLabels", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(time.Now()).Asserts(rbac.ResourceSystem, rbac.ActionRead)
+	}))
+	s.Run("GetUserBandwidthInsights", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.GetUserBandwidthInsightsParams{}).Asserts(rbac.ResourceTemplate.All(), rbac.ActionUpdate)
+	}))
 	s.Run("GetProvisionerJobsCreatedAfter", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: add provisioner job resource type
 		_ = dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{CreatedAt: time.Now().Add(-time.Hour)})
@@ -1484,6 +2247,12 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			Asserts( /*rbac.ResourceSystem, rbac.ActionRead*/ ).
 			Returns(slice.New(a, b))
 	}))
+	s.Run("GetProvisionerJobConcurrencyLimit", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
+		check.Args(j.ID).
+			Asserts(rbac.ResourceSystem, rbac.ActionRead).
+			Returns("")
+	}))
 	s.Run("InsertWorkspaceAgent", s.Subtest(func(db database.Store, check *expects) {
 		check.Args(database.InsertWorkspaceAgentParams{
 			ID:                    uuid.New(),
@@ -1502,6 +2271,23 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			WorkspaceResourceID: uuid.New(),
 		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
 	}))
+	s.Run("InsertWorkspaceAgentClientConnection", s.Subtest(func(db database.Store, check *expects) {
+		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
+		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
+		res := dbgen.WorkspaceResource(s.T(), db, database.WorkspaceResource{JobID: build.JobID})
+		agt := dbgen.WorkspaceAgent(s.T(), db, database.WorkspaceAgent{ResourceID: res.ID})
+		check.Args(database.InsertWorkspaceAgentClientConnectionParams{
+			ID:               uuid.New(),
+			WorkspaceAgentID: agt.ID,
+			ClientType:       "ssh",
+			ConnectedAt:      database.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
+	s.Run("UpdateWorkspaceAgentClientConnectionDisconnectedAt", s.Subtest(func(db database.Store, check *expects) {
+		check.Args(database.UpdateWorkspaceAgentClientConnectionDisconnectedAtParams{
+			ID: uuid.New(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 	s.Run("UpdateWorkspaceAgentConnectionByID", s.Subtest(func(db database.Store, check *expects) {
 		ws := dbgen.Workspace(s.T(), db, database.Workspace{})
 		build := dbgen.WorkspaceBuild(s.T(), db, database.WorkspaceBuild{WorkspaceID: ws.ID, JobID: uuid.New()})
@@ -1526,6 +2312,13 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			ID: j.ID,
 		}).Asserts( /*rbac.ResourceSystem, rbac.ActionUpdate*/ )
 	}))
+	s.Run("UpdateProvisionerJobWithRequeueByID", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
+		check.Args(database.UpdateProvisionerJobWithRequeueByIDParams{
+			ID:        j.ID,
+			UpdatedAt: time.Now(),
+		}).Asserts(rbac.ResourceSystem, rbac.ActionUpdate)
+	}))
 	s.Run("UpdateProvisionerJobByID", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: we need to create a ProvisionerJob resource
 		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
@@ -1550,6 +2343,15 @@ func (s *MethodTestSuite) TestSystemFunctions() {
 			JobID: j.ID,
 		}).Asserts( /*rbac.ResourceSystem, rbac.ActionCreate*/ )
 	}))
+	s.Run("InsertProvisionerJobBuildHook", s.Subtest(func(db database.Store, check *expects) {
+		j := dbgen.ProvisionerJob(s.T(), db, database.ProvisionerJob{})
+		check.Args(database.InsertProvisionerJobBuildHookParams{
+			ID:    uuid.New(),
+			JobID: j.ID,
+			Name:  "register-dns",
+			Phase: database.BuildHookPhasePre,
+		}).Asserts(rbac.ResourceSystem, rbac.ActionCreate)
+	}))
 	s.Run("InsertProvisionerDaemon", s.Subtest(func(db database.Store, check *expects) {
 		// TODO: we need to create a ProvisionerDaemon resource
 		check.Args(database.InsertProvisionerDaemonParams{
@@ -114,35 +114,63 @@ type data struct {
 	userLinks           []database.UserLink
 
 	// New tables
-	workspaceAgentStats           []database.WorkspaceAgentStat
-	auditLogs                     []database.AuditLog
-	files                         []database.File
-	gitAuthLinks                  []database.GitAuthLink
-	gitSSHKey                     []database.GitSSHKey
-	groupMembers                  []database.GroupMember
-	groups                        []database.Group
-	licenses                      []database.License
-	parameterSchemas              []database.ParameterSchema
-	provisionerDaemons            []database.ProvisionerDaemon
-	provisionerJobLogs            []database.ProvisionerJobLog
-	provisionerJobs               []database.ProvisionerJob
-	replicas                      []database.Replica
-	templateVersions              []database.TemplateVersionTable
-	templateVersionParameters     []database.TemplateVersionParameter
-	templateVersionVariables      []database.TemplateVersionVariable
-	templates                     []database.TemplateTable
-	workspaceAgents               []database.WorkspaceAgent
-	workspaceAgentMetadata        []database.WorkspaceAgentMetadatum
-	workspaceAgentLogs            []database.WorkspaceAgentLog
-	workspaceApps                 []database.WorkspaceApp
-	workspaceAppStatsLastInsertID int64
-	workspaceAppStats             []database.WorkspaceAppStat
-	workspaceBuilds               []database.WorkspaceBuildTable
-	workspaceBuildParameters      []database.WorkspaceBuildParameter
-	workspaceResourceMetadata     []database.WorkspaceResourceMetadatum
-	workspaceResources            []database.WorkspaceResource
-	workspaces                    []database.Workspace
-	workspaceProxies              []database.WorkspaceProxy
+	workspaceAgentStats                       []database.WorkspaceAgentStat
+	workspaceAgentAuthTokenRotations          []database.WorkspaceAgentAuthTokenRotation
+	workspaceAgentBandwidthStats              []database.WorkspaceAgentBandwidthStat
+	auditLogArchivals                         []database.AuditLogArchival
+	auditLogs                                 []database.AuditLog
+	entitlementSnapshots                      []database.EntitlementSnapshot
+	files                                     []database.File
+	gitAuthLinks                              []database.GitAuthLink
+	gitSSHKey                                 []database.GitSSHKey
+	groupDefaultTemplates                     []database.GroupDefaultTemplate
+	groupDefaultTemplateWorkspaces            []database.GroupDefaultTemplateWorkspace
+	groupMembers                              []database.GroupMember
+	groups                                    []database.Group
+	licenses                                  []database.License
+	licenseUsage                              []database.LicenseUsage
+	managedEnvironmentVariables               []database.ManagedEnvironmentVariable
+	netcheckReports                           []database.NetcheckReport
+	parameterSchemas                          []database.ParameterSchema
+	platformEvents                            []database.PlatformEvent
+	platformEventsLastInsertID                int64
+	provisionerDaemons                        []database.ProvisionerDaemon
+	provisionerJobBuildHooks                  []database.ProvisionerJobBuildHook
+	provisionerJobLogs                        []database.ProvisionerJobLog
+	provisionerJobs                           []database.ProvisionerJob
+	replicas                                  []database.Replica
+	templateAccessRequests                    []database.TemplateAccessRequest
+	templateVersions                          []database.TemplateVersionTable
+	templateVersionBuildInsights              []database.TemplateVersionBuildInsight
+	templateVersionParameters                 []database.TemplateVersionParameter
+	templateVersionPromotions                 []database.TemplateVersionPromotion
+	templateVersionVariables                  []database.TemplateVersionVariable
+	templates                                 []database.TemplateTable
+	userDeprovisions                          []database.UserDeprovision
+	userPTYSessionOverrides                   []database.UserPTYSessionOverride
+	userQuotaOverrides                        []database.UserQuotaOverride
+	userRegionLatencies                       []database.UserRegionLatency
+	workspaceAgents                           []database.WorkspaceAgent
+	workspaceAgentClientConnections           []database.WorkspaceAgentClientConnection
+	workspaceAgentMetadata                    []database.WorkspaceAgentMetadatum
+	workspaceAgentMetadataHistory             []database.WorkspaceAgentMetadataHistory
+	workspaceAgentMetadataHistoryLastInsertID int64
+	workspaceAgentLogs                        []database.WorkspaceAgentLog
+	workspaceAgentSSHHostKeys                 []database.WorkspaceAgentSSHHostKey
+	workspaceApps                             []database.WorkspaceApp
+	workspaceAppStatsLastInsertID             int64
+	workspaceAppStats                         []database.WorkspaceAppStat
+	workspaceBuilds                           []database.WorkspaceBuildTable
+	workspaceBuildParameters                  []database.WorkspaceBuildParameter
+	workspaceExternalMetadata                 []database.WorkspaceExternalMetadatum
+	workspacePreviousNames                    []database.WorkspacePreviousName
+	workspaceResourceMetadata                 []database.WorkspaceResourceMetadatum
+	workspaceResources                        []database.WorkspaceResource
+	workspaces                                []database.Workspace
+	workspaceProxies                          []database.WorkspaceProxy
+	workspaceWebhooks                         []database.WorkspaceWebhook
+	workspaceApprovals                        []database.WorkspaceApproval
+	workspaceBuildQueueEntries                []database.WorkspaceBuildQueueEntry
 	// Locks is a map of lock names. Any keys within the map are currently
 	// locked.
 	locks                   map[int64]struct{}
@@ -150,9 +178,11 @@ type data struct {
 	derpMeshKey             string
 	lastUpdateCheck         []byte
 	serviceBanner           []byte
+	runtimeExperiments      []byte
 	logoURL                 string
 	appSecurityKey          string
 	oauthSigningKey         string
+	sshCAKey                string
 	lastLicenseID           int32
 	defaultProxyDisplayName string
 	defaultProxyIconURL     string
@@ -343,6 +373,7 @@ func (q *FakeQuerier) convertToWorkspaceRowsNoLock(ctx context.Context, workspac
 			LastUsedAt:        w.LastUsedAt,
 			LockedAt:          w.LockedAt,
 			DeletingAt:        w.DeletingAt,
+			AutomaticUpdates:  w.AutomaticUpdates,
 			Count:             count,
 		}
 
@@ -423,6 +454,49 @@ func (q *FakeQuerier) getWorkspaceByAgentIDNoLock(_ context.Context, agentID uui
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+// agentStartBuildNoLock returns the build, job and workspace of the agent if
+// the agent was created by a successful start build.
+func (q *FakeQuerier) agentStartBuildNoLock(ctx context.Context, agent database.WorkspaceAgent) (database.WorkspaceBuildTable, database.ProvisionerJob, database.Workspace, bool) {
+	var resource database.WorkspaceResource
+	for _, _resource := range q.workspaceResources {
+		if _resource.ID == agent.ResourceID {
+			resource = _resource
+			break
+		}
+	}
+	if resource.ID == uuid.Nil {
+		return database.WorkspaceBuildTable{}, database.ProvisionerJob{}, database.Workspace{}, false
+	}
+	for _, build := range q.workspaceBuilds {
+		if build.JobID != resource.JobID {
+			continue
+		}
+		if build.Transition != database.WorkspaceTransitionStart {
+			break
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, build.JobID)
+		if err != nil || !job.CompletedAt.Valid || job.CanceledAt.Valid || job.Error.String != "" {
+			break
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, build.WorkspaceID)
+		if err != nil {
+			break
+		}
+		return build, job, workspace, true
+	}
+	return database.WorkspaceBuildTable{}, database.ProvisionerJob{}, database.Workspace{}, false
+}
+
+// agentConnectDeadline returns when the agent should have connected after the
+// build that created it completed.
+func agentConnectDeadline(agent database.WorkspaceAgent, job database.ProvisionerJob, defaultTimeoutSeconds int32) time.Time {
+	timeout := agent.ConnectionTimeoutSeconds
+	if timeout == 0 {
+		timeout = defaultTimeoutSeconds
+	}
+	return job.CompletedAt.Time.Add(time.Duration(timeout) * time.Second)
+}
+
 func (q *FakeQuerier) getWorkspaceBuildByIDNoLock(_ context.Context, id uuid.UUID) (database.WorkspaceBuild, error) {
 	for _, build := range q.workspaceBuilds {
 		if build.ID == id {
@@ -447,6 +521,71 @@ func (q *FakeQuerier) getLatestWorkspaceBuildByWorkspaceIDNoLock(_ context.Conte
 	return row, nil
 }
 
+// isJobHeldForApprovalNoLock reports whether the job has an approval request
+// that hasn't been approved, so provisioners must not acquire it.
+func (q *FakeQuerier) isJobHeldForApprovalNoLock(jobID uuid.UUID) bool {
+	for _, approval := range q.workspaceApprovals {
+		if approval.JobID == jobID && approval.Status != database.WorkspaceApprovalStatusApproved {
+			return true
+		}
+	}
+	return false
+}
+
+// provisionerJobTemplateIDNoLock mirrors the provisioner_job_template_id
+// function of the database.
+func (q *FakeQuerier) provisionerJobTemplateIDNoLock(jobID uuid.UUID) uuid.NullUUID {
+	for _, version := range q.templateVersions {
+		if version.JobID == jobID {
+			return version.TemplateID
+		}
+	}
+	for _, build := range q.workspaceBuilds {
+		if build.JobID != jobID {
+			continue
+		}
+		for _, workspace := range q.workspaces {
+			if workspace.ID == build.WorkspaceID {
+				return uuid.NullUUID{UUID: workspace.TemplateID, Valid: true}
+			}
+		}
+	}
+	return uuid.NullUUID{}
+}
+
+// provisionerJobConcurrencyLimitNoLock mirrors the
+// provisioner_job_concurrency_limit function of the database.
+func (q *FakeQuerier) provisionerJobConcurrencyLimitNoLock(job database.ProvisionerJob) string {
+	templateID := q.provisionerJobTemplateIDNoLock(job.ID)
+	var runningForTemplate, runningForOrganization int32
+	for _, running := range q.provisionerJobs {
+		if running.ID == job.ID || !running.StartedAt.Valid || running.CompletedAt.Valid {
+			continue
+		}
+		if running.OrganizationID == job.OrganizationID {
+			runningForOrganization++
+		}
+		if templateID.Valid && q.provisionerJobTemplateIDNoLock(running.ID) == templateID {
+			runningForTemplate++
+		}
+	}
+	if templateID.Valid {
+		for _, template := range q.templates {
+			if template.ID == templateID.UUID && template.MaxConcurrentProvisionerJobs > 0 &&
+				template.MaxConcurrentProvisionerJobs <= runningForTemplate {
+				return "template"
+			}
+		}
+	}
+	for _, org := range q.organizations {
+		if org.ID == job.OrganizationID && org.MaxConcurrentProvisionerJobs > 0 &&
+			org.MaxConcurrentProvisionerJobs <= runningForOrganization {
+			return "organization"
+		}
+	}
+	return ""
+}
+
 func (q *FakeQuerier) getTemplateByIDNoLock(_ context.Context, id uuid.UUID) (database.Template, error) {
 	for _, template := range q.templates {
 		if template.ID == id {
@@ -626,6 +765,12 @@ func uniqueSortedUUIDs(uuids []uuid.UUID) []uuid.UUID {
 	return unique
 }
 
+// truncateToDate mimics casting a timestamp to a postgres date.
+func truncateToDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 func (q *FakeQuerier) getOrganizationMember(orgID uuid.UUID) []database.OrganizationMember {
 	var members []database.OrganizationMember
 	for _, member := range q.organizationMembers {
@@ -668,6 +813,25 @@ func (*FakeQuerier) AcquireLock(_ context.Context, _ int64) error {
 	return xerrors.New("AcquireLock must only be called within a transaction")
 }
 
+// provisionerTagsSatisfy mirrors provisionerdserver.TagsSatisfy, which can't
+// be imported here without an import cycle.
+func provisionerTagsSatisfy(jobTags, daemonTags map[string]string) bool {
+	for key, expr := range jobTags {
+		value, ok := daemonTags[key]
+		if !ok {
+			return false
+		}
+		if expr == "*" {
+			continue
+		}
+		if !slices.Contains(strings.Split(expr, "|"), value) {
+			return false
+		}
+	}
+	// Regional jobs and provisioners must match exactly.
+	return jobTags["region"] == daemonTags["region"]
+}
+
 func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.AcquireProvisionerJobParams) (database.ProvisionerJob, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ProvisionerJob{}, err
@@ -699,19 +863,13 @@ func (q *FakeQuerier) AcquireProvisionerJob(_ context.Context, arg database.Acqu
 			}
 		}
 
-		missing := false
-		for key, value := range provisionerJob.Tags {
-			provided, found := tags[key]
-			if !found {
-				missing = true
-				break
-			}
-			if provided != value {
-				missing = true
-				break
-			}
+		if !provisionerTagsSatisfy(provisionerJob.Tags, tags) {
+			continue
+		}
+		if q.isJobHeldForApprovalNoLock(provisionerJob.ID) {
+			continue
 		}
-		if missing {
+		if q.provisionerJobConcurrencyLimitNoLock(provisionerJob) != "" {
 			continue
 		}
 		provisionerJob.StartedAt = arg.StartedAt
@@ -772,6 +930,21 @@ func (*FakeQuerier) DeleteCoordinator(context.Context, uuid.UUID) error {
 	return ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteAuditLogsByIDs(_ context.Context, ids []uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	kept := make([]database.AuditLog, 0, len(q.auditLogs))
+	for _, alog := range q.auditLogs {
+		if slices.Contains(ids, alog.ID) {
+			continue
+		}
+		kept = append(kept, alog)
+	}
+	q.auditLogs = kept
+	return nil
+}
+
 func (q *FakeQuerier) DeleteGitSSHKey(_ context.Context, userID uuid.UUID) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -801,6 +974,20 @@ func (q *FakeQuerier) DeleteGroupByID(_ context.Context, id uuid.UUID) error {
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteGroupDefaultTemplates(_ context.Context, groupID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	kept := make([]database.GroupDefaultTemplate, 0, len(q.groupDefaultTemplates))
+	for _, defaultTemplate := range q.groupDefaultTemplates {
+		if defaultTemplate.GroupID != groupID {
+			kept = append(kept, defaultTemplate)
+		}
+	}
+	q.groupDefaultTemplates = kept
+	return nil
+}
+
 func (q *FakeQuerier) DeleteGroupMemberFromGroup(_ context.Context, arg database.DeleteGroupMemberFromGroupParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -857,6 +1044,99 @@ func (q *FakeQuerier) DeleteLicense(_ context.Context, id int32) (int32, error)
 	return 0, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) DeleteManagedEnvironmentVariableByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, variable := range q.managedEnvironmentVariables {
+		if variable.ID == id {
+			q.managedEnvironmentVariables = append(q.managedEnvironmentVariables[:i], q.managedEnvironmentVariables[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldNetcheckReports(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	threshold := database.Now().Add(-30 * 24 * time.Hour)
+	kept := make([]database.NetcheckReport, 0, len(q.netcheckReports))
+	for _, report := range q.netcheckReports {
+		if report.CreatedAt.Before(threshold) {
+			continue
+		}
+		kept = append(kept, report)
+	}
+	q.netcheckReports = kept
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldPlatformEvents(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	threshold := database.Now().Add(-7 * 24 * time.Hour)
+	kept := make([]database.PlatformEvent, 0, len(q.platformEvents))
+	for _, event := range q.platformEvents {
+		if event.CreatedAt.Before(threshold) {
+			continue
+		}
+		kept = append(kept, event)
+	}
+	q.platformEvents = kept
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldWorkspaceAgentAuthTokenRotations(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	threshold := database.Now().Add(-30 * 24 * time.Hour)
+	kept := make([]database.WorkspaceAgentAuthTokenRotation, 0, len(q.workspaceAgentAuthTokenRotations))
+	for _, rotation := range q.workspaceAgentAuthTokenRotations {
+		if rotation.PreviousAuthTokenExpiresAt.Before(threshold) {
+			continue
+		}
+		kept = append(kept, rotation)
+	}
+	q.workspaceAgentAuthTokenRotations = kept
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldWorkspaceAgentBandwidthStats(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	cutoff := database.Now().Add(-30 * 24 * time.Hour)
+	stats := make([]database.WorkspaceAgentBandwidthStat, 0, len(q.workspaceAgentBandwidthStats))
+	for _, stat := range q.workspaceAgentBandwidthStats {
+		if stat.CreatedAt.Before(cutoff) {
+			continue
+		}
+		stats = append(stats, stat)
+	}
+	q.workspaceAgentBandwidthStats = stats
+	return nil
+}
+
+func (q *FakeQuerier) DeleteOldWorkspaceAgentClientConnections(_ context.Context) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	threshold := database.Now().Add(-30 * 24 * time.Hour)
+	kept := make([]database.WorkspaceAgentClientConnection, 0, len(q.workspaceAgentClientConnections))
+	for _, conn := range q.workspaceAgentClientConnections {
+		if conn.ConnectedAt.Before(threshold) {
+			continue
+		}
+		kept = append(kept, conn)
+	}
+	q.workspaceAgentClientConnections = kept
+	return nil
+}
+
 func (*FakeQuerier) DeleteOldWorkspaceAgentLogs(_ context.Context) error {
 	// noop
 	return nil
@@ -888,6 +1168,140 @@ func (*FakeQuerier) DeleteTailnetClient(context.Context, database.DeleteTailnetC
 	return database.DeleteTailnetClientRow{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) DeleteUserDeprovision(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, deprovision := range q.userDeprovisions {
+		if deprovision.UserID == userID {
+			q.userDeprovisions = append(q.userDeprovisions[:i], q.userDeprovisions[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteUserPTYSessionOverride(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, override := range q.userPTYSessionOverrides {
+		if override.UserID == userID {
+			q.userPTYSessionOverrides = append(q.userPTYSessionOverrides[:i], q.userPTYSessionOverrides[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteUserQuotaOverride(_ context.Context, userID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, override := range q.userQuotaOverrides {
+		if override.UserID == userID {
+			q.userQuotaOverrides = append(q.userQuotaOverrides[:i], q.userQuotaOverrides[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceBuildQueueEntriesByWorkspaceID(_ context.Context, workspaceID uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	entries := make([]database.WorkspaceBuildQueueEntry, 0, len(q.workspaceBuildQueueEntries))
+	for _, entry := range q.workspaceBuildQueueEntries {
+		if entry.WorkspaceID != workspaceID {
+			entries = append(entries, entry)
+		}
+	}
+	q.workspaceBuildQueueEntries = entries
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceBuildQueueEntryByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, entry := range q.workspaceBuildQueueEntries {
+		if entry.ID == id {
+			q.workspaceBuildQueueEntries = append(q.workspaceBuildQueueEntries[:i], q.workspaceBuildQueueEntries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceExternalMetadatum(_ context.Context, arg database.DeleteWorkspaceExternalMetadatumParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, metadatum := range q.workspaceExternalMetadata {
+		if metadatum.WorkspaceID == arg.WorkspaceID && metadatum.Namespace == arg.Namespace {
+			q.workspaceExternalMetadata = append(q.workspaceExternalMetadata[:i], q.workspaceExternalMetadata[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspacePreviousName(_ context.Context, arg database.DeleteWorkspacePreviousNameParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, previous := range q.workspacePreviousNames {
+		if previous.WorkspaceID == arg.WorkspaceID && strings.EqualFold(previous.Name, arg.Name) {
+			q.workspacePreviousNames = append(q.workspacePreviousNames[:i], q.workspacePreviousNames[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) DeleteWorkspaceWebhookByID(_ context.Context, id uuid.UUID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, webhook := range q.workspaceWebhooks {
+		if webhook.ID == id {
+			q.workspaceWebhooks = append(q.workspaceWebhooks[:i], q.workspaceWebhooks[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (q *FakeQuerier) ExpireWorkspaceAgentAuthTokenRotations(_ context.Context, arg database.ExpireWorkspaceAgentAuthTokenRotationsParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, rotation := range q.workspaceAgentAuthTokenRotations {
+		if rotation.AgentID != arg.AgentID || !rotation.PreviousAuthTokenExpiresAt.After(arg.Now) {
+			continue
+		}
+		rotation.PreviousAuthTokenExpiresAt = arg.Now
+		q.workspaceAgentAuthTokenRotations[index] = rotation
+	}
+	return nil
+}
+
 func (q *FakeQuerier) GetAPIKeyByID(_ context.Context, id string) (database.APIKey, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1014,17 +1428,43 @@ func (q *FakeQuerier) GetAppSecurityKey(_ context.Context) (string, error) {
 	return q.appSecurityKey, nil
 }
 
-func (q *FakeQuerier) GetAuditLogsOffset(_ context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
-	if err := validateDatabaseType(arg); err != nil {
-		return nil, err
+func (q *FakeQuerier) GetAuditLogArchivalByID(_ context.Context, id uuid.UUID) (database.AuditLogArchival, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, archival := range q.auditLogArchivals {
+		if archival.ID == id {
+			return archival, nil
+		}
 	}
+	return database.AuditLogArchival{}, sql.ErrNoRows
+}
 
+func (q *FakeQuerier) GetAuditLogArchivals(_ context.Context, rowLimit int32) ([]database.AuditLogArchival, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	logs := make([]database.GetAuditLogsOffsetRow, 0, arg.Limit)
+	archivals := slices.Clone(q.auditLogArchivals)
+	slices.SortFunc(archivals, func(a, b database.AuditLogArchival) int {
+		return b.StartedAt.Compare(a.StartedAt)
+	})
+	if len(archivals) > int(rowLimit) {
+		archivals = archivals[:rowLimit]
+	}
+	return archivals, nil
+}
 
-	// q.auditLogs are already sorted by time DESC, so no need to sort after the fact.
+func (q *FakeQuerier) GetAuditLogsOffset(_ context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logs := make([]database.GetAuditLogsOffsetRow, 0, arg.Limit)
+
+	// q.auditLogs are already sorted by time DESC, so no need to sort after the fact.
 	for _, alog := range q.auditLogs {
 		if arg.Offset > 0 {
 			arg.Offset--
@@ -1107,6 +1547,32 @@ func (q *FakeQuerier) GetAuditLogsOffset(_ context.Context, arg database.GetAudi
 	return logs, nil
 }
 
+func (q *FakeQuerier) GetAuditLogsOlderThan(_ context.Context, arg database.GetAuditLogsOlderThanParams) ([]database.AuditLog, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	logs := make([]database.AuditLog, 0)
+	for _, alog := range q.auditLogs {
+		if alog.Time.Before(arg.Before) {
+			logs = append(logs, alog)
+		}
+	}
+	slices.SortFunc(logs, func(a, b database.AuditLog) int {
+		if c := a.Time.Compare(b.Time); c != 0 {
+			return c
+		}
+		return slice.Ascending(a.ID.String(), b.ID.String())
+	})
+	if len(logs) > int(arg.RowLimit) {
+		logs = logs[:arg.RowLimit]
+	}
+	return logs, nil
+}
+
 func (q *FakeQuerier) GetAuthorizationUserRoles(_ context.Context, userID uuid.UUID) (database.GetAuthorizationUserRolesRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1304,6 +1770,30 @@ func (q *FakeQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (database
 	return stat, nil
 }
 
+func (q *FakeQuerier) GetEntitlementSnapshots(_ context.Context, arg database.GetEntitlementSnapshotsParams) ([]database.EntitlementSnapshot, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	startDate := truncateToDate(arg.StartDate)
+	endDate := truncateToDate(arg.EndDate)
+	snapshots := make([]database.EntitlementSnapshot, 0)
+	for _, s := range q.entitlementSnapshots {
+		if s.Date.Before(startDate) || s.Date.After(endDate) {
+			continue
+		}
+		snapshots = append(snapshots, s)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Date.Before(snapshots[j].Date)
+	})
+	return snapshots, nil
+}
+
 func (q *FakeQuerier) GetFileByHashAndCreator(_ context.Context, arg database.GetFileByHashAndCreatorParams) (database.File, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.File{}, err
@@ -1429,6 +1919,28 @@ func (q *FakeQuerier) GetGroupByOrgAndName(_ context.Context, arg database.GetGr
 	return database.Group{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetGroupDefaultTemplates(_ context.Context, groupID uuid.UUID) ([]database.GroupDefaultTemplate, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	defaultTemplates := make([]database.GroupDefaultTemplate, 0)
+	for _, defaultTemplate := range q.groupDefaultTemplates {
+		if defaultTemplate.GroupID == groupID {
+			defaultTemplates = append(defaultTemplates, defaultTemplate)
+		}
+	}
+	slices.SortFunc(defaultTemplates, func(a, b database.GroupDefaultTemplate) int {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			if a.CreatedAt.Before(b.CreatedAt) {
+				return -1
+			}
+			return 1
+		}
+		return slice.Ascending(a.TemplateID.String(), b.TemplateID.String())
+	})
+	return defaultTemplates, nil
+}
+
 func (q *FakeQuerier) GetGroupMembers(_ context.Context, id uuid.UUID) ([]database.User, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1458,6 +1970,26 @@ func (q *FakeQuerier) GetGroupMembers(_ context.Context, id uuid.UUID) ([]databa
 	return users, nil
 }
 
+func (q *FakeQuerier) GetGroupMembersAnyStatus(_ context.Context, groupID uuid.UUID) ([]database.User, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	users := make([]database.User, 0)
+	for _, member := range q.groupMembers {
+		if member.GroupID != groupID {
+			continue
+		}
+		for _, user := range q.users {
+			if user.ID == member.UserID && !user.Deleted {
+				users = append(users, user)
+				break
+			}
+		}
+	}
+
+	return users, nil
+}
+
 func (q *FakeQuerier) GetGroupsByOrganizationID(_ context.Context, id uuid.UUID) ([]database.Group, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1495,6 +2027,22 @@ func (q *FakeQuerier) GetLastUpdateCheck(_ context.Context) (string, error) {
 	return string(q.lastUpdateCheck), nil
 }
 
+func (q *FakeQuerier) GetLatestWorkspaceApprovalByWorkspaceID(_ context.Context, workspaceID uuid.UUID) (database.WorkspaceApproval, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var latest database.WorkspaceApproval
+	for _, approval := range q.workspaceApprovals {
+		if approval.WorkspaceID == workspaceID && !approval.CreatedAt.Before(latest.CreatedAt) {
+			latest = approval
+		}
+	}
+	if latest.ID == uuid.Nil {
+		return database.WorkspaceApproval{}, sql.ErrNoRows
+	}
+	return latest, nil
+}
+
 func (q *FakeQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1567,6 +2115,33 @@ func (q *FakeQuerier) GetLicenseByID(_ context.Context, id int32) (database.Lice
 	return database.License{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetLicenseUsage(_ context.Context, arg database.GetLicenseUsageParams) ([]database.LicenseUsage, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	startDate := truncateToDate(arg.StartDate)
+	endDate := truncateToDate(arg.EndDate)
+	usage := make([]database.LicenseUsage, 0)
+	for _, u := range q.licenseUsage {
+		if u.Date.Before(startDate) || u.Date.After(endDate) {
+			continue
+		}
+		usage = append(usage, u)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Feature != usage[j].Feature {
+			return usage[i].Feature < usage[j].Feature
+		}
+		return usage[i].Date.Before(usage[j].Date)
+	})
+	return usage, nil
+}
+
 func (q *FakeQuerier) GetLicenses(_ context.Context) ([]database.License, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1587,6 +2162,81 @@ func (q *FakeQuerier) GetLogoURL(_ context.Context) (string, error) {
 	return q.logoURL, nil
 }
 
+func (q *FakeQuerier) GetManagedEnvironmentVariableByID(_ context.Context, id uuid.UUID) (database.ManagedEnvironmentVariable, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, variable := range q.managedEnvironmentVariables {
+		if variable.ID == id {
+			return variable, nil
+		}
+	}
+	return database.ManagedEnvironmentVariable{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetManagedEnvironmentVariablesByOrganizationID(_ context.Context, organizationID uuid.UUID) ([]database.ManagedEnvironmentVariable, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	variables := make([]database.ManagedEnvironmentVariable, 0)
+	for _, variable := range q.managedEnvironmentVariables {
+		if variable.OrganizationID == organizationID && !variable.TemplateID.Valid {
+			variables = append(variables, variable)
+		}
+	}
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Name < variables[j].Name
+	})
+	return variables, nil
+}
+
+func (q *FakeQuerier) GetManagedEnvironmentVariablesByTemplateID(_ context.Context, templateID uuid.NullUUID) ([]database.ManagedEnvironmentVariable, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	variables := make([]database.ManagedEnvironmentVariable, 0)
+	if !templateID.Valid {
+		return variables, nil
+	}
+	for _, variable := range q.managedEnvironmentVariables {
+		if variable.TemplateID == templateID {
+			variables = append(variables, variable)
+		}
+	}
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Name < variables[j].Name
+	})
+	return variables, nil
+}
+
+func (q *FakeQuerier) GetNetcheckReports(_ context.Context, arg database.GetNetcheckReportsParams) ([]database.NetcheckReport, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	reports := make([]database.NetcheckReport, 0)
+	for _, report := range q.netcheckReports {
+		if report.CreatedAt.Before(arg.StartTime) || !report.CreatedAt.Before(arg.EndTime) {
+			continue
+		}
+		if arg.UserID != uuid.Nil && report.UserID != arg.UserID {
+			continue
+		}
+		reports = append(reports, report)
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].CreatedAt.After(reports[j].CreatedAt)
+	})
+	if arg.LimitOpt >= 0 && len(reports) > int(arg.LimitOpt) {
+		reports = reports[:arg.LimitOpt]
+	}
+	return reports, nil
+}
+
 func (q *FakeQuerier) GetOAuthSigningKey(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1708,6 +2358,32 @@ func (q *FakeQuerier) GetOrganizationsByUserID(_ context.Context, userID uuid.UU
 	return organizations, nil
 }
 
+func (q *FakeQuerier) GetOutdatedWorkspacesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.Workspace, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	template, err := q.getTemplateByIDNoLock(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces := make([]database.Workspace, 0)
+	for _, workspace := range q.workspaces {
+		if workspace.TemplateID != templateID || workspace.Deleted {
+			continue
+		}
+		build, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if err != nil {
+			continue
+		}
+		if build.TemplateVersionID == template.ActiveVersionID {
+			continue
+		}
+		workspaces = append(workspaces, workspace)
+	}
+	return workspaces, nil
+}
+
 func (q *FakeQuerier) GetParameterSchemasByJobID(_ context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1728,6 +2404,112 @@ func (q *FakeQuerier) GetParameterSchemasByJobID(_ context.Context, jobID uuid.U
 	return parameters, nil
 }
 
+func (q *FakeQuerier) GetPendingGroupDefaultTemplateWorkspaces(ctx context.Context) ([]database.GetPendingGroupDefaultTemplateWorkspacesRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	type key struct {
+		templateID uuid.UUID
+		userID     uuid.UUID
+	}
+	seen := make(map[key]bool)
+	for _, workspace := range q.groupDefaultTemplateWorkspaces {
+		seen[key{workspace.TemplateID, workspace.UserID}] = true
+	}
+
+	defaultTemplates := slices.Clone(q.groupDefaultTemplates)
+	slices.SortFunc(defaultTemplates, func(a, b database.GroupDefaultTemplate) int {
+		if a.CreatedAt.Before(b.CreatedAt) {
+			return -1
+		}
+		if a.CreatedAt.After(b.CreatedAt) {
+			return 1
+		}
+		return 0
+	})
+
+	rows := make([]database.GetPendingGroupDefaultTemplateWorkspacesRow, 0)
+	for _, defaultTemplate := range defaultTemplates {
+		if !defaultTemplate.CreateWorkspace {
+			continue
+		}
+		template, err := q.getTemplateByIDNoLock(ctx, defaultTemplate.TemplateID)
+		if err != nil || template.Deleted {
+			continue
+		}
+
+		var memberIDs []uuid.UUID
+		if q.isEveryoneGroup(defaultTemplate.GroupID) {
+			for _, member := range q.getOrganizationMember(defaultTemplate.GroupID) {
+				memberIDs = append(memberIDs, member.UserID)
+			}
+		} else {
+			for _, member := range q.groupMembers {
+				if member.GroupID == defaultTemplate.GroupID {
+					memberIDs = append(memberIDs, member.UserID)
+				}
+			}
+		}
+		for _, memberID := range memberIDs {
+			k := key{defaultTemplate.TemplateID, memberID}
+			if seen[k] {
+				continue
+			}
+			for _, user := range q.users {
+				if user.ID != memberID || user.Status != database.UserStatusActive || user.Deleted {
+					continue
+				}
+				seen[k] = true
+				rows = append(rows, database.GetPendingGroupDefaultTemplateWorkspacesRow{
+					GroupID:    defaultTemplate.GroupID,
+					TemplateID: defaultTemplate.TemplateID,
+					UserID:     memberID,
+				})
+				break
+			}
+		}
+	}
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetPendingProvisionerJobs(_ context.Context) ([]database.ProvisionerJob, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	jobs := make([]database.ProvisionerJob, 0)
+	for _, job := range q.provisionerJobs {
+		if job.StartedAt.Valid || job.CanceledAt.Valid {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+	return jobs, nil
+}
+
+func (q *FakeQuerier) GetPlatformEventsAfterID(_ context.Context, arg database.GetPlatformEventsAfterIDParams) ([]database.PlatformEvent, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	events := make([]database.PlatformEvent, 0)
+	for _, event := range q.platformEvents {
+		if event.ID <= arg.AfterID {
+			continue
+		}
+		if arg.LimitOpt > 0 && len(events) >= int(arg.LimitOpt) {
+			break
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
 func (q *FakeQuerier) GetPreviousTemplateVersion(_ context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.TemplateVersion{}, err
@@ -1789,6 +2571,22 @@ func (q *FakeQuerier) GetProvisionerDaemons(_ context.Context) ([]database.Provi
 	return q.provisionerDaemons, nil
 }
 
+func (q *FakeQuerier) GetProvisionerJobBuildHooksByJobID(_ context.Context, jobID uuid.UUID) ([]database.ProvisionerJobBuildHook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	hooks := make([]database.ProvisionerJobBuildHook, 0)
+	for _, hook := range q.provisionerJobBuildHooks {
+		if hook.JobID == jobID {
+			hooks = append(hooks, hook)
+		}
+	}
+	sort.SliceStable(hooks, func(i, j int) bool {
+		return hooks[i].StartedAt.Before(hooks[j].StartedAt)
+	})
+	return hooks, nil
+}
+
 func (q *FakeQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (database.ProvisionerJob, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1796,6 +2594,18 @@ func (q *FakeQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (
 	return q.getProvisionerJobByIDNoLock(ctx, id)
 }
 
+func (q *FakeQuerier) GetProvisionerJobConcurrencyLimit(_ context.Context, id uuid.UUID) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, job := range q.provisionerJobs {
+		if job.ID == id {
+			return q.provisionerJobConcurrencyLimitNoLock(job), nil
+		}
+	}
+	return "", nil
+}
+
 func (q *FakeQuerier) GetProvisionerJobsByIDs(_ context.Context, ids []uuid.UUID) ([]database.ProvisionerJob, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1830,6 +2640,7 @@ func (q *FakeQuerier) GetProvisionerJobsByIDsWithQueuePosition(_ context.Context
 				}
 				if !job.ProvisionerJob.StartedAt.Valid {
 					job.QueuePosition = queuePosition
+					job.QueueLimitedBy = q.provisionerJobConcurrencyLimitNoLock(job.ProvisionerJob)
 				}
 				jobs = append(jobs, job)
 				break
@@ -1861,6 +2672,23 @@ func (q *FakeQuerier) GetProvisionerJobsCreatedAfter(_ context.Context, after ti
 	return jobs, nil
 }
 
+func (q *FakeQuerier) GetProvisionerJobsStartedAfter(_ context.Context, startedAfter time.Time) ([]database.ProvisionerJob, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	jobs := make([]database.ProvisionerJob, 0)
+	for _, job := range q.provisionerJobs {
+		if !job.StartedAt.Valid || !job.StartedAt.Time.After(startedAfter) {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].StartedAt.Time.Before(jobs[j].StartedAt.Time)
+	})
+	return jobs, nil
+}
+
 func (q *FakeQuerier) GetProvisionerLogsAfterID(_ context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -1882,30 +2710,28 @@ func (q *FakeQuerier) GetProvisionerLogsAfterID(_ context.Context, arg database.
 	return logs, nil
 }
 
-func (q *FakeQuerier) GetQuotaAllowanceForUser(_ context.Context, userID uuid.UUID) (int64, error) {
+func (q *FakeQuerier) GetQuotaBudgetsForUser(_ context.Context, userID uuid.UUID) ([]database.Group, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	var sum int64
+	memberOf := make(map[uuid.UUID]bool)
 	for _, member := range q.groupMembers {
-		if member.UserID != userID {
-			continue
-		}
-		for _, group := range q.groups {
-			if group.ID == member.GroupID {
-				sum += int64(group.QuotaAllowance)
-				continue
-			}
+		if member.UserID == userID {
+			memberOf[member.GroupID] = true
 		}
 	}
-	// Grab the quota for the Everyone group.
+
+	groups := make([]database.Group, 0)
 	for _, group := range q.groups {
-		if group.ID == group.OrganizationID {
-			sum += int64(group.QuotaAllowance)
-			break
+		// The Everyone group has the same ID as the organization.
+		if memberOf[group.ID] || group.ID == group.OrganizationID {
+			groups = append(groups, group)
 		}
 	}
-	return sum, nil
+	slices.SortFunc(groups, func(a, b database.Group) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return groups, nil
 }
 
 func (q *FakeQuerier) GetQuotaConsumedForUser(_ context.Context, userID uuid.UUID) (int64, error) {
@@ -1935,6 +2761,45 @@ func (q *FakeQuerier) GetQuotaConsumedForUser(_ context.Context, userID uuid.UUI
 	return sum, nil
 }
 
+func (q *FakeQuerier) GetQuotaConsumedWorkspacesForUser(_ context.Context, ownerID uuid.UUID) ([]database.GetQuotaConsumedWorkspacesForUserRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetQuotaConsumedWorkspacesForUserRow, 0)
+	for _, workspace := range q.workspaces {
+		if workspace.OwnerID != ownerID || workspace.Deleted {
+			continue
+		}
+
+		var lastBuild database.WorkspaceBuildTable
+		found := false
+		for _, build := range q.workspaceBuilds {
+			if build.WorkspaceID != workspace.ID {
+				continue
+			}
+			if !found || build.CreatedAt.After(lastBuild.CreatedAt) {
+				lastBuild = build
+				found = true
+			}
+		}
+		if !found {
+			continue
+		}
+		rows = append(rows, database.GetQuotaConsumedWorkspacesForUserRow{
+			WorkspaceID:   workspace.ID,
+			WorkspaceName: workspace.Name,
+			DailyCost:     lastBuild.DailyCost,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetQuotaConsumedWorkspacesForUserRow) int {
+		if a.DailyCost != b.DailyCost {
+			return int(b.DailyCost - a.DailyCost)
+		}
+		return strings.Compare(a.WorkspaceName, b.WorkspaceName)
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetReplicaByID(_ context.Context, id uuid.UUID) (database.Replica, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1960,6 +2825,24 @@ func (q *FakeQuerier) GetReplicasUpdatedAfter(_ context.Context, updatedAt time.
 	return replicas, nil
 }
 
+func (q *FakeQuerier) GetRuntimeExperiments(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.runtimeExperiments == nil {
+		return "", sql.ErrNoRows
+	}
+
+	return string(q.runtimeExperiments), nil
+}
+
+func (q *FakeQuerier) GetSSHCAKey(_ context.Context) (string, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return q.sshCAKey, nil
+}
+
 func (q *FakeQuerier) GetServiceBanner(_ context.Context) (string, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -1979,6 +2862,71 @@ func (*FakeQuerier) GetTailnetClientsForAgent(context.Context, uuid.UUID) ([]dat
 	return nil, ErrUnimplemented
 }
 
+func (q *FakeQuerier) GetTemplateAgentFirstConnectInsights(ctx context.Context, arg database.GetTemplateAgentFirstConnectInsightsParams) ([]database.GetTemplateAgentFirstConnectInsightsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	now := time.Now()
+	rowsByTemplateID := make(map[uuid.UUID]*database.GetTemplateAgentFirstConnectInsightsRow)
+	secondsByTemplateID := make(map[uuid.UUID][]float64)
+	for _, agent := range q.workspaceAgents {
+		_, job, workspace, ok := q.agentStartBuildNoLock(ctx, agent)
+		if !ok {
+			continue
+		}
+		if job.CompletedAt.Time.Before(arg.StartTime) || !job.CompletedAt.Time.Before(arg.EndTime) {
+			continue
+		}
+		if len(arg.TemplateIDs) > 0 && !slices.Contains(arg.TemplateIDs, workspace.TemplateID) {
+			continue
+		}
+
+		row, ok := rowsByTemplateID[workspace.TemplateID]
+		if !ok {
+			row = &database.GetTemplateAgentFirstConnectInsightsRow{TemplateID: workspace.TemplateID}
+			rowsByTemplateID[workspace.TemplateID] = row
+		}
+		row.TotalAgents++
+		if agent.FirstConnectedAt.Valid {
+			row.ConnectedAgents++
+			took := agent.FirstConnectedAt.Time.Sub(job.CompletedAt.Time).Seconds()
+			if took < 0 {
+				took = 0
+			}
+			secondsByTemplateID[workspace.TemplateID] = append(secondsByTemplateID[workspace.TemplateID], took)
+		} else if agentConnectDeadline(agent, job, arg.DefaultConnectionTimeoutSeconds).Before(now) {
+			row.NeverConnectedAgents++
+		}
+	}
+
+	tryPercentile := func(fs []float64, p float64) float64 {
+		if len(fs) == 0 {
+			return -1
+		}
+		sort.Float64s(fs)
+		return fs[int(float64(len(fs))*p/100)]
+	}
+
+	rows := make([]database.GetTemplateAgentFirstConnectInsightsRow, 0, len(rowsByTemplateID))
+	for templateID, row := range rowsByTemplateID {
+		seconds := secondsByTemplateID[templateID]
+		row.FirstConnectSeconds50 = tryPercentile(seconds, 50)
+		row.FirstConnectSeconds95 = tryPercentile(seconds, 95)
+		row.FirstConnectSeconds99 = tryPercentile(seconds, 99)
+		rows = append(rows, *row)
+	}
+	slices.SortFunc(rows, func(a, b database.GetTemplateAgentFirstConnectInsightsRow) int {
+		return slice.Ascending(a.TemplateID.String(), b.TemplateID.String())
+	})
+
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -2098,6 +3046,43 @@ func (q *FakeQuerier) GetTemplateAppInsights(ctx context.Context, arg database.G
 	return rows, nil
 }
 
+func (q *FakeQuerier) GetTemplateAccessRequestByID(_ context.Context, id uuid.UUID) (database.TemplateAccessRequest, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, request := range q.templateAccessRequests {
+		if request.ID == id {
+			return request, nil
+		}
+	}
+	return database.TemplateAccessRequest{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetTemplateAccessRequestsByTemplateID(_ context.Context, arg database.GetTemplateAccessRequestsByTemplateIDParams) ([]database.TemplateAccessRequest, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	requests := make([]database.TemplateAccessRequest, 0)
+	for _, request := range q.templateAccessRequests {
+		if request.TemplateID != arg.TemplateID {
+			continue
+		}
+		if arg.Status != "" && string(request.Status) != arg.Status {
+			continue
+		}
+		requests = append(requests, request)
+	}
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].CreatedAt.After(requests[j].CreatedAt)
+	})
+	return requests, nil
+}
+
 func (q *FakeQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg database.GetTemplateAverageBuildTimeParams) (database.GetTemplateAverageBuildTimeRow, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.GetTemplateAverageBuildTimeRow{}, err
@@ -2152,6 +3137,74 @@ func (q *FakeQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg datab
 	return row, nil
 }
 
+func (q *FakeQuerier) GetTemplateBuildSLOInsights(ctx context.Context, arg database.GetTemplateBuildSLOInsightsParams) ([]database.GetTemplateBuildSLOInsightsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rowsByTemplateID := make(map[uuid.UUID]*database.GetTemplateBuildSLOInsightsRow)
+	durationsByTemplateID := make(map[uuid.UUID][]float64)
+	for _, wb := range q.workspaceBuilds {
+		job, err := q.getProvisionerJobByIDNoLock(ctx, wb.JobID)
+		if err != nil {
+			return nil, err
+		}
+		if !job.CompletedAt.Valid || !job.StartedAt.Valid || job.CanceledAt.Valid {
+			continue
+		}
+		if job.CompletedAt.Time.Before(arg.StartTime) || !job.CompletedAt.Time.Before(arg.EndTime) {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(ctx, wb.WorkspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if len(arg.TemplateIDs) > 0 && !slices.Contains(arg.TemplateIDs, workspace.TemplateID) {
+			continue
+		}
+
+		row, ok := rowsByTemplateID[workspace.TemplateID]
+		if !ok {
+			row = &database.GetTemplateBuildSLOInsightsRow{TemplateID: workspace.TemplateID}
+			rowsByTemplateID[workspace.TemplateID] = row
+		}
+		took := job.CompletedAt.Time.Sub(job.StartedAt.Time).Seconds()
+		durationsByTemplateID[workspace.TemplateID] = append(durationsByTemplateID[workspace.TemplateID], took)
+		row.TotalBuilds++
+		if job.Error.String == "" {
+			row.SuccessfulBuilds++
+			if took <= arg.DurationObjectiveSeconds {
+				row.BuildsWithinDurationObjective++
+			}
+		}
+	}
+
+	tryPercentile := func(fs []float64, p float64) float64 {
+		if len(fs) == 0 {
+			return -1
+		}
+		sort.Float64s(fs)
+		return fs[int(float64(len(fs))*p/100)]
+	}
+
+	rows := make([]database.GetTemplateBuildSLOInsightsRow, 0, len(rowsByTemplateID))
+	for templateID, row := range rowsByTemplateID {
+		durations := durationsByTemplateID[templateID]
+		row.BuildDurationSeconds50 = tryPercentile(durations, 50)
+		row.BuildDurationSeconds95 = tryPercentile(durations, 95)
+		rows = append(rows, *row)
+	}
+	slices.SortFunc(rows, func(a, b database.GetTemplateBuildSLOInsightsRow) int {
+		return slice.Ascending(a.TemplateID.String(), b.TemplateID.String())
+	})
+
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2483,6 +3536,41 @@ func (q *FakeQuerier) GetTemplateParameterInsights(ctx context.Context, arg data
 	return rows, nil
 }
 
+func (q *FakeQuerier) GetTemplateVersionBuildInsights(ctx context.Context, templateID uuid.UUID) ([]database.GetTemplateVersionBuildInsightsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	var rows []database.GetTemplateVersionBuildInsightsRow
+	for _, insight := range q.templateVersionBuildInsights {
+		if insight.TemplateID != templateID {
+			continue
+		}
+		version, err := q.getTemplateVersionByIDNoLock(ctx, insight.TemplateVersionID)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, database.GetTemplateVersionBuildInsightsRow{
+			TemplateVersionID:        insight.TemplateVersionID,
+			TemplateVersionName:      version.Name,
+			TemplateVersionCreatedAt: version.CreatedAt,
+			TotalBuilds:              insight.TotalBuilds,
+			FailedBuilds:             insight.FailedBuilds,
+			Failures:                 insight.Failures,
+			DurationSeconds50:        insight.DurationSeconds50,
+			DurationSeconds95:        insight.DurationSeconds95,
+			UpdatedAt:                insight.UpdatedAt,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetTemplateVersionBuildInsightsRow) int {
+		if c := b.TemplateVersionCreatedAt.Compare(a.TemplateVersionCreatedAt); c != 0 {
+			return c
+		}
+		return slice.Ascending(a.TemplateVersionName, b.TemplateVersionName)
+	})
+
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetTemplateVersionByID(ctx context.Context, templateVersionID uuid.UUID) (database.TemplateVersion, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2543,6 +3631,43 @@ func (q *FakeQuerier) GetTemplateVersionParameters(_ context.Context, templateVe
 	return parameters, nil
 }
 
+func (q *FakeQuerier) GetTemplateVersionPromotionByID(_ context.Context, id uuid.UUID) (database.TemplateVersionPromotion, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, promotion := range q.templateVersionPromotions {
+		if promotion.ID == id {
+			return promotion, nil
+		}
+	}
+	return database.TemplateVersionPromotion{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetTemplateVersionPromotions(_ context.Context, arg database.GetTemplateVersionPromotionsParams) ([]database.TemplateVersionPromotion, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	promotions := make([]database.TemplateVersionPromotion, 0)
+	for _, promotion := range q.templateVersionPromotions {
+		if arg.TemplateID != uuid.Nil && promotion.TemplateID != arg.TemplateID {
+			continue
+		}
+		if arg.Status != "" && string(promotion.Status) != arg.Status {
+			continue
+		}
+		promotions = append(promotions, promotion)
+	}
+	sort.SliceStable(promotions, func(i, j int) bool {
+		return promotions[i].CreatedAt.After(promotions[j].CreatedAt)
+	})
+	return promotions, nil
+}
+
 func (q *FakeQuerier) GetTemplateVersionVariables(_ context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -2694,6 +3819,79 @@ func (q *FakeQuerier) GetUnexpiredLicenses(_ context.Context) ([]database.Licens
 	return results, nil
 }
 
+func (q *FakeQuerier) GetUserBandwidthInsights(_ context.Context, arg database.GetUserBandwidthInsightsParams) ([]database.GetUserBandwidthInsightsRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	type rowKey struct {
+		userID         uuid.UUID
+		connectionType string
+		port           int32
+	}
+	rowsByKey := map[rowKey]*database.GetUserBandwidthInsightsRow{}
+	peersByKey := map[rowKey]map[string]struct{}{}
+	for _, s := range q.workspaceAgentBandwidthStats {
+		if len(arg.TemplateIDs) > 0 && !slices.Contains(arg.TemplateIDs, s.TemplateID) {
+			continue
+		}
+		if s.CreatedAt.Before(arg.StartTime) || !s.CreatedAt.Before(arg.EndTime) {
+			continue
+		}
+
+		key := rowKey{userID: s.UserID, connectionType: s.ConnectionType, port: s.Port}
+		row, ok := rowsByKey[key]
+		if !ok {
+			user, err := q.getUserByIDNoLock(s.UserID)
+			if err != nil {
+				return nil, err
+			}
+			row = &database.GetUserBandwidthInsightsRow{
+				UserID:         s.UserID,
+				Username:       user.Username,
+				AvatarURL:      user.AvatarURL,
+				ConnectionType: s.ConnectionType,
+				Port:           s.Port,
+			}
+			rowsByKey[key] = row
+			peersByKey[key] = map[string]struct{}{}
+		}
+		if !slices.Contains(row.TemplateIDs, s.TemplateID) {
+			row.TemplateIDs = append(row.TemplateIDs, s.TemplateID)
+		}
+		peersByKey[key][s.Peer] = struct{}{}
+		row.RxBytes += s.RxBytes
+		row.TxBytes += s.TxBytes
+	}
+
+	rows := make([]database.GetUserBandwidthInsightsRow, 0, len(rowsByKey))
+	for key, row := range rowsByKey {
+		slices.SortFunc(row.TemplateIDs, func(a, b uuid.UUID) int {
+			return slice.Ascending(a.String(), b.String())
+		})
+		row.Peers = int64(len(peersByKey[key]))
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.RxBytes+a.TxBytes != b.RxBytes+b.TxBytes {
+			return a.RxBytes+a.TxBytes > b.RxBytes+b.TxBytes
+		}
+		if a.UserID != b.UserID {
+			return a.UserID.String() < b.UserID.String()
+		}
+		if a.ConnectionType != b.ConnectionType {
+			return a.ConnectionType < b.ConnectionType
+		}
+		return a.Port < b.Port
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetUserByEmailOrUsername(_ context.Context, arg database.GetUserByEmailOrUsernameParams) (database.User, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.User{}, err
@@ -2730,6 +3928,17 @@ func (q *FakeQuerier) GetUserCount(_ context.Context) (int64, error) {
 	return existing, nil
 }
 
+func (q *FakeQuerier) GetUserDeprovisions(_ context.Context) ([]database.UserDeprovision, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	deprovisions := slices.Clone(q.userDeprovisions)
+	slices.SortFunc(deprovisions, func(a, b database.UserDeprovision) int {
+		return a.DeprovisionedAt.Compare(b.DeprovisionedAt)
+	})
+	return deprovisions, nil
+}
+
 func (q *FakeQuerier) GetUserLatencyInsights(_ context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -2830,6 +4039,51 @@ func (q *FakeQuerier) GetUserLinkByUserIDLoginType(_ context.Context, params dat
 	return database.UserLink{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetUserPTYSessionOverride(_ context.Context, userID uuid.UUID) (database.UserPTYSessionOverride, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, override := range q.userPTYSessionOverrides {
+		if override.UserID == userID {
+			return override, nil
+		}
+	}
+	return database.UserPTYSessionOverride{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetUserQuotaOverride(_ context.Context, userID uuid.UUID) (database.UserQuotaOverride, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, override := range q.userQuotaOverrides {
+		if override.UserID == userID {
+			return override, nil
+		}
+	}
+	return database.UserQuotaOverride{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetUserRegionLatencies(_ context.Context, arg database.GetUserRegionLatenciesParams) ([]database.UserRegionLatency, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	latencies := make([]database.UserRegionLatency, 0)
+	for _, latency := range q.userRegionLatencies {
+		if latency.UserID != arg.UserID || !latency.UpdatedAt.After(arg.UpdatedAfter) {
+			continue
+		}
+		latencies = append(latencies, latency)
+	}
+	slices.SortFunc(latencies, func(a, b database.UserRegionLatency) int {
+		return slice.Ascending(a.LatencyMS, b.LatencyMS)
+	})
+	return latencies, nil
+}
+
 func (q *FakeQuerier) GetUsers(_ context.Context, params database.GetUsersParams) ([]database.GetUsersRow, error) {
 	if err := validateDatabaseType(params); err != nil {
 		return nil, err
@@ -2972,8 +4226,17 @@ func (q *FakeQuerier) GetWorkspaceAgentAndOwnerByAuthToken(_ context.Context, au
 	// We want to return the latest build number
 	var latestBuildNumber int32
 
+	// Rotated tokens stay valid until they expire.
+	now := database.Now()
+	rotatedAgentIDs := make(map[uuid.UUID]struct{})
+	for _, rotation := range q.workspaceAgentAuthTokenRotations {
+		if rotation.PreviousAuthToken == authToken && rotation.PreviousAuthTokenExpiresAt.After(now) {
+			rotatedAgentIDs[rotation.AgentID] = struct{}{}
+		}
+	}
+
 	for _, agt := range q.workspaceAgents {
-		if agt.AuthToken != authToken {
+		if _, ok := rotatedAgentIDs[agt.ID]; agt.AuthToken != authToken && !ok {
 			continue
 		}
 		// get the related workspace and user
@@ -3030,12 +4293,96 @@ func (q *FakeQuerier) GetWorkspaceAgentAndOwnerByAuthToken(_ context.Context, au
 	return rows[latestBuildNumber], nil
 }
 
-func (q *FakeQuerier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgent, error) {
+func (q *FakeQuerier) GetWorkspaceAgentBandwidthStatsAndLabels(ctx context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentBandwidthStatsAndLabelsRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
 
-	return q.getWorkspaceAgentByIDNoLock(ctx, id)
-}
+	type statKey struct {
+		agentID        uuid.UUID
+		connectionType string
+	}
+	statByKey := map[statKey]database.GetWorkspaceAgentBandwidthStatsAndLabelsRow{}
+	for _, bandwidthStat := range q.workspaceAgentBandwidthStats {
+		if !bandwidthStat.CreatedAt.After(createdAfter) {
+			continue
+		}
+		key := statKey{agentID: bandwidthStat.AgentID, connectionType: bandwidthStat.ConnectionType}
+		stat, ok := statByKey[key]
+		if !ok {
+			user, err := q.getUserByIDNoLock(bandwidthStat.UserID)
+			if err != nil {
+				return nil, err
+			}
+			workspace, err := q.getWorkspaceByIDNoLock(ctx, bandwidthStat.WorkspaceID)
+			if err != nil {
+				return nil, err
+			}
+			agent, err := q.getWorkspaceAgentByIDNoLock(ctx, bandwidthStat.AgentID)
+			if err != nil {
+				return nil, err
+			}
+			stat = database.GetWorkspaceAgentBandwidthStatsAndLabelsRow{
+				Username:       user.Username,
+				AgentName:      agent.Name,
+				WorkspaceName:  workspace.Name,
+				ConnectionType: bandwidthStat.ConnectionType,
+			}
+		}
+		stat.RxBytes += bandwidthStat.RxBytes
+		stat.TxBytes += bandwidthStat.TxBytes
+		statByKey[key] = stat
+	}
+
+	stats := make([]database.GetWorkspaceAgentBandwidthStatsAndLabelsRow, 0, len(statByKey))
+	for _, stat := range statByKey {
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentBandwidthStatsByPeer(_ context.Context, arg database.GetWorkspaceAgentBandwidthStatsByPeerParams) ([]database.GetWorkspaceAgentBandwidthStatsByPeerRow, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	type statKey struct {
+		agentID uuid.UUID
+		peer    string
+	}
+	statByKey := map[statKey]database.GetWorkspaceAgentBandwidthStatsByPeerRow{}
+	for _, bandwidthStat := range q.workspaceAgentBandwidthStats {
+		if bandwidthStat.WorkspaceID != arg.WorkspaceID || !bandwidthStat.CreatedAt.After(arg.CreatedAfter) {
+			continue
+		}
+		key := statKey{agentID: bandwidthStat.AgentID, peer: bandwidthStat.Peer}
+		stat, ok := statByKey[key]
+		if !ok {
+			stat = database.GetWorkspaceAgentBandwidthStatsByPeerRow{
+				AgentID: bandwidthStat.AgentID,
+				Peer:    bandwidthStat.Peer,
+			}
+		}
+		stat.RxBytes += bandwidthStat.RxBytes
+		stat.TxBytes += bandwidthStat.TxBytes
+		statByKey[key] = stat
+	}
+
+	stats := make([]database.GetWorkspaceAgentBandwidthStatsByPeerRow, 0, len(statByKey))
+	for _, stat := range statByKey {
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgent, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	return q.getWorkspaceAgentByIDNoLock(ctx, id)
+}
 
 func (q *FakeQuerier) GetWorkspaceAgentByInstanceID(_ context.Context, instanceID string) (database.WorkspaceAgent, error) {
 	q.mutex.RLock()
@@ -3051,6 +4398,87 @@ func (q *FakeQuerier) GetWorkspaceAgentByInstanceID(_ context.Context, instanceI
 	return database.WorkspaceAgent{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentClientConnections(_ context.Context, arg database.GetWorkspaceAgentClientConnectionsParams) ([]database.WorkspaceAgentClientConnection, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	conns := make([]database.WorkspaceAgentClientConnection, 0)
+	for _, conn := range q.workspaceAgentClientConnections {
+		if conn.WorkspaceAgentID != arg.WorkspaceAgentID {
+			continue
+		}
+		if !conn.ConnectedAt.After(arg.ConnectedAfter) {
+			continue
+		}
+		conns = append(conns, conn)
+	}
+	sort.SliceStable(conns, func(i, j int) bool {
+		return conns[i].ConnectedAt.After(conns[j].ConnectedAt)
+	})
+	if arg.LimitOpt >= 0 && len(conns) > int(arg.LimitOpt) {
+		conns = conns[:arg.LimitOpt]
+	}
+	return conns, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentClientConnectionsByUserID(ctx context.Context, arg database.GetWorkspaceAgentClientConnectionsByUserIDParams) ([]database.GetWorkspaceAgentClientConnectionsByUserIDRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetWorkspaceAgentClientConnectionsByUserIDRow, 0)
+	for _, conn := range q.workspaceAgentClientConnections {
+		if !conn.UserID.Valid || !arg.UserID.Valid || conn.UserID.UUID != arg.UserID.UUID {
+			continue
+		}
+		if !conn.ConnectedAt.After(arg.ConnectedAfter) {
+			continue
+		}
+		agent, err := q.getWorkspaceAgentByIDNoLock(ctx, conn.WorkspaceAgentID)
+		if err != nil {
+			continue
+		}
+		workspace, err := q.getWorkspaceByAgentIDNoLock(ctx, conn.WorkspaceAgentID)
+		if err != nil {
+			continue
+		}
+		owner, err := q.getUserByIDNoLock(workspace.OwnerID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, database.GetWorkspaceAgentClientConnectionsByUserIDRow{
+			ID:                     conn.ID,
+			WorkspaceAgentID:       conn.WorkspaceAgentID,
+			UserID:                 conn.UserID,
+			ClientType:             conn.ClientType,
+			ClientVersion:          conn.ClientVersion,
+			ConnectedAt:            conn.ConnectedAt,
+			DisconnectedAt:         conn.DisconnectedAt,
+			IPAddress:              conn.IPAddress,
+			AgentName:              agent.Name,
+			WorkspaceID:            workspace.ID,
+			WorkspaceName:          workspace.Name,
+			WorkspaceOwnerUsername: owner.Username,
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].ConnectedAt.After(rows[j].ConnectedAt)
+	})
+	if arg.LimitOpt >= 0 && len(rows) > int(arg.LimitOpt) {
+		rows = rows[:arg.LimitOpt]
+	}
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3100,6 +4528,50 @@ func (q *FakeQuerier) GetWorkspaceAgentMetadata(_ context.Context, workspaceAgen
 	return metadata, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentMetadataHistory(_ context.Context, arg database.GetWorkspaceAgentMetadataHistoryParams) ([]database.WorkspaceAgentMetadataHistory, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	history := make([]database.WorkspaceAgentMetadataHistory, 0)
+	for _, h := range q.workspaceAgentMetadataHistory {
+		if h.WorkspaceAgentID != arg.WorkspaceAgentID || h.Key != arg.Key {
+			continue
+		}
+		if !h.CollectedAt.After(arg.CollectedAfter) {
+			continue
+		}
+		history = append(history, h)
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].ID > history[j].ID
+	})
+	if arg.LimitOpt >= 0 && len(history) > int(arg.LimitOpt) {
+		history = history[:arg.LimitOpt]
+	}
+	return history, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceAgentSSHHostKey(_ context.Context, arg database.GetWorkspaceAgentSSHHostKeyParams) (database.WorkspaceAgentSSHHostKey, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspaceAgentSSHHostKey{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, key := range q.workspaceAgentSSHHostKeys {
+		if key.WorkspaceID == arg.WorkspaceID && key.AgentName == arg.AgentName {
+			return key, nil
+		}
+	}
+	return database.WorkspaceAgentSSHHostKey{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetWorkspaceAgentStats(_ context.Context, createdAfter time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3300,6 +4772,52 @@ func (q *FakeQuerier) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.C
 	return agents, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceAgentsNeverConnected(ctx context.Context, arg database.GetWorkspaceAgentsNeverConnectedParams) ([]database.GetWorkspaceAgentsNeverConnectedRow, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := []database.GetWorkspaceAgentsNeverConnectedRow{}
+	for _, agent := range q.workspaceAgents {
+		if agent.FirstConnectedAt.Valid {
+			continue
+		}
+		build, job, workspace, ok := q.agentStartBuildNoLock(ctx, agent)
+		if !ok || workspace.Deleted {
+			continue
+		}
+		latest, err := q.getLatestWorkspaceBuildByWorkspaceIDNoLock(ctx, workspace.ID)
+		if err != nil || latest.ID != build.ID {
+			continue
+		}
+		deadline := agentConnectDeadline(agent, job, arg.DefaultConnectionTimeoutSeconds)
+		if !deadline.After(arg.DeadlineAfter) || deadline.After(arg.DeadlineBefore) {
+			continue
+		}
+		template, err := q.getTemplateByIDNoLock(ctx, workspace.TemplateID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, database.GetWorkspaceAgentsNeverConnectedRow{
+			AgentID:       agent.ID,
+			AgentName:     agent.Name,
+			WorkspaceID:   workspace.ID,
+			WorkspaceName: workspace.Name,
+			TemplateID:    template.ID,
+			TemplateName:  template.Name,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetWorkspaceAgentsNeverConnectedRow) int {
+		return slice.Ascending(a.AgentID.String(), b.AgentID.String())
+	})
+
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg database.GetWorkspaceAppByAgentIDAndSlugParams) (database.WorkspaceApp, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.WorkspaceApp{}, err
@@ -3311,6 +4829,30 @@ func (q *FakeQuerier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg d
 	return q.getWorkspaceAppByAgentIDAndSlugNoLock(ctx, arg)
 }
 
+func (q *FakeQuerier) GetWorkspaceApprovalByID(_ context.Context, id uuid.UUID) (database.WorkspaceApproval, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, approval := range q.workspaceApprovals {
+		if approval.ID == id {
+			return approval, nil
+		}
+	}
+	return database.WorkspaceApproval{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceApprovalByJobID(_ context.Context, jobID uuid.UUID) (database.WorkspaceApproval, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, approval := range q.workspaceApprovals {
+		if approval.JobID == jobID {
+			return approval, nil
+		}
+	}
+	return database.WorkspaceApproval{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) GetWorkspaceAppsByAgentID(_ context.Context, id uuid.UUID) ([]database.WorkspaceApp, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3409,6 +4951,38 @@ func (q *FakeQuerier) GetWorkspaceBuildParameters(_ context.Context, workspaceBu
 	return params, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceBuildQueueEntriesByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceBuildQueueEntry, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	entries := make([]database.WorkspaceBuildQueueEntry, 0)
+	for _, entry := range q.workspaceBuildQueueEntries {
+		if entry.WorkspaceID == workspaceID {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b database.WorkspaceBuildQueueEntry) int {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		}
+		return slice.Ascending(a.ID.String(), b.ID.String())
+	})
+	return entries, nil
+}
+
+func (q *FakeQuerier) GetWorkspaceBuildQueueWorkspaceIDs(_ context.Context) ([]uuid.UUID, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	workspaceIDs := make([]uuid.UUID, 0)
+	for _, entry := range q.workspaceBuildQueueEntries {
+		if !slices.Contains(workspaceIDs, entry.WorkspaceID) {
+			workspaceIDs = append(workspaceIDs, entry.WorkspaceID)
+		}
+	}
+	return workspaceIDs, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceBuildsByWorkspaceID(_ context.Context,
 	params database.GetWorkspaceBuildsByWorkspaceIDParams,
 ) ([]database.WorkspaceBuild, error) {
@@ -3424,6 +4998,12 @@ func (q *FakeQuerier) GetWorkspaceBuildsByWorkspaceID(_ context.Context,
 		if workspaceBuild.CreatedAt.Before(params.Since) {
 			continue
 		}
+		if params.Reason != "" && string(workspaceBuild.Reason) != params.Reason {
+			continue
+		}
+		if params.InitiatorID != uuid.Nil && workspaceBuild.InitiatorID != params.InitiatorID {
+			continue
+		}
 		if workspaceBuild.WorkspaceID == params.WorkspaceID {
 			history = append(history, q.workspaceBuildWithUserNoLock(workspaceBuild))
 		}
@@ -3530,6 +5110,36 @@ func (q *FakeQuerier) GetWorkspaceByOwnerIDAndName(_ context.Context, arg databa
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceByOwnerIDAndPreviousName(_ context.Context, arg database.GetWorkspaceByOwnerIDAndPreviousNameParams) (database.Workspace, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.Workspace{}, err
+	}
+
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	now := time.Now()
+	var found *database.WorkspacePreviousName
+	for _, previous := range q.workspacePreviousNames {
+		previous := previous
+		if !strings.EqualFold(previous.Name, arg.Name) || !previous.RedirectExpiresAt.After(now) {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(context.Background(), previous.WorkspaceID)
+		if err != nil || workspace.OwnerID != arg.OwnerID || workspace.Deleted {
+			continue
+		}
+		if found == nil || previous.RenamedAt.After(found.RenamedAt) {
+			found = &previous
+		}
+	}
+	if found == nil {
+		return database.Workspace{}, sql.ErrNoRows
+	}
+	return q.getWorkspaceByIDNoLock(context.Background(), found.WorkspaceID)
+}
+
 func (q *FakeQuerier) GetWorkspaceByWorkspaceAppID(_ context.Context, workspaceAppID uuid.UUID) (database.Workspace, error) {
 	if err := validateDatabaseType(workspaceAppID); err != nil {
 		return database.Workspace{}, err
@@ -3547,6 +5157,68 @@ func (q *FakeQuerier) GetWorkspaceByWorkspaceAppID(_ context.Context, workspaceA
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) GetWorkspaceExternalMetadataByWorkspaceIDs(_ context.Context, ids []uuid.UUID) ([]database.GetWorkspaceExternalMetadataByWorkspaceIDsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	workspaces := make(map[uuid.UUID]database.Workspace)
+	for _, workspace := range q.workspaces {
+		workspaces[workspace.ID] = workspace
+	}
+
+	rows := make([]database.GetWorkspaceExternalMetadataByWorkspaceIDsRow, 0)
+	for _, metadatum := range q.workspaceExternalMetadata {
+		if !slices.Contains(ids, metadatum.WorkspaceID) {
+			continue
+		}
+		workspace, ok := workspaces[metadatum.WorkspaceID]
+		if !ok {
+			continue
+		}
+		rows = append(rows, database.GetWorkspaceExternalMetadataByWorkspaceIDsRow{
+			WorkspaceID:    metadatum.WorkspaceID,
+			Namespace:      metadatum.Namespace,
+			Visibility:     metadatum.Visibility,
+			Data:           metadatum.Data,
+			UpdatedAt:      metadatum.UpdatedAt,
+			OwnerID:        workspace.OwnerID,
+			OrganizationID: workspace.OrganizationID,
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Namespace < rows[j].Namespace
+	})
+	return rows, nil
+}
+
+func (q *FakeQuerier) GetWorkspacePreviousNamesByWorkspaceIDs(_ context.Context, ids []uuid.UUID) ([]database.GetWorkspacePreviousNamesByWorkspaceIDsRow, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	rows := make([]database.GetWorkspacePreviousNamesByWorkspaceIDsRow, 0)
+	for _, previous := range q.workspacePreviousNames {
+		if !slices.Contains(ids, previous.WorkspaceID) {
+			continue
+		}
+		workspace, err := q.getWorkspaceByIDNoLock(context.Background(), previous.WorkspaceID)
+		if err != nil {
+			continue
+		}
+		rows = append(rows, database.GetWorkspacePreviousNamesByWorkspaceIDsRow{
+			WorkspaceID:       previous.WorkspaceID,
+			Name:              previous.Name,
+			RenamedAt:         previous.RenamedAt,
+			RedirectExpiresAt: previous.RedirectExpiresAt,
+			OwnerID:           workspace.OwnerID,
+			OrganizationID:    workspace.OrganizationID,
+		})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].RenamedAt.After(rows[j].RenamedAt)
+	})
+	return rows, nil
+}
+
 func (q *FakeQuerier) GetWorkspaceProxies(_ context.Context) ([]database.WorkspaceProxy, error) {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -3711,6 +5383,34 @@ func (q *FakeQuerier) GetWorkspaceResourcesCreatedAfter(_ context.Context, after
 	return resources, nil
 }
 
+func (q *FakeQuerier) GetWorkspaceWebhookByID(_ context.Context, id uuid.UUID) (database.WorkspaceWebhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	for _, webhook := range q.workspaceWebhooks {
+		if webhook.ID == id {
+			return webhook, nil
+		}
+	}
+	return database.WorkspaceWebhook{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) GetWorkspaceWebhooksByWorkspaceID(_ context.Context, workspaceID uuid.UUID) ([]database.WorkspaceWebhook, error) {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	webhooks := make([]database.WorkspaceWebhook, 0)
+	for _, webhook := range q.workspaceWebhooks {
+		if webhook.WorkspaceID == workspaceID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].Name < webhooks[j].Name
+	})
+	return webhooks, nil
+}
+
 func (q *FakeQuerier) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -3816,6 +5516,7 @@ func (q *FakeQuerier) InsertAllUsersGroup(ctx context.Context, orgID uuid.UUID)
 		Name:           database.EveryoneGroup,
 		DisplayName:    "",
 		OrganizationID: orgID,
+		Metadata:       json.RawMessage("{}"),
 	})
 }
 
@@ -3843,6 +5544,26 @@ func (q *FakeQuerier) InsertAuditLog(_ context.Context, arg database.InsertAudit
 	return alog, nil
 }
 
+func (q *FakeQuerier) InsertAuditLogArchival(_ context.Context, arg database.InsertAuditLogArchivalParams) (database.AuditLogArchival, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.AuditLogArchival{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	archival := database.AuditLogArchival{
+		ID:          arg.ID,
+		InitiatorID: arg.InitiatorID,
+		Status:      database.AuditLogArchivalStatusRunning,
+		Cutoff:      arg.Cutoff,
+		Destination: arg.Destination,
+		StartedAt:   arg.StartedAt,
+	}
+	q.auditLogArchivals = append(q.auditLogArchivals, archival)
+	return archival, nil
+}
+
 func (q *FakeQuerier) InsertDERPMeshKey(_ context.Context, id string) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -3859,6 +5580,36 @@ func (q *FakeQuerier) InsertDeploymentID(_ context.Context, id string) error {
 	return nil
 }
 
+func (q *FakeQuerier) InsertEntitlementSnapshot(_ context.Context, arg database.InsertEntitlementSnapshotParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	date := truncateToDate(arg.Date)
+	for _, s := range q.entitlementSnapshots {
+		if s.Date.Equal(date) {
+			return nil
+		}
+	}
+	q.entitlementSnapshots = append(q.entitlementSnapshots, database.EntitlementSnapshot{
+		Date:            date,
+		HasLicense:      arg.HasLicense,
+		Trial:           arg.Trial,
+		Features:        arg.Features,
+		UserCount:       arg.UserCount,
+		ActiveUserCount: arg.ActiveUserCount,
+		ReplicaCount:    arg.ReplicaCount,
+		Warnings:        arg.Warnings,
+		Errors:          arg.Errors,
+		CreatedAt:       arg.CreatedAt,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) InsertFile(_ context.Context, arg database.InsertFileParams) (database.File, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.File{}, err
@@ -3945,6 +5696,7 @@ func (q *FakeQuerier) InsertGroup(_ context.Context, arg database.InsertGroupPar
 		AvatarURL:      arg.AvatarURL,
 		QuotaAllowance: arg.QuotaAllowance,
 		Source:         database.GroupSourceUser,
+		Metadata:       arg.Metadata,
 	}
 
 	q.groups = append(q.groups, group)
@@ -3952,6 +5704,58 @@ func (q *FakeQuerier) InsertGroup(_ context.Context, arg database.InsertGroupPar
 	return group, nil
 }
 
+func (q *FakeQuerier) InsertGroupDefaultTemplate(_ context.Context, arg database.InsertGroupDefaultTemplateParams) (database.GroupDefaultTemplate, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.GroupDefaultTemplate{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, defaultTemplate := range q.groupDefaultTemplates {
+		if defaultTemplate.GroupID == arg.GroupID && defaultTemplate.TemplateID == arg.TemplateID {
+			return database.GroupDefaultTemplate{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple // Every insert function uses this pattern.
+	defaultTemplate := database.GroupDefaultTemplate{
+		GroupID:         arg.GroupID,
+		TemplateID:      arg.TemplateID,
+		CreateWorkspace: arg.CreateWorkspace,
+		CreatedAt:       arg.CreatedAt,
+	}
+	q.groupDefaultTemplates = append(q.groupDefaultTemplates, defaultTemplate)
+	return defaultTemplate, nil
+}
+
+func (q *FakeQuerier) InsertGroupDefaultTemplateWorkspace(_ context.Context, arg database.InsertGroupDefaultTemplateWorkspaceParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, workspace := range q.groupDefaultTemplateWorkspaces {
+		if workspace.TemplateID == arg.TemplateID && workspace.UserID == arg.UserID {
+			return errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple // Every insert function uses this pattern.
+	q.groupDefaultTemplateWorkspaces = append(q.groupDefaultTemplateWorkspaces, database.GroupDefaultTemplateWorkspace{
+		TemplateID:  arg.TemplateID,
+		UserID:      arg.UserID,
+		GroupID:     arg.GroupID,
+		WorkspaceID: arg.WorkspaceID,
+		CreatedAt:   arg.CreatedAt,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) InsertGroupMember(_ context.Context, arg database.InsertGroupMemberParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -3987,16 +5791,51 @@ func (q *FakeQuerier) InsertLicense(
 	defer q.mutex.Unlock()
 
 	l := database.License{
-		ID:         q.lastLicenseID + 1,
-		UploadedAt: arg.UploadedAt,
-		JWT:        arg.JWT,
-		Exp:        arg.Exp,
+		ID:            q.lastLicenseID + 1,
+		UploadedAt:    arg.UploadedAt,
+		JWT:           arg.JWT,
+		Exp:           arg.Exp,
+		UUID:          arg.UUID,
+		ActivationKey: arg.ActivationKey,
 	}
 	q.lastLicenseID = l.ID
 	q.licenses = append(q.licenses, l)
 	return l, nil
 }
 
+func (q *FakeQuerier) InsertManagedEnvironmentVariable(_ context.Context, arg database.InsertManagedEnvironmentVariableParams) (database.ManagedEnvironmentVariable, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.ManagedEnvironmentVariable{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, variable := range q.managedEnvironmentVariables {
+		if variable.Name != arg.Name || variable.TemplateID != arg.TemplateID {
+			continue
+		}
+		if arg.TemplateID.Valid || variable.OrganizationID == arg.OrganizationID {
+			return database.ManagedEnvironmentVariable{}, errDuplicateKey
+		}
+	}
+
+	//nolint:gosimple
+	variable := database.ManagedEnvironmentVariable{
+		ID:             arg.ID,
+		OrganizationID: arg.OrganizationID,
+		TemplateID:     arg.TemplateID,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.UpdatedAt,
+		Name:           arg.Name,
+		Value:          arg.Value,
+		Secret:         arg.Secret,
+	}
+	q.managedEnvironmentVariables = append(q.managedEnvironmentVariables, variable)
+	return variable, nil
+}
+
 func (q *FakeQuerier) InsertMissingGroups(_ context.Context, arg database.InsertMissingGroupsParams) ([]database.Group, error) {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -4028,6 +5867,7 @@ func (q *FakeQuerier) InsertMissingGroups(_ context.Context, arg database.Insert
 			QuotaAllowance: 0,
 			DisplayName:    "",
 			Source:         arg.Source,
+			Metadata:       json.RawMessage("{}"),
 		}
 		q.groups = append(q.groups, g)
 		newGroups = append(newGroups, g)
@@ -4036,6 +5876,34 @@ func (q *FakeQuerier) InsertMissingGroups(_ context.Context, arg database.Insert
 	return newGroups, nil
 }
 
+func (q *FakeQuerier) InsertNetcheckReport(_ context.Context, arg database.InsertNetcheckReportParams) (database.NetcheckReport, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.NetcheckReport{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	report := database.NetcheckReport{
+		ID:                    arg.ID,
+		UserID:                arg.UserID,
+		CreatedAt:             arg.CreatedAt,
+		ClientVersion:         arg.ClientVersion,
+		ClientOS:              arg.ClientOS,
+		NATType:               arg.NATType,
+		UDP:                   arg.UDP,
+		IPv4:                  arg.IPv4,
+		IPv6:                  arg.IPv6,
+		MappingVariesByDestIP: arg.MappingVariesByDestIP,
+		HairPinning:           arg.HairPinning,
+		PreferredDERP:         arg.PreferredDERP,
+		RegionLatencies:       arg.RegionLatencies,
+		BlockedPorts:          arg.BlockedPorts,
+	}
+	q.netcheckReports = append(q.netcheckReports, report)
+	return report, nil
+}
+
 func (q *FakeQuerier) InsertOrganization(_ context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Organization{}, err
@@ -4074,6 +5942,26 @@ func (q *FakeQuerier) InsertOrganizationMember(_ context.Context, arg database.I
 	return organizationMember, nil
 }
 
+func (q *FakeQuerier) InsertPlatformEvent(_ context.Context, arg database.InsertPlatformEventParams) (database.PlatformEvent, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.PlatformEvent{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.platformEventsLastInsertID++
+	event := database.PlatformEvent{
+		ID:         q.platformEventsLastInsertID,
+		CreatedAt:  arg.CreatedAt,
+		Type:       arg.Type,
+		ResourceID: arg.ResourceID,
+		Data:       arg.Data,
+	}
+	q.platformEvents = append(q.platformEvents, event)
+	return event, nil
+}
+
 func (q *FakeQuerier) InsertProvisionerDaemon(_ context.Context, arg database.InsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.ProvisionerDaemon{}, err
@@ -4118,6 +6006,29 @@ func (q *FakeQuerier) InsertProvisionerJob(_ context.Context, arg database.Inser
 	return job, nil
 }
 
+func (q *FakeQuerier) InsertProvisionerJobBuildHook(_ context.Context, arg database.InsertProvisionerJobBuildHookParams) (database.ProvisionerJobBuildHook, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.ProvisionerJobBuildHook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	hook := database.ProvisionerJobBuildHook{
+		ID:          arg.ID,
+		JobID:       arg.JobID,
+		Name:        arg.Name,
+		Phase:       arg.Phase,
+		StartedAt:   arg.StartedAt,
+		CompletedAt: arg.CompletedAt,
+		Attempts:    arg.Attempts,
+		ExitCode:    arg.ExitCode,
+		Error:       arg.Error,
+	}
+	q.provisionerJobBuildHooks = append(q.provisionerJobBuildHooks, hook)
+	return hook, nil
+}
+
 func (q *FakeQuerier) InsertProvisionerJobLogs(_ context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -4197,11 +6108,41 @@ func (q *FakeQuerier) InsertTemplate(_ context.Context, arg database.InsertTempl
 		AllowUserCancelWorkspaceJobs: arg.AllowUserCancelWorkspaceJobs,
 		AllowUserAutostart:           true,
 		AllowUserAutostop:            true,
+		ActiveVersionUpdatedAt:       arg.CreatedAt,
+		PortPolicy:                   json.RawMessage("{}"),
 	}
 	q.templates = append(q.templates, template)
 	return nil
 }
 
+func (q *FakeQuerier) InsertTemplateAccessRequest(_ context.Context, arg database.InsertTemplateAccessRequestParams) (database.TemplateAccessRequest, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateAccessRequest{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, request := range q.templateAccessRequests {
+		if request.TemplateID == arg.TemplateID && request.RequestedBy == arg.RequestedBy &&
+			request.Status == database.TemplateAccessRequestStatusPending {
+			return database.TemplateAccessRequest{}, errDuplicateKey
+		}
+	}
+
+	request := database.TemplateAccessRequest{
+		ID:          arg.ID,
+		TemplateID:  arg.TemplateID,
+		RequestedBy: arg.RequestedBy,
+		CreatedAt:   arg.CreatedAt,
+		Message:     arg.Message,
+		Status:      database.TemplateAccessRequestStatusPending,
+	}
+	q.templateAccessRequests = append(q.templateAccessRequests, request)
+	return request, nil
+}
+
 func (q *FakeQuerier) InsertTemplateVersion(_ context.Context, arg database.InsertTemplateVersionParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -4258,11 +6199,43 @@ func (q *FakeQuerier) InsertTemplateVersionParameter(_ context.Context, arg data
 		Required:            arg.Required,
 		DisplayOrder:        arg.DisplayOrder,
 		Ephemeral:           arg.Ephemeral,
+		Dependencies:        arg.Dependencies,
+		OptionsURL:          arg.OptionsURL,
 	}
 	q.templateVersionParameters = append(q.templateVersionParameters, param)
 	return param, nil
 }
 
+func (q *FakeQuerier) InsertTemplateVersionPromotion(_ context.Context, arg database.InsertTemplateVersionPromotionParams) (database.TemplateVersionPromotion, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateVersionPromotion{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, promotion := range q.templateVersionPromotions {
+		if promotion.TemplateVersionID == arg.TemplateVersionID &&
+			promotion.Status == database.TemplateVersionPromotionStatusPending {
+			return database.TemplateVersionPromotion{}, errDuplicateKey
+		}
+	}
+
+	promotion := database.TemplateVersionPromotion{
+		ID:                arg.ID,
+		OrganizationID:    arg.OrganizationID,
+		TemplateID:        arg.TemplateID,
+		TemplateVersionID: arg.TemplateVersionID,
+		RequestedBy:       arg.RequestedBy,
+		CreatedAt:         arg.CreatedAt,
+		Message:           arg.Message,
+		Status:            database.TemplateVersionPromotionStatusPending,
+	}
+	q.templateVersionPromotions = append(q.templateVersionPromotions, promotion)
+	return promotion, nil
+}
+
 func (q *FakeQuerier) InsertTemplateVersionVariable(_ context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.TemplateVersionVariable{}, err
@@ -4330,6 +6303,27 @@ func (q *FakeQuerier) InsertUser(_ context.Context, arg database.InsertUserParam
 	return user, nil
 }
 
+func (q *FakeQuerier) InsertUserDeprovision(_ context.Context, arg database.InsertUserDeprovisionParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, deprovision := range q.userDeprovisions {
+		if deprovision.UserID == arg.UserID {
+			return nil
+		}
+	}
+	q.userDeprovisions = append(q.userDeprovisions, database.UserDeprovision{
+		UserID:          arg.UserID,
+		DeprovisionedAt: arg.DeprovisionedAt,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) InsertUserGroupsByName(_ context.Context, arg database.InsertUserGroupsByNameParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -4392,6 +6386,7 @@ func (q *FakeQuerier) InsertWorkspace(_ context.Context, arg database.InsertWork
 		AutostartSchedule: arg.AutostartSchedule,
 		Ttl:               arg.Ttl,
 		LastUsedAt:        arg.LastUsedAt,
+		AutomaticUpdates:  arg.AutomaticUpdates,
 	}
 	q.workspaces = append(q.workspaces, workspace)
 	return workspace, nil
@@ -4426,12 +6421,84 @@ func (q *FakeQuerier) InsertWorkspaceAgent(_ context.Context, arg database.Inser
 		MOTDFile:                 arg.MOTDFile,
 		LifecycleState:           database.WorkspaceAgentLifecycleStateCreated,
 		ShutdownScript:           arg.ShutdownScript,
+		DisplayOrder:             arg.DisplayOrder,
+		HostFacts:                json.RawMessage("{}"),
 	}
 
 	q.workspaceAgents = append(q.workspaceAgents, agent)
 	return agent, nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceAgentAuthTokenRotation(_ context.Context, arg database.InsertWorkspaceAgentAuthTokenRotationParams) (database.WorkspaceAgentAuthTokenRotation, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceAgentAuthTokenRotation{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	//nolint:gosimple // Every insert function uses this pattern.
+	rotation := database.WorkspaceAgentAuthTokenRotation{
+		ID:                         arg.ID,
+		AgentID:                    arg.AgentID,
+		PreviousAuthToken:          arg.PreviousAuthToken,
+		PreviousAuthTokenExpiresAt: arg.PreviousAuthTokenExpiresAt,
+		Revoked:                    arg.Revoked,
+		CreatedAt:                  arg.CreatedAt,
+	}
+	q.workspaceAgentAuthTokenRotations = append(q.workspaceAgentAuthTokenRotations, rotation)
+	return rotation, nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceAgentBandwidthStats(_ context.Context, arg database.InsertWorkspaceAgentBandwidthStatsParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i := 0; i < len(arg.ID); i++ {
+		q.workspaceAgentBandwidthStats = append(q.workspaceAgentBandwidthStats, database.WorkspaceAgentBandwidthStat{
+			ID:             arg.ID[i],
+			CreatedAt:      arg.CreatedAt[i],
+			UserID:         arg.UserID[i],
+			AgentID:        arg.AgentID[i],
+			WorkspaceID:    arg.WorkspaceID[i],
+			TemplateID:     arg.TemplateID[i],
+			ConnectionType: arg.ConnectionType[i],
+			Peer:           arg.Peer[i],
+			Port:           arg.Port[i],
+			RxBytes:        arg.RxBytes[i],
+			TxBytes:        arg.TxBytes[i],
+		})
+	}
+	return nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceAgentClientConnection(_ context.Context, arg database.InsertWorkspaceAgentClientConnectionParams) (database.WorkspaceAgentClientConnection, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspaceAgentClientConnection{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	conn := database.WorkspaceAgentClientConnection{
+		ID:               arg.ID,
+		WorkspaceAgentID: arg.WorkspaceAgentID,
+		UserID:           arg.UserID,
+		ClientType:       arg.ClientType,
+		ClientVersion:    arg.ClientVersion,
+		ConnectedAt:      arg.ConnectedAt,
+		IPAddress:        arg.IPAddress,
+	}
+	q.workspaceAgentClientConnections = append(q.workspaceAgentClientConnections, conn)
+	return conn, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceAgentLogs(_ context.Context, arg database.InsertWorkspaceAgentLogsParams) ([]database.WorkspaceAgentLog, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -4495,6 +6562,80 @@ func (q *FakeQuerier) InsertWorkspaceAgentMetadata(_ context.Context, arg databa
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceAgentMetadataHistory(_ context.Context, arg database.InsertWorkspaceAgentMetadataHistoryParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	declared := false
+	for _, m := range q.workspaceAgentMetadata {
+		if m.WorkspaceAgentID == arg.WorkspaceAgentID && m.Key == arg.Key {
+			declared = true
+			break
+		}
+	}
+	if !declared {
+		return nil
+	}
+
+	q.workspaceAgentMetadataHistoryLastInsertID++
+	q.workspaceAgentMetadataHistory = append(q.workspaceAgentMetadataHistory, database.WorkspaceAgentMetadataHistory{
+		ID:               q.workspaceAgentMetadataHistoryLastInsertID,
+		WorkspaceAgentID: arg.WorkspaceAgentID,
+		Key:              arg.Key,
+		Value:            arg.Value,
+		Error:            arg.Error,
+		CollectedAt:      arg.CollectedAt,
+	})
+
+	// Entries are appended in ID order, so the oldest values of the key
+	// come first and are the ones to drop.
+	count := 0
+	for _, h := range q.workspaceAgentMetadataHistory {
+		if h.WorkspaceAgentID == arg.WorkspaceAgentID && h.Key == arg.Key {
+			count++
+		}
+	}
+	drop := count - int(arg.MaxEntries)
+	history := make([]database.WorkspaceAgentMetadataHistory, 0, len(q.workspaceAgentMetadataHistory))
+	for _, h := range q.workspaceAgentMetadataHistory {
+		if drop > 0 && h.WorkspaceAgentID == arg.WorkspaceAgentID && h.Key == arg.Key {
+			drop--
+			continue
+		}
+		history = append(history, h)
+	}
+	q.workspaceAgentMetadataHistory = history
+	return nil
+}
+
+func (q *FakeQuerier) InsertWorkspaceAgentSSHHostKey(_ context.Context, arg database.InsertWorkspaceAgentSSHHostKeyParams) (database.WorkspaceAgentSSHHostKey, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspaceAgentSSHHostKey{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, key := range q.workspaceAgentSSHHostKeys {
+		if key.WorkspaceID == arg.WorkspaceID && key.AgentName == arg.AgentName {
+			return key, nil
+		}
+	}
+	key := database.WorkspaceAgentSSHHostKey{
+		WorkspaceID:         arg.WorkspaceID,
+		AgentName:           arg.AgentName,
+		EncryptedPrivateKey: arg.EncryptedPrivateKey,
+		CreatedAt:           arg.CreatedAt,
+	}
+	q.workspaceAgentSSHHostKeys = append(q.workspaceAgentSSHHostKeys, key)
+	return key, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceAgentStat(_ context.Context, p database.InsertWorkspaceAgentStatParams) (database.WorkspaceAgentStat, error) {
 	if err := validateDatabaseType(p); err != nil {
 		return database.WorkspaceAgentStat{}, err
@@ -4598,6 +6739,8 @@ func (q *FakeQuerier) InsertWorkspaceApp(_ context.Context, arg database.InsertW
 		HealthcheckInterval:  arg.HealthcheckInterval,
 		HealthcheckThreshold: arg.HealthcheckThreshold,
 		Health:               arg.Health,
+		DisplayOrder:         arg.DisplayOrder,
+		Hidden:               arg.Hidden,
 	}
 	q.workspaceApps = append(q.workspaceApps, workspaceApp)
 	return workspaceApp, nil
@@ -4641,6 +6784,33 @@ InsertWorkspaceAppStatsLoop:
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceApproval(_ context.Context, arg database.InsertWorkspaceApprovalParams) (database.WorkspaceApproval, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, approval := range q.workspaceApprovals {
+		if approval.JobID == arg.JobID {
+			return database.WorkspaceApproval{}, errDuplicateKey
+		}
+	}
+
+	approval := database.WorkspaceApproval{
+		ID:          arg.ID,
+		WorkspaceID: arg.WorkspaceID,
+		JobID:       arg.JobID,
+		RequestedBy: arg.RequestedBy,
+		CreatedAt:   arg.CreatedAt,
+		Status:      database.WorkspaceApprovalStatusPending,
+	}
+	q.workspaceApprovals = append(q.workspaceApprovals, approval)
+	return approval, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceBuild(_ context.Context, arg database.InsertWorkspaceBuildParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -4650,18 +6820,19 @@ func (q *FakeQuerier) InsertWorkspaceBuild(_ context.Context, arg database.Inser
 	defer q.mutex.Unlock()
 
 	workspaceBuild := database.WorkspaceBuildTable{
-		ID:                arg.ID,
-		CreatedAt:         arg.CreatedAt,
-		UpdatedAt:         arg.UpdatedAt,
-		WorkspaceID:       arg.WorkspaceID,
-		TemplateVersionID: arg.TemplateVersionID,
-		BuildNumber:       arg.BuildNumber,
-		Transition:        arg.Transition,
-		InitiatorID:       arg.InitiatorID,
-		JobID:             arg.JobID,
-		ProvisionerState:  arg.ProvisionerState,
-		Deadline:          arg.Deadline,
-		Reason:            arg.Reason,
+		ID:                 arg.ID,
+		CreatedAt:          arg.CreatedAt,
+		UpdatedAt:          arg.UpdatedAt,
+		WorkspaceID:        arg.WorkspaceID,
+		TemplateVersionID:  arg.TemplateVersionID,
+		BuildNumber:        arg.BuildNumber,
+		Transition:         arg.Transition,
+		InitiatorID:        arg.InitiatorID,
+		JobID:              arg.JobID,
+		ProvisionerState:   arg.ProvisionerState,
+		Deadline:           arg.Deadline,
+		Reason:             arg.Reason,
+		InitiatorTokenName: arg.InitiatorTokenName,
 	}
 	q.workspaceBuilds = append(q.workspaceBuilds, workspaceBuild)
 	return nil
@@ -4685,6 +6856,27 @@ func (q *FakeQuerier) InsertWorkspaceBuildParameters(_ context.Context, arg data
 	return nil
 }
 
+func (q *FakeQuerier) InsertWorkspaceBuildQueueEntry(_ context.Context, arg database.InsertWorkspaceBuildQueueEntryParams) (database.WorkspaceBuildQueueEntry, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceBuildQueueEntry{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	entry := database.WorkspaceBuildQueueEntry{
+		ID:                 arg.ID,
+		WorkspaceID:        arg.WorkspaceID,
+		InitiatorID:        arg.InitiatorID,
+		InitiatorTokenName: arg.InitiatorTokenName,
+		CreatedAt:          arg.CreatedAt,
+		Request:            arg.Request,
+	}
+	q.workspaceBuildQueueEntries = append(q.workspaceBuildQueueEntries, entry)
+	return entry, nil
+}
+
 func (q *FakeQuerier) InsertWorkspaceProxy(_ context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -4711,6 +6903,8 @@ func (q *FakeQuerier) InsertWorkspaceProxy(_ context.Context, arg database.Inser
 		CreatedAt:         arg.CreatedAt,
 		UpdatedAt:         arg.UpdatedAt,
 		Deleted:           false,
+		OrganizationIDs:   []uuid.UUID{},
+		GroupIDs:          []uuid.UUID{},
 	}
 	q.workspaceProxies = append(q.workspaceProxies, p)
 	return p, nil
@@ -4726,15 +6920,16 @@ func (q *FakeQuerier) InsertWorkspaceResource(_ context.Context, arg database.In
 
 	//nolint:gosimple
 	resource := database.WorkspaceResource{
-		ID:         arg.ID,
-		CreatedAt:  arg.CreatedAt,
-		JobID:      arg.JobID,
-		Transition: arg.Transition,
-		Type:       arg.Type,
-		Name:       arg.Name,
-		Hide:       arg.Hide,
-		Icon:       arg.Icon,
-		DailyCost:  arg.DailyCost,
+		ID:           arg.ID,
+		CreatedAt:    arg.CreatedAt,
+		JobID:        arg.JobID,
+		Transition:   arg.Transition,
+		Type:         arg.Type,
+		Name:         arg.Name,
+		Hide:         arg.Hide,
+		Icon:         arg.Icon,
+		DailyCost:    arg.DailyCost,
+		DisplayOrder: arg.DisplayOrder,
 	}
 	q.workspaceResources = append(q.workspaceResources, resource)
 	return resource, nil
@@ -4771,7 +6966,37 @@ func (q *FakeQuerier) InsertWorkspaceResourceMetadata(_ context.Context, arg dat
 	return metadata, nil
 }
 
-func (q *FakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
+func (q *FakeQuerier) InsertWorkspaceWebhook(_ context.Context, arg database.InsertWorkspaceWebhookParams) (database.WorkspaceWebhook, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceWebhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, webhook := range q.workspaceWebhooks {
+		if webhook.WorkspaceID == arg.WorkspaceID && webhook.Name == arg.Name {
+			return database.WorkspaceWebhook{}, errDuplicateKey
+		}
+	}
+
+	webhook := database.WorkspaceWebhook{
+		ID:          arg.ID,
+		WorkspaceID: arg.WorkspaceID,
+		CreatedBy:   arg.CreatedBy,
+		CreatedAt:   arg.CreatedAt,
+		Name:        arg.Name,
+		Action:      arg.Action,
+		AgentName:   arg.AgentName,
+		Command:     arg.Command,
+		Secret:      arg.Secret,
+	}
+	q.workspaceWebhooks = append(q.workspaceWebhooks, webhook)
+	return webhook, nil
+}
+
+func (q *FakeQuerier) RegisterWorkspaceProxy(_ context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
 
@@ -4814,6 +7039,28 @@ func (q *FakeQuerier) UpdateAPIKeyByID(_ context.Context, arg database.UpdateAPI
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateAuditLogArchivalByID(_ context.Context, arg database.UpdateAuditLogArchivalByIDParams) (database.AuditLogArchival, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.AuditLogArchival{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, archival := range q.auditLogArchivals {
+		if archival.ID != arg.ID {
+			continue
+		}
+		archival.Status = arg.Status
+		archival.ArchivedCount = arg.ArchivedCount
+		archival.Error = arg.Error
+		archival.FinishedAt = arg.FinishedAt
+		q.auditLogArchivals[i] = archival
+		return archival, nil
+	}
+	return database.AuditLogArchival{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateGitAuthLink(_ context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.GitAuthLink{}, err
@@ -4860,6 +7107,25 @@ func (q *FakeQuerier) UpdateGitSSHKey(_ context.Context, arg database.UpdateGitS
 	return database.GitSSHKey{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateGroupAvatarByID(_ context.Context, arg database.UpdateGroupAvatarByIDParams) (database.Group, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Group{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, group := range q.groups {
+		if group.ID == arg.ID {
+			group.AvatarFileID = arg.AvatarFileID
+			group.AvatarURL = arg.AvatarURL
+			q.groups[i] = group
+			return group, nil
+		}
+	}
+	return database.Group{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateGroupByID(_ context.Context, arg database.UpdateGroupByIDParams) (database.Group, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.Group{}, err
@@ -4872,8 +7138,13 @@ func (q *FakeQuerier) UpdateGroupByID(_ context.Context, arg database.UpdateGrou
 		if group.ID == arg.ID {
 			group.DisplayName = arg.DisplayName
 			group.Name = arg.Name
+			if group.AvatarURL != arg.AvatarURL {
+				group.AvatarFileID = uuid.NullUUID{}
+			}
 			group.AvatarURL = arg.AvatarURL
 			group.QuotaAllowance = arg.QuotaAllowance
+			group.QuotaOverride = arg.QuotaOverride
+			group.Metadata = arg.Metadata
 			q.groups[i] = group
 			return group, nil
 		}
@@ -4904,6 +7175,28 @@ func (q *FakeQuerier) UpdateInactiveUsersToDormant(_ context.Context, params dat
 	return updated, nil
 }
 
+func (q *FakeQuerier) UpdateManagedEnvironmentVariableByID(_ context.Context, arg database.UpdateManagedEnvironmentVariableByIDParams) (database.ManagedEnvironmentVariable, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.ManagedEnvironmentVariable{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, variable := range q.managedEnvironmentVariables {
+		if variable.ID != arg.ID {
+			continue
+		}
+		variable.UpdatedAt = arg.UpdatedAt
+		variable.Value = arg.Value
+		variable.Secret = arg.Secret
+		q.managedEnvironmentVariables[i] = variable
+		return variable, nil
+	}
+	return database.ManagedEnvironmentVariable{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return database.OrganizationMember{}, err
@@ -4934,6 +7227,26 @@ func (q *FakeQuerier) UpdateMemberRoles(_ context.Context, arg database.UpdateMe
 	return database.OrganizationMember{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateOrganizationProvisionerLimits(_ context.Context, arg database.UpdateOrganizationProvisionerLimitsParams) (database.Organization, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Organization{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, org := range q.organizations {
+		if org.ID != arg.ID {
+			continue
+		}
+		org.UpdatedAt = arg.UpdatedAt
+		org.MaxConcurrentProvisionerJobs = arg.MaxConcurrentProvisionerJobs
+		q.organizations[i] = org
+		return org, nil
+	}
+	return database.Organization{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateProvisionerJobByID(_ context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -4989,6 +7302,29 @@ func (q *FakeQuerier) UpdateProvisionerJobWithCompleteByID(_ context.Context, ar
 		job.CompletedAt = arg.CompletedAt
 		job.Error = arg.Error
 		job.ErrorCode = arg.ErrorCode
+		job.CancelOutcome = arg.CancelOutcome
+		q.provisionerJobs[index] = job
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateProvisionerJobWithRequeueByID(_ context.Context, arg database.UpdateProvisionerJobWithRequeueByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, job := range q.provisionerJobs {
+		if arg.ID != job.ID {
+			continue
+		}
+		job.UpdatedAt = arg.UpdatedAt
+		job.StartedAt = sql.NullTime{}
+		job.WorkerID = uuid.NullUUID{}
+		job.RequeueCount++
 		q.provisionerJobs[index] = job
 		return nil
 	}
@@ -5023,6 +7359,25 @@ func (q *FakeQuerier) UpdateReplica(_ context.Context, arg database.UpdateReplic
 	return database.Replica{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateReplicaDrainingAt(_ context.Context, arg database.UpdateReplicaDrainingAtParams) (database.Replica, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.Replica{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, replica := range q.replicas {
+		if replica.ID != arg.ID || replica.StoppedAt.Valid {
+			continue
+		}
+		replica.DrainingAt = arg.DrainingAt
+		q.replicas[index] = replica
+		return replica, nil
+	}
+	return database.Replica{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateTemplateACLByID(_ context.Context, arg database.UpdateTemplateACLByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5044,6 +7399,29 @@ func (q *FakeQuerier) UpdateTemplateACLByID(_ context.Context, arg database.Upda
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplateAccessRequestStatusByID(_ context.Context, arg database.UpdateTemplateAccessRequestStatusByIDParams) (database.TemplateAccessRequest, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateAccessRequest{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, request := range q.templateAccessRequests {
+		if request.ID != arg.ID || request.Status != database.TemplateAccessRequestStatusPending {
+			continue
+		}
+		request.Status = arg.Status
+		request.DecidedBy = arg.DecidedBy
+		request.DecidedAt = arg.DecidedAt
+		request.Reason = arg.Reason
+		q.templateAccessRequests[i] = request
+		return request, nil
+	}
+	return database.TemplateAccessRequest{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateTemplateActiveVersionByID(_ context.Context, arg database.UpdateTemplateActiveVersionByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5058,6 +7436,7 @@ func (q *FakeQuerier) UpdateTemplateActiveVersionByID(_ context.Context, arg dat
 		}
 		template.ActiveVersionID = arg.ActiveVersionID
 		template.UpdatedAt = arg.UpdatedAt
+		template.ActiveVersionUpdatedAt = arg.UpdatedAt
 		q.templates[index] = template
 		return nil
 	}
@@ -5101,6 +7480,17 @@ func (q *FakeQuerier) UpdateTemplateMetaByID(_ context.Context, arg database.Upd
 		tpl.DisplayName = arg.DisplayName
 		tpl.Description = arg.Description
 		tpl.Icon = arg.Icon
+		tpl.RequireActiveVersion = arg.RequireActiveVersion
+		tpl.RequireActiveVersionGracePeriod = arg.RequireActiveVersionGracePeriod
+		tpl.MaxBuildDuration = arg.MaxBuildDuration
+		tpl.ProvisionerMemoryLimit = arg.ProvisionerMemoryLimit
+		tpl.ProvisionerCPULimit = arg.ProvisionerCPULimit
+		tpl.RequireWorkspaceApproval = arg.RequireWorkspaceApproval
+		tpl.RequireAgentBinaryVerification = arg.RequireAgentBinaryVerification
+		tpl.RequirePromotionApproval = arg.RequirePromotionApproval
+		tpl.MaxConcurrentProvisionerJobs = arg.MaxConcurrentProvisionerJobs
+		tpl.MaxSessionIdle = arg.MaxSessionIdle
+		tpl.PortPolicy = arg.PortPolicy
 		q.templates[idx] = tpl
 		return nil
 	}
@@ -5130,6 +7520,7 @@ func (q *FakeQuerier) UpdateTemplateScheduleByID(_ context.Context, arg database
 		tpl.FailureTTL = arg.FailureTTL
 		tpl.InactivityTTL = arg.InactivityTTL
 		tpl.LockedTTL = arg.LockedTTL
+		tpl.AutostopActivitySources = arg.AutostopActivitySources
 		q.templates[idx] = tpl
 		return nil
 	}
@@ -5199,6 +7590,29 @@ func (q *FakeQuerier) UpdateTemplateVersionGitAuthProvidersByJobID(_ context.Con
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateTemplateVersionPromotionStatusByID(_ context.Context, arg database.UpdateTemplateVersionPromotionStatusByIDParams) (database.TemplateVersionPromotion, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.TemplateVersionPromotion{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, promotion := range q.templateVersionPromotions {
+		if promotion.ID != arg.ID || promotion.Status != database.TemplateVersionPromotionStatusPending {
+			continue
+		}
+		promotion.Status = arg.Status
+		promotion.DecidedBy = arg.DecidedBy
+		promotion.DecidedAt = arg.DecidedAt
+		promotion.Reason = arg.Reason
+		q.templateVersionPromotions[i] = promotion
+		return promotion, nil
+	}
+	return database.TemplateVersionPromotion{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateTemplateWorkspacesLastUsedAt(_ context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	err := validateDatabaseType(arg)
 	if err != nil {
@@ -5478,6 +7892,64 @@ func (q *FakeQuerier) UpdateWorkspace(_ context.Context, arg database.UpdateWork
 	return database.Workspace{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceAgentAuthTokenByID(_ context.Context, arg database.UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, agent := range q.workspaceAgents {
+		if agent.ID != arg.ID {
+			continue
+		}
+		agent.AuthToken = arg.AuthToken
+		agent.UpdatedAt = arg.UpdatedAt
+		q.workspaceAgents[index] = agent
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateWorkspaceAgentClientConnectionDisconnectedAt(_ context.Context, arg database.UpdateWorkspaceAgentClientConnectionDisconnectedAtParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, conn := range q.workspaceAgentClientConnections {
+		if conn.ID != arg.ID {
+			continue
+		}
+		conn.DisconnectedAt = arg.DisconnectedAt
+		q.workspaceAgentClientConnections[i] = conn
+		return nil
+	}
+	return nil
+}
+
+func (q *FakeQuerier) UpdateWorkspaceAgentClockOffsetByID(_ context.Context, arg database.UpdateWorkspaceAgentClockOffsetByIDParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, agent := range q.workspaceAgents {
+		if agent.ID != arg.ID {
+			continue
+		}
+		agent.ClockOffsetMS = arg.ClockOffsetMS
+		q.workspaceAgents[index] = agent
+		return nil
+	}
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAgentConnectionByID(_ context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5592,6 +8064,7 @@ func (q *FakeQuerier) UpdateWorkspaceAgentStartupByID(_ context.Context, arg dat
 		agent.Version = arg.Version
 		agent.ExpandedDirectory = arg.ExpandedDirectory
 		agent.Subsystems = arg.Subsystems
+		agent.HostFacts = arg.HostFacts
 		q.workspaceAgents[index] = agent
 		return nil
 	}
@@ -5617,6 +8090,49 @@ func (q *FakeQuerier) UpdateWorkspaceAppHealthByID(_ context.Context, arg databa
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceApprovalStatusByID(_ context.Context, arg database.UpdateWorkspaceApprovalStatusByIDParams) (database.WorkspaceApproval, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceApproval{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, approval := range q.workspaceApprovals {
+		if approval.ID != arg.ID || approval.Status != database.WorkspaceApprovalStatusPending {
+			continue
+		}
+		approval.Status = arg.Status
+		approval.DecidedBy = arg.DecidedBy
+		approval.DecidedAt = arg.DecidedAt
+		approval.Reason = arg.Reason
+		q.workspaceApprovals[i] = approval
+		return approval, nil
+	}
+	return database.WorkspaceApproval{}, sql.ErrNoRows
+}
+
+func (q *FakeQuerier) UpdateWorkspaceAutomaticUpdates(_ context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for index, workspace := range q.workspaces {
+		if workspace.ID != arg.ID {
+			continue
+		}
+		workspace.AutomaticUpdates = arg.AutomaticUpdates
+		q.workspaces[index] = workspace
+		return nil
+	}
+
+	return sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceAutostart(_ context.Context, arg database.UpdateWorkspaceAutostartParams) error {
 	if err := validateDatabaseType(arg); err != nil {
 		return err
@@ -5781,6 +8297,26 @@ func (q *FakeQuerier) UpdateWorkspaceProxy(_ context.Context, arg database.Updat
 	return database.WorkspaceProxy{}, sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceProxyACL(_ context.Context, arg database.UpdateWorkspaceProxyACLParams) (database.WorkspaceProxy, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.WorkspaceProxy{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, p := range q.workspaceProxies {
+		if p.ID == arg.ID {
+			p.OrganizationIDs = arg.OrganizationIDs
+			p.GroupIDs = arg.GroupIDs
+			p.UpdatedAt = database.Now()
+			q.workspaceProxies[i] = p
+			return p, nil
+		}
+	}
+	return database.WorkspaceProxy{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspaceProxyDeleted(_ context.Context, arg database.UpdateWorkspaceProxyDeletedParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -5816,6 +8352,28 @@ func (q *FakeQuerier) UpdateWorkspaceTTL(_ context.Context, arg database.UpdateW
 	return sql.ErrNoRows
 }
 
+func (q *FakeQuerier) UpdateWorkspaceWebhookLastTriggeredAt(_ context.Context, arg database.UpdateWorkspaceWebhookLastTriggeredAtParams) (database.WorkspaceWebhook, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceWebhook{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, webhook := range q.workspaceWebhooks {
+		if webhook.ID != arg.ID {
+			continue
+		}
+		if webhook.LastTriggeredAt.Valid && !webhook.LastTriggeredAt.Time.Before(arg.LastTriggeredAt.Time) {
+			return database.WorkspaceWebhook{}, sql.ErrNoRows
+		}
+		q.workspaceWebhooks[i].LastTriggeredAt = arg.LastTriggeredAt
+		return q.workspaceWebhooks[i], nil
+	}
+	return database.WorkspaceWebhook{}, sql.ErrNoRows
+}
+
 func (q *FakeQuerier) UpdateWorkspacesLockedDeletingAtByTemplateID(_ context.Context, arg database.UpdateWorkspacesLockedDeletingAtByTemplateIDParams) error {
 	q.mutex.Lock()
 	defer q.mutex.Unlock()
@@ -5876,6 +8434,45 @@ func (q *FakeQuerier) UpsertLastUpdateCheck(_ context.Context, data string) erro
 	return nil
 }
 
+func (q *FakeQuerier) UpsertLicenseUsage(_ context.Context, arg database.UpsertLicenseUsageParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	date := truncateToDate(arg.Date)
+	for i, u := range q.licenseUsage {
+		if u.Feature != arg.Feature || !u.Date.Equal(date) {
+			continue
+		}
+		if arg.Actual > u.PeakActual {
+			u.PeakActual = arg.Actual
+		}
+		u.LatestActual = arg.Actual
+		u.FeatureLimit = arg.FeatureLimit
+		if !u.FirstExceededAt.Valid {
+			u.FirstExceededAt = arg.FirstExceededAt
+		}
+		u.UpdatedAt = arg.UpdatedAt
+		q.licenseUsage[i] = u
+		return nil
+	}
+
+	q.licenseUsage = append(q.licenseUsage, database.LicenseUsage{
+		Feature:         arg.Feature,
+		Date:            date,
+		PeakActual:      arg.Actual,
+		LatestActual:    arg.Actual,
+		FeatureLimit:    arg.FeatureLimit,
+		FirstExceededAt: arg.FirstExceededAt,
+		UpdatedAt:       arg.UpdatedAt,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) UpsertLogoURL(_ context.Context, data string) error {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -5892,6 +8489,22 @@ func (q *FakeQuerier) UpsertOAuthSigningKey(_ context.Context, value string) err
 	return nil
 }
 
+func (q *FakeQuerier) UpsertRuntimeExperiments(_ context.Context, value string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.runtimeExperiments = []byte(value)
+	return nil
+}
+
+func (q *FakeQuerier) UpsertSSHCAKey(_ context.Context, value string) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.sshCAKey = value
+	return nil
+}
+
 func (q *FakeQuerier) UpsertServiceBanner(_ context.Context, data string) error {
 	q.mutex.RLock()
 	defer q.mutex.RUnlock()
@@ -5912,6 +8525,230 @@ func (*FakeQuerier) UpsertTailnetCoordinator(context.Context, uuid.UUID) (databa
 	return database.TailnetCoordinator{}, ErrUnimplemented
 }
 
+func (q *FakeQuerier) UpsertTemplateVersionBuildInsights(ctx context.Context, arg database.UpsertTemplateVersionBuildInsightsParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	versionIDs := make(map[uuid.UUID]bool)
+	for _, wb := range q.workspaceBuilds {
+		job, err := q.getProvisionerJobByIDNoLock(ctx, wb.JobID)
+		if err != nil {
+			return err
+		}
+		if job.CompletedAt.Valid && !job.CompletedAt.Time.Before(arg.Since) {
+			versionIDs[wb.TemplateVersionID] = true
+		}
+	}
+
+	type rollup struct {
+		insight   database.TemplateVersionBuildInsight
+		failures  map[string]int64
+		durations []float64
+	}
+	rollups := make(map[uuid.UUID]*rollup)
+	for _, wb := range q.workspaceBuilds {
+		if !versionIDs[wb.TemplateVersionID] {
+			continue
+		}
+		job, err := q.getProvisionerJobByIDNoLock(ctx, wb.JobID)
+		if err != nil {
+			return err
+		}
+		if !job.CompletedAt.Valid || !job.StartedAt.Valid || job.CanceledAt.Valid {
+			continue
+		}
+		version, err := q.getTemplateVersionByIDNoLock(ctx, wb.TemplateVersionID)
+		if err != nil {
+			return err
+		}
+		if !version.TemplateID.Valid {
+			continue
+		}
+
+		r, ok := rollups[version.ID]
+		if !ok {
+			r = &rollup{
+				insight: database.TemplateVersionBuildInsight{
+					TemplateVersionID: version.ID,
+					TemplateID:        version.TemplateID.UUID,
+					UpdatedAt:         arg.UpdatedAt,
+				},
+				failures: make(map[string]int64),
+			}
+			rollups[version.ID] = r
+		}
+		r.insight.TotalBuilds++
+		if job.Error.String != "" {
+			r.insight.FailedBuilds++
+			r.failures[job.ErrorCode.String]++
+			continue
+		}
+		r.durations = append(r.durations, job.CompletedAt.Time.Sub(job.StartedAt.Time).Seconds())
+	}
+
+	tryPercentile := func(fs []float64, p float64) float64 {
+		if len(fs) == 0 {
+			return -1
+		}
+		sort.Float64s(fs)
+		return fs[int(float64(len(fs))*p/100)]
+	}
+
+	for _, r := range rollups {
+		failures, err := json.Marshal(r.failures)
+		if err != nil {
+			return err
+		}
+		r.insight.Failures = failures
+		r.insight.DurationSeconds50 = tryPercentile(r.durations, 50)
+		r.insight.DurationSeconds95 = tryPercentile(r.durations, 95)
+
+		i := slices.IndexFunc(q.templateVersionBuildInsights, func(insight database.TemplateVersionBuildInsight) bool {
+			return insight.TemplateVersionID == r.insight.TemplateVersionID
+		})
+		if i >= 0 {
+			q.templateVersionBuildInsights[i] = r.insight
+			continue
+		}
+		q.templateVersionBuildInsights = append(q.templateVersionBuildInsights, r.insight)
+	}
+
+	return nil
+}
+
+func (q *FakeQuerier) UpsertUserPTYSessionOverride(_ context.Context, arg database.UpsertUserPTYSessionOverrideParams) (database.UserPTYSessionOverride, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserPTYSessionOverride{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, override := range q.userPTYSessionOverrides {
+		if override.UserID == arg.UserID {
+			override.MaxPTYSessions = arg.MaxPTYSessions
+			override.UpdatedAt = arg.CreatedAt
+			q.userPTYSessionOverrides[i] = override
+			return override, nil
+		}
+	}
+	override := database.UserPTYSessionOverride{
+		UserID:         arg.UserID,
+		MaxPTYSessions: arg.MaxPTYSessions,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.CreatedAt,
+	}
+	q.userPTYSessionOverrides = append(q.userPTYSessionOverrides, override)
+	return override, nil
+}
+
+func (q *FakeQuerier) UpsertUserQuotaOverride(_ context.Context, arg database.UpsertUserQuotaOverrideParams) (database.UserQuotaOverride, error) {
+	if err := validateDatabaseType(arg); err != nil {
+		return database.UserQuotaOverride{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, override := range q.userQuotaOverrides {
+		if override.UserID == arg.UserID {
+			override.QuotaAllowance = arg.QuotaAllowance
+			override.UpdatedAt = arg.CreatedAt
+			q.userQuotaOverrides[i] = override
+			return override, nil
+		}
+	}
+	override := database.UserQuotaOverride{
+		UserID:         arg.UserID,
+		QuotaAllowance: arg.QuotaAllowance,
+		CreatedAt:      arg.CreatedAt,
+		UpdatedAt:      arg.CreatedAt,
+	}
+	q.userQuotaOverrides = append(q.userQuotaOverrides, override)
+	return override, nil
+}
+
+func (q *FakeQuerier) UpsertUserRegionLatency(_ context.Context, arg database.UpsertUserRegionLatencyParams) error {
+	if err := validateDatabaseType(arg); err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, latency := range q.userRegionLatencies {
+		if latency.UserID == arg.UserID && latency.RegionID == arg.RegionID {
+			latency.LatencyMS = arg.LatencyMS
+			latency.UpdatedAt = arg.UpdatedAt
+			q.userRegionLatencies[i] = latency
+			return nil
+		}
+	}
+	q.userRegionLatencies = append(q.userRegionLatencies, database.UserRegionLatency{
+		UserID:    arg.UserID,
+		RegionID:  arg.RegionID,
+		LatencyMS: arg.LatencyMS,
+		UpdatedAt: arg.UpdatedAt,
+	})
+	return nil
+}
+
+func (q *FakeQuerier) UpsertWorkspaceExternalMetadatum(_ context.Context, arg database.UpsertWorkspaceExternalMetadatumParams) (database.WorkspaceExternalMetadatum, error) {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return database.WorkspaceExternalMetadatum{}, err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	metadatum := database.WorkspaceExternalMetadatum{
+		WorkspaceID: arg.WorkspaceID,
+		Namespace:   arg.Namespace,
+		Visibility:  arg.Visibility,
+		Data:        arg.Data,
+		UpdatedAt:   arg.UpdatedAt,
+	}
+	for i, existing := range q.workspaceExternalMetadata {
+		if existing.WorkspaceID == arg.WorkspaceID && existing.Namespace == arg.Namespace {
+			q.workspaceExternalMetadata[i] = metadatum
+			return metadatum, nil
+		}
+	}
+	q.workspaceExternalMetadata = append(q.workspaceExternalMetadata, metadatum)
+	return metadatum, nil
+}
+
+func (q *FakeQuerier) UpsertWorkspacePreviousName(_ context.Context, arg database.UpsertWorkspacePreviousNameParams) error {
+	err := validateDatabaseType(arg)
+	if err != nil {
+		return err
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for i, previous := range q.workspacePreviousNames {
+		if previous.WorkspaceID == arg.WorkspaceID && previous.Name == arg.Name {
+			q.workspacePreviousNames[i].RenamedAt = arg.RenamedAt
+			q.workspacePreviousNames[i].RedirectExpiresAt = arg.RedirectExpiresAt
+			return nil
+		}
+	}
+	q.workspacePreviousNames = append(q.workspacePreviousNames, database.WorkspacePreviousName{
+		WorkspaceID:       arg.WorkspaceID,
+		Name:              arg.Name,
+		RenamedAt:         arg.RenamedAt,
+		RedirectExpiresAt: arg.RedirectExpiresAt,
+	})
+	return nil
+}
+
 func (q *FakeQuerier) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	if err := validateDatabaseType(arg); err != nil {
 		return nil, err
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
@@ -86,6 +87,32 @@ func Template(t testing.TB, db database.Store, seed database.Template) database.
 	return template
 }
 
+func TemplateAccessRequest(t testing.TB, db database.Store, orig database.TemplateAccessRequest) database.TemplateAccessRequest {
+	request, err := db.InsertTemplateAccessRequest(genCtx, database.InsertTemplateAccessRequestParams{
+		ID:          takeFirst(orig.ID, uuid.New()),
+		TemplateID:  takeFirst(orig.TemplateID, uuid.New()),
+		RequestedBy: takeFirst(orig.RequestedBy, uuid.New()),
+		CreatedAt:   takeFirst(orig.CreatedAt, database.Now()),
+		Message:     orig.Message,
+	})
+	require.NoError(t, err, "insert template access request")
+	return request
+}
+
+func TemplateVersionPromotion(t testing.TB, db database.Store, orig database.TemplateVersionPromotion) database.TemplateVersionPromotion {
+	promotion, err := db.InsertTemplateVersionPromotion(genCtx, database.InsertTemplateVersionPromotionParams{
+		ID:                takeFirst(orig.ID, uuid.New()),
+		OrganizationID:    takeFirst(orig.OrganizationID, uuid.New()),
+		TemplateID:        takeFirst(orig.TemplateID, uuid.New()),
+		TemplateVersionID: takeFirst(orig.TemplateVersionID, uuid.New()),
+		RequestedBy:       takeFirst(orig.RequestedBy, uuid.New()),
+		CreatedAt:         takeFirst(orig.CreatedAt, database.Now()),
+		Message:           orig.Message,
+	})
+	require.NoError(t, err, "insert template version promotion")
+	return promotion
+}
+
 func APIKey(t testing.TB, db database.Store, seed database.APIKey) (key database.APIKey, token string) {
 	id, _ := cryptorand.String(10)
 	secret, _ := cryptorand.String(22)
@@ -174,6 +201,7 @@ func Workspace(t testing.TB, db database.Store, orig database.Workspace) databas
 		Name:              takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
 		AutostartSchedule: orig.AutostartSchedule,
 		Ttl:               orig.Ttl,
+		AutomaticUpdates:  takeFirst(orig.AutomaticUpdates, database.AutomaticUpdatesNever),
 	})
 	require.NoError(t, err, "insert workspace")
 	return workspace
@@ -184,18 +212,19 @@ func WorkspaceBuild(t testing.TB, db database.Store, orig database.WorkspaceBuil
 	var build database.WorkspaceBuild
 	err := db.InTx(func(db database.Store) error {
 		err := db.InsertWorkspaceBuild(genCtx, database.InsertWorkspaceBuildParams{
-			ID:                buildID,
-			CreatedAt:         takeFirst(orig.CreatedAt, database.Now()),
-			UpdatedAt:         takeFirst(orig.UpdatedAt, database.Now()),
-			WorkspaceID:       takeFirst(orig.WorkspaceID, uuid.New()),
-			TemplateVersionID: takeFirst(orig.TemplateVersionID, uuid.New()),
-			BuildNumber:       takeFirst(orig.BuildNumber, 1),
-			Transition:        takeFirst(orig.Transition, database.WorkspaceTransitionStart),
-			InitiatorID:       takeFirst(orig.InitiatorID, uuid.New()),
-			JobID:             takeFirst(orig.JobID, uuid.New()),
-			ProvisionerState:  takeFirstSlice(orig.ProvisionerState, []byte{}),
-			Deadline:          takeFirst(orig.Deadline, database.Now().Add(time.Hour)),
-			Reason:            takeFirst(orig.Reason, database.BuildReasonInitiator),
+			ID:                 buildID,
+			CreatedAt:          takeFirst(orig.CreatedAt, database.Now()),
+			UpdatedAt:          takeFirst(orig.UpdatedAt, database.Now()),
+			WorkspaceID:        takeFirst(orig.WorkspaceID, uuid.New()),
+			TemplateVersionID:  takeFirst(orig.TemplateVersionID, uuid.New()),
+			BuildNumber:        takeFirst(orig.BuildNumber, 1),
+			Transition:         takeFirst(orig.Transition, database.WorkspaceTransitionStart),
+			InitiatorID:        takeFirst(orig.InitiatorID, uuid.New()),
+			JobID:              takeFirst(orig.JobID, uuid.New()),
+			ProvisionerState:   takeFirstSlice(orig.ProvisionerState, []byte{}),
+			Deadline:           takeFirst(orig.Deadline, database.Now().Add(time.Hour)),
+			Reason:             takeFirst(orig.Reason, database.BuildReasonInitiator),
+			InitiatorTokenName: orig.InitiatorTokenName,
 		})
 		if err != nil {
 			return err
@@ -287,6 +316,7 @@ func Group(t testing.TB, db database.Store, orig database.Group) database.Group
 		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
 		AvatarURL:      takeFirst(orig.AvatarURL, "https://logo.example.com"),
 		QuotaAllowance: takeFirst(orig.QuotaAllowance, 0),
+		Metadata:       takeFirstSlice(orig.Metadata, []byte("{}")),
 	})
 	require.NoError(t, err, "insert group")
 	return group
@@ -454,6 +484,62 @@ func WorkspaceProxy(t testing.TB, db database.Store, orig database.WorkspaceProx
 	return proxy, secret
 }
 
+func WorkspaceWebhook(t testing.TB, db database.Store, orig database.WorkspaceWebhook) database.WorkspaceWebhook {
+	webhook, err := db.InsertWorkspaceWebhook(genCtx, database.InsertWorkspaceWebhookParams{
+		ID:          takeFirst(orig.ID, uuid.New()),
+		WorkspaceID: takeFirst(orig.WorkspaceID, uuid.New()),
+		CreatedBy:   takeFirst(orig.CreatedBy, uuid.New()),
+		CreatedAt:   takeFirst(orig.CreatedAt, database.Now()),
+		Name:        takeFirst(orig.Name, namesgenerator.GetRandomName(1)),
+		Action:      takeFirst(orig.Action, database.WorkspaceWebhookActionStart),
+		AgentName:   orig.AgentName,
+		Command:     orig.Command,
+		Secret:      takeFirst(orig.Secret, must(cryptorand.String(32))),
+	})
+	require.NoError(t, err, "insert workspace webhook")
+	return webhook
+}
+
+func WorkspaceApproval(t testing.TB, db database.Store, orig database.WorkspaceApproval) database.WorkspaceApproval {
+	approval, err := db.InsertWorkspaceApproval(genCtx, database.InsertWorkspaceApprovalParams{
+		ID:          takeFirst(orig.ID, uuid.New()),
+		WorkspaceID: takeFirst(orig.WorkspaceID, uuid.New()),
+		JobID:       takeFirst(orig.JobID, uuid.New()),
+		RequestedBy: takeFirst(orig.RequestedBy, uuid.New()),
+		CreatedAt:   takeFirst(orig.CreatedAt, database.Now()),
+	})
+	require.NoError(t, err, "insert workspace approval")
+	return approval
+}
+
+func WorkspaceBuildQueueEntry(t testing.TB, db database.Store, orig database.WorkspaceBuildQueueEntry) database.WorkspaceBuildQueueEntry {
+	entry, err := db.InsertWorkspaceBuildQueueEntry(genCtx, database.InsertWorkspaceBuildQueueEntryParams{
+		ID:                 takeFirst(orig.ID, uuid.New()),
+		WorkspaceID:        takeFirst(orig.WorkspaceID, uuid.New()),
+		InitiatorID:        takeFirst(orig.InitiatorID, uuid.New()),
+		InitiatorTokenName: orig.InitiatorTokenName,
+		CreatedAt:          takeFirst(orig.CreatedAt, database.Now()),
+		Request:            takeFirstSlice(orig.Request, []byte(`{"transition":"start"}`)),
+	})
+	require.NoError(t, err, "insert workspace build queue entry")
+	return entry
+}
+
+func ManagedEnvironmentVariable(t testing.TB, db database.Store, orig database.ManagedEnvironmentVariable) database.ManagedEnvironmentVariable {
+	variable, err := db.InsertManagedEnvironmentVariable(genCtx, database.InsertManagedEnvironmentVariableParams{
+		ID:             takeFirst(orig.ID, uuid.New()),
+		OrganizationID: takeFirst(orig.OrganizationID, uuid.New()),
+		TemplateID:     orig.TemplateID,
+		CreatedAt:      takeFirst(orig.CreatedAt, database.Now()),
+		UpdatedAt:      takeFirst(orig.UpdatedAt, database.Now()),
+		Name:           takeFirst(orig.Name, "VARIABLE_"+strings.ToUpper(namesgenerator.GetRandomName(1))),
+		Value:          takeFirst(orig.Value, namesgenerator.GetRandomName(1)),
+		Secret:         orig.Secret,
+	})
+	require.NoError(t, err, "insert managed environment variable")
+	return variable
+}
+
 func File(t testing.TB, db database.Store, orig database.File) database.File {
 	file, err := db.InsertFile(genCtx, database.InsertFileParams{
 		ID:        takeFirst(orig.ID, uuid.New()),
@@ -121,6 +121,13 @@ func (m metricsStore) DeleteApplicationConnectAPIKeysByUserID(ctx context.Contex
 	return err
 }
 
+func (m metricsStore) DeleteAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	start := time.Now()
+	err := m.s.DeleteAuditLogsByIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("DeleteAuditLogsByIDs").Observe(time.Since(start).Seconds())
+	return err
+}
+
 func (m metricsStore) DeleteCoordinator(ctx context.Context, id uuid.UUID) error {
 	start := time.Now()
 	defer m.queryLatencies.WithLabelValues("DeleteCoordinator").Observe(time.Since(start).Seconds())
@@ -141,6 +148,13 @@ func (m metricsStore) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+func (m metricsStore) DeleteGroupDefaultTemplates(ctx context.Context, groupID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteGroupDefaultTemplates(ctx, groupID)
+	m.queryLatencies.WithLabelValues("DeleteGroupDefaultTemplates").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteGroupMemberFromGroup(ctx context.Context, arg database.DeleteGroupMemberFromGroupParams) error {
 	start := time.Now()
 	err := m.s.DeleteGroupMemberFromGroup(ctx, arg)
@@ -162,6 +176,48 @@ func (m metricsStore) DeleteLicense(ctx context.Context, id int32) (int32, error
 	return licenseID, err
 }
 
+func (m metricsStore) DeleteManagedEnvironmentVariableByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteManagedEnvironmentVariableByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteManagedEnvironmentVariableByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldNetcheckReports(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldNetcheckReports(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldNetcheckReports").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldPlatformEvents(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldPlatformEvents(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldPlatformEvents").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldWorkspaceAgentAuthTokenRotations(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWorkspaceAgentAuthTokenRotations(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentAuthTokenRotations").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldWorkspaceAgentBandwidthStats(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWorkspaceAgentBandwidthStats(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentBandwidthStats").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteOldWorkspaceAgentClientConnections(ctx context.Context) error {
+	start := time.Now()
+	r0 := m.s.DeleteOldWorkspaceAgentClientConnections(ctx)
+	m.queryLatencies.WithLabelValues("DeleteOldWorkspaceAgentClientConnections").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 	start := time.Now()
 	r0 := m.s.DeleteOldWorkspaceAgentLogs(ctx)
@@ -195,6 +251,69 @@ func (m metricsStore) DeleteTailnetClient(ctx context.Context, arg database.Dele
 	return m.s.DeleteTailnetClient(ctx, arg)
 }
 
+func (m metricsStore) DeleteUserDeprovision(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserDeprovision(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserDeprovision").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteUserPTYSessionOverride(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserPTYSessionOverride(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserPTYSessionOverride").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteUserQuotaOverride(ctx context.Context, userID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteUserQuotaOverride(ctx, userID)
+	m.queryLatencies.WithLabelValues("DeleteUserQuotaOverride").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteWorkspaceBuildQueueEntriesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceBuildQueueEntriesByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceBuildQueueEntriesByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteWorkspaceBuildQueueEntryByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceBuildQueueEntryByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceBuildQueueEntryByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteWorkspaceExternalMetadatum(ctx context.Context, arg database.DeleteWorkspaceExternalMetadatumParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceExternalMetadatum(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceExternalMetadatum").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteWorkspacePreviousName(ctx context.Context, arg database.DeleteWorkspacePreviousNameParams) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspacePreviousName(ctx, arg)
+	m.queryLatencies.WithLabelValues("DeleteWorkspacePreviousName").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) DeleteWorkspaceWebhookByID(ctx context.Context, id uuid.UUID) error {
+	start := time.Now()
+	r0 := m.s.DeleteWorkspaceWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("DeleteWorkspaceWebhookByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) ExpireWorkspaceAgentAuthTokenRotations(ctx context.Context, arg database.ExpireWorkspaceAgentAuthTokenRotationsParams) error {
+	start := time.Now()
+	r0 := m.s.ExpireWorkspaceAgentAuthTokenRotations(ctx, arg)
+	m.queryLatencies.WithLabelValues("ExpireWorkspaceAgentAuthTokenRotations").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) GetAPIKeyByID(ctx context.Context, id string) (database.APIKey, error) {
 	start := time.Now()
 	apiKey, err := m.s.GetAPIKeyByID(ctx, id)
@@ -265,6 +384,20 @@ func (m metricsStore) GetAppSecurityKey(ctx context.Context) (string, error) {
 	return key, err
 }
 
+func (m metricsStore) GetAuditLogArchivalByID(ctx context.Context, id uuid.UUID) (database.AuditLogArchival, error) {
+	start := time.Now()
+	archival, err := m.s.GetAuditLogArchivalByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetAuditLogArchivalByID").Observe(time.Since(start).Seconds())
+	return archival, err
+}
+
+func (m metricsStore) GetAuditLogArchivals(ctx context.Context, rowLimit int32) ([]database.AuditLogArchival, error) {
+	start := time.Now()
+	archivals, err := m.s.GetAuditLogArchivals(ctx, rowLimit)
+	m.queryLatencies.WithLabelValues("GetAuditLogArchivals").Observe(time.Since(start).Seconds())
+	return archivals, err
+}
+
 func (m metricsStore) GetAuditLogsOffset(ctx context.Context, arg database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	start := time.Now()
 	rows, err := m.s.GetAuditLogsOffset(ctx, arg)
@@ -272,6 +405,13 @@ func (m metricsStore) GetAuditLogsOffset(ctx context.Context, arg database.GetAu
 	return rows, err
 }
 
+func (m metricsStore) GetAuditLogsOlderThan(ctx context.Context, arg database.GetAuditLogsOlderThanParams) ([]database.AuditLog, error) {
+	start := time.Now()
+	logs, err := m.s.GetAuditLogsOlderThan(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetAuditLogsOlderThan").Observe(time.Since(start).Seconds())
+	return logs, err
+}
+
 func (m metricsStore) GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (database.GetAuthorizationUserRolesRow, error) {
 	start := time.Now()
 	row, err := m.s.GetAuthorizationUserRoles(ctx, userID)
@@ -321,6 +461,13 @@ func (m metricsStore) GetDeploymentWorkspaceStats(ctx context.Context) (database
 	return row, err
 }
 
+func (m metricsStore) GetEntitlementSnapshots(ctx context.Context, arg database.GetEntitlementSnapshotsParams) ([]database.EntitlementSnapshot, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetEntitlementSnapshots(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetEntitlementSnapshots").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetFileByHashAndCreator(ctx context.Context, arg database.GetFileByHashAndCreatorParams) (database.File, error) {
 	start := time.Now()
 	file, err := m.s.GetFileByHashAndCreator(ctx, arg)
@@ -370,6 +517,13 @@ func (m metricsStore) GetGroupByOrgAndName(ctx context.Context, arg database.Get
 	return group, err
 }
 
+func (m metricsStore) GetGroupDefaultTemplates(ctx context.Context, groupID uuid.UUID) ([]database.GroupDefaultTemplate, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetGroupDefaultTemplates(ctx, groupID)
+	m.queryLatencies.WithLabelValues("GetGroupDefaultTemplates").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]database.User, error) {
 	start := time.Now()
 	users, err := m.s.GetGroupMembers(ctx, groupID)
@@ -377,6 +531,13 @@ func (m metricsStore) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([
 	return users, err
 }
 
+func (m metricsStore) GetGroupMembersAnyStatus(ctx context.Context, groupID uuid.UUID) ([]database.User, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetGroupMembersAnyStatus(ctx, groupID)
+	m.queryLatencies.WithLabelValues("GetGroupMembersAnyStatus").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.Group, error) {
 	start := time.Now()
 	groups, err := m.s.GetGroupsByOrganizationID(ctx, organizationID)
@@ -398,6 +559,13 @@ func (m metricsStore) GetLastUpdateCheck(ctx context.Context) (string, error) {
 	return version, err
 }
 
+func (m metricsStore) GetLatestWorkspaceApprovalByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceApproval, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLatestWorkspaceApprovalByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetLatestWorkspaceApprovalByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (database.WorkspaceBuild, error) {
 	start := time.Now()
 	build, err := m.s.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspaceID)
@@ -426,6 +594,13 @@ func (m metricsStore) GetLicenseByID(ctx context.Context, id int32) (database.Li
 	return license, err
 }
 
+func (m metricsStore) GetLicenseUsage(ctx context.Context, arg database.GetLicenseUsageParams) ([]database.LicenseUsage, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetLicenseUsage(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetLicenseUsage").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetLicenses(ctx context.Context) ([]database.License, error) {
 	start := time.Now()
 	licenses, err := m.s.GetLicenses(ctx)
@@ -440,6 +615,34 @@ func (m metricsStore) GetLogoURL(ctx context.Context) (string, error) {
 	return url, err
 }
 
+func (m metricsStore) GetManagedEnvironmentVariableByID(ctx context.Context, id uuid.UUID) (database.ManagedEnvironmentVariable, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetManagedEnvironmentVariableByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetManagedEnvironmentVariableByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetManagedEnvironmentVariablesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]database.ManagedEnvironmentVariable, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetManagedEnvironmentVariablesByOrganizationID(ctx, organizationID)
+	m.queryLatencies.WithLabelValues("GetManagedEnvironmentVariablesByOrganizationID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetManagedEnvironmentVariablesByTemplateID(ctx context.Context, templateID uuid.NullUUID) ([]database.ManagedEnvironmentVariable, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetManagedEnvironmentVariablesByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetManagedEnvironmentVariablesByTemplateID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetNetcheckReports(ctx context.Context, arg database.GetNetcheckReportsParams) ([]database.NetcheckReport, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetNetcheckReports(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetNetcheckReports").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetOAuthSigningKey(ctx)
@@ -496,6 +699,13 @@ func (m metricsStore) GetOrganizationsByUserID(ctx context.Context, userID uuid.
 	return organizations, err
 }
 
+func (m metricsStore) GetOutdatedWorkspacesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]database.Workspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetOutdatedWorkspacesByTemplateID(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetOutdatedWorkspacesByTemplateID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ParameterSchema, error) {
 	start := time.Now()
 	schemas, err := m.s.GetParameterSchemasByJobID(ctx, jobID)
@@ -503,6 +713,27 @@ func (m metricsStore) GetParameterSchemasByJobID(ctx context.Context, jobID uuid
 	return schemas, err
 }
 
+func (m metricsStore) GetPendingGroupDefaultTemplateWorkspaces(ctx context.Context) ([]database.GetPendingGroupDefaultTemplateWorkspacesRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPendingGroupDefaultTemplateWorkspaces(ctx)
+	m.queryLatencies.WithLabelValues("GetPendingGroupDefaultTemplateWorkspaces").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetPendingProvisionerJobs(ctx context.Context) ([]database.ProvisionerJob, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPendingProvisionerJobs(ctx)
+	m.queryLatencies.WithLabelValues("GetPendingProvisionerJobs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetPlatformEventsAfterID(ctx context.Context, arg database.GetPlatformEventsAfterIDParams) ([]database.PlatformEvent, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetPlatformEventsAfterID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetPlatformEventsAfterID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetPreviousTemplateVersion(ctx context.Context, arg database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	start := time.Now()
 	version, err := m.s.GetPreviousTemplateVersion(ctx, arg)
@@ -517,6 +748,13 @@ func (m metricsStore) GetProvisionerDaemons(ctx context.Context) ([]database.Pro
 	return daemons, err
 }
 
+func (m metricsStore) GetProvisionerJobBuildHooksByJobID(ctx context.Context, jobID uuid.UUID) ([]database.ProvisionerJobBuildHook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobBuildHooksByJobID(ctx, jobID)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobBuildHooksByJobID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (database.ProvisionerJob, error) {
 	start := time.Now()
 	job, err := m.s.GetProvisionerJobByID(ctx, id)
@@ -524,6 +762,13 @@ func (m metricsStore) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (
 	return job, err
 }
 
+func (m metricsStore) GetProvisionerJobConcurrencyLimit(ctx context.Context, id uuid.UUID) (string, error) {
+	start := time.Now()
+	limitedBy, err := m.s.GetProvisionerJobConcurrencyLimit(ctx, id)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobConcurrencyLimit").Observe(time.Since(start).Seconds())
+	return limitedBy, err
+}
+
 func (m metricsStore) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]database.ProvisionerJob, error) {
 	start := time.Now()
 	jobs, err := m.s.GetProvisionerJobsByIDs(ctx, ids)
@@ -545,6 +790,13 @@ func (m metricsStore) GetProvisionerJobsCreatedAfter(ctx context.Context, create
 	return jobs, err
 }
 
+func (m metricsStore) GetProvisionerJobsStartedAfter(ctx context.Context, startedAfter time.Time) ([]database.ProvisionerJob, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetProvisionerJobsStartedAfter(ctx, startedAfter)
+	m.queryLatencies.WithLabelValues("GetProvisionerJobsStartedAfter").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetProvisionerLogsAfterID(ctx context.Context, arg database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	start := time.Now()
 	logs, err := m.s.GetProvisionerLogsAfterID(ctx, arg)
@@ -552,11 +804,11 @@ func (m metricsStore) GetProvisionerLogsAfterID(ctx context.Context, arg databas
 	return logs, err
 }
 
-func (m metricsStore) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+func (m metricsStore) GetQuotaBudgetsForUser(ctx context.Context, userID uuid.UUID) ([]database.Group, error) {
 	start := time.Now()
-	allowance, err := m.s.GetQuotaAllowanceForUser(ctx, userID)
-	m.queryLatencies.WithLabelValues("GetQuotaAllowanceForUser").Observe(time.Since(start).Seconds())
-	return allowance, err
+	r0, r1 := m.s.GetQuotaBudgetsForUser(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetQuotaBudgetsForUser").Observe(time.Since(start).Seconds())
+	return r0, r1
 }
 
 func (m metricsStore) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error) {
@@ -566,6 +818,13 @@ func (m metricsStore) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.
 	return consumed, err
 }
 
+func (m metricsStore) GetQuotaConsumedWorkspacesForUser(ctx context.Context, ownerID uuid.UUID) ([]database.GetQuotaConsumedWorkspacesForUserRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetQuotaConsumedWorkspacesForUser(ctx, ownerID)
+	m.queryLatencies.WithLabelValues("GetQuotaConsumedWorkspacesForUser").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetReplicaByID(ctx context.Context, id uuid.UUID) (database.Replica, error) {
 	start := time.Now()
 	replica, err := m.s.GetReplicaByID(ctx, id)
@@ -580,6 +839,20 @@ func (m metricsStore) GetReplicasUpdatedAfter(ctx context.Context, updatedAt tim
 	return replicas, err
 }
 
+func (m metricsStore) GetRuntimeExperiments(ctx context.Context) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetRuntimeExperiments(ctx)
+	m.queryLatencies.WithLabelValues("GetRuntimeExperiments").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetSSHCAKey(ctx context.Context) (string, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetSSHCAKey(ctx)
+	m.queryLatencies.WithLabelValues("GetSSHCAKey").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetServiceBanner(ctx context.Context) (string, error) {
 	start := time.Now()
 	banner, err := m.s.GetServiceBanner(ctx)
@@ -599,6 +872,13 @@ func (m metricsStore) GetTailnetClientsForAgent(ctx context.Context, agentID uui
 	return m.s.GetTailnetClientsForAgent(ctx, agentID)
 }
 
+func (m metricsStore) GetTemplateAgentFirstConnectInsights(ctx context.Context, arg database.GetTemplateAgentFirstConnectInsightsParams) ([]database.GetTemplateAgentFirstConnectInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateAgentFirstConnectInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateAgentFirstConnectInsights").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetTemplateAppInsights(ctx context.Context, arg database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetTemplateAppInsights(ctx, arg)
@@ -606,6 +886,20 @@ func (m metricsStore) GetTemplateAppInsights(ctx context.Context, arg database.G
 	return r0, r1
 }
 
+func (m metricsStore) GetTemplateAccessRequestByID(ctx context.Context, id uuid.UUID) (database.TemplateAccessRequest, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateAccessRequestByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetTemplateAccessRequestByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetTemplateAccessRequestsByTemplateID(ctx context.Context, arg database.GetTemplateAccessRequestsByTemplateIDParams) ([]database.TemplateAccessRequest, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateAccessRequestsByTemplateID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateAccessRequestsByTemplateID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetTemplateAverageBuildTime(ctx context.Context, arg database.GetTemplateAverageBuildTimeParams) (database.GetTemplateAverageBuildTimeRow, error) {
 	start := time.Now()
 	buildTime, err := m.s.GetTemplateAverageBuildTime(ctx, arg)
@@ -613,6 +907,13 @@ func (m metricsStore) GetTemplateAverageBuildTime(ctx context.Context, arg datab
 	return buildTime, err
 }
 
+func (m metricsStore) GetTemplateBuildSLOInsights(ctx context.Context, arg database.GetTemplateBuildSLOInsightsParams) ([]database.GetTemplateBuildSLOInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateBuildSLOInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateBuildSLOInsights").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetTemplateByID(ctx context.Context, id uuid.UUID) (database.Template, error) {
 	start := time.Now()
 	template, err := m.s.GetTemplateByID(ctx, id)
@@ -655,6 +956,13 @@ func (m metricsStore) GetTemplateParameterInsights(ctx context.Context, arg data
 	return r0, r1
 }
 
+func (m metricsStore) GetTemplateVersionBuildInsights(ctx context.Context, templateID uuid.UUID) ([]database.GetTemplateVersionBuildInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionBuildInsights(ctx, templateID)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionBuildInsights").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (database.TemplateVersion, error) {
 	start := time.Now()
 	version, err := m.s.GetTemplateVersionByID(ctx, id)
@@ -683,6 +991,20 @@ func (m metricsStore) GetTemplateVersionParameters(ctx context.Context, template
 	return parameters, err
 }
 
+func (m metricsStore) GetTemplateVersionPromotionByID(ctx context.Context, id uuid.UUID) (database.TemplateVersionPromotion, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionPromotionByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionPromotionByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetTemplateVersionPromotions(ctx context.Context, arg database.GetTemplateVersionPromotionsParams) ([]database.TemplateVersionPromotion, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetTemplateVersionPromotions(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetTemplateVersionPromotions").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	start := time.Now()
 	variables, err := m.s.GetTemplateVersionVariables(ctx, templateVersionID)
@@ -732,6 +1054,13 @@ func (m metricsStore) GetUnexpiredLicenses(ctx context.Context) ([]database.Lice
 	return licenses, err
 }
 
+func (m metricsStore) GetUserBandwidthInsights(ctx context.Context, arg database.GetUserBandwidthInsightsParams) ([]database.GetUserBandwidthInsightsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserBandwidthInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUserBandwidthInsights").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetUserByEmailOrUsername(ctx context.Context, arg database.GetUserByEmailOrUsernameParams) (database.User, error) {
 	start := time.Now()
 	user, err := m.s.GetUserByEmailOrUsername(ctx, arg)
@@ -753,6 +1082,13 @@ func (m metricsStore) GetUserCount(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+func (m metricsStore) GetUserDeprovisions(ctx context.Context) ([]database.UserDeprovision, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserDeprovisions(ctx)
+	m.queryLatencies.WithLabelValues("GetUserDeprovisions").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetUserLatencyInsights(ctx context.Context, arg database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetUserLatencyInsights(ctx, arg)
@@ -774,6 +1110,27 @@ func (m metricsStore) GetUserLinkByUserIDLoginType(ctx context.Context, arg data
 	return link, err
 }
 
+func (m metricsStore) GetUserPTYSessionOverride(ctx context.Context, userID uuid.UUID) (database.UserPTYSessionOverride, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserPTYSessionOverride(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserPTYSessionOverride").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetUserQuotaOverride(ctx context.Context, userID uuid.UUID) (database.UserQuotaOverride, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserQuotaOverride(ctx, userID)
+	m.queryLatencies.WithLabelValues("GetUserQuotaOverride").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetUserRegionLatencies(ctx context.Context, arg database.GetUserRegionLatenciesParams) ([]database.UserRegionLatency, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetUserRegionLatencies(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetUserRegionLatencies").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetUsers(ctx context.Context, arg database.GetUsersParams) ([]database.GetUsersRow, error) {
 	start := time.Now()
 	users, err := m.s.GetUsers(ctx, arg)
@@ -795,6 +1152,20 @@ func (m metricsStore) GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context,
 	return r0, r1
 }
 
+func (m metricsStore) GetWorkspaceAgentBandwidthStatsAndLabels(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentBandwidthStatsAndLabelsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentBandwidthStatsAndLabels(ctx, createdAt)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentBandwidthStatsAndLabels").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentBandwidthStatsByPeer(ctx context.Context, arg database.GetWorkspaceAgentBandwidthStatsByPeerParams) ([]database.GetWorkspaceAgentBandwidthStatsByPeerRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentBandwidthStatsByPeer(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentBandwidthStatsByPeer").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (database.WorkspaceAgent, error) {
 	start := time.Now()
 	agent, err := m.s.GetWorkspaceAgentByID(ctx, id)
@@ -809,6 +1180,20 @@ func (m metricsStore) GetWorkspaceAgentByInstanceID(ctx context.Context, authIns
 	return agent, err
 }
 
+func (m metricsStore) GetWorkspaceAgentClientConnections(ctx context.Context, arg database.GetWorkspaceAgentClientConnectionsParams) ([]database.WorkspaceAgentClientConnection, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentClientConnections(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentClientConnections").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentClientConnectionsByUserID(ctx context.Context, arg database.GetWorkspaceAgentClientConnectionsByUserIDParams) ([]database.GetWorkspaceAgentClientConnectionsByUserIDRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentClientConnectionsByUserID(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentClientConnectionsByUserID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
 	start := time.Now()
 	r0, r1 := m.s.GetWorkspaceAgentLifecycleStateByID(ctx, id)
@@ -830,6 +1215,20 @@ func (m metricsStore) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAg
 	return metadata, err
 }
 
+func (m metricsStore) GetWorkspaceAgentMetadataHistory(ctx context.Context, arg database.GetWorkspaceAgentMetadataHistoryParams) ([]database.WorkspaceAgentMetadataHistory, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentMetadataHistory(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentMetadataHistory").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceAgentSSHHostKey(ctx context.Context, arg database.GetWorkspaceAgentSSHHostKeyParams) (database.WorkspaceAgentSSHHostKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentSSHHostKey(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentSSHHostKey").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	start := time.Now()
 	stats, err := m.s.GetWorkspaceAgentStats(ctx, createdAt)
@@ -865,6 +1264,13 @@ func (m metricsStore) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.C
 	return agents, err
 }
 
+func (m metricsStore) GetWorkspaceAgentsNeverConnected(ctx context.Context, arg database.GetWorkspaceAgentsNeverConnectedParams) ([]database.GetWorkspaceAgentsNeverConnectedRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceAgentsNeverConnected(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceAgentsNeverConnected").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg database.GetWorkspaceAppByAgentIDAndSlugParams) (database.WorkspaceApp, error) {
 	start := time.Now()
 	app, err := m.s.GetWorkspaceAppByAgentIDAndSlug(ctx, arg)
@@ -872,6 +1278,20 @@ func (m metricsStore) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg d
 	return app, err
 }
 
+func (m metricsStore) GetWorkspaceApprovalByID(ctx context.Context, id uuid.UUID) (database.WorkspaceApproval, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceApprovalByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceApprovalByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceApprovalByJobID(ctx context.Context, jobID uuid.UUID) (database.WorkspaceApproval, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceApprovalByJobID(ctx, jobID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceApprovalByJobID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]database.WorkspaceApp, error) {
 	start := time.Now()
 	apps, err := m.s.GetWorkspaceAppsByAgentID(ctx, agentID)
@@ -921,6 +1341,20 @@ func (m metricsStore) GetWorkspaceBuildParameters(ctx context.Context, workspace
 	return params, err
 }
 
+func (m metricsStore) GetWorkspaceBuildQueueEntriesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceBuildQueueEntry, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildQueueEntriesByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceBuildQueueEntriesByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceBuildQueueWorkspaceIDs(ctx context.Context) ([]uuid.UUID, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceBuildQueueWorkspaceIDs(ctx)
+	m.queryLatencies.WithLabelValues("GetWorkspaceBuildQueueWorkspaceIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg database.GetWorkspaceBuildsByWorkspaceIDParams) ([]database.WorkspaceBuild, error) {
 	start := time.Now()
 	builds, err := m.s.GetWorkspaceBuildsByWorkspaceID(ctx, arg)
@@ -956,6 +1390,13 @@ func (m metricsStore) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg data
 	return workspace, err
 }
 
+func (m metricsStore) GetWorkspaceByOwnerIDAndPreviousName(ctx context.Context, arg database.GetWorkspaceByOwnerIDAndPreviousNameParams) (database.Workspace, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceByOwnerIDAndPreviousName(ctx, arg)
+	m.queryLatencies.WithLabelValues("GetWorkspaceByOwnerIDAndPreviousName").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (database.Workspace, error) {
 	start := time.Now()
 	workspace, err := m.s.GetWorkspaceByWorkspaceAppID(ctx, workspaceAppID)
@@ -963,6 +1404,20 @@ func (m metricsStore) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspac
 	return workspace, err
 }
 
+func (m metricsStore) GetWorkspaceExternalMetadataByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetWorkspaceExternalMetadataByWorkspaceIDsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceExternalMetadataByWorkspaceIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspaceExternalMetadataByWorkspaceIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspacePreviousNamesByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]database.GetWorkspacePreviousNamesByWorkspaceIDsRow, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspacePreviousNamesByWorkspaceIDs(ctx, ids)
+	m.queryLatencies.WithLabelValues("GetWorkspacePreviousNamesByWorkspaceIDs").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaceProxies(ctx context.Context) ([]database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxies, err := m.s.GetWorkspaceProxies(ctx)
@@ -1033,6 +1488,20 @@ func (m metricsStore) GetWorkspaceResourcesCreatedAfter(ctx context.Context, cre
 	return resources, err
 }
 
+func (m metricsStore) GetWorkspaceWebhookByID(ctx context.Context, id uuid.UUID) (database.WorkspaceWebhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceWebhookByID(ctx, id)
+	m.queryLatencies.WithLabelValues("GetWorkspaceWebhookByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) GetWorkspaceWebhooksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]database.WorkspaceWebhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.GetWorkspaceWebhooksByWorkspaceID(ctx, workspaceID)
+	m.queryLatencies.WithLabelValues("GetWorkspaceWebhooksByWorkspaceID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) GetWorkspaces(ctx context.Context, arg database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
 	start := time.Now()
 	workspaces, err := m.s.GetWorkspaces(ctx, arg)
@@ -1068,6 +1537,13 @@ func (m metricsStore) InsertAuditLog(ctx context.Context, arg database.InsertAud
 	return log, err
 }
 
+func (m metricsStore) InsertAuditLogArchival(ctx context.Context, arg database.InsertAuditLogArchivalParams) (database.AuditLogArchival, error) {
+	start := time.Now()
+	archival, err := m.s.InsertAuditLogArchival(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertAuditLogArchival").Observe(time.Since(start).Seconds())
+	return archival, err
+}
+
 func (m metricsStore) InsertDERPMeshKey(ctx context.Context, value string) error {
 	start := time.Now()
 	err := m.s.InsertDERPMeshKey(ctx, value)
@@ -1082,6 +1558,13 @@ func (m metricsStore) InsertDeploymentID(ctx context.Context, value string) erro
 	return err
 }
 
+func (m metricsStore) InsertEntitlementSnapshot(ctx context.Context, arg database.InsertEntitlementSnapshotParams) error {
+	start := time.Now()
+	r0 := m.s.InsertEntitlementSnapshot(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertEntitlementSnapshot").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) InsertFile(ctx context.Context, arg database.InsertFileParams) (database.File, error) {
 	start := time.Now()
 	file, err := m.s.InsertFile(ctx, arg)
@@ -1110,6 +1593,20 @@ func (m metricsStore) InsertGroup(ctx context.Context, arg database.InsertGroupP
 	return group, err
 }
 
+func (m metricsStore) InsertGroupDefaultTemplate(ctx context.Context, arg database.InsertGroupDefaultTemplateParams) (database.GroupDefaultTemplate, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertGroupDefaultTemplate(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertGroupDefaultTemplate").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertGroupDefaultTemplateWorkspace(ctx context.Context, arg database.InsertGroupDefaultTemplateWorkspaceParams) error {
+	start := time.Now()
+	r0 := m.s.InsertGroupDefaultTemplateWorkspace(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertGroupDefaultTemplateWorkspace").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) InsertGroupMember(ctx context.Context, arg database.InsertGroupMemberParams) error {
 	start := time.Now()
 	err := m.s.InsertGroupMember(ctx, arg)
@@ -1124,6 +1621,13 @@ func (m metricsStore) InsertLicense(ctx context.Context, arg database.InsertLice
 	return license, err
 }
 
+func (m metricsStore) InsertManagedEnvironmentVariable(ctx context.Context, arg database.InsertManagedEnvironmentVariableParams) (database.ManagedEnvironmentVariable, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertManagedEnvironmentVariable(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertManagedEnvironmentVariable").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertMissingGroups(ctx context.Context, arg database.InsertMissingGroupsParams) ([]database.Group, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertMissingGroups(ctx, arg)
@@ -1131,6 +1635,13 @@ func (m metricsStore) InsertMissingGroups(ctx context.Context, arg database.Inse
 	return r0, r1
 }
 
+func (m metricsStore) InsertNetcheckReport(ctx context.Context, arg database.InsertNetcheckReportParams) (database.NetcheckReport, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertNetcheckReport(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertNetcheckReport").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertOrganization(ctx context.Context, arg database.InsertOrganizationParams) (database.Organization, error) {
 	start := time.Now()
 	organization, err := m.s.InsertOrganization(ctx, arg)
@@ -1145,6 +1656,13 @@ func (m metricsStore) InsertOrganizationMember(ctx context.Context, arg database
 	return member, err
 }
 
+func (m metricsStore) InsertPlatformEvent(ctx context.Context, arg database.InsertPlatformEventParams) (database.PlatformEvent, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertPlatformEvent(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertPlatformEvent").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertProvisionerDaemon(ctx context.Context, arg database.InsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	start := time.Now()
 	daemon, err := m.s.InsertProvisionerDaemon(ctx, arg)
@@ -1159,6 +1677,13 @@ func (m metricsStore) InsertProvisionerJob(ctx context.Context, arg database.Ins
 	return job, err
 }
 
+func (m metricsStore) InsertProvisionerJobBuildHook(ctx context.Context, arg database.InsertProvisionerJobBuildHookParams) (database.ProvisionerJobBuildHook, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertProvisionerJobBuildHook(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertProvisionerJobBuildHook").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertProvisionerJobLogs(ctx context.Context, arg database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	start := time.Now()
 	logs, err := m.s.InsertProvisionerJobLogs(ctx, arg)
@@ -1180,6 +1705,13 @@ func (m metricsStore) InsertTemplate(ctx context.Context, arg database.InsertTem
 	return err
 }
 
+func (m metricsStore) InsertTemplateAccessRequest(ctx context.Context, arg database.InsertTemplateAccessRequestParams) (database.TemplateAccessRequest, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateAccessRequest(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateAccessRequest").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertTemplateVersion(ctx context.Context, arg database.InsertTemplateVersionParams) error {
 	start := time.Now()
 	err := m.s.InsertTemplateVersion(ctx, arg)
@@ -1194,6 +1726,13 @@ func (m metricsStore) InsertTemplateVersionParameter(ctx context.Context, arg da
 	return parameter, err
 }
 
+func (m metricsStore) InsertTemplateVersionPromotion(ctx context.Context, arg database.InsertTemplateVersionPromotionParams) (database.TemplateVersionPromotion, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertTemplateVersionPromotion(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertTemplateVersionPromotion").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertTemplateVersionVariable(ctx context.Context, arg database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	start := time.Now()
 	variable, err := m.s.InsertTemplateVersionVariable(ctx, arg)
@@ -1208,6 +1747,13 @@ func (m metricsStore) InsertUser(ctx context.Context, arg database.InsertUserPar
 	return user, err
 }
 
+func (m metricsStore) InsertUserDeprovision(ctx context.Context, arg database.InsertUserDeprovisionParams) error {
+	start := time.Now()
+	r0 := m.s.InsertUserDeprovision(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertUserDeprovision").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) InsertUserGroupsByName(ctx context.Context, arg database.InsertUserGroupsByNameParams) error {
 	start := time.Now()
 	err := m.s.InsertUserGroupsByName(ctx, arg)
@@ -1236,6 +1782,27 @@ func (m metricsStore) InsertWorkspaceAgent(ctx context.Context, arg database.Ins
 	return agent, err
 }
 
+func (m metricsStore) InsertWorkspaceAgentAuthTokenRotation(ctx context.Context, arg database.InsertWorkspaceAgentAuthTokenRotationParams) (database.WorkspaceAgentAuthTokenRotation, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentAuthTokenRotation(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentAuthTokenRotation").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) InsertWorkspaceAgentBandwidthStats(ctx context.Context, arg database.InsertWorkspaceAgentBandwidthStatsParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceAgentBandwidthStats(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentBandwidthStats").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) InsertWorkspaceAgentClientConnection(ctx context.Context, arg database.InsertWorkspaceAgentClientConnectionParams) (database.WorkspaceAgentClientConnection, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentClientConnection(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentClientConnection").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspaceAgentLogs(ctx context.Context, arg database.InsertWorkspaceAgentLogsParams) ([]database.WorkspaceAgentLog, error) {
 	start := time.Now()
 	r0, r1 := m.s.InsertWorkspaceAgentLogs(ctx, arg)
@@ -1250,6 +1817,20 @@ func (m metricsStore) InsertWorkspaceAgentMetadata(ctx context.Context, arg data
 	return err
 }
 
+func (m metricsStore) InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg database.InsertWorkspaceAgentMetadataHistoryParams) error {
+	start := time.Now()
+	r0 := m.s.InsertWorkspaceAgentMetadataHistory(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentMetadataHistory").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) InsertWorkspaceAgentSSHHostKey(ctx context.Context, arg database.InsertWorkspaceAgentSSHHostKeyParams) (database.WorkspaceAgentSSHHostKey, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceAgentSSHHostKey(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceAgentSSHHostKey").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspaceAgentStat(ctx context.Context, arg database.InsertWorkspaceAgentStatParams) (database.WorkspaceAgentStat, error) {
 	start := time.Now()
 	stat, err := m.s.InsertWorkspaceAgentStat(ctx, arg)
@@ -1278,6 +1859,13 @@ func (m metricsStore) InsertWorkspaceAppStats(ctx context.Context, arg database.
 	return r0
 }
 
+func (m metricsStore) InsertWorkspaceApproval(ctx context.Context, arg database.InsertWorkspaceApprovalParams) (database.WorkspaceApproval, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceApproval(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceApproval").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspaceBuild(ctx context.Context, arg database.InsertWorkspaceBuildParams) error {
 	start := time.Now()
 	err := m.s.InsertWorkspaceBuild(ctx, arg)
@@ -1292,6 +1880,13 @@ func (m metricsStore) InsertWorkspaceBuildParameters(ctx context.Context, arg da
 	return err
 }
 
+func (m metricsStore) InsertWorkspaceBuildQueueEntry(ctx context.Context, arg database.InsertWorkspaceBuildQueueEntryParams) (database.WorkspaceBuildQueueEntry, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceBuildQueueEntry(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceBuildQueueEntry").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) InsertWorkspaceProxy(ctx context.Context, arg database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.InsertWorkspaceProxy(ctx, arg)
@@ -1313,6 +1908,13 @@ func (m metricsStore) InsertWorkspaceResourceMetadata(ctx context.Context, arg d
 	return metadata, err
 }
 
+func (m metricsStore) InsertWorkspaceWebhook(ctx context.Context, arg database.InsertWorkspaceWebhookParams) (database.WorkspaceWebhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.InsertWorkspaceWebhook(ctx, arg)
+	m.queryLatencies.WithLabelValues("InsertWorkspaceWebhook").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) RegisterWorkspaceProxy(ctx context.Context, arg database.RegisterWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	start := time.Now()
 	proxy, err := m.s.RegisterWorkspaceProxy(ctx, arg)
@@ -1334,6 +1936,13 @@ func (m metricsStore) UpdateAPIKeyByID(ctx context.Context, arg database.UpdateA
 	return err
 }
 
+func (m metricsStore) UpdateAuditLogArchivalByID(ctx context.Context, arg database.UpdateAuditLogArchivalByIDParams) (database.AuditLogArchival, error) {
+	start := time.Now()
+	archival, err := m.s.UpdateAuditLogArchivalByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateAuditLogArchivalByID").Observe(time.Since(start).Seconds())
+	return archival, err
+}
+
 func (m metricsStore) UpdateGitAuthLink(ctx context.Context, arg database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	start := time.Now()
 	link, err := m.s.UpdateGitAuthLink(ctx, arg)
@@ -1348,6 +1957,13 @@ func (m metricsStore) UpdateGitSSHKey(ctx context.Context, arg database.UpdateGi
 	return key, err
 }
 
+func (m metricsStore) UpdateGroupAvatarByID(ctx context.Context, arg database.UpdateGroupAvatarByIDParams) (database.Group, error) {
+	start := time.Now()
+	group, err := m.s.UpdateGroupAvatarByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateGroupAvatarByID").Observe(time.Since(start).Seconds())
+	return group, err
+}
+
 func (m metricsStore) UpdateGroupByID(ctx context.Context, arg database.UpdateGroupByIDParams) (database.Group, error) {
 	start := time.Now()
 	group, err := m.s.UpdateGroupByID(ctx, arg)
@@ -1362,6 +1978,13 @@ func (m metricsStore) UpdateInactiveUsersToDormant(ctx context.Context, lastSeen
 	return r0, r1
 }
 
+func (m metricsStore) UpdateManagedEnvironmentVariableByID(ctx context.Context, arg database.UpdateManagedEnvironmentVariableByIDParams) (database.ManagedEnvironmentVariable, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateManagedEnvironmentVariableByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateManagedEnvironmentVariableByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateMemberRoles(ctx context.Context, arg database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	start := time.Now()
 	member, err := m.s.UpdateMemberRoles(ctx, arg)
@@ -1369,6 +1992,13 @@ func (m metricsStore) UpdateMemberRoles(ctx context.Context, arg database.Update
 	return member, err
 }
 
+func (m metricsStore) UpdateOrganizationProvisionerLimits(ctx context.Context, arg database.UpdateOrganizationProvisionerLimitsParams) (database.Organization, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateOrganizationProvisionerLimits(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateOrganizationProvisionerLimits").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateProvisionerJobByID(ctx context.Context, arg database.UpdateProvisionerJobByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateProvisionerJobByID(ctx, arg)
@@ -1390,6 +2020,13 @@ func (m metricsStore) UpdateProvisionerJobWithCompleteByID(ctx context.Context,
 	return err
 }
 
+func (m metricsStore) UpdateProvisionerJobWithRequeueByID(ctx context.Context, arg database.UpdateProvisionerJobWithRequeueByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateProvisionerJobWithRequeueByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateProvisionerJobWithRequeueByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateReplica(ctx context.Context, arg database.UpdateReplicaParams) (database.Replica, error) {
 	start := time.Now()
 	replica, err := m.s.UpdateReplica(ctx, arg)
@@ -1397,6 +2034,13 @@ func (m metricsStore) UpdateReplica(ctx context.Context, arg database.UpdateRepl
 	return replica, err
 }
 
+func (m metricsStore) UpdateReplicaDrainingAt(ctx context.Context, arg database.UpdateReplicaDrainingAtParams) (database.Replica, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateReplicaDrainingAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateReplicaDrainingAt").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateTemplateACLByID(ctx context.Context, arg database.UpdateTemplateACLByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateACLByID(ctx, arg)
@@ -1404,6 +2048,13 @@ func (m metricsStore) UpdateTemplateACLByID(ctx context.Context, arg database.Up
 	return err
 }
 
+func (m metricsStore) UpdateTemplateAccessRequestStatusByID(ctx context.Context, arg database.UpdateTemplateAccessRequestStatusByIDParams) (database.TemplateAccessRequest, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateTemplateAccessRequestStatusByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateAccessRequestStatusByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateTemplateActiveVersionByID(ctx context.Context, arg database.UpdateTemplateActiveVersionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateTemplateActiveVersionByID(ctx, arg)
@@ -1453,6 +2104,13 @@ func (m metricsStore) UpdateTemplateVersionGitAuthProvidersByJobID(ctx context.C
 	return err
 }
 
+func (m metricsStore) UpdateTemplateVersionPromotionStatusByID(ctx context.Context, arg database.UpdateTemplateVersionPromotionStatusByIDParams) (database.TemplateVersionPromotion, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateTemplateVersionPromotionStatusByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateTemplateVersionPromotionStatusByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateTemplateWorkspacesLastUsedAt(ctx, arg)
@@ -1537,6 +2195,27 @@ func (m metricsStore) UpdateWorkspace(ctx context.Context, arg database.UpdateWo
 	return workspace, err
 }
 
+func (m metricsStore) UpdateWorkspaceAgentAuthTokenByID(ctx context.Context, arg database.UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceAgentAuthTokenByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentAuthTokenByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) UpdateWorkspaceAgentClientConnectionDisconnectedAt(ctx context.Context, arg database.UpdateWorkspaceAgentClientConnectionDisconnectedAtParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceAgentClientConnectionDisconnectedAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentClientConnectionDisconnectedAt").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) UpdateWorkspaceAgentClockOffsetByID(ctx context.Context, arg database.UpdateWorkspaceAgentClockOffsetByIDParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceAgentClockOffsetByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAgentClockOffsetByID").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAgentConnectionByID(ctx, arg)
@@ -1579,6 +2258,20 @@ func (m metricsStore) UpdateWorkspaceAppHealthByID(ctx context.Context, arg data
 	return err
 }
 
+func (m metricsStore) UpdateWorkspaceApprovalStatusByID(ctx context.Context, arg database.UpdateWorkspaceApprovalStatusByIDParams) (database.WorkspaceApproval, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceApprovalStatusByID(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceApprovalStatusByID").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg database.UpdateWorkspaceAutomaticUpdatesParams) error {
+	start := time.Now()
+	r0 := m.s.UpdateWorkspaceAutomaticUpdates(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceAutomaticUpdates").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpdateWorkspaceAutostart(ctx context.Context, arg database.UpdateWorkspaceAutostartParams) error {
 	start := time.Now()
 	err := m.s.UpdateWorkspaceAutostart(ctx, arg)
@@ -1628,6 +2321,13 @@ func (m metricsStore) UpdateWorkspaceProxy(ctx context.Context, arg database.Upd
 	return proxy, err
 }
 
+func (m metricsStore) UpdateWorkspaceProxyACL(ctx context.Context, arg database.UpdateWorkspaceProxyACLParams) (database.WorkspaceProxy, error) {
+	start := time.Now()
+	proxy, err := m.s.UpdateWorkspaceProxyACL(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceProxyACL").Observe(time.Since(start).Seconds())
+	return proxy, err
+}
+
 func (m metricsStore) UpdateWorkspaceProxyDeleted(ctx context.Context, arg database.UpdateWorkspaceProxyDeletedParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspaceProxyDeleted(ctx, arg)
@@ -1642,6 +2342,13 @@ func (m metricsStore) UpdateWorkspaceTTL(ctx context.Context, arg database.Updat
 	return r0
 }
 
+func (m metricsStore) UpdateWorkspaceWebhookLastTriggeredAt(ctx context.Context, arg database.UpdateWorkspaceWebhookLastTriggeredAtParams) (database.WorkspaceWebhook, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpdateWorkspaceWebhookLastTriggeredAt(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpdateWorkspaceWebhookLastTriggeredAt").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
 func (m metricsStore) UpdateWorkspacesLockedDeletingAtByTemplateID(ctx context.Context, arg database.UpdateWorkspacesLockedDeletingAtByTemplateIDParams) error {
 	start := time.Now()
 	r0 := m.s.UpdateWorkspacesLockedDeletingAtByTemplateID(ctx, arg)
@@ -1670,6 +2377,13 @@ func (m metricsStore) UpsertLastUpdateCheck(ctx context.Context, value string) e
 	return r0
 }
 
+func (m metricsStore) UpsertLicenseUsage(ctx context.Context, arg database.UpsertLicenseUsageParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertLicenseUsage(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertLicenseUsage").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpsertLogoURL(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertLogoURL(ctx, value)
@@ -1684,6 +2398,20 @@ func (m metricsStore) UpsertOAuthSigningKey(ctx context.Context, value string) e
 	return r0
 }
 
+func (m metricsStore) UpsertRuntimeExperiments(ctx context.Context, value string) error {
+	start := time.Now()
+	r0 := m.s.UpsertRuntimeExperiments(ctx, value)
+	m.queryLatencies.WithLabelValues("UpsertRuntimeExperiments").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) UpsertSSHCAKey(ctx context.Context, value string) error {
+	start := time.Now()
+	r0 := m.s.UpsertSSHCAKey(ctx, value)
+	m.queryLatencies.WithLabelValues("UpsertSSHCAKey").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) UpsertServiceBanner(ctx context.Context, value string) error {
 	start := time.Now()
 	r0 := m.s.UpsertServiceBanner(ctx, value)
@@ -1709,6 +2437,48 @@ func (m metricsStore) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID
 	return m.s.UpsertTailnetCoordinator(ctx, id)
 }
 
+func (m metricsStore) UpsertTemplateVersionBuildInsights(ctx context.Context, arg database.UpsertTemplateVersionBuildInsightsParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertTemplateVersionBuildInsights(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertTemplateVersionBuildInsights").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) UpsertUserPTYSessionOverride(ctx context.Context, arg database.UpsertUserPTYSessionOverrideParams) (database.UserPTYSessionOverride, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertUserPTYSessionOverride(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserPTYSessionOverride").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpsertUserQuotaOverride(ctx context.Context, arg database.UpsertUserQuotaOverrideParams) (database.UserQuotaOverride, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertUserQuotaOverride(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserQuotaOverride").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpsertUserRegionLatency(ctx context.Context, arg database.UpsertUserRegionLatencyParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertUserRegionLatency(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertUserRegionLatency").Observe(time.Since(start).Seconds())
+	return r0
+}
+
+func (m metricsStore) UpsertWorkspaceExternalMetadatum(ctx context.Context, arg database.UpsertWorkspaceExternalMetadatumParams) (database.WorkspaceExternalMetadatum, error) {
+	start := time.Now()
+	r0, r1 := m.s.UpsertWorkspaceExternalMetadatum(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspaceExternalMetadatum").Observe(time.Since(start).Seconds())
+	return r0, r1
+}
+
+func (m metricsStore) UpsertWorkspacePreviousName(ctx context.Context, arg database.UpsertWorkspacePreviousNameParams) error {
+	start := time.Now()
+	r0 := m.s.UpsertWorkspacePreviousName(ctx, arg)
+	m.queryLatencies.WithLabelValues("UpsertWorkspacePreviousName").Observe(time.Since(start).Seconds())
+	return r0
+}
+
 func (m metricsStore) GetAuthorizedTemplates(ctx context.Context, arg database.GetTemplatesWithFilterParams, prepared rbac.PreparedAuthorized) ([]database.Template, error) {
 	start := time.Now()
 	templates, err := m.s.GetAuthorizedTemplates(ctx, arg, prepared)
@@ -124,6 +124,20 @@ func (mr *MockStoreMockRecorder) DeleteApplicationConnectAPIKeysByUserID(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteApplicationConnectAPIKeysByUserID", reflect.TypeOf((*MockStore)(nil).DeleteApplicationConnectAPIKeysByUserID), arg0, arg1)
 }
 
+// DeleteAuditLogsByIDs mocks base method.
+func (m *MockStore) DeleteAuditLogsByIDs(arg0 context.Context, arg1 []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuditLogsByIDs", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuditLogsByIDs indicates an expected call of DeleteAuditLogsByIDs.
+func (mr *MockStoreMockRecorder) DeleteAuditLogsByIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuditLogsByIDs", reflect.TypeOf((*MockStore)(nil).DeleteAuditLogsByIDs), arg0, arg1)
+}
+
 // DeleteCoordinator mocks base method.
 func (m *MockStore) DeleteCoordinator(arg0 context.Context, arg1 uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -166,6 +180,20 @@ func (mr *MockStoreMockRecorder) DeleteGroupByID(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupByID", reflect.TypeOf((*MockStore)(nil).DeleteGroupByID), arg0, arg1)
 }
 
+// DeleteGroupDefaultTemplates mocks base method.
+func (m *MockStore) DeleteGroupDefaultTemplates(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGroupDefaultTemplates", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGroupDefaultTemplates indicates an expected call of DeleteGroupDefaultTemplates.
+func (mr *MockStoreMockRecorder) DeleteGroupDefaultTemplates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGroupDefaultTemplates", reflect.TypeOf((*MockStore)(nil).DeleteGroupDefaultTemplates), arg0, arg1)
+}
+
 // DeleteGroupMemberFromGroup mocks base method.
 func (m *MockStore) DeleteGroupMemberFromGroup(arg0 context.Context, arg1 database.DeleteGroupMemberFromGroupParams) error {
 	m.ctrl.T.Helper()
@@ -209,6 +237,90 @@ func (mr *MockStoreMockRecorder) DeleteLicense(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLicense", reflect.TypeOf((*MockStore)(nil).DeleteLicense), arg0, arg1)
 }
 
+// DeleteManagedEnvironmentVariableByID mocks base method.
+func (m *MockStore) DeleteManagedEnvironmentVariableByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteManagedEnvironmentVariableByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteManagedEnvironmentVariableByID indicates an expected call of DeleteManagedEnvironmentVariableByID.
+func (mr *MockStoreMockRecorder) DeleteManagedEnvironmentVariableByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteManagedEnvironmentVariableByID", reflect.TypeOf((*MockStore)(nil).DeleteManagedEnvironmentVariableByID), arg0, arg1)
+}
+
+// DeleteOldNetcheckReports mocks base method.
+func (m *MockStore) DeleteOldNetcheckReports(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldNetcheckReports", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldNetcheckReports indicates an expected call of DeleteOldNetcheckReports.
+func (mr *MockStoreMockRecorder) DeleteOldNetcheckReports(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldNetcheckReports", reflect.TypeOf((*MockStore)(nil).DeleteOldNetcheckReports), arg0)
+}
+
+// DeleteOldPlatformEvents mocks base method.
+func (m *MockStore) DeleteOldPlatformEvents(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldPlatformEvents", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldPlatformEvents indicates an expected call of DeleteOldPlatformEvents.
+func (mr *MockStoreMockRecorder) DeleteOldPlatformEvents(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldPlatformEvents", reflect.TypeOf((*MockStore)(nil).DeleteOldPlatformEvents), arg0)
+}
+
+// DeleteOldWorkspaceAgentAuthTokenRotations mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentAuthTokenRotations(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentAuthTokenRotations", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceAgentAuthTokenRotations indicates an expected call of DeleteOldWorkspaceAgentAuthTokenRotations.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentAuthTokenRotations(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentAuthTokenRotations", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentAuthTokenRotations), arg0)
+}
+
+// DeleteOldWorkspaceAgentBandwidthStats mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentBandwidthStats(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentBandwidthStats", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceAgentBandwidthStats indicates an expected call of DeleteOldWorkspaceAgentBandwidthStats.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentBandwidthStats(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentBandwidthStats", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentBandwidthStats), arg0)
+}
+
+// DeleteOldWorkspaceAgentClientConnections mocks base method.
+func (m *MockStore) DeleteOldWorkspaceAgentClientConnections(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOldWorkspaceAgentClientConnections", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOldWorkspaceAgentClientConnections indicates an expected call of DeleteOldWorkspaceAgentClientConnections.
+func (mr *MockStoreMockRecorder) DeleteOldWorkspaceAgentClientConnections(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOldWorkspaceAgentClientConnections", reflect.TypeOf((*MockStore)(nil).DeleteOldWorkspaceAgentClientConnections), arg0)
+}
+
 // DeleteOldWorkspaceAgentLogs mocks base method.
 func (m *MockStore) DeleteOldWorkspaceAgentLogs(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -281,6 +393,132 @@ func (mr *MockStoreMockRecorder) DeleteTailnetClient(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTailnetClient", reflect.TypeOf((*MockStore)(nil).DeleteTailnetClient), arg0, arg1)
 }
 
+// DeleteUserDeprovision mocks base method.
+func (m *MockStore) DeleteUserDeprovision(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserDeprovision", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserDeprovision indicates an expected call of DeleteUserDeprovision.
+func (mr *MockStoreMockRecorder) DeleteUserDeprovision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserDeprovision", reflect.TypeOf((*MockStore)(nil).DeleteUserDeprovision), arg0, arg1)
+}
+
+// DeleteUserPTYSessionOverride mocks base method.
+func (m *MockStore) DeleteUserPTYSessionOverride(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserPTYSessionOverride", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserPTYSessionOverride indicates an expected call of DeleteUserPTYSessionOverride.
+func (mr *MockStoreMockRecorder) DeleteUserPTYSessionOverride(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserPTYSessionOverride", reflect.TypeOf((*MockStore)(nil).DeleteUserPTYSessionOverride), arg0, arg1)
+}
+
+// DeleteUserQuotaOverride mocks base method.
+func (m *MockStore) DeleteUserQuotaOverride(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserQuotaOverride", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserQuotaOverride indicates an expected call of DeleteUserQuotaOverride.
+func (mr *MockStoreMockRecorder) DeleteUserQuotaOverride(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserQuotaOverride", reflect.TypeOf((*MockStore)(nil).DeleteUserQuotaOverride), arg0, arg1)
+}
+
+// DeleteWorkspaceBuildQueueEntriesByWorkspaceID mocks base method.
+func (m *MockStore) DeleteWorkspaceBuildQueueEntriesByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceBuildQueueEntriesByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceBuildQueueEntriesByWorkspaceID indicates an expected call of DeleteWorkspaceBuildQueueEntriesByWorkspaceID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceBuildQueueEntriesByWorkspaceID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceBuildQueueEntriesByWorkspaceID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceBuildQueueEntriesByWorkspaceID), arg0, arg1)
+}
+
+// DeleteWorkspaceBuildQueueEntryByID mocks base method.
+func (m *MockStore) DeleteWorkspaceBuildQueueEntryByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceBuildQueueEntryByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceBuildQueueEntryByID indicates an expected call of DeleteWorkspaceBuildQueueEntryByID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceBuildQueueEntryByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceBuildQueueEntryByID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceBuildQueueEntryByID), arg0, arg1)
+}
+
+// DeleteWorkspaceExternalMetadatum mocks base method.
+func (m *MockStore) DeleteWorkspaceExternalMetadatum(arg0 context.Context, arg1 database.DeleteWorkspaceExternalMetadatumParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceExternalMetadatum", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceExternalMetadatum indicates an expected call of DeleteWorkspaceExternalMetadatum.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceExternalMetadatum(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceExternalMetadatum", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceExternalMetadatum), arg0, arg1)
+}
+
+// DeleteWorkspacePreviousName mocks base method.
+func (m *MockStore) DeleteWorkspacePreviousName(arg0 context.Context, arg1 database.DeleteWorkspacePreviousNameParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspacePreviousName", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspacePreviousName indicates an expected call of DeleteWorkspacePreviousName.
+func (mr *MockStoreMockRecorder) DeleteWorkspacePreviousName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspacePreviousName", reflect.TypeOf((*MockStore)(nil).DeleteWorkspacePreviousName), arg0, arg1)
+}
+
+// DeleteWorkspaceWebhookByID mocks base method.
+func (m *MockStore) DeleteWorkspaceWebhookByID(arg0 context.Context, arg1 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspaceWebhookByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspaceWebhookByID indicates an expected call of DeleteWorkspaceWebhookByID.
+func (mr *MockStoreMockRecorder) DeleteWorkspaceWebhookByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspaceWebhookByID", reflect.TypeOf((*MockStore)(nil).DeleteWorkspaceWebhookByID), arg0, arg1)
+}
+
+// ExpireWorkspaceAgentAuthTokenRotations mocks base method.
+func (m *MockStore) ExpireWorkspaceAgentAuthTokenRotations(arg0 context.Context, arg1 database.ExpireWorkspaceAgentAuthTokenRotationsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireWorkspaceAgentAuthTokenRotations", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireWorkspaceAgentAuthTokenRotations indicates an expected call of ExpireWorkspaceAgentAuthTokenRotations.
+func (mr *MockStoreMockRecorder) ExpireWorkspaceAgentAuthTokenRotations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireWorkspaceAgentAuthTokenRotations", reflect.TypeOf((*MockStore)(nil).ExpireWorkspaceAgentAuthTokenRotations), arg0, arg1)
+}
+
 // GetAPIKeyByID mocks base method.
 func (m *MockStore) GetAPIKeyByID(arg0 context.Context, arg1 string) (database.APIKey, error) {
 	m.ctrl.T.Helper()
@@ -431,6 +669,36 @@ func (mr *MockStoreMockRecorder) GetAppSecurityKey(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAppSecurityKey", reflect.TypeOf((*MockStore)(nil).GetAppSecurityKey), arg0)
 }
 
+// GetAuditLogArchivalByID mocks base method.
+func (m *MockStore) GetAuditLogArchivalByID(arg0 context.Context, arg1 uuid.UUID) (database.AuditLogArchival, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogArchivalByID", arg0, arg1)
+	ret0, _ := ret[0].(database.AuditLogArchival)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogArchivalByID indicates an expected call of GetAuditLogArchivalByID.
+func (mr *MockStoreMockRecorder) GetAuditLogArchivalByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogArchivalByID", reflect.TypeOf((*MockStore)(nil).GetAuditLogArchivalByID), arg0, arg1)
+}
+
+// GetAuditLogArchivals mocks base method.
+func (m *MockStore) GetAuditLogArchivals(arg0 context.Context, arg1 int32) ([]database.AuditLogArchival, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogArchivals", arg0, arg1)
+	ret0, _ := ret[0].([]database.AuditLogArchival)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogArchivals indicates an expected call of GetAuditLogArchivals.
+func (mr *MockStoreMockRecorder) GetAuditLogArchivals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogArchivals", reflect.TypeOf((*MockStore)(nil).GetAuditLogArchivals), arg0, arg1)
+}
+
 // GetAuditLogsOffset mocks base method.
 func (m *MockStore) GetAuditLogsOffset(arg0 context.Context, arg1 database.GetAuditLogsOffsetParams) ([]database.GetAuditLogsOffsetRow, error) {
 	m.ctrl.T.Helper()
@@ -446,6 +714,21 @@ func (mr *MockStoreMockRecorder) GetAuditLogsOffset(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogsOffset", reflect.TypeOf((*MockStore)(nil).GetAuditLogsOffset), arg0, arg1)
 }
 
+// GetAuditLogsOlderThan mocks base method.
+func (m *MockStore) GetAuditLogsOlderThan(arg0 context.Context, arg1 database.GetAuditLogsOlderThanParams) ([]database.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogsOlderThan", arg0, arg1)
+	ret0, _ := ret[0].([]database.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogsOlderThan indicates an expected call of GetAuditLogsOlderThan.
+func (mr *MockStoreMockRecorder) GetAuditLogsOlderThan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogsOlderThan", reflect.TypeOf((*MockStore)(nil).GetAuditLogsOlderThan), arg0, arg1)
+}
+
 // GetAuthorizationUserRoles mocks base method.
 func (m *MockStore) GetAuthorizationUserRoles(arg0 context.Context, arg1 uuid.UUID) (database.GetAuthorizationUserRolesRow, error) {
 	m.ctrl.T.Helper()
@@ -596,6 +879,21 @@ func (mr *MockStoreMockRecorder) GetDeploymentWorkspaceStats(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeploymentWorkspaceStats", reflect.TypeOf((*MockStore)(nil).GetDeploymentWorkspaceStats), arg0)
 }
 
+// GetEntitlementSnapshots mocks base method.
+func (m *MockStore) GetEntitlementSnapshots(arg0 context.Context, arg1 database.GetEntitlementSnapshotsParams) ([]database.EntitlementSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntitlementSnapshots", arg0, arg1)
+	ret0, _ := ret[0].([]database.EntitlementSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntitlementSnapshots indicates an expected call of GetEntitlementSnapshots.
+func (mr *MockStoreMockRecorder) GetEntitlementSnapshots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntitlementSnapshots", reflect.TypeOf((*MockStore)(nil).GetEntitlementSnapshots), arg0, arg1)
+}
+
 // GetFileByHashAndCreator mocks base method.
 func (m *MockStore) GetFileByHashAndCreator(arg0 context.Context, arg1 database.GetFileByHashAndCreatorParams) (database.File, error) {
 	m.ctrl.T.Helper()
@@ -701,6 +999,21 @@ func (mr *MockStoreMockRecorder) GetGroupByOrgAndName(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupByOrgAndName", reflect.TypeOf((*MockStore)(nil).GetGroupByOrgAndName), arg0, arg1)
 }
 
+// GetGroupDefaultTemplates mocks base method.
+func (m *MockStore) GetGroupDefaultTemplates(arg0 context.Context, arg1 uuid.UUID) ([]database.GroupDefaultTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupDefaultTemplates", arg0, arg1)
+	ret0, _ := ret[0].([]database.GroupDefaultTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupDefaultTemplates indicates an expected call of GetGroupDefaultTemplates.
+func (mr *MockStoreMockRecorder) GetGroupDefaultTemplates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupDefaultTemplates", reflect.TypeOf((*MockStore)(nil).GetGroupDefaultTemplates), arg0, arg1)
+}
+
 // GetGroupMembers mocks base method.
 func (m *MockStore) GetGroupMembers(arg0 context.Context, arg1 uuid.UUID) ([]database.User, error) {
 	m.ctrl.T.Helper()
@@ -716,6 +1029,21 @@ func (mr *MockStoreMockRecorder) GetGroupMembers(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupMembers", reflect.TypeOf((*MockStore)(nil).GetGroupMembers), arg0, arg1)
 }
 
+// GetGroupMembersAnyStatus mocks base method.
+func (m *MockStore) GetGroupMembersAnyStatus(arg0 context.Context, arg1 uuid.UUID) ([]database.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupMembersAnyStatus", arg0, arg1)
+	ret0, _ := ret[0].([]database.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupMembersAnyStatus indicates an expected call of GetGroupMembersAnyStatus.
+func (mr *MockStoreMockRecorder) GetGroupMembersAnyStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupMembersAnyStatus", reflect.TypeOf((*MockStore)(nil).GetGroupMembersAnyStatus), arg0, arg1)
+}
+
 // GetGroupsByOrganizationID mocks base method.
 func (m *MockStore) GetGroupsByOrganizationID(arg0 context.Context, arg1 uuid.UUID) ([]database.Group, error) {
 	m.ctrl.T.Helper()
@@ -761,6 +1089,21 @@ func (mr *MockStoreMockRecorder) GetLastUpdateCheck(arg0 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastUpdateCheck", reflect.TypeOf((*MockStore)(nil).GetLastUpdateCheck), arg0)
 }
 
+// GetLatestWorkspaceApprovalByWorkspaceID mocks base method.
+func (m *MockStore) GetLatestWorkspaceApprovalByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestWorkspaceApprovalByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestWorkspaceApprovalByWorkspaceID indicates an expected call of GetLatestWorkspaceApprovalByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetLatestWorkspaceApprovalByWorkspaceID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestWorkspaceApprovalByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetLatestWorkspaceApprovalByWorkspaceID), arg0, arg1)
+}
+
 // GetLatestWorkspaceBuildByWorkspaceID mocks base method.
 func (m *MockStore) GetLatestWorkspaceBuildByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceBuild, error) {
 	m.ctrl.T.Helper()
@@ -821,6 +1164,21 @@ func (mr *MockStoreMockRecorder) GetLicenseByID(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenseByID", reflect.TypeOf((*MockStore)(nil).GetLicenseByID), arg0, arg1)
 }
 
+// GetLicenseUsage mocks base method.
+func (m *MockStore) GetLicenseUsage(arg0 context.Context, arg1 database.GetLicenseUsageParams) ([]database.LicenseUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLicenseUsage", arg0, arg1)
+	ret0, _ := ret[0].([]database.LicenseUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLicenseUsage indicates an expected call of GetLicenseUsage.
+func (mr *MockStoreMockRecorder) GetLicenseUsage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLicenseUsage", reflect.TypeOf((*MockStore)(nil).GetLicenseUsage), arg0, arg1)
+}
+
 // GetLicenses mocks base method.
 func (m *MockStore) GetLicenses(arg0 context.Context) ([]database.License, error) {
 	m.ctrl.T.Helper()
@@ -851,6 +1209,66 @@ func (mr *MockStoreMockRecorder) GetLogoURL(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogoURL", reflect.TypeOf((*MockStore)(nil).GetLogoURL), arg0)
 }
 
+// GetManagedEnvironmentVariableByID mocks base method.
+func (m *MockStore) GetManagedEnvironmentVariableByID(arg0 context.Context, arg1 uuid.UUID) (database.ManagedEnvironmentVariable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetManagedEnvironmentVariableByID", arg0, arg1)
+	ret0, _ := ret[0].(database.ManagedEnvironmentVariable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetManagedEnvironmentVariableByID indicates an expected call of GetManagedEnvironmentVariableByID.
+func (mr *MockStoreMockRecorder) GetManagedEnvironmentVariableByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetManagedEnvironmentVariableByID", reflect.TypeOf((*MockStore)(nil).GetManagedEnvironmentVariableByID), arg0, arg1)
+}
+
+// GetManagedEnvironmentVariablesByOrganizationID mocks base method.
+func (m *MockStore) GetManagedEnvironmentVariablesByOrganizationID(arg0 context.Context, arg1 uuid.UUID) ([]database.ManagedEnvironmentVariable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetManagedEnvironmentVariablesByOrganizationID", arg0, arg1)
+	ret0, _ := ret[0].([]database.ManagedEnvironmentVariable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetManagedEnvironmentVariablesByOrganizationID indicates an expected call of GetManagedEnvironmentVariablesByOrganizationID.
+func (mr *MockStoreMockRecorder) GetManagedEnvironmentVariablesByOrganizationID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetManagedEnvironmentVariablesByOrganizationID", reflect.TypeOf((*MockStore)(nil).GetManagedEnvironmentVariablesByOrganizationID), arg0, arg1)
+}
+
+// GetManagedEnvironmentVariablesByTemplateID mocks base method.
+func (m *MockStore) GetManagedEnvironmentVariablesByTemplateID(arg0 context.Context, arg1 uuid.NullUUID) ([]database.ManagedEnvironmentVariable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetManagedEnvironmentVariablesByTemplateID", arg0, arg1)
+	ret0, _ := ret[0].([]database.ManagedEnvironmentVariable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetManagedEnvironmentVariablesByTemplateID indicates an expected call of GetManagedEnvironmentVariablesByTemplateID.
+func (mr *MockStoreMockRecorder) GetManagedEnvironmentVariablesByTemplateID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetManagedEnvironmentVariablesByTemplateID", reflect.TypeOf((*MockStore)(nil).GetManagedEnvironmentVariablesByTemplateID), arg0, arg1)
+}
+
+// GetNetcheckReports mocks base method.
+func (m *MockStore) GetNetcheckReports(arg0 context.Context, arg1 database.GetNetcheckReportsParams) ([]database.NetcheckReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNetcheckReports", arg0, arg1)
+	ret0, _ := ret[0].([]database.NetcheckReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNetcheckReports indicates an expected call of GetNetcheckReports.
+func (mr *MockStoreMockRecorder) GetNetcheckReports(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNetcheckReports", reflect.TypeOf((*MockStore)(nil).GetNetcheckReports), arg0, arg1)
+}
+
 // GetOAuthSigningKey mocks base method.
 func (m *MockStore) GetOAuthSigningKey(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -971,6 +1389,21 @@ func (mr *MockStoreMockRecorder) GetOrganizationsByUserID(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationsByUserID", reflect.TypeOf((*MockStore)(nil).GetOrganizationsByUserID), arg0, arg1)
 }
 
+// GetOutdatedWorkspacesByTemplateID mocks base method.
+func (m *MockStore) GetOutdatedWorkspacesByTemplateID(arg0 context.Context, arg1 uuid.UUID) ([]database.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutdatedWorkspacesByTemplateID", arg0, arg1)
+	ret0, _ := ret[0].([]database.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutdatedWorkspacesByTemplateID indicates an expected call of GetOutdatedWorkspacesByTemplateID.
+func (mr *MockStoreMockRecorder) GetOutdatedWorkspacesByTemplateID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutdatedWorkspacesByTemplateID", reflect.TypeOf((*MockStore)(nil).GetOutdatedWorkspacesByTemplateID), arg0, arg1)
+}
+
 // GetParameterSchemasByJobID mocks base method.
 func (m *MockStore) GetParameterSchemasByJobID(arg0 context.Context, arg1 uuid.UUID) ([]database.ParameterSchema, error) {
 	m.ctrl.T.Helper()
@@ -986,6 +1419,51 @@ func (mr *MockStoreMockRecorder) GetParameterSchemasByJobID(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParameterSchemasByJobID", reflect.TypeOf((*MockStore)(nil).GetParameterSchemasByJobID), arg0, arg1)
 }
 
+// GetPendingGroupDefaultTemplateWorkspaces mocks base method.
+func (m *MockStore) GetPendingGroupDefaultTemplateWorkspaces(arg0 context.Context) ([]database.GetPendingGroupDefaultTemplateWorkspacesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingGroupDefaultTemplateWorkspaces", arg0)
+	ret0, _ := ret[0].([]database.GetPendingGroupDefaultTemplateWorkspacesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingGroupDefaultTemplateWorkspaces indicates an expected call of GetPendingGroupDefaultTemplateWorkspaces.
+func (mr *MockStoreMockRecorder) GetPendingGroupDefaultTemplateWorkspaces(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingGroupDefaultTemplateWorkspaces", reflect.TypeOf((*MockStore)(nil).GetPendingGroupDefaultTemplateWorkspaces), arg0)
+}
+
+// GetPendingProvisionerJobs mocks base method.
+func (m *MockStore) GetPendingProvisionerJobs(arg0 context.Context) ([]database.ProvisionerJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingProvisionerJobs", arg0)
+	ret0, _ := ret[0].([]database.ProvisionerJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingProvisionerJobs indicates an expected call of GetPendingProvisionerJobs.
+func (mr *MockStoreMockRecorder) GetPendingProvisionerJobs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingProvisionerJobs", reflect.TypeOf((*MockStore)(nil).GetPendingProvisionerJobs), arg0)
+}
+
+// GetPlatformEventsAfterID mocks base method.
+func (m *MockStore) GetPlatformEventsAfterID(arg0 context.Context, arg1 database.GetPlatformEventsAfterIDParams) ([]database.PlatformEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlatformEventsAfterID", arg0, arg1)
+	ret0, _ := ret[0].([]database.PlatformEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlatformEventsAfterID indicates an expected call of GetPlatformEventsAfterID.
+func (mr *MockStoreMockRecorder) GetPlatformEventsAfterID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlatformEventsAfterID", reflect.TypeOf((*MockStore)(nil).GetPlatformEventsAfterID), arg0, arg1)
+}
+
 // GetPreviousTemplateVersion mocks base method.
 func (m *MockStore) GetPreviousTemplateVersion(arg0 context.Context, arg1 database.GetPreviousTemplateVersionParams) (database.TemplateVersion, error) {
 	m.ctrl.T.Helper()
@@ -1016,6 +1494,21 @@ func (mr *MockStoreMockRecorder) GetProvisionerDaemons(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerDaemons", reflect.TypeOf((*MockStore)(nil).GetProvisionerDaemons), arg0)
 }
 
+// GetProvisionerJobBuildHooksByJobID mocks base method.
+func (m *MockStore) GetProvisionerJobBuildHooksByJobID(arg0 context.Context, arg1 uuid.UUID) ([]database.ProvisionerJobBuildHook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobBuildHooksByJobID", arg0, arg1)
+	ret0, _ := ret[0].([]database.ProvisionerJobBuildHook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobBuildHooksByJobID indicates an expected call of GetProvisionerJobBuildHooksByJobID.
+func (mr *MockStoreMockRecorder) GetProvisionerJobBuildHooksByJobID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobBuildHooksByJobID", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobBuildHooksByJobID), arg0, arg1)
+}
+
 // GetProvisionerJobByID mocks base method.
 func (m *MockStore) GetProvisionerJobByID(arg0 context.Context, arg1 uuid.UUID) (database.ProvisionerJob, error) {
 	m.ctrl.T.Helper()
@@ -1031,6 +1524,21 @@ func (mr *MockStoreMockRecorder) GetProvisionerJobByID(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobByID", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobByID), arg0, arg1)
 }
 
+// GetProvisionerJobConcurrencyLimit mocks base method.
+func (m *MockStore) GetProvisionerJobConcurrencyLimit(arg0 context.Context, arg1 uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobConcurrencyLimit", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobConcurrencyLimit indicates an expected call of GetProvisionerJobConcurrencyLimit.
+func (mr *MockStoreMockRecorder) GetProvisionerJobConcurrencyLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobConcurrencyLimit", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobConcurrencyLimit), arg0, arg1)
+}
+
 // GetProvisionerJobsByIDs mocks base method.
 func (m *MockStore) GetProvisionerJobsByIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.ProvisionerJob, error) {
 	m.ctrl.T.Helper()
@@ -1076,6 +1584,21 @@ func (mr *MockStoreMockRecorder) GetProvisionerJobsCreatedAfter(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobsCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobsCreatedAfter), arg0, arg1)
 }
 
+// GetProvisionerJobsStartedAfter mocks base method.
+func (m *MockStore) GetProvisionerJobsStartedAfter(arg0 context.Context, arg1 time.Time) ([]database.ProvisionerJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvisionerJobsStartedAfter", arg0, arg1)
+	ret0, _ := ret[0].([]database.ProvisionerJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvisionerJobsStartedAfter indicates an expected call of GetProvisionerJobsStartedAfter.
+func (mr *MockStoreMockRecorder) GetProvisionerJobsStartedAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerJobsStartedAfter", reflect.TypeOf((*MockStore)(nil).GetProvisionerJobsStartedAfter), arg0, arg1)
+}
+
 // GetProvisionerLogsAfterID mocks base method.
 func (m *MockStore) GetProvisionerLogsAfterID(arg0 context.Context, arg1 database.GetProvisionerLogsAfterIDParams) ([]database.ProvisionerJobLog, error) {
 	m.ctrl.T.Helper()
@@ -1091,19 +1614,19 @@ func (mr *MockStoreMockRecorder) GetProvisionerLogsAfterID(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvisionerLogsAfterID", reflect.TypeOf((*MockStore)(nil).GetProvisionerLogsAfterID), arg0, arg1)
 }
 
-// GetQuotaAllowanceForUser mocks base method.
-func (m *MockStore) GetQuotaAllowanceForUser(arg0 context.Context, arg1 uuid.UUID) (int64, error) {
+// GetQuotaBudgetsForUser mocks base method.
+func (m *MockStore) GetQuotaBudgetsForUser(arg0 context.Context, arg1 uuid.UUID) ([]database.Group, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetQuotaAllowanceForUser", arg0, arg1)
-	ret0, _ := ret[0].(int64)
+	ret := m.ctrl.Call(m, "GetQuotaBudgetsForUser", arg0, arg1)
+	ret0, _ := ret[0].([]database.Group)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetQuotaAllowanceForUser indicates an expected call of GetQuotaAllowanceForUser.
-func (mr *MockStoreMockRecorder) GetQuotaAllowanceForUser(arg0, arg1 interface{}) *gomock.Call {
+// GetQuotaBudgetsForUser indicates an expected call of GetQuotaBudgetsForUser.
+func (mr *MockStoreMockRecorder) GetQuotaBudgetsForUser(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaAllowanceForUser", reflect.TypeOf((*MockStore)(nil).GetQuotaAllowanceForUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaBudgetsForUser", reflect.TypeOf((*MockStore)(nil).GetQuotaBudgetsForUser), arg0, arg1)
 }
 
 // GetQuotaConsumedForUser mocks base method.
@@ -1121,6 +1644,21 @@ func (mr *MockStoreMockRecorder) GetQuotaConsumedForUser(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaConsumedForUser", reflect.TypeOf((*MockStore)(nil).GetQuotaConsumedForUser), arg0, arg1)
 }
 
+// GetQuotaConsumedWorkspacesForUser mocks base method.
+func (m *MockStore) GetQuotaConsumedWorkspacesForUser(arg0 context.Context, arg1 uuid.UUID) ([]database.GetQuotaConsumedWorkspacesForUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQuotaConsumedWorkspacesForUser", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetQuotaConsumedWorkspacesForUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQuotaConsumedWorkspacesForUser indicates an expected call of GetQuotaConsumedWorkspacesForUser.
+func (mr *MockStoreMockRecorder) GetQuotaConsumedWorkspacesForUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQuotaConsumedWorkspacesForUser", reflect.TypeOf((*MockStore)(nil).GetQuotaConsumedWorkspacesForUser), arg0, arg1)
+}
+
 // GetReplicaByID mocks base method.
 func (m *MockStore) GetReplicaByID(arg0 context.Context, arg1 uuid.UUID) (database.Replica, error) {
 	m.ctrl.T.Helper()
@@ -1151,6 +1689,36 @@ func (mr *MockStoreMockRecorder) GetReplicasUpdatedAfter(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplicasUpdatedAfter", reflect.TypeOf((*MockStore)(nil).GetReplicasUpdatedAfter), arg0, arg1)
 }
 
+// GetRuntimeExperiments mocks base method.
+func (m *MockStore) GetRuntimeExperiments(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRuntimeExperiments", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRuntimeExperiments indicates an expected call of GetRuntimeExperiments.
+func (mr *MockStoreMockRecorder) GetRuntimeExperiments(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRuntimeExperiments", reflect.TypeOf((*MockStore)(nil).GetRuntimeExperiments), arg0)
+}
+
+// GetSSHCAKey mocks base method.
+func (m *MockStore) GetSSHCAKey(arg0 context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSSHCAKey", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSSHCAKey indicates an expected call of GetSSHCAKey.
+func (mr *MockStoreMockRecorder) GetSSHCAKey(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSSHCAKey", reflect.TypeOf((*MockStore)(nil).GetSSHCAKey), arg0)
+}
+
 // GetServiceBanner mocks base method.
 func (m *MockStore) GetServiceBanner(arg0 context.Context) (string, error) {
 	m.ctrl.T.Helper()
@@ -1196,6 +1764,21 @@ func (mr *MockStoreMockRecorder) GetTailnetClientsForAgent(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTailnetClientsForAgent", reflect.TypeOf((*MockStore)(nil).GetTailnetClientsForAgent), arg0, arg1)
 }
 
+// GetTemplateAgentFirstConnectInsights mocks base method.
+func (m *MockStore) GetTemplateAgentFirstConnectInsights(arg0 context.Context, arg1 database.GetTemplateAgentFirstConnectInsightsParams) ([]database.GetTemplateAgentFirstConnectInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateAgentFirstConnectInsights", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetTemplateAgentFirstConnectInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateAgentFirstConnectInsights indicates an expected call of GetTemplateAgentFirstConnectInsights.
+func (mr *MockStoreMockRecorder) GetTemplateAgentFirstConnectInsights(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAgentFirstConnectInsights", reflect.TypeOf((*MockStore)(nil).GetTemplateAgentFirstConnectInsights), arg0, arg1)
+}
+
 // GetTemplateAppInsights mocks base method.
 func (m *MockStore) GetTemplateAppInsights(arg0 context.Context, arg1 database.GetTemplateAppInsightsParams) ([]database.GetTemplateAppInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -1211,6 +1794,36 @@ func (mr *MockStoreMockRecorder) GetTemplateAppInsights(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAppInsights", reflect.TypeOf((*MockStore)(nil).GetTemplateAppInsights), arg0, arg1)
 }
 
+// GetTemplateAccessRequestByID mocks base method.
+func (m *MockStore) GetTemplateAccessRequestByID(arg0 context.Context, arg1 uuid.UUID) (database.TemplateAccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateAccessRequestByID", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateAccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateAccessRequestByID indicates an expected call of GetTemplateAccessRequestByID.
+func (mr *MockStoreMockRecorder) GetTemplateAccessRequestByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAccessRequestByID", reflect.TypeOf((*MockStore)(nil).GetTemplateAccessRequestByID), arg0, arg1)
+}
+
+// GetTemplateAccessRequestsByTemplateID mocks base method.
+func (m *MockStore) GetTemplateAccessRequestsByTemplateID(arg0 context.Context, arg1 database.GetTemplateAccessRequestsByTemplateIDParams) ([]database.TemplateAccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateAccessRequestsByTemplateID", arg0, arg1)
+	ret0, _ := ret[0].([]database.TemplateAccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateAccessRequestsByTemplateID indicates an expected call of GetTemplateAccessRequestsByTemplateID.
+func (mr *MockStoreMockRecorder) GetTemplateAccessRequestsByTemplateID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAccessRequestsByTemplateID", reflect.TypeOf((*MockStore)(nil).GetTemplateAccessRequestsByTemplateID), arg0, arg1)
+}
+
 // GetTemplateAverageBuildTime mocks base method.
 func (m *MockStore) GetTemplateAverageBuildTime(arg0 context.Context, arg1 database.GetTemplateAverageBuildTimeParams) (database.GetTemplateAverageBuildTimeRow, error) {
 	m.ctrl.T.Helper()
@@ -1226,6 +1839,21 @@ func (mr *MockStoreMockRecorder) GetTemplateAverageBuildTime(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateAverageBuildTime", reflect.TypeOf((*MockStore)(nil).GetTemplateAverageBuildTime), arg0, arg1)
 }
 
+// GetTemplateBuildSLOInsights mocks base method.
+func (m *MockStore) GetTemplateBuildSLOInsights(arg0 context.Context, arg1 database.GetTemplateBuildSLOInsightsParams) ([]database.GetTemplateBuildSLOInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateBuildSLOInsights", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetTemplateBuildSLOInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateBuildSLOInsights indicates an expected call of GetTemplateBuildSLOInsights.
+func (mr *MockStoreMockRecorder) GetTemplateBuildSLOInsights(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateBuildSLOInsights", reflect.TypeOf((*MockStore)(nil).GetTemplateBuildSLOInsights), arg0, arg1)
+}
+
 // GetTemplateByID mocks base method.
 func (m *MockStore) GetTemplateByID(arg0 context.Context, arg1 uuid.UUID) (database.Template, error) {
 	m.ctrl.T.Helper()
@@ -1346,6 +1974,21 @@ func (mr *MockStoreMockRecorder) GetTemplateUserRoles(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateUserRoles", reflect.TypeOf((*MockStore)(nil).GetTemplateUserRoles), arg0, arg1)
 }
 
+// GetTemplateVersionBuildInsights mocks base method.
+func (m *MockStore) GetTemplateVersionBuildInsights(arg0 context.Context, arg1 uuid.UUID) ([]database.GetTemplateVersionBuildInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionBuildInsights", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetTemplateVersionBuildInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionBuildInsights indicates an expected call of GetTemplateVersionBuildInsights.
+func (mr *MockStoreMockRecorder) GetTemplateVersionBuildInsights(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionBuildInsights", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionBuildInsights), arg0, arg1)
+}
+
 // GetTemplateVersionByID mocks base method.
 func (m *MockStore) GetTemplateVersionByID(arg0 context.Context, arg1 uuid.UUID) (database.TemplateVersion, error) {
 	m.ctrl.T.Helper()
@@ -1406,6 +2049,36 @@ func (mr *MockStoreMockRecorder) GetTemplateVersionParameters(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionParameters", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionParameters), arg0, arg1)
 }
 
+// GetTemplateVersionPromotionByID mocks base method.
+func (m *MockStore) GetTemplateVersionPromotionByID(arg0 context.Context, arg1 uuid.UUID) (database.TemplateVersionPromotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionPromotionByID", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateVersionPromotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionPromotionByID indicates an expected call of GetTemplateVersionPromotionByID.
+func (mr *MockStoreMockRecorder) GetTemplateVersionPromotionByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionPromotionByID", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionPromotionByID), arg0, arg1)
+}
+
+// GetTemplateVersionPromotions mocks base method.
+func (m *MockStore) GetTemplateVersionPromotions(arg0 context.Context, arg1 database.GetTemplateVersionPromotionsParams) ([]database.TemplateVersionPromotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplateVersionPromotions", arg0, arg1)
+	ret0, _ := ret[0].([]database.TemplateVersionPromotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplateVersionPromotions indicates an expected call of GetTemplateVersionPromotions.
+func (mr *MockStoreMockRecorder) GetTemplateVersionPromotions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplateVersionPromotions", reflect.TypeOf((*MockStore)(nil).GetTemplateVersionPromotions), arg0, arg1)
+}
+
 // GetTemplateVersionVariables mocks base method.
 func (m *MockStore) GetTemplateVersionVariables(arg0 context.Context, arg1 uuid.UUID) ([]database.TemplateVersionVariable, error) {
 	m.ctrl.T.Helper()
@@ -1511,6 +2184,21 @@ func (mr *MockStoreMockRecorder) GetUnexpiredLicenses(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnexpiredLicenses", reflect.TypeOf((*MockStore)(nil).GetUnexpiredLicenses), arg0)
 }
 
+// GetUserBandwidthInsights mocks base method.
+func (m *MockStore) GetUserBandwidthInsights(arg0 context.Context, arg1 database.GetUserBandwidthInsightsParams) ([]database.GetUserBandwidthInsightsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserBandwidthInsights", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetUserBandwidthInsightsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserBandwidthInsights indicates an expected call of GetUserBandwidthInsights.
+func (mr *MockStoreMockRecorder) GetUserBandwidthInsights(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBandwidthInsights", reflect.TypeOf((*MockStore)(nil).GetUserBandwidthInsights), arg0, arg1)
+}
+
 // GetUserByEmailOrUsername mocks base method.
 func (m *MockStore) GetUserByEmailOrUsername(arg0 context.Context, arg1 database.GetUserByEmailOrUsernameParams) (database.User, error) {
 	m.ctrl.T.Helper()
@@ -1556,6 +2244,21 @@ func (mr *MockStoreMockRecorder) GetUserCount(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCount", reflect.TypeOf((*MockStore)(nil).GetUserCount), arg0)
 }
 
+// GetUserDeprovisions mocks base method.
+func (m *MockStore) GetUserDeprovisions(arg0 context.Context) ([]database.UserDeprovision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserDeprovisions", arg0)
+	ret0, _ := ret[0].([]database.UserDeprovision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserDeprovisions indicates an expected call of GetUserDeprovisions.
+func (mr *MockStoreMockRecorder) GetUserDeprovisions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserDeprovisions", reflect.TypeOf((*MockStore)(nil).GetUserDeprovisions), arg0)
+}
+
 // GetUserLatencyInsights mocks base method.
 func (m *MockStore) GetUserLatencyInsights(arg0 context.Context, arg1 database.GetUserLatencyInsightsParams) ([]database.GetUserLatencyInsightsRow, error) {
 	m.ctrl.T.Helper()
@@ -1598,7 +2301,52 @@ func (m *MockStore) GetUserLinkByUserIDLoginType(arg0 context.Context, arg1 data
 // GetUserLinkByUserIDLoginType indicates an expected call of GetUserLinkByUserIDLoginType.
 func (mr *MockStoreMockRecorder) GetUserLinkByUserIDLoginType(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinkByUserIDLoginType", reflect.TypeOf((*MockStore)(nil).GetUserLinkByUserIDLoginType), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLinkByUserIDLoginType", reflect.TypeOf((*MockStore)(nil).GetUserLinkByUserIDLoginType), arg0, arg1)
+}
+
+// GetUserPTYSessionOverride mocks base method.
+func (m *MockStore) GetUserPTYSessionOverride(arg0 context.Context, arg1 uuid.UUID) (database.UserPTYSessionOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPTYSessionOverride", arg0, arg1)
+	ret0, _ := ret[0].(database.UserPTYSessionOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPTYSessionOverride indicates an expected call of GetUserPTYSessionOverride.
+func (mr *MockStoreMockRecorder) GetUserPTYSessionOverride(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPTYSessionOverride", reflect.TypeOf((*MockStore)(nil).GetUserPTYSessionOverride), arg0, arg1)
+}
+
+// GetUserQuotaOverride mocks base method.
+func (m *MockStore) GetUserQuotaOverride(arg0 context.Context, arg1 uuid.UUID) (database.UserQuotaOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserQuotaOverride", arg0, arg1)
+	ret0, _ := ret[0].(database.UserQuotaOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserQuotaOverride indicates an expected call of GetUserQuotaOverride.
+func (mr *MockStoreMockRecorder) GetUserQuotaOverride(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserQuotaOverride", reflect.TypeOf((*MockStore)(nil).GetUserQuotaOverride), arg0, arg1)
+}
+
+// GetUserRegionLatencies mocks base method.
+func (m *MockStore) GetUserRegionLatencies(arg0 context.Context, arg1 database.GetUserRegionLatenciesParams) ([]database.UserRegionLatency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRegionLatencies", arg0, arg1)
+	ret0, _ := ret[0].([]database.UserRegionLatency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRegionLatencies indicates an expected call of GetUserRegionLatencies.
+func (mr *MockStoreMockRecorder) GetUserRegionLatencies(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRegionLatencies", reflect.TypeOf((*MockStore)(nil).GetUserRegionLatencies), arg0, arg1)
 }
 
 // GetUsers mocks base method.
@@ -1646,6 +2394,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentAndOwnerByAuthToken(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentAndOwnerByAuthToken", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentAndOwnerByAuthToken), arg0, arg1)
 }
 
+// GetWorkspaceAgentBandwidthStatsAndLabels mocks base method.
+func (m *MockStore) GetWorkspaceAgentBandwidthStatsAndLabels(arg0 context.Context, arg1 time.Time) ([]database.GetWorkspaceAgentBandwidthStatsAndLabelsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentBandwidthStatsAndLabels", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentBandwidthStatsAndLabelsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentBandwidthStatsAndLabels indicates an expected call of GetWorkspaceAgentBandwidthStatsAndLabels.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentBandwidthStatsAndLabels(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentBandwidthStatsAndLabels", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentBandwidthStatsAndLabels), arg0, arg1)
+}
+
+// GetWorkspaceAgentBandwidthStatsByPeer mocks base method.
+func (m *MockStore) GetWorkspaceAgentBandwidthStatsByPeer(arg0 context.Context, arg1 database.GetWorkspaceAgentBandwidthStatsByPeerParams) ([]database.GetWorkspaceAgentBandwidthStatsByPeerRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentBandwidthStatsByPeer", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentBandwidthStatsByPeerRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentBandwidthStatsByPeer indicates an expected call of GetWorkspaceAgentBandwidthStatsByPeer.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentBandwidthStatsByPeer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentBandwidthStatsByPeer", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentBandwidthStatsByPeer), arg0, arg1)
+}
+
 // GetWorkspaceAgentByID mocks base method.
 func (m *MockStore) GetWorkspaceAgentByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceAgent, error) {
 	m.ctrl.T.Helper()
@@ -1676,6 +2454,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentByInstanceID(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentByInstanceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentByInstanceID), arg0, arg1)
 }
 
+// GetWorkspaceAgentClientConnections mocks base method.
+func (m *MockStore) GetWorkspaceAgentClientConnections(arg0 context.Context, arg1 database.GetWorkspaceAgentClientConnectionsParams) ([]database.WorkspaceAgentClientConnection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentClientConnections", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentClientConnection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentClientConnections indicates an expected call of GetWorkspaceAgentClientConnections.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentClientConnections(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentClientConnections", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentClientConnections), arg0, arg1)
+}
+
+// GetWorkspaceAgentClientConnectionsByUserID mocks base method.
+func (m *MockStore) GetWorkspaceAgentClientConnectionsByUserID(arg0 context.Context, arg1 database.GetWorkspaceAgentClientConnectionsByUserIDParams) ([]database.GetWorkspaceAgentClientConnectionsByUserIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentClientConnectionsByUserID", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentClientConnectionsByUserIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentClientConnectionsByUserID indicates an expected call of GetWorkspaceAgentClientConnectionsByUserID.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentClientConnectionsByUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentClientConnectionsByUserID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentClientConnectionsByUserID), arg0, arg1)
+}
+
 // GetWorkspaceAgentLifecycleStateByID mocks base method.
 func (m *MockStore) GetWorkspaceAgentLifecycleStateByID(arg0 context.Context, arg1 uuid.UUID) (database.GetWorkspaceAgentLifecycleStateByIDRow, error) {
 	m.ctrl.T.Helper()
@@ -1721,6 +2529,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentMetadata(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentMetadata), arg0, arg1)
 }
 
+// GetWorkspaceAgentMetadataHistory mocks base method.
+func (m *MockStore) GetWorkspaceAgentMetadataHistory(arg0 context.Context, arg1 database.GetWorkspaceAgentMetadataHistoryParams) ([]database.WorkspaceAgentMetadataHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentMetadataHistory", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceAgentMetadataHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentMetadataHistory indicates an expected call of GetWorkspaceAgentMetadataHistory.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentMetadataHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentMetadataHistory", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentMetadataHistory), arg0, arg1)
+}
+
+// GetWorkspaceAgentSSHHostKey mocks base method.
+func (m *MockStore) GetWorkspaceAgentSSHHostKey(arg0 context.Context, arg1 database.GetWorkspaceAgentSSHHostKeyParams) (database.WorkspaceAgentSSHHostKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentSSHHostKey", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgentSSHHostKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentSSHHostKey indicates an expected call of GetWorkspaceAgentSSHHostKey.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentSSHHostKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentSSHHostKey", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentSSHHostKey), arg0, arg1)
+}
+
 // GetWorkspaceAgentStats mocks base method.
 func (m *MockStore) GetWorkspaceAgentStats(arg0 context.Context, arg1 time.Time) ([]database.GetWorkspaceAgentStatsRow, error) {
 	m.ctrl.T.Helper()
@@ -1796,6 +2634,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentsInLatestBuildByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentsInLatestBuildByWorkspaceID), arg0, arg1)
 }
 
+// GetWorkspaceAgentsNeverConnected mocks base method.
+func (m *MockStore) GetWorkspaceAgentsNeverConnected(arg0 context.Context, arg1 database.GetWorkspaceAgentsNeverConnectedParams) ([]database.GetWorkspaceAgentsNeverConnectedRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceAgentsNeverConnected", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceAgentsNeverConnectedRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceAgentsNeverConnected indicates an expected call of GetWorkspaceAgentsNeverConnected.
+func (mr *MockStoreMockRecorder) GetWorkspaceAgentsNeverConnected(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAgentsNeverConnected", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAgentsNeverConnected), arg0, arg1)
+}
+
 // GetWorkspaceAppByAgentIDAndSlug mocks base method.
 func (m *MockStore) GetWorkspaceAppByAgentIDAndSlug(arg0 context.Context, arg1 database.GetWorkspaceAppByAgentIDAndSlugParams) (database.WorkspaceApp, error) {
 	m.ctrl.T.Helper()
@@ -1811,6 +2664,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceAppByAgentIDAndSlug(arg0, arg1 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceAppByAgentIDAndSlug", reflect.TypeOf((*MockStore)(nil).GetWorkspaceAppByAgentIDAndSlug), arg0, arg1)
 }
 
+// GetWorkspaceApprovalByID mocks base method.
+func (m *MockStore) GetWorkspaceApprovalByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceApprovalByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceApprovalByID indicates an expected call of GetWorkspaceApprovalByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceApprovalByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceApprovalByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceApprovalByID), arg0, arg1)
+}
+
+// GetWorkspaceApprovalByJobID mocks base method.
+func (m *MockStore) GetWorkspaceApprovalByJobID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceApprovalByJobID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceApprovalByJobID indicates an expected call of GetWorkspaceApprovalByJobID.
+func (mr *MockStoreMockRecorder) GetWorkspaceApprovalByJobID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceApprovalByJobID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceApprovalByJobID), arg0, arg1)
+}
+
 // GetWorkspaceAppsByAgentID mocks base method.
 func (m *MockStore) GetWorkspaceAppsByAgentID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceApp, error) {
 	m.ctrl.T.Helper()
@@ -1916,6 +2799,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceBuildParameters(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildParameters), arg0, arg1)
 }
 
+// GetWorkspaceBuildQueueEntriesByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceBuildQueueEntriesByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceBuildQueueEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildQueueEntriesByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceBuildQueueEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildQueueEntriesByWorkspaceID indicates an expected call of GetWorkspaceBuildQueueEntriesByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildQueueEntriesByWorkspaceID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildQueueEntriesByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildQueueEntriesByWorkspaceID), arg0, arg1)
+}
+
+// GetWorkspaceBuildQueueWorkspaceIDs mocks base method.
+func (m *MockStore) GetWorkspaceBuildQueueWorkspaceIDs(arg0 context.Context) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceBuildQueueWorkspaceIDs", arg0)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceBuildQueueWorkspaceIDs indicates an expected call of GetWorkspaceBuildQueueWorkspaceIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceBuildQueueWorkspaceIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceBuildQueueWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceBuildQueueWorkspaceIDs), arg0)
+}
+
 // GetWorkspaceBuildsByWorkspaceID mocks base method.
 func (m *MockStore) GetWorkspaceBuildsByWorkspaceID(arg0 context.Context, arg1 database.GetWorkspaceBuildsByWorkspaceIDParams) ([]database.WorkspaceBuild, error) {
 	m.ctrl.T.Helper()
@@ -1991,6 +2904,21 @@ func (mr *MockStoreMockRecorder) GetWorkspaceByOwnerIDAndName(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByOwnerIDAndName", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByOwnerIDAndName), arg0, arg1)
 }
 
+// GetWorkspaceByOwnerIDAndPreviousName mocks base method.
+func (m *MockStore) GetWorkspaceByOwnerIDAndPreviousName(arg0 context.Context, arg1 database.GetWorkspaceByOwnerIDAndPreviousNameParams) (database.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceByOwnerIDAndPreviousName", arg0, arg1)
+	ret0, _ := ret[0].(database.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceByOwnerIDAndPreviousName indicates an expected call of GetWorkspaceByOwnerIDAndPreviousName.
+func (mr *MockStoreMockRecorder) GetWorkspaceByOwnerIDAndPreviousName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByOwnerIDAndPreviousName", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByOwnerIDAndPreviousName), arg0, arg1)
+}
+
 // GetWorkspaceByWorkspaceAppID mocks base method.
 func (m *MockStore) GetWorkspaceByWorkspaceAppID(arg0 context.Context, arg1 uuid.UUID) (database.Workspace, error) {
 	m.ctrl.T.Helper()
@@ -2006,6 +2934,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceByWorkspaceAppID(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceByWorkspaceAppID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceByWorkspaceAppID), arg0, arg1)
 }
 
+// GetWorkspaceExternalMetadataByWorkspaceIDs mocks base method.
+func (m *MockStore) GetWorkspaceExternalMetadataByWorkspaceIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.GetWorkspaceExternalMetadataByWorkspaceIDsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceExternalMetadataByWorkspaceIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspaceExternalMetadataByWorkspaceIDsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceExternalMetadataByWorkspaceIDs indicates an expected call of GetWorkspaceExternalMetadataByWorkspaceIDs.
+func (mr *MockStoreMockRecorder) GetWorkspaceExternalMetadataByWorkspaceIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceExternalMetadataByWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspaceExternalMetadataByWorkspaceIDs), arg0, arg1)
+}
+
+// GetWorkspacePreviousNamesByWorkspaceIDs mocks base method.
+func (m *MockStore) GetWorkspacePreviousNamesByWorkspaceIDs(arg0 context.Context, arg1 []uuid.UUID) ([]database.GetWorkspacePreviousNamesByWorkspaceIDsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspacePreviousNamesByWorkspaceIDs", arg0, arg1)
+	ret0, _ := ret[0].([]database.GetWorkspacePreviousNamesByWorkspaceIDsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspacePreviousNamesByWorkspaceIDs indicates an expected call of GetWorkspacePreviousNamesByWorkspaceIDs.
+func (mr *MockStoreMockRecorder) GetWorkspacePreviousNamesByWorkspaceIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspacePreviousNamesByWorkspaceIDs", reflect.TypeOf((*MockStore)(nil).GetWorkspacePreviousNamesByWorkspaceIDs), arg0, arg1)
+}
+
 // GetWorkspaceProxies mocks base method.
 func (m *MockStore) GetWorkspaceProxies(arg0 context.Context) ([]database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -2156,6 +3114,36 @@ func (mr *MockStoreMockRecorder) GetWorkspaceResourcesCreatedAfter(arg0, arg1 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceResourcesCreatedAfter", reflect.TypeOf((*MockStore)(nil).GetWorkspaceResourcesCreatedAfter), arg0, arg1)
 }
 
+// GetWorkspaceWebhookByID mocks base method.
+func (m *MockStore) GetWorkspaceWebhookByID(arg0 context.Context, arg1 uuid.UUID) (database.WorkspaceWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceWebhookByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceWebhookByID indicates an expected call of GetWorkspaceWebhookByID.
+func (mr *MockStoreMockRecorder) GetWorkspaceWebhookByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceWebhookByID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceWebhookByID), arg0, arg1)
+}
+
+// GetWorkspaceWebhooksByWorkspaceID mocks base method.
+func (m *MockStore) GetWorkspaceWebhooksByWorkspaceID(arg0 context.Context, arg1 uuid.UUID) ([]database.WorkspaceWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceWebhooksByWorkspaceID", arg0, arg1)
+	ret0, _ := ret[0].([]database.WorkspaceWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceWebhooksByWorkspaceID indicates an expected call of GetWorkspaceWebhooksByWorkspaceID.
+func (mr *MockStoreMockRecorder) GetWorkspaceWebhooksByWorkspaceID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceWebhooksByWorkspaceID", reflect.TypeOf((*MockStore)(nil).GetWorkspaceWebhooksByWorkspaceID), arg0, arg1)
+}
+
 // GetWorkspaces mocks base method.
 func (m *MockStore) GetWorkspaces(arg0 context.Context, arg1 database.GetWorkspacesParams) ([]database.GetWorkspacesRow, error) {
 	m.ctrl.T.Helper()
@@ -2245,6 +3233,21 @@ func (mr *MockStoreMockRecorder) InsertAuditLog(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditLog", reflect.TypeOf((*MockStore)(nil).InsertAuditLog), arg0, arg1)
 }
 
+// InsertAuditLogArchival mocks base method.
+func (m *MockStore) InsertAuditLogArchival(arg0 context.Context, arg1 database.InsertAuditLogArchivalParams) (database.AuditLogArchival, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertAuditLogArchival", arg0, arg1)
+	ret0, _ := ret[0].(database.AuditLogArchival)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertAuditLogArchival indicates an expected call of InsertAuditLogArchival.
+func (mr *MockStoreMockRecorder) InsertAuditLogArchival(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditLogArchival", reflect.TypeOf((*MockStore)(nil).InsertAuditLogArchival), arg0, arg1)
+}
+
 // InsertDERPMeshKey mocks base method.
 func (m *MockStore) InsertDERPMeshKey(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -2273,6 +3276,20 @@ func (mr *MockStoreMockRecorder) InsertDeploymentID(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDeploymentID", reflect.TypeOf((*MockStore)(nil).InsertDeploymentID), arg0, arg1)
 }
 
+// InsertEntitlementSnapshot mocks base method.
+func (m *MockStore) InsertEntitlementSnapshot(arg0 context.Context, arg1 database.InsertEntitlementSnapshotParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertEntitlementSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertEntitlementSnapshot indicates an expected call of InsertEntitlementSnapshot.
+func (mr *MockStoreMockRecorder) InsertEntitlementSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertEntitlementSnapshot", reflect.TypeOf((*MockStore)(nil).InsertEntitlementSnapshot), arg0, arg1)
+}
+
 // InsertFile mocks base method.
 func (m *MockStore) InsertFile(arg0 context.Context, arg1 database.InsertFileParams) (database.File, error) {
 	m.ctrl.T.Helper()
@@ -2333,6 +3350,35 @@ func (mr *MockStoreMockRecorder) InsertGroup(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGroup", reflect.TypeOf((*MockStore)(nil).InsertGroup), arg0, arg1)
 }
 
+// InsertGroupDefaultTemplate mocks base method.
+func (m *MockStore) InsertGroupDefaultTemplate(arg0 context.Context, arg1 database.InsertGroupDefaultTemplateParams) (database.GroupDefaultTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertGroupDefaultTemplate", arg0, arg1)
+	ret0, _ := ret[0].(database.GroupDefaultTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertGroupDefaultTemplate indicates an expected call of InsertGroupDefaultTemplate.
+func (mr *MockStoreMockRecorder) InsertGroupDefaultTemplate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGroupDefaultTemplate", reflect.TypeOf((*MockStore)(nil).InsertGroupDefaultTemplate), arg0, arg1)
+}
+
+// InsertGroupDefaultTemplateWorkspace mocks base method.
+func (m *MockStore) InsertGroupDefaultTemplateWorkspace(arg0 context.Context, arg1 database.InsertGroupDefaultTemplateWorkspaceParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertGroupDefaultTemplateWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertGroupDefaultTemplateWorkspace indicates an expected call of InsertGroupDefaultTemplateWorkspace.
+func (mr *MockStoreMockRecorder) InsertGroupDefaultTemplateWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertGroupDefaultTemplateWorkspace", reflect.TypeOf((*MockStore)(nil).InsertGroupDefaultTemplateWorkspace), arg0, arg1)
+}
+
 // InsertGroupMember mocks base method.
 func (m *MockStore) InsertGroupMember(arg0 context.Context, arg1 database.InsertGroupMemberParams) error {
 	m.ctrl.T.Helper()
@@ -2362,6 +3408,21 @@ func (mr *MockStoreMockRecorder) InsertLicense(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertLicense", reflect.TypeOf((*MockStore)(nil).InsertLicense), arg0, arg1)
 }
 
+// InsertManagedEnvironmentVariable mocks base method.
+func (m *MockStore) InsertManagedEnvironmentVariable(arg0 context.Context, arg1 database.InsertManagedEnvironmentVariableParams) (database.ManagedEnvironmentVariable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertManagedEnvironmentVariable", arg0, arg1)
+	ret0, _ := ret[0].(database.ManagedEnvironmentVariable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertManagedEnvironmentVariable indicates an expected call of InsertManagedEnvironmentVariable.
+func (mr *MockStoreMockRecorder) InsertManagedEnvironmentVariable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertManagedEnvironmentVariable", reflect.TypeOf((*MockStore)(nil).InsertManagedEnvironmentVariable), arg0, arg1)
+}
+
 // InsertMissingGroups mocks base method.
 func (m *MockStore) InsertMissingGroups(arg0 context.Context, arg1 database.InsertMissingGroupsParams) ([]database.Group, error) {
 	m.ctrl.T.Helper()
@@ -2377,6 +3438,21 @@ func (mr *MockStoreMockRecorder) InsertMissingGroups(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMissingGroups", reflect.TypeOf((*MockStore)(nil).InsertMissingGroups), arg0, arg1)
 }
 
+// InsertNetcheckReport mocks base method.
+func (m *MockStore) InsertNetcheckReport(arg0 context.Context, arg1 database.InsertNetcheckReportParams) (database.NetcheckReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertNetcheckReport", arg0, arg1)
+	ret0, _ := ret[0].(database.NetcheckReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertNetcheckReport indicates an expected call of InsertNetcheckReport.
+func (mr *MockStoreMockRecorder) InsertNetcheckReport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertNetcheckReport", reflect.TypeOf((*MockStore)(nil).InsertNetcheckReport), arg0, arg1)
+}
+
 // InsertOrganization mocks base method.
 func (m *MockStore) InsertOrganization(arg0 context.Context, arg1 database.InsertOrganizationParams) (database.Organization, error) {
 	m.ctrl.T.Helper()
@@ -2407,6 +3483,21 @@ func (mr *MockStoreMockRecorder) InsertOrganizationMember(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOrganizationMember", reflect.TypeOf((*MockStore)(nil).InsertOrganizationMember), arg0, arg1)
 }
 
+// InsertPlatformEvent mocks base method.
+func (m *MockStore) InsertPlatformEvent(arg0 context.Context, arg1 database.InsertPlatformEventParams) (database.PlatformEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPlatformEvent", arg0, arg1)
+	ret0, _ := ret[0].(database.PlatformEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertPlatformEvent indicates an expected call of InsertPlatformEvent.
+func (mr *MockStoreMockRecorder) InsertPlatformEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPlatformEvent", reflect.TypeOf((*MockStore)(nil).InsertPlatformEvent), arg0, arg1)
+}
+
 // InsertProvisionerDaemon mocks base method.
 func (m *MockStore) InsertProvisionerDaemon(arg0 context.Context, arg1 database.InsertProvisionerDaemonParams) (database.ProvisionerDaemon, error) {
 	m.ctrl.T.Helper()
@@ -2437,6 +3528,21 @@ func (mr *MockStoreMockRecorder) InsertProvisionerJob(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJob", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJob), arg0, arg1)
 }
 
+// InsertProvisionerJobBuildHook mocks base method.
+func (m *MockStore) InsertProvisionerJobBuildHook(arg0 context.Context, arg1 database.InsertProvisionerJobBuildHookParams) (database.ProvisionerJobBuildHook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertProvisionerJobBuildHook", arg0, arg1)
+	ret0, _ := ret[0].(database.ProvisionerJobBuildHook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertProvisionerJobBuildHook indicates an expected call of InsertProvisionerJobBuildHook.
+func (mr *MockStoreMockRecorder) InsertProvisionerJobBuildHook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertProvisionerJobBuildHook", reflect.TypeOf((*MockStore)(nil).InsertProvisionerJobBuildHook), arg0, arg1)
+}
+
 // InsertProvisionerJobLogs mocks base method.
 func (m *MockStore) InsertProvisionerJobLogs(arg0 context.Context, arg1 database.InsertProvisionerJobLogsParams) ([]database.ProvisionerJobLog, error) {
 	m.ctrl.T.Helper()
@@ -2481,6 +3587,21 @@ func (mr *MockStoreMockRecorder) InsertTemplate(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplate", reflect.TypeOf((*MockStore)(nil).InsertTemplate), arg0, arg1)
 }
 
+// InsertTemplateAccessRequest mocks base method.
+func (m *MockStore) InsertTemplateAccessRequest(arg0 context.Context, arg1 database.InsertTemplateAccessRequestParams) (database.TemplateAccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateAccessRequest", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateAccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateAccessRequest indicates an expected call of InsertTemplateAccessRequest.
+func (mr *MockStoreMockRecorder) InsertTemplateAccessRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateAccessRequest", reflect.TypeOf((*MockStore)(nil).InsertTemplateAccessRequest), arg0, arg1)
+}
+
 // InsertTemplateVersion mocks base method.
 func (m *MockStore) InsertTemplateVersion(arg0 context.Context, arg1 database.InsertTemplateVersionParams) error {
 	m.ctrl.T.Helper()
@@ -2510,6 +3631,21 @@ func (mr *MockStoreMockRecorder) InsertTemplateVersionParameter(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionParameter", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionParameter), arg0, arg1)
 }
 
+// InsertTemplateVersionPromotion mocks base method.
+func (m *MockStore) InsertTemplateVersionPromotion(arg0 context.Context, arg1 database.InsertTemplateVersionPromotionParams) (database.TemplateVersionPromotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTemplateVersionPromotion", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateVersionPromotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTemplateVersionPromotion indicates an expected call of InsertTemplateVersionPromotion.
+func (mr *MockStoreMockRecorder) InsertTemplateVersionPromotion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTemplateVersionPromotion", reflect.TypeOf((*MockStore)(nil).InsertTemplateVersionPromotion), arg0, arg1)
+}
+
 // InsertTemplateVersionVariable mocks base method.
 func (m *MockStore) InsertTemplateVersionVariable(arg0 context.Context, arg1 database.InsertTemplateVersionVariableParams) (database.TemplateVersionVariable, error) {
 	m.ctrl.T.Helper()
@@ -2540,6 +3676,20 @@ func (mr *MockStoreMockRecorder) InsertUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUser", reflect.TypeOf((*MockStore)(nil).InsertUser), arg0, arg1)
 }
 
+// InsertUserDeprovision mocks base method.
+func (m *MockStore) InsertUserDeprovision(arg0 context.Context, arg1 database.InsertUserDeprovisionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertUserDeprovision", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertUserDeprovision indicates an expected call of InsertUserDeprovision.
+func (mr *MockStoreMockRecorder) InsertUserDeprovision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserDeprovision", reflect.TypeOf((*MockStore)(nil).InsertUserDeprovision), arg0, arg1)
+}
+
 // InsertUserGroupsByName mocks base method.
 func (m *MockStore) InsertUserGroupsByName(arg0 context.Context, arg1 database.InsertUserGroupsByNameParams) error {
 	m.ctrl.T.Helper()
@@ -2566,37 +3716,81 @@ func (m *MockStore) InsertUserLink(arg0 context.Context, arg1 database.InsertUse
 // InsertUserLink indicates an expected call of InsertUserLink.
 func (mr *MockStoreMockRecorder) InsertUserLink(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLink", reflect.TypeOf((*MockStore)(nil).InsertUserLink), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertUserLink", reflect.TypeOf((*MockStore)(nil).InsertUserLink), arg0, arg1)
+}
+
+// InsertWorkspace mocks base method.
+func (m *MockStore) InsertWorkspace(arg0 context.Context, arg1 database.InsertWorkspaceParams) (database.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(database.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspace indicates an expected call of InsertWorkspace.
+func (mr *MockStoreMockRecorder) InsertWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspace", reflect.TypeOf((*MockStore)(nil).InsertWorkspace), arg0, arg1)
+}
+
+// InsertWorkspaceAgent mocks base method.
+func (m *MockStore) InsertWorkspaceAgent(arg0 context.Context, arg1 database.InsertWorkspaceAgentParams) (database.WorkspaceAgent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgent", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgent indicates an expected call of InsertWorkspaceAgent.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgent", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgent), arg0, arg1)
+}
+
+// InsertWorkspaceAgentAuthTokenRotation mocks base method.
+func (m *MockStore) InsertWorkspaceAgentAuthTokenRotation(arg0 context.Context, arg1 database.InsertWorkspaceAgentAuthTokenRotationParams) (database.WorkspaceAgentAuthTokenRotation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentAuthTokenRotation", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgentAuthTokenRotation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgentAuthTokenRotation indicates an expected call of InsertWorkspaceAgentAuthTokenRotation.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentAuthTokenRotation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentAuthTokenRotation", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentAuthTokenRotation), arg0, arg1)
 }
 
-// InsertWorkspace mocks base method.
-func (m *MockStore) InsertWorkspace(arg0 context.Context, arg1 database.InsertWorkspaceParams) (database.Workspace, error) {
+// InsertWorkspaceAgentBandwidthStats mocks base method.
+func (m *MockStore) InsertWorkspaceAgentBandwidthStats(arg0 context.Context, arg1 database.InsertWorkspaceAgentBandwidthStatsParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertWorkspace", arg0, arg1)
-	ret0, _ := ret[0].(database.Workspace)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentBandwidthStats", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// InsertWorkspace indicates an expected call of InsertWorkspace.
-func (mr *MockStoreMockRecorder) InsertWorkspace(arg0, arg1 interface{}) *gomock.Call {
+// InsertWorkspaceAgentBandwidthStats indicates an expected call of InsertWorkspaceAgentBandwidthStats.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentBandwidthStats(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspace", reflect.TypeOf((*MockStore)(nil).InsertWorkspace), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentBandwidthStats", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentBandwidthStats), arg0, arg1)
 }
 
-// InsertWorkspaceAgent mocks base method.
-func (m *MockStore) InsertWorkspaceAgent(arg0 context.Context, arg1 database.InsertWorkspaceAgentParams) (database.WorkspaceAgent, error) {
+// InsertWorkspaceAgentClientConnection mocks base method.
+func (m *MockStore) InsertWorkspaceAgentClientConnection(arg0 context.Context, arg1 database.InsertWorkspaceAgentClientConnectionParams) (database.WorkspaceAgentClientConnection, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InsertWorkspaceAgent", arg0, arg1)
-	ret0, _ := ret[0].(database.WorkspaceAgent)
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentClientConnection", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgentClientConnection)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// InsertWorkspaceAgent indicates an expected call of InsertWorkspaceAgent.
-func (mr *MockStoreMockRecorder) InsertWorkspaceAgent(arg0, arg1 interface{}) *gomock.Call {
+// InsertWorkspaceAgentClientConnection indicates an expected call of InsertWorkspaceAgentClientConnection.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentClientConnection(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgent", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgent), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentClientConnection", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentClientConnection), arg0, arg1)
 }
 
 // InsertWorkspaceAgentLogs mocks base method.
@@ -2628,6 +3822,35 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAgentMetadata(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentMetadata), arg0, arg1)
 }
 
+// InsertWorkspaceAgentMetadataHistory mocks base method.
+func (m *MockStore) InsertWorkspaceAgentMetadataHistory(arg0 context.Context, arg1 database.InsertWorkspaceAgentMetadataHistoryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentMetadataHistory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertWorkspaceAgentMetadataHistory indicates an expected call of InsertWorkspaceAgentMetadataHistory.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentMetadataHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentMetadataHistory", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentMetadataHistory), arg0, arg1)
+}
+
+// InsertWorkspaceAgentSSHHostKey mocks base method.
+func (m *MockStore) InsertWorkspaceAgentSSHHostKey(arg0 context.Context, arg1 database.InsertWorkspaceAgentSSHHostKeyParams) (database.WorkspaceAgentSSHHostKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceAgentSSHHostKey", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceAgentSSHHostKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceAgentSSHHostKey indicates an expected call of InsertWorkspaceAgentSSHHostKey.
+func (mr *MockStoreMockRecorder) InsertWorkspaceAgentSSHHostKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAgentSSHHostKey", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAgentSSHHostKey), arg0, arg1)
+}
+
 // InsertWorkspaceAgentStat mocks base method.
 func (m *MockStore) InsertWorkspaceAgentStat(arg0 context.Context, arg1 database.InsertWorkspaceAgentStatParams) (database.WorkspaceAgentStat, error) {
 	m.ctrl.T.Helper()
@@ -2686,6 +3909,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceAppStats(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceAppStats", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceAppStats), arg0, arg1)
 }
 
+// InsertWorkspaceApproval mocks base method.
+func (m *MockStore) InsertWorkspaceApproval(arg0 context.Context, arg1 database.InsertWorkspaceApprovalParams) (database.WorkspaceApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceApproval", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceApproval indicates an expected call of InsertWorkspaceApproval.
+func (mr *MockStoreMockRecorder) InsertWorkspaceApproval(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceApproval", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceApproval), arg0, arg1)
+}
+
 // InsertWorkspaceBuild mocks base method.
 func (m *MockStore) InsertWorkspaceBuild(arg0 context.Context, arg1 database.InsertWorkspaceBuildParams) error {
 	m.ctrl.T.Helper()
@@ -2714,6 +3952,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceBuildParameters(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildParameters", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildParameters), arg0, arg1)
 }
 
+// InsertWorkspaceBuildQueueEntry mocks base method.
+func (m *MockStore) InsertWorkspaceBuildQueueEntry(arg0 context.Context, arg1 database.InsertWorkspaceBuildQueueEntryParams) (database.WorkspaceBuildQueueEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceBuildQueueEntry", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceBuildQueueEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceBuildQueueEntry indicates an expected call of InsertWorkspaceBuildQueueEntry.
+func (mr *MockStoreMockRecorder) InsertWorkspaceBuildQueueEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceBuildQueueEntry", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceBuildQueueEntry), arg0, arg1)
+}
+
 // InsertWorkspaceProxy mocks base method.
 func (m *MockStore) InsertWorkspaceProxy(arg0 context.Context, arg1 database.InsertWorkspaceProxyParams) (database.WorkspaceProxy, error) {
 	m.ctrl.T.Helper()
@@ -2759,6 +4012,21 @@ func (mr *MockStoreMockRecorder) InsertWorkspaceResourceMetadata(arg0, arg1 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceResourceMetadata", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceResourceMetadata), arg0, arg1)
 }
 
+// InsertWorkspaceWebhook mocks base method.
+func (m *MockStore) InsertWorkspaceWebhook(arg0 context.Context, arg1 database.InsertWorkspaceWebhookParams) (database.WorkspaceWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWorkspaceWebhook", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWorkspaceWebhook indicates an expected call of InsertWorkspaceWebhook.
+func (mr *MockStoreMockRecorder) InsertWorkspaceWebhook(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWorkspaceWebhook", reflect.TypeOf((*MockStore)(nil).InsertWorkspaceWebhook), arg0, arg1)
+}
+
 // Ping mocks base method.
 func (m *MockStore) Ping(arg0 context.Context) (time.Duration, error) {
 	m.ctrl.T.Helper()
@@ -2818,6 +4086,21 @@ func (mr *MockStoreMockRecorder) UpdateAPIKeyByID(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAPIKeyByID", reflect.TypeOf((*MockStore)(nil).UpdateAPIKeyByID), arg0, arg1)
 }
 
+// UpdateAuditLogArchivalByID mocks base method.
+func (m *MockStore) UpdateAuditLogArchivalByID(arg0 context.Context, arg1 database.UpdateAuditLogArchivalByIDParams) (database.AuditLogArchival, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuditLogArchivalByID", arg0, arg1)
+	ret0, _ := ret[0].(database.AuditLogArchival)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAuditLogArchivalByID indicates an expected call of UpdateAuditLogArchivalByID.
+func (mr *MockStoreMockRecorder) UpdateAuditLogArchivalByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuditLogArchivalByID", reflect.TypeOf((*MockStore)(nil).UpdateAuditLogArchivalByID), arg0, arg1)
+}
+
 // UpdateGitAuthLink mocks base method.
 func (m *MockStore) UpdateGitAuthLink(arg0 context.Context, arg1 database.UpdateGitAuthLinkParams) (database.GitAuthLink, error) {
 	m.ctrl.T.Helper()
@@ -2848,6 +4131,21 @@ func (mr *MockStoreMockRecorder) UpdateGitSSHKey(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGitSSHKey", reflect.TypeOf((*MockStore)(nil).UpdateGitSSHKey), arg0, arg1)
 }
 
+// UpdateGroupAvatarByID mocks base method.
+func (m *MockStore) UpdateGroupAvatarByID(arg0 context.Context, arg1 database.UpdateGroupAvatarByIDParams) (database.Group, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGroupAvatarByID", arg0, arg1)
+	ret0, _ := ret[0].(database.Group)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateGroupAvatarByID indicates an expected call of UpdateGroupAvatarByID.
+func (mr *MockStoreMockRecorder) UpdateGroupAvatarByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGroupAvatarByID", reflect.TypeOf((*MockStore)(nil).UpdateGroupAvatarByID), arg0, arg1)
+}
+
 // UpdateGroupByID mocks base method.
 func (m *MockStore) UpdateGroupByID(arg0 context.Context, arg1 database.UpdateGroupByIDParams) (database.Group, error) {
 	m.ctrl.T.Helper()
@@ -2878,6 +4176,21 @@ func (mr *MockStoreMockRecorder) UpdateInactiveUsersToDormant(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInactiveUsersToDormant", reflect.TypeOf((*MockStore)(nil).UpdateInactiveUsersToDormant), arg0, arg1)
 }
 
+// UpdateManagedEnvironmentVariableByID mocks base method.
+func (m *MockStore) UpdateManagedEnvironmentVariableByID(arg0 context.Context, arg1 database.UpdateManagedEnvironmentVariableByIDParams) (database.ManagedEnvironmentVariable, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateManagedEnvironmentVariableByID", arg0, arg1)
+	ret0, _ := ret[0].(database.ManagedEnvironmentVariable)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateManagedEnvironmentVariableByID indicates an expected call of UpdateManagedEnvironmentVariableByID.
+func (mr *MockStoreMockRecorder) UpdateManagedEnvironmentVariableByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateManagedEnvironmentVariableByID", reflect.TypeOf((*MockStore)(nil).UpdateManagedEnvironmentVariableByID), arg0, arg1)
+}
+
 // UpdateMemberRoles mocks base method.
 func (m *MockStore) UpdateMemberRoles(arg0 context.Context, arg1 database.UpdateMemberRolesParams) (database.OrganizationMember, error) {
 	m.ctrl.T.Helper()
@@ -2893,6 +4206,21 @@ func (mr *MockStoreMockRecorder) UpdateMemberRoles(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRoles", reflect.TypeOf((*MockStore)(nil).UpdateMemberRoles), arg0, arg1)
 }
 
+// UpdateOrganizationProvisionerLimits mocks base method.
+func (m *MockStore) UpdateOrganizationProvisionerLimits(arg0 context.Context, arg1 database.UpdateOrganizationProvisionerLimitsParams) (database.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrganizationProvisionerLimits", arg0, arg1)
+	ret0, _ := ret[0].(database.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrganizationProvisionerLimits indicates an expected call of UpdateOrganizationProvisionerLimits.
+func (mr *MockStoreMockRecorder) UpdateOrganizationProvisionerLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrganizationProvisionerLimits", reflect.TypeOf((*MockStore)(nil).UpdateOrganizationProvisionerLimits), arg0, arg1)
+}
+
 // UpdateProvisionerJobByID mocks base method.
 func (m *MockStore) UpdateProvisionerJobByID(arg0 context.Context, arg1 database.UpdateProvisionerJobByIDParams) error {
 	m.ctrl.T.Helper()
@@ -2935,6 +4263,20 @@ func (mr *MockStoreMockRecorder) UpdateProvisionerJobWithCompleteByID(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerJobWithCompleteByID", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerJobWithCompleteByID), arg0, arg1)
 }
 
+// UpdateProvisionerJobWithRequeueByID mocks base method.
+func (m *MockStore) UpdateProvisionerJobWithRequeueByID(arg0 context.Context, arg1 database.UpdateProvisionerJobWithRequeueByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProvisionerJobWithRequeueByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProvisionerJobWithRequeueByID indicates an expected call of UpdateProvisionerJobWithRequeueByID.
+func (mr *MockStoreMockRecorder) UpdateProvisionerJobWithRequeueByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProvisionerJobWithRequeueByID", reflect.TypeOf((*MockStore)(nil).UpdateProvisionerJobWithRequeueByID), arg0, arg1)
+}
+
 // UpdateReplica mocks base method.
 func (m *MockStore) UpdateReplica(arg0 context.Context, arg1 database.UpdateReplicaParams) (database.Replica, error) {
 	m.ctrl.T.Helper()
@@ -2950,6 +4292,21 @@ func (mr *MockStoreMockRecorder) UpdateReplica(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReplica", reflect.TypeOf((*MockStore)(nil).UpdateReplica), arg0, arg1)
 }
 
+// UpdateReplicaDrainingAt mocks base method.
+func (m *MockStore) UpdateReplicaDrainingAt(arg0 context.Context, arg1 database.UpdateReplicaDrainingAtParams) (database.Replica, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReplicaDrainingAt", arg0, arg1)
+	ret0, _ := ret[0].(database.Replica)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateReplicaDrainingAt indicates an expected call of UpdateReplicaDrainingAt.
+func (mr *MockStoreMockRecorder) UpdateReplicaDrainingAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReplicaDrainingAt", reflect.TypeOf((*MockStore)(nil).UpdateReplicaDrainingAt), arg0, arg1)
+}
+
 // UpdateTemplateACLByID mocks base method.
 func (m *MockStore) UpdateTemplateACLByID(arg0 context.Context, arg1 database.UpdateTemplateACLByIDParams) error {
 	m.ctrl.T.Helper()
@@ -2964,6 +4321,21 @@ func (mr *MockStoreMockRecorder) UpdateTemplateACLByID(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateACLByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateACLByID), arg0, arg1)
 }
 
+// UpdateTemplateAccessRequestStatusByID mocks base method.
+func (m *MockStore) UpdateTemplateAccessRequestStatusByID(arg0 context.Context, arg1 database.UpdateTemplateAccessRequestStatusByIDParams) (database.TemplateAccessRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateAccessRequestStatusByID", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateAccessRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplateAccessRequestStatusByID indicates an expected call of UpdateTemplateAccessRequestStatusByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateAccessRequestStatusByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateAccessRequestStatusByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateAccessRequestStatusByID), arg0, arg1)
+}
+
 // UpdateTemplateActiveVersionByID mocks base method.
 func (m *MockStore) UpdateTemplateActiveVersionByID(arg0 context.Context, arg1 database.UpdateTemplateActiveVersionByIDParams) error {
 	m.ctrl.T.Helper()
@@ -3062,6 +4434,21 @@ func (mr *MockStoreMockRecorder) UpdateTemplateVersionGitAuthProvidersByJobID(ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionGitAuthProvidersByJobID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionGitAuthProvidersByJobID), arg0, arg1)
 }
 
+// UpdateTemplateVersionPromotionStatusByID mocks base method.
+func (m *MockStore) UpdateTemplateVersionPromotionStatusByID(arg0 context.Context, arg1 database.UpdateTemplateVersionPromotionStatusByIDParams) (database.TemplateVersionPromotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplateVersionPromotionStatusByID", arg0, arg1)
+	ret0, _ := ret[0].(database.TemplateVersionPromotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplateVersionPromotionStatusByID indicates an expected call of UpdateTemplateVersionPromotionStatusByID.
+func (mr *MockStoreMockRecorder) UpdateTemplateVersionPromotionStatusByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplateVersionPromotionStatusByID", reflect.TypeOf((*MockStore)(nil).UpdateTemplateVersionPromotionStatusByID), arg0, arg1)
+}
+
 // UpdateTemplateWorkspacesLastUsedAt mocks base method.
 func (m *MockStore) UpdateTemplateWorkspacesLastUsedAt(arg0 context.Context, arg1 database.UpdateTemplateWorkspacesLastUsedAtParams) error {
 	m.ctrl.T.Helper()
@@ -3239,6 +4626,48 @@ func (mr *MockStoreMockRecorder) UpdateWorkspace(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspace", reflect.TypeOf((*MockStore)(nil).UpdateWorkspace), arg0, arg1)
 }
 
+// UpdateWorkspaceAgentAuthTokenByID mocks base method.
+func (m *MockStore) UpdateWorkspaceAgentAuthTokenByID(arg0 context.Context, arg1 database.UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceAgentAuthTokenByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceAgentAuthTokenByID indicates an expected call of UpdateWorkspaceAgentAuthTokenByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceAgentAuthTokenByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAgentAuthTokenByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAgentAuthTokenByID), arg0, arg1)
+}
+
+// UpdateWorkspaceAgentClientConnectionDisconnectedAt mocks base method.
+func (m *MockStore) UpdateWorkspaceAgentClientConnectionDisconnectedAt(arg0 context.Context, arg1 database.UpdateWorkspaceAgentClientConnectionDisconnectedAtParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceAgentClientConnectionDisconnectedAt", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceAgentClientConnectionDisconnectedAt indicates an expected call of UpdateWorkspaceAgentClientConnectionDisconnectedAt.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceAgentClientConnectionDisconnectedAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAgentClientConnectionDisconnectedAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAgentClientConnectionDisconnectedAt), arg0, arg1)
+}
+
+// UpdateWorkspaceAgentClockOffsetByID mocks base method.
+func (m *MockStore) UpdateWorkspaceAgentClockOffsetByID(arg0 context.Context, arg1 database.UpdateWorkspaceAgentClockOffsetByIDParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceAgentClockOffsetByID", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceAgentClockOffsetByID indicates an expected call of UpdateWorkspaceAgentClockOffsetByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceAgentClockOffsetByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAgentClockOffsetByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAgentClockOffsetByID), arg0, arg1)
+}
+
 // UpdateWorkspaceAgentConnectionByID mocks base method.
 func (m *MockStore) UpdateWorkspaceAgentConnectionByID(arg0 context.Context, arg1 database.UpdateWorkspaceAgentConnectionByIDParams) error {
 	m.ctrl.T.Helper()
@@ -3323,6 +4752,35 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceAppHealthByID(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAppHealthByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAppHealthByID), arg0, arg1)
 }
 
+// UpdateWorkspaceApprovalStatusByID mocks base method.
+func (m *MockStore) UpdateWorkspaceApprovalStatusByID(arg0 context.Context, arg1 database.UpdateWorkspaceApprovalStatusByIDParams) (database.WorkspaceApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceApprovalStatusByID", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceApprovalStatusByID indicates an expected call of UpdateWorkspaceApprovalStatusByID.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceApprovalStatusByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceApprovalStatusByID", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceApprovalStatusByID), arg0, arg1)
+}
+
+// UpdateWorkspaceAutomaticUpdates mocks base method.
+func (m *MockStore) UpdateWorkspaceAutomaticUpdates(arg0 context.Context, arg1 database.UpdateWorkspaceAutomaticUpdatesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceAutomaticUpdates", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWorkspaceAutomaticUpdates indicates an expected call of UpdateWorkspaceAutomaticUpdates.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceAutomaticUpdates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceAutomaticUpdates", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceAutomaticUpdates), arg0, arg1)
+}
+
 // UpdateWorkspaceAutostart mocks base method.
 func (m *MockStore) UpdateWorkspaceAutostart(arg0 context.Context, arg1 database.UpdateWorkspaceAutostartParams) error {
 	m.ctrl.T.Helper()
@@ -3423,6 +4881,21 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceProxy(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceProxy", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceProxy), arg0, arg1)
 }
 
+// UpdateWorkspaceProxyACL mocks base method.
+func (m *MockStore) UpdateWorkspaceProxyACL(arg0 context.Context, arg1 database.UpdateWorkspaceProxyACLParams) (database.WorkspaceProxy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceProxyACL", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceProxy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceProxyACL indicates an expected call of UpdateWorkspaceProxyACL.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceProxyACL(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceProxyACL", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceProxyACL), arg0, arg1)
+}
+
 // UpdateWorkspaceProxyDeleted mocks base method.
 func (m *MockStore) UpdateWorkspaceProxyDeleted(arg0 context.Context, arg1 database.UpdateWorkspaceProxyDeletedParams) error {
 	m.ctrl.T.Helper()
@@ -3451,6 +4924,21 @@ func (mr *MockStoreMockRecorder) UpdateWorkspaceTTL(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceTTL", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceTTL), arg0, arg1)
 }
 
+// UpdateWorkspaceWebhookLastTriggeredAt mocks base method.
+func (m *MockStore) UpdateWorkspaceWebhookLastTriggeredAt(arg0 context.Context, arg1 database.UpdateWorkspaceWebhookLastTriggeredAtParams) (database.WorkspaceWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWorkspaceWebhookLastTriggeredAt", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWorkspaceWebhookLastTriggeredAt indicates an expected call of UpdateWorkspaceWebhookLastTriggeredAt.
+func (mr *MockStoreMockRecorder) UpdateWorkspaceWebhookLastTriggeredAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWorkspaceWebhookLastTriggeredAt", reflect.TypeOf((*MockStore)(nil).UpdateWorkspaceWebhookLastTriggeredAt), arg0, arg1)
+}
+
 // UpdateWorkspacesLockedDeletingAtByTemplateID mocks base method.
 func (m *MockStore) UpdateWorkspacesLockedDeletingAtByTemplateID(arg0 context.Context, arg1 database.UpdateWorkspacesLockedDeletingAtByTemplateIDParams) error {
 	m.ctrl.T.Helper()
@@ -3507,6 +4995,20 @@ func (mr *MockStoreMockRecorder) UpsertLastUpdateCheck(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLastUpdateCheck", reflect.TypeOf((*MockStore)(nil).UpsertLastUpdateCheck), arg0, arg1)
 }
 
+// UpsertLicenseUsage mocks base method.
+func (m *MockStore) UpsertLicenseUsage(arg0 context.Context, arg1 database.UpsertLicenseUsageParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertLicenseUsage", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertLicenseUsage indicates an expected call of UpsertLicenseUsage.
+func (mr *MockStoreMockRecorder) UpsertLicenseUsage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLicenseUsage", reflect.TypeOf((*MockStore)(nil).UpsertLicenseUsage), arg0, arg1)
+}
+
 // UpsertLogoURL mocks base method.
 func (m *MockStore) UpsertLogoURL(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -3535,6 +5037,34 @@ func (mr *MockStoreMockRecorder) UpsertOAuthSigningKey(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOAuthSigningKey", reflect.TypeOf((*MockStore)(nil).UpsertOAuthSigningKey), arg0, arg1)
 }
 
+// UpsertRuntimeExperiments mocks base method.
+func (m *MockStore) UpsertRuntimeExperiments(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertRuntimeExperiments", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertRuntimeExperiments indicates an expected call of UpsertRuntimeExperiments.
+func (mr *MockStoreMockRecorder) UpsertRuntimeExperiments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRuntimeExperiments", reflect.TypeOf((*MockStore)(nil).UpsertRuntimeExperiments), arg0, arg1)
+}
+
+// UpsertSSHCAKey mocks base method.
+func (m *MockStore) UpsertSSHCAKey(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertSSHCAKey", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertSSHCAKey indicates an expected call of UpsertSSHCAKey.
+func (mr *MockStoreMockRecorder) UpsertSSHCAKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertSSHCAKey", reflect.TypeOf((*MockStore)(nil).UpsertSSHCAKey), arg0, arg1)
+}
+
 // UpsertServiceBanner mocks base method.
 func (m *MockStore) UpsertServiceBanner(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -3594,6 +5124,93 @@ func (mr *MockStoreMockRecorder) UpsertTailnetCoordinator(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTailnetCoordinator", reflect.TypeOf((*MockStore)(nil).UpsertTailnetCoordinator), arg0, arg1)
 }
 
+// UpsertTemplateVersionBuildInsights mocks base method.
+func (m *MockStore) UpsertTemplateVersionBuildInsights(arg0 context.Context, arg1 database.UpsertTemplateVersionBuildInsightsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTemplateVersionBuildInsights", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertTemplateVersionBuildInsights indicates an expected call of UpsertTemplateVersionBuildInsights.
+func (mr *MockStoreMockRecorder) UpsertTemplateVersionBuildInsights(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTemplateVersionBuildInsights", reflect.TypeOf((*MockStore)(nil).UpsertTemplateVersionBuildInsights), arg0, arg1)
+}
+
+// UpsertUserPTYSessionOverride mocks base method.
+func (m *MockStore) UpsertUserPTYSessionOverride(arg0 context.Context, arg1 database.UpsertUserPTYSessionOverrideParams) (database.UserPTYSessionOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserPTYSessionOverride", arg0, arg1)
+	ret0, _ := ret[0].(database.UserPTYSessionOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserPTYSessionOverride indicates an expected call of UpsertUserPTYSessionOverride.
+func (mr *MockStoreMockRecorder) UpsertUserPTYSessionOverride(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserPTYSessionOverride", reflect.TypeOf((*MockStore)(nil).UpsertUserPTYSessionOverride), arg0, arg1)
+}
+
+// UpsertUserQuotaOverride mocks base method.
+func (m *MockStore) UpsertUserQuotaOverride(arg0 context.Context, arg1 database.UpsertUserQuotaOverrideParams) (database.UserQuotaOverride, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserQuotaOverride", arg0, arg1)
+	ret0, _ := ret[0].(database.UserQuotaOverride)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserQuotaOverride indicates an expected call of UpsertUserQuotaOverride.
+func (mr *MockStoreMockRecorder) UpsertUserQuotaOverride(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserQuotaOverride", reflect.TypeOf((*MockStore)(nil).UpsertUserQuotaOverride), arg0, arg1)
+}
+
+// UpsertUserRegionLatency mocks base method.
+func (m *MockStore) UpsertUserRegionLatency(arg0 context.Context, arg1 database.UpsertUserRegionLatencyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserRegionLatency", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertUserRegionLatency indicates an expected call of UpsertUserRegionLatency.
+func (mr *MockStoreMockRecorder) UpsertUserRegionLatency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserRegionLatency", reflect.TypeOf((*MockStore)(nil).UpsertUserRegionLatency), arg0, arg1)
+}
+
+// UpsertWorkspaceExternalMetadatum mocks base method.
+func (m *MockStore) UpsertWorkspaceExternalMetadatum(arg0 context.Context, arg1 database.UpsertWorkspaceExternalMetadatumParams) (database.WorkspaceExternalMetadatum, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspaceExternalMetadatum", arg0, arg1)
+	ret0, _ := ret[0].(database.WorkspaceExternalMetadatum)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertWorkspaceExternalMetadatum indicates an expected call of UpsertWorkspaceExternalMetadatum.
+func (mr *MockStoreMockRecorder) UpsertWorkspaceExternalMetadatum(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspaceExternalMetadatum", reflect.TypeOf((*MockStore)(nil).UpsertWorkspaceExternalMetadatum), arg0, arg1)
+}
+
+// UpsertWorkspacePreviousName mocks base method.
+func (m *MockStore) UpsertWorkspacePreviousName(arg0 context.Context, arg1 database.UpsertWorkspacePreviousNameParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertWorkspacePreviousName", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertWorkspacePreviousName indicates an expected call of UpsertWorkspacePreviousName.
+func (mr *MockStoreMockRecorder) UpsertWorkspacePreviousName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertWorkspacePreviousName", reflect.TypeOf((*MockStore)(nil).UpsertWorkspacePreviousName), arg0, arg1)
+}
+
 // Wrappers mocks base method.
 func (m *MockStore) Wrappers() []string {
 	m.ctrl.T.Helper()
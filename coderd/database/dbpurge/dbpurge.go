@@ -45,6 +45,21 @@ func New(ctx context.Context, logger slog.Logger, db database.Store) io.Closer {
 			eg.Go(func() error {
 				return db.DeleteOldWorkspaceAgentStats(ctx)
 			})
+			eg.Go(func() error {
+				return db.DeleteOldWorkspaceAgentBandwidthStats(ctx)
+			})
+			eg.Go(func() error {
+				return db.DeleteOldWorkspaceAgentClientConnections(ctx)
+			})
+			eg.Go(func() error {
+				return db.DeleteOldWorkspaceAgentAuthTokenRotations(ctx)
+			})
+			eg.Go(func() error {
+				return db.DeleteOldPlatformEvents(ctx)
+			})
+			eg.Go(func() error {
+				return db.DeleteOldNetcheckReports(ctx)
+			})
 			err := eg.Wait()
 			if err != nil {
 				if errors.Is(err, context.Canceled) {
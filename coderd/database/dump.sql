@@ -19,7 +19,26 @@ CREATE TYPE audit_action AS ENUM (
     'stop',
     'login',
     'logout',
-    'register'
+    'register',
+    'open',
+    'connect'
+);
+
+CREATE TYPE audit_log_archival_status AS ENUM (
+    'running',
+    'succeeded',
+    'failed'
+);
+
+CREATE TYPE automatic_updates AS ENUM (
+    'always',
+    'never'
+);
+
+CREATE TYPE build_hook_phase AS ENUM (
+    'pre',
+    'post',
+    'clone'
 );
 
 CREATE TYPE build_reason AS ENUM (
@@ -28,7 +47,9 @@ CREATE TYPE build_reason AS ENUM (
     'autostop',
     'autolock',
     'failedstop',
-    'autodelete'
+    'autodelete',
+    'bulk',
+    'webhook'
 );
 
 CREATE TYPE group_source AS ENUM (
@@ -81,6 +102,11 @@ CREATE TYPE parameter_type_system AS ENUM (
     'hcl'
 );
 
+CREATE TYPE provisioner_job_cancel_outcome AS ENUM (
+    'graceful',
+    'forced'
+);
+
 CREATE TYPE provisioner_job_type AS ENUM (
     'template_version_import',
     'workspace_build',
@@ -108,7 +134,13 @@ CREATE TYPE resource_type AS ENUM (
     'workspace_build',
     'license',
     'workspace_proxy',
-    'convert_login'
+    'convert_login',
+    'workspace_webhook',
+    'workspace_approval',
+    'environment_variable',
+    'template_access_request',
+    'template_version_promotion',
+    'workspace_agent_auth_token'
 );
 
 CREATE TYPE startup_script_behavior AS ENUM (
@@ -116,6 +148,18 @@ CREATE TYPE startup_script_behavior AS ENUM (
     'non-blocking'
 );
 
+CREATE TYPE template_access_request_status AS ENUM (
+    'pending',
+    'approved',
+    'denied'
+);
+
+CREATE TYPE template_version_promotion_status AS ENUM (
+    'pending',
+    'approved',
+    'rejected'
+);
+
 CREATE TYPE user_status AS ENUM (
     'active',
     'suspended',
@@ -159,12 +203,30 @@ CREATE TYPE workspace_app_health AS ENUM (
     'unhealthy'
 );
 
+CREATE TYPE workspace_approval_status AS ENUM (
+    'pending',
+    'approved',
+    'rejected'
+);
+
+CREATE TYPE workspace_external_metadata_visibility AS ENUM (
+    'public',
+    'private'
+);
+
 CREATE TYPE workspace_transition AS ENUM (
     'start',
     'stop',
     'delete'
 );
 
+CREATE TYPE workspace_webhook_action AS ENUM (
+    'start',
+    'stop',
+    'update',
+    'exec'
+);
+
 CREATE FUNCTION delete_deleted_user_api_keys() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -193,6 +255,45 @@ BEGIN
 END;
 $$;
 
+CREATE FUNCTION provisioner_job_concurrency_limit(job_id uuid) RETURNS text
+    LANGUAGE sql STABLE
+    AS $$
+	WITH job AS (
+		SELECT organization_id, provisioner_job_template_id(id) AS template_id FROM provisioner_jobs WHERE id = $1
+	), running AS (
+		SELECT
+			provisioner_jobs.organization_id,
+			COALESCE(template_versions.template_id, workspaces.template_id) AS template_id
+		FROM provisioner_jobs
+		LEFT JOIN template_versions ON template_versions.job_id = provisioner_jobs.id
+		LEFT JOIN workspace_builds ON workspace_builds.job_id = provisioner_jobs.id
+		LEFT JOIN workspaces ON workspaces.id = workspace_builds.workspace_id
+		WHERE provisioner_jobs.started_at IS NOT NULL AND provisioner_jobs.completed_at IS NULL AND provisioner_jobs.id != $1
+	)
+	SELECT CASE
+		WHEN EXISTS (
+			SELECT 1 FROM job JOIN templates ON templates.id = job.template_id
+			WHERE templates.max_concurrent_provisioner_jobs > 0
+				AND templates.max_concurrent_provisioner_jobs <= (SELECT COUNT(*) FROM running WHERE running.template_id = job.template_id)
+		) THEN 'template'
+		WHEN EXISTS (
+			SELECT 1 FROM job JOIN organizations ON organizations.id = job.organization_id
+			WHERE organizations.max_concurrent_provisioner_jobs > 0
+				AND organizations.max_concurrent_provisioner_jobs <= (SELECT COUNT(*) FROM running WHERE running.organization_id = job.organization_id)
+		) THEN 'organization'
+		ELSE ''
+	END
+$$;
+
+CREATE FUNCTION provisioner_job_template_id(job_id uuid) RETURNS uuid
+    LANGUAGE sql STABLE
+    AS $$
+	SELECT template_versions.template_id FROM template_versions WHERE template_versions.job_id = $1
+	UNION ALL
+	SELECT workspaces.template_id FROM workspace_builds JOIN workspaces ON workspaces.id = workspace_builds.workspace_id WHERE workspace_builds.job_id = $1
+	LIMIT 1
+$$;
+
 CREATE FUNCTION tailnet_notify_agent_change() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
@@ -249,6 +350,26 @@ CREATE TABLE api_keys (
 
 COMMENT ON COLUMN api_keys.hashed_secret IS 'hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.';
 
+CREATE TABLE audit_log_archivals (
+    id uuid NOT NULL,
+    initiator_id uuid,
+    status audit_log_archival_status DEFAULT 'running'::audit_log_archival_status NOT NULL,
+    cutoff timestamp with time zone NOT NULL,
+    destination text NOT NULL,
+    archived_count bigint DEFAULT 0 NOT NULL,
+    error text DEFAULT ''::text NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    finished_at timestamp with time zone
+);
+
+COMMENT ON TABLE audit_log_archivals IS 'Runs of the audit log archiver, which exports the audit logs older than the retention window before deleting them.';
+
+COMMENT ON COLUMN audit_log_archivals.initiator_id IS 'The user that started the run. Null for scheduled runs.';
+
+COMMENT ON COLUMN audit_log_archivals.cutoff IS 'Audit logs older than the cutoff are archived by the run.';
+
+COMMENT ON COLUMN audit_log_archivals.destination IS 'Where the audit logs are exported to. Empty if they are deleted without being exported.';
+
 CREATE TABLE audit_logs (
     id uuid NOT NULL,
     "time" timestamp with time zone NOT NULL,
@@ -267,6 +388,29 @@ CREATE TABLE audit_logs (
     resource_icon text NOT NULL
 );
 
+CREATE TABLE entitlement_snapshots (
+    date date NOT NULL,
+    has_license boolean NOT NULL,
+    trial boolean NOT NULL,
+    features jsonb NOT NULL,
+    user_count bigint NOT NULL,
+    active_user_count bigint NOT NULL,
+    replica_count integer NOT NULL,
+    warnings text[] NOT NULL,
+    errors text[] NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE entitlement_snapshots IS 'Daily snapshots of the entitlements of the deployment, kept as evidence of license adherence.';
+
+COMMENT ON COLUMN entitlement_snapshots.date IS 'The UTC day the snapshot was taken on.';
+
+COMMENT ON COLUMN entitlement_snapshots.features IS 'The entitlement, enablement, limit and actual usage of every feature, by feature name.';
+
+COMMENT ON COLUMN entitlement_snapshots.user_count IS 'The number of users that are not deleted, including suspended users.';
+
+COMMENT ON COLUMN entitlement_snapshots.replica_count IS 'The number of primary replicas of the deployment.';
+
 CREATE TABLE files (
     hash character varying(64) NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -294,6 +438,29 @@ CREATE TABLE gitsshkeys (
     public_key text NOT NULL
 );
 
+CREATE TABLE group_default_template_workspaces (
+    template_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    group_id uuid NOT NULL,
+    workspace_id uuid,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE group_default_template_workspaces IS 'Starter workspaces created for members of groups. Members get at most one starter workspace per template, even if they delete it or are in several groups with the template.';
+
+COMMENT ON COLUMN group_default_template_workspaces.workspace_id IS 'NULL if the workspace could not be created, for example because the template requires parameters without a default.';
+
+CREATE TABLE group_default_templates (
+    group_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    create_workspace boolean DEFAULT false NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE group_default_templates IS 'Templates members of a group get access to.';
+
+COMMENT ON COLUMN group_default_templates.create_workspace IS 'Members of the group get a starter workspace from the template.';
+
 CREATE TABLE group_members (
     user_id uuid NOT NULL,
     group_id uuid NOT NULL
@@ -306,23 +473,55 @@ CREATE TABLE groups (
     avatar_url text DEFAULT ''::text NOT NULL,
     quota_allowance integer DEFAULT 0 NOT NULL,
     display_name text DEFAULT ''::text NOT NULL,
-    source group_source DEFAULT 'user'::group_source NOT NULL
+    source group_source DEFAULT 'user'::group_source NOT NULL,
+    quota_override integer,
+    avatar_file_id uuid,
+    metadata jsonb DEFAULT '{}'::jsonb NOT NULL
 );
 
 COMMENT ON COLUMN groups.display_name IS 'Display name is a custom, human-friendly group name that user can set. This is not required to be unique and can be the empty string.';
 
 COMMENT ON COLUMN groups.source IS 'Source indicates how the group was created. It can be created by a user manually, or through some system process like OIDC group sync.';
 
+COMMENT ON COLUMN groups.quota_override IS 'Quota override replaces the summed quota allowances of members of the group. If a user is a member of several groups with an override, the largest override applies.';
+
+COMMENT ON COLUMN groups.avatar_file_id IS 'The uploaded avatar of the group. It is cleared when the avatar URL is changed to something else.';
+
+COMMENT ON COLUMN groups.metadata IS 'Arbitrary key-value metadata of the group, such as the team or cost center it represents.';
+
+CREATE TABLE license_usage (
+    feature text NOT NULL,
+    date date NOT NULL,
+    peak_actual bigint NOT NULL,
+    latest_actual bigint NOT NULL,
+    feature_limit bigint,
+    first_exceeded_at timestamp with time zone,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE license_usage IS 'Daily usage of licensed features that have a measured consumption, such as the user limit.';
+
+COMMENT ON COLUMN license_usage.date IS 'The UTC day the usage was recorded on.';
+
+COMMENT ON COLUMN license_usage.peak_actual IS 'The highest usage recorded during the day.';
+
+COMMENT ON COLUMN license_usage.feature_limit IS 'The licensed limit at the time of the last recording, if the feature is limited.';
+
+COMMENT ON COLUMN license_usage.first_exceeded_at IS 'The first time during the day that usage exceeded the licensed limit.';
+
 CREATE TABLE licenses (
     id integer NOT NULL,
     uploaded_at timestamp with time zone NOT NULL,
     jwt text NOT NULL,
     exp timestamp with time zone NOT NULL,
-    uuid uuid NOT NULL
+    uuid uuid NOT NULL,
+    activation_key text DEFAULT ''::text NOT NULL
 );
 
 COMMENT ON COLUMN licenses.exp IS 'exp tracks the claim of the same name in the JWT, and we include it here so that we can easily query for licenses that have not yet expired.';
 
+COMMENT ON COLUMN licenses.activation_key IS 'The key the license was activated with online, or empty if it was added as a JWT. Licenses with a key are renewed from the activation server before they expire.';
+
 CREATE SEQUENCE licenses_id_seq
     AS integer
     START WITH 1
@@ -333,6 +532,48 @@ CREATE SEQUENCE licenses_id_seq
 
 ALTER SEQUENCE licenses_id_seq OWNED BY licenses.id;
 
+CREATE TABLE managed_environment_variables (
+    id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    template_id uuid,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL,
+    name text NOT NULL,
+    value text NOT NULL,
+    secret boolean DEFAULT false NOT NULL
+);
+
+COMMENT ON TABLE managed_environment_variables IS 'Environment variables that are injected into every agent of the workspaces of an organization or template.';
+
+COMMENT ON COLUMN managed_environment_variables.template_id IS 'The template whose workspaces receive the variable. If null, the variable applies to every workspace of the organization.';
+
+COMMENT ON COLUMN managed_environment_variables.secret IS 'Secret values are never returned by the API.';
+
+CREATE TABLE netcheck_reports (
+    id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    client_version text NOT NULL,
+    client_os text NOT NULL,
+    nat_type text NOT NULL,
+    udp boolean NOT NULL,
+    ipv4 boolean NOT NULL,
+    ipv6 boolean NOT NULL,
+    mapping_varies_by_dest_ip boolean,
+    hair_pinning boolean,
+    preferred_derp integer NOT NULL,
+    region_latencies jsonb DEFAULT '{}'::jsonb NOT NULL,
+    blocked_ports text[] DEFAULT '{}'::text[] NOT NULL
+);
+
+COMMENT ON TABLE netcheck_reports IS 'Connectivity preflights reported by clients. Used to correlate connection issues with the networks of users.';
+
+COMMENT ON COLUMN netcheck_reports.nat_type IS 'One of unknown, udp_blocked, easy or hard. Direct connections are unlikely to succeed behind hard NATs, where the mapping varies by destination.';
+
+COMMENT ON COLUMN netcheck_reports.region_latencies IS 'The latencies in milliseconds to the DERP regions the client reached, keyed by region ID.';
+
+COMMENT ON COLUMN netcheck_reports.blocked_ports IS 'The ports the client could not reach, e.g. udp/3478 when STUN is blocked.';
+
 CREATE TABLE organization_members (
     user_id uuid NOT NULL,
     organization_id uuid NOT NULL,
@@ -346,9 +587,12 @@ CREATE TABLE organizations (
     name text NOT NULL,
     description text NOT NULL,
     created_at timestamp with time zone NOT NULL,
-    updated_at timestamp with time zone NOT NULL
+    updated_at timestamp with time zone NOT NULL,
+    max_concurrent_provisioner_jobs integer DEFAULT 0 NOT NULL
 );
 
+COMMENT ON COLUMN organizations.max_concurrent_provisioner_jobs IS 'The maximum number of provisioner jobs of the organization that run at once. Further jobs wait in the queue. 0 means no limit.';
+
 CREATE TABLE parameter_schemas (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -381,6 +625,25 @@ CREATE TABLE parameter_values (
     destination_scheme parameter_destination_scheme NOT NULL
 );
 
+CREATE TABLE platform_events (
+    id bigint NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    type text NOT NULL,
+    resource_id uuid NOT NULL,
+    data jsonb NOT NULL
+);
+
+COMMENT ON TABLE platform_events IS 'Significant domain events streamed to platform integrations. The id is used as a resume cursor.';
+
+CREATE SEQUENCE platform_events_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE platform_events_id_seq OWNED BY platform_events.id;
+
 CREATE TABLE provisioner_daemons (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -391,6 +654,24 @@ CREATE TABLE provisioner_daemons (
     tags jsonb DEFAULT '{}'::jsonb NOT NULL
 );
 
+CREATE TABLE provisioner_job_build_hooks (
+    id uuid NOT NULL,
+    job_id uuid NOT NULL,
+    name text NOT NULL,
+    phase build_hook_phase NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    completed_at timestamp with time zone NOT NULL,
+    attempts integer NOT NULL,
+    exit_code integer NOT NULL,
+    error text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE provisioner_job_build_hooks IS 'The outcomes of the pre-build and post-build hooks of templates, run by provisioner daemons around workspace builds.';
+
+COMMENT ON COLUMN provisioner_job_build_hooks.attempts IS 'How many times the hook ran, including retries.';
+
+COMMENT ON COLUMN provisioner_job_build_hooks.exit_code IS 'The exit code of the last attempt. -1 if the hook was killed, e.g. when it timed out.';
+
 CREATE TABLE provisioner_job_logs (
     job_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -428,9 +709,15 @@ CREATE TABLE provisioner_jobs (
     file_id uuid NOT NULL,
     tags jsonb DEFAULT '{"scope": "organization"}'::jsonb NOT NULL,
     error_code text,
-    trace_metadata jsonb
+    trace_metadata jsonb,
+    cancel_outcome provisioner_job_cancel_outcome,
+    requeue_count integer DEFAULT 0 NOT NULL
 );
 
+COMMENT ON COLUMN provisioner_jobs.cancel_outcome IS 'How the job stopped after it was canceled. graceful means the provisioner stopped at a safe point, forced means it was killed after the force cancel interval.';
+
+COMMENT ON COLUMN provisioner_jobs.requeue_count IS 'The number of times the job was put back in the queue after being detected as hung.';
+
 CREATE TABLE replicas (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -443,9 +730,12 @@ CREATE TABLE replicas (
     database_latency integer NOT NULL,
     version text NOT NULL,
     error text DEFAULT ''::text NOT NULL,
-    "primary" boolean DEFAULT true NOT NULL
+    "primary" boolean DEFAULT true NOT NULL,
+    draining_at timestamp with time zone
 );
 
+COMMENT ON COLUMN replicas.draining_at IS 'The time the replica started draining. A draining replica doesn''t accept new tailnet connections or provisioner jobs.';
+
 CREATE TABLE site_configs (
     key character varying(256) NOT NULL,
     value character varying(8192) NOT NULL
@@ -473,6 +763,43 @@ CREATE TABLE tailnet_coordinators (
 
 COMMENT ON TABLE tailnet_coordinators IS 'We keep this separate from replicas in case we need to break the coordinator out into its own service';
 
+CREATE TABLE template_access_requests (
+    id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    requested_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    message text DEFAULT ''::text NOT NULL,
+    status template_access_request_status DEFAULT 'pending'::template_access_request_status NOT NULL,
+    decided_by uuid,
+    decided_at timestamp with time zone,
+    reason text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE template_access_requests IS 'Requests of users to be granted use of a template, decided by the template admins.';
+
+COMMENT ON COLUMN template_access_requests.message IS 'An optional explanation from the requester of why they need access.';
+
+COMMENT ON COLUMN template_access_requests.reason IS 'An optional explanation of the decision.';
+
+CREATE TABLE template_version_build_insights (
+    template_version_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    total_builds bigint NOT NULL,
+    failed_builds bigint NOT NULL,
+    failures jsonb NOT NULL,
+    duration_seconds_50 double precision NOT NULL,
+    duration_seconds_95 double precision NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE template_version_build_insights IS 'Rollup of the completed workspace builds of each template version, refreshed periodically. Canceled builds are excluded.';
+
+COMMENT ON COLUMN template_version_build_insights.failures IS 'The number of failed builds by the error code of their job, unclassified failures have an empty error code.';
+
+COMMENT ON COLUMN template_version_build_insights.duration_seconds_50 IS 'The median duration of the successful builds, -1 if there are none.';
+
+COMMENT ON COLUMN template_version_build_insights.duration_seconds_95 IS 'The 95th percentile duration of the successful builds, -1 if there are none.';
+
 CREATE TABLE template_version_parameters (
     template_version_id uuid NOT NULL,
     name text NOT NULL,
@@ -491,6 +818,8 @@ CREATE TABLE template_version_parameters (
     display_name text DEFAULT ''::text NOT NULL,
     display_order integer DEFAULT 0 NOT NULL,
     ephemeral boolean DEFAULT false NOT NULL,
+    dependencies jsonb DEFAULT '[]'::jsonb NOT NULL,
+    options_url text DEFAULT ''::text NOT NULL,
     CONSTRAINT validation_monotonic_order CHECK ((validation_monotonic = ANY (ARRAY['increasing'::text, 'decreasing'::text, ''::text])))
 );
 
@@ -526,6 +855,30 @@ COMMENT ON COLUMN template_version_parameters.display_order IS 'Specifies the or
 
 COMMENT ON COLUMN template_version_parameters.ephemeral IS 'The value of an ephemeral parameter will not be preserved between consecutive workspace builds.';
 
+COMMENT ON COLUMN template_version_parameters.dependencies IS 'Rules restricting the parameter based on the values of other parameters';
+
+COMMENT ON COLUMN template_version_parameters.options_url IS 'Endpoint returning the options of the parameter, replacing the static options';
+
+CREATE TABLE template_version_promotions (
+    id uuid NOT NULL,
+    organization_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    template_version_id uuid NOT NULL,
+    requested_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    message text DEFAULT ''::text NOT NULL,
+    status template_version_promotion_status DEFAULT 'pending'::template_version_promotion_status NOT NULL,
+    decided_by uuid,
+    decided_at timestamp with time zone,
+    reason text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE template_version_promotions IS 'Requests to make a template version the active version, decided by template approvers.';
+
+COMMENT ON COLUMN template_version_promotions.message IS 'An optional description from the requester of the changes in the version.';
+
+COMMENT ON COLUMN template_version_promotions.reason IS 'An optional explanation of the decision, such as a change ticket number.';
+
 CREATE TABLE template_version_variables (
     template_version_id uuid NOT NULL,
     name text NOT NULL,
@@ -638,7 +991,20 @@ CREATE TABLE templates (
     inactivity_ttl bigint DEFAULT 0 NOT NULL,
     locked_ttl bigint DEFAULT 0 NOT NULL,
     restart_requirement_days_of_week smallint DEFAULT 0 NOT NULL,
-    restart_requirement_weeks bigint DEFAULT 0 NOT NULL
+    restart_requirement_weeks bigint DEFAULT 0 NOT NULL,
+    require_active_version boolean DEFAULT false NOT NULL,
+    require_active_version_grace_period bigint DEFAULT 0 NOT NULL,
+    active_version_updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    max_build_duration bigint DEFAULT 0 NOT NULL,
+    provisioner_memory_limit bigint DEFAULT 0 NOT NULL,
+    provisioner_cpu_limit bigint DEFAULT 0 NOT NULL,
+    require_workspace_approval boolean DEFAULT false NOT NULL,
+    require_agent_binary_verification boolean DEFAULT false NOT NULL,
+    autostop_activity_sources text[] DEFAULT '{}'::text[] NOT NULL,
+    require_promotion_approval boolean DEFAULT false NOT NULL,
+    max_concurrent_provisioner_jobs integer DEFAULT 0 NOT NULL,
+    max_session_idle bigint DEFAULT 0 NOT NULL,
+    port_policy jsonb DEFAULT '{}'::jsonb NOT NULL
 );
 
 COMMENT ON COLUMN templates.default_ttl IS 'The default duration for autostop for workspaces created from this template.';
@@ -655,6 +1021,32 @@ COMMENT ON COLUMN templates.restart_requirement_days_of_week IS 'A bitmap of day
 
 COMMENT ON COLUMN templates.restart_requirement_weeks IS 'The number of weeks between restarts. 0 or 1 weeks means "every week", 2 week means "every second week", etc. Weeks are counted from January 2, 2023, which is the first Monday of 2023. This is to ensure workspaces are started consistently for all customers on the same n-week cycles.';
 
+COMMENT ON COLUMN templates.require_active_version IS 'Outdated workspaces are updated to the active version of the template when they are started.';
+
+COMMENT ON COLUMN templates.require_active_version_grace_period IS 'The duration after the active version changes during which outdated workspaces can still be started with their current version.';
+
+COMMENT ON COLUMN templates.active_version_updated_at IS 'The time the active version of the template was last changed.';
+
+COMMENT ON COLUMN templates.max_build_duration IS 'The maximum duration of a provisioner job for the template. Jobs running longer are canceled. 0 means no limit.';
+
+COMMENT ON COLUMN templates.provisioner_memory_limit IS 'The maximum memory in bytes the provisioner can use for a job of the template. 0 means no limit.';
+
+COMMENT ON COLUMN templates.provisioner_cpu_limit IS 'The maximum CPU time the provisioner can use for a job of the template. 0 means no limit.';
+
+COMMENT ON COLUMN templates.require_workspace_approval IS 'Workspaces created from the template are held until their first build is approved.';
+
+COMMENT ON COLUMN templates.require_agent_binary_verification IS 'Agents of workspaces created from the template fail to start if their binary does not match the checksum served by the deployment.';
+
+COMMENT ON COLUMN templates.autostop_activity_sources IS 'The kinds of activity reported by agents that postpone the autostop of workspaces. Any connection postpones it when empty.';
+
+COMMENT ON COLUMN templates.require_promotion_approval IS 'New versions of the template only become active once a template approver approves their promotion.';
+
+COMMENT ON COLUMN templates.max_concurrent_provisioner_jobs IS 'The maximum number of provisioner jobs of the template that run at once. Further jobs wait in the queue. 0 means no limit.';
+
+COMMENT ON COLUMN templates.max_session_idle IS 'How long web terminal and app sessions of the workspaces of the template may be idle before they are disconnected. 0 means sessions are never disconnected.';
+
+COMMENT ON COLUMN templates.port_policy IS 'Restricts which ports of the agents of the workspaces of the template can be forwarded, and how far apps on them can be shared.';
+
 CREATE VIEW template_with_users AS
  SELECT templates.id,
     templates.created_at,
@@ -680,6 +1072,19 @@ CREATE VIEW template_with_users AS
     templates.locked_ttl,
     templates.restart_requirement_days_of_week,
     templates.restart_requirement_weeks,
+    templates.require_active_version,
+    templates.require_active_version_grace_period,
+    templates.active_version_updated_at,
+    templates.max_build_duration,
+    templates.provisioner_memory_limit,
+    templates.provisioner_cpu_limit,
+    templates.require_workspace_approval,
+    templates.require_agent_binary_verification,
+    templates.autostop_activity_sources,
+    templates.require_promotion_approval,
+    templates.max_concurrent_provisioner_jobs,
+    templates.max_session_idle,
+    templates.port_policy,
     COALESCE(visible_users.avatar_url, ''::text) AS created_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS created_by_username
    FROM (public.templates
@@ -687,6 +1092,13 @@ CREATE VIEW template_with_users AS
 
 COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
 
+CREATE TABLE user_deprovisions (
+    user_id uuid NOT NULL,
+    deprovisioned_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_deprovisions IS 'Users the identity provider deactivated through SCIM. The deprovisioning policy is applied to these users until they are reactivated or deleted.';
+
 CREATE TABLE user_links (
     user_id uuid NOT NULL,
     login_type login_type NOT NULL,
@@ -696,6 +1108,87 @@ CREATE TABLE user_links (
     oauth_expiry timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
 );
 
+CREATE TABLE user_pty_session_overrides (
+    user_id uuid NOT NULL,
+    max_pty_sessions integer NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_pty_session_overrides IS 'Per-user limits of concurrent web terminal sessions. An override takes precedence over the limit of the deployment.';
+
+COMMENT ON COLUMN user_pty_session_overrides.max_pty_sessions IS '0 means the user has no limit.';
+
+CREATE TABLE user_quota_overrides (
+    user_id uuid NOT NULL,
+    quota_allowance integer NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_quota_overrides IS 'Per-user quota allowances. An override takes precedence over any quota the user receives from groups.';
+
+CREATE TABLE user_region_latencies (
+    user_id uuid NOT NULL,
+    region_id uuid NOT NULL,
+    latency_ms double precision NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE user_region_latencies IS 'The latest latency each user reported for each region. Used to rank the regions a user should connect through.';
+
+COMMENT ON COLUMN user_region_latencies.region_id IS 'The ID of the workspace proxy, or the deployment ID for the primary region.';
+
+CREATE TABLE workspace_agent_auth_token_rotations (
+    id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    previous_auth_token uuid NOT NULL,
+    previous_auth_token_expires_at timestamp with time zone NOT NULL,
+    revoked boolean DEFAULT false NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_auth_token_rotations IS 'Replacements of the auth tokens of workspace agents. The previous token stays valid until it expires, so requests of the agent in flight during the rotation succeed.';
+
+COMMENT ON COLUMN workspace_agent_auth_token_rotations.revoked IS 'The previous token was revoked because it may be compromised, it expired immediately.';
+
+CREATE TABLE workspace_agent_bandwidth_stats (
+    id uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    user_id uuid NOT NULL,
+    agent_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    connection_type text NOT NULL,
+    peer text NOT NULL,
+    port integer DEFAULT 0 NOT NULL,
+    rx_bytes bigint DEFAULT 0 NOT NULL,
+    tx_bytes bigint DEFAULT 0 NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_bandwidth_stats IS 'Traffic of workspace agent connections by connection type and peer. Rows are purged with workspace_agent_stats.';
+
+COMMENT ON COLUMN workspace_agent_bandwidth_stats.peer IS 'Tailnet IP address of the remote end of the connections.';
+
+COMMENT ON COLUMN workspace_agent_bandwidth_stats.port IS 'Agent port of port forwarded connections, 0 for other connection types.';
+
+CREATE TABLE workspace_agent_client_connections (
+    id uuid NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    user_id uuid,
+    client_type text NOT NULL,
+    client_version text NOT NULL,
+    connected_at timestamp with time zone NOT NULL,
+    disconnected_at timestamp with time zone,
+    ip_address inet
+);
+
+COMMENT ON TABLE workspace_agent_client_connections IS 'Connections made to workspace agents by clients such as the CLI and IDE plugins';
+
+COMMENT ON COLUMN workspace_agent_client_connections.user_id IS 'The user the client connected as. Null if the client is a workspace proxy.';
+
+COMMENT ON COLUMN workspace_agent_client_connections.ip_address IS 'The IP address the client connected from.';
+
 CREATE TABLE workspace_agent_logs (
     agent_id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -717,6 +1210,37 @@ CREATE UNLOGGED TABLE workspace_agent_metadata (
     collected_at timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
 );
 
+CREATE UNLOGGED TABLE workspace_agent_metadata_history (
+    id bigint NOT NULL,
+    workspace_agent_id uuid NOT NULL,
+    key character varying(127) NOT NULL,
+    value character varying(65535) DEFAULT ''::character varying NOT NULL,
+    error character varying(65535) DEFAULT ''::character varying NOT NULL,
+    collected_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_metadata_history IS 'A bounded history of the values reported for each workspace agent metadata key';
+
+CREATE SEQUENCE workspace_agent_metadata_history_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+ALTER SEQUENCE workspace_agent_metadata_history_id_seq OWNED BY workspace_agent_metadata_history.id;
+
+CREATE TABLE workspace_agent_ssh_host_keys (
+    workspace_id uuid NOT NULL,
+    agent_name text NOT NULL,
+    encrypted_private_key text NOT NULL,
+    created_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_ssh_host_keys IS 'SSH host keys of workspace agents. Agents are recreated by every build, so keys are stored by workspace and agent name to present the same key across rebuilds.';
+
+COMMENT ON COLUMN workspace_agent_ssh_host_keys.encrypted_private_key IS 'The PEM encoded private key, encrypted with the app security key of the deployment.';
+
 CREATE SEQUENCE workspace_agent_startup_logs_id_seq
     START WITH 1
     INCREMENT BY 1
@@ -780,6 +1304,9 @@ CREATE TABLE workspace_agents (
     started_at timestamp with time zone,
     ready_at timestamp with time zone,
     subsystems workspace_agent_subsystem[] DEFAULT '{}'::workspace_agent_subsystem[],
+    display_order integer DEFAULT 0 NOT NULL,
+    host_facts jsonb DEFAULT '{}'::jsonb NOT NULL,
+    clock_offset_ms bigint DEFAULT 0 NOT NULL,
     CONSTRAINT max_logs_length CHECK ((logs_length <= 1048576)),
     CONSTRAINT subsystems_not_none CHECK ((NOT ('none'::workspace_agent_subsystem = ANY (subsystems))))
 );
@@ -812,6 +1339,12 @@ COMMENT ON COLUMN workspace_agents.started_at IS 'The time the agent entered the
 
 COMMENT ON COLUMN workspace_agents.ready_at IS 'The time the agent entered the ready or start_error lifecycle state';
 
+COMMENT ON COLUMN workspace_agents.display_order IS 'Specifies the order in which agents are displayed within their resource, lowest first.';
+
+COMMENT ON COLUMN workspace_agents.host_facts IS 'Facts about the host reported by the workspace agent on startup, such as the Windows version and build.';
+
+COMMENT ON COLUMN workspace_agents.clock_offset_ms IS 'The offset of the clock of the workspace agent from the clock of coderd in milliseconds, as last reported by the agent. Positive values mean the agent clock is ahead.';
+
 CREATE TABLE workspace_app_stats (
     id bigint NOT NULL,
     user_id uuid NOT NULL,
@@ -856,6 +1389,24 @@ CREATE SEQUENCE workspace_app_stats_id_seq
 
 ALTER SEQUENCE workspace_app_stats_id_seq OWNED BY workspace_app_stats.id;
 
+CREATE TABLE workspace_approvals (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    job_id uuid NOT NULL,
+    requested_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    status workspace_approval_status DEFAULT 'pending'::workspace_approval_status NOT NULL,
+    decided_by uuid,
+    decided_at timestamp with time zone,
+    reason text DEFAULT ''::text NOT NULL
+);
+
+COMMENT ON TABLE workspace_approvals IS 'Approval requests for workspace builds that are held until an approver decides on them.';
+
+COMMENT ON COLUMN workspace_approvals.job_id IS 'The provisioner job of the held build. Provisioners only acquire it once the request is approved.';
+
+COMMENT ON COLUMN workspace_approvals.reason IS 'An optional explanation of the decision, such as a change ticket number.';
+
 CREATE TABLE workspace_apps (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -871,9 +1422,15 @@ CREATE TABLE workspace_apps (
     subdomain boolean DEFAULT false NOT NULL,
     sharing_level app_sharing_level DEFAULT 'owner'::app_sharing_level NOT NULL,
     slug text NOT NULL,
-    external boolean DEFAULT false NOT NULL
+    external boolean DEFAULT false NOT NULL,
+    display_order integer DEFAULT 0 NOT NULL,
+    hidden boolean DEFAULT false NOT NULL
 );
 
+COMMENT ON COLUMN workspace_apps.display_order IS 'Specifies the order in which apps are displayed within their agent, lowest first.';
+
+COMMENT ON COLUMN workspace_apps.hidden IS 'Determines if the app is not shown in user interfaces.';
+
 CREATE TABLE workspace_build_parameters (
     workspace_build_id uuid NOT NULL,
     name text NOT NULL,
@@ -884,6 +1441,19 @@ COMMENT ON COLUMN workspace_build_parameters.name IS 'Parameter name';
 
 COMMENT ON COLUMN workspace_build_parameters.value IS 'Parameter value';
 
+CREATE TABLE workspace_build_queue_entries (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    initiator_id uuid NOT NULL,
+    initiator_token_name text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    request jsonb NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_queue_entries IS 'Workspace build requests waiting for the active build of their workspace to finish.';
+
+COMMENT ON COLUMN workspace_build_queue_entries.request IS 'The request the build is created with once the workspace has no active build.';
+
 CREATE TABLE workspace_builds (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -898,9 +1468,12 @@ CREATE TABLE workspace_builds (
     deadline timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL,
     reason build_reason DEFAULT 'initiator'::build_reason NOT NULL,
     daily_cost integer DEFAULT 0 NOT NULL,
-    max_deadline timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL
+    max_deadline timestamp with time zone DEFAULT '0001-01-01 00:00:00+00'::timestamp with time zone NOT NULL,
+    initiator_token_name text DEFAULT ''::text NOT NULL
 );
 
+COMMENT ON COLUMN workspace_builds.initiator_token_name IS 'The name of the API token the initiator used to start the build. Empty if the build was not started with a named token.';
+
 CREATE VIEW workspace_build_with_user AS
  SELECT workspace_builds.id,
     workspace_builds.created_at,
@@ -916,6 +1489,7 @@ CREATE VIEW workspace_build_with_user AS
     workspace_builds.reason,
     workspace_builds.daily_cost,
     workspace_builds.max_deadline,
+    workspace_builds.initiator_token_name,
     COALESCE(visible_users.avatar_url, ''::text) AS initiator_by_avatar_url,
     COALESCE(visible_users.username, ''::text) AS initiator_by_username
    FROM (public.workspace_builds
@@ -923,6 +1497,29 @@ CREATE VIEW workspace_build_with_user AS
 
 COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
 
+CREATE TABLE workspace_external_metadata (
+    workspace_id uuid NOT NULL,
+    namespace text NOT NULL,
+    visibility workspace_external_metadata_visibility DEFAULT 'public'::workspace_external_metadata_visibility NOT NULL,
+    data jsonb NOT NULL,
+    updated_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_external_metadata IS 'Namespaced JSON attached to workspaces by external integrations';
+
+COMMENT ON COLUMN workspace_external_metadata.visibility IS 'Public metadata is visible to anyone who can read the workspace. Private metadata is only visible to users who can read all workspaces in the organization.';
+
+CREATE TABLE workspace_previous_names (
+    workspace_id uuid NOT NULL,
+    name text NOT NULL,
+    renamed_at timestamp with time zone NOT NULL,
+    redirect_expires_at timestamp with time zone NOT NULL
+);
+
+COMMENT ON TABLE workspace_previous_names IS 'Names workspaces had before they were renamed.';
+
+COMMENT ON COLUMN workspace_previous_names.redirect_expires_at IS 'Until this time, app URLs and lookups using the previous name resolve to the renamed workspace.';
+
 CREATE TABLE workspace_proxies (
     id uuid NOT NULL,
     name text NOT NULL,
@@ -936,7 +1533,9 @@ CREATE TABLE workspace_proxies (
     token_hashed_secret bytea NOT NULL,
     region_id integer NOT NULL,
     derp_enabled boolean DEFAULT true NOT NULL,
-    derp_only boolean DEFAULT false NOT NULL
+    derp_only boolean DEFAULT false NOT NULL,
+    organization_ids uuid[] DEFAULT '{}'::uuid[] NOT NULL,
+    group_ids uuid[] DEFAULT '{}'::uuid[] NOT NULL
 );
 
 COMMENT ON COLUMN workspace_proxies.icon IS 'Expects an emoji character. (/emojis/1f1fa-1f1f8.png)';
@@ -951,6 +1550,10 @@ COMMENT ON COLUMN workspace_proxies.token_hashed_secret IS 'Hashed secret is use
 
 COMMENT ON COLUMN workspace_proxies.derp_only IS 'Disables app/terminal proxying for this proxy and only acts as a DERP relay.';
 
+COMMENT ON COLUMN workspace_proxies.organization_ids IS 'Members of these organizations may use the proxy. The proxy is available to everyone when both organization_ids and group_ids are empty.';
+
+COMMENT ON COLUMN workspace_proxies.group_ids IS 'Members of these groups may use the proxy. The proxy is available to everyone when both organization_ids and group_ids are empty.';
+
 CREATE SEQUENCE workspace_proxies_region_id_seq
     AS integer
     START WITH 1
@@ -988,9 +1591,33 @@ CREATE TABLE workspace_resources (
     hide boolean DEFAULT false NOT NULL,
     icon character varying(256) DEFAULT ''::character varying NOT NULL,
     instance_type character varying(256),
-    daily_cost integer DEFAULT 0 NOT NULL
+    daily_cost integer DEFAULT 0 NOT NULL,
+    display_order integer DEFAULT 0 NOT NULL
 );
 
+COMMENT ON COLUMN workspace_resources.display_order IS 'Specifies the order in which resources are displayed, lowest first.';
+
+CREATE TABLE workspace_webhooks (
+    id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    name text NOT NULL,
+    action workspace_webhook_action NOT NULL,
+    agent_name text DEFAULT ''::text NOT NULL,
+    command text DEFAULT ''::text NOT NULL,
+    secret text NOT NULL,
+    last_triggered_at timestamp with time zone
+);
+
+COMMENT ON TABLE workspace_webhooks IS 'Inbound webhooks that let external systems trigger a fixed action on a workspace.';
+
+COMMENT ON COLUMN workspace_webhooks.created_by IS 'Triggered actions run with the permissions of this user.';
+
+COMMENT ON COLUMN workspace_webhooks.agent_name IS 'The agent that runs the command of an exec webhook. Empty if the workspace has a single agent.';
+
+COMMENT ON COLUMN workspace_webhooks.secret IS 'Shared secret that trigger requests are signed with using HMAC-SHA256.';
+
 CREATE TABLE workspaces (
     id uuid NOT NULL,
     created_at timestamp with time zone NOT NULL,
@@ -1004,15 +1631,22 @@ CREATE TABLE workspaces (
     ttl bigint,
     last_used_at timestamp without time zone DEFAULT '0001-01-01 00:00:00'::timestamp without time zone NOT NULL,
     locked_at timestamp with time zone,
-    deleting_at timestamp with time zone
+    deleting_at timestamp with time zone,
+    automatic_updates automatic_updates DEFAULT 'never'::automatic_updates NOT NULL
 );
 
+COMMENT ON COLUMN workspaces.automatic_updates IS 'Determines if the workspace is started with the active template version when it is outdated.';
+
 ALTER TABLE ONLY licenses ALTER COLUMN id SET DEFAULT nextval('licenses_id_seq'::regclass);
 
+ALTER TABLE ONLY platform_events ALTER COLUMN id SET DEFAULT nextval('platform_events_id_seq'::regclass);
+
 ALTER TABLE ONLY provisioner_job_logs ALTER COLUMN id SET DEFAULT nextval('provisioner_job_logs_id_seq'::regclass);
 
 ALTER TABLE ONLY workspace_agent_logs ALTER COLUMN id SET DEFAULT nextval('workspace_agent_startup_logs_id_seq'::regclass);
 
+ALTER TABLE ONLY workspace_agent_metadata_history ALTER COLUMN id SET DEFAULT nextval('workspace_agent_metadata_history_id_seq'::regclass);
+
 ALTER TABLE ONLY workspace_app_stats ALTER COLUMN id SET DEFAULT nextval('workspace_app_stats_id_seq'::regclass);
 
 ALTER TABLE ONLY workspace_proxies ALTER COLUMN region_id SET DEFAULT nextval('workspace_proxies_region_id_seq'::regclass);
@@ -1025,9 +1659,15 @@ ALTER TABLE ONLY workspace_agent_stats
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY audit_log_archivals
+    ADD CONSTRAINT audit_log_archivals_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY audit_logs
     ADD CONSTRAINT audit_logs_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY entitlement_snapshots
+    ADD CONSTRAINT entitlement_snapshots_pkey PRIMARY KEY (date);
+
 ALTER TABLE ONLY files
     ADD CONSTRAINT files_hash_created_by_key UNIQUE (hash, created_by);
 
@@ -1040,6 +1680,12 @@ ALTER TABLE ONLY git_auth_links
 ALTER TABLE ONLY gitsshkeys
     ADD CONSTRAINT gitsshkeys_pkey PRIMARY KEY (user_id);
 
+ALTER TABLE ONLY group_default_template_workspaces
+    ADD CONSTRAINT group_default_template_workspaces_pkey PRIMARY KEY (template_id, user_id);
+
+ALTER TABLE ONLY group_default_templates
+    ADD CONSTRAINT group_default_templates_pkey PRIMARY KEY (group_id, template_id);
+
 ALTER TABLE ONLY group_members
     ADD CONSTRAINT group_members_user_id_group_id_key UNIQUE (user_id, group_id);
 
@@ -1049,12 +1695,21 @@ ALTER TABLE ONLY groups
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY license_usage
+    ADD CONSTRAINT license_usage_pkey PRIMARY KEY (feature, date);
+
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_jwt_key UNIQUE (jwt);
 
 ALTER TABLE ONLY licenses
     ADD CONSTRAINT licenses_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY managed_environment_variables
+    ADD CONSTRAINT managed_environment_variables_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY netcheck_reports
+    ADD CONSTRAINT netcheck_reports_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_pkey PRIMARY KEY (organization_id, user_id);
 
@@ -1073,12 +1728,18 @@ ALTER TABLE ONLY parameter_values
 ALTER TABLE ONLY parameter_values
     ADD CONSTRAINT parameter_values_scope_id_name_key UNIQUE (scope_id, name);
 
+ALTER TABLE ONLY platform_events
+    ADD CONSTRAINT platform_events_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY provisioner_daemons
     ADD CONSTRAINT provisioner_daemons_name_key UNIQUE (name);
 
 ALTER TABLE ONLY provisioner_daemons
     ADD CONSTRAINT provisioner_daemons_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY provisioner_job_build_hooks
+    ADD CONSTRAINT provisioner_job_build_hooks_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY provisioner_job_logs
     ADD CONSTRAINT provisioner_job_logs_pkey PRIMARY KEY (id);
 
@@ -1097,9 +1758,18 @@ ALTER TABLE ONLY tailnet_clients
 ALTER TABLE ONLY tailnet_coordinators
     ADD CONSTRAINT tailnet_coordinators_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY template_access_requests
+    ADD CONSTRAINT template_access_requests_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY template_version_build_insights
+    ADD CONSTRAINT template_version_build_insights_pkey PRIMARY KEY (template_version_id);
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_name_key UNIQUE (template_version_id, name);
 
+ALTER TABLE ONLY template_version_promotions
+    ADD CONSTRAINT template_version_promotions_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY template_version_variables
     ADD CONSTRAINT template_version_variables_template_version_id_name_key UNIQUE (template_version_id, name);
 
@@ -1112,15 +1782,42 @@ ALTER TABLE ONLY template_versions
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY user_deprovisions
+    ADD CONSTRAINT user_deprovisions_pkey PRIMARY KEY (user_id);
+
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_pkey PRIMARY KEY (user_id, login_type);
 
+ALTER TABLE ONLY user_pty_session_overrides
+    ADD CONSTRAINT user_pty_session_overrides_pkey PRIMARY KEY (user_id);
+
+ALTER TABLE ONLY user_quota_overrides
+    ADD CONSTRAINT user_quota_overrides_pkey PRIMARY KEY (user_id);
+
+ALTER TABLE ONLY user_region_latencies
+    ADD CONSTRAINT user_region_latencies_pkey PRIMARY KEY (user_id, region_id);
+
 ALTER TABLE ONLY users
     ADD CONSTRAINT users_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_agent_auth_token_rotations
+    ADD CONSTRAINT workspace_agent_auth_token_rotations_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_agent_bandwidth_stats
+    ADD CONSTRAINT workspace_agent_bandwidth_stats_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_agent_client_connections
+    ADD CONSTRAINT workspace_agent_client_connections_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_pkey PRIMARY KEY (workspace_agent_id, key);
 
+ALTER TABLE ONLY workspace_agent_metadata_history
+    ADD CONSTRAINT workspace_agent_metadata_history_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_agent_ssh_host_keys
+    ADD CONSTRAINT workspace_agent_ssh_host_keys_pkey PRIMARY KEY (workspace_id, agent_name);
+
 ALTER TABLE ONLY workspace_agent_logs
     ADD CONSTRAINT workspace_agent_startup_logs_pkey PRIMARY KEY (id);
 
@@ -1133,6 +1830,12 @@ ALTER TABLE ONLY workspace_app_stats
 ALTER TABLE ONLY workspace_app_stats
     ADD CONSTRAINT workspace_app_stats_user_id_agent_id_session_id_key UNIQUE (user_id, agent_id, session_id);
 
+ALTER TABLE ONLY workspace_approvals
+    ADD CONSTRAINT workspace_approvals_job_id_key UNIQUE (job_id);
+
+ALTER TABLE ONLY workspace_approvals
+    ADD CONSTRAINT workspace_approvals_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_agent_id_slug_idx UNIQUE (agent_id, slug);
 
@@ -1142,6 +1845,9 @@ ALTER TABLE ONLY workspace_apps
 ALTER TABLE ONLY workspace_build_parameters
     ADD CONSTRAINT workspace_build_parameters_workspace_build_id_name_key UNIQUE (workspace_build_id, name);
 
+ALTER TABLE ONLY workspace_build_queue_entries
+    ADD CONSTRAINT workspace_build_queue_entries_pkey PRIMARY KEY (id);
+
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_job_id_key UNIQUE (job_id);
 
@@ -1151,6 +1857,12 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_build_number_key UNIQUE (workspace_id, build_number);
 
+ALTER TABLE ONLY workspace_external_metadata
+    ADD CONSTRAINT workspace_external_metadata_pkey PRIMARY KEY (workspace_id, namespace);
+
+ALTER TABLE ONLY workspace_previous_names
+    ADD CONSTRAINT workspace_previous_names_pkey PRIMARY KEY (workspace_id, name);
+
 ALTER TABLE ONLY workspace_proxies
     ADD CONSTRAINT workspace_proxies_pkey PRIMARY KEY (id);
 
@@ -1166,9 +1878,17 @@ ALTER TABLE ONLY workspace_resource_metadata
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY workspace_webhooks
+    ADD CONSTRAINT workspace_webhooks_pkey PRIMARY KEY (id);
+
+ALTER TABLE ONLY workspace_webhooks
+    ADD CONSTRAINT workspace_webhooks_workspace_id_name_key UNIQUE (workspace_id, name);
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
 
+CREATE INDEX audit_log_archivals_started_at_idx ON audit_log_archivals USING btree (started_at DESC);
+
 CREATE INDEX idx_agent_stats_created_at ON workspace_agent_stats USING btree (created_at);
 
 CREATE INDEX idx_agent_stats_user_id ON workspace_agent_stats USING btree (user_id);
@@ -1203,16 +1923,48 @@ CREATE UNIQUE INDEX idx_users_email ON users USING btree (email) WHERE (deleted
 
 CREATE UNIQUE INDEX idx_users_username ON users USING btree (username) WHERE (deleted = false);
 
+CREATE INDEX idx_workspace_agent_bandwidth_stats_created_at ON workspace_agent_bandwidth_stats USING btree (created_at);
+
+CREATE UNIQUE INDEX managed_environment_variables_organization_name_idx ON managed_environment_variables USING btree (organization_id, name) WHERE (template_id IS NULL);
+
+CREATE UNIQUE INDEX managed_environment_variables_template_name_idx ON managed_environment_variables USING btree (template_id, name) WHERE (template_id IS NOT NULL);
+
+CREATE INDEX netcheck_reports_created_at_idx ON netcheck_reports USING btree (created_at DESC);
+
+CREATE INDEX netcheck_reports_user_id_created_at_idx ON netcheck_reports USING btree (user_id, created_at DESC);
+
+CREATE INDEX platform_events_created_at_idx ON platform_events USING btree (created_at);
+
+CREATE INDEX provisioner_job_build_hooks_job_id_idx ON provisioner_job_build_hooks USING btree (job_id, started_at);
+
 CREATE INDEX provisioner_job_logs_id_job_id_idx ON provisioner_job_logs USING btree (job_id, id);
 
 CREATE INDEX provisioner_jobs_started_at_idx ON provisioner_jobs USING btree (started_at) WHERE (started_at IS NULL);
 
+CREATE UNIQUE INDEX template_access_requests_pending_idx ON template_access_requests USING btree (template_id, requested_by) WHERE (status = 'pending'::template_access_request_status);
+
+CREATE INDEX template_access_requests_template_id_idx ON template_access_requests USING btree (template_id);
+
+CREATE INDEX template_version_build_insights_template_id_idx ON template_version_build_insights USING btree (template_id);
+
+CREATE UNIQUE INDEX template_version_promotions_pending_idx ON template_version_promotions USING btree (template_version_id) WHERE (status = 'pending'::template_version_promotion_status);
+
+CREATE INDEX template_version_promotions_template_id_idx ON template_version_promotions USING btree (template_id);
+
 CREATE UNIQUE INDEX templates_organization_id_name_idx ON templates USING btree (organization_id, lower((name)::text)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX users_email_lower_idx ON users USING btree (lower(email)) WHERE (deleted = false);
 
 CREATE UNIQUE INDEX users_username_lower_idx ON users USING btree (lower(username)) WHERE (deleted = false);
 
+CREATE INDEX workspace_agent_auth_token_rotations_previous_auth_token_idx ON workspace_agent_auth_token_rotations USING btree (previous_auth_token);
+
+CREATE INDEX workspace_agent_client_connections_user_id_connected_at_idx ON workspace_agent_client_connections USING btree (user_id, connected_at DESC);
+
+CREATE INDEX workspace_agent_client_connections_workspace_agent_id_connected_at_idx ON workspace_agent_client_connections USING btree (workspace_agent_id, connected_at DESC);
+
+CREATE INDEX workspace_agent_metadata_history_workspace_agent_id_key_idx ON workspace_agent_metadata_history USING btree (workspace_agent_id, key, id DESC);
+
 CREATE INDEX workspace_agent_startup_logs_id_agent_id_idx ON workspace_agent_logs USING btree (agent_id, id);
 
 CREATE INDEX workspace_agents_auth_token_idx ON workspace_agents USING btree (auth_token);
@@ -1221,6 +1973,12 @@ CREATE INDEX workspace_agents_resource_id_idx ON workspace_agents USING btree (r
 
 CREATE INDEX workspace_app_stats_workspace_id_idx ON workspace_app_stats USING btree (workspace_id);
 
+CREATE INDEX workspace_approvals_workspace_id_idx ON workspace_approvals USING btree (workspace_id);
+
+CREATE INDEX workspace_build_queue_entries_workspace_id_created_at_idx ON workspace_build_queue_entries USING btree (workspace_id, created_at);
+
+CREATE INDEX workspace_previous_names_name_idx ON workspace_previous_names USING btree (lower(name));
+
 CREATE UNIQUE INDEX workspace_proxies_lower_name_idx ON workspace_proxies USING btree (lower(name)) WHERE (deleted = false);
 
 CREATE INDEX workspace_resources_job_id_idx ON workspace_resources USING btree (job_id);
@@ -1240,18 +1998,51 @@ CREATE TRIGGER trigger_update_users AFTER INSERT OR UPDATE ON users FOR EACH ROW
 ALTER TABLE ONLY api_keys
     ADD CONSTRAINT api_keys_user_id_uuid_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY audit_log_archivals
+    ADD CONSTRAINT audit_log_archivals_initiator_id_fkey FOREIGN KEY (initiator_id) REFERENCES users(id) ON DELETE SET NULL;
+
 ALTER TABLE ONLY gitsshkeys
     ADD CONSTRAINT gitsshkeys_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
 
+ALTER TABLE ONLY group_default_template_workspaces
+    ADD CONSTRAINT group_default_template_workspaces_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY group_default_template_workspaces
+    ADD CONSTRAINT group_default_template_workspaces_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY group_default_template_workspaces
+    ADD CONSTRAINT group_default_template_workspaces_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY group_default_template_workspaces
+    ADD CONSTRAINT group_default_template_workspaces_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY group_default_templates
+    ADD CONSTRAINT group_default_templates_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY group_default_templates
+    ADD CONSTRAINT group_default_templates_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY group_members
     ADD CONSTRAINT group_members_group_id_fkey FOREIGN KEY (group_id) REFERENCES groups(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY group_members
     ADD CONSTRAINT group_members_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY groups
+    ADD CONSTRAINT groups_avatar_file_id_fkey FOREIGN KEY (avatar_file_id) REFERENCES files(id) ON DELETE SET NULL;
+
 ALTER TABLE ONLY groups
     ADD CONSTRAINT groups_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY managed_environment_variables
+    ADD CONSTRAINT managed_environment_variables_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY managed_environment_variables
+    ADD CONSTRAINT managed_environment_variables_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY netcheck_reports
+    ADD CONSTRAINT netcheck_reports_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY organization_members
     ADD CONSTRAINT organization_members_organization_id_uuid_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
@@ -1261,6 +2052,9 @@ ALTER TABLE ONLY organization_members
 ALTER TABLE ONLY parameter_schemas
     ADD CONSTRAINT parameter_schemas_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY provisioner_job_build_hooks
+    ADD CONSTRAINT provisioner_job_build_hooks_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY provisioner_job_logs
     ADD CONSTRAINT provisioner_job_logs_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
@@ -1273,9 +2067,39 @@ ALTER TABLE ONLY tailnet_agents
 ALTER TABLE ONLY tailnet_clients
     ADD CONSTRAINT tailnet_clients_coordinator_id_fkey FOREIGN KEY (coordinator_id) REFERENCES tailnet_coordinators(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_access_requests
+    ADD CONSTRAINT template_access_requests_decided_by_fkey FOREIGN KEY (decided_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY template_access_requests
+    ADD CONSTRAINT template_access_requests_requested_by_fkey FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_access_requests
+    ADD CONSTRAINT template_access_requests_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_build_insights
+    ADD CONSTRAINT template_version_build_insights_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_build_insights
+    ADD CONSTRAINT template_version_build_insights_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_parameters
     ADD CONSTRAINT template_version_parameters_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY template_version_promotions
+    ADD CONSTRAINT template_version_promotions_decided_by_fkey FOREIGN KEY (decided_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY template_version_promotions
+    ADD CONSTRAINT template_version_promotions_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_promotions
+    ADD CONSTRAINT template_version_promotions_requested_by_fkey FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_promotions
+    ADD CONSTRAINT template_version_promotions_template_id_fkey FOREIGN KEY (template_id) REFERENCES templates(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY template_version_promotions
+    ADD CONSTRAINT template_version_promotions_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY template_version_variables
     ADD CONSTRAINT template_version_variables_template_version_id_fkey FOREIGN KEY (template_version_id) REFERENCES template_versions(id) ON DELETE CASCADE;
 
@@ -1294,12 +2118,39 @@ ALTER TABLE ONLY templates
 ALTER TABLE ONLY templates
     ADD CONSTRAINT templates_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_deprovisions
+    ADD CONSTRAINT user_deprovisions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY user_links
     ADD CONSTRAINT user_links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY user_pty_session_overrides
+    ADD CONSTRAINT user_pty_session_overrides_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_quota_overrides
+    ADD CONSTRAINT user_quota_overrides_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY user_region_latencies
+    ADD CONSTRAINT user_region_latencies_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_auth_token_rotations
+    ADD CONSTRAINT workspace_agent_auth_token_rotations_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_client_connections
+    ADD CONSTRAINT workspace_agent_client_connections_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_client_connections
+    ADD CONSTRAINT workspace_agent_client_connections_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_metadata
     ADD CONSTRAINT workspace_agent_metadata_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_agent_metadata_history
+    ADD CONSTRAINT workspace_agent_metadata_history_workspace_agent_id_fkey FOREIGN KEY (workspace_agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_agent_ssh_host_keys
+    ADD CONSTRAINT workspace_agent_ssh_host_keys_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_agent_logs
     ADD CONSTRAINT workspace_agent_startup_logs_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
@@ -1315,12 +2166,30 @@ ALTER TABLE ONLY workspace_app_stats
 ALTER TABLE ONLY workspace_app_stats
     ADD CONSTRAINT workspace_app_stats_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id);
 
+ALTER TABLE ONLY workspace_approvals
+    ADD CONSTRAINT workspace_approvals_decided_by_fkey FOREIGN KEY (decided_by) REFERENCES users(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY workspace_approvals
+    ADD CONSTRAINT workspace_approvals_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_approvals
+    ADD CONSTRAINT workspace_approvals_requested_by_fkey FOREIGN KEY (requested_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_approvals
+    ADD CONSTRAINT workspace_approvals_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_apps
     ADD CONSTRAINT workspace_apps_agent_id_fkey FOREIGN KEY (agent_id) REFERENCES workspace_agents(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY workspace_build_parameters
     ADD CONSTRAINT workspace_build_parameters_workspace_build_id_fkey FOREIGN KEY (workspace_build_id) REFERENCES workspace_builds(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_build_queue_entries
+    ADD CONSTRAINT workspace_build_queue_entries_initiator_id_fkey FOREIGN KEY (initiator_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_build_queue_entries
+    ADD CONSTRAINT workspace_build_queue_entries_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
@@ -1330,12 +2199,24 @@ ALTER TABLE ONLY workspace_builds
 ALTER TABLE ONLY workspace_builds
     ADD CONSTRAINT workspace_builds_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_external_metadata
+    ADD CONSTRAINT workspace_external_metadata_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_previous_names
+    ADD CONSTRAINT workspace_previous_names_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspace_resource_metadata
     ADD CONSTRAINT workspace_resource_metadata_workspace_resource_id_fkey FOREIGN KEY (workspace_resource_id) REFERENCES workspace_resources(id) ON DELETE CASCADE;
 
 ALTER TABLE ONLY workspace_resources
     ADD CONSTRAINT workspace_resources_job_id_fkey FOREIGN KEY (job_id) REFERENCES provisioner_jobs(id) ON DELETE CASCADE;
 
+ALTER TABLE ONLY workspace_webhooks
+    ADD CONSTRAINT workspace_webhooks_created_by_fkey FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE ONLY workspace_webhooks
+    ADD CONSTRAINT workspace_webhooks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY workspaces
     ADD CONSTRAINT workspaces_organization_id_fkey FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT;
 
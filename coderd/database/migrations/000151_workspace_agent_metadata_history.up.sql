@@ -0,0 +1,12 @@
+CREATE UNLOGGED TABLE workspace_agent_metadata_history (
+	id BIGSERIAL PRIMARY KEY,
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	key varchar(127) NOT NULL,
+	value varchar(65535) NOT NULL DEFAULT '',
+	error varchar(65535) NOT NULL DEFAULT '',
+	collected_at timestamptz NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_metadata_history IS 'A bounded history of the values reported for each workspace agent metadata key';
+
+CREATE INDEX workspace_agent_metadata_history_workspace_agent_id_key_idx ON workspace_agent_metadata_history (workspace_agent_id, key, id DESC);
@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE workspace_apps
+	DROP COLUMN hidden,
+	DROP COLUMN display_order;
+
+ALTER TABLE workspace_agents
+	DROP COLUMN display_order;
+
+ALTER TABLE workspace_resources
+	DROP COLUMN display_order;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+ALTER TABLE workspace_resources
+	ADD COLUMN display_order integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN workspace_resources.display_order IS 'Specifies the order in which resources are displayed, lowest first.';
+
+ALTER TABLE workspace_agents
+	ADD COLUMN display_order integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN workspace_agents.display_order IS 'Specifies the order in which agents are displayed within their resource, lowest first.';
+
+ALTER TABLE workspace_apps
+	ADD COLUMN display_order integer NOT NULL DEFAULT 0,
+	ADD COLUMN hidden boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN workspace_apps.display_order IS 'Specifies the order in which apps are displayed within their agent, lowest first.';
+
+COMMENT ON COLUMN workspace_apps.hidden IS 'Determines if the app is not shown in user interfaces.';
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE template_version_parameters DROP COLUMN dependencies;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE template_version_parameters ADD COLUMN dependencies jsonb NOT NULL DEFAULT '[]'::jsonb;
+
+COMMENT ON COLUMN template_version_parameters.dependencies IS 'Rules restricting the parameter based on the values of other parameters';
+
+COMMIT;
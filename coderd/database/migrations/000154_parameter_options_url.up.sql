@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE template_version_parameters ADD COLUMN options_url text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN template_version_parameters.options_url IS 'Endpoint returning the options of the parameter, replacing the static options';
+
+COMMIT;
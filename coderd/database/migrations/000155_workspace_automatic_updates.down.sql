@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE workspaces DROP COLUMN automatic_updates;
+
+DROP TYPE automatic_updates;
+
+COMMIT;
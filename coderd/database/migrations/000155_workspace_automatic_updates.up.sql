@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TYPE automatic_updates AS ENUM (
+	'always',
+	'never'
+);
+
+ALTER TABLE workspaces
+	ADD COLUMN automatic_updates automatic_updates NOT NULL DEFAULT 'never';
+
+COMMENT ON COLUMN workspaces.automatic_updates IS 'Determines if the workspace is started with the active template version when it is outdated.';
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+ALTER TABLE templates
+	ADD COLUMN require_active_version boolean NOT NULL DEFAULT false,
+	ADD COLUMN require_active_version_grace_period bigint NOT NULL DEFAULT 0,
+	ADD COLUMN active_version_updated_at timestamp with time zone NOT NULL DEFAULT now();
+
+COMMENT ON COLUMN templates.require_active_version IS 'Outdated workspaces are updated to the active version of the template when they are started.';
+COMMENT ON COLUMN templates.require_active_version_grace_period IS 'The duration after the active version changes during which outdated workspaces can still be started with their current version.';
+COMMENT ON COLUMN templates.active_version_updated_at IS 'The time the active version of the template was last changed.';
+
+-- Update the template_with_users view by recreating it.
+DROP VIEW template_with_users;
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+ALTER TABLE templates
+	ADD COLUMN max_build_duration bigint NOT NULL DEFAULT 0,
+	ADD COLUMN provisioner_memory_limit bigint NOT NULL DEFAULT 0,
+	ADD COLUMN provisioner_cpu_limit bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN templates.max_build_duration IS 'The maximum duration of a provisioner job for the template. Jobs running longer are canceled. 0 means no limit.';
+COMMENT ON COLUMN templates.provisioner_memory_limit IS 'The maximum memory in bytes the provisioner can use for a job of the template. 0 means no limit.';
+COMMENT ON COLUMN templates.provisioner_cpu_limit IS 'The maximum CPU time the provisioner can use for a job of the template. 0 means no limit.';
+
+-- Update the template_with_users view by recreating it.
+DROP VIEW template_with_users;
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+COMMIT;
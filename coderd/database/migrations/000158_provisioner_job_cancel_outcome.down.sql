@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE provisioner_jobs DROP COLUMN cancel_outcome;
+
+DROP TYPE provisioner_job_cancel_outcome;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TYPE provisioner_job_cancel_outcome AS ENUM (
+	'graceful',
+	'forced'
+);
+
+ALTER TABLE provisioner_jobs
+	ADD COLUMN cancel_outcome provisioner_job_cancel_outcome;
+
+COMMENT ON COLUMN provisioner_jobs.cancel_outcome IS 'How the job stopped after it was canceled. graceful means the provisioner stopped at a safe point, forced means it was killed after the force cancel interval.';
+
+COMMIT;
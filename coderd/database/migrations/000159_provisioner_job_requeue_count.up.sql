@@ -0,0 +1,4 @@
+ALTER TABLE provisioner_jobs
+	ADD COLUMN requeue_count integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN provisioner_jobs.requeue_count IS 'The number of times the job was put back in the queue after being detected as hung.';
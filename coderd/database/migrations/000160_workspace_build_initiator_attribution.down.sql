@@ -0,0 +1,28 @@
+BEGIN;
+
+-- Delete the new version of the workspace_build_with_user view to remove the
+-- column dependency.
+DROP VIEW workspace_build_with_user;
+
+ALTER TABLE workspace_builds DROP COLUMN initiator_token_name;
+
+-- It's not possible to delete enum values, so 'bulk' is kept in build_reason.
+
+-- Restore the old version of the workspace_build_with_user view.
+CREATE VIEW
+	workspace_build_with_user
+AS
+SELECT
+	workspace_builds.*,
+	coalesce(visible_users.avatar_url, '') AS initiator_by_avatar_url,
+	coalesce(visible_users.username, '') AS initiator_by_username
+FROM
+	workspace_builds
+	LEFT JOIN
+		visible_users
+	ON
+		workspace_builds.initiator_id = visible_users.id;
+
+COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
+
+COMMIT;
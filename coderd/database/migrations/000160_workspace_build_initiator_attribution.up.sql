@@ -0,0 +1,28 @@
+BEGIN;
+
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'bulk';
+
+ALTER TABLE workspace_builds
+	ADD COLUMN initiator_token_name text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN workspace_builds.initiator_token_name IS 'The name of the API token the initiator used to start the build. Empty if the build was not started with a named token.';
+
+-- Update the workspace_build_with_user view by recreating it.
+DROP VIEW workspace_build_with_user;
+CREATE VIEW
+	workspace_build_with_user
+AS
+SELECT
+	workspace_builds.*,
+	coalesce(visible_users.avatar_url, '') AS initiator_by_avatar_url,
+	coalesce(visible_users.username, '') AS initiator_by_username
+FROM
+	workspace_builds
+	LEFT JOIN
+		visible_users
+	ON
+		workspace_builds.initiator_id = visible_users.id;
+
+COMMENT ON VIEW workspace_build_with_user IS 'Joins in the username + avatar url of the initiated by user.';
+
+COMMIT;
@@ -0,0 +1,15 @@
+CREATE TABLE workspace_agent_client_connections (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	user_id uuid REFERENCES users (id) ON DELETE CASCADE,
+	client_type text NOT NULL,
+	client_version text NOT NULL,
+	connected_at timestamptz NOT NULL,
+	disconnected_at timestamptz
+);
+
+COMMENT ON TABLE workspace_agent_client_connections IS 'Connections made to workspace agents by clients such as the CLI and IDE plugins';
+
+COMMENT ON COLUMN workspace_agent_client_connections.user_id IS 'The user the client connected as. Null if the client is a workspace proxy.';
+
+CREATE INDEX workspace_agent_client_connections_workspace_agent_id_connected_at_idx ON workspace_agent_client_connections (workspace_agent_id, connected_at DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS workspace_agent_client_connections_user_id_connected_at_idx;
+
+ALTER TABLE workspace_agent_client_connections DROP COLUMN ip_address;
@@ -0,0 +1,5 @@
+ALTER TABLE workspace_agent_client_connections ADD COLUMN ip_address inet;
+
+COMMENT ON COLUMN workspace_agent_client_connections.ip_address IS 'The IP address the client connected from.';
+
+CREATE INDEX workspace_agent_client_connections_user_id_connected_at_idx ON workspace_agent_client_connections (user_id, connected_at DESC);
@@ -0,0 +1,3 @@
+DROP TABLE workspace_external_metadata;
+
+DROP TYPE workspace_external_metadata_visibility;
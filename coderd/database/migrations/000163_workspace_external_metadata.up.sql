@@ -0,0 +1,17 @@
+CREATE TYPE workspace_external_metadata_visibility AS ENUM (
+	'public',
+	'private'
+);
+
+CREATE TABLE workspace_external_metadata (
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	namespace text NOT NULL,
+	visibility workspace_external_metadata_visibility NOT NULL DEFAULT 'public'::workspace_external_metadata_visibility,
+	data jsonb NOT NULL,
+	updated_at timestamptz NOT NULL,
+	PRIMARY KEY (workspace_id, namespace)
+);
+
+COMMENT ON TABLE workspace_external_metadata IS 'Namespaced JSON attached to workspaces by external integrations';
+
+COMMENT ON COLUMN workspace_external_metadata.visibility IS 'Public metadata is visible to anyone who can read the workspace. Private metadata is only visible to users who can read all workspaces in the organization.';
@@ -0,0 +1 @@
+DROP TABLE platform_events;
@@ -0,0 +1,11 @@
+CREATE TABLE platform_events (
+	id bigserial PRIMARY KEY,
+	created_at timestamptz NOT NULL,
+	type text NOT NULL,
+	resource_id uuid NOT NULL,
+	data jsonb NOT NULL
+);
+
+COMMENT ON TABLE platform_events IS 'Significant domain events streamed to platform integrations. The id is used as a resume cursor.';
+
+CREATE INDEX platform_events_created_at_idx ON platform_events (created_at);
@@ -0,0 +1,6 @@
+DROP TABLE workspace_webhooks;
+
+DROP TYPE workspace_webhook_action;
+
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
@@ -0,0 +1,27 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'workspace_webhook';
+
+ALTER TYPE build_reason ADD VALUE IF NOT EXISTS 'webhook';
+
+CREATE TYPE workspace_webhook_action AS ENUM ('start', 'stop', 'update', 'exec');
+
+CREATE TABLE workspace_webhooks (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	created_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL,
+	name text NOT NULL,
+	action workspace_webhook_action NOT NULL,
+	agent_name text NOT NULL DEFAULT '',
+	command text NOT NULL DEFAULT '',
+	secret text NOT NULL,
+	last_triggered_at timestamptz,
+	UNIQUE (workspace_id, name)
+);
+
+COMMENT ON TABLE workspace_webhooks IS 'Inbound webhooks that let external systems trigger a fixed action on a workspace.';
+
+COMMENT ON COLUMN workspace_webhooks.created_by IS 'Triggered actions run with the permissions of this user.';
+
+COMMENT ON COLUMN workspace_webhooks.agent_name IS 'The agent that runs the command of an exec webhook. Empty if the workspace has a single agent.';
+
+COMMENT ON COLUMN workspace_webhooks.secret IS 'Shared secret that trigger requests are signed with using HMAC-SHA256.';
@@ -0,0 +1,28 @@
+BEGIN;
+
+DROP TABLE workspace_approvals;
+DROP TYPE workspace_approval_status;
+
+-- Delete the new version of the template_with_users view to remove the column
+-- dependency.
+DROP VIEW template_with_users;
+
+ALTER TABLE templates DROP COLUMN require_workspace_approval;
+
+-- Restore the old version of the template_with_users view.
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+COMMIT;
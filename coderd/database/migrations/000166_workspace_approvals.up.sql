@@ -0,0 +1,48 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'workspace_approval';
+
+BEGIN;
+
+ALTER TABLE templates ADD COLUMN require_workspace_approval boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.require_workspace_approval IS 'Workspaces created from the template are held until their first build is approved.';
+
+-- Update the template_with_users view by recreating it.
+DROP VIEW template_with_users;
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+CREATE TYPE workspace_approval_status AS ENUM ('pending', 'approved', 'rejected');
+
+CREATE TABLE workspace_approvals (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	job_id uuid NOT NULL UNIQUE REFERENCES provisioner_jobs (id) ON DELETE CASCADE,
+	requested_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL,
+	status workspace_approval_status NOT NULL DEFAULT 'pending',
+	decided_by uuid REFERENCES users (id) ON DELETE SET NULL,
+	decided_at timestamptz,
+	reason text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX workspace_approvals_workspace_id_idx ON workspace_approvals (workspace_id);
+
+COMMENT ON TABLE workspace_approvals IS 'Approval requests for workspace builds that are held until an approver decides on them.';
+
+COMMENT ON COLUMN workspace_approvals.job_id IS 'The provisioner job of the held build. Provisioners only acquire it once the request is approved.';
+
+COMMENT ON COLUMN workspace_approvals.reason IS 'An optional explanation of the decision, such as a change ticket number.';
+
+COMMIT;
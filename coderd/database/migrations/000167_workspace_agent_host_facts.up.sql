@@ -0,0 +1,3 @@
+ALTER TABLE workspace_agents ADD COLUMN host_facts jsonb NOT NULL DEFAULT '{}'::jsonb;
+
+COMMENT ON COLUMN workspace_agents.host_facts IS 'Facts about the host reported by the workspace agent on startup, such as the Windows version and build.';
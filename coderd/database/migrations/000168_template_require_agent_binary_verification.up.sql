@@ -0,0 +1,24 @@
+BEGIN;
+
+ALTER TABLE templates ADD COLUMN require_agent_binary_verification boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.require_agent_binary_verification IS 'Agents of workspaces created from the template fail to start if their binary does not match the checksum served by the deployment.';
+
+-- Update the template_with_users view by recreating it.
+DROP VIEW template_with_users;
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+COMMIT;
@@ -0,0 +1,4 @@
+DROP TABLE managed_environment_variables;
+
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
@@ -0,0 +1,22 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'environment_variable';
+
+CREATE TABLE managed_environment_variables (
+	id uuid NOT NULL PRIMARY KEY,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	template_id uuid REFERENCES templates (id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL,
+	name text NOT NULL,
+	value text NOT NULL,
+	secret boolean NOT NULL DEFAULT false
+);
+
+COMMENT ON TABLE managed_environment_variables IS 'Environment variables that are injected into every agent of the workspaces of an organization or template.';
+
+COMMENT ON COLUMN managed_environment_variables.template_id IS 'The template whose workspaces receive the variable. If null, the variable applies to every workspace of the organization.';
+
+COMMENT ON COLUMN managed_environment_variables.secret IS 'Secret values are never returned by the API.';
+
+CREATE UNIQUE INDEX managed_environment_variables_organization_name_idx ON managed_environment_variables USING btree (organization_id, name) WHERE (template_id IS NULL);
+
+CREATE UNIQUE INDEX managed_environment_variables_template_name_idx ON managed_environment_variables USING btree (template_id, name) WHERE (template_id IS NOT NULL);
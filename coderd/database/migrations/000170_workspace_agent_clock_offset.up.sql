@@ -0,0 +1,3 @@
+ALTER TABLE workspace_agents ADD COLUMN clock_offset_ms bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN workspace_agents.clock_offset_ms IS 'The offset of the clock of the workspace agent from the clock of coderd in milliseconds, as last reported by the agent. Positive values mean the agent clock is ahead.';
@@ -0,0 +1 @@
+DROP TABLE workspace_build_queue_entries;
@@ -0,0 +1,14 @@
+CREATE TABLE workspace_build_queue_entries (
+	id uuid NOT NULL PRIMARY KEY,
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	initiator_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	initiator_token_name text NOT NULL DEFAULT '',
+	created_at timestamptz NOT NULL,
+	request jsonb NOT NULL
+);
+
+COMMENT ON TABLE workspace_build_queue_entries IS 'Workspace build requests waiting for the active build of their workspace to finish.';
+
+COMMENT ON COLUMN workspace_build_queue_entries.request IS 'The request the build is created with once the workspace has no active build.';
+
+CREATE INDEX workspace_build_queue_entries_workspace_id_created_at_idx ON workspace_build_queue_entries USING btree (workspace_id, created_at);
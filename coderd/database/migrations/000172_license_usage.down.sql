@@ -0,0 +1 @@
+DROP TABLE license_usage;
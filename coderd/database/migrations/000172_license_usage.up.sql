@@ -0,0 +1,20 @@
+CREATE TABLE license_usage (
+	feature text NOT NULL,
+	date date NOT NULL,
+	peak_actual bigint NOT NULL,
+	latest_actual bigint NOT NULL,
+	feature_limit bigint,
+	first_exceeded_at timestamptz,
+	updated_at timestamptz NOT NULL,
+	PRIMARY KEY (feature, date)
+);
+
+COMMENT ON TABLE license_usage IS 'Daily usage of licensed features that have a measured consumption, such as the user limit.';
+
+COMMENT ON COLUMN license_usage.date IS 'The UTC day the usage was recorded on.';
+
+COMMENT ON COLUMN license_usage.peak_actual IS 'The highest usage recorded during the day.';
+
+COMMENT ON COLUMN license_usage.feature_limit IS 'The licensed limit at the time of the last recording, if the feature is limited.';
+
+COMMENT ON COLUMN license_usage.first_exceeded_at IS 'The first time during the day that usage exceeded the licensed limit.';
@@ -0,0 +1 @@
+DROP TABLE workspace_previous_names;
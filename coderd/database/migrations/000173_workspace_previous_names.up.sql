@@ -0,0 +1,13 @@
+CREATE TABLE workspace_previous_names (
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	renamed_at timestamptz NOT NULL,
+	redirect_expires_at timestamptz NOT NULL,
+	PRIMARY KEY (workspace_id, name)
+);
+
+COMMENT ON TABLE workspace_previous_names IS 'Names workspaces had before they were renamed.';
+
+COMMENT ON COLUMN workspace_previous_names.redirect_expires_at IS 'Until this time, app URLs and lookups using the previous name resolve to the renamed workspace.';
+
+CREATE INDEX workspace_previous_names_name_idx ON workspace_previous_names USING btree (lower(name));
@@ -0,0 +1,6 @@
+CREATE TABLE user_deprovisions (
+	user_id uuid NOT NULL PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+	deprovisioned_at timestamptz NOT NULL
+);
+
+COMMENT ON TABLE user_deprovisions IS 'Users the identity provider deactivated through SCIM. The deprovisioning policy is applied to these users until they are reactivated or deleted.';
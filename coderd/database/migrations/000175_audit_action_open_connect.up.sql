@@ -0,0 +1,2 @@
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'open';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'connect';
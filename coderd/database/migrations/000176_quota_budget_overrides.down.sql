@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE user_quota_overrides;
+
+ALTER TABLE groups DROP COLUMN quota_override;
+
+COMMIT;
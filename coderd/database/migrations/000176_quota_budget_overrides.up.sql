@@ -0,0 +1,16 @@
+BEGIN;
+
+ALTER TABLE groups ADD COLUMN quota_override integer;
+
+COMMENT ON COLUMN groups.quota_override IS 'Quota override replaces the summed quota allowances of members of the group. If a user is a member of several groups with an override, the largest override applies.';
+
+CREATE TABLE user_quota_overrides (
+	user_id uuid NOT NULL PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+	quota_allowance integer NOT NULL,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL
+);
+
+COMMENT ON TABLE user_quota_overrides IS 'Per-user quota allowances. An override takes precedence over any quota the user receives from groups.';
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+CREATE TABLE workspace_agent_bandwidth_stats (
+	id uuid NOT NULL PRIMARY KEY,
+	created_at timestamptz NOT NULL,
+	user_id uuid NOT NULL,
+	agent_id uuid NOT NULL,
+	workspace_id uuid NOT NULL,
+	template_id uuid NOT NULL,
+	connection_type text NOT NULL,
+	peer text NOT NULL,
+	port integer DEFAULT 0 NOT NULL,
+	rx_bytes bigint DEFAULT 0 NOT NULL,
+	tx_bytes bigint DEFAULT 0 NOT NULL
+);
+
+COMMENT ON TABLE workspace_agent_bandwidth_stats IS 'Traffic of workspace agent connections by connection type and peer. Rows are purged with workspace_agent_stats.';
+COMMENT ON COLUMN workspace_agent_bandwidth_stats.peer IS 'Tailnet IP address of the remote end of the connections.';
+COMMENT ON COLUMN workspace_agent_bandwidth_stats.port IS 'Agent port of port forwarded connections, 0 for other connection types.';
+
+CREATE INDEX idx_workspace_agent_bandwidth_stats_created_at ON workspace_agent_bandwidth_stats USING btree (created_at);
+
+COMMIT;
@@ -0,0 +1 @@
+ALTER TABLE replicas DROP COLUMN draining_at;
@@ -0,0 +1,3 @@
+ALTER TABLE replicas ADD COLUMN draining_at timestamp with time zone;
+
+COMMENT ON COLUMN replicas.draining_at IS 'The time the replica started draining. A draining replica doesn''t accept new tailnet connections or provisioner jobs.';
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE groups DROP COLUMN metadata;
+ALTER TABLE groups DROP COLUMN avatar_file_id;
+
+COMMIT;
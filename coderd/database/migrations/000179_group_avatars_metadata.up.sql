@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE groups ADD COLUMN avatar_file_id uuid REFERENCES files (id) ON DELETE SET NULL;
+ALTER TABLE groups ADD COLUMN metadata jsonb DEFAULT '{}'::jsonb NOT NULL;
+
+COMMENT ON COLUMN groups.avatar_file_id IS 'The uploaded avatar of the group. It is cleared when the avatar URL is changed to something else.';
+
+COMMENT ON COLUMN groups.metadata IS 'Arbitrary key-value metadata of the group, such as the team or cost center it represents.';
+
+COMMIT;
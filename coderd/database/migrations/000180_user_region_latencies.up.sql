@@ -0,0 +1,11 @@
+CREATE TABLE user_region_latencies (
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	region_id uuid NOT NULL,
+	latency_ms double precision NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (user_id, region_id)
+);
+
+COMMENT ON TABLE user_region_latencies IS 'The latest latency each user reported for each region. Used to rank the regions a user should connect through.';
+
+COMMENT ON COLUMN user_region_latencies.region_id IS 'The ID of the workspace proxy, or the deployment ID for the primary region.';
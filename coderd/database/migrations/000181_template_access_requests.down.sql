@@ -0,0 +1,2 @@
+DROP TABLE template_access_requests;
+DROP TYPE template_access_request_status;
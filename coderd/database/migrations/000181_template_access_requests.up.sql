@@ -0,0 +1,30 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'template_access_request';
+
+BEGIN;
+
+CREATE TYPE template_access_request_status AS ENUM ('pending', 'approved', 'denied');
+
+CREATE TABLE template_access_requests (
+	id uuid NOT NULL PRIMARY KEY,
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	requested_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL,
+	message text NOT NULL DEFAULT '',
+	status template_access_request_status NOT NULL DEFAULT 'pending',
+	decided_by uuid REFERENCES users (id) ON DELETE SET NULL,
+	decided_at timestamptz,
+	reason text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX template_access_requests_template_id_idx ON template_access_requests (template_id);
+
+-- A user can only have one pending request per template.
+CREATE UNIQUE INDEX template_access_requests_pending_idx ON template_access_requests (template_id, requested_by) WHERE status = 'pending';
+
+COMMENT ON TABLE template_access_requests IS 'Requests of users to be granted use of a template, decided by the template admins.';
+
+COMMENT ON COLUMN template_access_requests.message IS 'An optional explanation from the requester of why they need access.';
+
+COMMENT ON COLUMN template_access_requests.reason IS 'An optional explanation of the decision.';
+
+COMMIT;
@@ -0,0 +1,53 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'template_version_promotion';
+
+BEGIN;
+
+ALTER TABLE templates ADD COLUMN require_promotion_approval boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN templates.require_promotion_approval IS 'New versions of the template only become active once a template approver approves their promotion.';
+
+-- Update the template_with_users view by recreating it.
+DROP VIEW template_with_users;
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+CREATE TYPE template_version_promotion_status AS ENUM ('pending', 'approved', 'rejected');
+
+CREATE TABLE template_version_promotions (
+	id uuid NOT NULL PRIMARY KEY,
+	organization_id uuid NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	template_version_id uuid NOT NULL REFERENCES template_versions (id) ON DELETE CASCADE,
+	requested_by uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL,
+	message text NOT NULL DEFAULT '',
+	status template_version_promotion_status NOT NULL DEFAULT 'pending',
+	decided_by uuid REFERENCES users (id) ON DELETE SET NULL,
+	decided_at timestamptz,
+	reason text NOT NULL DEFAULT ''
+);
+
+CREATE INDEX template_version_promotions_template_id_idx ON template_version_promotions (template_id);
+
+-- A version can only have one pending promotion.
+CREATE UNIQUE INDEX template_version_promotions_pending_idx ON template_version_promotions (template_version_id) WHERE status = 'pending';
+
+COMMENT ON TABLE template_version_promotions IS 'Requests to make a template version the active version, decided by template approvers.';
+
+COMMENT ON COLUMN template_version_promotions.message IS 'An optional description from the requester of the changes in the version.';
+
+COMMENT ON COLUMN template_version_promotions.reason IS 'An optional explanation of the decision, such as a change ticket number.';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE workspace_proxies
+	DROP COLUMN organization_ids,
+	DROP COLUMN group_ids;
+
+COMMIT;
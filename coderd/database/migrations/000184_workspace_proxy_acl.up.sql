@@ -0,0 +1,10 @@
+BEGIN;
+
+ALTER TABLE workspace_proxies
+	ADD COLUMN organization_ids uuid[] NOT NULL DEFAULT '{}',
+	ADD COLUMN group_ids uuid[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN workspace_proxies.organization_ids IS 'Members of these organizations may use the proxy. The proxy is available to everyone when both organization_ids and group_ids are empty.';
+COMMENT ON COLUMN workspace_proxies.group_ids IS 'Members of these groups may use the proxy. The proxy is available to everyone when both organization_ids and group_ids are empty.';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE audit_log_archivals;
+DROP TYPE audit_log_archival_status;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+CREATE TYPE audit_log_archival_status AS ENUM ('running', 'succeeded', 'failed');
+
+CREATE TABLE audit_log_archivals (
+	id uuid NOT NULL PRIMARY KEY,
+	initiator_id uuid REFERENCES users (id) ON DELETE SET NULL,
+	status audit_log_archival_status NOT NULL DEFAULT 'running',
+	cutoff timestamptz NOT NULL,
+	destination text NOT NULL,
+	archived_count bigint NOT NULL DEFAULT 0,
+	error text NOT NULL DEFAULT '',
+	started_at timestamptz NOT NULL,
+	finished_at timestamptz
+);
+
+COMMENT ON TABLE audit_log_archivals IS 'Runs of the audit log archiver, which exports the audit logs older than the retention window before deleting them.';
+COMMENT ON COLUMN audit_log_archivals.initiator_id IS 'The user that started the run. Null for scheduled runs.';
+COMMENT ON COLUMN audit_log_archivals.cutoff IS 'Audit logs older than the cutoff are archived by the run.';
+COMMENT ON COLUMN audit_log_archivals.destination IS 'Where the audit logs are exported to. Empty if they are deleted without being exported.';
+
+CREATE INDEX audit_log_archivals_started_at_idx ON audit_log_archivals (started_at DESC);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE FUNCTION pg_temp.replace_acl_action(acl jsonb, old_action text, new_action text) RETURNS jsonb AS $$
+	SELECT COALESCE(jsonb_object_agg(
+		key,
+		CASE WHEN value ? old_action THEN (value - old_action) || to_jsonb(new_action) ELSE value END
+	), '{}'::jsonb)
+	FROM jsonb_each(acl)
+$$ LANGUAGE sql;
+
+UPDATE templates SET
+	user_acl = pg_temp.replace_acl_action(user_acl, 'push_version', 'create'),
+	group_acl = pg_temp.replace_acl_action(group_acl, 'push_version', 'create');
+
+COMMIT;
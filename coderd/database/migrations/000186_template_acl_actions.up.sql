@@ -0,0 +1,17 @@
+BEGIN;
+
+-- The push and settings template roles granted the create action to push
+-- versions, which now has its own action.
+CREATE FUNCTION pg_temp.replace_acl_action(acl jsonb, old_action text, new_action text) RETURNS jsonb AS $$
+	SELECT COALESCE(jsonb_object_agg(
+		key,
+		CASE WHEN value ? old_action THEN (value - old_action) || to_jsonb(new_action) ELSE value END
+	), '{}'::jsonb)
+	FROM jsonb_each(acl)
+$$ LANGUAGE sql;
+
+UPDATE templates SET
+	user_acl = pg_temp.replace_acl_action(user_acl, 'create', 'push_version'),
+	group_acl = pg_temp.replace_acl_action(group_acl, 'create', 'push_version');
+
+COMMIT;
@@ -0,0 +1,65 @@
+BEGIN;
+
+ALTER TABLE templates ADD COLUMN max_concurrent_provisioner_jobs integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN templates.max_concurrent_provisioner_jobs IS 'The maximum number of provisioner jobs of the template that run at once. Further jobs wait in the queue. 0 means no limit.';
+
+-- Update the template_with_users view by recreating it.
+DROP VIEW template_with_users;
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+ALTER TABLE organizations ADD COLUMN max_concurrent_provisioner_jobs integer NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN organizations.max_concurrent_provisioner_jobs IS 'The maximum number of provisioner jobs of the organization that run at once. Further jobs wait in the queue. 0 means no limit.';
+
+-- Template imports are linked to their template through the template version,
+-- and workspace builds through the workspace.
+CREATE FUNCTION provisioner_job_template_id(job_id uuid) RETURNS uuid
+    LANGUAGE sql STABLE
+    AS $$
+	SELECT template_versions.template_id FROM template_versions WHERE template_versions.job_id = $1
+	UNION ALL
+	SELECT workspaces.template_id FROM workspace_builds JOIN workspaces ON workspaces.id = workspace_builds.workspace_id WHERE workspace_builds.job_id = $1
+	LIMIT 1
+$$;
+
+-- Returns 'template' when the template of the job already runs as many jobs as
+-- its concurrency limit allows, 'organization' when the organization of the job
+-- does, and '' otherwise.
+CREATE FUNCTION provisioner_job_concurrency_limit(job_id uuid) RETURNS text
+    LANGUAGE sql STABLE
+    AS $$
+	WITH job AS (
+		SELECT organization_id, provisioner_job_template_id(id) AS template_id FROM provisioner_jobs WHERE id = $1
+	), running AS (
+		SELECT organization_id, provisioner_job_template_id(id) AS template_id FROM provisioner_jobs WHERE started_at IS NOT NULL AND completed_at IS NULL
+	)
+	SELECT CASE
+		WHEN EXISTS (
+			SELECT 1 FROM job JOIN templates ON templates.id = job.template_id
+			WHERE templates.max_concurrent_provisioner_jobs > 0
+				AND templates.max_concurrent_provisioner_jobs <= (SELECT COUNT(*) FROM running WHERE running.template_id = job.template_id)
+		) THEN 'template'
+		WHEN EXISTS (
+			SELECT 1 FROM job JOIN organizations ON organizations.id = job.organization_id
+			WHERE organizations.max_concurrent_provisioner_jobs > 0
+				AND organizations.max_concurrent_provisioner_jobs <= (SELECT COUNT(*) FROM running WHERE running.organization_id = job.organization_id)
+		) THEN 'organization'
+		ELSE ''
+	END
+$$;
+
+COMMIT;
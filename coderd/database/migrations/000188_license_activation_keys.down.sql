@@ -0,0 +1 @@
+ALTER TABLE licenses DROP COLUMN activation_key;
@@ -0,0 +1,3 @@
+ALTER TABLE licenses ADD COLUMN activation_key text NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN licenses.activation_key IS 'The key the license was activated with online, or empty if it was added as a JWT. Licenses with a key are renewed from the activation server before they expire.';
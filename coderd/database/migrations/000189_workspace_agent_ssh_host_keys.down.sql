@@ -0,0 +1 @@
+DROP TABLE workspace_agent_ssh_host_keys;
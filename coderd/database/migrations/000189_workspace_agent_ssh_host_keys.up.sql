@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE workspace_agent_ssh_host_keys (
+	workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+	agent_name text NOT NULL,
+	encrypted_private_key text NOT NULL,
+	created_at timestamptz NOT NULL,
+	PRIMARY KEY (workspace_id, agent_name)
+);
+
+COMMENT ON TABLE workspace_agent_ssh_host_keys IS 'SSH host keys of workspace agents. Agents are recreated by every build, so keys are stored by workspace and agent name to present the same key across rebuilds.';
+COMMENT ON COLUMN workspace_agent_ssh_host_keys.encrypted_private_key IS 'The PEM encoded private key, encrypted with the app security key of the deployment.';
+
+COMMIT;
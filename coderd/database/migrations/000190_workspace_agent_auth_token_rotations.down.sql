@@ -0,0 +1,3 @@
+-- It's not possible to drop enum values from enum types, so the UP has "IF NOT
+-- EXISTS".
+DROP TABLE workspace_agent_auth_token_rotations;
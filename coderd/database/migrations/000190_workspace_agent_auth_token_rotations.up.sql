@@ -0,0 +1,19 @@
+ALTER TYPE resource_type ADD VALUE IF NOT EXISTS 'workspace_agent_auth_token';
+
+BEGIN;
+
+CREATE TABLE workspace_agent_auth_token_rotations (
+	id uuid NOT NULL PRIMARY KEY,
+	agent_id uuid NOT NULL REFERENCES workspace_agents (id) ON DELETE CASCADE,
+	previous_auth_token uuid NOT NULL,
+	previous_auth_token_expires_at timestamptz NOT NULL,
+	revoked boolean NOT NULL DEFAULT false,
+	created_at timestamptz NOT NULL
+);
+
+CREATE INDEX workspace_agent_auth_token_rotations_previous_auth_token_idx ON workspace_agent_auth_token_rotations (previous_auth_token);
+
+COMMENT ON TABLE workspace_agent_auth_token_rotations IS 'Replacements of the auth tokens of workspace agents. The previous token stays valid until it expires, so requests of the agent in flight during the rotation succeed.';
+COMMENT ON COLUMN workspace_agent_auth_token_rotations.revoked IS 'The previous token was revoked because it may be compromised, it expired immediately.';
+
+COMMIT;
@@ -0,0 +1,2 @@
+DROP TABLE group_default_template_workspaces;
+DROP TABLE group_default_templates;
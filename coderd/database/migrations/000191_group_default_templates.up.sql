@@ -0,0 +1,26 @@
+BEGIN;
+
+CREATE TABLE group_default_templates (
+	group_id uuid NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	create_workspace boolean NOT NULL DEFAULT false,
+	created_at timestamptz NOT NULL,
+	PRIMARY KEY (group_id, template_id)
+);
+
+COMMENT ON TABLE group_default_templates IS 'Templates members of a group get access to.';
+COMMENT ON COLUMN group_default_templates.create_workspace IS 'Members of the group get a starter workspace from the template.';
+
+CREATE TABLE group_default_template_workspaces (
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	group_id uuid NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+	workspace_id uuid REFERENCES workspaces (id) ON DELETE SET NULL,
+	created_at timestamptz NOT NULL,
+	PRIMARY KEY (template_id, user_id)
+);
+
+COMMENT ON TABLE group_default_template_workspaces IS 'Starter workspaces created for members of groups. Members get at most one starter workspace per template, even if they delete it or are in several groups with the template.';
+COMMENT ON COLUMN group_default_template_workspaces.workspace_id IS 'NULL if the workspace could not be created, for example because the template requires parameters without a default.';
+
+COMMIT;
@@ -0,0 +1 @@
+DROP TABLE netcheck_reports;
@@ -0,0 +1,28 @@
+CREATE TABLE netcheck_reports (
+	id uuid NOT NULL PRIMARY KEY,
+	user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	created_at timestamptz NOT NULL,
+	client_version text NOT NULL,
+	client_os text NOT NULL,
+	nat_type text NOT NULL,
+	udp boolean NOT NULL,
+	ipv4 boolean NOT NULL,
+	ipv6 boolean NOT NULL,
+	mapping_varies_by_dest_ip boolean,
+	hair_pinning boolean,
+	preferred_derp integer NOT NULL,
+	region_latencies jsonb NOT NULL DEFAULT '{}'::jsonb,
+	blocked_ports text[] NOT NULL DEFAULT '{}'::text[]
+);
+
+COMMENT ON TABLE netcheck_reports IS 'Connectivity preflights reported by clients. Used to correlate connection issues with the networks of users.';
+
+COMMENT ON COLUMN netcheck_reports.nat_type IS 'One of unknown, udp_blocked, easy or hard. Direct connections are unlikely to succeed behind hard NATs, where the mapping varies by destination.';
+
+COMMENT ON COLUMN netcheck_reports.region_latencies IS 'The latencies in milliseconds to the DERP regions the client reached, keyed by region ID.';
+
+COMMENT ON COLUMN netcheck_reports.blocked_ports IS 'The ports the client could not reach, e.g. udp/3478 when STUN is blocked.';
+
+CREATE INDEX netcheck_reports_created_at_idx ON netcheck_reports (created_at DESC);
+
+CREATE INDEX netcheck_reports_user_id_created_at_idx ON netcheck_reports (user_id, created_at DESC);
@@ -0,0 +1,24 @@
+BEGIN;
+
+ALTER TABLE templates ADD COLUMN max_session_idle bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN templates.max_session_idle IS 'How long web terminal and app sessions of the workspaces of the template may be idle before they are disconnected. 0 means sessions are never disconnected.';
+
+-- Update the template_with_users view by recreating it.
+DROP VIEW template_with_users;
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+COMMIT;
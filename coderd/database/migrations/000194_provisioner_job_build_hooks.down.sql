@@ -0,0 +1,3 @@
+DROP TABLE provisioner_job_build_hooks;
+
+DROP TYPE build_hook_phase;
@@ -0,0 +1,24 @@
+CREATE TYPE build_hook_phase AS ENUM (
+	'pre',
+	'post'
+);
+
+CREATE TABLE provisioner_job_build_hooks (
+	id uuid NOT NULL PRIMARY KEY,
+	job_id uuid NOT NULL REFERENCES provisioner_jobs (id) ON DELETE CASCADE,
+	name text NOT NULL,
+	phase build_hook_phase NOT NULL,
+	started_at timestamptz NOT NULL,
+	completed_at timestamptz NOT NULL,
+	attempts integer NOT NULL,
+	exit_code integer NOT NULL,
+	error text NOT NULL DEFAULT ''
+);
+
+COMMENT ON TABLE provisioner_job_build_hooks IS 'The outcomes of the pre-build and post-build hooks of templates, run by provisioner daemons around workspace builds.';
+
+COMMENT ON COLUMN provisioner_job_build_hooks.attempts IS 'How many times the hook ran, including retries.';
+
+COMMENT ON COLUMN provisioner_job_build_hooks.exit_code IS 'The exit code of the last attempt. -1 if the hook was killed, e.g. when it timed out.';
+
+CREATE INDEX provisioner_job_build_hooks_job_id_idx ON provisioner_job_build_hooks (job_id, started_at);
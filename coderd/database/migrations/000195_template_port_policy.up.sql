@@ -0,0 +1,24 @@
+BEGIN;
+
+ALTER TABLE templates ADD COLUMN port_policy jsonb NOT NULL DEFAULT '{}'::jsonb;
+
+COMMENT ON COLUMN templates.port_policy IS 'Restricts which ports of the agents of the workspaces of the template can be forwarded, and how far apps on them can be shared.';
+
+-- Update the template_with_users view by recreating it.
+DROP VIEW template_with_users;
+CREATE VIEW
+    template_with_users
+AS
+    SELECT
+        templates.*,
+		coalesce(visible_users.avatar_url, '') AS created_by_avatar_url,
+		coalesce(visible_users.username, '') AS created_by_username
+    FROM
+        templates
+    LEFT JOIN
+		visible_users
+	ON
+	    templates.created_by = visible_users.id;
+COMMENT ON VIEW template_with_users IS 'Joins in the username + avatar url of the created by user.';
+
+COMMIT;
@@ -0,0 +1 @@
+DROP TABLE user_pty_session_overrides;
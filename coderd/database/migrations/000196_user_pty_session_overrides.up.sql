@@ -0,0 +1,9 @@
+CREATE TABLE user_pty_session_overrides (
+	user_id uuid NOT NULL PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+	max_pty_sessions integer NOT NULL,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL
+);
+
+COMMENT ON TABLE user_pty_session_overrides IS 'Per-user limits of concurrent web terminal sessions. An override takes precedence over the limit of the deployment.';
+COMMENT ON COLUMN user_pty_session_overrides.max_pty_sessions IS '0 means the user has no limit.';
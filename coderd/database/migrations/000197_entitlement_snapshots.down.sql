@@ -0,0 +1 @@
+DROP TABLE entitlement_snapshots;
@@ -0,0 +1,22 @@
+CREATE TABLE entitlement_snapshots (
+	date date NOT NULL PRIMARY KEY,
+	has_license boolean NOT NULL,
+	trial boolean NOT NULL,
+	features jsonb NOT NULL,
+	user_count bigint NOT NULL,
+	active_user_count bigint NOT NULL,
+	replica_count integer NOT NULL,
+	warnings text[] NOT NULL,
+	errors text[] NOT NULL,
+	created_at timestamptz NOT NULL
+);
+
+COMMENT ON TABLE entitlement_snapshots IS 'Daily snapshots of the entitlements of the deployment, kept as evidence of license adherence.';
+
+COMMENT ON COLUMN entitlement_snapshots.date IS 'The UTC day the snapshot was taken on.';
+
+COMMENT ON COLUMN entitlement_snapshots.features IS 'The entitlement, enablement, limit and actual usage of every feature, by feature name.';
+
+COMMENT ON COLUMN entitlement_snapshots.user_count IS 'The number of users that are not deleted, including suspended users.';
+
+COMMENT ON COLUMN entitlement_snapshots.replica_count IS 'The number of primary replicas of the deployment.';
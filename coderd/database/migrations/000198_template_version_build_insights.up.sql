@@ -0,0 +1,20 @@
+CREATE TABLE template_version_build_insights (
+	template_version_id uuid NOT NULL PRIMARY KEY REFERENCES template_versions (id) ON DELETE CASCADE,
+	template_id uuid NOT NULL REFERENCES templates (id) ON DELETE CASCADE,
+	total_builds bigint NOT NULL,
+	failed_builds bigint NOT NULL,
+	failures jsonb NOT NULL,
+	duration_seconds_50 double precision NOT NULL,
+	duration_seconds_95 double precision NOT NULL,
+	updated_at timestamptz NOT NULL
+);
+
+CREATE INDEX template_version_build_insights_template_id_idx ON template_version_build_insights (template_id);
+
+COMMENT ON TABLE template_version_build_insights IS 'Rollup of the completed workspace builds of each template version, refreshed periodically. Canceled builds are excluded.';
+
+COMMENT ON COLUMN template_version_build_insights.failures IS 'The number of failed builds by the error code of their job, unclassified failures have an empty error code.';
+
+COMMENT ON COLUMN template_version_build_insights.duration_seconds_50 IS 'The median duration of the successful builds, -1 if there are none.';
+
+COMMENT ON COLUMN template_version_build_insights.duration_seconds_95 IS 'The 95th percentile duration of the successful builds, -1 if there are none.';
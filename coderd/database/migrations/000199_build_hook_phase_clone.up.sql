@@ -0,0 +1 @@
+ALTER TYPE build_hook_phase ADD VALUE IF NOT EXISTS 'clone';
@@ -0,0 +1,26 @@
+BEGIN;
+
+CREATE OR REPLACE FUNCTION provisioner_job_concurrency_limit(job_id uuid) RETURNS text
+    LANGUAGE sql STABLE
+    AS $$
+	WITH job AS (
+		SELECT organization_id, provisioner_job_template_id(id) AS template_id FROM provisioner_jobs WHERE id = $1
+	), running AS (
+		SELECT organization_id, provisioner_job_template_id(id) AS template_id FROM provisioner_jobs WHERE started_at IS NOT NULL AND completed_at IS NULL
+	)
+	SELECT CASE
+		WHEN EXISTS (
+			SELECT 1 FROM job JOIN templates ON templates.id = job.template_id
+			WHERE templates.max_concurrent_provisioner_jobs > 0
+				AND templates.max_concurrent_provisioner_jobs <= (SELECT COUNT(*) FROM running WHERE running.template_id = job.template_id)
+		) THEN 'template'
+		WHEN EXISTS (
+			SELECT 1 FROM job JOIN organizations ON organizations.id = job.organization_id
+			WHERE organizations.max_concurrent_provisioner_jobs > 0
+				AND organizations.max_concurrent_provisioner_jobs <= (SELECT COUNT(*) FROM running WHERE running.organization_id = job.organization_id)
+		) THEN 'organization'
+		ELSE ''
+	END
+$$;
+
+COMMIT;
@@ -0,0 +1,36 @@
+BEGIN;
+
+-- Join the running jobs to their templates once instead of computing the
+-- template of every running job for each job, and leave the job itself out of
+-- the count so it can be checked again after the job started.
+CREATE OR REPLACE FUNCTION provisioner_job_concurrency_limit(job_id uuid) RETURNS text
+    LANGUAGE sql STABLE
+    AS $$
+	WITH job AS (
+		SELECT organization_id, provisioner_job_template_id(id) AS template_id FROM provisioner_jobs WHERE id = $1
+	), running AS (
+		SELECT
+			provisioner_jobs.organization_id,
+			COALESCE(template_versions.template_id, workspaces.template_id) AS template_id
+		FROM provisioner_jobs
+		LEFT JOIN template_versions ON template_versions.job_id = provisioner_jobs.id
+		LEFT JOIN workspace_builds ON workspace_builds.job_id = provisioner_jobs.id
+		LEFT JOIN workspaces ON workspaces.id = workspace_builds.workspace_id
+		WHERE provisioner_jobs.started_at IS NOT NULL AND provisioner_jobs.completed_at IS NULL AND provisioner_jobs.id != $1
+	)
+	SELECT CASE
+		WHEN EXISTS (
+			SELECT 1 FROM job JOIN templates ON templates.id = job.template_id
+			WHERE templates.max_concurrent_provisioner_jobs > 0
+				AND templates.max_concurrent_provisioner_jobs <= (SELECT COUNT(*) FROM running WHERE running.template_id = job.template_id)
+		) THEN 'template'
+		WHEN EXISTS (
+			SELECT 1 FROM job JOIN organizations ON organizations.id = job.organization_id
+			WHERE organizations.max_concurrent_provisioner_jobs > 0
+				AND organizations.max_concurrent_provisioner_jobs <= (SELECT COUNT(*) FROM running WHERE running.organization_id = job.organization_id)
+		) THEN 'organization'
+		ELSE ''
+	END
+$$;
+
+COMMIT;
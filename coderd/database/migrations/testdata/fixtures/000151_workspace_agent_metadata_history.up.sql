@@ -0,0 +1,16 @@
+INSERT INTO
+	workspace_agent_metadata_history (
+		workspace_agent_id,
+		key,
+		value,
+		error,
+		collected_at
+	)
+VALUES
+	(
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'ahem',
+		'42',
+		'',
+		'2023-08-01 00:00:00+00'
+	);
@@ -0,0 +1,20 @@
+INSERT INTO
+	workspace_agent_client_connections (
+		id,
+		workspace_agent_id,
+		user_id,
+		client_type,
+		client_version,
+		connected_at,
+		disconnected_at
+	)
+VALUES
+	(
+		'6b2a8ac7-2d0a-4bc4-a2f7-5f7c0b1b6d37',
+		'45e89705-e09d-4850-bcec-f9a937f5d78d',
+		'30095c71-380b-457a-8995-97b8ee6e5307',
+		'ssh',
+		'v2.1.0',
+		'2023-08-01 00:00:00+00',
+		'2023-08-01 01:00:00+00'
+	);
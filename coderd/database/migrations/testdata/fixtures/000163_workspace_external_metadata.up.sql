@@ -0,0 +1,16 @@
+INSERT INTO
+	workspace_external_metadata (
+		workspace_id,
+		namespace,
+		visibility,
+		data,
+		updated_at
+	)
+VALUES
+	(
+		'3a9a1feb-e89d-457c-9d53-ac751b198ebe',
+		'cmdb',
+		'private',
+		'{"asset_id": "A-1234"}',
+		'2023-08-01 00:00:00+00'
+	);
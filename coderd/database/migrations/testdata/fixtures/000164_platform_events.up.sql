@@ -0,0 +1,14 @@
+INSERT INTO
+	platform_events (
+		created_at,
+		type,
+		resource_id,
+		data
+	)
+VALUES
+	(
+		'2023-08-01 00:00:00+00',
+		'user.created',
+		'fc1511ef-4fcf-4a3b-98a1-8df64160e35a',
+		'{"id": "fc1511ef-4fcf-4a3b-98a1-8df64160e35a", "username": "fixture", "email": "fixture@coder.com", "status": "active"}'
+	);
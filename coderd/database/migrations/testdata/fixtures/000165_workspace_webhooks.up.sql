@@ -0,0 +1,20 @@
+INSERT INTO
+	workspace_webhooks (
+		id,
+		workspace_id,
+		created_by,
+		created_at,
+		name,
+		action,
+		secret
+	)
+VALUES
+	(
+		'0d1c5a3e-7b64-4c27-9a3a-7b9c8e0f2d41',
+		'b90547be-8870-4d68-8184-e8b2242b7c01',
+		'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+		'2023-08-01 00:00:00+00',
+		'ci',
+		'start',
+		'fixture-secret'
+	);
@@ -0,0 +1,24 @@
+INSERT INTO
+	workspace_approvals (
+		id,
+		workspace_id,
+		job_id,
+		requested_by,
+		created_at,
+		status,
+		decided_by,
+		decided_at,
+		reason
+	)
+VALUES
+	(
+		'6c1b7f2e-3d4a-4e8b-9f0c-2a5d7e9b1c34',
+		'b90547be-8870-4d68-8184-e8b2242b7c01',
+		'52874f66-89cc-4e6b-8066-80ba21ad9e57',
+		'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+		'2023-08-01 00:00:00+00',
+		'approved',
+		'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+		'2023-08-01 01:00:00+00',
+		'CHG0012345'
+	);
@@ -0,0 +1,32 @@
+INSERT INTO
+	managed_environment_variables (
+		id,
+		organization_id,
+		template_id,
+		created_at,
+		updated_at,
+		name,
+		value,
+		secret
+	)
+VALUES
+	(
+		'9a3e1c5b-2f47-4d8e-b6a1-0c7d3e5f9b12',
+		'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1',
+		NULL,
+		'2023-08-01 00:00:00+00',
+		'2023-08-01 00:00:00+00',
+		'HTTPS_PROXY',
+		'http://proxy.internal:3128',
+		false
+	),
+	(
+		'e4b7d2a9-6c13-4f5e-8a0b-3d9f1c7e2a56',
+		'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1',
+		'4cc1f466-f326-477e-8762-9d0c6781fc56',
+		'2023-08-01 00:00:00+00',
+		'2023-08-01 00:00:00+00',
+		'NPM_TOKEN',
+		'fixture-secret',
+		true
+	);
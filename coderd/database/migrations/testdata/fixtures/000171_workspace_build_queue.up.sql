@@ -0,0 +1,18 @@
+INSERT INTO
+	workspace_build_queue_entries (
+		id,
+		workspace_id,
+		initiator_id,
+		initiator_token_name,
+		created_at,
+		request
+	)
+VALUES
+	(
+		'3f8a2d6c-1b9e-4c7a-8d05-6e2f4b9a1c73',
+		'b90547be-8870-4d68-8184-e8b2242b7c01',
+		'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+		'',
+		'2023-08-01 00:00:00+00',
+		'{"transition":"start"}'
+	);
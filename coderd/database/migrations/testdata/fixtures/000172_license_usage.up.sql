@@ -0,0 +1,20 @@
+INSERT INTO
+	license_usage (
+		feature,
+		date,
+		peak_actual,
+		latest_actual,
+		feature_limit,
+		first_exceeded_at,
+		updated_at
+	)
+VALUES
+	(
+		'user_limit',
+		'2023-08-01',
+		12,
+		11,
+		10,
+		'2023-08-01 09:30:00+00',
+		'2023-08-01 18:00:00+00'
+	);
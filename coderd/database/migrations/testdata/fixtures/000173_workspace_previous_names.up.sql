@@ -0,0 +1,14 @@
+INSERT INTO
+	workspace_previous_names (
+		workspace_id,
+		name,
+		renamed_at,
+		redirect_expires_at
+	)
+VALUES
+	(
+		'b90547be-8870-4d68-8184-e8b2242b7c01',
+		'previous-name',
+		'2023-08-01 00:00:00+00',
+		'2023-08-08 00:00:00+00'
+	);
@@ -0,0 +1,10 @@
+INSERT INTO
+	user_deprovisions (
+		user_id,
+		deprovisioned_at
+	)
+VALUES
+	(
+		'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+		'2023-08-01 00:00:00+00'
+	);
@@ -0,0 +1,14 @@
+INSERT INTO
+	user_quota_overrides (
+		user_id,
+		quota_allowance,
+		created_at,
+		updated_at
+	)
+VALUES
+	(
+		'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+		100,
+		'2023-08-01 00:00:00+00',
+		'2023-08-01 00:00:00+00'
+	);
@@ -0,0 +1,28 @@
+INSERT INTO
+	workspace_agent_bandwidth_stats (
+		id,
+		created_at,
+		user_id,
+		agent_id,
+		workspace_id,
+		template_id,
+		connection_type,
+		peer,
+		port,
+		rx_bytes,
+		tx_bytes
+	)
+VALUES
+	(
+		'a0c79b29-6a5a-4dcb-9bfa-3b5a3e8ee7fb',
+		'2023-08-01 00:00:00+00',
+		'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+		'7a1ce5f8-8d00-431c-ad1b-97a846512804',
+		'b90547be-8870-4d68-8184-e8b2242b7c01',
+		'3c5b3e2d-1b4a-4a1d-9b5e-0f1c7f7a8e9d',
+		'port_forward',
+		'fd7a:115c:a1e0:4b7b:9e1c:33b6:5c0a:1f2e',
+		8080,
+		1024,
+		4096
+	);
@@ -0,0 +1,14 @@
+INSERT INTO
+	user_region_latencies (
+		user_id,
+		region_id,
+		latency_ms,
+		updated_at
+	)
+VALUES
+	(
+		'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+		'f7a5aa10-6b3c-4b8a-9a46-6f2f12c4d4a1',
+		42.5,
+		'2023-08-01 00:00:00+00'
+	);
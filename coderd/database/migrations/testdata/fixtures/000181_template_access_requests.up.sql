@@ -0,0 +1,24 @@
+INSERT INTO
+	template_access_requests (
+		id,
+		template_id,
+		requested_by,
+		created_at,
+		message,
+		status,
+		decided_by,
+		decided_at,
+		reason
+	)
+VALUES
+	(
+		'3f8e2d71-9c4b-4a6e-b5d0-7e1f2a3c4b59',
+		'4cc1f466-f326-477e-8762-9d0c6781fc56',
+		'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+		'2023-08-01 00:00:00+00',
+		'I need it for the data pipeline project.',
+		'approved',
+		'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+		'2023-08-01 01:00:00+00',
+		''
+	);
@@ -0,0 +1,28 @@
+INSERT INTO
+	template_version_promotions (
+		id,
+		organization_id,
+		template_id,
+		template_version_id,
+		requested_by,
+		created_at,
+		message,
+		status,
+		decided_by,
+		decided_at,
+		reason
+	)
+VALUES
+	(
+		'8d2c4e6f-1a3b-4c5d-9e7f-0b1a2c3d4e5f',
+		'bb640d07-ca8a-4869-b6bc-ae61ebb2fda1',
+		'4cc1f466-f326-477e-8762-9d0c6781fc56',
+		'4e681a60-83da-42c2-902e-6535376ebb77',
+		'30095c71-380b-457a-8995-97b8ee6e5307',
+		'2023-08-01 00:00:00+00',
+		'Bump code-server.',
+		'approved',
+		'0ed9befc-4911-4ccf-a8e2-559bf72daa94',
+		'2023-08-01 01:00:00+00',
+		'CHG0012346'
+	);
@@ -0,0 +1,24 @@
+INSERT INTO
+	audit_log_archivals (
+		id,
+		initiator_id,
+		status,
+		cutoff,
+		destination,
+		archived_count,
+		error,
+		started_at,
+		finished_at
+	)
+VALUES
+	(
+		'3b0f6a2e-5c1d-4e8f-9a7b-2c4d6e8f0a1b',
+		'30095c71-380b-457a-8995-97b8ee6e5307',
+		'succeeded',
+		'2023-05-01 00:00:00+00',
+		's3://audit-archive/coder/',
+		42,
+		'',
+		'2023-08-01 00:00:00+00',
+		'2023-08-01 00:01:00+00'
+	);
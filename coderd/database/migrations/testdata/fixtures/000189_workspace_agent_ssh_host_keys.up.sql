@@ -0,0 +1,15 @@
+INSERT INTO
+	workspace_agent_ssh_host_keys (
+		workspace_id,
+		agent_name,
+		encrypted_private_key,
+		created_at
+	)
+SELECT
+	id,
+	'main',
+	'encrypted',
+	'2023-08-01 00:00:00+00'
+FROM
+	workspaces
+LIMIT 1;
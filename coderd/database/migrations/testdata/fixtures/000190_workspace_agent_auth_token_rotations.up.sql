@@ -0,0 +1,19 @@
+INSERT INTO
+	workspace_agent_auth_token_rotations (
+		id,
+		agent_id,
+		previous_auth_token,
+		previous_auth_token_expires_at,
+		revoked,
+		created_at
+	)
+SELECT
+	'a9b4a1b6-6f4a-4b0e-9a43-3c1e5c1d4a2f',
+	id,
+	auth_token,
+	'2023-08-01 01:00:00+00',
+	false,
+	'2023-08-01 00:00:00+00'
+FROM
+	workspace_agents
+LIMIT 1;
@@ -0,0 +1,41 @@
+INSERT INTO
+	group_default_templates (
+		group_id,
+		template_id,
+		create_workspace,
+		created_at
+	)
+SELECT
+	groups.id,
+	templates.id,
+	true,
+	'2023-08-01 00:00:00+00'
+FROM
+	groups
+JOIN
+	templates
+ON
+	templates.organization_id = groups.organization_id
+LIMIT 1;
+
+INSERT INTO
+	group_default_template_workspaces (
+		template_id,
+		user_id,
+		group_id,
+		workspace_id,
+		created_at
+	)
+SELECT
+	workspaces.template_id,
+	workspaces.owner_id,
+	group_default_templates.group_id,
+	workspaces.id,
+	'2023-08-01 00:00:00+00'
+FROM
+	workspaces
+JOIN
+	group_default_templates
+ON
+	group_default_templates.template_id = workspaces.template_id
+LIMIT 1;
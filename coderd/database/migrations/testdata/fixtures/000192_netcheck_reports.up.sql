@@ -0,0 +1,35 @@
+INSERT INTO
+	netcheck_reports (
+		id,
+		user_id,
+		created_at,
+		client_version,
+		client_os,
+		nat_type,
+		udp,
+		ipv4,
+		ipv6,
+		mapping_varies_by_dest_ip,
+		hair_pinning,
+		preferred_derp,
+		region_latencies,
+		blocked_ports
+	)
+SELECT
+	'3b0a2f4e-5d1c-4f7a-9e2b-8c6d4a1f0e93',
+	id,
+	'2023-08-01 00:00:00+00',
+	'v2.1.0',
+	'linux',
+	'hard',
+	true,
+	true,
+	false,
+	true,
+	false,
+	999,
+	'{"999": 12.5}',
+	'{}'
+FROM
+	users
+LIMIT 1;
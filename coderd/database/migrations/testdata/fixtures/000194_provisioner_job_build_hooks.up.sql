@@ -0,0 +1,25 @@
+INSERT INTO
+	provisioner_job_build_hooks (
+		id,
+		job_id,
+		name,
+		phase,
+		started_at,
+		completed_at,
+		attempts,
+		exit_code,
+		error
+	)
+SELECT
+	'8f3c1d2a-6b4e-4a9f-b7c5-2e1d0a9f8b36',
+	id,
+	'register-dns',
+	'pre',
+	'2023-08-01 00:00:00+00',
+	'2023-08-01 00:00:05+00',
+	1,
+	0,
+	''
+FROM
+	provisioner_jobs
+LIMIT 1;
@@ -0,0 +1,15 @@
+INSERT INTO
+	user_pty_session_overrides (
+		user_id,
+		max_pty_sessions,
+		created_at,
+		updated_at
+	)
+SELECT
+	id,
+	20,
+	'2023-08-01 00:00:00+00',
+	'2023-08-01 00:00:00+00'
+FROM
+	users
+LIMIT 1;
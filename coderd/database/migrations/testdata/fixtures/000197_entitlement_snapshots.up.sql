@@ -0,0 +1,26 @@
+INSERT INTO
+	entitlement_snapshots (
+		date,
+		has_license,
+		trial,
+		features,
+		user_count,
+		active_user_count,
+		replica_count,
+		warnings,
+		errors,
+		created_at
+	)
+VALUES
+	(
+		'2023-08-01',
+		true,
+		false,
+		'{"user_limit": {"entitlement": "entitled", "enabled": true, "limit": 10, "actual": 8}}',
+		12,
+		8,
+		2,
+		'{}',
+		'{}',
+		'2023-08-01 00:05:00+00'
+	);
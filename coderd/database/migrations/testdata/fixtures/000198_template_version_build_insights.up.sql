@@ -0,0 +1,22 @@
+INSERT INTO
+	template_version_build_insights (
+		template_version_id,
+		template_id,
+		total_builds,
+		failed_builds,
+		failures,
+		duration_seconds_50,
+		duration_seconds_95,
+		updated_at
+	)
+VALUES
+	(
+		'4e681a60-83da-42c2-902e-6535376ebb77',
+		'4cc1f466-f326-477e-8762-9d0c6781fc56',
+		20,
+		3,
+		'{"BUILD_TIMEOUT": 1, "": 2}',
+		42.5,
+		118.2,
+		'2023-08-01 00:00:00+00'
+	);
@@ -116,6 +116,10 @@ func (t GetFileTemplatesRow) RBACObject() rbac.Object {
 		WithGroupACL(t.GroupACL)
 }
 
+func (p TemplateVersionPromotion) RBACObject() rbac.Object {
+	return rbac.ResourceTemplateVersionPromotion.WithID(p.ID).InOrg(p.OrganizationID)
+}
+
 func (t Template) DeepCopy() Template {
 	cpy := t
 	cpy.UserACL = maps.Clone(t.UserACL)
@@ -123,6 +127,19 @@ func (t Template) DeepCopy() Template {
 	return cpy
 }
 
+// ActiveVersionRequiredAt returns the time from which outdated workspaces of
+// the template must be started with its active version, if the template
+// requires it.
+func (t Template) ActiveVersionRequiredAt() time.Time {
+	return t.ActiveVersionUpdatedAt.Add(time.Duration(t.RequireActiveVersionGracePeriod))
+}
+
+// ActiveVersionRequired returns true if outdated workspaces of the template
+// must be started with its active version at the given time.
+func (t Template) ActiveVersionRequired(now time.Time) bool {
+	return t.RequireActiveVersion && !now.Before(t.ActiveVersionRequiredAt())
+}
+
 func (TemplateVersion) RBACObject(template Template) rbac.Object {
 	// Just use the parent template resource for controlling versions
 	return template.RBACObject()
@@ -144,6 +161,12 @@ func (w Workspace) RBACObject() rbac.Object {
 		WithOwner(w.OwnerID.String())
 }
 
+func (w GetWorkspacesRow) RBACObject() rbac.Object {
+	return rbac.ResourceWorkspace.WithID(w.ID).
+		InOrg(w.OrganizationID).
+		WithOwner(w.OwnerID.String())
+}
+
 func (w Workspace) ExecutionRBAC() rbac.Object {
 	// If a workspace is locked it cannot be accessed.
 	if w.LockedAt.Valid {
@@ -191,6 +214,34 @@ func (w Workspace) LockedRBAC() rbac.Object {
 		WithOwner(w.OwnerID.String())
 }
 
+// ExternalMetadataRBAC returns the object external metadata with the given
+// visibility is authorized against. Private metadata has no owner, so only
+// users with access to all workspaces in the organization can see it.
+func (w Workspace) ExternalMetadataRBAC(visibility WorkspaceExternalMetadataVisibility) rbac.Object {
+	if visibility == WorkspaceExternalMetadataVisibilityPrivate {
+		return rbac.ResourceWorkspace.
+			WithID(w.ID).
+			InOrg(w.OrganizationID)
+	}
+	return w.RBACObject()
+}
+
+func (m GetWorkspaceExternalMetadataByWorkspaceIDsRow) RBACObject() rbac.Object {
+	return Workspace{
+		ID:             m.WorkspaceID,
+		OwnerID:        m.OwnerID,
+		OrganizationID: m.OrganizationID,
+	}.ExternalMetadataRBAC(m.Visibility)
+}
+
+func (m GetWorkspacePreviousNamesByWorkspaceIDsRow) RBACObject() rbac.Object {
+	return Workspace{
+		ID:             m.WorkspaceID,
+		OwnerID:        m.OwnerID,
+		OrganizationID: m.OrganizationID,
+	}.RBACObject()
+}
+
 func (m OrganizationMember) RBACObject() rbac.Object {
 	return rbac.ResourceOrganizationMember.
 		WithID(m.UserID).
@@ -357,6 +408,7 @@ func ConvertWorkspaceRows(rows []GetWorkspacesRow) []Workspace {
 			LastUsedAt:        r.LastUsedAt,
 			LockedAt:          r.LockedAt,
 			DeletingAt:        r.DeletingAt,
+			AutomaticUpdates:  r.AutomaticUpdates,
 		}
 	}
 
@@ -85,6 +85,19 @@ func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplate
 			&i.LockedTTL,
 			&i.RestartRequirementDaysOfWeek,
 			&i.RestartRequirementWeeks,
+			&i.RequireActiveVersion,
+			&i.RequireActiveVersionGracePeriod,
+			&i.ActiveVersionUpdatedAt,
+			&i.MaxBuildDuration,
+			&i.ProvisionerMemoryLimit,
+			&i.ProvisionerCPULimit,
+			&i.RequireWorkspaceApproval,
+			&i.RequireAgentBinaryVerification,
+			pq.Array(&i.AutostopActivitySources),
+			&i.RequirePromotionApproval,
+			&i.MaxConcurrentProvisionerJobs,
+			&i.MaxSessionIdle,
+			&i.PortPolicy,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -98,7 +111,10 @@ func (q *sqlQuerier) GetAuthorizedTemplates(ctx context.Context, arg GetTemplate
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return items, nil
+	if !rbac.RequiresPostFilter(prepared) {
+		return items, nil
+	}
+	return postFilterAuthorized(ctx, prepared, items)
 }
 
 type TemplateUser struct {
@@ -205,6 +221,14 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 		return nil, xerrors.Errorf("insert authorized filter: %w", err)
 	}
 
+	// Rows denied by a policy that requires post-filtering can only be left
+	// out after the query, so all rows are fetched and paginated afterwards.
+	postFilter := rbac.RequiresPostFilter(prepared)
+	offset, limit := arg.Offset, arg.Limit
+	if postFilter {
+		offset, limit = 0, 0
+	}
+
 	// The name comment is for metric tracking
 	query := fmt.Sprintf("-- name: GetAuthorizedWorkspaces :many\n%s", filtered)
 	rows, err := q.db.QueryContext(ctx, query,
@@ -220,8 +244,8 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 		arg.LockedAt,
 		arg.LastUsedBefore,
 		arg.LastUsedAfter,
-		arg.Offset,
-		arg.Limit,
+		offset,
+		limit,
 	)
 	if err != nil {
 		return nil, err
@@ -244,6 +268,7 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 			&i.LastUsedAt,
 			&i.LockedAt,
 			&i.DeletingAt,
+			&i.AutomaticUpdates,
 			&i.TemplateName,
 			&i.TemplateVersionID,
 			&i.TemplateVersionName,
@@ -259,7 +284,16 @@ func (q *sqlQuerier) GetAuthorizedWorkspaces(ctx context.Context, arg GetWorkspa
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return items, nil
+	if !postFilter {
+		return items, nil
+	}
+	authorized, err := postFilterAuthorized(ctx, prepared, items)
+	if err != nil {
+		return nil, err
+	}
+	return pagePostFiltered(authorized, arg.Offset, arg.Limit, func(row *GetWorkspacesRow, count int64) {
+		row.Count = count
+	}), nil
 }
 
 type userQuerier interface {
@@ -279,6 +313,13 @@ func (q *sqlQuerier) GetAuthorizedUsers(ctx context.Context, arg GetUsersParams,
 		return nil, xerrors.Errorf("insert authorized filter: %w", err)
 	}
 
+	// See GetAuthorizedWorkspaces.
+	postFilter := rbac.RequiresPostFilter(prepared)
+	offset, limit := arg.OffsetOpt, arg.LimitOpt
+	if postFilter {
+		offset, limit = 0, 0
+	}
+
 	query := fmt.Sprintf("-- name: GetAuthorizedUsers :many\n%s", filtered)
 	rows, err := q.db.QueryContext(ctx, query,
 		arg.AfterID,
@@ -287,8 +328,8 @@ func (q *sqlQuerier) GetAuthorizedUsers(ctx context.Context, arg GetUsersParams,
 		pq.Array(arg.RbacRole),
 		arg.LastSeenBefore,
 		arg.LastSeenAfter,
-		arg.OffsetOpt,
-		arg.LimitOpt,
+		offset,
+		limit,
 	)
 	if err != nil {
 		return nil, err
@@ -323,7 +364,55 @@ func (q *sqlQuerier) GetAuthorizedUsers(ctx context.Context, arg GetUsersParams,
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return items, nil
+	if !postFilter {
+		return items, nil
+	}
+	authorized, err := postFilterAuthorized(ctx, prepared, items)
+	if err != nil {
+		return nil, err
+	}
+	return pagePostFiltered(authorized, arg.OffsetOpt, arg.LimitOpt, func(row *GetUsersRow, count int64) {
+		row.Count = count
+	}), nil
+}
+
+// postFilterAuthorized removes the items that the prepared authorizer doesn't
+// allow. It's used when the SQL filter of the authorizer can't be relied on
+// alone, see rbac.PostFilteredAuthorized.
+func postFilterAuthorized[T rbac.Objecter](ctx context.Context, prepared rbac.PreparedAuthorized, items []T) ([]T, error) {
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		err := prepared.Authorize(ctx, item.RBACObject())
+		if rbac.IsUnauthorizedError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("authorize %s: %w", item.RBACObject().Type, err)
+		}
+		filtered = append(filtered, item)
+	}
+	return filtered, nil
+}
+
+// pagePostFiltered returns the page of the post-filtered rows of a listing,
+// with the count of each row set to the number of authorized rows. A limit of
+// zero returns all rows after the offset.
+func pagePostFiltered[T any](items []T, offset, limit int32, setCount func(row *T, count int64)) []T {
+	count := int64(len(items))
+	if offset < 0 {
+		offset = 0
+	}
+	if int(offset) >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && int(limit) < len(items) {
+		items = items[:limit]
+	}
+	for i := range items {
+		setCount(&items[i], count)
+	}
+	return items
 }
 
 func insertAuthorizedFilter(query string, replaceWith string) (string, error) {
@@ -146,6 +146,8 @@ const (
 	AuditActionLogin    AuditAction = "login"
 	AuditActionLogout   AuditAction = "logout"
 	AuditActionRegister AuditAction = "register"
+	AuditActionOpen     AuditAction = "open"
+	AuditActionConnect  AuditAction = "connect"
 )
 
 func (e *AuditAction) Scan(src interface{}) error {
@@ -192,7 +194,9 @@ func (e AuditAction) Valid() bool {
 		AuditActionStop,
 		AuditActionLogin,
 		AuditActionLogout,
-		AuditActionRegister:
+		AuditActionRegister,
+		AuditActionOpen,
+		AuditActionConnect:
 		return true
 	}
 	return false
@@ -208,6 +212,188 @@ func AllAuditActionValues() []AuditAction {
 		AuditActionLogin,
 		AuditActionLogout,
 		AuditActionRegister,
+		AuditActionOpen,
+		AuditActionConnect,
+	}
+}
+
+type AuditLogArchivalStatus string
+
+const (
+	AuditLogArchivalStatusRunning   AuditLogArchivalStatus = "running"
+	AuditLogArchivalStatusSucceeded AuditLogArchivalStatus = "succeeded"
+	AuditLogArchivalStatusFailed    AuditLogArchivalStatus = "failed"
+)
+
+func (e *AuditLogArchivalStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AuditLogArchivalStatus(s)
+	case string:
+		*e = AuditLogArchivalStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AuditLogArchivalStatus: %T", src)
+	}
+	return nil
+}
+
+type NullAuditLogArchivalStatus struct {
+	AuditLogArchivalStatus AuditLogArchivalStatus `json:"audit_log_archival_status"`
+	Valid                  bool                   `json:"valid"` // Valid is true if AuditLogArchivalStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAuditLogArchivalStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.AuditLogArchivalStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AuditLogArchivalStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAuditLogArchivalStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AuditLogArchivalStatus), nil
+}
+
+func (e AuditLogArchivalStatus) Valid() bool {
+	switch e {
+	case AuditLogArchivalStatusRunning,
+		AuditLogArchivalStatusSucceeded,
+		AuditLogArchivalStatusFailed:
+		return true
+	}
+	return false
+}
+
+func AllAuditLogArchivalStatusValues() []AuditLogArchivalStatus {
+	return []AuditLogArchivalStatus{
+		AuditLogArchivalStatusRunning,
+		AuditLogArchivalStatusSucceeded,
+		AuditLogArchivalStatusFailed,
+	}
+}
+
+type AutomaticUpdates string
+
+const (
+	AutomaticUpdatesAlways AutomaticUpdates = "always"
+	AutomaticUpdatesNever  AutomaticUpdates = "never"
+)
+
+func (e *AutomaticUpdates) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AutomaticUpdates(s)
+	case string:
+		*e = AutomaticUpdates(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AutomaticUpdates: %T", src)
+	}
+	return nil
+}
+
+type NullAutomaticUpdates struct {
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates"`
+	Valid            bool             `json:"valid"` // Valid is true if AutomaticUpdates is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAutomaticUpdates) Scan(value interface{}) error {
+	if value == nil {
+		ns.AutomaticUpdates, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AutomaticUpdates.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAutomaticUpdates) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.AutomaticUpdates), nil
+}
+
+func (e AutomaticUpdates) Valid() bool {
+	switch e {
+	case AutomaticUpdatesAlways,
+		AutomaticUpdatesNever:
+		return true
+	}
+	return false
+}
+
+func AllAutomaticUpdatesValues() []AutomaticUpdates {
+	return []AutomaticUpdates{
+		AutomaticUpdatesAlways,
+		AutomaticUpdatesNever,
+	}
+}
+
+type BuildHookPhase string
+
+const (
+	BuildHookPhasePre   BuildHookPhase = "pre"
+	BuildHookPhasePost  BuildHookPhase = "post"
+	BuildHookPhaseClone BuildHookPhase = "clone"
+)
+
+func (e *BuildHookPhase) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = BuildHookPhase(s)
+	case string:
+		*e = BuildHookPhase(s)
+	default:
+		return fmt.Errorf("unsupported scan type for BuildHookPhase: %T", src)
+	}
+	return nil
+}
+
+type NullBuildHookPhase struct {
+	BuildHookPhase BuildHookPhase `json:"build_hook_phase"`
+	Valid          bool           `json:"valid"` // Valid is true if BuildHookPhase is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullBuildHookPhase) Scan(value interface{}) error {
+	if value == nil {
+		ns.BuildHookPhase, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.BuildHookPhase.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullBuildHookPhase) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.BuildHookPhase), nil
+}
+
+func (e BuildHookPhase) Valid() bool {
+	switch e {
+	case BuildHookPhasePre,
+		BuildHookPhasePost,
+		BuildHookPhaseClone:
+		return true
+	}
+	return false
+}
+
+func AllBuildHookPhaseValues() []BuildHookPhase {
+	return []BuildHookPhase{
+		BuildHookPhasePre,
+		BuildHookPhasePost,
+		BuildHookPhaseClone,
 	}
 }
 
@@ -220,6 +406,8 @@ const (
 	BuildReasonAutolock   BuildReason = "autolock"
 	BuildReasonFailedstop BuildReason = "failedstop"
 	BuildReasonAutodelete BuildReason = "autodelete"
+	BuildReasonBulk       BuildReason = "bulk"
+	BuildReasonWebhook    BuildReason = "webhook"
 )
 
 func (e *BuildReason) Scan(src interface{}) error {
@@ -264,7 +452,9 @@ func (e BuildReason) Valid() bool {
 		BuildReasonAutostop,
 		BuildReasonAutolock,
 		BuildReasonFailedstop,
-		BuildReasonAutodelete:
+		BuildReasonAutodelete,
+		BuildReasonBulk,
+		BuildReasonWebhook:
 		return true
 	}
 	return false
@@ -278,6 +468,8 @@ func AllBuildReasonValues() []BuildReason {
 		BuildReasonAutolock,
 		BuildReasonFailedstop,
 		BuildReasonAutodelete,
+		BuildReasonBulk,
+		BuildReasonWebhook,
 	}
 }
 
@@ -770,6 +962,64 @@ func AllParameterTypeSystemValues() []ParameterTypeSystem {
 	}
 }
 
+type ProvisionerJobCancelOutcome string
+
+const (
+	ProvisionerJobCancelOutcomeGraceful ProvisionerJobCancelOutcome = "graceful"
+	ProvisionerJobCancelOutcomeForced   ProvisionerJobCancelOutcome = "forced"
+)
+
+func (e *ProvisionerJobCancelOutcome) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ProvisionerJobCancelOutcome(s)
+	case string:
+		*e = ProvisionerJobCancelOutcome(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ProvisionerJobCancelOutcome: %T", src)
+	}
+	return nil
+}
+
+type NullProvisionerJobCancelOutcome struct {
+	ProvisionerJobCancelOutcome ProvisionerJobCancelOutcome `json:"provisioner_job_cancel_outcome"`
+	Valid                       bool                        `json:"valid"` // Valid is true if ProvisionerJobCancelOutcome is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullProvisionerJobCancelOutcome) Scan(value interface{}) error {
+	if value == nil {
+		ns.ProvisionerJobCancelOutcome, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ProvisionerJobCancelOutcome.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullProvisionerJobCancelOutcome) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.ProvisionerJobCancelOutcome), nil
+}
+
+func (e ProvisionerJobCancelOutcome) Valid() bool {
+	switch e {
+	case ProvisionerJobCancelOutcomeGraceful,
+		ProvisionerJobCancelOutcomeForced:
+		return true
+	}
+	return false
+}
+
+func AllProvisionerJobCancelOutcomeValues() []ProvisionerJobCancelOutcome {
+	return []ProvisionerJobCancelOutcome{
+		ProvisionerJobCancelOutcomeGraceful,
+		ProvisionerJobCancelOutcomeForced,
+	}
+}
+
 type ProvisionerJobType string
 
 const (
@@ -947,18 +1197,24 @@ func AllProvisionerTypeValues() []ProvisionerType {
 type ResourceType string
 
 const (
-	ResourceTypeOrganization    ResourceType = "organization"
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
-	ResourceTypeGitSshKey       ResourceType = "git_ssh_key"
-	ResourceTypeApiKey          ResourceType = "api_key"
-	ResourceTypeGroup           ResourceType = "group"
-	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
-	ResourceTypeLicense         ResourceType = "license"
-	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
-	ResourceTypeConvertLogin    ResourceType = "convert_login"
+	ResourceTypeOrganization             ResourceType = "organization"
+	ResourceTypeTemplate                 ResourceType = "template"
+	ResourceTypeTemplateVersion          ResourceType = "template_version"
+	ResourceTypeUser                     ResourceType = "user"
+	ResourceTypeWorkspace                ResourceType = "workspace"
+	ResourceTypeGitSshKey                ResourceType = "git_ssh_key"
+	ResourceTypeApiKey                   ResourceType = "api_key"
+	ResourceTypeGroup                    ResourceType = "group"
+	ResourceTypeWorkspaceBuild           ResourceType = "workspace_build"
+	ResourceTypeLicense                  ResourceType = "license"
+	ResourceTypeWorkspaceProxy           ResourceType = "workspace_proxy"
+	ResourceTypeConvertLogin             ResourceType = "convert_login"
+	ResourceTypeWorkspaceWebhook         ResourceType = "workspace_webhook"
+	ResourceTypeWorkspaceApproval        ResourceType = "workspace_approval"
+	ResourceTypeEnvironmentVariable      ResourceType = "environment_variable"
+	ResourceTypeTemplateAccessRequest    ResourceType = "template_access_request"
+	ResourceTypeTemplateVersionPromotion ResourceType = "template_version_promotion"
+	ResourceTypeWorkspaceAgentAuthToken  ResourceType = "workspace_agent_auth_token"
 )
 
 func (e *ResourceType) Scan(src interface{}) error {
@@ -1009,7 +1265,13 @@ func (e ResourceType) Valid() bool {
 		ResourceTypeWorkspaceBuild,
 		ResourceTypeLicense,
 		ResourceTypeWorkspaceProxy,
-		ResourceTypeConvertLogin:
+		ResourceTypeConvertLogin,
+		ResourceTypeWorkspaceWebhook,
+		ResourceTypeWorkspaceApproval,
+		ResourceTypeEnvironmentVariable,
+		ResourceTypeTemplateAccessRequest,
+		ResourceTypeTemplateVersionPromotion,
+		ResourceTypeWorkspaceAgentAuthToken:
 		return true
 	}
 	return false
@@ -1029,6 +1291,12 @@ func AllResourceTypeValues() []ResourceType {
 		ResourceTypeLicense,
 		ResourceTypeWorkspaceProxy,
 		ResourceTypeConvertLogin,
+		ResourceTypeWorkspaceWebhook,
+		ResourceTypeWorkspaceApproval,
+		ResourceTypeEnvironmentVariable,
+		ResourceTypeTemplateAccessRequest,
+		ResourceTypeTemplateVersionPromotion,
+		ResourceTypeWorkspaceAgentAuthToken,
 	}
 }
 
@@ -1090,6 +1358,128 @@ func AllStartupScriptBehaviorValues() []StartupScriptBehavior {
 	}
 }
 
+type TemplateAccessRequestStatus string
+
+const (
+	TemplateAccessRequestStatusPending  TemplateAccessRequestStatus = "pending"
+	TemplateAccessRequestStatusApproved TemplateAccessRequestStatus = "approved"
+	TemplateAccessRequestStatusDenied   TemplateAccessRequestStatus = "denied"
+)
+
+func (e *TemplateAccessRequestStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TemplateAccessRequestStatus(s)
+	case string:
+		*e = TemplateAccessRequestStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TemplateAccessRequestStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTemplateAccessRequestStatus struct {
+	TemplateAccessRequestStatus TemplateAccessRequestStatus `json:"template_access_request_status"`
+	Valid                       bool                        `json:"valid"` // Valid is true if TemplateAccessRequestStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTemplateAccessRequestStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TemplateAccessRequestStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TemplateAccessRequestStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTemplateAccessRequestStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TemplateAccessRequestStatus), nil
+}
+
+func (e TemplateAccessRequestStatus) Valid() bool {
+	switch e {
+	case TemplateAccessRequestStatusPending,
+		TemplateAccessRequestStatusApproved,
+		TemplateAccessRequestStatusDenied:
+		return true
+	}
+	return false
+}
+
+func AllTemplateAccessRequestStatusValues() []TemplateAccessRequestStatus {
+	return []TemplateAccessRequestStatus{
+		TemplateAccessRequestStatusPending,
+		TemplateAccessRequestStatusApproved,
+		TemplateAccessRequestStatusDenied,
+	}
+}
+
+type TemplateVersionPromotionStatus string
+
+const (
+	TemplateVersionPromotionStatusPending  TemplateVersionPromotionStatus = "pending"
+	TemplateVersionPromotionStatusApproved TemplateVersionPromotionStatus = "approved"
+	TemplateVersionPromotionStatusRejected TemplateVersionPromotionStatus = "rejected"
+)
+
+func (e *TemplateVersionPromotionStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = TemplateVersionPromotionStatus(s)
+	case string:
+		*e = TemplateVersionPromotionStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for TemplateVersionPromotionStatus: %T", src)
+	}
+	return nil
+}
+
+type NullTemplateVersionPromotionStatus struct {
+	TemplateVersionPromotionStatus TemplateVersionPromotionStatus `json:"template_version_promotion_status"`
+	Valid                          bool                           `json:"valid"` // Valid is true if TemplateVersionPromotionStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullTemplateVersionPromotionStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.TemplateVersionPromotionStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.TemplateVersionPromotionStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullTemplateVersionPromotionStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.TemplateVersionPromotionStatus), nil
+}
+
+func (e TemplateVersionPromotionStatus) Valid() bool {
+	switch e {
+	case TemplateVersionPromotionStatusPending,
+		TemplateVersionPromotionStatusApproved,
+		TemplateVersionPromotionStatusRejected:
+		return true
+	}
+	return false
+}
+
+func AllTemplateVersionPromotionStatusValues() []TemplateVersionPromotionStatus {
+	return []TemplateVersionPromotionStatus{
+		TemplateVersionPromotionStatusPending,
+		TemplateVersionPromotionStatusApproved,
+		TemplateVersionPromotionStatusRejected,
+	}
+}
+
 // Defines the user status: active, dormant, or suspended.
 type UserStatus string
 
@@ -1429,6 +1819,125 @@ func AllWorkspaceAppHealthValues() []WorkspaceAppHealth {
 	}
 }
 
+type WorkspaceApprovalStatus string
+
+const (
+	WorkspaceApprovalStatusPending  WorkspaceApprovalStatus = "pending"
+	WorkspaceApprovalStatusApproved WorkspaceApprovalStatus = "approved"
+	WorkspaceApprovalStatusRejected WorkspaceApprovalStatus = "rejected"
+)
+
+func (e *WorkspaceApprovalStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceApprovalStatus(s)
+	case string:
+		*e = WorkspaceApprovalStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceApprovalStatus: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceApprovalStatus struct {
+	WorkspaceApprovalStatus WorkspaceApprovalStatus `json:"workspace_approval_status"`
+	Valid                   bool                    `json:"valid"` // Valid is true if WorkspaceApprovalStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceApprovalStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceApprovalStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceApprovalStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceApprovalStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceApprovalStatus), nil
+}
+
+func (e WorkspaceApprovalStatus) Valid() bool {
+	switch e {
+	case WorkspaceApprovalStatusPending,
+		WorkspaceApprovalStatusApproved,
+		WorkspaceApprovalStatusRejected:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceApprovalStatusValues() []WorkspaceApprovalStatus {
+	return []WorkspaceApprovalStatus{
+		WorkspaceApprovalStatusPending,
+		WorkspaceApprovalStatusApproved,
+		WorkspaceApprovalStatusRejected,
+	}
+}
+
+type WorkspaceExternalMetadataVisibility string
+
+const (
+	WorkspaceExternalMetadataVisibilityPublic  WorkspaceExternalMetadataVisibility = "public"
+	WorkspaceExternalMetadataVisibilityPrivate WorkspaceExternalMetadataVisibility = "private"
+)
+
+func (e *WorkspaceExternalMetadataVisibility) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceExternalMetadataVisibility(s)
+	case string:
+		*e = WorkspaceExternalMetadataVisibility(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceExternalMetadataVisibility: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceExternalMetadataVisibility struct {
+	WorkspaceExternalMetadataVisibility WorkspaceExternalMetadataVisibility `json:"workspace_external_metadata_visibility"`
+	Valid                               bool                                `json:"valid"` // Valid is true if WorkspaceExternalMetadataVisibility is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceExternalMetadataVisibility) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceExternalMetadataVisibility, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceExternalMetadataVisibility.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceExternalMetadataVisibility) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceExternalMetadataVisibility), nil
+}
+
+func (e WorkspaceExternalMetadataVisibility) Valid() bool {
+	switch e {
+	case WorkspaceExternalMetadataVisibilityPublic,
+		WorkspaceExternalMetadataVisibilityPrivate:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceExternalMetadataVisibilityValues() []WorkspaceExternalMetadataVisibility {
+	return []WorkspaceExternalMetadataVisibility{
+		WorkspaceExternalMetadataVisibilityPublic,
+		WorkspaceExternalMetadataVisibilityPrivate,
+	}
+}
+
 type WorkspaceTransition string
 
 const (
@@ -1490,6 +1999,70 @@ func AllWorkspaceTransitionValues() []WorkspaceTransition {
 	}
 }
 
+type WorkspaceWebhookAction string
+
+const (
+	WorkspaceWebhookActionStart  WorkspaceWebhookAction = "start"
+	WorkspaceWebhookActionStop   WorkspaceWebhookAction = "stop"
+	WorkspaceWebhookActionUpdate WorkspaceWebhookAction = "update"
+	WorkspaceWebhookActionExec   WorkspaceWebhookAction = "exec"
+)
+
+func (e *WorkspaceWebhookAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = WorkspaceWebhookAction(s)
+	case string:
+		*e = WorkspaceWebhookAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for WorkspaceWebhookAction: %T", src)
+	}
+	return nil
+}
+
+type NullWorkspaceWebhookAction struct {
+	WorkspaceWebhookAction WorkspaceWebhookAction `json:"workspace_webhook_action"`
+	Valid                  bool                   `json:"valid"` // Valid is true if WorkspaceWebhookAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullWorkspaceWebhookAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.WorkspaceWebhookAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.WorkspaceWebhookAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullWorkspaceWebhookAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.WorkspaceWebhookAction), nil
+}
+
+func (e WorkspaceWebhookAction) Valid() bool {
+	switch e {
+	case WorkspaceWebhookActionStart,
+		WorkspaceWebhookActionStop,
+		WorkspaceWebhookActionUpdate,
+		WorkspaceWebhookActionExec:
+		return true
+	}
+	return false
+}
+
+func AllWorkspaceWebhookActionValues() []WorkspaceWebhookAction {
+	return []WorkspaceWebhookAction{
+		WorkspaceWebhookActionStart,
+		WorkspaceWebhookActionStop,
+		WorkspaceWebhookActionUpdate,
+		WorkspaceWebhookActionExec,
+	}
+}
+
 type APIKey struct {
 	ID string `db:"id" json:"id"`
 	// hashed_secret contains a SHA256 hash of the key secret. This is considered a secret and MUST NOT be returned from the API as it is used for API key encryption in app proxying code.
@@ -1524,6 +2097,39 @@ type AuditLog struct {
 	ResourceIcon     string          `db:"resource_icon" json:"resource_icon"`
 }
 
+type AuditLogArchival struct {
+	ID uuid.UUID `db:"id" json:"id"`
+	// The user that started the run. Null for scheduled runs.
+	InitiatorID uuid.NullUUID          `db:"initiator_id" json:"initiator_id"`
+	Status      AuditLogArchivalStatus `db:"status" json:"status"`
+	// Audit logs older than the cutoff are archived by the run.
+	Cutoff time.Time `db:"cutoff" json:"cutoff"`
+	// Where the audit logs are exported to. Empty if they are deleted without being exported.
+	Destination   string       `db:"destination" json:"destination"`
+	ArchivedCount int64        `db:"archived_count" json:"archived_count"`
+	Error         string       `db:"error" json:"error"`
+	StartedAt     time.Time    `db:"started_at" json:"started_at"`
+	FinishedAt    sql.NullTime `db:"finished_at" json:"finished_at"`
+}
+
+// Daily snapshots of the entitlements of the deployment, kept as evidence of license adherence.
+type EntitlementSnapshot struct {
+	// The UTC day the snapshot was taken on.
+	Date       time.Time `db:"date" json:"date"`
+	HasLicense bool      `db:"has_license" json:"has_license"`
+	Trial      bool      `db:"trial" json:"trial"`
+	// The entitlement, enablement, limit and actual usage of every feature, by feature name.
+	Features json.RawMessage `db:"features" json:"features"`
+	// The number of users that are not deleted, including suspended users.
+	UserCount       int64 `db:"user_count" json:"user_count"`
+	ActiveUserCount int64 `db:"active_user_count" json:"active_user_count"`
+	// The number of primary replicas of the deployment.
+	ReplicaCount int32     `db:"replica_count" json:"replica_count"`
+	Warnings     []string  `db:"warnings" json:"warnings"`
+	Errors       []string  `db:"errors" json:"errors"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
 type File struct {
 	Hash      string    `db:"hash" json:"hash"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
@@ -1561,6 +2167,31 @@ type Group struct {
 	DisplayName string `db:"display_name" json:"display_name"`
 	// Source indicates how the group was created. It can be created by a user manually, or through some system process like OIDC group sync.
 	Source GroupSource `db:"source" json:"source"`
+	// Quota override replaces the summed quota allowances of members of the group. If a user is a member of several groups with an override, the largest override applies.
+	QuotaOverride sql.NullInt32 `db:"quota_override" json:"quota_override"`
+	// The uploaded avatar of the group. It is cleared when the avatar URL is changed to something else.
+	AvatarFileID uuid.NullUUID `db:"avatar_file_id" json:"avatar_file_id"`
+	// Arbitrary key-value metadata of the group, such as the team or cost center it represents.
+	Metadata json.RawMessage `db:"metadata" json:"metadata"`
+}
+
+// Templates members of a group get access to.
+type GroupDefaultTemplate struct {
+	GroupID    uuid.UUID `db:"group_id" json:"group_id"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	// Members of the group get a starter workspace from the template.
+	CreateWorkspace bool      `db:"create_workspace" json:"create_workspace"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+}
+
+// Starter workspaces created for members of groups. Members get at most one starter workspace per template, even if they delete it or are in several groups with the template.
+type GroupDefaultTemplateWorkspace struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+	GroupID    uuid.UUID `db:"group_id" json:"group_id"`
+	// NULL if the workspace could not be created, for example because the template requires parameters without a default.
+	WorkspaceID uuid.NullUUID `db:"workspace_id" json:"workspace_id"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
 }
 
 type GroupMember struct {
@@ -1575,6 +2206,23 @@ type License struct {
 	// exp tracks the claim of the same name in the JWT, and we include it here so that we can easily query for licenses that have not yet expired.
 	Exp  time.Time `db:"exp" json:"exp"`
 	UUID uuid.UUID `db:"uuid" json:"uuid"`
+	// The key the license was activated with online, or empty if it was added as a JWT. Licenses with a key are renewed from the activation server before they expire.
+	ActivationKey string `db:"activation_key" json:"activation_key"`
+}
+
+// Daily usage of licensed features that have a measured consumption, such as the user limit.
+type LicenseUsage struct {
+	Feature string `db:"feature" json:"feature"`
+	// The UTC day the usage was recorded on.
+	Date time.Time `db:"date" json:"date"`
+	// The highest usage recorded during the day.
+	PeakActual   int64 `db:"peak_actual" json:"peak_actual"`
+	LatestActual int64 `db:"latest_actual" json:"latest_actual"`
+	// The licensed limit at the time of the last recording, if the feature is limited.
+	FeatureLimit sql.NullInt64 `db:"feature_limit" json:"feature_limit"`
+	// The first time during the day that usage exceeded the licensed limit.
+	FirstExceededAt sql.NullTime `db:"first_exceeded_at" json:"first_exceeded_at"`
+	UpdatedAt       time.Time    `db:"updated_at" json:"updated_at"`
 }
 
 type Organization struct {
@@ -1583,6 +2231,43 @@ type Organization struct {
 	Description string    `db:"description" json:"description"`
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+	// The maximum number of provisioner jobs of the organization that run at once. Further jobs wait in the queue. 0 means no limit.
+	MaxConcurrentProvisionerJobs int32 `db:"max_concurrent_provisioner_jobs" json:"max_concurrent_provisioner_jobs"`
+}
+
+// Environment variables that are injected into every agent of the workspaces of an organization or template.
+type ManagedEnvironmentVariable struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+	// The template whose workspaces receive the variable. If null, the variable applies to every workspace of the organization.
+	TemplateID uuid.NullUUID `db:"template_id" json:"template_id"`
+	CreatedAt  time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time     `db:"updated_at" json:"updated_at"`
+	Name       string        `db:"name" json:"name"`
+	Value      string        `db:"value" json:"value"`
+	// Secret values are never returned by the API.
+	Secret bool `db:"secret" json:"secret"`
+}
+
+// Connectivity preflights reported by clients. Used to correlate connection issues with the networks of users.
+type NetcheckReport struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	UserID        uuid.UUID `db:"user_id" json:"user_id"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+	ClientVersion string    `db:"client_version" json:"client_version"`
+	ClientOS      string    `db:"client_os" json:"client_os"`
+	// One of unknown, udp_blocked, easy or hard. Direct connections are unlikely to succeed behind hard NATs, where the mapping varies by destination.
+	NATType               string       `db:"nat_type" json:"nat_type"`
+	UDP                   bool         `db:"udp" json:"udp"`
+	IPv4                  bool         `db:"ipv4" json:"ipv4"`
+	IPv6                  bool         `db:"ipv6" json:"ipv6"`
+	MappingVariesByDestIP sql.NullBool `db:"mapping_varies_by_dest_ip" json:"mapping_varies_by_dest_ip"`
+	HairPinning           sql.NullBool `db:"hair_pinning" json:"hair_pinning"`
+	PreferredDERP         int32        `db:"preferred_derp" json:"preferred_derp"`
+	// The latencies in milliseconds to the DERP regions the client reached, keyed by region ID.
+	RegionLatencies json.RawMessage `db:"region_latencies" json:"region_latencies"`
+	// The ports the client could not reach, e.g. udp/3478 when STUN is blocked.
+	BlockedPorts []string `db:"blocked_ports" json:"blocked_ports"`
 }
 
 type OrganizationMember struct {
@@ -1625,6 +2310,15 @@ type ParameterValue struct {
 	DestinationScheme ParameterDestinationScheme `db:"destination_scheme" json:"destination_scheme"`
 }
 
+// Significant domain events streamed to platform integrations. The id is used as a resume cursor.
+type PlatformEvent struct {
+	ID         int64           `db:"id" json:"id"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+	Type       string          `db:"type" json:"type"`
+	ResourceID uuid.UUID       `db:"resource_id" json:"resource_id"`
+	Data       json.RawMessage `db:"data" json:"data"`
+}
+
 type ProvisionerDaemon struct {
 	ID           uuid.UUID         `db:"id" json:"id"`
 	CreatedAt    time.Time         `db:"created_at" json:"created_at"`
@@ -1654,6 +2348,25 @@ type ProvisionerJob struct {
 	Tags           StringMap                `db:"tags" json:"tags"`
 	ErrorCode      sql.NullString           `db:"error_code" json:"error_code"`
 	TraceMetadata  pqtype.NullRawMessage    `db:"trace_metadata" json:"trace_metadata"`
+	// How the job stopped after it was canceled. graceful means the provisioner stopped at a safe point, forced means it was killed after the force cancel interval.
+	CancelOutcome NullProvisionerJobCancelOutcome `db:"cancel_outcome" json:"cancel_outcome"`
+	// The number of times the job was put back in the queue after being detected as hung.
+	RequeueCount int32 `db:"requeue_count" json:"requeue_count"`
+}
+
+// The outcomes of the pre-build and post-build hooks of templates, run by provisioner daemons around workspace builds.
+type ProvisionerJobBuildHook struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	JobID       uuid.UUID      `db:"job_id" json:"job_id"`
+	Name        string         `db:"name" json:"name"`
+	Phase       BuildHookPhase `db:"phase" json:"phase"`
+	StartedAt   time.Time      `db:"started_at" json:"started_at"`
+	CompletedAt time.Time      `db:"completed_at" json:"completed_at"`
+	// How many times the hook ran, including retries.
+	Attempts int32 `db:"attempts" json:"attempts"`
+	// The exit code of the last attempt. -1 if the hook was killed, e.g. when it timed out.
+	ExitCode int32  `db:"exit_code" json:"exit_code"`
+	Error    string `db:"error" json:"error"`
 }
 
 type ProvisionerJobLog struct {
@@ -1679,6 +2392,8 @@ type Replica struct {
 	Version         string       `db:"version" json:"version"`
 	Error           string       `db:"error" json:"error"`
 	Primary         bool         `db:"primary" json:"primary"`
+	// The time the replica started draining. A draining replica doesn't accept new tailnet connections or provisioner jobs.
+	DrainingAt sql.NullTime `db:"draining_at" json:"draining_at"`
 }
 
 type SiteConfig struct {
@@ -1709,32 +2424,60 @@ type TailnetCoordinator struct {
 
 // Joins in the username + avatar url of the created by user.
 type Template struct {
-	ID                           uuid.UUID       `db:"id" json:"id"`
-	CreatedAt                    time.Time       `db:"created_at" json:"created_at"`
-	UpdatedAt                    time.Time       `db:"updated_at" json:"updated_at"`
-	OrganizationID               uuid.UUID       `db:"organization_id" json:"organization_id"`
-	Deleted                      bool            `db:"deleted" json:"deleted"`
-	Name                         string          `db:"name" json:"name"`
-	Provisioner                  ProvisionerType `db:"provisioner" json:"provisioner"`
-	ActiveVersionID              uuid.UUID       `db:"active_version_id" json:"active_version_id"`
-	Description                  string          `db:"description" json:"description"`
-	DefaultTTL                   int64           `db:"default_ttl" json:"default_ttl"`
-	CreatedBy                    uuid.UUID       `db:"created_by" json:"created_by"`
-	Icon                         string          `db:"icon" json:"icon"`
-	UserACL                      TemplateACL     `db:"user_acl" json:"user_acl"`
-	GroupACL                     TemplateACL     `db:"group_acl" json:"group_acl"`
-	DisplayName                  string          `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
-	MaxTTL                       int64           `db:"max_ttl" json:"max_ttl"`
-	AllowUserAutostart           bool            `db:"allow_user_autostart" json:"allow_user_autostart"`
-	AllowUserAutostop            bool            `db:"allow_user_autostop" json:"allow_user_autostop"`
-	FailureTTL                   int64           `db:"failure_ttl" json:"failure_ttl"`
-	InactivityTTL                int64           `db:"inactivity_ttl" json:"inactivity_ttl"`
-	LockedTTL                    int64           `db:"locked_ttl" json:"locked_ttl"`
-	RestartRequirementDaysOfWeek int16           `db:"restart_requirement_days_of_week" json:"restart_requirement_days_of_week"`
-	RestartRequirementWeeks      int64           `db:"restart_requirement_weeks" json:"restart_requirement_weeks"`
-	CreatedByAvatarURL           sql.NullString  `db:"created_by_avatar_url" json:"created_by_avatar_url"`
-	CreatedByUsername            string          `db:"created_by_username" json:"created_by_username"`
+	ID                              uuid.UUID       `db:"id" json:"id"`
+	CreatedAt                       time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt                       time.Time       `db:"updated_at" json:"updated_at"`
+	OrganizationID                  uuid.UUID       `db:"organization_id" json:"organization_id"`
+	Deleted                         bool            `db:"deleted" json:"deleted"`
+	Name                            string          `db:"name" json:"name"`
+	Provisioner                     ProvisionerType `db:"provisioner" json:"provisioner"`
+	ActiveVersionID                 uuid.UUID       `db:"active_version_id" json:"active_version_id"`
+	Description                     string          `db:"description" json:"description"`
+	DefaultTTL                      int64           `db:"default_ttl" json:"default_ttl"`
+	CreatedBy                       uuid.UUID       `db:"created_by" json:"created_by"`
+	Icon                            string          `db:"icon" json:"icon"`
+	UserACL                         TemplateACL     `db:"user_acl" json:"user_acl"`
+	GroupACL                        TemplateACL     `db:"group_acl" json:"group_acl"`
+	DisplayName                     string          `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs    bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	MaxTTL                          int64           `db:"max_ttl" json:"max_ttl"`
+	AllowUserAutostart              bool            `db:"allow_user_autostart" json:"allow_user_autostart"`
+	AllowUserAutostop               bool            `db:"allow_user_autostop" json:"allow_user_autostop"`
+	FailureTTL                      int64           `db:"failure_ttl" json:"failure_ttl"`
+	InactivityTTL                   int64           `db:"inactivity_ttl" json:"inactivity_ttl"`
+	LockedTTL                       int64           `db:"locked_ttl" json:"locked_ttl"`
+	RestartRequirementDaysOfWeek    int16           `db:"restart_requirement_days_of_week" json:"restart_requirement_days_of_week"`
+	RestartRequirementWeeks         int64           `db:"restart_requirement_weeks" json:"restart_requirement_weeks"`
+	RequireActiveVersion            bool            `db:"require_active_version" json:"require_active_version"`
+	RequireActiveVersionGracePeriod int64           `db:"require_active_version_grace_period" json:"require_active_version_grace_period"`
+	ActiveVersionUpdatedAt          time.Time       `db:"active_version_updated_at" json:"active_version_updated_at"`
+	MaxBuildDuration                int64           `db:"max_build_duration" json:"max_build_duration"`
+	ProvisionerMemoryLimit          int64           `db:"provisioner_memory_limit" json:"provisioner_memory_limit"`
+	ProvisionerCPULimit             int64           `db:"provisioner_cpu_limit" json:"provisioner_cpu_limit"`
+	RequireWorkspaceApproval        bool            `db:"require_workspace_approval" json:"require_workspace_approval"`
+	RequireAgentBinaryVerification  bool            `db:"require_agent_binary_verification" json:"require_agent_binary_verification"`
+	AutostopActivitySources         []string        `db:"autostop_activity_sources" json:"autostop_activity_sources"`
+	RequirePromotionApproval        bool            `db:"require_promotion_approval" json:"require_promotion_approval"`
+	MaxConcurrentProvisionerJobs    int32           `db:"max_concurrent_provisioner_jobs" json:"max_concurrent_provisioner_jobs"`
+	MaxSessionIdle                  int64           `db:"max_session_idle" json:"max_session_idle"`
+	PortPolicy                      json.RawMessage `db:"port_policy" json:"port_policy"`
+	CreatedByAvatarURL              sql.NullString  `db:"created_by_avatar_url" json:"created_by_avatar_url"`
+	CreatedByUsername               string          `db:"created_by_username" json:"created_by_username"`
+}
+
+// Requests of users to be granted use of a template, decided by the template admins.
+type TemplateAccessRequest struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	TemplateID  uuid.UUID `db:"template_id" json:"template_id"`
+	RequestedBy uuid.UUID `db:"requested_by" json:"requested_by"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	// An optional explanation from the requester of why they need access.
+	Message   string                      `db:"message" json:"message"`
+	Status    TemplateAccessRequestStatus `db:"status" json:"status"`
+	DecidedBy uuid.NullUUID               `db:"decided_by" json:"decided_by"`
+	DecidedAt sql.NullTime                `db:"decided_at" json:"decided_at"`
+	// An optional explanation of the decision.
+	Reason string `db:"reason" json:"reason"`
 }
 
 type TemplateTable struct {
@@ -1769,6 +2512,32 @@ type TemplateTable struct {
 	RestartRequirementDaysOfWeek int16 `db:"restart_requirement_days_of_week" json:"restart_requirement_days_of_week"`
 	// The number of weeks between restarts. 0 or 1 weeks means "every week", 2 week means "every second week", etc. Weeks are counted from January 2, 2023, which is the first Monday of 2023. This is to ensure workspaces are started consistently for all customers on the same n-week cycles.
 	RestartRequirementWeeks int64 `db:"restart_requirement_weeks" json:"restart_requirement_weeks"`
+	// Outdated workspaces are updated to the active version of the template when they are started.
+	RequireActiveVersion bool `db:"require_active_version" json:"require_active_version"`
+	// The duration after the active version changes during which outdated workspaces can still be started with their current version.
+	RequireActiveVersionGracePeriod int64 `db:"require_active_version_grace_period" json:"require_active_version_grace_period"`
+	// The time the active version of the template was last changed.
+	ActiveVersionUpdatedAt time.Time `db:"active_version_updated_at" json:"active_version_updated_at"`
+	// The maximum duration of a provisioner job for the template. Jobs running longer are canceled. 0 means no limit.
+	MaxBuildDuration int64 `db:"max_build_duration" json:"max_build_duration"`
+	// The maximum memory in bytes the provisioner can use for a job of the template. 0 means no limit.
+	ProvisionerMemoryLimit int64 `db:"provisioner_memory_limit" json:"provisioner_memory_limit"`
+	// The maximum CPU time the provisioner can use for a job of the template. 0 means no limit.
+	ProvisionerCPULimit int64 `db:"provisioner_cpu_limit" json:"provisioner_cpu_limit"`
+	// Workspaces created from the template are held until their first build is approved.
+	RequireWorkspaceApproval bool `db:"require_workspace_approval" json:"require_workspace_approval"`
+	// Agents of workspaces created from the template fail to start if their binary does not match the checksum served by the deployment.
+	RequireAgentBinaryVerification bool `db:"require_agent_binary_verification" json:"require_agent_binary_verification"`
+	// The kinds of activity reported by agents that postpone the autostop of workspaces. Any connection postpones it when empty.
+	AutostopActivitySources []string `db:"autostop_activity_sources" json:"autostop_activity_sources"`
+	// New versions of the template only become active once a template approver approves their promotion.
+	RequirePromotionApproval bool `db:"require_promotion_approval" json:"require_promotion_approval"`
+	// The maximum number of provisioner jobs of the template that run at once. Further jobs wait in the queue. 0 means no limit.
+	MaxConcurrentProvisionerJobs int32 `db:"max_concurrent_provisioner_jobs" json:"max_concurrent_provisioner_jobs"`
+	// How long web terminal and app sessions of the workspaces of the template may be idle before they are disconnected. 0 means sessions are never disconnected.
+	MaxSessionIdle int64 `db:"max_session_idle" json:"max_session_idle"`
+	// Restricts which ports of the agents of the workspaces of the template can be forwarded, and how far apps on them can be shared.
+	PortPolicy json.RawMessage `db:"port_policy" json:"port_policy"`
 }
 
 // Joins in the username + avatar url of the created by user.
@@ -1788,6 +2557,21 @@ type TemplateVersion struct {
 	CreatedByUsername  string         `db:"created_by_username" json:"created_by_username"`
 }
 
+// Rollup of the completed workspace builds of each template version, refreshed periodically. Canceled builds are excluded.
+type TemplateVersionBuildInsight struct {
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	TotalBuilds       int64     `db:"total_builds" json:"total_builds"`
+	FailedBuilds      int64     `db:"failed_builds" json:"failed_builds"`
+	// The number of failed builds by the error code of their job, unclassified failures have an empty error code.
+	Failures json.RawMessage `db:"failures" json:"failures"`
+	// The median duration of the successful builds, -1 if there are none.
+	DurationSeconds50 float64 `db:"duration_seconds_50" json:"duration_seconds_50"`
+	// The 95th percentile duration of the successful builds, -1 if there are none.
+	DurationSeconds95 float64   `db:"duration_seconds_95" json:"duration_seconds_95"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
+
 type TemplateVersionParameter struct {
 	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
 	// Parameter name
@@ -1822,6 +2606,26 @@ type TemplateVersionParameter struct {
 	DisplayOrder int32 `db:"display_order" json:"display_order"`
 	// The value of an ephemeral parameter will not be preserved between consecutive workspace builds.
 	Ephemeral bool `db:"ephemeral" json:"ephemeral"`
+	// Rules restricting the parameter based on the values of other parameters
+	Dependencies json.RawMessage `db:"dependencies" json:"dependencies"`
+	// Endpoint returning the options of the parameter, replacing the static options
+	OptionsURL string `db:"options_url" json:"options_url"`
+}
+
+type TemplateVersionPromotion struct {
+	ID                uuid.UUID `db:"id" json:"id"`
+	OrganizationID    uuid.UUID `db:"organization_id" json:"organization_id"`
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	RequestedBy       uuid.UUID `db:"requested_by" json:"requested_by"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	// An optional description from the requester of the changes in the version.
+	Message   string                         `db:"message" json:"message"`
+	Status    TemplateVersionPromotionStatus `db:"status" json:"status"`
+	DecidedBy uuid.NullUUID                  `db:"decided_by" json:"decided_by"`
+	DecidedAt sql.NullTime                   `db:"decided_at" json:"decided_at"`
+	// An optional explanation of the decision, such as a change ticket number.
+	Reason string `db:"reason" json:"reason"`
 }
 
 type TemplateVersionTable struct {
@@ -1875,6 +2679,12 @@ type User struct {
 	QuietHoursSchedule string `db:"quiet_hours_schedule" json:"quiet_hours_schedule"`
 }
 
+// Users the identity provider deactivated through SCIM. The deprovisioning policy is applied to these users until they are reactivated or deleted.
+type UserDeprovision struct {
+	UserID          uuid.UUID `db:"user_id" json:"user_id"`
+	DeprovisionedAt time.Time `db:"deprovisioned_at" json:"deprovisioned_at"`
+}
+
 type UserLink struct {
 	UserID            uuid.UUID `db:"user_id" json:"user_id"`
 	LoginType         LoginType `db:"login_type" json:"login_type"`
@@ -1884,6 +2694,32 @@ type UserLink struct {
 	OAuthExpiry       time.Time `db:"oauth_expiry" json:"oauth_expiry"`
 }
 
+// Per-user limits of concurrent web terminal sessions. An override takes precedence over the limit of the deployment.
+type UserPTYSessionOverride struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	// 0 means the user has no limit.
+	MaxPTYSessions int32     `db:"max_pty_sessions" json:"max_pty_sessions"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Per-user quota allowances. An override takes precedence over any quota the user receives from groups.
+type UserQuotaOverride struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// The latest latency each user reported for each region. Used to rank the regions a user should connect through.
+type UserRegionLatency struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	// The ID of the workspace proxy, or the deployment ID for the primary region.
+	RegionID  uuid.UUID `db:"region_id" json:"region_id"`
+	LatencyMS float64   `db:"latency_ms" json:"latency_ms"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
 // Visible fields of users are allowed to be joined with other tables for including context of other resources.
 type VisibleUser struct {
 	ID        uuid.UUID      `db:"id" json:"id"`
@@ -1905,6 +2741,8 @@ type Workspace struct {
 	LastUsedAt        time.Time      `db:"last_used_at" json:"last_used_at"`
 	LockedAt          sql.NullTime   `db:"locked_at" json:"locked_at"`
 	DeletingAt        sql.NullTime   `db:"deleting_at" json:"deleting_at"`
+	// Determines if the workspace is started with the active template version when it is outdated.
+	AutomaticUpdates AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 }
 
 type WorkspaceAgent struct {
@@ -1955,6 +2793,54 @@ type WorkspaceAgent struct {
 	// The time the agent entered the ready or start_error lifecycle state
 	ReadyAt    sql.NullTime              `db:"ready_at" json:"ready_at"`
 	Subsystems []WorkspaceAgentSubsystem `db:"subsystems" json:"subsystems"`
+	// Specifies the order in which agents are displayed within their resource, lowest first.
+	DisplayOrder int32 `db:"display_order" json:"display_order"`
+	// Facts about the host reported by the workspace agent on startup, such as the Windows version and build.
+	HostFacts json.RawMessage `db:"host_facts" json:"host_facts"`
+	// The offset of the clock of the workspace agent from the clock of coderd in milliseconds, as last reported by the agent. Positive values mean the agent clock is ahead.
+	ClockOffsetMS int64 `db:"clock_offset_ms" json:"clock_offset_ms"`
+}
+
+// Replacements of the auth tokens of workspace agents. The previous token stays valid until it expires, so requests of the agent in flight during the rotation succeed.
+type WorkspaceAgentAuthTokenRotation struct {
+	ID                         uuid.UUID `db:"id" json:"id"`
+	AgentID                    uuid.UUID `db:"agent_id" json:"agent_id"`
+	PreviousAuthToken          uuid.UUID `db:"previous_auth_token" json:"previous_auth_token"`
+	PreviousAuthTokenExpiresAt time.Time `db:"previous_auth_token_expires_at" json:"previous_auth_token_expires_at"`
+	// The previous token was revoked because it may be compromised, it expired immediately.
+	Revoked   bool      `db:"revoked" json:"revoked"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Connections made to workspace agents by clients such as the CLI and IDE plugins
+// Traffic of workspace agent connections by connection type and peer. Rows are purged with workspace_agent_stats.
+type WorkspaceAgentBandwidthStat struct {
+	ID             uuid.UUID `db:"id" json:"id"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	AgentID        uuid.UUID `db:"agent_id" json:"agent_id"`
+	WorkspaceID    uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TemplateID     uuid.UUID `db:"template_id" json:"template_id"`
+	ConnectionType string    `db:"connection_type" json:"connection_type"`
+	// Tailnet IP address of the remote end of the connections.
+	Peer string `db:"peer" json:"peer"`
+	// Agent port of port forwarded connections, 0 for other connection types.
+	Port    int32 `db:"port" json:"port"`
+	RxBytes int64 `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes int64 `db:"tx_bytes" json:"tx_bytes"`
+}
+
+type WorkspaceAgentClientConnection struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	// The user the client connected as. Null if the client is a workspace proxy.
+	UserID         uuid.NullUUID `db:"user_id" json:"user_id"`
+	ClientType     string        `db:"client_type" json:"client_type"`
+	ClientVersion  string        `db:"client_version" json:"client_version"`
+	ConnectedAt    time.Time     `db:"connected_at" json:"connected_at"`
+	DisconnectedAt sql.NullTime  `db:"disconnected_at" json:"disconnected_at"`
+	// The IP address the client connected from.
+	IPAddress pqtype.Inet `db:"ip_address" json:"ip_address"`
 }
 
 type WorkspaceAgentLog struct {
@@ -1978,6 +2864,25 @@ type WorkspaceAgentMetadatum struct {
 	CollectedAt      time.Time `db:"collected_at" json:"collected_at"`
 }
 
+// A bounded history of the values reported for each workspace agent metadata key
+type WorkspaceAgentMetadataHistory struct {
+	ID               int64     `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Key              string    `db:"key" json:"key"`
+	Value            string    `db:"value" json:"value"`
+	Error            string    `db:"error" json:"error"`
+	CollectedAt      time.Time `db:"collected_at" json:"collected_at"`
+}
+
+// SSH host keys of workspace agents. Agents are recreated by every build, so keys are stored by workspace and agent name to present the same key across rebuilds.
+type WorkspaceAgentSSHHostKey struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+	// The PEM encoded private key, encrypted with the app security key of the deployment.
+	EncryptedPrivateKey string    `db:"encrypted_private_key" json:"encrypted_private_key"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
+}
+
 type WorkspaceAgentStat struct {
 	ID                          uuid.UUID       `db:"id" json:"id"`
 	CreatedAt                   time.Time       `db:"created_at" json:"created_at"`
@@ -2014,6 +2919,10 @@ type WorkspaceApp struct {
 	SharingLevel         AppSharingLevel    `db:"sharing_level" json:"sharing_level"`
 	Slug                 string             `db:"slug" json:"slug"`
 	External             bool               `db:"external" json:"external"`
+	// Specifies the order in which apps are displayed within their agent, lowest first.
+	DisplayOrder int32 `db:"display_order" json:"display_order"`
+	// Determines if the app is not shown in user interfaces.
+	Hidden bool `db:"hidden" json:"hidden"`
 }
 
 // A record of workspace app usage statistics
@@ -2040,6 +2949,21 @@ type WorkspaceAppStat struct {
 	Requests int32 `db:"requests" json:"requests"`
 }
 
+// Approval requests for workspace builds that are held until an approver decides on them.
+type WorkspaceApproval struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// The provisioner job of the held build. Provisioners only acquire it once the request is approved.
+	JobID       uuid.UUID               `db:"job_id" json:"job_id"`
+	RequestedBy uuid.UUID               `db:"requested_by" json:"requested_by"`
+	CreatedAt   time.Time               `db:"created_at" json:"created_at"`
+	Status      WorkspaceApprovalStatus `db:"status" json:"status"`
+	DecidedBy   uuid.NullUUID           `db:"decided_by" json:"decided_by"`
+	DecidedAt   sql.NullTime            `db:"decided_at" json:"decided_at"`
+	// An optional explanation of the decision, such as a change ticket number.
+	Reason string `db:"reason" json:"reason"`
+}
+
 // Joins in the username + avatar url of the initiated by user.
 type WorkspaceBuild struct {
 	ID                   uuid.UUID           `db:"id" json:"id"`
@@ -2056,6 +2980,7 @@ type WorkspaceBuild struct {
 	Reason               BuildReason         `db:"reason" json:"reason"`
 	DailyCost            int32               `db:"daily_cost" json:"daily_cost"`
 	MaxDeadline          time.Time           `db:"max_deadline" json:"max_deadline"`
+	InitiatorTokenName   string              `db:"initiator_token_name" json:"initiator_token_name"`
 	InitiatorByAvatarUrl sql.NullString      `db:"initiator_by_avatar_url" json:"initiator_by_avatar_url"`
 	InitiatorByUsername  string              `db:"initiator_by_username" json:"initiator_by_username"`
 }
@@ -2068,6 +2993,17 @@ type WorkspaceBuildParameter struct {
 	Value string `db:"value" json:"value"`
 }
 
+// Workspace build requests waiting for the active build of their workspace to finish.
+type WorkspaceBuildQueueEntry struct {
+	ID                 uuid.UUID `db:"id" json:"id"`
+	WorkspaceID        uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	InitiatorID        uuid.UUID `db:"initiator_id" json:"initiator_id"`
+	InitiatorTokenName string    `db:"initiator_token_name" json:"initiator_token_name"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+	// The request the build is created with once the workspace has no active build.
+	Request json.RawMessage `db:"request" json:"request"`
+}
+
 type WorkspaceBuildTable struct {
 	ID                uuid.UUID           `db:"id" json:"id"`
 	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
@@ -2083,6 +3019,27 @@ type WorkspaceBuildTable struct {
 	Reason            BuildReason         `db:"reason" json:"reason"`
 	DailyCost         int32               `db:"daily_cost" json:"daily_cost"`
 	MaxDeadline       time.Time           `db:"max_deadline" json:"max_deadline"`
+	// The name of the API token the initiator used to start the build. Empty if the build was not started with a named token.
+	InitiatorTokenName string `db:"initiator_token_name" json:"initiator_token_name"`
+}
+
+// Namespaced JSON attached to workspaces by external integrations
+type WorkspaceExternalMetadatum struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Namespace   string    `db:"namespace" json:"namespace"`
+	// Public metadata is visible to anyone who can read the workspace. Private metadata is only visible to users who can read all workspaces in the organization.
+	Visibility WorkspaceExternalMetadataVisibility `db:"visibility" json:"visibility"`
+	Data       json.RawMessage                     `db:"data" json:"data"`
+	UpdatedAt  time.Time                           `db:"updated_at" json:"updated_at"`
+}
+
+// Names workspaces had before they were renamed.
+type WorkspacePreviousName struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name        string    `db:"name" json:"name"`
+	RenamedAt   time.Time `db:"renamed_at" json:"renamed_at"`
+	// Until this time, app URLs and lookups using the previous name resolve to the renamed workspace.
+	RedirectExpiresAt time.Time `db:"redirect_expires_at" json:"redirect_expires_at"`
 }
 
 type WorkspaceProxy struct {
@@ -2105,6 +3062,10 @@ type WorkspaceProxy struct {
 	DerpEnabled       bool   `db:"derp_enabled" json:"derp_enabled"`
 	// Disables app/terminal proxying for this proxy and only acts as a DERP relay.
 	DerpOnly bool `db:"derp_only" json:"derp_only"`
+	// Members of these organizations may use the proxy. The proxy is available to everyone when both organization_ids and group_ids are empty.
+	OrganizationIDs []uuid.UUID `db:"organization_ids" json:"organization_ids"`
+	// Members of these groups may use the proxy. The proxy is available to everyone when both organization_ids and group_ids are empty.
+	GroupIDs []uuid.UUID `db:"group_ids" json:"group_ids"`
 }
 
 type WorkspaceResource struct {
@@ -2118,6 +3079,8 @@ type WorkspaceResource struct {
 	Icon         string              `db:"icon" json:"icon"`
 	InstanceType sql.NullString      `db:"instance_type" json:"instance_type"`
 	DailyCost    int32               `db:"daily_cost" json:"daily_cost"`
+	// Specifies the order in which resources are displayed, lowest first.
+	DisplayOrder int32 `db:"display_order" json:"display_order"`
 }
 
 type WorkspaceResourceMetadatum struct {
@@ -2127,3 +3090,20 @@ type WorkspaceResourceMetadatum struct {
 	Sensitive           bool           `db:"sensitive" json:"sensitive"`
 	ID                  int64          `db:"id" json:"id"`
 }
+
+// Inbound webhooks that let external systems trigger a fixed action on a workspace.
+type WorkspaceWebhook struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	// Triggered actions run with the permissions of this user.
+	CreatedBy uuid.UUID              `db:"created_by" json:"created_by"`
+	CreatedAt time.Time              `db:"created_at" json:"created_at"`
+	Name      string                 `db:"name" json:"name"`
+	Action    WorkspaceWebhookAction `db:"action" json:"action"`
+	// The agent that runs the command of an exec webhook. Empty if the workspace has a single agent.
+	AgentName string `db:"agent_name" json:"agent_name"`
+	Command   string `db:"command" json:"command"`
+	// Shared secret that trigger requests are signed with using HMAC-SHA256.
+	Secret          string       `db:"secret" json:"secret"`
+	LastTriggeredAt sql.NullTime `db:"last_triggered_at" json:"last_triggered_at"`
+}
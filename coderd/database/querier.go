@@ -28,19 +28,39 @@ type sqlcQuerier interface {
 	DeleteAPIKeyByID(ctx context.Context, id string) error
 	DeleteAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
 	DeleteApplicationConnectAPIKeysByUserID(ctx context.Context, userID uuid.UUID) error
+	DeleteAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) error
 	DeleteCoordinator(ctx context.Context, id uuid.UUID) error
 	DeleteGitSSHKey(ctx context.Context, userID uuid.UUID) error
 	DeleteGroupByID(ctx context.Context, id uuid.UUID) error
+	DeleteGroupDefaultTemplates(ctx context.Context, groupID uuid.UUID) error
 	DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteGroupMemberFromGroupParams) error
 	DeleteGroupMembersByOrgAndUser(ctx context.Context, arg DeleteGroupMembersByOrgAndUserParams) error
 	DeleteLicense(ctx context.Context, id int32) (int32, error)
+	DeleteManagedEnvironmentVariableByID(ctx context.Context, id uuid.UUID) error
+	DeleteOldNetcheckReports(ctx context.Context) error
 	// If an agent hasn't connected in the last 7 days, we purge it's logs.
 	// Logs can take up a lot of space, so it's important we clean up frequently.
+	DeleteOldPlatformEvents(ctx context.Context) error
+	// Rotations are only needed to accept the previous token, the audit log
+	// keeps their history.
+	DeleteOldWorkspaceAgentAuthTokenRotations(ctx context.Context) error
+	DeleteOldWorkspaceAgentBandwidthStats(ctx context.Context) error
+	DeleteOldWorkspaceAgentClientConnections(ctx context.Context) error
 	DeleteOldWorkspaceAgentLogs(ctx context.Context) error
 	DeleteOldWorkspaceAgentStats(ctx context.Context) error
 	DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt time.Time) error
 	DeleteTailnetAgent(ctx context.Context, arg DeleteTailnetAgentParams) (DeleteTailnetAgentRow, error)
 	DeleteTailnetClient(ctx context.Context, arg DeleteTailnetClientParams) (DeleteTailnetClientRow, error)
+	DeleteUserDeprovision(ctx context.Context, userID uuid.UUID) error
+	DeleteUserPTYSessionOverride(ctx context.Context, userID uuid.UUID) error
+	DeleteUserQuotaOverride(ctx context.Context, userID uuid.UUID) error
+	DeleteWorkspaceBuildQueueEntriesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error
+	DeleteWorkspaceBuildQueueEntryByID(ctx context.Context, id uuid.UUID) error
+	DeleteWorkspaceExternalMetadatum(ctx context.Context, arg DeleteWorkspaceExternalMetadatumParams) error
+	DeleteWorkspacePreviousName(ctx context.Context, arg DeleteWorkspacePreviousNameParams) error
+	DeleteWorkspaceWebhookByID(ctx context.Context, id uuid.UUID) error
+	// Expires the previous auth tokens of the agent that are still valid.
+	ExpireWorkspaceAgentAuthTokenRotations(ctx context.Context, arg ExpireWorkspaceAgentAuthTokenRotationsParams) error
 	GetAPIKeyByID(ctx context.Context, id string) (APIKey, error)
 	// there is no unique constraint on empty token names
 	GetAPIKeyByName(ctx context.Context, arg GetAPIKeyByNameParams) (APIKey, error)
@@ -52,9 +72,14 @@ type sqlcQuerier interface {
 	GetAllTailnetAgents(ctx context.Context) ([]TailnetAgent, error)
 	GetAllTailnetClients(ctx context.Context) ([]TailnetClient, error)
 	GetAppSecurityKey(ctx context.Context) (string, error)
+	GetAuditLogArchivalByID(ctx context.Context, id uuid.UUID) (AuditLogArchival, error)
+	GetAuditLogArchivals(ctx context.Context, rowLimit int32) ([]AuditLogArchival, error)
 	// GetAuditLogsBefore retrieves `row_limit` number of audit logs before the provided
 	// ID.
 	GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOffsetParams) ([]GetAuditLogsOffsetRow, error)
+	// GetAuditLogsOlderThan returns the oldest audit logs from before the given
+	// time, so they can be archived in batches.
+	GetAuditLogsOlderThan(ctx context.Context, arg GetAuditLogsOlderThanParams) ([]AuditLog, error)
 	// This function returns roles for authorization purposes. Implied member roles
 	// are included.
 	GetAuthorizationUserRoles(ctx context.Context, userID uuid.UUID) (GetAuthorizationUserRolesRow, error)
@@ -64,6 +89,7 @@ type sqlcQuerier interface {
 	GetDeploymentID(ctx context.Context) (string, error)
 	GetDeploymentWorkspaceAgentStats(ctx context.Context, createdAt time.Time) (GetDeploymentWorkspaceAgentStatsRow, error)
 	GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploymentWorkspaceStatsRow, error)
+	GetEntitlementSnapshots(ctx context.Context, arg GetEntitlementSnapshotsParams) ([]EntitlementSnapshot, error)
 	GetFileByHashAndCreator(ctx context.Context, arg GetFileByHashAndCreatorParams) (File, error)
 	GetFileByID(ctx context.Context, id uuid.UUID) (File, error)
 	// Get all templates that use a file.
@@ -72,18 +98,31 @@ type sqlcQuerier interface {
 	GetGitSSHKey(ctx context.Context, userID uuid.UUID) (GitSSHKey, error)
 	GetGroupByID(ctx context.Context, id uuid.UUID) (Group, error)
 	GetGroupByOrgAndName(ctx context.Context, arg GetGroupByOrgAndNameParams) (Group, error)
+	GetGroupDefaultTemplates(ctx context.Context, groupID uuid.UUID) ([]GroupDefaultTemplate, error)
 	// If the group is a user made group, then we need to check the group_members table.
 	// If it is the "Everyone" group, then we need to check the organization_members table.
 	GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]User, error)
+	// GetGroupMembersAnyStatus returns the members of a user made group regardless
+	// of their status, so dormant and suspended members are included.
+	GetGroupMembersAnyStatus(ctx context.Context, groupID uuid.UUID) ([]User, error)
 	GetGroupsByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]Group, error)
 	GetHungProvisionerJobs(ctx context.Context, updatedAt time.Time) ([]ProvisionerJob, error)
 	GetLastUpdateCheck(ctx context.Context) (string, error)
+	GetLatestWorkspaceApprovalByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceApproval, error)
 	GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceBuild, error)
 	GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceBuild, error)
 	GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceBuild, error)
 	GetLicenseByID(ctx context.Context, id int32) (License, error)
+	GetLicenseUsage(ctx context.Context, arg GetLicenseUsageParams) ([]LicenseUsage, error)
 	GetLicenses(ctx context.Context) ([]License, error)
 	GetLogoURL(ctx context.Context) (string, error)
+	GetManagedEnvironmentVariableByID(ctx context.Context, id uuid.UUID) (ManagedEnvironmentVariable, error)
+	// Returns the variables of the organization that aren't scoped to a template.
+	GetManagedEnvironmentVariablesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]ManagedEnvironmentVariable, error)
+	GetManagedEnvironmentVariablesByTemplateID(ctx context.Context, templateID uuid.NullUUID) ([]ManagedEnvironmentVariable, error)
+	// GetNetcheckReports returns the reports created in the time range, newest
+	// first, optionally filtered by user.
+	GetNetcheckReports(ctx context.Context, arg GetNetcheckReportsParams) ([]NetcheckReport, error)
 	GetOAuthSigningKey(ctx context.Context) (string, error)
 	GetOrganizationByID(ctx context.Context, id uuid.UUID) (Organization, error)
 	GetOrganizationByName(ctx context.Context, name string) (Organization, error)
@@ -92,26 +131,71 @@ type sqlcQuerier interface {
 	GetOrganizationMembershipsByUserID(ctx context.Context, userID uuid.UUID) ([]OrganizationMember, error)
 	GetOrganizations(ctx context.Context) ([]Organization, error)
 	GetOrganizationsByUserID(ctx context.Context, userID uuid.UUID) ([]Organization, error)
+	// Returns the workspaces of the template whose latest build doesn't use the
+	// active version of the template.
+	GetOutdatedWorkspacesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]Workspace, error)
 	GetParameterSchemasByJobID(ctx context.Context, jobID uuid.UUID) ([]ParameterSchema, error)
+	// Lists the active members of groups that didn't get the starter workspace of
+	// a default template of the group yet. The members of the Everyone group are
+	// the members of the organization.
+	GetPendingGroupDefaultTemplateWorkspaces(ctx context.Context) ([]GetPendingGroupDefaultTemplateWorkspacesRow, error)
+	// GetPendingProvisionerJobs returns the jobs that haven't been acquired by a
+	// provisioner daemon yet, oldest first.
+	GetPendingProvisionerJobs(ctx context.Context) ([]ProvisionerJob, error)
+	// GetPlatformEventsAfterID returns events newer than the given cursor, oldest
+	// first.
+	GetPlatformEventsAfterID(ctx context.Context, arg GetPlatformEventsAfterIDParams) ([]PlatformEvent, error)
 	GetPreviousTemplateVersion(ctx context.Context, arg GetPreviousTemplateVersionParams) (TemplateVersion, error)
 	GetProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error)
+	GetProvisionerJobBuildHooksByJobID(ctx context.Context, jobID uuid.UUID) ([]ProvisionerJobBuildHook, error)
 	GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (ProvisionerJob, error)
+	// GetProvisionerJobConcurrencyLimit returns the concurrency limit that the job
+	// would exceed if it ran, 'template' or 'organization', or an empty string.
+	// The job doesn't count against the limit itself, so it may already be
+	// started.
+	GetProvisionerJobConcurrencyLimit(ctx context.Context, id uuid.UUID) (string, error)
 	GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUID) ([]ProvisionerJob, error)
 	GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids []uuid.UUID) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error)
 	GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error)
+	// GetProvisionerJobsStartedAfter returns the jobs acquired by a provisioner
+	// daemon after the given time, which is used to measure queue wait times.
+	GetProvisionerJobsStartedAfter(ctx context.Context, startedAfter time.Time) ([]ProvisionerJob, error)
 	GetProvisionerLogsAfterID(ctx context.Context, arg GetProvisionerLogsAfterIDParams) ([]ProvisionerJobLog, error)
-	GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	// Returns the groups that contribute to the quota of the user: the groups the
+	// user is a member of, and the Everyone groups, whose allowance is the
+	// organization default.
+	GetQuotaBudgetsForUser(ctx context.Context, userID uuid.UUID) ([]Group, error)
 	GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error)
+	// Returns the daily cost of the latest build of each workspace of the user.
+	// The costs add up to GetQuotaConsumedForUser.
+	GetQuotaConsumedWorkspacesForUser(ctx context.Context, ownerID uuid.UUID) ([]GetQuotaConsumedWorkspacesForUserRow, error)
 	GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error)
 	GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error)
+	GetRuntimeExperiments(ctx context.Context) (string, error)
+	GetSSHCAKey(ctx context.Context) (string, error)
 	GetServiceBanner(ctx context.Context) (string, error)
 	GetTailnetAgents(ctx context.Context, id uuid.UUID) ([]TailnetAgent, error)
 	GetTailnetClientsForAgent(ctx context.Context, agentID uuid.UUID) ([]TailnetClient, error)
+	// GetTemplateAgentFirstConnectInsights returns, per template, how long the
+	// agents of successful start builds that completed within the given timeframe
+	// took to connect for the first time after the build completed. Agents that
+	// haven't connected by the end of their connection timeout are counted as
+	// never connected, agents without a connection timeout use the default one.
+	// The result can be filtered on template_ids.
+	GetTemplateAgentFirstConnectInsights(ctx context.Context, arg GetTemplateAgentFirstConnectInsightsParams) ([]GetTemplateAgentFirstConnectInsightsRow, error)
 	// GetTemplateAppInsights returns the aggregate usage of each app in a given
 	// timeframe. The result can be filtered on template_ids, meaning only user data
 	// from workspaces based on those templates will be included.
 	GetTemplateAppInsights(ctx context.Context, arg GetTemplateAppInsightsParams) ([]GetTemplateAppInsightsRow, error)
+	GetTemplateAccessRequestByID(ctx context.Context, id uuid.UUID) (TemplateAccessRequest, error)
+	GetTemplateAccessRequestsByTemplateID(ctx context.Context, arg GetTemplateAccessRequestsByTemplateIDParams) ([]TemplateAccessRequest, error)
 	GetTemplateAverageBuildTime(ctx context.Context, arg GetTemplateAverageBuildTimeParams) (GetTemplateAverageBuildTimeRow, error)
+	// GetTemplateBuildSLOInsights returns the number of workspace builds per
+	// template that completed within the given timeframe, along with how many of
+	// them succeeded and how many succeeded within the duration objective.
+	// Canceled builds are excluded as they say nothing about the health of the
+	// template. The result can be filtered on template_ids.
+	GetTemplateBuildSLOInsights(ctx context.Context, arg GetTemplateBuildSLOInsightsParams) ([]GetTemplateBuildSLOInsightsRow, error)
 	GetTemplateByID(ctx context.Context, id uuid.UUID) (Template, error)
 	GetTemplateByOrganizationAndName(ctx context.Context, arg GetTemplateByOrganizationAndNameParams) (Template, error)
 	GetTemplateDAUs(ctx context.Context, arg GetTemplateDAUsParams) ([]GetTemplateDAUsRow, error)
@@ -129,10 +213,15 @@ type sqlcQuerier interface {
 	// created in the timeframe and return the aggregate usage counts of parameter
 	// values.
 	GetTemplateParameterInsights(ctx context.Context, arg GetTemplateParameterInsightsParams) ([]GetTemplateParameterInsightsRow, error)
+	// GetTemplateVersionBuildInsights returns the build rollup of the versions of a
+	// template that have completed builds, newest version first.
+	GetTemplateVersionBuildInsights(ctx context.Context, templateID uuid.UUID) ([]GetTemplateVersionBuildInsightsRow, error)
 	GetTemplateVersionByID(ctx context.Context, id uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByJobID(ctx context.Context, jobID uuid.UUID) (TemplateVersion, error)
 	GetTemplateVersionByTemplateIDAndName(ctx context.Context, arg GetTemplateVersionByTemplateIDAndNameParams) (TemplateVersion, error)
 	GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error)
+	GetTemplateVersionPromotionByID(ctx context.Context, id uuid.UUID) (TemplateVersionPromotion, error)
+	GetTemplateVersionPromotions(ctx context.Context, arg GetTemplateVersionPromotionsParams) ([]TemplateVersionPromotion, error)
 	GetTemplateVersionVariables(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionVariable, error)
 	GetTemplateVersionsByIDs(ctx context.Context, ids []uuid.UUID) ([]TemplateVersion, error)
 	GetTemplateVersionsByTemplateID(ctx context.Context, arg GetTemplateVersionsByTemplateIDParams) ([]TemplateVersion, error)
@@ -140,9 +229,15 @@ type sqlcQuerier interface {
 	GetTemplates(ctx context.Context) ([]Template, error)
 	GetTemplatesWithFilter(ctx context.Context, arg GetTemplatesWithFilterParams) ([]Template, error)
 	GetUnexpiredLicenses(ctx context.Context) ([]License, error)
+	// GetUserBandwidthInsights returns the bytes transferred by the workspaces of
+	// each user, by connection type and agent port, largest first. The result can
+	// be filtered on template_ids, meaning only user data from workspaces based on
+	// those templates will be included.
+	GetUserBandwidthInsights(ctx context.Context, arg GetUserBandwidthInsightsParams) ([]GetUserBandwidthInsightsRow, error)
 	GetUserByEmailOrUsername(ctx context.Context, arg GetUserByEmailOrUsernameParams) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserCount(ctx context.Context) (int64, error)
+	GetUserDeprovisions(ctx context.Context) ([]UserDeprovision, error)
 	// GetUserLatencyInsights returns the median and 95th percentile connection
 	// latency that users have experienced. The result can be filtered on
 	// template_ids, meaning only user data from workspaces based on those templates
@@ -150,6 +245,9 @@ type sqlcQuerier interface {
 	GetUserLatencyInsights(ctx context.Context, arg GetUserLatencyInsightsParams) ([]GetUserLatencyInsightsRow, error)
 	GetUserLinkByLinkedID(ctx context.Context, linkedID string) (UserLink, error)
 	GetUserLinkByUserIDLoginType(ctx context.Context, arg GetUserLinkByUserIDLoginTypeParams) (UserLink, error)
+	GetUserPTYSessionOverride(ctx context.Context, userID uuid.UUID) (UserPTYSessionOverride, error)
+	GetUserQuotaOverride(ctx context.Context, userID uuid.UUID) (UserQuotaOverride, error)
+	GetUserRegionLatencies(ctx context.Context, arg GetUserRegionLatenciesParams) ([]UserRegionLatency, error)
 	// This will never return deleted users.
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]GetUsersRow, error)
 	// This shouldn't check for deleted, because it's frequently used
@@ -157,17 +255,38 @@ type sqlcQuerier interface {
 	// for another user, then be deleted... we still want them to appear!
 	GetUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User, error)
 	GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, authToken uuid.UUID) (GetWorkspaceAgentAndOwnerByAuthTokenRow, error)
+	GetWorkspaceAgentBandwidthStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentBandwidthStatsAndLabelsRow, error)
+	// Sums the traffic of the agents of a workspace by agent and peer, so it can
+	// be attributed to the tailnet clients connected to the agents.
+	GetWorkspaceAgentBandwidthStatsByPeer(ctx context.Context, arg GetWorkspaceAgentBandwidthStatsByPeerParams) ([]GetWorkspaceAgentBandwidthStatsByPeerRow, error)
 	GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (WorkspaceAgent, error)
 	GetWorkspaceAgentByInstanceID(ctx context.Context, authInstanceID string) (WorkspaceAgent, error)
+	// GetWorkspaceAgentClientConnections returns the most recent client
+	// connections made to an agent, newest first.
+	GetWorkspaceAgentClientConnections(ctx context.Context, arg GetWorkspaceAgentClientConnectionsParams) ([]WorkspaceAgentClientConnection, error)
+	// GetWorkspaceAgentClientConnectionsByUserID returns the most recent client
+	// connections made by a user to any agent, newest first.
+	GetWorkspaceAgentClientConnectionsByUserID(ctx context.Context, arg GetWorkspaceAgentClientConnectionsByUserIDParams) ([]GetWorkspaceAgentClientConnectionsByUserIDRow, error)
 	GetWorkspaceAgentLifecycleStateByID(ctx context.Context, id uuid.UUID) (GetWorkspaceAgentLifecycleStateByIDRow, error)
 	GetWorkspaceAgentLogsAfter(ctx context.Context, arg GetWorkspaceAgentLogsAfterParams) ([]WorkspaceAgentLog, error)
 	GetWorkspaceAgentMetadata(ctx context.Context, workspaceAgentID uuid.UUID) ([]WorkspaceAgentMetadatum, error)
+	// GetWorkspaceAgentMetadataHistory returns the most recent values of a
+	// metadata key, newest first.
+	GetWorkspaceAgentMetadataHistory(ctx context.Context, arg GetWorkspaceAgentMetadataHistoryParams) ([]WorkspaceAgentMetadataHistory, error)
+	GetWorkspaceAgentSSHHostKey(ctx context.Context, arg GetWorkspaceAgentSSHHostKeyParams) (WorkspaceAgentSSHHostKey, error)
 	GetWorkspaceAgentStats(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsRow, error)
 	GetWorkspaceAgentStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentStatsAndLabelsRow, error)
 	GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceAgent, error)
 	GetWorkspaceAgentsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceAgent, error)
 	GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceAgent, error)
+	// GetWorkspaceAgentsNeverConnected returns the agents of the latest successful
+	// start builds of workspaces that haven't connected by the end of their
+	// connection timeout, where the timeout elapsed within the given timeframe.
+	// Agents without a connection timeout use the default one.
+	GetWorkspaceAgentsNeverConnected(ctx context.Context, arg GetWorkspaceAgentsNeverConnectedParams) ([]GetWorkspaceAgentsNeverConnectedRow, error)
 	GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg GetWorkspaceAppByAgentIDAndSlugParams) (WorkspaceApp, error)
+	GetWorkspaceApprovalByID(ctx context.Context, id uuid.UUID) (WorkspaceApproval, error)
+	GetWorkspaceApprovalByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceApproval, error)
 	GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error)
 	GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error)
@@ -175,12 +294,20 @@ type sqlcQuerier interface {
 	GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceBuild, error)
 	GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Context, arg GetWorkspaceBuildByWorkspaceIDAndBuildNumberParams) (WorkspaceBuild, error)
 	GetWorkspaceBuildParameters(ctx context.Context, workspaceBuildID uuid.UUID) ([]WorkspaceBuildParameter, error)
+	GetWorkspaceBuildQueueEntriesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceBuildQueueEntry, error)
+	// Returns the workspaces that have builds waiting in their queue.
+	GetWorkspaceBuildQueueWorkspaceIDs(ctx context.Context) ([]uuid.UUID, error)
 	GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg GetWorkspaceBuildsByWorkspaceIDParams) ([]WorkspaceBuild, error)
 	GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error)
 	GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUID) (Workspace, error)
 	GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Workspace, error)
 	GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWorkspaceByOwnerIDAndNameParams) (Workspace, error)
+	// Returns the workspace most recently renamed away from the given name, as
+	// long as the redirect from that name has not expired.
+	GetWorkspaceByOwnerIDAndPreviousName(ctx context.Context, arg GetWorkspaceByOwnerIDAndPreviousNameParams) (Workspace, error)
 	GetWorkspaceByWorkspaceAppID(ctx context.Context, workspaceAppID uuid.UUID) (Workspace, error)
+	GetWorkspaceExternalMetadataByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]GetWorkspaceExternalMetadataByWorkspaceIDsRow, error)
+	GetWorkspacePreviousNamesByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]GetWorkspacePreviousNamesByWorkspaceIDsRow, error)
 	GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy, error)
 	// Finds a workspace proxy that has an access URL or app hostname that matches
 	// the provided hostname. This is to check if a hostname matches any workspace
@@ -198,6 +325,8 @@ type sqlcQuerier interface {
 	GetWorkspaceResourcesByJobID(ctx context.Context, jobID uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceResource, error)
 	GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error)
+	GetWorkspaceWebhookByID(ctx context.Context, id uuid.UUID) (WorkspaceWebhook, error)
+	GetWorkspaceWebhooksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceWebhook, error)
 	GetWorkspaces(ctx context.Context, arg GetWorkspacesParams) ([]GetWorkspacesRow, error)
 	GetWorkspacesEligibleForTransition(ctx context.Context, now time.Time) ([]Workspace, error)
 	InsertAPIKey(ctx context.Context, arg InsertAPIKeyParams) (APIKey, error)
@@ -206,46 +335,74 @@ type sqlcQuerier interface {
 	// every member of the org.
 	InsertAllUsersGroup(ctx context.Context, organizationID uuid.UUID) (Group, error)
 	InsertAuditLog(ctx context.Context, arg InsertAuditLogParams) (AuditLog, error)
+	InsertAuditLogArchival(ctx context.Context, arg InsertAuditLogArchivalParams) (AuditLogArchival, error)
 	InsertDERPMeshKey(ctx context.Context, value string) error
 	InsertDeploymentID(ctx context.Context, value string) error
+	// Only the first snapshot of a day is kept, so replicas can all try to take
+	// it.
+	InsertEntitlementSnapshot(ctx context.Context, arg InsertEntitlementSnapshotParams) error
 	InsertFile(ctx context.Context, arg InsertFileParams) (File, error)
 	InsertGitAuthLink(ctx context.Context, arg InsertGitAuthLinkParams) (GitAuthLink, error)
 	InsertGitSSHKey(ctx context.Context, arg InsertGitSSHKeyParams) (GitSSHKey, error)
 	InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error)
+	InsertGroupDefaultTemplate(ctx context.Context, arg InsertGroupDefaultTemplateParams) (GroupDefaultTemplate, error)
+	InsertGroupDefaultTemplateWorkspace(ctx context.Context, arg InsertGroupDefaultTemplateWorkspaceParams) error
 	InsertGroupMember(ctx context.Context, arg InsertGroupMemberParams) error
 	InsertLicense(ctx context.Context, arg InsertLicenseParams) (License, error)
+	InsertManagedEnvironmentVariable(ctx context.Context, arg InsertManagedEnvironmentVariableParams) (ManagedEnvironmentVariable, error)
 	// Inserts any group by name that does not exist. All new groups are given
 	// a random uuid, are inserted into the same organization. They have the default
 	// values for avatar, display name, and quota allowance (all zero values).
 	// If the name conflicts, do nothing.
 	InsertMissingGroups(ctx context.Context, arg InsertMissingGroupsParams) ([]Group, error)
+	InsertNetcheckReport(ctx context.Context, arg InsertNetcheckReportParams) (NetcheckReport, error)
 	InsertOrganization(ctx context.Context, arg InsertOrganizationParams) (Organization, error)
 	InsertOrganizationMember(ctx context.Context, arg InsertOrganizationMemberParams) (OrganizationMember, error)
+	InsertPlatformEvent(ctx context.Context, arg InsertPlatformEventParams) (PlatformEvent, error)
 	InsertProvisionerDaemon(ctx context.Context, arg InsertProvisionerDaemonParams) (ProvisionerDaemon, error)
 	InsertProvisionerJob(ctx context.Context, arg InsertProvisionerJobParams) (ProvisionerJob, error)
+	InsertProvisionerJobBuildHook(ctx context.Context, arg InsertProvisionerJobBuildHookParams) (ProvisionerJobBuildHook, error)
 	InsertProvisionerJobLogs(ctx context.Context, arg InsertProvisionerJobLogsParams) ([]ProvisionerJobLog, error)
 	InsertReplica(ctx context.Context, arg InsertReplicaParams) (Replica, error)
 	InsertTemplate(ctx context.Context, arg InsertTemplateParams) error
+	InsertTemplateAccessRequest(ctx context.Context, arg InsertTemplateAccessRequestParams) (TemplateAccessRequest, error)
 	InsertTemplateVersion(ctx context.Context, arg InsertTemplateVersionParams) error
 	InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error)
+	InsertTemplateVersionPromotion(ctx context.Context, arg InsertTemplateVersionPromotionParams) (TemplateVersionPromotion, error)
 	InsertTemplateVersionVariable(ctx context.Context, arg InsertTemplateVersionVariableParams) (TemplateVersionVariable, error)
 	InsertUser(ctx context.Context, arg InsertUserParams) (User, error)
+	// Keeps the original deprovisioning time if the identity provider
+	// deactivates a user more than once.
+	InsertUserDeprovision(ctx context.Context, arg InsertUserDeprovisionParams) error
 	// InsertUserGroupsByName adds a user to all provided groups, if they exist.
 	InsertUserGroupsByName(ctx context.Context, arg InsertUserGroupsByNameParams) error
 	InsertUserLink(ctx context.Context, arg InsertUserLinkParams) (UserLink, error)
 	InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (Workspace, error)
 	InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error)
+	InsertWorkspaceAgentAuthTokenRotation(ctx context.Context, arg InsertWorkspaceAgentAuthTokenRotationParams) (WorkspaceAgentAuthTokenRotation, error)
+	InsertWorkspaceAgentBandwidthStats(ctx context.Context, arg InsertWorkspaceAgentBandwidthStatsParams) error
+	InsertWorkspaceAgentClientConnection(ctx context.Context, arg InsertWorkspaceAgentClientConnectionParams) (WorkspaceAgentClientConnection, error)
 	InsertWorkspaceAgentLogs(ctx context.Context, arg InsertWorkspaceAgentLogsParams) ([]WorkspaceAgentLog, error)
 	InsertWorkspaceAgentMetadata(ctx context.Context, arg InsertWorkspaceAgentMetadataParams) error
+	// InsertWorkspaceAgentMetadataHistory records a collected metadata value and
+	// drops the oldest values of the key so that at most max_entries values are
+	// retained.
+	InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg InsertWorkspaceAgentMetadataHistoryParams) error
+	// Returns the existing key instead if the agent already has one, so agents
+	// that fetch their manifest concurrently get the same key.
+	InsertWorkspaceAgentSSHHostKey(ctx context.Context, arg InsertWorkspaceAgentSSHHostKeyParams) (WorkspaceAgentSSHHostKey, error)
 	InsertWorkspaceAgentStat(ctx context.Context, arg InsertWorkspaceAgentStatParams) (WorkspaceAgentStat, error)
 	InsertWorkspaceAgentStats(ctx context.Context, arg InsertWorkspaceAgentStatsParams) error
 	InsertWorkspaceApp(ctx context.Context, arg InsertWorkspaceAppParams) (WorkspaceApp, error)
 	InsertWorkspaceAppStats(ctx context.Context, arg InsertWorkspaceAppStatsParams) error
+	InsertWorkspaceApproval(ctx context.Context, arg InsertWorkspaceApprovalParams) (WorkspaceApproval, error)
 	InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error
 	InsertWorkspaceBuildParameters(ctx context.Context, arg InsertWorkspaceBuildParametersParams) error
+	InsertWorkspaceBuildQueueEntry(ctx context.Context, arg InsertWorkspaceBuildQueueEntryParams) (WorkspaceBuildQueueEntry, error)
 	InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspaceProxyParams) (WorkspaceProxy, error)
 	InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error)
 	InsertWorkspaceResourceMetadata(ctx context.Context, arg InsertWorkspaceResourceMetadataParams) ([]WorkspaceResourceMetadatum, error)
+	InsertWorkspaceWebhook(ctx context.Context, arg InsertWorkspaceWebhookParams) (WorkspaceWebhook, error)
 	RegisterWorkspaceProxy(ctx context.Context, arg RegisterWorkspaceProxyParams) (WorkspaceProxy, error)
 	// Non blocking lock. Returns true if the lock was acquired, false otherwise.
 	//
@@ -253,16 +410,27 @@ type sqlcQuerier interface {
 	// released when the transaction ends.
 	TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock int64) (bool, error)
 	UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDParams) error
+	UpdateAuditLogArchivalByID(ctx context.Context, arg UpdateAuditLogArchivalByIDParams) (AuditLogArchival, error)
 	UpdateGitAuthLink(ctx context.Context, arg UpdateGitAuthLinkParams) (GitAuthLink, error)
 	UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyParams) (GitSSHKey, error)
+	UpdateGroupAvatarByID(ctx context.Context, arg UpdateGroupAvatarByIDParams) (Group, error)
 	UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error)
 	UpdateInactiveUsersToDormant(ctx context.Context, arg UpdateInactiveUsersToDormantParams) ([]UpdateInactiveUsersToDormantRow, error)
+	UpdateManagedEnvironmentVariableByID(ctx context.Context, arg UpdateManagedEnvironmentVariableByIDParams) (ManagedEnvironmentVariable, error)
 	UpdateMemberRoles(ctx context.Context, arg UpdateMemberRolesParams) (OrganizationMember, error)
+	UpdateOrganizationProvisionerLimits(ctx context.Context, arg UpdateOrganizationProvisionerLimitsParams) (Organization, error)
 	UpdateProvisionerJobByID(ctx context.Context, arg UpdateProvisionerJobByIDParams) error
 	UpdateProvisionerJobWithCancelByID(ctx context.Context, arg UpdateProvisionerJobWithCancelByIDParams) error
 	UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error
+	// Puts a hung job back in the queue so another provisioner daemon can
+	// acquire it.
+	UpdateProvisionerJobWithRequeueByID(ctx context.Context, arg UpdateProvisionerJobWithRequeueByIDParams) error
 	UpdateReplica(ctx context.Context, arg UpdateReplicaParams) (Replica, error)
+	UpdateReplicaDrainingAt(ctx context.Context, arg UpdateReplicaDrainingAtParams) (Replica, error)
 	UpdateTemplateACLByID(ctx context.Context, arg UpdateTemplateACLByIDParams) error
+	// Records the decision on a pending access request. Requests can only be
+	// decided once, so no rows are returned if the request was already decided.
+	UpdateTemplateAccessRequestStatusByID(ctx context.Context, arg UpdateTemplateAccessRequestStatusByIDParams) (TemplateAccessRequest, error)
 	UpdateTemplateActiveVersionByID(ctx context.Context, arg UpdateTemplateActiveVersionByIDParams) error
 	UpdateTemplateDeletedByID(ctx context.Context, arg UpdateTemplateDeletedByIDParams) error
 	UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error
@@ -270,6 +438,9 @@ type sqlcQuerier interface {
 	UpdateTemplateVersionByID(ctx context.Context, arg UpdateTemplateVersionByIDParams) error
 	UpdateTemplateVersionDescriptionByJobID(ctx context.Context, arg UpdateTemplateVersionDescriptionByJobIDParams) error
 	UpdateTemplateVersionGitAuthProvidersByJobID(ctx context.Context, arg UpdateTemplateVersionGitAuthProvidersByJobIDParams) error
+	// Records the decision on a pending promotion. Promotions can only be decided
+	// once, so no rows are returned if the promotion was already decided.
+	UpdateTemplateVersionPromotionStatusByID(ctx context.Context, arg UpdateTemplateVersionPromotionStatusByIDParams) (TemplateVersionPromotion, error)
 	UpdateTemplateWorkspacesLastUsedAt(ctx context.Context, arg UpdateTemplateWorkspacesLastUsedAtParams) error
 	UpdateUserDeletedByID(ctx context.Context, arg UpdateUserDeletedByIDParams) error
 	UpdateUserHashedPassword(ctx context.Context, arg UpdateUserHashedPasswordParams) error
@@ -282,12 +453,19 @@ type sqlcQuerier interface {
 	UpdateUserRoles(ctx context.Context, arg UpdateUserRolesParams) (User, error)
 	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) (User, error)
 	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) (Workspace, error)
+	UpdateWorkspaceAgentAuthTokenByID(ctx context.Context, arg UpdateWorkspaceAgentAuthTokenByIDParams) error
+	UpdateWorkspaceAgentClientConnectionDisconnectedAt(ctx context.Context, arg UpdateWorkspaceAgentClientConnectionDisconnectedAtParams) error
+	UpdateWorkspaceAgentClockOffsetByID(ctx context.Context, arg UpdateWorkspaceAgentClockOffsetByIDParams) error
 	UpdateWorkspaceAgentConnectionByID(ctx context.Context, arg UpdateWorkspaceAgentConnectionByIDParams) error
 	UpdateWorkspaceAgentLifecycleStateByID(ctx context.Context, arg UpdateWorkspaceAgentLifecycleStateByIDParams) error
 	UpdateWorkspaceAgentLogOverflowByID(ctx context.Context, arg UpdateWorkspaceAgentLogOverflowByIDParams) error
 	UpdateWorkspaceAgentMetadata(ctx context.Context, arg UpdateWorkspaceAgentMetadataParams) error
 	UpdateWorkspaceAgentStartupByID(ctx context.Context, arg UpdateWorkspaceAgentStartupByIDParams) error
 	UpdateWorkspaceAppHealthByID(ctx context.Context, arg UpdateWorkspaceAppHealthByIDParams) error
+	// Records the decision on a pending approval request. Requests can only be
+	// decided once, so no rows are returned if the request was already decided.
+	UpdateWorkspaceApprovalStatusByID(ctx context.Context, arg UpdateWorkspaceApprovalStatusByIDParams) (WorkspaceApproval, error)
+	UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg UpdateWorkspaceAutomaticUpdatesParams) error
 	UpdateWorkspaceAutostart(ctx context.Context, arg UpdateWorkspaceAutostartParams) error
 	UpdateWorkspaceBuildByID(ctx context.Context, arg UpdateWorkspaceBuildByIDParams) error
 	UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error
@@ -296,8 +474,14 @@ type sqlcQuerier interface {
 	UpdateWorkspaceLockedDeletingAt(ctx context.Context, arg UpdateWorkspaceLockedDeletingAtParams) (Workspace, error)
 	// This allows editing the properties of a workspace proxy.
 	UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspaceProxyParams) (WorkspaceProxy, error)
+	// Replaces the organizations and groups whose members may use the proxy.
+	UpdateWorkspaceProxyACL(ctx context.Context, arg UpdateWorkspaceProxyACLParams) (WorkspaceProxy, error)
 	UpdateWorkspaceProxyDeleted(ctx context.Context, arg UpdateWorkspaceProxyDeletedParams) error
 	UpdateWorkspaceTTL(ctx context.Context, arg UpdateWorkspaceTTLParams) error
+	// UpdateWorkspaceWebhookLastTriggeredAt records the signed timestamp of an
+	// accepted trigger request. It returns no rows if the timestamp isn't later
+	// than the last accepted one, so a request can't be replayed.
+	UpdateWorkspaceWebhookLastTriggeredAt(ctx context.Context, arg UpdateWorkspaceWebhookLastTriggeredAtParams) (WorkspaceWebhook, error)
 	UpdateWorkspacesLockedDeletingAtByTemplateID(ctx context.Context, arg UpdateWorkspacesLockedDeletingAtByTemplateIDParams) error
 	UpsertAppSecurityKey(ctx context.Context, value string) error
 	// The default proxy is implied and not actually stored in the database.
@@ -305,12 +489,27 @@ type sqlcQuerier interface {
 	// The functional values are immutable and controlled implicitly.
 	UpsertDefaultProxy(ctx context.Context, arg UpsertDefaultProxyParams) error
 	UpsertLastUpdateCheck(ctx context.Context, value string) error
+	// Records a usage sample for a feature, keeping the peak and the first time
+	// the limit was exceeded on the given day.
+	UpsertLicenseUsage(ctx context.Context, arg UpsertLicenseUsageParams) error
 	UpsertLogoURL(ctx context.Context, value string) error
 	UpsertOAuthSigningKey(ctx context.Context, value string) error
+	UpsertRuntimeExperiments(ctx context.Context, value string) error
+	UpsertSSHCAKey(ctx context.Context, value string) error
 	UpsertServiceBanner(ctx context.Context, value string) error
 	UpsertTailnetAgent(ctx context.Context, arg UpsertTailnetAgentParams) (TailnetAgent, error)
 	UpsertTailnetClient(ctx context.Context, arg UpsertTailnetClientParams) (TailnetClient, error)
 	UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (TailnetCoordinator, error)
+	// UpsertTemplateVersionBuildInsights recomputes the build rollup of every
+	// template version that had a workspace build complete since the given time.
+	// Canceled builds are excluded as they say nothing about the health of the
+	// template version.
+	UpsertTemplateVersionBuildInsights(ctx context.Context, arg UpsertTemplateVersionBuildInsightsParams) error
+	UpsertUserPTYSessionOverride(ctx context.Context, arg UpsertUserPTYSessionOverrideParams) (UserPTYSessionOverride, error)
+	UpsertUserQuotaOverride(ctx context.Context, arg UpsertUserQuotaOverrideParams) (UserQuotaOverride, error)
+	UpsertUserRegionLatency(ctx context.Context, arg UpsertUserRegionLatencyParams) error
+	UpsertWorkspaceExternalMetadatum(ctx context.Context, arg UpsertWorkspaceExternalMetadatumParams) (WorkspaceExternalMetadatum, error)
+	UpsertWorkspacePreviousName(ctx context.Context, arg UpsertWorkspacePreviousNameParams) error
 }
 
 var _ sqlcQuerier = (*sqlQuerier)(nil)
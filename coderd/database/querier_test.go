@@ -390,6 +390,78 @@ func TestQueuePosition(t *testing.T) {
 	}
 }
 
+func TestAcquireProvisionerJobConcurrencyLimit(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.SkipNow()
+	}
+	sqlDB := testSQLDB(t)
+	err := migrations.Up(sqlDB)
+	require.NoError(t, err)
+	db := database.New(sqlDB)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	org := dbgen.Organization(t, db, database.Organization{})
+	org, err = db.UpdateOrganizationProvisionerLimits(ctx, database.UpdateOrganizationProvisionerLimitsParams{
+		ID:                           org.ID,
+		UpdatedAt:                    database.Now(),
+		MaxConcurrentProvisionerJobs: 1,
+	})
+	require.NoError(t, err)
+
+	jobIDs := []uuid.UUID{}
+	for i := 0; i < 2; i++ {
+		job := dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+			OrganizationID: org.ID,
+			Tags:           database.StringMap{},
+		})
+		jobIDs = append(jobIDs, job.ID)
+		time.Sleep(time.Millisecond)
+	}
+
+	acquire := func() (database.ProvisionerJob, error) {
+		return db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			StartedAt: sql.NullTime{
+				Time:  database.Now(),
+				Valid: true,
+			},
+			Types: database.AllProvisionerTypeValues(),
+			WorkerID: uuid.NullUUID{
+				UUID:  uuid.New(),
+				Valid: true,
+			},
+			Tags: json.RawMessage("{}"),
+		})
+	}
+	job, err := acquire()
+	require.NoError(t, err)
+	require.Equal(t, jobIDs[0], job.ID)
+
+	// The organization already runs as many jobs as it allows.
+	_, err = acquire()
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	queued, err := db.GetProvisionerJobsByIDsWithQueuePosition(ctx, []uuid.UUID{jobIDs[1]})
+	require.NoError(t, err)
+	require.Len(t, queued, 1)
+	require.Equal(t, "organization", queued[0].QueueLimitedBy)
+
+	err = db.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
+		ID:        job.ID,
+		UpdatedAt: database.Now(),
+		CompletedAt: sql.NullTime{
+			Time:  database.Now(),
+			Valid: true,
+		},
+	})
+	require.NoError(t, err)
+
+	job, err = acquire()
+	require.NoError(t, err)
+	require.Equal(t, jobIDs[1], job.ID)
+}
+
 func TestUserLastSeenFilter(t *testing.T) {
 	t.Parallel()
 	if testing.Short() {
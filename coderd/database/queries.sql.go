@@ -349,6 +349,176 @@ func (q *sqlQuerier) UpdateAPIKeyByID(ctx context.Context, arg UpdateAPIKeyByIDP
 	return err
 }
 
+const getAuditLogArchivalByID = `-- name: GetAuditLogArchivalByID :one
+SELECT
+	id, initiator_id, status, cutoff, destination, archived_count, error, started_at, finished_at
+FROM
+	audit_log_archivals
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetAuditLogArchivalByID(ctx context.Context, id uuid.UUID) (AuditLogArchival, error) {
+	row := q.db.QueryRowContext(ctx, getAuditLogArchivalByID, id)
+	var i AuditLogArchival
+	err := row.Scan(
+		&i.ID,
+		&i.InitiatorID,
+		&i.Status,
+		&i.Cutoff,
+		&i.Destination,
+		&i.ArchivedCount,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const getAuditLogArchivals = `-- name: GetAuditLogArchivals :many
+SELECT
+	id, initiator_id, status, cutoff, destination, archived_count, error, started_at, finished_at
+FROM
+	audit_log_archivals
+ORDER BY
+	started_at DESC
+LIMIT
+	$1 :: int
+`
+
+func (q *sqlQuerier) GetAuditLogArchivals(ctx context.Context, rowLimit int32) ([]AuditLogArchival, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogArchivals, rowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLogArchival
+	for rows.Next() {
+		var i AuditLogArchival
+		if err := rows.Scan(
+			&i.ID,
+			&i.InitiatorID,
+			&i.Status,
+			&i.Cutoff,
+			&i.Destination,
+			&i.ArchivedCount,
+			&i.Error,
+			&i.StartedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertAuditLogArchival = `-- name: InsertAuditLogArchival :one
+INSERT INTO
+	audit_log_archivals (
+		id,
+		initiator_id,
+		cutoff,
+		destination,
+		started_at
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING id, initiator_id, status, cutoff, destination, archived_count, error, started_at, finished_at
+`
+
+type InsertAuditLogArchivalParams struct {
+	ID          uuid.UUID     `db:"id" json:"id"`
+	InitiatorID uuid.NullUUID `db:"initiator_id" json:"initiator_id"`
+	Cutoff      time.Time     `db:"cutoff" json:"cutoff"`
+	Destination string        `db:"destination" json:"destination"`
+	StartedAt   time.Time     `db:"started_at" json:"started_at"`
+}
+
+func (q *sqlQuerier) InsertAuditLogArchival(ctx context.Context, arg InsertAuditLogArchivalParams) (AuditLogArchival, error) {
+	row := q.db.QueryRowContext(ctx, insertAuditLogArchival,
+		arg.ID,
+		arg.InitiatorID,
+		arg.Cutoff,
+		arg.Destination,
+		arg.StartedAt,
+	)
+	var i AuditLogArchival
+	err := row.Scan(
+		&i.ID,
+		&i.InitiatorID,
+		&i.Status,
+		&i.Cutoff,
+		&i.Destination,
+		&i.ArchivedCount,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const updateAuditLogArchivalByID = `-- name: UpdateAuditLogArchivalByID :one
+UPDATE
+	audit_log_archivals
+SET
+	status = $2,
+	archived_count = $3,
+	error = $4,
+	finished_at = $5
+WHERE
+	id = $1
+RETURNING id, initiator_id, status, cutoff, destination, archived_count, error, started_at, finished_at
+`
+
+type UpdateAuditLogArchivalByIDParams struct {
+	ID            uuid.UUID              `db:"id" json:"id"`
+	Status        AuditLogArchivalStatus `db:"status" json:"status"`
+	ArchivedCount int64                  `db:"archived_count" json:"archived_count"`
+	Error         string                 `db:"error" json:"error"`
+	FinishedAt    sql.NullTime           `db:"finished_at" json:"finished_at"`
+}
+
+func (q *sqlQuerier) UpdateAuditLogArchivalByID(ctx context.Context, arg UpdateAuditLogArchivalByIDParams) (AuditLogArchival, error) {
+	row := q.db.QueryRowContext(ctx, updateAuditLogArchivalByID,
+		arg.ID,
+		arg.Status,
+		arg.ArchivedCount,
+		arg.Error,
+		arg.FinishedAt,
+	)
+	var i AuditLogArchival
+	err := row.Scan(
+		&i.ID,
+		&i.InitiatorID,
+		&i.Status,
+		&i.Cutoff,
+		&i.Destination,
+		&i.ArchivedCount,
+		&i.Error,
+		&i.StartedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const deleteAuditLogsByIDs = `-- name: DeleteAuditLogsByIDs :exec
+DELETE FROM
+	audit_logs
+WHERE
+	id = ANY($1 :: uuid[])
+`
+
+func (q *sqlQuerier) DeleteAuditLogsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteAuditLogsByIDs, pq.Array(ids))
+	return err
+}
+
 const getAuditLogsOffset = `-- name: GetAuditLogsOffset :many
 SELECT
     audit_logs.id, audit_logs.time, audit_logs.user_id, audit_logs.organization_id, audit_logs.ip, audit_logs.user_agent, audit_logs.resource_type, audit_logs.resource_id, audit_logs.resource_target, audit_logs.action, audit_logs.diff, audit_logs.status_code, audit_logs.additional_fields, audit_logs.request_id, audit_logs.resource_icon,
@@ -556,6 +726,65 @@ func (q *sqlQuerier) GetAuditLogsOffset(ctx context.Context, arg GetAuditLogsOff
 	return items, nil
 }
 
+const getAuditLogsOlderThan = `-- name: GetAuditLogsOlderThan :many
+SELECT
+	id, time, user_id, organization_id, ip, user_agent, resource_type, resource_id, resource_target, action, diff, status_code, additional_fields, request_id, resource_icon
+FROM
+	audit_logs
+WHERE
+	"time" < $1 :: timestamptz
+ORDER BY
+	"time" ASC, id ASC
+LIMIT
+	$2 :: int
+`
+
+type GetAuditLogsOlderThanParams struct {
+	Before   time.Time `db:"before" json:"before"`
+	RowLimit int32     `db:"row_limit" json:"row_limit"`
+}
+
+// GetAuditLogsOlderThan returns the oldest audit logs from before the given
+// time, so they can be archived in batches.
+func (q *sqlQuerier) GetAuditLogsOlderThan(ctx context.Context, arg GetAuditLogsOlderThanParams) ([]AuditLog, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditLogsOlderThan, arg.Before, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AuditLog
+	for rows.Next() {
+		var i AuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Time,
+			&i.UserID,
+			&i.OrganizationID,
+			&i.Ip,
+			&i.UserAgent,
+			&i.ResourceType,
+			&i.ResourceID,
+			&i.ResourceTarget,
+			&i.Action,
+			&i.Diff,
+			&i.StatusCode,
+			&i.AdditionalFields,
+			&i.RequestID,
+			&i.ResourceIcon,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const insertAuditLog = `-- name: InsertAuditLog :one
 INSERT INTO
 	audit_logs (
@@ -636,6 +865,104 @@ func (q *sqlQuerier) InsertAuditLog(ctx context.Context, arg InsertAuditLogParam
 	return i, err
 }
 
+const getEntitlementSnapshots = `-- name: GetEntitlementSnapshots :many
+SELECT date, has_license, trial, features, user_count, active_user_count, replica_count, warnings, errors, created_at
+FROM entitlement_snapshots
+WHERE date >= $1 :: date AND date <= $2 :: date
+ORDER BY date
+`
+
+type GetEntitlementSnapshotsParams struct {
+	StartDate time.Time `db:"start_date" json:"start_date"`
+	EndDate   time.Time `db:"end_date" json:"end_date"`
+}
+
+func (q *sqlQuerier) GetEntitlementSnapshots(ctx context.Context, arg GetEntitlementSnapshotsParams) ([]EntitlementSnapshot, error) {
+	rows, err := q.db.QueryContext(ctx, getEntitlementSnapshots, arg.StartDate, arg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EntitlementSnapshot
+	for rows.Next() {
+		var i EntitlementSnapshot
+		if err := rows.Scan(
+			&i.Date,
+			&i.HasLicense,
+			&i.Trial,
+			&i.Features,
+			&i.UserCount,
+			&i.ActiveUserCount,
+			&i.ReplicaCount,
+			pq.Array(&i.Warnings),
+			pq.Array(&i.Errors),
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertEntitlementSnapshot = `-- name: InsertEntitlementSnapshot :exec
+INSERT INTO
+	entitlement_snapshots (
+	date,
+	has_license,
+	trial,
+	features,
+	user_count,
+	active_user_count,
+	replica_count,
+	warnings,
+	errors,
+	created_at
+)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT
+	(date)
+DO NOTHING
+`
+
+type InsertEntitlementSnapshotParams struct {
+	Date            time.Time       `db:"date" json:"date"`
+	HasLicense      bool            `db:"has_license" json:"has_license"`
+	Trial           bool            `db:"trial" json:"trial"`
+	Features        json.RawMessage `db:"features" json:"features"`
+	UserCount       int64           `db:"user_count" json:"user_count"`
+	ActiveUserCount int64           `db:"active_user_count" json:"active_user_count"`
+	ReplicaCount    int32           `db:"replica_count" json:"replica_count"`
+	Warnings        []string        `db:"warnings" json:"warnings"`
+	Errors          []string        `db:"errors" json:"errors"`
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
+}
+
+// Only the first snapshot of a day is kept, so replicas can all try to take
+// it.
+func (q *sqlQuerier) InsertEntitlementSnapshot(ctx context.Context, arg InsertEntitlementSnapshotParams) error {
+	_, err := q.db.ExecContext(ctx, insertEntitlementSnapshot,
+		arg.Date,
+		arg.HasLicense,
+		arg.Trial,
+		arg.Features,
+		arg.UserCount,
+		arg.ActiveUserCount,
+		arg.ReplicaCount,
+		pq.Array(arg.Warnings),
+		pq.Array(arg.Errors),
+		arg.CreatedAt,
+	)
+	return err
+}
+
 const getFileByHashAndCreator = `-- name: GetFileByHashAndCreator :one
 SELECT
 	hash, created_at, created_by, mimetype, data, id
@@ -1028,93 +1355,40 @@ func (q *sqlQuerier) UpdateGitSSHKey(ctx context.Context, arg UpdateGitSSHKeyPar
 	return i, err
 }
 
-const deleteGroupMemberFromGroup = `-- name: DeleteGroupMemberFromGroup :exec
-DELETE FROM
-	group_members
-WHERE
-	user_id = $1 AND
-	group_id = $2
-`
-
-type DeleteGroupMemberFromGroupParams struct {
-	UserID  uuid.UUID `db:"user_id" json:"user_id"`
-	GroupID uuid.UUID `db:"group_id" json:"group_id"`
-}
-
-func (q *sqlQuerier) DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteGroupMemberFromGroupParams) error {
-	_, err := q.db.ExecContext(ctx, deleteGroupMemberFromGroup, arg.UserID, arg.GroupID)
-	return err
-}
-
-const deleteGroupMembersByOrgAndUser = `-- name: DeleteGroupMembersByOrgAndUser :exec
-DELETE FROM
-	group_members
-WHERE
-	group_members.user_id = $1
-	AND group_id = ANY(SELECT id FROM groups WHERE organization_id = $2)
+const deleteGroupDefaultTemplates = `-- name: DeleteGroupDefaultTemplates :exec
+DELETE FROM group_default_templates WHERE group_id = $1
 `
 
-type DeleteGroupMembersByOrgAndUserParams struct {
-	UserID         uuid.UUID `db:"user_id" json:"user_id"`
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-}
-
-func (q *sqlQuerier) DeleteGroupMembersByOrgAndUser(ctx context.Context, arg DeleteGroupMembersByOrgAndUserParams) error {
-	_, err := q.db.ExecContext(ctx, deleteGroupMembersByOrgAndUser, arg.UserID, arg.OrganizationID)
+func (q *sqlQuerier) DeleteGroupDefaultTemplates(ctx context.Context, groupID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupDefaultTemplates, groupID)
 	return err
 }
 
-const getGroupMembers = `-- name: GetGroupMembers :many
+const getGroupDefaultTemplates = `-- name: GetGroupDefaultTemplates :many
 SELECT
-	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at, users.quiet_hours_schedule
+	group_id, template_id, create_workspace, created_at
 FROM
-	users
-LEFT JOIN
-	group_members
-ON
-	group_members.user_id = users.id AND
-	group_members.group_id = $1
-LEFT JOIN
-	organization_members
-ON
-	organization_members.user_id = users.id AND
-	organization_members.organization_id = $1
+	group_default_templates
 WHERE
-	-- In either case, the group_id will only match an org or a group.
-    (group_members.group_id = $1
-         OR
-     organization_members.organization_id = $1)
-AND
-	users.status = 'active'
-AND
-	users.deleted = 'false'
+	group_id = $1
+ORDER BY
+	created_at, template_id
 `
 
-// If the group is a user made group, then we need to check the group_members table.
-// If it is the "Everyone" group, then we need to check the organization_members table.
-func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, getGroupMembers, groupID)
+func (q *sqlQuerier) GetGroupDefaultTemplates(ctx context.Context, groupID uuid.UUID) ([]GroupDefaultTemplate, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupDefaultTemplates, groupID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []GroupDefaultTemplate
 	for rows.Next() {
-		var i User
+		var i GroupDefaultTemplate
 		if err := rows.Scan(
-			&i.ID,
-			&i.Email,
-			&i.Username,
-			&i.HashedPassword,
+			&i.GroupID,
+			&i.TemplateID,
+			&i.CreateWorkspace,
 			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Status,
-			&i.RBACRoles,
-			&i.LoginType,
-			&i.AvatarURL,
-			&i.Deleted,
-			&i.LastSeenAt,
-			&i.QuietHoursSchedule,
 		); err != nil {
 			return nil, err
 		}
@@ -1129,10 +1403,305 @@ func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]
 	return items, nil
 }
 
-const insertGroupMember = `-- name: InsertGroupMember :exec
-INSERT INTO
-    group_members (user_id, group_id)
-VALUES
+const getPendingGroupDefaultTemplateWorkspaces = `-- name: GetPendingGroupDefaultTemplateWorkspaces :many
+SELECT DISTINCT ON (group_default_templates.template_id, members.user_id)
+	group_default_templates.group_id,
+	group_default_templates.template_id,
+	members.user_id
+FROM
+	group_default_templates
+JOIN (
+	SELECT group_members.group_id, group_members.user_id FROM group_members
+	UNION
+	SELECT organization_members.organization_id AS group_id, organization_members.user_id FROM organization_members
+) AS members
+ON
+	members.group_id = group_default_templates.group_id
+JOIN
+	users
+ON
+	users.id = members.user_id
+JOIN
+	templates
+ON
+	templates.id = group_default_templates.template_id
+WHERE
+	group_default_templates.create_workspace
+	AND users.status = 'active'
+	AND NOT users.deleted
+	AND NOT templates.deleted
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			group_default_template_workspaces
+		WHERE
+			group_default_template_workspaces.template_id = group_default_templates.template_id
+			AND group_default_template_workspaces.user_id = members.user_id
+	)
+ORDER BY
+	group_default_templates.template_id, members.user_id, group_default_templates.created_at
+`
+
+type GetPendingGroupDefaultTemplateWorkspacesRow struct {
+	GroupID    uuid.UUID `db:"group_id" json:"group_id"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+}
+
+// Lists the active members of groups that didn't get the starter workspace of
+// a default template of the group yet. The members of the Everyone group are
+// the members of the organization.
+func (q *sqlQuerier) GetPendingGroupDefaultTemplateWorkspaces(ctx context.Context) ([]GetPendingGroupDefaultTemplateWorkspacesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPendingGroupDefaultTemplateWorkspaces)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPendingGroupDefaultTemplateWorkspacesRow
+	for rows.Next() {
+		var i GetPendingGroupDefaultTemplateWorkspacesRow
+		if err := rows.Scan(&i.GroupID, &i.TemplateID, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertGroupDefaultTemplate = `-- name: InsertGroupDefaultTemplate :one
+INSERT INTO
+	group_default_templates (
+		group_id,
+		template_id,
+		create_workspace,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4) RETURNING group_id, template_id, create_workspace, created_at
+`
+
+type InsertGroupDefaultTemplateParams struct {
+	GroupID         uuid.UUID `db:"group_id" json:"group_id"`
+	TemplateID      uuid.UUID `db:"template_id" json:"template_id"`
+	CreateWorkspace bool      `db:"create_workspace" json:"create_workspace"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertGroupDefaultTemplate(ctx context.Context, arg InsertGroupDefaultTemplateParams) (GroupDefaultTemplate, error) {
+	row := q.db.QueryRowContext(ctx, insertGroupDefaultTemplate,
+		arg.GroupID,
+		arg.TemplateID,
+		arg.CreateWorkspace,
+		arg.CreatedAt,
+	)
+	var i GroupDefaultTemplate
+	err := row.Scan(
+		&i.GroupID,
+		&i.TemplateID,
+		&i.CreateWorkspace,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertGroupDefaultTemplateWorkspace = `-- name: InsertGroupDefaultTemplateWorkspace :exec
+INSERT INTO
+	group_default_template_workspaces (
+		template_id,
+		user_id,
+		group_id,
+		workspace_id,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5)
+`
+
+type InsertGroupDefaultTemplateWorkspaceParams struct {
+	TemplateID  uuid.UUID     `db:"template_id" json:"template_id"`
+	UserID      uuid.UUID     `db:"user_id" json:"user_id"`
+	GroupID     uuid.UUID     `db:"group_id" json:"group_id"`
+	WorkspaceID uuid.NullUUID `db:"workspace_id" json:"workspace_id"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertGroupDefaultTemplateWorkspace(ctx context.Context, arg InsertGroupDefaultTemplateWorkspaceParams) error {
+	_, err := q.db.ExecContext(ctx, insertGroupDefaultTemplateWorkspace,
+		arg.TemplateID,
+		arg.UserID,
+		arg.GroupID,
+		arg.WorkspaceID,
+		arg.CreatedAt,
+	)
+	return err
+}
+
+const deleteGroupMemberFromGroup = `-- name: DeleteGroupMemberFromGroup :exec
+DELETE FROM
+	group_members
+WHERE
+	user_id = $1 AND
+	group_id = $2
+`
+
+type DeleteGroupMemberFromGroupParams struct {
+	UserID  uuid.UUID `db:"user_id" json:"user_id"`
+	GroupID uuid.UUID `db:"group_id" json:"group_id"`
+}
+
+func (q *sqlQuerier) DeleteGroupMemberFromGroup(ctx context.Context, arg DeleteGroupMemberFromGroupParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupMemberFromGroup, arg.UserID, arg.GroupID)
+	return err
+}
+
+const deleteGroupMembersByOrgAndUser = `-- name: DeleteGroupMembersByOrgAndUser :exec
+DELETE FROM
+	group_members
+WHERE
+	group_members.user_id = $1
+	AND group_id = ANY(SELECT id FROM groups WHERE organization_id = $2)
+`
+
+type DeleteGroupMembersByOrgAndUserParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) DeleteGroupMembersByOrgAndUser(ctx context.Context, arg DeleteGroupMembersByOrgAndUserParams) error {
+	_, err := q.db.ExecContext(ctx, deleteGroupMembersByOrgAndUser, arg.UserID, arg.OrganizationID)
+	return err
+}
+
+const getGroupMembers = `-- name: GetGroupMembers :many
+SELECT
+	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at, users.quiet_hours_schedule
+FROM
+	users
+LEFT JOIN
+	group_members
+ON
+	group_members.user_id = users.id AND
+	group_members.group_id = $1
+LEFT JOIN
+	organization_members
+ON
+	organization_members.user_id = users.id AND
+	organization_members.organization_id = $1
+WHERE
+	-- In either case, the group_id will only match an org or a group.
+    (group_members.group_id = $1
+         OR
+     organization_members.organization_id = $1)
+AND
+	users.status = 'active'
+AND
+	users.deleted = 'false'
+`
+
+// If the group is a user made group, then we need to check the group_members table.
+// If it is the "Everyone" group, then we need to check the organization_members table.
+func (q *sqlQuerier) GetGroupMembers(ctx context.Context, groupID uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupMembers, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGroupMembersAnyStatus = `-- name: GetGroupMembersAnyStatus :many
+SELECT
+	users.id, users.email, users.username, users.hashed_password, users.created_at, users.updated_at, users.status, users.rbac_roles, users.login_type, users.avatar_url, users.deleted, users.last_seen_at, users.quiet_hours_schedule
+FROM
+	users
+JOIN
+	group_members
+ON
+	group_members.user_id = users.id
+WHERE
+	group_members.group_id = $1
+AND
+	users.deleted = 'false'
+`
+
+// GetGroupMembersAnyStatus returns the members of a user made group regardless
+// of their status, so dormant and suspended members are included.
+func (q *sqlQuerier) GetGroupMembersAnyStatus(ctx context.Context, groupID uuid.UUID) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getGroupMembersAnyStatus, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Username,
+			&i.HashedPassword,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.RBACRoles,
+			&i.LoginType,
+			&i.AvatarURL,
+			&i.Deleted,
+			&i.LastSeenAt,
+			&i.QuietHoursSchedule,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertGroupMember = `-- name: InsertGroupMember :exec
+INSERT INTO
+    group_members (user_id, group_id)
+VALUES
     ($1, $2)
 `
 
@@ -1191,7 +1760,7 @@ func (q *sqlQuerier) DeleteGroupByID(ctx context.Context, id uuid.UUID) error {
 
 const getGroupByID = `-- name: GetGroupByID :one
 SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source, quota_override, avatar_file_id, metadata
 FROM
 	groups
 WHERE
@@ -1211,13 +1780,16 @@ func (q *sqlQuerier) GetGroupByID(ctx context.Context, id uuid.UUID) (Group, err
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.QuotaOverride,
+		&i.AvatarFileID,
+		&i.Metadata,
 	)
 	return i, err
 }
 
 const getGroupByOrgAndName = `-- name: GetGroupByOrgAndName :one
 SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source, quota_override, avatar_file_id, metadata
 FROM
 	groups
 WHERE
@@ -1244,13 +1816,16 @@ func (q *sqlQuerier) GetGroupByOrgAndName(ctx context.Context, arg GetGroupByOrg
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.QuotaOverride,
+		&i.AvatarFileID,
+		&i.Metadata,
 	)
 	return i, err
 }
 
 const getGroupsByOrganizationID = `-- name: GetGroupsByOrganizationID :many
 SELECT
-	id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	id, name, organization_id, avatar_url, quota_allowance, display_name, source, quota_override, avatar_file_id, metadata
 FROM
 	groups
 WHERE
@@ -1274,6 +1849,9 @@ func (q *sqlQuerier) GetGroupsByOrganizationID(ctx context.Context, organization
 			&i.QuotaAllowance,
 			&i.DisplayName,
 			&i.Source,
+			&i.QuotaOverride,
+			&i.AvatarFileID,
+			&i.Metadata,
 		); err != nil {
 			return nil, err
 		}
@@ -1295,7 +1873,7 @@ INSERT INTO groups (
 	organization_id
 )
 VALUES
-	($1, 'Everyone', $1) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	($1, 'Everyone', $1) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, quota_override, avatar_file_id, metadata
 `
 
 // We use the organization_id as the id
@@ -1312,6 +1890,9 @@ func (q *sqlQuerier) InsertAllUsersGroup(ctx context.Context, organizationID uui
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.QuotaOverride,
+		&i.AvatarFileID,
+		&i.Metadata,
 	)
 	return i, err
 }
@@ -1323,19 +1904,21 @@ INSERT INTO groups (
 	display_name,
 	organization_id,
 	avatar_url,
-	quota_allowance
+	quota_allowance,
+	metadata
 )
 VALUES
-	($1, $2, $3, $4, $5, $6) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, quota_override, avatar_file_id, metadata
 `
 
 type InsertGroupParams struct {
-	ID             uuid.UUID `db:"id" json:"id"`
-	Name           string    `db:"name" json:"name"`
-	DisplayName    string    `db:"display_name" json:"display_name"`
-	OrganizationID uuid.UUID `db:"organization_id" json:"organization_id"`
-	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
-	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+	ID             uuid.UUID       `db:"id" json:"id"`
+	Name           string          `db:"name" json:"name"`
+	DisplayName    string          `db:"display_name" json:"display_name"`
+	OrganizationID uuid.UUID       `db:"organization_id" json:"organization_id"`
+	AvatarURL      string          `db:"avatar_url" json:"avatar_url"`
+	QuotaAllowance int32           `db:"quota_allowance" json:"quota_allowance"`
+	Metadata       json.RawMessage `db:"metadata" json:"metadata"`
 }
 
 func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Group, error) {
@@ -1346,6 +1929,7 @@ func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Gr
 		arg.OrganizationID,
 		arg.AvatarURL,
 		arg.QuotaAllowance,
+		arg.Metadata,
 	)
 	var i Group
 	err := row.Scan(
@@ -1356,6 +1940,9 @@ func (q *sqlQuerier) InsertGroup(ctx context.Context, arg InsertGroupParams) (Gr
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.QuotaOverride,
+		&i.AvatarFileID,
+		&i.Metadata,
 	)
 	return i, err
 }
@@ -1375,7 +1962,7 @@ SELECT
 FROM
     UNNEST($3 :: text[]) AS group_name
 ON CONFLICT DO NOTHING
-RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, quota_override, avatar_file_id, metadata
 `
 
 type InsertMissingGroupsParams struct {
@@ -1405,6 +1992,9 @@ func (q *sqlQuerier) InsertMissingGroups(ctx context.Context, arg InsertMissingG
 			&i.QuotaAllowance,
 			&i.DisplayName,
 			&i.Source,
+			&i.QuotaOverride,
+			&i.AvatarFileID,
+			&i.Metadata,
 		); err != nil {
 			return nil, err
 		}
@@ -1419,6 +2009,41 @@ func (q *sqlQuerier) InsertMissingGroups(ctx context.Context, arg InsertMissingG
 	return items, nil
 }
 
+const updateGroupAvatarByID = `-- name: UpdateGroupAvatarByID :one
+UPDATE
+	groups
+SET
+	avatar_file_id = $1,
+	avatar_url = $2
+WHERE
+	id = $3
+RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, quota_override, avatar_file_id, metadata
+`
+
+type UpdateGroupAvatarByIDParams struct {
+	AvatarFileID uuid.NullUUID `db:"avatar_file_id" json:"avatar_file_id"`
+	AvatarURL    string        `db:"avatar_url" json:"avatar_url"`
+	ID           uuid.UUID     `db:"id" json:"id"`
+}
+
+func (q *sqlQuerier) UpdateGroupAvatarByID(ctx context.Context, arg UpdateGroupAvatarByIDParams) (Group, error) {
+	row := q.db.QueryRowContext(ctx, updateGroupAvatarByID, arg.AvatarFileID, arg.AvatarURL, arg.ID)
+	var i Group
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.OrganizationID,
+		&i.AvatarURL,
+		&i.QuotaAllowance,
+		&i.DisplayName,
+		&i.Source,
+		&i.QuotaOverride,
+		&i.AvatarFileID,
+		&i.Metadata,
+	)
+	return i, err
+}
+
 const updateGroupByID = `-- name: UpdateGroupByID :one
 UPDATE
 	groups
@@ -1426,18 +2051,24 @@ SET
 	name = $1,
 	display_name = $2,
 	avatar_url = $3,
-	quota_allowance = $4
+	-- An uploaded avatar is only served while the avatar URL points at it.
+	avatar_file_id = CASE WHEN avatar_url = $3 THEN avatar_file_id ELSE NULL END,
+	quota_allowance = $4,
+	quota_override = $5,
+	metadata = $6
 WHERE
-	id = $5
-RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source
+	id = $7
+RETURNING id, name, organization_id, avatar_url, quota_allowance, display_name, source, quota_override, avatar_file_id, metadata
 `
 
 type UpdateGroupByIDParams struct {
-	Name           string    `db:"name" json:"name"`
-	DisplayName    string    `db:"display_name" json:"display_name"`
-	AvatarURL      string    `db:"avatar_url" json:"avatar_url"`
-	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
-	ID             uuid.UUID `db:"id" json:"id"`
+	Name           string          `db:"name" json:"name"`
+	DisplayName    string          `db:"display_name" json:"display_name"`
+	AvatarURL      string          `db:"avatar_url" json:"avatar_url"`
+	QuotaAllowance int32           `db:"quota_allowance" json:"quota_allowance"`
+	QuotaOverride  sql.NullInt32   `db:"quota_override" json:"quota_override"`
+	Metadata       json.RawMessage `db:"metadata" json:"metadata"`
+	ID             uuid.UUID       `db:"id" json:"id"`
 }
 
 func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDParams) (Group, error) {
@@ -1446,6 +2077,8 @@ func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDPar
 		arg.DisplayName,
 		arg.AvatarURL,
 		arg.QuotaAllowance,
+		arg.QuotaOverride,
+		arg.Metadata,
 		arg.ID,
 	)
 	var i Group
@@ -1457,10 +2090,100 @@ func (q *sqlQuerier) UpdateGroupByID(ctx context.Context, arg UpdateGroupByIDPar
 		&i.QuotaAllowance,
 		&i.DisplayName,
 		&i.Source,
+		&i.QuotaOverride,
+		&i.AvatarFileID,
+		&i.Metadata,
 	)
 	return i, err
 }
 
+const getTemplateAgentFirstConnectInsights = `-- name: GetTemplateAgentFirstConnectInsights :many
+SELECT
+	w.template_id,
+	COUNT(*)::bigint AS total_agents,
+	COUNT(wa.first_connected_at)::bigint AS connected_agents,
+	COUNT(*) FILTER (
+		WHERE wa.first_connected_at IS NULL
+		AND pj.completed_at + make_interval(secs => COALESCE(NULLIF(wa.connection_timeout_seconds, 0), $1::int)) < NOW()
+	)::bigint AS never_connected_agents,
+	coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (wa.first_connected_at - pj.completed_at)), 0))), -1)::FLOAT AS first_connect_seconds_50,
+	coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (wa.first_connected_at - pj.completed_at)), 0))), -1)::FLOAT AS first_connect_seconds_95,
+	coalesce((PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (wa.first_connected_at - pj.completed_at)), 0))), -1)::FLOAT AS first_connect_seconds_99
+FROM workspace_agents wa
+JOIN workspace_resources wr ON (wr.id = wa.resource_id)
+JOIN workspace_builds wb ON (wb.job_id = wr.job_id)
+JOIN provisioner_jobs pj ON (pj.id = wb.job_id)
+JOIN workspaces w ON (w.id = wb.workspace_id)
+WHERE
+	wb.transition = 'start'
+	AND pj.completed_at >= $2::timestamptz
+	AND pj.completed_at < $3::timestamptz
+	AND pj.canceled_at IS NULL
+	AND (pj.error IS NULL OR pj.error = '')
+	AND CASE WHEN COALESCE(array_length($4::uuid[], 1), 0) > 0 THEN w.template_id = ANY($4::uuid[]) ELSE TRUE END
+GROUP BY w.template_id
+ORDER BY w.template_id ASC
+`
+
+type GetTemplateAgentFirstConnectInsightsParams struct {
+	DefaultConnectionTimeoutSeconds int32       `db:"default_connection_timeout_seconds" json:"default_connection_timeout_seconds"`
+	StartTime                       time.Time   `db:"start_time" json:"start_time"`
+	EndTime                         time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs                     []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetTemplateAgentFirstConnectInsightsRow struct {
+	TemplateID            uuid.UUID `db:"template_id" json:"template_id"`
+	TotalAgents           int64     `db:"total_agents" json:"total_agents"`
+	ConnectedAgents       int64     `db:"connected_agents" json:"connected_agents"`
+	NeverConnectedAgents  int64     `db:"never_connected_agents" json:"never_connected_agents"`
+	FirstConnectSeconds50 float64   `db:"first_connect_seconds_50" json:"first_connect_seconds_50"`
+	FirstConnectSeconds95 float64   `db:"first_connect_seconds_95" json:"first_connect_seconds_95"`
+	FirstConnectSeconds99 float64   `db:"first_connect_seconds_99" json:"first_connect_seconds_99"`
+}
+
+// GetTemplateAgentFirstConnectInsights returns, per template, how long the
+// agents of successful start builds that completed within the given timeframe
+// took to connect for the first time after the build completed. Agents that
+// haven't connected by the end of their connection timeout are counted as
+// never connected, agents without a connection timeout use the default one.
+// The result can be filtered on template_ids.
+func (q *sqlQuerier) GetTemplateAgentFirstConnectInsights(ctx context.Context, arg GetTemplateAgentFirstConnectInsightsParams) ([]GetTemplateAgentFirstConnectInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateAgentFirstConnectInsights,
+		arg.DefaultConnectionTimeoutSeconds,
+		arg.StartTime,
+		arg.EndTime,
+		pq.Array(arg.TemplateIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateAgentFirstConnectInsightsRow
+	for rows.Next() {
+		var i GetTemplateAgentFirstConnectInsightsRow
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.TotalAgents,
+			&i.ConnectedAgents,
+			&i.NeverConnectedAgents,
+			&i.FirstConnectSeconds50,
+			&i.FirstConnectSeconds95,
+			&i.FirstConnectSeconds99,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTemplateAppInsights = `-- name: GetTemplateAppInsights :many
 WITH ts AS (
 	SELECT
@@ -1574,6 +2297,86 @@ func (q *sqlQuerier) GetTemplateAppInsights(ctx context.Context, arg GetTemplate
 	return items, nil
 }
 
+const getTemplateBuildSLOInsights = `-- name: GetTemplateBuildSLOInsights :many
+SELECT
+	w.template_id,
+	COUNT(*)::bigint AS total_builds,
+	COUNT(*) FILTER (WHERE pj.error IS NULL OR pj.error = '')::bigint AS successful_builds,
+	COUNT(*) FILTER (
+		WHERE (pj.error IS NULL OR pj.error = '')
+		AND EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at)) <= $1::float
+	)::bigint AS builds_within_duration_objective,
+	coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at)))), -1)::FLOAT AS build_duration_seconds_50,
+	coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at)))), -1)::FLOAT AS build_duration_seconds_95
+FROM workspace_builds wb
+JOIN provisioner_jobs pj ON (pj.id = wb.job_id)
+JOIN workspaces w ON (w.id = wb.workspace_id)
+WHERE
+	pj.completed_at >= $2::timestamptz
+	AND pj.completed_at < $3::timestamptz
+	AND pj.started_at IS NOT NULL
+	AND pj.canceled_at IS NULL
+	AND CASE WHEN COALESCE(array_length($4::uuid[], 1), 0) > 0 THEN w.template_id = ANY($4::uuid[]) ELSE TRUE END
+GROUP BY w.template_id
+ORDER BY w.template_id ASC
+`
+
+type GetTemplateBuildSLOInsightsParams struct {
+	DurationObjectiveSeconds float64     `db:"duration_objective_seconds" json:"duration_objective_seconds"`
+	StartTime                time.Time   `db:"start_time" json:"start_time"`
+	EndTime                  time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs              []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetTemplateBuildSLOInsightsRow struct {
+	TemplateID                    uuid.UUID `db:"template_id" json:"template_id"`
+	TotalBuilds                   int64     `db:"total_builds" json:"total_builds"`
+	SuccessfulBuilds              int64     `db:"successful_builds" json:"successful_builds"`
+	BuildsWithinDurationObjective int64     `db:"builds_within_duration_objective" json:"builds_within_duration_objective"`
+	BuildDurationSeconds50        float64   `db:"build_duration_seconds_50" json:"build_duration_seconds_50"`
+	BuildDurationSeconds95        float64   `db:"build_duration_seconds_95" json:"build_duration_seconds_95"`
+}
+
+// GetTemplateBuildSLOInsights returns the number of workspace builds per
+// template that completed within the given timeframe, along with how many of
+// them succeeded and how many succeeded within the duration objective.
+// Canceled builds are excluded as they say nothing about the health of the
+// template. The result can be filtered on template_ids.
+func (q *sqlQuerier) GetTemplateBuildSLOInsights(ctx context.Context, arg GetTemplateBuildSLOInsightsParams) ([]GetTemplateBuildSLOInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateBuildSLOInsights,
+		arg.DurationObjectiveSeconds,
+		arg.StartTime,
+		arg.EndTime,
+		pq.Array(arg.TemplateIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateBuildSLOInsightsRow
+	for rows.Next() {
+		var i GetTemplateBuildSLOInsightsRow
+		if err := rows.Scan(
+			&i.TemplateID,
+			&i.TotalBuilds,
+			&i.SuccessfulBuilds,
+			&i.BuildsWithinDurationObjective,
+			&i.BuildDurationSeconds50,
+			&i.BuildDurationSeconds95,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTemplateDailyInsights = `-- name: GetTemplateDailyInsights :many
 WITH ts AS (
 	SELECT
@@ -1867,6 +2670,82 @@ func (q *sqlQuerier) GetTemplateParameterInsights(ctx context.Context, arg GetTe
 	return items, nil
 }
 
+const getUserBandwidthInsights = `-- name: GetUserBandwidthInsights :many
+SELECT
+	workspace_agent_bandwidth_stats.user_id,
+	users.username,
+	users.avatar_url,
+	workspace_agent_bandwidth_stats.connection_type,
+	workspace_agent_bandwidth_stats.port,
+	array_agg(DISTINCT template_id)::uuid[] AS template_ids,
+	COUNT(DISTINCT peer) AS peers,
+	coalesce(SUM(rx_bytes), 0)::bigint AS rx_bytes,
+	coalesce(SUM(tx_bytes), 0)::bigint AS tx_bytes
+FROM workspace_agent_bandwidth_stats
+JOIN users ON (users.id = workspace_agent_bandwidth_stats.user_id)
+WHERE
+	workspace_agent_bandwidth_stats.created_at >= $1
+	AND workspace_agent_bandwidth_stats.created_at < $2
+	AND CASE WHEN COALESCE(array_length($3::uuid[], 1), 0) > 0 THEN template_id = ANY($3::uuid[]) ELSE TRUE END
+GROUP BY workspace_agent_bandwidth_stats.user_id, users.username, users.avatar_url, workspace_agent_bandwidth_stats.connection_type, workspace_agent_bandwidth_stats.port
+ORDER BY coalesce(SUM(rx_bytes), 0) + coalesce(SUM(tx_bytes), 0) DESC, user_id ASC, connection_type ASC, port ASC
+`
+
+type GetUserBandwidthInsightsParams struct {
+	StartTime   time.Time   `db:"start_time" json:"start_time"`
+	EndTime     time.Time   `db:"end_time" json:"end_time"`
+	TemplateIDs []uuid.UUID `db:"template_ids" json:"template_ids"`
+}
+
+type GetUserBandwidthInsightsRow struct {
+	UserID         uuid.UUID      `db:"user_id" json:"user_id"`
+	Username       string         `db:"username" json:"username"`
+	AvatarURL      sql.NullString `db:"avatar_url" json:"avatar_url"`
+	ConnectionType string         `db:"connection_type" json:"connection_type"`
+	Port           int32          `db:"port" json:"port"`
+	TemplateIDs    []uuid.UUID    `db:"template_ids" json:"template_ids"`
+	Peers          int64          `db:"peers" json:"peers"`
+	RxBytes        int64          `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes        int64          `db:"tx_bytes" json:"tx_bytes"`
+}
+
+// GetUserBandwidthInsights returns the bytes transferred by the workspaces of
+// each user, by connection type and agent port, largest first. The result can
+// be filtered on template_ids, meaning only user data from workspaces based on
+// those templates will be included.
+func (q *sqlQuerier) GetUserBandwidthInsights(ctx context.Context, arg GetUserBandwidthInsightsParams) ([]GetUserBandwidthInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getUserBandwidthInsights, arg.StartTime, arg.EndTime, pq.Array(arg.TemplateIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserBandwidthInsightsRow
+	for rows.Next() {
+		var i GetUserBandwidthInsightsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.AvatarURL,
+			&i.ConnectionType,
+			&i.Port,
+			pq.Array(&i.TemplateIDs),
+			&i.Peers,
+			&i.RxBytes,
+			&i.TxBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserLatencyInsights = `-- name: GetUserLatencyInsights :many
 SELECT
 	workspace_agent_stats.user_id,
@@ -1951,7 +2830,7 @@ func (q *sqlQuerier) DeleteLicense(ctx context.Context, id int32) (int32, error)
 
 const getLicenseByID = `-- name: GetLicenseByID :one
 SELECT
-	id, uploaded_at, jwt, exp, uuid
+	id, uploaded_at, jwt, exp, uuid, activation_key
 FROM
 	licenses
 WHERE
@@ -1969,12 +2848,56 @@ func (q *sqlQuerier) GetLicenseByID(ctx context.Context, id int32) (License, err
 		&i.JWT,
 		&i.Exp,
 		&i.UUID,
+		&i.ActivationKey,
 	)
 	return i, err
 }
 
+const getLicenseUsage = `-- name: GetLicenseUsage :many
+SELECT feature, date, peak_actual, latest_actual, feature_limit, first_exceeded_at, updated_at
+FROM license_usage
+WHERE date >= $1 :: date AND date <= $2 :: date
+ORDER BY feature, date
+`
+
+type GetLicenseUsageParams struct {
+	StartDate time.Time `db:"start_date" json:"start_date"`
+	EndDate   time.Time `db:"end_date" json:"end_date"`
+}
+
+func (q *sqlQuerier) GetLicenseUsage(ctx context.Context, arg GetLicenseUsageParams) ([]LicenseUsage, error) {
+	rows, err := q.db.QueryContext(ctx, getLicenseUsage, arg.StartDate, arg.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LicenseUsage
+	for rows.Next() {
+		var i LicenseUsage
+		if err := rows.Scan(
+			&i.Feature,
+			&i.Date,
+			&i.PeakActual,
+			&i.LatestActual,
+			&i.FeatureLimit,
+			&i.FirstExceededAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLicenses = `-- name: GetLicenses :many
-SELECT id, uploaded_at, jwt, exp, uuid
+SELECT id, uploaded_at, jwt, exp, uuid, activation_key
 FROM licenses
 ORDER BY (id)
 `
@@ -1994,6 +2917,7 @@ func (q *sqlQuerier) GetLicenses(ctx context.Context) ([]License, error) {
 			&i.JWT,
 			&i.Exp,
 			&i.UUID,
+			&i.ActivationKey,
 		); err != nil {
 			return nil, err
 		}
@@ -2009,7 +2933,7 @@ func (q *sqlQuerier) GetLicenses(ctx context.Context) ([]License, error) {
 }
 
 const getUnexpiredLicenses = `-- name: GetUnexpiredLicenses :many
-SELECT id, uploaded_at, jwt, exp, uuid
+SELECT id, uploaded_at, jwt, exp, uuid, activation_key
 FROM licenses
 WHERE exp > NOW()
 ORDER BY (id)
@@ -2030,6 +2954,219 @@ func (q *sqlQuerier) GetUnexpiredLicenses(ctx context.Context) ([]License, error
 			&i.JWT,
 			&i.Exp,
 			&i.UUID,
+			&i.ActivationKey,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertLicense = `-- name: InsertLicense :one
+INSERT INTO
+	licenses (
+	uploaded_at,
+	jwt,
+	exp,
+	uuid,
+	activation_key
+)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING id, uploaded_at, jwt, exp, uuid, activation_key
+`
+
+type InsertLicenseParams struct {
+	UploadedAt    time.Time `db:"uploaded_at" json:"uploaded_at"`
+	JWT           string    `db:"jwt" json:"jwt"`
+	Exp           time.Time `db:"exp" json:"exp"`
+	UUID          uuid.UUID `db:"uuid" json:"uuid"`
+	ActivationKey string    `db:"activation_key" json:"activation_key"`
+}
+
+func (q *sqlQuerier) InsertLicense(ctx context.Context, arg InsertLicenseParams) (License, error) {
+	row := q.db.QueryRowContext(ctx, insertLicense,
+		arg.UploadedAt,
+		arg.JWT,
+		arg.Exp,
+		arg.UUID,
+		arg.ActivationKey,
+	)
+	var i License
+	err := row.Scan(
+		&i.ID,
+		&i.UploadedAt,
+		&i.JWT,
+		&i.Exp,
+		&i.UUID,
+		&i.ActivationKey,
+	)
+	return i, err
+}
+
+const upsertLicenseUsage = `-- name: UpsertLicenseUsage :exec
+INSERT INTO
+	license_usage (
+	feature,
+	date,
+	peak_actual,
+	latest_actual,
+	feature_limit,
+	first_exceeded_at,
+	updated_at
+)
+VALUES
+	($1, $2, $3, $3, $4, $5, $6)
+ON CONFLICT
+	(feature, date)
+DO UPDATE SET
+	peak_actual = GREATEST(license_usage.peak_actual, EXCLUDED.peak_actual),
+	latest_actual = EXCLUDED.latest_actual,
+	feature_limit = EXCLUDED.feature_limit,
+	first_exceeded_at = COALESCE(license_usage.first_exceeded_at, EXCLUDED.first_exceeded_at),
+	updated_at = EXCLUDED.updated_at
+`
+
+type UpsertLicenseUsageParams struct {
+	Feature         string        `db:"feature" json:"feature"`
+	Date            time.Time     `db:"date" json:"date"`
+	Actual          int64         `db:"actual" json:"actual"`
+	FeatureLimit    sql.NullInt64 `db:"feature_limit" json:"feature_limit"`
+	FirstExceededAt sql.NullTime  `db:"first_exceeded_at" json:"first_exceeded_at"`
+	UpdatedAt       time.Time     `db:"updated_at" json:"updated_at"`
+}
+
+// Records a usage sample for a feature, keeping the peak and the first time
+// the limit was exceeded on the given day.
+func (q *sqlQuerier) UpsertLicenseUsage(ctx context.Context, arg UpsertLicenseUsageParams) error {
+	_, err := q.db.ExecContext(ctx, upsertLicenseUsage,
+		arg.Feature,
+		arg.Date,
+		arg.Actual,
+		arg.FeatureLimit,
+		arg.FirstExceededAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const deleteManagedEnvironmentVariableByID = `-- name: DeleteManagedEnvironmentVariableByID :exec
+DELETE FROM
+	managed_environment_variables
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteManagedEnvironmentVariableByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteManagedEnvironmentVariableByID, id)
+	return err
+}
+
+const getManagedEnvironmentVariableByID = `-- name: GetManagedEnvironmentVariableByID :one
+SELECT
+	id, organization_id, template_id, created_at, updated_at, name, value, secret
+FROM
+	managed_environment_variables
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetManagedEnvironmentVariableByID(ctx context.Context, id uuid.UUID) (ManagedEnvironmentVariable, error) {
+	row := q.db.QueryRowContext(ctx, getManagedEnvironmentVariableByID, id)
+	var i ManagedEnvironmentVariable
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Value,
+		&i.Secret,
+	)
+	return i, err
+}
+
+const getManagedEnvironmentVariablesByOrganizationID = `-- name: GetManagedEnvironmentVariablesByOrganizationID :many
+SELECT
+	id, organization_id, template_id, created_at, updated_at, name, value, secret
+FROM
+	managed_environment_variables
+WHERE
+	organization_id = $1
+	AND template_id IS NULL
+ORDER BY
+	name ASC
+`
+
+// Returns the variables of the organization that aren't scoped to a template.
+func (q *sqlQuerier) GetManagedEnvironmentVariablesByOrganizationID(ctx context.Context, organizationID uuid.UUID) ([]ManagedEnvironmentVariable, error) {
+	rows, err := q.db.QueryContext(ctx, getManagedEnvironmentVariablesByOrganizationID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ManagedEnvironmentVariable
+	for rows.Next() {
+		var i ManagedEnvironmentVariable
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.TemplateID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Value,
+			&i.Secret,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getManagedEnvironmentVariablesByTemplateID = `-- name: GetManagedEnvironmentVariablesByTemplateID :many
+SELECT
+	id, organization_id, template_id, created_at, updated_at, name, value, secret
+FROM
+	managed_environment_variables
+WHERE
+	template_id = $1
+ORDER BY
+	name ASC
+`
+
+func (q *sqlQuerier) GetManagedEnvironmentVariablesByTemplateID(ctx context.Context, templateID uuid.NullUUID) ([]ManagedEnvironmentVariable, error) {
+	rows, err := q.db.QueryContext(ctx, getManagedEnvironmentVariablesByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ManagedEnvironmentVariable
+	for rows.Next() {
+		var i ManagedEnvironmentVariable
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.TemplateID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.Value,
+			&i.Secret,
 		); err != nil {
 			return nil, err
 		}
@@ -2044,39 +3181,94 @@ func (q *sqlQuerier) GetUnexpiredLicenses(ctx context.Context) ([]License, error
 	return items, nil
 }
 
-const insertLicense = `-- name: InsertLicense :one
-INSERT INTO
-	licenses (
-	uploaded_at,
-	jwt,
-	exp,
-	uuid
-)
-VALUES
-	($1, $2, $3, $4) RETURNING id, uploaded_at, jwt, exp, uuid
+const insertManagedEnvironmentVariable = `-- name: InsertManagedEnvironmentVariable :one
+INSERT INTO
+	managed_environment_variables (
+		id,
+		organization_id,
+		template_id,
+		created_at,
+		updated_at,
+		name,
+		value,
+		secret
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, organization_id, template_id, created_at, updated_at, name, value, secret
+`
+
+type InsertManagedEnvironmentVariableParams struct {
+	ID             uuid.UUID     `db:"id" json:"id"`
+	OrganizationID uuid.UUID     `db:"organization_id" json:"organization_id"`
+	TemplateID     uuid.NullUUID `db:"template_id" json:"template_id"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time     `db:"updated_at" json:"updated_at"`
+	Name           string        `db:"name" json:"name"`
+	Value          string        `db:"value" json:"value"`
+	Secret         bool          `db:"secret" json:"secret"`
+}
+
+func (q *sqlQuerier) InsertManagedEnvironmentVariable(ctx context.Context, arg InsertManagedEnvironmentVariableParams) (ManagedEnvironmentVariable, error) {
+	row := q.db.QueryRowContext(ctx, insertManagedEnvironmentVariable,
+		arg.ID,
+		arg.OrganizationID,
+		arg.TemplateID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.Name,
+		arg.Value,
+		arg.Secret,
+	)
+	var i ManagedEnvironmentVariable
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Value,
+		&i.Secret,
+	)
+	return i, err
+}
+
+const updateManagedEnvironmentVariableByID = `-- name: UpdateManagedEnvironmentVariableByID :one
+UPDATE
+	managed_environment_variables
+SET
+	updated_at = $2,
+	value = $3,
+	secret = $4
+WHERE
+	id = $1
+RETURNING id, organization_id, template_id, created_at, updated_at, name, value, secret
 `
 
-type InsertLicenseParams struct {
-	UploadedAt time.Time `db:"uploaded_at" json:"uploaded_at"`
-	JWT        string    `db:"jwt" json:"jwt"`
-	Exp        time.Time `db:"exp" json:"exp"`
-	UUID       uuid.UUID `db:"uuid" json:"uuid"`
+type UpdateManagedEnvironmentVariableByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	Value     string    `db:"value" json:"value"`
+	Secret    bool      `db:"secret" json:"secret"`
 }
 
-func (q *sqlQuerier) InsertLicense(ctx context.Context, arg InsertLicenseParams) (License, error) {
-	row := q.db.QueryRowContext(ctx, insertLicense,
-		arg.UploadedAt,
-		arg.JWT,
-		arg.Exp,
-		arg.UUID,
+func (q *sqlQuerier) UpdateManagedEnvironmentVariableByID(ctx context.Context, arg UpdateManagedEnvironmentVariableByIDParams) (ManagedEnvironmentVariable, error) {
+	row := q.db.QueryRowContext(ctx, updateManagedEnvironmentVariableByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.Value,
+		arg.Secret,
 	)
-	var i License
+	var i ManagedEnvironmentVariable
 	err := row.Scan(
 		&i.ID,
-		&i.UploadedAt,
-		&i.JWT,
-		&i.Exp,
-		&i.UUID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Value,
+		&i.Secret,
 	)
 	return i, err
 }
@@ -2109,6 +3301,162 @@ func (q *sqlQuerier) TryAcquireLock(ctx context.Context, pgTryAdvisoryXactLock i
 	return pg_try_advisory_xact_lock, err
 }
 
+const deleteOldNetcheckReports = `-- name: DeleteOldNetcheckReports :exec
+DELETE FROM netcheck_reports WHERE created_at < NOW() - INTERVAL '30 days'
+`
+
+func (q *sqlQuerier) DeleteOldNetcheckReports(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldNetcheckReports)
+	return err
+}
+
+const getNetcheckReports = `-- name: GetNetcheckReports :many
+SELECT
+	id, user_id, created_at, client_version, client_os, nat_type, udp, ipv4, ipv6, mapping_varies_by_dest_ip, hair_pinning, preferred_derp, region_latencies, blocked_ports
+FROM
+	netcheck_reports
+WHERE
+	created_at >= $1
+	AND created_at < $2
+	AND CASE
+		WHEN $3::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			user_id = $3
+		ELSE true
+	END
+ORDER BY
+	created_at DESC
+LIMIT
+	$4::int
+`
+
+type GetNetcheckReportsParams struct {
+	StartTime time.Time `db:"start_time" json:"start_time"`
+	EndTime   time.Time `db:"end_time" json:"end_time"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	LimitOpt  int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+// GetNetcheckReports returns the reports created in the time range, newest
+// first, optionally filtered by user.
+func (q *sqlQuerier) GetNetcheckReports(ctx context.Context, arg GetNetcheckReportsParams) ([]NetcheckReport, error) {
+	rows, err := q.db.QueryContext(ctx, getNetcheckReports,
+		arg.StartTime,
+		arg.EndTime,
+		arg.UserID,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NetcheckReport
+	for rows.Next() {
+		var i NetcheckReport
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.ClientVersion,
+			&i.ClientOS,
+			&i.NATType,
+			&i.UDP,
+			&i.IPv4,
+			&i.IPv6,
+			&i.MappingVariesByDestIP,
+			&i.HairPinning,
+			&i.PreferredDERP,
+			&i.RegionLatencies,
+			pq.Array(&i.BlockedPorts),
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertNetcheckReport = `-- name: InsertNetcheckReport :one
+INSERT INTO
+	netcheck_reports (
+		id,
+		user_id,
+		created_at,
+		client_version,
+		client_os,
+		nat_type,
+		udp,
+		ipv4,
+		ipv6,
+		mapping_varies_by_dest_ip,
+		hair_pinning,
+		preferred_derp,
+		region_latencies,
+		blocked_ports
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id, user_id, created_at, client_version, client_os, nat_type, udp, ipv4, ipv6, mapping_varies_by_dest_ip, hair_pinning, preferred_derp, region_latencies, blocked_ports
+`
+
+type InsertNetcheckReportParams struct {
+	ID                    uuid.UUID       `db:"id" json:"id"`
+	UserID                uuid.UUID       `db:"user_id" json:"user_id"`
+	CreatedAt             time.Time       `db:"created_at" json:"created_at"`
+	ClientVersion         string          `db:"client_version" json:"client_version"`
+	ClientOS              string          `db:"client_os" json:"client_os"`
+	NATType               string          `db:"nat_type" json:"nat_type"`
+	UDP                   bool            `db:"udp" json:"udp"`
+	IPv4                  bool            `db:"ipv4" json:"ipv4"`
+	IPv6                  bool            `db:"ipv6" json:"ipv6"`
+	MappingVariesByDestIP sql.NullBool    `db:"mapping_varies_by_dest_ip" json:"mapping_varies_by_dest_ip"`
+	HairPinning           sql.NullBool    `db:"hair_pinning" json:"hair_pinning"`
+	PreferredDERP         int32           `db:"preferred_derp" json:"preferred_derp"`
+	RegionLatencies       json.RawMessage `db:"region_latencies" json:"region_latencies"`
+	BlockedPorts          []string        `db:"blocked_ports" json:"blocked_ports"`
+}
+
+func (q *sqlQuerier) InsertNetcheckReport(ctx context.Context, arg InsertNetcheckReportParams) (NetcheckReport, error) {
+	row := q.db.QueryRowContext(ctx, insertNetcheckReport,
+		arg.ID,
+		arg.UserID,
+		arg.CreatedAt,
+		arg.ClientVersion,
+		arg.ClientOS,
+		arg.NATType,
+		arg.UDP,
+		arg.IPv4,
+		arg.IPv6,
+		arg.MappingVariesByDestIP,
+		arg.HairPinning,
+		arg.PreferredDERP,
+		arg.RegionLatencies,
+		pq.Array(arg.BlockedPorts),
+	)
+	var i NetcheckReport
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.ClientVersion,
+		&i.ClientOS,
+		&i.NATType,
+		&i.UDP,
+		&i.IPv4,
+		&i.IPv6,
+		&i.MappingVariesByDestIP,
+		&i.HairPinning,
+		&i.PreferredDERP,
+		&i.RegionLatencies,
+		pq.Array(&i.BlockedPorts),
+	)
+	return i, err
+}
+
 const getOrganizationIDsByMemberIDs = `-- name: GetOrganizationIDsByMemberIDs :many
 SELECT
     user_id, array_agg(organization_id) :: uuid [ ] AS "organization_IDs"
@@ -2289,7 +3637,7 @@ func (q *sqlQuerier) UpdateMemberRoles(ctx context.Context, arg UpdateMemberRole
 
 const getOrganizationByID = `-- name: GetOrganizationByID :one
 SELECT
-	id, name, description, created_at, updated_at
+	id, name, description, created_at, updated_at, max_concurrent_provisioner_jobs
 FROM
 	organizations
 WHERE
@@ -2305,13 +3653,14 @@ func (q *sqlQuerier) GetOrganizationByID(ctx context.Context, id uuid.UUID) (Org
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MaxConcurrentProvisionerJobs,
 	)
 	return i, err
 }
 
 const getOrganizationByName = `-- name: GetOrganizationByName :one
 SELECT
-	id, name, description, created_at, updated_at
+	id, name, description, created_at, updated_at, max_concurrent_provisioner_jobs
 FROM
 	organizations
 WHERE
@@ -2329,13 +3678,14 @@ func (q *sqlQuerier) GetOrganizationByName(ctx context.Context, name string) (Or
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MaxConcurrentProvisionerJobs,
 	)
 	return i, err
 }
 
 const getOrganizations = `-- name: GetOrganizations :many
 SELECT
-	id, name, description, created_at, updated_at
+	id, name, description, created_at, updated_at, max_concurrent_provisioner_jobs
 FROM
 	organizations
 `
@@ -2355,6 +3705,7 @@ func (q *sqlQuerier) GetOrganizations(ctx context.Context) ([]Organization, erro
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.MaxConcurrentProvisionerJobs,
 		); err != nil {
 			return nil, err
 		}
@@ -2371,7 +3722,7 @@ func (q *sqlQuerier) GetOrganizations(ctx context.Context) ([]Organization, erro
 
 const getOrganizationsByUserID = `-- name: GetOrganizationsByUserID :many
 SELECT
-	id, name, description, created_at, updated_at
+	id, name, description, created_at, updated_at, max_concurrent_provisioner_jobs
 FROM
 	organizations
 WHERE
@@ -2400,6 +3751,7 @@ func (q *sqlQuerier) GetOrganizationsByUserID(ctx context.Context, userID uuid.U
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.MaxConcurrentProvisionerJobs,
 		); err != nil {
 			return nil, err
 		}
@@ -2418,7 +3770,7 @@ const insertOrganization = `-- name: InsertOrganization :one
 INSERT INTO
 	organizations (id, "name", description, created_at, updated_at)
 VALUES
-	($1, $2, $3, $4, $5) RETURNING id, name, description, created_at, updated_at
+	($1, $2, $3, $4, $5) RETURNING id, name, description, created_at, updated_at, max_concurrent_provisioner_jobs
 `
 
 type InsertOrganizationParams struct {
@@ -2444,6 +3796,38 @@ func (q *sqlQuerier) InsertOrganization(ctx context.Context, arg InsertOrganizat
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.MaxConcurrentProvisionerJobs,
+	)
+	return i, err
+}
+
+const updateOrganizationProvisionerLimits = `-- name: UpdateOrganizationProvisionerLimits :one
+UPDATE
+	organizations
+SET
+	updated_at = $2,
+	max_concurrent_provisioner_jobs = $3
+WHERE
+	id = $1
+RETURNING id, name, description, created_at, updated_at, max_concurrent_provisioner_jobs
+`
+
+type UpdateOrganizationProvisionerLimitsParams struct {
+	ID                           uuid.UUID `db:"id" json:"id"`
+	UpdatedAt                    time.Time `db:"updated_at" json:"updated_at"`
+	MaxConcurrentProvisionerJobs int32     `db:"max_concurrent_provisioner_jobs" json:"max_concurrent_provisioner_jobs"`
+}
+
+func (q *sqlQuerier) UpdateOrganizationProvisionerLimits(ctx context.Context, arg UpdateOrganizationProvisionerLimitsParams) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, updateOrganizationProvisionerLimits, arg.ID, arg.UpdatedAt, arg.MaxConcurrentProvisionerJobs)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MaxConcurrentProvisionerJobs,
 	)
 	return i, err
 }
@@ -2500,6 +3884,101 @@ func (q *sqlQuerier) GetParameterSchemasByJobID(ctx context.Context, jobID uuid.
 	return items, nil
 }
 
+const deleteOldPlatformEvents = `-- name: DeleteOldPlatformEvents :exec
+DELETE FROM platform_events WHERE created_at < NOW() - INTERVAL '7 days'
+`
+
+func (q *sqlQuerier) DeleteOldPlatformEvents(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldPlatformEvents)
+	return err
+}
+
+const getPlatformEventsAfterID = `-- name: GetPlatformEventsAfterID :many
+SELECT
+	id, created_at, type, resource_id, data
+FROM
+	platform_events
+WHERE
+	id > $1
+ORDER BY
+	id ASC
+LIMIT
+	$2::int
+`
+
+type GetPlatformEventsAfterIDParams struct {
+	AfterID  int64 `db:"after_id" json:"after_id"`
+	LimitOpt int32 `db:"limit_opt" json:"limit_opt"`
+}
+
+// GetPlatformEventsAfterID returns events newer than the given cursor, oldest
+// first.
+func (q *sqlQuerier) GetPlatformEventsAfterID(ctx context.Context, arg GetPlatformEventsAfterIDParams) ([]PlatformEvent, error) {
+	rows, err := q.db.QueryContext(ctx, getPlatformEventsAfterID, arg.AfterID, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlatformEvent
+	for rows.Next() {
+		var i PlatformEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.Type,
+			&i.ResourceID,
+			&i.Data,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertPlatformEvent = `-- name: InsertPlatformEvent :one
+INSERT INTO
+	platform_events (
+		created_at,
+		type,
+		resource_id,
+		data
+	)
+VALUES
+	($1, $2, $3, $4) RETURNING id, created_at, type, resource_id, data
+`
+
+type InsertPlatformEventParams struct {
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+	Type       string          `db:"type" json:"type"`
+	ResourceID uuid.UUID       `db:"resource_id" json:"resource_id"`
+	Data       json.RawMessage `db:"data" json:"data"`
+}
+
+func (q *sqlQuerier) InsertPlatformEvent(ctx context.Context, arg InsertPlatformEventParams) (PlatformEvent, error) {
+	row := q.db.QueryRowContext(ctx, insertPlatformEvent,
+		arg.CreatedAt,
+		arg.Type,
+		arg.ResourceID,
+		arg.Data,
+	)
+	var i PlatformEvent
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.Type,
+		&i.ResourceID,
+		&i.Data,
+	)
+	return i, err
+}
+
 const getProvisionerDaemons = `-- name: GetProvisionerDaemons :many
 SELECT
 	id, created_at, updated_at, name, provisioners, replica_id, tags
@@ -2580,6 +4059,106 @@ func (q *sqlQuerier) InsertProvisionerDaemon(ctx context.Context, arg InsertProv
 	return i, err
 }
 
+const getProvisionerJobBuildHooksByJobID = `-- name: GetProvisionerJobBuildHooksByJobID :many
+SELECT
+	id, job_id, name, phase, started_at, completed_at, attempts, exit_code, error
+FROM
+	provisioner_job_build_hooks
+WHERE
+	job_id = $1
+ORDER BY
+	started_at ASC
+`
+
+func (q *sqlQuerier) GetProvisionerJobBuildHooksByJobID(ctx context.Context, jobID uuid.UUID) ([]ProvisionerJobBuildHook, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobBuildHooksByJobID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJobBuildHook
+	for rows.Next() {
+		var i ProvisionerJobBuildHook
+		if err := rows.Scan(
+			&i.ID,
+			&i.JobID,
+			&i.Name,
+			&i.Phase,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.Attempts,
+			&i.ExitCode,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertProvisionerJobBuildHook = `-- name: InsertProvisionerJobBuildHook :one
+INSERT INTO
+	provisioner_job_build_hooks (
+		id,
+		job_id,
+		name,
+		phase,
+		started_at,
+		completed_at,
+		attempts,
+		exit_code,
+		error
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, job_id, name, phase, started_at, completed_at, attempts, exit_code, error
+`
+
+type InsertProvisionerJobBuildHookParams struct {
+	ID          uuid.UUID      `db:"id" json:"id"`
+	JobID       uuid.UUID      `db:"job_id" json:"job_id"`
+	Name        string         `db:"name" json:"name"`
+	Phase       BuildHookPhase `db:"phase" json:"phase"`
+	StartedAt   time.Time      `db:"started_at" json:"started_at"`
+	CompletedAt time.Time      `db:"completed_at" json:"completed_at"`
+	Attempts    int32          `db:"attempts" json:"attempts"`
+	ExitCode    int32          `db:"exit_code" json:"exit_code"`
+	Error       string         `db:"error" json:"error"`
+}
+
+func (q *sqlQuerier) InsertProvisionerJobBuildHook(ctx context.Context, arg InsertProvisionerJobBuildHookParams) (ProvisionerJobBuildHook, error) {
+	row := q.db.QueryRowContext(ctx, insertProvisionerJobBuildHook,
+		arg.ID,
+		arg.JobID,
+		arg.Name,
+		arg.Phase,
+		arg.StartedAt,
+		arg.CompletedAt,
+		arg.Attempts,
+		arg.ExitCode,
+		arg.Error,
+	)
+	var i ProvisionerJobBuildHook
+	err := row.Scan(
+		&i.ID,
+		&i.JobID,
+		&i.Name,
+		&i.Phase,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.Attempts,
+		&i.ExitCode,
+		&i.Error,
+	)
+	return i, err
+}
+
 const getProvisionerLogsAfterID = `-- name: GetProvisionerLogsAfterID :many
 SELECT
 	job_id, created_at, source, level, stage, output, id
@@ -2688,6 +4267,54 @@ func (q *sqlQuerier) InsertProvisionerJobLogs(ctx context.Context, arg InsertPro
 }
 
 const acquireProvisionerJob = `-- name: AcquireProvisionerJob :one
+WITH running_jobs AS (
+	-- Template imports are linked to their template through the template
+	-- version, and workspace builds through the workspace.
+	SELECT
+		provisioner_jobs.organization_id,
+		COALESCE(template_versions.template_id, workspaces.template_id) AS template_id
+	FROM
+		provisioner_jobs
+	LEFT JOIN
+		template_versions ON template_versions.job_id = provisioner_jobs.id
+	LEFT JOIN
+		workspace_builds ON workspace_builds.job_id = provisioner_jobs.id
+	LEFT JOIN
+		workspaces ON workspaces.id = workspace_builds.workspace_id
+	WHERE
+		provisioner_jobs.started_at IS NOT NULL
+		AND provisioner_jobs.completed_at IS NULL
+),
+-- Templates and organizations that already run as many jobs as their
+-- concurrency limit allows.
+limited_templates AS (
+	SELECT
+		templates.id
+	FROM
+		templates
+	JOIN
+		running_jobs ON running_jobs.template_id = templates.id
+	WHERE
+		templates.max_concurrent_provisioner_jobs > 0
+	GROUP BY
+		templates.id
+	HAVING
+		COUNT(*) >= templates.max_concurrent_provisioner_jobs
+),
+limited_organizations AS (
+	SELECT
+		organizations.id
+	FROM
+		organizations
+	JOIN
+		running_jobs ON running_jobs.organization_id = organizations.id
+	WHERE
+		organizations.max_concurrent_provisioner_jobs > 0
+	GROUP BY
+		organizations.id
+	HAVING
+		COUNT(*) >= organizations.max_concurrent_provisioner_jobs
+)
 UPDATE
 	provisioner_jobs
 SET
@@ -2697,22 +4324,74 @@ SET
 WHERE
 	id = (
 		SELECT
-			id
+			nested.id
 		FROM
 			provisioner_jobs AS nested
+		LEFT JOIN
+			template_versions ON template_versions.job_id = nested.id
+		LEFT JOIN
+			workspace_builds ON workspace_builds.job_id = nested.id
+		LEFT JOIN
+			workspaces ON workspaces.id = workspace_builds.workspace_id
 		WHERE
 			nested.started_at IS NULL
 			-- Ensure the caller has the correct provisioner.
 			AND nested.provisioner = ANY($3 :: provisioner_type [ ])
-			-- Ensure the caller satisfies all job tags.
-			AND nested.tags <@ $4 :: jsonb
+			-- Ensure the caller satisfies all job tags. A job tag value of "*"
+			-- is satisfied by any value, and values separated by "|" by any of
+			-- them. Keep in sync with provisionerdserver.TagsSatisfy.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					jsonb_each_text(nested.tags) AS job_tag
+				WHERE
+					(($4 :: jsonb) ->> job_tag.key) IS NULL
+					OR (
+						job_tag.value != '*'
+						AND NOT (($4 :: jsonb) ->> job_tag.key) = ANY(string_to_array(job_tag.value, '|'))
+					)
+			)
+			-- Regional provisioners only acquire jobs pinned to their region,
+			-- and jobs pinned to a region only run there.
+			AND COALESCE(nested.tags ->> 'region', '') = COALESCE(($4 :: jsonb) ->> 'region', '')
+			-- Skip jobs that are held until they are approved.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workspace_approvals
+				WHERE
+					workspace_approvals.job_id = nested.id
+					AND workspace_approvals.status != 'approved'
+			)
+			-- Skip jobs whose template or organization already runs as many
+			-- jobs as its concurrency limit allows. Concurrent acquires can
+			-- both see room under a limit, so callers check it again with
+			-- GetProvisionerJobConcurrencyLimit under a lock.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					limited_organizations
+				WHERE
+					limited_organizations.id = nested.organization_id
+			)
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					limited_templates
+				WHERE
+					limited_templates.id = COALESCE(template_versions.template_id, workspaces.template_id)
+			)
 		ORDER BY
 			nested.created_at
-		FOR UPDATE
+		FOR UPDATE OF nested
 		SKIP LOCKED
 		LIMIT
 			1
-	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
+	) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, cancel_outcome, requeue_count
 `
 
 type AcquireProvisionerJobParams struct {
@@ -2755,13 +4434,15 @@ func (q *sqlQuerier) AcquireProvisionerJob(ctx context.Context, arg AcquireProvi
 		&i.Tags,
 		&i.ErrorCode,
 		&i.TraceMetadata,
+		&i.CancelOutcome,
+		&i.RequeueCount,
 	)
 	return i, err
 }
 
 const getHungProvisionerJobs = `-- name: GetHungProvisionerJobs :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, cancel_outcome, requeue_count
 FROM
 	provisioner_jobs
 WHERE
@@ -2798,6 +4479,66 @@ func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.
 			&i.Tags,
 			&i.ErrorCode,
 			&i.TraceMetadata,
+			&i.CancelOutcome,
+			&i.RequeueCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPendingProvisionerJobs = `-- name: GetPendingProvisionerJobs :many
+SELECT
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, cancel_outcome, requeue_count
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NULL
+	AND canceled_at IS NULL
+ORDER BY
+	created_at ASC
+`
+
+// GetPendingProvisionerJobs returns the jobs that haven't been acquired by a
+// provisioner daemon yet, oldest first.
+func (q *sqlQuerier) GetPendingProvisionerJobs(ctx context.Context) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getPendingProvisionerJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJob
+	for rows.Next() {
+		var i ProvisionerJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.TraceMetadata,
+			&i.CancelOutcome,
+			&i.RequeueCount,
 		); err != nil {
 			return nil, err
 		}
@@ -2814,7 +4555,7 @@ func (q *sqlQuerier) GetHungProvisionerJobs(ctx context.Context, updatedAt time.
 
 const getProvisionerJobByID = `-- name: GetProvisionerJobByID :one
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, cancel_outcome, requeue_count
 FROM
 	provisioner_jobs
 WHERE
@@ -2843,13 +4584,30 @@ func (q *sqlQuerier) GetProvisionerJobByID(ctx context.Context, id uuid.UUID) (P
 		&i.Tags,
 		&i.ErrorCode,
 		&i.TraceMetadata,
+		&i.CancelOutcome,
+		&i.RequeueCount,
 	)
 	return i, err
 }
 
+const getProvisionerJobConcurrencyLimit = `-- name: GetProvisionerJobConcurrencyLimit :one
+SELECT provisioner_job_concurrency_limit($1 :: uuid) :: text AS limited_by
+`
+
+// GetProvisionerJobConcurrencyLimit returns the concurrency limit that the job
+// would exceed if it ran, 'template' or 'organization', or an empty string.
+// The job doesn't count against the limit itself, so it may already be
+// started.
+func (q *sqlQuerier) GetProvisionerJobConcurrencyLimit(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRowContext(ctx, getProvisionerJobConcurrencyLimit, id)
+	var limited_by string
+	err := row.Scan(&limited_by)
+	return limited_by, err
+}
+
 const getProvisionerJobsByIDs = `-- name: GetProvisionerJobsByIDs :many
 SELECT
-	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, cancel_outcome, requeue_count
 FROM
 	provisioner_jobs
 WHERE
@@ -2884,6 +4642,8 @@ func (q *sqlQuerier) GetProvisionerJobsByIDs(ctx context.Context, ids []uuid.UUI
 			&i.Tags,
 			&i.ErrorCode,
 			&i.TraceMetadata,
+			&i.CancelOutcome,
+			&i.RequeueCount,
 		); err != nil {
 			return nil, err
 		}
@@ -2918,9 +4678,11 @@ queue_size AS (
 	SELECT COUNT(*) as count FROM unstarted_jobs
 )
 SELECT
-	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata,
+	pj.id, pj.created_at, pj.updated_at, pj.started_at, pj.canceled_at, pj.completed_at, pj.error, pj.organization_id, pj.initiator_id, pj.provisioner, pj.storage_method, pj.type, pj.input, pj.worker_id, pj.file_id, pj.tags, pj.error_code, pj.trace_metadata, pj.cancel_outcome, pj.requeue_count,
     COALESCE(qp.queue_position, 0) AS queue_position,
-    COALESCE(qs.count, 0) AS queue_size
+    COALESCE(qs.count, 0) AS queue_size,
+    -- The concurrency limit that holds back the job, if any.
+    (CASE WHEN pj.started_at IS NULL THEN provisioner_job_concurrency_limit(pj.id) ELSE '' END) :: text AS queue_limited_by
 FROM
 	provisioner_jobs pj
 LEFT JOIN
@@ -2935,6 +4697,7 @@ type GetProvisionerJobsByIDsWithQueuePositionRow struct {
 	ProvisionerJob ProvisionerJob `db:"provisioner_job" json:"provisioner_job"`
 	QueuePosition  int64          `db:"queue_position" json:"queue_position"`
 	QueueSize      int64          `db:"queue_size" json:"queue_size"`
+	QueueLimitedBy string         `db:"queue_limited_by" json:"queue_limited_by"`
 }
 
 func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Context, ids []uuid.UUID) ([]GetProvisionerJobsByIDsWithQueuePositionRow, error) {
@@ -2965,8 +4728,11 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 			&i.ProvisionerJob.Tags,
 			&i.ProvisionerJob.ErrorCode,
 			&i.ProvisionerJob.TraceMetadata,
+			&i.ProvisionerJob.CancelOutcome,
+			&i.ProvisionerJob.RequeueCount,
 			&i.QueuePosition,
 			&i.QueueSize,
+			&i.QueueLimitedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -2982,7 +4748,7 @@ func (q *sqlQuerier) GetProvisionerJobsByIDsWithQueuePosition(ctx context.Contex
 }
 
 const getProvisionerJobsCreatedAfter = `-- name: GetProvisionerJobsCreatedAfter :many
-SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata FROM provisioner_jobs WHERE created_at > $1
+SELECT id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, cancel_outcome, requeue_count FROM provisioner_jobs WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, createdAt time.Time) ([]ProvisionerJob, error) {
@@ -3013,6 +4779,65 @@ func (q *sqlQuerier) GetProvisionerJobsCreatedAfter(ctx context.Context, created
 			&i.Tags,
 			&i.ErrorCode,
 			&i.TraceMetadata,
+			&i.CancelOutcome,
+			&i.RequeueCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getProvisionerJobsStartedAfter = `-- name: GetProvisionerJobsStartedAfter :many
+SELECT
+	id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, cancel_outcome, requeue_count
+FROM
+	provisioner_jobs
+WHERE
+	started_at > $1 :: timestamptz
+ORDER BY
+	started_at ASC
+`
+
+// GetProvisionerJobsStartedAfter returns the jobs acquired by a provisioner
+// daemon after the given time, which is used to measure queue wait times.
+func (q *sqlQuerier) GetProvisionerJobsStartedAfter(ctx context.Context, startedAfter time.Time) ([]ProvisionerJob, error) {
+	rows, err := q.db.QueryContext(ctx, getProvisionerJobsStartedAfter, startedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProvisionerJob
+	for rows.Next() {
+		var i ProvisionerJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.StartedAt,
+			&i.CanceledAt,
+			&i.CompletedAt,
+			&i.Error,
+			&i.OrganizationID,
+			&i.InitiatorID,
+			&i.Provisioner,
+			&i.StorageMethod,
+			&i.Type,
+			&i.Input,
+			&i.WorkerID,
+			&i.FileID,
+			&i.Tags,
+			&i.ErrorCode,
+			&i.TraceMetadata,
+			&i.CancelOutcome,
+			&i.RequeueCount,
 		); err != nil {
 			return nil, err
 		}
@@ -3044,7 +4869,7 @@ INSERT INTO
 		trace_metadata
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at, updated_at, started_at, canceled_at, completed_at, error, organization_id, initiator_id, provisioner, storage_method, type, input, worker_id, file_id, tags, error_code, trace_metadata, cancel_outcome, requeue_count
 `
 
 type InsertProvisionerJobParams struct {
@@ -3097,6 +4922,8 @@ func (q *sqlQuerier) InsertProvisionerJob(ctx context.Context, arg InsertProvisi
 		&i.Tags,
 		&i.ErrorCode,
 		&i.TraceMetadata,
+		&i.CancelOutcome,
+		&i.RequeueCount,
 	)
 	return i, err
 }
@@ -3148,17 +4975,19 @@ SET
 	updated_at = $2,
 	completed_at = $3,
 	error = $4,
-	error_code = $5
+	error_code = $5,
+	cancel_outcome = $6
 WHERE
 	id = $1
 `
 
 type UpdateProvisionerJobWithCompleteByIDParams struct {
-	ID          uuid.UUID      `db:"id" json:"id"`
-	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
-	CompletedAt sql.NullTime   `db:"completed_at" json:"completed_at"`
-	Error       sql.NullString `db:"error" json:"error"`
-	ErrorCode   sql.NullString `db:"error_code" json:"error_code"`
+	ID            uuid.UUID                       `db:"id" json:"id"`
+	UpdatedAt     time.Time                       `db:"updated_at" json:"updated_at"`
+	CompletedAt   sql.NullTime                    `db:"completed_at" json:"completed_at"`
+	Error         sql.NullString                  `db:"error" json:"error"`
+	ErrorCode     sql.NullString                  `db:"error_code" json:"error_code"`
+	CancelOutcome NullProvisionerJobCancelOutcome `db:"cancel_outcome" json:"cancel_outcome"`
 }
 
 func (q *sqlQuerier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, arg UpdateProvisionerJobWithCompleteByIDParams) error {
@@ -3168,13 +4997,38 @@ func (q *sqlQuerier) UpdateProvisionerJobWithCompleteByID(ctx context.Context, a
 		arg.CompletedAt,
 		arg.Error,
 		arg.ErrorCode,
+		arg.CancelOutcome,
 	)
 	return err
 }
 
+const updateProvisionerJobWithRequeueByID = `-- name: UpdateProvisionerJobWithRequeueByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = $2,
+	started_at = NULL,
+	worker_id = NULL,
+	requeue_count = requeue_count + 1
+WHERE
+	id = $1
+`
+
+type UpdateProvisionerJobWithRequeueByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Puts a hung job back in the queue so another provisioner daemon can
+// acquire it.
+func (q *sqlQuerier) UpdateProvisionerJobWithRequeueByID(ctx context.Context, arg UpdateProvisionerJobWithRequeueByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateProvisionerJobWithRequeueByID, arg.ID, arg.UpdatedAt)
+	return err
+}
+
 const getWorkspaceProxies = `-- name: GetWorkspaceProxies :many
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, organization_ids, group_ids
 FROM
 	workspace_proxies
 WHERE
@@ -3204,6 +5058,8 @@ func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy,
 			&i.RegionID,
 			&i.DerpEnabled,
 			&i.DerpOnly,
+			pq.Array(&i.OrganizationIDs),
+			pq.Array(&i.GroupIDs),
 		); err != nil {
 			return nil, err
 		}
@@ -3220,7 +5076,7 @@ func (q *sqlQuerier) GetWorkspaceProxies(ctx context.Context) ([]WorkspaceProxy,
 
 const getWorkspaceProxyByHostname = `-- name: GetWorkspaceProxyByHostname :one
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, organization_ids, group_ids
 FROM
 	workspace_proxies
 WHERE
@@ -3279,13 +5135,15 @@ func (q *sqlQuerier) GetWorkspaceProxyByHostname(ctx context.Context, arg GetWor
 		&i.RegionID,
 		&i.DerpEnabled,
 		&i.DerpOnly,
+		pq.Array(&i.OrganizationIDs),
+		pq.Array(&i.GroupIDs),
 	)
 	return i, err
 }
 
 const getWorkspaceProxyByID = `-- name: GetWorkspaceProxyByID :one
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, organization_ids, group_ids
 FROM
 	workspace_proxies
 WHERE
@@ -3311,13 +5169,15 @@ func (q *sqlQuerier) GetWorkspaceProxyByID(ctx context.Context, id uuid.UUID) (W
 		&i.RegionID,
 		&i.DerpEnabled,
 		&i.DerpOnly,
+		pq.Array(&i.OrganizationIDs),
+		pq.Array(&i.GroupIDs),
 	)
 	return i, err
 }
 
 const getWorkspaceProxyByName = `-- name: GetWorkspaceProxyByName :one
 SELECT
-	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+	id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, organization_ids, group_ids
 FROM
 	workspace_proxies
 WHERE
@@ -3344,6 +5204,8 @@ func (q *sqlQuerier) GetWorkspaceProxyByName(ctx context.Context, name string) (
 		&i.RegionID,
 		&i.DerpEnabled,
 		&i.DerpOnly,
+		pq.Array(&i.OrganizationIDs),
+		pq.Array(&i.GroupIDs),
 	)
 	return i, err
 }
@@ -3365,7 +5227,7 @@ INSERT INTO
 		deleted
 	)
 VALUES
-	($1, '', '', $2, $3, $4, $5, $6, $7, $8, $9, false) RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+	($1, '', '', $2, $3, $4, $5, $6, $7, $8, $9, false) RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, organization_ids, group_ids
 `
 
 type InsertWorkspaceProxyParams struct {
@@ -3407,6 +5269,8 @@ func (q *sqlQuerier) InsertWorkspaceProxy(ctx context.Context, arg InsertWorkspa
 		&i.RegionID,
 		&i.DerpEnabled,
 		&i.DerpOnly,
+		pq.Array(&i.OrganizationIDs),
+		pq.Array(&i.GroupIDs),
 	)
 	return i, err
 }
@@ -3422,7 +5286,7 @@ SET
 	updated_at = Now()
 WHERE
 	id = $5
-RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, organization_ids, group_ids
 `
 
 type RegisterWorkspaceProxyParams struct {
@@ -3456,6 +5320,8 @@ func (q *sqlQuerier) RegisterWorkspaceProxy(ctx context.Context, arg RegisterWor
 		&i.RegionID,
 		&i.DerpEnabled,
 		&i.DerpOnly,
+		pq.Array(&i.OrganizationIDs),
+		pq.Array(&i.GroupIDs),
 	)
 	return i, err
 }
@@ -3478,7 +5344,7 @@ SET
 	updated_at = Now()
 WHERE
 	id = $5
-RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only
+RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, organization_ids, group_ids
 `
 
 type UpdateWorkspaceProxyParams struct {
@@ -3513,6 +5379,50 @@ func (q *sqlQuerier) UpdateWorkspaceProxy(ctx context.Context, arg UpdateWorkspa
 		&i.RegionID,
 		&i.DerpEnabled,
 		&i.DerpOnly,
+		pq.Array(&i.OrganizationIDs),
+		pq.Array(&i.GroupIDs),
+	)
+	return i, err
+}
+
+const updateWorkspaceProxyACL = `-- name: UpdateWorkspaceProxyACL :one
+UPDATE
+	workspace_proxies
+SET
+	organization_ids = $1 :: uuid[],
+	group_ids = $2 :: uuid[],
+	updated_at = Now()
+WHERE
+	id = $3
+RETURNING id, name, display_name, icon, url, wildcard_hostname, created_at, updated_at, deleted, token_hashed_secret, region_id, derp_enabled, derp_only, organization_ids, group_ids
+`
+
+type UpdateWorkspaceProxyACLParams struct {
+	OrganizationIDs []uuid.UUID `db:"organization_ids" json:"organization_ids"`
+	GroupIDs        []uuid.UUID `db:"group_ids" json:"group_ids"`
+	ID              uuid.UUID   `db:"id" json:"id"`
+}
+
+// Replaces the organizations and groups whose members may use the proxy.
+func (q *sqlQuerier) UpdateWorkspaceProxyACL(ctx context.Context, arg UpdateWorkspaceProxyACLParams) (WorkspaceProxy, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceProxyACL, pq.Array(arg.OrganizationIDs), pq.Array(arg.GroupIDs), arg.ID)
+	var i WorkspaceProxy
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DisplayName,
+		&i.Icon,
+		&i.Url,
+		&i.WildcardHostname,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.TokenHashedSecret,
+		&i.RegionID,
+		&i.DerpEnabled,
+		&i.DerpOnly,
+		pq.Array(&i.OrganizationIDs),
+		pq.Array(&i.GroupIDs),
 	)
 	return i, err
 }
@@ -3537,27 +5447,155 @@ func (q *sqlQuerier) UpdateWorkspaceProxyDeleted(ctx context.Context, arg Update
 	return err
 }
 
-const getQuotaAllowanceForUser = `-- name: GetQuotaAllowanceForUser :one
+const getUserPTYSessionOverride = `-- name: GetUserPTYSessionOverride :one
+SELECT
+	user_id, max_pty_sessions, created_at, updated_at
+FROM
+	user_pty_session_overrides
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetUserPTYSessionOverride(ctx context.Context, userID uuid.UUID) (UserPTYSessionOverride, error) {
+	row := q.db.QueryRowContext(ctx, getUserPTYSessionOverride, userID)
+	var i UserPTYSessionOverride
+	err := row.Scan(
+		&i.UserID,
+		&i.MaxPTYSessions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertUserPTYSessionOverride = `-- name: UpsertUserPTYSessionOverride :one
+INSERT INTO
+	user_pty_session_overrides (
+		user_id,
+		max_pty_sessions,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $3)
+ON CONFLICT
+	(user_id)
+DO UPDATE SET
+	max_pty_sessions = $2,
+	updated_at = $3
+RETURNING user_id, max_pty_sessions, created_at, updated_at
+`
+
+type UpsertUserPTYSessionOverrideParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	MaxPTYSessions int32     `db:"max_pty_sessions" json:"max_pty_sessions"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) UpsertUserPTYSessionOverride(ctx context.Context, arg UpsertUserPTYSessionOverrideParams) (UserPTYSessionOverride, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserPTYSessionOverride, arg.UserID, arg.MaxPTYSessions, arg.CreatedAt)
+	var i UserPTYSessionOverride
+	err := row.Scan(
+		&i.UserID,
+		&i.MaxPTYSessions,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteUserPTYSessionOverride = `-- name: DeleteUserPTYSessionOverride :exec
+DELETE FROM
+	user_pty_session_overrides
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserPTYSessionOverride(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserPTYSessionOverride, userID)
+	return err
+}
+
+const getQuotaBudgetsForUser = `-- name: GetQuotaBudgetsForUser :many
+SELECT
+	g.id, g.name, g.organization_id, g.avatar_url, g.quota_allowance, g.display_name, g.source, g.quota_override, g.avatar_file_id, g.metadata
+FROM
+	groups g
+WHERE
+	g.id IN (SELECT group_id FROM group_members WHERE user_id = $1)
+OR
+	g.id = g.organization_id
+ORDER BY
+	g.name ASC
+`
+
+// Returns the groups that contribute to the quota of the user: the groups the
+// user is a member of, and the Everyone groups, whose allowance is the
+// organization default.
+func (q *sqlQuerier) GetQuotaBudgetsForUser(ctx context.Context, userID uuid.UUID) ([]Group, error) {
+	rows, err := q.db.QueryContext(ctx, getQuotaBudgetsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Group
+	for rows.Next() {
+		var i Group
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.OrganizationID,
+			&i.AvatarURL,
+			&i.QuotaAllowance,
+			&i.DisplayName,
+			&i.Source,
+			&i.QuotaOverride,
+			&i.AvatarFileID,
+			&i.Metadata,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getQuotaConsumedForUser = `-- name: GetQuotaConsumedForUser :one
+WITH latest_builds AS (
 SELECT
-	coalesce(SUM(quota_allowance), 0)::BIGINT
+	DISTINCT ON
+	(workspace_id) id,
+	workspace_id,
+	daily_cost
 FROM
-	groups g
-LEFT JOIN group_members gm ON
-	g.id = gm.group_id
-WHERE
-	user_id = $1
-OR
-    g.id = g.organization_id
+	workspace_builds wb
+ORDER BY
+	workspace_id,
+	created_at DESC
+)
+SELECT
+	coalesce(SUM(daily_cost), 0)::BIGINT
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+WHERE NOT deleted AND workspaces.owner_id = $1
 `
 
-func (q *sqlQuerier) GetQuotaAllowanceForUser(ctx context.Context, userID uuid.UUID) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getQuotaAllowanceForUser, userID)
+func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getQuotaConsumedForUser, ownerID)
 	var column_1 int64
 	err := row.Scan(&column_1)
 	return column_1, err
 }
 
-const getQuotaConsumedForUser = `-- name: GetQuotaConsumedForUser :one
+const getQuotaConsumedWorkspacesForUser = `-- name: GetQuotaConsumedWorkspacesForUser :many
 WITH latest_builds AS (
 SELECT
 	DISTINCT ON
@@ -3571,19 +5609,116 @@ ORDER BY
 	created_at DESC
 )
 SELECT
-	coalesce(SUM(daily_cost), 0)::BIGINT
+	workspaces.id AS workspace_id,
+	workspaces.name AS workspace_name,
+	latest_builds.daily_cost
 FROM
 	workspaces
 JOIN latest_builds ON
 	latest_builds.workspace_id = workspaces.id
 WHERE NOT deleted AND workspaces.owner_id = $1
+ORDER BY
+	latest_builds.daily_cost DESC, workspaces.name ASC
 `
 
-func (q *sqlQuerier) GetQuotaConsumedForUser(ctx context.Context, ownerID uuid.UUID) (int64, error) {
-	row := q.db.QueryRowContext(ctx, getQuotaConsumedForUser, ownerID)
-	var column_1 int64
-	err := row.Scan(&column_1)
-	return column_1, err
+type GetQuotaConsumedWorkspacesForUserRow struct {
+	WorkspaceID   uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName string    `db:"workspace_name" json:"workspace_name"`
+	DailyCost     int32     `db:"daily_cost" json:"daily_cost"`
+}
+
+// Returns the daily cost of the latest build of each workspace of the user.
+// The costs add up to GetQuotaConsumedForUser.
+func (q *sqlQuerier) GetQuotaConsumedWorkspacesForUser(ctx context.Context, ownerID uuid.UUID) ([]GetQuotaConsumedWorkspacesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getQuotaConsumedWorkspacesForUser, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetQuotaConsumedWorkspacesForUserRow
+	for rows.Next() {
+		var i GetQuotaConsumedWorkspacesForUserRow
+		if err := rows.Scan(&i.WorkspaceID, &i.WorkspaceName, &i.DailyCost); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserQuotaOverride = `-- name: GetUserQuotaOverride :one
+SELECT
+	user_id, quota_allowance, created_at, updated_at
+FROM
+	user_quota_overrides
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) GetUserQuotaOverride(ctx context.Context, userID uuid.UUID) (UserQuotaOverride, error) {
+	row := q.db.QueryRowContext(ctx, getUserQuotaOverride, userID)
+	var i UserQuotaOverride
+	err := row.Scan(
+		&i.UserID,
+		&i.QuotaAllowance,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertUserQuotaOverride = `-- name: UpsertUserQuotaOverride :one
+INSERT INTO
+	user_quota_overrides (
+		user_id,
+		quota_allowance,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $3)
+ON CONFLICT
+	(user_id)
+DO UPDATE SET
+	quota_allowance = $2,
+	updated_at = $3
+RETURNING user_id, quota_allowance, created_at, updated_at
+`
+
+type UpsertUserQuotaOverrideParams struct {
+	UserID         uuid.UUID `db:"user_id" json:"user_id"`
+	QuotaAllowance int32     `db:"quota_allowance" json:"quota_allowance"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) UpsertUserQuotaOverride(ctx context.Context, arg UpsertUserQuotaOverrideParams) (UserQuotaOverride, error) {
+	row := q.db.QueryRowContext(ctx, upsertUserQuotaOverride, arg.UserID, arg.QuotaAllowance, arg.CreatedAt)
+	var i UserQuotaOverride
+	err := row.Scan(
+		&i.UserID,
+		&i.QuotaAllowance,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteUserQuotaOverride = `-- name: DeleteUserQuotaOverride :exec
+DELETE FROM
+	user_quota_overrides
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserQuotaOverride(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserQuotaOverride, userID)
+	return err
 }
 
 const deleteReplicasUpdatedBefore = `-- name: DeleteReplicasUpdatedBefore :exec
@@ -3596,7 +5731,7 @@ func (q *sqlQuerier) DeleteReplicasUpdatedBefore(ctx context.Context, updatedAt
 }
 
 const getReplicaByID = `-- name: GetReplicaByID :one
-SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE id = $1
+SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary", draining_at FROM replicas WHERE id = $1
 `
 
 func (q *sqlQuerier) GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica, error) {
@@ -3615,12 +5750,13 @@ func (q *sqlQuerier) GetReplicaByID(ctx context.Context, id uuid.UUID) (Replica,
 		&i.Version,
 		&i.Error,
 		&i.Primary,
+		&i.DrainingAt,
 	)
 	return i, err
 }
 
 const getReplicasUpdatedAfter = `-- name: GetReplicasUpdatedAfter :many
-SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary" FROM replicas WHERE updated_at > $1 AND stopped_at IS NULL
+SELECT id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary", draining_at FROM replicas WHERE updated_at > $1 AND stopped_at IS NULL
 `
 
 func (q *sqlQuerier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time.Time) ([]Replica, error) {
@@ -3645,6 +5781,7 @@ func (q *sqlQuerier) GetReplicasUpdatedAfter(ctx context.Context, updatedAt time
 			&i.Version,
 			&i.Error,
 			&i.Primary,
+			&i.DrainingAt,
 		); err != nil {
 			return nil, err
 		}
@@ -3671,7 +5808,7 @@ INSERT INTO replicas (
     version,
     database_latency,
 	"primary"
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary"
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary", draining_at
 `
 
 type InsertReplicaParams struct {
@@ -3714,6 +5851,7 @@ func (q *sqlQuerier) InsertReplica(ctx context.Context, arg InsertReplicaParams)
 		&i.Version,
 		&i.Error,
 		&i.Primary,
+		&i.DrainingAt,
 	)
 	return i, err
 }
@@ -3730,7 +5868,7 @@ UPDATE replicas SET
     error = $9,
     database_latency = $10,
 	"primary" = $11
-WHERE id = $1 RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary"
+WHERE id = $1 RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary", draining_at
 `
 
 type UpdateReplicaParams struct {
@@ -3775,6 +5913,39 @@ func (q *sqlQuerier) UpdateReplica(ctx context.Context, arg UpdateReplicaParams)
 		&i.Version,
 		&i.Error,
 		&i.Primary,
+		&i.DrainingAt,
+	)
+	return i, err
+}
+
+const updateReplicaDrainingAt = `-- name: UpdateReplicaDrainingAt :one
+UPDATE replicas SET
+	draining_at = $2
+WHERE id = $1 AND stopped_at IS NULL RETURNING id, created_at, started_at, stopped_at, updated_at, hostname, region_id, relay_address, database_latency, version, error, "primary", draining_at
+`
+
+type UpdateReplicaDrainingAtParams struct {
+	ID         uuid.UUID    `db:"id" json:"id"`
+	DrainingAt sql.NullTime `db:"draining_at" json:"draining_at"`
+}
+
+func (q *sqlQuerier) UpdateReplicaDrainingAt(ctx context.Context, arg UpdateReplicaDrainingAtParams) (Replica, error) {
+	row := q.db.QueryRowContext(ctx, updateReplicaDrainingAt, arg.ID, arg.DrainingAt)
+	var i Replica
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.StoppedAt,
+		&i.UpdatedAt,
+		&i.Hostname,
+		&i.RegionID,
+		&i.RelayAddress,
+		&i.DatabaseLatency,
+		&i.Version,
+		&i.Error,
+		&i.Primary,
+		&i.DrainingAt,
 	)
 	return i, err
 }
@@ -3863,6 +6034,28 @@ func (q *sqlQuerier) GetOAuthSigningKey(ctx context.Context) (string, error) {
 	return value, err
 }
 
+const getRuntimeExperiments = `-- name: GetRuntimeExperiments :one
+SELECT value FROM site_configs WHERE key = 'runtime_experiments'
+`
+
+func (q *sqlQuerier) GetRuntimeExperiments(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getRuntimeExperiments)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const getSSHCAKey = `-- name: GetSSHCAKey :one
+SELECT value FROM site_configs WHERE key = 'ssh_ca_key'
+`
+
+func (q *sqlQuerier) GetSSHCAKey(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getSSHCAKey)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
 const getServiceBanner = `-- name: GetServiceBanner :one
 SELECT value FROM site_configs WHERE key = 'service_banner'
 `
@@ -3955,6 +6148,26 @@ func (q *sqlQuerier) UpsertOAuthSigningKey(ctx context.Context, value string) er
 	return err
 }
 
+const upsertRuntimeExperiments = `-- name: UpsertRuntimeExperiments :exec
+INSERT INTO site_configs (key, value) VALUES ('runtime_experiments', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'runtime_experiments'
+`
+
+func (q *sqlQuerier) UpsertRuntimeExperiments(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertRuntimeExperiments, value)
+	return err
+}
+
+const upsertSSHCAKey = `-- name: UpsertSSHCAKey :exec
+INSERT INTO site_configs (key, value) VALUES ('ssh_ca_key', $1)
+ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'ssh_ca_key'
+`
+
+func (q *sqlQuerier) UpsertSSHCAKey(ctx context.Context, value string) error {
+	_, err := q.db.ExecContext(ctx, upsertSSHCAKey, value)
+	return err
+}
+
 const upsertServiceBanner = `-- name: UpsertServiceBanner :exec
 INSERT INTO site_configs (key, value) VALUES ('service_banner', $1)
 ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'service_banner'
@@ -4230,50 +6443,221 @@ DO UPDATE SET
 RETURNING id, coordinator_id, agent_id, updated_at, node
 `
 
-type UpsertTailnetClientParams struct {
-	ID            uuid.UUID       `db:"id" json:"id"`
-	CoordinatorID uuid.UUID       `db:"coordinator_id" json:"coordinator_id"`
-	AgentID       uuid.UUID       `db:"agent_id" json:"agent_id"`
-	Node          json.RawMessage `db:"node" json:"node"`
+type UpsertTailnetClientParams struct {
+	ID            uuid.UUID       `db:"id" json:"id"`
+	CoordinatorID uuid.UUID       `db:"coordinator_id" json:"coordinator_id"`
+	AgentID       uuid.UUID       `db:"agent_id" json:"agent_id"`
+	Node          json.RawMessage `db:"node" json:"node"`
+}
+
+func (q *sqlQuerier) UpsertTailnetClient(ctx context.Context, arg UpsertTailnetClientParams) (TailnetClient, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetClient,
+		arg.ID,
+		arg.CoordinatorID,
+		arg.AgentID,
+		arg.Node,
+	)
+	var i TailnetClient
+	err := row.Scan(
+		&i.ID,
+		&i.CoordinatorID,
+		&i.AgentID,
+		&i.UpdatedAt,
+		&i.Node,
+	)
+	return i, err
+}
+
+const upsertTailnetCoordinator = `-- name: UpsertTailnetCoordinator :one
+INSERT INTO
+	tailnet_coordinators (
+	id,
+	heartbeat_at
+)
+VALUES
+	($1, now() at time zone 'utc')
+ON CONFLICT (id)
+DO UPDATE SET
+  id = $1,
+  heartbeat_at = now() at time zone 'utc'
+RETURNING id, heartbeat_at
+`
+
+func (q *sqlQuerier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (TailnetCoordinator, error) {
+	row := q.db.QueryRowContext(ctx, upsertTailnetCoordinator, id)
+	var i TailnetCoordinator
+	err := row.Scan(&i.ID, &i.HeartbeatAt)
+	return i, err
+}
+
+const getTemplateAccessRequestByID = `-- name: GetTemplateAccessRequestByID :one
+SELECT
+	id, template_id, requested_by, created_at, message, status, decided_by, decided_at, reason
+FROM
+	template_access_requests
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetTemplateAccessRequestByID(ctx context.Context, id uuid.UUID) (TemplateAccessRequest, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateAccessRequestByID, id)
+	var i TemplateAccessRequest
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.Message,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const getTemplateAccessRequestsByTemplateID = `-- name: GetTemplateAccessRequestsByTemplateID :many
+SELECT
+	id, template_id, requested_by, created_at, message, status, decided_by, decided_at, reason
+FROM
+	template_access_requests
+WHERE
+	template_id = $1
+	AND CASE
+		WHEN $2 :: text != '' THEN
+			status = $2 :: template_access_request_status
+		ELSE true
+	END
+ORDER BY
+	created_at DESC
+`
+
+type GetTemplateAccessRequestsByTemplateIDParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	Status     string    `db:"status" json:"status"`
+}
+
+func (q *sqlQuerier) GetTemplateAccessRequestsByTemplateID(ctx context.Context, arg GetTemplateAccessRequestsByTemplateIDParams) ([]TemplateAccessRequest, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateAccessRequestsByTemplateID, arg.TemplateID, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateAccessRequest
+	for rows.Next() {
+		var i TemplateAccessRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.TemplateID,
+			&i.RequestedBy,
+			&i.CreatedAt,
+			&i.Message,
+			&i.Status,
+			&i.DecidedBy,
+			&i.DecidedAt,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateAccessRequest = `-- name: InsertTemplateAccessRequest :one
+INSERT INTO
+	template_access_requests (
+		id,
+		template_id,
+		requested_by,
+		created_at,
+		message
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING id, template_id, requested_by, created_at, message, status, decided_by, decided_at, reason
+`
+
+type InsertTemplateAccessRequestParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	TemplateID  uuid.UUID `db:"template_id" json:"template_id"`
+	RequestedBy uuid.UUID `db:"requested_by" json:"requested_by"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	Message     string    `db:"message" json:"message"`
 }
 
-func (q *sqlQuerier) UpsertTailnetClient(ctx context.Context, arg UpsertTailnetClientParams) (TailnetClient, error) {
-	row := q.db.QueryRowContext(ctx, upsertTailnetClient,
+func (q *sqlQuerier) InsertTemplateAccessRequest(ctx context.Context, arg InsertTemplateAccessRequestParams) (TemplateAccessRequest, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateAccessRequest,
 		arg.ID,
-		arg.CoordinatorID,
-		arg.AgentID,
-		arg.Node,
+		arg.TemplateID,
+		arg.RequestedBy,
+		arg.CreatedAt,
+		arg.Message,
 	)
-	var i TailnetClient
+	var i TemplateAccessRequest
 	err := row.Scan(
 		&i.ID,
-		&i.CoordinatorID,
-		&i.AgentID,
-		&i.UpdatedAt,
-		&i.Node,
+		&i.TemplateID,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.Message,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedAt,
+		&i.Reason,
 	)
 	return i, err
 }
 
-const upsertTailnetCoordinator = `-- name: UpsertTailnetCoordinator :one
-INSERT INTO
-	tailnet_coordinators (
-	id,
-	heartbeat_at
-)
-VALUES
-	($1, now() at time zone 'utc')
-ON CONFLICT (id)
-DO UPDATE SET
-  id = $1,
-  heartbeat_at = now() at time zone 'utc'
-RETURNING id, heartbeat_at
+const updateTemplateAccessRequestStatusByID = `-- name: UpdateTemplateAccessRequestStatusByID :one
+UPDATE
+	template_access_requests
+SET
+	status = $2,
+	decided_by = $3,
+	decided_at = $4,
+	reason = $5
+WHERE
+	id = $1
+	AND status = 'pending'
+RETURNING id, template_id, requested_by, created_at, message, status, decided_by, decided_at, reason
 `
 
-func (q *sqlQuerier) UpsertTailnetCoordinator(ctx context.Context, id uuid.UUID) (TailnetCoordinator, error) {
-	row := q.db.QueryRowContext(ctx, upsertTailnetCoordinator, id)
-	var i TailnetCoordinator
-	err := row.Scan(&i.ID, &i.HeartbeatAt)
+type UpdateTemplateAccessRequestStatusByIDParams struct {
+	ID        uuid.UUID                   `db:"id" json:"id"`
+	Status    TemplateAccessRequestStatus `db:"status" json:"status"`
+	DecidedBy uuid.NullUUID               `db:"decided_by" json:"decided_by"`
+	DecidedAt sql.NullTime                `db:"decided_at" json:"decided_at"`
+	Reason    string                      `db:"reason" json:"reason"`
+}
+
+// Records the decision on a pending access request. Requests can only be
+// decided once, so no rows are returned if the request was already decided.
+func (q *sqlQuerier) UpdateTemplateAccessRequestStatusByID(ctx context.Context, arg UpdateTemplateAccessRequestStatusByIDParams) (TemplateAccessRequest, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplateAccessRequestStatusByID,
+		arg.ID,
+		arg.Status,
+		arg.DecidedBy,
+		arg.DecidedAt,
+		arg.Reason,
+	)
+	var i TemplateAccessRequest
+	err := row.Scan(
+		&i.ID,
+		&i.TemplateID,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.Message,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedAt,
+		&i.Reason,
+	)
 	return i, err
 }
 
@@ -4339,7 +6723,7 @@ func (q *sqlQuerier) GetTemplateAverageBuildTime(ctx context.Context, arg GetTem
 
 const getTemplateByID = `-- name: GetTemplateByID :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, restart_requirement_days_of_week, restart_requirement_weeks, created_by_avatar_url, created_by_username
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, restart_requirement_days_of_week, restart_requirement_weeks, require_active_version, require_active_version_grace_period, active_version_updated_at, max_build_duration, provisioner_memory_limit, provisioner_cpu_limit, require_workspace_approval, require_agent_binary_verification, autostop_activity_sources, require_promotion_approval, max_concurrent_provisioner_jobs, max_session_idle, port_policy, created_by_avatar_url, created_by_username
 FROM
 	template_with_users
 WHERE
@@ -4376,6 +6760,19 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 		&i.LockedTTL,
 		&i.RestartRequirementDaysOfWeek,
 		&i.RestartRequirementWeeks,
+		&i.RequireActiveVersion,
+		&i.RequireActiveVersionGracePeriod,
+		&i.ActiveVersionUpdatedAt,
+		&i.MaxBuildDuration,
+		&i.ProvisionerMemoryLimit,
+		&i.ProvisionerCPULimit,
+		&i.RequireWorkspaceApproval,
+		&i.RequireAgentBinaryVerification,
+		pq.Array(&i.AutostopActivitySources),
+		&i.RequirePromotionApproval,
+		&i.MaxConcurrentProvisionerJobs,
+		&i.MaxSessionIdle,
+		&i.PortPolicy,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
@@ -4384,7 +6781,7 @@ func (q *sqlQuerier) GetTemplateByID(ctx context.Context, id uuid.UUID) (Templat
 
 const getTemplateByOrganizationAndName = `-- name: GetTemplateByOrganizationAndName :one
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, restart_requirement_days_of_week, restart_requirement_weeks, created_by_avatar_url, created_by_username
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, restart_requirement_days_of_week, restart_requirement_weeks, require_active_version, require_active_version_grace_period, active_version_updated_at, max_build_duration, provisioner_memory_limit, provisioner_cpu_limit, require_workspace_approval, require_agent_binary_verification, autostop_activity_sources, require_promotion_approval, max_concurrent_provisioner_jobs, max_session_idle, port_policy, created_by_avatar_url, created_by_username
 FROM
 	template_with_users AS templates
 WHERE
@@ -4429,6 +6826,19 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 		&i.LockedTTL,
 		&i.RestartRequirementDaysOfWeek,
 		&i.RestartRequirementWeeks,
+		&i.RequireActiveVersion,
+		&i.RequireActiveVersionGracePeriod,
+		&i.ActiveVersionUpdatedAt,
+		&i.MaxBuildDuration,
+		&i.ProvisionerMemoryLimit,
+		&i.ProvisionerCPULimit,
+		&i.RequireWorkspaceApproval,
+		&i.RequireAgentBinaryVerification,
+		pq.Array(&i.AutostopActivitySources),
+		&i.RequirePromotionApproval,
+		&i.MaxConcurrentProvisionerJobs,
+		&i.MaxSessionIdle,
+		&i.PortPolicy,
 		&i.CreatedByAvatarURL,
 		&i.CreatedByUsername,
 	)
@@ -4436,7 +6846,7 @@ func (q *sqlQuerier) GetTemplateByOrganizationAndName(ctx context.Context, arg G
 }
 
 const getTemplates = `-- name: GetTemplates :many
-SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, restart_requirement_days_of_week, restart_requirement_weeks, created_by_avatar_url, created_by_username FROM template_with_users AS templates
+SELECT id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, restart_requirement_days_of_week, restart_requirement_weeks, require_active_version, require_active_version_grace_period, active_version_updated_at, max_build_duration, provisioner_memory_limit, provisioner_cpu_limit, require_workspace_approval, require_agent_binary_verification, autostop_activity_sources, require_promotion_approval, max_concurrent_provisioner_jobs, max_session_idle, port_policy, created_by_avatar_url, created_by_username FROM template_with_users AS templates
 ORDER BY (name, id) ASC
 `
 
@@ -4474,6 +6884,19 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 			&i.LockedTTL,
 			&i.RestartRequirementDaysOfWeek,
 			&i.RestartRequirementWeeks,
+			&i.RequireActiveVersion,
+			&i.RequireActiveVersionGracePeriod,
+			&i.ActiveVersionUpdatedAt,
+			&i.MaxBuildDuration,
+			&i.ProvisionerMemoryLimit,
+			&i.ProvisionerCPULimit,
+			&i.RequireWorkspaceApproval,
+			&i.RequireAgentBinaryVerification,
+			pq.Array(&i.AutostopActivitySources),
+			&i.RequirePromotionApproval,
+			&i.MaxConcurrentProvisionerJobs,
+			&i.MaxSessionIdle,
+			&i.PortPolicy,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -4492,7 +6915,7 @@ func (q *sqlQuerier) GetTemplates(ctx context.Context) ([]Template, error) {
 
 const getTemplatesWithFilter = `-- name: GetTemplatesWithFilter :many
 SELECT
-	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, restart_requirement_days_of_week, restart_requirement_weeks, created_by_avatar_url, created_by_username
+	id, created_at, updated_at, organization_id, deleted, name, provisioner, active_version_id, description, default_ttl, created_by, icon, user_acl, group_acl, display_name, allow_user_cancel_workspace_jobs, max_ttl, allow_user_autostart, allow_user_autostop, failure_ttl, inactivity_ttl, locked_ttl, restart_requirement_days_of_week, restart_requirement_weeks, require_active_version, require_active_version_grace_period, active_version_updated_at, max_build_duration, provisioner_memory_limit, provisioner_cpu_limit, require_workspace_approval, require_agent_binary_verification, autostop_activity_sources, require_promotion_approval, max_concurrent_provisioner_jobs, max_session_idle, port_policy, created_by_avatar_url, created_by_username
 FROM
 	template_with_users AS templates
 WHERE
@@ -4567,6 +6990,19 @@ func (q *sqlQuerier) GetTemplatesWithFilter(ctx context.Context, arg GetTemplate
 			&i.LockedTTL,
 			&i.RestartRequirementDaysOfWeek,
 			&i.RestartRequirementWeeks,
+			&i.RequireActiveVersion,
+			&i.RequireActiveVersionGracePeriod,
+			&i.ActiveVersionUpdatedAt,
+			&i.MaxBuildDuration,
+			&i.ProvisionerMemoryLimit,
+			&i.ProvisionerCPULimit,
+			&i.RequireWorkspaceApproval,
+			&i.RequireAgentBinaryVerification,
+			pq.Array(&i.AutostopActivitySources),
+			&i.RequirePromotionApproval,
+			&i.MaxConcurrentProvisionerJobs,
+			&i.MaxSessionIdle,
+			&i.PortPolicy,
 			&i.CreatedByAvatarURL,
 			&i.CreatedByUsername,
 		); err != nil {
@@ -4668,7 +7104,8 @@ UPDATE
 	templates
 SET
 	active_version_id = $2,
-	updated_at = $3
+	updated_at = $3,
+	active_version_updated_at = $3
 WHERE
 	id = $1
 `
@@ -4714,19 +7151,41 @@ SET
 	name = $4,
 	icon = $5,
 	display_name = $6,
-	allow_user_cancel_workspace_jobs = $7
+	allow_user_cancel_workspace_jobs = $7,
+	require_active_version = $8,
+	require_active_version_grace_period = $9,
+	max_build_duration = $10,
+	provisioner_memory_limit = $11,
+	provisioner_cpu_limit = $12,
+	require_workspace_approval = $13,
+	require_agent_binary_verification = $14,
+	require_promotion_approval = $15,
+	max_concurrent_provisioner_jobs = $16,
+	max_session_idle = $17,
+	port_policy = $18
 WHERE
 	id = $1
 `
 
 type UpdateTemplateMetaByIDParams struct {
-	ID                           uuid.UUID `db:"id" json:"id"`
-	UpdatedAt                    time.Time `db:"updated_at" json:"updated_at"`
-	Description                  string    `db:"description" json:"description"`
-	Name                         string    `db:"name" json:"name"`
-	Icon                         string    `db:"icon" json:"icon"`
-	DisplayName                  string    `db:"display_name" json:"display_name"`
-	AllowUserCancelWorkspaceJobs bool      `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	ID                              uuid.UUID       `db:"id" json:"id"`
+	UpdatedAt                       time.Time       `db:"updated_at" json:"updated_at"`
+	Description                     string          `db:"description" json:"description"`
+	Name                            string          `db:"name" json:"name"`
+	Icon                            string          `db:"icon" json:"icon"`
+	DisplayName                     string          `db:"display_name" json:"display_name"`
+	AllowUserCancelWorkspaceJobs    bool            `db:"allow_user_cancel_workspace_jobs" json:"allow_user_cancel_workspace_jobs"`
+	RequireActiveVersion            bool            `db:"require_active_version" json:"require_active_version"`
+	RequireActiveVersionGracePeriod int64           `db:"require_active_version_grace_period" json:"require_active_version_grace_period"`
+	MaxBuildDuration                int64           `db:"max_build_duration" json:"max_build_duration"`
+	ProvisionerMemoryLimit          int64           `db:"provisioner_memory_limit" json:"provisioner_memory_limit"`
+	ProvisionerCPULimit             int64           `db:"provisioner_cpu_limit" json:"provisioner_cpu_limit"`
+	RequireWorkspaceApproval        bool            `db:"require_workspace_approval" json:"require_workspace_approval"`
+	RequireAgentBinaryVerification  bool            `db:"require_agent_binary_verification" json:"require_agent_binary_verification"`
+	RequirePromotionApproval        bool            `db:"require_promotion_approval" json:"require_promotion_approval"`
+	MaxConcurrentProvisionerJobs    int32           `db:"max_concurrent_provisioner_jobs" json:"max_concurrent_provisioner_jobs"`
+	MaxSessionIdle                  int64           `db:"max_session_idle" json:"max_session_idle"`
+	PortPolicy                      json.RawMessage `db:"port_policy" json:"port_policy"`
 }
 
 func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTemplateMetaByIDParams) error {
@@ -4738,6 +7197,17 @@ func (q *sqlQuerier) UpdateTemplateMetaByID(ctx context.Context, arg UpdateTempl
 		arg.Icon,
 		arg.DisplayName,
 		arg.AllowUserCancelWorkspaceJobs,
+		arg.RequireActiveVersion,
+		arg.RequireActiveVersionGracePeriod,
+		arg.MaxBuildDuration,
+		arg.ProvisionerMemoryLimit,
+		arg.ProvisionerCPULimit,
+		arg.RequireWorkspaceApproval,
+		arg.RequireAgentBinaryVerification,
+		arg.RequirePromotionApproval,
+		arg.MaxConcurrentProvisionerJobs,
+		arg.MaxSessionIdle,
+		arg.PortPolicy,
 	)
 	return err
 }
@@ -4755,7 +7225,8 @@ SET
 	restart_requirement_weeks = $8,
 	failure_ttl = $9,
 	inactivity_ttl = $10,
-	locked_ttl = $11
+	locked_ttl = $11,
+	autostop_activity_sources = $12
 WHERE
 	id = $1
 `
@@ -4772,6 +7243,7 @@ type UpdateTemplateScheduleByIDParams struct {
 	FailureTTL                   int64     `db:"failure_ttl" json:"failure_ttl"`
 	InactivityTTL                int64     `db:"inactivity_ttl" json:"inactivity_ttl"`
 	LockedTTL                    int64     `db:"locked_ttl" json:"locked_ttl"`
+	AutostopActivitySources      []string  `db:"autostop_activity_sources" json:"autostop_activity_sources"`
 }
 
 func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateTemplateScheduleByIDParams) error {
@@ -4787,12 +7259,155 @@ func (q *sqlQuerier) UpdateTemplateScheduleByID(ctx context.Context, arg UpdateT
 		arg.FailureTTL,
 		arg.InactivityTTL,
 		arg.LockedTTL,
+		pq.Array(arg.AutostopActivitySources),
+	)
+	return err
+}
+
+const getTemplateVersionBuildInsights = `-- name: GetTemplateVersionBuildInsights :many
+SELECT
+	tvbi.template_version_id,
+	tv.name AS template_version_name,
+	tv.created_at AS template_version_created_at,
+	tvbi.total_builds,
+	tvbi.failed_builds,
+	tvbi.failures,
+	tvbi.duration_seconds_50,
+	tvbi.duration_seconds_95,
+	tvbi.updated_at
+FROM template_version_build_insights tvbi
+JOIN template_versions tv ON (tv.id = tvbi.template_version_id)
+WHERE tvbi.template_id = $1
+ORDER BY tv.created_at DESC, tv.name ASC
+`
+
+type GetTemplateVersionBuildInsightsRow struct {
+	TemplateVersionID        uuid.UUID       `db:"template_version_id" json:"template_version_id"`
+	TemplateVersionName      string          `db:"template_version_name" json:"template_version_name"`
+	TemplateVersionCreatedAt time.Time       `db:"template_version_created_at" json:"template_version_created_at"`
+	TotalBuilds              int64           `db:"total_builds" json:"total_builds"`
+	FailedBuilds             int64           `db:"failed_builds" json:"failed_builds"`
+	Failures                 json.RawMessage `db:"failures" json:"failures"`
+	DurationSeconds50        float64         `db:"duration_seconds_50" json:"duration_seconds_50"`
+	DurationSeconds95        float64         `db:"duration_seconds_95" json:"duration_seconds_95"`
+	UpdatedAt                time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// GetTemplateVersionBuildInsights returns the build rollup of the versions of a
+// template that have completed builds, newest version first.
+func (q *sqlQuerier) GetTemplateVersionBuildInsights(ctx context.Context, templateID uuid.UUID) ([]GetTemplateVersionBuildInsightsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionBuildInsights, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTemplateVersionBuildInsightsRow
+	for rows.Next() {
+		var i GetTemplateVersionBuildInsightsRow
+		if err := rows.Scan(
+			&i.TemplateVersionID,
+			&i.TemplateVersionName,
+			&i.TemplateVersionCreatedAt,
+			&i.TotalBuilds,
+			&i.FailedBuilds,
+			&i.Failures,
+			&i.DurationSeconds50,
+			&i.DurationSeconds95,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertTemplateVersionBuildInsights = `-- name: UpsertTemplateVersionBuildInsights :exec
+WITH versions AS (
+	SELECT DISTINCT wb.template_version_id
+	FROM workspace_builds wb
+	JOIN provisioner_jobs pj ON (pj.id = wb.job_id)
+	WHERE pj.completed_at >= $1::timestamptz
+), builds AS (
+	SELECT
+		wb.template_version_id,
+		tv.template_id,
+		(pj.error IS NOT NULL AND pj.error != '') AS failed,
+		COALESCE(pj.error_code, '') AS error_code,
+		EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at)) AS duration_seconds
+	FROM workspace_builds wb
+	JOIN provisioner_jobs pj ON (pj.id = wb.job_id)
+	JOIN template_versions tv ON (tv.id = wb.template_version_id)
+	WHERE
+		wb.template_version_id IN (SELECT template_version_id FROM versions)
+		AND tv.template_id IS NOT NULL
+		AND pj.completed_at IS NOT NULL
+		AND pj.started_at IS NOT NULL
+		AND pj.canceled_at IS NULL
+), failures AS (
+	SELECT template_version_id, jsonb_object_agg(error_code, builds) AS failures
+	FROM (
+		SELECT template_version_id, error_code, COUNT(*) AS builds
+		FROM builds
+		WHERE failed
+		GROUP BY template_version_id, error_code
+	) f
+	GROUP BY template_version_id
+)
+INSERT INTO
+	template_version_build_insights (
+		template_version_id,
+		template_id,
+		total_builds,
+		failed_builds,
+		failures,
+		duration_seconds_50,
+		duration_seconds_95,
+		updated_at
 	)
+SELECT
+	b.template_version_id,
+	b.template_id,
+	COUNT(*)::bigint,
+	COUNT(*) FILTER (WHERE b.failed)::bigint,
+	COALESCE(f.failures, '{}'::jsonb),
+	coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY b.duration_seconds) FILTER (WHERE NOT b.failed)), -1)::FLOAT,
+	coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY b.duration_seconds) FILTER (WHERE NOT b.failed)), -1)::FLOAT,
+	$2::timestamptz
+FROM builds b
+LEFT JOIN failures f ON (f.template_version_id = b.template_version_id)
+GROUP BY b.template_version_id, b.template_id, f.failures
+ON CONFLICT (template_version_id) DO UPDATE SET
+	total_builds = EXCLUDED.total_builds,
+	failed_builds = EXCLUDED.failed_builds,
+	failures = EXCLUDED.failures,
+	duration_seconds_50 = EXCLUDED.duration_seconds_50,
+	duration_seconds_95 = EXCLUDED.duration_seconds_95,
+	updated_at = EXCLUDED.updated_at
+`
+
+type UpsertTemplateVersionBuildInsightsParams struct {
+	Since     time.Time `db:"since" json:"since"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// UpsertTemplateVersionBuildInsights recomputes the build rollup of every
+// template version that had a workspace build complete since the given time.
+// Canceled builds are excluded as they say nothing about the health of the
+// template version.
+func (q *sqlQuerier) UpsertTemplateVersionBuildInsights(ctx context.Context, arg UpsertTemplateVersionBuildInsightsParams) error {
+	_, err := q.db.ExecContext(ctx, upsertTemplateVersionBuildInsights, arg.Since, arg.UpdatedAt)
 	return err
 }
 
 const getTemplateVersionParameters = `-- name: GetTemplateVersionParameters :many
-SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
+SELECT template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, dependencies, options_url FROM template_version_parameters WHERE template_version_id = $1 ORDER BY display_order ASC, LOWER(name) ASC
 `
 
 func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateVersionID uuid.UUID) ([]TemplateVersionParameter, error) {
@@ -4822,6 +7437,8 @@ func (q *sqlQuerier) GetTemplateVersionParameters(ctx context.Context, templateV
 			&i.DisplayName,
 			&i.DisplayOrder,
 			&i.Ephemeral,
+			&i.Dependencies,
+			&i.OptionsURL,
 		); err != nil {
 			return nil, err
 		}
@@ -4855,7 +7472,9 @@ INSERT INTO
         required,
         display_name,
         display_order,
-        ephemeral
+        ephemeral,
+        dependencies,
+        options_url
     )
 VALUES
     (
@@ -4875,8 +7494,10 @@ VALUES
         $14,
         $15,
         $16,
-        $17
-    ) RETURNING template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral
+        $17,
+        $18,
+        $19
+    ) RETURNING template_version_id, name, description, type, mutable, default_value, icon, options, validation_regex, validation_min, validation_max, validation_error, validation_monotonic, required, display_name, display_order, ephemeral, dependencies, options_url
 `
 
 type InsertTemplateVersionParameterParams struct {
@@ -4897,47 +7518,242 @@ type InsertTemplateVersionParameterParams struct {
 	DisplayName         string          `db:"display_name" json:"display_name"`
 	DisplayOrder        int32           `db:"display_order" json:"display_order"`
 	Ephemeral           bool            `db:"ephemeral" json:"ephemeral"`
+	Dependencies        json.RawMessage `db:"dependencies" json:"dependencies"`
+	OptionsURL          string          `db:"options_url" json:"options_url"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionParameter,
+		arg.TemplateVersionID,
+		arg.Name,
+		arg.Description,
+		arg.Type,
+		arg.Mutable,
+		arg.DefaultValue,
+		arg.Icon,
+		arg.Options,
+		arg.ValidationRegex,
+		arg.ValidationMin,
+		arg.ValidationMax,
+		arg.ValidationError,
+		arg.ValidationMonotonic,
+		arg.Required,
+		arg.DisplayName,
+		arg.DisplayOrder,
+		arg.Ephemeral,
+		arg.Dependencies,
+		arg.OptionsURL,
+	)
+	var i TemplateVersionParameter
+	err := row.Scan(
+		&i.TemplateVersionID,
+		&i.Name,
+		&i.Description,
+		&i.Type,
+		&i.Mutable,
+		&i.DefaultValue,
+		&i.Icon,
+		&i.Options,
+		&i.ValidationRegex,
+		&i.ValidationMin,
+		&i.ValidationMax,
+		&i.ValidationError,
+		&i.ValidationMonotonic,
+		&i.Required,
+		&i.DisplayName,
+		&i.DisplayOrder,
+		&i.Ephemeral,
+		&i.Dependencies,
+		&i.OptionsURL,
+	)
+	return i, err
+}
+
+const getTemplateVersionPromotionByID = `-- name: GetTemplateVersionPromotionByID :one
+SELECT
+	id, organization_id, template_id, template_version_id, requested_by, created_at, message, status, decided_by, decided_at, reason
+FROM
+	template_version_promotions
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetTemplateVersionPromotionByID(ctx context.Context, id uuid.UUID) (TemplateVersionPromotion, error) {
+	row := q.db.QueryRowContext(ctx, getTemplateVersionPromotionByID, id)
+	var i TemplateVersionPromotion
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.Message,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const getTemplateVersionPromotions = `-- name: GetTemplateVersionPromotions :many
+SELECT
+	id, organization_id, template_id, template_version_id, requested_by, created_at, message, status, decided_by, decided_at, reason
+FROM
+	template_version_promotions
+WHERE
+	CASE
+		WHEN $1 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			template_id = $1
+		ELSE true
+	END
+	AND CASE
+		WHEN $2 :: text != '' THEN
+			status = $2 :: template_version_promotion_status
+		ELSE true
+	END
+ORDER BY
+	created_at DESC
+`
+
+type GetTemplateVersionPromotionsParams struct {
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	Status     string    `db:"status" json:"status"`
+}
+
+func (q *sqlQuerier) GetTemplateVersionPromotions(ctx context.Context, arg GetTemplateVersionPromotionsParams) ([]TemplateVersionPromotion, error) {
+	rows, err := q.db.QueryContext(ctx, getTemplateVersionPromotions, arg.TemplateID, arg.Status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TemplateVersionPromotion
+	for rows.Next() {
+		var i TemplateVersionPromotion
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.TemplateID,
+			&i.TemplateVersionID,
+			&i.RequestedBy,
+			&i.CreatedAt,
+			&i.Message,
+			&i.Status,
+			&i.DecidedBy,
+			&i.DecidedAt,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertTemplateVersionPromotion = `-- name: InsertTemplateVersionPromotion :one
+INSERT INTO
+	template_version_promotions (
+		id,
+		organization_id,
+		template_id,
+		template_version_id,
+		requested_by,
+		created_at,
+		message
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, organization_id, template_id, template_version_id, requested_by, created_at, message, status, decided_by, decided_at, reason
+`
+
+type InsertTemplateVersionPromotionParams struct {
+	ID                uuid.UUID `db:"id" json:"id"`
+	OrganizationID    uuid.UUID `db:"organization_id" json:"organization_id"`
+	TemplateID        uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateVersionID uuid.UUID `db:"template_version_id" json:"template_version_id"`
+	RequestedBy       uuid.UUID `db:"requested_by" json:"requested_by"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	Message           string    `db:"message" json:"message"`
+}
+
+func (q *sqlQuerier) InsertTemplateVersionPromotion(ctx context.Context, arg InsertTemplateVersionPromotionParams) (TemplateVersionPromotion, error) {
+	row := q.db.QueryRowContext(ctx, insertTemplateVersionPromotion,
+		arg.ID,
+		arg.OrganizationID,
+		arg.TemplateID,
+		arg.TemplateVersionID,
+		arg.RequestedBy,
+		arg.CreatedAt,
+		arg.Message,
+	)
+	var i TemplateVersionPromotion
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.TemplateVersionID,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.Message,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const updateTemplateVersionPromotionStatusByID = `-- name: UpdateTemplateVersionPromotionStatusByID :one
+UPDATE
+	template_version_promotions
+SET
+	status = $2,
+	decided_by = $3,
+	decided_at = $4,
+	reason = $5
+WHERE
+	id = $1
+	AND status = 'pending'
+RETURNING id, organization_id, template_id, template_version_id, requested_by, created_at, message, status, decided_by, decided_at, reason
+`
+
+type UpdateTemplateVersionPromotionStatusByIDParams struct {
+	ID        uuid.UUID                      `db:"id" json:"id"`
+	Status    TemplateVersionPromotionStatus `db:"status" json:"status"`
+	DecidedBy uuid.NullUUID                  `db:"decided_by" json:"decided_by"`
+	DecidedAt sql.NullTime                   `db:"decided_at" json:"decided_at"`
+	Reason    string                         `db:"reason" json:"reason"`
 }
 
-func (q *sqlQuerier) InsertTemplateVersionParameter(ctx context.Context, arg InsertTemplateVersionParameterParams) (TemplateVersionParameter, error) {
-	row := q.db.QueryRowContext(ctx, insertTemplateVersionParameter,
-		arg.TemplateVersionID,
-		arg.Name,
-		arg.Description,
-		arg.Type,
-		arg.Mutable,
-		arg.DefaultValue,
-		arg.Icon,
-		arg.Options,
-		arg.ValidationRegex,
-		arg.ValidationMin,
-		arg.ValidationMax,
-		arg.ValidationError,
-		arg.ValidationMonotonic,
-		arg.Required,
-		arg.DisplayName,
-		arg.DisplayOrder,
-		arg.Ephemeral,
+// Records the decision on a pending promotion. Promotions can only be decided
+// once, so no rows are returned if the promotion was already decided.
+func (q *sqlQuerier) UpdateTemplateVersionPromotionStatusByID(ctx context.Context, arg UpdateTemplateVersionPromotionStatusByIDParams) (TemplateVersionPromotion, error) {
+	row := q.db.QueryRowContext(ctx, updateTemplateVersionPromotionStatusByID,
+		arg.ID,
+		arg.Status,
+		arg.DecidedBy,
+		arg.DecidedAt,
+		arg.Reason,
 	)
-	var i TemplateVersionParameter
+	var i TemplateVersionPromotion
 	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.TemplateID,
 		&i.TemplateVersionID,
-		&i.Name,
-		&i.Description,
-		&i.Type,
-		&i.Mutable,
-		&i.DefaultValue,
-		&i.Icon,
-		&i.Options,
-		&i.ValidationRegex,
-		&i.ValidationMin,
-		&i.ValidationMax,
-		&i.ValidationError,
-		&i.ValidationMonotonic,
-		&i.Required,
-		&i.DisplayName,
-		&i.DisplayOrder,
-		&i.Ephemeral,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.Message,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedAt,
+		&i.Reason,
 	)
 	return i, err
 }
@@ -5633,6 +8449,152 @@ func (q *sqlQuerier) UpdateUserLinkedID(ctx context.Context, arg UpdateUserLinke
 	return i, err
 }
 
+const deleteUserDeprovision = `-- name: DeleteUserDeprovision :exec
+DELETE FROM
+	user_deprovisions
+WHERE
+	user_id = $1
+`
+
+func (q *sqlQuerier) DeleteUserDeprovision(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserDeprovision, userID)
+	return err
+}
+
+const getUserDeprovisions = `-- name: GetUserDeprovisions :many
+SELECT
+	user_id, deprovisioned_at
+FROM
+	user_deprovisions
+ORDER BY
+	deprovisioned_at ASC
+`
+
+func (q *sqlQuerier) GetUserDeprovisions(ctx context.Context) ([]UserDeprovision, error) {
+	rows, err := q.db.QueryContext(ctx, getUserDeprovisions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserDeprovision
+	for rows.Next() {
+		var i UserDeprovision
+		if err := rows.Scan(&i.UserID, &i.DeprovisionedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertUserDeprovision = `-- name: InsertUserDeprovision :exec
+INSERT INTO
+	user_deprovisions (
+		user_id,
+		deprovisioned_at
+	)
+VALUES
+	($1, $2)
+ON CONFLICT (user_id)
+DO NOTHING
+`
+
+type InsertUserDeprovisionParams struct {
+	UserID          uuid.UUID `db:"user_id" json:"user_id"`
+	DeprovisionedAt time.Time `db:"deprovisioned_at" json:"deprovisioned_at"`
+}
+
+// Keeps the original deprovisioning time if the identity provider
+// deactivates a user more than once.
+func (q *sqlQuerier) InsertUserDeprovision(ctx context.Context, arg InsertUserDeprovisionParams) error {
+	_, err := q.db.ExecContext(ctx, insertUserDeprovision, arg.UserID, arg.DeprovisionedAt)
+	return err
+}
+
+const getUserRegionLatencies = `-- name: GetUserRegionLatencies :many
+SELECT
+	user_id, region_id, latency_ms, updated_at
+FROM
+	user_region_latencies
+WHERE
+	user_id = $1 AND
+	updated_at > $2
+ORDER BY
+	latency_ms ASC
+`
+
+type GetUserRegionLatenciesParams struct {
+	UserID       uuid.UUID `db:"user_id" json:"user_id"`
+	UpdatedAfter time.Time `db:"updated_after" json:"updated_after"`
+}
+
+func (q *sqlQuerier) GetUserRegionLatencies(ctx context.Context, arg GetUserRegionLatenciesParams) ([]UserRegionLatency, error) {
+	rows, err := q.db.QueryContext(ctx, getUserRegionLatencies, arg.UserID, arg.UpdatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserRegionLatency
+	for rows.Next() {
+		var i UserRegionLatency
+		if err := rows.Scan(
+			&i.UserID,
+			&i.RegionID,
+			&i.LatencyMS,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertUserRegionLatency = `-- name: UpsertUserRegionLatency :exec
+INSERT INTO
+	user_region_latencies (
+		user_id,
+		region_id,
+		latency_ms,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (user_id, region_id)
+DO UPDATE SET
+	latency_ms = $3,
+	updated_at = $4
+`
+
+type UpsertUserRegionLatencyParams struct {
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	RegionID  uuid.UUID `db:"region_id" json:"region_id"`
+	LatencyMS float64   `db:"latency_ms" json:"latency_ms"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertUserRegionLatency(ctx context.Context, arg UpsertUserRegionLatencyParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserRegionLatency,
+		arg.UserID,
+		arg.RegionID,
+		arg.LatencyMS,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
 const getActiveUserCount = `-- name: GetActiveUserCount :one
 SELECT
 	COUNT(*)
@@ -6369,6 +9331,91 @@ func (q *sqlQuerier) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusP
 	return i, err
 }
 
+const deleteOldWorkspaceAgentAuthTokenRotations = `-- name: DeleteOldWorkspaceAgentAuthTokenRotations :exec
+DELETE FROM workspace_agent_auth_token_rotations WHERE previous_auth_token_expires_at < NOW() - INTERVAL '30 days'
+`
+
+// Rotations are only needed to accept the previous token, the audit log
+// keeps their history.
+func (q *sqlQuerier) DeleteOldWorkspaceAgentAuthTokenRotations(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentAuthTokenRotations)
+	return err
+}
+
+const expireWorkspaceAgentAuthTokenRotations = `-- name: ExpireWorkspaceAgentAuthTokenRotations :exec
+UPDATE
+	workspace_agent_auth_token_rotations
+SET
+	previous_auth_token_expires_at = $1
+WHERE
+	agent_id = $2
+	AND previous_auth_token_expires_at > $1
+`
+
+type ExpireWorkspaceAgentAuthTokenRotationsParams struct {
+	Now     time.Time `db:"now" json:"now"`
+	AgentID uuid.UUID `db:"agent_id" json:"agent_id"`
+}
+
+// Expires the previous auth tokens of the agent that are still valid.
+func (q *sqlQuerier) ExpireWorkspaceAgentAuthTokenRotations(ctx context.Context, arg ExpireWorkspaceAgentAuthTokenRotationsParams) error {
+	_, err := q.db.ExecContext(ctx, expireWorkspaceAgentAuthTokenRotations, arg.Now, arg.AgentID)
+	return err
+}
+
+const insertWorkspaceAgentAuthTokenRotation = `-- name: InsertWorkspaceAgentAuthTokenRotation :one
+INSERT INTO
+	workspace_agent_auth_token_rotations (
+		id,
+		agent_id,
+		previous_auth_token,
+		previous_auth_token_expires_at,
+		revoked,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, agent_id, previous_auth_token, previous_auth_token_expires_at, revoked, created_at
+`
+
+type InsertWorkspaceAgentAuthTokenRotationParams struct {
+	ID                         uuid.UUID `db:"id" json:"id"`
+	AgentID                    uuid.UUID `db:"agent_id" json:"agent_id"`
+	PreviousAuthToken          uuid.UUID `db:"previous_auth_token" json:"previous_auth_token"`
+	PreviousAuthTokenExpiresAt time.Time `db:"previous_auth_token_expires_at" json:"previous_auth_token_expires_at"`
+	Revoked                    bool      `db:"revoked" json:"revoked"`
+	CreatedAt                  time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentAuthTokenRotation(ctx context.Context, arg InsertWorkspaceAgentAuthTokenRotationParams) (WorkspaceAgentAuthTokenRotation, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentAuthTokenRotation,
+		arg.ID,
+		arg.AgentID,
+		arg.PreviousAuthToken,
+		arg.PreviousAuthTokenExpiresAt,
+		arg.Revoked,
+		arg.CreatedAt,
+	)
+	var i WorkspaceAgentAuthTokenRotation
+	err := row.Scan(
+		&i.ID,
+		&i.AgentID,
+		&i.PreviousAuthToken,
+		&i.PreviousAuthTokenExpiresAt,
+		&i.Revoked,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteOldWorkspaceAgentClientConnections = `-- name: DeleteOldWorkspaceAgentClientConnections :exec
+DELETE FROM workspace_agent_client_connections WHERE connected_at < NOW() - INTERVAL '30 days'
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceAgentClientConnections(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentClientConnections)
+	return err
+}
+
 const deleteOldWorkspaceAgentLogs = `-- name: DeleteOldWorkspaceAgentLogs :exec
 DELETE FROM workspace_agent_logs WHERE agent_id IN
 	(SELECT id FROM workspace_agents WHERE last_connected_at IS NOT NULL
@@ -6384,7 +9431,7 @@ func (q *sqlQuerier) DeleteOldWorkspaceAgentLogs(ctx context.Context) error {
 
 const getWorkspaceAgentAndOwnerByAuthToken = `-- name: GetWorkspaceAgentAndOwnerByAuthToken :one
 SELECT
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.startup_script, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.startup_script_timeout_seconds, workspace_agents.expanded_directory, workspace_agents.shutdown_script, workspace_agents.shutdown_script_timeout_seconds, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.startup_script_behavior, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems,
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.startup_script, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.startup_script_timeout_seconds, workspace_agents.expanded_directory, workspace_agents.shutdown_script, workspace_agents.shutdown_script_timeout_seconds, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.startup_script_behavior, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_order, workspace_agents.host_facts, workspace_agents.clock_offset_ms,
 	workspaces.id AS workspace_id,
 	users.id AS owner_id,
 	users.username AS owner_name,
@@ -6425,6 +9472,17 @@ WHERE
 	-- 2) The user must not be deleted
 	-- 3) The workspace must be running
 	workspace_agents.auth_token = $1
+	-- Rotated tokens stay valid until they expire, so requests of the agent
+	-- in flight during the rotation succeed.
+	OR workspace_agents.id IN (
+		SELECT
+			agent_id
+		FROM
+			workspace_agent_auth_token_rotations
+		WHERE
+			previous_auth_token = $1
+			AND previous_auth_token_expires_at > NOW()
+	)
 GROUP BY
 	workspace_agents.id,
 	workspaces.id,
@@ -6483,6 +9541,9 @@ func (q *sqlQuerier) GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, a
 		&i.WorkspaceAgent.StartedAt,
 		&i.WorkspaceAgent.ReadyAt,
 		pq.Array(&i.WorkspaceAgent.Subsystems),
+		&i.WorkspaceAgent.DisplayOrder,
+		&i.WorkspaceAgent.HostFacts,
+		&i.WorkspaceAgent.ClockOffsetMS,
 		&i.WorkspaceID,
 		&i.OwnerID,
 		&i.OwnerName,
@@ -6495,7 +9556,7 @@ func (q *sqlQuerier) GetWorkspaceAgentAndOwnerByAuthToken(ctx context.Context, a
 
 const getWorkspaceAgentByID = `-- name: GetWorkspaceAgentByID :one
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_order, host_facts, clock_offset_ms
 FROM
 	workspace_agents
 WHERE
@@ -6539,13 +9600,16 @@ func (q *sqlQuerier) GetWorkspaceAgentByID(ctx context.Context, id uuid.UUID) (W
 		&i.StartedAt,
 		&i.ReadyAt,
 		pq.Array(&i.Subsystems),
+		&i.DisplayOrder,
+		&i.HostFacts,
+		&i.ClockOffsetMS,
 	)
 	return i, err
 }
 
 const getWorkspaceAgentByInstanceID = `-- name: GetWorkspaceAgentByInstanceID :one
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_order, host_facts, clock_offset_ms
 FROM
 	workspace_agents
 WHERE
@@ -6591,10 +9655,154 @@ func (q *sqlQuerier) GetWorkspaceAgentByInstanceID(ctx context.Context, authInst
 		&i.StartedAt,
 		&i.ReadyAt,
 		pq.Array(&i.Subsystems),
+		&i.DisplayOrder,
+		&i.HostFacts,
+		&i.ClockOffsetMS,
 	)
 	return i, err
 }
 
+const getWorkspaceAgentClientConnections = `-- name: GetWorkspaceAgentClientConnections :many
+SELECT
+	id, workspace_agent_id, user_id, client_type, client_version, connected_at, disconnected_at, ip_address
+FROM
+	workspace_agent_client_connections
+WHERE
+	workspace_agent_id = $1
+	AND connected_at > $2
+ORDER BY
+	connected_at DESC
+LIMIT
+	$3::int
+`
+
+type GetWorkspaceAgentClientConnectionsParams struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	ConnectedAfter   time.Time `db:"connected_after" json:"connected_after"`
+	LimitOpt         int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+// GetWorkspaceAgentClientConnections returns the most recent client
+// connections made to an agent, newest first.
+func (q *sqlQuerier) GetWorkspaceAgentClientConnections(ctx context.Context, arg GetWorkspaceAgentClientConnectionsParams) ([]WorkspaceAgentClientConnection, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentClientConnections, arg.WorkspaceAgentID, arg.ConnectedAfter, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentClientConnection
+	for rows.Next() {
+		var i WorkspaceAgentClientConnection
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.UserID,
+			&i.ClientType,
+			&i.ClientVersion,
+			&i.ConnectedAt,
+			&i.DisconnectedAt,
+			&i.IPAddress,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentClientConnectionsByUserID = `-- name: GetWorkspaceAgentClientConnectionsByUserID :many
+SELECT
+	workspace_agent_client_connections.id, workspace_agent_client_connections.workspace_agent_id, workspace_agent_client_connections.user_id, workspace_agent_client_connections.client_type, workspace_agent_client_connections.client_version, workspace_agent_client_connections.connected_at, workspace_agent_client_connections.disconnected_at, workspace_agent_client_connections.ip_address,
+	workspace_agents.name AS agent_name,
+	workspaces.id AS workspace_id,
+	workspaces.name AS workspace_name,
+	users.username AS workspace_owner_username
+FROM
+	workspace_agent_client_connections
+JOIN
+	workspace_agents ON workspace_agents.id = workspace_agent_client_connections.workspace_agent_id
+JOIN
+	workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+JOIN
+	workspace_builds ON workspace_builds.job_id = workspace_resources.job_id
+JOIN
+	workspaces ON workspaces.id = workspace_builds.workspace_id
+JOIN
+	users ON users.id = workspaces.owner_id
+WHERE
+	workspace_agent_client_connections.user_id = $1
+	AND workspace_agent_client_connections.connected_at > $2
+ORDER BY
+	workspace_agent_client_connections.connected_at DESC
+LIMIT
+	$3::int
+`
+
+type GetWorkspaceAgentClientConnectionsByUserIDParams struct {
+	UserID         uuid.NullUUID `db:"user_id" json:"user_id"`
+	ConnectedAfter time.Time     `db:"connected_after" json:"connected_after"`
+	LimitOpt       int32         `db:"limit_opt" json:"limit_opt"`
+}
+
+type GetWorkspaceAgentClientConnectionsByUserIDRow struct {
+	ID                     uuid.UUID     `db:"id" json:"id"`
+	WorkspaceAgentID       uuid.UUID     `db:"workspace_agent_id" json:"workspace_agent_id"`
+	UserID                 uuid.NullUUID `db:"user_id" json:"user_id"`
+	ClientType             string        `db:"client_type" json:"client_type"`
+	ClientVersion          string        `db:"client_version" json:"client_version"`
+	ConnectedAt            time.Time     `db:"connected_at" json:"connected_at"`
+	DisconnectedAt         sql.NullTime  `db:"disconnected_at" json:"disconnected_at"`
+	IPAddress              pqtype.Inet   `db:"ip_address" json:"ip_address"`
+	AgentName              string        `db:"agent_name" json:"agent_name"`
+	WorkspaceID            uuid.UUID     `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName          string        `db:"workspace_name" json:"workspace_name"`
+	WorkspaceOwnerUsername string        `db:"workspace_owner_username" json:"workspace_owner_username"`
+}
+
+// GetWorkspaceAgentClientConnectionsByUserID returns the most recent client
+// connections made by a user to any agent, newest first.
+func (q *sqlQuerier) GetWorkspaceAgentClientConnectionsByUserID(ctx context.Context, arg GetWorkspaceAgentClientConnectionsByUserIDParams) ([]GetWorkspaceAgentClientConnectionsByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentClientConnectionsByUserID, arg.UserID, arg.ConnectedAfter, arg.LimitOpt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentClientConnectionsByUserIDRow
+	for rows.Next() {
+		var i GetWorkspaceAgentClientConnectionsByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.UserID,
+			&i.ClientType,
+			&i.ClientVersion,
+			&i.ConnectedAt,
+			&i.DisconnectedAt,
+			&i.IPAddress,
+			&i.AgentName,
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.WorkspaceOwnerUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentLifecycleStateByID = `-- name: GetWorkspaceAgentLifecycleStateByID :one
 SELECT
 	lifecycle_state,
@@ -6708,9 +9916,68 @@ func (q *sqlQuerier) GetWorkspaceAgentMetadata(ctx context.Context, workspaceAge
 	return items, nil
 }
 
+const getWorkspaceAgentMetadataHistory = `-- name: GetWorkspaceAgentMetadataHistory :many
+SELECT
+	id, workspace_agent_id, key, value, error, collected_at
+FROM
+	workspace_agent_metadata_history
+WHERE
+	workspace_agent_id = $1
+	AND key = $2
+	AND collected_at > $3
+ORDER BY
+	id DESC
+LIMIT
+	$4::int
+`
+
+type GetWorkspaceAgentMetadataHistoryParams struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Key              string    `db:"key" json:"key"`
+	CollectedAfter   time.Time `db:"collected_after" json:"collected_after"`
+	LimitOpt         int32     `db:"limit_opt" json:"limit_opt"`
+}
+
+// GetWorkspaceAgentMetadataHistory returns the most recent values of a
+// metadata key, newest first.
+func (q *sqlQuerier) GetWorkspaceAgentMetadataHistory(ctx context.Context, arg GetWorkspaceAgentMetadataHistoryParams) ([]WorkspaceAgentMetadataHistory, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentMetadataHistory,
+		arg.WorkspaceAgentID,
+		arg.Key,
+		arg.CollectedAfter,
+		arg.LimitOpt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceAgentMetadataHistory
+	for rows.Next() {
+		var i WorkspaceAgentMetadataHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceAgentID,
+			&i.Key,
+			&i.Value,
+			&i.Error,
+			&i.CollectedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentsByResourceIDs = `-- name: GetWorkspaceAgentsByResourceIDs :many
 SELECT
-	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems
+	id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_order, host_facts, clock_offset_ms
 FROM
 	workspace_agents
 WHERE
@@ -6760,6 +10027,9 @@ func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []
 			&i.StartedAt,
 			&i.ReadyAt,
 			pq.Array(&i.Subsystems),
+			&i.DisplayOrder,
+			&i.HostFacts,
+			&i.ClockOffsetMS,
 		); err != nil {
 			return nil, err
 		}
@@ -6775,7 +10045,7 @@ func (q *sqlQuerier) GetWorkspaceAgentsByResourceIDs(ctx context.Context, ids []
 }
 
 const getWorkspaceAgentsCreatedAfter = `-- name: GetWorkspaceAgentsCreatedAfter :many
-SELECT id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems FROM workspace_agents WHERE created_at > $1
+SELECT id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_order, host_facts, clock_offset_ms FROM workspace_agents WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceAgent, error) {
@@ -6821,6 +10091,9 @@ func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, created
 			&i.StartedAt,
 			&i.ReadyAt,
 			pq.Array(&i.Subsystems),
+			&i.DisplayOrder,
+			&i.HostFacts,
+			&i.ClockOffsetMS,
 		); err != nil {
 			return nil, err
 		}
@@ -6837,7 +10110,7 @@ func (q *sqlQuerier) GetWorkspaceAgentsCreatedAfter(ctx context.Context, created
 
 const getWorkspaceAgentsInLatestBuildByWorkspaceID = `-- name: GetWorkspaceAgentsInLatestBuildByWorkspaceID :many
 SELECT
-	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.startup_script, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.startup_script_timeout_seconds, workspace_agents.expanded_directory, workspace_agents.shutdown_script, workspace_agents.shutdown_script_timeout_seconds, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.startup_script_behavior, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems
+	workspace_agents.id, workspace_agents.created_at, workspace_agents.updated_at, workspace_agents.name, workspace_agents.first_connected_at, workspace_agents.last_connected_at, workspace_agents.disconnected_at, workspace_agents.resource_id, workspace_agents.auth_token, workspace_agents.auth_instance_id, workspace_agents.architecture, workspace_agents.environment_variables, workspace_agents.operating_system, workspace_agents.startup_script, workspace_agents.instance_metadata, workspace_agents.resource_metadata, workspace_agents.directory, workspace_agents.version, workspace_agents.last_connected_replica_id, workspace_agents.connection_timeout_seconds, workspace_agents.troubleshooting_url, workspace_agents.motd_file, workspace_agents.lifecycle_state, workspace_agents.startup_script_timeout_seconds, workspace_agents.expanded_directory, workspace_agents.shutdown_script, workspace_agents.shutdown_script_timeout_seconds, workspace_agents.logs_length, workspace_agents.logs_overflowed, workspace_agents.startup_script_behavior, workspace_agents.started_at, workspace_agents.ready_at, workspace_agents.subsystems, workspace_agents.display_order, workspace_agents.host_facts, workspace_agents.clock_offset_ms
 FROM
 	workspace_agents
 JOIN
@@ -6899,6 +10172,97 @@ func (q *sqlQuerier) GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx context.Co
 			&i.StartedAt,
 			&i.ReadyAt,
 			pq.Array(&i.Subsystems),
+			&i.DisplayOrder,
+			&i.HostFacts,
+			&i.ClockOffsetMS,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentsNeverConnected = `-- name: GetWorkspaceAgentsNeverConnected :many
+SELECT
+	workspace_agents.id AS agent_id,
+	workspace_agents.name AS agent_name,
+	workspaces.id AS workspace_id,
+	workspaces.name AS workspace_name,
+	templates.id AS template_id,
+	templates.name AS template_name
+FROM
+	workspace_agents
+JOIN
+	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
+JOIN
+	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
+JOIN
+	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
+JOIN
+	workspaces ON workspace_builds.workspace_id = workspaces.id
+JOIN
+	templates ON workspaces.template_id = templates.id
+WHERE
+	workspace_agents.first_connected_at IS NULL AND
+	workspace_builds.transition = 'start' AND
+	(provisioner_jobs.error IS NULL OR provisioner_jobs.error = '') AND
+	provisioner_jobs.canceled_at IS NULL AND
+	NOT workspaces.deleted AND
+	provisioner_jobs.completed_at + make_interval(secs => COALESCE(NULLIF(workspace_agents.connection_timeout_seconds, 0), $1::int)) > $2::timestamptz AND
+	provisioner_jobs.completed_at + make_interval(secs => COALESCE(NULLIF(workspace_agents.connection_timeout_seconds, 0), $1::int)) <= $3::timestamptz AND
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds AS wb
+		WHERE
+			wb.workspace_id = workspaces.id
+	)
+ORDER BY workspace_agents.id ASC
+`
+
+type GetWorkspaceAgentsNeverConnectedParams struct {
+	DefaultConnectionTimeoutSeconds int32     `db:"default_connection_timeout_seconds" json:"default_connection_timeout_seconds"`
+	DeadlineAfter                   time.Time `db:"deadline_after" json:"deadline_after"`
+	DeadlineBefore                  time.Time `db:"deadline_before" json:"deadline_before"`
+}
+
+type GetWorkspaceAgentsNeverConnectedRow struct {
+	AgentID       uuid.UUID `db:"agent_id" json:"agent_id"`
+	AgentName     string    `db:"agent_name" json:"agent_name"`
+	WorkspaceID   uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	WorkspaceName string    `db:"workspace_name" json:"workspace_name"`
+	TemplateID    uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateName  string    `db:"template_name" json:"template_name"`
+}
+
+// GetWorkspaceAgentsNeverConnected returns the agents of the latest successful
+// start builds of workspaces that haven't connected by the end of their
+// connection timeout, where the timeout elapsed within the given timeframe.
+// Agents without a connection timeout use the default one.
+func (q *sqlQuerier) GetWorkspaceAgentsNeverConnected(ctx context.Context, arg GetWorkspaceAgentsNeverConnectedParams) ([]GetWorkspaceAgentsNeverConnectedRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentsNeverConnected, arg.DefaultConnectionTimeoutSeconds, arg.DeadlineAfter, arg.DeadlineBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentsNeverConnectedRow
+	for rows.Next() {
+		var i GetWorkspaceAgentsNeverConnectedRow
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.AgentName,
+			&i.WorkspaceID,
+			&i.WorkspaceName,
+			&i.TemplateID,
+			&i.TemplateName,
 		); err != nil {
 			return nil, err
 		}
@@ -6936,10 +10300,11 @@ INSERT INTO
 		startup_script_behavior,
 		startup_script_timeout_seconds,
 		shutdown_script,
-		shutdown_script_timeout_seconds
+		shutdown_script_timeout_seconds,
+		display_order
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21) RETURNING id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22) RETURNING id, created_at, updated_at, name, first_connected_at, last_connected_at, disconnected_at, resource_id, auth_token, auth_instance_id, architecture, environment_variables, operating_system, startup_script, instance_metadata, resource_metadata, directory, version, last_connected_replica_id, connection_timeout_seconds, troubleshooting_url, motd_file, lifecycle_state, startup_script_timeout_seconds, expanded_directory, shutdown_script, shutdown_script_timeout_seconds, logs_length, logs_overflowed, startup_script_behavior, started_at, ready_at, subsystems, display_order, host_facts, clock_offset_ms
 `
 
 type InsertWorkspaceAgentParams struct {
@@ -6964,6 +10329,7 @@ type InsertWorkspaceAgentParams struct {
 	StartupScriptTimeoutSeconds  int32                 `db:"startup_script_timeout_seconds" json:"startup_script_timeout_seconds"`
 	ShutdownScript               sql.NullString        `db:"shutdown_script" json:"shutdown_script"`
 	ShutdownScriptTimeoutSeconds int32                 `db:"shutdown_script_timeout_seconds" json:"shutdown_script_timeout_seconds"`
+	DisplayOrder                 int32                 `db:"display_order" json:"display_order"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspaceAgentParams) (WorkspaceAgent, error) {
@@ -6989,6 +10355,7 @@ func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspa
 		arg.StartupScriptTimeoutSeconds,
 		arg.ShutdownScript,
 		arg.ShutdownScriptTimeoutSeconds,
+		arg.DisplayOrder,
 	)
 	var i WorkspaceAgent
 	err := row.Scan(
@@ -7025,6 +10392,58 @@ func (q *sqlQuerier) InsertWorkspaceAgent(ctx context.Context, arg InsertWorkspa
 		&i.StartedAt,
 		&i.ReadyAt,
 		pq.Array(&i.Subsystems),
+		&i.DisplayOrder,
+		&i.HostFacts,
+		&i.ClockOffsetMS,
+	)
+	return i, err
+}
+
+const insertWorkspaceAgentClientConnection = `-- name: InsertWorkspaceAgentClientConnection :one
+INSERT INTO
+	workspace_agent_client_connections (
+		id,
+		workspace_agent_id,
+		user_id,
+		client_type,
+		client_version,
+		connected_at,
+		ip_address
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING id, workspace_agent_id, user_id, client_type, client_version, connected_at, disconnected_at, ip_address
+`
+
+type InsertWorkspaceAgentClientConnectionParams struct {
+	ID               uuid.UUID     `db:"id" json:"id"`
+	WorkspaceAgentID uuid.UUID     `db:"workspace_agent_id" json:"workspace_agent_id"`
+	UserID           uuid.NullUUID `db:"user_id" json:"user_id"`
+	ClientType       string        `db:"client_type" json:"client_type"`
+	ClientVersion    string        `db:"client_version" json:"client_version"`
+	ConnectedAt      time.Time     `db:"connected_at" json:"connected_at"`
+	IPAddress        pqtype.Inet   `db:"ip_address" json:"ip_address"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentClientConnection(ctx context.Context, arg InsertWorkspaceAgentClientConnectionParams) (WorkspaceAgentClientConnection, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentClientConnection,
+		arg.ID,
+		arg.WorkspaceAgentID,
+		arg.UserID,
+		arg.ClientType,
+		arg.ClientVersion,
+		arg.ConnectedAt,
+		arg.IPAddress,
+	)
+	var i WorkspaceAgentClientConnection
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceAgentID,
+		&i.UserID,
+		&i.ClientType,
+		&i.ClientVersion,
+		&i.ConnectedAt,
+		&i.DisconnectedAt,
+		&i.IPAddress,
 	)
 	return i, err
 }
@@ -7126,6 +10545,138 @@ func (q *sqlQuerier) InsertWorkspaceAgentMetadata(ctx context.Context, arg Inser
 	return err
 }
 
+const insertWorkspaceAgentMetadataHistory = `-- name: InsertWorkspaceAgentMetadataHistory :exec
+WITH inserted AS (
+	INSERT INTO
+		workspace_agent_metadata_history (
+			workspace_agent_id,
+			key,
+			value,
+			error,
+			collected_at
+		)
+	SELECT
+		$1, $2, $3, $4, $5
+	WHERE
+		-- Only keys declared by the agent are recorded, otherwise a
+		-- misbehaving agent could grow the table without bound.
+		EXISTS (
+			SELECT
+				1
+			FROM
+				workspace_agent_metadata
+			WHERE
+				workspace_agent_id = $1
+				AND key = $2
+		)
+)
+DELETE FROM
+	workspace_agent_metadata_history
+WHERE
+	workspace_agent_id = $1
+	AND key = $2
+	-- The inserted row is not visible to this statement, so only keep
+	-- max_entries - 1 of the existing rows.
+	AND id <= (
+		SELECT
+			id
+		FROM
+			workspace_agent_metadata_history
+		WHERE
+			workspace_agent_id = $1
+			AND key = $2
+		ORDER BY
+			id DESC
+		OFFSET
+			$6::int - 1
+		LIMIT
+			1
+	)
+`
+
+type InsertWorkspaceAgentMetadataHistoryParams struct {
+	WorkspaceAgentID uuid.UUID `db:"workspace_agent_id" json:"workspace_agent_id"`
+	Key              string    `db:"key" json:"key"`
+	Value            string    `db:"value" json:"value"`
+	Error            string    `db:"error" json:"error"`
+	CollectedAt      time.Time `db:"collected_at" json:"collected_at"`
+	MaxEntries       int32     `db:"max_entries" json:"max_entries"`
+}
+
+// InsertWorkspaceAgentMetadataHistory records a collected metadata value and
+// drops the oldest values of the key so that at most max_entries values are
+// retained.
+func (q *sqlQuerier) InsertWorkspaceAgentMetadataHistory(ctx context.Context, arg InsertWorkspaceAgentMetadataHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentMetadataHistory,
+		arg.WorkspaceAgentID,
+		arg.Key,
+		arg.Value,
+		arg.Error,
+		arg.CollectedAt,
+		arg.MaxEntries,
+	)
+	return err
+}
+
+const updateWorkspaceAgentAuthTokenByID = `-- name: UpdateWorkspaceAgentAuthTokenByID :exec
+UPDATE
+	workspace_agents
+SET
+	auth_token = $2,
+	updated_at = $3
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentAuthTokenByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	AuthToken uuid.UUID `db:"auth_token" json:"auth_token"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentAuthTokenByID(ctx context.Context, arg UpdateWorkspaceAgentAuthTokenByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentAuthTokenByID, arg.ID, arg.AuthToken, arg.UpdatedAt)
+	return err
+}
+
+const updateWorkspaceAgentClientConnectionDisconnectedAt = `-- name: UpdateWorkspaceAgentClientConnectionDisconnectedAt :exec
+UPDATE
+	workspace_agent_client_connections
+SET
+	disconnected_at = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentClientConnectionDisconnectedAtParams struct {
+	ID             uuid.UUID    `db:"id" json:"id"`
+	DisconnectedAt sql.NullTime `db:"disconnected_at" json:"disconnected_at"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentClientConnectionDisconnectedAt(ctx context.Context, arg UpdateWorkspaceAgentClientConnectionDisconnectedAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentClientConnectionDisconnectedAt, arg.ID, arg.DisconnectedAt)
+	return err
+}
+
+const updateWorkspaceAgentClockOffsetByID = `-- name: UpdateWorkspaceAgentClockOffsetByID :exec
+UPDATE
+	workspace_agents
+SET
+	clock_offset_ms = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAgentClockOffsetByIDParams struct {
+	ID            uuid.UUID `db:"id" json:"id"`
+	ClockOffsetMS int64     `db:"clock_offset_ms" json:"clock_offset_ms"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentClockOffsetByID(ctx context.Context, arg UpdateWorkspaceAgentClockOffsetByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentClockOffsetByID, arg.ID, arg.ClockOffsetMS)
+	return err
+}
+
 const updateWorkspaceAgentConnectionByID = `-- name: UpdateWorkspaceAgentConnectionByID :exec
 UPDATE
 	workspace_agents
@@ -7244,25 +10795,106 @@ UPDATE
 SET
 	version = $2,
 	expanded_directory = $3,
-	subsystems = $4
+	subsystems = $4,
+	host_facts = $5
 WHERE
 	id = $1
 `
 
-type UpdateWorkspaceAgentStartupByIDParams struct {
-	ID                uuid.UUID                 `db:"id" json:"id"`
-	Version           string                    `db:"version" json:"version"`
-	ExpandedDirectory string                    `db:"expanded_directory" json:"expanded_directory"`
-	Subsystems        []WorkspaceAgentSubsystem `db:"subsystems" json:"subsystems"`
+type UpdateWorkspaceAgentStartupByIDParams struct {
+	ID                uuid.UUID                 `db:"id" json:"id"`
+	Version           string                    `db:"version" json:"version"`
+	ExpandedDirectory string                    `db:"expanded_directory" json:"expanded_directory"`
+	Subsystems        []WorkspaceAgentSubsystem `db:"subsystems" json:"subsystems"`
+	HostFacts         json.RawMessage           `db:"host_facts" json:"host_facts"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg UpdateWorkspaceAgentStartupByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentStartupByID,
+		arg.ID,
+		arg.Version,
+		arg.ExpandedDirectory,
+		pq.Array(arg.Subsystems),
+		arg.HostFacts,
+	)
+	return err
+}
+
+const getWorkspaceAgentSSHHostKey = `-- name: GetWorkspaceAgentSSHHostKey :one
+SELECT
+	workspace_id, agent_name, encrypted_private_key, created_at
+FROM
+	workspace_agent_ssh_host_keys
+WHERE
+	workspace_id = $1
+	AND agent_name = $2
+`
+
+type GetWorkspaceAgentSSHHostKeyParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName   string    `db:"agent_name" json:"agent_name"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAgentSSHHostKey(ctx context.Context, arg GetWorkspaceAgentSSHHostKeyParams) (WorkspaceAgentSSHHostKey, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceAgentSSHHostKey, arg.WorkspaceID, arg.AgentName)
+	var i WorkspaceAgentSSHHostKey
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.EncryptedPrivateKey,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const insertWorkspaceAgentSSHHostKey = `-- name: InsertWorkspaceAgentSSHHostKey :one
+INSERT INTO
+	workspace_agent_ssh_host_keys (
+		workspace_id,
+		agent_name,
+		encrypted_private_key,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (workspace_id, agent_name) DO UPDATE SET
+	encrypted_private_key = workspace_agent_ssh_host_keys.encrypted_private_key
+RETURNING
+	workspace_id, agent_name, encrypted_private_key, created_at
+`
+
+type InsertWorkspaceAgentSSHHostKeyParams struct {
+	WorkspaceID         uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	AgentName           string    `db:"agent_name" json:"agent_name"`
+	EncryptedPrivateKey string    `db:"encrypted_private_key" json:"encrypted_private_key"`
+	CreatedAt           time.Time `db:"created_at" json:"created_at"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceAgentStartupByID(ctx context.Context, arg UpdateWorkspaceAgentStartupByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceAgentStartupByID,
-		arg.ID,
-		arg.Version,
-		arg.ExpandedDirectory,
-		pq.Array(arg.Subsystems),
+// Returns the existing key instead if the agent already has one, so agents
+// that fetch their manifest concurrently get the same key.
+func (q *sqlQuerier) InsertWorkspaceAgentSSHHostKey(ctx context.Context, arg InsertWorkspaceAgentSSHHostKeyParams) (WorkspaceAgentSSHHostKey, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceAgentSSHHostKey,
+		arg.WorkspaceID,
+		arg.AgentName,
+		arg.EncryptedPrivateKey,
+		arg.CreatedAt,
+	)
+	var i WorkspaceAgentSSHHostKey
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.AgentName,
+		&i.EncryptedPrivateKey,
+		&i.CreatedAt,
 	)
+	return i, err
+}
+
+const deleteOldWorkspaceAgentBandwidthStats = `-- name: DeleteOldWorkspaceAgentBandwidthStats :exec
+DELETE FROM workspace_agent_bandwidth_stats WHERE created_at < NOW() - INTERVAL '30 days'
+`
+
+func (q *sqlQuerier) DeleteOldWorkspaceAgentBandwidthStats(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteOldWorkspaceAgentBandwidthStats)
 	return err
 }
 
@@ -7416,6 +11048,130 @@ func (q *sqlQuerier) GetTemplateDAUs(ctx context.Context, arg GetTemplateDAUsPar
 	return items, nil
 }
 
+const getWorkspaceAgentBandwidthStatsAndLabels = `-- name: GetWorkspaceAgentBandwidthStatsAndLabels :many
+SELECT
+	users.username,
+	workspace_agents.name AS agent_name,
+	workspaces.name AS workspace_name,
+	workspace_agent_bandwidth_stats.connection_type,
+	coalesce(SUM(workspace_agent_bandwidth_stats.rx_bytes), 0)::bigint AS rx_bytes,
+	coalesce(SUM(workspace_agent_bandwidth_stats.tx_bytes), 0)::bigint AS tx_bytes
+FROM
+	workspace_agent_bandwidth_stats
+JOIN
+	users
+ON
+	users.id = workspace_agent_bandwidth_stats.user_id
+JOIN
+	workspace_agents
+ON
+	workspace_agents.id = workspace_agent_bandwidth_stats.agent_id
+JOIN
+	workspaces
+ON
+	workspaces.id = workspace_agent_bandwidth_stats.workspace_id
+WHERE
+	workspace_agent_bandwidth_stats.created_at > $1
+GROUP BY
+	users.username, workspace_agents.name, workspaces.name, workspace_agent_bandwidth_stats.connection_type
+`
+
+type GetWorkspaceAgentBandwidthStatsAndLabelsRow struct {
+	Username       string `db:"username" json:"username"`
+	AgentName      string `db:"agent_name" json:"agent_name"`
+	WorkspaceName  string `db:"workspace_name" json:"workspace_name"`
+	ConnectionType string `db:"connection_type" json:"connection_type"`
+	RxBytes        int64  `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes        int64  `db:"tx_bytes" json:"tx_bytes"`
+}
+
+func (q *sqlQuerier) GetWorkspaceAgentBandwidthStatsAndLabels(ctx context.Context, createdAt time.Time) ([]GetWorkspaceAgentBandwidthStatsAndLabelsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentBandwidthStatsAndLabels, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentBandwidthStatsAndLabelsRow
+	for rows.Next() {
+		var i GetWorkspaceAgentBandwidthStatsAndLabelsRow
+		if err := rows.Scan(
+			&i.Username,
+			&i.AgentName,
+			&i.WorkspaceName,
+			&i.ConnectionType,
+			&i.RxBytes,
+			&i.TxBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceAgentBandwidthStatsByPeer = `-- name: GetWorkspaceAgentBandwidthStatsByPeer :many
+SELECT
+	agent_id,
+	peer,
+	coalesce(SUM(rx_bytes), 0)::bigint AS rx_bytes,
+	coalesce(SUM(tx_bytes), 0)::bigint AS tx_bytes
+FROM
+	workspace_agent_bandwidth_stats
+WHERE
+	workspace_id = $1
+	AND created_at > $2
+GROUP BY
+	agent_id, peer
+`
+
+type GetWorkspaceAgentBandwidthStatsByPeerParams struct {
+	WorkspaceID  uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	CreatedAfter time.Time `db:"created_after" json:"created_after"`
+}
+
+type GetWorkspaceAgentBandwidthStatsByPeerRow struct {
+	AgentID uuid.UUID `db:"agent_id" json:"agent_id"`
+	Peer    string    `db:"peer" json:"peer"`
+	RxBytes int64     `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes int64     `db:"tx_bytes" json:"tx_bytes"`
+}
+
+// Sums the traffic of the agents of a workspace by agent and peer, so it can
+// be attributed to the tailnet clients connected to the agents.
+func (q *sqlQuerier) GetWorkspaceAgentBandwidthStatsByPeer(ctx context.Context, arg GetWorkspaceAgentBandwidthStatsByPeerParams) ([]GetWorkspaceAgentBandwidthStatsByPeerRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceAgentBandwidthStatsByPeer, arg.WorkspaceID, arg.CreatedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceAgentBandwidthStatsByPeerRow
+	for rows.Next() {
+		var i GetWorkspaceAgentBandwidthStatsByPeerRow
+		if err := rows.Scan(
+			&i.AgentID,
+			&i.Peer,
+			&i.RxBytes,
+			&i.TxBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceAgentStats = `-- name: GetWorkspaceAgentStats :many
 WITH agent_stats AS (
 	SELECT
@@ -7603,6 +11359,66 @@ func (q *sqlQuerier) GetWorkspaceAgentStatsAndLabels(ctx context.Context, create
 	return items, nil
 }
 
+const insertWorkspaceAgentBandwidthStats = `-- name: InsertWorkspaceAgentBandwidthStats :exec
+INSERT INTO
+	workspace_agent_bandwidth_stats (
+		id,
+		created_at,
+		user_id,
+		workspace_id,
+		template_id,
+		agent_id,
+		connection_type,
+		peer,
+		port,
+		rx_bytes,
+		tx_bytes
+	)
+SELECT
+	unnest($1 :: uuid[]) AS id,
+	unnest($2 :: timestamptz[]) AS created_at,
+	unnest($3 :: uuid[]) AS user_id,
+	unnest($4 :: uuid[]) AS workspace_id,
+	unnest($5 :: uuid[]) AS template_id,
+	unnest($6 :: uuid[]) AS agent_id,
+	unnest($7 :: text[]) AS connection_type,
+	unnest($8 :: text[]) AS peer,
+	unnest($9 :: integer[]) AS port,
+	unnest($10 :: bigint[]) AS rx_bytes,
+	unnest($11 :: bigint[]) AS tx_bytes
+`
+
+type InsertWorkspaceAgentBandwidthStatsParams struct {
+	ID             []uuid.UUID `db:"id" json:"id"`
+	CreatedAt      []time.Time `db:"created_at" json:"created_at"`
+	UserID         []uuid.UUID `db:"user_id" json:"user_id"`
+	WorkspaceID    []uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	TemplateID     []uuid.UUID `db:"template_id" json:"template_id"`
+	AgentID        []uuid.UUID `db:"agent_id" json:"agent_id"`
+	ConnectionType []string    `db:"connection_type" json:"connection_type"`
+	Peer           []string    `db:"peer" json:"peer"`
+	Port           []int32     `db:"port" json:"port"`
+	RxBytes        []int64     `db:"rx_bytes" json:"rx_bytes"`
+	TxBytes        []int64     `db:"tx_bytes" json:"tx_bytes"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceAgentBandwidthStats(ctx context.Context, arg InsertWorkspaceAgentBandwidthStatsParams) error {
+	_, err := q.db.ExecContext(ctx, insertWorkspaceAgentBandwidthStats,
+		pq.Array(arg.ID),
+		pq.Array(arg.CreatedAt),
+		pq.Array(arg.UserID),
+		pq.Array(arg.WorkspaceID),
+		pq.Array(arg.TemplateID),
+		pq.Array(arg.AgentID),
+		pq.Array(arg.ConnectionType),
+		pq.Array(arg.Peer),
+		pq.Array(arg.Port),
+		pq.Array(arg.RxBytes),
+		pq.Array(arg.TxBytes),
+	)
+	return err
+}
+
 const insertWorkspaceAgentStat = `-- name: InsertWorkspaceAgentStat :one
 INSERT INTO
 	workspace_agent_stats (
@@ -7775,8 +11591,181 @@ func (q *sqlQuerier) InsertWorkspaceAgentStats(ctx context.Context, arg InsertWo
 	return err
 }
 
+const getLatestWorkspaceApprovalByWorkspaceID = `-- name: GetLatestWorkspaceApprovalByWorkspaceID :one
+SELECT
+	id, workspace_id, job_id, requested_by, created_at, status, decided_by, decided_at, reason
+FROM
+	workspace_approvals
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC
+LIMIT
+	1
+`
+
+func (q *sqlQuerier) GetLatestWorkspaceApprovalByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) (WorkspaceApproval, error) {
+	row := q.db.QueryRowContext(ctx, getLatestWorkspaceApprovalByWorkspaceID, workspaceID)
+	var i WorkspaceApproval
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.JobID,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const getWorkspaceApprovalByID = `-- name: GetWorkspaceApprovalByID :one
+SELECT
+	id, workspace_id, job_id, requested_by, created_at, status, decided_by, decided_at, reason
+FROM
+	workspace_approvals
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceApprovalByID(ctx context.Context, id uuid.UUID) (WorkspaceApproval, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceApprovalByID, id)
+	var i WorkspaceApproval
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.JobID,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const getWorkspaceApprovalByJobID = `-- name: GetWorkspaceApprovalByJobID :one
+SELECT
+	id, workspace_id, job_id, requested_by, created_at, status, decided_by, decided_at, reason
+FROM
+	workspace_approvals
+WHERE
+	job_id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceApprovalByJobID(ctx context.Context, jobID uuid.UUID) (WorkspaceApproval, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceApprovalByJobID, jobID)
+	var i WorkspaceApproval
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.JobID,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const insertWorkspaceApproval = `-- name: InsertWorkspaceApproval :one
+INSERT INTO
+	workspace_approvals (
+		id,
+		workspace_id,
+		job_id,
+		requested_by,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING id, workspace_id, job_id, requested_by, created_at, status, decided_by, decided_at, reason
+`
+
+type InsertWorkspaceApprovalParams struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	JobID       uuid.UUID `db:"job_id" json:"job_id"`
+	RequestedBy uuid.UUID `db:"requested_by" json:"requested_by"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceApproval(ctx context.Context, arg InsertWorkspaceApprovalParams) (WorkspaceApproval, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceApproval,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.JobID,
+		arg.RequestedBy,
+		arg.CreatedAt,
+	)
+	var i WorkspaceApproval
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.JobID,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
+const updateWorkspaceApprovalStatusByID = `-- name: UpdateWorkspaceApprovalStatusByID :one
+UPDATE
+	workspace_approvals
+SET
+	status = $2,
+	decided_by = $3,
+	decided_at = $4,
+	reason = $5
+WHERE
+	id = $1
+	AND status = 'pending'
+RETURNING id, workspace_id, job_id, requested_by, created_at, status, decided_by, decided_at, reason
+`
+
+type UpdateWorkspaceApprovalStatusByIDParams struct {
+	ID        uuid.UUID               `db:"id" json:"id"`
+	Status    WorkspaceApprovalStatus `db:"status" json:"status"`
+	DecidedBy uuid.NullUUID           `db:"decided_by" json:"decided_by"`
+	DecidedAt sql.NullTime            `db:"decided_at" json:"decided_at"`
+	Reason    string                  `db:"reason" json:"reason"`
+}
+
+// Records the decision on a pending approval request. Requests can only be
+// decided once, so no rows are returned if the request was already decided.
+func (q *sqlQuerier) UpdateWorkspaceApprovalStatusByID(ctx context.Context, arg UpdateWorkspaceApprovalStatusByIDParams) (WorkspaceApproval, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceApprovalStatusByID,
+		arg.ID,
+		arg.Status,
+		arg.DecidedBy,
+		arg.DecidedAt,
+		arg.Reason,
+	)
+	var i WorkspaceApproval
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.JobID,
+		&i.RequestedBy,
+		&i.CreatedAt,
+		&i.Status,
+		&i.DecidedBy,
+		&i.DecidedAt,
+		&i.Reason,
+	)
+	return i, err
+}
+
 const getWorkspaceAppByAgentIDAndSlug = `-- name: GetWorkspaceAppByAgentIDAndSlug :one
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = $1 AND slug = $2
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden FROM workspace_apps WHERE agent_id = $1 AND slug = $2
 `
 
 type GetWorkspaceAppByAgentIDAndSlugParams struct {
@@ -7803,12 +11792,14 @@ func (q *sqlQuerier) GetWorkspaceAppByAgentIDAndSlug(ctx context.Context, arg Ge
 		&i.SharingLevel,
 		&i.Slug,
 		&i.External,
+		&i.DisplayOrder,
+		&i.Hidden,
 	)
 	return i, err
 }
 
 const getWorkspaceAppsByAgentID = `-- name: GetWorkspaceAppsByAgentID :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = $1 ORDER BY slug ASC
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden FROM workspace_apps WHERE agent_id = $1 ORDER BY slug ASC
 `
 
 func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid.UUID) ([]WorkspaceApp, error) {
@@ -7836,6 +11827,8 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid
 			&i.SharingLevel,
 			&i.Slug,
 			&i.External,
+			&i.DisplayOrder,
+			&i.Hidden,
 		); err != nil {
 			return nil, err
 		}
@@ -7851,7 +11844,7 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentID(ctx context.Context, agentID uuid
 }
 
 const getWorkspaceAppsByAgentIDs = `-- name: GetWorkspaceAppsByAgentIDs :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE agent_id = ANY($1 :: uuid [ ]) ORDER BY slug ASC
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden FROM workspace_apps WHERE agent_id = ANY($1 :: uuid [ ]) ORDER BY slug ASC
 `
 
 func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.UUID) ([]WorkspaceApp, error) {
@@ -7879,6 +11872,8 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.
 			&i.SharingLevel,
 			&i.Slug,
 			&i.External,
+			&i.DisplayOrder,
+			&i.Hidden,
 		); err != nil {
 			return nil, err
 		}
@@ -7894,7 +11889,7 @@ func (q *sqlQuerier) GetWorkspaceAppsByAgentIDs(ctx context.Context, ids []uuid.
 }
 
 const getWorkspaceAppsCreatedAfter = `-- name: GetWorkspaceAppsCreatedAfter :many
-SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external FROM workspace_apps WHERE created_at > $1 ORDER BY slug ASC
+SELECT id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden FROM workspace_apps WHERE created_at > $1 ORDER BY slug ASC
 `
 
 func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceApp, error) {
@@ -7922,6 +11917,8 @@ func (q *sqlQuerier) GetWorkspaceAppsCreatedAfter(ctx context.Context, createdAt
 			&i.SharingLevel,
 			&i.Slug,
 			&i.External,
+			&i.DisplayOrder,
+			&i.Hidden,
 		); err != nil {
 			return nil, err
 		}
@@ -7953,10 +11950,12 @@ INSERT INTO
         healthcheck_url,
         healthcheck_interval,
         healthcheck_threshold,
-        health
+        health,
+        display_order,
+        hidden
     )
 VALUES
-    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external
+    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING id, created_at, agent_id, display_name, icon, command, url, healthcheck_url, healthcheck_interval, healthcheck_threshold, health, subdomain, sharing_level, slug, external, display_order, hidden
 `
 
 type InsertWorkspaceAppParams struct {
@@ -7975,6 +11974,8 @@ type InsertWorkspaceAppParams struct {
 	HealthcheckInterval  int32              `db:"healthcheck_interval" json:"healthcheck_interval"`
 	HealthcheckThreshold int32              `db:"healthcheck_threshold" json:"healthcheck_threshold"`
 	Health               WorkspaceAppHealth `db:"health" json:"health"`
+	DisplayOrder         int32              `db:"display_order" json:"display_order"`
+	Hidden               bool               `db:"hidden" json:"hidden"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspaceAppParams) (WorkspaceApp, error) {
@@ -7994,6 +11995,8 @@ func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspace
 		arg.HealthcheckInterval,
 		arg.HealthcheckThreshold,
 		arg.Health,
+		arg.DisplayOrder,
+		arg.Hidden,
 	)
 	var i WorkspaceApp
 	err := row.Scan(
@@ -8012,6 +12015,8 @@ func (q *sqlQuerier) InsertWorkspaceApp(ctx context.Context, arg InsertWorkspace
 		&i.SharingLevel,
 		&i.Slug,
 		&i.External,
+		&i.DisplayOrder,
+		&i.Hidden,
 	)
 	return i, err
 }
@@ -8154,8 +12159,149 @@ func (q *sqlQuerier) InsertWorkspaceBuildParameters(ctx context.Context, arg Ins
 	return err
 }
 
+const deleteWorkspaceBuildQueueEntriesByWorkspaceID = `-- name: DeleteWorkspaceBuildQueueEntriesByWorkspaceID :exec
+DELETE FROM
+	workspace_build_queue_entries
+WHERE
+	workspace_id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceBuildQueueEntriesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceBuildQueueEntriesByWorkspaceID, workspaceID)
+	return err
+}
+
+const deleteWorkspaceBuildQueueEntryByID = `-- name: DeleteWorkspaceBuildQueueEntryByID :exec
+DELETE FROM
+	workspace_build_queue_entries
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceBuildQueueEntryByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceBuildQueueEntryByID, id)
+	return err
+}
+
+const getWorkspaceBuildQueueEntriesByWorkspaceID = `-- name: GetWorkspaceBuildQueueEntriesByWorkspaceID :many
+SELECT
+	id, workspace_id, initiator_id, initiator_token_name, created_at, request
+FROM
+	workspace_build_queue_entries
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at ASC,
+	id ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceBuildQueueEntriesByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceBuildQueueEntry, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildQueueEntriesByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceBuildQueueEntry
+	for rows.Next() {
+		var i WorkspaceBuildQueueEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.InitiatorID,
+			&i.InitiatorTokenName,
+			&i.CreatedAt,
+			&i.Request,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaceBuildQueueWorkspaceIDs = `-- name: GetWorkspaceBuildQueueWorkspaceIDs :many
+SELECT DISTINCT
+	workspace_id
+FROM
+	workspace_build_queue_entries
+`
+
+// Returns the workspaces that have builds waiting in their queue.
+func (q *sqlQuerier) GetWorkspaceBuildQueueWorkspaceIDs(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceBuildQueueWorkspaceIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var workspace_id uuid.UUID
+		if err := rows.Scan(&workspace_id); err != nil {
+			return nil, err
+		}
+		items = append(items, workspace_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceBuildQueueEntry = `-- name: InsertWorkspaceBuildQueueEntry :one
+INSERT INTO
+	workspace_build_queue_entries (
+		id,
+		workspace_id,
+		initiator_id,
+		initiator_token_name,
+		created_at,
+		request
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING id, workspace_id, initiator_id, initiator_token_name, created_at, request
+`
+
+type InsertWorkspaceBuildQueueEntryParams struct {
+	ID                 uuid.UUID       `db:"id" json:"id"`
+	WorkspaceID        uuid.UUID       `db:"workspace_id" json:"workspace_id"`
+	InitiatorID        uuid.UUID       `db:"initiator_id" json:"initiator_id"`
+	InitiatorTokenName string          `db:"initiator_token_name" json:"initiator_token_name"`
+	CreatedAt          time.Time       `db:"created_at" json:"created_at"`
+	Request            json.RawMessage `db:"request" json:"request"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceBuildQueueEntry(ctx context.Context, arg InsertWorkspaceBuildQueueEntryParams) (WorkspaceBuildQueueEntry, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceBuildQueueEntry,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.InitiatorID,
+		arg.InitiatorTokenName,
+		arg.CreatedAt,
+		arg.Request,
+	)
+	var i WorkspaceBuildQueueEntry
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.InitiatorID,
+		&i.InitiatorTokenName,
+		&i.CreatedAt,
+		&i.Request,
+	)
+	return i, err
+}
+
 const getActiveWorkspaceBuildsByTemplateID = `-- name: GetActiveWorkspaceBuildsByTemplateID :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_token_name, wb.initiator_by_avatar_url, wb.initiator_by_username
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -8204,6 +12350,7 @@ func (q *sqlQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, t
 			&i.Reason,
 			&i.DailyCost,
 			&i.MaxDeadline,
+			&i.InitiatorTokenName,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 		); err != nil {
@@ -8222,7 +12369,7 @@ func (q *sqlQuerier) GetActiveWorkspaceBuildsByTemplateID(ctx context.Context, t
 
 const getLatestWorkspaceBuildByWorkspaceID = `-- name: GetLatestWorkspaceBuildByWorkspaceID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_token_name, initiator_by_avatar_url, initiator_by_username
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -8251,6 +12398,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, w
 		&i.Reason,
 		&i.DailyCost,
 		&i.MaxDeadline,
+		&i.InitiatorTokenName,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 	)
@@ -8258,7 +12406,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildByWorkspaceID(ctx context.Context, w
 }
 
 const getLatestWorkspaceBuilds = `-- name: GetLatestWorkspaceBuilds :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_token_name, wb.initiator_by_avatar_url, wb.initiator_by_username
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -8296,6 +12444,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceB
 			&i.Reason,
 			&i.DailyCost,
 			&i.MaxDeadline,
+			&i.InitiatorTokenName,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 		); err != nil {
@@ -8313,7 +12462,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuilds(ctx context.Context) ([]WorkspaceB
 }
 
 const getLatestWorkspaceBuildsByWorkspaceIDs = `-- name: GetLatestWorkspaceBuildsByWorkspaceIDs :many
-SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_by_avatar_url, wb.initiator_by_username
+SELECT wb.id, wb.created_at, wb.updated_at, wb.workspace_id, wb.template_version_id, wb.build_number, wb.transition, wb.initiator_id, wb.provisioner_state, wb.job_id, wb.deadline, wb.reason, wb.daily_cost, wb.max_deadline, wb.initiator_token_name, wb.initiator_by_avatar_url, wb.initiator_by_username
 FROM (
     SELECT
         workspace_id, MAX(build_number) as max_build_number
@@ -8353,6 +12502,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context,
 			&i.Reason,
 			&i.DailyCost,
 			&i.MaxDeadline,
+			&i.InitiatorTokenName,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 		); err != nil {
@@ -8371,7 +12521,7 @@ func (q *sqlQuerier) GetLatestWorkspaceBuildsByWorkspaceIDs(ctx context.Context,
 
 const getWorkspaceBuildByID = `-- name: GetWorkspaceBuildByID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_token_name, initiator_by_avatar_url, initiator_by_username
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -8398,6 +12548,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (W
 		&i.Reason,
 		&i.DailyCost,
 		&i.MaxDeadline,
+		&i.InitiatorTokenName,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 	)
@@ -8406,7 +12557,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByID(ctx context.Context, id uuid.UUID) (W
 
 const getWorkspaceBuildByJobID = `-- name: GetWorkspaceBuildByJobID :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_token_name, initiator_by_avatar_url, initiator_by_username
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -8433,6 +12584,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UU
 		&i.Reason,
 		&i.DailyCost,
 		&i.MaxDeadline,
+		&i.InitiatorTokenName,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 	)
@@ -8441,7 +12593,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByJobID(ctx context.Context, jobID uuid.UU
 
 const getWorkspaceBuildByWorkspaceIDAndBuildNumber = `-- name: GetWorkspaceBuildByWorkspaceIDAndBuildNumber :one
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_token_name, initiator_by_avatar_url, initiator_by_username
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -8472,6 +12624,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Co
 		&i.Reason,
 		&i.DailyCost,
 		&i.MaxDeadline,
+		&i.InitiatorTokenName,
 		&i.InitiatorByAvatarUrl,
 		&i.InitiatorByUsername,
 	)
@@ -8480,7 +12633,7 @@ func (q *sqlQuerier) GetWorkspaceBuildByWorkspaceIDAndBuildNumber(ctx context.Co
 
 const getWorkspaceBuildsByWorkspaceID = `-- name: GetWorkspaceBuildsByWorkspaceID :many
 SELECT
-	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username
+	id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_token_name, initiator_by_avatar_url, initiator_by_username
 FROM
 	workspace_build_with_user AS workspace_builds
 WHERE
@@ -8505,17 +12658,31 @@ WHERE
 		)
 		ELSE true
 END
+	-- Filter by the reason of the build.
+	AND CASE
+		WHEN $4 :: text != '' THEN
+			workspace_builds.reason :: text = $4
+		ELSE true
+	END
+	-- Filter by the user who initiated the build.
+	AND CASE
+		WHEN $5 :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_builds.initiator_id = $5
+		ELSE true
+	END
 ORDER BY
-    build_number desc OFFSET $4
+    build_number desc OFFSET $6
 LIMIT
     -- A null limit means "no limit", so 0 means return all
-    NULLIF($5 :: int, 0)
+    NULLIF($7 :: int, 0)
 `
 
 type GetWorkspaceBuildsByWorkspaceIDParams struct {
 	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
 	Since       time.Time `db:"since" json:"since"`
 	AfterID     uuid.UUID `db:"after_id" json:"after_id"`
+	Reason      string    `db:"reason" json:"reason"`
+	InitiatorID uuid.UUID `db:"initiator_id" json:"initiator_id"`
 	OffsetOpt   int32     `db:"offset_opt" json:"offset_opt"`
 	LimitOpt    int32     `db:"limit_opt" json:"limit_opt"`
 }
@@ -8525,6 +12692,8 @@ func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg Ge
 		arg.WorkspaceID,
 		arg.Since,
 		arg.AfterID,
+		arg.Reason,
+		arg.InitiatorID,
 		arg.OffsetOpt,
 		arg.LimitOpt,
 	)
@@ -8550,6 +12719,7 @@ func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg Ge
 			&i.Reason,
 			&i.DailyCost,
 			&i.MaxDeadline,
+			&i.InitiatorTokenName,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 		); err != nil {
@@ -8567,7 +12737,7 @@ func (q *sqlQuerier) GetWorkspaceBuildsByWorkspaceID(ctx context.Context, arg Ge
 }
 
 const getWorkspaceBuildsCreatedAfter = `-- name: GetWorkspaceBuildsCreatedAfter :many
-SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_by_avatar_url, initiator_by_username FROM workspace_build_with_user WHERE created_at > $1
+SELECT id, created_at, updated_at, workspace_id, template_version_id, build_number, transition, initiator_id, provisioner_state, job_id, deadline, reason, daily_cost, max_deadline, initiator_token_name, initiator_by_avatar_url, initiator_by_username FROM workspace_build_with_user WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceBuild, error) {
@@ -8594,6 +12764,7 @@ func (q *sqlQuerier) GetWorkspaceBuildsCreatedAfter(ctx context.Context, created
 			&i.Reason,
 			&i.DailyCost,
 			&i.MaxDeadline,
+			&i.InitiatorTokenName,
 			&i.InitiatorByAvatarUrl,
 			&i.InitiatorByUsername,
 		); err != nil {
@@ -8625,26 +12796,28 @@ INSERT INTO
 		provisioner_state,
 		deadline,
 		max_deadline,
-		reason
+		reason,
+		initiator_token_name
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 `
 
 type InsertWorkspaceBuildParams struct {
-	ID                uuid.UUID           `db:"id" json:"id"`
-	CreatedAt         time.Time           `db:"created_at" json:"created_at"`
-	UpdatedAt         time.Time           `db:"updated_at" json:"updated_at"`
-	WorkspaceID       uuid.UUID           `db:"workspace_id" json:"workspace_id"`
-	TemplateVersionID uuid.UUID           `db:"template_version_id" json:"template_version_id"`
-	BuildNumber       int32               `db:"build_number" json:"build_number"`
-	Transition        WorkspaceTransition `db:"transition" json:"transition"`
-	InitiatorID       uuid.UUID           `db:"initiator_id" json:"initiator_id"`
-	JobID             uuid.UUID           `db:"job_id" json:"job_id"`
-	ProvisionerState  []byte              `db:"provisioner_state" json:"provisioner_state"`
-	Deadline          time.Time           `db:"deadline" json:"deadline"`
-	MaxDeadline       time.Time           `db:"max_deadline" json:"max_deadline"`
-	Reason            BuildReason         `db:"reason" json:"reason"`
+	ID                 uuid.UUID           `db:"id" json:"id"`
+	CreatedAt          time.Time           `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time           `db:"updated_at" json:"updated_at"`
+	WorkspaceID        uuid.UUID           `db:"workspace_id" json:"workspace_id"`
+	TemplateVersionID  uuid.UUID           `db:"template_version_id" json:"template_version_id"`
+	BuildNumber        int32               `db:"build_number" json:"build_number"`
+	Transition         WorkspaceTransition `db:"transition" json:"transition"`
+	InitiatorID        uuid.UUID           `db:"initiator_id" json:"initiator_id"`
+	JobID              uuid.UUID           `db:"job_id" json:"job_id"`
+	ProvisionerState   []byte              `db:"provisioner_state" json:"provisioner_state"`
+	Deadline           time.Time           `db:"deadline" json:"deadline"`
+	MaxDeadline        time.Time           `db:"max_deadline" json:"max_deadline"`
+	Reason             BuildReason         `db:"reason" json:"reason"`
+	InitiatorTokenName string              `db:"initiator_token_name" json:"initiator_token_name"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspaceBuildParams) error {
@@ -8662,6 +12835,7 @@ func (q *sqlQuerier) InsertWorkspaceBuild(ctx context.Context, arg InsertWorkspa
 		arg.Deadline,
 		arg.MaxDeadline,
 		arg.Reason,
+		arg.InitiatorTokenName,
 	)
 	return err
 }
@@ -8675,50 +12849,331 @@ SET
 	deadline = $4,
 	max_deadline = $5
 WHERE
-	id = $1
+	id = $1
+`
+
+type UpdateWorkspaceBuildByIDParams struct {
+	ID               uuid.UUID `db:"id" json:"id"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
+	Deadline         time.Time `db:"deadline" json:"deadline"`
+	MaxDeadline      time.Time `db:"max_deadline" json:"max_deadline"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildByID(ctx context.Context, arg UpdateWorkspaceBuildByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildByID,
+		arg.ID,
+		arg.UpdatedAt,
+		arg.ProvisionerState,
+		arg.Deadline,
+		arg.MaxDeadline,
+	)
+	return err
+}
+
+const updateWorkspaceBuildCostByID = `-- name: UpdateWorkspaceBuildCostByID :exec
+UPDATE
+	workspace_builds
+SET
+	daily_cost = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceBuildCostByIDParams struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	DailyCost int32     `db:"daily_cost" json:"daily_cost"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildCostByID, arg.ID, arg.DailyCost)
+	return err
+}
+
+const deleteWorkspaceExternalMetadatum = `-- name: DeleteWorkspaceExternalMetadatum :exec
+DELETE FROM
+	workspace_external_metadata
+WHERE
+	workspace_id = $1
+	AND namespace = $2
+`
+
+type DeleteWorkspaceExternalMetadatumParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Namespace   string    `db:"namespace" json:"namespace"`
+}
+
+func (q *sqlQuerier) DeleteWorkspaceExternalMetadatum(ctx context.Context, arg DeleteWorkspaceExternalMetadatumParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceExternalMetadatum, arg.WorkspaceID, arg.Namespace)
+	return err
+}
+
+const getWorkspaceExternalMetadataByWorkspaceIDs = `-- name: GetWorkspaceExternalMetadataByWorkspaceIDs :many
+SELECT
+	workspace_external_metadata.workspace_id, workspace_external_metadata.namespace, workspace_external_metadata.visibility, workspace_external_metadata.data, workspace_external_metadata.updated_at,
+	workspaces.owner_id,
+	workspaces.organization_id
+FROM
+	workspace_external_metadata
+JOIN
+	workspaces ON workspaces.id = workspace_external_metadata.workspace_id
+WHERE
+	workspace_external_metadata.workspace_id = ANY($1 :: uuid [ ])
+ORDER BY
+	workspace_external_metadata.namespace ASC
+`
+
+type GetWorkspaceExternalMetadataByWorkspaceIDsRow struct {
+	WorkspaceID    uuid.UUID                           `db:"workspace_id" json:"workspace_id"`
+	Namespace      string                              `db:"namespace" json:"namespace"`
+	Visibility     WorkspaceExternalMetadataVisibility `db:"visibility" json:"visibility"`
+	Data           json.RawMessage                     `db:"data" json:"data"`
+	UpdatedAt      time.Time                           `db:"updated_at" json:"updated_at"`
+	OwnerID        uuid.UUID                           `db:"owner_id" json:"owner_id"`
+	OrganizationID uuid.UUID                           `db:"organization_id" json:"organization_id"`
+}
+
+func (q *sqlQuerier) GetWorkspaceExternalMetadataByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]GetWorkspaceExternalMetadataByWorkspaceIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceExternalMetadataByWorkspaceIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceExternalMetadataByWorkspaceIDsRow
+	for rows.Next() {
+		var i GetWorkspaceExternalMetadataByWorkspaceIDsRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.Namespace,
+			&i.Visibility,
+			&i.Data,
+			&i.UpdatedAt,
+			&i.OwnerID,
+			&i.OrganizationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspaceExternalMetadatum = `-- name: UpsertWorkspaceExternalMetadatum :one
+INSERT INTO
+	workspace_external_metadata (
+		workspace_id,
+		namespace,
+		visibility,
+		data,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5)
+ON CONFLICT (workspace_id, namespace)
+DO UPDATE SET
+	visibility = $3,
+	data = $4,
+	updated_at = $5
+RETURNING workspace_id, namespace, visibility, data, updated_at
+`
+
+type UpsertWorkspaceExternalMetadatumParams struct {
+	WorkspaceID uuid.UUID                           `db:"workspace_id" json:"workspace_id"`
+	Namespace   string                              `db:"namespace" json:"namespace"`
+	Visibility  WorkspaceExternalMetadataVisibility `db:"visibility" json:"visibility"`
+	Data        json.RawMessage                     `db:"data" json:"data"`
+	UpdatedAt   time.Time                           `db:"updated_at" json:"updated_at"`
+}
+
+func (q *sqlQuerier) UpsertWorkspaceExternalMetadatum(ctx context.Context, arg UpsertWorkspaceExternalMetadatumParams) (WorkspaceExternalMetadatum, error) {
+	row := q.db.QueryRowContext(ctx, upsertWorkspaceExternalMetadatum,
+		arg.WorkspaceID,
+		arg.Namespace,
+		arg.Visibility,
+		arg.Data,
+		arg.UpdatedAt,
+	)
+	var i WorkspaceExternalMetadatum
+	err := row.Scan(
+		&i.WorkspaceID,
+		&i.Namespace,
+		&i.Visibility,
+		&i.Data,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteWorkspacePreviousName = `-- name: DeleteWorkspacePreviousName :exec
+DELETE FROM
+	workspace_previous_names
+WHERE
+	workspace_id = $1
+	AND LOWER(name) = LOWER($2)
+`
+
+type DeleteWorkspacePreviousNameParams struct {
+	WorkspaceID uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name        string    `db:"name" json:"name"`
+}
+
+func (q *sqlQuerier) DeleteWorkspacePreviousName(ctx context.Context, arg DeleteWorkspacePreviousNameParams) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspacePreviousName, arg.WorkspaceID, arg.Name)
+	return err
+}
+
+const getWorkspaceByOwnerIDAndPreviousName = `-- name: GetWorkspaceByOwnerIDAndPreviousName :one
+-- Returns the workspace most recently renamed away from the given name, as
+-- long as the redirect from that name has not expired.
+SELECT
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at, automatic_updates
+FROM
+	workspaces
+WHERE
+	workspaces.id = (
+		SELECT
+			workspace_previous_names.workspace_id
+		FROM
+			workspace_previous_names
+		JOIN
+			workspaces ON workspaces.id = workspace_previous_names.workspace_id
+		WHERE
+			workspaces.owner_id = $1
+			AND workspaces.deleted = false
+			AND LOWER(workspace_previous_names.name) = LOWER($2)
+			AND workspace_previous_names.redirect_expires_at > NOW()
+		ORDER BY
+			workspace_previous_names.renamed_at DESC
+		LIMIT
+			1
+	)
+`
+
+type GetWorkspaceByOwnerIDAndPreviousNameParams struct {
+	OwnerID uuid.UUID `db:"owner_id" json:"owner_id"`
+	Name    string    `db:"name" json:"name"`
+}
+
+// Returns the workspace most recently renamed away from the given name, as
+// long as the redirect from that name has not expired.
+func (q *sqlQuerier) GetWorkspaceByOwnerIDAndPreviousName(ctx context.Context, arg GetWorkspaceByOwnerIDAndPreviousNameParams) (Workspace, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceByOwnerIDAndPreviousName, arg.OwnerID, arg.Name)
+	var i Workspace
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OwnerID,
+		&i.OrganizationID,
+		&i.TemplateID,
+		&i.Deleted,
+		&i.Name,
+		&i.AutostartSchedule,
+		&i.Ttl,
+		&i.LastUsedAt,
+		&i.LockedAt,
+		&i.DeletingAt,
+		&i.AutomaticUpdates,
+	)
+	return i, err
+}
+
+const getWorkspacePreviousNamesByWorkspaceIDs = `-- name: GetWorkspacePreviousNamesByWorkspaceIDs :many
+SELECT
+	workspace_previous_names.workspace_id, workspace_previous_names.name, workspace_previous_names.renamed_at, workspace_previous_names.redirect_expires_at,
+	workspaces.owner_id,
+	workspaces.organization_id
+FROM
+	workspace_previous_names
+JOIN
+	workspaces ON workspaces.id = workspace_previous_names.workspace_id
+WHERE
+	workspace_previous_names.workspace_id = ANY($1 :: uuid [ ])
+ORDER BY
+	workspace_previous_names.renamed_at DESC
 `
 
-type UpdateWorkspaceBuildByIDParams struct {
-	ID               uuid.UUID `db:"id" json:"id"`
-	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
-	ProvisionerState []byte    `db:"provisioner_state" json:"provisioner_state"`
-	Deadline         time.Time `db:"deadline" json:"deadline"`
-	MaxDeadline      time.Time `db:"max_deadline" json:"max_deadline"`
+type GetWorkspacePreviousNamesByWorkspaceIDsRow struct {
+	WorkspaceID       uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name              string    `db:"name" json:"name"`
+	RenamedAt         time.Time `db:"renamed_at" json:"renamed_at"`
+	RedirectExpiresAt time.Time `db:"redirect_expires_at" json:"redirect_expires_at"`
+	OwnerID           uuid.UUID `db:"owner_id" json:"owner_id"`
+	OrganizationID    uuid.UUID `db:"organization_id" json:"organization_id"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildByID(ctx context.Context, arg UpdateWorkspaceBuildByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildByID,
-		arg.ID,
-		arg.UpdatedAt,
-		arg.ProvisionerState,
-		arg.Deadline,
-		arg.MaxDeadline,
-	)
-	return err
+func (q *sqlQuerier) GetWorkspacePreviousNamesByWorkspaceIDs(ctx context.Context, ids []uuid.UUID) ([]GetWorkspacePreviousNamesByWorkspaceIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspacePreviousNamesByWorkspaceIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspacePreviousNamesByWorkspaceIDsRow
+	for rows.Next() {
+		var i GetWorkspacePreviousNamesByWorkspaceIDsRow
+		if err := rows.Scan(
+			&i.WorkspaceID,
+			&i.Name,
+			&i.RenamedAt,
+			&i.RedirectExpiresAt,
+			&i.OwnerID,
+			&i.OrganizationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const updateWorkspaceBuildCostByID = `-- name: UpdateWorkspaceBuildCostByID :exec
-UPDATE
-	workspace_builds
-SET
-	daily_cost = $2
-WHERE
-	id = $1
+const upsertWorkspacePreviousName = `-- name: UpsertWorkspacePreviousName :exec
+INSERT INTO
+	workspace_previous_names (
+		workspace_id,
+		name,
+		renamed_at,
+		redirect_expires_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (workspace_id, name)
+DO UPDATE SET
+	renamed_at = $3,
+	redirect_expires_at = $4
 `
 
-type UpdateWorkspaceBuildCostByIDParams struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	DailyCost int32     `db:"daily_cost" json:"daily_cost"`
+type UpsertWorkspacePreviousNameParams struct {
+	WorkspaceID       uuid.UUID `db:"workspace_id" json:"workspace_id"`
+	Name              string    `db:"name" json:"name"`
+	RenamedAt         time.Time `db:"renamed_at" json:"renamed_at"`
+	RedirectExpiresAt time.Time `db:"redirect_expires_at" json:"redirect_expires_at"`
 }
 
-func (q *sqlQuerier) UpdateWorkspaceBuildCostByID(ctx context.Context, arg UpdateWorkspaceBuildCostByIDParams) error {
-	_, err := q.db.ExecContext(ctx, updateWorkspaceBuildCostByID, arg.ID, arg.DailyCost)
+func (q *sqlQuerier) UpsertWorkspacePreviousName(ctx context.Context, arg UpsertWorkspacePreviousNameParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspacePreviousName,
+		arg.WorkspaceID,
+		arg.Name,
+		arg.RenamedAt,
+		arg.RedirectExpiresAt,
+	)
 	return err
 }
 
 const getWorkspaceResourceByID = `-- name: GetWorkspaceResourceByID :one
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost
+	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, display_order
 FROM
 	workspace_resources
 WHERE
@@ -8739,6 +13194,7 @@ func (q *sqlQuerier) GetWorkspaceResourceByID(ctx context.Context, id uuid.UUID)
 		&i.Icon,
 		&i.InstanceType,
 		&i.DailyCost,
+		&i.DisplayOrder,
 	)
 	return i, err
 }
@@ -8818,7 +13274,7 @@ func (q *sqlQuerier) GetWorkspaceResourceMetadataCreatedAfter(ctx context.Contex
 
 const getWorkspaceResourcesByJobID = `-- name: GetWorkspaceResourcesByJobID :many
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost
+	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, display_order
 FROM
 	workspace_resources
 WHERE
@@ -8845,6 +13301,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uui
 			&i.Icon,
 			&i.InstanceType,
 			&i.DailyCost,
+			&i.DisplayOrder,
 		); err != nil {
 			return nil, err
 		}
@@ -8861,7 +13318,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobID(ctx context.Context, jobID uui
 
 const getWorkspaceResourcesByJobIDs = `-- name: GetWorkspaceResourcesByJobIDs :many
 SELECT
-	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost
+	id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, display_order
 FROM
 	workspace_resources
 WHERE
@@ -8888,6 +13345,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uu
 			&i.Icon,
 			&i.InstanceType,
 			&i.DailyCost,
+			&i.DisplayOrder,
 		); err != nil {
 			return nil, err
 		}
@@ -8903,7 +13361,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesByJobIDs(ctx context.Context, ids []uu
 }
 
 const getWorkspaceResourcesCreatedAfter = `-- name: GetWorkspaceResourcesCreatedAfter :many
-SELECT id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost FROM workspace_resources WHERE created_at > $1
+SELECT id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, display_order FROM workspace_resources WHERE created_at > $1
 `
 
 func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, createdAt time.Time) ([]WorkspaceResource, error) {
@@ -8926,6 +13384,7 @@ func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, crea
 			&i.Icon,
 			&i.InstanceType,
 			&i.DailyCost,
+			&i.DisplayOrder,
 		); err != nil {
 			return nil, err
 		}
@@ -8942,9 +13401,9 @@ func (q *sqlQuerier) GetWorkspaceResourcesCreatedAfter(ctx context.Context, crea
 
 const insertWorkspaceResource = `-- name: InsertWorkspaceResource :one
 INSERT INTO
-	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost)
+	workspace_resources (id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, display_order)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, job_id, transition, type, name, hide, icon, instance_type, daily_cost, display_order
 `
 
 type InsertWorkspaceResourceParams struct {
@@ -8958,6 +13417,7 @@ type InsertWorkspaceResourceParams struct {
 	Icon         string              `db:"icon" json:"icon"`
 	InstanceType sql.NullString      `db:"instance_type" json:"instance_type"`
 	DailyCost    int32               `db:"daily_cost" json:"daily_cost"`
+	DisplayOrder int32               `db:"display_order" json:"display_order"`
 }
 
 func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWorkspaceResourceParams) (WorkspaceResource, error) {
@@ -8972,6 +13432,7 @@ func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWork
 		arg.Icon,
 		arg.InstanceType,
 		arg.DailyCost,
+		arg.DisplayOrder,
 	)
 	var i WorkspaceResource
 	err := row.Scan(
@@ -8985,6 +13446,7 @@ func (q *sqlQuerier) InsertWorkspaceResource(ctx context.Context, arg InsertWork
 		&i.Icon,
 		&i.InstanceType,
 		&i.DailyCost,
+		&i.DisplayOrder,
 	)
 	return i, err
 }
@@ -9124,9 +13586,76 @@ func (q *sqlQuerier) GetDeploymentWorkspaceStats(ctx context.Context) (GetDeploy
 	return i, err
 }
 
+const getOutdatedWorkspacesByTemplateID = `-- name: GetOutdatedWorkspacesByTemplateID :many
+SELECT
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.locked_at, workspaces.deleting_at, workspaces.automatic_updates
+FROM
+	workspaces
+INNER JOIN
+	templates
+ON
+	templates.id = workspaces.template_id
+INNER JOIN LATERAL (
+	SELECT
+		workspace_builds.template_version_id
+	FROM
+		workspace_builds
+	WHERE
+		workspace_builds.workspace_id = workspaces.id
+	ORDER BY
+		workspace_builds.build_number DESC
+	LIMIT
+		1
+) latest_build ON TRUE
+WHERE
+	workspaces.template_id = $1
+	AND workspaces.deleted = false
+	AND latest_build.template_version_id != templates.active_version_id
+`
+
+// Returns the workspaces of the template whose latest build doesn't use the
+// active version of the template.
+func (q *sqlQuerier) GetOutdatedWorkspacesByTemplateID(ctx context.Context, templateID uuid.UUID) ([]Workspace, error) {
+	rows, err := q.db.QueryContext(ctx, getOutdatedWorkspacesByTemplateID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Workspace
+	for rows.Next() {
+		var i Workspace
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OwnerID,
+			&i.OrganizationID,
+			&i.TemplateID,
+			&i.Deleted,
+			&i.Name,
+			&i.AutostartSchedule,
+			&i.Ttl,
+			&i.LastUsedAt,
+			&i.LockedAt,
+			&i.DeletingAt,
+			&i.AutomaticUpdates,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkspaceByAgentID = `-- name: GetWorkspaceByAgentID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -9171,13 +13700,14 @@ func (q *sqlQuerier) GetWorkspaceByAgentID(ctx context.Context, agentID uuid.UUI
 		&i.LastUsedAt,
 		&i.LockedAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaceByID = `-- name: GetWorkspaceByID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -9203,13 +13733,14 @@ func (q *sqlQuerier) GetWorkspaceByID(ctx context.Context, id uuid.UUID) (Worksp
 		&i.LastUsedAt,
 		&i.LockedAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaceByOwnerIDAndName = `-- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -9242,13 +13773,14 @@ func (q *sqlQuerier) GetWorkspaceByOwnerIDAndName(ctx context.Context, arg GetWo
 		&i.LastUsedAt,
 		&i.LockedAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaceByWorkspaceAppID = `-- name: GetWorkspaceByWorkspaceAppID :one
 SELECT
-	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at
+	id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at, automatic_updates
 FROM
 	workspaces
 WHERE
@@ -9300,13 +13832,14 @@ func (q *sqlQuerier) GetWorkspaceByWorkspaceAppID(ctx context.Context, workspace
 		&i.LastUsedAt,
 		&i.LockedAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
 const getWorkspaces = `-- name: GetWorkspaces :many
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.locked_at, workspaces.deleting_at,
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.locked_at, workspaces.deleting_at, workspaces.automatic_updates,
 	COALESCE(template_name.template_name, 'unknown') as template_name,
 	latest_build.template_version_id,
 	latest_build.template_version_name,
@@ -9545,23 +14078,24 @@ type GetWorkspacesParams struct {
 }
 
 type GetWorkspacesRow struct {
-	ID                  uuid.UUID      `db:"id" json:"id"`
-	CreatedAt           time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt           time.Time      `db:"updated_at" json:"updated_at"`
-	OwnerID             uuid.UUID      `db:"owner_id" json:"owner_id"`
-	OrganizationID      uuid.UUID      `db:"organization_id" json:"organization_id"`
-	TemplateID          uuid.UUID      `db:"template_id" json:"template_id"`
-	Deleted             bool           `db:"deleted" json:"deleted"`
-	Name                string         `db:"name" json:"name"`
-	AutostartSchedule   sql.NullString `db:"autostart_schedule" json:"autostart_schedule"`
-	Ttl                 sql.NullInt64  `db:"ttl" json:"ttl"`
-	LastUsedAt          time.Time      `db:"last_used_at" json:"last_used_at"`
-	LockedAt            sql.NullTime   `db:"locked_at" json:"locked_at"`
-	DeletingAt          sql.NullTime   `db:"deleting_at" json:"deleting_at"`
-	TemplateName        string         `db:"template_name" json:"template_name"`
-	TemplateVersionID   uuid.UUID      `db:"template_version_id" json:"template_version_id"`
-	TemplateVersionName sql.NullString `db:"template_version_name" json:"template_version_name"`
-	Count               int64          `db:"count" json:"count"`
+	ID                  uuid.UUID        `db:"id" json:"id"`
+	CreatedAt           time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time        `db:"updated_at" json:"updated_at"`
+	OwnerID             uuid.UUID        `db:"owner_id" json:"owner_id"`
+	OrganizationID      uuid.UUID        `db:"organization_id" json:"organization_id"`
+	TemplateID          uuid.UUID        `db:"template_id" json:"template_id"`
+	Deleted             bool             `db:"deleted" json:"deleted"`
+	Name                string           `db:"name" json:"name"`
+	AutostartSchedule   sql.NullString   `db:"autostart_schedule" json:"autostart_schedule"`
+	Ttl                 sql.NullInt64    `db:"ttl" json:"ttl"`
+	LastUsedAt          time.Time        `db:"last_used_at" json:"last_used_at"`
+	LockedAt            sql.NullTime     `db:"locked_at" json:"locked_at"`
+	DeletingAt          sql.NullTime     `db:"deleting_at" json:"deleting_at"`
+	AutomaticUpdates    AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+	TemplateName        string           `db:"template_name" json:"template_name"`
+	TemplateVersionID   uuid.UUID        `db:"template_version_id" json:"template_version_id"`
+	TemplateVersionName sql.NullString   `db:"template_version_name" json:"template_version_name"`
+	Count               int64            `db:"count" json:"count"`
 }
 
 func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams) ([]GetWorkspacesRow, error) {
@@ -9602,6 +14136,7 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 			&i.LastUsedAt,
 			&i.LockedAt,
 			&i.DeletingAt,
+			&i.AutomaticUpdates,
 			&i.TemplateName,
 			&i.TemplateVersionID,
 			&i.TemplateVersionName,
@@ -9622,7 +14157,7 @@ func (q *sqlQuerier) GetWorkspaces(ctx context.Context, arg GetWorkspacesParams)
 
 const getWorkspacesEligibleForTransition = `-- name: GetWorkspacesEligibleForTransition :many
 SELECT
-	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.locked_at, workspaces.deleting_at
+	workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.locked_at, workspaces.deleting_at, workspaces.automatic_updates
 FROM
 	workspaces
 LEFT JOIN
@@ -9709,6 +14244,7 @@ func (q *sqlQuerier) GetWorkspacesEligibleForTransition(ctx context.Context, now
 			&i.LastUsedAt,
 			&i.LockedAt,
 			&i.DeletingAt,
+			&i.AutomaticUpdates,
 		); err != nil {
 			return nil, err
 		}
@@ -9735,23 +14271,25 @@ INSERT INTO
 		name,
 		autostart_schedule,
 		ttl,
-		last_used_at
+		last_used_at,
+		automatic_updates
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at, automatic_updates
 `
 
 type InsertWorkspaceParams struct {
-	ID                uuid.UUID      `db:"id" json:"id"`
-	CreatedAt         time.Time      `db:"created_at" json:"created_at"`
-	UpdatedAt         time.Time      `db:"updated_at" json:"updated_at"`
-	OwnerID           uuid.UUID      `db:"owner_id" json:"owner_id"`
-	OrganizationID    uuid.UUID      `db:"organization_id" json:"organization_id"`
-	TemplateID        uuid.UUID      `db:"template_id" json:"template_id"`
-	Name              string         `db:"name" json:"name"`
-	AutostartSchedule sql.NullString `db:"autostart_schedule" json:"autostart_schedule"`
-	Ttl               sql.NullInt64  `db:"ttl" json:"ttl"`
-	LastUsedAt        time.Time      `db:"last_used_at" json:"last_used_at"`
+	ID                uuid.UUID        `db:"id" json:"id"`
+	CreatedAt         time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time        `db:"updated_at" json:"updated_at"`
+	OwnerID           uuid.UUID        `db:"owner_id" json:"owner_id"`
+	OrganizationID    uuid.UUID        `db:"organization_id" json:"organization_id"`
+	TemplateID        uuid.UUID        `db:"template_id" json:"template_id"`
+	Name              string           `db:"name" json:"name"`
+	AutostartSchedule sql.NullString   `db:"autostart_schedule" json:"autostart_schedule"`
+	Ttl               sql.NullInt64    `db:"ttl" json:"ttl"`
+	LastUsedAt        time.Time        `db:"last_used_at" json:"last_used_at"`
+	AutomaticUpdates  AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
 }
 
 func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspaceParams) (Workspace, error) {
@@ -9766,6 +14304,7 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		arg.AutostartSchedule,
 		arg.Ttl,
 		arg.LastUsedAt,
+		arg.AutomaticUpdates,
 	)
 	var i Workspace
 	err := row.Scan(
@@ -9782,6 +14321,7 @@ func (q *sqlQuerier) InsertWorkspace(ctx context.Context, arg InsertWorkspacePar
 		&i.LastUsedAt,
 		&i.LockedAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
@@ -9812,7 +14352,7 @@ SET
 WHERE
 	id = $1
 	AND deleted = false
-RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at
+RETURNING id, created_at, updated_at, owner_id, organization_id, template_id, deleted, name, autostart_schedule, ttl, last_used_at, locked_at, deleting_at, automatic_updates
 `
 
 type UpdateWorkspaceParams struct {
@@ -9837,10 +14377,30 @@ func (q *sqlQuerier) UpdateWorkspace(ctx context.Context, arg UpdateWorkspacePar
 		&i.LastUsedAt,
 		&i.LockedAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
 
+const updateWorkspaceAutomaticUpdates = `-- name: UpdateWorkspaceAutomaticUpdates :exec
+UPDATE
+	workspaces
+SET
+	automatic_updates = $2
+WHERE
+	id = $1
+`
+
+type UpdateWorkspaceAutomaticUpdatesParams struct {
+	ID               uuid.UUID        `db:"id" json:"id"`
+	AutomaticUpdates AutomaticUpdates `db:"automatic_updates" json:"automatic_updates"`
+}
+
+func (q *sqlQuerier) UpdateWorkspaceAutomaticUpdates(ctx context.Context, arg UpdateWorkspaceAutomaticUpdatesParams) error {
+	_, err := q.db.ExecContext(ctx, updateWorkspaceAutomaticUpdates, arg.ID, arg.AutomaticUpdates)
+	return err
+}
+
 const updateWorkspaceAutostart = `-- name: UpdateWorkspaceAutostart :exec
 UPDATE
 	workspaces
@@ -9915,7 +14475,7 @@ WHERE
 	workspaces.template_id = templates.id
 AND
 	workspaces.id = $1
-RETURNING workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.locked_at, workspaces.deleting_at
+RETURNING workspaces.id, workspaces.created_at, workspaces.updated_at, workspaces.owner_id, workspaces.organization_id, workspaces.template_id, workspaces.deleted, workspaces.name, workspaces.autostart_schedule, workspaces.ttl, workspaces.last_used_at, workspaces.locked_at, workspaces.deleting_at, workspaces.automatic_updates
 `
 
 type UpdateWorkspaceLockedDeletingAtParams struct {
@@ -9940,6 +14500,7 @@ func (q *sqlQuerier) UpdateWorkspaceLockedDeletingAt(ctx context.Context, arg Up
 		&i.LastUsedAt,
 		&i.LockedAt,
 		&i.DeletingAt,
+		&i.AutomaticUpdates,
 	)
 	return i, err
 }
@@ -9988,3 +14549,181 @@ func (q *sqlQuerier) UpdateWorkspacesLockedDeletingAtByTemplateID(ctx context.Co
 	_, err := q.db.ExecContext(ctx, updateWorkspacesLockedDeletingAtByTemplateID, arg.LockedTtlMs, arg.LockedAt, arg.TemplateID)
 	return err
 }
+
+const deleteWorkspaceWebhookByID = `-- name: DeleteWorkspaceWebhookByID :exec
+DELETE FROM
+	workspace_webhooks
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) DeleteWorkspaceWebhookByID(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteWorkspaceWebhookByID, id)
+	return err
+}
+
+const getWorkspaceWebhookByID = `-- name: GetWorkspaceWebhookByID :one
+SELECT
+	id, workspace_id, created_by, created_at, name, action, agent_name, command, secret, last_triggered_at
+FROM
+	workspace_webhooks
+WHERE
+	id = $1
+`
+
+func (q *sqlQuerier) GetWorkspaceWebhookByID(ctx context.Context, id uuid.UUID) (WorkspaceWebhook, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceWebhookByID, id)
+	var i WorkspaceWebhook
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.Name,
+		&i.Action,
+		&i.AgentName,
+		&i.Command,
+		&i.Secret,
+		&i.LastTriggeredAt,
+	)
+	return i, err
+}
+
+const getWorkspaceWebhooksByWorkspaceID = `-- name: GetWorkspaceWebhooksByWorkspaceID :many
+SELECT
+	id, workspace_id, created_by, created_at, name, action, agent_name, command, secret, last_triggered_at
+FROM
+	workspace_webhooks
+WHERE
+	workspace_id = $1
+ORDER BY
+	name ASC
+`
+
+func (q *sqlQuerier) GetWorkspaceWebhooksByWorkspaceID(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceWebhook, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceWebhooksByWorkspaceID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WorkspaceWebhook
+	for rows.Next() {
+		var i WorkspaceWebhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.Name,
+			&i.Action,
+			&i.AgentName,
+			&i.Command,
+			&i.Secret,
+			&i.LastTriggeredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const insertWorkspaceWebhook = `-- name: InsertWorkspaceWebhook :one
+INSERT INTO
+	workspace_webhooks (
+		id,
+		workspace_id,
+		created_by,
+		created_at,
+		name,
+		action,
+		agent_name,
+		command,
+		secret
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id, workspace_id, created_by, created_at, name, action, agent_name, command, secret, last_triggered_at
+`
+
+type InsertWorkspaceWebhookParams struct {
+	ID          uuid.UUID              `db:"id" json:"id"`
+	WorkspaceID uuid.UUID              `db:"workspace_id" json:"workspace_id"`
+	CreatedBy   uuid.UUID              `db:"created_by" json:"created_by"`
+	CreatedAt   time.Time              `db:"created_at" json:"created_at"`
+	Name        string                 `db:"name" json:"name"`
+	Action      WorkspaceWebhookAction `db:"action" json:"action"`
+	AgentName   string                 `db:"agent_name" json:"agent_name"`
+	Command     string                 `db:"command" json:"command"`
+	Secret      string                 `db:"secret" json:"secret"`
+}
+
+func (q *sqlQuerier) InsertWorkspaceWebhook(ctx context.Context, arg InsertWorkspaceWebhookParams) (WorkspaceWebhook, error) {
+	row := q.db.QueryRowContext(ctx, insertWorkspaceWebhook,
+		arg.ID,
+		arg.WorkspaceID,
+		arg.CreatedBy,
+		arg.CreatedAt,
+		arg.Name,
+		arg.Action,
+		arg.AgentName,
+		arg.Command,
+		arg.Secret,
+	)
+	var i WorkspaceWebhook
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.Name,
+		&i.Action,
+		&i.AgentName,
+		&i.Command,
+		&i.Secret,
+		&i.LastTriggeredAt,
+	)
+	return i, err
+}
+
+const updateWorkspaceWebhookLastTriggeredAt = `-- name: UpdateWorkspaceWebhookLastTriggeredAt :one
+UPDATE
+	workspace_webhooks
+SET
+	last_triggered_at = $2
+WHERE
+	id = $1
+	AND (last_triggered_at IS NULL OR last_triggered_at < $2)
+RETURNING id, workspace_id, created_by, created_at, name, action, agent_name, command, secret, last_triggered_at
+`
+
+type UpdateWorkspaceWebhookLastTriggeredAtParams struct {
+	ID              uuid.UUID    `db:"id" json:"id"`
+	LastTriggeredAt sql.NullTime `db:"last_triggered_at" json:"last_triggered_at"`
+}
+
+// UpdateWorkspaceWebhookLastTriggeredAt records the signed timestamp of an
+// accepted trigger request. It returns no rows if the timestamp isn't later
+// than the last accepted one, so a request can't be replayed.
+func (q *sqlQuerier) UpdateWorkspaceWebhookLastTriggeredAt(ctx context.Context, arg UpdateWorkspaceWebhookLastTriggeredAtParams) (WorkspaceWebhook, error) {
+	row := q.db.QueryRowContext(ctx, updateWorkspaceWebhookLastTriggeredAt, arg.ID, arg.LastTriggeredAt)
+	var i WorkspaceWebhook
+	err := row.Scan(
+		&i.ID,
+		&i.WorkspaceID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.Name,
+		&i.Action,
+		&i.AgentName,
+		&i.Command,
+		&i.Secret,
+		&i.LastTriggeredAt,
+	)
+	return i, err
+}
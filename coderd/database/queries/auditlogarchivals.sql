@@ -0,0 +1,41 @@
+-- name: GetAuditLogArchivalByID :one
+SELECT
+	*
+FROM
+	audit_log_archivals
+WHERE
+	id = $1;
+
+-- name: GetAuditLogArchivals :many
+SELECT
+	*
+FROM
+	audit_log_archivals
+ORDER BY
+	started_at DESC
+LIMIT
+	@row_limit :: int;
+
+-- name: InsertAuditLogArchival :one
+INSERT INTO
+	audit_log_archivals (
+		id,
+		initiator_id,
+		cutoff,
+		destination,
+		started_at
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: UpdateAuditLogArchivalByID :one
+UPDATE
+	audit_log_archivals
+SET
+	status = $2,
+	archived_count = $3,
+	error = $4,
+	finished_at = $5
+WHERE
+	id = $1
+RETURNING *;
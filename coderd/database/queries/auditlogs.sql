@@ -126,3 +126,23 @@ INSERT INTO
     )
 VALUES
 	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING *;
+
+-- GetAuditLogsOlderThan returns the oldest audit logs from before the given
+-- time, so they can be archived in batches.
+-- name: GetAuditLogsOlderThan :many
+SELECT
+	*
+FROM
+	audit_logs
+WHERE
+	"time" < @before :: timestamptz
+ORDER BY
+	"time" ASC, id ASC
+LIMIT
+	@row_limit :: int;
+
+-- name: DeleteAuditLogsByIDs :exec
+DELETE FROM
+	audit_logs
+WHERE
+	id = ANY(@ids :: uuid[]);
@@ -0,0 +1,27 @@
+-- name: InsertEntitlementSnapshot :exec
+-- Only the first snapshot of a day is kept, so replicas can all try to take
+-- it.
+INSERT INTO
+	entitlement_snapshots (
+	date,
+	has_license,
+	trial,
+	features,
+	user_count,
+	active_user_count,
+	replica_count,
+	warnings,
+	errors,
+	created_at
+)
+VALUES
+	(@date, @has_license, @trial, @features, @user_count, @active_user_count, @replica_count, @warnings, @errors, @created_at)
+ON CONFLICT
+	(date)
+DO NOTHING;
+
+-- name: GetEntitlementSnapshots :many
+SELECT *
+FROM entitlement_snapshots
+WHERE date >= @start_date :: date AND date <= @end_date :: date
+ORDER BY date;
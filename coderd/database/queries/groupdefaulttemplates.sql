@@ -0,0 +1,77 @@
+-- name: GetGroupDefaultTemplates :many
+SELECT
+	*
+FROM
+	group_default_templates
+WHERE
+	group_id = @group_id
+ORDER BY
+	created_at, template_id;
+
+-- name: DeleteGroupDefaultTemplates :exec
+DELETE FROM group_default_templates WHERE group_id = @group_id;
+
+-- name: InsertGroupDefaultTemplate :one
+INSERT INTO
+	group_default_templates (
+		group_id,
+		template_id,
+		create_workspace,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4) RETURNING *;
+
+-- name: GetPendingGroupDefaultTemplateWorkspaces :many
+-- Lists the active members of groups that didn't get the starter workspace of
+-- a default template of the group yet. The members of the Everyone group are
+-- the members of the organization.
+SELECT DISTINCT ON (group_default_templates.template_id, members.user_id)
+	group_default_templates.group_id,
+	group_default_templates.template_id,
+	members.user_id
+FROM
+	group_default_templates
+JOIN (
+	SELECT group_members.group_id, group_members.user_id FROM group_members
+	UNION
+	SELECT organization_members.organization_id AS group_id, organization_members.user_id FROM organization_members
+) AS members
+ON
+	members.group_id = group_default_templates.group_id
+JOIN
+	users
+ON
+	users.id = members.user_id
+JOIN
+	templates
+ON
+	templates.id = group_default_templates.template_id
+WHERE
+	group_default_templates.create_workspace
+	AND users.status = 'active'
+	AND NOT users.deleted
+	AND NOT templates.deleted
+	AND NOT EXISTS (
+		SELECT
+			1
+		FROM
+			group_default_template_workspaces
+		WHERE
+			group_default_template_workspaces.template_id = group_default_templates.template_id
+			AND group_default_template_workspaces.user_id = members.user_id
+	)
+ORDER BY
+	group_default_templates.template_id, members.user_id, group_default_templates.created_at;
+
+-- name: InsertGroupDefaultTemplateWorkspace :exec
+INSERT INTO
+	group_default_template_workspaces (
+		template_id,
+		user_id,
+		group_id,
+		workspace_id,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5);
@@ -25,6 +25,22 @@ AND
 AND
 	users.deleted = 'false';
 
+-- GetGroupMembersAnyStatus returns the members of a user made group regardless
+-- of their status, so dormant and suspended members are included.
+-- name: GetGroupMembersAnyStatus :many
+SELECT
+	users.*
+FROM
+	users
+JOIN
+	group_members
+ON
+	group_members.user_id = users.id
+WHERE
+	group_members.group_id = @group_id
+AND
+	users.deleted = 'false';
+
 -- InsertUserGroupsByName adds a user to all provided groups, if they exist.
 -- name: InsertUserGroupsByName :exec
 WITH groups AS (
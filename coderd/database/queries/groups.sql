@@ -35,10 +35,11 @@ INSERT INTO groups (
 	display_name,
 	organization_id,
 	avatar_url,
-	quota_allowance
+	quota_allowance,
+	metadata
 )
 VALUES
-	($1, $2, $3, $4, $5, $6) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
 
 -- name: InsertMissingGroups :many
 -- Inserts any group by name that does not exist. All new groups are given
@@ -81,7 +82,21 @@ SET
 	name = @name,
 	display_name = @display_name,
 	avatar_url = @avatar_url,
-	quota_allowance = @quota_allowance
+	-- An uploaded avatar is only served while the avatar URL points at it.
+	avatar_file_id = CASE WHEN avatar_url = @avatar_url THEN avatar_file_id ELSE NULL END,
+	quota_allowance = @quota_allowance,
+	quota_override = @quota_override,
+	metadata = @metadata
+WHERE
+	id = @id
+RETURNING *;
+
+-- name: UpdateGroupAvatarByID :one
+UPDATE
+	groups
+SET
+	avatar_file_id = @avatar_file_id,
+	avatar_url = @avatar_url
 WHERE
 	id = @id
 RETURNING *;
@@ -21,6 +21,30 @@ WHERE
 GROUP BY workspace_agent_stats.user_id, users.username, users.avatar_url
 ORDER BY user_id ASC;
 
+-- name: GetUserBandwidthInsights :many
+-- GetUserBandwidthInsights returns the bytes transferred by the workspaces of
+-- each user, by connection type and agent port, largest first. The result can
+-- be filtered on template_ids, meaning only user data from workspaces based on
+-- those templates will be included.
+SELECT
+	workspace_agent_bandwidth_stats.user_id,
+	users.username,
+	users.avatar_url,
+	workspace_agent_bandwidth_stats.connection_type,
+	workspace_agent_bandwidth_stats.port,
+	array_agg(DISTINCT template_id)::uuid[] AS template_ids,
+	COUNT(DISTINCT peer) AS peers,
+	coalesce(SUM(rx_bytes), 0)::bigint AS rx_bytes,
+	coalesce(SUM(tx_bytes), 0)::bigint AS tx_bytes
+FROM workspace_agent_bandwidth_stats
+JOIN users ON (users.id = workspace_agent_bandwidth_stats.user_id)
+WHERE
+	workspace_agent_bandwidth_stats.created_at >= @start_time
+	AND workspace_agent_bandwidth_stats.created_at < @end_time
+	AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+GROUP BY workspace_agent_bandwidth_stats.user_id, users.username, users.avatar_url, workspace_agent_bandwidth_stats.connection_type, workspace_agent_bandwidth_stats.port
+ORDER BY coalesce(SUM(rx_bytes), 0) + coalesce(SUM(tx_bytes), 0) DESC, user_id ASC, connection_type ASC, port ASC;
+
 -- name: GetTemplateInsights :one
 -- GetTemplateInsights has a granularity of 5 minutes where if a session/app was
 -- in use during a minute, we will add 5 minutes to the total usage for that
@@ -252,3 +276,64 @@ SELECT
 FROM unique_template_params utp
 JOIN workspace_build_parameters wbp ON (utp.workspace_build_ids @> ARRAY[wbp.workspace_build_id] AND utp.name = wbp.name)
 GROUP BY utp.num, utp.name, utp.display_name, utp.description, utp.options, utp.template_ids, utp.type, wbp.value;
+
+-- name: GetTemplateBuildSLOInsights :many
+-- GetTemplateBuildSLOInsights returns the number of workspace builds per
+-- template that completed within the given timeframe, along with how many of
+-- them succeeded and how many succeeded within the duration objective.
+-- Canceled builds are excluded as they say nothing about the health of the
+-- template. The result can be filtered on template_ids.
+SELECT
+	w.template_id,
+	COUNT(*)::bigint AS total_builds,
+	COUNT(*) FILTER (WHERE pj.error IS NULL OR pj.error = '')::bigint AS successful_builds,
+	COUNT(*) FILTER (
+		WHERE (pj.error IS NULL OR pj.error = '')
+		AND EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at)) <= @duration_objective_seconds::float
+	)::bigint AS builds_within_duration_objective,
+	coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at)))), -1)::FLOAT AS build_duration_seconds_50,
+	coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at)))), -1)::FLOAT AS build_duration_seconds_95
+FROM workspace_builds wb
+JOIN provisioner_jobs pj ON (pj.id = wb.job_id)
+JOIN workspaces w ON (w.id = wb.workspace_id)
+WHERE
+	pj.completed_at >= @start_time::timestamptz
+	AND pj.completed_at < @end_time::timestamptz
+	AND pj.started_at IS NOT NULL
+	AND pj.canceled_at IS NULL
+	AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN w.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+GROUP BY w.template_id
+ORDER BY w.template_id ASC;
+
+-- name: GetTemplateAgentFirstConnectInsights :many
+-- GetTemplateAgentFirstConnectInsights returns, per template, how long the
+-- agents of successful start builds that completed within the given timeframe
+-- took to connect for the first time after the build completed. Agents that
+-- haven't connected by the end of their connection timeout are counted as
+-- never connected, agents without a connection timeout use the default one.
+-- The result can be filtered on template_ids.
+SELECT
+	w.template_id,
+	COUNT(*)::bigint AS total_agents,
+	COUNT(wa.first_connected_at)::bigint AS connected_agents,
+	COUNT(*) FILTER (
+		WHERE wa.first_connected_at IS NULL
+		AND pj.completed_at + make_interval(secs => COALESCE(NULLIF(wa.connection_timeout_seconds, 0), @default_connection_timeout_seconds::int)) < NOW()
+	)::bigint AS never_connected_agents,
+	coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (wa.first_connected_at - pj.completed_at)), 0))), -1)::FLOAT AS first_connect_seconds_50,
+	coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (wa.first_connected_at - pj.completed_at)), 0))), -1)::FLOAT AS first_connect_seconds_95,
+	coalesce((PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (wa.first_connected_at - pj.completed_at)), 0))), -1)::FLOAT AS first_connect_seconds_99
+FROM workspace_agents wa
+JOIN workspace_resources wr ON (wr.id = wa.resource_id)
+JOIN workspace_builds wb ON (wb.job_id = wr.job_id)
+JOIN provisioner_jobs pj ON (pj.id = wb.job_id)
+JOIN workspaces w ON (w.id = wb.workspace_id)
+WHERE
+	wb.transition = 'start'
+	AND pj.completed_at >= @start_time::timestamptz
+	AND pj.completed_at < @end_time::timestamptz
+	AND pj.canceled_at IS NULL
+	AND (pj.error IS NULL OR pj.error = '')
+	AND CASE WHEN COALESCE(array_length(@template_ids::uuid[], 1), 0) > 0 THEN w.template_id = ANY(@template_ids::uuid[]) ELSE TRUE END
+GROUP BY w.template_id
+ORDER BY w.template_id ASC;
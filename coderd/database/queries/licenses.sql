@@ -4,10 +4,11 @@ INSERT INTO
 	uploaded_at,
 	jwt,
 	exp,
-	uuid
+	uuid,
+	activation_key
 )
 VALUES
-	($1, $2, $3, $4) RETURNING *;
+	($1, $2, $3, $4, $5) RETURNING *;
 
 -- name: GetLicenses :many
 SELECT *
@@ -35,3 +36,33 @@ DELETE
 FROM licenses
 WHERE id = $1
 RETURNING id;
+
+-- name: UpsertLicenseUsage :exec
+-- Records a usage sample for a feature, keeping the peak and the first time
+-- the limit was exceeded on the given day.
+INSERT INTO
+	license_usage (
+	feature,
+	date,
+	peak_actual,
+	latest_actual,
+	feature_limit,
+	first_exceeded_at,
+	updated_at
+)
+VALUES
+	(@feature, @date, @actual, @actual, @feature_limit, @first_exceeded_at, @updated_at)
+ON CONFLICT
+	(feature, date)
+DO UPDATE SET
+	peak_actual = GREATEST(license_usage.peak_actual, EXCLUDED.peak_actual),
+	latest_actual = EXCLUDED.latest_actual,
+	feature_limit = EXCLUDED.feature_limit,
+	first_exceeded_at = COALESCE(license_usage.first_exceeded_at, EXCLUDED.first_exceeded_at),
+	updated_at = EXCLUDED.updated_at;
+
+-- name: GetLicenseUsage :many
+SELECT *
+FROM license_usage
+WHERE date >= @start_date :: date AND date <= @end_date :: date
+ORDER BY feature, date;
@@ -0,0 +1,61 @@
+-- name: GetManagedEnvironmentVariableByID :one
+SELECT
+	*
+FROM
+	managed_environment_variables
+WHERE
+	id = $1;
+
+-- name: GetManagedEnvironmentVariablesByOrganizationID :many
+-- Returns the variables of the organization that aren't scoped to a template.
+SELECT
+	*
+FROM
+	managed_environment_variables
+WHERE
+	organization_id = $1
+	AND template_id IS NULL
+ORDER BY
+	name ASC;
+
+-- name: GetManagedEnvironmentVariablesByTemplateID :many
+SELECT
+	*
+FROM
+	managed_environment_variables
+WHERE
+	template_id = $1
+ORDER BY
+	name ASC;
+
+-- name: InsertManagedEnvironmentVariable :one
+INSERT INTO
+	managed_environment_variables (
+		id,
+		organization_id,
+		template_id,
+		created_at,
+		updated_at,
+		name,
+		value,
+		secret
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;
+
+-- name: UpdateManagedEnvironmentVariableByID :one
+UPDATE
+	managed_environment_variables
+SET
+	updated_at = $2,
+	value = $3,
+	secret = $4
+WHERE
+	id = $1
+RETURNING *;
+
+-- name: DeleteManagedEnvironmentVariableByID :exec
+DELETE FROM
+	managed_environment_variables
+WHERE
+	id = $1;
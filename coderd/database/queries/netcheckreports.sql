@@ -0,0 +1,43 @@
+-- name: InsertNetcheckReport :one
+INSERT INTO
+	netcheck_reports (
+		id,
+		user_id,
+		created_at,
+		client_version,
+		client_os,
+		nat_type,
+		udp,
+		ipv4,
+		ipv6,
+		mapping_varies_by_dest_ip,
+		hair_pinning,
+		preferred_derp,
+		region_latencies,
+		blocked_ports
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING *;
+
+-- name: GetNetcheckReports :many
+-- GetNetcheckReports returns the reports created in the time range, newest
+-- first, optionally filtered by user.
+SELECT
+	*
+FROM
+	netcheck_reports
+WHERE
+	created_at >= @start_time
+	AND created_at < @end_time
+	AND CASE
+		WHEN @user_id::uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			user_id = @user_id
+		ELSE true
+	END
+ORDER BY
+	created_at DESC
+LIMIT
+	@limit_opt::int;
+
+-- name: DeleteOldNetcheckReports :exec
+DELETE FROM netcheck_reports WHERE created_at < NOW() - INTERVAL '30 days';
@@ -42,3 +42,13 @@ INSERT INTO
 	organizations (id, "name", description, created_at, updated_at)
 VALUES
 	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: UpdateOrganizationProvisionerLimits :one
+UPDATE
+	organizations
+SET
+	updated_at = $2,
+	max_concurrent_provisioner_jobs = $3
+WHERE
+	id = $1
+RETURNING *;
@@ -0,0 +1,27 @@
+-- name: InsertPlatformEvent :one
+INSERT INTO
+	platform_events (
+		created_at,
+		type,
+		resource_id,
+		data
+	)
+VALUES
+	($1, $2, $3, $4) RETURNING *;
+
+-- name: GetPlatformEventsAfterID :many
+-- GetPlatformEventsAfterID returns events newer than the given cursor, oldest
+-- first.
+SELECT
+	*
+FROM
+	platform_events
+WHERE
+	id > @after_id
+ORDER BY
+	id ASC
+LIMIT
+	@limit_opt::int;
+
+-- name: DeleteOldPlatformEvents :exec
+DELETE FROM platform_events WHERE created_at < NOW() - INTERVAL '7 days';
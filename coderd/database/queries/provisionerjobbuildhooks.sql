@@ -0,0 +1,25 @@
+-- name: GetProvisionerJobBuildHooksByJobID :many
+SELECT
+	*
+FROM
+	provisioner_job_build_hooks
+WHERE
+	job_id = @job_id
+ORDER BY
+	started_at ASC;
+
+-- name: InsertProvisionerJobBuildHook :one
+INSERT INTO
+	provisioner_job_build_hooks (
+		id,
+		job_id,
+		name,
+		phase,
+		started_at,
+		completed_at,
+		attempts,
+		exit_code,
+		error
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
@@ -5,6 +5,54 @@
 -- multiple provisioners from acquiring the same jobs. See:
 -- https://www.postgresql.org/docs/9.5/sql-select.html#SQL-FOR-UPDATE-SHARE
 -- name: AcquireProvisionerJob :one
+WITH running_jobs AS (
+	-- Template imports are linked to their template through the template
+	-- version, and workspace builds through the workspace.
+	SELECT
+		provisioner_jobs.organization_id,
+		COALESCE(template_versions.template_id, workspaces.template_id) AS template_id
+	FROM
+		provisioner_jobs
+	LEFT JOIN
+		template_versions ON template_versions.job_id = provisioner_jobs.id
+	LEFT JOIN
+		workspace_builds ON workspace_builds.job_id = provisioner_jobs.id
+	LEFT JOIN
+		workspaces ON workspaces.id = workspace_builds.workspace_id
+	WHERE
+		provisioner_jobs.started_at IS NOT NULL
+		AND provisioner_jobs.completed_at IS NULL
+),
+-- Templates and organizations that already run as many jobs as their
+-- concurrency limit allows.
+limited_templates AS (
+	SELECT
+		templates.id
+	FROM
+		templates
+	JOIN
+		running_jobs ON running_jobs.template_id = templates.id
+	WHERE
+		templates.max_concurrent_provisioner_jobs > 0
+	GROUP BY
+		templates.id
+	HAVING
+		COUNT(*) >= templates.max_concurrent_provisioner_jobs
+),
+limited_organizations AS (
+	SELECT
+		organizations.id
+	FROM
+		organizations
+	JOIN
+		running_jobs ON running_jobs.organization_id = organizations.id
+	WHERE
+		organizations.max_concurrent_provisioner_jobs > 0
+	GROUP BY
+		organizations.id
+	HAVING
+		COUNT(*) >= organizations.max_concurrent_provisioner_jobs
+)
 UPDATE
 	provisioner_jobs
 SET
@@ -14,18 +62,70 @@ SET
 WHERE
 	id = (
 		SELECT
-			id
+			nested.id
 		FROM
 			provisioner_jobs AS nested
+		LEFT JOIN
+			template_versions ON template_versions.job_id = nested.id
+		LEFT JOIN
+			workspace_builds ON workspace_builds.job_id = nested.id
+		LEFT JOIN
+			workspaces ON workspaces.id = workspace_builds.workspace_id
 		WHERE
 			nested.started_at IS NULL
 			-- Ensure the caller has the correct provisioner.
 			AND nested.provisioner = ANY(@types :: provisioner_type [ ])
-			-- Ensure the caller satisfies all job tags.
-			AND nested.tags <@ @tags :: jsonb
+			-- Ensure the caller satisfies all job tags. A job tag value of "*"
+			-- is satisfied by any value, and values separated by "|" by any of
+			-- them. Keep in sync with provisionerdserver.TagsSatisfy.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					jsonb_each_text(nested.tags) AS job_tag
+				WHERE
+					((@tags :: jsonb) ->> job_tag.key) IS NULL
+					OR (
+						job_tag.value != '*'
+						AND NOT ((@tags :: jsonb) ->> job_tag.key) = ANY(string_to_array(job_tag.value, '|'))
+					)
+			)
+			-- Regional provisioners only acquire jobs pinned to their region,
+			-- and jobs pinned to a region only run there.
+			AND COALESCE(nested.tags ->> 'region', '') = COALESCE((@tags :: jsonb) ->> 'region', '')
+			-- Skip jobs that are held until they are approved.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					workspace_approvals
+				WHERE
+					workspace_approvals.job_id = nested.id
+					AND workspace_approvals.status != 'approved'
+			)
+			-- Skip jobs whose template or organization already runs as many
+			-- jobs as its concurrency limit allows. Concurrent acquires can
+			-- both see room under a limit, so callers check it again with
+			-- GetProvisionerJobConcurrencyLimit under a lock.
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					limited_organizations
+				WHERE
+					limited_organizations.id = nested.organization_id
+			)
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					limited_templates
+				WHERE
+					limited_templates.id = COALESCE(template_versions.template_id, workspaces.template_id)
+			)
 		ORDER BY
 			nested.created_at
-		FOR UPDATE
+		FOR UPDATE OF nested
 		SKIP LOCKED
 		LIMIT
 			1
@@ -39,6 +139,13 @@ FROM
 WHERE
 	id = $1;
 
+-- name: GetProvisionerJobConcurrencyLimit :one
+-- GetProvisionerJobConcurrencyLimit returns the concurrency limit that the job
+-- would exceed if it ran, 'template' or 'organization', or an empty string.
+-- The job doesn't count against the limit itself, so it may already be
+-- started.
+SELECT provisioner_job_concurrency_limit(@id :: uuid) :: text AS limited_by;
+
 -- name: GetProvisionerJobsByIDs :many
 SELECT
 	*
@@ -69,7 +176,9 @@ queue_size AS (
 SELECT
 	sqlc.embed(pj),
     COALESCE(qp.queue_position, 0) AS queue_position,
-    COALESCE(qs.count, 0) AS queue_size
+    COALESCE(qs.count, 0) AS queue_size,
+    -- The concurrency limit that holds back the job, if any.
+    (CASE WHEN pj.started_at IS NULL THEN provisioner_job_concurrency_limit(pj.id) ELSE '' END) :: text AS queue_limited_by
 FROM
 	provisioner_jobs pj
 LEFT JOIN
@@ -82,6 +191,18 @@ WHERE
 -- name: GetProvisionerJobsCreatedAfter :many
 SELECT * FROM provisioner_jobs WHERE created_at > $1;
 
+-- name: GetProvisionerJobsStartedAfter :many
+-- GetProvisionerJobsStartedAfter returns the jobs acquired by a provisioner
+-- daemon after the given time, which is used to measure queue wait times.
+SELECT
+	*
+FROM
+	provisioner_jobs
+WHERE
+	started_at > @started_after :: timestamptz
+ORDER BY
+	started_at ASC;
+
 -- name: InsertProvisionerJob :one
 INSERT INTO
 	provisioner_jobs (
@@ -125,7 +246,21 @@ SET
 	updated_at = $2,
 	completed_at = $3,
 	error = $4,
-	error_code = $5
+	error_code = $5,
+	cancel_outcome = $6
+WHERE
+	id = $1;
+
+-- Puts a hung job back in the queue so another provisioner daemon can
+-- acquire it.
+-- name: UpdateProvisionerJobWithRequeueByID :exec
+UPDATE
+	provisioner_jobs
+SET
+	updated_at = $2,
+	started_at = NULL,
+	worker_id = NULL,
+	requeue_count = requeue_count + 1
 WHERE
 	id = $1;
 
@@ -138,3 +273,16 @@ WHERE
 	updated_at < $1
 	AND started_at IS NOT NULL
 	AND completed_at IS NULL;
+
+-- name: GetPendingProvisionerJobs :many
+-- GetPendingProvisionerJobs returns the jobs that haven't been acquired by a
+-- provisioner daemon yet, oldest first.
+SELECT
+	*
+FROM
+	provisioner_jobs
+WHERE
+	started_at IS NULL
+	AND canceled_at IS NULL
+ORDER BY
+	created_at ASC;
@@ -62,6 +62,18 @@ WHERE
 RETURNING *
 ;
 
+-- name: UpdateWorkspaceProxyACL :one
+-- Replaces the organizations and groups whose members may use the proxy.
+UPDATE
+	workspace_proxies
+SET
+	organization_ids = @organization_ids :: uuid[],
+	group_ids = @group_ids :: uuid[],
+	updated_at = Now()
+WHERE
+	id = @id
+RETURNING *;
+
 -- name: GetWorkspaceProxyByID :one
 SELECT
 	*
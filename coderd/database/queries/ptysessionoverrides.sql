@@ -0,0 +1,30 @@
+-- name: GetUserPTYSessionOverride :one
+SELECT
+	*
+FROM
+	user_pty_session_overrides
+WHERE
+	user_id = $1;
+
+-- name: UpsertUserPTYSessionOverride :one
+INSERT INTO
+	user_pty_session_overrides (
+		user_id,
+		max_pty_sessions,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $3)
+ON CONFLICT
+	(user_id)
+DO UPDATE SET
+	max_pty_sessions = $2,
+	updated_at = $3
+RETURNING *;
+
+-- name: DeleteUserPTYSessionOverride :exec
+DELETE FROM
+	user_pty_session_overrides
+WHERE
+	user_id = $1;
@@ -1,14 +1,17 @@
--- name: GetQuotaAllowanceForUser :one
+-- name: GetQuotaBudgetsForUser :many
+-- Returns the groups that contribute to the quota of the user: the groups the
+-- user is a member of, and the Everyone groups, whose allowance is the
+-- organization default.
 SELECT
-	coalesce(SUM(quota_allowance), 0)::BIGINT
+	g.*
 FROM
 	groups g
-LEFT JOIN group_members gm ON
-	g.id = gm.group_id
 WHERE
-	user_id = $1
+	g.id IN (SELECT group_id FROM group_members WHERE user_id = $1)
 OR
-    g.id = g.organization_id;
+	g.id = g.organization_id
+ORDER BY
+	g.name ASC;
 
 -- name: GetQuotaConsumedForUser :one
 WITH latest_builds AS (
@@ -30,3 +33,61 @@ FROM
 JOIN latest_builds ON
 	latest_builds.workspace_id = workspaces.id
 WHERE NOT deleted AND workspaces.owner_id = $1;
+
+-- name: GetQuotaConsumedWorkspacesForUser :many
+-- Returns the daily cost of the latest build of each workspace of the user.
+-- The costs add up to GetQuotaConsumedForUser.
+WITH latest_builds AS (
+SELECT
+	DISTINCT ON
+	(workspace_id) id,
+	workspace_id,
+	daily_cost
+FROM
+	workspace_builds wb
+ORDER BY
+	workspace_id,
+	created_at DESC
+)
+SELECT
+	workspaces.id AS workspace_id,
+	workspaces.name AS workspace_name,
+	latest_builds.daily_cost
+FROM
+	workspaces
+JOIN latest_builds ON
+	latest_builds.workspace_id = workspaces.id
+WHERE NOT deleted AND workspaces.owner_id = $1
+ORDER BY
+	latest_builds.daily_cost DESC, workspaces.name ASC;
+
+-- name: GetUserQuotaOverride :one
+SELECT
+	*
+FROM
+	user_quota_overrides
+WHERE
+	user_id = $1;
+
+-- name: UpsertUserQuotaOverride :one
+INSERT INTO
+	user_quota_overrides (
+		user_id,
+		quota_allowance,
+		created_at,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $3)
+ON CONFLICT
+	(user_id)
+DO UPDATE SET
+	quota_allowance = $2,
+	updated_at = $3
+RETURNING *;
+
+-- name: DeleteUserQuotaOverride :exec
+DELETE FROM
+	user_quota_overrides
+WHERE
+	user_id = $1;
@@ -32,5 +32,10 @@ UPDATE replicas SET
 	"primary" = $11
 WHERE id = $1 RETURNING *;
 
+-- name: UpdateReplicaDrainingAt :one
+UPDATE replicas SET
+	draining_at = $2
+WHERE id = $1 AND stopped_at IS NULL RETURNING *;
+
 -- name: DeleteReplicasUpdatedBefore :exec
 DELETE FROM replicas WHERE updated_at < $1;
@@ -63,3 +63,17 @@ SELECT value FROM site_configs WHERE key = 'oauth_signing_key';
 -- name: UpsertOAuthSigningKey :exec
 INSERT INTO site_configs (key, value) VALUES ('oauth_signing_key', $1)
 ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'oauth_signing_key';
+
+-- name: GetSSHCAKey :one
+SELECT value FROM site_configs WHERE key = 'ssh_ca_key';
+
+-- name: UpsertSSHCAKey :exec
+INSERT INTO site_configs (key, value) VALUES ('ssh_ca_key', $1)
+ON CONFLICT (key) DO UPDATE set value = $1 WHERE site_configs.key = 'ssh_ca_key';
+
+-- name: UpsertRuntimeExperiments :exec
+INSERT INTO site_configs (key, value) VALUES ('runtime_experiments', $1)
+ON CONFLICT (key) DO UPDATE SET value = $1 WHERE site_configs.key = 'runtime_experiments';
+
+-- name: GetRuntimeExperiments :one
+SELECT value FROM site_configs WHERE key = 'runtime_experiments';
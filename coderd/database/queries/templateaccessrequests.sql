@@ -0,0 +1,49 @@
+-- name: GetTemplateAccessRequestByID :one
+SELECT
+	*
+FROM
+	template_access_requests
+WHERE
+	id = $1;
+
+-- name: GetTemplateAccessRequestsByTemplateID :many
+SELECT
+	*
+FROM
+	template_access_requests
+WHERE
+	template_id = @template_id
+	AND CASE
+		WHEN @status :: text != '' THEN
+			status = @status :: template_access_request_status
+		ELSE true
+	END
+ORDER BY
+	created_at DESC;
+
+-- name: InsertTemplateAccessRequest :one
+INSERT INTO
+	template_access_requests (
+		id,
+		template_id,
+		requested_by,
+		created_at,
+		message
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: UpdateTemplateAccessRequestStatusByID :one
+-- Records the decision on a pending access request. Requests can only be
+-- decided once, so no rows are returned if the request was already decided.
+UPDATE
+	template_access_requests
+SET
+	status = $2,
+	decided_by = $3,
+	decided_at = $4,
+	reason = $5
+WHERE
+	id = $1
+	AND status = 'pending'
+RETURNING *;
@@ -82,7 +82,8 @@ UPDATE
 	templates
 SET
 	active_version_id = $2,
-	updated_at = $3
+	updated_at = $3,
+	active_version_updated_at = $3
 WHERE
 	id = $1;
 
@@ -104,7 +105,18 @@ SET
 	name = $4,
 	icon = $5,
 	display_name = $6,
-	allow_user_cancel_workspace_jobs = $7
+	allow_user_cancel_workspace_jobs = $7,
+	require_active_version = $8,
+	require_active_version_grace_period = $9,
+	max_build_duration = $10,
+	provisioner_memory_limit = $11,
+	provisioner_cpu_limit = $12,
+	require_workspace_approval = $13,
+	require_agent_binary_verification = $14,
+	require_promotion_approval = $15,
+	max_concurrent_provisioner_jobs = $16,
+	max_session_idle = $17,
+	port_policy = $18
 WHERE
 	id = $1
 ;
@@ -122,7 +134,8 @@ SET
 	restart_requirement_weeks = $8,
 	failure_ttl = $9,
 	inactivity_ttl = $10,
-	locked_ttl = $11
+	locked_ttl = $11,
+	autostop_activity_sources = $12
 WHERE
 	id = $1
 ;
@@ -0,0 +1,84 @@
+-- name: UpsertTemplateVersionBuildInsights :exec
+-- UpsertTemplateVersionBuildInsights recomputes the build rollup of every
+-- template version that had a workspace build complete since the given time.
+-- Canceled builds are excluded as they say nothing about the health of the
+-- template version.
+WITH versions AS (
+	SELECT DISTINCT wb.template_version_id
+	FROM workspace_builds wb
+	JOIN provisioner_jobs pj ON (pj.id = wb.job_id)
+	WHERE pj.completed_at >= @since::timestamptz
+), builds AS (
+	SELECT
+		wb.template_version_id,
+		tv.template_id,
+		(pj.error IS NOT NULL AND pj.error != '') AS failed,
+		COALESCE(pj.error_code, '') AS error_code,
+		EXTRACT(EPOCH FROM (pj.completed_at - pj.started_at)) AS duration_seconds
+	FROM workspace_builds wb
+	JOIN provisioner_jobs pj ON (pj.id = wb.job_id)
+	JOIN template_versions tv ON (tv.id = wb.template_version_id)
+	WHERE
+		wb.template_version_id IN (SELECT template_version_id FROM versions)
+		AND tv.template_id IS NOT NULL
+		AND pj.completed_at IS NOT NULL
+		AND pj.started_at IS NOT NULL
+		AND pj.canceled_at IS NULL
+), failures AS (
+	SELECT template_version_id, jsonb_object_agg(error_code, builds) AS failures
+	FROM (
+		SELECT template_version_id, error_code, COUNT(*) AS builds
+		FROM builds
+		WHERE failed
+		GROUP BY template_version_id, error_code
+	) f
+	GROUP BY template_version_id
+)
+INSERT INTO
+	template_version_build_insights (
+		template_version_id,
+		template_id,
+		total_builds,
+		failed_builds,
+		failures,
+		duration_seconds_50,
+		duration_seconds_95,
+		updated_at
+	)
+SELECT
+	b.template_version_id,
+	b.template_id,
+	COUNT(*)::bigint,
+	COUNT(*) FILTER (WHERE b.failed)::bigint,
+	COALESCE(f.failures, '{}'::jsonb),
+	coalesce((PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY b.duration_seconds) FILTER (WHERE NOT b.failed)), -1)::FLOAT,
+	coalesce((PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY b.duration_seconds) FILTER (WHERE NOT b.failed)), -1)::FLOAT,
+	@updated_at::timestamptz
+FROM builds b
+LEFT JOIN failures f ON (f.template_version_id = b.template_version_id)
+GROUP BY b.template_version_id, b.template_id, f.failures
+ON CONFLICT (template_version_id) DO UPDATE SET
+	total_builds = EXCLUDED.total_builds,
+	failed_builds = EXCLUDED.failed_builds,
+	failures = EXCLUDED.failures,
+	duration_seconds_50 = EXCLUDED.duration_seconds_50,
+	duration_seconds_95 = EXCLUDED.duration_seconds_95,
+	updated_at = EXCLUDED.updated_at;
+
+-- name: GetTemplateVersionBuildInsights :many
+-- GetTemplateVersionBuildInsights returns the build rollup of the versions of a
+-- template that have completed builds, newest version first.
+SELECT
+	tvbi.template_version_id,
+	tv.name AS template_version_name,
+	tv.created_at AS template_version_created_at,
+	tvbi.total_builds,
+	tvbi.failed_builds,
+	tvbi.failures,
+	tvbi.duration_seconds_50,
+	tvbi.duration_seconds_95,
+	tvbi.updated_at
+FROM template_version_build_insights tvbi
+JOIN template_versions tv ON (tv.id = tvbi.template_version_id)
+WHERE tvbi.template_id = @template_id
+ORDER BY tv.created_at DESC, tv.name ASC;
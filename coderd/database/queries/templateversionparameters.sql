@@ -17,7 +17,9 @@ INSERT INTO
         required,
         display_name,
         display_order,
-        ephemeral
+        ephemeral,
+        dependencies,
+        options_url
     )
 VALUES
     (
@@ -37,7 +39,9 @@ VALUES
         $14,
         $15,
         $16,
-        $17
+        $17,
+        $18,
+        $19
     ) RETURNING *;
 
 -- name: GetTemplateVersionParameters :many
@@ -0,0 +1,55 @@
+-- name: GetTemplateVersionPromotionByID :one
+SELECT
+	*
+FROM
+	template_version_promotions
+WHERE
+	id = $1;
+
+-- name: GetTemplateVersionPromotions :many
+SELECT
+	*
+FROM
+	template_version_promotions
+WHERE
+	CASE
+		WHEN @template_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			template_id = @template_id
+		ELSE true
+	END
+	AND CASE
+		WHEN @status :: text != '' THEN
+			status = @status :: template_version_promotion_status
+		ELSE true
+	END
+ORDER BY
+	created_at DESC;
+
+-- name: InsertTemplateVersionPromotion :one
+INSERT INTO
+	template_version_promotions (
+		id,
+		organization_id,
+		template_id,
+		template_version_id,
+		requested_by,
+		created_at,
+		message
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: UpdateTemplateVersionPromotionStatusByID :one
+-- Records the decision on a pending promotion. Promotions can only be decided
+-- once, so no rows are returned if the promotion was already decided.
+UPDATE
+	template_version_promotions
+SET
+	status = $2,
+	decided_by = $3,
+	decided_at = $4,
+	reason = $5
+WHERE
+	id = $1
+	AND status = 'pending'
+RETURNING *;
@@ -0,0 +1,26 @@
+-- name: GetUserDeprovisions :many
+SELECT
+	*
+FROM
+	user_deprovisions
+ORDER BY
+	deprovisioned_at ASC;
+
+-- name: InsertUserDeprovision :exec
+-- Keeps the original deprovisioning time if the identity provider
+-- deactivates a user more than once.
+INSERT INTO
+	user_deprovisions (
+		user_id,
+		deprovisioned_at
+	)
+VALUES
+	($1, $2)
+ON CONFLICT (user_id)
+DO NOTHING;
+
+-- name: DeleteUserDeprovision :exec
+DELETE FROM
+	user_deprovisions
+WHERE
+	user_id = $1;
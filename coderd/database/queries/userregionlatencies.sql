@@ -0,0 +1,25 @@
+-- name: GetUserRegionLatencies :many
+SELECT
+	*
+FROM
+	user_region_latencies
+WHERE
+	user_id = @user_id AND
+	updated_at > @updated_after
+ORDER BY
+	latency_ms ASC;
+
+-- name: UpsertUserRegionLatency :exec
+INSERT INTO
+	user_region_latencies (
+		user_id,
+		region_id,
+		latency_ms,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (user_id, region_id)
+DO UPDATE SET
+	latency_ms = $3,
+	updated_at = $4;
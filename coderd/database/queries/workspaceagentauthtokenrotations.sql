@@ -0,0 +1,27 @@
+-- name: InsertWorkspaceAgentAuthTokenRotation :one
+INSERT INTO
+	workspace_agent_auth_token_rotations (
+		id,
+		agent_id,
+		previous_auth_token,
+		previous_auth_token_expires_at,
+		revoked,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: ExpireWorkspaceAgentAuthTokenRotations :exec
+-- Expires the previous auth tokens of the agent that are still valid.
+UPDATE
+	workspace_agent_auth_token_rotations
+SET
+	previous_auth_token_expires_at = @now
+WHERE
+	agent_id = @agent_id
+	AND previous_auth_token_expires_at > @now;
+
+-- name: DeleteOldWorkspaceAgentAuthTokenRotations :exec
+-- Rotations are only needed to accept the previous token, the audit log
+-- keeps their history.
+DELETE FROM workspace_agent_auth_token_rotations WHERE previous_auth_token_expires_at < NOW() - INTERVAL '30 days';
@@ -50,10 +50,11 @@ INSERT INTO
 		startup_script_behavior,
 		startup_script_timeout_seconds,
 		shutdown_script,
-		shutdown_script_timeout_seconds
+		shutdown_script_timeout_seconds,
+		display_order
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22) RETURNING *;
 
 -- name: UpdateWorkspaceAgentConnectionByID :exec
 UPDATE
@@ -73,7 +74,25 @@ UPDATE
 SET
 	version = $2,
 	expanded_directory = $3,
-	subsystems = $4
+	subsystems = $4,
+	host_facts = $5
+WHERE
+	id = $1;
+
+-- name: UpdateWorkspaceAgentClockOffsetByID :exec
+UPDATE
+	workspace_agents
+SET
+	clock_offset_ms = $2
+WHERE
+	id = $1;
+
+-- name: UpdateWorkspaceAgentAuthTokenByID :exec
+UPDATE
+	workspace_agents
+SET
+	auth_token = $2,
+	updated_at = $3
 WHERE
 	id = $1;
 
@@ -130,6 +149,142 @@ FROM
 WHERE
 	workspace_agent_id = $1;
 
+-- name: InsertWorkspaceAgentMetadataHistory :exec
+-- InsertWorkspaceAgentMetadataHistory records a collected metadata value and
+-- drops the oldest values of the key so that at most max_entries values are
+-- retained.
+WITH inserted AS (
+	INSERT INTO
+		workspace_agent_metadata_history (
+			workspace_agent_id,
+			key,
+			value,
+			error,
+			collected_at
+		)
+	SELECT
+		@workspace_agent_id, @key, @value, @error, @collected_at
+	WHERE
+		-- Only keys declared by the agent are recorded, otherwise a
+		-- misbehaving agent could grow the table without bound.
+		EXISTS (
+			SELECT
+				1
+			FROM
+				workspace_agent_metadata
+			WHERE
+				workspace_agent_id = @workspace_agent_id
+				AND key = @key
+		)
+)
+DELETE FROM
+	workspace_agent_metadata_history
+WHERE
+	workspace_agent_id = @workspace_agent_id
+	AND key = @key
+	-- The inserted row is not visible to this statement, so only keep
+	-- max_entries - 1 of the existing rows.
+	AND id <= (
+		SELECT
+			id
+		FROM
+			workspace_agent_metadata_history
+		WHERE
+			workspace_agent_id = @workspace_agent_id
+			AND key = @key
+		ORDER BY
+			id DESC
+		OFFSET
+			@max_entries::int - 1
+		LIMIT
+			1
+	);
+
+-- name: GetWorkspaceAgentMetadataHistory :many
+-- GetWorkspaceAgentMetadataHistory returns the most recent values of a
+-- metadata key, newest first.
+SELECT
+	*
+FROM
+	workspace_agent_metadata_history
+WHERE
+	workspace_agent_id = @workspace_agent_id
+	AND key = @key
+	AND collected_at > @collected_after
+ORDER BY
+	id DESC
+LIMIT
+	@limit_opt::int;
+
+-- name: InsertWorkspaceAgentClientConnection :one
+INSERT INTO
+	workspace_agent_client_connections (
+		id,
+		workspace_agent_id,
+		user_id,
+		client_type,
+		client_version,
+		connected_at,
+		ip_address
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: UpdateWorkspaceAgentClientConnectionDisconnectedAt :exec
+UPDATE
+	workspace_agent_client_connections
+SET
+	disconnected_at = $2
+WHERE
+	id = $1;
+
+-- name: GetWorkspaceAgentClientConnections :many
+-- GetWorkspaceAgentClientConnections returns the most recent client
+-- connections made to an agent, newest first.
+SELECT
+	*
+FROM
+	workspace_agent_client_connections
+WHERE
+	workspace_agent_id = @workspace_agent_id
+	AND connected_at > @connected_after
+ORDER BY
+	connected_at DESC
+LIMIT
+	@limit_opt::int;
+
+-- name: GetWorkspaceAgentClientConnectionsByUserID :many
+-- GetWorkspaceAgentClientConnectionsByUserID returns the most recent client
+-- connections made by a user to any agent, newest first.
+SELECT
+	workspace_agent_client_connections.*,
+	workspace_agents.name AS agent_name,
+	workspaces.id AS workspace_id,
+	workspaces.name AS workspace_name,
+	users.username AS workspace_owner_username
+FROM
+	workspace_agent_client_connections
+JOIN
+	workspace_agents ON workspace_agents.id = workspace_agent_client_connections.workspace_agent_id
+JOIN
+	workspace_resources ON workspace_resources.id = workspace_agents.resource_id
+JOIN
+	workspace_builds ON workspace_builds.job_id = workspace_resources.job_id
+JOIN
+	workspaces ON workspaces.id = workspace_builds.workspace_id
+JOIN
+	users ON users.id = workspaces.owner_id
+WHERE
+	workspace_agent_client_connections.user_id = @user_id
+	AND workspace_agent_client_connections.connected_at > @connected_after
+ORDER BY
+	workspace_agent_client_connections.connected_at DESC
+LIMIT
+	@limit_opt::int;
+
+-- name: DeleteOldWorkspaceAgentClientConnections :exec
+DELETE FROM workspace_agent_client_connections WHERE connected_at < NOW() - INTERVAL '30 days';
+
 -- name: UpdateWorkspaceAgentLogOverflowByID :exec
 UPDATE
 	workspace_agents
@@ -191,6 +346,48 @@ WHERE
 			wb.workspace_id = @workspace_id :: uuid
 	);
 
+-- name: GetWorkspaceAgentsNeverConnected :many
+-- GetWorkspaceAgentsNeverConnected returns the agents of the latest successful
+-- start builds of workspaces that haven't connected by the end of their
+-- connection timeout, where the timeout elapsed within the given timeframe.
+-- Agents without a connection timeout use the default one.
+SELECT
+	workspace_agents.id AS agent_id,
+	workspace_agents.name AS agent_name,
+	workspaces.id AS workspace_id,
+	workspaces.name AS workspace_name,
+	templates.id AS template_id,
+	templates.name AS template_name
+FROM
+	workspace_agents
+JOIN
+	workspace_resources ON workspace_agents.resource_id = workspace_resources.id
+JOIN
+	workspace_builds ON workspace_resources.job_id = workspace_builds.job_id
+JOIN
+	provisioner_jobs ON workspace_builds.job_id = provisioner_jobs.id
+JOIN
+	workspaces ON workspace_builds.workspace_id = workspaces.id
+JOIN
+	templates ON workspaces.template_id = templates.id
+WHERE
+	workspace_agents.first_connected_at IS NULL AND
+	workspace_builds.transition = 'start' AND
+	(provisioner_jobs.error IS NULL OR provisioner_jobs.error = '') AND
+	provisioner_jobs.canceled_at IS NULL AND
+	NOT workspaces.deleted AND
+	provisioner_jobs.completed_at + make_interval(secs => COALESCE(NULLIF(workspace_agents.connection_timeout_seconds, 0), @default_connection_timeout_seconds::int)) > @deadline_after::timestamptz AND
+	provisioner_jobs.completed_at + make_interval(secs => COALESCE(NULLIF(workspace_agents.connection_timeout_seconds, 0), @default_connection_timeout_seconds::int)) <= @deadline_before::timestamptz AND
+	workspace_builds.build_number = (
+		SELECT
+			MAX(build_number)
+		FROM
+			workspace_builds AS wb
+		WHERE
+			wb.workspace_id = workspaces.id
+	)
+ORDER BY workspace_agents.id ASC;
+
 -- name: GetWorkspaceAgentAndOwnerByAuthToken :one
 SELECT
 	sqlc.embed(workspace_agents),
@@ -234,6 +431,17 @@ WHERE
 	-- 2) The user must not be deleted
 	-- 3) The workspace must be running
 	workspace_agents.auth_token = @auth_token
+	-- Rotated tokens stay valid until they expire, so requests of the agent
+	-- in flight during the rotation succeed.
+	OR workspace_agents.id IN (
+		SELECT
+			agent_id
+		FROM
+			workspace_agent_auth_token_rotations
+		WHERE
+			previous_auth_token = @auth_token
+			AND previous_auth_token_expires_at > NOW()
+	)
 GROUP BY
 	workspace_agents.id,
 	workspaces.id,
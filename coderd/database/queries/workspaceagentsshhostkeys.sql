@@ -0,0 +1,25 @@
+-- name: GetWorkspaceAgentSSHHostKey :one
+SELECT
+	*
+FROM
+	workspace_agent_ssh_host_keys
+WHERE
+	workspace_id = $1
+	AND agent_name = $2;
+
+-- name: InsertWorkspaceAgentSSHHostKey :one
+-- Returns the existing key instead if the agent already has one, so agents
+-- that fetch their manifest concurrently get the same key.
+INSERT INTO
+	workspace_agent_ssh_host_keys (
+		workspace_id,
+		agent_name,
+		encrypted_private_key,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (workspace_id, agent_name) DO UPDATE SET
+	encrypted_private_key = workspace_agent_ssh_host_keys.encrypted_private_key
+RETURNING
+	*;
@@ -62,6 +62,34 @@ SELECT
 	unnest(@session_count_ssh :: bigint[]) AS session_count_ssh,
 	unnest(@connection_median_latency_ms :: double precision[]) AS connection_median_latency_ms;
 
+-- name: InsertWorkspaceAgentBandwidthStats :exec
+INSERT INTO
+	workspace_agent_bandwidth_stats (
+		id,
+		created_at,
+		user_id,
+		workspace_id,
+		template_id,
+		agent_id,
+		connection_type,
+		peer,
+		port,
+		rx_bytes,
+		tx_bytes
+	)
+SELECT
+	unnest(@id :: uuid[]) AS id,
+	unnest(@created_at :: timestamptz[]) AS created_at,
+	unnest(@user_id :: uuid[]) AS user_id,
+	unnest(@workspace_id :: uuid[]) AS workspace_id,
+	unnest(@template_id :: uuid[]) AS template_id,
+	unnest(@agent_id :: uuid[]) AS agent_id,
+	unnest(@connection_type :: text[]) AS connection_type,
+	unnest(@peer :: text[]) AS peer,
+	unnest(@port :: integer[]) AS port,
+	unnest(@rx_bytes :: bigint[]) AS rx_bytes,
+	unnest(@tx_bytes :: bigint[]) AS tx_bytes;
+
 -- name: GetTemplateDAUs :many
 SELECT
 	(created_at at TIME ZONE cast(@tz_offset::integer as text))::date as date,
@@ -92,6 +120,9 @@ ORDER BY
 -- name: DeleteOldWorkspaceAgentStats :exec
 DELETE FROM workspace_agent_stats WHERE created_at < NOW() - INTERVAL '30 days';
 
+-- name: DeleteOldWorkspaceAgentBandwidthStats :exec
+DELETE FROM workspace_agent_bandwidth_stats WHERE created_at < NOW() - INTERVAL '30 days';
+
 -- name: GetDeploymentWorkspaceAgentStats :one
 WITH agent_stats AS (
 	SELECT
@@ -195,3 +226,46 @@ JOIN
 	workspaces
 ON
 	workspaces.id = agent_stats.workspace_id;
+
+-- name: GetWorkspaceAgentBandwidthStatsAndLabels :many
+SELECT
+	users.username,
+	workspace_agents.name AS agent_name,
+	workspaces.name AS workspace_name,
+	workspace_agent_bandwidth_stats.connection_type,
+	coalesce(SUM(workspace_agent_bandwidth_stats.rx_bytes), 0)::bigint AS rx_bytes,
+	coalesce(SUM(workspace_agent_bandwidth_stats.tx_bytes), 0)::bigint AS tx_bytes
+FROM
+	workspace_agent_bandwidth_stats
+JOIN
+	users
+ON
+	users.id = workspace_agent_bandwidth_stats.user_id
+JOIN
+	workspace_agents
+ON
+	workspace_agents.id = workspace_agent_bandwidth_stats.agent_id
+JOIN
+	workspaces
+ON
+	workspaces.id = workspace_agent_bandwidth_stats.workspace_id
+WHERE
+	workspace_agent_bandwidth_stats.created_at > $1
+GROUP BY
+	users.username, workspace_agents.name, workspaces.name, workspace_agent_bandwidth_stats.connection_type;
+
+-- name: GetWorkspaceAgentBandwidthStatsByPeer :many
+-- Sums the traffic of the agents of a workspace by agent and peer, so it can
+-- be attributed to the tailnet clients connected to the agents.
+SELECT
+	agent_id,
+	peer,
+	coalesce(SUM(rx_bytes), 0)::bigint AS rx_bytes,
+	coalesce(SUM(tx_bytes), 0)::bigint AS tx_bytes
+FROM
+	workspace_agent_bandwidth_stats
+WHERE
+	workspace_id = @workspace_id
+	AND created_at > @created_after
+GROUP BY
+	agent_id, peer;
@@ -0,0 +1,54 @@
+-- name: GetWorkspaceApprovalByID :one
+SELECT
+	*
+FROM
+	workspace_approvals
+WHERE
+	id = $1;
+
+-- name: GetWorkspaceApprovalByJobID :one
+SELECT
+	*
+FROM
+	workspace_approvals
+WHERE
+	job_id = $1;
+
+-- name: GetLatestWorkspaceApprovalByWorkspaceID :one
+SELECT
+	*
+FROM
+	workspace_approvals
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at DESC
+LIMIT
+	1;
+
+-- name: InsertWorkspaceApproval :one
+INSERT INTO
+	workspace_approvals (
+		id,
+		workspace_id,
+		job_id,
+		requested_by,
+		created_at
+	)
+VALUES
+	($1, $2, $3, $4, $5) RETURNING *;
+
+-- name: UpdateWorkspaceApprovalStatusByID :one
+-- Records the decision on a pending approval request. Requests can only be
+-- decided once, so no rows are returned if the request was already decided.
+UPDATE
+	workspace_approvals
+SET
+	status = $2,
+	decided_by = $3,
+	decided_at = $4,
+	reason = $5
+WHERE
+	id = $1
+	AND status = 'pending'
+RETURNING *;
@@ -0,0 +1,42 @@
+-- name: GetWorkspaceBuildQueueEntriesByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_build_queue_entries
+WHERE
+	workspace_id = $1
+ORDER BY
+	created_at ASC,
+	id ASC;
+
+-- name: GetWorkspaceBuildQueueWorkspaceIDs :many
+-- Returns the workspaces that have builds waiting in their queue.
+SELECT DISTINCT
+	workspace_id
+FROM
+	workspace_build_queue_entries;
+
+-- name: InsertWorkspaceBuildQueueEntry :one
+INSERT INTO
+	workspace_build_queue_entries (
+		id,
+		workspace_id,
+		initiator_id,
+		initiator_token_name,
+		created_at,
+		request
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6) RETURNING *;
+
+-- name: DeleteWorkspaceBuildQueueEntryByID :exec
+DELETE FROM
+	workspace_build_queue_entries
+WHERE
+	id = $1;
+
+-- name: DeleteWorkspaceBuildQueueEntriesByWorkspaceID :exec
+DELETE FROM
+	workspace_build_queue_entries
+WHERE
+	workspace_id = $1;
@@ -57,6 +57,18 @@ WHERE
 		)
 		ELSE true
 END
+	-- Filter by the reason of the build.
+	AND CASE
+		WHEN @reason :: text != '' THEN
+			workspace_builds.reason :: text = @reason
+		ELSE true
+	END
+	-- Filter by the user who initiated the build.
+	AND CASE
+		WHEN @initiator_id :: uuid != '00000000-0000-0000-0000-000000000000'::uuid THEN
+			workspace_builds.initiator_id = @initiator_id
+		ELSE true
+	END
 ORDER BY
     build_number desc OFFSET @offset_opt
 LIMIT
@@ -120,10 +132,11 @@ INSERT INTO
 		provisioner_state,
 		deadline,
 		max_deadline,
-		reason
+		reason,
+		initiator_token_name
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13);
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
 
 -- name: UpdateWorkspaceBuildByID :exec
 UPDATE
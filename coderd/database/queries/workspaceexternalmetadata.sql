@@ -0,0 +1,38 @@
+-- name: GetWorkspaceExternalMetadataByWorkspaceIDs :many
+SELECT
+	workspace_external_metadata.*,
+	workspaces.owner_id,
+	workspaces.organization_id
+FROM
+	workspace_external_metadata
+JOIN
+	workspaces ON workspaces.id = workspace_external_metadata.workspace_id
+WHERE
+	workspace_external_metadata.workspace_id = ANY(@ids :: uuid [ ])
+ORDER BY
+	workspace_external_metadata.namespace ASC;
+
+-- name: UpsertWorkspaceExternalMetadatum :one
+INSERT INTO
+	workspace_external_metadata (
+		workspace_id,
+		namespace,
+		visibility,
+		data,
+		updated_at
+	)
+VALUES
+	($1, $2, $3, $4, $5)
+ON CONFLICT (workspace_id, namespace)
+DO UPDATE SET
+	visibility = $3,
+	data = $4,
+	updated_at = $5
+RETURNING *;
+
+-- name: DeleteWorkspaceExternalMetadatum :exec
+DELETE FROM
+	workspace_external_metadata
+WHERE
+	workspace_id = $1
+	AND namespace = $2;
@@ -0,0 +1,61 @@
+-- name: GetWorkspacePreviousNamesByWorkspaceIDs :many
+SELECT
+	workspace_previous_names.*,
+	workspaces.owner_id,
+	workspaces.organization_id
+FROM
+	workspace_previous_names
+JOIN
+	workspaces ON workspaces.id = workspace_previous_names.workspace_id
+WHERE
+	workspace_previous_names.workspace_id = ANY(@ids :: uuid [ ])
+ORDER BY
+	workspace_previous_names.renamed_at DESC;
+
+-- name: GetWorkspaceByOwnerIDAndPreviousName :one
+-- Returns the workspace most recently renamed away from the given name, as
+-- long as the redirect from that name has not expired.
+SELECT
+	*
+FROM
+	workspaces
+WHERE
+	workspaces.id = (
+		SELECT
+			workspace_previous_names.workspace_id
+		FROM
+			workspace_previous_names
+		JOIN
+			workspaces ON workspaces.id = workspace_previous_names.workspace_id
+		WHERE
+			workspaces.owner_id = @owner_id
+			AND workspaces.deleted = false
+			AND LOWER(workspace_previous_names.name) = LOWER(@name)
+			AND workspace_previous_names.redirect_expires_at > NOW()
+		ORDER BY
+			workspace_previous_names.renamed_at DESC
+		LIMIT
+			1
+	);
+
+-- name: UpsertWorkspacePreviousName :exec
+INSERT INTO
+	workspace_previous_names (
+		workspace_id,
+		name,
+		renamed_at,
+		redirect_expires_at
+	)
+VALUES
+	($1, $2, $3, $4)
+ON CONFLICT (workspace_id, name)
+DO UPDATE SET
+	renamed_at = $3,
+	redirect_expires_at = $4;
+
+-- name: DeleteWorkspacePreviousName :exec
+DELETE FROM
+	workspace_previous_names
+WHERE
+	workspace_id = $1
+	AND LOWER(name) = LOWER($2);
@@ -296,6 +296,34 @@ OFFSET
 	@offset_
 ;
 
+-- name: GetOutdatedWorkspacesByTemplateID :many
+-- Returns the workspaces of the template whose latest build doesn't use the
+-- active version of the template.
+SELECT
+	workspaces.*
+FROM
+	workspaces
+INNER JOIN
+	templates
+ON
+	templates.id = workspaces.template_id
+INNER JOIN LATERAL (
+	SELECT
+		workspace_builds.template_version_id
+	FROM
+		workspace_builds
+	WHERE
+		workspace_builds.workspace_id = workspaces.id
+	ORDER BY
+		workspace_builds.build_number DESC
+	LIMIT
+		1
+) latest_build ON TRUE
+WHERE
+	workspaces.template_id = @template_id
+	AND workspaces.deleted = false
+	AND latest_build.template_version_id != templates.active_version_id;
+
 -- name: GetWorkspaceByOwnerIDAndName :one
 SELECT
 	*
@@ -319,10 +347,11 @@ INSERT INTO
 		name,
 		autostart_schedule,
 		ttl,
-		last_used_at
+		last_used_at,
+		automatic_updates
 	)
 VALUES
-	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING *;
+	($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING *;
 
 -- name: UpdateWorkspaceDeletedByID :exec
 UPDATE
@@ -350,6 +379,14 @@ SET
 WHERE
 	id = $1;
 
+-- name: UpdateWorkspaceAutomaticUpdates :exec
+UPDATE
+	workspaces
+SET
+	automatic_updates = $2
+WHERE
+	id = $1;
+
 -- name: UpdateWorkspaceTTL :exec
 UPDATE
 	workspaces
@@ -0,0 +1,52 @@
+-- name: GetWorkspaceWebhookByID :one
+SELECT
+	*
+FROM
+	workspace_webhooks
+WHERE
+	id = $1;
+
+-- name: GetWorkspaceWebhooksByWorkspaceID :many
+SELECT
+	*
+FROM
+	workspace_webhooks
+WHERE
+	workspace_id = $1
+ORDER BY
+	name ASC;
+
+-- name: InsertWorkspaceWebhook :one
+INSERT INTO
+	workspace_webhooks (
+		id,
+		workspace_id,
+		created_by,
+		created_at,
+		name,
+		action,
+		agent_name,
+		command,
+		secret
+	)
+VALUES
+	($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING *;
+
+-- name: UpdateWorkspaceWebhookLastTriggeredAt :one
+-- UpdateWorkspaceWebhookLastTriggeredAt records the signed timestamp of an
+-- accepted trigger request. It returns no rows if the timestamp isn't later
+-- than the last accepted one, so a request can't be replayed.
+UPDATE
+	workspace_webhooks
+SET
+	last_triggered_at = $2
+WHERE
+	id = $1
+	AND (last_triggered_at IS NULL OR last_triggered_at < $2)
+RETURNING *;
+
+-- name: DeleteWorkspaceWebhookByID :exec
+DELETE FROM
+	workspace_webhooks
+WHERE
+	id = $1;
@@ -2,24 +2,207 @@ package coderd
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"os"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/healthcheck"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/wsconncache"
 	"github.com/coder/coder/v2/codersdk"
 )
 
+// debugCoordinator reports the coordinator of the replica that serves the
+// request. Browsers get the debug page of the coordinator instead.
+//
 // @Summary Debug Info Wireguard Coordinator
 // @ID debug-info-wireguard-coordinator
 // @Security CoderSessionToken
-// @Produce text/html
+// @Produce json
 // @Tags Debug
-// @Success 200
+// @Success 200 {object} healthcheck.CoordinatorReport
 // @Router /debug/coordinator [get]
 func (api *API) debugCoordinator(rw http.ResponseWriter, r *http.Request) {
-	(*api.TailnetCoordinator.Load()).ServeHTTPDebug(rw, r)
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		(*api.TailnetCoordinator.Load()).ServeHTTPDebug(rw, r)
+		return
+	}
+
+	ctx := r.Context()
+	var report healthcheck.CoordinatorReport
+	report.Run(ctx, &healthcheck.CoordinatorReportOptions{
+		ReplicaID: api.ID,
+		Status:    *api.TailnetCoordinatorStatus.Load(),
+		Pubsub:    api.Pubsub,
+	})
+	httpapi.Write(ctx, rw, http.StatusOK, report)
+}
+
+const (
+	// debugConnectionsRequestChannel receives the channel replicas publish
+	// their connections to.
+	debugConnectionsRequestChannel = "debug_connections_request"
+	// debugConnectionsTimeout is how long to wait for the connections of
+	// other replicas.
+	debugConnectionsTimeout = 3 * time.Second
+)
+
+func debugConnectionsResponseChannel(requestID string) string {
+	return "debug_connections_response:" + requestID
+}
+
+// @Summary Debug connections
+// @ID debug-connections
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Debug
+// @Success 200 {object} codersdk.DebugConnectionsReport
+// @Router /debug/connections [get]
+func (api *API) debugConnections(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	report := codersdk.DebugConnectionsReport{
+		ExpectedReplicas: 1,
+		Replicas:         []codersdk.ReplicaConnections{},
+	}
+	if fn := api.ReplicaCountFn.Load(); fn != nil {
+		report.ExpectedReplicas = (*fn)()
+	}
+
+	// Every replica, including this one, answers on the response channel.
+	requestID := uuid.NewString()
+	replicas := make(chan codersdk.ReplicaConnections, report.ExpectedReplicas)
+	cancelSub, err := api.Pubsub.Subscribe(debugConnectionsResponseChannel(requestID), func(_ context.Context, message []byte) {
+		var replica codersdk.ReplicaConnections
+		err := json.Unmarshal(message, &replica)
+		if err != nil {
+			api.Logger.Warn(ctx, "invalid debug connections response", slog.Error(err))
+			return
+		}
+		select {
+		case replicas <- replica:
+		default:
+		}
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	defer cancelSub()
+	err = api.Pubsub.Publish(debugConnectionsRequestChannel, []byte(requestID))
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	timer := time.NewTimer(debugConnectionsTimeout)
+	defer timer.Stop()
+	seen := map[uuid.UUID]struct{}{}
+collect:
+	for len(seen) < report.ExpectedReplicas {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			break collect
+		case replica := <-replicas:
+			if _, ok := seen[replica.ReplicaID]; ok {
+				continue
+			}
+			seen[replica.ReplicaID] = struct{}{}
+			report.Replicas = append(report.Replicas, replica)
+		}
+	}
+	sort.Slice(report.Replicas, func(i, j int) bool {
+		if report.Replicas[i].Hostname != report.Replicas[j].Hostname {
+			return report.Replicas[i].Hostname < report.Replicas[j].Hostname
+		}
+		return report.Replicas[i].ReplicaID.String() < report.Replicas[j].ReplicaID.String()
+	})
+	httpapi.Write(ctx, rw, http.StatusOK, report)
+}
+
+// handleDebugConnectionsRequest publishes the connections of this replica to
+// the replica that requested them.
+func (api *API) handleDebugConnectionsRequest(_ context.Context, message []byte) {
+	requestID, err := uuid.ParseBytes(message)
+	if err != nil {
+		api.Logger.Warn(api.ctx, "invalid debug connections request", slog.Error(err))
+		return
+	}
+	raw, err := json.Marshal(api.replicaConnections())
+	if err != nil {
+		api.Logger.Error(api.ctx, "marshal debug connections", slog.Error(err))
+		return
+	}
+	// Publish asynchronously, publishing from a listener blocks the in-memory
+	// pubsub.
+	go func() {
+		err := api.Pubsub.Publish(debugConnectionsResponseChannel(requestID.String()), raw)
+		if err != nil {
+			api.Logger.Warn(api.ctx, "publish debug connections", slog.Error(err))
+		}
+	}()
+}
+
+func (api *API) replicaConnections() codersdk.ReplicaConnections {
+	hostname, _ := os.Hostname()
+	replica := codersdk.ReplicaConnections{
+		ReplicaID: api.ID,
+		Hostname:  hostname,
+		Agents:    api.connectedAgents.Load(),
+		Clients:   api.connectedClients.Load(),
+	}
+	switch provider := api.agentProvider.(type) {
+	case *ServerTailnet:
+		replica.ServerTailnetAgents = int64(provider.AgentCount())
+		replica.ConnectionCacheEntries = int64(provider.CacheLen())
+	case *wsconncache.AgentProvider:
+		replica.ConnectionCacheEntries = int64(provider.Cache.Len())
+	}
+	return replica
+}
+
+// @Summary Debug DERP usage
+// @ID debug-derp-usage
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Debug
+// @Success 200 {object} codersdk.DERPUsageReport
+// @Router /debug/derp/usage [get]
+func (api *API) debugDERPUsage(rw http.ResponseWriter, r *http.Request) {
+	received, sent, connections := api.DERPUsage.Totals()
+	report := codersdk.DERPUsageReport{
+		RegionID:      api.DERPUsage.RegionID(),
+		ReceivedBytes: received,
+		SentBytes:     sent,
+		Connections:   connections,
+		Clients:       []codersdk.DERPClientUsage{},
+		Mesh:          []codersdk.DERPMeshUsage{},
+	}
+	for _, client := range api.DERPUsage.Clients() {
+		report.Clients = append(report.Clients, codersdk.DERPClientUsage{
+			Address:       client.Address,
+			ReceivedBytes: client.ReceivedBytes,
+			SentBytes:     client.SentBytes,
+			Connections:   client.Connections,
+			LastSeenAt:    client.LastSeen,
+		})
+	}
+	for _, mesh := range api.DERPUsage.Mesh() {
+		report.Mesh = append(report.Mesh, codersdk.DERPMeshUsage{
+			Address:       mesh.Address,
+			ReceivedBytes: mesh.ReceivedBytes,
+			SentBytes:     mesh.SentBytes,
+		})
+	}
+	httpapi.Write(r.Context(), rw, http.StatusOK, report)
 }
 
 // @Summary Debug Info Deployment Health
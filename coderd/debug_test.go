@@ -9,9 +9,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"tailscale.com/derp/derphttp"
+	"tailscale.com/types/key"
 
+	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/healthcheck"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/tailnet"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -108,6 +113,49 @@ func TestDebugHealth(t *testing.T) {
 	})
 }
 
+func TestDebugDERPUsage(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	derpURL, err := client.URL.Parse("/derp")
+	require.NoError(t, err)
+	derpClient, err := derphttp.NewClient(key.NewNode(), derpURL.String(), tailnet.Logger(slogtest.Make(t, nil)))
+	require.NoError(t, err)
+	defer derpClient.Close()
+	err = derpClient.Connect(ctx)
+	require.NoError(t, err)
+
+	var report codersdk.DERPUsageReport
+	require.Eventually(t, func() bool {
+		report, err = client.DERPUsage(ctx)
+		return assert.NoError(t, err) && report.Connections == 1
+	}, testutil.WaitShort, testutil.IntervalFast)
+	require.Positive(t, report.ReceivedBytes)
+	require.Positive(t, report.SentBytes)
+	require.Len(t, report.Clients, 1)
+	require.Equal(t, "127.0.0.1", report.Clients[0].Address)
+	require.Empty(t, report.Mesh)
+}
+
+func TestDebugConnections(t *testing.T) {
+	t.Parallel()
+
+	client, _, api := coderdtest.NewWithAPI(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	report, err := client.DebugConnections(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, report.ExpectedReplicas)
+	require.Len(t, report.Replicas, 1)
+	require.Equal(t, api.ID, report.Replicas[0].ReplicaID)
+	require.Zero(t, report.Replicas[0].Agents)
+	require.Zero(t, report.Replicas[0].Clients)
+}
+
 func TestDebugWebsocket(t *testing.T) {
 	t.Parallel()
 
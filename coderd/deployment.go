@@ -1,8 +1,10 @@
 package coderd
 
 import (
+	"context"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/coderd/httpapi"
@@ -38,6 +40,56 @@ func (api *API) deploymentValues(rw http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// @Summary Get redacted deployment config
+// @ID get-redacted-deployment-config
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.DeploymentConfig
+// @Router /deployment/config/redacted [get]
+func (api *API) deploymentValuesRedacted(rw http.ResponseWriter, r *http.Request) {
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceDeploymentValues) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	values, err := api.DeploymentValues.Redacted()
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(
+		r.Context(), rw, http.StatusOK,
+		codersdk.DeploymentConfig{
+			Values:  values,
+			Options: values.Options(),
+		},
+	)
+}
+
+// @Summary Get deployment config diff against defaults
+// @ID get-deployment-config-diff-against-defaults
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.DeploymentConfigDiff
+// @Router /deployment/config/diff [get]
+func (api *API) deploymentValuesDiff(rw http.ResponseWriter, r *http.Request) {
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceDeploymentValues) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	diff, err := api.DeploymentValues.Diff()
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(r.Context(), rw, http.StatusOK, diff)
+}
+
 // @Summary Get deployment stats
 // @ID get-deployment-stats
 // @Security CoderSessionToken
@@ -62,6 +114,66 @@ func (api *API) deploymentStats(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(r.Context(), rw, http.StatusOK, stats)
 }
 
+// @Summary Get deployment status
+// @ID get-deployment-status
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.DeploymentStatus
+// @Failure 503 {object} codersdk.DeploymentStatus
+// @Router /deployment/status [get]
+func (api *API) deploymentStatus(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	status, ok := api.cachedDeploymentStatus()
+	if !ok {
+		res := <-api.deploymentStatusGroup.DoChan("", func() (codersdk.DeploymentStatus, error) {
+			// Create a new context not tied to the request.
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			status := api.checkDeploymentStatus(ctx)
+			api.deploymentStatusCache.Store(&status)
+			return status, nil
+		})
+		status = res.Val
+	}
+
+	code := http.StatusOK
+	if !status.Healthy {
+		code = http.StatusServiceUnavailable
+	}
+	httpapi.Write(ctx, rw, code, status)
+}
+
+// deploymentStatusCacheTTL is how long a deployment status is served before
+// the database is checked again.
+const deploymentStatusCacheTTL = 5 * time.Second
+
+func (api *API) cachedDeploymentStatus() (codersdk.DeploymentStatus, bool) {
+	status := api.deploymentStatusCache.Load()
+	if status == nil || time.Since(status.CheckedAt) >= deploymentStatusCacheTTL {
+		return codersdk.DeploymentStatus{}, false
+	}
+	return *status, true
+}
+
+func (api *API) checkDeploymentStatus(ctx context.Context) codersdk.DeploymentStatus {
+	status := codersdk.DeploymentStatus{
+		CheckedAt: time.Now(),
+	}
+
+	latency, err := api.Database.Ping(ctx)
+	if err == nil {
+		status.Database.Reachable = true
+		status.Database.LatencyMS = latency.Milliseconds()
+	}
+	status.Healthy = status.Database.Reachable
+
+	if f := api.WorkspaceProxiesStatusFn.Load(); f != nil {
+		status.Proxies = (*f)()
+	}
+	return status
+}
+
 // @Summary Build info
 // @ID build-info
 // @Produce json
@@ -0,0 +1,344 @@
+package coderd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+var environmentVariableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateEnvironmentVariableName returns an error if name can't be set as an
+// environment variable, or is reserved for variables set by the agent.
+func validateEnvironmentVariableName(name string) error {
+	if !environmentVariableNameRegex.MatchString(name) {
+		return xerrors.New("Must start with a letter or underscore, and contain only letters, digits and underscores.")
+	}
+	if strings.HasPrefix(strings.ToUpper(name), "CODER_") {
+		return xerrors.New("Names starting with CODER_ are reserved.")
+	}
+	return nil
+}
+
+// @Summary Get organization environment variables
+// @ID get-organization-environment-variables
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.ManagedEnvironmentVariable
+// @Router /organizations/{organization}/environmentvariables [get]
+func (api *API) organizationEnvironmentVariables(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	organization := httpmw.OrganizationParam(r)
+
+	variables, err := api.Database.GetManagedEnvironmentVariablesByOrganizationID(ctx, organization.ID)
+	api.writeManagedEnvironmentVariables(ctx, rw, variables, err)
+}
+
+// @Summary Create organization environment variable
+// @Description Organization environment variables are injected into the agents
+// @Description of every workspace in the organization.
+// @ID create-organization-environment-variable
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.CreateManagedEnvironmentVariableRequest true "Create environment variable request"
+// @Success 201 {object} codersdk.ManagedEnvironmentVariable
+// @Router /organizations/{organization}/environmentvariables [post]
+func (api *API) postOrganizationEnvironmentVariable(rw http.ResponseWriter, r *http.Request) {
+	organization := httpmw.OrganizationParam(r)
+	api.postManagedEnvironmentVariable(rw, r, organization.ID, uuid.NullUUID{})
+}
+
+// @Summary Get template environment variables
+// @ID get-template-environment-variables
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {array} codersdk.ManagedEnvironmentVariable
+// @Router /templates/{template}/environmentvariables [get]
+func (api *API) templateEnvironmentVariables(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	variables, err := api.Database.GetManagedEnvironmentVariablesByTemplateID(ctx, uuid.NullUUID{UUID: template.ID, Valid: true})
+	api.writeManagedEnvironmentVariables(ctx, rw, variables, err)
+}
+
+// @Summary Create template environment variable
+// @Description Template environment variables are injected into the agents of
+// @Description every workspace of the template, and override organization
+// @Description environment variables with the same name.
+// @ID create-template-environment-variable
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateManagedEnvironmentVariableRequest true "Create environment variable request"
+// @Success 201 {object} codersdk.ManagedEnvironmentVariable
+// @Router /templates/{template}/environmentvariables [post]
+func (api *API) postTemplateEnvironmentVariable(rw http.ResponseWriter, r *http.Request) {
+	template := httpmw.TemplateParam(r)
+	api.postManagedEnvironmentVariable(rw, r, template.OrganizationID, uuid.NullUUID{UUID: template.ID, Valid: true})
+}
+
+// @Summary Update environment variable
+// @ID update-environment-variable
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Organizations
+// @Param environmentvariable path string true "Environment variable ID" format(uuid)
+// @Param request body codersdk.UpdateManagedEnvironmentVariableRequest true "Update environment variable request"
+// @Success 200 {object} codersdk.ManagedEnvironmentVariable
+// @Router /environmentvariables/{environmentvariable} [patch]
+func (api *API) patchEnvironmentVariable(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.ManagedEnvironmentVariable](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	variable, ok := api.managedEnvironmentVariableParam(rw, r)
+	if !ok {
+		return
+	}
+	aReq.Old = variable
+
+	var req codersdk.UpdateManagedEnvironmentVariableRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	updated, err := api.Database.UpdateManagedEnvironmentVariableByID(ctx, database.UpdateManagedEnvironmentVariableByIDParams{
+		ID:        variable.ID,
+		UpdatedAt: database.Now(),
+		Value:     req.Value,
+		Secret:    req.Secret,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating environment variable.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = updated
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertManagedEnvironmentVariable(updated))
+}
+
+// @Summary Delete environment variable
+// @ID delete-environment-variable
+// @Security CoderSessionToken
+// @Tags Organizations
+// @Param environmentvariable path string true "Environment variable ID" format(uuid)
+// @Success 204
+// @Router /environmentvariables/{environmentvariable} [delete]
+func (api *API) deleteEnvironmentVariable(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.ManagedEnvironmentVariable](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	variable, ok := api.managedEnvironmentVariableParam(rw, r)
+	if !ok {
+		return
+	}
+	aReq.Old = variable
+
+	err := api.Database.DeleteManagedEnvironmentVariableByID(ctx, variable.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting environment variable.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (api *API) postManagedEnvironmentVariable(rw http.ResponseWriter, r *http.Request, organizationID uuid.UUID, templateID uuid.NullUUID) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.ManagedEnvironmentVariable](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateManagedEnvironmentVariableRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if err := validateEnvironmentVariableName(req.Name); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid environment variable name %q.", req.Name),
+			Validations: []codersdk.ValidationError{
+				{Field: "name", Detail: err.Error()},
+			},
+		})
+		return
+	}
+
+	now := database.Now()
+	variable, err := api.Database.InsertManagedEnvironmentVariable(ctx, database.InsertManagedEnvironmentVariableParams{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		TemplateID:     templateID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Name:           req.Name,
+		Value:          req.Value,
+		Secret:         req.Secret,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("An environment variable named %q already exists.", req.Name),
+			Validations: []codersdk.ValidationError{
+				{Field: "name", Detail: "This value is already in use and should be unique."},
+			},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating environment variable.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = variable
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertManagedEnvironmentVariable(variable))
+}
+
+func (api *API) managedEnvironmentVariableParam(rw http.ResponseWriter, r *http.Request) (database.ManagedEnvironmentVariable, bool) {
+	ctx := r.Context()
+	variableID, ok := httpmw.ParseUUIDParam(rw, r, "environmentvariable")
+	if !ok {
+		return database.ManagedEnvironmentVariable{}, false
+	}
+
+	variable, err := api.Database.GetManagedEnvironmentVariableByID(ctx, variableID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return database.ManagedEnvironmentVariable{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching environment variable.",
+			Detail:  err.Error(),
+		})
+		return database.ManagedEnvironmentVariable{}, false
+	}
+	return variable, true
+}
+
+func (*API) writeManagedEnvironmentVariables(ctx context.Context, rw http.ResponseWriter, variables []database.ManagedEnvironmentVariable, err error) {
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching environment variables.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiVariables := make([]codersdk.ManagedEnvironmentVariable, 0, len(variables))
+	for _, variable := range variables {
+		apiVariables = append(apiVariables, convertManagedEnvironmentVariable(variable))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiVariables)
+}
+
+// managedEnvironmentVariables returns the environment variables managed for
+// workspaces of the template. Template variables override organization
+// variables of the same name.
+func (api *API) managedEnvironmentVariables(ctx context.Context, template database.Template) (map[string]string, error) {
+	//nolint:gocritic // Agents can't read organization or template settings,
+	// but need the variables injected into them.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	orgVariables, err := api.Database.GetManagedEnvironmentVariablesByOrganizationID(ctx, template.OrganizationID)
+	if err != nil {
+		return nil, xerrors.Errorf("get organization environment variables: %w", err)
+	}
+	templateVariables, err := api.Database.GetManagedEnvironmentVariablesByTemplateID(ctx, uuid.NullUUID{UUID: template.ID, Valid: true})
+	if err != nil {
+		return nil, xerrors.Errorf("get template environment variables: %w", err)
+	}
+
+	env := make(map[string]string, len(orgVariables)+len(templateVariables))
+	for _, variable := range append(orgVariables, templateVariables...) {
+		env[variable.Name] = variable.Value
+	}
+	return env, nil
+}
+
+func convertManagedEnvironmentVariable(variable database.ManagedEnvironmentVariable) codersdk.ManagedEnvironmentVariable {
+	converted := codersdk.ManagedEnvironmentVariable{
+		ID:             variable.ID,
+		OrganizationID: variable.OrganizationID,
+		CreatedAt:      variable.CreatedAt,
+		UpdatedAt:      variable.UpdatedAt,
+		Name:           variable.Name,
+		Secret:         variable.Secret,
+	}
+	if variable.TemplateID.Valid {
+		converted.TemplateID = &variable.TemplateID.UUID
+	}
+	if !variable.Secret {
+		converted.Value = variable.Value
+	}
+	return converted
+}
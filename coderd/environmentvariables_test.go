@@ -0,0 +1,192 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk/proto"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestManagedEnvironmentVariables(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Organization", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{Auditor: auditor})
+		user := coderdtest.CreateFirstUser(t, client)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		auditor.ResetLogs()
+		created, err := client.CreateOrganizationEnvironmentVariable(ctx, user.OrganizationID, codersdk.CreateManagedEnvironmentVariableRequest{
+			Name:  "HTTPS_PROXY",
+			Value: "http://proxy.internal:3128",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "HTTPS_PROXY", created.Name)
+		require.Equal(t, "http://proxy.internal:3128", created.Value)
+		require.Nil(t, created.TemplateID)
+		require.Len(t, auditor.AuditLogs(), 1)
+		require.Equal(t, database.ResourceTypeEnvironmentVariable, auditor.AuditLogs()[0].ResourceType)
+		require.Equal(t, database.AuditActionCreate, auditor.AuditLogs()[0].Action)
+
+		// Members can see the variables injected into their workspaces, but
+		// can't change them.
+		variables, err := memberClient.OrganizationEnvironmentVariables(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.ManagedEnvironmentVariable{created}, variables)
+
+		_, err = memberClient.CreateOrganizationEnvironmentVariable(ctx, user.OrganizationID, codersdk.CreateManagedEnvironmentVariableRequest{
+			Name: "NO_PROXY",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = client.CreateOrganizationEnvironmentVariable(ctx, user.OrganizationID, codersdk.CreateManagedEnvironmentVariableRequest{
+			Name: "HTTPS_PROXY",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		updated, err := client.UpdateEnvironmentVariable(ctx, created.ID, codersdk.UpdateManagedEnvironmentVariableRequest{
+			Value: "http://other.internal:3128",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "http://other.internal:3128", updated.Value)
+
+		err = memberClient.DeleteEnvironmentVariable(ctx, created.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		err = client.DeleteEnvironmentVariable(ctx, created.ID)
+		require.NoError(t, err)
+		variables, err = client.OrganizationEnvironmentVariables(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Empty(t, variables)
+	})
+
+	t.Run("Secret", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		created, err := client.CreateOrganizationEnvironmentVariable(ctx, user.OrganizationID, codersdk.CreateManagedEnvironmentVariableRequest{
+			Name:   "NPM_TOKEN",
+			Value:  "hunter2",
+			Secret: true,
+		})
+		require.NoError(t, err)
+		require.True(t, created.Secret)
+		require.Empty(t, created.Value)
+
+		variables, err := client.OrganizationEnvironmentVariables(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.Len(t, variables, 1)
+		require.Empty(t, variables[0].Value)
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, name := range []string{"1PROXY", "HTTPS-PROXY", "CODER_AGENT_TOKEN"} {
+			_, err := client.CreateOrganizationEnvironmentVariable(ctx, user.OrganizationID, codersdk.CreateManagedEnvironmentVariableRequest{
+				Name: name,
+			})
+			var apiErr *codersdk.Error
+			require.ErrorAs(t, err, &apiErr, name)
+			require.Equal(t, http.StatusBadRequest, apiErr.StatusCode(), name)
+		}
+	})
+
+	t.Run("Manifest", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:         echo.ParseComplete,
+			ProvisionPlan: echo.ProvisionComplete,
+			ProvisionApply: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Resources: []*proto.Resource{{
+							Name: "example",
+							Type: "aws_instance",
+							Agents: []*proto.Agent{{
+								Id:   uuid.NewString(),
+								Name: "example",
+								Env:  map[string]string{"EDITOR": "vim"},
+								Auth: &proto.Agent_Token{
+									Token: authToken,
+								},
+							}},
+						}},
+					},
+				},
+			}},
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		for _, req := range []codersdk.CreateManagedEnvironmentVariableRequest{
+			{Name: "HTTPS_PROXY", Value: "http://org.internal:3128"},
+			{Name: "NO_PROXY", Value: "localhost"},
+			{Name: "EDITOR", Value: "nano"},
+		} {
+			_, err := client.CreateOrganizationEnvironmentVariable(ctx, user.OrganizationID, req)
+			require.NoError(t, err)
+		}
+		_, err := client.CreateTemplateEnvironmentVariable(ctx, template.ID, codersdk.CreateManagedEnvironmentVariableRequest{
+			Name:  "HTTPS_PROXY",
+			Value: "http://template.internal:3128",
+		})
+		require.NoError(t, err)
+		_, err = client.CreateTemplateEnvironmentVariable(ctx, template.ID, codersdk.CreateManagedEnvironmentVariableRequest{
+			Name:   "NPM_TOKEN",
+			Value:  "hunter2",
+			Secret: true,
+		})
+		require.NoError(t, err)
+
+		variables, err := client.TemplateEnvironmentVariables(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, variables, 2)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		manifest, err := agentClient.Manifest(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			// Template variables override organization variables, and the
+			// agent overrides both.
+			"HTTPS_PROXY": "http://template.internal:3128",
+			"NO_PROXY":    "localhost",
+			"EDITOR":      "vim",
+			// Secrets are hidden from the API, but injected into agents.
+			"NPM_TOKEN": "hunter2",
+		}, manifest.EnvironmentVariables)
+	})
+}
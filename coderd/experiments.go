@@ -1,11 +1,27 @@
 package coderd
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"regexp"
 
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/slice"
+	"github.com/coder/coder/v2/codersdk"
 )
 
+var experimentNameRegex = regexp.MustCompile(`^[a-z0-9_]+$`)
+
 // @Summary Get experiments
 // @ID get-experiments
 // @Security CoderSessionToken
@@ -15,5 +31,198 @@ import (
 // @Router /experiments [get]
 func (api *API) handleExperimentsGet(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	httpapi.Write(ctx, rw, http.StatusOK, api.Experiments)
+	apiKey := httpmw.APIKey(r)
+
+	experiments, err := api.userExperiments(ctx, apiKey.UserID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, experiments)
+}
+
+// @Summary Get experiments config
+// @ID get-experiments-config
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.ExperimentsConfig
+// @Router /experiments/config [get]
+func (api *API) experimentsConfig(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourceDeploymentValues) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	runtime, err := api.runtimeExperiments(ctx)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.ExperimentsConfig{
+		Deployment: api.Experiments,
+		Runtime:    runtime,
+	})
+}
+
+// @Summary Update runtime experiments
+// @ID update-runtime-experiments
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags General
+// @Param request body codersdk.UpdateRuntimeExperimentsRequest true "Runtime experiments"
+// @Success 200 {object} codersdk.ExperimentsConfig
+// @Router /experiments/config [put]
+func (api *API) putRuntimeExperiments(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionUpdate, rbac.ResourceDeploymentValues) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateRuntimeExperimentsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var validations []codersdk.ValidationError
+	seen := map[codersdk.Experiment]struct{}{}
+	for i, experiment := range req.Runtime {
+		field := fmt.Sprintf("runtime[%d]", i)
+		if !experimentNameRegex.MatchString(string(experiment.Experiment)) {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  field + ".experiment",
+				Detail: "Experiment names may only contain lowercase letters, numbers and underscores.",
+			})
+		}
+		if _, ok := seen[experiment.Experiment]; ok {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  field + ".experiment",
+				Detail: fmt.Sprintf("Experiment %q is listed more than once.", experiment.Experiment),
+			})
+		}
+		seen[experiment.Experiment] = struct{}{}
+		if !experiment.Everyone && len(experiment.OrganizationIDs) == 0 && len(experiment.GroupIDs) == 0 && len(experiment.UserIDs) == 0 {
+			validations = append(validations, codersdk.ValidationError{
+				Field:  field,
+				Detail: "Target everyone, or at least one organization, group or user.",
+			})
+		}
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid runtime experiments.",
+			Validations: validations,
+		})
+		return
+	}
+
+	if req.Runtime == nil {
+		req.Runtime = []codersdk.RuntimeExperiment{}
+	}
+	raw, err := json.Marshal(req.Runtime)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	err = api.Database.UpsertRuntimeExperiments(ctx, string(raw))
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.ExperimentsConfig{
+		Deployment: api.Experiments,
+		Runtime:    req.Runtime,
+	})
+}
+
+// runtimeExperiments returns the experiments admins enabled at runtime.
+func (api *API) runtimeExperiments(ctx context.Context) ([]codersdk.RuntimeExperiment, error) {
+	raw, err := api.Database.GetRuntimeExperiments(ctx)
+	if xerrors.Is(err, sql.ErrNoRows) {
+		return []codersdk.RuntimeExperiment{}, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("get runtime experiments: %w", err)
+	}
+	var runtime []codersdk.RuntimeExperiment
+	err = json.Unmarshal([]byte(raw), &runtime)
+	if err != nil {
+		return nil, xerrors.Errorf("unmarshal runtime experiments: %w", err)
+	}
+	return runtime, nil
+}
+
+// userExperiments returns the experiments enabled for the deployment along
+// with the runtime experiments targeting the user, their organizations or
+// their groups.
+func (api *API) userExperiments(ctx context.Context, userID uuid.UUID) (codersdk.Experiments, error) {
+	experiments := append(codersdk.Experiments{}, api.Experiments...)
+
+	runtime, err := api.runtimeExperiments(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(runtime) == 0 {
+		return experiments, nil
+	}
+
+	// The user may not be allowed to read their organizations' members or
+	// groups, but they're allowed to know which experiments target them.
+	//nolint:gocritic // Evaluating experiment targets is a system function.
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	var organizationIDs []uuid.UUID
+	rows, err := api.Database.GetOrganizationIDsByMemberIDs(sysCtx, []uuid.UUID{userID})
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		return nil, xerrors.Errorf("get organization ids: %w", err)
+	}
+	if len(rows) > 0 {
+		organizationIDs = rows[0].OrganizationIDs
+	}
+
+	groupMember := map[uuid.UUID]bool{}
+	isGroupMember := func(groupID uuid.UUID) (bool, error) {
+		if member, ok := groupMember[groupID]; ok {
+			return member, nil
+		}
+		// The Everyone group shares its ID with the organization and has
+		// no member rows.
+		if slice.Contains(organizationIDs, groupID) {
+			groupMember[groupID] = true
+			return true, nil
+		}
+		members, err := api.Database.GetGroupMembers(sysCtx, groupID)
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			return false, xerrors.Errorf("get group %s members: %w", groupID, err)
+		}
+		groupMember[groupID] = slice.ContainsCompare(members, database.User{ID: userID}, func(a, b database.User) bool {
+			return a.ID == b.ID
+		})
+		return groupMember[groupID], nil
+	}
+
+	for _, experiment := range runtime {
+		if experiments.Enabled(experiment.Experiment) {
+			continue
+		}
+		enabled := experiment.Everyone ||
+			slice.Contains(experiment.UserIDs, userID) ||
+			slice.Overlap(experiment.OrganizationIDs, organizationIDs)
+		for _, groupID := range experiment.GroupIDs {
+			if enabled {
+				break
+			}
+			enabled, err = isGroupMember(groupID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if enabled {
+			experiments = append(experiments, experiment.Experiment)
+		}
+	}
+	return experiments, nil
 }
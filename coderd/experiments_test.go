@@ -2,8 +2,10 @@ package coderd_test
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
@@ -116,4 +118,73 @@ func Test_Experiments(t *testing.T) {
 		require.Error(t, err)
 		require.ErrorContains(t, err, httpmw.SignedOutErrorMessage)
 	})
+
+	t.Run("Runtime", func(t *testing.T) {
+		t.Parallel()
+		cfg := coderdtest.DeploymentValues(t)
+		cfg.Experiments = []string{"foo"}
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: cfg,
+		})
+		owner := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		config, err := client.UpdateRuntimeExperiments(ctx, codersdk.UpdateRuntimeExperimentsRequest{
+			Runtime: []codersdk.RuntimeExperiment{
+				{Experiment: "everyone", Everyone: true},
+				{Experiment: "member", UserIDs: []uuid.UUID{member.ID}},
+				{Experiment: "organization", OrganizationIDs: []uuid.UUID{owner.OrganizationID}},
+				// The Everyone group shares its ID with the organization.
+				{Experiment: "group", GroupIDs: []uuid.UUID{owner.OrganizationID}},
+				{Experiment: "other", OrganizationIDs: []uuid.UUID{uuid.New()}},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.Experiments{"foo"}, config.Deployment)
+		require.Len(t, config.Runtime, 5)
+
+		experiments, err := memberClient.Experiments(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []codersdk.Experiment{"foo", "everyone", "member", "organization", "group"}, experiments)
+
+		experiments, err = client.Experiments(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []codersdk.Experiment{"foo", "everyone", "organization", "group"}, experiments)
+
+		// Members can't see or change who experiments are enabled for.
+		_, err = memberClient.ExperimentsConfig(ctx)
+		require.Error(t, err)
+		_, err = memberClient.UpdateRuntimeExperiments(ctx, codersdk.UpdateRuntimeExperimentsRequest{})
+		require.Error(t, err)
+
+		// Clearing runtime experiments takes effect immediately.
+		_, err = client.UpdateRuntimeExperiments(ctx, codersdk.UpdateRuntimeExperimentsRequest{})
+		require.NoError(t, err)
+		experiments, err = memberClient.Experiments(ctx)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.Experiments{"foo"}, experiments)
+	})
+
+	t.Run("RuntimeInvalid", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.UpdateRuntimeExperiments(ctx, codersdk.UpdateRuntimeExperimentsRequest{
+			Runtime: []codersdk.RuntimeExperiment{
+				{Experiment: "Not Valid", Everyone: true},
+				{Experiment: "untargeted"},
+			},
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+		require.Len(t, sdkErr.Validations, 2)
+	})
 }
@@ -0,0 +1,91 @@
+package coderd
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+var featureFlagNameRegex = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// ReadFeatureFlags parses feature flags in the form "name" or
+// "name:percentage" into the percentage of users and agents each flag is
+// rolled out to. Invalid entries are logged and ignored so a typo can't
+// prevent the deployment from starting.
+func ReadFeatureFlags(log slog.Logger, raw []string) map[string]int {
+	flags := make(map[string]int, len(raw))
+	for _, v := range raw {
+		name, percentage, hasPercentage := strings.Cut(strings.TrimSpace(v), ":")
+		name = strings.ToLower(name)
+		if !featureFlagNameRegex.MatchString(name) {
+			log.Warn(context.Background(), "ignoring feature flag with invalid name", slog.F("feature_flag", v))
+			continue
+		}
+		rollout := 100
+		if hasPercentage {
+			var err error
+			rollout, err = strconv.Atoi(strings.TrimSuffix(percentage, "%"))
+			if err != nil || rollout < 0 || rollout > 100 {
+				log.Warn(context.Background(), "ignoring feature flag with invalid percentage", slog.F("feature_flag", v))
+				continue
+			}
+		}
+		flags[name] = rollout
+	}
+	return flags
+}
+
+// evaluateFeatureFlags returns which flags are enabled for the subject.
+// Subjects are bucketed per flag, so the same subject isn't always in the
+// first percentage of every rollout.
+func evaluateFeatureFlags(flags map[string]int, subject uuid.UUID) codersdk.FeatureFlags {
+	evaluated := codersdk.FeatureFlags{
+		Flags: make(map[string]bool, len(flags)),
+	}
+	for name, rollout := range flags {
+		hash := fnv.New64a()
+		_, _ = hash.Write([]byte(name))
+		_, _ = hash.Write(subject[:])
+		bucket := binary.BigEndian.Uint64(hash.Sum(nil)) % 100
+		evaluated.Flags[name] = bucket < uint64(rollout)
+	}
+	return evaluated
+}
+
+// @Summary Get feature flags
+// @ID get-feature-flags
+// @Security CoderSessionToken
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.FeatureFlags
+// @Router /feature-flags [get]
+func (api *API) userFeatureFlags(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+	httpapi.Write(ctx, rw, http.StatusOK, evaluateFeatureFlags(api.featureFlags, apiKey.UserID))
+}
+
+// @Summary Get workspace agent feature flags
+// @ID get-workspace-agent-feature-flags
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 200 {object} codersdk.FeatureFlags
+// @Router /workspaceagents/me/feature-flags [get]
+func (api *API) workspaceAgentFeatureFlags(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	httpapi.Write(ctx, rw, http.StatusOK, evaluateFeatureFlags(api.featureFlags, workspaceAgent.ID))
+}
@@ -0,0 +1,28 @@
+package coderd
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	flags := map[string]int{"half": 50, "tenth": 10}
+	enabled := map[string]int{}
+	const subjects = 2000
+	for i := 0; i < subjects; i++ {
+		subject := uuid.New()
+		evaluated := evaluateFeatureFlags(flags, subject)
+		require.Equal(t, evaluated, evaluateFeatureFlags(flags, subject), "evaluation must be deterministic")
+		for name, on := range evaluated.Flags {
+			if on {
+				enabled[name]++
+			}
+		}
+	}
+	require.InDelta(t, subjects/2, enabled["half"], subjects*0.05)
+	require.InDelta(t, subjects/10, enabled["tenth"], subjects*0.05)
+}
@@ -0,0 +1,106 @@
+package coderd_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestReadFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+	flags := coderd.ReadFeatureFlags(logger, []string{
+		"everyone",
+		"UPPER",
+		"nobody:0",
+		"half:50%",
+		"invalid name",
+		"too_many:101",
+		"not_a_number:half",
+	})
+	require.Equal(t, map[string]int{
+		"everyone": 100,
+		"upper":    100,
+		"nobody":   0,
+		"half":     50,
+	}, flags)
+}
+
+func TestFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		flags, err := client.FeatureFlags(ctx)
+		require.NoError(t, err)
+		require.Empty(t, flags.Flags)
+		require.False(t, flags.Enabled("foo"))
+	})
+
+	t.Run("User", func(t *testing.T) {
+		t.Parallel()
+		cfg := coderdtest.DeploymentValues(t)
+		cfg.FeatureFlags = []string{"everyone", "nobody:0", "half:50"}
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues: cfg,
+		})
+		_ = coderdtest.CreateFirstUser(t, client)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		flags, err := client.FeatureFlags(ctx)
+		require.NoError(t, err)
+		require.Len(t, flags.Flags, 3)
+		require.True(t, flags.Enabled("everyone"))
+		require.False(t, flags.Enabled("nobody"))
+		require.False(t, flags.Enabled("unknown"))
+
+		// Percentage rollouts are stable for the same user.
+		again, err := client.FeatureFlags(ctx)
+		require.NoError(t, err)
+		require.Equal(t, flags, again)
+	})
+
+	t.Run("Agent", func(t *testing.T) {
+		t.Parallel()
+		cfg := coderdtest.DeploymentValues(t)
+		cfg.FeatureFlags = []string{"everyone", "nobody:0"}
+		client := coderdtest.New(t, &coderdtest.Options{
+			DeploymentValues:         cfg,
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		flags, err := agentClient.FeatureFlags(ctx)
+		require.NoError(t, err)
+		require.Len(t, flags.Flags, 2)
+		require.True(t, flags.Enabled("everyone"))
+		require.False(t, flags.Enabled("nobody"))
+	})
+}
@@ -0,0 +1,121 @@
+package healthcheck
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+)
+
+type CoordinatorType string
+
+const (
+	// CoordinatorTypeMemory only coordinates connections to agents that are
+	// connected to the same replica.
+	CoordinatorTypeMemory CoordinatorType = "memory"
+	// CoordinatorTypeHA coordinates connections between replicas over pubsub.
+	CoordinatorTypeHA CoordinatorType = "ha"
+	// CoordinatorTypePG coordinates connections between replicas through the
+	// database.
+	CoordinatorTypePG CoordinatorType = "pg"
+)
+
+// CoordinatorStatus is the state of the tailnet coordinator of a replica.
+type CoordinatorStatus struct {
+	Type CoordinatorType
+	// HighAvailability is true if the replica is supposed to run a high
+	// availability coordinator.
+	HighAvailability bool
+	// FailedOverAt is the last time the high availability coordinator could
+	// not be set up, and the replica kept the in-memory coordinator.
+	FailedOverAt  time.Time
+	FailoverError string
+}
+
+// @typescript-generate CoordinatorReport
+type CoordinatorReport struct {
+	Healthy bool `json:"healthy"`
+	// ReplicaID is the replica that generated the report.
+	ReplicaID        uuid.UUID       `json:"replica_id" format:"uuid"`
+	Type             CoordinatorType `json:"type" enums:"memory,ha,pg"`
+	HighAvailability bool            `json:"high_availability"`
+	PubsubLatency    string          `json:"pubsub_latency"`
+	PubsubLatencyMs  int             `json:"pubsub_latency_ms"`
+	// LastFailoverAt is the last time the replica fell back to the in-memory
+	// coordinator.
+	LastFailoverAt    *time.Time `json:"last_failover_at" format:"date-time"`
+	LastFailoverError *string    `json:"last_failover_error"`
+	Error             *string    `json:"error"`
+}
+
+type CoordinatorReportOptions struct {
+	ReplicaID uuid.UUID
+	Status    CoordinatorStatus
+	Pubsub    pubsub.Pubsub
+}
+
+func (r *CoordinatorReport) Run(ctx context.Context, opts *CoordinatorReportOptions) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	r.ReplicaID = opts.ReplicaID
+	r.Type = opts.Status.Type
+	r.HighAvailability = opts.Status.HighAvailability
+	if !opts.Status.FailedOverAt.IsZero() {
+		failedOverAt := opts.Status.FailedOverAt
+		r.LastFailoverAt = &failedOverAt
+		r.LastFailoverError = &opts.Status.FailoverError
+	}
+
+	latency, err := pubsubLatency(ctx, opts.Pubsub)
+	if err != nil {
+		r.Error = convertError(xerrors.Errorf("measure pubsub latency: %w", err))
+		return
+	}
+	r.PubsubLatency = latency.String()
+	r.PubsubLatencyMs = int(latency.Milliseconds())
+
+	// Agents connected to other replicas are unreachable from this replica
+	// if it fell back to the in-memory coordinator.
+	if r.HighAvailability && r.Type == CoordinatorTypeMemory {
+		r.Error = convertError(xerrors.New("the high availability coordinator is not running"))
+		return
+	}
+	r.Healthy = true
+}
+
+// pubsubLatency returns the time it takes for a message published to pubsub
+// to be received.
+func pubsubLatency(ctx context.Context, ps pubsub.Pubsub) (time.Duration, error) {
+	if ps == nil {
+		return 0, xerrors.New("no pubsub configured")
+	}
+
+	event := "healthcheck_pubsub_" + uuid.NewString()
+	received := make(chan struct{}, 1)
+	cancel, err := ps.Subscribe(event, func(context.Context, []byte) {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		return 0, xerrors.Errorf("subscribe: %w", err)
+	}
+	defer cancel()
+
+	start := time.Now()
+	err = ps.Publish(event, []byte("ping"))
+	if err != nil {
+		return 0, xerrors.Errorf("publish: %w", err)
+	}
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-received:
+		return time.Since(start), nil
+	}
+}
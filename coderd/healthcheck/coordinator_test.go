@@ -0,0 +1,96 @@
+package healthcheck_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/healthcheck"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestCoordinator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), testutil.WaitShort)
+			report      = healthcheck.CoordinatorReport{}
+			replicaID   = uuid.New()
+		)
+		defer cancel()
+
+		report.Run(ctx, &healthcheck.CoordinatorReportOptions{
+			ReplicaID: replicaID,
+			Status: healthcheck.CoordinatorStatus{
+				Type:             healthcheck.CoordinatorTypeHA,
+				HighAvailability: true,
+			},
+			Pubsub: pubsub.NewInMemory(),
+		})
+
+		assert.True(t, report.Healthy)
+		assert.Equal(t, replicaID, report.ReplicaID)
+		assert.Equal(t, healthcheck.CoordinatorTypeHA, report.Type)
+		assert.NotEmpty(t, report.PubsubLatency)
+		assert.Nil(t, report.LastFailoverAt)
+		assert.Nil(t, report.Error)
+	})
+
+	t.Run("FailedOver", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel  = context.WithTimeout(context.Background(), testutil.WaitShort)
+			report       = healthcheck.CoordinatorReport{}
+			failedOverAt = time.Now()
+		)
+		defer cancel()
+
+		report.Run(ctx, &healthcheck.CoordinatorReportOptions{
+			ReplicaID: uuid.New(),
+			Status: healthcheck.CoordinatorStatus{
+				Type:             healthcheck.CoordinatorTypeMemory,
+				HighAvailability: true,
+				FailedOverAt:     failedOverAt,
+				FailoverError:    "connection refused",
+			},
+			Pubsub: pubsub.NewInMemory(),
+		})
+
+		assert.False(t, report.Healthy)
+		require.NotNil(t, report.LastFailoverAt)
+		assert.Equal(t, failedOverAt, *report.LastFailoverAt)
+		require.NotNil(t, report.LastFailoverError)
+		assert.Equal(t, "connection refused", *report.LastFailoverError)
+		assert.NotNil(t, report.Error)
+	})
+
+	t.Run("InMemory", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithTimeout(context.Background(), testutil.WaitShort)
+			report      = healthcheck.CoordinatorReport{}
+		)
+		defer cancel()
+
+		report.Run(ctx, &healthcheck.CoordinatorReportOptions{
+			ReplicaID: uuid.New(),
+			Status: healthcheck.CoordinatorStatus{
+				Type: healthcheck.CoordinatorTypeMemory,
+			},
+			Pubsub: pubsub.NewInMemory(),
+		})
+
+		assert.True(t, report.Healthy)
+		assert.Nil(t, report.Error)
+	})
+}
@@ -8,18 +8,21 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"tailscale.com/tailcfg"
 
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 )
 
 const (
-	SectionDERP      string = "DERP"
-	SectionAccessURL string = "AccessURL"
-	SectionWebsocket string = "Websocket"
-	SectionDatabase  string = "Database"
+	SectionDERP        string = "DERP"
+	SectionAccessURL   string = "AccessURL"
+	SectionWebsocket   string = "Websocket"
+	SectionDatabase    string = "Database"
+	SectionCoordinator string = "Coordinator"
 )
 
 type Checker interface {
@@ -27,6 +30,7 @@ type Checker interface {
 	AccessURL(ctx context.Context, opts *AccessURLReportOptions) AccessURLReport
 	Websocket(ctx context.Context, opts *WebsocketReportOptions) WebsocketReport
 	Database(ctx context.Context, opts *DatabaseReportOptions) DatabaseReport
+	Coordinator(ctx context.Context, opts *CoordinatorReportOptions) CoordinatorReport
 }
 
 // @typescript-generate Report
@@ -38,10 +42,11 @@ type Report struct {
 	// FailingSections is a list of sections that have failed their healthcheck.
 	FailingSections []string `json:"failing_sections"`
 
-	DERP      DERPReport      `json:"derp"`
-	AccessURL AccessURLReport `json:"access_url"`
-	Websocket WebsocketReport `json:"websocket"`
-	Database  DatabaseReport  `json:"database"`
+	DERP        DERPReport        `json:"derp"`
+	AccessURL   AccessURLReport   `json:"access_url"`
+	Websocket   WebsocketReport   `json:"websocket"`
+	Database    DatabaseReport    `json:"database"`
+	Coordinator CoordinatorReport `json:"coordinator"`
 
 	// The Coder version of the server that the report was generated on.
 	CoderVersion string `json:"coder_version"`
@@ -54,6 +59,11 @@ type ReportOptions struct {
 	AccessURL *url.URL
 	Client    *http.Client
 	APIKey    string
+	// ReplicaID, Pubsub and CoordinatorStatus describe the tailnet
+	// coordinator of the replica running the healthcheck.
+	ReplicaID         uuid.UUID
+	Pubsub            pubsub.Pubsub
+	CoordinatorStatus CoordinatorStatus
 
 	Checker Checker
 }
@@ -80,6 +90,11 @@ func (defaultChecker) Database(ctx context.Context, opts *DatabaseReportOptions)
 	return report
 }
 
+func (defaultChecker) Coordinator(ctx context.Context, opts *CoordinatorReportOptions) (report CoordinatorReport) {
+	report.Run(ctx, opts)
+	return report
+}
+
 func Run(ctx context.Context, opts *ReportOptions) *Report {
 	var (
 		wg     sync.WaitGroup
@@ -148,6 +163,22 @@ func Run(ctx context.Context, opts *ReportOptions) *Report {
 		})
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer func() {
+			if err := recover(); err != nil {
+				report.Coordinator.Error = ptr.Ref(fmt.Sprint(err))
+			}
+		}()
+
+		report.Coordinator = opts.Checker.Coordinator(ctx, &CoordinatorReportOptions{
+			ReplicaID: opts.ReplicaID,
+			Status:    opts.CoordinatorStatus,
+			Pubsub:    opts.Pubsub,
+		})
+	}()
+
 	report.CoderVersion = buildinfo.Version()
 	wg.Wait()
 
@@ -164,6 +195,9 @@ func Run(ctx context.Context, opts *ReportOptions) *Report {
 	if !report.Database.Healthy {
 		report.FailingSections = append(report.FailingSections, SectionDatabase)
 	}
+	if !report.Coordinator.Healthy {
+		report.FailingSections = append(report.FailingSections, SectionCoordinator)
+	}
 
 	report.Healthy = len(report.FailingSections) == 0
 	return &report
@@ -10,10 +10,11 @@ import (
 )
 
 type testChecker struct {
-	DERPReport      healthcheck.DERPReport
-	AccessURLReport healthcheck.AccessURLReport
-	WebsocketReport healthcheck.WebsocketReport
-	DatabaseReport  healthcheck.DatabaseReport
+	DERPReport        healthcheck.DERPReport
+	AccessURLReport   healthcheck.AccessURLReport
+	WebsocketReport   healthcheck.WebsocketReport
+	DatabaseReport    healthcheck.DatabaseReport
+	CoordinatorReport healthcheck.CoordinatorReport
 }
 
 func (c *testChecker) DERP(context.Context, *healthcheck.DERPReportOptions) healthcheck.DERPReport {
@@ -32,6 +33,10 @@ func (c *testChecker) Database(context.Context, *healthcheck.DatabaseReportOptio
 	return c.DatabaseReport
 }
 
+func (c *testChecker) Coordinator(context.Context, *healthcheck.CoordinatorReportOptions) healthcheck.CoordinatorReport {
+	return c.CoordinatorReport
+}
+
 func TestHealthcheck(t *testing.T) {
 	t.Parallel()
 
@@ -55,6 +60,9 @@ func TestHealthcheck(t *testing.T) {
 			DatabaseReport: healthcheck.DatabaseReport{
 				Healthy: true,
 			},
+			CoordinatorReport: healthcheck.CoordinatorReport{
+				Healthy: true,
+			},
 		},
 		healthy:         true,
 		failingSections: nil,
@@ -73,6 +81,9 @@ func TestHealthcheck(t *testing.T) {
 			DatabaseReport: healthcheck.DatabaseReport{
 				Healthy: true,
 			},
+			CoordinatorReport: healthcheck.CoordinatorReport{
+				Healthy: true,
+			},
 		},
 		healthy:         false,
 		failingSections: []string{healthcheck.SectionDERP},
@@ -91,6 +102,9 @@ func TestHealthcheck(t *testing.T) {
 			DatabaseReport: healthcheck.DatabaseReport{
 				Healthy: true,
 			},
+			CoordinatorReport: healthcheck.CoordinatorReport{
+				Healthy: true,
+			},
 		},
 		healthy:         false,
 		failingSections: []string{healthcheck.SectionAccessURL},
@@ -109,6 +123,9 @@ func TestHealthcheck(t *testing.T) {
 			DatabaseReport: healthcheck.DatabaseReport{
 				Healthy: true,
 			},
+			CoordinatorReport: healthcheck.CoordinatorReport{
+				Healthy: true,
+			},
 		},
 		healthy:         false,
 		failingSections: []string{healthcheck.SectionWebsocket},
@@ -127,9 +144,33 @@ func TestHealthcheck(t *testing.T) {
 			DatabaseReport: healthcheck.DatabaseReport{
 				Healthy: false,
 			},
+			CoordinatorReport: healthcheck.CoordinatorReport{
+				Healthy: true,
+			},
 		},
 		healthy:         false,
 		failingSections: []string{healthcheck.SectionDatabase},
+	}, {
+		name: "CoordinatorFail",
+		checker: &testChecker{
+			DERPReport: healthcheck.DERPReport{
+				Healthy: true,
+			},
+			AccessURLReport: healthcheck.AccessURLReport{
+				Healthy: true,
+			},
+			WebsocketReport: healthcheck.WebsocketReport{
+				Healthy: true,
+			},
+			DatabaseReport: healthcheck.DatabaseReport{
+				Healthy: true,
+			},
+			CoordinatorReport: healthcheck.CoordinatorReport{
+				Healthy: false,
+			},
+		},
+		healthy:         false,
+		failingSections: []string{healthcheck.SectionCoordinator},
 	}, {
 		name:    "AllFail",
 		checker: &testChecker{},
@@ -139,6 +180,7 @@ func TestHealthcheck(t *testing.T) {
 			healthcheck.SectionAccessURL,
 			healthcheck.SectionWebsocket,
 			healthcheck.SectionDatabase,
+			healthcheck.SectionCoordinator,
 		},
 	}} {
 		c := c
@@ -154,6 +196,7 @@ func TestHealthcheck(t *testing.T) {
 			assert.Equal(t, c.checker.DERPReport.Healthy, report.DERP.Healthy)
 			assert.Equal(t, c.checker.AccessURLReport.Healthy, report.AccessURL.Healthy)
 			assert.Equal(t, c.checker.WebsocketReport.Healthy, report.Websocket.Healthy)
+			assert.Equal(t, c.checker.CoordinatorReport.Healthy, report.Coordinator.Healthy)
 			assert.NotZero(t, report.Time)
 			assert.NotZero(t, report.CoderVersion)
 		})
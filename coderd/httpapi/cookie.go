@@ -24,7 +24,8 @@ func StripCoderCookies(header string) string {
 			name == codersdk.OAuth2StateCookie ||
 			name == codersdk.OAuth2RedirectCookie ||
 			name == codersdk.DevURLSessionTokenCookie ||
-			name == codersdk.DevURLSignedAppTokenCookie {
+			name == codersdk.DevURLSignedAppTokenCookie ||
+			name == codersdk.ProxyAffinityCookie {
 			continue
 		}
 		cookies = append(cookies, part)
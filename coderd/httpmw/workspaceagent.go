@@ -18,6 +18,8 @@ import (
 
 type workspaceAgentContextKey struct{}
 
+type workspaceAgentPreviousTokenContextKey struct{}
+
 func WorkspaceAgentOptional(r *http.Request) (database.WorkspaceAgent, bool) {
 	user, ok := r.Context().Value(workspaceAgentContextKey{}).(database.WorkspaceAgent)
 	return user, ok
@@ -32,6 +34,14 @@ func WorkspaceAgent(r *http.Request) database.WorkspaceAgent {
 	return user
 }
 
+// WorkspaceAgentUsedPreviousToken returns whether the agent authenticated with
+// a previous auth token that's still valid after a rotation, instead of its
+// current one.
+func WorkspaceAgentUsedPreviousToken(r *http.Request) bool {
+	previous, _ := r.Context().Value(workspaceAgentPreviousTokenContextKey{}).(bool)
+	return previous
+}
+
 type ExtractWorkspaceAgentConfig struct {
 	DB database.Store
 	// Optional indicates whether the middleware should be optional.  If true, any
@@ -101,6 +111,7 @@ func ExtractWorkspaceAgent(opts ExtractWorkspaceAgentConfig) func(http.Handler)
 			}.WithCachedASTValue()
 
 			ctx = context.WithValue(ctx, workspaceAgentContextKey{}, row.WorkspaceAgent)
+			ctx = context.WithValue(ctx, workspaceAgentPreviousTokenContextKey{}, row.WorkspaceAgent.AuthToken != token)
 			// Also set the dbauthz actor for the request.
 			ctx = dbauthz.As(ctx, subject)
 			next.ServeHTTP(rw, r.WithContext(ctx))
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,6 +14,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
@@ -147,6 +149,97 @@ func (api *API) insightsUserLatency(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
+// @Summary Get insights about user bandwidth
+// @ID get-insights-about-user-bandwidth
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Success 200 {object} codersdk.UserBandwidthInsightsResponse
+// @Router /insights/user-bandwidth [get]
+func (api *API) insightsUserBandwidth(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		Required("start_time").
+		Required("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetUserBandwidthInsights(ctx, database.GetUserBandwidthInsightsParams{
+		StartTime:   startTime,
+		EndTime:     endTime,
+		TemplateIDs: templateIDs,
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user bandwidth.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	templateIDSet := make(map[uuid.UUID]struct{})
+	userBandwidths := make([]codersdk.UserBandwidth, 0, len(rows))
+	for _, row := range rows {
+		for _, templateID := range row.TemplateIDs {
+			templateIDSet[templateID] = struct{}{}
+		}
+		userBandwidths = append(userBandwidths, codersdk.UserBandwidth{
+			TemplateIDs:    row.TemplateIDs,
+			UserID:         row.UserID,
+			Username:       row.Username,
+			AvatarURL:      row.AvatarURL.String,
+			ConnectionType: row.ConnectionType,
+			Port:           uint16(row.Port),
+			Peers:          row.Peers,
+			RxBytes:        row.RxBytes,
+			TxBytes:        row.TxBytes,
+		})
+	}
+
+	// TemplateIDs that contributed to the data.
+	seenTemplateIDs := make([]uuid.UUID, 0, len(templateIDSet))
+	for templateID := range templateIDSet {
+		seenTemplateIDs = append(seenTemplateIDs, templateID)
+	}
+	slices.SortFunc(seenTemplateIDs, func(a, b uuid.UUID) int {
+		return slice.Ascending(a.String(), b.String())
+	})
+
+	resp := codersdk.UserBandwidthInsightsResponse{
+		Report: codersdk.UserBandwidthInsightsReport{
+			StartTime:   startTime,
+			EndTime:     endTime,
+			TemplateIDs: seenTemplateIDs,
+			Users:       userBandwidths,
+		},
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
 // @Summary Get insights about templates
 // @ID get-insights-about-templates
 // @Security CoderSessionToken
@@ -383,6 +476,353 @@ func convertTemplateInsightsApps(usage database.GetTemplateInsightsRow, appUsage
 	return apps
 }
 
+// Defaults for the template build SLO insights endpoint. The windows
+// correspond to the short and long windows commonly paired in multi-window
+// burn-rate alerts.
+var (
+	defaultBuildSLOWindows = []time.Duration{
+		5 * time.Minute,
+		30 * time.Minute,
+		time.Hour,
+		6 * time.Hour,
+		24 * time.Hour,
+		72 * time.Hour,
+	}
+	defaultBuildSLOSuccessObjective  = 0.99
+	defaultBuildSLODurationObjective = 10 * time.Minute
+)
+
+const (
+	maxBuildSLOWindows = 10
+	maxBuildSLOWindow  = 30 * 24 * time.Hour
+)
+
+// @Summary Get insights about template build SLOs
+// @ID get-insights-about-template-build-slos
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Success 200 {object} codersdk.TemplateBuildSLOInsightsResponse
+// @Router /insights/template-build-slos [get]
+func (api *API) insightsTemplateBuildSLOs(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	var (
+		templateIDs = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+		windows     = httpapi.ParseCustomList(p, vals, defaultBuildSLOWindows, "windows", parseBuildSLODuration)
+		successObj  = httpapi.ParseCustom(p, vals, defaultBuildSLOSuccessObjective, "success_objective", parseBuildSLOObjective)
+		durationObj = httpapi.ParseCustom(p, vals, defaultBuildSLODurationObjective, "duration_objective", parseBuildSLODuration)
+	)
+	p.ErrorExcessParams(vals)
+	if len(windows) > maxBuildSLOWindows {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "windows",
+			Detail: fmt.Sprintf("Query param %q must not contain more than %d windows", "windows", maxBuildSLOWindows),
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	// All windows share the same end time so that short and long windows can
+	// be compared against each other.
+	now := database.Now()
+	rowsByWindow := make([][]database.GetTemplateBuildSLOInsightsRow, len(windows))
+	err := api.Database.InTx(func(tx database.Store) error {
+		for i, window := range windows {
+			rows, err := tx.GetTemplateBuildSLOInsights(ctx, database.GetTemplateBuildSLOInsightsParams{
+				DurationObjectiveSeconds: durationObj.Seconds(),
+				StartTime:                now.Add(-window),
+				EndTime:                  now,
+				TemplateIDs:              templateIDs,
+			})
+			if err != nil {
+				return xerrors.Errorf("get template build slo insights for window %s: %w", window, err)
+			}
+			rowsByWindow[i] = rows
+		}
+		return nil
+	}, nil)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template build SLO insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateBuildSLOInsights(now, templateIDs, windows, successObj, durationObj, rowsByWindow))
+}
+
+// convertTemplateBuildSLOInsights builds the per template SLO report. Every
+// requested template is included, templates without builds in any window are
+// otherwise omitted. Windows without builds report full compliance.
+func convertTemplateBuildSLOInsights(now time.Time, templateIDs []uuid.UUID, windows []time.Duration, successObjective float64, durationObjective time.Duration, rowsByWindow [][]database.GetTemplateBuildSLOInsightsRow) codersdk.TemplateBuildSLOInsightsResponse {
+	templateIDSet := make(map[uuid.UUID]struct{})
+	for _, id := range templateIDs {
+		templateIDSet[id] = struct{}{}
+	}
+	for _, rows := range rowsByWindow {
+		for _, row := range rows {
+			templateIDSet[row.TemplateID] = struct{}{}
+		}
+	}
+	seenTemplateIDs := make([]uuid.UUID, 0, len(templateIDSet))
+	for id := range templateIDSet {
+		seenTemplateIDs = append(seenTemplateIDs, id)
+	}
+	slices.SortFunc(seenTemplateIDs, func(a, b uuid.UUID) int {
+		return slice.Ascending(a.String(), b.String())
+	})
+
+	// The error budget is the fraction of builds allowed to miss the
+	// objective, so the burn rate is the observed miss rate relative to it.
+	errorBudget := 1 - successObjective
+	burnRate := func(compliance float64) float64 {
+		return (1 - compliance) / errorBudget
+	}
+
+	resp := codersdk.TemplateBuildSLOInsightsResponse{
+		GeneratedAt:              now,
+		SuccessObjective:         successObjective,
+		DurationObjectiveSeconds: durationObjective.Seconds(),
+		Templates:                make([]codersdk.TemplateBuildSLO, 0, len(seenTemplateIDs)),
+	}
+	for _, templateID := range seenTemplateIDs {
+		slo := codersdk.TemplateBuildSLO{
+			TemplateID: templateID,
+			Windows:    make([]codersdk.TemplateBuildSLOWindow, 0, len(windows)),
+		}
+		for i, window := range windows {
+			w := codersdk.TemplateBuildSLOWindow{
+				WindowSeconds:               int64(window.Seconds()),
+				StartTime:                   now.Add(-window),
+				EndTime:                     now,
+				SuccessRate:                 1,
+				DurationObjectiveCompliance: 1,
+				BuildDurationP50Seconds:     -1,
+				BuildDurationP95Seconds:     -1,
+			}
+			for _, row := range rowsByWindow[i] {
+				if row.TemplateID != templateID {
+					continue
+				}
+				w.TotalBuilds = row.TotalBuilds
+				w.SuccessfulBuilds = row.SuccessfulBuilds
+				w.FailedBuilds = row.TotalBuilds - row.SuccessfulBuilds
+				w.BuildsWithinDurationObjective = row.BuildsWithinDurationObjective
+				w.BuildDurationP50Seconds = row.BuildDurationSeconds50
+				w.BuildDurationP95Seconds = row.BuildDurationSeconds95
+				if row.TotalBuilds > 0 {
+					w.SuccessRate = float64(row.SuccessfulBuilds) / float64(row.TotalBuilds)
+					w.DurationObjectiveCompliance = float64(row.BuildsWithinDurationObjective) / float64(row.TotalBuilds)
+				}
+				break
+			}
+			w.SuccessBurnRate = burnRate(w.SuccessRate)
+			w.DurationBurnRate = burnRate(w.DurationObjectiveCompliance)
+			slo.Windows = append(slo.Windows, w)
+		}
+		resp.Templates = append(resp.Templates, slo)
+	}
+	return resp
+}
+
+// @Summary Get insights about agent first connect times
+// @ID get-insights-about-agent-first-connect-times
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Success 200 {object} codersdk.AgentFirstConnectInsightsResponse
+// @Router /insights/agent-first-connect [get]
+func (api *API) insightsAgentFirstConnect(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	p := httpapi.NewQueryParamParser().
+		Required("start_time").
+		Required("end_time")
+	vals := r.URL.Query()
+	var (
+		// The QueryParamParser does not preserve timezone, so we need
+		// to parse the time ourselves.
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		templateIDs     = p.UUIDs(vals, []uuid.UUID{}, "template_ids")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	rows, err := api.Database.GetTemplateAgentFirstConnectInsights(ctx, database.GetTemplateAgentFirstConnectInsightsParams{
+		DefaultConnectionTimeoutSeconds: int32(agentDefaultConnectionTimeout.Seconds()),
+		StartTime:                       startTime,
+		EndTime:                         endTime,
+		TemplateIDs:                     templateIDs,
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching agent first connect insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	seenTemplateIDs := make([]uuid.UUID, 0, len(rows))
+	templates := make([]codersdk.TemplateAgentFirstConnect, 0, len(rows))
+	for _, row := range rows {
+		seenTemplateIDs = append(seenTemplateIDs, row.TemplateID)
+		templates = append(templates, codersdk.TemplateAgentFirstConnect{
+			TemplateID:             row.TemplateID,
+			TotalAgents:            row.TotalAgents,
+			ConnectedAgents:        row.ConnectedAgents,
+			NeverConnectedAgents:   row.NeverConnectedAgents,
+			FirstConnectP50Seconds: row.FirstConnectSeconds50,
+			FirstConnectP95Seconds: row.FirstConnectSeconds95,
+			FirstConnectP99Seconds: row.FirstConnectSeconds99,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.AgentFirstConnectInsightsResponse{
+		Report: codersdk.AgentFirstConnectInsightsReport{
+			StartTime:   startTime,
+			EndTime:     endTime,
+			TemplateIDs: seenTemplateIDs,
+			Templates:   templates,
+		},
+	})
+}
+
+const (
+	// netcheckInsightsMaxReports bounds the reports aggregated by the netcheck
+	// insights endpoint.
+	netcheckInsightsMaxReports = 10000
+	// netcheckInsightsRecentReports is the number of reports returned as is.
+	netcheckInsightsRecentReports = 25
+)
+
+// @Summary Get insights about netcheck reports
+// @ID get-insights-about-netcheck-reports
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param start_time query string true "Start time" format(date-time)
+// @Param end_time query string true "End time" format(date-time)
+// @Param user_id query string false "User ID or me" format(uuid)
+// @Success 200 {object} codersdk.NetcheckInsightsResponse
+// @Router /insights/netcheck [get]
+func (api *API) insightsNetcheck(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	p := httpapi.NewQueryParamParser().
+		Required("start_time").
+		Required("end_time")
+	vals := r.URL.Query()
+	var (
+		startTimeString = p.String(vals, "", "start_time")
+		endTimeString   = p.String(vals, "", "end_time")
+		userID          = p.UUIDorMe(vals, uuid.Nil, apiKey.UserID, "user_id")
+	)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	startTime, endTime, ok := parseInsightsStartAndEndTime(ctx, rw, startTimeString, endTimeString)
+	if !ok {
+		return
+	}
+
+	reports, err := api.Database.GetNetcheckReports(ctx, database.GetNetcheckReportsParams{
+		StartTime: startTime,
+		EndTime:   endTime,
+		UserID:    userID,
+		LimitOpt:  netcheckInsightsMaxReports,
+	})
+	if err != nil {
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching netcheck reports.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	report := codersdk.NetcheckInsightsReport{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		TotalReports: int64(len(reports)),
+		Reports:      make([]codersdk.NetcheckReport, 0, netcheckInsightsRecentReports),
+	}
+	if userID != uuid.Nil {
+		report.UserID = &userID
+	}
+	report.NATTypes, report.BlockedPorts = netcheckInsights(reports)
+	for i, row := range reports {
+		if i == netcheckInsightsRecentReports {
+			break
+		}
+		report.Reports = append(report.Reports, convertNetcheckReport(row))
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.NetcheckInsightsResponse{
+		Report: report,
+	})
+}
+
+func parseBuildSLODuration(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, err
+	}
+	if d < time.Minute || d > maxBuildSLOWindow {
+		return 0, xerrors.Errorf("duration must be between %s and %s", time.Minute, maxBuildSLOWindow)
+	}
+	return d, nil
+}
+
+func parseBuildSLOObjective(v string) (float64, error) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, err
+	}
+	if f <= 0 || f >= 1 {
+		return 0, xerrors.New("objective must be greater than 0 and less than 1")
+	}
+	return f, nil
+}
+
 // parseInsightsStartAndEndTime parses the start and end time query parameters
 // and returns the parsed values. The client provided timezone must be preserved
 // when parsing the time. Verification is performed so that the start and end
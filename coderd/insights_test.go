@@ -17,7 +17,10 @@ import (
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/agent"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/codersdk"
@@ -232,6 +235,95 @@ func TestUserLatencyInsights_BadRequest(t *testing.T) {
 	assert.Error(t, err, "want error for end time partial day when not today")
 }
 
+func TestUserBandwidthInsights(t *testing.T) {
+	t.Parallel()
+
+	db, pubsub := dbtestutil.NewDB(t)
+	client, closer, api := coderdtest.NewWithAPI(t, &coderdtest.Options{
+		Database: db,
+		Pubsub:   pubsub,
+	})
+	defer closer.Close()
+	user := coderdtest.CreateFirstUser(t, client)
+
+	y, m, d := time.Now().UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	var (
+		templateID  = uuid.New()
+		workspaceID = uuid.New()
+		agentID     = uuid.New()
+		now         = database.Now()
+	)
+	stats := database.InsertWorkspaceAgentBandwidthStatsParams{}
+	for _, s := range []struct {
+		typ  string
+		peer string
+		port int32
+		rx   int64
+		tx   int64
+	}{
+		{typ: "ssh", peer: "fd7a:115c:a1e0::1", rx: 10, tx: 20},
+		{typ: "ssh", peer: "fd7a:115c:a1e0::2", rx: 30, tx: 40},
+		{typ: "port_forward", peer: "fd7a:115c:a1e0::1", port: 8080, rx: 1000, tx: 2000},
+	} {
+		stats.ID = append(stats.ID, uuid.New())
+		stats.CreatedAt = append(stats.CreatedAt, now)
+		stats.UserID = append(stats.UserID, user.UserID)
+		stats.WorkspaceID = append(stats.WorkspaceID, workspaceID)
+		stats.TemplateID = append(stats.TemplateID, templateID)
+		stats.AgentID = append(stats.AgentID, agentID)
+		stats.ConnectionType = append(stats.ConnectionType, s.typ)
+		stats.Peer = append(stats.Peer, s.peer)
+		stats.Port = append(stats.Port, s.port)
+		stats.RxBytes = append(stats.RxBytes, s.rx)
+		stats.TxBytes = append(stats.TxBytes, s.tx)
+	}
+	//nolint:gocritic // Inserting stats is a system function.
+	err := api.Database.InsertWorkspaceAgentBandwidthStats(dbauthz.AsSystemRestricted(ctx), stats)
+	require.NoError(t, err)
+
+	resp, err := client.UserBandwidthInsights(ctx, codersdk.UserBandwidthInsightsRequest{
+		StartTime: today,
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{templateID}, resp.Report.TemplateIDs)
+	require.Len(t, resp.Report.Users, 2)
+
+	// The largest consumer comes first.
+	forward := resp.Report.Users[0]
+	assert.Equal(t, user.UserID, forward.UserID)
+	assert.Equal(t, "port_forward", forward.ConnectionType)
+	assert.EqualValues(t, 8080, forward.Port)
+	assert.EqualValues(t, 1, forward.Peers)
+	assert.EqualValues(t, 1000, forward.RxBytes)
+	assert.EqualValues(t, 2000, forward.TxBytes)
+
+	ssh := resp.Report.Users[1]
+	assert.Equal(t, "ssh", ssh.ConnectionType)
+	assert.Zero(t, ssh.Port)
+	assert.EqualValues(t, 2, ssh.Peers)
+	assert.EqualValues(t, 40, ssh.RxBytes)
+	assert.EqualValues(t, 60, ssh.TxBytes)
+
+	// Filtering on another template excludes the traffic.
+	other := dbgen.Template(t, db, database.Template{
+		OrganizationID: user.OrganizationID,
+		CreatedBy:      user.UserID,
+	})
+	resp, err = client.UserBandwidthInsights(ctx, codersdk.UserBandwidthInsightsRequest{
+		StartTime:   today,
+		EndTime:     time.Now().UTC().Truncate(time.Hour).Add(time.Hour),
+		TemplateIDs: []uuid.UUID{other.ID},
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.Report.Users)
+}
+
 func TestTemplateInsights(t *testing.T) {
 	t.Parallel()
 
@@ -811,3 +903,267 @@ func TestUserLatencyInsights_RBAC(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplateBuildSLOInsights(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+
+	okVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionComplete,
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, okVersion.ID)
+	okTemplate := coderdtest.CreateTemplate(t, client, user.OrganizationID, okVersion.ID)
+	okWorkspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, okTemplate.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, okWorkspace.LatestBuild.ID)
+
+	failVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionFailed,
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, failVersion.ID)
+	failTemplate := coderdtest.CreateTemplate(t, client, user.OrganizationID, failVersion.ID)
+	failWorkspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, failTemplate.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, failWorkspace.LatestBuild.ID)
+
+	// A template without builds should still be reported when requested.
+	idleVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, idleVersion.ID)
+	idleTemplate := coderdtest.CreateTemplate(t, client, user.OrganizationID, idleVersion.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	resp, err := client.TemplateBuildSLOInsights(ctx, codersdk.TemplateBuildSLOInsightsRequest{
+		TemplateIDs:       []uuid.UUID{okTemplate.ID, failTemplate.ID, idleTemplate.ID},
+		Windows:           []time.Duration{time.Hour, 24 * time.Hour},
+		SuccessObjective:  0.9,
+		DurationObjective: time.Minute,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0.9, resp.SuccessObjective)
+	assert.Equal(t, time.Minute.Seconds(), resp.DurationObjectiveSeconds)
+	require.Len(t, resp.Templates, 3)
+
+	byTemplateID := make(map[uuid.UUID]codersdk.TemplateBuildSLO)
+	for _, tmpl := range resp.Templates {
+		require.Len(t, tmpl.Windows, 2)
+		assert.Equal(t, int64(time.Hour.Seconds()), tmpl.Windows[0].WindowSeconds)
+		assert.Equal(t, int64((24 * time.Hour).Seconds()), tmpl.Windows[1].WindowSeconds)
+		byTemplateID[tmpl.TemplateID] = tmpl
+	}
+
+	for _, w := range byTemplateID[okTemplate.ID].Windows {
+		assert.EqualValues(t, 1, w.TotalBuilds)
+		assert.EqualValues(t, 1, w.SuccessfulBuilds)
+		assert.EqualValues(t, 1, w.BuildsWithinDurationObjective)
+		assert.Equal(t, 1.0, w.SuccessRate)
+		assert.Zero(t, w.SuccessBurnRate)
+		assert.GreaterOrEqual(t, w.BuildDurationP50Seconds, 0.0)
+	}
+	for _, w := range byTemplateID[failTemplate.ID].Windows {
+		assert.EqualValues(t, 1, w.TotalBuilds)
+		assert.EqualValues(t, 1, w.FailedBuilds)
+		assert.Zero(t, w.SuccessRate)
+		assert.InDelta(t, 10, w.SuccessBurnRate, 0.0001)
+		assert.InDelta(t, 10, w.DurationBurnRate, 0.0001)
+	}
+	for _, w := range byTemplateID[idleTemplate.ID].Windows {
+		assert.Zero(t, w.TotalBuilds)
+		assert.Equal(t, 1.0, w.SuccessRate)
+		assert.Equal(t, -1.0, w.BuildDurationP50Seconds)
+	}
+}
+
+func TestTemplateBuildSLOInsights_BadRequest(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{})
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	_, err := client.TemplateBuildSLOInsights(ctx, codersdk.TemplateBuildSLOInsightsRequest{
+		SuccessObjective: 1.5,
+	})
+	assert.Error(t, err, "want error for objective above 1")
+
+	_, err = client.TemplateBuildSLOInsights(ctx, codersdk.TemplateBuildSLOInsightsRequest{
+		Windows: []time.Duration{time.Second},
+	})
+	assert.Error(t, err, "want error for window below a minute")
+
+	_, err = client.TemplateBuildSLOInsights(ctx, codersdk.TemplateBuildSLOInsightsRequest{
+		Windows: []time.Duration{365 * 24 * time.Hour},
+	})
+	assert.Error(t, err, "want error for window above 30 days")
+}
+
+func TestTemplateBuildInsights(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon:    true,
+			MetricsCacheRefreshInterval: time.Millisecond * 100,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+
+		okVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionComplete,
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, okVersion.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, okVersion.ID)
+		okWorkspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, okWorkspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		failVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionFailed,
+		}, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, failVersion.ID)
+		err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: failVersion.ID,
+		})
+		require.NoError(t, err)
+		failWorkspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, failWorkspace.LatestBuild.ID)
+
+		// The builds are rolled up periodically.
+		var resp codersdk.TemplateBuildInsightsResponse
+		require.Eventually(t, func() bool {
+			resp, err = client.TemplateBuildInsights(ctx, template.ID)
+			return assert.NoError(t, err) && len(resp.Versions) == 2
+		}, testutil.WaitLong, testutil.IntervalFast)
+		assert.Equal(t, template.ID, resp.TemplateID)
+
+		failed := resp.Versions[0]
+		assert.Equal(t, failVersion.ID, failed.TemplateVersionID)
+		assert.True(t, failed.Active)
+		assert.EqualValues(t, 1, failed.TotalBuilds)
+		assert.EqualValues(t, 1, failed.FailedBuilds)
+		assert.Equal(t, 1.0, failed.FailureRate)
+		assert.Equal(t, -1.0, failed.DurationP50Seconds)
+		require.Len(t, failed.Failures, 1)
+		assert.Empty(t, failed.Failures[0].ErrorCode)
+		assert.EqualValues(t, 1, failed.Failures[0].Builds)
+		require.NotNil(t, failed.Comparison)
+		assert.Equal(t, okVersion.ID, failed.Comparison.PreviousTemplateVersionID)
+		assert.Equal(t, 1.0, failed.Comparison.FailureRateChange)
+		assert.Zero(t, failed.Comparison.DurationP50ChangeSeconds)
+
+		ok := resp.Versions[1]
+		assert.Equal(t, okVersion.ID, ok.TemplateVersionID)
+		assert.False(t, ok.Active)
+		assert.EqualValues(t, 1, ok.TotalBuilds)
+		assert.Zero(t, ok.FailedBuilds)
+		assert.Empty(t, ok.Failures)
+		assert.GreaterOrEqual(t, ok.DurationP50Seconds, 0.0)
+		assert.Nil(t, ok.Comparison)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := member.TemplateBuildInsights(ctx, template.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
+func TestAgentFirstConnectInsights(t *testing.T) {
+	t.Parallel()
+
+	logger := slogtest.Make(t, nil)
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	// Start must be at the beginning of the day, initialize it early in case
+	// the day changes.
+	y, m, d := time.Now().UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Logger: logger.Named("agent"),
+		Client: agentClient,
+	})
+	defer func() {
+		_ = agentCloser.Close()
+	}()
+	coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	resp, err := client.AgentFirstConnectInsights(ctx, codersdk.AgentFirstConnectInsightsRequest{
+		StartTime:   today,
+		EndTime:     time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+		TemplateIDs: []uuid.UUID{template.ID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{template.ID}, resp.Report.TemplateIDs)
+	require.Len(t, resp.Report.Templates, 1)
+	got := resp.Report.Templates[0]
+	assert.EqualValues(t, 1, got.TotalAgents)
+	assert.EqualValues(t, 1, got.ConnectedAgents)
+	assert.Zero(t, got.NeverConnectedAgents)
+	assert.GreaterOrEqual(t, got.FirstConnectP50Seconds, 0.0)
+	assert.GreaterOrEqual(t, got.FirstConnectP99Seconds, got.FirstConnectP50Seconds)
+}
+
+func TestTemplateBuildSLOInsights_RBAC(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{})
+	admin := coderdtest.CreateFirstUser(t, client)
+	templateAdmin, _ := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID, rbac.RoleTemplateAdmin())
+	regular, _ := coderdtest.CreateAnotherUser(t, client, admin.OrganizationID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	_, err := templateAdmin.TemplateBuildSLOInsights(ctx, codersdk.TemplateBuildSLOInsightsRequest{})
+	require.NoError(t, err)
+
+	_, err = regular.TemplateBuildSLOInsights(ctx, codersdk.TemplateBuildSLOInsightsRequest{})
+	require.Error(t, err)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
@@ -0,0 +1,211 @@
+package coderd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// netcheckMaxBlockedPorts bounds the ports a client may report as
+	// blocked, as they're stored with every report.
+	netcheckMaxBlockedPorts = 64
+	netcheckMaxStringLength = 64
+)
+
+var netcheckPortRegex = regexp.MustCompile(`^(tcp|udp)/[0-9]{1,5}$`)
+
+// @Summary Post netcheck report
+// @ID post-netcheck-report
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags General
+// @Param request body codersdk.PostNetcheckReportRequest true "Netcheck report"
+// @Success 201 {object} codersdk.NetcheckReport
+// @Router /netcheck [post]
+func (api *API) postNetcheckReport(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	var req codersdk.PostNetcheckReportRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if err := validateNetcheckReport(req); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid netcheck report.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	latencies, err := json.Marshal(req.RegionLatencyMS)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	blockedPorts := req.BlockedPorts
+	if blockedPorts == nil {
+		blockedPorts = []string{}
+	}
+
+	report, err := api.Database.InsertNetcheckReport(ctx, database.InsertNetcheckReportParams{
+		ID:                    uuid.New(),
+		UserID:                apiKey.UserID,
+		CreatedAt:             database.Now(),
+		ClientVersion:         req.ClientVersion,
+		ClientOS:              req.ClientOS,
+		NATType:               string(req.NATType),
+		UDP:                   req.UDP,
+		IPv4:                  req.IPv4,
+		IPv6:                  req.IPv6,
+		MappingVariesByDestIP: nullBool(req.MappingVariesByDestIP),
+		HairPinning:           nullBool(req.HairPinning),
+		PreferredDERP:         int32(req.PreferredDERP),
+		RegionLatencies:       latencies,
+		BlockedPorts:          blockedPorts,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error inserting netcheck report.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertNetcheckReport(report))
+}
+
+func validateNetcheckReport(req codersdk.PostNetcheckReportRequest) error {
+	switch req.NATType {
+	case codersdk.NATTypeUnknown, codersdk.NATTypeUDPBlocked, codersdk.NATTypeEasy, codersdk.NATTypeHard:
+	default:
+		return xerrors.Errorf("unknown nat type %q", req.NATType)
+	}
+	if len(req.ClientVersion) > netcheckMaxStringLength || len(req.ClientOS) > netcheckMaxStringLength {
+		return xerrors.Errorf("client version and os must be at most %d characters", netcheckMaxStringLength)
+	}
+	if len(req.BlockedPorts) > netcheckMaxBlockedPorts {
+		return xerrors.Errorf("at most %d blocked ports may be reported", netcheckMaxBlockedPorts)
+	}
+	for _, port := range req.BlockedPorts {
+		if !netcheckPortRegex.MatchString(port) {
+			return xerrors.Errorf("blocked port %q must be in the form tcp/<port> or udp/<port>", port)
+		}
+	}
+	for regionID, latency := range req.RegionLatencyMS {
+		if latency < 0 {
+			return xerrors.Errorf("latency for region %d must not be negative", regionID)
+		}
+	}
+	return nil
+}
+
+func convertNetcheckReport(report database.NetcheckReport) codersdk.NetcheckReport {
+	latencies := map[int]float64{}
+	// The latencies are validated before they're stored.
+	_ = json.Unmarshal(report.RegionLatencies, &latencies)
+	blockedPorts := report.BlockedPorts
+	if blockedPorts == nil {
+		blockedPorts = []string{}
+	}
+	return codersdk.NetcheckReport{
+		ID:        report.ID,
+		UserID:    report.UserID,
+		CreatedAt: report.CreatedAt,
+		PostNetcheckReportRequest: codersdk.PostNetcheckReportRequest{
+			ClientVersion:         report.ClientVersion,
+			ClientOS:              report.ClientOS,
+			NATType:               codersdk.NATType(report.NATType),
+			UDP:                   report.UDP,
+			IPv4:                  report.IPv4,
+			IPv6:                  report.IPv6,
+			MappingVariesByDestIP: boolPtr(report.MappingVariesByDestIP),
+			HairPinning:           boolPtr(report.HairPinning),
+			PreferredDERP:         int(report.PreferredDERP),
+			RegionLatencyMS:       latencies,
+			BlockedPorts:          blockedPorts,
+		},
+	}
+}
+
+func nullBool(v *bool) sql.NullBool {
+	if v == nil {
+		return sql.NullBool{}
+	}
+	return sql.NullBool{Bool: *v, Valid: true}
+}
+
+func boolPtr(v sql.NullBool) *bool {
+	if !v.Valid {
+		return nil
+	}
+	b := v.Bool
+	return &b
+}
+
+// netcheckInsights aggregates the reports by NAT type and blocked port.
+func netcheckInsights(reports []database.NetcheckReport) ([]codersdk.NetcheckNATTypeInsight, []codersdk.NetcheckBlockedPortInsight) {
+	type counts struct {
+		reports int64
+		users   map[uuid.UUID]struct{}
+	}
+	add := func(m map[string]*counts, key string, userID uuid.UUID) {
+		c, ok := m[key]
+		if !ok {
+			c = &counts{users: map[uuid.UUID]struct{}{}}
+			m[key] = c
+		}
+		c.reports++
+		c.users[userID] = struct{}{}
+	}
+	natTypes := map[string]*counts{}
+	ports := map[string]*counts{}
+	for _, report := range reports {
+		add(natTypes, report.NATType, report.UserID)
+		for _, port := range report.BlockedPorts {
+			add(ports, port, report.UserID)
+		}
+	}
+
+	natInsights := make([]codersdk.NetcheckNATTypeInsight, 0, len(natTypes))
+	for natType, c := range natTypes {
+		natInsights = append(natInsights, codersdk.NetcheckNATTypeInsight{
+			NATType: codersdk.NATType(natType),
+			Reports: c.reports,
+			Users:   int64(len(c.users)),
+		})
+	}
+	portInsights := make([]codersdk.NetcheckBlockedPortInsight, 0, len(ports))
+	for port, c := range ports {
+		portInsights = append(portInsights, codersdk.NetcheckBlockedPortInsight{
+			Port:    port,
+			Reports: c.reports,
+			Users:   int64(len(c.users)),
+		})
+	}
+	// Most reported first, so the most common issues stand out.
+	sort.Slice(natInsights, func(i, j int) bool {
+		if natInsights[i].Reports != natInsights[j].Reports {
+			return natInsights[i].Reports > natInsights[j].Reports
+		}
+		return natInsights[i].NATType < natInsights[j].NATType
+	})
+	sort.Slice(portInsights, func(i, j int) bool {
+		if portInsights[i].Reports != portInsights[j].Reports {
+			return portInsights[i].Reports > portInsights[j].Reports
+		}
+		return portInsights[i].Port < portInsights[j].Port
+	})
+	return natInsights, portInsights
+}
@@ -0,0 +1,112 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestNetcheckReports(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, nil)
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	mappingVaries := true
+	report, err := memberClient.PostNetcheckReport(ctx, codersdk.PostNetcheckReportRequest{
+		ClientVersion:         "v2.1.0",
+		ClientOS:              "darwin",
+		NATType:               codersdk.NATTypeHard,
+		UDP:                   true,
+		IPv4:                  true,
+		MappingVariesByDestIP: &mappingVaries,
+		PreferredDERP:         999,
+		RegionLatencyMS:       map[int]float64{999: 12.5},
+		BlockedPorts:          []string{"tcp/443"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, member.ID, report.UserID)
+	require.Equal(t, codersdk.NATTypeHard, report.NATType)
+	require.Equal(t, map[int]float64{999: 12.5}, report.RegionLatencyMS)
+	require.NotNil(t, report.MappingVariesByDestIP)
+	require.True(t, *report.MappingVariesByDestIP)
+	require.Nil(t, report.HairPinning)
+
+	_, err = client.PostNetcheckReport(ctx, codersdk.PostNetcheckReportRequest{
+		NATType:      codersdk.NATTypeUDPBlocked,
+		BlockedPorts: []string{"udp/3478", "tcp/443"},
+	})
+	require.NoError(t, err)
+
+	_, err = client.PostNetcheckReport(ctx, codersdk.PostNetcheckReportRequest{
+		NATType: "symmetric",
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	_, err = client.PostNetcheckReport(ctx, codersdk.PostNetcheckReportRequest{
+		NATType:      codersdk.NATTypeEasy,
+		BlockedPorts: []string{"3478"},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	y, m, d := time.Now().UTC().Date()
+	req := codersdk.NetcheckInsightsRequest{
+		StartTime: time.Date(y, m, d, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Now().UTC().Truncate(time.Hour).Add(time.Hour), // Round up to include the current hour.
+	}
+
+	t.Run("Deployment", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp, err := client.NetcheckInsights(ctx, req)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, resp.Report.TotalReports)
+		assert.Nil(t, resp.Report.UserID)
+		require.Len(t, resp.Report.Reports, 2)
+		assert.Equal(t, codersdk.NATTypeUDPBlocked, resp.Report.Reports[0].NATType, "newest first")
+		assert.ElementsMatch(t, []codersdk.NetcheckNATTypeInsight{
+			{NATType: codersdk.NATTypeHard, Reports: 1, Users: 1},
+			{NATType: codersdk.NATTypeUDPBlocked, Reports: 1, Users: 1},
+		}, resp.Report.NATTypes)
+		assert.Equal(t, []codersdk.NetcheckBlockedPortInsight{
+			{Port: "tcp/443", Reports: 2, Users: 2},
+			{Port: "udp/3478", Reports: 1, Users: 1},
+		}, resp.Report.BlockedPorts)
+	})
+
+	t.Run("User", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		userReq := req
+		userReq.UserID = member.ID
+		resp, err := client.NetcheckInsights(ctx, userReq)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, resp.Report.TotalReports)
+		require.NotNil(t, resp.Report.UserID)
+		assert.Equal(t, member.ID, *resp.Report.UserID)
+		require.Len(t, resp.Report.Reports, 1)
+		assert.Equal(t, report.ID, resp.Report.Reports[0].ID)
+
+		// Members may only see their own reports.
+		resp, err = memberClient.NetcheckInsights(ctx, userReq)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, resp.Report.TotalReports)
+		_, err = memberClient.NetcheckInsights(ctx, req)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -109,6 +109,63 @@ func (api *API) postOrganizations(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusCreated, convertOrganization(organization))
 }
 
+// @Summary Update organization
+// @ID update-organization
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Organizations
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param request body codersdk.UpdateOrganizationRequest true "Update organization request"
+// @Success 200 {object} codersdk.Organization
+// @Router /organizations/{organization} [patch]
+func (api *API) patchOrganization(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	organization := httpmw.OrganizationParam(r)
+
+	var req codersdk.UpdateOrganizationRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	maxConcurrentProvisionerJobs := organization.MaxConcurrentProvisionerJobs
+	if req.MaxConcurrentProvisionerJobs != nil {
+		if *req.MaxConcurrentProvisionerJobs < 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Invalid request to update organization.",
+				Validations: []codersdk.ValidationError{
+					{Field: "max_concurrent_provisioner_jobs", Detail: "Must be a positive integer."},
+				},
+			})
+			return
+		}
+		maxConcurrentProvisionerJobs = *req.MaxConcurrentProvisionerJobs
+	}
+	if maxConcurrentProvisionerJobs == organization.MaxConcurrentProvisionerJobs {
+		httpapi.Write(ctx, rw, http.StatusOK, convertOrganization(organization))
+		return
+	}
+
+	updated, err := api.Database.UpdateOrganizationProvisionerLimits(ctx, database.UpdateOrganizationProvisionerLimitsParams{
+		ID:                           organization.ID,
+		UpdatedAt:                    database.Now(),
+		MaxConcurrentProvisionerJobs: maxConcurrentProvisionerJobs,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating organization.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertOrganization(updated))
+}
+
 // convertOrganization consumes the database representation and outputs an API friendly representation.
 func convertOrganization(organization database.Organization) codersdk.Organization {
 	return codersdk.Organization{
@@ -116,5 +173,7 @@ func convertOrganization(organization database.Organization) codersdk.Organizati
 		Name:      organization.Name,
 		CreatedAt: organization.CreatedAt,
 		UpdatedAt: organization.UpdatedAt,
+
+		MaxConcurrentProvisionerJobs: organization.MaxConcurrentProvisionerJobs,
 	}
 }
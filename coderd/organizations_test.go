@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -110,3 +111,49 @@ func TestPostOrganizationsByUser(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestPatchOrganization(t *testing.T) {
+	t.Parallel()
+	t.Run("ProvisionerLimits", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		org, err := client.UpdateOrganization(ctx, user.OrganizationID, codersdk.UpdateOrganizationRequest{
+			MaxConcurrentProvisionerJobs: ptr.Ref(int32(4)),
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 4, org.MaxConcurrentProvisionerJobs)
+
+		org, err = client.Organization(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		require.EqualValues(t, 4, org.MaxConcurrentProvisionerJobs)
+
+		_, err = client.UpdateOrganization(ctx, user.OrganizationID, codersdk.UpdateOrganizationRequest{
+			MaxConcurrentProvisionerJobs: ptr.Ref(int32(-1)),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("NoPermission", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := member.UpdateOrganization(ctx, user.OrganizationID, codersdk.UpdateOrganizationRequest{
+			MaxConcurrentProvisionerJobs: ptr.Ref(int32(1)),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
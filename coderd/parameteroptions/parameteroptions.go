@@ -0,0 +1,150 @@
+// Package parameteroptions fetches the options of template parameters that are
+// provided by a template-defined endpoint instead of being static.
+package parameteroptions
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// CacheDuration is how long the options returned by an endpoint are
+	// reused before the endpoint is called again.
+	CacheDuration = time.Minute
+	// MaxOptions is the maximum number of options an endpoint can return.
+	MaxOptions = 1024
+
+	fetchTimeout    = 10 * time.Second
+	maxResponseSize = 1 << 20
+)
+
+// ValidateURL checks that rawURL can be used as the options endpoint of a
+// parameter.
+func ValidateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return xerrors.Errorf("parse options URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return xerrors.Errorf("options URL %q must use the http or https scheme", rawURL)
+	}
+	if u.Host == "" {
+		return xerrors.Errorf("options URL %q must have a host", rawURL)
+	}
+	return nil
+}
+
+type cachedOptions struct {
+	options []codersdk.TemplateVersionParameterOption
+	expires time.Time
+}
+
+// Fetcher fetches and caches the options of parameters from their endpoints.
+// Endpoints must respond with a JSON array of options.
+type Fetcher struct {
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]cachedOptions
+}
+
+// New returns a Fetcher using the provided HTTP client. If client is nil,
+// http.DefaultClient is used.
+func New(client *http.Client) *Fetcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Fetcher{
+		client: client,
+		cache:  make(map[string]cachedOptions),
+	}
+}
+
+// Fetch returns the options served by the endpoint at rawURL. Options are
+// cached for CacheDuration, and errors are not cached.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) ([]codersdk.TemplateVersionParameterOption, error) {
+	f.mu.Lock()
+	cached, ok := f.cache[rawURL]
+	f.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.options, nil
+	}
+
+	options, err := f.fetch(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	f.mu.Lock()
+	for u, c := range f.cache {
+		if !now.Before(c.expires) {
+			delete(f.cache, u)
+		}
+	}
+	f.cache[rawURL] = cachedOptions{
+		options: options,
+		expires: now.Add(CacheDuration),
+	}
+	f.mu.Unlock()
+	return options, nil
+}
+
+func (f *Fetcher) fetch(ctx context.Context, rawURL string) ([]codersdk.TemplateVersionParameterOption, error) {
+	err := ValidateURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, xerrors.Errorf("fetch options: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("fetch options: unexpected status code %d", res.StatusCode)
+	}
+
+	var options []codersdk.TemplateVersionParameterOption
+	err = json.NewDecoder(io.LimitReader(res.Body, maxResponseSize)).Decode(&options)
+	if err != nil {
+		return nil, xerrors.Errorf("decode options: %w", err)
+	}
+	if len(options) == 0 {
+		return nil, xerrors.New("the endpoint returned no options")
+	}
+	if len(options) > MaxOptions {
+		return nil, xerrors.Errorf("the endpoint returned %d options, the maximum is %d", len(options), MaxOptions)
+	}
+	seen := make(map[string]struct{}, len(options))
+	for i, option := range options {
+		if option.Value == "" {
+			return nil, xerrors.Errorf("option %d has an empty value", i)
+		}
+		if _, ok := seen[option.Value]; ok {
+			return nil, xerrors.Errorf("option value %q is duplicated", option.Value)
+		}
+		seen[option.Value] = struct{}{}
+		if option.Name == "" {
+			options[i].Name = option.Value
+		}
+	}
+	return options, nil
+}
@@ -0,0 +1,83 @@
+package parameteroptions_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/parameteroptions"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestFetcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		var calls atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			_, _ = rw.Write([]byte(`[{"name":"Small","value":"small"},{"value":"large"}]`))
+		}))
+		t.Cleanup(srv.Close)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+		defer cancel()
+
+		fetcher := parameteroptions.New(srv.Client())
+		options, err := fetcher.Fetch(ctx, srv.URL)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.TemplateVersionParameterOption{
+			{Name: "Small", Value: "small"},
+			{Name: "large", Value: "large"},
+		}, options)
+
+		// The second call is served from the cache.
+		_, err = fetcher.Fetch(ctx, srv.URL)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, calls.Load())
+	})
+
+	for _, tc := range []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "BadStatus", status: http.StatusInternalServerError, body: `[{"value":"a"}]`},
+		{name: "NotJSON", status: http.StatusOK, body: `not JSON`},
+		{name: "NoOptions", status: http.StatusOK, body: `[]`},
+		{name: "EmptyValue", status: http.StatusOK, body: `[{"name":"a"}]`},
+		{name: "DuplicateValue", status: http.StatusOK, body: `[{"value":"a"},{"value":"a"}]`},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(tc.status)
+				_, _ = rw.Write([]byte(tc.body))
+			}))
+			t.Cleanup(srv.Close)
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+			defer cancel()
+
+			_, err := parameteroptions.New(srv.Client()).Fetch(ctx, srv.URL)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, parameteroptions.ValidateURL("https://example.com/options"))
+	require.NoError(t, parameteroptions.ValidateURL("http://localhost:8080"))
+	require.Error(t, parameteroptions.ValidateURL("file:///etc/passwd"))
+	require.Error(t, parameteroptions.ValidateURL("https://"))
+	require.Error(t, parameteroptions.ValidateURL("://"))
+}
@@ -0,0 +1,136 @@
+package coderd
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	platformEventsPageSize = 100
+	// platformEventsPollInterval is how often the stream checks for events
+	// without a notification. This picks up events that were notified before
+	// their transaction committed, or that committed out of order.
+	platformEventsPollInterval = 10 * time.Second
+)
+
+// @Summary Watch platform events
+// @ID watch-platform-events
+// @Security CoderSessionToken
+// @Produce text/event-stream
+// @Tags General
+// @Param after query int false "Only stream events after this cursor. Omit to replay every retained event."
+// @Success 200 {object} codersdk.PlatformEvent
+// @Router /platform-events [get]
+func (api *API) watchPlatformEvents(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.Authorize(r, rbac.ActionRead, rbac.ResourcePlatformEvent) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var after int64
+	if raw := r.URL.Query().Get("after"); raw != "" {
+		var err error
+		after, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil || after < 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Query param \"after\" must be a non-negative integer.",
+				Validations: []codersdk.ValidationError{
+					{Field: "after", Detail: "Must be a non-negative integer"},
+				},
+			})
+			return
+		}
+	}
+
+	// Subscribe before the first read so no event can be recorded between
+	// reading and subscribing without us hearing about it.
+	notify := make(chan struct{}, 1)
+	cancelSubscribe, err := api.Pubsub.Subscribe(codersdk.PlatformEventsNotifyChannel, func(_ context.Context, _ []byte) {
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error subscribing to platform events.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer cancelSubscribe()
+
+	sendEvent, senderClosed, err := httpapi.ServerSentEventSender(rw, r)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error setting up server-sent events.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Prevent handler from returning until the sender is closed.
+	defer func() {
+		<-senderClosed
+	}()
+
+	ticker := time.NewTicker(platformEventsPollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			events, err := api.Database.GetPlatformEventsAfterID(ctx, database.GetPlatformEventsAfterIDParams{
+				AfterID:  after,
+				LimitOpt: platformEventsPageSize,
+			})
+			if err != nil {
+				_ = sendEvent(ctx, codersdk.ServerSentEvent{
+					Type: codersdk.ServerSentEventTypeError,
+					Data: codersdk.Response{
+						Message: "Internal error fetching platform events.",
+						Detail:  err.Error(),
+					},
+				})
+				return
+			}
+			for _, event := range events {
+				err = sendEvent(ctx, codersdk.ServerSentEvent{
+					Type: codersdk.ServerSentEventTypeData,
+					Data: convertPlatformEvent(event),
+				})
+				if err != nil {
+					return
+				}
+				after = event.ID
+			}
+			if len(events) < platformEventsPageSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-senderClosed:
+			return
+		case <-notify:
+		case <-ticker.C:
+		}
+	}
+}
+
+func convertPlatformEvent(event database.PlatformEvent) codersdk.PlatformEvent {
+	return codersdk.PlatformEvent{
+		ID:         event.ID,
+		Type:       codersdk.PlatformEventType(event.Type),
+		CreatedAt:  event.CreatedAt,
+		ResourceID: event.ResourceID,
+		Data:       event.Data,
+	}
+}
@@ -0,0 +1,153 @@
+// Package platformevents records the significant domain events that are
+// streamed to platform integrations from /api/v2/platform-events.
+package platformevents
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// Publisher records platform events and notifies watchers of them. Recording
+// is best-effort: failures are logged rather than returned so an event can
+// never fail the operation it describes.
+type Publisher struct {
+	logger slog.Logger
+	db     database.Store
+	ps     pubsub.Pubsub
+}
+
+func New(logger slog.Logger, db database.Store, ps pubsub.Pubsub) *Publisher {
+	return &Publisher{
+		logger: logger,
+		db:     db,
+		ps:     ps,
+	}
+}
+
+// WithStore returns a Publisher that records events using db. Use it inside
+// a transaction so that events are only recorded if the transaction commits.
+func (p *Publisher) WithStore(db database.Store) *Publisher {
+	return New(p.logger, db, p.ps)
+}
+
+func (p *Publisher) User(ctx context.Context, eventType codersdk.PlatformEventType, user database.User) {
+	p.publish(ctx, eventType, user.ID, codersdk.PlatformEventUser{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Status:   codersdk.UserStatus(user.Status),
+	})
+}
+
+func (p *Publisher) Workspace(ctx context.Context, eventType codersdk.PlatformEventType, workspace database.Workspace) {
+	p.publish(ctx, eventType, workspace.ID, codersdk.PlatformEventWorkspace{
+		ID:             workspace.ID,
+		Name:           workspace.Name,
+		OwnerID:        workspace.OwnerID,
+		OrganizationID: workspace.OrganizationID,
+		TemplateID:     workspace.TemplateID,
+	})
+}
+
+// WorkspaceBuild records a build transition. buildErr should be empty unless
+// the build failed.
+func (p *Publisher) WorkspaceBuild(ctx context.Context, eventType codersdk.PlatformEventType, build database.WorkspaceBuild, buildErr string) {
+	p.publish(ctx, eventType, build.ID, codersdk.PlatformEventWorkspaceBuild{
+		ID:          build.ID,
+		WorkspaceID: build.WorkspaceID,
+		BuildNumber: build.BuildNumber,
+		Transition:  codersdk.WorkspaceTransition(build.Transition),
+		Reason:      codersdk.BuildReason(build.Reason),
+		InitiatorID: build.InitiatorID,
+		Error:       buildErr,
+	})
+}
+
+// WorkspaceApproval records an approval request of a workspace build being
+// requested or decided.
+func (p *Publisher) WorkspaceApproval(ctx context.Context, eventType codersdk.PlatformEventType, approval database.WorkspaceApproval, workspace database.Workspace) {
+	data := codersdk.PlatformEventWorkspaceApproval{
+		ID:          approval.ID,
+		WorkspaceID: approval.WorkspaceID,
+		TemplateID:  workspace.TemplateID,
+		RequestedBy: approval.RequestedBy,
+		Status:      codersdk.WorkspaceApprovalStatus(approval.Status),
+		Reason:      approval.Reason,
+	}
+	if approval.DecidedBy.Valid {
+		data.DecidedBy = &approval.DecidedBy.UUID
+	}
+	p.publish(ctx, eventType, approval.ID, data)
+}
+
+// TemplateAccessRequest records a request to use a template being made or
+// decided.
+func (p *Publisher) TemplateAccessRequest(ctx context.Context, eventType codersdk.PlatformEventType, request database.TemplateAccessRequest) {
+	data := codersdk.PlatformEventTemplateAccessRequest{
+		ID:          request.ID,
+		TemplateID:  request.TemplateID,
+		RequestedBy: request.RequestedBy,
+		Status:      codersdk.TemplateAccessRequestStatus(request.Status),
+		Reason:      request.Reason,
+	}
+	if request.DecidedBy.Valid {
+		data.DecidedBy = &request.DecidedBy.UUID
+	}
+	p.publish(ctx, eventType, request.ID, data)
+}
+
+// TemplateVersionPromotion records a promotion of a template version being
+// requested or decided.
+func (p *Publisher) TemplateVersionPromotion(ctx context.Context, eventType codersdk.PlatformEventType, promotion database.TemplateVersionPromotion) {
+	data := codersdk.PlatformEventTemplateVersionPromotion{
+		ID:                promotion.ID,
+		TemplateID:        promotion.TemplateID,
+		TemplateVersionID: promotion.TemplateVersionID,
+		RequestedBy:       promotion.RequestedBy,
+		Status:            codersdk.TemplateVersionPromotionStatus(promotion.Status),
+		Reason:            promotion.Reason,
+	}
+	if promotion.DecidedBy.Valid {
+		data.DecidedBy = &promotion.DecidedBy.UUID
+	}
+	p.publish(ctx, eventType, promotion.ID, data)
+}
+
+// EntitlementsChanged records a change in entitlements. Entitlements are
+// deployment-wide, so the event has no resource ID.
+func (p *Publisher) EntitlementsChanged(ctx context.Context, entitlements codersdk.PlatformEventEntitlements) {
+	p.publish(ctx, codersdk.PlatformEventTypeEntitlementsChanged, uuid.Nil, entitlements)
+}
+
+func (p *Publisher) publish(ctx context.Context, eventType codersdk.PlatformEventType, resourceID uuid.UUID, data any) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		p.logger.Error(ctx, "marshal platform event", slog.F("type", eventType), slog.Error(err))
+		return
+	}
+	//nolint:gocritic // Events are recorded by the system on behalf of
+	// whoever triggered them, who can't write events themselves.
+	event, err := p.db.InsertPlatformEvent(dbauthz.AsSystemRestricted(ctx), database.InsertPlatformEventParams{
+		CreatedAt:  database.Now(),
+		Type:       string(eventType),
+		ResourceID: resourceID,
+		Data:       raw,
+	})
+	if err != nil {
+		p.logger.Error(ctx, "insert platform event", slog.F("type", eventType), slog.Error(err))
+		return
+	}
+	err = p.ps.Publish(codersdk.PlatformEventsNotifyChannel, []byte(strconv.FormatInt(event.ID, 10)))
+	if err != nil {
+		p.logger.Warn(ctx, "publish platform event", slog.F("type", eventType), slog.Error(err))
+	}
+}
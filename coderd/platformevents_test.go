@@ -0,0 +1,88 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWatchPlatformEvents(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	_, err := memberClient.WatchPlatformEvents(ctx, 0)
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	events, err := client.WatchPlatformEvents(ctx, 0)
+	require.NoError(t, err)
+	// next returns the next event of the given type, skipping any others.
+	next := func(eventType codersdk.PlatformEventType) codersdk.PlatformEvent {
+		t.Helper()
+		for {
+			select {
+			case <-ctx.Done():
+				require.FailNow(t, "timed out waiting for event", eventType)
+			case event, ok := <-events:
+				require.True(t, ok, "event stream closed waiting for %s", eventType)
+				if event.Type == eventType {
+					return event
+				}
+			}
+		}
+	}
+
+	// Events from before the stream was opened are replayed.
+	event := next(codersdk.PlatformEventTypeUserCreated)
+	require.Equal(t, user.UserID, event.ResourceID)
+	event = next(codersdk.PlatformEventTypeUserCreated)
+	require.Equal(t, member.ID, event.ResourceID)
+	resumeAfter := event.ID
+
+	_, err = client.UpdateUserStatus(ctx, member.ID.String(), codersdk.UserStatusSuspended)
+	require.NoError(t, err)
+	event = next(codersdk.PlatformEventTypeUserStatusChanged)
+	var eventUser codersdk.PlatformEventUser
+	require.NoError(t, json.Unmarshal(event.Data, &eventUser))
+	require.Equal(t, member.ID, eventUser.ID)
+	require.Equal(t, codersdk.UserStatusSuspended, eventUser.Status)
+
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	event = next(codersdk.PlatformEventTypeWorkspaceCreated)
+	require.Equal(t, workspace.ID, event.ResourceID)
+	event = next(codersdk.PlatformEventTypeWorkspaceBuildStarted)
+	require.Equal(t, workspace.LatestBuild.ID, event.ResourceID)
+	event = next(codersdk.PlatformEventTypeWorkspaceBuildSucceeded)
+	var eventBuild codersdk.PlatformEventWorkspaceBuild
+	require.NoError(t, json.Unmarshal(event.Data, &eventBuild))
+	require.Equal(t, workspace.ID, eventBuild.WorkspaceID)
+	require.Equal(t, codersdk.WorkspaceTransitionStart, eventBuild.Transition)
+
+	// Resuming from a cursor skips everything up to and including it.
+	resumed, err := client.WatchPlatformEvents(ctx, resumeAfter)
+	require.NoError(t, err)
+	select {
+	case <-ctx.Done():
+		require.FailNow(t, "timed out waiting for resumed event")
+	case event := <-resumed:
+		require.Equal(t, codersdk.PlatformEventTypeUserStatusChanged, event.Type)
+		require.Greater(t, event.ID, resumeAfter)
+	}
+}
@@ -77,7 +77,13 @@ func (am *annotatedMetric) asPrometheus() (prometheus.Metric, error) {
 	labelValues = append(labelValues, am.username, am.workspaceName, am.agentName)
 
 	for _, l := range am.Labels {
-		labels = append(labels, l.Name)
+		name := l.Name
+		// Agents may forward metrics scraped from arbitrary exporters, so
+		// follow Prometheus' convention for labels conflicting with ours.
+		if slices.Contains(agentMetricsLabels, name) {
+			name = "exported_" + name
+		}
+		labels = append(labels, name)
 		labelValues = append(labelValues, l.Value)
 	}
 
@@ -86,7 +92,7 @@ func (am *annotatedMetric) asPrometheus() (prometheus.Metric, error) {
 	if err != nil {
 		return nil, err
 	}
-	return prometheus.MustNewConstMetric(desc, valueType, am.Value, labelValues...), nil
+	return prometheus.NewConstMetric(desc, valueType, am.Value, labelValues...)
 }
 
 func NewMetricsAggregator(logger slog.Logger, registerer prometheus.Registerer, duration time.Duration) (*MetricsAggregator, error) {
@@ -175,7 +181,7 @@ func (ma *MetricsAggregator) Run(ctx context.Context) func() {
 				for _, m := range ma.queue {
 					promMetric, err := m.asPrometheus()
 					if err != nil {
-						ma.log.Error(ctx, "can't convert Prometheus value type", slog.F("name", m.Name), slog.F("type", m.Type), slog.F("value", m.Value), slog.Error(err))
+						ma.log.Error(ctx, "can't convert metric to Prometheus", slog.F("name", m.Name), slog.F("type", m.Type), slog.F("value", m.Value), slog.Error(err))
 						continue
 					}
 					output = append(output, promMetric)
@@ -52,6 +52,9 @@ func TestUpdateMetrics_MetricsDoNotExpire(t *testing.T) {
 			{Name: "hello", Value: "world"},
 		}},
 		{Name: "d_gauge_four", Type: agentsdk.AgentMetricTypeGauge, Value: 6},
+		{Name: "e_gauge_five", Type: agentsdk.AgentMetricTypeGauge, Value: 7, Labels: []agentsdk.AgentMetricLabel{
+			{Name: "username", Value: "exporter"},
+		}},
 	}
 
 	commonLabels := []agentsdk.AgentMetricLabel{
@@ -71,6 +74,12 @@ func TestUpdateMetrics_MetricsDoNotExpire(t *testing.T) {
 			{Name: "workspace_name", Value: testWorkspaceName},
 		}},
 		{Name: "d_gauge_four", Type: agentsdk.AgentMetricTypeGauge, Value: 6, Labels: commonLabels},
+		{Name: "e_gauge_five", Type: agentsdk.AgentMetricTypeGauge, Value: 7, Labels: []agentsdk.AgentMetricLabel{
+			{Name: "agent_name", Value: testAgentName},
+			{Name: "exported_username", Value: "exporter"},
+			{Name: "username", Value: testUsername},
+			{Name: "workspace_name", Value: testWorkspaceName},
+		}},
 	}
 
 	// when
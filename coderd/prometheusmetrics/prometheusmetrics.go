@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -18,13 +19,15 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/tailnet"
 )
 
 const (
-	agentNameLabel     = "agent_name"
-	usernameLabel      = "username"
-	workspaceNameLabel = "workspace_name"
+	agentNameLabel      = "agent_name"
+	usernameLabel       = "username"
+	workspaceNameLabel  = "workspace_name"
+	connectionTypeLabel = "connection_type"
 )
 
 // ActiveUsers tracks the number of users that have authenticated within the past hour.
@@ -141,6 +144,153 @@ func Workspaces(ctx context.Context, registerer prometheus.Registerer, db databa
 	}, nil
 }
 
+// ProvisionerJobQueue tracks the depth and wait times of the provisioner job
+// queue, labeled by provisioner and job tags. Operators scale external
+// provisioner daemons on it.
+func ProvisionerJobQueue(ctx context.Context, logger slog.Logger, registerer prometheus.Registerer, db database.Store, duration time.Duration) (func(), error) {
+	if duration == 0 {
+		duration = time.Minute
+	}
+
+	labels := []string{"provisioner", "tags"}
+	depthGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "provisioner_jobs",
+		Name:      "queue_depth",
+		Help:      "The number of provisioner jobs waiting for a provisioner daemon.",
+	}, labels)
+	err := registerer.Register(depthGauge)
+	if err != nil {
+		return nil, err
+	}
+	oldestAgeGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "provisioner_jobs",
+		Name:      "queue_oldest_age_seconds",
+		Help:      "How long the oldest pending provisioner job has been waiting.",
+	}, labels)
+	err = registerer.Register(oldestAgeGauge)
+	if err != nil {
+		return nil, err
+	}
+	ageGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "provisioner_jobs",
+		Name:      "queue_age_seconds",
+		Help:      "Percentiles of how long the pending provisioner jobs have been waiting.",
+	}, append(labels, "quantile"))
+	err = registerer.Register(ageGauge)
+	if err != nil {
+		return nil, err
+	}
+	waitGauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "provisioner_jobs",
+		Name:      "queue_wait_seconds_average",
+		Help:      "The average time provisioner jobs started in the last 15 minutes waited for a provisioner daemon.",
+	}, labels)
+	err = registerer.Register(waitGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	// nolint:gocritic // Prometheus must collect metrics for all provisioner jobs.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	done := make(chan struct{})
+
+	// Use time.Nanosecond to force an initial tick. It will be reset to the
+	// correct duration after executing once.
+	ticker := time.NewTicker(time.Nanosecond)
+	doTick := func() {
+		defer ticker.Reset(duration)
+
+		now := database.Now()
+		pending, err := db.GetPendingProvisionerJobs(ctx)
+		if err != nil {
+			logger.Error(ctx, "can't get pending provisioner jobs", slog.Error(err))
+			return
+		}
+		started, err := db.GetProvisionerJobsStartedAfter(ctx, now.Add(-provisionerdserver.QueueWaitWindow))
+		if err != nil {
+			logger.Error(ctx, "can't get started provisioner jobs", slog.Error(err))
+			return
+		}
+
+		type queue struct {
+			provisioner string
+			tags        string
+			pending     []database.ProvisionerJob
+			started     []database.ProvisionerJob
+		}
+		queues := map[string]*queue{}
+		queueOf := func(job database.ProvisionerJob) *queue {
+			tags := queueTagsLabel(job.Tags)
+			key := string(job.Provisioner) + " " + tags
+			q, ok := queues[key]
+			if !ok {
+				q = &queue{provisioner: string(job.Provisioner), tags: tags}
+				queues[key] = q
+			}
+			return q
+		}
+		for _, job := range pending {
+			q := queueOf(job)
+			q.pending = append(q.pending, job)
+		}
+		for _, job := range started {
+			q := queueOf(job)
+			q.started = append(q.started, job)
+		}
+
+		depthGauge.Reset()
+		oldestAgeGauge.Reset()
+		ageGauge.Reset()
+		waitGauge.Reset()
+		for _, q := range queues {
+			stats := provisionerdserver.ComputeQueueStats(now, q.pending, q.started)
+			depthGauge.WithLabelValues(q.provisioner, q.tags).Set(float64(stats.Depth))
+			oldestAgeGauge.WithLabelValues(q.provisioner, q.tags).Set(stats.OldestJobAge.Seconds())
+			ageGauge.WithLabelValues(q.provisioner, q.tags, "0.5").Set(stats.JobAgeP50.Seconds())
+			ageGauge.WithLabelValues(q.provisioner, q.tags, "0.9").Set(stats.JobAgeP90.Seconds())
+			ageGauge.WithLabelValues(q.provisioner, q.tags, "0.99").Set(stats.JobAgeP99.Seconds())
+			waitGauge.WithLabelValues(q.provisioner, q.tags).Set(stats.AverageWait.Seconds())
+		}
+	}
+
+	go func() {
+		defer close(done)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				doTick()
+			}
+		}
+	}()
+	return func() {
+		cancelFunc()
+		<-done
+	}, nil
+}
+
+// queueTagsLabel formats job tags as a label value, e.g.
+// "cloud=aws,scope=organization". The owner tag is left out to keep the
+// cardinality bounded, as every user-scoped job has a distinct owner.
+func queueTagsLabel(tags database.StringMap) string {
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		if key == provisionerdserver.TagOwner {
+			continue
+		}
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
 // Agents tracks the total number of workspaces with labels on status.
 func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Registerer, db database.Store, coordinator *atomic.Pointer[tailnet.Coordinator], derpMapFn func() *tailcfg.DERPMap, agentInactiveDisconnectTimeout, duration time.Duration) (func(), error) {
 	if duration == 0 {
@@ -191,6 +341,17 @@ func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Regis
 		return nil, err
 	}
 
+	agentsClockOffsetGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agents",
+		Name:      "clock_offset_seconds",
+		Help:      "Offset of the clock of connected agents from the clock of coderd in seconds. Positive values mean the agent clock is ahead.",
+	}, []string{agentNameLabel, usernameLabel, workspaceNameLabel}))
+	err = registerer.Register(agentsClockOffsetGauge)
+	if err != nil {
+		return nil, err
+	}
+
 	metricsCollectorAgents := prometheus.NewHistogram(prometheus.HistogramOpts{
 		Namespace: "coderd",
 		Subsystem: "prometheusmetrics",
@@ -274,6 +435,10 @@ func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Regis
 
 					agentsConnectionsGauge.WithLabelValues(VectorOperationSet, 1, agent.Name, user.Username, workspace.Name, string(connectionStatus.Status), string(agent.LifecycleState), tailnetNode)
 
+					if connectionStatus.Status == database.WorkspaceAgentStatusConnected {
+						agentsClockOffsetGauge.WithLabelValues(VectorOperationSet, float64(agent.ClockOffsetMS)/1000, agent.Name, user.Username, workspace.Name)
+					}
+
 					if node == nil {
 						logger.Debug(ctx, "can't read in-memory node for agent", slog.F("agent_id", agent.ID))
 					} else {
@@ -318,6 +483,7 @@ func Agents(ctx context.Context, logger slog.Logger, registerer prometheus.Regis
 			agentsConnectionsGauge.Commit()
 			agentsConnectionLatenciesGauge.Commit()
 			agentsAppsGauge.Commit()
+			agentsClockOffsetGauge.Commit()
 
 		done:
 			logger.Debug(ctx, "agent metrics collection is done")
@@ -370,6 +536,28 @@ func AgentStats(ctx context.Context, logger slog.Logger, registerer prometheus.R
 		return nil, err
 	}
 
+	agentStatsConnectionTxBytesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "connection_tx_bytes",
+		Help:      "Agent Tx bytes by connection type",
+	}, []string{agentNameLabel, usernameLabel, workspaceNameLabel, connectionTypeLabel}))
+	err = registerer.Register(agentStatsConnectionTxBytesGauge)
+	if err != nil {
+		return nil, err
+	}
+
+	agentStatsConnectionRxBytesGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agentstats",
+		Name:      "connection_rx_bytes",
+		Help:      "Agent Rx bytes by connection type",
+	}, []string{agentNameLabel, usernameLabel, workspaceNameLabel, connectionTypeLabel}))
+	err = registerer.Register(agentStatsConnectionRxBytesGauge)
+	if err != nil {
+		return nil, err
+	}
+
 	agentStatsConnectionCountGauge := NewCachedGaugeVec(prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "coderd",
 		Subsystem: "agentstats",
@@ -488,6 +676,21 @@ func AgentStats(ctx context.Context, logger slog.Logger, registerer prometheus.R
 				}
 			}
 
+			bandwidthStats, err := db.GetWorkspaceAgentBandwidthStatsAndLabels(ctx, createdAfter)
+			if err != nil {
+				logger.Error(ctx, "can't get agent bandwidth stats", slog.Error(err))
+			} else {
+				for _, bandwidthStat := range bandwidthStats {
+					agentStatsConnectionRxBytesGauge.WithLabelValues(VectorOperationAdd, float64(bandwidthStat.RxBytes), bandwidthStat.AgentName, bandwidthStat.Username, bandwidthStat.WorkspaceName, bandwidthStat.ConnectionType)
+					agentStatsConnectionTxBytesGauge.WithLabelValues(VectorOperationAdd, float64(bandwidthStat.TxBytes), bandwidthStat.AgentName, bandwidthStat.Username, bandwidthStat.WorkspaceName, bandwidthStat.ConnectionType)
+				}
+
+				if len(bandwidthStats) > 0 {
+					agentStatsConnectionRxBytesGauge.Commit()
+					agentStatsConnectionTxBytesGauge.Commit()
+				}
+			}
+
 			logger.Debug(ctx, "agent metrics collection is done", slog.F("len", len(stats)))
 			timer.ObserveDuration()
 
@@ -16,6 +16,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"tailscale.com/tailcfg"
@@ -28,6 +29,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/provisioner/echo"
@@ -258,6 +260,61 @@ func TestWorkspaces(t *testing.T) {
 	}
 }
 
+func TestProvisionerJobQueue(t *testing.T) {
+	t.Parallel()
+
+	db := dbfake.New()
+	for i := 0; i < 2; i++ {
+		dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+			CreatedAt: database.Now().Add(-time.Minute),
+			Tags: database.StringMap{
+				"cloud": "aws",
+				// Owners are left out of the labels.
+				provisionerdserver.TagOwner: uuid.NewString(),
+			},
+		})
+	}
+	dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+		CreatedAt: database.Now().Add(-2 * time.Minute),
+		StartedAt: sql.NullTime{Time: database.Now().Add(-time.Minute), Valid: true},
+	})
+
+	registry := prometheus.NewRegistry()
+	closeFunc, err := prometheusmetrics.ProvisionerJobQueue(context.Background(), slogtest.Make(t, nil), registry, db, time.Millisecond)
+	require.NoError(t, err)
+	t.Cleanup(closeFunc)
+
+	require.Eventually(t, func() bool {
+		metrics, err := registry.Gather()
+		assert.NoError(t, err)
+		var depth, wait float64
+		for _, metric := range metrics {
+			switch metric.GetName() {
+			case "coderd_provisioner_jobs_queue_depth":
+				for _, m := range metric.Metric {
+					if labelValue(m.Label, "tags") == "cloud=aws" {
+						depth = m.Gauge.GetValue()
+					}
+				}
+			case "coderd_provisioner_jobs_queue_wait_seconds_average":
+				for _, m := range metric.Metric {
+					wait += m.Gauge.GetValue()
+				}
+			}
+		}
+		return depth == 2 && wait >= 59
+	}, testutil.WaitShort, testutil.IntervalFast)
+}
+
+func labelValue(labels []*dto.LabelPair, name string) string {
+	for _, label := range labels {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
 func TestAgents(t *testing.T) {
 	t.Parallel()
 
@@ -415,6 +472,9 @@ func TestAgentStats(t *testing.T) {
 			SessionCountVSCode: 3 + i, SessionCountJetBrains: 4 + i, SessionCountReconnectingPTY: 5 + i, SessionCountSSH: 6 + i,
 			ConnectionCount: 7 + i, ConnectionMedianLatencyMS: 8000,
 			ConnectionsByProto: map[string]int64{"TCP": 1},
+			ConnectionBandwidth: []agentsdk.ConnectionBandwidth{
+				{Type: agentsdk.ConnectionTypeSSH, Peer: "fd7a:115c:a1e0::1", RxBytes: 1 + i, TxBytes: 2 + i},
+			},
 		})
 		require.NoError(t, err)
 
@@ -478,6 +538,12 @@ func TestAgentStats(t *testing.T) {
 					// username:workspace:agent:metric = value
 					collected[m.Label[1].GetValue()+":"+m.Label[2].GetValue()+":"+m.Label[0].GetValue()+":"+metric.GetName()] = int(m.Gauge.GetValue())
 				}
+			case "coderd_agentstats_connection_rx_bytes",
+				"coderd_agentstats_connection_tx_bytes":
+				for _, m := range metric.Metric {
+					// username:workspace:agent:connection_type:metric = value
+					collected[m.Label[2].GetValue()+":"+m.Label[3].GetValue()+":"+m.Label[0].GetValue()+":"+m.Label[1].GetValue()+":"+metric.GetName()] = int(m.Gauge.GetValue())
+				}
 			default:
 				require.FailNowf(t, "unexpected metric collected", "metric: %s", metric.GetName())
 			}
@@ -0,0 +1,135 @@
+package provisionerdserver
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/provisionerd/proto"
+)
+
+const (
+	// EventJobPosted is the pubsub channel notified when a provisioner job
+	// is created or requeued.
+	EventJobPosted = "provisioner_job_posted"
+
+	// EventWorkspaceBuildFinished is the pubsub channel notified with the
+	// workspace ID when a workspace build job finishes, so builds queued for
+	// the workspace can start.
+	EventWorkspaceBuildFinished = "workspace_build_finished"
+
+	// MaxAcquireJobWait is the longest a provisioner daemon can wait for a
+	// job in a single AcquireJobWithWait call.
+	MaxAcquireJobWait = time.Minute
+
+	// acquireJobWaitPollInterval is how often a waiting provisioner daemon
+	// checks the database for jobs without being notified. This catches jobs
+	// whose notification was lost.
+	acquireJobWaitPollInterval = 10 * time.Second
+)
+
+// JobPosting is the payload published on EventJobPosted.
+type JobPosting struct {
+	Provisioner database.ProvisionerType `json:"provisioner"`
+	Tags        map[string]string        `json:"tags"`
+}
+
+// PostJob notifies waiting provisioner daemons that the job can be acquired.
+// It must be called once the transaction inserting or requeueing the job is
+// committed, otherwise the daemons may not see the job yet.
+func PostJob(ps pubsub.Pubsub, job database.ProvisionerJob) error {
+	msg, err := json.Marshal(JobPosting{
+		Provisioner: job.Provisioner,
+		Tags:        job.Tags,
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal job posting: %w", err)
+	}
+	err = ps.Publish(EventJobPosted, msg)
+	if err != nil {
+		return xerrors.Errorf("publish job posting: %w", err)
+	}
+	return nil
+}
+
+// PublishWorkspaceBuildFinished notifies EventWorkspaceBuildFinished that a
+// build job of the workspace finished.
+func PublishWorkspaceBuildFinished(ps pubsub.Pubsub, workspaceID uuid.UUID) error {
+	err := ps.Publish(EventWorkspaceBuildFinished, []byte(workspaceID.String()))
+	if err != nil {
+		return xerrors.Errorf("publish workspace build finished: %w", err)
+	}
+	return nil
+}
+
+// AcquireJobWithWait locks a job like AcquireJob, but waits for a job to be
+// posted when none is available instead of returning immediately. This lets
+// idle provisioner daemons wait on coderd rather than polling the database.
+func (server *Server) AcquireJobWithWait(ctx context.Context, req *proto.AcquireJobWithWaitRequest) (*proto.AcquiredJob, error) {
+	//nolint:gocritic // Provisionerd has specific authz rules.
+	ctx = dbauthz.AsProvisionerd(ctx)
+
+	wait := time.Duration(req.WaitMs) * time.Millisecond
+	if wait > MaxAcquireJobWait {
+		wait = MaxAcquireJobWait
+	}
+
+	daemonTags := map[string]string{}
+	if len(server.Tags) > 0 {
+		err := json.Unmarshal(server.Tags, &daemonTags)
+		if err != nil {
+			return nil, xerrors.Errorf("unmarshal daemon tags: %w", err)
+		}
+	}
+
+	// Subscribe before the first attempt so jobs posted in between are not
+	// missed. Only jobs this daemon can acquire wake it up, so postings are
+	// routed to the daemons whose tags satisfy the job.
+	posted := make(chan struct{}, 1)
+	cancelSub, err := server.Pubsub.Subscribe(EventJobPosted, func(_ context.Context, message []byte) {
+		var posting JobPosting
+		err := json.Unmarshal(message, &posting)
+		if err == nil && (!slices.Contains(server.Provisioners, posting.Provisioner) ||
+			!TagsSatisfy(posting.Tags, daemonTags)) {
+			return
+		}
+		select {
+		case posted <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("subscribe to job postings: %w", err)
+	}
+	defer cancelSub()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	ticker := time.NewTicker(acquireJobWaitPollInterval)
+	defer ticker.Stop()
+	for {
+		job, err := server.acquireJob(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if job.JobId != "" {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return job, nil
+		case <-posted:
+		case <-ticker.C:
+		}
+	}
+}
@@ -33,9 +33,13 @@ import (
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/gitauth"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/parameteroptions"
+	"github.com/coder/coder/v2/coderd/platformevents"
 	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/secretmanager"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/coderd/workspacehooks"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner"
 	"github.com/coder/coder/v2/provisionerd/proto"
@@ -64,6 +68,12 @@ type Server struct {
 	TemplateScheduleStore       *atomic.Pointer[schedule.TemplateScheduleStore]
 	UserQuietHoursScheduleStore *atomic.Pointer[schedule.UserQuietHoursScheduleStore]
 	DeploymentValues            *codersdk.DeploymentValues
+	// Draining is set when the replica is draining. No jobs are acquired
+	// while it is.
+	Draining *atomic.Bool
+	// SecretManager resolves secret:// references in template variables
+	// when they're sent to provisioners.
+	SecretManager *secretmanager.Manager
 
 	AcquireJobDebounce time.Duration
 	OIDCConfig         httpmw.OAuth2Config
@@ -95,25 +105,67 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 		return &proto.AcquiredJob{}, nil
 	}
 	lastAcquireMutex.RUnlock()
+
+	job, err := server.acquireJob(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if job.JobId == "" {
+		lastAcquireMutex.Lock()
+		lastAcquire = database.Now()
+		lastAcquireMutex.Unlock()
+	}
+	return job, nil
+}
+
+// acquireJob locks a job in the database and converts it for the provisioner
+// daemon. An empty job is returned if none is available.
+func (server *Server) acquireJob(ctx context.Context) (*proto.AcquiredJob, error) {
+	// Jobs are left for other replicas while this one drains.
+	if server.Draining != nil && server.Draining.Load() {
+		return &proto.AcquiredJob{}, nil
+	}
 	// This marks the job as locked in the database.
-	job, err := server.Database.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
-		StartedAt: sql.NullTime{
-			Time:  database.Now(),
-			Valid: true,
-		},
-		WorkerID: uuid.NullUUID{
-			UUID:  server.ID,
-			Valid: true,
-		},
-		Types: server.Provisioners,
-		Tags:  server.Tags,
-	})
+	var job database.ProvisionerJob
+	err := server.Database.InTx(func(db database.Store) error {
+		var err error
+		job, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+			StartedAt: sql.NullTime{
+				Time:  database.Now(),
+				Valid: true,
+			},
+			WorkerID: uuid.NullUUID{
+				UUID:  server.ID,
+				Valid: true,
+			},
+			Types: server.Provisioners,
+			Tags:  server.Tags,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Concurrent acquires can each see room under a concurrency limit.
+		// Serialize them per organization, which covers the limits of its
+		// templates too, and check the limit again now that the jobs started
+		// by the others are visible. The job is left queued if it no longer
+		// fits.
+		err = db.AcquireLock(ctx, database.GenLockID("provisioner-job-concurrency-"+job.OrganizationID.String()))
+		if err != nil {
+			return xerrors.Errorf("acquire concurrency lock: %w", err)
+		}
+		limitedBy, err := db.GetProvisionerJobConcurrencyLimit(ctx, job.ID)
+		if err != nil {
+			return xerrors.Errorf("get concurrency limit: %w", err)
+		}
+		if limitedBy != "" {
+			return sql.ErrNoRows
+		}
+		return nil
+	}, nil)
 	if errors.Is(err, sql.ErrNoRows) {
 		// The provisioner daemon assumes no jobs are available if
 		// an empty struct is returned.
-		lastAcquireMutex.Lock()
-		lastAcquire = database.Now()
-		lastAcquireMutex.Unlock()
 		return &proto.AcquiredJob{}, nil
 	}
 	if err != nil {
@@ -185,6 +237,10 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
 			return nil, failJob(fmt.Sprintf("get template version variables: %s", err))
 		}
+		variableValues, err := server.resolveVariableValues(ctx, asVariableValues(templateVariables))
+		if err != nil {
+			return nil, failJob(err.Error())
+		}
 		template, err := server.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("get template: %s", err))
@@ -193,10 +249,18 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("get owner: %s", err))
 		}
+		// Pre hooks run before the provisioner changes any resources, so
+		// external systems can veto or prepare for the build.
+		preEvent, _ := workspacehooks.Events(codersdk.WorkspaceTransition(workspaceBuild.Transition))
+		err = server.workspaceHooks().Run(ctx, workspaceHookPayload(preEvent, workspaceBuild, workspace, owner, template))
+		if err != nil {
+			return nil, failJob(err.Error())
+		}
 		err = server.Pubsub.Publish(codersdk.WorkspaceNotifyChannel(workspace.ID), []byte{})
 		if err != nil {
 			return nil, failJob(fmt.Sprintf("publish workspace update: %s", err))
 		}
+		server.platformEvents().WorkspaceBuild(ctx, codersdk.PlatformEventTypeWorkspaceBuildStarted, workspaceBuild, "")
 
 		var workspaceOwnerOIDCAccessToken string
 		if server.OIDCConfig != nil {
@@ -272,30 +336,40 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 			})
 		}
 
+		var cloneSourceWorkspaceID string
+		if input.CloneSourceWorkspaceID.Valid {
+			cloneSourceWorkspaceID = input.CloneSourceWorkspaceID.UUID.String()
+		}
+
 		protoJob.Type = &proto.AcquiredJob_WorkspaceBuild_{
 			WorkspaceBuild: &proto.AcquiredJob_WorkspaceBuild{
 				WorkspaceBuildId:    workspaceBuild.ID.String(),
 				WorkspaceName:       workspace.Name,
 				State:               workspaceBuild.ProvisionerState,
 				RichParameterValues: convertRichParameterValues(workspaceBuildParameters),
-				VariableValues:      asVariableValues(templateVariables),
+				VariableValues:      variableValues,
 				GitAuthProviders:    gitAuthProviders,
 				Metadata: &sdkproto.Provision_Metadata{
-					CoderUrl:                      server.AccessURL.String(),
-					WorkspaceTransition:           transition,
-					WorkspaceName:                 workspace.Name,
-					WorkspaceOwner:                owner.Username,
-					WorkspaceOwnerEmail:           owner.Email,
-					WorkspaceOwnerOidcAccessToken: workspaceOwnerOIDCAccessToken,
-					WorkspaceId:                   workspace.ID.String(),
-					WorkspaceOwnerId:              owner.ID.String(),
-					TemplateName:                  template.Name,
-					TemplateVersion:               templateVersion.Name,
-					WorkspaceOwnerSessionToken:    sessionToken,
+					CoderUrl:                       server.AccessURL.String(),
+					WorkspaceTransition:            transition,
+					WorkspaceName:                  workspace.Name,
+					WorkspaceOwner:                 owner.Username,
+					WorkspaceOwnerEmail:            owner.Email,
+					WorkspaceOwnerOidcAccessToken:  workspaceOwnerOIDCAccessToken,
+					WorkspaceId:                    workspace.ID.String(),
+					WorkspaceOwnerId:               owner.ID.String(),
+					TemplateName:                   template.Name,
+					TemplateVersion:                templateVersion.Name,
+					WorkspaceOwnerSessionToken:     sessionToken,
+					RequireAgentBinaryVerification: template.RequireAgentBinaryVerification,
+					AgentBinarySigningCertificate:  server.DeploymentValues.AgentBinarySigningCertificate.String(),
 				},
-				LogLevel: input.LogLevel,
+				LogLevel:                 input.LogLevel,
+				CloneSourceWorkspaceId:   cloneSourceWorkspaceID,
+				CloneSourceWorkspaceName: input.CloneSourceWorkspaceName,
 			},
 		}
+		setTemplateLimits(protoJob, template)
 	case database.ProvisionerJobTypeTemplateVersionDryRun:
 		var input TemplateVersionDryRunJob
 		err = json.Unmarshal(job.Input, &input)
@@ -311,17 +385,28 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
 			return nil, failJob(fmt.Sprintf("get template version variables: %s", err))
 		}
+		variableValues, err := server.resolveVariableValues(ctx, asVariableValues(templateVariables))
+		if err != nil {
+			return nil, failJob(err.Error())
+		}
 
 		protoJob.Type = &proto.AcquiredJob_TemplateDryRun_{
 			TemplateDryRun: &proto.AcquiredJob_TemplateDryRun{
 				RichParameterValues: convertRichParameterValues(input.RichParameterValues),
-				VariableValues:      asVariableValues(templateVariables),
+				VariableValues:      variableValues,
 				Metadata: &sdkproto.Provision_Metadata{
 					CoderUrl:      server.AccessURL.String(),
 					WorkspaceName: input.WorkspaceName,
 				},
 			},
 		}
+		if templateVersion.TemplateID.Valid {
+			template, err := server.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get template: %s", err))
+			}
+			setTemplateLimits(protoJob, template)
+		}
 	case database.ProvisionerJobTypeTemplateVersionImport:
 		var input TemplateVersionImportJob
 		err = json.Unmarshal(job.Input, &input)
@@ -342,6 +427,21 @@ func (server *Server) AcquireJob(ctx context.Context, _ *proto.Empty) (*proto.Ac
 				},
 			},
 		}
+
+		// Versions pushed to an existing template are subject to its limits.
+		if input.TemplateVersionID != uuid.Nil {
+			templateVersion, err := server.Database.GetTemplateVersionByID(ctx, input.TemplateVersionID)
+			if err != nil {
+				return nil, failJob(fmt.Sprintf("get template version: %s", err))
+			}
+			if templateVersion.TemplateID.Valid {
+				template, err := server.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
+				if err != nil {
+					return nil, failJob(fmt.Sprintf("get template: %s", err))
+				}
+				setTemplateLimits(protoJob, template)
+			}
+		}
 	}
 	switch job.StorageMethod {
 	case database.ProvisionerStorageMethodFile:
@@ -522,6 +622,27 @@ func (server *Server) UpdateJob(ctx context.Context, request *proto.UpdateJobReq
 		server.Logger.Debug(ctx, "published job logs", slog.F("job_id", parsedID))
 	}
 
+	for _, hook := range request.BuildHookResults {
+		phase := database.BuildHookPhase(hook.Phase)
+		if !phase.Valid() {
+			return nil, xerrors.Errorf("invalid build hook phase %q", hook.Phase)
+		}
+		_, err := server.Database.InsertProvisionerJobBuildHook(ctx, database.InsertProvisionerJobBuildHookParams{
+			ID:          uuid.New(),
+			JobID:       job.ID,
+			Name:        hook.Name,
+			Phase:       phase,
+			StartedAt:   time.UnixMilli(hook.StartedAt),
+			CompletedAt: time.UnixMilli(hook.CompletedAt),
+			Attempts:    hook.Attempts,
+			ExitCode:    hook.ExitCode,
+			Error:       hook.Error,
+		})
+		if err != nil {
+			return nil, xerrors.Errorf("insert build hook result: %w", err)
+		}
+	}
+
 	if len(request.Readme) > 0 {
 		err := server.Database.UpdateTemplateVersionDescriptionByJobID(ctx, database.UpdateTemplateVersionDescriptionByJobIDParams{
 			JobID:     job.ID,
@@ -581,6 +702,12 @@ func (server *Server) UpdateJob(ctx context.Context, request *proto.UpdateJobReq
 		if len(variablesWithMissingValues) > 0 {
 			return nil, xerrors.Errorf("required template variables need values: %s", strings.Join(variablesWithMissingValues, ", "))
 		}
+		// References to secrets are stored as they are, and only resolved
+		// for the provisioner.
+		variableValues, err = server.resolveVariableValues(ctx, variableValues)
+		if err != nil {
+			return nil, err
+		}
 
 		return &proto.UpdateJobResponse{
 			Canceled:       job.CanceledAt.Valid,
@@ -626,13 +753,26 @@ func (server *Server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*p
 		String: failJob.ErrorCode,
 		Valid:  failJob.ErrorCode != "",
 	}
+	switch failJob.CancelOutcome {
+	case proto.CancelOutcome_GRACEFUL:
+		job.CancelOutcome = database.NullProvisionerJobCancelOutcome{
+			ProvisionerJobCancelOutcome: database.ProvisionerJobCancelOutcomeGraceful,
+			Valid:                       true,
+		}
+	case proto.CancelOutcome_FORCED:
+		job.CancelOutcome = database.NullProvisionerJobCancelOutcome{
+			ProvisionerJobCancelOutcome: database.ProvisionerJobCancelOutcomeForced,
+			Valid:                       true,
+		}
+	}
 
 	err = server.Database.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
-		ID:          jobID,
-		CompletedAt: job.CompletedAt,
-		UpdatedAt:   database.Now(),
-		Error:       job.Error,
-		ErrorCode:   job.ErrorCode,
+		ID:            jobID,
+		CompletedAt:   job.CompletedAt,
+		UpdatedAt:     database.Now(),
+		Error:         job.Error,
+		ErrorCode:     job.ErrorCode,
+		CancelOutcome: job.CancelOutcome,
 	})
 	if err != nil {
 		return nil, xerrors.Errorf("update provisioner job: %w", err)
@@ -679,6 +819,16 @@ func (server *Server) FailJob(ctx context.Context, failJob *proto.FailedJob) (*p
 		if err != nil {
 			return nil, xerrors.Errorf("update workspace: %w", err)
 		}
+		err = PublishWorkspaceBuildFinished(server.Pubsub, build.WorkspaceID)
+		if err != nil {
+			server.Logger.Warn(ctx, "failed to publish finished workspace build", slog.Error(err))
+		}
+
+		eventType := codersdk.PlatformEventTypeWorkspaceBuildFailed
+		if job.CanceledAt.Valid {
+			eventType = codersdk.PlatformEventTypeWorkspaceBuildCanceled
+		}
+		server.platformEvents().WorkspaceBuild(ctx, eventType, build, failJob.Error)
 	case *proto.FailedJob_TemplateImport_:
 	}
 
@@ -806,6 +956,10 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 			if err != nil {
 				return nil, xerrors.Errorf("marshal parameter options: %w", err)
 			}
+			dependencies, err := json.Marshal(richParameter.Dependencies)
+			if err != nil {
+				return nil, xerrors.Errorf("marshal parameter dependencies: %w", err)
+			}
 
 			var validationMin, validationMax sql.NullInt32
 			if richParameter.ValidationMin != nil {
@@ -839,6 +993,8 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 				Required:            richParameter.Required,
 				DisplayOrder:        richParameter.Order,
 				Ephemeral:           richParameter.Ephemeral,
+				Dependencies:        dependencies,
+				OptionsURL:          richParameter.OptionsUrl,
 			})
 			if err != nil {
 				return nil, xerrors.Errorf("insert parameter: %w", err)
@@ -847,6 +1003,22 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 
 		var completedError sql.NullString
 
+		err = validateRichParameterDependencies(jobType.TemplateImport.RichParameters)
+		if err != nil {
+			completedError = sql.NullString{
+				String: fmt.Sprintf("invalid parameter dependencies: %s", err),
+				Valid:  true,
+			}
+		}
+
+		err = validateRichParameterOptionsURLs(jobType.TemplateImport.RichParameters)
+		if err != nil {
+			completedError = sql.NullString{
+				String: fmt.Sprintf("invalid parameter options: %s", err),
+				Valid:  true,
+			}
+		}
+
 		for _, gitAuthProvider := range jobType.TemplateImport.GitAuthProviders {
 			contains := false
 			for _, configuredProvider := range server.GitAuthConfigs {
@@ -1066,6 +1238,18 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 		if err != nil {
 			return nil, xerrors.Errorf("update workspace: %w", err)
 		}
+		err = PublishWorkspaceBuildFinished(server.Pubsub, workspaceBuild.WorkspaceID)
+		if err != nil {
+			server.Logger.Warn(ctx, "failed to publish finished workspace build", slog.Error(err))
+		}
+
+		server.platformEvents().WorkspaceBuild(ctx, codersdk.PlatformEventTypeWorkspaceBuildSucceeded, workspaceBuild, "")
+		if workspaceBuild.Transition == database.WorkspaceTransitionDelete && getWorkspaceError == nil {
+			server.platformEvents().Workspace(ctx, codersdk.PlatformEventTypeWorkspaceDeleted, workspace)
+		}
+		if getWorkspaceError == nil {
+			server.runPostWorkspaceHooks(ctx, workspaceBuild, workspace)
+		}
 	case *proto.CompletedJob_TemplateDryRun_:
 		for _, resource := range jobType.TemplateDryRun.Resources {
 			server.Logger.Info(ctx, "inserting template dry-run job resource",
@@ -1117,6 +1301,56 @@ func (server *Server) CompleteJob(ctx context.Context, completed *proto.Complete
 	return &proto.Empty{}, nil
 }
 
+func (server *Server) platformEvents() *platformevents.Publisher {
+	return platformevents.New(server.Logger, server.Database, server.Pubsub)
+}
+
+func (server *Server) workspaceHooks() *workspacehooks.Runner {
+	return workspacehooks.New(server.Logger, nil, server.DeploymentValues.WorkspaceHooks.Value)
+}
+
+// runPostWorkspaceHooks calls the post hooks of a successful build in the
+// background, so provisioners don't wait for them.
+func (server *Server) runPostWorkspaceHooks(ctx context.Context, build database.WorkspaceBuild, workspace database.Workspace) {
+	_, postEvent := workspacehooks.Events(codersdk.WorkspaceTransition(build.Transition))
+	if !slices.ContainsFunc(server.DeploymentValues.WorkspaceHooks.Value, func(hook codersdk.WorkspaceHookConfig) bool {
+		return slices.Contains(hook.Events, postEvent)
+	}) {
+		return
+	}
+	owner, err := server.Database.GetUserByID(ctx, workspace.OwnerID)
+	if err != nil {
+		server.Logger.Error(ctx, "get workspace owner for post hooks", slog.F("workspace_id", workspace.ID), slog.Error(err))
+		return
+	}
+	template, err := server.Database.GetTemplateByID(ctx, workspace.TemplateID)
+	if err != nil {
+		server.Logger.Error(ctx, "get template for post hooks", slog.F("workspace_id", workspace.ID), slog.Error(err))
+		return
+	}
+	payload := workspaceHookPayload(postEvent, build, workspace, owner, template)
+	go func() {
+		// The hooks outlive the request of the provisioner. Failures of post
+		// hooks are only logged, so the error is always nil.
+		_ = server.workspaceHooks().Run(context.Background(), payload)
+	}()
+}
+
+func workspaceHookPayload(event codersdk.WorkspaceHookEvent, build database.WorkspaceBuild, workspace database.Workspace, owner database.User, template database.Template) codersdk.WorkspaceHookPayload {
+	return codersdk.WorkspaceHookPayload{
+		Event:              event,
+		WorkspaceID:        workspace.ID,
+		WorkspaceName:      workspace.Name,
+		WorkspaceOwnerID:   owner.ID,
+		WorkspaceOwnerName: owner.Username,
+		TemplateID:         template.ID,
+		TemplateName:       template.Name,
+		WorkspaceBuildID:   build.ID,
+		BuildNumber:        build.BuildNumber,
+		Transition:         codersdk.WorkspaceTransition(build.Transition),
+	}
+}
+
 func (server *Server) startTrace(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
 	return server.Tracer.Start(ctx, name, append(opts, trace.WithAttributes(
 		semconv.ServiceNameKey.String("coderd.provisionerd"),
@@ -1138,6 +1372,7 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 			String: protoResource.InstanceType,
 			Valid:  protoResource.InstanceType != "",
 		},
+		DisplayOrder: int32(protoResource.Order),
 	})
 	if err != nil {
 		return xerrors.Errorf("insert provisioner job resource %q: %w", protoResource.Name, err)
@@ -1212,6 +1447,7 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 				Valid:  prAgent.ShutdownScript != "",
 			},
 			ShutdownScriptTimeoutSeconds: prAgent.GetShutdownScriptTimeoutSeconds(),
+			DisplayOrder:                 int32(prAgent.GetOrder()),
 		})
 		if err != nil {
 			return xerrors.Errorf("insert agent: %w", err)
@@ -1284,6 +1520,8 @@ func InsertWorkspaceResource(ctx context.Context, db database.Store, jobID uuid.
 				HealthcheckInterval:  app.Healthcheck.Interval,
 				HealthcheckThreshold: app.Healthcheck.Threshold,
 				Health:               health,
+				DisplayOrder:         int32(app.Order),
+				Hidden:               app.Hidden,
 			})
 			if err != nil {
 				return xerrors.Errorf("insert app: %w", err)
@@ -1456,6 +1694,50 @@ func convertRichParameterValues(workspaceBuildParameters []database.WorkspaceBui
 	return protoParameters
 }
 
+// validateRichParameterDependencies checks the dependencies between the rich
+// parameters of a template import, so that workspace builds can rely on them.
+func validateRichParameterDependencies(richParameters []*sdkproto.RichParameter) error {
+	params := make([]codersdk.TemplateVersionParameter, 0, len(richParameters))
+	for _, richParameter := range richParameters {
+		param := codersdk.TemplateVersionParameter{
+			Name:           richParameter.Name,
+			DynamicOptions: richParameter.OptionsUrl != "",
+		}
+		for _, option := range richParameter.Options {
+			param.Options = append(param.Options, codersdk.TemplateVersionParameterOption{
+				Value: option.Value,
+			})
+		}
+		for _, dependency := range richParameter.Dependencies {
+			param.Dependencies = append(param.Dependencies, codersdk.TemplateVersionParameterDependency{
+				Parameter: dependency.Parameter,
+				Values:    dependency.Values,
+				Options:   dependency.Options,
+			})
+		}
+		params = append(params, param)
+	}
+	return codersdk.ValidateParameterDependencies(params)
+}
+
+// validateRichParameterOptionsURLs checks the endpoints of the rich parameters
+// whose options are fetched dynamically.
+func validateRichParameterOptionsURLs(richParameters []*sdkproto.RichParameter) error {
+	for _, richParameter := range richParameters {
+		if richParameter.OptionsUrl == "" {
+			continue
+		}
+		if len(richParameter.Options) > 0 {
+			return xerrors.Errorf("parameter %q can't have both static options and an options URL", richParameter.Name)
+		}
+		err := parameteroptions.ValidateURL(richParameter.OptionsUrl)
+		if err != nil {
+			return xerrors.Errorf("parameter %q: %w", richParameter.Name, err)
+		}
+	}
+	return nil
+}
+
 func convertVariableValues(variableValues []codersdk.VariableValue) []*sdkproto.VariableValue {
 	protoVariableValues := make([]*sdkproto.VariableValue, len(variableValues))
 	for i, variableValue := range variableValues {
@@ -1468,6 +1750,14 @@ func convertVariableValues(variableValues []codersdk.VariableValue) []*sdkproto.
 	return protoVariableValues
 }
 
+// setTemplateLimits sets the build duration and provisioner resource limits
+// of the template on the job.
+func setTemplateLimits(job *proto.AcquiredJob, template database.Template) {
+	job.MaxDurationMs = time.Duration(template.MaxBuildDuration).Milliseconds()
+	job.ProvisionerMemoryLimitBytes = template.ProvisionerMemoryLimit
+	job.ProvisionerCpuLimitMs = time.Duration(template.ProvisionerCPULimit).Milliseconds()
+}
+
 func convertWorkspaceTransition(transition database.WorkspaceTransition) (sdkproto.WorkspaceTransition, error) {
 	switch transition {
 	case database.WorkspaceTransitionStart:
@@ -1504,6 +1794,11 @@ type WorkspaceProvisionJob struct {
 	WorkspaceBuildID uuid.UUID `json:"workspace_build_id"`
 	DryRun           bool      `json:"dry_run"`
 	LogLevel         string    `json:"log_level,omitempty"`
+	// CloneSourceWorkspaceID and CloneSourceWorkspaceName are set for the
+	// first build of a cloned workspace that copies the data of the workspace
+	// it was cloned from.
+	CloneSourceWorkspaceID   uuid.NullUUID `json:"clone_source_workspace_id"`
+	CloneSourceWorkspaceName string        `json:"clone_source_workspace_name,omitempty"`
 }
 
 // TemplateVersionDryRunJob is the payload for the "template_version_dry_run" job type.
@@ -1532,6 +1827,29 @@ func asVariableValues(templateVariables []database.TemplateVersionVariable) []*s
 	return apiVariableValues
 }
 
+// resolveVariableValues replaces secret:// references in the values of template
+// variables with the secrets they refer to. Resolved values are marked
+// sensitive, so provisioners don't log them.
+func (server *Server) resolveVariableValues(ctx context.Context, values []*sdkproto.VariableValue) ([]*sdkproto.VariableValue, error) {
+	resolved := make([]*sdkproto.VariableValue, 0, len(values))
+	for _, value := range values {
+		if !secretmanager.IsReference(value.Value) {
+			resolved = append(resolved, value)
+			continue
+		}
+		secret, err := server.SecretManager.Resolve(ctx, value.Value)
+		if err != nil {
+			return nil, xerrors.Errorf("resolve template variable %q: %w", value.Name, err)
+		}
+		resolved = append(resolved, &sdkproto.VariableValue{
+			Name:      value.Name,
+			Value:     secret,
+			Sensitive: true,
+		})
+	}
+	return resolved, nil
+}
+
 func redactTemplateVariable(templateVariable *sdkproto.TemplateVariable) *sdkproto.TemplateVariable {
 	if templateVariable == nil {
 		return nil
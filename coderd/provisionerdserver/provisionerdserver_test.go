@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2"
+	"golang.org/x/sync/errgroup"
 
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/cli/clibase"
@@ -22,10 +23,12 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbfake"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
 	"github.com/coder/coder/v2/coderd/gitauth"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/schedule"
+	"github.com/coder/coder/v2/coderd/secretmanager"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionerd/proto"
@@ -98,6 +101,23 @@ func TestAcquireJob(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, &proto.AcquiredJob{}, job)
 	})
+	t.Run("Draining", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		srv.Draining = &atomic.Bool{}
+		srv.Draining.Store(true)
+		_, err := srv.Database.InsertProvisionerJob(context.Background(), database.InsertProvisionerJobParams{
+			ID:            uuid.New(),
+			InitiatorID:   uuid.New(),
+			Provisioner:   database.ProvisionerTypeEcho,
+			StorageMethod: database.ProvisionerStorageMethodFile,
+			Type:          database.ProvisionerJobTypeTemplateVersionDryRun,
+		})
+		require.NoError(t, err)
+		job, err := srv.AcquireJob(context.Background(), nil)
+		require.NoError(t, err)
+		require.Equal(t, &proto.AcquiredJob{}, job)
+	})
 	t.Run("InitiatorNotFound", func(t *testing.T) {
 		t.Parallel()
 		srv := setup(t, false)
@@ -141,6 +161,15 @@ func TestAcquireJob(t *testing.T) {
 			Name:        "template",
 			Provisioner: database.ProvisionerTypeEcho,
 		})
+		err := srv.Database.UpdateTemplateMetaByID(ctx, database.UpdateTemplateMetaByIDParams{
+			ID:                     template.ID,
+			UpdatedAt:              database.Now(),
+			Name:                   template.Name,
+			MaxBuildDuration:       int64(time.Hour),
+			ProvisionerMemoryLimit: 1 << 30,
+			ProvisionerCPULimit:    int64(10 * time.Minute),
+		})
+		require.NoError(t, err)
 		file := dbgen.File(t, srv.Database, database.File{CreatedBy: user.ID})
 		versionFile := dbgen.File(t, srv.Database, database.File{CreatedBy: user.ID})
 		version := dbgen.TemplateVersion(t, srv.Database, database.TemplateVersion{
@@ -150,7 +179,7 @@ func TestAcquireJob(t *testing.T) {
 			},
 			JobID: uuid.New(),
 		})
-		err := srv.Database.UpdateTemplateVersionGitAuthProvidersByJobID(ctx, database.UpdateTemplateVersionGitAuthProvidersByJobIDParams{
+		err = srv.Database.UpdateTemplateVersionGitAuthProvidersByJobID(ctx, database.UpdateTemplateVersionGitAuthProvidersByJobIDParams{
 			JobID:            version.JobID,
 			GitAuthProviders: []string{gitAuthProvider},
 			UpdatedAt:        database.Now(),
@@ -285,6 +314,9 @@ func TestAcquireJob(t *testing.T) {
 		require.NoError(t, err)
 
 		require.JSONEq(t, string(want), string(got))
+		require.Equal(t, time.Hour.Milliseconds(), job.MaxDurationMs)
+		require.EqualValues(t, 1<<30, job.ProvisionerMemoryLimitBytes)
+		require.Equal(t, (10 * time.Minute).Milliseconds(), job.ProvisionerCpuLimitMs)
 
 		// Assert that we delete the session token whenever
 		// a stop is issued.
@@ -368,6 +400,60 @@ func TestAcquireJob(t *testing.T) {
 		require.NoError(t, err)
 		require.JSONEq(t, string(want), string(got))
 	})
+	t.Run("SecretVariables", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		srv.SecretManager = secretmanager.New(map[string]secretmanager.Provider{
+			"static": staticSecrets{"db": "hunter2"},
+		})
+		ctx := context.Background()
+
+		user := dbgen.User(t, srv.Database, database.User{})
+		version := dbgen.TemplateVersion(t, srv.Database, database.TemplateVersion{})
+		_ = dbgen.TemplateVersionVariable(t, srv.Database, database.TemplateVersionVariable{
+			TemplateVersionID: version.ID,
+			Name:              "db_password",
+			Value:             "secret://static/db",
+		})
+		_ = dbgen.TemplateVersionVariable(t, srv.Database, database.TemplateVersionVariable{
+			TemplateVersionID: version.ID,
+			Name:              "region",
+			Value:             "eu",
+		})
+		file := dbgen.File(t, srv.Database, database.File{CreatedBy: user.ID})
+		_ = dbgen.ProvisionerJob(t, srv.Database, database.ProvisionerJob{
+			InitiatorID:   user.ID,
+			Provisioner:   database.ProvisionerTypeEcho,
+			StorageMethod: database.ProvisionerStorageMethodFile,
+			FileID:        file.ID,
+			Type:          database.ProvisionerJobTypeTemplateVersionDryRun,
+			Input: must(json.Marshal(provisionerdserver.TemplateVersionDryRunJob{
+				TemplateVersionID: version.ID,
+				WorkspaceName:     "testing",
+			})),
+		})
+
+		job, err := srv.AcquireJob(ctx, nil)
+		require.NoError(t, err)
+		values := job.Type.(*proto.AcquiredJob_TemplateDryRun_).TemplateDryRun.VariableValues
+		require.Len(t, values, 2)
+		for _, value := range values {
+			switch value.Name {
+			case "db_password":
+				require.Equal(t, "hunter2", value.Value)
+				require.True(t, value.Sensitive)
+			case "region":
+				require.Equal(t, "eu", value.Value)
+			}
+		}
+
+		// The reference is stored, not the secret.
+		variables, err := srv.Database.GetTemplateVersionVariables(ctx, version.ID)
+		require.NoError(t, err)
+		for _, variable := range variables {
+			require.NotEqual(t, "hunter2", variable.Value)
+		}
+	})
 	t.Run("TemplateVersionImport", func(t *testing.T) {
 		t.Parallel()
 		srv := setup(t, false)
@@ -399,6 +485,54 @@ func TestAcquireJob(t *testing.T) {
 		require.NoError(t, err)
 		require.JSONEq(t, string(want), string(got))
 	})
+	t.Run("ConcurrencyLimit", func(t *testing.T) {
+		t.Parallel()
+		db, ps := dbtestutil.NewDB(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		org := dbgen.Organization(t, db, database.Organization{})
+		_, err := db.UpdateOrganizationProvisionerLimits(ctx, database.UpdateOrganizationProvisionerLimitsParams{
+			ID:                           org.ID,
+			UpdatedAt:                    database.Now(),
+			MaxConcurrentProvisionerJobs: 1,
+		})
+		require.NoError(t, err)
+		user := dbgen.User(t, db, database.User{})
+		file := dbgen.File(t, db, database.File{CreatedBy: user.ID})
+		const daemons = 5
+		for i := 0; i < daemons; i++ {
+			_ = dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+				OrganizationID: org.ID,
+				FileID:         file.ID,
+				InitiatorID:    user.ID,
+				Provisioner:    database.ProvisionerTypeEcho,
+				StorageMethod:  database.ProvisionerStorageMethodFile,
+				Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			})
+		}
+
+		// Every daemon sees room under the limit at once, but only one may
+		// start a job.
+		var acquired atomic.Int64
+		var eg errgroup.Group
+		for i := 0; i < daemons; i++ {
+			srv := setup(t, false)
+			srv.Database = db
+			srv.Pubsub = ps
+			eg.Go(func() error {
+				job, err := srv.AcquireJob(ctx, nil)
+				if err != nil {
+					return err
+				}
+				if job.JobId != "" {
+					acquired.Add(1)
+				}
+				return nil
+			})
+		}
+		require.NoError(t, eg.Wait())
+		require.EqualValues(t, 1, acquired.Load())
+	})
 	t.Run("TemplateVersionImportWithUserVariable", func(t *testing.T) {
 		t.Parallel()
 		srv := setup(t, false)
@@ -444,6 +578,64 @@ func TestAcquireJob(t *testing.T) {
 	})
 }
 
+func TestAcquireJobWithWait(t *testing.T) {
+	t.Parallel()
+	t.Run("NoJobs", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		job, err := srv.AcquireJobWithWait(context.Background(), &proto.AcquireJobWithWaitRequest{
+			WaitMs: 50,
+		})
+		require.NoError(t, err)
+		require.Equal(t, &proto.AcquiredJob{}, job)
+	})
+	t.Run("PostedJob", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		user := dbgen.User(t, srv.Database, database.User{})
+		file := dbgen.File(t, srv.Database, database.File{CreatedBy: user.ID})
+
+		type result struct {
+			job *proto.AcquiredJob
+			err error
+		}
+		resultChan := make(chan result, 1)
+		go func() {
+			job, err := srv.AcquireJobWithWait(ctx, &proto.AcquireJobWithWaitRequest{
+				WaitMs: provisionerdserver.MaxAcquireJobWait.Milliseconds(),
+			})
+			resultChan <- result{job: job, err: err}
+		}()
+
+		// Keep posting until the waiting call returns, since the job may be
+		// posted before the call subscribes.
+		job := dbgen.ProvisionerJob(t, srv.Database, database.ProvisionerJob{
+			FileID:        file.ID,
+			InitiatorID:   user.ID,
+			Provisioner:   database.ProvisionerTypeEcho,
+			StorageMethod: database.ProvisionerStorageMethodFile,
+			Type:          database.ProvisionerJobTypeTemplateVersionImport,
+		})
+		ticker := time.NewTicker(testutil.IntervalFast)
+		defer ticker.Stop()
+		for {
+			require.NoError(t, provisionerdserver.PostJob(srv.Pubsub, job))
+			select {
+			case <-ctx.Done():
+				t.Fatal("timed out waiting for job")
+			case res := <-resultChan:
+				require.NoError(t, res.err)
+				require.Equal(t, job.ID.String(), res.job.JobId)
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+}
+
 func TestUpdateJob(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -575,6 +767,45 @@ func TestUpdateJob(t *testing.T) {
 		require.Equal(t, "# hello world", version.Readme)
 	})
 
+	t.Run("BuildHookResults", func(t *testing.T) {
+		t.Parallel()
+		srv := setup(t, false)
+		job := setupJob(t, srv)
+		startedAt := time.Now().Add(-time.Minute).UnixMilli()
+		_, err := srv.UpdateJob(ctx, &proto.UpdateJobRequest{
+			JobId: job.String(),
+			BuildHookResults: []*proto.BuildHookResult{{
+				Name:        "notify",
+				Phase:       "post",
+				StartedAt:   startedAt,
+				CompletedAt: startedAt + 1000,
+				Attempts:    3,
+				ExitCode:    1,
+				Error:       "exited with code 1",
+			}},
+		})
+		require.NoError(t, err)
+
+		hooks, err := srv.Database.GetProvisionerJobBuildHooksByJobID(ctx, job)
+		require.NoError(t, err)
+		require.Len(t, hooks, 1)
+		require.Equal(t, "notify", hooks[0].Name)
+		require.Equal(t, database.BuildHookPhasePost, hooks[0].Phase)
+		require.Equal(t, startedAt, hooks[0].StartedAt.UnixMilli())
+		require.EqualValues(t, 3, hooks[0].Attempts)
+		require.EqualValues(t, 1, hooks[0].ExitCode)
+		require.Equal(t, "exited with code 1", hooks[0].Error)
+
+		_, err = srv.UpdateJob(ctx, &proto.UpdateJobRequest{
+			JobId: job.String(),
+			BuildHookResults: []*proto.BuildHookResult{{
+				Name:  "notify",
+				Phase: "during",
+			}},
+		})
+		require.ErrorContains(t, err, "invalid build hook phase")
+	})
+
 	t.Run("TemplateVariables", func(t *testing.T) {
 		t.Parallel()
 
@@ -749,7 +980,8 @@ func TestFailJob(t *testing.T) {
 		ignoreLogErrors := true
 		srv := setup(t, ignoreLogErrors)
 		workspace, err := srv.Database.InsertWorkspace(ctx, database.InsertWorkspaceParams{
-			ID: uuid.New(),
+			ID:               uuid.New(),
+			AutomaticUpdates: database.AutomaticUpdatesNever,
 		})
 		require.NoError(t, err)
 		buildID := uuid.New()
@@ -1545,6 +1777,16 @@ func setup(t *testing.T, ignoreLogErrors bool) *provisionerdserver.Server {
 	}
 }
 
+type staticSecrets map[string]string
+
+func (s staticSecrets) Secret(_ context.Context, path, _ string) (string, error) {
+	value, ok := s[path]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+	return value, nil
+}
+
 func must[T any](value T, err error) T {
 	if err != nil {
 		panic(err)
@@ -1,15 +1,42 @@
 package provisionerdserver
 
-import "github.com/google/uuid"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+)
 
 const (
 	TagScope = "scope"
 	TagOwner = "owner"
+	// TagRegion pins jobs to provisioner daemons running in the region of a
+	// workspace proxy. Unlike other tags, it must match exactly: regional
+	// daemons only acquire jobs for their region.
+	TagRegion = "region"
 
 	ScopeUser         = "user"
 	ScopeOrganization = "organization"
+
+	// TagValueAny as the value of a job tag is satisfied by provisioner
+	// daemons with the tag set to any value.
+	TagValueAny = "*"
+	// TagValueSeparator separates alternative values of a job tag, e.g.
+	// "us-east|us-west". Daemons with the tag set to one of them satisfy it.
+	TagValueSeparator = "|"
 )
 
+// ErrUnknownRegion is returned by ResolveRegionTag if the region tag doesn't
+// refer to a workspace proxy.
+var ErrUnknownRegion = xerrors.New("unknown region")
+
 // MutateTags adjusts the "owner" tag dependent on the "scope".
 // If the scope is "user", the "owner" is changed to the user ID.
 // This is for user-scoped provisioner daemons, where users should
@@ -31,3 +58,68 @@ func MutateTags(userID uuid.UUID, tags map[string]string) map[string]string {
 	}
 	return tags
 }
+
+// ResolveRegionTag replaces the "region" tag, which may be the name or ID of
+// a workspace proxy, with the proxy ID. Jobs stay pinned to the region if the
+// proxy is renamed.
+func ResolveRegionTag(ctx context.Context, db database.Store, tags map[string]string) error {
+	region, ok := tags[TagRegion]
+	if !ok {
+		return nil
+	}
+	// Workspace proxies are visible to every user as regions, and
+	// provisioner daemons may authenticate with a PSK.
+	//nolint:gocritic // System access is required to look up the proxy.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	var (
+		proxy database.WorkspaceProxy
+		err   error
+	)
+	if id, parseErr := uuid.Parse(region); parseErr == nil {
+		proxy, err = db.GetWorkspaceProxyByID(ctx, id)
+	} else {
+		proxy, err = db.GetWorkspaceProxyByName(ctx, region)
+	}
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && proxy.Deleted) {
+		return xerrors.Errorf("workspace proxy %q: %w", region, ErrUnknownRegion)
+	}
+	if err != nil {
+		return xerrors.Errorf("get workspace proxy %q: %w", region, err)
+	}
+	tags[TagRegion] = proxy.ID.String()
+	return nil
+}
+
+// TagsSatisfy returns true if a provisioner daemon with the given tags may
+// acquire a job with the given tags. Every job tag must be set on the daemon,
+// to a value matching the job tag's expression. The region tag must match
+// exactly in both directions. AcquireProvisionerJob implements the same
+// rules in SQL.
+func TagsSatisfy(jobTags, daemonTags map[string]string) bool {
+	for key, expr := range jobTags {
+		value, ok := daemonTags[key]
+		if !ok {
+			return false
+		}
+		if expr == TagValueAny {
+			continue
+		}
+		if !slices.Contains(strings.Split(expr, TagValueSeparator), value) {
+			return false
+		}
+	}
+	return jobTags[TagRegion] == daemonTags[TagRegion]
+}
+
+// ValidateDaemonTags returns an error if the tags of a provisioner daemon
+// contain match expressions. Expressions are only meaningful on jobs, which
+// state the requirements daemons must satisfy.
+func ValidateDaemonTags(tags map[string]string) error {
+	for key, value := range tags {
+		if value == TagValueAny || strings.Contains(value, TagValueSeparator) {
+			return xerrors.Errorf("tag %q has the match expression %q, daemon tags must be plain values", key, value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package provisionerdserver_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+)
+
+func TestTagsSatisfy(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name       string
+		jobTags    map[string]string
+		daemonTags map[string]string
+		satisfied  bool
+	}{
+		{
+			name:       "Untagged",
+			jobTags:    map[string]string{},
+			daemonTags: map[string]string{"cloud": "aws"},
+			satisfied:  true,
+		},
+		{
+			name:       "Exact",
+			jobTags:    map[string]string{"cloud": "aws"},
+			daemonTags: map[string]string{"cloud": "aws"},
+			satisfied:  true,
+		},
+		{
+			name:       "Mismatch",
+			jobTags:    map[string]string{"cloud": "aws"},
+			daemonTags: map[string]string{"cloud": "gcp"},
+			satisfied:  false,
+		},
+		{
+			name:       "Missing",
+			jobTags:    map[string]string{"cloud": "aws"},
+			daemonTags: map[string]string{},
+			satisfied:  false,
+		},
+		{
+			name:       "Any",
+			jobTags:    map[string]string{"gpu": provisionerdserver.TagValueAny},
+			daemonTags: map[string]string{"gpu": "a100"},
+			satisfied:  true,
+		},
+		{
+			name:       "AnyMissing",
+			jobTags:    map[string]string{"gpu": provisionerdserver.TagValueAny},
+			daemonTags: map[string]string{},
+			satisfied:  false,
+		},
+		{
+			name:       "Alternatives",
+			jobTags:    map[string]string{"cloud": "aws|azure"},
+			daemonTags: map[string]string{"cloud": "azure"},
+			satisfied:  true,
+		},
+		{
+			name:       "AlternativesMismatch",
+			jobTags:    map[string]string{"cloud": "aws|azure"},
+			daemonTags: map[string]string{"cloud": "gcp"},
+			satisfied:  false,
+		},
+		{
+			name:       "RegionalDaemon",
+			jobTags:    map[string]string{"cloud": "aws"},
+			daemonTags: map[string]string{"cloud": "aws", provisionerdserver.TagRegion: "sydney"},
+			satisfied:  false,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tc.satisfied, provisionerdserver.TagsSatisfy(tc.jobTags, tc.daemonTags))
+		})
+	}
+}
+
+func TestValidateDaemonTags(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, provisionerdserver.ValidateDaemonTags(map[string]string{"cloud": "aws"}))
+	require.Error(t, provisionerdserver.ValidateDaemonTags(map[string]string{"cloud": "aws|gcp"}))
+	require.Error(t, provisionerdserver.ValidateDaemonTags(map[string]string{"gpu": provisionerdserver.TagValueAny}))
+}
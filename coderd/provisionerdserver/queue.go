@@ -0,0 +1,112 @@
+package provisionerdserver
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+// QueueWaitWindow is how far back started jobs are considered when averaging
+// the time jobs waited for a provisioner daemon. It's short enough for the
+// average to follow changes in load.
+const QueueWaitWindow = 15 * time.Minute
+
+// QueueFilter selects the provisioner jobs to include in queue stats. Zero
+// fields match every job.
+type QueueFilter struct {
+	OrganizationID uuid.UUID
+	Provisioner    database.ProvisionerType
+	// Tags are the tags of a provisioner daemon. Only jobs a daemon with
+	// these tags may acquire are matched.
+	Tags map[string]string
+}
+
+// Matches returns true if the job is selected by the filter.
+func (f QueueFilter) Matches(job database.ProvisionerJob) bool {
+	if f.OrganizationID != uuid.Nil && job.OrganizationID != f.OrganizationID {
+		return false
+	}
+	if f.Provisioner != "" && job.Provisioner != f.Provisioner {
+		return false
+	}
+	if f.Tags != nil && !TagsSatisfy(job.Tags, f.Tags) {
+		return false
+	}
+	return true
+}
+
+// QueueStats summarizes the provisioner jobs waiting to be acquired.
+type QueueStats struct {
+	// Depth is the number of pending jobs.
+	Depth int
+	// OldestJobAge is how long the oldest pending job has been waiting.
+	OldestJobAge time.Duration
+	// JobAgeP50, JobAgeP90 and JobAgeP99 are percentiles of how long the
+	// pending jobs have been waiting.
+	JobAgeP50 time.Duration
+	JobAgeP90 time.Duration
+	JobAgeP99 time.Duration
+	// StartedJobs is the number of jobs the average wait is computed over.
+	StartedJobs int
+	// AverageWait is the average time started jobs waited to be acquired.
+	AverageWait time.Duration
+}
+
+// ComputeQueueStats computes the queue stats from the pending jobs and the
+// jobs started recently, e.g. within the QueueWaitWindow.
+func ComputeQueueStats(now time.Time, pending, started []database.ProvisionerJob) QueueStats {
+	ages := make([]time.Duration, 0, len(pending))
+	for _, job := range pending {
+		age := now.Sub(job.CreatedAt)
+		if age < 0 {
+			age = 0
+		}
+		ages = append(ages, age)
+	}
+	sort.Slice(ages, func(i, j int) bool {
+		return ages[i] < ages[j]
+	})
+
+	stats := QueueStats{
+		Depth:     len(ages),
+		JobAgeP50: percentile(ages, 0.5),
+		JobAgeP90: percentile(ages, 0.9),
+		JobAgeP99: percentile(ages, 0.99),
+	}
+	if len(ages) > 0 {
+		stats.OldestJobAge = ages[len(ages)-1]
+	}
+
+	var totalWait time.Duration
+	for _, job := range started {
+		if !job.StartedAt.Valid {
+			continue
+		}
+		wait := job.StartedAt.Time.Sub(job.CreatedAt)
+		if wait < 0 {
+			wait = 0
+		}
+		totalWait += wait
+		stats.StartedJobs++
+	}
+	if stats.StartedJobs > 0 {
+		stats.AverageWait = totalWait / time.Duration(stats.StartedJobs)
+	}
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,85 @@
+package provisionerdserver_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+)
+
+func TestComputeQueueStats(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Empty", func(t *testing.T) {
+		t.Parallel()
+		require.Equal(t, provisionerdserver.QueueStats{}, provisionerdserver.ComputeQueueStats(now, nil, nil))
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		t.Parallel()
+
+		pending := make([]database.ProvisionerJob, 0, 10)
+		for i := 1; i <= 10; i++ {
+			pending = append(pending, database.ProvisionerJob{
+				CreatedAt: now.Add(-time.Duration(i) * time.Minute),
+			})
+		}
+		started := []database.ProvisionerJob{{
+			CreatedAt: now.Add(-10 * time.Minute),
+			StartedAt: sql.NullTime{Time: now.Add(-9 * time.Minute), Valid: true},
+		}, {
+			CreatedAt: now.Add(-10 * time.Minute),
+			StartedAt: sql.NullTime{Time: now.Add(-7 * time.Minute), Valid: true},
+		}}
+
+		stats := provisionerdserver.ComputeQueueStats(now, pending, started)
+		require.Equal(t, provisionerdserver.QueueStats{
+			Depth:        10,
+			OldestJobAge: 10 * time.Minute,
+			JobAgeP50:    5 * time.Minute,
+			JobAgeP90:    9 * time.Minute,
+			JobAgeP99:    10 * time.Minute,
+			StartedJobs:  2,
+			AverageWait:  2 * time.Minute,
+		}, stats)
+	})
+}
+
+func TestQueueFilter(t *testing.T) {
+	t.Parallel()
+
+	orgID := uuid.New()
+	job := database.ProvisionerJob{
+		OrganizationID: orgID,
+		Provisioner:    database.ProvisionerTypeTerraform,
+		Tags: database.StringMap{
+			provisionerdserver.TagScope: provisionerdserver.ScopeOrganization,
+			"cloud":                     "aws|gcp",
+		},
+	}
+
+	require.True(t, provisionerdserver.QueueFilter{}.Matches(job))
+	require.True(t, provisionerdserver.QueueFilter{
+		OrganizationID: orgID,
+		Provisioner:    database.ProvisionerTypeTerraform,
+		Tags: map[string]string{
+			provisionerdserver.TagScope: provisionerdserver.ScopeOrganization,
+			"cloud":                     "gcp",
+		},
+	}.Matches(job))
+	require.False(t, provisionerdserver.QueueFilter{OrganizationID: uuid.New()}.Matches(job))
+	require.False(t, provisionerdserver.QueueFilter{Provisioner: database.ProvisionerTypeEcho}.Matches(job))
+	require.False(t, provisionerdserver.QueueFilter{
+		Tags: map[string]string{
+			provisionerdserver.TagScope: provisionerdserver.ScopeOrganization,
+			"cloud":                     "azure",
+		},
+	}.Matches(job))
+}
@@ -172,6 +172,7 @@ func (api *API) provisionerJobResources(rw http.ResponseWriter, r *http.Request,
 	sort.Slice(apiResources, func(i, j int) bool {
 		return apiResources[i].Name < apiResources[j].Name
 	})
+	sortWorkspaceResources(apiResources)
 
 	httpapi.Write(ctx, rw, http.StatusOK, apiResources)
 }
@@ -198,14 +199,15 @@ func convertProvisionerJobLog(provisionerJobLog database.ProvisionerJobLog) code
 func convertProvisionerJob(pj database.GetProvisionerJobsByIDsWithQueuePositionRow) codersdk.ProvisionerJob {
 	provisionerJob := pj.ProvisionerJob
 	job := codersdk.ProvisionerJob{
-		ID:            provisionerJob.ID,
-		CreatedAt:     provisionerJob.CreatedAt,
-		Error:         provisionerJob.Error.String,
-		ErrorCode:     codersdk.JobErrorCode(provisionerJob.ErrorCode.String),
-		FileID:        provisionerJob.FileID,
-		Tags:          provisionerJob.Tags,
-		QueuePosition: int(pj.QueuePosition),
-		QueueSize:     int(pj.QueueSize),
+		ID:             provisionerJob.ID,
+		CreatedAt:      provisionerJob.CreatedAt,
+		Error:          provisionerJob.Error.String,
+		ErrorCode:      codersdk.JobErrorCode(provisionerJob.ErrorCode.String),
+		FileID:         provisionerJob.FileID,
+		Tags:           provisionerJob.Tags,
+		QueuePosition:  int(pj.QueuePosition),
+		QueueSize:      int(pj.QueueSize),
+		QueueLimitedBy: codersdk.ProvisionerJobQueueLimit(pj.QueueLimitedBy),
 	}
 	// Applying values optional to the struct.
 	if provisionerJob.StartedAt.Valid {
@@ -220,6 +222,9 @@ func convertProvisionerJob(pj database.GetProvisionerJobsByIDsWithQueuePositionR
 	if provisionerJob.WorkerID.Valid {
 		job.WorkerID = &provisionerJob.WorkerID.UUID
 	}
+	if provisionerJob.CancelOutcome.Valid {
+		job.CancelOutcome = codersdk.ProvisionerJobCancelOutcome(provisionerJob.CancelOutcome.ProvisionerJobCancelOutcome)
+	}
 	job.Status = db2sdk.ProvisionerJobStatus(provisionerJob)
 
 	return job
@@ -0,0 +1,72 @@
+package coderd
+
+import (
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Update web terminal session limit override by user
+// @ID update-web-terminal-session-limit-override-by-user
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.UpdateUserPTYSessionOverrideRequest true "Update web terminal session limit override request"
+// @Success 204
+// @Router /users/{user}/pty-session-override [put]
+func (api *API) putUserPTYSessionOverride(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	if !api.Authorize(r, rbac.ActionUpdate, user) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateUserPTYSessionOverrideRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	_, err := api.Database.UpsertUserPTYSessionOverride(ctx, database.UpsertUserPTYSessionOverrideParams{
+		UserID:         user.ID,
+		MaxPTYSessions: int32(req.MaxPTYSessions),
+		CreatedAt:      database.Now(),
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Delete web terminal session limit override by user
+// @ID delete-web-terminal-session-limit-override-by-user
+// @Security CoderSessionToken
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Success 204
+// @Router /users/{user}/pty-session-override [delete]
+func (api *API) deleteUserPTYSessionOverride(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	if !api.Authorize(r, rbac.ActionUpdate, user) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	err := api.Database.DeleteUserPTYSessionOverride(ctx, user.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,104 @@
+package coderd_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestPTYSessionLimits(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.MaxPTYSessionsPerUser = 1
+	client := coderdtest.New(t, &coderdtest.Options{
+		DeploymentValues:         dv,
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	defer func() {
+		_ = agentCloser.Close()
+	}()
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	openPTY := func(reconnect uuid.UUID) error {
+		conn, err := client.WorkspaceAgentReconnectingPTY(ctx, codersdk.WorkspaceAgentReconnectingPTYOpts{
+			AgentID:   agentID,
+			Reconnect: reconnect,
+			Width:     80,
+			Height:    24,
+		})
+		if err == nil {
+			t.Cleanup(func() {
+				_ = conn.Close()
+			})
+		}
+		return err
+	}
+
+	first := uuid.New()
+	require.NoError(t, openPTY(first))
+
+	// Attaching to the same session again doesn't count as another one.
+	require.NoError(t, openPTY(first))
+
+	err := openPTY(uuid.New())
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode())
+	require.Contains(t, apiErr.Message, "limit of 1 concurrent terminal sessions")
+
+	// Members can't override their own limit.
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	err = memberClient.UpdateUserPTYSessionOverride(ctx, codersdk.Me, codersdk.UpdateUserPTYSessionOverrideRequest{
+		MaxPTYSessions: 0,
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	err = client.UpdateUserPTYSessionOverride(ctx, codersdk.Me, codersdk.UpdateUserPTYSessionOverrideRequest{
+		MaxPTYSessions: 2,
+	})
+	require.NoError(t, err)
+	require.NoError(t, openPTY(uuid.New()))
+
+	err = client.DeleteUserPTYSessionOverride(ctx, codersdk.Me)
+	require.NoError(t, err)
+	err = openPTY(uuid.New())
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode())
+}
@@ -32,11 +32,16 @@ const (
 	ActionRead   Action = "read"
 	ActionUpdate Action = "update"
 	ActionDelete Action = "delete"
+
+	// ActionPushVersion allows pushing and promoting versions of a template.
+	ActionPushVersion Action = "push_version"
+	// ActionManageACL allows changing who can access a template.
+	ActionManageACL Action = "manage_acl"
 )
 
 // AllActions is a helper function to return all the possible actions types.
 func AllActions() []Action {
-	return []Action{ActionCreate, ActionRead, ActionUpdate, ActionDelete}
+	return []Action{ActionCreate, ActionRead, ActionUpdate, ActionDelete, ActionPushVersion, ActionManageACL}
 }
 
 type AuthCall struct {
@@ -141,6 +146,22 @@ type PreparedAuthorized interface {
 	CompileToSQL(ctx context.Context, cfg regosql.ConvertConfig) (string, error)
 }
 
+// PostFilteredAuthorized is implemented by prepared authorizers whose SQL
+// filter can allow more than Authorize does, e.g. because some decisions are
+// made outside of rego. Rows returned by queries using the SQL filter must
+// still be authorized one at a time.
+type PostFilteredAuthorized interface {
+	PreparedAuthorized
+	RequiresPostFilter() bool
+}
+
+// RequiresPostFilter returns whether rows filtered in SQL by the prepared
+// authorizer must still be authorized one at a time.
+func RequiresPostFilter(prepared PreparedAuthorized) bool {
+	p, ok := prepared.(PostFilteredAuthorized)
+	return ok && p.RequiresPostFilter()
+}
+
 // Filter takes in a list of objects, and will filter the list removing all
 // the elements the subject does not have permission for. All objects must be
 // of the same type.
@@ -969,6 +969,55 @@ func TestAuthorizeScope(t *testing.T) {
 		},
 	)
 
+	ownerID := uuid.New()
+	otherWorkspaceID := uuid.New()
+	user = Subject{
+		ID: ownerID.String(),
+		Roles: Roles{
+			must(RoleByName(RoleMember())),
+			must(RoleByName(RoleOrgMember(defOrg))),
+		},
+		Scope: WorkspaceAgentScope(workspaceID, ownerID),
+	}
+
+	testAuthorize(t, "User_WorkspaceAgentScope", user,
+		// Other workspaces of the same owner cannot be accessed.
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []Action{ActionCreate, ActionRead, ActionUpdate, ActionDelete}
+			c.allow = false
+			return c
+		}, []authTestCase{
+			{resource: ResourceWorkspace.WithID(otherWorkspaceID).InOrg(defOrg).WithOwner(user.ID)},
+			{resource: ResourceWorkspace.WithID(otherWorkspaceID).WithOwner(user.ID)},
+			{resource: ResourceWorkspace.InOrg(defOrg).WithOwner(user.ID)},
+			{resource: ResourceWorkspace.WithOwner(user.ID)},
+			{resource: ResourceWorkspaceExecution.WithID(otherWorkspaceID).InOrg(defOrg).WithOwner(user.ID)},
+			{resource: ResourceWorkspaceApplicationConnect.WithID(otherWorkspaceID).InOrg(defOrg).WithOwner(user.ID)},
+		}),
+		// Only the resource types needed by the agent are allowed, even on
+		// the agent's own workspace.
+		cases(func(c authTestCase) authTestCase {
+			c.actions = []Action{ActionCreate, ActionRead, ActionUpdate, ActionDelete}
+			c.allow = false
+			return c
+		}, []authTestCase{
+			{resource: ResourceWorkspaceExecution.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID)},
+			{resource: ResourceWorkspaceApplicationConnect.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID)},
+			{resource: ResourceTemplate.WithID(workspaceID).InOrg(defOrg)},
+			{resource: ResourceAPIKey.WithID(ownerID).WithOwner(user.ID)},
+		}),
+		// Allowed by scope:
+		[]authTestCase{
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionRead, ActionUpdate}, allow: true},
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner(user.ID), actions: []Action{ActionCreate, ActionDelete}, allow: false},
+			{resource: ResourceUserObject(ownerID), actions: []Action{ActionRead}, allow: true},
+			{resource: ResourceUserObject(ownerID), actions: []Action{ActionUpdate, ActionDelete}, allow: false},
+			{resource: ResourceUserData.WithID(ownerID).WithOwner(user.ID), actions: []Action{ActionRead, ActionUpdate}, allow: true},
+			// The scope allows the workspace, but the owner cannot read it.
+			{resource: ResourceWorkspace.WithID(workspaceID).InOrg(defOrg).WithOwner("not-me"), actions: []Action{ActionRead}, allow: false},
+		},
+	)
+
 	// This scope can only create workspaces
 	user = Subject{
 		ID: "me",
@@ -1082,6 +1131,14 @@ func testAuthorize(t *testing.T, name string, subject Subject, sets ...[]authTes
 						assert.Error(t, authError, "expected unauthorized")
 					}
 
+					// Explain ignores scopes, so it only has to agree with the
+					// authorizer when the scope allows everything.
+					if must(subject.Scope.Expand()).Name() == must(ExpandScope(ScopeAll)).Name() {
+						explanation, err := Explain(subject, a, c.resource)
+						require.NoError(t, err, "explain")
+						assert.Equal(t, authError == nil, explanation.Allowed(), "explanation disagrees with the authorizer")
+					}
+
 					prepared, err := authorizer.Prepare(ctx, subject, a, c.resource.Type)
 					require.NoError(t, err, "make prepared authorizer")
 
@@ -0,0 +1,175 @@
+package rbac
+
+import (
+	"sort"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/util/slice"
+)
+
+// ExplanationSource is where a permission that matches an authorize call
+// comes from.
+type ExplanationSource string
+
+const (
+	// ExplanationSourceSite is a site wide permission of a role.
+	ExplanationSourceSite ExplanationSource = "site"
+	// ExplanationSourceOrg is a permission of a role in the organization of
+	// the object.
+	ExplanationSourceOrg ExplanationSource = "org"
+	// ExplanationSourceOwner is a permission of a role that applies because the
+	// subject owns the object.
+	ExplanationSourceOwner ExplanationSource = "owner"
+	// ExplanationSourceUserACL is an entry for the subject in the user ACL of
+	// the object.
+	ExplanationSourceUserACL ExplanationSource = "user_acl"
+	// ExplanationSourceGroupACL is an entry for a group of the subject in the
+	// group ACL of the object.
+	ExplanationSourceGroupACL ExplanationSource = "group_acl"
+)
+
+// ExplanationEntry is a role permission or an ACL entry that matches the
+// action and the object of an authorize call.
+type ExplanationEntry struct {
+	Source ExplanationSource
+	// Role is the name of the role the permission belongs to. It is empty for
+	// ACL entries.
+	Role string
+	// Group is the ID of the group the ACL entry belongs to. The group of all
+	// users of an organization shares the ID of the organization.
+	Group string
+	// Allow is false for negated permissions, which deny the action.
+	Allow bool
+}
+
+// Explanation lists everything that grants or denies an action on an object
+// to a subject.
+type Explanation struct {
+	// OrgMember is true if the object has no organization, or if the subject
+	// is a member of it. Owner permissions and group ACL entries only apply to
+	// members.
+	OrgMember bool
+	Entries   []ExplanationEntry
+}
+
+// Explain mirrors the role and ACL rules of policy.rego to report which
+// permissions of the subject match the action on the object. Scopes are not
+// taken into account. It is meant for debugging, the Authorizer remains the
+// source of truth for every authorize decision.
+func Explain(subject Subject, action Action, object Object) (Explanation, error) {
+	roles, err := subject.Roles.Expand()
+	if err != nil {
+		return Explanation{}, xerrors.Errorf("expand roles: %w", err)
+	}
+
+	matches := func(perm Permission) bool {
+		return (perm.Action == action || perm.Action == WildcardSymbol) &&
+			(perm.ResourceType == object.Type || perm.ResourceType == WildcardSymbol)
+	}
+
+	orgMember := object.OrgID == ""
+	for _, role := range roles {
+		if _, ok := role.Org[object.OrgID]; ok && object.OrgID != "" {
+			orgMember = true
+		}
+	}
+
+	var explanation Explanation
+	explanation.OrgMember = orgMember
+	for _, role := range roles {
+		for _, perm := range role.Site {
+			if matches(perm) {
+				explanation.Entries = append(explanation.Entries, ExplanationEntry{
+					Source: ExplanationSourceSite,
+					Role:   role.Name,
+					Allow:  !perm.Negate,
+				})
+			}
+		}
+	}
+	if object.OrgID != "" {
+		for _, role := range roles {
+			for _, perm := range role.Org[object.OrgID] {
+				if matches(perm) {
+					explanation.Entries = append(explanation.Entries, ExplanationEntry{
+						Source: ExplanationSourceOrg,
+						Role:   role.Name,
+						Allow:  !perm.Negate,
+					})
+				}
+			}
+		}
+	}
+	if object.Owner != "" && object.Owner == subject.ID {
+		for _, role := range roles {
+			for _, perm := range role.User {
+				if matches(perm) {
+					explanation.Entries = append(explanation.Entries, ExplanationEntry{
+						Source: ExplanationSourceOwner,
+						Role:   role.Name,
+						Allow:  !perm.Negate,
+					})
+				}
+			}
+		}
+	}
+
+	aclMatches := func(actions []Action) bool {
+		return slice.Contains(actions, action) || slice.Contains(actions, WildcardSymbol)
+	}
+	if aclMatches(object.ACLUserList[subject.ID]) {
+		explanation.Entries = append(explanation.Entries, ExplanationEntry{
+			Source: ExplanationSourceUserACL,
+			Allow:  true,
+		})
+	}
+	if object.OrgID != "" && orgMember {
+		groups := make([]string, 0, len(subject.Groups)+1)
+		groups = append(groups, subject.Groups...)
+		if !slice.Contains(groups, object.OrgID) {
+			groups = append(groups, object.OrgID)
+		}
+		sort.Strings(groups)
+		for _, group := range groups {
+			if aclMatches(object.ACLGroupList[group]) {
+				explanation.Entries = append(explanation.Entries, ExplanationEntry{
+					Source: ExplanationSourceGroupACL,
+					Group:  group,
+					Allow:  true,
+				})
+			}
+		}
+	}
+	return explanation, nil
+}
+
+// Allowed combines the entries the same way policy.rego does. A denial at a
+// level takes precedence over every lower level, and an ACL entry allows the
+// action regardless of roles.
+func (e Explanation) Allowed() bool {
+	level := func(source ExplanationSource) int {
+		num := 0
+		for _, entry := range e.Entries {
+			if entry.Source != source {
+				continue
+			}
+			if !entry.Allow {
+				return -1
+			}
+			num = 1
+		}
+		return num
+	}
+
+	site, org, owner := level(ExplanationSourceSite), level(ExplanationSourceOrg), level(ExplanationSourceOwner)
+	switch {
+	case site == 1:
+		return true
+	case site != -1 && org == 1:
+		return true
+	case site != -1 && org != -1 && e.OrgMember && owner == 1:
+		return true
+	}
+	return level(ExplanationSourceUserACL) == 1 || level(ExplanationSourceGroupACL) == 1
+}
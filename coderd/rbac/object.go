@@ -77,12 +77,22 @@ var (
 
 	// ResourceTemplate CRUD. Org owner only.
 	//	create/delete = Make or delete a new template
-	//	update = Update the template, make new template versions
+	//	push_version = Push and promote new template versions
+	//	update = Update the template settings
+	//	manage_acl = Manage the template ACL
 	//	read = read the template and all versions associated
 	ResourceTemplate = Object{
 		Type: "template",
 	}
 
+	// ResourceTemplateVersionPromotion is a request to make a template version
+	// the active version of its template.
+	//	read = see pending and decided promotions
+	//	update = approve or reject a promotion
+	ResourceTemplateVersionPromotion = Object{
+		Type: "template_version_promotion",
+	}
+
 	// ResourceGroup CRUD. Org admins only.
 	//	create/delete = Make or delete a new group.
 	//	update = Update the name or members of a group.
@@ -184,6 +194,13 @@ var (
 		Type: "debug_info",
 	}
 
+	// ResourcePlatformEvent is the stream of domain events served from
+	// `/api/v2/platform-events`.
+	//	read = watch the event stream
+	ResourcePlatformEvent = Object{
+		Type: "platform_event",
+	}
+
 	// ResourceSystem is a pseudo-resource only used for system-level actions.
 	ResourceSystem = Object{
 		Type: "system",
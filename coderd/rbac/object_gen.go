@@ -14,12 +14,14 @@ func AllResources() []Object {
 		ResourceOrgRoleAssignment,
 		ResourceOrganization,
 		ResourceOrganizationMember,
+		ResourcePlatformEvent,
 		ResourceProvisionerDaemon,
 		ResourceReplicas,
 		ResourceRoleAssignment,
 		ResourceSystem,
 		ResourceTailnetCoordinator,
 		ResourceTemplate,
+		ResourceTemplateVersionPromotion,
 		ResourceUser,
 		ResourceUserData,
 		ResourceWildcard,
@@ -11,11 +11,12 @@ import (
 )
 
 const (
-	owner         string = "owner"
-	member        string = "member"
-	templateAdmin string = "template-admin"
-	userAdmin     string = "user-admin"
-	auditor       string = "auditor"
+	owner            string = "owner"
+	member           string = "member"
+	templateAdmin    string = "template-admin"
+	templateApprover string = "template-approver"
+	userAdmin        string = "user-admin"
+	auditor          string = "auditor"
 
 	orgAdmin  string = "organization-admin"
 	orgMember string = "organization-member"
@@ -52,6 +53,10 @@ func RoleTemplateAdmin() string {
 	return roleName(templateAdmin, "")
 }
 
+func RoleTemplateApprover() string {
+	return roleName(templateApprover, "")
+}
+
 func RoleUserAdmin() string {
 	return roleName(userAdmin, "")
 }
@@ -171,6 +176,9 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 			ResourceGroup.Type:    {ActionRead},
 			// Org roles are not really used yet, so grant the perm at the site level.
 			ResourceOrganizationMember.Type: {ActionRead},
+			// Support collects the deployment config through auditors, the
+			// API never returns secrets.
+			ResourceDeploymentValues.Type: {ActionRead},
 		}),
 		Org:  map[string][]Permission{},
 		User: []Permission{},
@@ -180,7 +188,7 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 		Name:        templateAdmin,
 		DisplayName: "Template Admin",
 		Site: Permissions(map[string][]Action{
-			ResourceTemplate.Type: {ActionCreate, ActionRead, ActionUpdate, ActionDelete},
+			ResourceTemplate.Type: {ActionCreate, ActionRead, ActionUpdate, ActionDelete, ActionPushVersion, ActionManageACL},
 			// CRUD all files, even those they did not upload.
 			ResourceFile.Type:      {ActionCreate, ActionRead, ActionUpdate, ActionDelete},
 			ResourceWorkspace.Type: {ActionRead},
@@ -192,6 +200,20 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 			ResourceGroup.Type:        {ActionRead},
 			// Org roles are not really used yet, so grant the perm at the site level.
 			ResourceOrganizationMember.Type: {ActionRead},
+			// Can request promotions, but not decide them.
+			ResourceTemplateVersionPromotion.Type: {ActionRead},
+		}),
+		Org:  map[string][]Permission{},
+		User: []Permission{},
+	}.withCachedRegoValue()
+
+	templateApproverRole := Role{
+		Name:        templateApprover,
+		DisplayName: "Template Approver",
+		Site: Permissions(map[string][]Action{
+			// Needs to read the templates and versions they decide on.
+			ResourceTemplate.Type:                 {ActionRead},
+			ResourceTemplateVersionPromotion.Type: {ActionRead, ActionUpdate},
 		}),
 		Org:  map[string][]Permission{},
 		User: []Permission{},
@@ -233,6 +255,12 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 			return templateAdminRole
 		},
 
+		// templateApprover decides on promotions of template versions
+		// requested by template admins.
+		templateApprover: func(_ string) Role {
+			return templateApproverRole
+		},
+
 		userAdmin: func(_ string) Role {
 			return userAdminRole
 		},
@@ -291,22 +319,24 @@ func ReloadBuiltinRoles(opts *RoleOptions) {
 //	map[actor_role][assign_role]<can_assign>
 var assignRoles = map[string]map[string]bool{
 	"system": {
-		owner:         true,
-		auditor:       true,
-		member:        true,
-		orgAdmin:      true,
-		orgMember:     true,
-		templateAdmin: true,
-		userAdmin:     true,
+		owner:            true,
+		auditor:          true,
+		member:           true,
+		orgAdmin:         true,
+		orgMember:        true,
+		templateAdmin:    true,
+		templateApprover: true,
+		userAdmin:        true,
 	},
 	owner: {
-		owner:         true,
-		auditor:       true,
-		member:        true,
-		orgAdmin:      true,
-		orgMember:     true,
-		templateAdmin: true,
-		userAdmin:     true,
+		owner:            true,
+		auditor:          true,
+		member:           true,
+		orgAdmin:         true,
+		orgMember:        true,
+		templateAdmin:    true,
+		templateApprover: true,
+		userAdmin:        true,
 	},
 	userAdmin: {
 		member:    true,
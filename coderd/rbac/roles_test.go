@@ -85,6 +85,7 @@ func TestRolePermissions(t *testing.T) {
 
 	templateAdmin := authSubject{Name: "template-admin", Actor: rbac.Subject{ID: templateAdminID.String(), Roles: rbac.RoleNames{rbac.RoleMember(), rbac.RoleTemplateAdmin()}}}
 	userAdmin := authSubject{Name: "user-admin", Actor: rbac.Subject{ID: templateAdminID.String(), Roles: rbac.RoleNames{rbac.RoleMember(), rbac.RoleUserAdmin()}}}
+	templateApprover := authSubject{Name: "template-approver", Actor: rbac.Subject{ID: uuid.NewString(), Roles: rbac.RoleNames{rbac.RoleMember(), rbac.RoleTemplateApprover()}}}
 
 	// requiredSubjects are required to be asserted in each test case. This is
 	// to make sure one is not forgotten.
@@ -167,7 +168,16 @@ func TestRolePermissions(t *testing.T) {
 			Resource: rbac.ResourceTemplate.WithID(templateID).InOrg(orgID),
 			AuthorizeMap: map[bool][]authSubject{
 				true:  {owner, orgAdmin, templateAdmin},
-				false: {memberMe, orgMemberMe, otherOrgAdmin, otherOrgMember, userAdmin},
+				false: {memberMe, orgMemberMe, otherOrgAdmin, otherOrgMember, userAdmin, templateApprover},
+			},
+		},
+		{
+			Name:     "TemplatePushVersionAndManageACL",
+			Actions:  []rbac.Action{rbac.ActionPushVersion, rbac.ActionManageACL},
+			Resource: rbac.ResourceTemplate.WithID(templateID).InOrg(orgID),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, orgAdmin, templateAdmin},
+				false: {memberMe, orgMemberMe, otherOrgAdmin, otherOrgMember, userAdmin, templateApprover},
 			},
 		},
 		{
@@ -175,10 +185,28 @@ func TestRolePermissions(t *testing.T) {
 			Actions:  []rbac.Action{rbac.ActionRead},
 			Resource: rbac.ResourceTemplate.InOrg(orgID),
 			AuthorizeMap: map[bool][]authSubject{
-				true:  {owner, orgAdmin, templateAdmin},
+				true:  {owner, orgAdmin, templateAdmin, templateApprover},
 				false: {memberMe, otherOrgAdmin, otherOrgMember, userAdmin, orgMemberMe},
 			},
 		},
+		{
+			Name:     "ReadTemplateVersionPromotions",
+			Actions:  []rbac.Action{rbac.ActionRead},
+			Resource: rbac.ResourceTemplateVersionPromotion.WithID(uuid.New()).InOrg(orgID),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, orgAdmin, templateAdmin, templateApprover},
+				false: {memberMe, otherOrgAdmin, otherOrgMember, userAdmin, orgMemberMe},
+			},
+		},
+		{
+			Name:     "DecideTemplateVersionPromotions",
+			Actions:  []rbac.Action{rbac.ActionUpdate},
+			Resource: rbac.ResourceTemplateVersionPromotion.WithID(uuid.New()).InOrg(orgID),
+			AuthorizeMap: map[bool][]authSubject{
+				true:  {owner, orgAdmin, templateApprover},
+				false: {memberMe, otherOrgAdmin, otherOrgMember, userAdmin, orgMemberMe, templateAdmin},
+			},
+		},
 		{
 			Name:     "Files",
 			Actions:  []rbac.Action{rbac.ActionCreate},
@@ -444,6 +472,7 @@ func TestListRoles(t *testing.T) {
 		"member",
 		"auditor",
 		"template-admin",
+		"template-approver",
 		"user-admin",
 	},
 		siteRoleNames)
@@ -8,25 +8,31 @@ import (
 	"golang.org/x/xerrors"
 )
 
-// WorkspaceAgentScope returns a scope that is the same as ScopeAll but can only
-// affect resources in the allow list. Only a scope is returned as the roles
-// should come from the workspace owner.
+// WorkspaceAgentScope returns a scope that can only affect the workspace the
+// agent belongs to and the user data of its owner. Only a scope is returned as
+// the roles should come from the workspace owner. A token leaked from one
+// workspace therefore cannot be used to read or modify the other workspaces of
+// the same owner.
 func WorkspaceAgentScope(workspaceID, ownerID uuid.UUID) Scope {
-	allScope, err := ScopeAll.Expand()
-	if err != nil {
-		panic("failed to expand scope all, this should never happen")
-	}
 	return Scope{
-		// TODO: We want to limit the role too to be extra safe.
-		// Even though the allowlist blocks anything else, it is still good
-		// incase we change the behavior of the allowlist. The allowlist is new
-		// and evolving.
-		Role: allScope.Role,
+		Role: Role{
+			Name:        "Scope_workspace_agent",
+			DisplayName: "Workspace agent",
+			Site: Permissions(map[string][]Action{
+				// Agents report stats, logs and lifecycle changes on their
+				// workspace.
+				ResourceWorkspace.Type: {ActionRead, ActionUpdate},
+				// Agents read their owner and the owner's git credentials.
+				ResourceUser.Type:     {ActionRead},
+				ResourceUserData.Type: {ActionRead, ActionUpdate},
+			}),
+			Org:  map[string][]Permission{},
+			User: []Permission{},
+		},
 		// This prevents the agent from being able to access any other resource.
 		AllowIDList: []string{
 			workspaceID.String(),
 			ownerID.String(),
-			// TODO: Might want to include the template the workspace uses too?
 		},
 	}
 }
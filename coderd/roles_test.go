@@ -46,10 +46,11 @@ func TestListRoles(t *testing.T) {
 				return x, err
 			},
 			ExpectedRoles: convertRoles(map[string]bool{
-				"owner":          false,
-				"auditor":        false,
-				"template-admin": false,
-				"user-admin":     false,
+				"owner":             false,
+				"auditor":           false,
+				"template-admin":    false,
+				"template-approver": false,
+				"user-admin":        false,
 			}),
 		},
 		{
@@ -75,10 +76,11 @@ func TestListRoles(t *testing.T) {
 				return orgAdmin.ListSiteRoles(ctx)
 			},
 			ExpectedRoles: convertRoles(map[string]bool{
-				"owner":          false,
-				"auditor":        false,
-				"template-admin": false,
-				"user-admin":     false,
+				"owner":             false,
+				"auditor":           false,
+				"template-admin":    false,
+				"template-approver": false,
+				"user-admin":        false,
 			}),
 		},
 		{
@@ -104,10 +106,11 @@ func TestListRoles(t *testing.T) {
 				return client.ListSiteRoles(ctx)
 			},
 			ExpectedRoles: convertRoles(map[string]bool{
-				"owner":          true,
-				"auditor":        true,
-				"template-admin": true,
-				"user-admin":     true,
+				"owner":             true,
+				"auditor":           true,
+				"template-admin":    true,
+				"template-approver": true,
+				"user-admin":        true,
 			}),
 		},
 		{
@@ -105,6 +105,10 @@ type TemplateScheduleOptions struct {
 	// LockedTTL dictates the duration after which locked workspaces will be
 	// permanently deleted.
 	LockedTTL time.Duration `json:"locked_ttl"`
+	// AutostopActivitySources are the kinds of activity reported by agents
+	// that postpone the autostop of workspaces. If empty, any connection
+	// postpones it.
+	AutostopActivitySources []string `json:"autostop_activity_sources"`
 	// UpdateWorkspaceLastUsedAt updates the template's workspaces'
 	// last_used_at field. This is useful for preventing updates to the
 	// templates inactivity_ttl immediately triggering a lock action against
@@ -150,16 +154,18 @@ func (*agplTemplateScheduleStore) Get(ctx context.Context, db database.Store, te
 		UserAutostopEnabled:  true,
 		DefaultTTL:           time.Duration(tpl.DefaultTTL),
 		// Disregard the values in the database, since RestartRequirement,
-		// FailureTTL, InactivityTTL, and LockedTTL are enterprise features.
+		// FailureTTL, InactivityTTL, LockedTTL, and AutostopActivitySources
+		// are enterprise features.
 		UseRestartRequirement: false,
 		MaxTTL:                0,
 		RestartRequirement: TemplateRestartRequirement{
 			DaysOfWeek: 0,
 			Weeks:      0,
 		},
-		FailureTTL:    0,
-		InactivityTTL: 0,
-		LockedTTL:     0,
+		FailureTTL:              0,
+		InactivityTTL:           0,
+		LockedTTL:               0,
+		AutostopActivitySources: nil,
 	}, nil
 }
 
@@ -188,6 +194,7 @@ func (*agplTemplateScheduleStore) Set(ctx context.Context, db database.Store, tp
 			FailureTTL:                   tpl.FailureTTL,
 			InactivityTTL:                tpl.InactivityTTL,
 			LockedTTL:                    tpl.LockedTTL,
+			AutostopActivitySources:      tpl.AutostopActivitySources,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template schedule: %w", err)
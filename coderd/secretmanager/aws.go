@@ -0,0 +1,86 @@
+package secretmanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"golang.org/x/xerrors"
+)
+
+type awsSecretsManager struct {
+	client      *http.Client
+	signer      *v4.Signer
+	credentials aws.CredentialsProvider
+	region      string
+	endpoint    string
+}
+
+// NewAWS returns a provider that reads secrets from AWS Secrets Manager. The
+// path is the name or ARN of the secret, and key selects a field of secrets
+// stored as JSON objects, e.g. secret://aws/prod/coder/db#password. endpoint
+// overrides the regional endpoint of the service if it's set.
+func NewAWS(client *http.Client, credentials aws.CredentialsProvider, region, endpoint string) Provider {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com/", region)
+	}
+	return &awsSecretsManager{
+		client:      client,
+		signer:      v4.NewSigner(),
+		credentials: credentials,
+		region:      region,
+		endpoint:    endpoint,
+	}
+}
+
+func (a *awsSecretsManager) Secret(ctx context.Context, path, key string) (string, error) {
+	payload, err := json.Marshal(map[string]string{"SecretId": path})
+	if err != nil {
+		return "", xerrors.Errorf("encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", xerrors.Errorf("create request: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+
+	creds, err := a.credentials.Retrieve(ctx)
+	if err != nil {
+		return "", xerrors.Errorf("retrieve aws credentials: %w", err)
+	}
+	err = a.signer.SignHTTP(ctx, creds, req, payloadHash, "secretsmanager", a.region, time.Now())
+	if err != nil {
+		return "", xerrors.Errorf("sign request: %w", err)
+	}
+	res, err := a.client.Do(req)
+	if err != nil {
+		return "", xerrors.Errorf("get secret value: %w", err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		SecretString *string `json:"SecretString"`
+		SecretBinary []byte  `json:"SecretBinary"`
+	}
+	err = readResponse(res, &body)
+	if err != nil {
+		return "", xerrors.Errorf("get secret value: %w", err)
+	}
+	if body.SecretString == nil {
+		// Binary secrets are base64 encoded, as terraform variables are
+		// strings.
+		return base64.StdEncoding.EncodeToString(body.SecretBinary), nil
+	}
+	return jsonField(*body.SecretString, key)
+}
@@ -0,0 +1,65 @@
+package secretmanager
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// GCPEndpoint is the endpoint of the Secret Manager API of Google Cloud.
+var GCPEndpoint = &url.URL{Scheme: "https", Host: "secretmanager.googleapis.com"}
+
+type gcpSecretManager struct {
+	client   *http.Client
+	endpoint *url.URL
+}
+
+// NewGCP returns a provider that reads secrets from Google Cloud Secret
+// Manager. The path is the resource name of the secret, with the latest version
+// read if it doesn't name one, e.g.
+// secret://gcp/projects/my-project/secrets/db-password. key selects a field of
+// secrets stored as JSON objects. The client must authenticate its requests,
+// e.g. the client of golang.org/x/oauth2/google.DefaultClient.
+func NewGCP(client *http.Client, endpoint *url.URL) Provider {
+	return &gcpSecretManager{
+		client:   client,
+		endpoint: endpoint,
+	}
+}
+
+func (g *gcpSecretManager) Secret(ctx context.Context, path, key string) (string, error) {
+	if !strings.HasPrefix(path, "projects/") || !strings.Contains(path, "/secrets/") {
+		return "", xerrors.New("gcp secret path must be of the form projects/<project>/secrets/<secret>")
+	}
+	if !strings.Contains(path, "/versions/") {
+		path += "/versions/latest"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, joinURL(g.endpoint, "v1", path+":access"), nil)
+	if err != nil {
+		return "", xerrors.Errorf("create request: %w", err)
+	}
+	res, err := g.client.Do(req)
+	if err != nil {
+		return "", xerrors.Errorf("access secret version: %w", err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	err = readResponse(res, &body)
+	if err != nil {
+		return "", xerrors.Errorf("access secret version: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(body.Payload.Data)
+	if err != nil {
+		return "", xerrors.Errorf("decode secret payload: %w", err)
+	}
+	return jsonField(string(data), key)
+}
@@ -0,0 +1,211 @@
+// Package secretmanager resolves references to secrets held by external secret
+// managers. Template variables can be set to a secret:// URI instead of the
+// secret, which is then only read when a build needs it.
+package secretmanager
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Scheme is the URI scheme of references to secrets.
+const Scheme = "secret"
+
+// Provider reads secrets from a secret manager.
+type Provider interface {
+	// Secret returns the secret at the path. Secrets with several fields
+	// return the field named by key, which may be empty for secrets with
+	// a single value.
+	Secret(ctx context.Context, path, key string) (string, error)
+}
+
+// Reference is a parsed secret:// URI of the form
+// secret://<provider>/<path>#<key>.
+type Reference struct {
+	Provider string
+	Path     string
+	Key      string
+}
+
+func (r Reference) String() string {
+	u := url.URL{Scheme: Scheme, Host: r.Provider, Path: "/" + r.Path, Fragment: r.Key}
+	return u.String()
+}
+
+// IsReference reports whether the value refers to a secret rather than being
+// a value itself.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, Scheme+"://")
+}
+
+// ParseReference parses a secret:// URI.
+func ParseReference(value string) (Reference, error) {
+	if !IsReference(value) {
+		return Reference{}, xerrors.Errorf("secret references must start with %s://", Scheme)
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return Reference{}, xerrors.Errorf("parse secret reference: %w", err)
+	}
+	ref := Reference{
+		Provider: u.Host,
+		Path:     strings.Trim(u.Path, "/"),
+		Key:      u.Fragment,
+	}
+	if ref.Provider == "" {
+		return Reference{}, xerrors.New("secret reference must name a secret manager")
+	}
+	if ref.Path == "" {
+		return Reference{}, xerrors.New("secret reference must have a path")
+	}
+	if u.RawQuery != "" || u.User != nil {
+		return Reference{}, xerrors.New("secret reference can't have a query or user info")
+	}
+	return ref, nil
+}
+
+// Manager resolves references to the secret managers it's configured with. A
+// nil Manager has no secret managers, so every reference fails to resolve.
+type Manager struct {
+	providers map[string]Provider
+}
+
+// New returns a manager with the providers, keyed by the name references use
+// for them, e.g. "vault".
+func New(providers map[string]Provider) *Manager {
+	return &Manager{providers: providers}
+}
+
+// Providers returns the names of the configured secret managers.
+func (m *Manager) Providers() []string {
+	if m == nil {
+		return nil
+	}
+	names := make([]string, 0, len(m.providers))
+	for name := range m.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate returns an error if the value is a reference that can't be
+// resolved by any configured secret manager. The secret itself isn't read.
+// Values that aren't references are valid.
+func (m *Manager) Validate(value string) error {
+	if !IsReference(value) {
+		return nil
+	}
+	ref, err := ParseReference(value)
+	if err != nil {
+		return err
+	}
+	_, err = m.provider(ref.Provider)
+	return err
+}
+
+// Resolve returns the secret the value refers to, or the value itself if it
+// isn't a reference. Errors never contain the secret.
+func (m *Manager) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+	ref, err := ParseReference(value)
+	if err != nil {
+		return "", err
+	}
+	provider, err := m.provider(ref.Provider)
+	if err != nil {
+		return "", err
+	}
+	secret, err := provider.Secret(ctx, ref.Path, ref.Key)
+	if err != nil {
+		return "", xerrors.Errorf("read secret %s: %w", ref, err)
+	}
+	return secret, nil
+}
+
+func (m *Manager) provider(name string) (Provider, error) {
+	if m != nil {
+		if provider, ok := m.providers[name]; ok {
+			return provider, nil
+		}
+	}
+	if len(m.Providers()) == 0 {
+		return nil, xerrors.Errorf("secret manager %q isn't configured, no secret managers are configured", name)
+	}
+	return nil, xerrors.Errorf("secret manager %q isn't configured, configured secret managers are %s", name, strings.Join(m.Providers(), ", "))
+}
+
+// field returns the value of the key in a JSON object of fields, or the
+// only field if key is empty.
+func field(fields map[string]interface{}, key string) (string, error) {
+	if key == "" {
+		if len(fields) != 1 {
+			names := make([]string, 0, len(fields))
+			for name := range fields {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return "", xerrors.Errorf("secret has fields %s, select one with #<field>", strings.Join(names, ", "))
+		}
+		for name := range fields {
+			key = name
+		}
+	}
+	value, ok := fields[key]
+	if !ok {
+		return "", xerrors.Errorf("secret has no field %q", key)
+	}
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", xerrors.Errorf("encode field %q: %w", key, err)
+		}
+		return string(data), nil
+	}
+}
+
+// jsonField returns the key of a secret stored as a JSON object, or the whole
+// secret if key is empty.
+func jsonField(secret, key string) (string, error) {
+	if key == "" {
+		return secret, nil
+	}
+	var fields map[string]interface{}
+	err := json.Unmarshal([]byte(secret), &fields)
+	if err != nil {
+		return "", xerrors.Errorf("secret isn't a JSON object, so field %q can't be selected", key)
+	}
+	return field(fields, key)
+}
+
+// readResponse decodes a JSON response into v, or returns an error with the
+// status and the start of the body of failed requests.
+func readResponse(res *http.Response, v interface{}) error {
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return xerrors.Errorf("unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	err := json.NewDecoder(res.Body).Decode(v)
+	if err != nil {
+		return xerrors.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+func joinURL(base *url.URL, elem ...string) string {
+	u := *base
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + strings.Join(elem, "/")
+	return u.String()
+}
@@ -0,0 +1,179 @@
+package secretmanager_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/secretmanager"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Parallel()
+
+	ref, err := secretmanager.ParseReference("secret://vault/secret/coder/db#password")
+	require.NoError(t, err)
+	require.Equal(t, secretmanager.Reference{Provider: "vault", Path: "secret/coder/db", Key: "password"}, ref)
+	require.Equal(t, "secret://vault/secret/coder/db#password", ref.String())
+
+	for _, value := range []string{
+		"vault/secret/coder/db",
+		"secret:///secret/coder/db",
+		"secret://vault",
+		"secret://vault/db?version=2",
+	} {
+		_, err := secretmanager.ParseReference(value)
+		require.Error(t, err, value)
+	}
+}
+
+func TestManager(t *testing.T) {
+	t.Parallel()
+
+	manager := secretmanager.New(map[string]secretmanager.Provider{
+		"static": staticProvider{"db": "hunter2"},
+	})
+	ctx := context.Background()
+
+	value, err := manager.Resolve(ctx, "plain value")
+	require.NoError(t, err)
+	require.Equal(t, "plain value", value)
+
+	value, err = manager.Resolve(ctx, "secret://static/db")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", value)
+
+	require.NoError(t, manager.Validate("secret://static/other"))
+	err = manager.Validate("secret://vault/secret/db")
+	require.ErrorContains(t, err, `secret manager "vault" isn't configured`)
+
+	_, err = manager.Resolve(ctx, "secret://static/missing")
+	require.ErrorContains(t, err, "secret://static/missing")
+
+	var unconfigured *secretmanager.Manager
+	require.NoError(t, unconfigured.Validate("plain value"))
+	require.ErrorContains(t, unconfigured.Validate("secret://static/db"), "no secret managers are configured")
+}
+
+func TestVault(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/data/coder/db" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, `{"data":{"data":{"username":"coder","password":"hunter2"}}}`)
+	}))
+	t.Cleanup(srv.Close)
+	address, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	vault := secretmanager.NewVault(srv.Client(), address, "token")
+	ctx := context.Background()
+
+	value, err := vault.Secret(ctx, "secret/coder/db", "password")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", value)
+
+	_, err = vault.Secret(ctx, "secret/coder/db", "")
+	require.ErrorContains(t, err, "password, username")
+
+	_, err = vault.Secret(ctx, "secret/coder/missing", "password")
+	require.ErrorContains(t, err, "unexpected status 404")
+}
+
+func TestAWS(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.Contains(r.Header.Get("Authorization"), "Credential=AKID/") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req struct {
+			SecretID string `json:"SecretId"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		switch req.SecretID {
+		case "prod/db":
+			_, _ = io.WriteString(w, `{"SecretString":"{\"password\":\"hunter2\"}"}`)
+		case "prod/token":
+			_, _ = io.WriteString(w, `{"SecretString":"token"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"ResourceNotFoundException"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	credentials := aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+	})
+	provider := secretmanager.NewAWS(srv.Client(), credentials, "us-east-1", srv.URL)
+	ctx := context.Background()
+
+	value, err := provider.Secret(ctx, "prod/db", "password")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", value)
+
+	value, err = provider.Secret(ctx, "prod/token", "")
+	require.NoError(t, err)
+	require.Equal(t, "token", value)
+
+	_, err = provider.Secret(ctx, "prod/token", "password")
+	require.ErrorContains(t, err, "isn't a JSON object")
+
+	_, err = provider.Secret(ctx, "prod/missing", "")
+	require.ErrorContains(t, err, "ResourceNotFoundException")
+}
+
+func TestGCP(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/projects/coder/secrets/db/versions/latest:access" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"payload": map[string]string{
+				"data": base64.StdEncoding.EncodeToString([]byte("hunter2")),
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	endpoint, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	provider := secretmanager.NewGCP(srv.Client(), endpoint)
+	ctx := context.Background()
+
+	value, err := provider.Secret(ctx, "projects/coder/secrets/db", "")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", value)
+
+	_, err = provider.Secret(ctx, "coder/db", "")
+	require.ErrorContains(t, err, "projects/<project>/secrets/<secret>")
+}
+
+type staticProvider map[string]string
+
+func (p staticProvider) Secret(_ context.Context, path, _ string) (string, error) {
+	value, ok := p[path]
+	if !ok {
+		return "", io.EOF
+	}
+	return value, nil
+}
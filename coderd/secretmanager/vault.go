@@ -0,0 +1,56 @@
+package secretmanager
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+type vault struct {
+	client  *http.Client
+	address *url.URL
+	token   string
+}
+
+// NewVault returns a provider that reads secrets from the KV version 2 secrets
+// engines of a HashiCorp Vault server. The first element of the path is the
+// mount of the secrets engine, e.g. secret://vault/secret/coder/db#password
+// reads the password field of coder/db in the engine mounted at secret.
+func NewVault(client *http.Client, address *url.URL, token string) Provider {
+	return &vault{
+		client:  client,
+		address: address,
+		token:   token,
+	}
+}
+
+func (v *vault) Secret(ctx context.Context, path, key string) (string, error) {
+	mount, secretPath, ok := strings.Cut(path, "/")
+	if !ok || secretPath == "" {
+		return "", xerrors.New("vault secret path must start with the mount of the secrets engine")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, joinURL(v.address, "v1", mount, "data", secretPath), nil)
+	if err != nil {
+		return "", xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	res, err := v.client.Do(req)
+	if err != nil {
+		return "", xerrors.Errorf("read vault secret: %w", err)
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	err = readResponse(res, &body)
+	if err != nil {
+		return "", xerrors.Errorf("read vault secret: %w", err)
+	}
+	return field(body.Data.Data, key)
+}
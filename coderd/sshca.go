@@ -0,0 +1,178 @@
+package coderd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"net/http"
+	"strings"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// sshUserCertificateLifetime is how long SSH certificates of users are
+	// valid. They are cheap to issue again, so they are kept short to not
+	// need revocation.
+	sshUserCertificateLifetime = time.Hour
+	// sshHostCertificateLifetime is how long SSH certificates of workspace
+	// agents are valid. Agents get a new certificate every time they fetch
+	// their manifest, so it only has to outlast long running workspaces.
+	sshHostCertificateLifetime = 365 * 24 * time.Hour
+	// sshCertificateClockSkew backdates certificates, so hosts with a clock
+	// behind coderd accept them right away.
+	sshCertificateClockSkew = time.Minute
+)
+
+// newSSHCertificateAuthority returns the signer of SSH certificates for the
+// key. A random key is generated if none is given, which is only useful in
+// tests since certificates become invalid on restart.
+func newSSHCertificateAuthority(key ed25519.PrivateKey) (gossh.Signer, error) {
+	if key == nil {
+		var err error
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, xerrors.Errorf("generate key: %w", err)
+		}
+	}
+	return gossh.NewSignerFromKey(key)
+}
+
+// signSSHCertificate signs the public key with the certificate authority of
+// the deployment.
+func (api *API) signSSHCertificate(publicKey gossh.PublicKey, certType uint32, keyID string, principals []string, lifetime time.Duration) (*gossh.Certificate, error) {
+	var serial [8]byte
+	_, err := rand.Read(serial[:])
+	if err != nil {
+		return nil, xerrors.Errorf("generate serial: %w", err)
+	}
+	now := time.Now()
+	cert := &gossh.Certificate{
+		Key:             publicKey,
+		Serial:          binary.BigEndian.Uint64(serial[:]),
+		CertType:        certType,
+		KeyId:           keyID,
+		ValidPrincipals: principals,
+		ValidAfter:      uint64(now.Add(-sshCertificateClockSkew).Unix()),
+		ValidBefore:     uint64(now.Add(lifetime).Unix()),
+	}
+	if certType == gossh.UserCert {
+		// These are the extensions ssh-keygen grants by default.
+		cert.Permissions.Extensions = map[string]string{
+			"permit-X11-forwarding":   "",
+			"permit-agent-forwarding": "",
+			"permit-port-forwarding":  "",
+			"permit-pty":              "",
+			"permit-user-rc":          "",
+		}
+	}
+	err = cert.SignCert(rand.Reader, api.sshCA)
+	if err != nil {
+		return nil, xerrors.Errorf("sign certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// workspaceAgentSSHHostCertificate signs the host key of the agent for the
+// hostnames config-ssh uses for it. config-ssh only adds the bare workspace
+// name for workspaces with a single agent, it's included for every agent so
+// the certificate doesn't depend on the other agents of the build.
+func (api *API) workspaceAgentSSHHostCertificate(hostKey string, workspaceName, agentName string) (string, error) {
+	signer, err := gossh.ParsePrivateKey([]byte(hostKey))
+	if err != nil {
+		return "", xerrors.Errorf("parse host key: %w", err)
+	}
+	cert, err := api.signSSHCertificate(signer.PublicKey(), gossh.HostCert, workspaceName+"."+agentName, []string{
+		api.SSHConfig.HostnamePrefix + workspaceName,
+		api.SSHConfig.HostnamePrefix + workspaceName + "." + agentName,
+	}, sshHostCertificateLifetime)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(gossh.MarshalAuthorizedKey(cert))), nil
+}
+
+// @Summary Get SSH certificate authority
+// @ID get-ssh-certificate-authority
+// @Produce json
+// @Tags General
+// @Success 200 {object} codersdk.SSHCertificateAuthority
+// @Router /deployment/ssh/ca [get]
+func (api *API) sshCertificateAuthority(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.SSHCertificateAuthority{
+		PublicKey: strings.TrimSpace(string(gossh.MarshalAuthorizedKey(api.sshCA.PublicKey()))),
+	})
+}
+
+// postUserSSHCertificate signs a short-lived SSH certificate for the public
+// key, which sshd servers trusting the certificate authority accept for the
+// username of the user.
+//
+// @Summary Create SSH certificate for user
+// @ID create-ssh-certificate-for-user
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.CreateSSHCertificateRequest true "Create SSH certificate request"
+// @Success 201 {object} codersdk.SSHCertificate
+// @Router /users/{user}/ssh/certificate [post]
+func (api *API) postUserSSHCertificate(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	// The certificate grants the same access as a session of the user.
+	if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceAPIKey.WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.CreateSSHCertificateRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	publicKey, _, _, _, err := gossh.ParseAuthorizedKey([]byte(req.PublicKey))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid SSH public key.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if _, ok := publicKey.(*gossh.Certificate); ok {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Certificates can't be signed, use the public key instead.",
+		})
+		return
+	}
+
+	cert, err := api.signSSHCertificate(publicKey, gossh.UserCert, user.Username, []string{user.Username}, sshUserCertificateLifetime)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error signing SSH certificate.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.Logger.Info(ctx, "issued ssh certificate",
+		slog.F("user_id", user.ID),
+		slog.F("serial", cert.Serial),
+		slog.F("fingerprint", gossh.FingerprintSHA256(publicKey)),
+	)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.SSHCertificate{
+		Certificate: strings.TrimSpace(string(gossh.MarshalAuthorizedKey(cert))),
+		Principals:  cert.ValidPrincipals,
+		ValidAfter:  time.Unix(int64(cert.ValidAfter), 0).UTC(),
+		ValidBefore: time.Unix(int64(cert.ValidBefore), 0).UTC(),
+	})
+}
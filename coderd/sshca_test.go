@@ -0,0 +1,126 @@
+package coderd_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	gossh "golang.org/x/crypto/ssh"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSSHCertificateAuthority(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	owner := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	// The certificate authority is public.
+	ca, err := codersdk.New(client.URL).SSHCertificateAuthority(ctx)
+	require.NoError(t, err)
+	caKey, _, _, _, err := gossh.ParseAuthorizedKey([]byte(ca.PublicKey))
+	require.NoError(t, err)
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshPublicKey, err := gossh.NewPublicKey(publicKey)
+	require.NoError(t, err)
+	rawPublicKey := string(gossh.MarshalAuthorizedKey(sshPublicKey))
+
+	t.Run("UserCertificate", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		cert, err := memberClient.CreateSSHCertificate(ctx, codersdk.Me, codersdk.CreateSSHCertificateRequest{
+			PublicKey: rawPublicKey,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{member.Username}, cert.Principals)
+		require.True(t, cert.ValidBefore.After(cert.ValidAfter))
+
+		parsed, _, _, _, err := gossh.ParseAuthorizedKey([]byte(cert.Certificate))
+		require.NoError(t, err)
+		sshCert, ok := parsed.(*gossh.Certificate)
+		require.True(t, ok)
+		require.Equal(t, uint32(gossh.UserCert), sshCert.CertType)
+		require.Equal(t, sshPublicKey.Marshal(), sshCert.Key.Marshal())
+		require.Equal(t, caKey.Marshal(), sshCert.SignatureKey.Marshal())
+		checker := &gossh.CertChecker{}
+		require.NoError(t, checker.CheckCert(member.Username, sshCert))
+		require.Error(t, checker.CheckCert("root", sshCert))
+	})
+
+	t.Run("OtherUser", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := memberClient.CreateSSHCertificate(ctx, owner.UserID.String(), codersdk.CreateSSHCertificateRequest{
+			PublicKey: rawPublicKey,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+
+	t.Run("InvalidPublicKey", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := memberClient.CreateSSHCertificate(ctx, codersdk.Me, codersdk.CreateSSHCertificateRequest{
+			PublicKey: "not a key",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("HostCertificate", func(t *testing.T) {
+		t.Parallel()
+
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, owner.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, owner.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, owner.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		agentName := build.Resources[0].Agents[0].Name
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		manifest, err := agentClient.Manifest(ctx)
+		require.NoError(t, err)
+		hostKey, err := gossh.ParsePrivateKey([]byte(manifest.SSHHostKey))
+		require.NoError(t, err)
+
+		parsed, _, _, _, err := gossh.ParseAuthorizedKey([]byte(manifest.SSHHostCertificate))
+		require.NoError(t, err)
+		sshCert, ok := parsed.(*gossh.Certificate)
+		require.True(t, ok)
+		require.Equal(t, uint32(gossh.HostCert), sshCert.CertType)
+		require.Equal(t, hostKey.PublicKey().Marshal(), sshCert.Key.Marshal())
+		require.Equal(t, caKey.Marshal(), sshCert.SignatureKey.Marshal())
+		require.Equal(t, []string{
+			"coder." + workspace.Name,
+			"coder." + workspace.Name + "." + agentName,
+		}, sshCert.ValidPrincipals)
+		checker := &gossh.CertChecker{}
+		require.NoError(t, checker.CheckCert("coder."+workspace.Name, sshCert))
+	})
+}
@@ -398,6 +398,19 @@ func (c *netConnCloser) Close() error {
 	return c.Conn.Close()
 }
 
+// AgentCount returns the number of agents the server keeps a connection to.
+func (s *ServerTailnet) AgentCount() int {
+	s.nodesMu.Lock()
+	defer s.nodesMu.Unlock()
+	return len(s.agentConnectionTimes)
+}
+
+// CacheLen returns the number of agent connections cached for agents that
+// don't support the server tailnet.
+func (s *ServerTailnet) CacheLen() int {
+	return s.cache.Len()
+}
+
 func (s *ServerTailnet) Close() error {
 	s.cancel()
 	_ = s.cache.Close()
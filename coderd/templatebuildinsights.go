@@ -0,0 +1,169 @@
+package coderd
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// templateBuildInsightsRollupOverlap is how far back each rollup looks before
+// the start of the previous one, so that builds that were committed while the
+// previous rollup ran aren't missed. Recomputing a version is idempotent.
+const templateBuildInsightsRollupOverlap = time.Minute
+
+// runTemplateBuildInsightsRollup periodically rolls up the completed
+// workspace builds of each template version. Only the versions that had
+// builds complete since the previous rollup are recomputed.
+func (api *API) runTemplateBuildInsightsRollup(interval time.Duration) {
+	defer close(api.templateBuildInsightsRollupDone)
+
+	//nolint:gocritic // The rollup looks at the builds of all templates.
+	ctx := dbauthz.AsSystemRestricted(api.ctx)
+	logger := api.Logger.Named("template_build_insights")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	// Every build is rolled up on startup, as builds may have completed
+	// while no replica was running.
+	var since time.Time
+	for {
+		now := database.Now()
+		err := api.Database.UpsertTemplateVersionBuildInsights(ctx, database.UpsertTemplateVersionBuildInsightsParams{
+			Since:     since,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			if !database.IsQueryCanceledError(err) {
+				logger.Error(ctx, "roll up template version builds", slog.Error(err))
+			}
+		} else {
+			since = now.Add(-templateBuildInsightsRollupOverlap)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// @Summary Get template build insights
+// @Description Build durations, failures by provisioner error code and the
+// @Description change to the previous version, for each version of the
+// @Description template. The builds are rolled up periodically.
+// @ID get-template-build-insights
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Insights
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.TemplateBuildInsightsResponse
+// @Router /templates/{template}/insights/builds [get]
+func (api *API) templateBuildInsights(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	template := httpmw.TemplateParam(r)
+
+	if !api.Authorize(r, rbac.ActionUpdate, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	rows, err := api.Database.GetTemplateVersionBuildInsights(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template build insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp, err := convertTemplateBuildInsights(template, rows)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting template build insights.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
+// convertTemplateBuildInsights converts the rollups of the versions of a
+// template, which must be ordered from the newest to the oldest version, and
+// compares each version to the one before it.
+func convertTemplateBuildInsights(template database.Template, rows []database.GetTemplateVersionBuildInsightsRow) (codersdk.TemplateBuildInsightsResponse, error) {
+	resp := codersdk.TemplateBuildInsightsResponse{
+		TemplateID: template.ID,
+		Versions:   make([]codersdk.TemplateVersionBuildInsights, 0, len(rows)),
+	}
+	for _, row := range rows {
+		var failures map[string]int64
+		err := json.Unmarshal(row.Failures, &failures)
+		if err != nil {
+			return codersdk.TemplateBuildInsightsResponse{}, xerrors.Errorf("unmarshal failures of template version %s: %w", row.TemplateVersionID, err)
+		}
+
+		version := codersdk.TemplateVersionBuildInsights{
+			TemplateVersionID:   row.TemplateVersionID,
+			TemplateVersionName: row.TemplateVersionName,
+			CreatedAt:           row.TemplateVersionCreatedAt,
+			Active:              row.TemplateVersionID == template.ActiveVersionID,
+			TotalBuilds:         row.TotalBuilds,
+			FailedBuilds:        row.FailedBuilds,
+			FailureRate:         buildRate(row.FailedBuilds, row.TotalBuilds),
+			DurationP50Seconds:  row.DurationSeconds50,
+			DurationP95Seconds:  row.DurationSeconds95,
+			Failures:            make([]codersdk.TemplateBuildFailureInsight, 0, len(failures)),
+			UpdatedAt:           row.UpdatedAt,
+		}
+		for code, builds := range failures {
+			version.Failures = append(version.Failures, codersdk.TemplateBuildFailureInsight{
+				ErrorCode: codersdk.JobErrorCode(code),
+				Builds:    builds,
+				Rate:      buildRate(builds, row.TotalBuilds),
+			})
+		}
+		sort.Slice(version.Failures, func(i, j int) bool {
+			a, b := version.Failures[i], version.Failures[j]
+			if a.Builds != b.Builds {
+				return a.Builds > b.Builds
+			}
+			return a.ErrorCode < b.ErrorCode
+		})
+		resp.Versions = append(resp.Versions, version)
+	}
+
+	for i := 0; i < len(resp.Versions)-1; i++ {
+		current, previous := &resp.Versions[i], resp.Versions[i+1]
+		comparison := &codersdk.TemplateVersionBuildComparison{
+			PreviousTemplateVersionID:   previous.TemplateVersionID,
+			PreviousTemplateVersionName: previous.TemplateVersionName,
+			FailureRateChange:           current.FailureRate - previous.FailureRate,
+		}
+		if current.DurationP50Seconds >= 0 && previous.DurationP50Seconds >= 0 {
+			comparison.DurationP50ChangeSeconds = current.DurationP50Seconds - previous.DurationP50Seconds
+			comparison.DurationP95ChangeSeconds = current.DurationP95Seconds - previous.DurationP95Seconds
+		}
+		current.Comparison = comparison
+	}
+	return resp, nil
+}
+
+func buildRate(builds, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(builds) / float64(total)
+}
@@ -1,7 +1,9 @@
 package coderd
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
@@ -21,6 +24,7 @@ import (
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/telemetry"
 	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/examples"
 )
@@ -542,6 +546,93 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 	if req.LockedTTLMillis < 0 {
 		validErrs = append(validErrs, codersdk.ValidationError{Field: "locked_ttl_ms", Detail: "Must be a positive integer."})
 	}
+	requireActiveVersion := template.RequireActiveVersion
+	if req.RequireActiveVersion != nil {
+		requireActiveVersion = *req.RequireActiveVersion
+	}
+	requireActiveVersionGracePeriod := time.Duration(template.RequireActiveVersionGracePeriod)
+	if req.RequireActiveVersionGracePeriodMillis != nil {
+		if *req.RequireActiveVersionGracePeriodMillis < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "require_active_version_grace_period_ms", Detail: "Must be a positive integer."})
+		}
+		requireActiveVersionGracePeriod = time.Duration(*req.RequireActiveVersionGracePeriodMillis) * time.Millisecond
+	}
+	maxBuildDuration := time.Duration(template.MaxBuildDuration)
+	if req.MaxBuildDurationMillis != nil {
+		if *req.MaxBuildDurationMillis < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "max_build_duration_ms", Detail: "Must be a positive integer."})
+		}
+		maxBuildDuration = time.Duration(*req.MaxBuildDurationMillis) * time.Millisecond
+	}
+	provisionerMemoryLimit := template.ProvisionerMemoryLimit
+	if req.ProvisionerMemoryLimitBytes != nil {
+		if *req.ProvisionerMemoryLimitBytes < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "provisioner_memory_limit_bytes", Detail: "Must be a positive integer."})
+		}
+		provisionerMemoryLimit = *req.ProvisionerMemoryLimitBytes
+	}
+	provisionerCPULimit := time.Duration(template.ProvisionerCPULimit)
+	if req.ProvisionerCPULimitMillis != nil {
+		if *req.ProvisionerCPULimitMillis < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "provisioner_cpu_limit_ms", Detail: "Must be a positive integer."})
+		}
+		provisionerCPULimit = time.Duration(*req.ProvisionerCPULimitMillis) * time.Millisecond
+	}
+	maxConcurrentProvisionerJobs := template.MaxConcurrentProvisionerJobs
+	if req.MaxConcurrentProvisionerJobs != nil {
+		if *req.MaxConcurrentProvisionerJobs < 0 {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "max_concurrent_provisioner_jobs", Detail: "Must be a positive integer."})
+		}
+		maxConcurrentProvisionerJobs = *req.MaxConcurrentProvisionerJobs
+	}
+	maxSessionIdle := time.Duration(template.MaxSessionIdle)
+	if req.MaxSessionIdleMillis != nil {
+		maxSessionIdle = time.Duration(*req.MaxSessionIdleMillis) * time.Millisecond
+		// Users are warned before they are disconnected, which needs some
+		// time to be meaningful.
+		if maxSessionIdle < 0 || (maxSessionIdle > 0 && maxSessionIdle < time.Minute) {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "max_session_idle_ms", Detail: "Must be 0 or at least one minute."})
+		}
+	}
+	portPolicy := template.PortPolicy
+	if req.PortPolicy != nil {
+		err := req.PortPolicy.Validate()
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "port_policy", Detail: err.Error()})
+		}
+		portPolicy, err = json.Marshal(req.PortPolicy)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error encoding port policy.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+	requireWorkspaceApproval := template.RequireWorkspaceApproval
+	if req.RequireWorkspaceApproval != nil {
+		requireWorkspaceApproval = *req.RequireWorkspaceApproval
+	}
+	requireAgentBinaryVerification := template.RequireAgentBinaryVerification
+	if req.RequireAgentBinaryVerification != nil {
+		requireAgentBinaryVerification = *req.RequireAgentBinaryVerification
+	}
+	requirePromotionApproval := template.RequirePromotionApproval
+	if req.RequirePromotionApproval != nil {
+		requirePromotionApproval = *req.RequirePromotionApproval
+	}
+	autostopActivitySources := template.AutostopActivitySources
+	if req.AutostopActivitySources != nil {
+		autostopActivitySources = make([]string, 0, len(*req.AutostopActivitySources))
+		for _, source := range *req.AutostopActivitySources {
+			if !source.Valid() {
+				validErrs = append(validErrs, codersdk.ValidationError{Field: "autostop_activity_sources", Detail: fmt.Sprintf("Invalid activity source %q.", source)})
+				continue
+			}
+			autostopActivitySources = append(autostopActivitySources, string(source))
+		}
+		autostopActivitySources = slice.Unique(autostopActivitySources)
+	}
 
 	if len(validErrs) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -566,7 +657,19 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			req.RestartRequirement.Weeks == scheduleOpts.RestartRequirement.Weeks &&
 			req.FailureTTLMillis == time.Duration(template.FailureTTL).Milliseconds() &&
 			req.InactivityTTLMillis == time.Duration(template.InactivityTTL).Milliseconds() &&
-			req.LockedTTLMillis == time.Duration(template.LockedTTL).Milliseconds() {
+			req.LockedTTLMillis == time.Duration(template.LockedTTL).Milliseconds() &&
+			requireActiveVersion == template.RequireActiveVersion &&
+			requireActiveVersionGracePeriod == time.Duration(template.RequireActiveVersionGracePeriod) &&
+			maxBuildDuration == time.Duration(template.MaxBuildDuration) &&
+			provisionerMemoryLimit == template.ProvisionerMemoryLimit &&
+			provisionerCPULimit == time.Duration(template.ProvisionerCPULimit) &&
+			maxConcurrentProvisionerJobs == template.MaxConcurrentProvisionerJobs &&
+			maxSessionIdle == time.Duration(template.MaxSessionIdle) &&
+			req.PortPolicy == nil &&
+			requireWorkspaceApproval == template.RequireWorkspaceApproval &&
+			requireAgentBinaryVerification == template.RequireAgentBinaryVerification &&
+			requirePromotionApproval == template.RequirePromotionApproval &&
+			slice.SameElements(autostopActivitySources, template.AutostopActivitySources) {
 			return nil
 		}
 
@@ -578,13 +681,24 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 
 		var err error
 		err = tx.UpdateTemplateMetaByID(ctx, database.UpdateTemplateMetaByIDParams{
-			ID:                           template.ID,
-			UpdatedAt:                    database.Now(),
-			Name:                         name,
-			DisplayName:                  req.DisplayName,
-			Description:                  req.Description,
-			Icon:                         req.Icon,
-			AllowUserCancelWorkspaceJobs: req.AllowUserCancelWorkspaceJobs,
+			ID:                              template.ID,
+			UpdatedAt:                       database.Now(),
+			Name:                            name,
+			DisplayName:                     req.DisplayName,
+			Description:                     req.Description,
+			Icon:                            req.Icon,
+			AllowUserCancelWorkspaceJobs:    req.AllowUserCancelWorkspaceJobs,
+			RequireActiveVersion:            requireActiveVersion,
+			RequireActiveVersionGracePeriod: int64(requireActiveVersionGracePeriod),
+			MaxBuildDuration:                int64(maxBuildDuration),
+			ProvisionerMemoryLimit:          provisionerMemoryLimit,
+			ProvisionerCPULimit:             int64(provisionerCPULimit),
+			RequireWorkspaceApproval:        requireWorkspaceApproval,
+			RequireAgentBinaryVerification:  requireAgentBinaryVerification,
+			RequirePromotionApproval:        requirePromotionApproval,
+			MaxConcurrentProvisionerJobs:    maxConcurrentProvisionerJobs,
+			MaxSessionIdle:                  int64(maxSessionIdle),
+			PortPolicy:                      portPolicy,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template metadata: %w", err)
@@ -609,7 +723,8 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 			inactivityTTL != time.Duration(template.InactivityTTL) ||
 			lockedTTL != time.Duration(template.LockedTTL) ||
 			req.AllowUserAutostart != template.AllowUserAutostart ||
-			req.AllowUserAutostop != template.AllowUserAutostop {
+			req.AllowUserAutostop != template.AllowUserAutostop ||
+			!slice.SameElements(autostopActivitySources, template.AutostopActivitySources) {
 			updated, err = (*api.TemplateScheduleStore.Load()).Set(ctx, tx, updated, schedule.TemplateScheduleOptions{
 				// Some of these values are enterprise-only, but the
 				// TemplateScheduleStore will handle avoiding setting them if
@@ -625,6 +740,7 @@ func (api *API) patchTemplateMeta(rw http.ResponseWriter, r *http.Request) {
 				FailureTTL:                failureTTL,
 				InactivityTTL:             inactivityTTL,
 				LockedTTL:                 lockedTTL,
+				AutostopActivitySources:   autostopActivitySources,
 				UpdateWorkspaceLastUsedAt: req.UpdateWorkspaceLastUsedAt,
 				UpdateWorkspaceLockedAt:   req.UpdateWorkspaceLockedAt,
 			})
@@ -684,6 +800,61 @@ func (api *API) templateDAUs(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, resp)
 }
 
+// @Summary Notify owners of outdated workspaces by template ID
+// @ID notify-owners-of-outdated-workspaces-by-template-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Success 200 {object} codersdk.NotifyOutdatedWorkspacesResponse
+// @Router /templates/{template}/notify-outdated [post]
+func (api *API) postNotifyOutdatedWorkspaces(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+	)
+
+	// Owners are notified after a version is promoted, so those who may
+	// promote versions may notify them.
+	if !api.Authorize(r, rbac.ActionPushVersion, template) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	workspaceIDs, err := api.notifyOutdatedWorkspaces(ctx, template.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error notifying outdated workspaces.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.NotifyOutdatedWorkspacesResponse{
+		WorkspaceIDs: workspaceIDs,
+	})
+}
+
+// notifyOutdatedWorkspaces publishes an update for every workspace of the
+// template that isn't built with its active version, so that watching
+// clients show the workspace as outdated. The IDs of the notified workspaces
+// are returned.
+func (api *API) notifyOutdatedWorkspaces(ctx context.Context, templateID uuid.UUID) ([]uuid.UUID, error) {
+	// The caller may not be able to read every workspace of the template, but
+	// all of their owners must be notified.
+	//nolint:gocritic // System needs to find the outdated workspaces of all users.
+	workspaces, err := api.Database.GetOutdatedWorkspacesByTemplateID(dbauthz.AsSystemRestricted(ctx), templateID)
+	if err != nil {
+		return nil, xerrors.Errorf("get outdated workspaces: %w", err)
+	}
+	workspaceIDs := make([]uuid.UUID, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		api.publishWorkspaceUpdate(ctx, workspace.ID)
+		workspaceIDs = append(workspaceIDs, workspace.ID)
+	}
+	return workspaceIDs, nil
+}
+
 // @Summary Get template examples by organization
 // @ID get-template-examples-by-organization
 // @Security CoderSessionToken
@@ -737,6 +908,14 @@ func (api *API) convertTemplate(
 
 	buildTimeStats := api.metricsCache.TemplateBuildTimeStats(template.ID)
 
+	autostopActivitySources := make([]codersdk.AgentActivitySource, 0, len(template.AutostopActivitySources))
+	for _, source := range template.AutostopActivitySources {
+		autostopActivitySources = append(autostopActivitySources, codersdk.AgentActivitySource(source))
+	}
+
+	// The port policy was validated when it was written.
+	portPolicy, _ := db2sdk.PortPolicy(template.PortPolicy)
+
 	return codersdk.Template{
 		ID:                           template.ID,
 		CreatedAt:                    template.CreatedAt,
@@ -764,5 +943,17 @@ func (api *API) convertTemplate(
 			DaysOfWeek: codersdk.BitmapToWeekdays(uint8(template.RestartRequirementDaysOfWeek)),
 			Weeks:      template.RestartRequirementWeeks,
 		},
+		RequireActiveVersion:                  template.RequireActiveVersion,
+		RequireActiveVersionGracePeriodMillis: time.Duration(template.RequireActiveVersionGracePeriod).Milliseconds(),
+		MaxBuildDurationMillis:                time.Duration(template.MaxBuildDuration).Milliseconds(),
+		ProvisionerMemoryLimitBytes:           template.ProvisionerMemoryLimit,
+		ProvisionerCPULimitMillis:             time.Duration(template.ProvisionerCPULimit).Milliseconds(),
+		MaxConcurrentProvisionerJobs:          template.MaxConcurrentProvisionerJobs,
+		MaxSessionIdleMillis:                  time.Duration(template.MaxSessionIdle).Milliseconds(),
+		PortPolicy:                            portPolicy,
+		RequireWorkspaceApproval:              template.RequireWorkspaceApproval,
+		RequireAgentBinaryVerification:        template.RequireAgentBinaryVerification,
+		RequirePromotionApproval:              template.RequirePromotionApproval,
+		AutostopActivitySources:               autostopActivitySources,
 	}
 }
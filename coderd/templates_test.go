@@ -514,6 +514,157 @@ func TestPatchTemplateMeta(t *testing.T) {
 		assert.Equal(t, database.AuditActionWrite, auditor.AuditLogs()[4].Action)
 	})
 
+	t.Run("ProvisionerLimits", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Zero(t, template.MaxBuildDurationMillis)
+		require.Zero(t, template.ProvisionerMemoryLimitBytes)
+		require.Zero(t, template.ProvisionerCPULimitMillis)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxBuildDurationMillis:      ptr.Ref(time.Hour.Milliseconds()),
+			ProvisionerMemoryLimitBytes: ptr.Ref(int64(1 << 30)),
+			ProvisionerCPULimitMillis:   ptr.Ref(10 * time.Minute.Milliseconds()),
+		})
+		require.NoError(t, err)
+		require.Equal(t, time.Hour.Milliseconds(), updated.MaxBuildDurationMillis)
+		require.EqualValues(t, 1<<30, updated.ProvisionerMemoryLimitBytes)
+		require.Equal(t, 10*time.Minute.Milliseconds(), updated.ProvisionerCPULimitMillis)
+
+		// Omitted limits are left unchanged.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxBuildDurationMillis: ptr.Ref(int64(0)),
+		})
+		require.NoError(t, err)
+		require.Zero(t, updated.MaxBuildDurationMillis)
+		require.EqualValues(t, 1<<30, updated.ProvisionerMemoryLimitBytes)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			ProvisionerMemoryLimitBytes: ptr.Ref(int64(-1)),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MaxConcurrentProvisionerJobs", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Zero(t, template.MaxConcurrentProvisionerJobs)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxConcurrentProvisionerJobs: ptr.Ref(int32(2)),
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, updated.MaxConcurrentProvisionerJobs)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxConcurrentProvisionerJobs: ptr.Ref(int32(-1)),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MaxSessionIdle", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Zero(t, template.MaxSessionIdleMillis)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxSessionIdleMillis: ptr.Ref((15 * time.Minute).Milliseconds()),
+		})
+		require.NoError(t, err)
+		require.Equal(t, (15 * time.Minute).Milliseconds(), updated.MaxSessionIdleMillis)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxSessionIdleMillis: ptr.Ref((30 * time.Second).Milliseconds()),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			MaxSessionIdleMillis: ptr.Ref(int64(0)),
+		})
+		require.NoError(t, err)
+		require.Zero(t, updated.MaxSessionIdleMillis)
+	})
+
+	t.Run("PortPolicy", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Empty(t, template.PortPolicy.Rules)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		policy := codersdk.PortPolicy{
+			Default: codersdk.PortPolicyActionDeny,
+			Rules: []codersdk.PortPolicyRule{{
+				Ports:           "8000-8999",
+				Action:          codersdk.PortPolicyActionAllow,
+				MaxSharingLevel: codersdk.WorkspaceAppSharingLevelAuthenticated,
+			}},
+		}
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			PortPolicy: &policy,
+		})
+		require.NoError(t, err)
+		require.Equal(t, policy, updated.PortPolicy)
+
+		// Other updates keep the policy.
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "allowlisted",
+		})
+		require.NoError(t, err)
+		require.Equal(t, policy, updated.PortPolicy)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			PortPolicy: &codersdk.PortPolicy{
+				Rules: []codersdk.PortPolicyRule{{
+					Ports:  "9000-8000",
+					Action: codersdk.PortPolicyActionAllow,
+				}},
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Equal(t, "port_policy", apiErr.Validations[0].Field)
+
+		updated, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			PortPolicy: &codersdk.PortPolicy{},
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.PortPolicy{}, updated.PortPolicy)
+	})
+
 	t.Run("NoDefaultTTL", func(t *testing.T) {
 		t.Parallel()
 
@@ -1188,6 +1339,57 @@ func TestDeleteTemplate(t *testing.T) {
 	})
 }
 
+func TestNotifyOutdatedWorkspaces(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		workspace := coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		res, err := client.NotifyOutdatedWorkspaces(ctx, template.ID)
+		require.NoError(t, err)
+		require.Empty(t, res.WorkspaceIDs)
+
+		newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+		err = client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: newVersion.ID,
+		})
+		require.NoError(t, err)
+
+		res, err = client.NotifyOutdatedWorkspaces(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{workspace.ID}, res.WorkspaceIDs)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := memberClient.NotifyOutdatedWorkspaces(ctx, template.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
 func TestTemplateMetrics(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,341 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Promote template version
+// @Description Requests the template version to become the active version of
+// @Description the template. The version becomes active once a template
+// @Description approver approves the promotion.
+// @ID promote-template-version
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param template path string true "Template ID" format(uuid)
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param request body codersdk.CreateTemplateVersionPromotionRequest true "Create template version promotion request"
+// @Success 201 {object} codersdk.TemplateVersionPromotion
+// @Router /templates/{template}/versions/{templateversion}/promote [post]
+func (api *API) postTemplateVersionPromotion(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		template          = httpmw.TemplateParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateVersionPromotion](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	versionID, ok := httpmw.ParseUUIDParam(rw, r, "templateversion")
+	if !ok {
+		return
+	}
+
+	var req codersdk.CreateTemplateVersionPromotionRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	version, err := api.Database.GetTemplateVersionByID(ctx, versionID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "Template version not found.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if version.TemplateID.UUID != template.ID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The provided template version doesn't belong to the specified template.",
+		})
+		return
+	}
+	if version.ID == template.ActiveVersionID {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The template version is already active.",
+		})
+		return
+	}
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, version.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if db2sdk.ProvisionerJobStatus(job) != codersdk.ProvisionerJobSucceeded {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Only template versions that were imported successfully can be promoted.",
+		})
+		return
+	}
+
+	promotion, err := api.Database.InsertTemplateVersionPromotion(ctx, database.InsertTemplateVersionPromotionParams{
+		ID:                uuid.New(),
+		OrganizationID:    template.OrganizationID,
+		TemplateID:        template.ID,
+		TemplateVersionID: version.ID,
+		RequestedBy:       apiKey.UserID,
+		CreatedAt:         database.Now(),
+		Message:           req.Message,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The template version already has a pending promotion.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating template version promotion.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = promotion
+
+	api.PlatformEvents.TemplateVersionPromotion(ctx, codersdk.PlatformEventTypeTemplateVersionPromotionRequested, promotion)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertTemplateVersionPromotion(promotion))
+}
+
+// @Summary Get template version promotions
+// @Description Returns the template version promotions visible to the user,
+// @Description newest first. Pass status=pending to list the promotions
+// @Description awaiting approval.
+// @ID get-template-version-promotions
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Templates
+// @Param template_id query string false "Filter by template ID" format(uuid)
+// @Param status query string false "Filter by status" Enums(pending,approved,rejected)
+// @Success 200 {array} codersdk.TemplateVersionPromotion
+// @Router /templateversionpromotions [get]
+func (api *API) templateVersionPromotions(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		status = r.URL.Query().Get("status")
+	)
+
+	if status != "" && !database.TemplateVersionPromotionStatus(status).Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid status %q.", status),
+			Validations: []codersdk.ValidationError{
+				{Field: "status", Detail: "Must be one of pending, approved or rejected."},
+			},
+		})
+		return
+	}
+	var templateID uuid.UUID
+	if raw := r.URL.Query().Get("template_id"); raw != "" {
+		var err error
+		templateID, err = uuid.Parse(raw)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: fmt.Sprintf("Invalid template ID %q.", raw),
+				Validations: []codersdk.ValidationError{
+					{Field: "template_id", Detail: "Must be a valid UUID."},
+				},
+			})
+			return
+		}
+	}
+
+	promotions, err := api.Database.GetTemplateVersionPromotions(ctx, database.GetTemplateVersionPromotionsParams{
+		TemplateID: templateID,
+		Status:     status,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version promotions.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiPromotions := make([]codersdk.TemplateVersionPromotion, 0, len(promotions))
+	for _, promotion := range promotions {
+		apiPromotions = append(apiPromotions, convertTemplateVersionPromotion(promotion))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiPromotions)
+}
+
+// @Summary Decide template version promotion
+// @Description Approves or rejects a pending promotion. Approving makes the
+// @Description template version the active version of the template. Only
+// @Description template approvers can decide promotions, and users can't
+// @Description decide their own.
+// @ID decide-template-version-promotion
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param templateversionpromotion path string true "Template version promotion ID" format(uuid)
+// @Param request body codersdk.DecideTemplateVersionPromotionRequest true "Decide template version promotion request"
+// @Success 200 {object} codersdk.TemplateVersionPromotion
+// @Router /templateversionpromotions/{templateversionpromotion}/decision [post]
+func (api *API) postTemplateVersionPromotionDecision(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateVersionPromotion](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	promotionID, ok := httpmw.ParseUUIDParam(rw, r, "templateversionpromotion")
+	if !ok {
+		return
+	}
+
+	var req codersdk.DecideTemplateVersionPromotionRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	promotion, err := api.Database.GetTemplateVersionPromotionByID(ctx, promotionID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template version promotion.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = promotion
+
+	// Promotions exist so a second person signs off on every version, so
+	// requesters can't approve their own, even as owners.
+	if promotion.RequestedBy == apiKey.UserID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can't decide your own promotion.",
+		})
+		return
+	}
+	if promotion.Status != database.TemplateVersionPromotionStatusPending {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The promotion has already been decided.",
+		})
+		return
+	}
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		promotion, err = tx.UpdateTemplateVersionPromotionStatusByID(ctx, database.UpdateTemplateVersionPromotionStatusByIDParams{
+			ID:        promotion.ID,
+			Status:    database.TemplateVersionPromotionStatus(req.Status),
+			DecidedBy: uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+			DecidedAt: sql.NullTime{Time: database.Now(), Valid: true},
+			Reason:    req.Reason,
+		})
+		if err != nil {
+			return xerrors.Errorf("update template version promotion: %w", err)
+		}
+		if promotion.Status != database.TemplateVersionPromotionStatusApproved {
+			return nil
+		}
+		//nolint:gocritic // Approvers activate versions of templates they
+		// aren't otherwise allowed to push to.
+		err = tx.UpdateTemplateActiveVersionByID(dbauthz.AsTemplatePromoter(ctx), database.UpdateTemplateActiveVersionByIDParams{
+			ID:              promotion.TemplateID,
+			ActiveVersionID: promotion.TemplateVersionID,
+			UpdatedAt:       database.Now(),
+		})
+		if err != nil {
+			return xerrors.Errorf("update active version: %w", err)
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The promotion has already been decided.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deciding template version promotion.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = promotion
+
+	if promotion.Status == database.TemplateVersionPromotionStatusApproved {
+		api.publishTemplateUpdate(ctx, promotion.TemplateID)
+		_, err = api.notifyOutdatedWorkspaces(ctx, promotion.TemplateID)
+		if err != nil {
+			api.Logger.Warn(ctx, "failed to notify outdated workspaces",
+				slog.F("template_id", promotion.TemplateID), slog.Error(err))
+		}
+	}
+	api.PlatformEvents.TemplateVersionPromotion(ctx, codersdk.PlatformEventTypeTemplateVersionPromotionDecided, promotion)
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateVersionPromotion(promotion))
+}
+
+func convertTemplateVersionPromotion(promotion database.TemplateVersionPromotion) codersdk.TemplateVersionPromotion {
+	apiPromotion := codersdk.TemplateVersionPromotion{
+		ID:                promotion.ID,
+		TemplateID:        promotion.TemplateID,
+		TemplateVersionID: promotion.TemplateVersionID,
+		RequestedBy:       promotion.RequestedBy,
+		CreatedAt:         promotion.CreatedAt,
+		Message:           promotion.Message,
+		Status:            codersdk.TemplateVersionPromotionStatus(promotion.Status),
+		Reason:            promotion.Reason,
+	}
+	if promotion.DecidedBy.Valid {
+		apiPromotion.DecidedBy = &promotion.DecidedBy.UUID
+	}
+	if promotion.DecidedAt.Valid {
+		apiPromotion.DecidedAt = &promotion.DecidedAt.Time
+	}
+	return apiPromotion
+}
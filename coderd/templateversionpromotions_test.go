@@ -0,0 +1,183 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateVersionPromotions(t *testing.T) {
+	t.Parallel()
+
+	// setup creates a template that requires promotions to be approved, and
+	// a new version of it pushed by a template admin.
+	setup := func(t *testing.T) (adminClient, approverClient *codersdk.Client, template codersdk.Template, version codersdk.TemplateVersion, auditor *audit.MockAuditor) {
+		auditor = audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, Auditor: auditor})
+		user := coderdtest.CreateFirstUser(t, client)
+		adminClient, _ = coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleTemplateAdmin())
+		approverClient, _ = coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleTemplateApprover())
+		initial := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, initial.ID)
+		template = coderdtest.CreateTemplate(t, client, user.OrganizationID, initial.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			RequirePromotionApproval: ptr.Ref(true),
+		})
+		require.NoError(t, err)
+		require.True(t, template.RequirePromotionApproval)
+
+		version = coderdtest.UpdateTemplateVersion(t, adminClient, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, adminClient, version.ID)
+		return adminClient, approverClient, template, version, auditor
+	}
+
+	t.Run("Approve", func(t *testing.T) {
+		t.Parallel()
+		adminClient, approverClient, template, version, auditor := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The version can't be activated directly.
+		err := adminClient.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: version.ID,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		promotion, err := adminClient.PromoteTemplateVersion(ctx, template.ID, version.ID, codersdk.CreateTemplateVersionPromotionRequest{
+			Message: "Bump the base image.",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionPromotionStatusPending, promotion.Status)
+		require.Equal(t, version.ID, promotion.TemplateVersionID)
+		require.True(t, hasPromotionAuditLog(auditor, promotion.ID, database.AuditActionCreate))
+
+		// A version can only have one pending promotion.
+		_, err = adminClient.PromoteTemplateVersion(ctx, template.ID, version.ID, codersdk.CreateTemplateVersionPromotionRequest{})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		pending, err := approverClient.TemplateVersionPromotions(ctx, codersdk.TemplateVersionPromotionFilter{
+			Status: codersdk.TemplateVersionPromotionStatusPending,
+		})
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, promotion.ID, pending[0].ID)
+
+		// Template admins can request promotions, but not decide them.
+		_, err = adminClient.DecideTemplateVersionPromotion(ctx, promotion.ID, codersdk.DecideTemplateVersionPromotionRequest{
+			Status: codersdk.TemplateVersionPromotionStatusApproved,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		promotion, err = approverClient.DecideTemplateVersionPromotion(ctx, promotion.ID, codersdk.DecideTemplateVersionPromotionRequest{
+			Status: codersdk.TemplateVersionPromotionStatusApproved,
+			Reason: "CHG0012346",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionPromotionStatusApproved, promotion.Status)
+		require.Equal(t, "CHG0012346", promotion.Reason)
+		require.NotNil(t, promotion.DecidedBy)
+		require.NotNil(t, promotion.DecidedAt)
+		require.True(t, hasPromotionAuditLog(auditor, promotion.ID, database.AuditActionWrite))
+
+		template, err = adminClient.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, version.ID, template.ActiveVersionID)
+
+		// Promotions can only be decided once.
+		_, err = approverClient.DecideTemplateVersionPromotion(ctx, promotion.ID, codersdk.DecideTemplateVersionPromotionRequest{
+			Status: codersdk.TemplateVersionPromotionStatusRejected,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		pending, err = approverClient.TemplateVersionPromotions(ctx, codersdk.TemplateVersionPromotionFilter{
+			Status: codersdk.TemplateVersionPromotionStatusPending,
+		})
+		require.NoError(t, err)
+		require.Empty(t, pending)
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		t.Parallel()
+		adminClient, approverClient, template, version, _ := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		promotion, err := adminClient.PromoteTemplateVersion(ctx, template.ID, version.ID, codersdk.CreateTemplateVersionPromotionRequest{})
+		require.NoError(t, err)
+		promotion, err = approverClient.DecideTemplateVersionPromotion(ctx, promotion.ID, codersdk.DecideTemplateVersionPromotionRequest{
+			Status: codersdk.TemplateVersionPromotionStatusRejected,
+			Reason: "No change ticket",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateVersionPromotionStatusRejected, promotion.Status)
+
+		updated, err := adminClient.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.Equal(t, template.ActiveVersionID, updated.ActiveVersionID)
+
+		// Rejected versions can be promoted again.
+		_, err = adminClient.PromoteTemplateVersion(ctx, template.ID, version.ID, codersdk.CreateTemplateVersionPromotionRequest{})
+		require.NoError(t, err)
+	})
+
+	t.Run("OwnPromotion", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		initial := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, initial.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, initial.ID)
+		version := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Owners can decide promotions, but not their own.
+		promotion, err := client.PromoteTemplateVersion(ctx, template.ID, version.ID, codersdk.CreateTemplateVersionPromotionRequest{})
+		require.NoError(t, err)
+		_, err = client.DecideTemplateVersionPromotion(ctx, promotion.ID, codersdk.DecideTemplateVersionPromotionRequest{
+			Status: codersdk.TemplateVersionPromotionStatusApproved,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("ActiveVersion", func(t *testing.T) {
+		t.Parallel()
+		adminClient, _, template, _, _ := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := adminClient.PromoteTemplateVersion(ctx, template.ID, template.ActiveVersionID, codersdk.CreateTemplateVersionPromotionRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func hasPromotionAuditLog(auditor *audit.MockAuditor, id uuid.UUID, action database.AuditAction) bool {
+	for _, alog := range auditor.AuditLogs() {
+		if alog.ResourceType == database.ResourceTypeTemplateVersionPromotion && alog.ResourceID == id && alog.Action == action {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package coderd
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"database/sql"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/gitauth"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
@@ -29,6 +31,7 @@ import (
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/examples"
+	"github.com/coder/coder/v2/provisioner/terraform/tfvalidate"
 	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
 )
 
@@ -268,6 +271,20 @@ func (api *API) templateVersionRichParameters(rw http.ResponseWriter, r *http.Re
 		})
 		return
 	}
+	for i, dbParameter := range dbTemplateVersionParameters {
+		if dbParameter.OptionsURL == "" {
+			continue
+		}
+		options, err := api.parameterOptions.Fetch(ctx, dbParameter.OptionsURL)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: fmt.Sprintf("Internal error fetching the options of parameter %q.", dbParameter.Name),
+				Detail:  err.Error(),
+			})
+			return
+		}
+		templateVersionParameters[i].Options = options
+	}
 	httpapi.Write(ctx, rw, http.StatusOK, templateVersionParameters)
 }
 
@@ -501,6 +518,11 @@ func (api *API) postTemplateVersionDryRun(rw http.ResponseWriter, r *http.Reques
 		})
 		return
 	}
+	err = provisionerdserver.PostJob(api.Pubsub, provisionerJob)
+	if err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
 
 	httpapi.Write(ctx, rw, http.StatusCreated, convertProvisionerJob(database.GetProvisionerJobsByIDsWithQueuePositionRow{
 		ProvisionerJob: provisionerJob,
@@ -1031,6 +1053,13 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 		})
 		return
 	}
+	if template.RequirePromotionApproval {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "The template requires new versions to be approved before they become active.",
+			Detail:  fmt.Sprintf("Request a promotion with POST /api/v2/templates/%s/versions/%s/promote instead.", template.ID, version.ID),
+		})
+		return
+	}
 
 	err = api.Database.InTx(func(store database.Store) error {
 		err = store.UpdateTemplateActiveVersionByID(ctx, database.UpdateTemplateActiveVersionByIDParams{
@@ -1055,6 +1084,11 @@ func (api *API) patchActiveTemplateVersion(rw http.ResponseWriter, r *http.Reque
 	aReq.New = newTemplate
 
 	api.publishTemplateUpdate(ctx, template.ID)
+	_, err = api.notifyOutdatedWorkspaces(ctx, template.ID)
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to notify outdated workspaces",
+			slog.F("template_id", template.ID), slog.Error(err))
+	}
 
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
 		Message: "Updated the active template version!",
@@ -1113,6 +1147,41 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 
 	// Ensures the "owner" is properly applied.
 	tags := provisionerdserver.MutateTags(apiKey.UserID, req.ProvisionerTags)
+	err := provisionerdserver.ResolveRegionTag(ctx, api.Database, tags)
+	if errors.Is(err, provisionerdserver.ErrUnknownRegion) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The %q tag must be the name or ID of a workspace proxy.", provisionerdserver.TagRegion),
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error resolving provisioner tags.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Variables may refer to secrets, which are only read by builds. Catch
+	// references to secret managers that aren't configured early.
+	var validErrs []codersdk.ValidationError
+	for _, variableValue := range req.UserVariableValues {
+		err := api.SecretManager.Validate(variableValue.Value)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "user_variable_values",
+				Detail: fmt.Sprintf("Variable %q: %s", variableValue.Name, err),
+			})
+		}
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Template variables refer to secrets that can't be read.",
+			Validations: validErrs,
+		})
+		return
+	}
 
 	if req.ExampleID != "" && req.FileID != uuid.Nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -1122,7 +1191,6 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 	}
 
 	var file database.File
-	var err error
 	// if example id is specified we need to copy the embedded tar into a new file in the database
 	if req.ExampleID != "" {
 		if !api.Authorize(r, rbac.ActionCreate, rbac.ResourceFile.WithOwner(apiKey.UserID.String())) {
@@ -1288,6 +1356,11 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 		return
 	}
 	aReq.New = templateVersion
+	err = provisionerdserver.PostJob(api.Pubsub, provisionerJob)
+	if err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
 
 	httpapi.Write(ctx, rw, http.StatusCreated, convertTemplateVersion(templateVersion, convertProvisionerJob(database.GetProvisionerJobsByIDsWithQueuePositionRow{
 		ProvisionerJob: provisionerJob,
@@ -1295,6 +1368,77 @@ func (api *API) postTemplateVersionsByOrganization(rw http.ResponseWriter, r *ht
 	}), nil))
 }
 
+// postValidateTemplateVersion statically checks an uploaded template archive
+// for errors without creating a template version. Terraform isn't run, so
+// problems that depend on provider schemas or variable values are only found
+// when the version is imported.
+//
+// @Summary Validate template version archive
+// @ID validate-template-version-archive
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param request body codersdk.ValidateTemplateVersionRequest true "Validate template version request"
+// @Success 200 {object} codersdk.ValidateTemplateVersionResponse
+// @Router /templateversions/validate [post]
+func (api *API) postValidateTemplateVersion(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req codersdk.ValidateTemplateVersionRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	file, err := api.Database.GetFileByID(ctx, req.FileID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "File not found.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching file.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if file.Mimetype != tarMimeType {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Only %q files can be validated.", tarMimeType),
+		})
+		return
+	}
+
+	diags, err := tfvalidate.Archive(bytes.NewReader(file.Data))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid template archive.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	resp := codersdk.ValidateTemplateVersionResponse{
+		Valid:       true,
+		Diagnostics: make([]codersdk.TemplateVersionDiagnostic, 0, len(diags)),
+	}
+	for _, diag := range diags {
+		if diag.Severity == tfvalidate.SeverityError {
+			resp.Valid = false
+		}
+		resp.Diagnostics = append(resp.Diagnostics, codersdk.TemplateVersionDiagnostic{
+			Severity: codersdk.TemplateVersionDiagnosticSeverity(diag.Severity),
+			Summary:  diag.Summary,
+			Detail:   diag.Detail,
+			Filename: diag.Filename,
+			Line:     diag.Line,
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, resp)
+}
+
 // templateVersionResources returns the workspace agent resources associated
 // with a template version. A template can specify more than one resource to be
 // provisioned, each resource can have an agent that dials back to coderd. The
@@ -1326,6 +1470,102 @@ func (api *API) templateVersionResources(rw http.ResponseWriter, r *http.Request
 	api.provisionerJobResources(rw, r, job)
 }
 
+// @Summary Analyze Terraform state for template version
+// @Description Maps the resources of an existing Terraform state to the
+// @Description resources of the template version, to check which resources
+// @Description a workspace created with the state adopts.
+// @ID analyze-terraform-state-for-template-version
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Templates
+// @Param templateversion path string true "Template version ID" format(uuid)
+// @Param request body codersdk.TemplateVersionStateAnalysisRequest true "Terraform state"
+// @Success 200 {object} codersdk.TemplateVersionStateAnalysis
+// @Router /templateversions/{templateversion}/state-analysis [post]
+func (api *API) postTemplateVersionStateAnalysis(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx             = r.Context()
+		templateVersion = httpmw.TemplateVersionParam(r)
+	)
+
+	// Only template managers may create workspaces with custom state, so
+	// there's no use in analyzing it for anyone else.
+	var object rbac.Objecter = rbac.ResourceTemplate.InOrg(templateVersion.OrganizationID)
+	if templateVersion.TemplateID.Valid {
+		template, err := api.Database.GetTemplateByID(ctx, templateVersion.TemplateID.UUID)
+		if httpapi.Is404Error(err) {
+			httpapi.ResourceNotFound(rw)
+			return
+		}
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		object = template
+	}
+	if !api.Authorize(r, rbac.ActionUpdate, object) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.TemplateVersionStateAnalysisRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	state, err := parseTerraformState(req.State)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid Terraform state.",
+			Validations: []codersdk.ValidationError{{Field: "state", Detail: err.Error()}},
+		})
+		return
+	}
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, templateVersion.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if !job.CompletedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Job hasn't completed!",
+		})
+		return
+	}
+
+	// nolint:gocritic // GetWorkspaceResourcesByJobID is a system function.
+	resources, err := api.Database.GetWorkspaceResourcesByJobID(dbauthz.AsSystemRestricted(ctx), job.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching job resources.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	resourceIDs := make([]uuid.UUID, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+	// nolint:gocritic // GetWorkspaceAgentsByResourceIDs is a system function.
+	agents, err := api.Database.GetWorkspaceAgentsByResourceIDs(dbauthz.AsSystemRestricted(ctx), resourceIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, analyzeTerraformState(state, resources, agents))
+}
+
 // templateVersionLogs returns the logs returned by the provisioner for the given
 // template version. These logs are only associated with the template version,
 // and not any build logs for a workspace.
@@ -1407,6 +1647,22 @@ func convertTemplateVersionParameter(param database.TemplateVersionParameter) (c
 		})
 	}
 
+	var dependencies []codersdk.TemplateVersionParameterDependency
+	if len(param.Dependencies) > 0 {
+		var protoDependencies []*sdkproto.RichParameterDependency
+		err = json.Unmarshal(param.Dependencies, &protoDependencies)
+		if err != nil {
+			return codersdk.TemplateVersionParameter{}, err
+		}
+		for _, dependency := range protoDependencies {
+			dependencies = append(dependencies, codersdk.TemplateVersionParameterDependency{
+				Parameter: dependency.Parameter,
+				Values:    dependency.Values,
+				Options:   dependency.Options,
+			})
+		}
+	}
+
 	descriptionPlaintext, err := parameter.Plaintext(param.Description)
 	if err != nil {
 		return codersdk.TemplateVersionParameter{}, err
@@ -1437,6 +1693,8 @@ func convertTemplateVersionParameter(param database.TemplateVersionParameter) (c
 		ValidationMonotonic:  codersdk.ValidationMonotonicOrder(param.ValidationMonotonic),
 		Required:             param.Required,
 		Ephemeral:            param.Ephemeral,
+		Dependencies:         dependencies,
+		DynamicOptions:       param.OptionsURL != "",
 	}, nil
 }
 
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"net/http"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
@@ -22,6 +24,7 @@ import (
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/examples"
 	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/provisionersdk"
 	"github.com/coder/coder/v2/provisionersdk/proto"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -129,6 +132,29 @@ func TestPostTemplateVersionsByOrganization(t *testing.T) {
 		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
 	})
 
+	t.Run("UnconfiguredSecretManager", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		_, err := client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			FileID:        uuid.New(),
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+			UserVariableValues: []codersdk.VariableValue{
+				{Name: "db_password", Value: "secret://vault/secret/db#password"},
+			},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+		require.Len(t, apiErr.Validations, 1)
+		require.Contains(t, apiErr.Validations[0].Detail, `secret manager "vault" isn't configured`)
+	})
+
 	t.Run("WithParameters", func(t *testing.T) {
 		t.Parallel()
 		auditor := audit.NewMock()
@@ -433,6 +459,178 @@ func TestTemplateVersionResources(t *testing.T) {
 	})
 }
 
+func TestValidateTemplateVersion(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, nil)
+	_ = coderdtest.CreateFirstUser(t, client)
+
+	upload := func(ctx context.Context, t *testing.T, content string) uuid.UUID {
+		t.Helper()
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(content), 0o600)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		err = provisionersdk.Tar(&buf, dir, 1<<20)
+		require.NoError(t, err)
+		file, err := client.Upload(ctx, codersdk.ContentTypeTar, &buf)
+		require.NoError(t, err)
+		return file.ID
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		fileID := upload(ctx, t, `
+resource "coder_agent" "main" {
+  os   = "linux"
+  arch = "amd64"
+}
+`)
+		resp, err := client.ValidateTemplateVersion(ctx, codersdk.ValidateTemplateVersionRequest{FileID: fileID})
+		require.NoError(t, err)
+		require.True(t, resp.Valid)
+		require.Empty(t, resp.Diagnostics)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		fileID := upload(ctx, t, `
+resource "coder_agent" "main" {
+  os                 = "linux"
+  arch               = "amd64"
+  login_before_ready = false
+}
+
+resource "coder_app" "code" {
+  agent_id = coder_agent.main.id
+  slug     = "code"
+  url      = "http://localhost:8080"
+  command  = "code-server"
+}
+`)
+		resp, err := client.ValidateTemplateVersion(ctx, codersdk.ValidateTemplateVersionRequest{FileID: fileID})
+		require.NoError(t, err)
+		require.False(t, resp.Valid)
+		require.Equal(t, []codersdk.TemplateVersionDiagnostic{{
+			Severity: codersdk.TemplateVersionDiagnosticSeverityWarning,
+			Summary:  "Deprecated attribute",
+			Detail:   `"login_before_ready" is deprecated on coder_agent, use "startup_script_behavior" instead.`,
+			Filename: "main.tf",
+			Line:     5,
+		}, {
+			Severity: codersdk.TemplateVersionDiagnosticSeverityError,
+			Summary:  "Conflicting app configuration",
+			Detail:   `The app "code" sets both url and command. Set url for web apps, or command for terminal apps.`,
+			Filename: "main.tf",
+			Line:     12,
+		}}, resp.Diagnostics)
+	})
+
+	t.Run("FileNotFound", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.ValidateTemplateVersion(ctx, codersdk.ValidateTemplateVersionRequest{FileID: uuid.New()})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
+func TestTemplateVersionStateAnalysis(t *testing.T) {
+	t.Parallel()
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionApply: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "dev",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id:   uuid.NewString(),
+							Name: "main",
+							Auth: &proto.Agent_Token{},
+						}},
+					}, {
+						Name: "home",
+						Type: "aws_ebs_volume",
+					}},
+				},
+			},
+		}},
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	state := []byte(`{
+		"version": 4,
+		"resources": [
+			{"mode": "managed", "type": "aws_instance", "name": "dev", "instances": [{}]},
+			{"mode": "managed", "type": "aws_s3_bucket", "name": "cache", "instances": [{}, {}]},
+			{"mode": "managed", "type": "coder_agent", "name": "main", "instances": [{}]},
+			{"mode": "data", "type": "aws_ami", "name": "ubuntu", "instances": [{}]}
+		]
+	}`)
+
+	t.Run("Analyze", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		analysis, err := client.TemplateVersionStateAnalysis(ctx, version.ID, codersdk.TemplateVersionStateAnalysisRequest{
+			State: state,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.TemplateVersionStateResource{{
+			Type:   "aws_ebs_volume",
+			Name:   "home",
+			Status: codersdk.TemplateVersionStateResourceMissing,
+		}, {
+			Type:      "aws_instance",
+			Name:      "dev",
+			Status:    codersdk.TemplateVersionStateResourceMatched,
+			Instances: 1,
+			Agents:    []string{"main"},
+		}, {
+			Type:      "aws_s3_bucket",
+			Name:      "cache",
+			Status:    codersdk.TemplateVersionStateResourceUnmanaged,
+			Instances: 2,
+		}}, analysis.Resources)
+		require.Len(t, analysis.Warnings, 3)
+	})
+
+	t.Run("InvalidState", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.TemplateVersionStateAnalysis(ctx, version.ID, codersdk.TemplateVersionStateAnalysisRequest{
+			State: []byte(`{"version":3}`),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.TemplateVersionStateAnalysis(ctx, version.ID, codersdk.TemplateVersionStateAnalysisRequest{
+			State: state,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
+
 func TestTemplateVersionLogs(t *testing.T) {
 	t.Parallel()
 	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
@@ -1473,3 +1671,63 @@ func TestTemplateVersionParameters_Order(t *testing.T) {
 	require.Equal(t, secondParameterName, templateRichParameters[3].Name)
 	require.Equal(t, thirdParameterName, templateRichParameters[4].Name)
 }
+
+func TestTemplateVersionParameters_InvalidDependencies(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Provision_Response{
+			{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Parameters: []*proto.RichParameter{
+							{
+								Name: "first_parameter",
+								Type: "string",
+								Dependencies: []*proto.RichParameterDependency{
+									{Parameter: "unknown_parameter"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		ProvisionApply: echo.ProvisionComplete,
+	})
+	version = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	require.Equal(t, codersdk.ProvisionerJobFailed, version.Job.Status)
+	require.Contains(t, version.Job.Error, "invalid parameter dependencies")
+}
+
+func TestTemplateVersionParameters_InvalidOptionsURL(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Provision_Response{
+			{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Parameters: []*proto.RichParameter{
+							{
+								Name:       "first_parameter",
+								Type:       "string",
+								OptionsUrl: "file:///etc/passwd",
+							},
+						},
+					},
+				},
+			},
+		},
+		ProvisionApply: echo.ProvisionComplete,
+	})
+	version = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	require.Equal(t, codersdk.ProvisionerJobFailed, version.Job.Status)
+	require.Contains(t, version.Job.Error, "invalid parameter options")
+}
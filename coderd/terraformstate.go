@@ -0,0 +1,127 @@
+package coderd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// terraformState is the subset of a Terraform state file that is needed to
+// map its resources to the resources of a template.
+type terraformState struct {
+	Version   int `json:"version"`
+	Resources []struct {
+		Mode      string            `json:"mode"`
+		Type      string            `json:"type"`
+		Name      string            `json:"name"`
+		Instances []json.RawMessage `json:"instances"`
+	} `json:"resources"`
+}
+
+// parseTerraformState parses a state file as written by "terraform state
+// pull". Only the format of Terraform 0.12 and later is supported.
+func parseTerraformState(raw []byte) (terraformState, error) {
+	var state terraformState
+	err := json.Unmarshal(raw, &state)
+	if err != nil {
+		return terraformState{}, xerrors.Errorf("state is not valid JSON: %w", err)
+	}
+	if state.Version != 4 {
+		return terraformState{}, xerrors.Errorf("state version %d is not supported, only version 4 written by Terraform 0.12 and later is", state.Version)
+	}
+	return state, nil
+}
+
+// analyzeTerraformState maps the managed resources of a state to the
+// resources the template creates on start. Resources are matched by their
+// type and name, regardless of the module they're in.
+func analyzeTerraformState(state terraformState, resources []database.WorkspaceResource, agents []database.WorkspaceAgent) codersdk.TemplateVersionStateAnalysis {
+	analysis := codersdk.TemplateVersionStateAnalysis{
+		Resources: []codersdk.TemplateVersionStateResource{},
+		Warnings:  []string{},
+	}
+
+	agentNames := make(map[string][]string)
+	templateResources := make(map[string]bool)
+	for _, resource := range resources {
+		if resource.Transition != database.WorkspaceTransitionStart {
+			continue
+		}
+		address := resource.Type + "." + resource.Name
+		templateResources[address] = true
+		for _, agent := range agents {
+			if agent.ResourceID == resource.ID {
+				agentNames[address] = append(agentNames[address], agent.Name)
+			}
+		}
+	}
+
+	stateResources := make(map[string]int)
+	var coderResources []string
+	for _, resource := range state.Resources {
+		if resource.Mode != "managed" {
+			continue
+		}
+		address := resource.Type + "." + resource.Name
+		if strings.HasPrefix(resource.Type, "coder_") {
+			coderResources = append(coderResources, address)
+			continue
+		}
+		stateResources[address] += len(resource.Instances)
+	}
+
+	for address, instances := range stateResources {
+		typ, name, _ := strings.Cut(address, ".")
+		status := codersdk.TemplateVersionStateResourceUnmanaged
+		if templateResources[address] {
+			status = codersdk.TemplateVersionStateResourceMatched
+		}
+		analysis.Resources = append(analysis.Resources, codersdk.TemplateVersionStateResource{
+			Type:      typ,
+			Name:      name,
+			Status:    status,
+			Instances: instances,
+			Agents:    agentNames[address],
+		})
+	}
+	for address := range templateResources {
+		if _, ok := stateResources[address]; ok {
+			continue
+		}
+		typ, name, _ := strings.Cut(address, ".")
+		analysis.Resources = append(analysis.Resources, codersdk.TemplateVersionStateResource{
+			Type:   typ,
+			Name:   name,
+			Status: codersdk.TemplateVersionStateResourceMissing,
+			Agents: agentNames[address],
+		})
+	}
+	sort.Slice(analysis.Resources, func(i, j int) bool {
+		a, b := analysis.Resources[i], analysis.Resources[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Name < b.Name
+	})
+
+	for _, resource := range analysis.Resources {
+		address := resource.Type + "." + resource.Name
+		switch {
+		case resource.Status == codersdk.TemplateVersionStateResourceUnmanaged:
+			analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("%s isn't part of the template and will be destroyed by the first build.", address))
+		case resource.Status == codersdk.TemplateVersionStateResourceMatched && len(resource.Agents) > 0:
+			analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("The agents of %s (%s) only connect if the existing resource runs their init script, otherwise it must be replaced.", address, strings.Join(resource.Agents, ", ")))
+		}
+	}
+	if len(coderResources) > 0 {
+		sort.Strings(coderResources)
+		analysis.Warnings = append(analysis.Warnings, fmt.Sprintf("The state contains the Coder resources %s. They are kept by the first build, so agents reuse the tokens of the state. Remove them from the state to issue new tokens.", strings.Join(coderResources, ", ")))
+	}
+	return analysis
+}
@@ -11,18 +11,22 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/pubsub"
+	"github.com/coder/coder/v2/coderd/platformevents"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisionersdk"
 )
 
 const (
-	// HungJobDuration is the duration of time since the last update to a job
-	// before it is considered hung.
+	// HungJobDuration is the default duration of time since the last update to
+	// a job before it is considered hung.
 	HungJobDuration = 5 * time.Minute
 
 	// HungJobExitTimeout is the duration of time that provisioners should allow
@@ -39,13 +43,36 @@ const (
 )
 
 // HungJobLogMessages are written to provisioner job logs when a job is hung and
-// terminated.
-var HungJobLogMessages = []string{
-	"",
-	"====================",
-	"Coder: Build has been detected as hung for 5 minutes and will be terminated.",
-	"====================",
-	"",
+// terminated with the default hung job duration.
+var HungJobLogMessages = hungJobLogMessages(HungJobDuration, false)
+
+// hungJobLogMessages returns the messages written to provisioner job logs when
+// a job has been hung for threshold and is terminated or requeued.
+func hungJobLogMessages(threshold time.Duration, requeue bool) []string {
+	action := "terminated"
+	if requeue {
+		action = "requeued"
+	}
+	return []string{
+		"",
+		"====================",
+		fmt.Sprintf("Coder: Build has been detected as hung for %s and will be %s.", humanDuration(threshold), action),
+		"====================",
+		"",
+	}
+}
+
+// humanDuration formats whole minutes as "N minutes" to keep the log messages
+// readable, and falls back to the default formatting otherwise.
+func humanDuration(d time.Duration) string {
+	switch {
+	case d == time.Minute:
+		return "1 minute"
+	case d > 0 && d%time.Minute == 0:
+		return fmt.Sprintf("%d minutes", d/time.Minute)
+	default:
+		return d.String()
+	}
 }
 
 // acquireLockError is returned when the detector fails to acquire a lock and
@@ -80,6 +107,11 @@ type Detector struct {
 	log    slog.Logger
 	tick   <-chan time.Time
 	stats  chan<- Stats
+
+	hungJobDuration time.Duration
+	requeueLimit    int32
+	terminatedJobs  prometheus.Counter
+	requeuedJobs    prometheus.Counter
 }
 
 // Stats contains statistics about the last run of the detector.
@@ -87,6 +119,9 @@ type Stats struct {
 	// TerminatedJobIDs contains the IDs of all jobs that were detected as hung and
 	// terminated.
 	TerminatedJobIDs []uuid.UUID
+	// RequeuedJobIDs contains the IDs of all jobs that were detected as hung and
+	// put back in the queue.
+	RequeuedJobIDs []uuid.UUID
 	// Error is the fatal error that occurred during the last run of the
 	// detector, if any. Error may be set to AcquireLockError if the detector
 	// failed to acquire a lock.
@@ -106,10 +141,49 @@ func New(ctx context.Context, db database.Store, pub pubsub.Pubsub, log slog.Log
 		log:    log,
 		tick:   tick,
 		stats:  nil,
+
+		hungJobDuration: HungJobDuration,
+		requeueLimit:    0,
+		terminatedJobs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "hang_detector",
+			Name:      "jobs_terminated_total",
+			Help:      "The number of hung provisioner jobs that were terminated.",
+		}),
+		requeuedJobs: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "hang_detector",
+			Name:      "jobs_requeued_total",
+			Help:      "The number of hung provisioner jobs put back in the queue.",
+		}),
+	}
+	return d
+}
+
+// WithHungJobDuration sets the duration of time since the last update to a job
+// before it is considered hung. Defaults to HungJobDuration.
+func (d *Detector) WithHungJobDuration(dur time.Duration) *Detector {
+	if dur > 0 {
+		d.hungJobDuration = dur
 	}
 	return d
 }
 
+// WithRequeueLimit causes hung jobs to be put back in the queue instead of
+// being terminated, up to limit times per job. Jobs that were canceled or have
+// been requeued limit times already are terminated. Defaults to 0, which
+// disables requeueing.
+func (d *Detector) WithRequeueLimit(limit int32) *Detector {
+	d.requeueLimit = limit
+	return d
+}
+
+// WithMetrics registers the metrics of the detector with reg.
+func (d *Detector) WithMetrics(reg prometheus.Registerer) *Detector {
+	reg.MustRegister(d.terminatedJobs, d.requeuedJobs)
+	return d
+}
+
 // WithStatsChannel will cause Executor to push a RunStats to ch after
 // every tick. This push is blocking, so if ch is not read, the detector will
 // hang. This should only be used in tests.
@@ -169,12 +243,13 @@ func (d *Detector) run(t time.Time) Stats {
 
 	stats := Stats{
 		TerminatedJobIDs: []uuid.UUID{},
+		RequeuedJobIDs:   []uuid.UUID{},
 		Error:            nil,
 	}
 
 	// Find all provisioner jobs that are currently running but have not
-	// received an update in the last 5 minutes.
-	jobs, err := d.db.GetHungProvisionerJobs(ctx, t.Add(-HungJobDuration))
+	// received an update within the hung job duration.
+	jobs, err := d.db.GetHungProvisionerJobs(ctx, t.Add(-d.hungJobDuration))
 	if err != nil {
 		stats.Error = xerrors.Errorf("get hung provisioner jobs: %w", err)
 		return stats
@@ -191,12 +266,12 @@ func (d *Detector) run(t time.Time) Stats {
 	}
 
 	// Send a message into the build log for each hung job saying that it
-	// has been detected and will be terminated, then mark the job as
-	// failed.
+	// has been detected and will be terminated or requeued, then mark the
+	// job as failed or put it back in the queue.
 	for _, job := range jobs {
 		log := d.log.With(slog.F("job_id", job.ID))
 
-		err := unhangJob(ctx, log, d.db, d.pubsub, job.ID)
+		requeued, err := d.unhangJob(ctx, log, job.ID)
 		if err != nil {
 			if !(xerrors.As(err, &acquireLockError{}) || xerrors.As(err, &jobInelligibleError{})) {
 				log.Error(ctx, "error forcefully terminating hung provisioner job", slog.Error(err))
@@ -204,16 +279,32 @@ func (d *Detector) run(t time.Time) Stats {
 			continue
 		}
 
+		if requeued {
+			d.requeuedJobs.Inc()
+			stats.RequeuedJobIDs = append(stats.RequeuedJobIDs, job.ID)
+			continue
+		}
+		d.terminatedJobs.Inc()
 		stats.TerminatedJobIDs = append(stats.TerminatedJobIDs, job.ID)
 	}
 
 	return stats
 }
 
-func unhangJob(ctx context.Context, log slog.Logger, db database.Store, pub pubsub.Pubsub, jobID uuid.UUID) error {
-	var lowestLogID int64
-
-	err := db.InTx(func(db database.Store) error {
+// unhangJob terminates the hung job, or puts it back in the queue if it is
+// eligible for requeueing. It returns whether the job was requeued.
+func (d *Detector) unhangJob(ctx context.Context, log slog.Logger, jobID uuid.UUID) (bool, error) {
+	var (
+		lowestLogID int64
+		requeue     bool
+		hungJob     database.ProvisionerJob
+		// hungBuild is set if the job was a workspace build that was
+		// terminated.
+		hungBuild database.WorkspaceBuild
+		jobError  string
+	)
+
+	err := d.db.InTx(func(db database.Store) error {
 		locked, err := db.TryAcquireLock(ctx, database.GenLockID(fmt.Sprintf("hang-detector:%s", jobID)))
 		if err != nil {
 			return xerrors.Errorf("acquire lock: %w", err)
@@ -242,16 +333,29 @@ func unhangJob(ctx context.Context, log slog.Logger, db database.Store, pub pubs
 				Err: xerrors.Errorf("job is completed (status %s)", db2sdk.ProvisionerJobStatus(job)),
 			}
 		}
-		if job.UpdatedAt.After(time.Now().Add(-HungJobDuration)) {
+		if job.UpdatedAt.After(time.Now().Add(-d.hungJobDuration)) {
 			return jobInelligibleError{
 				Err: xerrors.New("job has been updated recently"),
 			}
 		}
 
-		log.Warn(
-			ctx, "detected hung provisioner job, forcefully terminating",
-			"threshold", HungJobDuration,
-		)
+		hungJob = job
+
+		// Canceled jobs are never requeued since nobody wants them to
+		// run anymore.
+		requeue = !job.CanceledAt.Valid && job.RequeueCount < d.requeueLimit
+		if requeue {
+			log.Warn(
+				ctx, "detected hung provisioner job, requeueing",
+				slog.F("threshold", d.hungJobDuration),
+				slog.F("requeue_count", job.RequeueCount),
+			)
+		} else {
+			log.Warn(
+				ctx, "detected hung provisioner job, forcefully terminating",
+				slog.F("threshold", d.hungJobDuration),
+			)
+		}
 
 		// First, get the latest logs from the build so we can make sure
 		// our messages are in the latest stage.
@@ -275,7 +379,7 @@ func unhangJob(ctx context.Context, log slog.Logger, db database.Store, pub pubs
 			JobID: job.ID,
 		}
 		now := database.Now()
-		for i, msg := range HungJobLogMessages {
+		for i, msg := range hungJobLogMessages(d.hungJobDuration, requeue) {
 			// Set the created at in a way that ensures each message has
 			// a unique timestamp so they will be sorted correctly.
 			insertParams.CreatedAt = append(insertParams.CreatedAt, now.Add(time.Millisecond*time.Duration(i)))
@@ -290,8 +394,23 @@ func unhangJob(ctx context.Context, log slog.Logger, db database.Store, pub pubs
 		}
 		lowestLogID = newLogs[0].ID
 
+		// Put the job back in the queue. It keeps its logs, and another
+		// provisioner daemon will pick it up. The daemon that was running
+		// it can no longer update it.
+		if requeue {
+			err = db.UpdateProvisionerJobWithRequeueByID(ctx, database.UpdateProvisionerJobWithRequeueByIDParams{
+				ID:        job.ID,
+				UpdatedAt: database.Now(),
+			})
+			if err != nil {
+				return xerrors.Errorf("requeue job: %w", err)
+			}
+			return nil
+		}
+
 		// Mark the job as failed.
 		now = database.Now()
+		jobError = fmt.Sprintf("Coder: Build has been detected as hung for %s and has been terminated by hang detector.", humanDuration(d.hungJobDuration))
 		err = db.UpdateProvisionerJobWithCompleteByID(ctx, database.UpdateProvisionerJobWithCompleteByIDParams{
 			ID:        job.ID,
 			UpdatedAt: now,
@@ -300,7 +419,7 @@ func unhangJob(ctx context.Context, log slog.Logger, db database.Store, pub pubs
 				Valid: true,
 			},
 			Error: sql.NullString{
-				String: "Coder: Build has been detected as hung for 5 minutes and has been terminated by hang detector.",
+				String: jobError,
 				Valid:  true,
 			},
 			ErrorCode: sql.NullString{
@@ -319,6 +438,7 @@ func unhangJob(ctx context.Context, log slog.Logger, db database.Store, pub pubs
 			if err != nil {
 				return xerrors.Errorf("get workspace build for workspace build job by job id: %w", err)
 			}
+			hungBuild = build
 
 			// Only copy the provisioner state if there's no state in
 			// the current build.
@@ -349,22 +469,45 @@ func unhangJob(ctx context.Context, log slog.Logger, db database.Store, pub pubs
 		return nil
 	}, nil)
 	if err != nil {
-		return xerrors.Errorf("in tx: %w", err)
+		return false, xerrors.Errorf("in tx: %w", err)
 	}
 
 	// Publish the new log notification to pubsub. Use the lowest log ID
 	// inserted so the log stream will fetch everything after that point.
+	// Requeued jobs will produce more logs once they are picked up again.
 	data, err := json.Marshal(provisionersdk.ProvisionerJobLogsNotifyMessage{
 		CreatedAfter: lowestLogID - 1,
-		EndOfLogs:    true,
+		EndOfLogs:    !requeue,
 	})
 	if err != nil {
-		return xerrors.Errorf("marshal log notification: %w", err)
+		return false, xerrors.Errorf("marshal log notification: %w", err)
 	}
-	err = pub.Publish(provisionersdk.ProvisionerJobLogsNotifyChannel(jobID), data)
+	err = d.pubsub.Publish(provisionersdk.ProvisionerJobLogsNotifyChannel(jobID), data)
 	if err != nil {
-		return xerrors.Errorf("publish log notification: %w", err)
+		return false, xerrors.Errorf("publish log notification: %w", err)
+	}
+
+	if hungBuild.ID != uuid.Nil {
+		eventType := codersdk.PlatformEventTypeWorkspaceBuildFailed
+		if hungJob.CanceledAt.Valid {
+			eventType = codersdk.PlatformEventTypeWorkspaceBuildCanceled
+		}
+		platformevents.New(d.log, d.db, d.pubsub).WorkspaceBuild(ctx, eventType, hungBuild, jobError)
+
+		err = provisionerdserver.PublishWorkspaceBuildFinished(d.pubsub, hungBuild.WorkspaceID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// Wake up waiting provisioner daemons so the requeued job is picked up
+	// right away.
+	if requeue {
+		err = provisionerdserver.PostJob(d.pubsub, hungJob)
+		if err != nil {
+			return false, xerrors.Errorf("post requeued job: %w", err)
+		}
 	}
 
-	return nil
+	return requeue, nil
 }
@@ -591,6 +591,166 @@ func TestDetectorHungCanceledJob(t *testing.T) {
 	detector.Wait()
 }
 
+func TestDetectorRequeueHungJob(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = testutil.Context(t, testutil.WaitLong)
+		db, pubsub = dbtestutil.NewDB(t)
+		log        = slogtest.Make(t, nil)
+		tickCh     = make(chan time.Time)
+		statsCh    = make(chan unhanger.Stats)
+	)
+
+	var (
+		now       = time.Now()
+		tenMinAgo = now.Add(-time.Minute * 10)
+		org       = dbgen.Organization(t, db, database.Organization{})
+		user      = dbgen.User(t, db, database.User{})
+		file      = dbgen.File(t, db, database.File{})
+
+		// Template import job.
+		templateImportJob = dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+			CreatedAt: tenMinAgo,
+			UpdatedAt: tenMinAgo,
+			StartedAt: sql.NullTime{
+				Time:  tenMinAgo,
+				Valid: true,
+			},
+			OrganizationID: org.ID,
+			InitiatorID:    user.ID,
+			Provisioner:    database.ProvisionerTypeEcho,
+			StorageMethod:  database.ProvisionerStorageMethodFile,
+			FileID:         file.ID,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			Input:          []byte("{}"),
+		})
+
+		// Canceled jobs are never requeued.
+		canceledJob = dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+			CreatedAt: tenMinAgo,
+			CanceledAt: sql.NullTime{
+				Time:  tenMinAgo,
+				Valid: true,
+			},
+			UpdatedAt: tenMinAgo,
+			StartedAt: sql.NullTime{
+				Time:  tenMinAgo,
+				Valid: true,
+			},
+			OrganizationID: org.ID,
+			InitiatorID:    user.ID,
+			Provisioner:    database.ProvisionerTypeEcho,
+			StorageMethod:  database.ProvisionerStorageMethodFile,
+			FileID:         file.ID,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			Input:          []byte("{}"),
+		})
+	)
+
+	detector := unhanger.New(ctx, db, pubsub, log, tickCh).WithStatsChannel(statsCh).WithRequeueLimit(1)
+	detector.Start()
+	tickCh <- now
+
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{templateImportJob.ID}, stats.RequeuedJobIDs)
+	require.Equal(t, []uuid.UUID{canceledJob.ID}, stats.TerminatedJobIDs)
+
+	// Check that the job was put back in the queue.
+	job, err := db.GetProvisionerJobByID(ctx, templateImportJob.ID)
+	require.NoError(t, err)
+	require.WithinDuration(t, now, job.UpdatedAt, 30*time.Second)
+	require.False(t, job.StartedAt.Valid)
+	require.False(t, job.WorkerID.Valid)
+	require.False(t, job.CompletedAt.Valid)
+	require.False(t, job.Error.Valid)
+	require.EqualValues(t, 1, job.RequeueCount)
+
+	// Acquire the job again and let it hang once more. It has reached the
+	// requeue limit, so it is terminated this time.
+	tags, err := json.Marshal(templateImportJob.Tags)
+	require.NoError(t, err)
+	_, err = db.AcquireProvisionerJob(ctx, database.AcquireProvisionerJobParams{
+		StartedAt: sql.NullTime{
+			Time:  tenMinAgo,
+			Valid: true,
+		},
+		Types: []database.ProvisionerType{database.ProvisionerTypeEcho},
+		Tags:  tags,
+	})
+	require.NoError(t, err)
+
+	tickCh <- now
+	stats = <-statsCh
+	require.NoError(t, stats.Error)
+	require.Empty(t, stats.RequeuedJobIDs)
+	require.Equal(t, []uuid.UUID{templateImportJob.ID}, stats.TerminatedJobIDs)
+
+	job, err = db.GetProvisionerJobByID(ctx, templateImportJob.ID)
+	require.NoError(t, err)
+	require.True(t, job.CompletedAt.Valid)
+	require.True(t, job.Error.Valid)
+	require.Contains(t, job.Error.String, "Build has been detected as hung")
+
+	detector.Close()
+	detector.Wait()
+}
+
+func TestDetectorHungJobDuration(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = testutil.Context(t, testutil.WaitLong)
+		db, pubsub = dbtestutil.NewDB(t)
+		log        = slogtest.Make(t, nil)
+		tickCh     = make(chan time.Time)
+		statsCh    = make(chan unhanger.Stats)
+	)
+
+	var (
+		now       = time.Now()
+		twoMinAgo = now.Add(-time.Minute * 2)
+		org       = dbgen.Organization(t, db, database.Organization{})
+		user      = dbgen.User(t, db, database.User{})
+		file      = dbgen.File(t, db, database.File{})
+
+		// Template import job that would not be hung with the default
+		// duration.
+		templateImportJob = dbgen.ProvisionerJob(t, db, database.ProvisionerJob{
+			CreatedAt: twoMinAgo,
+			UpdatedAt: twoMinAgo,
+			StartedAt: sql.NullTime{
+				Time:  twoMinAgo,
+				Valid: true,
+			},
+			OrganizationID: org.ID,
+			InitiatorID:    user.ID,
+			Provisioner:    database.ProvisionerTypeEcho,
+			StorageMethod:  database.ProvisionerStorageMethodFile,
+			FileID:         file.ID,
+			Type:           database.ProvisionerJobTypeTemplateVersionImport,
+			Input:          []byte("{}"),
+		})
+	)
+
+	detector := unhanger.New(ctx, db, pubsub, log, tickCh).WithStatsChannel(statsCh).WithHungJobDuration(time.Minute)
+	detector.Start()
+	tickCh <- now
+
+	stats := <-statsCh
+	require.NoError(t, stats.Error)
+	require.Equal(t, []uuid.UUID{templateImportJob.ID}, stats.TerminatedJobIDs)
+
+	job, err := db.GetProvisionerJobByID(ctx, templateImportJob.ID)
+	require.NoError(t, err)
+	require.True(t, job.Error.Valid)
+	require.Contains(t, job.Error.String, "Build has been detected as hung for 1 minute")
+
+	detector.Close()
+	detector.Wait()
+}
+
 func TestDetectorPushesLogs(t *testing.T) {
 	t.Parallel()
 
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/netip"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
@@ -487,6 +489,7 @@ func (api *API) deleteUser(rw http.ResponseWriter, r *http.Request) {
 	}
 	user.Deleted = true
 	aReq.New = user
+	api.PlatformEvents.User(ctx, codersdk.PlatformEventTypeUserDeleted, user)
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
 		Message: "User has been deleted!",
 	})
@@ -708,6 +711,9 @@ func (api *API) putUserStatus(status database.UserStatus) func(rw http.ResponseW
 			return
 		}
 		aReq.New = suspendedUser
+		if suspendedUser.Status != user.Status {
+			api.PlatformEvents.User(ctx, codersdk.PlatformEventTypeUserStatusChanged, suspendedUser)
+		}
 
 		organizations, err := userOrganizationIDs(ctx, api, user)
 		if err != nil {
@@ -1055,6 +1061,89 @@ func (api *API) organizationByUserAndName(rw http.ResponseWriter, r *http.Reques
 	httpapi.Write(ctx, rw, http.StatusOK, convertOrganization(organization))
 }
 
+// userConnectionLogMaxEntries is the maximum number of connections returned
+// at once.
+const userConnectionLogMaxEntries = 100
+
+// Returns the user's own recent connections to workspace agents, so users can
+// spot unexpected use of their credentials without access to the audit log.
+//
+// @Summary Get user connection log
+// @ID get-user-connection-log
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Users
+// @Param user path string true "User ID, name, or me"
+// @Param after query string false "Only return connections established after this time" format(date-time)
+// @Param limit query int false "Maximum number of connections to return"
+// @Success 200 {array} codersdk.UserConnectionLogEntry
+// @Router /users/{user}/connection-log [get]
+func (api *API) userConnectionLog(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	var (
+		after = p.Time3339Nano(vals, time.Time{}, "after")
+		limit = p.Int(vals, userConnectionLogMaxEntries, "limit")
+	)
+	p.ErrorExcessParams(vals)
+	if limit < 1 || limit > userConnectionLogMaxEntries {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "limit",
+			Detail: fmt.Sprintf("Query param \"limit\" must be between 1 and %d", userConnectionLogMaxEntries),
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	connections, err := api.Database.GetWorkspaceAgentClientConnectionsByUserID(ctx, database.GetWorkspaceAgentClientConnectionsByUserIDParams{
+		UserID:         uuid.NullUUID{UUID: user.ID, Valid: true},
+		ConnectedAfter: after,
+		LimitOpt:       int32(limit),
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching connection log.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	entries := make([]codersdk.UserConnectionLogEntry, 0, len(connections))
+	for _, connection := range connections {
+		ip, _ := netip.AddrFromSlice(connection.IPAddress.IPNet.IP)
+		entry := codersdk.UserConnectionLogEntry{
+			ID:                     connection.ID,
+			WorkspaceID:            connection.WorkspaceID,
+			WorkspaceName:          connection.WorkspaceName,
+			WorkspaceOwnerUsername: connection.WorkspaceOwnerUsername,
+			AgentName:              connection.AgentName,
+			ClientType:             codersdk.WorkspaceAgentClientType(connection.ClientType),
+			ClientVersion:          connection.ClientVersion,
+			IP:                     ip.Unmap(),
+			ConnectedAt:            connection.ConnectedAt,
+		}
+		if connection.DisconnectedAt.Valid {
+			disconnectedAt := connection.DisconnectedAt.Time
+			entry.DisconnectedAt = &disconnectedAt
+		}
+		entries = append(entries, entry)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, entries)
+}
+
 type CreateUserRequest struct {
 	codersdk.CreateUserRequest
 	CreateOrganization bool
@@ -1149,6 +1238,7 @@ func (api *API) CreateUser(ctx context.Context, store database.Store, req Create
 		if err != nil {
 			return xerrors.Errorf("create organization member: %w", err)
 		}
+		api.PlatformEvents.WithStore(tx).User(ctx, codersdk.PlatformEventTypeUserCreated, user)
 		return nil
 	}, nil)
 }
@@ -3,6 +3,7 @@ package coderd_test
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"strings"
 	"testing"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/slices"
@@ -23,6 +25,7 @@ import (
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -1610,6 +1613,74 @@ func TestWorkspacesByUser(t *testing.T) {
 	})
 }
 
+func TestUserConnectionLog(t *testing.T) {
+	t.Parallel()
+	client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(uuid.NewString()),
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	workspace, err := client.Workspace(context.Background(), workspace.ID)
+	require.NoError(t, err)
+	agent := workspace.LatestBuild.Resources[0].Agents[0]
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	for i, userID := range []uuid.UUID{user.UserID, user.UserID, member.ID} {
+		//nolint:gocritic // Connections are recorded by the system.
+		_, err := api.Database.InsertWorkspaceAgentClientConnection(dbauthz.AsSystemRestricted(ctx), database.InsertWorkspaceAgentClientConnectionParams{
+			ID:               uuid.New(),
+			WorkspaceAgentID: agent.ID,
+			UserID:           uuid.NullUUID{UUID: userID, Valid: true},
+			ClientType:       string(codersdk.WorkspaceAgentClientTypeSSH),
+			ClientVersion:    "v1.2.3",
+			ConnectedAt:      database.Now().Add(time.Duration(i) * time.Second),
+			IPAddress: pqtype.Inet{
+				IPNet: net.IPNet{
+					IP:   net.IPv4(10, 0, 0, byte(i)),
+					Mask: net.CIDRMask(32, 32),
+				},
+				Valid: true,
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	entries, err := client.UserConnectionLog(ctx, codersdk.Me, codersdk.UserConnectionLogRequest{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "10.0.0.1", entries[0].IP.String())
+	require.Equal(t, "10.0.0.0", entries[1].IP.String())
+	require.Equal(t, workspace.ID, entries[0].WorkspaceID)
+	require.Equal(t, workspace.Name, entries[0].WorkspaceName)
+	require.Equal(t, agent.Name, entries[0].AgentName)
+	require.Equal(t, codersdk.WorkspaceAgentClientTypeSSH, entries[0].ClientType)
+
+	entries, err = client.UserConnectionLog(ctx, codersdk.Me, codersdk.UserConnectionLogRequest{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// Members see their own connections, even to other users' workspaces...
+	entries, err = memberClient.UserConnectionLog(ctx, codersdk.Me, codersdk.UserConnectionLogRequest{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, "10.0.0.2", entries[0].IP.String())
+	require.Equal(t, workspace.OwnerName, entries[0].WorkspaceOwnerUsername)
+
+	// ...but not those of other users.
+	_, err = memberClient.UserConnectionLog(ctx, user.UserID.String(), codersdk.UserConnectionLogRequest{})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+}
+
 func TestDormantUser(t *testing.T) {
 	t.Parallel()
 
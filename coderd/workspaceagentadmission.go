@@ -0,0 +1,132 @@
+package coderd
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+const (
+	// agentAdmissionMaxDelay is the longest an agent is asked to wait. Agents
+	// that would have to wait longer are asked to come back after it without
+	// a slot, so the schedule doesn't grow without bounds.
+	agentAdmissionMaxDelay = 5 * time.Minute
+	// agentAdmissionSlotExpiry is how long a slot is kept for an agent that
+	// doesn't come back. Agents admitted less than this long ago are admitted
+	// again without waiting, so an agent that fetched its manifest isn't
+	// paced a second time when it connects to the coordinator.
+	agentAdmissionSlotExpiry = time.Minute
+)
+
+// agentAdmission paces connecting agents, so thousands of agents
+// reconnecting after a restart don't fetch their manifests or connect to the
+// coordinator at once. Agents arriving faster than the admission rate are
+// assigned a slot in the future and asked to come back then. Slots are
+// jittered, so agents don't return in bursts. A nil agentAdmission admits
+// every agent.
+type agentAdmission struct {
+	mu        sync.Mutex
+	limiter   *rate.Limiter
+	slots     map[uuid.UUID]time.Time
+	admitted  map[uuid.UUID]time.Time
+	lastPrune time.Time
+}
+
+// newAgentAdmission returns an agentAdmission that admits perSecond agents a
+// second, or nil if perSecond isn't positive.
+func newAgentAdmission(perSecond int64) *agentAdmission {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &agentAdmission{
+		limiter: rate.NewLimiter(rate.Limit(perSecond), int(perSecond)),
+		slots:    map[uuid.UUID]time.Time{},
+		admitted: map[uuid.UUID]time.Time{},
+	}
+}
+
+// admit returns whether the agent may connect now. Otherwise it returns how
+// long the agent should wait before trying again.
+func (a *agentAdmission) admit(agentID uuid.UUID, now time.Time) (time.Duration, bool) {
+	if a == nil {
+		return 0, true
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if now.Sub(a.lastPrune) > agentAdmissionSlotExpiry {
+		for id, slot := range a.slots {
+			if now.Sub(slot) > agentAdmissionSlotExpiry {
+				delete(a.slots, id)
+			}
+		}
+		for id, at := range a.admitted {
+			if now.Sub(at) > agentAdmissionSlotExpiry {
+				delete(a.admitted, id)
+			}
+		}
+		a.lastPrune = now
+	}
+
+	if at, ok := a.admitted[agentID]; ok && now.Sub(at) <= agentAdmissionSlotExpiry {
+		return 0, true
+	}
+
+	if slot, ok := a.slots[agentID]; ok {
+		// The token of the slot was reserved when it was assigned, so the
+		// agent is admitted without taking another one.
+		if !now.Before(slot) {
+			delete(a.slots, agentID)
+			a.admitted[agentID] = now
+			return 0, true
+		}
+		return slot.Sub(now), false
+	}
+
+	reservation := a.limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	if delay == 0 {
+		a.admitted[agentID] = now
+		return 0, true
+	}
+	if delay > agentAdmissionMaxDelay {
+		reservation.CancelAt(now)
+		return agentAdmissionMaxDelay + agentAdmissionJitter(agentAdmissionMaxDelay), false
+	}
+	slot := now.Add(delay + agentAdmissionJitter(delay))
+	a.slots[agentID] = slot
+	return slot.Sub(now), false
+}
+
+// admitWorkspaceAgent returns whether the agent may connect now. Otherwise it
+// tells the agent when to retry and returns false.
+func (api *API) admitWorkspaceAgent(rw http.ResponseWriter, r *http.Request, agent database.WorkspaceAgent) bool {
+	wait, ok := api.agentAdmission.admit(agent.ID, time.Now())
+	if ok {
+		return true
+	}
+	rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	httpapi.Write(r.Context(), rw, http.StatusTooManyRequests, codersdk.Response{
+		Message: "Too many agents are connecting, try again later.",
+	})
+	return false
+}
+
+// agentAdmissionJitter returns a random duration of up to a tenth of delay.
+func agentAdmissionJitter(delay time.Duration) time.Duration {
+	f, err := cryptorand.Float64()
+	if err != nil {
+		return 0
+	}
+	return time.Duration(f * float64(delay/10))
+}
@@ -0,0 +1,86 @@
+package coderd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentAdmission(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		admission := newAgentAdmission(0)
+		require.Nil(t, admission)
+		_, ok := admission.admit(uuid.New(), time.Now())
+		require.True(t, ok)
+	})
+
+	t.Run("Slots", func(t *testing.T) {
+		t.Parallel()
+		admission := newAgentAdmission(2)
+		now := time.Now()
+
+		// The burst admits as many agents as the rate at once.
+		for i := 0; i < 2; i++ {
+			_, ok := admission.admit(uuid.New(), now)
+			require.True(t, ok)
+		}
+
+		late := uuid.New()
+		wait, ok := admission.admit(late, now)
+		require.False(t, ok)
+		require.GreaterOrEqual(t, wait, 500*time.Millisecond)
+		require.LessOrEqual(t, wait, 550*time.Millisecond)
+
+		// Coming back early keeps the same slot.
+		again, ok := admission.admit(late, now.Add(100*time.Millisecond))
+		require.False(t, ok)
+		require.Equal(t, wait-100*time.Millisecond, again)
+
+		// Coming back at the slot admits the agent.
+		_, ok = admission.admit(late, now.Add(wait))
+		require.True(t, ok)
+	})
+
+	t.Run("Readmitted", func(t *testing.T) {
+		t.Parallel()
+		admission := newAgentAdmission(1)
+		now := time.Now()
+
+		agentID := uuid.New()
+		_, ok := admission.admit(agentID, now)
+		require.True(t, ok)
+		_, ok = admission.admit(uuid.New(), now)
+		require.False(t, ok)
+
+		// An agent that was just admitted for its manifest isn't paced again
+		// when it connects to the coordinator.
+		_, ok = admission.admit(agentID, now.Add(time.Second))
+		require.True(t, ok)
+
+		// Agents admitted a while ago are paced like the others.
+		later := now.Add(2 * agentAdmissionSlotExpiry)
+		_, ok = admission.admit(uuid.New(), later)
+		require.True(t, ok)
+		_, ok = admission.admit(agentID, later)
+		require.False(t, ok)
+	})
+
+	t.Run("MaxDelay", func(t *testing.T) {
+		t.Parallel()
+		admission := newAgentAdmission(1)
+		now := time.Now()
+
+		for i := 0; i < int(agentAdmissionMaxDelay/time.Second)+1; i++ {
+			_, _ = admission.admit(uuid.New(), now)
+		}
+		wait, ok := admission.admit(uuid.New(), now)
+		require.False(t, ok)
+		require.GreaterOrEqual(t, wait, agentAdmissionMaxDelay)
+		require.LessOrEqual(t, wait, agentAdmissionMaxDelay+agentAdmissionMaxDelay/10)
+	})
+}
@@ -0,0 +1,184 @@
+package coderd
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+)
+
+// agentAuthTokenRotationOverlap is how long the previous auth token of an
+// agent stays valid after a rotation, so requests the agent sent with it
+// before learning the new one don't fail.
+const agentAuthTokenRotationOverlap = time.Hour
+
+// rotateWorkspaceAgentAuthToken replaces the auth token of the agent. The
+// previous token expires after the overlap. Revoking also expires all previous
+// tokens that are still valid, so none of the tokens the agent had works
+// anymore.
+func (api *API) rotateWorkspaceAgentAuthToken(ctx context.Context, agentID uuid.UUID, overlap time.Duration, revoke bool) (uuid.UUID, database.WorkspaceAgentAuthTokenRotation, error) {
+	var (
+		authToken = uuid.New()
+		rotation  database.WorkspaceAgentAuthTokenRotation
+	)
+	err := api.Database.InTx(func(tx database.Store) error {
+		agent, err := tx.GetWorkspaceAgentByID(ctx, agentID)
+		if err != nil {
+			return xerrors.Errorf("get agent: %w", err)
+		}
+		now := database.Now()
+		err = tx.UpdateWorkspaceAgentAuthTokenByID(ctx, database.UpdateWorkspaceAgentAuthTokenByIDParams{
+			ID:        agent.ID,
+			AuthToken: authToken,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return xerrors.Errorf("update auth token: %w", err)
+		}
+		if revoke {
+			err = tx.ExpireWorkspaceAgentAuthTokenRotations(ctx, database.ExpireWorkspaceAgentAuthTokenRotationsParams{
+				Now:     now,
+				AgentID: agent.ID,
+			})
+			if err != nil {
+				return xerrors.Errorf("expire previous auth tokens: %w", err)
+			}
+		}
+		rotation, err = tx.InsertWorkspaceAgentAuthTokenRotation(ctx, database.InsertWorkspaceAgentAuthTokenRotationParams{
+			ID:                         uuid.New(),
+			AgentID:                    agent.ID,
+			PreviousAuthToken:          agent.AuthToken,
+			PreviousAuthTokenExpiresAt: now.Add(overlap),
+			Revoked:                    revoke,
+			CreatedAt:                  now,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert rotation: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return uuid.Nil, database.WorkspaceAgentAuthTokenRotation{}, err
+	}
+	return authToken, rotation, nil
+}
+
+// @Summary Rotate workspace agent auth token
+// @ID rotate-workspace-agent-auth-token
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Success 201 {object} agentsdk.RotateAuthTokenResponse
+// @Router /workspaceagents/me/rotate-token [post]
+// @x-apidocgen {"skip": true}
+func (api *API) postWorkspaceAgentRotateAuthToken(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspaceAgent    = httpmw.WorkspaceAgent(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceAgentAuthTokenRotation](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	// Previous tokens are only accepted during the overlap so requests in
+	// flight succeed. Letting them rotate would let a leaked token renew
+	// itself forever.
+	if httpmw.WorkspaceAgentUsedPreviousToken(r) {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Only the current auth token of the agent can be rotated.",
+		})
+		return
+	}
+
+	workspace, err := api.Database.GetWorkspaceByAgentID(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Agents don't have an API key, the rotation is attributed to the owner
+	// of the workspace.
+	aReq.UserID = workspace.OwnerID
+
+	authToken, rotation, err := api.rotateWorkspaceAgentAuthToken(ctx, workspaceAgent.ID, agentAuthTokenRotationOverlap, false)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error rotating auth token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = rotation
+	api.Logger.Debug(ctx, "rotated workspace agent auth token", slog.F("agent_id", workspaceAgent.ID))
+
+	httpapi.Write(ctx, rw, http.StatusCreated, agentsdk.RotateAuthTokenResponse{
+		AuthToken:                  authToken,
+		PreviousAuthTokenExpiresAt: rotation.PreviousAuthTokenExpiresAt,
+	})
+}
+
+// postWorkspaceAgentRevokeAuthToken invalidates every auth token of the agent
+// when it's suspected to be compromised. The agent can't authenticate anymore,
+// so the workspace has to be rebuilt to get it running again.
+//
+// @Summary Revoke workspace agent auth token
+// @ID revoke-workspace-agent-auth-token
+// @Security CoderSessionToken
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 204
+// @Router /workspaceagents/{workspaceagent}/revoke-token [post]
+func (api *API) postWorkspaceAgentRevokeAuthToken(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspaceAgent    = httpmw.WorkspaceAgentParam(r)
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceAgentAuthTokenRotation](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	if !api.Authorize(r, rbac.ActionUpdate, workspace) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	_, rotation, err := api.rotateWorkspaceAgentAuthToken(ctx, workspaceAgent.ID, 0, true)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error revoking auth token.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = rotation
+	api.Logger.Info(ctx, "revoked workspace agent auth token",
+		slog.F("agent_id", workspaceAgent.ID),
+		slog.F("workspace_id", workspace.ID),
+	)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
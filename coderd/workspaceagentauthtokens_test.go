@@ -0,0 +1,146 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceAgentAuthTokens(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, *audit.MockAuditor, uuid.UUID, string) {
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			Auditor:                  auditor,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		return client, auditor, build.Resources[0].Agents[0].ID, authToken
+	}
+
+	requireUnauthorized := func(t *testing.T, err error) {
+		t.Helper()
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+	}
+
+	t.Run("Rotate", func(t *testing.T) {
+		t.Parallel()
+
+		client, auditor, _, authToken := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		resp, err := agentClient.RotateAuthToken(ctx)
+		require.NoError(t, err)
+		require.NotEqual(t, authToken, resp.AuthToken.String())
+		require.WithinDuration(t, time.Now().Add(time.Hour), resp.PreviousAuthTokenExpiresAt, time.Minute)
+
+		// The client switched to the new token.
+		require.Equal(t, resp.AuthToken.String(), agentClient.SDK.SessionToken())
+		_, err = agentClient.Manifest(ctx)
+		require.NoError(t, err)
+
+		// The previous token keeps working during the overlap.
+		previousClient := agentsdk.New(client.URL)
+		previousClient.SetSessionToken(authToken)
+		_, err = previousClient.Manifest(ctx)
+		require.NoError(t, err)
+
+		logs := auditor.AuditLogs()
+		require.Equal(t, database.ResourceTypeWorkspaceAgentAuthToken, logs[len(logs)-1].ResourceType)
+		require.Equal(t, database.AuditActionCreate, logs[len(logs)-1].Action)
+	})
+
+	t.Run("RotatePreviousToken", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, _, authToken := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		resp, err := agentClient.RotateAuthToken(ctx)
+		require.NoError(t, err)
+
+		// The previous token can't be used to get a new token, so a leaked
+		// token expires with the overlap.
+		previousClient := agentsdk.New(client.URL)
+		previousClient.SetSessionToken(authToken)
+		_, err = previousClient.RotateAuthToken(ctx)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+		require.Equal(t, authToken, previousClient.SDK.SessionToken())
+
+		// The current token still rotates.
+		_, err = agentClient.RotateAuthToken(ctx)
+		require.NoError(t, err)
+		require.NotEqual(t, resp.AuthToken.String(), agentClient.SDK.SessionToken())
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		t.Parallel()
+
+		client, auditor, agentID, authToken := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		_, err := agentClient.RotateAuthToken(ctx)
+		require.NoError(t, err)
+
+		err = client.RevokeWorkspaceAgentAuthToken(ctx, agentID)
+		require.NoError(t, err)
+
+		// Neither the rotated nor the previous token work anymore.
+		_, err = agentClient.Manifest(ctx)
+		requireUnauthorized(t, err)
+		previousClient := agentsdk.New(client.URL)
+		previousClient.SetSessionToken(authToken)
+		_, err = previousClient.Manifest(ctx)
+		requireUnauthorized(t, err)
+
+		logs := auditor.AuditLogs()
+		require.Equal(t, database.ResourceTypeWorkspaceAgentAuthToken, logs[len(logs)-1].ResourceType)
+	})
+
+	t.Run("RevokeOtherUser", func(t *testing.T) {
+		t.Parallel()
+
+		client, _, agentID, _ := setup(t)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		firstUser, err := client.User(ctx, codersdk.Me)
+		require.NoError(t, err)
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, firstUser.OrganizationIDs[0])
+		err = memberClient.RevokeWorkspaceAgentAuthToken(ctx, agentID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,127 @@
+package coderd
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+)
+
+const (
+	// agentDefaultConnectionTimeout decides when agents without a connection
+	// timeout are considered to never have connected.
+	agentDefaultConnectionTimeout = 10 * time.Minute
+	// agentFirstConnectCheckInterval is how often agents that never connected
+	// are looked for.
+	agentFirstConnectCheckInterval = time.Minute
+)
+
+// agentFirstConnectMetrics tracks how long agents take to connect for the
+// first time after the build that created them completed, and how many never
+// do.
+type agentFirstConnectMetrics struct {
+	firstConnect   prometheus.Histogram
+	neverConnected *prometheus.CounterVec
+}
+
+func newAgentFirstConnectMetrics() *agentFirstConnectMetrics {
+	return &agentFirstConnectMetrics{
+		firstConnect: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "coderd",
+			Subsystem: "agents",
+			Name:      "first_connect_seconds",
+			Help:      "Time from the completion of a workspace build to the first connection of its agents in seconds.",
+			Buckets:   []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800},
+		}),
+		neverConnected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "agents",
+			Name:      "never_connected_total",
+			Help:      "The number of agents that didn't connect before their connection timeout.",
+		}, []string{"template_name"}),
+	}
+}
+
+func (m *agentFirstConnectMetrics) register(registerer prometheus.Registerer) error {
+	if err := registerer.Register(m.firstConnect); err != nil {
+		return err
+	}
+	return registerer.Register(m.neverConnected)
+}
+
+// recordAgentFirstConnect records the time from the completion of the build
+// to the first connection of the agent.
+func (api *API) recordAgentFirstConnect(ctx context.Context, agent database.WorkspaceAgent, build database.WorkspaceBuild, connectedAt time.Time) {
+	//nolint:gocritic // The agent can't read its provisioner job.
+	job, err := api.Database.GetProvisionerJobByID(dbauthz.AsSystemRestricted(ctx), build.JobID)
+	if err != nil {
+		api.Logger.Warn(ctx, "get build job of first agent connection", slog.F("agent_id", agent.ID), slog.Error(err))
+		return
+	}
+	if !job.CompletedAt.Valid {
+		return
+	}
+	took := connectedAt.Sub(job.CompletedAt.Time)
+	if took < 0 {
+		took = 0
+	}
+	api.agentFirstConnectMetrics.firstConnect.Observe(took.Seconds())
+	api.Logger.Debug(ctx, "agent connected for the first time",
+		slog.F("agent_id", agent.ID),
+		slog.F("workspace_id", build.WorkspaceID),
+		slog.F("since_build_completed", took),
+	)
+}
+
+// runAgentFirstConnectMonitor warns about agents that didn't connect before
+// their connection timeout, as that usually means the startup script or image
+// of the template is broken.
+func (api *API) runAgentFirstConnectMonitor() {
+	defer close(api.agentFirstConnectMonitorDone)
+
+	//nolint:gocritic // The monitor looks at the agents of all workspaces.
+	ctx := dbauthz.AsSystemRestricted(api.ctx)
+	logger := api.Logger.Named("agent_first_connect")
+
+	ticker := time.NewTicker(agentFirstConnectCheckInterval)
+	defer ticker.Stop()
+	lastCheck := database.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := database.Now()
+		agents, err := api.Database.GetWorkspaceAgentsNeverConnected(ctx, database.GetWorkspaceAgentsNeverConnectedParams{
+			DefaultConnectionTimeoutSeconds: int32(agentDefaultConnectionTimeout.Seconds()),
+			DeadlineAfter:                   lastCheck,
+			DeadlineBefore:                  now,
+		})
+		if err != nil {
+			if !database.IsQueryCanceledError(err) {
+				logger.Error(ctx, "get agents that never connected", slog.Error(err))
+			}
+			continue
+		}
+		lastCheck = now
+
+		for _, agent := range agents {
+			api.agentFirstConnectMetrics.neverConnected.WithLabelValues(agent.TemplateName).Inc()
+			logger.Warn(ctx, "workspace agent never connected after the build completed, check the startup script of the template",
+				slog.F("agent_id", agent.AgentID),
+				slog.F("agent_name", agent.AgentName),
+				slog.F("workspace_id", agent.WorkspaceID),
+				slog.F("workspace_name", agent.WorkspaceName),
+				slog.F("template_id", agent.TemplateID),
+				slog.F("template_name", agent.TemplateName),
+			)
+		}
+	}
+}
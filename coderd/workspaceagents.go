@@ -23,6 +23,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 	"golang.org/x/exp/slices"
 	"golang.org/x/mod/semver"
 	"golang.org/x/sync/errgroup"
@@ -33,6 +34,7 @@ import (
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/gitauth"
 	"github.com/coder/coder/v2/coderd/httpapi"
@@ -90,6 +92,9 @@ func (api *API) workspaceAgent(rw http.ResponseWriter, r *http.Request) {
 func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	workspaceAgent := httpmw.WorkspaceAgent(r)
+	if !api.admitWorkspaceAgent(rw, r, workspaceAgent) {
+		return
+	}
 	apiAgent, err := convertWorkspaceAgent(
 		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
 		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
@@ -151,6 +156,53 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		})
 		return
 	}
+	//nolint:gocritic // Agents can't read templates, but need to know whether
+	// theirs requires binary verification.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	portPolicy, err := db2sdk.PortPolicy(template.PortPolicy)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error decoding template port policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Environment variables set on the agent in the template take
+	// precedence over the ones managed for the organization or template.
+	env, err := api.managedEnvironmentVariables(ctx, template)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching managed environment variables.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	for name, value := range apiAgent.EnvironmentVariables {
+		env[name] = value
+	}
+
+	// The agent keeps a random host key if the persisted one isn't available,
+	// which only costs users a host key warning.
+	sshHostKey, err := api.workspaceAgentSSHHostKey(ctx, workspace.ID, workspaceAgent.Name)
+	if err != nil {
+		api.Logger.Warn(ctx, "get workspace agent ssh host key", slog.F("agent_id", workspaceAgent.ID), slog.Error(err))
+	}
+	var sshHostCertificate string
+	if sshHostKey != "" {
+		sshHostCertificate, err = api.workspaceAgentSSHHostCertificate(sshHostKey, workspace.Name, workspaceAgent.Name)
+		if err != nil {
+			api.Logger.Warn(ctx, "sign workspace agent ssh host certificate", slog.F("agent_id", workspaceAgent.ID), slog.Error(err))
+		}
+	}
 
 	vscodeProxyURI := strings.ReplaceAll(api.AppHostname, "*",
 		fmt.Sprintf("%s://{{port}}--%s--%s--%s",
@@ -163,21 +215,32 @@ func (api *API) workspaceAgentManifest(rw http.ResponseWriter, r *http.Request)
 		vscodeProxyURI += fmt.Sprintf(":%s", api.AccessURL.Port())
 	}
 
+	// Agents get every workspace proxy in the DERP map, since clients may
+	// connect through regions the workspace owner can't use.
 	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.Manifest{
-		AgentID:                  apiAgent.ID,
-		Apps:                     convertApps(dbApps),
-		DERPMap:                  api.DERPMap(),
-		GitAuthConfigs:           len(api.GitAuthConfigs),
-		EnvironmentVariables:     apiAgent.EnvironmentVariables,
-		StartupScript:            apiAgent.StartupScript,
-		Directory:                apiAgent.Directory,
-		VSCodePortProxyURI:       vscodeProxyURI,
-		MOTDFile:                 workspaceAgent.MOTDFile,
-		StartupScriptTimeout:     time.Duration(apiAgent.StartupScriptTimeoutSeconds) * time.Second,
-		ShutdownScript:           apiAgent.ShutdownScript,
-		ShutdownScriptTimeout:    time.Duration(apiAgent.ShutdownScriptTimeoutSeconds) * time.Second,
-		DisableDirectConnections: api.DeploymentValues.DERP.Config.BlockDirect.Value(),
-		Metadata:                 convertWorkspaceAgentMetadataDesc(metadata),
+		AgentID:                   apiAgent.ID,
+		Apps:                      convertApps(dbApps),
+		DERPMap:                   api.DERPMap(),
+		GitAuthConfigs:            len(api.GitAuthConfigs),
+		EnvironmentVariables:      env,
+		StartupScript:             apiAgent.StartupScript,
+		Directory:                 apiAgent.Directory,
+		VSCodePortProxyURI:        vscodeProxyURI,
+		MOTDFile:                  workspaceAgent.MOTDFile,
+		StartupScriptTimeout:      time.Duration(apiAgent.StartupScriptTimeoutSeconds) * time.Second,
+		ShutdownScript:            apiAgent.ShutdownScript,
+		ShutdownScriptTimeout:     time.Duration(apiAgent.ShutdownScriptTimeoutSeconds) * time.Second,
+		DisableDirectConnections:  api.DeploymentValues.DERP.Config.BlockDirect.Value(),
+		Metadata:                  convertWorkspaceAgentMetadataDesc(metadata),
+		RequireBinaryVerification: template.RequireAgentBinaryVerification,
+		DNSSearchDomains:          api.DeploymentValues.AgentDNSSearchDomains.Value(),
+		DNSNameservers:            api.DeploymentValues.AgentDNSNameservers.Value(),
+		KeepaliveInterval:         api.AgentConnectionUpdateFrequency,
+		DisconnectTimeout:         api.AgentInactiveDisconnectTimeout,
+		SSHHostKey:                sshHostKey,
+		SSHHostCertificate:        sshHostCertificate,
+		AuthTokenRotationInterval: api.DeploymentValues.AgentTokenRotationInterval.Value(),
+		PortPolicies:              []codersdk.PortPolicy{api.DeploymentValues.PortForwardPolicy.Value, portPolicy},
 	})
 }
 
@@ -247,11 +310,24 @@ func (api *API) postWorkspaceAgentStartup(rw http.ResponseWriter, r *http.Reques
 		seen[s] = true
 	}
 
+	if req.HostFacts == nil {
+		req.HostFacts = map[string]string{}
+	}
+	hostFacts, err := json.Marshal(req.HostFacts)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error encoding host facts.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	if err := api.Database.UpdateWorkspaceAgentStartupByID(ctx, database.UpdateWorkspaceAgentStartupByIDParams{
 		ID:                apiAgent.ID,
 		Version:           req.Version,
 		ExpandedDirectory: req.ExpandedDirectory,
 		Subsystems:        convertWorkspaceAgentSubsystems(req.Subsystems),
+		HostFacts:         hostFacts,
 	}); err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Error setting agent version",
@@ -631,6 +707,7 @@ func (api *API) workspaceAgentLogs(rw http.ResponseWriter, r *http.Request) {
 // @Router /workspaceagents/{workspaceagent}/listening-ports [get]
 func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
 	workspaceAgent := httpmw.WorkspaceAgentParam(r)
 
 	apiAgent, err := convertWorkspaceAgent(
@@ -670,6 +747,27 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Agents filter the ports by the policies they received when they
+	// connected, which may have changed since.
+	//nolint:gocritic // Users that can see the ports of a workspace may not
+	// be able to read its template.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), workspace.TemplateID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	portPolicy, err := db2sdk.PortPolicy(template.PortPolicy)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error decoding template port policy.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	// Get a list of ports that are in-use by applications.
 	apps, err := api.Database.GetWorkspaceAppsByAgentID(ctx, workspaceAgent.ID)
 	if xerrors.Is(err, sql.ErrNoRows) {
@@ -706,13 +804,17 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 		appPorts[uint16(portNum)] = struct{}{}
 	}
 
-	// Filter out ports that are globally blocked, in-use by applications, or
-	// common non-HTTP ports such as databases, FTP, SSH, etc.
+	// Filter out ports that are globally blocked, denied by the port
+	// policies, in-use by applications, or common non-HTTP ports such as
+	// databases, FTP, SSH, etc.
 	filteredPorts := make([]codersdk.WorkspaceAgentListeningPort, 0, len(portsResponse.Ports))
 	for _, port := range portsResponse.Ports {
 		if port.Port < codersdk.WorkspaceAgentMinimumListeningPort {
 			continue
 		}
+		if allowed, _ := codersdk.CheckPortPolicies(port.Port, api.DeploymentValues.PortForwardPolicy.Value, portPolicy); !allowed {
+			continue
+		}
 		if _, ok := appPorts[port.Port]; ok {
 			continue
 		}
@@ -726,6 +828,74 @@ func (api *API) workspaceAgentListeningPorts(rw http.ResponseWriter, r *http.Req
 	httpapi.Write(ctx, rw, http.StatusOK, portsResponse)
 }
 
+// @Summary Get support bundle for workspace agent
+// @ID get-support-bundle-for-workspace-agent
+// @Security CoderSessionToken
+// @Produce application/zip
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Success 200 {file} binary
+// @Router /workspaceagents/{workspaceagent}/support-bundle [get]
+func (api *API) workspaceAgentSupportBundle(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	// The bundle includes the logs and the manifest of the agent, so only
+	// users that can connect to the workspace may gather it.
+	if !api.Authorize(r, rbac.ActionCreate, workspace.ExecutionRBAC()) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	apiAgent, err := convertWorkspaceAgent(
+		api.DERPMap(), *api.TailnetCoordinator.Load(), workspaceAgent, nil, api.AgentInactiveDisconnectTimeout,
+		api.DeploymentValues.AgentFallbackTroubleshootingURL.String(),
+	)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error reading workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if apiAgent.Status != codersdk.WorkspaceAgentConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", apiAgent.Status, codersdk.WorkspaceAgentConnected),
+		})
+		return
+	}
+
+	agentConn, release, err := api.agentProvider.AgentConn(ctx, workspaceAgent.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error dialing workspace agent.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer release()
+
+	bundle, err := agentConn.SupportBundle(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error gathering support bundle.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	defer bundle.Close()
+
+	filename := fmt.Sprintf("%s-%s-support-bundle.zip", workspace.Name, workspaceAgent.Name)
+	rw.Header().Set("Content-Type", "application/zip")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	rw.WriteHeader(http.StatusOK)
+	_, err = io.Copy(rw, bundle)
+	if err != nil {
+		api.Logger.Debug(ctx, "copy support bundle", slog.F("agent_id", workspaceAgent.ID), slog.Error(err))
+	}
+}
+
 // Deprecated: use api.tailnet.AgentConn instead.
 // See: https://github.com/coder/coder/issues/8218
 func (api *API) _dialWorkspaceAgentTailnet(agentID uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
@@ -830,7 +1000,7 @@ func (api *API) workspaceAgentConnection(rw http.ResponseWriter, r *http.Request
 	ctx := r.Context()
 
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.WorkspaceAgentConnectionInfo{
-		DERPMap:                  api.DERPMap(),
+		DERPMap:                  api.ActorDERPMap(ctx),
 		DisableDirectConnections: api.DeploymentValues.DERP.Config.BlockDirect.Value(),
 	})
 }
@@ -913,9 +1083,18 @@ func (api *API) derpMapUpdates(rw http.ResponseWriter, r *http.Request) {
 	ticker := time.NewTicker(api.Options.DERPMapUpdateFrequency)
 	defer ticker.Stop()
 
+	// Agents relay connections for every client of the workspace, so only
+	// the DERP map of users is narrowed to the proxies they may use.
+	derpMapFn := func() *tailcfg.DERPMap {
+		return api.ActorDERPMap(ctx)
+	}
+	if _, ok := httpmw.WorkspaceAgentOptional(r); ok {
+		derpMapFn = api.DERPMap
+	}
+
 	var lastDERPMap *tailcfg.DERPMap
 	for {
-		derpMap := api.DERPMap()
+		derpMap := derpMapFn()
 		if lastDERPMap == nil || !tailnet.CompareDERPMaps(lastDERPMap, derpMap) {
 			err := json.NewEncoder(nconn).Encode(derpMap)
 			if err != nil {
@@ -947,11 +1126,22 @@ func (api *API) derpMapUpdates(rw http.ResponseWriter, r *http.Request) {
 func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	if api.Draining.Load() {
+		httpapi.Write(ctx, rw, http.StatusServiceUnavailable, codersdk.Response{
+			Message: "This replica is draining and doesn't accept new connections.",
+		})
+		return
+	}
+
+	workspaceAgent := httpmw.WorkspaceAgent(r)
+	if !api.admitWorkspaceAgent(rw, r, workspaceAgent) {
+		return
+	}
+
 	api.WebsocketWaitMutex.Lock()
 	api.WebsocketWaitGroup.Add(1)
 	api.WebsocketWaitMutex.Unlock()
 	defer api.WebsocketWaitGroup.Done()
-	workspaceAgent := httpmw.WorkspaceAgent(r)
 	resource, err := api.Database.GetWorkspaceResourceByID(ctx, workspaceAgent.ResourceID)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -1129,6 +1319,9 @@ func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request
 		return
 	}
 	api.publishWorkspaceUpdate(ctx, build.WorkspaceID)
+	if !workspaceAgent.FirstConnectedAt.Valid {
+		api.recordAgentFirstConnect(ctx, workspaceAgent, build, firstConnectedAt.Time)
+	}
 
 	api.Logger.Debug(ctx, "accepting agent",
 		slog.F("owner", owner.Username),
@@ -1139,6 +1332,9 @@ func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request
 
 	defer conn.Close(websocket.StatusNormalClosure, "")
 
+	api.connectedAgents.Add(1)
+	defer api.connectedAgents.Add(-1)
+
 	closeChan := make(chan struct{})
 	go func() {
 		defer close(closeChan)
@@ -1206,6 +1402,8 @@ func (api *API) workspaceAgentCoordinate(rw http.ResponseWriter, r *http.Request
 // @Security CoderSessionToken
 // @Tags Agents
 // @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param client_type query string false "Type of the connecting client"
+// @Param client_version query string false "Version of the connecting client"
 // @Success 101
 // @Router /workspaceagents/{workspaceagent}/coordinate [get]
 func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.Request) {
@@ -1219,6 +1417,13 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 		return
 	}
 
+	if api.Draining.Load() {
+		httpapi.Write(ctx, rw, http.StatusServiceUnavailable, codersdk.Response{
+			Message: "This replica is draining and doesn't accept new connections.",
+		})
+		return
+	}
+
 	// This is used by Enterprise code to control the functionality of this route.
 	override := api.WorkspaceClientCoordinateOverride.Load()
 	if override != nil {
@@ -1248,13 +1453,172 @@ func (api *API) workspaceAgentClientCoordinate(rw http.ResponseWriter, r *http.R
 	go httpapi.Heartbeat(ctx, conn)
 
 	defer conn.Close(websocket.StatusNormalClosure, "")
-	err = (*api.TailnetCoordinator.Load()).ServeClient(wsNetConn, uuid.New(), workspaceAgent.ID)
+
+	// The connection record shares the ID of the client in the coordinator,
+	// so connections of the workspace can be described with it.
+	id := uuid.New()
+	done := api.recordWorkspaceAgentClientConnection(ctx, r, id, workspaceAgent)
+	defer done()
+	api.connectedClients.Add(1)
+	defer api.connectedClients.Add(-1)
+
+	err = (*api.TailnetCoordinator.Load()).ServeClient(wsNetConn, id, workspaceAgent.ID)
 	if err != nil {
 		_ = conn.Close(websocket.StatusInternalError, err.Error())
 		return
 	}
 }
 
+// workspaceAgentClientVersionMaxLength bounds the client reported version
+// stored for each connection.
+const workspaceAgentClientVersionMaxLength = 128
+
+// recordWorkspaceAgentClientConnection stores the type and version the client
+// coordinating with the agent identified itself with. The returned function
+// marks the connection as disconnected. Failing to record the connection is
+// logged rather than refusing the client.
+func (api *API) recordWorkspaceAgentClientConnection(ctx context.Context, r *http.Request, id uuid.UUID, workspaceAgent database.WorkspaceAgent) func() {
+	// Older clients don't identify themselves, and clients newer than this
+	// deployment may use types it doesn't know about yet.
+	clientType := codersdk.WorkspaceAgentClientType(r.URL.Query().Get("client_type"))
+	if !clientType.Valid() {
+		clientType = codersdk.WorkspaceAgentClientTypeUnknown
+	}
+	clientVersion := r.URL.Query().Get("client_version")
+	if len(clientVersion) > workspaceAgentClientVersionMaxLength {
+		clientVersion = clientVersion[:workspaceAgentClientVersionMaxLength]
+	}
+	// Workspace proxies connect on behalf of their users, so there is no API
+	// key to attribute the connection to.
+	var userID uuid.NullUUID
+	if apiKey, ok := httpmw.APIKeyOptional(r); ok {
+		userID = uuid.NullUUID{UUID: apiKey.UserID, Valid: true}
+	}
+	ipAddress := pqtype.Inet{}
+	if ip := net.ParseIP(r.RemoteAddr); ip != nil {
+		ipAddress = pqtype.Inet{
+			IPNet: net.IPNet{
+				IP:   ip,
+				Mask: net.CIDRMask(len(ip)*8, len(ip)*8),
+			},
+			Valid: true,
+		}
+	}
+
+	//nolint:gocritic // Clients can't write connection records themselves.
+	connection, err := api.Database.InsertWorkspaceAgentClientConnection(dbauthz.AsSystemRestricted(ctx), database.InsertWorkspaceAgentClientConnectionParams{
+		ID:               id,
+		WorkspaceAgentID: workspaceAgent.ID,
+		UserID:           userID,
+		ClientType:       string(clientType),
+		ClientVersion:    clientVersion,
+		ConnectedAt:      database.Now(),
+		IPAddress:        ipAddress,
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to record workspace agent client connection",
+			slog.F("workspace_agent_id", workspaceAgent.ID),
+			slog.Error(err),
+		)
+		return func() {}
+	}
+
+	return func() {
+		// The request context is canceled once the client disconnects.
+		//nolint:gocritic // Clients can't write connection records themselves.
+		ctx, cancel := context.WithTimeout(dbauthz.AsSystemRestricted(api.ctx), 10*time.Second)
+		defer cancel()
+		err := api.Database.UpdateWorkspaceAgentClientConnectionDisconnectedAt(ctx, database.UpdateWorkspaceAgentClientConnectionDisconnectedAtParams{
+			ID:             connection.ID,
+			DisconnectedAt: sql.NullTime{Time: database.Now(), Valid: true},
+		})
+		if err != nil && !xerrors.Is(err, context.Canceled) {
+			api.Logger.Warn(ctx, "failed to record workspace agent client disconnection",
+				slog.F("workspace_agent_id", workspaceAgent.ID),
+				slog.F("connection_id", connection.ID),
+				slog.Error(err),
+			)
+		}
+	}
+}
+
+// workspaceAgentClientConnectionsMaxEntries is the maximum number of
+// connections returned at once.
+const workspaceAgentClientConnectionsMaxEntries = 100
+
+// @Summary Get workspace agent client connections
+// @ID get-workspace-agent-client-connections
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param after query string false "Only return connections established after this time" format(date-time)
+// @Param limit query int false "Maximum number of connections to return"
+// @Success 200 {array} codersdk.WorkspaceAgentClientConnection
+// @Router /workspaceagents/{workspaceagent}/connections [get]
+func (api *API) workspaceAgentClientConnections(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	var (
+		after = p.Time3339Nano(vals, time.Time{}, "after")
+		limit = p.Int(vals, workspaceAgentClientConnectionsMaxEntries, "limit")
+	)
+	p.ErrorExcessParams(vals)
+	if limit < 1 || limit > workspaceAgentClientConnectionsMaxEntries {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "limit",
+			Detail: fmt.Sprintf("Query param \"limit\" must be between 1 and %d", workspaceAgentClientConnectionsMaxEntries),
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	connections, err := api.Database.GetWorkspaceAgentClientConnections(ctx, database.GetWorkspaceAgentClientConnectionsParams{
+		WorkspaceAgentID: workspaceAgent.ID,
+		ConnectedAfter:   after,
+		LimitOpt:         int32(limit),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent client connections.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceAgentClientConnections(connections))
+}
+
+func convertWorkspaceAgentClientConnections(dbConnections []database.WorkspaceAgentClientConnection) []codersdk.WorkspaceAgentClientConnection {
+	connections := make([]codersdk.WorkspaceAgentClientConnection, 0, len(dbConnections))
+	for _, dbConnection := range dbConnections {
+		connection := codersdk.WorkspaceAgentClientConnection{
+			ID:            dbConnection.ID,
+			ClientType:    codersdk.WorkspaceAgentClientType(dbConnection.ClientType),
+			ClientVersion: dbConnection.ClientVersion,
+			ConnectedAt:   dbConnection.ConnectedAt,
+		}
+		if dbConnection.UserID.Valid {
+			userID := dbConnection.UserID.UUID
+			connection.UserID = &userID
+		}
+		if dbConnection.DisconnectedAt.Valid {
+			disconnectedAt := dbConnection.DisconnectedAt.Time
+			connection.DisconnectedAt = &disconnectedAt
+		}
+		connections = append(connections, connection)
+	}
+	return connections
+}
+
 func convertApps(dbApps []database.WorkspaceApp) []codersdk.WorkspaceApp {
 	apps := make([]codersdk.WorkspaceApp, 0)
 	for _, dbApp := range dbApps {
@@ -1273,9 +1637,16 @@ func convertApps(dbApps []database.WorkspaceApp) []codersdk.WorkspaceApp {
 				Interval:  dbApp.HealthcheckInterval,
 				Threshold: dbApp.HealthcheckThreshold,
 			},
-			Health: codersdk.WorkspaceAppHealth(dbApp.Health),
+			Health:       codersdk.WorkspaceAppHealth(dbApp.Health),
+			DisplayOrder: dbApp.DisplayOrder,
+			Hidden:       dbApp.Hidden,
 		})
 	}
+	// Apps are fetched ordered by slug, so a stable sort keeps that as the
+	// tie breaker.
+	sort.SliceStable(apps, func(i, j int) bool {
+		return apps[i].DisplayOrder < apps[j].DisplayOrder
+	})
 	return apps
 }
 
@@ -1293,6 +1664,10 @@ func convertWorkspaceAgentMetadataDesc(mds []database.WorkspaceAgentMetadatum) [
 	return metadata
 }
 
+// workspaceAgentClockSkewThreshold is the clock offset beyond which agents are
+// reported as unhealthy.
+const workspaceAgentClockSkewThreshold = 30 * time.Second
+
 func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordinator, dbAgent database.WorkspaceAgent, apps []codersdk.WorkspaceApp, agentInactiveDisconnectTimeout time.Duration, agentFallbackTroubleshootingURL string) (codersdk.WorkspaceAgent, error) {
 	var envs map[string]string
 	if dbAgent.EnvironmentVariables.Valid {
@@ -1309,6 +1684,13 @@ func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordin
 	for i, subsystem := range dbAgent.Subsystems {
 		subsystems[i] = codersdk.AgentSubsystem(subsystem)
 	}
+	var hostFacts map[string]string
+	if len(dbAgent.HostFacts) > 0 {
+		err := json.Unmarshal(dbAgent.HostFacts, &hostFacts)
+		if err != nil {
+			return codersdk.WorkspaceAgent{}, xerrors.Errorf("unmarshal host facts: %w", err)
+		}
+	}
 
 	workspaceAgent := codersdk.WorkspaceAgent{
 		ID:                           dbAgent.ID,
@@ -1336,6 +1718,9 @@ func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordin
 		ShutdownScript:               dbAgent.ShutdownScript.String,
 		ShutdownScriptTimeoutSeconds: dbAgent.ShutdownScriptTimeoutSeconds,
 		Subsystems:                   subsystems,
+		HostFacts:                    hostFacts,
+		ClockOffsetMS:                dbAgent.ClockOffsetMS,
+		DisplayOrder:                 dbAgent.DisplayOrder,
 	}
 	node := coordinator.Node(dbAgent.ID)
 	if node != nil {
@@ -1376,6 +1761,7 @@ func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordin
 		workspaceAgent.ReadyAt = &dbAgent.ReadyAt.Time
 	}
 
+	clockOffset := time.Duration(dbAgent.ClockOffsetMS) * time.Millisecond
 	switch {
 	case workspaceAgent.Status != codersdk.WorkspaceAgentConnected && workspaceAgent.LifecycleState == codersdk.WorkspaceAgentLifecycleOff:
 		workspaceAgent.Health.Reason = "agent is not running"
@@ -1390,6 +1776,8 @@ func convertWorkspaceAgent(derpMap *tailcfg.DERPMap, coordinator tailnet.Coordin
 		workspaceAgent.Health.Reason = "agent startup script exited with an error"
 	case workspaceAgent.LifecycleState.ShuttingDown():
 		workspaceAgent.Health.Reason = "agent is shutting down"
+	case workspaceAgent.Status == codersdk.WorkspaceAgentConnected && clockOffset.Abs() > workspaceAgentClockSkewThreshold:
+		workspaceAgent.Health.Reason = fmt.Sprintf("agent clock is off by %s, which breaks token validation and TLS", clockOffset.Round(time.Second))
 	default:
 		workspaceAgent.Health.Healthy = true
 	}
@@ -1428,6 +1816,7 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 	if req.ConnectionsByProto == nil {
 		httpapi.Write(ctx, rw, http.StatusOK, agentsdk.StatsResponse{
 			ReportInterval: api.AgentStatsRefreshInterval,
+			ServerTime:     time.Now(),
 		})
 		return
 	}
@@ -1439,8 +1828,21 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 		slog.F("payload", req),
 	)
 
-	if req.ConnectionCount > 0 {
-		activityBumpWorkspace(ctx, api.Logger.Named("activity_bump"), api.Database, workspace.ID)
+	if req.ConnectionCount > 0 || len(req.Activity) > 0 {
+		//nolint:gocritic // The agent can't read the template schedule.
+		templateSchedule, err := (*api.TemplateScheduleStore.Load()).Get(dbauthz.AsSystemRestricted(ctx), api.Database, workspace.TemplateID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching template schedule.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		// Activity is reported at every interval, so older activity was
+		// already taken into account by a previous report.
+		if statsPostponeAutostop(req, templateSchedule.AutostopActivitySources, time.Now(), 2*api.AgentStatsRefreshInterval) {
+			activityBumpWorkspace(ctx, api.Logger.Named("activity_bump"), api.Database, workspace.ID)
+		}
 	}
 
 	now := database.Now()
@@ -1463,6 +1865,20 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 		}
 		return nil
 	})
+	// The offset jitters with the latency of reports, so it's only stored
+	// when it changes noticeably.
+	if offsetChange := time.Duration(req.ClockOffsetMS-workspaceAgent.ClockOffsetMS) * time.Millisecond; offsetChange >= time.Second || offsetChange <= -time.Second {
+		errGroup.Go(func() error {
+			err := api.Database.UpdateWorkspaceAgentClockOffsetByID(ctx, database.UpdateWorkspaceAgentClockOffsetByIDParams{
+				ID:            workspaceAgent.ID,
+				ClockOffsetMS: req.ClockOffsetMS,
+			})
+			if err != nil {
+				return xerrors.Errorf("can't update workspace agent clock offset: %w", err)
+			}
+			return nil
+		})
+	}
 	if api.Options.UpdateAgentMetrics != nil {
 		errGroup.Go(func() error {
 			user, err := api.Database.GetUserByID(ctx, workspace.OwnerID)
@@ -1482,6 +1898,7 @@ func (api *API) workspaceAgentReportStats(rw http.ResponseWriter, r *http.Reques
 
 	httpapi.Write(ctx, rw, http.StatusOK, agentsdk.StatsResponse{
 		ReportInterval: api.AgentStatsRefreshInterval,
+		ServerTime:     time.Now(),
 	})
 }
 
@@ -1558,6 +1975,19 @@ func (api *API) workspaceAgentPostMetadata(rw http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	err = api.Database.InsertWorkspaceAgentMetadataHistory(ctx, database.InsertWorkspaceAgentMetadataHistoryParams{
+		WorkspaceAgentID: datum.WorkspaceAgentID,
+		Key:              datum.Key,
+		Value:            datum.Value,
+		Error:            datum.Error,
+		CollectedAt:      datum.CollectedAt,
+		MaxEntries:       workspaceAgentMetadataHistoryMaxEntries,
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
 	api.Logger.Debug(
 		ctx, "accepted metadata report",
 		slog.F("workspace_agent_id", workspaceAgent.ID),
@@ -1576,6 +2006,66 @@ func (api *API) workspaceAgentPostMetadata(rw http.ResponseWriter, r *http.Reque
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
+// workspaceAgentMetadataHistoryMaxEntries is the number of values retained
+// per metadata key. Agents report metadata on a per-key interval, so this
+// covers roughly the last hour and a half of a key collected every minute.
+const workspaceAgentMetadataHistoryMaxEntries = 100
+
+// @Summary Get workspace agent metadata history
+// @ID get-workspace-agent-metadata-history
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Agents
+// @Param workspaceagent path string true "Workspace agent ID" format(uuid)
+// @Param key query string true "Metadata key"
+// @Param after query string false "Only return values collected after this time" format(date-time)
+// @Param limit query int false "Maximum number of values to return"
+// @Success 200 {array} codersdk.WorkspaceAgentMetadataResult
+// @Router /workspaceagents/{workspaceagent}/metadata/history [get]
+func (api *API) workspaceAgentMetadataHistory(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceAgent := httpmw.WorkspaceAgentParam(r)
+
+	p := httpapi.NewQueryParamParser().
+		Required("key")
+	vals := r.URL.Query()
+	var (
+		key   = p.String(vals, "", "key")
+		after = p.Time3339Nano(vals, time.Time{}, "after")
+		limit = p.Int(vals, workspaceAgentMetadataHistoryMaxEntries, "limit")
+	)
+	p.ErrorExcessParams(vals)
+	if limit < 1 || limit > workspaceAgentMetadataHistoryMaxEntries {
+		p.Errors = append(p.Errors, codersdk.ValidationError{
+			Field:  "limit",
+			Detail: fmt.Sprintf("Query param \"limit\" must be between 1 and %d", workspaceAgentMetadataHistoryMaxEntries),
+		})
+	}
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+
+	history, err := api.Database.GetWorkspaceAgentMetadataHistory(ctx, database.GetWorkspaceAgentMetadataHistoryParams{
+		WorkspaceAgentID: workspaceAgent.ID,
+		Key:              key,
+		CollectedAfter:   after,
+		LimitOpt:         int32(limit),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent metadata history.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertWorkspaceAgentMetadataHistory(history))
+}
+
 // @Summary Watch for workspace agent metadata updates
 // @ID watch-for-workspace-agent-metadata-updates
 // @Security CoderSessionToken
@@ -1720,6 +2210,21 @@ func convertWorkspaceAgentMetadata(db []database.WorkspaceAgentMetadatum) []code
 	return result
 }
 
+// convertWorkspaceAgentMetadataHistory converts history rows, which are
+// fetched newest first, into results ordered oldest first for graphing.
+func convertWorkspaceAgentMetadataHistory(db []database.WorkspaceAgentMetadataHistory) []codersdk.WorkspaceAgentMetadataResult {
+	result := make([]codersdk.WorkspaceAgentMetadataResult, len(db))
+	for i, datum := range db {
+		result[len(db)-1-i] = codersdk.WorkspaceAgentMetadataResult{
+			Value:       datum.Value,
+			Error:       datum.Error,
+			CollectedAt: datum.CollectedAt,
+			Age:         int64(time.Since(datum.CollectedAt).Seconds()),
+		}
+	}
+	return result
+}
+
 func watchWorkspaceAgentMetadataChannel(id uuid.UUID) string {
 	return "workspace_agent_metadata:" + id.String()
 }
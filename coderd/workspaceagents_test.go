@@ -1,9 +1,12 @@
 package coderd_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"runtime"
@@ -16,6 +19,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	gossh "golang.org/x/crypto/ssh"
 	"tailscale.com/tailcfg"
 
 	"cdr.dev/slog"
@@ -23,6 +27,7 @@ import (
 	"github.com/coder/coder/v2/agent"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/provisioner/echo"
@@ -456,6 +461,41 @@ func TestWorkspaceAgentTailnet(t *testing.T) {
 	require.Equal(t, "test", strings.TrimSpace(string(output)))
 }
 
+func TestWorkspaceAgentManifestDNSConfig(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	err := dv.AgentDNSSearchDomains.Set("corp.example.com")
+	require.NoError(t, err)
+	err = dv.AgentDNSNameservers.Set("10.0.0.53,10.0.1.53")
+	require.NoError(t, err)
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		DeploymentValues:         dv,
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	manifest, err := agentClient.Manifest(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"corp.example.com"}, manifest.DNSSearchDomains)
+	require.Equal(t, []string{"10.0.0.53", "10.0.1.53"}, manifest.DNSNameservers)
+}
+
 func TestWorkspaceAgentTailnetDirectDisabled(t *testing.T) {
 	t.Parallel()
 
@@ -532,6 +572,101 @@ func TestWorkspaceAgentTailnetDirectDisabled(t *testing.T) {
 	require.False(t, p2p)
 }
 
+func TestWorkspaceAgentManifestBinaryVerification(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	manifest, err := agentClient.Manifest(ctx)
+	require.NoError(t, err)
+	require.False(t, manifest.RequireBinaryVerification)
+
+	template, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		RequireAgentBinaryVerification: ptr.Ref(true),
+	})
+	require.NoError(t, err)
+	require.True(t, template.RequireAgentBinaryVerification)
+
+	manifest, err = agentClient.Manifest(ctx)
+	require.NoError(t, err)
+	require.True(t, manifest.RequireBinaryVerification)
+}
+
+func TestWorkspaceAgentClientConnections(t *testing.T) {
+	t.Parallel()
+	client, daemonCloser := coderdtest.NewWithProvisionerCloser(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	daemonCloser.Close()
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	defer agentCloser.Close()
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	conn, err := client.DialWorkspaceAgent(ctx, agentID, &codersdk.DialWorkspaceAgentOptions{
+		Logger:        slogtest.Make(t, nil).Named("client").Leveled(slog.LevelDebug),
+		ClientType:    codersdk.WorkspaceAgentClientTypeJetBrains,
+		ClientVersion: "v1.2.3",
+	})
+	require.NoError(t, err)
+
+	var connections []codersdk.WorkspaceAgentClientConnection
+	require.Eventually(t, func() bool {
+		connections, err = client.WorkspaceAgentClientConnections(ctx, agentID, codersdk.WorkspaceAgentClientConnectionsRequest{})
+		return assert.NoError(t, err) && len(connections) == 1
+	}, testutil.WaitShort, testutil.IntervalFast)
+	require.Equal(t, codersdk.WorkspaceAgentClientTypeJetBrains, connections[0].ClientType)
+	require.Equal(t, "v1.2.3", connections[0].ClientVersion)
+	require.NotNil(t, connections[0].UserID)
+	require.Equal(t, user.UserID, *connections[0].UserID)
+	require.Nil(t, connections[0].DisconnectedAt)
+
+	// Closing the connection marks it as disconnected.
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool {
+		connections, err = client.WorkspaceAgentClientConnections(ctx, agentID, codersdk.WorkspaceAgentClientConnectionsRequest{})
+		return assert.NoError(t, err) && len(connections) == 1 && connections[0].DisconnectedAt != nil
+	}, testutil.WaitShort, testutil.IntervalFast)
+
+	_, err = client.WorkspaceAgentClientConnections(ctx, agentID, codersdk.WorkspaceAgentClientConnectionsRequest{
+		Limit: 1000,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
+
 func TestWorkspaceAgentListeningPorts(t *testing.T) {
 	t.Parallel()
 
@@ -777,6 +912,85 @@ func TestWorkspaceAgentListeningPorts(t *testing.T) {
 	})
 }
 
+func TestWorkspaceAgentSupportBundle(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:         echo.ParseComplete,
+		ProvisionPlan: echo.ProvisionComplete,
+		ProvisionApply: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Id: uuid.NewString(),
+							Env: map[string]string{
+								"GITHUB_TOKEN": "hunter2",
+							},
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	t.Cleanup(func() {
+		_ = agentCloser.Close()
+	})
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	bundle, err := client.WorkspaceAgentSupportBundle(ctx, resources[0].Agents[0].ID)
+	require.NoError(t, err)
+	defer bundle.Close()
+	data, err := io.ReadAll(bundle)
+	require.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, file := range archive.File {
+		f, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		_ = f.Close()
+		require.NoError(t, err)
+		files[file.Name] = content
+	}
+	require.Contains(t, files, "agent.json")
+	require.Contains(t, files, "listening_ports.json")
+	require.Contains(t, files, "tailnet.json")
+	require.Contains(t, files, "netcheck.json")
+	require.Contains(t, files, "manifest.json")
+
+	var manifest agentsdk.Manifest
+	err = json.Unmarshal(files["manifest.json"], &manifest)
+	require.NoError(t, err)
+	require.Equal(t, resources[0].Agents[0].ID, manifest.AgentID)
+	require.Equal(t, codersdk.RedactedValue, manifest.EnvironmentVariables["GITHUB_TOKEN"])
+	require.NotContains(t, string(files["manifest.json"]), "hunter2")
+}
+
 func TestWorkspaceAgentAppHealth(t *testing.T) {
 	t.Parallel()
 	client := coderdtest.New(t, &coderdtest.Options{
@@ -927,6 +1141,42 @@ func TestWorkspaceAgentReportStats(t *testing.T) {
 			"%s is not after %s", newWorkspace.LastUsedAt, workspace.LastUsedAt,
 		)
 	})
+
+	t.Run("ClockOffset", func(t *testing.T) {
+		t.Parallel()
+
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+
+		res, err := agentClient.PostStats(ctx, &agentsdk.Stats{
+			ConnectionsByProto: map[string]int64{},
+			ClockOffsetMS:      (2 * time.Minute).Milliseconds(),
+		})
+		require.NoError(t, err)
+		require.False(t, res.ServerTime.IsZero())
+
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		agent := workspace.LatestBuild.Resources[0].Agents[0]
+		require.Equal(t, (2 * time.Minute).Milliseconds(), agent.ClockOffsetMS)
+	})
 }
 
 func TestWorkspaceAgent_LifecycleState(t *testing.T) {
@@ -1171,6 +1421,104 @@ func TestWorkspaceAgent_Metadata(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestWorkspaceAgent_MetadataHistory(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:         echo.ParseComplete,
+		ProvisionPlan: echo.ProvisionComplete,
+		ProvisionApply: []*proto.Provision_Response{{
+			Type: &proto.Provision_Response_Complete{
+				Complete: &proto.Provision_Complete{
+					Resources: []*proto.Resource{{
+						Name: "example",
+						Type: "aws_instance",
+						Agents: []*proto.Agent{{
+							Metadata: []*proto.Agent_Metadata{{
+								DisplayName: "GPU Utilization",
+								Key:         "gpu",
+								Script:      "echo 0",
+								Interval:    10,
+								Timeout:     3,
+							}},
+							Id: uuid.NewString(),
+							Auth: &proto.Agent_Token{
+								Token: authToken,
+							},
+						}},
+					}},
+				},
+			},
+		}},
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+
+	ctx := testutil.Context(t, testutil.WaitMedium)
+
+	workspace, err := client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	agentID := workspace.LatestBuild.Resources[0].Agents[0].ID
+
+	for _, value := range []string{"10", "20", "30"} {
+		err := agentClient.PostMetadata(ctx, "gpu", codersdk.WorkspaceAgentMetadataResult{
+			CollectedAt: time.Now(),
+			Value:       value,
+		})
+		require.NoError(t, err)
+	}
+	// Keys that the agent did not declare are not recorded.
+	err = agentClient.PostMetadata(ctx, "unknown", codersdk.WorkspaceAgentMetadataResult{
+		CollectedAt: time.Now(),
+		Value:       "1",
+	})
+	require.NoError(t, err)
+
+	history, err := client.WorkspaceAgentMetadataHistory(ctx, agentID, codersdk.WorkspaceAgentMetadataHistoryRequest{
+		Key: "gpu",
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	// Values are returned oldest first.
+	require.Equal(t, "10", history[0].Value)
+	require.Equal(t, "20", history[1].Value)
+	require.Equal(t, "30", history[2].Value)
+
+	// The limit keeps the most recent values.
+	history, err = client.WorkspaceAgentMetadataHistory(ctx, agentID, codersdk.WorkspaceAgentMetadataHistoryRequest{
+		Key:   "gpu",
+		Limit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, "20", history[0].Value)
+	require.Equal(t, "30", history[1].Value)
+
+	history, err = client.WorkspaceAgentMetadataHistory(ctx, agentID, codersdk.WorkspaceAgentMetadataHistoryRequest{
+		Key: "unknown",
+	})
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	_, err = client.WorkspaceAgentMetadataHistory(ctx, agentID, codersdk.WorkspaceAgentMetadataHistoryRequest{
+		Key:   "gpu",
+		Limit: 1000,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+}
+
 func TestWorkspaceAgent_Startup(t *testing.T) {
 	t.Parallel()
 
@@ -1204,6 +1552,10 @@ func TestWorkspaceAgent_Startup(t *testing.T) {
 				codersdk.AgentSubsystemEnvbox,
 				codersdk.AgentSubsystemExectrace,
 			}
+			expectedHostFacts = map[string]string{
+				codersdk.AgentHostFactOSName:  "Windows Server 2022 Datacenter",
+				codersdk.AgentHostFactOSBuild: "20348.1906",
+			}
 		)
 
 		err := agentClient.PostStartup(ctx, agentsdk.PostStartupRequest{
@@ -1214,6 +1566,7 @@ func TestWorkspaceAgent_Startup(t *testing.T) {
 				expectedSubsystems[1],
 				expectedSubsystems[0],
 			},
+			HostFacts: expectedHostFacts,
 		})
 		require.NoError(t, err)
 
@@ -1226,6 +1579,7 @@ func TestWorkspaceAgent_Startup(t *testing.T) {
 		require.Equal(t, expectedDir, wsagent.ExpandedDirectory)
 		// Sorted
 		require.Equal(t, expectedSubsystems, wsagent.Subsystems)
+		require.Equal(t, expectedHostFacts, wsagent.HostFacts)
 	})
 
 	t.Run("InvalidSemver", func(t *testing.T) {
@@ -1264,6 +1618,64 @@ func TestWorkspaceAgent_Startup(t *testing.T) {
 // TestWorkspaceAgent_UpdatedDERP runs a real coderd server, with a real agent
 // and a real client, and updates the DERP map live to ensure connections still
 // work.
+func TestWorkspaceAgent_SSHHostKey(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	responses := func(authToken string) *echo.Responses {
+		return &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		}
+	}
+	manifest := func(authToken string) agentsdk.Manifest {
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		ctx := testutil.Context(t, testutil.WaitLong)
+		manifest, err := agentClient.Manifest(ctx)
+		require.NoError(t, err)
+		return manifest
+	}
+
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, responses(authToken))
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	hostKey := manifest(authToken).SSHHostKey
+	require.NotEmpty(t, hostKey)
+	_, err := gossh.ParsePrivateKey([]byte(hostKey))
+	require.NoError(t, err)
+	require.Equal(t, hostKey, manifest(authToken).SSHHostKey)
+
+	// The rebuilt agent has a new ID and token, but the same name.
+	authToken = uuid.NewString()
+	version = coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, responses(authToken), template.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStart, func(req *codersdk.CreateWorkspaceBuildRequest) {
+		req.TemplateVersionID = version.ID
+	})
+	coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+	require.Equal(t, hostKey, manifest(authToken).SSHHostKey)
+
+	// Other workspaces have their own key.
+	authToken = uuid.NewString()
+	otherVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, responses(authToken))
+	coderdtest.AwaitTemplateVersionJob(t, client, otherVersion.ID)
+	otherTemplate := coderdtest.CreateTemplate(t, client, user.OrganizationID, otherVersion.ID)
+	otherWorkspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, otherTemplate.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, otherWorkspace.LatestBuild.ID)
+	otherHostKey := manifest(authToken).SSHHostKey
+	require.NotEmpty(t, otherHostKey)
+	require.NotEqual(t, hostKey, otherHostKey)
+}
+
 func TestWorkspaceAgent_UpdatedDERP(t *testing.T) {
 	t.Parallel()
 
@@ -1284,7 +1696,7 @@ func TestWorkspaceAgent_UpdatedDERP(t *testing.T) {
 	var currentDerpMap atomic.Pointer[tailcfg.DERPMap]
 	originalDerpMap, _ := tailnettest.RunDERPAndSTUN(t)
 	currentDerpMap.Store(originalDerpMap)
-	derpMapFn := func(_ *tailcfg.DERPMap) *tailcfg.DERPMap {
+	derpMapFn := func(_ context.Context, _ *tailcfg.DERPMap) *tailcfg.DERPMap {
 		return currentDerpMap.Load().Clone()
 	}
 	api.DERPMapper.Store(&derpMapFn)
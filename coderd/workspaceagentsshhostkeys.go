@@ -0,0 +1,67 @@
+package coderd
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"database/sql"
+	"encoding/pem"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+)
+
+// workspaceAgentSSHHostKey returns the PEM encoded SSH host key of the agent
+// with the name in the workspace, generating it the first time. Agents are
+// recreated by every build, so the key is kept by name to stay the same across
+// rebuilds and spare users host key warnings.
+func (api *API) workspaceAgentSSHHostKey(ctx context.Context, workspaceID uuid.UUID, agentName string) (string, error) {
+	//nolint:gocritic // Agents can't read the keys, they are only handed out
+	// in their manifest.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	key, err := api.Database.GetWorkspaceAgentSSHHostKey(ctx, database.GetWorkspaceAgentSSHHostKeyParams{
+		WorkspaceID: workspaceID,
+		AgentName:   agentName,
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// The agent generates RSA keys itself, so the persisted key replaces
+		// its random one instead of being offered next to it.
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return "", xerrors.Errorf("generate key: %w", err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return "", xerrors.Errorf("marshal key: %w", err)
+		}
+		encrypted, err := api.AppSecurityKey.EncryptSecret(pem.EncodeToMemory(&pem.Block{
+			Type:  "PRIVATE KEY",
+			Bytes: der,
+		}))
+		if err != nil {
+			return "", xerrors.Errorf("encrypt key: %w", err)
+		}
+		key, err = api.Database.InsertWorkspaceAgentSSHHostKey(ctx, database.InsertWorkspaceAgentSSHHostKeyParams{
+			WorkspaceID:         workspaceID,
+			AgentName:           agentName,
+			EncryptedPrivateKey: encrypted,
+			CreatedAt:           database.Now(),
+		})
+		if err != nil {
+			return "", xerrors.Errorf("insert key: %w", err)
+		}
+	} else if err != nil {
+		return "", xerrors.Errorf("get key: %w", err)
+	}
+
+	decrypted, err := api.AppSecurityKey.DecryptSecret(key.EncryptedPrivateKey)
+	if err != nil {
+		return "", xerrors.Errorf("decrypt key: %w", err)
+	}
+	return string(decrypted), nil
+}
@@ -0,0 +1,470 @@
+package coderd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+const (
+	// workspaceApprovalWebhookTimeout is how long the approval webhook may
+	// take to accept a request.
+	workspaceApprovalWebhookTimeout = 10 * time.Second
+	// workspaceApprovalWebhookAttempts is how many times a request is sent to
+	// the approval webhook before giving up.
+	workspaceApprovalWebhookAttempts      = 3
+	workspaceApprovalWebhookRetryInterval = 10 * time.Second
+)
+
+// @Summary Get workspace approval
+// @Description Returns the most recent approval request of the workspace.
+// @ID get-workspace-approval
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {object} codersdk.WorkspaceApproval
+// @Router /workspaces/{workspace}/approval [get]
+func (api *API) workspaceApproval(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	approval, err := api.Database.GetLatestWorkspaceApprovalByWorkspaceID(ctx, workspace.ID)
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusNotFound, codersdk.Response{
+			Message: "The workspace has no approval requests.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace approval.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiApproval, err := api.convertWorkspaceApproval(ctx, approval)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace approval.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiApproval)
+}
+
+// @Summary Decide workspace approval
+// @Description Approves or rejects a pending approval request. Approved builds
+// @Description are queued for provisioners, rejected builds fail.
+// @Description Users can't decide their own requests.
+// @ID decide-workspace-approval
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspaceapproval path string true "Workspace approval ID" format(uuid)
+// @Param request body codersdk.DecideWorkspaceApprovalRequest true "Decide workspace approval request"
+// @Success 200 {object} codersdk.WorkspaceApproval
+// @Router /workspaceapprovals/{workspaceapproval}/decision [post]
+func (api *API) postWorkspaceApprovalDecision(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceApproval](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	approvalID, ok := httpmw.ParseUUIDParam(rw, r, "workspaceapproval")
+	if !ok {
+		return
+	}
+
+	var req codersdk.DecideWorkspaceApprovalRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	approval, err := api.Database.GetWorkspaceApprovalByID(ctx, approvalID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace approval.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = approval
+
+	// Change-management processes require a second person to sign off, so
+	// requesters can't approve their own workspaces, even as owners.
+	if approval.RequestedBy == apiKey.UserID {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "You can't decide your own approval request.",
+		})
+		return
+	}
+
+	approval, ok = api.decideWorkspaceApproval(ctx, rw, approval, uuid.NullUUID{UUID: apiKey.UserID, Valid: true}, req)
+	if !ok {
+		return
+	}
+	aReq.New = approval
+
+	apiApproval, err := api.convertWorkspaceApproval(ctx, approval)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace approval.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiApproval)
+}
+
+// @Summary Decide workspace approval from the approval webhook
+// @Description Records the decision of the system behind the workspace approval webhook
+// @Description of the deployment. Requests aren't authenticated with a session token, but
+// @Description must be signed with the webhook secret using the Coder-Webhook-Timestamp
+// @Description and Coder-Webhook-Signature headers. Requests can only be decided once.
+// @ID decide-workspace-approval-from-the-approval-webhook
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspaceapproval path string true "Workspace approval ID" format(uuid)
+// @Param request body codersdk.DecideWorkspaceApprovalRequest true "Decide workspace approval request"
+// @Success 200 {object} codersdk.WorkspaceApproval
+// @Router /workspaceapprovals/{workspaceapproval}/callback [post]
+func (api *API) postWorkspaceApprovalCallback(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceApproval](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	approvalID, ok := httpmw.ParseUUIDParam(rw, r, "workspaceapproval")
+	if !ok {
+		return
+	}
+
+	secret := api.DeploymentValues.WorkspaceApprovalWebhook.Secret.String()
+	if secret == "" {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "Workspace approval callbacks are disabled.",
+			Detail:  "The deployment has no workspace approval webhook secret to verify them with.",
+		})
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(rw, r.Body, workspaceWebhookMaxBodySize))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to read request body.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if _, ok := verifyWorkspaceWebhookSignature(secret, r.Header, body, time.Now()); !ok {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid webhook signature.",
+			Detail: fmt.Sprintf("Requests must be signed with the workspace approval webhook secret using the %q and %q headers.",
+				codersdk.WorkspaceWebhookTimestampHeader, codersdk.WorkspaceWebhookSignatureHeader),
+		})
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	var req codersdk.DecideWorkspaceApprovalRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	//nolint:gocritic // The signature proves the request comes from the
+	// system the deployment trusts to decide approval requests.
+	ctx = dbauthz.AsWorkspaceApprover(ctx)
+	approval, err := api.Database.GetWorkspaceApprovalByID(ctx, approvalID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace approval.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Callbacks aren't made by a user, so they're audited on behalf of the
+	// requester, like webhook triggers are audited on behalf of their
+	// creator. The decision itself is recorded without a user.
+	aReq.Old = approval
+	aReq.UserID = approval.RequestedBy
+
+	approval, ok = api.decideWorkspaceApproval(ctx, rw, approval, uuid.NullUUID{}, req)
+	if !ok {
+		return
+	}
+	aReq.New = approval
+
+	apiApproval, err := api.convertWorkspaceApproval(ctx, approval)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting workspace approval.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiApproval)
+}
+
+// decideWorkspaceApproval records the decision on a pending approval request,
+// and queues or fails its build. It writes an error response and returns false
+// on failure.
+func (api *API) decideWorkspaceApproval(ctx context.Context, rw http.ResponseWriter, approval database.WorkspaceApproval, decidedBy uuid.NullUUID, req codersdk.DecideWorkspaceApprovalRequest) (database.WorkspaceApproval, bool) {
+	if approval.Status != database.WorkspaceApprovalStatusPending {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The approval request has already been decided.",
+		})
+		return database.WorkspaceApproval{}, false
+	}
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, approval.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return database.WorkspaceApproval{}, false
+	}
+	if job.CompletedAt.Valid {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The build awaiting approval has been canceled.",
+		})
+		return database.WorkspaceApproval{}, false
+	}
+
+	err = api.Database.InTx(func(db database.Store) error {
+		now := database.Now()
+		approval, err = db.UpdateWorkspaceApprovalStatusByID(ctx, database.UpdateWorkspaceApprovalStatusByIDParams{
+			ID:        approval.ID,
+			Status:    database.WorkspaceApprovalStatus(req.Status),
+			DecidedBy: decidedBy,
+			DecidedAt: sql.NullTime{Time: now, Valid: true},
+			Reason:    req.Reason,
+		})
+		if err != nil {
+			return xerrors.Errorf("update workspace approval: %w", err)
+		}
+		if req.Status != codersdk.WorkspaceApprovalStatusRejected {
+			return nil
+		}
+		// The job was never acquired, so no provisioner will complete it.
+		//nolint:gocritic // Approvers can fail builds they aren't otherwise
+		// allowed to cancel.
+		err = db.UpdateProvisionerJobWithCompleteByID(dbauthz.AsSystemRestricted(ctx), database.UpdateProvisionerJobWithCompleteByIDParams{
+			ID:          job.ID,
+			UpdatedAt:   now,
+			CompletedAt: sql.NullTime{Time: now, Valid: true},
+			Error:       sql.NullString{String: workspaceApprovalRejectedError(req.Reason), Valid: true},
+		})
+		if err != nil {
+			return xerrors.Errorf("complete provisioner job: %w", err)
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return database.WorkspaceApproval{}, false
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The approval request has already been decided.",
+		})
+		return database.WorkspaceApproval{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deciding workspace approval.",
+			Detail:  err.Error(),
+		})
+		return database.WorkspaceApproval{}, false
+	}
+
+	if approval.Status == database.WorkspaceApprovalStatusApproved {
+		err = provisionerdserver.PostJob(api.Pubsub, job)
+		if err != nil {
+			// Client probably doesn't care about this error, so just log it.
+			api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+		}
+	}
+	api.publishWorkspaceUpdate(ctx, approval.WorkspaceID)
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, approval.WorkspaceID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return database.WorkspaceApproval{}, false
+	}
+	api.PlatformEvents.WorkspaceApproval(ctx, codersdk.PlatformEventTypeWorkspaceApprovalDecided, approval, workspace)
+	if approval.Status == database.WorkspaceApprovalStatusRejected {
+		build, err := api.Database.GetWorkspaceBuildByJobID(ctx, approval.JobID)
+		if err != nil {
+			api.Logger.Warn(ctx, "fetch rejected workspace build", slog.F("job_id", approval.JobID), slog.Error(err))
+		} else {
+			api.PlatformEvents.WorkspaceBuild(ctx, codersdk.PlatformEventTypeWorkspaceBuildFailed, build, workspaceApprovalRejectedError(approval.Reason))
+		}
+	}
+	return approval, true
+}
+
+// sendWorkspaceApprovalWebhook posts a new approval request to the workspace
+// approval webhook of the deployment, if there is one. The request stays
+// pending when the webhook can't be reached, so it can still be decided by an
+// approver.
+func (api *API) sendWorkspaceApprovalWebhook(approval database.WorkspaceApproval, build database.WorkspaceBuild, workspace database.Workspace, template database.Template, owner, requester database.User) {
+	webhookURL := api.DeploymentValues.WorkspaceApprovalWebhook.URL.String()
+	if webhookURL == "" {
+		return
+	}
+	body, err := json.Marshal(codersdk.WorkspaceApprovalWebhookPayload{
+		Approval: codersdk.WorkspaceApproval{
+			ID:               approval.ID,
+			WorkspaceID:      approval.WorkspaceID,
+			WorkspaceBuildID: build.ID,
+			RequestedBy:      approval.RequestedBy,
+			CreatedAt:        approval.CreatedAt,
+			Status:           codersdk.WorkspaceApprovalStatus(approval.Status),
+		},
+		WorkspaceName:     workspace.Name,
+		WorkspaceOwner:    owner.Username,
+		TemplateID:        template.ID,
+		TemplateName:      template.Name,
+		RequesterUsername: requester.Username,
+		CallbackURL:       api.AccessURL.JoinPath("/api/v2/workspaceapprovals", approval.ID.String(), "callback").String(),
+	})
+	if err != nil {
+		api.Logger.Error(api.ctx, "marshal workspace approval webhook payload", slog.Error(err))
+		return
+	}
+
+	api.WebsocketWaitMutex.Lock()
+	api.WebsocketWaitGroup.Add(1)
+	api.WebsocketWaitMutex.Unlock()
+	go func() {
+		defer api.WebsocketWaitGroup.Done()
+		logger := api.Logger.With(slog.F("workspace_approval_id", approval.ID))
+		for attempt := 1; ; attempt++ {
+			err := api.postWorkspaceApprovalWebhook(webhookURL, body)
+			if err == nil {
+				logger.Debug(api.ctx, "sent workspace approval webhook")
+				return
+			}
+			if attempt == workspaceApprovalWebhookAttempts {
+				logger.Warn(api.ctx, "workspace approval webhook failed", slog.Error(err))
+				return
+			}
+			select {
+			case <-api.ctx.Done():
+				return
+			case <-time.After(workspaceApprovalWebhookRetryInterval):
+			}
+		}
+	}()
+}
+
+func (api *API) postWorkspaceApprovalWebhook(webhookURL string, body []byte) error {
+	ctx, cancel := context.WithTimeout(api.ctx, workspaceApprovalWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret := api.DeploymentValues.WorkspaceApprovalWebhook.Secret.String(); secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(codersdk.WorkspaceWebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(codersdk.WorkspaceWebhookSignatureHeader, codersdk.SignWorkspaceWebhook(secret, timestamp, body))
+	}
+	client := api.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<20))
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+// workspaceApprovalRejectedError is the error of builds that were rejected.
+func workspaceApprovalRejectedError(reason string) string {
+	if reason == "" {
+		return "The build was rejected by an approver."
+	}
+	return fmt.Sprintf("The build was rejected by an approver: %s", reason)
+}
+
+func (api *API) convertWorkspaceApproval(ctx context.Context, approval database.WorkspaceApproval) (codersdk.WorkspaceApproval, error) {
+	build, err := api.Database.GetWorkspaceBuildByJobID(ctx, approval.JobID)
+	if err != nil {
+		return codersdk.WorkspaceApproval{}, xerrors.Errorf("get workspace build: %w", err)
+	}
+	apiApproval := codersdk.WorkspaceApproval{
+		ID:               approval.ID,
+		WorkspaceID:      approval.WorkspaceID,
+		WorkspaceBuildID: build.ID,
+		RequestedBy:      approval.RequestedBy,
+		CreatedAt:        approval.CreatedAt,
+		Status:           codersdk.WorkspaceApprovalStatus(approval.Status),
+		Reason:           approval.Reason,
+	}
+	if approval.DecidedBy.Valid {
+		apiApproval.DecidedBy = &approval.DecidedBy.UUID
+	}
+	if approval.DecidedAt.Valid {
+		apiApproval.DecidedAt = &approval.DecidedAt.Time
+	}
+	return apiApproval, nil
+}
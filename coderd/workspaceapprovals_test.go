@@ -0,0 +1,235 @@
+package coderd_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceApprovals(t *testing.T) {
+	t.Parallel()
+
+	// setup creates a template that requires approval, and a workspace from
+	// it owned by a member.
+	setup := func(t *testing.T) (*codersdk.Client, *codersdk.Client, codersdk.Workspace) {
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		approverClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID, rbac.RoleTemplateAdmin())
+		memberClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		template, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			RequireWorkspaceApproval: ptr.Ref(true),
+		})
+		require.NoError(t, err)
+		require.True(t, template.RequireWorkspaceApproval)
+
+		workspace := coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID)
+		return memberClient, approverClient, workspace
+	}
+
+	t.Run("Approve", func(t *testing.T) {
+		t.Parallel()
+		memberClient, approverClient, workspace := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		approval, err := memberClient.WorkspaceApproval(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceApprovalStatusPending, approval.Status)
+		require.Equal(t, workspace.LatestBuild.ID, approval.WorkspaceBuildID)
+		require.Equal(t, workspace.OwnerID, approval.RequestedBy)
+
+		// The build isn't acquired by the provisioner while it's pending.
+		require.Never(t, func() bool {
+			build, err := memberClient.WorkspaceBuild(ctx, workspace.LatestBuild.ID)
+			return err != nil || build.Job.Status != codersdk.ProvisionerJobPending
+		}, testutil.IntervalSlow, testutil.IntervalFast)
+
+		// Workspace owners can't approve their own workspaces.
+		_, err = memberClient.DecideWorkspaceApproval(ctx, approval.ID, codersdk.DecideWorkspaceApprovalRequest{
+			Status: codersdk.WorkspaceApprovalStatusApproved,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		approval, err = approverClient.DecideWorkspaceApproval(ctx, approval.ID, codersdk.DecideWorkspaceApprovalRequest{
+			Status: codersdk.WorkspaceApprovalStatusApproved,
+			Reason: "CHG0012345",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceApprovalStatusApproved, approval.Status)
+		require.Equal(t, "CHG0012345", approval.Reason)
+		require.NotNil(t, approval.DecidedBy)
+		require.NotNil(t, approval.DecidedAt)
+
+		build := coderdtest.AwaitWorkspaceBuildJob(t, memberClient, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+
+		// Requests can only be decided once.
+		_, err = approverClient.DecideWorkspaceApproval(ctx, approval.ID, codersdk.DecideWorkspaceApprovalRequest{
+			Status: codersdk.WorkspaceApprovalStatusRejected,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("Reject", func(t *testing.T) {
+		t.Parallel()
+		memberClient, approverClient, workspace := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		approval, err := memberClient.WorkspaceApproval(ctx, workspace.ID)
+		require.NoError(t, err)
+		approval, err = approverClient.DecideWorkspaceApproval(ctx, approval.ID, codersdk.DecideWorkspaceApprovalRequest{
+			Status: codersdk.WorkspaceApprovalStatusRejected,
+			Reason: "No change ticket",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceApprovalStatusRejected, approval.Status)
+
+		build, err := memberClient.WorkspaceBuild(ctx, workspace.LatestBuild.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ProvisionerJobFailed, build.Job.Status)
+		require.Contains(t, build.Job.Error, "No change ticket")
+	})
+
+	t.Run("Webhook", func(t *testing.T) {
+		t.Parallel()
+
+		const secret = "approval-secret"
+		payloads := make(chan codersdk.WorkspaceApprovalWebhookPayload, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if !assert.NoError(t, err) {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			timestamp, err := strconv.ParseInt(r.Header.Get(codersdk.WorkspaceWebhookTimestampHeader), 10, 64)
+			assert.NoError(t, err)
+			assert.Equal(t, codersdk.SignWorkspaceWebhook(secret, timestamp, body), r.Header.Get(codersdk.WorkspaceWebhookSignatureHeader))
+			var payload codersdk.WorkspaceApprovalWebhookPayload
+			assert.NoError(t, json.Unmarshal(body, &payload))
+			payloads <- payload
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		t.Cleanup(srv.Close)
+
+		dv := coderdtest.DeploymentValues(t)
+		require.NoError(t, dv.WorkspaceApprovalWebhook.URL.Set(srv.URL))
+		require.NoError(t, dv.WorkspaceApprovalWebhook.Secret.Set(secret))
+		client := coderdtest.New(t, &coderdtest.Options{
+			IncludeProvisionerDaemon: true,
+			DeploymentValues:         dv,
+		})
+		user := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		template, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			RequireWorkspaceApproval: ptr.Ref(true),
+		})
+		require.NoError(t, err)
+		workspace := coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID)
+
+		var payload codersdk.WorkspaceApprovalWebhookPayload
+		select {
+		case payload = <-payloads:
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the approval webhook")
+		}
+		require.Equal(t, workspace.ID, payload.Approval.WorkspaceID)
+		require.Equal(t, workspace.LatestBuild.ID, payload.Approval.WorkspaceBuildID)
+		require.Equal(t, codersdk.WorkspaceApprovalStatusPending, payload.Approval.Status)
+		require.Equal(t, workspace.Name, payload.WorkspaceName)
+		require.Equal(t, member.Username, payload.WorkspaceOwner)
+		require.Equal(t, member.Username, payload.RequesterUsername)
+		require.Equal(t, template.ID, payload.TemplateID)
+		require.Equal(t, client.URL.String()+"/api/v2/workspaceapprovals/"+payload.Approval.ID.String()+"/callback", payload.CallbackURL)
+
+		// Callbacks must be signed with the webhook secret.
+		anonClient := codersdk.New(client.URL)
+		_, err = anonClient.DecideWorkspaceApprovalCallback(ctx, payload.Approval.ID, "wrong-secret", codersdk.DecideWorkspaceApprovalRequest{
+			Status: codersdk.WorkspaceApprovalStatusApproved,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		approval, err := anonClient.DecideWorkspaceApprovalCallback(ctx, payload.Approval.ID, secret, codersdk.DecideWorkspaceApprovalRequest{
+			Status: codersdk.WorkspaceApprovalStatusApproved,
+			Reason: "CHG0012345",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceApprovalStatusApproved, approval.Status)
+		require.Equal(t, "CHG0012345", approval.Reason)
+		require.Nil(t, approval.DecidedBy)
+		require.NotNil(t, approval.DecidedAt)
+
+		build := coderdtest.AwaitWorkspaceBuildJob(t, memberClient, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+
+		// Requests can only be decided once.
+		_, err = anonClient.DecideWorkspaceApprovalCallback(ctx, payload.Approval.ID, secret, codersdk.DecideWorkspaceApprovalRequest{
+			Status: codersdk.WorkspaceApprovalStatusRejected,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("CallbackDisabled", func(t *testing.T) {
+		t.Parallel()
+		memberClient, _, workspace := setup(t)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		approval, err := memberClient.WorkspaceApproval(ctx, workspace.ID)
+		require.NoError(t, err)
+		// Without a secret, nothing can sign a valid callback.
+		_, err = codersdk.New(memberClient.URL).DecideWorkspaceApprovalCallback(ctx, approval.ID, "", codersdk.DecideWorkspaceApprovalRequest{
+			Status: codersdk.WorkspaceApprovalStatusApproved,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("NotRequired", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.WorkspaceApproval(ctx, workspace.ID)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
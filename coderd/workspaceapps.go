@@ -3,14 +3,19 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
 	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/apikey"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
@@ -40,6 +45,16 @@ func (api *API) appHost(rw http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// @Summary Get workspace app identity keys
+// @ID get-workspace-app-identity-keys
+// @Produce json
+// @Tags Applications
+// @Success 200
+// @Router /applications/jwks [get]
+func (api *API) workspaceApplicationIdentityKeys(rw http.ResponseWriter, r *http.Request) {
+	httpapi.Write(r.Context(), rw, http.StatusOK, api.AppSecurityKey.IdentityKeys())
+}
+
 // workspaceApplicationAuth is an endpoint on the main router that handles
 // redirects from the subdomain handler.
 //
@@ -208,3 +223,96 @@ func (api *API) ValidWorkspaceAppHostname(ctx context.Context, host string, opts
 
 	return "", nil
 }
+
+// AuditWorkspaceAccess records users opening workspace apps and terminals in
+// the audit log. Events are identified by their ID, so recording an event that
+// was already recorded is a no-op. This allows workspace proxies to retry
+// delivery of events safely.
+func (api *API) AuditWorkspaceAccess(ctx context.Context, events ...workspaceapps.AccessEvent) error {
+	// nolint:gocritic // Proxies and the app server act on behalf of the system.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	auditor := *api.Auditor.Load()
+
+	workspaces := make(map[uuid.UUID]database.Workspace)
+	for _, event := range events {
+		workspace, ok := workspaces[event.WorkspaceID]
+		if !ok {
+			var err error
+			workspace, err = api.Database.GetWorkspaceByID(ctx, event.WorkspaceID)
+			if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+				return xerrors.Errorf("get workspace %s: %w", event.WorkspaceID, err)
+			}
+			workspaces[event.WorkspaceID] = workspace
+		}
+
+		action := database.AuditActionOpen
+		if event.AccessMethod == workspaceapps.AccessMethodTerminal {
+			action = database.AuditActionConnect
+		}
+		additionalFields, err := json.Marshal(workspaceAccessAuditFields{
+			AgentID:      event.AgentID,
+			AccessMethod: event.AccessMethod,
+			SlugOrPort:   event.SlugOrPort,
+		})
+		if err != nil {
+			return xerrors.Errorf("marshal additional fields: %w", err)
+		}
+
+		err = auditor.Export(ctx, database.AuditLog{
+			ID:               event.ID,
+			Time:             event.Time,
+			UserID:           event.UserID,
+			OrganizationID:   workspace.OrganizationID,
+			Ip:               parseAccessEventIP(event.IP),
+			UserAgent:        sql.NullString{String: event.UserAgent, Valid: event.UserAgent != ""},
+			ResourceType:     database.ResourceTypeWorkspace,
+			ResourceID:       event.WorkspaceID,
+			ResourceTarget:   workspace.Name,
+			Action:           action,
+			Diff:             []byte("{}"),
+			StatusCode:       http.StatusOK,
+			AdditionalFields: additionalFields,
+			RequestID:        event.ID,
+		})
+		if err != nil && !database.IsUniqueViolation(err) {
+			return xerrors.Errorf("export audit log for access event %s: %w", event.ID, err)
+		}
+	}
+	return nil
+}
+
+type workspaceAccessAuditFields struct {
+	AgentID      uuid.UUID                  `json:"agent_id"`
+	AccessMethod workspaceapps.AccessMethod `json:"access_method"`
+	SlugOrPort   string                     `json:"slug_or_port,omitempty"`
+}
+
+func parseAccessEventIP(s string) pqtype.Inet {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return pqtype.Inet{}
+	}
+	return pqtype.Inet{
+		IPNet: net.IPNet{
+			IP:   ip,
+			Mask: net.CIDRMask(len(ip)*8, len(ip)*8),
+		},
+		Valid: true,
+	}
+}
+
+// primaryAccessAuditor records access events from the app server embedded in
+// coderd directly.
+type primaryAccessAuditor struct {
+	api *API
+}
+
+func (a primaryAccessAuditor) AuditAccess(ctx context.Context, event workspaceapps.AccessEvent) {
+	err := a.api.AuditWorkspaceAccess(ctx, event)
+	if err != nil {
+		a.api.Logger.Error(ctx, "audit workspace access", slog.F("event_id", event.ID), slog.Error(err))
+	}
+}
@@ -19,10 +19,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
+	"nhooyr.io/websocket"
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -105,6 +108,56 @@ func Run(t *testing.T, appHostIsPrimary bool, factory DeploymentFactory) {
 				SignedToken: issueRes.SignedToken,
 			})
 		})
+
+		t.Run("Resume", func(t *testing.T) {
+			t.Parallel()
+			appDetails := setupProxyTest(t, nil)
+			ctx := testutil.Context(t, testutil.WaitLong)
+			client := appDetails.AppClient(t)
+			opts := codersdk.WorkspaceAgentReconnectingPTYOpts{
+				AgentID:   appDetails.Agent.ID,
+				Reconnect: uuid.New(),
+				Height:    100,
+				Width:     100,
+				Command:   "bash",
+				Resume:    true,
+			}
+
+			// Sessions that don't run in the agent can't be resumed.
+			conn, err := client.WorkspaceAgentReconnectingPTY(ctx, opts)
+			require.NoError(t, err)
+			defer conn.Close()
+			_, err = io.ReadAll(conn)
+			require.Equal(t, codersdk.ReconnectingPTYSessionEndedStatus, websocket.CloseStatus(err))
+
+			opts.Resume = false
+			conn, err = client.WorkspaceAgentReconnectingPTY(ctx, opts)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			// Brief pause to reduce the likelihood that we send keystrokes
+			// while the shell is simultaneously sending a prompt.
+			time.Sleep(100 * time.Millisecond)
+
+			data, err := json.Marshal(codersdk.ReconnectingPTYRequest{
+				Data: "echo test\r\n",
+			})
+			require.NoError(t, err)
+			_, err = conn.Write(data)
+			require.NoError(t, err)
+			matchEchoOutput := func(line string) bool {
+				return strings.Contains(line, "test") && !strings.Contains(line, "echo")
+			}
+			require.NoError(t, testutil.ReadUntil(ctx, t, conn, matchEchoOutput), "find echo output")
+			_ = conn.Close()
+
+			// The resumed session replays the output of the terminal.
+			opts.Resume = true
+			conn, err = client.WorkspaceAgentReconnectingPTY(ctx, opts)
+			require.NoError(t, err)
+			defer conn.Close()
+			require.NoError(t, testutil.ReadUntil(ctx, t, conn, matchEchoOutput), "find echo output")
+		})
 	})
 
 	t.Run("WorkspaceAppsProxyPath", func(t *testing.T) {
@@ -1282,6 +1335,77 @@ func Run(t *testing.T, appHostIsPrimary bool, factory DeploymentFactory) {
 		}
 	})
 
+	t.Run("IdentityHeaders", func(t *testing.T) {
+		t.Parallel()
+
+		// Identity headers are enabled for port-forwarded apps, which are
+		// shared at the owner level, but only for declared apps shared with
+		// authenticated users.
+		appDetails := setupProxyTest(t, &DeploymentOptions{
+			AppIdentityHeaders:         []string{string(codersdk.WorkspaceAppSharingLevelAuthenticated)},
+			PortForwardIdentityHeaders: []string{string(codersdk.WorkspaceAppSharingLevelOwner)},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		res, err := appDetails.SDKClient.Request(ctx, http.MethodGet, "/api/v2/applications/jwks", nil)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		var keys jose.JSONWebKeySet
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&keys))
+
+		for _, c := range []struct {
+			name     string
+			app      App
+			identity bool
+		}{
+			{name: "Port", app: appDetails.Apps.Port, identity: true},
+			{name: "Authenticated", app: appDetails.Apps.Authenticated, identity: true},
+			{name: "Owner", app: appDetails.Apps.Owner, identity: false},
+		} {
+			c := c
+			t.Run(c.name, func(t *testing.T) {
+				t.Parallel()
+
+				ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+				defer cancel()
+
+				u := appDetails.SubdomainAppURL(c.app)
+				resp, err := requestWithRetries(ctx, t, appDetails.AppClient(t), http.MethodGet, u.String(), nil, func(r *http.Request) {
+					// Identities claimed by clients must never reach apps.
+					r.Header.Set(codersdk.WorkspaceAppUsernameHeader, "impostor")
+				})
+				require.NoError(t, err)
+				defer resp.Body.Close()
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				if !c.identity {
+					for _, name := range identityHeaders {
+						require.Empty(t, resp.Header.Get(name), name)
+					}
+					return
+				}
+				require.Equal(t, appDetails.Me.ID.String(), resp.Header.Get(codersdk.WorkspaceAppUserIDHeader))
+				require.Equal(t, appDetails.Me.Username, resp.Header.Get(codersdk.WorkspaceAppUsernameHeader))
+				require.Equal(t, appDetails.Me.Email, resp.Header.Get(codersdk.WorkspaceAppUserEmailHeader))
+
+				identity := resp.Header.Get(codersdk.WorkspaceAppIdentityHeader)
+				token, err := jwt.ParseSigned(identity)
+				require.NoError(t, err)
+				var unverified codersdk.WorkspaceAppIdentityClaims
+				require.NoError(t, token.UnsafeClaimsWithoutVerification(&unverified))
+				claims, err := workspaceapps.VerifyIdentity(keys, unverified.Audience, identity)
+				require.NoError(t, err)
+				require.Equal(t, appDetails.Me.ID.String(), claims.Subject)
+				audience, err := url.Parse(claims.Audience)
+				require.NoError(t, err)
+				require.Equal(t, u.Host, audience.Host)
+			})
+		}
+	})
+
 	t.Run("CORSHeadersStripped", func(t *testing.T) {
 		t.Parallel()
 
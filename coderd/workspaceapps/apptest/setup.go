@@ -51,6 +51,8 @@ type DeploymentOptions struct {
 	DangerousAllowPathAppSharing         bool
 	DangerousAllowPathAppSiteOwnerAccess bool
 	ServeHTTPS                           bool
+	AppIdentityHeaders                   []string
+	PortForwardIdentityHeaders           []string
 
 	StatsCollectorOptions workspaceapps.StatsCollectorOptions
 
@@ -239,6 +241,15 @@ func setupProxyTestWithFactory(t *testing.T, factory DeploymentFactory, opts *De
 	return details
 }
 
+// identityHeaders are echoed back by the app server so tests can check the
+// identity headers that apps receive.
+var identityHeaders = []string{
+	codersdk.WorkspaceAppIdentityHeader,
+	codersdk.WorkspaceAppUserIDHeader,
+	codersdk.WorkspaceAppUsernameHeader,
+	codersdk.WorkspaceAppUserEmailHeader,
+}
+
 //nolint:revive
 func appServer(t *testing.T, headers http.Header, isHTTPS bool) uint16 {
 	server := httptest.NewUnstartedServer(
@@ -247,6 +258,11 @@ func appServer(t *testing.T, headers http.Header, isHTTPS bool) uint16 {
 				_, err := r.Cookie(codersdk.SessionTokenCookie)
 				assert.ErrorIs(t, err, http.ErrNoCookie)
 				w.Header().Set("X-Forwarded-For", r.Header.Get("X-Forwarded-For"))
+				for _, name := range identityHeaders {
+					if value := r.Header.Get(name); value != "" {
+						w.Header().Set(name, value)
+					}
+				}
 				for name, values := range headers {
 					for _, value := range values {
 						w.Header().Add(name, value)
@@ -0,0 +1,235 @@
+package workspaceapps
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ammario/tlru"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// AssetCacheHeader is set on workspace app responses served by an AssetCache.
+const AssetCacheHeader = "X-Coder-Asset-Cache"
+
+type assetKey struct {
+	agentID uuid.UUID
+	appURL  string
+	path    string
+	query   string
+	// encoding is the Accept-Encoding of the request, since assets are
+	// commonly compressed based on it.
+	encoding string
+}
+
+type cachedAsset struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// AssetCache caches immutable static assets served by workspace apps, such as
+// the versioned scripts and stylesheets of web IDEs, so repeated requests for
+// them don't need a round-trip to the workspace agent.
+//
+// Only responses that mark themselves as immutable and shareable with
+// Cache-Control are stored, for at most their max-age. The cache is bounded by
+// the total size of the stored bodies and evicts the least recently used
+// assets first.
+type AssetCache struct {
+	cache        *tlru.Cache[assetKey, cachedAsset]
+	maxAssetSize int
+}
+
+// NewAssetCache returns a cache holding at most maxSize bytes of assets.
+func NewAssetCache(maxSize int) *AssetCache {
+	return &AssetCache{
+		cache: tlru.New[assetKey](func(asset cachedAsset) int {
+			return len(asset.body)
+		}, maxSize),
+		// A single asset may not take more than a quarter of the cache, so a
+		// few large assets can't keep evicting everything else.
+		maxAssetSize: maxSize / 4,
+	}
+}
+
+func newAssetKey(appToken SignedToken, r *http.Request) assetKey {
+	return assetKey{
+		agentID:  appToken.AgentID,
+		appURL:   appToken.AppURL,
+		path:     r.URL.Path,
+		query:    r.URL.RawQuery,
+		encoding: r.Header.Get("Accept-Encoding"),
+	}
+}
+
+// cacheableRequest returns true if the response to the request may be served
+// from or stored in the cache.
+func cacheableRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if r.Header.Get("Range") != "" {
+		return false
+	}
+	for _, directive := range cacheControlDirectives(r.Header) {
+		if directive == "no-cache" || directive == "no-store" {
+			return false
+		}
+	}
+	return true
+}
+
+// Serve writes the cached response to the request if there is one.
+func (c *AssetCache) Serve(rw http.ResponseWriter, r *http.Request, appToken SignedToken) bool {
+	if !cacheableRequest(r) {
+		return false
+	}
+	asset, _, ok := c.cache.Get(newAssetKey(appToken, r))
+	if !ok {
+		return false
+	}
+
+	for k, values := range asset.header {
+		for _, v := range values {
+			rw.Header().Add(k, v)
+		}
+	}
+	rw.Header().Set(AssetCacheHeader, "hit")
+	rw.Header().Set("Content-Length", strconv.Itoa(len(asset.body)))
+	rw.WriteHeader(asset.statusCode)
+	if r.Method != http.MethodHead {
+		_, _ = rw.Write(asset.body)
+	}
+	return true
+}
+
+// Store adds the response to the cache if it is an immutable, shareable asset
+// small enough to be cached. The response body is replaced so it can still be
+// read by the caller.
+func (c *AssetCache) Store(res *http.Response, appToken SignedToken) error {
+	if res.Request == nil || res.Request.Method != http.MethodGet || !cacheableRequest(res.Request) {
+		return nil
+	}
+	ttl, ok := assetTTL(res)
+	if !ok {
+		return nil
+	}
+	if res.ContentLength > int64(c.maxAssetSize) {
+		return nil
+	}
+
+	// Read one byte past the limit to detect bodies without a length that
+	// are too large, and hand whatever was read back to the caller.
+	body, err := io.ReadAll(io.LimitReader(res.Body, int64(c.maxAssetSize)+1))
+	if err != nil {
+		return xerrors.Errorf("read body: %w", err)
+	}
+	if len(body) > c.maxAssetSize {
+		res.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), res.Body), Closer: res.Body}
+		return nil
+	}
+	_ = res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := res.Header.Clone()
+	// Hop-by-hop and per-connection headers are set by the server for each
+	// response.
+	header.Del("Connection")
+	header.Del("Content-Length")
+	header.Del("Transfer-Encoding")
+	c.cache.Set(newAssetKey(appToken, res.Request), cachedAsset{
+		statusCode: res.StatusCode,
+		header:     header,
+		body:       body,
+	}, ttl)
+	return nil
+}
+
+// assetTTL returns how long the response may be cached for. Only successful,
+// immutable responses that any cache is allowed to store are cached.
+func assetTTL(res *http.Response) (time.Duration, bool) {
+	if res.StatusCode != http.StatusOK {
+		return 0, false
+	}
+	// Responses that set cookies or vary on anything but the encoding, which
+	// is part of the cache key, can't be shared between clients.
+	if res.Header.Get("Set-Cookie") != "" {
+		return 0, false
+	}
+	for _, value := range res.Header.Values("Vary") {
+		for _, header := range strings.Split(value, ",") {
+			if !strings.EqualFold(strings.TrimSpace(header), "Accept-Encoding") {
+				return 0, false
+			}
+		}
+	}
+
+	var (
+		immutable bool
+		public    bool
+		maxAge    = -1
+		sMaxAge   = -1
+	)
+	for _, directive := range cacheControlDirectives(res.Header) {
+		name, value, _ := strings.Cut(directive, "=")
+		switch name {
+		case "immutable":
+			immutable = true
+		case "public":
+			public = true
+		case "private", "no-store", "no-cache":
+			return 0, false
+		case "max-age":
+			maxAge = parseDeltaSeconds(value)
+		case "s-maxage":
+			sMaxAge = parseDeltaSeconds(value)
+		}
+	}
+	if !immutable {
+		return 0, false
+	}
+	// Responses to authenticated requests are only shared if they explicitly
+	// allow it.
+	if res.Request.Header.Get("Authorization") != "" && !public && sMaxAge < 0 {
+		return 0, false
+	}
+	// Shared caches prefer s-maxage over max-age.
+	if sMaxAge >= 0 {
+		maxAge = sMaxAge
+	}
+	if maxAge <= 0 {
+		return 0, false
+	}
+	return time.Duration(maxAge) * time.Second, true
+}
+
+func cacheControlDirectives(header http.Header) []string {
+	var directives []string
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			directive = strings.ToLower(strings.TrimSpace(directive))
+			if directive != "" {
+				directives = append(directives, directive)
+			}
+		}
+	}
+	return directives
+}
+
+func parseDeltaSeconds(value string) int {
+	seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+	if err != nil || seconds < 0 {
+		return -1
+	}
+	return seconds
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,162 @@
+package workspaceapps_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/workspaceapps"
+)
+
+func TestAssetCache(t *testing.T) {
+	t.Parallel()
+
+	appToken := workspaceapps.SignedToken{
+		AgentID: uuid.New(),
+		AppURL:  "http://127.0.0.1:8080",
+	}
+
+	newResponse := func(path string, cacheControl string, body string) *http.Response {
+		res := &http.Response{
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       httptest.NewRequest(http.MethodGet, path, nil),
+		}
+		res.Header.Set("Content-Type", "text/javascript")
+		res.Header.Set("Cache-Control", cacheControl)
+		return res
+	}
+
+	// store stores the response and asserts its body is still readable.
+	store := func(t *testing.T, cache *workspaceapps.AssetCache, res *http.Response, token workspaceapps.SignedToken) {
+		t.Helper()
+		want := new(bytes.Buffer)
+		_, err := io.Copy(want, res.Body)
+		require.NoError(t, err)
+		res.Body = io.NopCloser(bytes.NewReader(want.Bytes()))
+
+		err = cache.Store(res, token)
+		require.NoError(t, err)
+		got, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, want.String(), string(got))
+	}
+
+	serve := func(cache *workspaceapps.AssetCache, path string, token workspaceapps.SignedToken) (*httptest.ResponseRecorder, bool) {
+		rw := httptest.NewRecorder()
+		return rw, cache.Serve(rw, httptest.NewRequest(http.MethodGet, path, nil), token)
+	}
+
+	t.Run("Immutable", func(t *testing.T) {
+		t.Parallel()
+		cache := workspaceapps.NewAssetCache(1 << 20)
+		store(t, cache, newResponse("/static/main.js", "public, max-age=31536000, immutable", "console.log(1)"), appToken)
+
+		rw, ok := serve(cache, "/static/main.js", appToken)
+		require.True(t, ok)
+		require.Equal(t, http.StatusOK, rw.Code)
+		require.Equal(t, "console.log(1)", rw.Body.String())
+		require.Equal(t, "text/javascript", rw.Header().Get("Content-Type"))
+		require.Equal(t, "hit", rw.Header().Get(workspaceapps.AssetCacheHeader))
+
+		// Other paths and other agents serving the same app URL don't share
+		// the asset.
+		_, ok = serve(cache, "/static/other.js", appToken)
+		require.False(t, ok)
+		otherToken := appToken
+		otherToken.AgentID = uuid.New()
+		_, ok = serve(cache, "/static/main.js", otherToken)
+		require.False(t, ok)
+	})
+
+	t.Run("NotCached", func(t *testing.T) {
+		t.Parallel()
+		for _, tc := range []struct {
+			name   string
+			mutate func(res *http.Response)
+		}{{
+			name: "NotImmutable",
+			mutate: func(res *http.Response) {
+				res.Header.Set("Cache-Control", "public, max-age=3600")
+			},
+		}, {
+			name: "Private",
+			mutate: func(res *http.Response) {
+				res.Header.Set("Cache-Control", "private, max-age=3600, immutable")
+			},
+		}, {
+			name: "NoMaxAge",
+			mutate: func(res *http.Response) {
+				res.Header.Set("Cache-Control", "immutable")
+			},
+		}, {
+			name: "NotOK",
+			mutate: func(res *http.Response) {
+				res.StatusCode = http.StatusNotFound
+			},
+		}, {
+			name: "SetCookie",
+			mutate: func(res *http.Response) {
+				res.Header.Set("Set-Cookie", "session=abc")
+			},
+		}, {
+			name: "Vary",
+			mutate: func(res *http.Response) {
+				res.Header.Set("Vary", "Accept-Encoding, Cookie")
+			},
+		}, {
+			name: "Authorization",
+			mutate: func(res *http.Response) {
+				res.Header.Set("Cache-Control", "max-age=3600, immutable")
+				res.Request.Header.Set("Authorization", "Bearer token")
+			},
+		}, {
+			name: "NoStoreRequest",
+			mutate: func(res *http.Response) {
+				res.Request.Header.Set("Cache-Control", "no-store")
+			},
+		}, {
+			name: "TooLarge",
+			mutate: func(res *http.Response) {
+				body := strings.Repeat("a", 1<<10)
+				res.Body = io.NopCloser(strings.NewReader(body))
+				res.ContentLength = -1
+			},
+		}} {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				cache := workspaceapps.NewAssetCache(1 << 10)
+				res := newResponse("/static/main.js", "public, max-age=31536000, immutable", "console.log(1)")
+				tc.mutate(res)
+				store(t, cache, res, appToken)
+
+				_, ok := serve(cache, "/static/main.js", appToken)
+				require.False(t, ok)
+			})
+		}
+	})
+
+	t.Run("Evicts", func(t *testing.T) {
+		t.Parallel()
+		cache := workspaceapps.NewAssetCache(1 << 10)
+		body := strings.Repeat("a", 200)
+		for _, path := range []string{"/1.js", "/2.js", "/3.js", "/4.js", "/5.js", "/6.js"} {
+			store(t, cache, newResponse(path, "max-age=3600, immutable", body), appToken)
+		}
+
+		// The least recently used assets are evicted to fit the newest ones.
+		_, ok := serve(cache, "/1.js", appToken)
+		require.False(t, ok)
+		_, ok = serve(cache, "/6.js", appToken)
+		require.True(t, ok)
+	})
+}
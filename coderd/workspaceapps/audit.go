@@ -0,0 +1,95 @@
+package workspaceapps
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/database"
+)
+
+// accessAuditWindow is how long an app access is remembered for. Requests to
+// the same app by the same user within the window are not audited again, so
+// only the first request of a browsing session produces an audit log.
+const accessAuditWindow = time.Hour
+
+// AccessEvent is an audit-relevant access to a workspace, i.e. a user opening
+// a workspace app or connecting to a terminal.
+type AccessEvent struct {
+	// ID uniquely identifies the event. It's used as the audit log ID so that
+	// events delivered more than once are only recorded once.
+	ID           uuid.UUID    `json:"id"`
+	Time         time.Time    `json:"time"`
+	UserID       uuid.UUID    `json:"user_id"`
+	WorkspaceID  uuid.UUID    `json:"workspace_id"`
+	AgentID      uuid.UUID    `json:"agent_id"`
+	AccessMethod AccessMethod `json:"access_method"`
+	SlugOrPort   string       `json:"slug_or_port"`
+	IP           string       `json:"ip"`
+	UserAgent    string       `json:"user_agent"`
+}
+
+func newAccessEvent(r *http.Request, token SignedToken) AccessEvent {
+	return AccessEvent{
+		ID:           uuid.New(),
+		Time:         database.Now(),
+		UserID:       token.UserID,
+		WorkspaceID:  token.WorkspaceID,
+		AgentID:      token.AgentID,
+		AccessMethod: token.AccessMethod,
+		SlugOrPort:   token.AppSlugOrPort,
+		IP:           r.RemoteAddr,
+		UserAgent:    r.UserAgent(),
+	}
+}
+
+// AccessAuditor records AccessEvents in the audit log.
+type AccessAuditor interface {
+	// AuditAccess must not block the request that produced the event.
+	AuditAccess(ctx context.Context, event AccessEvent)
+}
+
+type accessAuditKey struct {
+	userID     uuid.UUID
+	agentID    uuid.UUID
+	slugOrPort string
+}
+
+// accessAuditDeduper tracks recently audited app accesses.
+type accessAuditDeduper struct {
+	mu      sync.Mutex
+	audited map[accessAuditKey]time.Time
+}
+
+// shouldAudit returns true if the access hasn't been audited within the
+// window, and remembers it if so.
+func (d *accessAuditDeduper) shouldAudit(event AccessEvent) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.audited == nil {
+		d.audited = make(map[accessAuditKey]time.Time)
+	}
+	key := accessAuditKey{
+		userID:     event.UserID,
+		agentID:    event.AgentID,
+		slugOrPort: event.SlugOrPort,
+	}
+	if last, ok := d.audited[key]; ok && event.Time.Sub(last) < accessAuditWindow {
+		return false
+	}
+	d.audited[key] = event.Time
+
+	// Keep the map from growing forever on long-running servers.
+	if len(d.audited) > 1024 {
+		for k, last := range d.audited {
+			if event.Time.Sub(last) >= accessAuditWindow {
+				delete(d.audited, k)
+			}
+		}
+	}
+	return true
+}
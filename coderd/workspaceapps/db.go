@@ -10,10 +10,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
@@ -99,6 +102,20 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 
 	// Lookup workspace app details from DB.
 	dbReq, err := appReq.getDatabase(dangerousSystemCtx, p.Database)
+	var renamedReq *Request
+	if xerrors.Is(err, sql.ErrNoRows) {
+		// The workspace may have been renamed recently. The app is resolved
+		// under the new name and, once the user is authorized, they are
+		// redirected to it.
+		name, renamedErr := appReq.getRenamedWorkspaceName(dangerousSystemCtx, p.Database)
+		if renamedErr == nil {
+			req := appReq.withWorkspaceName(name)
+			renamedReq = &req
+			dbReq, err = req.getDatabase(dangerousSystemCtx, p.Database)
+		} else if !xerrors.Is(renamedErr, sql.ErrNoRows) {
+			err = renamedErr
+		}
+	}
 	if xerrors.Is(err, sql.ErrNoRows) {
 		WriteWorkspaceApp404(p.Logger, p.DashboardURL, rw, r, &appReq, err.Error())
 		return nil, "", false
@@ -113,6 +130,33 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		token.AppURL = dbReq.AppURL.String()
 	}
 
+	template, err := p.Database.GetTemplateByID(dangerousSystemCtx, dbReq.Workspace.TemplateID)
+	if err != nil {
+		WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "get template")
+		return nil, "", false
+	}
+	token.MaxSessionIdle = time.Duration(template.MaxSessionIdle)
+
+	// Apps served from ports of the agent are subject to the port policies,
+	// which also cap how far they can be shared.
+	port, isAgentPort := dbReq.agentPort()
+	portAllowed := true
+	if isAgentPort {
+		portPolicy, err := db2sdk.PortPolicy(template.PortPolicy)
+		if err != nil {
+			WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "decode template port policy")
+			return nil, "", false
+		}
+		var maxSharingLevel codersdk.WorkspaceAppSharingLevel
+		portAllowed, maxSharingLevel = codersdk.CheckPortPolicies(port, p.DeploymentValues.PortForwardPolicy.Value, portPolicy)
+		if !portAllowed {
+			// Nobody but the owner may reach the port, and only to be told
+			// that it isn't allowed.
+			maxSharingLevel = codersdk.WorkspaceAppSharingLevelOwner
+		}
+		dbReq.AppSharingLevel = database.AppSharingLevel(codersdk.MinSharingLevel(codersdk.WorkspaceAppSharingLevel(dbReq.AppSharingLevel), maxSharingLevel))
+	}
+
 	// Verify the user has access to the app.
 	authed, err := p.authorizeRequest(r.Context(), authz, dbReq)
 	if err != nil {
@@ -187,6 +231,38 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		return nil, "", false
 	}
 
+	if renamedReq != nil {
+		redirectReq := issueReq
+		redirectReq.AppRequest = *renamedReq
+		redirectURL, err := redirectReq.AppBaseURL()
+		if err != nil {
+			WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "get renamed app base URL")
+			return nil, "", false
+		}
+		if issueReq.AppPath != "" {
+			redirectURL.Path = strings.TrimSuffix(redirectURL.Path, "/") + "/" + strings.TrimPrefix(issueReq.AppPath, "/")
+		}
+		redirectURL.RawQuery = issueReq.AppQuery
+		http.Redirect(rw, r, redirectURL.String(), http.StatusTemporaryRedirect)
+		return nil, "", false
+	}
+
+	if !portAllowed {
+		WriteWorkspaceApp404(p.Logger, p.DashboardURL, rw, r, &appReq, fmt.Sprintf("Port %d is not allowed by the port policy of the workspace.", port))
+		return nil, "", false
+	}
+
+	// Terminals are counted against the limits of the user opening them, who
+	// isn't necessarily the owner of the workspace.
+	if appReq.AccessMethod == AccessMethodTerminal && apiKey != nil {
+		limits, err := p.ptySessionLimits(dangerousSystemCtx, apiKey.UserID)
+		if err != nil {
+			WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "get terminal session limits")
+			return nil, "", false
+		}
+		token.PTYSessionLimits = &limits
+	}
+
 	// Check that the agent is online.
 	agentStatus := dbReq.Agent.Status(p.WorkspaceAgentInactiveTimeout)
 	if agentStatus.Status != database.WorkspaceAgentStatusConnected {
@@ -200,6 +276,16 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 		return nil, "", false
 	}
 
+	// Attach the identity of the user if identity headers are enabled for the
+	// sharing level of the app. Anonymous users of public apps have none.
+	if apiKey != nil && p.identityHeadersEnabled(dbReq) {
+		token.Identity, err = p.signIdentity(dangerousSystemCtx, issueReq, apiKey.UserID)
+		if err != nil {
+			WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "sign identity")
+			return nil, "", false
+		}
+	}
+
 	// As a sanity check, ensure the token we just made is valid for this
 	// request.
 	if !token.MatchesRequest(appReq) {
@@ -218,6 +304,59 @@ func (p *DBTokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *
 	return &token, tokenStr, true
 }
 
+// identityHeadersEnabled returns true if requests to the app should carry the
+// identity of the requesting user.
+func (p *DBTokenProvider) identityHeadersEnabled(dbReq *databaseRequest) bool {
+	if dbReq.AppURL == nil {
+		return false
+	}
+	levels := p.DeploymentValues.AppIdentityHeaders.Value()
+	if dbReq.AppIsPort {
+		levels = p.DeploymentValues.PortForwardIdentityHeaders.Value()
+	}
+	return slices.Contains(levels, string(dbReq.AppSharingLevel))
+}
+
+func (p *DBTokenProvider) signIdentity(ctx context.Context, issueReq IssueTokenRequest, userID uuid.UUID) (string, error) {
+	user, err := p.Database.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", xerrors.Errorf("get user: %w", err)
+	}
+	appBaseURL, err := issueReq.AppBaseURL()
+	if err != nil {
+		return "", xerrors.Errorf("get app base URL: %w", err)
+	}
+	now := time.Now()
+	return p.SigningKey.SignIdentity(codersdk.WorkspaceAppIdentityClaims{
+		Issuer:    p.DashboardURL.String(),
+		Subject:   user.ID.String(),
+		Audience:  appBaseURL.String(),
+		IssuedAt:  now.Unix(),
+		Expiry:    now.Add(IdentityExpiry).Unix(),
+		Username:  user.Username,
+		Email:     user.Email,
+		AvatarURL: user.AvatarURL.String,
+	})
+}
+
+// ptySessionLimits returns the limits of concurrent terminal sessions of the
+// user. An override of the user takes precedence over the limit of the
+// deployment.
+func (p *DBTokenProvider) ptySessionLimits(ctx context.Context, userID uuid.UUID) (PTYSessionLimits, error) {
+	limits := PTYSessionLimits{
+		UserID:       userID,
+		PerUser:      p.DeploymentValues.MaxPTYSessionsPerUser.Value(),
+		PerWorkspace: p.DeploymentValues.MaxPTYSessionsPerWorkspace.Value(),
+	}
+	override, err := p.Database.GetUserPTYSessionOverride(ctx, userID)
+	if err == nil {
+		limits.PerUser = int64(override.MaxPTYSessions)
+	} else if !xerrors.Is(err, sql.ErrNoRows) {
+		return PTYSessionLimits{}, xerrors.Errorf("get user pty session override: %w", err)
+	}
+	return limits, nil
+}
+
 func (p *DBTokenProvider) authorizeRequest(ctx context.Context, roles *httpmw.Authorization, dbReq *databaseRequest) (bool, error) {
 	accessMethod := dbReq.AccessMethod
 	if accessMethod == "" {
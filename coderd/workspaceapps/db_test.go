@@ -20,6 +20,8 @@ import (
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/agent"
 	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/codersdk"
@@ -768,6 +770,82 @@ func Test_ResolveRequest(t *testing.T) {
 		require.Equal(t, "/some-path", redirectURI.Path)
 	})
 
+	t.Run("RenamedWorkspace", func(t *testing.T) {
+		t.Parallel()
+
+		// Record a previous name for the workspace instead of renaming it so
+		// the other subtests are unaffected.
+		oldName := "old-" + workspace.Name
+		if len(oldName) > 32 {
+			oldName = oldName[:32]
+		}
+		now := database.Now()
+		err := api.Database.UpsertWorkspacePreviousName(dbauthz.AsSystemRestricted(ctx), database.UpsertWorkspacePreviousNameParams{
+			WorkspaceID:       workspace.ID,
+			Name:              oldName,
+			RenamedAt:         now,
+			RedirectExpiresAt: now.Add(time.Hour),
+		})
+		require.NoError(t, err)
+
+		req := workspaceapps.Request{
+			AccessMethod:      workspaceapps.AccessMethodPath,
+			BasePath:          fmt.Sprintf("/@%s/%s.%s/apps/%s/", me.Username, oldName, agentName, appNameOwner),
+			UsernameOrID:      me.Username,
+			WorkspaceNameOrID: oldName,
+			AgentNameOrID:     agentName,
+			AppSlugOrPort:     appNameOwner,
+		}
+
+		rw := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/some-path?foo=bar", nil)
+		r.Header.Set(codersdk.SessionTokenHeader, client.SessionToken())
+
+		token, ok := workspaceapps.ResolveRequest(rw, r, workspaceapps.ResolveRequestOptions{
+			Logger:              api.Logger,
+			SignedTokenProvider: api.WorkspaceAppsProvider,
+			DashboardURL:        api.AccessURL,
+			PathAppBaseURL:      api.AccessURL,
+			AppHostname:         api.AppHostname,
+			AppRequest:          req,
+			AppPath:             "/some-path",
+			AppQuery:            "foo=bar",
+		})
+		require.False(t, ok)
+		require.Nil(t, token)
+
+		w := rw.Result()
+		defer w.Body.Close()
+		require.Equal(t, http.StatusTemporaryRedirect, w.StatusCode)
+
+		loc, err := w.Location()
+		require.NoError(t, err)
+		require.Equal(t, api.AccessURL.Host, loc.Host)
+		require.Equal(t, fmt.Sprintf("/@%s/%s.%s/apps/%s/some-path", me.Username, workspace.Name, agentName, appNameOwner), loc.Path)
+		require.Equal(t, "foo=bar", loc.RawQuery)
+
+		// Other users are not told about the new name.
+		rw = httptest.NewRecorder()
+		r = httptest.NewRequest("GET", "/some-path", nil)
+		r.Header.Set(codersdk.SessionTokenHeader, secondUserClient.SessionToken())
+
+		token, ok = workspaceapps.ResolveRequest(rw, r, workspaceapps.ResolveRequestOptions{
+			Logger:              api.Logger,
+			SignedTokenProvider: api.WorkspaceAppsProvider,
+			DashboardURL:        api.AccessURL,
+			PathAppBaseURL:      api.AccessURL,
+			AppHostname:         api.AppHostname,
+			AppRequest:          req,
+			AppPath:             "/some-path",
+		})
+		require.False(t, ok)
+		require.Nil(t, token)
+
+		w = rw.Result()
+		defer w.Body.Close()
+		require.Equal(t, http.StatusNotFound, w.StatusCode)
+	})
+
 	t.Run("UnhealthyAgent", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,220 @@
+package workspaceapps
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// idleSessionRetention is how long app sessions are remembered after their
+// last activity. Disconnected sessions must be remembered long enough for
+// background requests of the app to keep failing until the user reloads it.
+const idleSessionRetention = 24 * time.Hour
+
+// sessionIdleWarning returns how long before an idle session is disconnected
+// the user is warned.
+func sessionIdleWarning(maxIdle time.Duration) time.Duration {
+	if maxIdle/4 < time.Minute {
+		return maxIdle / 4
+	}
+	return time.Minute
+}
+
+// idleTracker records the last activity of a session.
+type idleTracker struct {
+	last atomic.Int64
+}
+
+func newIdleTracker(now time.Time) *idleTracker {
+	t := &idleTracker{}
+	t.last.Store(now.UnixNano())
+	return t
+}
+
+func (t *idleTracker) touch() {
+	t.last.Store(time.Now().UnixNano())
+}
+
+func (t *idleTracker) lastActivity() time.Time {
+	return time.Unix(0, t.last.Load())
+}
+
+// watchIdle calls warn, if set, once the session has been idle for maxIdle
+// minus the warning period, and disconnect once it has been idle for maxIdle.
+// Activity after the warning rearms it. watchIdle returns when the context is
+// canceled or the session was disconnected.
+func watchIdle(ctx context.Context, tracker *idleTracker, maxIdle time.Duration, warn func(remaining time.Duration), disconnect func()) {
+	warning := sessionIdleWarning(maxIdle)
+	var warnedFor time.Time
+	timer := time.NewTimer(maxIdle - warning)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		last := tracker.lastActivity()
+		idle := time.Since(last)
+		if idle >= maxIdle {
+			disconnect()
+			return
+		}
+		next := maxIdle - warning - idle
+		if next <= 0 {
+			if warn != nil && !warnedFor.Equal(last) {
+				warnedFor = last
+				warn(maxIdle - idle)
+			}
+			next = maxIdle - idle
+		}
+		timer.Reset(next)
+	}
+}
+
+// idleConn records reads from the client as activity.
+type idleConn struct {
+	net.Conn
+	tracker *idleTracker
+}
+
+func (c *idleConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.tracker.touch()
+	}
+	return n, err
+}
+
+// idleResponseWriter wraps the connections hijacked from it, which proxied
+// WebSockets use, to track their activity.
+type idleResponseWriter struct {
+	http.ResponseWriter
+	onHijack func(net.Conn) net.Conn
+}
+
+func (w *idleResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, xerrors.Errorf("%T is not a http.Hijacker", w.ResponseWriter)
+	}
+	conn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	return w.onHijack(conn), brw, nil
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g. to
+// flush streamed responses.
+func (w *idleResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type idleSessionKey struct {
+	userID     uuid.UUID
+	agentID    uuid.UUID
+	slugOrPort string
+}
+
+type idleSession struct {
+	tracker      *idleTracker
+	disconnected bool
+}
+
+// idleSessions tracks the activity of app sessions of templates with a max
+// session idle. Sessions are tracked per replica, so a request served by
+// another replica starts a new session.
+type idleSessions struct {
+	mu       sync.Mutex
+	sessions map[idleSessionKey]*idleSession
+}
+
+// touch records a request to the app of the token and returns the tracker of
+// its session. It returns false if the session was disconnected for being
+// idle, in which case the request must be rejected. Navigations, e.g. the
+// user reloading the app, start a new session instead.
+func (s *idleSessions) touch(token SignedToken, navigation bool) (*idleTracker, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessions == nil {
+		s.sessions = make(map[idleSessionKey]*idleSession)
+	}
+	key := idleSessionKey{
+		userID:     token.UserID,
+		agentID:    token.AgentID,
+		slugOrPort: token.AppSlugOrPort,
+	}
+	now := time.Now()
+	session, ok := s.sessions[key]
+	if ok && now.Sub(session.tracker.lastActivity()) >= token.MaxSessionIdle {
+		session.disconnected = true
+	}
+	if !ok || (session.disconnected && navigation) {
+		session = &idleSession{tracker: newIdleTracker(now)}
+		s.sessions[key] = session
+	}
+	if session.disconnected {
+		return nil, false
+	}
+	session.tracker.touch()
+
+	// Keep the map from growing forever on long-running servers.
+	if len(s.sessions) > 1024 {
+		for k, other := range s.sessions {
+			if now.Sub(other.tracker.lastActivity()) >= idleSessionRetention {
+				delete(s.sessions, k)
+			}
+		}
+	}
+	return session.tracker, true
+}
+
+// disconnect marks the session of the token as disconnected, e.g. when one
+// of its WebSockets was closed for being idle.
+func (s *idleSessions) disconnect(token SignedToken) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[idleSessionKey{
+		userID:     token.UserID,
+		agentID:    token.AgentID,
+		slugOrPort: token.AppSlugOrPort,
+	}]
+	if ok {
+		session.disconnected = true
+	}
+}
+
+// formatIdle formats a session idle duration for users, e.g. "15 minutes".
+func formatIdle(d time.Duration) string {
+	if d >= time.Second {
+		d = d.Round(time.Second)
+	}
+	switch {
+	case d == time.Minute:
+		return "1 minute"
+	case d > time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%d minutes", d/time.Minute)
+	default:
+		return d.String()
+	}
+}
+
+// isNavigation returns true if the request loads a page the user navigated
+// to, rather than a resource or API call of a page. Clients that don't send
+// fetch metadata are treated as navigating.
+func isNavigation(r *http.Request) bool {
+	mode := r.Header.Get("Sec-Fetch-Mode")
+	return mode == "" || mode == "navigate"
+}
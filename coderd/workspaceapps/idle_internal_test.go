@@ -0,0 +1,124 @@
+package workspaceapps
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWatchIdle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WarnThenDisconnect", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		tracker := newIdleTracker(time.Now())
+		warned := make(chan time.Duration, 1)
+		disconnected := make(chan struct{})
+		go watchIdle(ctx, tracker, 200*time.Millisecond, func(remaining time.Duration) {
+			warned <- remaining
+		}, func() {
+			close(disconnected)
+		})
+
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for warning")
+		case remaining := <-warned:
+			require.LessOrEqual(t, remaining, 50*time.Millisecond)
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for disconnect")
+		case <-disconnected:
+		}
+	})
+
+	t.Run("ActivityKeepsSessionAlive", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(testutil.Context(t, testutil.WaitShort))
+		defer cancel()
+		tracker := newIdleTracker(time.Now())
+		disconnected := make(chan struct{})
+		go watchIdle(ctx, tracker, 200*time.Millisecond, nil, func() {
+			close(disconnected)
+		})
+
+		deadline := time.Now().Add(500 * time.Millisecond)
+		for time.Now().Before(deadline) {
+			tracker.touch()
+			select {
+			case <-disconnected:
+				t.Fatal("active session was disconnected")
+			case <-time.After(20 * time.Millisecond):
+			}
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for disconnect")
+		case <-disconnected:
+		}
+	})
+}
+
+func TestIdleSessions(t *testing.T) {
+	t.Parallel()
+
+	var sessions idleSessions
+	token := SignedToken{
+		Request:        Request{AppSlugOrPort: "code-server"},
+		UserID:         uuid.New(),
+		AgentID:        uuid.New(),
+		MaxSessionIdle: 50 * time.Millisecond,
+	}
+
+	_, ok := sessions.touch(token, true)
+	require.True(t, ok)
+	_, ok = sessions.touch(token, false)
+	require.True(t, ok)
+
+	time.Sleep(100 * time.Millisecond)
+	// Background requests of the idle session are rejected until the user
+	// reloads the app.
+	_, ok = sessions.touch(token, false)
+	require.False(t, ok)
+	_, ok = sessions.touch(token, false)
+	require.False(t, ok)
+	_, ok = sessions.touch(token, true)
+	require.True(t, ok)
+	_, ok = sessions.touch(token, false)
+	require.True(t, ok)
+
+	sessions.disconnect(token)
+	_, ok = sessions.touch(token, false)
+	require.False(t, ok)
+}
+
+func TestIsNavigation(t *testing.T) {
+	t.Parallel()
+
+	r, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	require.True(t, isNavigation(r))
+	r.Header.Set("Sec-Fetch-Mode", "navigate")
+	require.True(t, isNavigation(r))
+	r.Header.Set("Sec-Fetch-Mode", "cors")
+	require.False(t, isNavigation(r))
+}
+
+func TestFormatIdle(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "1 minute", formatIdle(59700*time.Millisecond))
+	require.Equal(t, "15 minutes", formatIdle(15*time.Minute))
+	require.Equal(t, "1m30s", formatIdle(90*time.Second))
+	require.Equal(t, "45s", formatIdle(45*time.Second))
+}
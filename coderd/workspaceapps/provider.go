@@ -14,6 +14,14 @@ import (
 const (
 	// TODO(@deansheather): configurable expiry
 	DefaultTokenExpiry = time.Minute
+	// ProxyTokenExpiry is the expiry of tokens issued to workspace proxies.
+	// It is shorter than DefaultTokenExpiry to limit how long a token can be
+	// replayed.
+	ProxyTokenExpiry = 30 * time.Second
+	// IdentityExpiry is the expiry of the identity tokens sent to workspace
+	// apps. It outlives the app token the identity is part of, so apps can
+	// verify identities they receive until the app token expires.
+	IdentityExpiry = 2 * DefaultTokenExpiry
 
 	// RedirectURIQueryParam is the query param for the app URL to be passed
 	// back to the API auth endpoint on the main access URL.
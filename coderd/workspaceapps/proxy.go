@@ -11,10 +11,13 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-jose/go-jose/v3/jwt"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/xerrors"
 	"nhooyr.io/websocket"
 
 	"cdr.dev/slog"
@@ -109,10 +112,23 @@ type Server struct {
 	// calls to the dashboard are not possible due to CORs.
 	DisablePathApps  bool
 	SecureAuthCookie bool
+	// DynamicOptions is optional. If set, it's called for every request to
+	// get the options that may change while the server runs, e.g. when
+	// workspace proxies receive new settings from the primary.
+	DynamicOptions func() DynamicOptions
 
 	AgentProvider  AgentProvider
 	StatsCollector *StatsCollector
-
+	// AssetCache is optional. If set, immutable static assets served by apps
+	// are cached and served without contacting the agent.
+	AssetCache *AssetCache
+	// AccessAuditor is optional. If set, users opening apps and terminals
+	// are recorded in the audit log.
+	AccessAuditor AccessAuditor
+
+	accessAudits       accessAuditDeduper
+	idleSessions       idleSessions
+	ptySessions        ptySessions
 	websocketWaitMutex sync.Mutex
 	websocketWaitGroup sync.WaitGroup
 }
@@ -217,15 +233,15 @@ func (s *Server) handleAPIKeySmuggling(rw http.ResponseWriter, r *http.Request,
 		domain = "." + hostSplit[1]
 	}
 
-	// We don't set an expiration because the key in the database already has an
-	// expiration, and expired tokens don't affect the user experience (they get
-	// auto-redirected to re-smuggle the API key).
+	// We don't set an expiration by default because the key in the database
+	// already has an expiration, and expired tokens don't affect the user
+	// experience (they get auto-redirected to re-smuggle the API key).
 	http.SetCookie(rw, &http.Cookie{
 		Name:     codersdk.DevURLSessionTokenCookie,
 		Value:    token,
 		Domain:   domain,
 		Path:     "/",
-		MaxAge:   0,
+		MaxAge:   int(s.dynamicOptions().SessionCookieMaxAge.Seconds()),
 		HttpOnly: true,
 		SameSite: http.SameSiteLaxMode,
 		Secure:   s.SecureAuthCookie,
@@ -247,10 +263,27 @@ func (s *Server) handleAPIKeySmuggling(rw http.ResponseWriter, r *http.Request,
 	return false
 }
 
+// DynamicOptions are options of the Server that may change while it runs.
+type DynamicOptions struct {
+	// DisablePathApps disables path-based apps in addition to
+	// Server.DisablePathApps.
+	DisablePathApps bool
+	// SessionCookieMaxAge limits how long browsers keep the app session
+	// cookie. Zero makes it a session cookie.
+	SessionCookieMaxAge time.Duration
+}
+
+func (s *Server) dynamicOptions() DynamicOptions {
+	if s.DynamicOptions == nil {
+		return DynamicOptions{}
+	}
+	return s.DynamicOptions()
+}
+
 // workspaceAppsProxyPath proxies requests to a workspace application
 // through a relative URL path.
 func (s *Server) workspaceAppsProxyPath(rw http.ResponseWriter, r *http.Request) {
-	if s.DisablePathApps {
+	if s.DisablePathApps || s.dynamicOptions().DisablePathApps {
 		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
 			Status:       http.StatusUnauthorized,
 			Title:        "Unauthorized",
@@ -539,6 +572,43 @@ func (s *Server) proxyWorkspaceApp(rw http.ResponseWriter, r *http.Request, appT
 	r.URL.Path = path
 	appURL.RawQuery = ""
 
+	s.auditAppAccess(r, appToken)
+
+	if appToken.MaxSessionIdle > 0 {
+		tracker, ok := s.idleSessions.touch(appToken, isNavigation(r))
+		if !ok {
+			site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+				Status:       http.StatusUnauthorized,
+				Title:        "Session Disconnected",
+				Description:  fmt.Sprintf("This session was idle for more than %s and was disconnected. Reload the page to reconnect.", formatIdle(appToken.MaxSessionIdle)),
+				RetryEnabled: true,
+				DashboardURL: s.DashboardURL.String(),
+			})
+			return
+		}
+		// Requests are activity of the session, and so is traffic from the
+		// client on proxied WebSockets, which are closed once the session
+		// is idle.
+		rw = &idleResponseWriter{
+			ResponseWriter: rw,
+			onHijack: func(conn net.Conn) net.Conn {
+				go watchIdle(ctx, tracker, appToken.MaxSessionIdle, nil, func() {
+					s.idleSessions.disconnect(appToken)
+					_ = conn.Close()
+				})
+				return &idleConn{Conn: conn, tracker: tracker}
+			},
+		}
+	}
+
+	if s.AssetCache != nil && s.AssetCache.Serve(rw, r, appToken) {
+		report := newStatsReportFromSignedToken(appToken)
+		s.collectStats(report)
+		report.SessionEndedAt = database.Now()
+		s.collectStats(report)
+		return
+	}
+
 	proxy, release, err := s.AgentProvider.ReverseProxy(appURL, s.DashboardURL, appToken.AgentID)
 	if err != nil {
 		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
@@ -569,6 +639,9 @@ func (s *Server) proxyWorkspaceApp(rw http.ResponseWriter, r *http.Request, appT
 				r.Header.Add(httpmw.VaryHeader, value)
 			}
 		}
+		if s.AssetCache != nil {
+			return s.AssetCache.Store(r, appToken)
+		}
 		return nil
 	}
 
@@ -579,6 +652,14 @@ func (s *Server) proxyWorkspaceApp(rw http.ResponseWriter, r *http.Request, appT
 		r.Header.Add("Cookie", httpapi.StripCoderCookies(cookieHeader))
 	}
 
+	// Clients must not be able to claim an identity, so the identity headers
+	// are only ever set from the token.
+	err = setIdentityHeaders(r.Header, appToken.Identity)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
 	// Convert canonicalized headers to their non-canonicalized counterparts.
 	// See the comment on `nonCanonicalHeaders` for more information on why this
 	// is necessary.
@@ -603,6 +684,36 @@ func (s *Server) proxyWorkspaceApp(rw http.ResponseWriter, r *http.Request, appT
 	proxy.ServeHTTP(rw, r)
 }
 
+// setIdentityHeaders replaces the identity headers of a request to a workspace
+// app with the given identity token. If identity is empty, the headers are
+// removed.
+func setIdentityHeaders(header http.Header, identity string) error {
+	header.Del(codersdk.WorkspaceAppIdentityHeader)
+	header.Del(codersdk.WorkspaceAppUserIDHeader)
+	header.Del(codersdk.WorkspaceAppUsernameHeader)
+	header.Del(codersdk.WorkspaceAppUserEmailHeader)
+	if identity == "" {
+		return nil
+	}
+
+	// The identity is part of the verified app token, so its claims can be
+	// trusted without verifying it again.
+	token, err := jwt.ParseSigned(identity)
+	if err != nil {
+		return xerrors.Errorf("parse identity: %w", err)
+	}
+	var claims codersdk.WorkspaceAppIdentityClaims
+	err = token.UnsafeClaimsWithoutVerification(&claims)
+	if err != nil {
+		return xerrors.Errorf("decode identity claims: %w", err)
+	}
+	header.Set(codersdk.WorkspaceAppIdentityHeader, identity)
+	header.Set(codersdk.WorkspaceAppUserIDHeader, claims.Subject)
+	header.Set(codersdk.WorkspaceAppUsernameHeader, claims.Username)
+	header.Set(codersdk.WorkspaceAppUserEmailHeader, claims.Email)
+	return nil
+}
+
 // workspaceAgentPTY spawns a PTY and pipes it over a WebSocket.
 // This is used for the web terminal.
 //
@@ -646,6 +757,7 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 	reconnect := parser.Required("reconnect").UUID(values, uuid.New(), "reconnect")
 	height := parser.UInt(values, 80, "height")
 	width := parser.UInt(values, 80, "width")
+	resume := values.Get("resume") == "true"
 	if len(parser.Errors) > 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message:     "Invalid query parameters.",
@@ -654,6 +766,24 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if appToken.PTYSessionLimits != nil {
+		releaseSession, err := s.ptySessions.acquire(appToken.WorkspaceID, reconnect, *appToken.PTYSessionLimits)
+		if err != nil {
+			var limitErr ptySessionLimitError
+			if !xerrors.As(err, &limitErr) {
+				httpapi.InternalServerError(rw, err)
+				return
+			}
+			log.Debug(ctx, "terminal session limit reached", slog.Error(err))
+			httpapi.Write(ctx, rw, http.StatusTooManyRequests, codersdk.Response{
+				Message: limitErr.Error(),
+				Detail:  limitErr.detail(),
+			})
+			return
+		}
+		defer releaseSession()
+	}
+
 	conn, err := websocket.Accept(rw, r, &websocket.AcceptOptions{
 		CompressionMode: websocket.CompressionDisabled,
 		// Always allow websockets from the primary dashboard URL.
@@ -684,7 +814,26 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 	}
 	defer release()
 	log.Debug(ctx, "dialed workspace agent")
-	ptNetConn, err := agentConn.ReconnectingPTY(ctx, reconnect, uint16(height), uint16(width), r.URL.Query().Get("command"))
+	var ptNetConn net.Conn
+	if resume {
+		// The session lives in the agent, so reconnects through any replica
+		// or proxy find it there.
+		var ended bool
+		ended, err = ptySessionEnded(ctx, agentConn, reconnect)
+		if err != nil {
+			log.Debug(ctx, "list reconnecting pty sessions in workspace agent", slog.Error(err))
+			_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("list sessions: %s", err))
+			return
+		}
+		if ended {
+			log.Debug(ctx, "reconnecting pty session to resume has ended", slog.F("reconnect", reconnect))
+			_ = conn.Close(codersdk.ReconnectingPTYSessionEndedStatus, "The terminal session has ended.")
+			return
+		}
+		ptNetConn, err = agentConn.ResumeReconnectingPTY(ctx, reconnect, uint16(height), uint16(width))
+	} else {
+		ptNetConn, err = agentConn.ReconnectingPTY(ctx, reconnect, uint16(height), uint16(width), r.URL.Query().Get("command"))
+	}
 	if err != nil {
 		log.Debug(ctx, "dial reconnecting pty server in workspace agent", slog.Error(err))
 		_ = conn.Close(websocket.StatusInternalError, httpapi.WebsocketCloseSprintf("dial: %s", err))
@@ -693,6 +842,10 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 	defer ptNetConn.Close()
 	log.Debug(ctx, "obtained PTY")
 
+	if s.AccessAuditor != nil {
+		s.AccessAuditor.AuditAccess(ctx, newAccessEvent(r, *appToken))
+	}
+
 	report := newStatsReportFromSignedToken(*appToken)
 	s.collectStats(report)
 	defer func() {
@@ -700,10 +853,36 @@ func (s *Server) workspaceAgentPTY(rw http.ResponseWriter, r *http.Request) {
 		s.collectStats(report)
 	}()
 
-	agentssh.Bicopy(ctx, wsNetConn, ptNetConn)
+	var clientConn net.Conn = wsNetConn
+	if appToken.MaxSessionIdle > 0 {
+		// Only input from the user is activity, output of the PTY isn't.
+		tracker := newIdleTracker(time.Now())
+		clientConn = &idleConn{Conn: wsNetConn, tracker: tracker}
+		go watchIdle(ctx, tracker, appToken.MaxSessionIdle, func(remaining time.Duration) {
+			_, _ = wsNetConn.Write([]byte(fmt.Sprintf("\r\n\x1b[1;33mThis session is idle and will be disconnected in %s. Type anything to stay connected.\x1b[0m\r\n", formatIdle(remaining))))
+		}, func() {
+			log.Debug(ctx, "disconnecting idle pty session", slog.F("max_session_idle", appToken.MaxSessionIdle))
+			_, _ = wsNetConn.Write([]byte(fmt.Sprintf("\r\n\x1b[1;31mThis session was idle for %s and was disconnected.\x1b[0m\r\n", formatIdle(appToken.MaxSessionIdle))))
+			_ = conn.Close(websocket.StatusPolicyViolation, "session idle timeout")
+		})
+	}
+
+	agentssh.Bicopy(ctx, clientConn, ptNetConn)
 	log.Debug(ctx, "pty Bicopy finished")
 }
 
+// auditAppAccess records the user opening the app, unless they already did so
+// recently.
+func (s *Server) auditAppAccess(r *http.Request, appToken SignedToken) {
+	if s.AccessAuditor == nil {
+		return
+	}
+	event := newAccessEvent(r, appToken)
+	if s.accessAudits.shouldAudit(event) {
+		s.AccessAuditor.AuditAccess(r.Context(), event)
+	}
+}
+
 func (s *Server) collectStats(stats StatsReport) {
 	if s.StatsCollector != nil {
 		s.StatsCollector.Collect(stats)
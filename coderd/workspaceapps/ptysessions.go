@@ -0,0 +1,129 @@
+package workspaceapps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// PTYSessionLimits limit the concurrent terminal sessions of the user that
+// opens them and of the workspace. Zero means no limit.
+type PTYSessionLimits struct {
+	UserID       uuid.UUID `json:"user_id"`
+	PerUser      int64     `json:"per_user,omitempty"`
+	PerWorkspace int64     `json:"per_workspace,omitempty"`
+}
+
+// ptySessionLimitError is returned when opening a terminal session would
+// exceed a limit.
+type ptySessionLimitError struct {
+	workspace bool
+	limit     int64
+}
+
+func (e ptySessionLimitError) Error() string {
+	if e.workspace {
+		return fmt.Sprintf("This workspace has reached its limit of %d concurrent terminal sessions.", e.limit)
+	}
+	return fmt.Sprintf("You have reached your limit of %d concurrent terminal sessions.", e.limit)
+}
+
+func (e ptySessionLimitError) detail() string {
+	if e.workspace {
+		return "Close a terminal of the workspace, or ask an administrator to raise the limit with --max-pty-sessions-per-workspace."
+	}
+	return "Close one of your terminals, or ask an administrator to raise your limit."
+}
+
+// ptySessions counts the terminal sessions open on the server. Sessions are
+// counted per replica. Attaching to the same session more than once, e.g.
+// from two tabs, counts as one session.
+type ptySessions struct {
+	mu sync.Mutex
+	// The connections to each session, by user and by workspace.
+	byUser      map[uuid.UUID]map[uuid.UUID]int
+	byWorkspace map[uuid.UUID]map[uuid.UUID]int
+}
+
+// acquire records a connection to the session, unless it is a new session
+// that exceeds the limits. The returned func must be called once the
+// connection is closed.
+func (s *ptySessions) acquire(workspaceID, sessionID uuid.UUID, limits PTYSessionLimits) (func(), error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.byUser == nil {
+		s.byUser = make(map[uuid.UUID]map[uuid.UUID]int)
+		s.byWorkspace = make(map[uuid.UUID]map[uuid.UUID]int)
+	}
+	if ptySessionsExceed(s.byUser[limits.UserID], sessionID, limits.PerUser) {
+		return nil, ptySessionLimitError{limit: limits.PerUser}
+	}
+	if ptySessionsExceed(s.byWorkspace[workspaceID], sessionID, limits.PerWorkspace) {
+		return nil, ptySessionLimitError{workspace: true, limit: limits.PerWorkspace}
+	}
+	addPTYSession(s.byUser, limits.UserID, sessionID, 1)
+	addPTYSession(s.byWorkspace, workspaceID, sessionID, 1)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			addPTYSession(s.byUser, limits.UserID, sessionID, -1)
+			addPTYSession(s.byWorkspace, workspaceID, sessionID, -1)
+		})
+	}, nil
+}
+
+// ptySessionsExceed returns true if connecting to the session exceeds the
+// limit of sessions.
+func ptySessionsExceed(sessions map[uuid.UUID]int, sessionID uuid.UUID, limit int64) bool {
+	if limit <= 0 {
+		return false
+	}
+	if _, ok := sessions[sessionID]; ok {
+		return false
+	}
+	return int64(len(sessions)) >= limit
+}
+
+func addPTYSession(counts map[uuid.UUID]map[uuid.UUID]int, key, sessionID uuid.UUID, delta int) {
+	sessions, ok := counts[key]
+	if !ok {
+		sessions = make(map[uuid.UUID]int)
+		counts[key] = sessions
+	}
+	sessions[sessionID] += delta
+	if sessions[sessionID] <= 0 {
+		delete(sessions, sessionID)
+	}
+	if len(sessions) == 0 {
+		delete(counts, key)
+	}
+}
+
+// ptySessionEnded returns true if the agent no longer runs the terminal
+// session. Agents that can't list their sessions are assumed to still run it.
+func ptySessionEnded(ctx context.Context, agentConn *codersdk.WorkspaceAgentConn, sessionID uuid.UUID) (bool, error) {
+	res, err := agentConn.ReconnectingPTYSessions(ctx)
+	if err != nil {
+		var sdkErr *codersdk.Error
+		if xerrors.As(err, &sdkErr) && sdkErr.StatusCode() == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, session := range res.Sessions {
+		if session.ID == sessionID {
+			return false, nil
+		}
+	}
+	return true, nil
+}
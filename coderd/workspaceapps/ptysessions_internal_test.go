@@ -0,0 +1,72 @@
+package workspaceapps
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPTYSessions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("PerUser", func(t *testing.T) {
+		t.Parallel()
+
+		var sessions ptySessions
+		limits := PTYSessionLimits{UserID: uuid.New(), PerUser: 2}
+		workspaceID := uuid.New()
+		first, second := uuid.New(), uuid.New()
+
+		releaseFirst, err := sessions.acquire(workspaceID, first, limits)
+		require.NoError(t, err)
+		releaseSecond, err := sessions.acquire(workspaceID, second, limits)
+		require.NoError(t, err)
+		// Attaching to a session again doesn't count.
+		releaseAgain, err := sessions.acquire(workspaceID, second, limits)
+		require.NoError(t, err)
+
+		// The limit applies across workspaces.
+		_, err = sessions.acquire(uuid.New(), uuid.New(), limits)
+		require.Equal(t, ptySessionLimitError{limit: 2}, err)
+
+		// The session stays open until all of its connections are closed.
+		releaseSecond()
+		releaseSecond()
+		_, err = sessions.acquire(workspaceID, uuid.New(), limits)
+		require.Error(t, err)
+		releaseAgain()
+		_, err = sessions.acquire(workspaceID, uuid.New(), limits)
+		require.NoError(t, err)
+
+		// Other users have their own limit.
+		_, err = sessions.acquire(workspaceID, uuid.New(), PTYSessionLimits{UserID: uuid.New(), PerUser: 2})
+		require.NoError(t, err)
+		releaseFirst()
+	})
+
+	t.Run("PerWorkspace", func(t *testing.T) {
+		t.Parallel()
+
+		var sessions ptySessions
+		workspaceID := uuid.New()
+
+		_, err := sessions.acquire(workspaceID, uuid.New(), PTYSessionLimits{UserID: uuid.New(), PerWorkspace: 1})
+		require.NoError(t, err)
+		_, err = sessions.acquire(workspaceID, uuid.New(), PTYSessionLimits{UserID: uuid.New(), PerWorkspace: 1})
+		require.Equal(t, ptySessionLimitError{workspace: true, limit: 1}, err)
+		_, err = sessions.acquire(uuid.New(), uuid.New(), PTYSessionLimits{UserID: uuid.New(), PerWorkspace: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("NoLimits", func(t *testing.T) {
+		t.Parallel()
+
+		var sessions ptySessions
+		limits := PTYSessionLimits{UserID: uuid.New()}
+		for i := 0; i < 10; i++ {
+			_, err := sessions.acquire(uuid.New(), uuid.New(), limits)
+			require.NoError(t, err)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net"
 	"net/url"
 	"strconv"
 	"strings"
@@ -162,6 +163,73 @@ func (r Request) Validate() error {
 	return nil
 }
 
+// getUser returns the user that owns the workspace in the request.
+func (r Request) getUser(ctx context.Context, db database.Store) (database.User, error) {
+	var (
+		user database.User
+		err  error
+	)
+	if userID, uuidErr := uuid.Parse(r.UsernameOrID); uuidErr == nil {
+		user, err = db.GetUserByID(ctx, userID)
+	} else {
+		user, err = db.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
+			Username: r.UsernameOrID,
+		})
+	}
+	if err != nil {
+		return database.User{}, xerrors.Errorf("get user %q: %w", r.UsernameOrID, err)
+	}
+	return user, nil
+}
+
+// getRenamedWorkspaceName returns the current name of the workspace if the
+// request refers to it by a name it was recently renamed away from. If it
+// does not, the error wraps sql.ErrNoRows.
+func (r Request) getRenamedWorkspaceName(ctx context.Context, db database.Store) (string, error) {
+	if r.AccessMethod == AccessMethodTerminal {
+		return "", sql.ErrNoRows
+	}
+	if _, err := uuid.Parse(r.WorkspaceNameOrID); err == nil {
+		return "", sql.ErrNoRows
+	}
+
+	user, err := r.getUser(ctx, db)
+	if err != nil {
+		return "", err
+	}
+	workspace, err := db.GetWorkspaceByOwnerIDAndPreviousName(ctx, database.GetWorkspaceByOwnerIDAndPreviousNameParams{
+		OwnerID: user.ID,
+		Name:    r.WorkspaceNameOrID,
+	})
+	if err != nil {
+		return "", xerrors.Errorf("get workspace by previous name %q: %w", r.WorkspaceNameOrID, err)
+	}
+	return workspace.Name, nil
+}
+
+// withWorkspaceName returns a copy of the request that refers to the
+// workspace by the given name, including in the base path of path apps.
+func (r Request) withWorkspaceName(name string) Request {
+	req := r
+	if req.AccessMethod == AccessMethodPath {
+		// The base path is "/@<user>/<workspace>[.<agent>]/apps/<app>/".
+		parts := strings.Split(req.BasePath, "/")
+		for i, part := range parts {
+			if strings.HasPrefix(part, "@") && i+1 < len(parts) {
+				workspaceAndAgent := name
+				if _, agent, ok := strings.Cut(parts[i+1], "."); ok {
+					workspaceAndAgent += "." + agent
+				}
+				parts[i+1] = workspaceAndAgent
+				break
+			}
+		}
+		req.BasePath = strings.Join(parts, "/")
+	}
+	req.WorkspaceNameOrID = name
+	return req
+}
+
 type databaseRequest struct {
 	Request
 	// User is the user that owns the app.
@@ -180,6 +248,40 @@ type databaseRequest struct {
 	// AppSharingLevel is the sharing level of the app. This is forced to be set
 	// to AppSharingLevelOwner if the access method is terminal.
 	AppSharingLevel database.AppSharingLevel
+	// AppIsPort is true if the app is a port-forwarded localhost port instead
+	// of an app declared by the template.
+	AppIsPort bool
+}
+
+// agentPort returns the port that the app is served from if it's a port of
+// the agent. Apps that proxy to other hosts aren't.
+func (r databaseRequest) agentPort() (uint16, bool) {
+	if r.AppURL == nil {
+		return 0, false
+	}
+	host := r.AppURL.Hostname()
+	if host != "localhost" {
+		ip := net.ParseIP(host)
+		if ip == nil || !(ip.IsLoopback() || ip.IsUnspecified()) {
+			return 0, false
+		}
+	}
+	rawPort := r.AppURL.Port()
+	if rawPort == "" {
+		switch r.AppURL.Scheme {
+		case "http":
+			return 80, true
+		case "https":
+			return 443, true
+		default:
+			return 0, false
+		}
+	}
+	port, err := strconv.ParseUint(rawPort, 10, 16)
+	if err != nil {
+		return 0, false
+	}
+	return uint16(port), true
 }
 
 // getDatabase does queries to get the owner user, workspace and agent
@@ -200,19 +302,9 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 	// fields available.
 
 	// Get user.
-	var (
-		user    database.User
-		userErr error
-	)
-	if userID, uuidErr := uuid.Parse(r.UsernameOrID); uuidErr == nil {
-		user, userErr = db.GetUserByID(ctx, userID)
-	} else {
-		user, userErr = db.GetUserByEmailOrUsername(ctx, database.GetUserByEmailOrUsernameParams{
-			Username: r.UsernameOrID,
-		})
-	}
-	if userErr != nil {
-		return nil, xerrors.Errorf("get user %q: %w", r.UsernameOrID, userErr)
+	user, err := r.getUser(ctx, db)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get workspace.
@@ -348,6 +440,7 @@ func (r Request) getDatabase(ctx context.Context, db database.Store) (*databaseR
 		AppURL:          appURLParsed,
 		AppHealth:       appHealth,
 		AppSharingLevel: appSharingLevel,
+		AppIsPort:       portUintErr == nil,
 	}, nil
 }
 
@@ -1,13 +1,18 @@
 package workspaceapps
 
 import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 
@@ -16,8 +21,10 @@ import (
 )
 
 const (
-	tokenSigningAlgorithm     = jose.HS512
-	apiKeyEncryptionAlgorithm = jose.A256GCMKW
+	tokenSigningAlgorithm      = jose.HS512
+	proxyTokenSigningAlgorithm = jose.EdDSA
+	identitySigningAlgorithm   = jose.EdDSA
+	apiKeyEncryptionAlgorithm  = jose.A256GCMKW
 )
 
 // SignedToken is the struct data contained inside a workspace app JWE. It
@@ -33,6 +40,23 @@ type SignedToken struct {
 	WorkspaceID uuid.UUID `json:"workspace_id"`
 	AgentID     uuid.UUID `json:"agent_id"`
 	AppURL      string    `json:"app_url"`
+	// Audience is the ID of the workspace proxy the token was issued to. It is
+	// only set on tokens signed with SignProxyToken.
+	Audience string `json:"audience,omitempty"`
+	// Identity is the identity token of the user the token was issued to,
+	// signed with SignIdentity. It is only set when identity headers are
+	// enabled for the sharing level of the app and the user is signed in.
+	Identity string `json:"identity,omitempty"`
+	// MaxSessionIdle is the max session idle of the template of the
+	// workspace. Sessions idle for longer are disconnected. It is unset if
+	// sessions are never disconnected.
+	MaxSessionIdle time.Duration `json:"max_session_idle,omitempty"`
+	// PTYSessionLimits are the limits of concurrent terminal sessions that
+	// apply to the request. It is only set on terminal tokens.
+	PTYSessionLimits *PTYSessionLimits `json:"pty_session_limits,omitempty"`
+	// Scope restricts the token to a single app. It is only set on tokens
+	// signed with SignProxyToken.
+	Scope *TokenScope `json:"scope,omitempty"`
 }
 
 // MatchesRequest returns true if the token matches the request. Any token that
@@ -46,6 +70,43 @@ func (t SignedToken) MatchesRequest(req Request) bool {
 		t.AppSlugOrPort == req.AppSlugOrPort
 }
 
+// TokenScope contains the claims that restrict a token issued to a workspace
+// proxy to a single app. Proxies verify them in addition to matching the
+// request, so a token leaked for one app can't be replayed against another app
+// of the same workspace.
+type TokenScope struct {
+	// AppSlug is the slug or port of the app. It is empty for terminal tokens.
+	AppSlug string `json:"app_slug"`
+	// AllowedPaths are the path prefixes the token may be used for.
+	AllowedPaths []string  `json:"allowed_paths"`
+	IssuedAt     time.Time `json:"issued_at"`
+	// MaxTTL is the longest the token may be valid for after it was issued.
+	MaxTTL time.Duration `json:"max_ttl"`
+}
+
+// NewTokenScope returns the scope of a token issued for the request at the
+// given time.
+func NewTokenScope(req Request, issuedAt time.Time, maxTTL time.Duration) TokenScope {
+	return TokenScope{
+		AppSlug:      req.AppSlugOrPort,
+		AllowedPaths: []string{req.BasePath},
+		IssuedAt:     issuedAt,
+		MaxTTL:       maxTTL,
+	}
+}
+
+// AllowsPath returns true if the token may be used for a request to the path.
+// The base path of an app is allowed with or without its trailing slash.
+func (s TokenScope) AllowsPath(path string) bool {
+	for _, allowed := range s.AllowedPaths {
+		base := strings.TrimSuffix(allowed, "/")
+		if path == allowed || path == base || strings.HasPrefix(path, base+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // SecurityKey is used for signing and encrypting app tokens and API keys.
 //
 // The first 64 bytes of the key are used for signing tokens with HMAC-SHA256,
@@ -145,6 +206,227 @@ func (k SecurityKey) VerifySignedToken(str string) (SignedToken, error) {
 	return tok, nil
 }
 
+// proxySigningKey returns the key used to sign tokens issued to workspace
+// proxies. It is derived from the signing key so every replica uses the same
+// key without storing another secret.
+func (k SecurityKey) proxySigningKey() jose.JSONWebKey {
+	seed := sha256.Sum256(append([]byte("workspace proxy app token:"), k.signingKey()...))
+	key := ed25519.NewKeyFromSeed(seed[:])
+	keyID := sha256.Sum256(key.Public().(ed25519.PublicKey))
+	return jose.JSONWebKey{
+		Key:       key,
+		KeyID:     hex.EncodeToString(keyID[:8]),
+		Algorithm: string(proxyTokenSigningAlgorithm),
+		Use:       "sig",
+	}
+}
+
+// ProxyTokenKeys returns the public keys that verify tokens signed with
+// SignProxyToken. Workspace proxies fetch them from the primary to verify the
+// tokens they are issued.
+func (k SecurityKey) ProxyTokenKeys() jose.JSONWebKeySet {
+	key := k.proxySigningKey()
+	return jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{key.Public()},
+	}
+}
+
+// SignProxyToken generates a signed workspace app token for the workspace
+// proxy in the payload audience. If the payload doesn't have a scope, it will
+// be scoped to the app of its request with the proxy token expiry as max TTL.
+// If the payload doesn't have an expiry, it will be set to the end of the max
+// TTL.
+func (k SecurityKey) SignProxyToken(payload SignedToken) (string, error) {
+	if payload.Audience == "" {
+		return "", xerrors.New("audience is required")
+	}
+	if payload.Scope == nil {
+		scope := NewTokenScope(payload.Request, time.Now(), ProxyTokenExpiry)
+		payload.Scope = &scope
+	}
+	if payload.Expiry.IsZero() {
+		payload.Expiry = payload.Scope.IssuedAt.Add(payload.Scope.MaxTTL)
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", xerrors.Errorf("marshal payload to JSON: %w", err)
+	}
+
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: proxyTokenSigningAlgorithm,
+		Key:       k.proxySigningKey(),
+	}, nil)
+	if err != nil {
+		return "", xerrors.Errorf("create signer: %w", err)
+	}
+
+	signedObject, err := signer.Sign(payloadBytes)
+	if err != nil {
+		return "", xerrors.Errorf("sign payload: %w", err)
+	}
+
+	serialized, err := signedObject.CompactSerialize()
+	if err != nil {
+		return "", xerrors.Errorf("serialize JWS: %w", err)
+	}
+
+	return serialized, nil
+}
+
+// VerifyProxyToken parses a token signed with SignProxyToken using the given
+// public keys and returns the payload. If the token is invalid, expired, issued
+// to another audience or not scoped to an app, an error is returned.
+func VerifyProxyToken(keys jose.JSONWebKeySet, audience string, str string) (SignedToken, error) {
+	object, err := jose.ParseSigned(str)
+	if err != nil {
+		return SignedToken{}, xerrors.Errorf("parse JWS: %w", err)
+	}
+	if len(object.Signatures) != 1 {
+		return SignedToken{}, xerrors.New("expected 1 signature")
+	}
+	header := object.Signatures[0].Header
+	if header.Algorithm != string(proxyTokenSigningAlgorithm) {
+		return SignedToken{}, xerrors.Errorf("expected token signing algorithm to be %q, got %q", proxyTokenSigningAlgorithm, header.Algorithm)
+	}
+	matching := keys.Key(header.KeyID)
+	if len(matching) == 0 {
+		return SignedToken{}, xerrors.Errorf("unknown signing key %q", header.KeyID)
+	}
+
+	output, err := object.Verify(matching[0])
+	if err != nil {
+		return SignedToken{}, xerrors.Errorf("verify JWS: %w", err)
+	}
+
+	var tok SignedToken
+	err = json.Unmarshal(output, &tok)
+	if err != nil {
+		return SignedToken{}, xerrors.Errorf("unmarshal payload: %w", err)
+	}
+	if tok.Audience != audience {
+		return SignedToken{}, xerrors.Errorf("signed app token was issued to %q, not %q", tok.Audience, audience)
+	}
+	if tok.Scope == nil {
+		return SignedToken{}, xerrors.New("signed app token is not scoped to an app")
+	}
+	if tok.Expiry.After(tok.Scope.IssuedAt.Add(tok.Scope.MaxTTL)) {
+		return SignedToken{}, xerrors.New("signed app token expiry exceeds the max TTL of its scope")
+	}
+	if tok.Expiry.Before(time.Now()) {
+		return SignedToken{}, xerrors.New("signed app token expired")
+	}
+
+	return tok, nil
+}
+
+// identitySigningKey returns the key used to sign the identity tokens sent to
+// workspace apps. Like proxySigningKey, it is derived from the signing key.
+func (k SecurityKey) identitySigningKey() jose.JSONWebKey {
+	seed := sha256.Sum256(append([]byte("workspace app identity:"), k.signingKey()...))
+	key := ed25519.NewKeyFromSeed(seed[:])
+	keyID := sha256.Sum256(key.Public().(ed25519.PublicKey))
+	return jose.JSONWebKey{
+		Key:       key,
+		KeyID:     hex.EncodeToString(keyID[:8]),
+		Algorithm: string(identitySigningAlgorithm),
+		Use:       "sig",
+	}
+}
+
+// IdentityKeys returns the public keys that verify tokens signed with
+// SignIdentity. Workspace apps fetch them to verify the identity of users.
+func (k SecurityKey) IdentityKeys() jose.JSONWebKeySet {
+	key := k.identitySigningKey()
+	return jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{key.Public()},
+	}
+}
+
+// SignIdentity returns a JWT with the given claims for the
+// codersdk.WorkspaceAppIdentityHeader header.
+func (k SecurityKey) SignIdentity(claims codersdk.WorkspaceAppIdentityClaims) (string, error) {
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: identitySigningAlgorithm,
+		Key:       k.identitySigningKey(),
+	}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return "", xerrors.Errorf("create signer: %w", err)
+	}
+	token, err := jwt.Signed(signer).Claims(claims).CompactSerialize()
+	if err != nil {
+		return "", xerrors.Errorf("sign claims: %w", err)
+	}
+	return token, nil
+}
+
+// VerifyIdentity parses a token signed with SignIdentity using the given
+// public keys and returns its claims. If the token is invalid, expired or was
+// issued for another audience, an error is returned.
+func VerifyIdentity(keys jose.JSONWebKeySet, audience string, str string) (codersdk.WorkspaceAppIdentityClaims, error) {
+	var claims codersdk.WorkspaceAppIdentityClaims
+	token, err := jwt.ParseSigned(str)
+	if err != nil {
+		return claims, xerrors.Errorf("parse JWT: %w", err)
+	}
+	if len(token.Headers) != 1 {
+		return claims, xerrors.New("expected 1 signature")
+	}
+	header := token.Headers[0]
+	if header.Algorithm != string(identitySigningAlgorithm) {
+		return claims, xerrors.Errorf("expected identity signing algorithm to be %q, got %q", identitySigningAlgorithm, header.Algorithm)
+	}
+	matching := keys.Key(header.KeyID)
+	if len(matching) == 0 {
+		return claims, xerrors.Errorf("unknown signing key %q", header.KeyID)
+	}
+	err = token.Claims(matching[0], &claims)
+	if err != nil {
+		return claims, xerrors.Errorf("verify JWT: %w", err)
+	}
+	if claims.Audience != audience {
+		return claims, xerrors.Errorf("identity was issued for %q, not %q", claims.Audience, audience)
+	}
+	if time.Unix(claims.Expiry, 0).Before(time.Now()) {
+		return claims, xerrors.New("identity expired")
+	}
+	return claims, nil
+}
+
+// SignAffinity returns a value identifying the workspace proxy replica a
+// client should be routed to, signed so that clients cannot pick a replica
+// themselves.
+func (k SecurityKey) SignAffinity(replicaID uuid.UUID) string {
+	id := replicaID.String()
+	return id + "." + base64.RawURLEncoding.EncodeToString(k.affinityMAC(id))
+}
+
+// VerifyAffinity parses a value signed with SignAffinity and returns the
+// replica ID it contains.
+func (k SecurityKey) VerifyAffinity(str string) (uuid.UUID, error) {
+	id, sig, ok := strings.Cut(str, ".")
+	if !ok {
+		return uuid.Nil, xerrors.New("malformed affinity")
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("decode affinity signature: %w", err)
+	}
+	if !hmac.Equal(decoded, k.affinityMAC(id)) {
+		return uuid.Nil, xerrors.New("invalid affinity signature")
+	}
+	replicaID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("parse affinity replica ID: %w", err)
+	}
+	return replicaID, nil
+}
+
+func (k SecurityKey) affinityMAC(id string) []byte {
+	mac := hmac.New(sha256.New, append([]byte("workspace proxy affinity:"), k.signingKey()...))
+	_, _ = mac.Write([]byte(id))
+	return mac.Sum(nil)
+}
+
 type EncryptedAPIKeyPayload struct {
 	APIKey    string    `json:"api_key"`
 	ExpiresAt time.Time `json:"expires_at"`
@@ -224,9 +506,64 @@ func (k SecurityKey) DecryptAPIKey(encryptedAPIKey string) (string, error) {
 	return payload.APIKey, nil
 }
 
+// EncryptSecret encrypts a secret that is stored in the database, such as the
+// SSH host key of a workspace agent.
+func (k SecurityKey) EncryptSecret(secret []byte) (string, error) {
+	encrypter, err := jose.NewEncrypter(
+		jose.A256GCM,
+		jose.Recipient{
+			Algorithm: apiKeyEncryptionAlgorithm,
+			Key:       k.encryptionKey(),
+		},
+		nil,
+	)
+	if err != nil {
+		return "", xerrors.Errorf("initializer jose encrypter: %w", err)
+	}
+	encryptedObject, err := encrypter.Encrypt(secret)
+	if err != nil {
+		return "", xerrors.Errorf("encrypt jwe: %w", err)
+	}
+	return encryptedObject.CompactSerialize()
+}
+
+// DecryptSecret undoes EncryptSecret.
+func (k SecurityKey) DecryptSecret(encrypted string) ([]byte, error) {
+	object, err := jose.ParseEncrypted(encrypted)
+	if err != nil {
+		return nil, xerrors.Errorf("parse encrypted secret: %w", err)
+	}
+	if object.Header.Algorithm != string(apiKeyEncryptionAlgorithm) {
+		return nil, xerrors.Errorf("expected secret encryption algorithm to be %q, got %q", apiKeyEncryptionAlgorithm, object.Header.Algorithm)
+	}
+	decrypted, err := object.Decrypt(k.encryptionKey())
+	if err != nil {
+		return nil, xerrors.Errorf("decrypt secret: %w", err)
+	}
+	return decrypted, nil
+}
+
 // FromRequest returns the signed token from the request, if it exists and is
 // valid. The caller must check that the token matches the request.
 func FromRequest(r *http.Request, key SecurityKey) (*SignedToken, bool) {
+	return fromRequest(r, key.VerifySignedToken)
+}
+
+// ProxyTokenFromRequest returns the token signed with SignProxyToken from the
+// request, if it exists, is valid, was issued to the audience, was issued to
+// the client making the request and is scoped to the path of the request. The
+// caller must check that the token matches the request.
+func ProxyTokenFromRequest(r *http.Request, keys jose.JSONWebKeySet, audience string) (*SignedToken, bool) {
+	token, ok := fromRequest(r, func(str string) (SignedToken, error) {
+		return VerifyProxyToken(keys, audience, str)
+	})
+	if !ok || !token.Scope.AllowsPath(r.URL.Path) {
+		return nil, false
+	}
+	return token, true
+}
+
+func fromRequest(r *http.Request, verify func(str string) (SignedToken, error)) (*SignedToken, bool) {
 	// Get the token string from the request. We usually use a cookie for this,
 	// but for web terminal we also support a query parameter to support
 	// cross-domain terminal access.
@@ -239,7 +576,7 @@ func FromRequest(r *http.Request, key SecurityKey) (*SignedToken, bool) {
 	}
 
 	if tokenStr != "" {
-		token, err := key.VerifySignedToken(tokenStr)
+		token, err := verify(tokenStr)
 		if err == nil {
 			req := token.Request.Normalize()
 			if cookieErr != nil && req.AccessMethod != AccessMethodTerminal {
@@ -2,6 +2,9 @@ package workspaceapps_test
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,6 +15,7 @@ import (
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 )
 
@@ -160,6 +164,47 @@ func Test_TokenMatchesRequest(t *testing.T) {
 	}
 }
 
+func TestTokenScope(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	scope := workspaceapps.NewTokenScope(workspaceapps.Request{
+		AccessMethod:  workspaceapps.AccessMethodPath,
+		BasePath:      "/@foo/bar.baz/apps/qux/",
+		AppSlugOrPort: "qux",
+	}, now, time.Minute)
+	require.Equal(t, workspaceapps.TokenScope{
+		AppSlug:      "qux",
+		AllowedPaths: []string{"/@foo/bar.baz/apps/qux/"},
+		IssuedAt:     now,
+		MaxTTL:       time.Minute,
+	}, scope)
+
+	cases := []struct {
+		path string
+		want bool
+	}{
+		{path: "/@foo/bar.baz/apps/qux/", want: true},
+		{path: "/@foo/bar.baz/apps/qux", want: true},
+		{path: "/@foo/bar.baz/apps/qux/index.html", want: true},
+		{path: "/@foo/bar.baz/apps/quxx/", want: false},
+		{path: "/@foo/bar.baz/apps/other/", want: false},
+		{path: "/", want: false},
+	}
+	for _, c := range cases {
+		require.Equal(t, c.want, scope.AllowsPath(c.path), c.path)
+	}
+
+	// Subdomain apps are served from the root of their hostname.
+	scope = workspaceapps.NewTokenScope(workspaceapps.Request{
+		AccessMethod:  workspaceapps.AccessMethodSubdomain,
+		BasePath:      "/",
+		AppSlugOrPort: "8080",
+	}, now, time.Minute)
+	require.True(t, scope.AllowsPath("/"))
+	require.True(t, scope.AllowsPath("/some/path"))
+}
+
 func Test_GenerateToken(t *testing.T) {
 	t.Parallel()
 
@@ -351,6 +396,235 @@ func Test_ParseToken(t *testing.T) {
 	})
 }
 
+func TestProxyToken(t *testing.T) {
+	t.Parallel()
+
+	audience := uuid.NewString()
+	keys := coderdtest.AppSecurityKey.ProxyTokenKeys()
+	require.Len(t, keys.Keys, 1)
+	require.True(t, keys.Keys[0].IsPublic())
+
+	payload := workspaceapps.SignedToken{
+		Request: workspaceapps.Request{
+			AccessMethod:      workspaceapps.AccessMethodPath,
+			BasePath:          "/app",
+			UsernameOrID:      "foo",
+			WorkspaceNameOrID: "bar",
+			AgentNameOrID:     "baz",
+			AppSlugOrPort:     "qux",
+		},
+		UserID:      uuid.New(),
+		WorkspaceID: uuid.New(),
+		AgentID:     uuid.New(),
+		AppURL:      "http://127.0.0.1:8080",
+		Audience:    audience,
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr, err := coderdtest.AppSecurityKey.SignProxyToken(payload)
+		require.NoError(t, err)
+
+		token, err := workspaceapps.VerifyProxyToken(keys, audience, tokenStr)
+		require.NoError(t, err)
+		require.WithinDuration(t, time.Now().Add(workspaceapps.ProxyTokenExpiry), token.Expiry, 10*time.Second)
+		require.Equal(t, audience, token.Audience)
+		require.Equal(t, payload.Request, token.Request)
+		require.NotNil(t, token.Scope)
+		require.Equal(t, "qux", token.Scope.AppSlug)
+		require.Equal(t, []string{"/app"}, token.Scope.AllowedPaths)
+		require.Equal(t, workspaceapps.ProxyTokenExpiry, token.Scope.MaxTTL)
+		require.True(t, token.Expiry.Equal(token.Scope.IssuedAt.Add(token.Scope.MaxTTL)))
+	})
+
+	t.Run("ExceedsMaxTTL", func(t *testing.T) {
+		t.Parallel()
+
+		tok := payload
+		scope := workspaceapps.NewTokenScope(tok.Request, time.Now(), time.Minute)
+		tok.Scope = &scope
+		tok.Expiry = time.Now().Add(time.Hour)
+		tokenStr, err := coderdtest.AppSecurityKey.SignProxyToken(tok)
+		require.NoError(t, err)
+
+		_, err = workspaceapps.VerifyProxyToken(keys, audience, tokenStr)
+		require.ErrorContains(t, err, "max TTL")
+	})
+
+	t.Run("FromRequest", func(t *testing.T) {
+		t.Parallel()
+
+		// The token is only accepted for paths of its app.
+		for path, want := range map[string]bool{
+			"/app/index.html":   true,
+			"/other/index.html": false,
+		} {
+			r := httptest.NewRequest("GET", path, nil)
+			tokenStr, err := coderdtest.AppSecurityKey.SignProxyToken(payload)
+			require.NoError(t, err)
+			r.AddCookie(&http.Cookie{Name: codersdk.DevURLSignedAppTokenCookie, Value: tokenStr})
+
+			_, ok := workspaceapps.ProxyTokenFromRequest(r, keys, audience)
+			require.Equal(t, want, ok, path)
+		}
+	})
+
+	t.Run("NoAudience", func(t *testing.T) {
+		t.Parallel()
+
+		tok := payload
+		tok.Audience = ""
+		_, err := coderdtest.AppSecurityKey.SignProxyToken(tok)
+		require.ErrorContains(t, err, "audience is required")
+	})
+
+	t.Run("OtherAudience", func(t *testing.T) {
+		t.Parallel()
+
+		tokenStr, err := coderdtest.AppSecurityKey.SignProxyToken(payload)
+		require.NoError(t, err)
+
+		_, err = workspaceapps.VerifyProxyToken(keys, uuid.NewString(), tokenStr)
+		require.ErrorContains(t, err, "was issued to")
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		t.Parallel()
+
+		tok := payload
+		tok.Expiry = time.Now().Add(-time.Minute)
+		tokenStr, err := coderdtest.AppSecurityKey.SignProxyToken(tok)
+		require.NoError(t, err)
+
+		_, err = workspaceapps.VerifyProxyToken(keys, audience, tokenStr)
+		require.ErrorContains(t, err, "expired")
+	})
+
+	t.Run("OtherKey", func(t *testing.T) {
+		t.Parallel()
+
+		var otherKey workspaceapps.SecurityKey
+		copy(otherKey[:], coderdtest.AppSecurityKey[:])
+		for i := range otherKey {
+			otherKey[i] ^= 0xff
+		}
+		tokenStr, err := otherKey.SignProxyToken(payload)
+		require.NoError(t, err)
+
+		_, err = workspaceapps.VerifyProxyToken(keys, audience, tokenStr)
+		require.ErrorContains(t, err, "unknown signing key")
+	})
+
+	t.Run("PrimaryToken", func(t *testing.T) {
+		t.Parallel()
+
+		// Tokens signed for the primary are not accepted by proxies.
+		tokenStr, err := coderdtest.AppSecurityKey.SignToken(payload)
+		require.NoError(t, err)
+
+		_, err = workspaceapps.VerifyProxyToken(keys, audience, tokenStr)
+		require.ErrorContains(t, err, "expected token signing algorithm")
+	})
+}
+
+func TestIdentity(t *testing.T) {
+	t.Parallel()
+
+	audience := "https://8080--main--ws--user.apps.coder.com/"
+	keys := coderdtest.AppSecurityKey.IdentityKeys()
+	require.Len(t, keys.Keys, 1)
+	require.True(t, keys.Keys[0].IsPublic())
+	// Identities are verified by apps, so they must not be signed with the key
+	// of proxy tokens.
+	require.NotEqual(t, coderdtest.AppSecurityKey.ProxyTokenKeys().Keys[0].KeyID, keys.Keys[0].KeyID)
+
+	claims := codersdk.WorkspaceAppIdentityClaims{
+		Issuer:   "https://coder.com",
+		Subject:  uuid.NewString(),
+		Audience: audience,
+		IssuedAt: time.Now().Unix(),
+		Expiry:   time.Now().Add(workspaceapps.IdentityExpiry).Unix(),
+		Username: "user",
+		Email:    "user@coder.com",
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		identity, err := coderdtest.AppSecurityKey.SignIdentity(claims)
+		require.NoError(t, err)
+
+		got, err := workspaceapps.VerifyIdentity(keys, audience, identity)
+		require.NoError(t, err)
+		require.Equal(t, claims, got)
+	})
+
+	t.Run("OtherAudience", func(t *testing.T) {
+		t.Parallel()
+
+		identity, err := coderdtest.AppSecurityKey.SignIdentity(claims)
+		require.NoError(t, err)
+
+		_, err = workspaceapps.VerifyIdentity(keys, "https://other.apps.coder.com/", identity)
+		require.ErrorContains(t, err, "was issued for")
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		t.Parallel()
+
+		expired := claims
+		expired.Expiry = time.Now().Add(-time.Minute).Unix()
+		identity, err := coderdtest.AppSecurityKey.SignIdentity(expired)
+		require.NoError(t, err)
+
+		_, err = workspaceapps.VerifyIdentity(keys, audience, identity)
+		require.ErrorContains(t, err, "expired")
+	})
+
+	t.Run("ProxyToken", func(t *testing.T) {
+		t.Parallel()
+
+		// Proxy tokens use the same algorithm, but not the same key.
+		tokenStr, err := coderdtest.AppSecurityKey.SignProxyToken(workspaceapps.SignedToken{Audience: audience})
+		require.NoError(t, err)
+
+		_, err = workspaceapps.VerifyIdentity(keys, audience, tokenStr)
+		require.ErrorContains(t, err, "unknown signing key")
+	})
+}
+
+func TestAffinity(t *testing.T) {
+	t.Parallel()
+
+	replicaID := uuid.New()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		value := coderdtest.AppSecurityKey.SignAffinity(replicaID)
+		got, err := coderdtest.AppSecurityKey.VerifyAffinity(value)
+		require.NoError(t, err)
+		require.Equal(t, replicaID, got)
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		t.Parallel()
+
+		value := coderdtest.AppSecurityKey.SignAffinity(replicaID)
+		_, sig, _ := strings.Cut(value, ".")
+		_, err := coderdtest.AppSecurityKey.VerifyAffinity(uuid.NewString() + "." + sig)
+		require.ErrorContains(t, err, "invalid affinity signature")
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := coderdtest.AppSecurityKey.VerifyAffinity(replicaID.String())
+		require.ErrorContains(t, err, "malformed affinity")
+	})
+}
+
 func TestAPIKeyEncryption(t *testing.T) {
 	t.Parallel()
 
@@ -420,3 +694,34 @@ func TestAPIKeyEncryption(t *testing.T) {
 		})
 	})
 }
+
+func TestSecretEncryption(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		encrypted, err := coderdtest.AppSecurityKey.EncryptSecret([]byte("secret"))
+		require.NoError(t, err)
+		require.NotContains(t, encrypted, "secret")
+
+		decrypted, err := coderdtest.AppSecurityKey.DecryptSecret(encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "secret", string(decrypted))
+	})
+
+	t.Run("EncryptionKey", func(t *testing.T) {
+		t.Parallel()
+
+		var otherKey workspaceapps.SecurityKey
+		copy(otherKey[:], coderdtest.AppSecurityKey[:])
+		for i := range otherKey {
+			otherKey[i] ^= 0xff
+		}
+
+		encrypted, err := otherKey.EncryptSecret([]byte("secret"))
+		require.NoError(t, err)
+		_, err = coderdtest.AppSecurityKey.DecryptSecret(encrypted)
+		require.ErrorContains(t, err, "decrypt secret")
+	})
+}
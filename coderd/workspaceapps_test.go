@@ -7,9 +7,11 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
@@ -249,6 +251,53 @@ func TestWorkspaceApplicationAuth(t *testing.T) {
 	}
 }
 
+func TestAuditWorkspaceAccess(t *testing.T) {
+	t.Parallel()
+
+	auditor := audit.NewMock()
+	client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		Auditor:                  auditor,
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	auditor.ResetLogs()
+	app := workspaceapps.AccessEvent{
+		ID:           uuid.New(),
+		Time:         database.Now(),
+		UserID:       user.UserID,
+		WorkspaceID:  workspace.ID,
+		AgentID:      uuid.New(),
+		AccessMethod: workspaceapps.AccessMethodSubdomain,
+		SlugOrPort:   "code-server",
+		IP:           "127.0.0.1:51234",
+		UserAgent:    "test",
+	}
+	terminal := app
+	terminal.ID = uuid.New()
+	terminal.AccessMethod = workspaceapps.AccessMethodTerminal
+	terminal.SlugOrPort = ""
+	err := api.AuditWorkspaceAccess(ctx, app, terminal)
+	require.NoError(t, err)
+
+	logs := auditor.AuditLogs()
+	require.Len(t, logs, 2)
+	require.Equal(t, app.ID, logs[0].ID)
+	require.Equal(t, database.AuditActionOpen, logs[0].Action)
+	require.Equal(t, database.ResourceTypeWorkspace, logs[0].ResourceType)
+	require.Equal(t, workspace.ID, logs[0].ResourceID)
+	require.Equal(t, workspace.Name, logs[0].ResourceTarget)
+	require.Equal(t, user.OrganizationID, logs[0].OrganizationID)
+	require.Equal(t, "127.0.0.1", logs[0].Ip.IPNet.IP.String())
+	require.Equal(t, terminal.ID, logs[1].ID)
+	require.Equal(t, database.AuditActionConnect, logs[1].Action)
+}
+
 func TestWorkspaceApps(t *testing.T) {
 	t.Parallel()
 
@@ -257,6 +306,8 @@ func TestWorkspaceApps(t *testing.T) {
 		deploymentValues.DisablePathApps = clibase.Bool(opts.DisablePathApps)
 		deploymentValues.Dangerous.AllowPathAppSharing = clibase.Bool(opts.DangerousAllowPathAppSharing)
 		deploymentValues.Dangerous.AllowPathAppSiteOwnerAccess = clibase.Bool(opts.DangerousAllowPathAppSiteOwnerAccess)
+		deploymentValues.AppIdentityHeaders = opts.AppIdentityHeaders
+		deploymentValues.PortForwardIdentityHeaders = opts.PortForwardIdentityHeaders
 
 		if opts.DisableSubdomainApps {
 			opts.AppHost = ""
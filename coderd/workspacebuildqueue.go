@@ -0,0 +1,382 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// workspaceBuildQueueSweepInterval is how often every workspace with queued
+// builds is checked, in case the end of its active build wasn't announced.
+const workspaceBuildQueueSweepInterval = 30 * time.Second
+
+// @Summary Get queued workspace builds
+// @ID get-queued-workspace-builds
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.QueuedWorkspaceBuild
+// @Router /workspaces/{workspace}/builds/queue [get]
+func (api *API) workspaceBuildQueue(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	entries, err := api.Database.GetWorkspaceBuildQueueEntriesByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching queued workspace builds.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	queued, err := convertQueuedWorkspaceBuilds(entries)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting queued workspace builds.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, queued)
+}
+
+// queueWorkspaceBuild queues a build that was rejected because the workspace
+// has an active build. With the supersede policy, the active build is canceled
+// and the builds queued before are dropped.
+func (api *API) queueWorkspaceBuild(rw http.ResponseWriter, r *http.Request, workspace database.Workspace, createBuild codersdk.CreateWorkspaceBuildRequest) {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	if createBuild.IfActive == codersdk.WorkspaceBuildConflictPolicySupersede {
+		valid, err := api.verifyUserCanCancelWorkspaceBuilds(ctx, apiKey.UserID, workspace.TemplateID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error verifying permission to cancel workspace build.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if !valid {
+			httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+				Message: "User is not allowed to cancel workspace builds. Owner role is required.",
+			})
+			return
+		}
+	}
+
+	request, err := json.Marshal(createBuild)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error encoding workspace build request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Superseding drops the queued builds and cancels the active build in the
+	// transaction that queues the new build, so they're only dropped and
+	// canceled if the new build is queued.
+	var (
+		entry       database.WorkspaceBuildQueueEntry
+		activeBuild database.WorkspaceBuild
+		activeJob   *database.ProvisionerJob
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		activeJob = nil
+		if createBuild.IfActive == codersdk.WorkspaceBuildConflictPolicySupersede {
+			err := tx.DeleteWorkspaceBuildQueueEntriesByWorkspaceID(ctx, workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("remove queued builds: %w", err)
+			}
+			activeBuild, err = tx.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+			if err != nil {
+				return xerrors.Errorf("get active build: %w", err)
+			}
+			job, err := tx.GetProvisionerJobByID(ctx, activeBuild.JobID)
+			if err != nil {
+				return xerrors.Errorf("get provisioner job: %w", err)
+			}
+			if !job.CompletedAt.Valid && !job.CanceledAt.Valid {
+				err = cancelWorkspaceBuildJob(ctx, tx, job)
+				if err != nil {
+					return xerrors.Errorf("cancel active build: %w", err)
+				}
+				activeJob = &job
+			}
+		}
+
+		var err error
+		entry, err = tx.InsertWorkspaceBuildQueueEntry(ctx, database.InsertWorkspaceBuildQueueEntryParams{
+			ID:                 uuid.New(),
+			WorkspaceID:        workspace.ID,
+			InitiatorID:        apiKey.UserID,
+			InitiatorTokenName: apiKey.TokenName,
+			CreatedAt:          database.Now(),
+			Request:            request,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert queued build: %w", err)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error queueing workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if activeJob != nil {
+		api.publishWorkspaceBuildCanceled(ctx, activeBuild, *activeJob)
+	}
+
+	entries, err := api.Database.GetWorkspaceBuildQueueEntriesByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching queued workspace builds.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// The active build may have finished since it was checked, in which case
+	// nothing else would start the queued build until the next sweep.
+	api.notifyWorkspaceBuildQueue(workspace.ID)
+
+	queued, err := convertQueuedWorkspaceBuilds(entries)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error converting queued workspace builds.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	for _, build := range queued {
+		if build.ID == entry.ID {
+			httpapi.Write(ctx, rw, http.StatusAccepted, build)
+			return
+		}
+	}
+	// The build already started, so it's no longer in the queue.
+	httpapi.Write(ctx, rw, http.StatusAccepted, codersdk.QueuedWorkspaceBuild{
+		ID:                entry.ID,
+		WorkspaceID:       entry.WorkspaceID,
+		InitiatorID:       entry.InitiatorID,
+		CreatedAt:         entry.CreatedAt,
+		Transition:        createBuild.Transition,
+		TemplateVersionID: createBuild.TemplateVersionID,
+	})
+}
+
+func (api *API) notifyWorkspaceBuildQueue(workspaceID uuid.UUID) {
+	select {
+	case api.workspaceBuildQueueNotify <- workspaceID:
+	default:
+		// The sweep starts the queued builds if too many are waiting.
+	}
+}
+
+// runWorkspaceBuildQueue starts queued builds when the active build of their
+// workspace finishes. Every replica runs it, and the build is created in the
+// same transaction that removes it from the queue, so each queued build starts
+// once.
+func (api *API) runWorkspaceBuildQueue() {
+	defer close(api.workspaceBuildQueueDone)
+
+	//nolint:gocritic // The queue starts builds on behalf of their initiators.
+	ctx := dbauthz.AsSystemRestricted(api.ctx)
+	logger := api.Logger.Named("workspace_build_queue")
+
+	cancelSub, err := api.Pubsub.Subscribe(provisionerdserver.EventWorkspaceBuildFinished, func(_ context.Context, message []byte) {
+		workspaceID, err := uuid.ParseBytes(message)
+		if err != nil {
+			logger.Warn(ctx, "invalid finished workspace build message", slog.Error(err))
+			return
+		}
+		api.notifyWorkspaceBuildQueue(workspaceID)
+	})
+	if err != nil {
+		logger.Error(ctx, "subscribe to finished workspace builds", slog.Error(err))
+	} else {
+		defer cancelSub()
+	}
+
+	ticker := time.NewTicker(workspaceBuildQueueSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case workspaceID := <-api.workspaceBuildQueueNotify:
+			api.startQueuedWorkspaceBuild(ctx, logger, workspaceID)
+		case <-ticker.C:
+			workspaceIDs, err := api.Database.GetWorkspaceBuildQueueWorkspaceIDs(ctx)
+			if err != nil {
+				if !database.IsQueryCanceledError(err) {
+					logger.Error(ctx, "get workspaces with queued builds", slog.Error(err))
+				}
+				continue
+			}
+			for _, workspaceID := range workspaceIDs {
+				api.startQueuedWorkspaceBuild(ctx, logger, workspaceID)
+			}
+		}
+	}
+}
+
+// startQueuedWorkspaceBuild starts the next queued build of the workspace if
+// the workspace has no active build. Queued builds that became invalid while
+// waiting, e.g. because their template version was archived, are dropped.
+func (api *API) startQueuedWorkspaceBuild(ctx context.Context, logger slog.Logger, workspaceID uuid.UUID) {
+	entries, err := api.Database.GetWorkspaceBuildQueueEntriesByWorkspaceID(ctx, workspaceID)
+	if err != nil {
+		logger.Error(ctx, "get queued workspace builds", slog.F("workspace_id", workspaceID), slog.Error(err))
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+	entry := entries[0]
+	logger = logger.With(slog.F("workspace_id", workspaceID), slog.F("queued_build_id", entry.ID))
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, workspaceID)
+	if err != nil {
+		logger.Error(ctx, "get workspace of queued build", slog.Error(err))
+		return
+	}
+	if workspace.Deleted {
+		err = api.Database.DeleteWorkspaceBuildQueueEntriesByWorkspaceID(ctx, workspaceID)
+		if err != nil {
+			logger.Error(ctx, "remove queued builds of deleted workspace", slog.Error(err))
+		}
+		return
+	}
+	var createBuild codersdk.CreateWorkspaceBuildRequest
+	err = json.Unmarshal(entry.Request, &createBuild)
+	if err != nil {
+		logger.Error(ctx, "decode queued workspace build request", slog.Error(err))
+		return
+	}
+
+	// The build starts with the permissions the initiator has now, so builds
+	// of users who were suspended or lost access while waiting don't start.
+	initiator, err := api.Database.GetUserByID(ctx, entry.InitiatorID)
+	if err != nil {
+		logger.Error(ctx, "get initiator of queued build", slog.Error(err))
+		return
+	}
+	if initiator.Deleted || initiator.Status == database.UserStatusSuspended {
+		api.dropQueuedWorkspaceBuild(ctx, logger, entry, "the initiator is no longer active", nil)
+		return
+	}
+	roles, err := api.Database.GetAuthorizationUserRoles(ctx, initiator.ID)
+	if err != nil {
+		logger.Error(ctx, "get roles of queued build initiator", slog.Error(err))
+		return
+	}
+	subject := rbac.Subject{
+		ID:     initiator.ID.String(),
+		Roles:  rbac.RoleNames(roles.Roles),
+		Groups: roles.Groups,
+		Scope:  rbac.ScopeAll,
+	}.WithCachedASTValue()
+	//nolint:gocritic // Acting as the initiator of the queued build.
+	initiatorCtx := dbauthz.As(ctx, subject)
+	authorize := func(action rbac.Action, object rbac.Objecter) bool {
+		return api.HTTPAuth.Authorizer.Authorize(initiatorCtx, subject, action, object.RBACObject()) == nil
+	}
+
+	var (
+		workspaceBuild *database.WorkspaceBuild
+		provisionerJob *database.ProvisionerJob
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		var err error
+		builder := api.workspaceBuilder(workspace, createBuild, entry.InitiatorID, entry.InitiatorTokenName)
+		workspaceBuild, provisionerJob, err = builder.Build(initiatorCtx, tx, authorize)
+		if err != nil {
+			return err
+		}
+		err = tx.DeleteWorkspaceBuildQueueEntryByID(ctx, entry.ID)
+		if err != nil {
+			return xerrors.Errorf("remove queued build: %w", err)
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	var buildErr wsbuilder.BuildError
+	switch {
+	case err == nil:
+	case xerrors.Is(err, wsbuilder.ErrActiveBuild):
+		// The build starts once the active build finishes.
+		return
+	case database.IsSerializedError(err) || database.IsUniqueViolation(err):
+		// Another replica started a build for the workspace at the same time.
+		return
+	case dbauthz.IsNotAuthorizedError(err):
+		api.dropQueuedWorkspaceBuild(ctx, logger, entry, "the initiator is no longer allowed to build the workspace", err)
+		return
+	case xerrors.As(err, &buildErr) && buildErr.Status < http.StatusInternalServerError:
+		api.dropQueuedWorkspaceBuild(ctx, logger, entry, buildErr.Message, err)
+		return
+	default:
+		logger.Error(ctx, "start queued workspace build", slog.Error(err))
+		return
+	}
+
+	err = provisionerdserver.PostJob(api.Pubsub, *provisionerJob)
+	if err != nil {
+		logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+	api.publishWorkspaceUpdate(ctx, workspaceID)
+	logger.Info(ctx, "started queued workspace build", slog.F("workspace_build_id", workspaceBuild.ID))
+}
+
+// dropQueuedWorkspaceBuild removes a queued build that can no longer be
+// built, and checks the next queued build of the workspace.
+func (api *API) dropQueuedWorkspaceBuild(ctx context.Context, logger slog.Logger, entry database.WorkspaceBuildQueueEntry, reason string, err error) {
+	logger.Warn(ctx, "dropping queued workspace build that can no longer be built",
+		slog.F("reason", reason), slog.Error(err))
+	err = api.Database.DeleteWorkspaceBuildQueueEntryByID(ctx, entry.ID)
+	if err != nil {
+		logger.Error(ctx, "remove queued workspace build", slog.Error(err))
+	}
+	// The next queued build may still be valid.
+	api.notifyWorkspaceBuildQueue(entry.WorkspaceID)
+}
+
+func convertQueuedWorkspaceBuilds(entries []database.WorkspaceBuildQueueEntry) ([]codersdk.QueuedWorkspaceBuild, error) {
+	queued := make([]codersdk.QueuedWorkspaceBuild, 0, len(entries))
+	for i, entry := range entries {
+		var createBuild codersdk.CreateWorkspaceBuildRequest
+		err := json.Unmarshal(entry.Request, &createBuild)
+		if err != nil {
+			return nil, xerrors.Errorf("decode request of queued build %s: %w", entry.ID, err)
+		}
+		queued = append(queued, codersdk.QueuedWorkspaceBuild{
+			ID:                entry.ID,
+			WorkspaceID:       entry.WorkspaceID,
+			InitiatorID:       entry.InitiatorID,
+			CreatedAt:         entry.CreatedAt,
+			Transition:        createBuild.Transition,
+			TemplateVersionID: createBuild.TemplateVersionID,
+			Position:          i + 1,
+		})
+	}
+	return queued, nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/wsbuilder"
 	"github.com/coder/coder/v2/codersdk"
@@ -98,6 +100,8 @@ func (api *API) workspaceBuild(rw http.ResponseWriter, r *http.Request) {
 // @Param limit query int false "Page limit"
 // @Param offset query int false "Page offset"
 // @Param since query string false "Since timestamp" format(date-time)
+// @Param reason query string false "Build reason" Enums(initiator,autostart,autostop,autolock,failedstop,autodelete,bulk)
+// @Param initiator_id query string false "Initiator ID" format(uuid)
 // @Success 200 {array} codersdk.WorkspaceBuild
 // @Router /workspaces/{workspace}/builds [get]
 func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
@@ -124,6 +128,28 @@ func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	reason := r.URL.Query().Get("reason")
+	if reason != "" && !database.BuildReason(reason).Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid build reason %q.", reason),
+		})
+		return
+	}
+
+	var initiatorID uuid.UUID
+	initiatorParam := r.URL.Query().Get("initiator_id")
+	if initiatorParam != "" {
+		var err error
+		initiatorID, err = uuid.Parse(initiatorParam)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "bad `initiator_id` format, must be a UUID",
+				Detail:  err.Error(),
+			})
+			return
+		}
+	}
+
 	var workspaceBuilds []database.WorkspaceBuild
 	// Ensure all db calls happen in the same tx
 	err := api.Database.InTx(func(store database.Store) error {
@@ -152,6 +178,8 @@ func (api *API) workspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 			OffsetOpt:   int32(paginationParams.Offset),
 			LimitOpt:    int32(paginationParams.Limit),
 			Since:       database.Time(since),
+			Reason:      reason,
+			InitiatorID: initiatorID,
 		}
 		workspaceBuilds, err = store.GetWorkspaceBuildsByWorkspaceID(ctx, req)
 		if xerrors.Is(err, sql.ErrNoRows) {
@@ -302,6 +330,7 @@ func (api *API) workspaceBuildByBuildNumber(rw http.ResponseWriter, r *http.Requ
 // @Param workspace path string true "Workspace ID" format(uuid)
 // @Param request body codersdk.CreateWorkspaceBuildRequest true "Create workspace build request"
 // @Success 200 {object} codersdk.WorkspaceBuild
+// @Success 202 {object} codersdk.QueuedWorkspaceBuild
 // @Router /workspaces/{workspace}/builds [post]
 // nolint:gocyclo
 func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
@@ -313,16 +342,6 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	builder := wsbuilder.New(workspace, database.WorkspaceTransition(createBuild.Transition)).
-		Initiator(apiKey.UserID).
-		RichParameterValues(createBuild.RichParameterValues).
-		LogLevel(string(createBuild.LogLevel)).
-		DeploymentValues(api.Options.DeploymentValues)
-
-	if createBuild.TemplateVersionID != uuid.Nil {
-		builder = builder.VersionID(createBuild.TemplateVersionID)
-	}
-
 	if createBuild.Orphan {
 		if createBuild.Transition != codersdk.WorkspaceTransitionDelete {
 			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -336,12 +355,9 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
-		builder = builder.Orphan()
-	}
-	if len(createBuild.ProvisionerState) > 0 {
-		builder = builder.State(createBuild.ProvisionerState)
 	}
 
+	builder := api.workspaceBuilder(workspace, createBuild, apiKey.UserID, apiKey.TokenName)
 	workspaceBuild, provisionerJob, err := builder.Build(
 		ctx,
 		api.Database,
@@ -349,6 +365,10 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 			return api.Authorize(r, action, object)
 		},
 	)
+	if xerrors.Is(err, wsbuilder.ErrActiveBuild) && createBuild.IfActive != "" && createBuild.IfActive != codersdk.WorkspaceBuildConflictPolicyFail {
+		api.queueWorkspaceBuild(rw, r, workspace, createBuild)
+		return
+	}
 	var buildErr wsbuilder.BuildError
 	if xerrors.As(err, &buildErr) {
 		var authErr dbauthz.NotAuthorizedError
@@ -373,6 +393,11 @@ func (api *API) postWorkspaceBuilds(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+	err = provisionerdserver.PostJob(api.Pubsub, *provisionerJob)
+	if err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
 
 	users, err := api.Database.GetUsersByIDs(ctx, []uuid.UUID{
 		workspace.OwnerID,
@@ -467,7 +492,25 @@ func (api *API) patchCancelWorkspaceBuild(rw http.ResponseWriter, r *http.Reques
 		})
 		return
 	}
-	err = api.Database.UpdateProvisionerJobWithCancelByID(ctx, database.UpdateProvisionerJobWithCancelByIDParams{
+	err = cancelWorkspaceBuildJob(ctx, api.Database, job)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	api.publishWorkspaceBuildCanceled(ctx, workspaceBuild, job)
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+		Message: "Job has been marked as canceled...",
+	})
+}
+
+// cancelWorkspaceBuildJob marks the job of a build as canceled. Callers must
+// call publishWorkspaceBuildCanceled once the cancellation is committed.
+func cancelWorkspaceBuildJob(ctx context.Context, db database.Store, job database.ProvisionerJob) error {
+	return db.UpdateProvisionerJobWithCancelByID(ctx, database.UpdateProvisionerJobWithCancelByIDParams{
 		ID: job.ID,
 		CanceledAt: sql.NullTime{
 			Time:  database.Now(),
@@ -479,19 +522,51 @@ func (api *API) patchCancelWorkspaceBuild(rw http.ResponseWriter, r *http.Reques
 			Valid: !job.WorkerID.Valid,
 		},
 	})
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
-			Message: "Internal error updating provisioner job.",
-			Detail:  err.Error(),
-		})
-		return
+}
+
+// publishWorkspaceBuildCanceled announces that the job of the build was
+// canceled.
+func (api *API) publishWorkspaceBuildCanceled(ctx context.Context, workspaceBuild database.WorkspaceBuild, job database.ProvisionerJob) {
+	api.publishWorkspaceUpdate(ctx, workspaceBuild.WorkspaceID)
+	if !job.WorkerID.Valid {
+		// Pending jobs are completed here, so no provisioner will report
+		// the cancellation.
+		api.PlatformEvents.WorkspaceBuild(ctx, codersdk.PlatformEventTypeWorkspaceBuildCanceled, workspaceBuild, "")
+		err := provisionerdserver.PublishWorkspaceBuildFinished(api.Pubsub, workspaceBuild.WorkspaceID)
+		if err != nil {
+			api.Logger.Warn(ctx, "failed to publish finished workspace build", slog.Error(err))
+		}
 	}
+}
 
-	api.publishWorkspaceUpdate(ctx, workspace.ID)
+// workspaceBuilder returns a builder for a build requested with req. The
+// request must already be validated.
+func (api *API) workspaceBuilder(workspace database.Workspace, req codersdk.CreateWorkspaceBuildRequest, initiatorID uuid.UUID, initiatorTokenName string) wsbuilder.Builder {
+	builder := wsbuilder.New(workspace, database.WorkspaceTransition(req.Transition)).
+		Initiator(initiatorID).
+		InitiatorTokenName(initiatorTokenName).
+		RichParameterValues(req.RichParameterValues).
+		LogLevel(string(req.LogLevel)).
+		DeploymentValues(api.Options.DeploymentValues).
+		ParameterOptions(api.parameterOptions)
 
-	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
-		Message: "Job has been marked as canceled...",
-	})
+	if req.Reason != "" {
+		builder = builder.Reason(database.BuildReason(req.Reason))
+	}
+
+	if req.TemplateVersionID != uuid.Nil {
+		builder = builder.VersionID(req.TemplateVersionID)
+	} else if req.Transition == codersdk.WorkspaceTransitionStart && workspace.AutomaticUpdates == database.AutomaticUpdatesAlways {
+		builder = builder.ActiveVersion()
+	}
+
+	if req.Orphan {
+		builder = builder.Orphan()
+	}
+	if len(req.ProvisionerState) > 0 {
+		builder = builder.State(req.ProvisionerState)
+	}
+	return builder
 }
 
 func (api *API) verifyUserCanCancelWorkspaceBuilds(ctx context.Context, userID uuid.UUID, templateID uuid.UUID) (bool, error) {
@@ -611,9 +686,9 @@ func (api *API) workspaceBuildState(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// You must have update permissions on the template to get the state.
-	// This matches a push!
-	if !api.Authorize(r, rbac.ActionUpdate, template.RBACObject()) {
+	// You must be able to push versions of the template to get the state,
+	// since pushing state back is limited the same way.
+	if !api.Authorize(r, rbac.ActionPushVersion, template.RBACObject()) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -623,6 +698,91 @@ func (api *API) workspaceBuildState(rw http.ResponseWriter, r *http.Request) {
 	_, _ = rw.Write(workspaceBuild.ProvisionerState)
 }
 
+// @Summary Get workspace build timeline
+// @ID get-workspace-build-timeline
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Builds
+// @Param workspacebuild path string true "Workspace build ID"
+// @Success 200 {object} codersdk.WorkspaceBuildTimeline
+// @Router /workspacebuilds/{workspacebuild}/timeline [get]
+func (api *API) workspaceBuildTimeline(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspaceBuild := httpmw.WorkspaceBuildParam(r)
+
+	job, err := api.Database.GetProvisionerJobByID(ctx, workspaceBuild.JobID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner job.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	logs, err := api.Database.GetProvisionerLogsAfterID(ctx, database.GetProvisionerLogsAfterIDParams{
+		JobID: job.ID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner logs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	hooks, err := api.Database.GetProvisionerJobBuildHooksByJobID(ctx, job.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching build hooks.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	timeline := codersdk.WorkspaceBuildTimeline{
+		Stages: convertBuildTimelineStages(logs, job),
+		Hooks:  make([]codersdk.WorkspaceBuildHook, 0, len(hooks)),
+	}
+	for _, hook := range hooks {
+		timeline.Hooks = append(timeline.Hooks, codersdk.WorkspaceBuildHook{
+			Name:        hook.Name,
+			Phase:       codersdk.BuildHookPhase(hook.Phase),
+			StartedAt:   hook.StartedAt,
+			CompletedAt: hook.CompletedAt,
+			Attempts:    hook.Attempts,
+			ExitCode:    hook.ExitCode,
+			Error:       hook.Error,
+		})
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, timeline)
+}
+
+// convertBuildTimelineStages groups consecutive logs of the same stage. A
+// stage ends when the next one starts, and the last stage ends when the job
+// completes.
+func convertBuildTimelineStages(logs []database.ProvisionerJobLog, job database.ProvisionerJob) []codersdk.WorkspaceBuildTimelineStage {
+	stages := make([]codersdk.WorkspaceBuildTimelineStage, 0)
+	for _, log := range logs {
+		if log.Stage == "" {
+			continue
+		}
+		if len(stages) > 0 && stages[len(stages)-1].Name == log.Stage {
+			continue
+		}
+		if len(stages) > 0 {
+			startedAt := log.CreatedAt
+			stages[len(stages)-1].CompletedAt = &startedAt
+		}
+		stages = append(stages, codersdk.WorkspaceBuildTimelineStage{
+			Name:      log.Stage,
+			StartedAt: log.CreatedAt,
+		})
+	}
+	if len(stages) > 0 && job.CompletedAt.Valid {
+		completedAt := job.CompletedAt.Time
+		stages[len(stages)-1].CompletedAt = &completedAt
+	}
+	return stages
+}
+
 type workspaceBuildsData struct {
 	users            []database.User
 	jobs             []database.GetProvisionerJobsByIDsWithQueuePositionRow
@@ -843,6 +1003,7 @@ func (api *API) convertWorkspaceBuild(
 		metadata := append(make([]database.WorkspaceResourceMetadatum, 0), metadataByResourceID[resource.ID]...)
 		apiResources = append(apiResources, convertWorkspaceResource(resource, apiAgents, metadata))
 	}
+	sortWorkspaceResources(apiResources)
 	apiJob := convertProvisionerJob(job)
 	transition := codersdk.WorkspaceTransition(build.Transition)
 	return codersdk.WorkspaceBuild{
@@ -863,6 +1024,7 @@ func (api *API) convertWorkspaceBuild(
 		Deadline:            codersdk.NewNullTime(build.Deadline, !build.Deadline.IsZero()),
 		MaxDeadline:         codersdk.NewNullTime(build.MaxDeadline, !build.MaxDeadline.IsZero()),
 		Reason:              codersdk.BuildReason(build.Reason),
+		InitiatorTokenName:  build.InitiatorTokenName,
 		Resources:           apiResources,
 		Status:              convertWorkspaceStatus(apiJob.Status, transition),
 		DailyCost:           build.DailyCost,
@@ -879,21 +1041,35 @@ func convertWorkspaceResource(resource database.WorkspaceResource, agents []code
 		})
 	}
 
+	sort.SliceStable(agents, func(i, j int) bool {
+		return agents[i].DisplayOrder < agents[j].DisplayOrder
+	})
+
 	return codersdk.WorkspaceResource{
-		ID:         resource.ID,
-		CreatedAt:  resource.CreatedAt,
-		JobID:      resource.JobID,
-		Transition: codersdk.WorkspaceTransition(resource.Transition),
-		Type:       resource.Type,
-		Name:       resource.Name,
-		Hide:       resource.Hide,
-		Icon:       resource.Icon,
-		Agents:     agents,
-		Metadata:   convertedMetadata,
-		DailyCost:  resource.DailyCost,
+		ID:           resource.ID,
+		CreatedAt:    resource.CreatedAt,
+		JobID:        resource.JobID,
+		Transition:   codersdk.WorkspaceTransition(resource.Transition),
+		Type:         resource.Type,
+		Name:         resource.Name,
+		Hide:         resource.Hide,
+		Icon:         resource.Icon,
+		Agents:       agents,
+		Metadata:     convertedMetadata,
+		DailyCost:    resource.DailyCost,
+		DisplayOrder: resource.DisplayOrder,
 	}
 }
 
+// sortWorkspaceResources orders resources as defined by the template. The
+// sort is stable, so resources with the same display order keep their
+// existing order.
+func sortWorkspaceResources(resources []codersdk.WorkspaceResource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resources[i].DisplayOrder < resources[j].DisplayOrder
+	})
+}
+
 func convertWorkspaceStatus(jobStatus codersdk.ProvisionerJobStatus, transition codersdk.WorkspaceTransition) codersdk.WorkspaceStatus {
 	switch jobStatus {
 	case codersdk.ProvisionerJobPending:
@@ -2,9 +2,11 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 	"time"
@@ -14,10 +16,13 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/provisioner/echo"
 	"github.com/coder/coder/v2/provisionersdk/proto"
@@ -276,6 +281,61 @@ func TestWorkspaceBuilds(t *testing.T) {
 		require.Equal(t, expectedBuilds[0].ID, secondPage[0].ID)
 		require.Equal(t, workspace.LatestBuild.ID, secondPage[1].ID) // build created while creating workspace
 	})
+
+	t.Run("FilterReasonAndInitiator", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		first := coderdtest.CreateFirstUser(t, client)
+		second, secondUser := coderdtest.CreateAnotherUser(t, client, first.OrganizationID, "owner")
+		version := coderdtest.CreateTemplateVersion(t, client, first.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, first.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, first.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		bulk, err := second.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+			Reason:     codersdk.BuildReasonBulk,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.BuildReasonBulk, bulk.Reason)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, bulk.ID)
+
+		builds, err := client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{
+			WorkspaceID: workspace.ID,
+			Reason:      codersdk.BuildReasonBulk,
+		})
+		require.NoError(t, err)
+		require.Len(t, builds, 1)
+		require.Equal(t, bulk.ID, builds[0].ID)
+
+		builds, err = client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{
+			WorkspaceID: workspace.ID,
+			InitiatorID: first.UserID,
+		})
+		require.NoError(t, err)
+		require.Len(t, builds, 1)
+		require.Equal(t, workspace.LatestBuild.ID, builds[0].ID)
+
+		builds, err = client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{
+			WorkspaceID: workspace.ID,
+			Reason:      codersdk.BuildReasonInitiator,
+			InitiatorID: secondUser.ID,
+		})
+		require.NoError(t, err)
+		require.Len(t, builds, 0)
+
+		_, err = client.WorkspaceBuilds(ctx, codersdk.WorkspaceBuildsRequest{
+			WorkspaceID: workspace.ID,
+			Reason:      "invalid",
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusBadRequest, apiError.StatusCode())
+	})
 }
 
 func TestWorkspaceBuildsProvisionerState(t *testing.T) {
@@ -486,6 +546,75 @@ func TestWorkspaceBuildResources(t *testing.T) {
 		require.Equal(t, "example", workspace.LatestBuild.Resources[1].Type)
 		require.Len(t, workspace.LatestBuild.Resources[0].Agents, 1)
 	})
+	t.Run("DisplayOrder", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionApply: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Resources: []*proto.Resource{{
+							Name:  "first",
+							Type:  "example",
+							Order: 2,
+							Agents: []*proto.Agent{{
+								Id:    "dev",
+								Name:  "dev",
+								Order: 1,
+								Auth:  &proto.Agent_Token{},
+								Apps: []*proto.App{{
+									Slug:  "aaa",
+									Order: 2,
+								}, {
+									Slug:   "bbb",
+									Order:  1,
+									Hidden: true,
+								}},
+							}, {
+								Id:    "build",
+								Name:  "build",
+								Order: 0,
+								Auth:  &proto.Agent_Token{},
+							}},
+						}, {
+							Name:  "second",
+							Type:  "example",
+							Order: 1,
+						}},
+					},
+				},
+			}},
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		workspace, err := client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		resources := workspace.LatestBuild.Resources
+		require.Len(t, resources, 2)
+		require.Equal(t, "second", resources[0].Name)
+		require.Equal(t, "first", resources[1].Name)
+		require.EqualValues(t, 2, resources[1].DisplayOrder)
+
+		agents := resources[1].Agents
+		require.Len(t, agents, 2)
+		require.Equal(t, "build", agents[0].Name)
+		require.Equal(t, "dev", agents[1].Name)
+
+		apps := agents[1].Apps
+		require.Len(t, apps, 2)
+		require.Equal(t, "bbb", apps[0].Slug)
+		require.True(t, apps[0].Hidden)
+		require.Equal(t, "aaa", apps[1].Slug)
+		require.False(t, apps[1].Hidden)
+	})
 }
 
 func TestWorkspaceBuildLogs(t *testing.T) {
@@ -570,6 +699,52 @@ func TestWorkspaceBuildState(t *testing.T) {
 	require.Equal(t, wantState, gotState)
 }
 
+func TestWorkspaceBuildTimeline(t *testing.T) {
+	t.Parallel()
+	client, _, api := coderdtest.NewWithAPI(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	startedAt := database.Now().Add(-time.Minute)
+	_, err := api.Database.InsertProvisionerJobBuildHook(dbauthz.AsSystemRestricted(ctx), database.InsertProvisionerJobBuildHookParams{
+		ID:          uuid.New(),
+		JobID:       build.Job.ID,
+		Name:        "register-dns",
+		Phase:       database.BuildHookPhasePre,
+		StartedAt:   startedAt,
+		CompletedAt: startedAt.Add(time.Second),
+		Attempts:    2,
+		ExitCode:    0,
+	})
+	require.NoError(t, err)
+
+	timeline, err := client.WorkspaceBuildTimeline(ctx, build.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, timeline.Stages)
+	for i, stage := range timeline.Stages {
+		require.NotEmpty(t, stage.Name)
+		require.NotNil(t, stage.CompletedAt, "stage %q should be completed", stage.Name)
+		require.False(t, stage.CompletedAt.Before(stage.StartedAt))
+		if i > 0 {
+			require.NotEqual(t, timeline.Stages[i-1].Name, stage.Name)
+		}
+	}
+	require.Len(t, timeline.Hooks, 1)
+	hook := timeline.Hooks[0]
+	require.Equal(t, "register-dns", hook.Name)
+	require.Equal(t, codersdk.BuildHookPhasePre, hook.Phase)
+	require.EqualValues(t, 2, hook.Attempts)
+	require.Zero(t, hook.ExitCode)
+	require.Empty(t, hook.Error)
+}
+
 func TestWorkspaceBuildStatus(t *testing.T) {
 	t.Parallel()
 
@@ -845,3 +1020,321 @@ func TestWorkspaceBuildDebugMode(t *testing.T) {
 		require.Len(t, echoResponses.ProvisionApply, logsProcessed)
 	})
 }
+
+func TestWorkspaceBuildRequireActiveVersion(t *testing.T) {
+	t.Parallel()
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	oldVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, oldVersion.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, oldVersion.ID)
+	workspace := coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	workspace = coderdtest.MustTransitionWorkspace(t, memberClient, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+	err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+		ID: newVersion.ID,
+	})
+	require.NoError(t, err)
+
+	// During the grace period the workspace can still be started with its
+	// current version.
+	template, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		RequireActiveVersion:                  ptr.Ref(true),
+		RequireActiveVersionGracePeriodMillis: ptr.Ref(time.Hour.Milliseconds()),
+	})
+	require.NoError(t, err)
+	require.True(t, template.RequireActiveVersion)
+	require.Equal(t, time.Hour.Milliseconds(), template.RequireActiveVersionGracePeriodMillis)
+
+	workspace, err = memberClient.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.True(t, workspace.Outdated)
+	require.NotNil(t, workspace.UpdateDeadline)
+	require.True(t, workspace.UpdateDeadline.After(time.Now()))
+
+	build, err := memberClient.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition:        codersdk.WorkspaceTransitionStart,
+		TemplateVersionID: oldVersion.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, oldVersion.ID, build.TemplateVersionID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+	workspace = coderdtest.MustTransitionWorkspace(t, memberClient, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+
+	// Once the grace period has passed, members must use the active version.
+	_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		RequireActiveVersionGracePeriodMillis: ptr.Ref(int64(0)),
+	})
+	require.NoError(t, err)
+
+	_, err = memberClient.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition:        codersdk.WorkspaceTransitionStart,
+		TemplateVersionID: oldVersion.ID,
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	// Users who can push versions of the template are exempt.
+	build, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition:        codersdk.WorkspaceTransitionStart,
+		TemplateVersionID: oldVersion.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, oldVersion.ID, build.TemplateVersionID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+	workspace = coderdtest.MustTransitionWorkspace(t, memberClient, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+
+	// Starting without a version moves the workspace to the active version.
+	build, err = memberClient.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStart,
+	})
+	require.NoError(t, err)
+	require.Equal(t, newVersion.ID, build.TemplateVersionID)
+}
+
+func TestWorkspaceBuildHooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Called", func(t *testing.T) {
+		t.Parallel()
+		events := make(chan codersdk.WorkspaceHookPayload, 8)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			var payload codersdk.WorkspaceHookPayload
+			if assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload)) {
+				events <- payload
+			}
+		}))
+		defer srv.Close()
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.WorkspaceHooks.Value = []codersdk.WorkspaceHookConfig{{
+			Name: "cost",
+			URL:  srv.URL,
+			Events: []codersdk.WorkspaceHookEvent{
+				codersdk.WorkspaceHookEventPreStart, codersdk.WorkspaceHookEventPostStart,
+				codersdk.WorkspaceHookEventPreStop, codersdk.WorkspaceHookEventPostStop,
+			},
+		}}
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, DeploymentValues: dv})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		next := func() codersdk.WorkspaceHookPayload {
+			select {
+			case <-ctx.Done():
+				t.Fatal("timed out waiting for workspace hook")
+			case payload := <-events:
+				return payload
+			}
+			return codersdk.WorkspaceHookPayload{}
+		}
+
+		payload := next()
+		require.Equal(t, codersdk.WorkspaceHookEventPreStart, payload.Event)
+		require.Equal(t, workspace.ID, payload.WorkspaceID)
+		require.Equal(t, workspace.LatestBuild.ID, payload.WorkspaceBuildID)
+		require.Equal(t, template.Name, payload.TemplateName)
+		require.Equal(t, codersdk.WorkspaceHookEventPostStart, next().Event)
+
+		build := coderdtest.CreateWorkspaceBuild(t, client, workspace, database.WorkspaceTransitionStop)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+		payload = next()
+		require.Equal(t, codersdk.WorkspaceHookEventPreStop, payload.Event)
+		require.Equal(t, build.ID, payload.WorkspaceBuildID)
+		require.Equal(t, codersdk.WorkspaceHookEventPostStop, next().Event)
+	})
+
+	t.Run("PreHookFails", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.WorkspaceHooks.Value = []codersdk.WorkspaceHookConfig{{
+			Name:   "license",
+			URL:    srv.URL,
+			Events: []codersdk.WorkspaceHookEvent{codersdk.WorkspaceHookEventPreStart},
+		}}
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, DeploymentValues: dv})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.ProvisionerJobFailed, build.Job.Status)
+		require.Contains(t, build.Job.Error, `pre_start hook "license"`)
+	})
+}
+
+func TestWorkspaceBuildQueue(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (*codersdk.Client, *coderd.API, codersdk.Workspace) {
+		client, closer, api := coderdtest.NewWithAPI(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		// Without a provisioner daemon new builds stay pending.
+		require.NoError(t, closer.Close())
+		return client, api, workspace
+	}
+
+	t.Run("Queue", func(t *testing.T) {
+		t.Parallel()
+		client, api, workspace := setup(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		stop, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+
+		_, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		build, queued, err := client.CreateOrQueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+			IfActive:   codersdk.WorkspaceBuildConflictPolicyQueue,
+		})
+		require.NoError(t, err)
+		require.Nil(t, build)
+		require.NotNil(t, queued)
+		require.EqualValues(t, 1, queued.Position)
+
+		_, queued, err = client.CreateOrQueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+			IfActive:   codersdk.WorkspaceBuildConflictPolicyQueue,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, queued)
+		require.EqualValues(t, 2, queued.Position)
+
+		queue, err := client.WorkspaceBuildQueue(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, queue, 2)
+		require.Equal(t, codersdk.WorkspaceTransitionStart, queue[0].Transition)
+		require.Equal(t, codersdk.WorkspaceTransitionStop, queue[1].Transition)
+
+		// Once builds run again, the queue drains in order.
+		_ = coderdtest.NewProvisionerDaemon(t, api)
+		require.Eventually(t, func() bool {
+			queue, err := client.WorkspaceBuildQueue(ctx, workspace.ID)
+			if err != nil || len(queue) != 0 {
+				return false
+			}
+			workspace, err := client.Workspace(ctx, workspace.ID)
+			if err != nil {
+				return false
+			}
+			latest := workspace.LatestBuild
+			return latest.BuildNumber == stop.BuildNumber+2 &&
+				latest.Transition == codersdk.WorkspaceTransitionStop &&
+				latest.Status == codersdk.WorkspaceStatusStopped
+		}, testutil.WaitLong, testutil.IntervalFast)
+	})
+
+	t.Run("Supersede", func(t *testing.T) {
+		t.Parallel()
+		client, _, workspace := setup(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		stop, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+
+		_, queued, err := client.CreateOrQueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+			IfActive:   codersdk.WorkspaceBuildConflictPolicySupersede,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, queued)
+
+		// Canceling the pending stop starts the superseding build.
+		require.Eventually(t, func() bool {
+			workspace, err := client.Workspace(ctx, workspace.ID)
+			if err != nil {
+				return false
+			}
+			latest := workspace.LatestBuild
+			return latest.BuildNumber == stop.BuildNumber+1 &&
+				latest.Transition == codersdk.WorkspaceTransitionStart
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		queue, err := client.WorkspaceBuildQueue(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, queue)
+
+		stop, err = client.WorkspaceBuild(ctx, stop.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.ProvisionerJobCanceled, stop.Job.Status)
+	})
+
+	t.Run("SuspendedInitiator", func(t *testing.T) {
+		t.Parallel()
+		client, closer, api := coderdtest.NewWithAPI(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.NoError(t, closer.Close())
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		stop, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStop,
+		})
+		require.NoError(t, err)
+		_, queued, err := memberClient.CreateOrQueueWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+			IfActive:   codersdk.WorkspaceBuildConflictPolicyQueue,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, queued)
+
+		// The build of a user suspended while it was queued doesn't start.
+		_, err = client.UpdateUserStatus(ctx, member.ID.String(), codersdk.UserStatusSuspended)
+		require.NoError(t, err)
+		_ = coderdtest.NewProvisionerDaemon(t, api)
+		require.Eventually(t, func() bool {
+			queue, err := client.WorkspaceBuildQueue(ctx, workspace.ID)
+			return err == nil && len(queue) == 0
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		workspace, err = client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, stop.ID, workspace.LatestBuild.ID)
+	})
+}
@@ -0,0 +1,158 @@
+package coderd
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"tailscale.com/tailcfg"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/tailnet"
+)
+
+// workspaceConnectionsBandwidthPeriod is how far back the traffic of
+// connections is summed. Tailnet IP addresses of clients are random per
+// connection, so the traffic of a peer belongs to a single client.
+const workspaceConnectionsBandwidthPeriod = time.Hour
+
+// @Summary Get workspace connections
+// @ID get-workspace-connections
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceConnection
+// @Router /workspaces/{workspace}/connections [get]
+func (api *API) workspaceConnections(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	bandwidths, err := api.Database.GetWorkspaceAgentBandwidthStatsByPeer(ctx, database.GetWorkspaceAgentBandwidthStatsByPeerParams{
+		WorkspaceID:  workspace.ID,
+		CreatedAfter: database.Now().Add(-workspaceConnectionsBandwidthPeriod),
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agent bandwidth.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	type peerKey struct {
+		agentID uuid.UUID
+		peer    string
+	}
+	bandwidthByPeer := make(map[peerKey]database.GetWorkspaceAgentBandwidthStatsByPeerRow, len(bandwidths))
+	for _, bandwidth := range bandwidths {
+		bandwidthByPeer[peerKey{agentID: bandwidth.AgentID, peer: bandwidth.Peer}] = bandwidth
+	}
+
+	coordinator := *api.TailnetCoordinator.Load()
+	derpMap := api.DERPMap()
+	connections := make([]codersdk.WorkspaceConnection, 0)
+	for _, agent := range agents {
+		peers, err := coordinator.AgentPeers(ctx, agent.ID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace agent peers.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		if len(peers) == 0 {
+			continue
+		}
+		records, err := api.Database.GetWorkspaceAgentClientConnections(ctx, database.GetWorkspaceAgentClientConnectionsParams{
+			WorkspaceAgentID: agent.ID,
+			LimitOpt:         workspaceAgentClientConnectionsMaxEntries,
+		})
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error fetching workspace agent client connections.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		recordByID := make(map[uuid.UUID]database.WorkspaceAgentClientConnection, len(records))
+		for _, record := range records {
+			recordByID[record.ID] = record
+		}
+		agentNode := coordinator.Node(agent.ID)
+
+		for _, peer := range peers {
+			connection := convertWorkspaceConnection(derpMap, agent, peer, agentNode)
+			if !peer.Multi {
+				connection.ClientType = codersdk.WorkspaceAgentClientTypeUnknown
+				if record, ok := recordByID[peer.ID]; ok {
+					connection.ClientType = codersdk.WorkspaceAgentClientType(record.ClientType)
+					connection.ClientVersion = record.ClientVersion
+					if record.UserID.Valid {
+						userID := record.UserID.UUID
+						connection.UserID = &userID
+					}
+				}
+			}
+			if bandwidth, ok := bandwidthByPeer[peerKey{agentID: agent.ID, peer: connection.IP}]; ok {
+				connection.RxBytes = bandwidth.RxBytes
+				connection.TxBytes = bandwidth.TxBytes
+			}
+			connections = append(connections, connection)
+		}
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, connections)
+}
+
+// convertWorkspaceConnection describes a client of the agent from the node it
+// sent to the coordinator.
+func convertWorkspaceConnection(derpMap *tailcfg.DERPMap, agent database.WorkspaceAgent, peer tailnet.AgentPeer, agentNode *tailnet.Node) codersdk.WorkspaceConnection {
+	node := peer.Node
+	connection := codersdk.WorkspaceConnection{
+		ID:            peer.ID,
+		AgentID:       agent.ID,
+		AgentName:     agent.Name,
+		Server:        peer.Multi,
+		DERPRegionID:  node.PreferredDERP,
+		DERPLatencyMS: -1,
+		Direct:        len(node.Endpoints) > 0 && agentNode != nil && len(agentNode.Endpoints) > 0,
+		UpdatedAt:     node.AsOf,
+	}
+	if len(node.Addresses) > 0 {
+		connection.IP = node.Addresses[0].Addr().String()
+	}
+	if region, ok := derpMap.Regions[node.PreferredDERP]; ok {
+		connection.DERPRegionName = region.RegionName
+	} else if node.PreferredDERP != 0 {
+		connection.DERPRegionName = fmt.Sprintf("Unnamed %d", node.PreferredDERP)
+	}
+	// Latencies are reported by region and address family, like "1-v4".
+	for rawRegion, latency := range node.DERPLatency {
+		regionID, err := strconv.Atoi(strings.SplitN(rawRegion, "-", 2)[0])
+		if err != nil || regionID != node.PreferredDERP {
+			continue
+		}
+		latencyMS := latency * 1000
+		if connection.DERPLatencyMS < 0 {
+			connection.DERPLatencyMS = latencyMS
+			continue
+		}
+		connection.DERPLatencyMS = math.Min(connection.DERPLatencyMS, latencyMS)
+	}
+	return connection
+}
@@ -0,0 +1,75 @@
+package coderd_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceConnections(t *testing.T) {
+	t.Parallel()
+	client, daemonCloser := coderdtest.NewWithProvisionerCloser(t, nil)
+	user := coderdtest.CreateFirstUser(t, client)
+	authToken := uuid.NewString()
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse:          echo.ParseComplete,
+		ProvisionPlan:  echo.ProvisionComplete,
+		ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+	})
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	daemonCloser.Close()
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	connections, err := client.WorkspaceConnections(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Empty(t, connections)
+
+	agentClient := agentsdk.New(client.URL)
+	agentClient.SetSessionToken(authToken)
+	agentCloser := agent.New(agent.Options{
+		Client: agentClient,
+		Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+	})
+	defer agentCloser.Close()
+	resources := coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+	agentID := resources[0].Agents[0].ID
+
+	conn, err := client.DialWorkspaceAgent(ctx, agentID, &codersdk.DialWorkspaceAgentOptions{
+		Logger:        slogtest.Make(t, nil).Named("client").Leveled(slog.LevelDebug),
+		ClientType:    codersdk.WorkspaceAgentClientTypeVSCode,
+		ClientVersion: "v1.2.3",
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The client sends its node again once it found its preferred DERP
+	// region.
+	require.Eventually(t, func() bool {
+		connections, err = client.WorkspaceConnections(ctx, workspace.ID)
+		return assert.NoError(t, err) && len(connections) == 1 && connections[0].DERPRegionID != 0
+	}, testutil.WaitShort, testutil.IntervalFast)
+	connection := connections[0]
+	require.Equal(t, agentID, connection.AgentID)
+	require.Equal(t, resources[0].Agents[0].Name, connection.AgentName)
+	require.False(t, connection.Server)
+	require.Equal(t, codersdk.WorkspaceAgentClientTypeVSCode, connection.ClientType)
+	require.Equal(t, "v1.2.3", connection.ClientVersion)
+	require.NotNil(t, connection.UserID)
+	require.Equal(t, user.UserID, *connection.UserID)
+	require.NotEmpty(t, connection.IP)
+	require.NotEmpty(t, connection.DERPRegionName)
+}
@@ -0,0 +1,155 @@
+// Package workspacehooks calls the webhooks that a deployment configures
+// around workspace start and stop builds, so external systems can prepare for
+// workspaces and clean up after them.
+package workspacehooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// DefaultTimeout is how long a hook may take when it doesn't set a timeout.
+const DefaultTimeout = 10 * time.Second
+
+// Validate checks that the configured hooks can be called.
+func Validate(hooks []codersdk.WorkspaceHookConfig) error {
+	for i, hook := range hooks {
+		if hook.Name == "" {
+			return xerrors.Errorf("workspace hook %d must have a name", i)
+		}
+		u, err := url.Parse(hook.URL)
+		if err != nil {
+			return xerrors.Errorf("workspace hook %q: parse URL: %w", hook.Name, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return xerrors.Errorf("workspace hook %q: URL %q must use the http or https scheme", hook.Name, hook.URL)
+		}
+		if len(hook.Events) == 0 {
+			return xerrors.Errorf("workspace hook %q must have at least one event", hook.Name)
+		}
+		for _, event := range hook.Events {
+			switch event {
+			case codersdk.WorkspaceHookEventPreStart, codersdk.WorkspaceHookEventPostStart,
+				codersdk.WorkspaceHookEventPreStop, codersdk.WorkspaceHookEventPostStop:
+			default:
+				return xerrors.Errorf("workspace hook %q: unknown event %q", hook.Name, event)
+			}
+		}
+		switch hook.FailurePolicy {
+		case "", codersdk.WorkspaceHookFailurePolicyFail, codersdk.WorkspaceHookFailurePolicyIgnore:
+		default:
+			return xerrors.Errorf("workspace hook %q: unknown failure policy %q", hook.Name, hook.FailurePolicy)
+		}
+		if hook.Timeout < 0 {
+			return xerrors.Errorf("workspace hook %q: timeout must not be negative", hook.Name)
+		}
+	}
+	return nil
+}
+
+// Runner calls the hooks of a deployment.
+type Runner struct {
+	logger slog.Logger
+	client *http.Client
+	hooks  []codersdk.WorkspaceHookConfig
+}
+
+// New returns a Runner that calls hooks using the provided HTTP client. If
+// client is nil, http.DefaultClient is used.
+func New(logger slog.Logger, client *http.Client, hooks []codersdk.WorkspaceHookConfig) *Runner {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Runner{
+		logger: logger.Named("workspacehooks"),
+		client: client,
+		hooks:  hooks,
+	}
+}
+
+// Run calls the hooks of the event of the payload in the order they are
+// configured. It stops at and returns the first failure of a hook whose
+// failure policy is "fail". Other failures are logged.
+func (r *Runner) Run(ctx context.Context, payload codersdk.WorkspaceHookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return xerrors.Errorf("marshal payload: %w", err)
+	}
+	for _, hook := range r.hooks {
+		if !slices.Contains(hook.Events, payload.Event) {
+			continue
+		}
+		logger := r.logger.With(
+			slog.F("hook", hook.Name),
+			slog.F("event", payload.Event),
+			slog.F("workspace_build_id", payload.WorkspaceBuildID),
+		)
+		err := r.call(ctx, hook, body)
+		if err == nil {
+			logger.Debug(ctx, "workspace hook succeeded")
+			continue
+		}
+		err = xerrors.Errorf("%s hook %q: %w", payload.Event, hook.Name, err)
+		if hook.FailurePolicy == codersdk.WorkspaceHookFailurePolicyIgnore || isPostEvent(payload.Event) {
+			logger.Warn(ctx, "workspace hook failed", slog.Error(err))
+			continue
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Runner) call(ctx context.Context, hook codersdk.WorkspaceHookConfig, body []byte) error {
+	timeout := hook.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if hook.Secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set(codersdk.WorkspaceWebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(codersdk.WorkspaceWebhookSignatureHeader, codersdk.SignWorkspaceWebhook(hook.Secret, timestamp, body))
+	}
+	res, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 1<<20))
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return xerrors.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return nil
+}
+
+func isPostEvent(event codersdk.WorkspaceHookEvent) bool {
+	return event == codersdk.WorkspaceHookEventPostStart || event == codersdk.WorkspaceHookEventPostStop
+}
+
+// Events returns the pre and post events of builds with the transition.
+func Events(transition codersdk.WorkspaceTransition) (pre, post codersdk.WorkspaceHookEvent) {
+	if transition == codersdk.WorkspaceTransitionStart {
+		return codersdk.WorkspaceHookEventPreStart, codersdk.WorkspaceHookEventPostStart
+	}
+	// Deleting a workspace stops it, so delete builds run the stop hooks.
+	return codersdk.WorkspaceHookEventPreStop, codersdk.WorkspaceHookEventPostStop
+}
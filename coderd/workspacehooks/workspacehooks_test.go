@@ -0,0 +1,158 @@
+package workspacehooks_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/workspacehooks"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := codersdk.WorkspaceHookConfig{
+		Name:   "dns",
+		URL:    "https://hooks.example.com/dns",
+		Events: []codersdk.WorkspaceHookEvent{codersdk.WorkspaceHookEventPostStart},
+	}
+	require.NoError(t, workspacehooks.Validate([]codersdk.WorkspaceHookConfig{valid}))
+
+	for name, mutate := range map[string]func(*codersdk.WorkspaceHookConfig){
+		"NoName":        func(h *codersdk.WorkspaceHookConfig) { h.Name = "" },
+		"BadScheme":     func(h *codersdk.WorkspaceHookConfig) { h.URL = "ftp://hooks.example.com" },
+		"NoEvents":      func(h *codersdk.WorkspaceHookConfig) { h.Events = nil },
+		"UnknownEvent":  func(h *codersdk.WorkspaceHookConfig) { h.Events = []codersdk.WorkspaceHookEvent{"pre_delete"} },
+		"UnknownPolicy": func(h *codersdk.WorkspaceHookConfig) { h.FailurePolicy = "retry" },
+		"NegativeTimeout": func(h *codersdk.WorkspaceHookConfig) {
+			h.Timeout = -time.Second
+		},
+	} {
+		mutate := mutate
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			hook := valid
+			mutate(&hook)
+			require.Error(t, workspacehooks.Validate([]codersdk.WorkspaceHookConfig{hook}))
+		})
+	}
+}
+
+func TestRunner(t *testing.T) {
+	t.Parallel()
+
+	payload := codersdk.WorkspaceHookPayload{
+		Event:            codersdk.WorkspaceHookEventPreStart,
+		WorkspaceID:      uuid.New(),
+		WorkspaceBuildID: uuid.New(),
+		Transition:       codersdk.WorkspaceTransitionStart,
+	}
+
+	t.Run("Signed", func(t *testing.T) {
+		t.Parallel()
+		var called atomic.Bool
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			called.Store(true)
+			body, err := io.ReadAll(r.Body)
+			if !assert.NoError(t, err) {
+				return
+			}
+			timestamp, err := strconv.ParseInt(r.Header.Get(codersdk.WorkspaceWebhookTimestampHeader), 10, 64)
+			assert.NoError(t, err)
+			assert.Equal(t, codersdk.SignWorkspaceWebhook("secret", timestamp, body), r.Header.Get(codersdk.WorkspaceWebhookSignatureHeader))
+			var got codersdk.WorkspaceHookPayload
+			assert.NoError(t, json.Unmarshal(body, &got))
+			assert.Equal(t, payload, got)
+		}))
+		defer srv.Close()
+
+		runner := workspacehooks.New(slogtest.Make(t, nil), nil, []codersdk.WorkspaceHookConfig{{
+			Name:   "signed",
+			URL:    srv.URL,
+			Events: []codersdk.WorkspaceHookEvent{codersdk.WorkspaceHookEventPreStart},
+			Secret: "secret",
+		}, {
+			Name:   "other-event",
+			URL:    "http://127.0.0.1:0",
+			Events: []codersdk.WorkspaceHookEvent{codersdk.WorkspaceHookEventPreStop},
+		}})
+		err := runner.Run(testutil.Context(t, testutil.WaitShort), payload)
+		require.NoError(t, err)
+		require.True(t, called.Load())
+	})
+
+	t.Run("FailurePolicy", func(t *testing.T) {
+		t.Parallel()
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		hook := codersdk.WorkspaceHookConfig{
+			Name:          "license",
+			URL:           srv.URL,
+			Events:        []codersdk.WorkspaceHookEvent{codersdk.WorkspaceHookEventPreStart, codersdk.WorkspaceHookEventPostStart},
+			FailurePolicy: codersdk.WorkspaceHookFailurePolicyIgnore,
+		}
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err := workspacehooks.New(logger, nil, []codersdk.WorkspaceHookConfig{hook}).Run(ctx, payload)
+		require.NoError(t, err)
+
+		hook.FailurePolicy = codersdk.WorkspaceHookFailurePolicyFail
+		err = workspacehooks.New(logger, nil, []codersdk.WorkspaceHookConfig{hook}).Run(ctx, payload)
+		require.ErrorContains(t, err, `pre_start hook "license"`)
+		require.ErrorContains(t, err, "503")
+
+		// Post hooks can't fail builds that already completed.
+		post := payload
+		post.Event = codersdk.WorkspaceHookEventPostStart
+		err = workspacehooks.New(logger, nil, []codersdk.WorkspaceHookConfig{hook}).Run(ctx, post)
+		require.NoError(t, err)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+		done := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-done:
+			}
+		}))
+		defer srv.Close()
+		defer close(done)
+
+		runner := workspacehooks.New(slogtest.Make(t, nil), nil, []codersdk.WorkspaceHookConfig{{
+			Name:    "slow",
+			URL:     srv.URL,
+			Events:  []codersdk.WorkspaceHookEvent{codersdk.WorkspaceHookEventPreStart},
+			Timeout: 50 * time.Millisecond,
+		}})
+		err := runner.Run(testutil.Context(t, testutil.WaitShort), payload)
+		require.ErrorContains(t, err, "deadline exceeded")
+	})
+}
+
+func TestEvents(t *testing.T) {
+	t.Parallel()
+
+	pre, post := workspacehooks.Events(codersdk.WorkspaceTransitionStart)
+	require.Equal(t, codersdk.WorkspaceHookEventPreStart, pre)
+	require.Equal(t, codersdk.WorkspaceHookEventPostStart, post)
+	pre, post = workspacehooks.Events(codersdk.WorkspaceTransitionDelete)
+	require.Equal(t, codersdk.WorkspaceHookEventPreStop, pre)
+	require.Equal(t, codersdk.WorkspaceHookEventPostStop, post)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"net/http"
+	"sort"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -64,6 +65,9 @@ func (api *API) PrimaryWorkspaceProxy(ctx context.Context) (database.WorkspacePr
 		Url:              region.PathAppURL,
 		WildcardHostname: region.WildcardHostname,
 		Deleted:          false,
+		// The primary is always available to everyone.
+		OrganizationIDs: []uuid.UUID{},
+		GroupIDs:        []uuid.UUID{},
 	}, nil
 }
 
@@ -89,5 +93,94 @@ func (api *API) regions(rw http.ResponseWriter, r *http.Request) {
 
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.RegionsResponse[codersdk.Region]{
 		Regions: []codersdk.Region{region},
+		// The primary region is the only one without workspace proxies.
+		PreferredRegionIDs: []uuid.UUID{region.ID},
 	})
 }
+
+// @Summary Rank regions by client latency
+// @ID rank-regions-by-client-latency
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags WorkspaceProxies
+// @Param request body codersdk.RankRegionsRequest true "Client latencies"
+// @Success 200 {object} codersdk.RegionsResponse[codersdk.RankedRegion]
+// @Router /regions/latency [post]
+func (api *API) rankRegions(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req codersdk.RankRegionsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if err := ValidateRegionLatencies(req.Latencies); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid region latencies.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	//nolint:gocritic // this route intentionally requests resources that users
+	// cannot usually access in order to give them a full list of available
+	// regions.
+	region, err := api.PrimaryRegion(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.RegionsResponse[codersdk.RankedRegion]{
+		Regions: RankRegions([]codersdk.Region{region}, req.Latencies),
+	})
+}
+
+// ValidateRegionLatencies checks the latencies reported by a client.
+func ValidateRegionLatencies(latencies []codersdk.RegionLatency) error {
+	for _, latency := range latencies {
+		if latency.LatencyMS < 0 {
+			return xerrors.Errorf("latency for region %s must not be negative", latency.RegionID)
+		}
+	}
+	return nil
+}
+
+// RankRegions annotates the regions with the latencies reported by a client
+// and orders them from the most to the least preferred. Healthy regions come
+// before unhealthy ones. Within each group, regions the client reported are
+// ordered by the reported latency and come before the rest, which are ordered
+// by the health check latency. Latencies for unknown regions are ignored.
+func RankRegions(regions []codersdk.Region, latencies []codersdk.RegionLatency) []codersdk.RankedRegion {
+	reported := make(map[uuid.UUID]float64, len(latencies))
+	for _, latency := range latencies {
+		reported[latency.RegionID] = latency.LatencyMS
+	}
+
+	ranked := make([]codersdk.RankedRegion, 0, len(regions))
+	for _, region := range regions {
+		rr := codersdk.RankedRegion{Region: region}
+		if latency, ok := reported[region.ID]; ok {
+			latency := latency
+			rr.ClientLatencyMS = &latency
+		}
+		ranked = append(ranked, rr)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Healthy != b.Healthy {
+			return a.Healthy
+		}
+		if (a.ClientLatencyMS != nil) != (b.ClientLatencyMS != nil) {
+			return a.ClientLatencyMS != nil
+		}
+		if a.ClientLatencyMS != nil && *a.ClientLatencyMS != *b.ClientLatencyMS {
+			return *a.ClientLatencyMS < *b.ClientLatencyMS
+		}
+		if a.ClientLatencyMS == nil && a.HealthLatencyMS != b.HealthLatencyMS {
+			return a.HealthLatencyMS < b.HealthLatencyMS
+		}
+		return a.Name < b.Name
+	})
+	return ranked
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
 	"github.com/coder/coder/v2/codersdk"
@@ -50,6 +51,21 @@ func TestRegions(t *testing.T) {
 		regions2, err := client.Regions(ctx)
 		require.NoError(t, err)
 		require.Equal(t, regions[0].ID, regions2[0].ID)
+
+		// Without workspace proxies, the primary region is preferred.
+		preferred, err := client.PreferredRegions(ctx)
+		require.NoError(t, err)
+		require.Len(t, preferred, 1)
+		require.Equal(t, deploymentID, preferred[0].ID)
+
+		ranked, err := client.RankRegions(ctx, codersdk.RankRegionsRequest{
+			Latencies: []codersdk.RegionLatency{{RegionID: deploymentID, LatencyMS: 20}},
+		})
+		require.NoError(t, err)
+		require.Len(t, ranked, 1)
+		require.Equal(t, deploymentID, ranked[0].ID)
+		require.NotNil(t, ranked[0].ClientLatencyMS)
+		require.EqualValues(t, 20, *ranked[0].ClientLatencyMS)
 	})
 
 	t.Run("RequireAuth", func(t *testing.T) {
@@ -65,3 +81,49 @@ func TestRegions(t *testing.T) {
 		require.Empty(t, regions)
 	})
 }
+
+func TestRankRegions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		primary   = codersdk.Region{ID: uuid.New(), Name: "primary", Healthy: true}
+		near      = codersdk.Region{ID: uuid.New(), Name: "near", Healthy: true, HealthLatencyMS: 40}
+		far       = codersdk.Region{ID: uuid.New(), Name: "far", Healthy: true, HealthLatencyMS: 10}
+		unhealthy = codersdk.Region{ID: uuid.New(), Name: "unhealthy"}
+	)
+	regions := []codersdk.Region{unhealthy, primary, far, near}
+
+	t.Run("NoLatencies", func(t *testing.T) {
+		t.Parallel()
+
+		// Without client latencies, regions are ranked by the health check
+		// latency.
+		ranked := coderd.RankRegions(regions, nil)
+		require.Equal(t, []uuid.UUID{primary.ID, far.ID, near.ID, unhealthy.ID}, rankedIDs(ranked))
+		for _, r := range ranked {
+			require.Nil(t, r.ClientLatencyMS)
+		}
+	})
+
+	t.Run("ClientLatencies", func(t *testing.T) {
+		t.Parallel()
+
+		ranked := coderd.RankRegions(regions, []codersdk.RegionLatency{
+			{RegionID: far.ID, LatencyMS: 200},
+			{RegionID: near.ID, LatencyMS: 15},
+			{RegionID: unhealthy.ID, LatencyMS: 1},
+			{RegionID: uuid.New(), LatencyMS: 1},
+		})
+		require.Equal(t, []uuid.UUID{near.ID, far.ID, primary.ID, unhealthy.ID}, rankedIDs(ranked))
+		require.EqualValues(t, 15, *ranked[0].ClientLatencyMS)
+		require.Nil(t, ranked[2].ClientLatencyMS)
+	})
+}
+
+func rankedIDs(regions []codersdk.RankedRegion) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(regions))
+	for _, r := range regions {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
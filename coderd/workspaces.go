@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
 	"time"
 
@@ -17,9 +18,11 @@ import (
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/searchquery"
@@ -34,6 +37,10 @@ var (
 	ttlMin = time.Minute //nolint:revive // min here means 'minimum' not 'minutes'
 	ttlMax = 30 * 24 * time.Hour
 
+	// workspaceRenameRedirectPeriod is how long the previous name of a
+	// renamed workspace keeps resolving to it.
+	workspaceRenameRedirectPeriod = 7 * 24 * time.Hour
+
 	errTTLMin              = xerrors.New("time until shutdown must be at least one minute")
 	errTTLMax              = xerrors.New("time until shutdown must be less than 30 days")
 	errDeadlineTooSoon     = xerrors.New("new deadline must be at least 30 minutes in the future")
@@ -96,6 +103,8 @@ func (api *API) workspace(rw http.ResponseWriter, r *http.Request) {
 		data.builds[0],
 		data.templates[0],
 		findUser(workspace.OwnerID, data.users),
+		data.externalMetadata,
+		data.previousNames,
 	))
 }
 
@@ -247,6 +256,13 @@ func (api *API) workspaceByOwnerAndName(rw http.ResponseWriter, r *http.Request)
 			Deleted: includeDeleted,
 		})
 	}
+	if errors.Is(err, sql.ErrNoRows) {
+		// The workspace may have been renamed recently.
+		workspace, err = api.Database.GetWorkspaceByOwnerIDAndPreviousName(ctx, database.GetWorkspaceByOwnerIDAndPreviousNameParams{
+			OwnerID: owner.ID,
+			Name:    workspaceName,
+		})
+	}
 	if httpapi.Is404Error(err) {
 		httpapi.ResourceNotFound(rw)
 		return
@@ -278,6 +294,8 @@ func (api *API) workspaceByOwnerAndName(rw http.ResponseWriter, r *http.Request)
 		data.builds[0],
 		data.templates[0],
 		findUser(workspace.OwnerID, data.users),
+		data.externalMetadata,
+		data.previousNames,
 	))
 }
 
@@ -298,7 +316,6 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 	var (
 		ctx                   = r.Context()
 		organization          = httpmw.OrganizationParam(r)
-		apiKey                = httpmw.APIKey(r)
 		auditor               = api.Auditor.Load()
 		user                  = httpmw.UserParam(r)
 		workspaceResourceInfo = audit.AdditionalFields{
@@ -333,6 +350,36 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
+	api.createWorkspace(rw, r, aReq, organization, user, createWorkspace, createWorkspaceOptions{})
+}
+
+// createWorkspaceOptions customizes the first build of workspaces created by
+// createWorkspace.
+type createWorkspaceOptions struct {
+	// versionID is the template version of the first build. It defaults to
+	// the active version of the template.
+	versionID uuid.UUID
+	// cloneSource is the workspace whose data the first build copies with the
+	// clone hooks of the template.
+	cloneSource *database.Workspace
+}
+
+// createWorkspace creates a workspace for the user and starts its first
+// build, writing the workspace or an error to the response.
+func (api *API) createWorkspace(
+	rw http.ResponseWriter,
+	r *http.Request,
+	aReq *audit.Request[database.Workspace],
+	organization database.Organization,
+	user database.User,
+	createWorkspace codersdk.CreateWorkspaceRequest,
+	opts createWorkspaceOptions,
+) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+
 	template, err := api.Database.GetTemplateByID(ctx, createWorkspace.TemplateID)
 	if errors.Is(err, sql.ErrNoRows) {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
@@ -398,6 +445,29 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if len(createWorkspace.ProvisionerState) > 0 {
+		_, err = parseTerraformState(createWorkspace.ProvisionerState)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     "Invalid Terraform state.",
+				Validations: []codersdk.ValidationError{{Field: "state", Detail: err.Error()}},
+			})
+			return
+		}
+	}
+
+	dbAutomaticUpdates := database.AutomaticUpdatesNever
+	if createWorkspace.AutomaticUpdates != "" {
+		dbAutomaticUpdates = database.AutomaticUpdates(createWorkspace.AutomaticUpdates)
+		if !dbAutomaticUpdates.Valid() {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message:     "Invalid automatic updates setting.",
+				Validations: []codersdk.ValidationError{{Field: "automatic_updates", Detail: fmt.Sprintf("Must be one of %q.", database.AllAutomaticUpdatesValues())}},
+			})
+			return
+		}
+	}
+
 	// TODO: This should be a system call as the actor might not be able to
 	// read other workspaces. Ideally we check the error on create and look for
 	// a postgres conflict error.
@@ -425,8 +495,9 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 	}
 
 	var (
-		provisionerJob *database.ProvisionerJob
-		workspaceBuild *database.WorkspaceBuild
+		provisionerJob    *database.ProvisionerJob
+		workspaceBuild    *database.WorkspaceBuild
+		workspaceApproval *database.WorkspaceApproval
 	)
 	err = api.Database.InTx(func(db database.Store) error {
 		now := database.Now()
@@ -441,6 +512,7 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 			Name:              createWorkspace.Name,
 			AutostartSchedule: dbAutostartSchedule,
 			Ttl:               dbTTL,
+			AutomaticUpdates:  dbAutomaticUpdates,
 			// The workspaces page will sort by last used at, and it's useful to
 			// have the newly created workspace at the top of the list!
 			LastUsedAt: database.Now(),
@@ -452,13 +524,42 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
 			Reason(database.BuildReasonInitiator).
 			Initiator(apiKey.UserID).
+			InitiatorTokenName(apiKey.TokenName).
 			ActiveVersion().
-			RichParameterValues(createWorkspace.RichParameterValues)
+			RichParameterValues(createWorkspace.RichParameterValues).
+			ParameterOptions(api.parameterOptions)
+		if opts.versionID != uuid.Nil {
+			builder = builder.VersionID(opts.versionID)
+		}
+		if opts.cloneSource != nil {
+			builder = builder.CloneSource(*opts.cloneSource)
+		}
+		if len(createWorkspace.ProvisionerState) > 0 {
+			builder = builder.State(createWorkspace.ProvisionerState)
+		}
 		workspaceBuild, provisionerJob, err = builder.Build(
 			ctx, db, func(action rbac.Action, object rbac.Objecter) bool {
 				return api.Authorize(r, action, object)
 			})
-		return err
+		if err != nil {
+			return err
+		}
+		if template.RequireWorkspaceApproval {
+			// Provisioners won't acquire the job until the request is
+			// approved.
+			approval, err := db.InsertWorkspaceApproval(ctx, database.InsertWorkspaceApprovalParams{
+				ID:          uuid.New(),
+				WorkspaceID: workspace.ID,
+				JobID:       provisionerJob.ID,
+				RequestedBy: apiKey.UserID,
+				CreatedAt:   now,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert workspace approval: %w", err)
+			}
+			workspaceApproval = &approval
+		}
+		return nil
 	}, nil)
 	var bldErr wsbuilder.BuildError
 	if xerrors.As(err, &bldErr) {
@@ -476,6 +577,17 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		return
 	}
 	aReq.New = workspace
+	api.PlatformEvents.Workspace(ctx, codersdk.PlatformEventTypeWorkspaceCreated, workspace)
+	if workspaceApproval != nil {
+		// The job is posted once it's approved.
+		api.PlatformEvents.WorkspaceApproval(ctx, codersdk.PlatformEventTypeWorkspaceApprovalRequested, *workspaceApproval, workspace)
+	} else {
+		err = provisionerdserver.PostJob(api.Pubsub, *provisionerJob)
+		if err != nil {
+			// Client probably doesn't care about this error, so just log it.
+			api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+		}
+	}
 
 	initiator, err := api.Database.GetUserByID(ctx, workspaceBuild.InitiatorID)
 	if err != nil {
@@ -485,6 +597,9 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		})
 		return
 	}
+	if workspaceApproval != nil {
+		api.sendWorkspaceApprovalWebhook(*workspaceApproval, *workspaceBuild, workspace, template, user, initiator)
+	}
 
 	api.Telemetry.Report(&telemetry.Snapshot{
 		Workspaces:      []telemetry.Workspace{telemetry.ConvertWorkspace(workspace)},
@@ -519,9 +634,110 @@ func (api *API) postWorkspacesByOrganization(rw http.ResponseWriter, r *http.Req
 		apiBuild,
 		template,
 		findUser(user.ID, users),
+		nil,
+		nil,
 	))
 }
 
+// Clone a workspace for the currently authenticated user. The clone uses the
+// template version and parameters of the latest build of the workspace, so
+// it can be used to reproduce problems of the workspace.
+//
+// @Summary Clone workspace
+// @ID clone-workspace
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CloneWorkspaceRequest true "Clone workspace request"
+// @Success 201 {object} codersdk.Workspace
+// @Router /workspaces/{workspace}/clone [post]
+func (api *API) postWorkspaceClone(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		source  = httpmw.WorkspaceParam(r)
+		apiKey  = httpmw.APIKey(r)
+		auditor = api.Auditor.Load()
+	)
+
+	user, err := api.Database.GetUserByID(ctx, apiKey.UserID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching user.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	wriBytes, err := json.Marshal(audit.AdditionalFields{
+		WorkspaceOwner: user.Username,
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "marshal workspace owner name")
+	}
+
+	aReq, commitAudit := audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+		Audit:            *auditor,
+		Log:              api.Logger,
+		Request:          r,
+		Action:           database.AuditActionCreate,
+		AdditionalFields: wriBytes,
+	})
+	defer commitAudit()
+
+	// Do this upfront to save work.
+	if !api.Authorize(r, rbac.ActionCreate,
+		rbac.ResourceWorkspace.InOrg(source.OrganizationID).WithOwner(user.ID.String())) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	var req codersdk.CloneWorkspaceRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	organization, err := api.Database.GetOrganizationByID(ctx, source.OrganizationID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching organization.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	build, err := api.Database.GetLatestWorkspaceBuildByWorkspaceID(ctx, source.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching latest workspace build.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	parameters, err := api.Database.GetWorkspaceBuildParameters(ctx, build.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace build parameters.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	opts := createWorkspaceOptions{
+		versionID: build.TemplateVersionID,
+	}
+	if req.CopyData {
+		opts.cloneSource = &source
+	}
+	api.createWorkspace(rw, r, aReq, organization, user, codersdk.CreateWorkspaceRequest{
+		TemplateID:          source.TemplateID,
+		Name:                req.Name,
+		RichParameterValues: db2sdk.WorkspaceBuildParameters(parameters),
+	}, opts)
+}
+
 // @Summary Update workspace metadata by ID
 // @ID update-workspace-metadata-by-id
 // @Security CoderSessionToken
@@ -565,10 +781,39 @@ func (api *API) patchWorkspace(rw http.ResponseWriter, r *http.Request) {
 		name = req.Name
 	}
 
-	newWorkspace, err := api.Database.UpdateWorkspace(ctx, database.UpdateWorkspaceParams{
-		ID:   workspace.ID,
-		Name: name,
-	})
+	var newWorkspace database.Workspace
+	err := api.Database.InTx(func(tx database.Store) error {
+		var err error
+		newWorkspace, err = tx.UpdateWorkspace(ctx, database.UpdateWorkspaceParams{
+			ID:   workspace.ID,
+			Name: name,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Keep resolving the previous name to the workspace for a while, so
+		// bookmarked app URLs and SSH configs don't break right away.
+		now := database.Now()
+		err = tx.UpsertWorkspacePreviousName(ctx, database.UpsertWorkspacePreviousNameParams{
+			WorkspaceID:       workspace.ID,
+			Name:              workspace.Name,
+			RenamedAt:         now,
+			RedirectExpiresAt: now.Add(workspaceRenameRedirectPeriod),
+		})
+		if err != nil {
+			return xerrors.Errorf("record previous name: %w", err)
+		}
+		// Renaming back to a previous name makes it current again.
+		err = tx.DeleteWorkspacePreviousName(ctx, database.DeleteWorkspacePreviousNameParams{
+			WorkspaceID: workspace.ID,
+			Name:        name,
+		})
+		if err != nil {
+			return xerrors.Errorf("delete previous name: %w", err)
+		}
+		return nil
+	}, nil)
 	if err != nil {
 		// The query protects against updating deleted workspaces and
 		// the existence of the workspace is checked in the request,
@@ -680,6 +925,163 @@ func (api *API) putWorkspaceAutostart(rw http.ResponseWriter, r *http.Request) {
 	rw.WriteHeader(http.StatusNoContent)
 }
 
+// workspaceExternalMetadataNamespaceRegex matches namespaces such as "cmdb" or
+// "com.example.tickets".
+var workspaceExternalMetadataNamespaceRegex = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*$`)
+
+const (
+	workspaceExternalMetadataNamespaceMaxLength = 64
+	// workspaceExternalMetadataMaxSize bounds the metadata stored per
+	// namespace, since it is returned with every workspace.
+	workspaceExternalMetadataMaxSize = 64 << 10
+)
+
+// @Summary Put workspace external metadata by ID
+// @ID put-workspace-external-metadata-by-id
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.PutWorkspaceExternalMetadataRequest true "External metadata request"
+// @Success 204
+// @Router /workspaces/{workspace}/external-metadata [put]
+func (api *API) putWorkspaceExternalMetadata(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	var req codersdk.PutWorkspaceExternalMetadataRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var validations []codersdk.ValidationError
+	if len(req.Namespace) > workspaceExternalMetadataNamespaceMaxLength || !workspaceExternalMetadataNamespaceRegex.MatchString(req.Namespace) {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "namespace",
+			Detail: fmt.Sprintf("Must be at most %d lowercase alphanumeric characters, optionally separated by \".\", \"_\" or \"-\".", workspaceExternalMetadataNamespaceMaxLength),
+		})
+	}
+	visibility := database.WorkspaceExternalMetadataVisibilityPublic
+	if req.Visibility != "" {
+		visibility = database.WorkspaceExternalMetadataVisibility(req.Visibility)
+	}
+	if !visibility.Valid() {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "visibility",
+			Detail: fmt.Sprintf("Must be one of %q.", database.AllWorkspaceExternalMetadataVisibilityValues()),
+		})
+	}
+	if len(req.Data) > workspaceExternalMetadataMaxSize {
+		validations = append(validations, codersdk.ValidationError{
+			Field:  "data",
+			Detail: fmt.Sprintf("Must be at most %d bytes.", workspaceExternalMetadataMaxSize),
+		})
+	}
+	if len(validations) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid external metadata.",
+			Validations: validations,
+		})
+		return
+	}
+
+	var err error
+	if len(req.Data) == 0 || string(req.Data) == "null" {
+		err = api.Database.DeleteWorkspaceExternalMetadatum(ctx, database.DeleteWorkspaceExternalMetadatumParams{
+			WorkspaceID: workspace.ID,
+			Namespace:   req.Namespace,
+		})
+	} else {
+		_, err = api.Database.UpsertWorkspaceExternalMetadatum(ctx, database.UpsertWorkspaceExternalMetadatumParams{
+			WorkspaceID: workspace.ID,
+			Namespace:   req.Namespace,
+			Visibility:  visibility,
+			Data:        req.Data,
+			UpdatedAt:   database.Now(),
+		})
+	}
+	// The caller can read the workspace, but may not be allowed to change
+	// it or the metadata stored under the namespace.
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace external metadata.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Update workspace automatic updates by ID
+// @ID update-workspace-automatic-updates-by-id
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceAutomaticUpdatesRequest true "Automatic updates request"
+// @Success 204
+// @Router /workspaces/{workspace}/autoupdates [put]
+func (api *API) putWorkspaceAutoupdates(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.Workspace](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+	aReq.Old = workspace
+
+	var req codersdk.UpdateWorkspaceAutomaticUpdatesRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	automaticUpdates := database.AutomaticUpdates(req.AutomaticUpdates)
+	if !automaticUpdates.Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid automatic updates setting.",
+			Validations: []codersdk.ValidationError{{Field: "automatic_updates", Detail: fmt.Sprintf("Must be one of %q.", database.AllAutomaticUpdatesValues())}},
+		})
+		return
+	}
+
+	err := api.Database.UpdateWorkspaceAutomaticUpdates(ctx, database.UpdateWorkspaceAutomaticUpdatesParams{
+		ID:               workspace.ID,
+		AutomaticUpdates: automaticUpdates,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace automatic updates setting.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	newWorkspace := workspace
+	newWorkspace.AutomaticUpdates = automaticUpdates
+	aReq.New = newWorkspace
+
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
 // @Summary Update workspace TTL by ID
 // @ID update-workspace-ttl-by-id
 // @Security CoderSessionToken
@@ -830,6 +1232,8 @@ func (api *API) putWorkspaceLock(rw http.ResponseWriter, r *http.Request) {
 		data.builds[0],
 		data.templates[0],
 		findUser(workspace.OwnerID, data.users),
+		data.externalMetadata,
+		data.previousNames,
 	))
 }
 
@@ -992,6 +1396,8 @@ func (api *API) watchWorkspace(rw http.ResponseWriter, r *http.Request) {
 				data.builds[0],
 				data.templates[0],
 				findUser(workspace.OwnerID, data.users),
+				data.externalMetadata,
+				data.previousNames,
 			),
 		})
 	}
@@ -1040,9 +1446,11 @@ func (api *API) watchWorkspace(rw http.ResponseWriter, r *http.Request) {
 }
 
 type workspaceData struct {
-	templates []database.Template
-	builds    []codersdk.WorkspaceBuild
-	users     []database.User
+	templates        []database.Template
+	builds           []codersdk.WorkspaceBuild
+	users            []database.User
+	externalMetadata []database.GetWorkspaceExternalMetadataByWorkspaceIDsRow
+	previousNames    []database.GetWorkspacePreviousNamesByWorkspaceIDsRow
 }
 
 // workspacesData only returns the data the caller can access. If the caller
@@ -1091,10 +1499,23 @@ func (api *API) workspaceData(ctx context.Context, workspaces []database.Workspa
 		return workspaceData{}, xerrors.Errorf("convert workspace builds: %w", err)
 	}
 
+	// Only the metadata the caller is allowed to see is returned.
+	externalMetadata, err := api.Database.GetWorkspaceExternalMetadataByWorkspaceIDs(ctx, workspaceIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return workspaceData{}, xerrors.Errorf("get workspace external metadata: %w", err)
+	}
+
+	previousNames, err := api.Database.GetWorkspacePreviousNamesByWorkspaceIDs(ctx, workspaceIDs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return workspaceData{}, xerrors.Errorf("get workspace previous names: %w", err)
+	}
+
 	return workspaceData{
-		templates: templates,
-		builds:    apiBuilds,
-		users:     data.users,
+		templates:        templates,
+		builds:           apiBuilds,
+		users:            data.users,
+		externalMetadata: externalMetadata,
+		previousNames:    previousNames,
 	}, nil
 }
 
@@ -1111,6 +1532,14 @@ func convertWorkspaces(workspaces []database.Workspace, data workspaceData) ([]c
 	for _, user := range data.users {
 		userByID[user.ID] = user
 	}
+	externalMetadataByWorkspaceID := map[uuid.UUID][]database.GetWorkspaceExternalMetadataByWorkspaceIDsRow{}
+	for _, metadatum := range data.externalMetadata {
+		externalMetadataByWorkspaceID[metadatum.WorkspaceID] = append(externalMetadataByWorkspaceID[metadatum.WorkspaceID], metadatum)
+	}
+	previousNamesByWorkspaceID := map[uuid.UUID][]database.GetWorkspacePreviousNamesByWorkspaceIDsRow{}
+	for _, previous := range data.previousNames {
+		previousNamesByWorkspaceID[previous.WorkspaceID] = append(previousNamesByWorkspaceID[previous.WorkspaceID], previous)
+	}
 
 	apiWorkspaces := make([]codersdk.Workspace, 0, len(workspaces))
 	for _, workspace := range workspaces {
@@ -1137,6 +1566,8 @@ func convertWorkspaces(workspaces []database.Workspace, data workspaceData) ([]c
 			build,
 			template,
 			&owner,
+			externalMetadataByWorkspaceID[workspace.ID],
+			previousNamesByWorkspaceID[workspace.ID],
 		))
 	}
 	return apiWorkspaces, nil
@@ -1147,6 +1578,8 @@ func convertWorkspace(
 	workspaceBuild codersdk.WorkspaceBuild,
 	template database.Template,
 	owner *database.User,
+	externalMetadata []database.GetWorkspaceExternalMetadataByWorkspaceIDsRow,
+	previousNames []database.GetWorkspacePreviousNamesByWorkspaceIDsRow,
 ) codersdk.Workspace {
 	var autostartSchedule *string
 	if workspace.AutostartSchedule.Valid {
@@ -1163,6 +1596,13 @@ func convertWorkspace(
 		deletedAt = &workspace.DeletingAt.Time
 	}
 
+	outdated := workspaceBuild.TemplateVersionID.String() != template.ActiveVersionID.String()
+	var updateDeadline *time.Time
+	if outdated && template.RequireActiveVersion {
+		deadline := template.ActiveVersionRequiredAt()
+		updateDeadline = &deadline
+	}
+
 	failingAgents := []uuid.UUID{}
 	for _, resource := range workspaceBuild.Resources {
 		for _, agent := range resource.Agents {
@@ -1174,6 +1614,25 @@ func convertWorkspace(
 
 	ttlMillis := convertWorkspaceTTLMillis(workspace.Ttl)
 
+	apiExternalMetadata := make([]codersdk.WorkspaceExternalMetadata, 0, len(externalMetadata))
+	for _, metadatum := range externalMetadata {
+		apiExternalMetadata = append(apiExternalMetadata, codersdk.WorkspaceExternalMetadata{
+			Namespace:  metadatum.Namespace,
+			Visibility: codersdk.WorkspaceExternalMetadataVisibility(metadatum.Visibility),
+			Data:       metadatum.Data,
+			UpdatedAt:  metadatum.UpdatedAt,
+		})
+	}
+
+	apiPreviousNames := make([]codersdk.WorkspacePreviousName, 0, len(previousNames))
+	for _, previous := range previousNames {
+		apiPreviousNames = append(apiPreviousNames, codersdk.WorkspacePreviousName{
+			Name:              previous.Name,
+			RenamedAt:         previous.RenamedAt,
+			RedirectExpiresAt: previous.RedirectExpiresAt,
+		})
+	}
+
 	return codersdk.Workspace{
 		ID:                                   workspace.ID,
 		CreatedAt:                            workspace.CreatedAt,
@@ -1187,17 +1646,21 @@ func convertWorkspace(
 		TemplateIcon:                         template.Icon,
 		TemplateDisplayName:                  template.DisplayName,
 		TemplateAllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
-		Outdated:                             workspaceBuild.TemplateVersionID.String() != template.ActiveVersionID.String(),
+		Outdated:                             outdated,
 		Name:                                 workspace.Name,
 		AutostartSchedule:                    autostartSchedule,
 		TTLMillis:                            ttlMillis,
 		LastUsedAt:                           workspace.LastUsedAt,
+		AutomaticUpdates:                     codersdk.AutomaticUpdates(workspace.AutomaticUpdates),
+		UpdateDeadline:                       updateDeadline,
 		DeletingAt:                           deletedAt,
 		LockedAt:                             lockedAt,
 		Health: codersdk.WorkspaceHealth{
 			Healthy:       len(failingAgents) == 0,
 			FailingAgents: failingAgents,
 		},
+		ExternalMetadata: apiExternalMetadata,
+		PreviousNames:    apiPreviousNames,
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"sync/atomic"
@@ -26,6 +27,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/parameter"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/schedule"
@@ -124,6 +126,26 @@ func TestWorkspace(t *testing.T) {
 		ws, err := client.Workspace(ctx, ws1.ID)
 		require.NoError(t, err)
 		require.Equal(t, want, ws.Name, "workspace name not updated")
+		require.Len(t, ws.PreviousNames, 1)
+		require.Equal(t, ws1.Name, ws.PreviousNames[0].Name)
+		require.True(t, ws.PreviousNames[0].RedirectExpiresAt.After(ws.PreviousNames[0].RenamedAt))
+
+		// The old name keeps resolving to the renamed workspace.
+		ws, err = client.WorkspaceByOwnerAndName(ctx, codersdk.Me, ws1.Name, codersdk.WorkspaceOptions{})
+		require.NoError(t, err)
+		require.Equal(t, ws1.ID, ws.ID)
+		require.Equal(t, want, ws.Name)
+
+		// Renaming back to the old name drops it from the history.
+		err = client.UpdateWorkspace(ctx, ws1.ID, codersdk.UpdateWorkspaceRequest{
+			Name: ws1.Name,
+		})
+		require.NoError(t, err)
+		ws, err = client.Workspace(ctx, ws1.ID)
+		require.NoError(t, err)
+		require.Equal(t, ws1.Name, ws.Name)
+		require.Len(t, ws.PreviousNames, 1)
+		require.Equal(t, want, ws.PreviousNames[0].Name)
 
 		err = client.UpdateWorkspace(ctx, ws1.ID, codersdk.UpdateWorkspaceRequest{
 			Name: ws2.Name,
@@ -489,6 +511,49 @@ func TestPostWorkspacesByOrganization(t *testing.T) {
 		}, testutil.WaitMedium, testutil.IntervalFast)
 	})
 
+	t.Run("CreateWithState", func(t *testing.T) {
+		t.Parallel()
+		client, closer := coderdtest.NewWithProvisionerCloser(t, nil)
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		// Stop the provisioner so the build keeps the state it starts with.
+		require.NoError(t, closer.Close())
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		state := []byte(`{"version":4,"resources":[]}`)
+		workspace, err := client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID:       template.ID,
+			Name:             "imported",
+			ProvisionerState: state,
+		})
+		require.NoError(t, err)
+		got, err := client.WorkspaceBuildState(ctx, workspace.LatestBuild.ID)
+		require.NoError(t, err)
+		require.Equal(t, state, got)
+
+		// Only template managers may provide state.
+		_, err = member.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID:       template.ID,
+			Name:             "member",
+			ProvisionerState: state,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+			TemplateID:       template.ID,
+			Name:             "invalid",
+			ProvisionerState: []byte(`{"version":3}`),
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
 	t.Run("CreateWithDeletedTemplate", func(t *testing.T) {
 		t.Parallel()
 		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
@@ -2063,6 +2128,252 @@ func TestWorkspaceUpdateTTL(t *testing.T) {
 	})
 }
 
+func TestWorkspaceUpdateAutomaticUpdates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		var (
+			auditor   = audit.NewMock()
+			client    = coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, Auditor: auditor})
+			user      = coderdtest.CreateFirstUser(t, client)
+			version   = coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+			_         = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+			template  = coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+			workspace = coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+		require.Equal(t, codersdk.AutomaticUpdatesNever, workspace.AutomaticUpdates)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		err := client.UpdateWorkspaceAutomaticUpdates(ctx, workspace.ID, codersdk.UpdateWorkspaceAutomaticUpdatesRequest{
+			AutomaticUpdates: codersdk.AutomaticUpdatesAlways,
+		})
+		require.NoError(t, err)
+
+		updated, err := client.Workspace(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.AutomaticUpdatesAlways, updated.AutomaticUpdates)
+		require.Eventually(t, func() bool {
+			for _, log := range auditor.AuditLogs() {
+				if log.ResourceID == workspace.ID && log.Action == database.AuditActionWrite {
+					return true
+				}
+			}
+			return false
+		}, testutil.WaitShort, testutil.IntervalFast, "missing audit log")
+
+		// Starting the workspace without an explicit version uses the
+		// active version of the template.
+		newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+		err = client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: newVersion.ID,
+		})
+		require.NoError(t, err)
+
+		build, err := client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionStart,
+		})
+		require.NoError(t, err)
+		require.Equal(t, newVersion.ID, build.TemplateVersionID)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+		var (
+			client    = coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+			user      = coderdtest.CreateFirstUser(t, client)
+			version   = coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+			_         = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+			template  = coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+			workspace = coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+
+		err := client.UpdateWorkspaceAutomaticUpdates(ctx, workspace.ID, codersdk.UpdateWorkspaceAutomaticUpdatesRequest{
+			AutomaticUpdates: "sometimes",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+}
+
+func TestWorkspaceClone(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, Auditor: auditor})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse: echo.ParseComplete,
+			ProvisionPlan: []*proto.Provision_Response{{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Parameters: []*proto.RichParameter{{Name: "region", Type: "string"}},
+					},
+				},
+			}},
+			ProvisionApply: echo.ProvisionComplete,
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		parameters := []codersdk.WorkspaceBuildParameter{{Name: "region", Value: "eu"}}
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+			cwr.RichParameterValues = parameters
+		})
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		// The clone uses the version of the workspace, not the active one.
+		newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+		coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: newVersion.ID,
+		})
+		require.NoError(t, err)
+
+		clone, err := client.CloneWorkspace(ctx, workspace.ID, codersdk.CloneWorkspaceRequest{
+			Name:     "clone",
+			CopyData: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "clone", clone.Name)
+		require.Equal(t, template.ID, clone.TemplateID)
+		require.Equal(t, version.ID, clone.LatestBuild.TemplateVersionID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, clone.LatestBuild.ID)
+		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
+
+		got, err := client.WorkspaceBuildParameters(ctx, build.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, parameters, got)
+		require.Eventually(t, func() bool {
+			for _, log := range auditor.AuditLogs() {
+				if log.ResourceID == clone.ID && log.Action == database.AuditActionCreate {
+					return true
+				}
+			}
+			return false
+		}, testutil.WaitShort, testutil.IntervalFast, "missing audit log")
+
+		_, err = client.CloneWorkspace(ctx, workspace.ID, codersdk.CloneWorkspaceRequest{
+			Name: "clone",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Members can't clone the workspaces of others.
+		_, err := member.CloneWorkspace(ctx, workspace.ID, codersdk.CloneWorkspaceRequest{
+			Name: "clone",
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
+
+func TestWorkspaceExternalMetadata(t *testing.T) {
+	t.Parallel()
+	var (
+		client          = coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user            = coderdtest.CreateFirstUser(t, client)
+		memberClient, _ = coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version         = coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		_               = coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template        = coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace       = coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID)
+		ctx             = testutil.Context(t, testutil.WaitLong)
+		namespaces      = func(workspace codersdk.Workspace) []string {
+			var namespaces []string
+			for _, metadata := range workspace.ExternalMetadata {
+				namespaces = append(namespaces, metadata.Namespace)
+			}
+			return namespaces
+		}
+	)
+	coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	require.Empty(t, workspace.ExternalMetadata)
+
+	// The owner of the workspace can attach public metadata...
+	err := memberClient.PutWorkspaceExternalMetadata(ctx, workspace.ID, codersdk.PutWorkspaceExternalMetadataRequest{
+		Namespace: "tickets",
+		Data:      json.RawMessage(`{"ticket":"OPS-1"}`),
+	})
+	require.NoError(t, err)
+	// ...which admins can see alongside private metadata the owner can't.
+	err = client.PutWorkspaceExternalMetadata(ctx, workspace.ID, codersdk.PutWorkspaceExternalMetadataRequest{
+		Namespace:  "cmdb",
+		Visibility: codersdk.WorkspaceExternalMetadataVisibilityPrivate,
+		Data:       json.RawMessage(`{"asset":"A-1"}`),
+	})
+	require.NoError(t, err)
+
+	got, err := client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"cmdb", "tickets"}, namespaces(got))
+	require.JSONEq(t, `{"asset":"A-1"}`, string(got.ExternalMetadata[0].Data))
+	require.Equal(t, codersdk.WorkspaceExternalMetadataVisibilityPrivate, got.ExternalMetadata[0].Visibility)
+
+	got, err = memberClient.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tickets"}, namespaces(got))
+	require.JSONEq(t, `{"ticket":"OPS-1"}`, string(got.ExternalMetadata[0].Data))
+	res, err := memberClient.Workspaces(ctx, codersdk.WorkspaceFilter{})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 1)
+	require.Equal(t, []string{"tickets"}, namespaces(res.Workspaces[0]))
+
+	// The owner can't overwrite private metadata by making it public.
+	err = memberClient.PutWorkspaceExternalMetadata(ctx, workspace.ID, codersdk.PutWorkspaceExternalMetadataRequest{
+		Namespace: "cmdb",
+		Data:      json.RawMessage(`{}`),
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+	err = memberClient.PutWorkspaceExternalMetadata(ctx, workspace.ID, codersdk.PutWorkspaceExternalMetadataRequest{
+		Namespace: "Not Valid",
+		Data:      json.RawMessage(`{}`),
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// Null data removes the namespace.
+	err = memberClient.PutWorkspaceExternalMetadata(ctx, workspace.ID, codersdk.PutWorkspaceExternalMetadataRequest{
+		Namespace: "tickets",
+		Data:      json.RawMessage(`null`),
+	})
+	require.NoError(t, err)
+	got, err = client.Workspace(ctx, workspace.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"cmdb"}, namespaces(got))
+}
+
 func TestWorkspaceExtend(t *testing.T) {
 	t.Parallel()
 	var (
@@ -2782,6 +3093,191 @@ func TestWorkspaceWithEphemeralRichParameters(t *testing.T) {
 	require.ElementsMatch(t, expectedBuildParameters, workspaceBuildParameters)
 }
 
+func TestWorkspaceWithDependentRichParameters(t *testing.T) {
+	t.Parallel()
+
+	const (
+		regionParameterName = "region"
+		zoneParameterName   = "zone"
+	)
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Provision_Response{
+			{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Parameters: []*proto.RichParameter{
+							{
+								Name:         zoneParameterName,
+								Type:         "string",
+								DefaultValue: "us-east",
+								Mutable:      true,
+								Options: []*proto.RichParameterOption{
+									{Name: "US East", Value: "us-east"},
+									{Name: "EU West", Value: "eu-west"},
+								},
+								Dependencies: []*proto.RichParameterDependency{
+									{Parameter: regionParameterName, Values: []string{"us"}, Options: []string{"us-east"}},
+									{Parameter: regionParameterName, Values: []string{"eu"}, Options: []string{"eu-west"}},
+								},
+							},
+							{
+								Name:         regionParameterName,
+								Type:         "string",
+								DefaultValue: "us",
+								Mutable:      true,
+								Options: []*proto.RichParameterOption{
+									{Name: "US", Value: "us"},
+									{Name: "EU", Value: "eu"},
+									{Name: "Local", Value: "local"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		ProvisionApply: echo.ProvisionComplete,
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	templateRichParameters, err := client.TemplateVersionRichParameters(ctx, version.ID)
+	require.NoError(t, err)
+	require.Len(t, templateRichParameters, 2)
+	require.Equal(t, zoneParameterName, templateRichParameters[1].Name)
+	require.Len(t, templateRichParameters[1].Dependencies, 2)
+
+	// The zone doesn't match the region.
+	_, err = client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateID: template.ID,
+		Name:       "mismatch",
+		RichParameterValues: []codersdk.WorkspaceBuildParameter{
+			{Name: regionParameterName, Value: "eu"},
+			{Name: zoneParameterName, Value: "us-east"},
+		},
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	// The zone doesn't apply to the local region.
+	_, err = client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateID: template.ID,
+		Name:       "local",
+		RichParameterValues: []codersdk.WorkspaceBuildParameter{
+			{Name: regionParameterName, Value: "local"},
+			{Name: zoneParameterName, Value: "eu-west"},
+		},
+	})
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.RichParameterValues = []codersdk.WorkspaceBuildParameter{
+			{Name: regionParameterName, Value: "eu"},
+			{Name: zoneParameterName, Value: "eu-west"},
+		}
+	})
+	workspaceBuild := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	require.Equal(t, codersdk.WorkspaceStatusRunning, workspaceBuild.Status)
+
+	// Switching to the local region resets the zone to its default value.
+	workspaceBuild, err = client.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+		Transition: codersdk.WorkspaceTransitionStart,
+		RichParameterValues: []codersdk.WorkspaceBuildParameter{
+			{Name: regionParameterName, Value: "local"},
+		},
+	})
+	require.NoError(t, err)
+	workspaceBuild = coderdtest.AwaitWorkspaceBuildJob(t, client, workspaceBuild.ID)
+	require.Equal(t, codersdk.WorkspaceStatusRunning, workspaceBuild.Status)
+
+	workspaceBuildParameters, err := client.WorkspaceBuildParameters(ctx, workspaceBuild.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []codersdk.WorkspaceBuildParameter{
+		{Name: regionParameterName, Value: "local"},
+		{Name: zoneParameterName, Value: "us-east"},
+	}, workspaceBuildParameters)
+}
+
+func TestWorkspaceWithDynamicRichParameterOptions(t *testing.T) {
+	t.Parallel()
+
+	const parameterName = "gpu"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		httpapi.Write(r.Context(), rw, http.StatusOK, []codersdk.TemplateVersionParameterOption{
+			{Name: "A100", Value: "a100"},
+			{Name: "H100", Value: "h100"},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+	user := coderdtest.CreateFirstUser(t, client)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+		Parse: echo.ParseComplete,
+		ProvisionPlan: []*proto.Provision_Response{
+			{
+				Type: &proto.Provision_Response_Complete{
+					Complete: &proto.Provision_Complete{
+						Parameters: []*proto.RichParameter{
+							{
+								Name:         parameterName,
+								Type:         "string",
+								DefaultValue: "a100",
+								Mutable:      true,
+								OptionsUrl:   srv.URL,
+							},
+						},
+					},
+				},
+			},
+		},
+		ProvisionApply: echo.ProvisionComplete,
+	})
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+	defer cancel()
+
+	templateRichParameters, err := client.TemplateVersionRichParameters(ctx, version.ID)
+	require.NoError(t, err)
+	require.Len(t, templateRichParameters, 1)
+	require.True(t, templateRichParameters[0].DynamicOptions)
+	require.Equal(t, []codersdk.TemplateVersionParameterOption{
+		{Name: "A100", Value: "a100"},
+		{Name: "H100", Value: "h100"},
+	}, templateRichParameters[0].Options)
+
+	_, err = client.CreateWorkspace(ctx, user.OrganizationID, codersdk.Me, codersdk.CreateWorkspaceRequest{
+		TemplateID: template.ID,
+		Name:       "unavailable",
+		RichParameterValues: []codersdk.WorkspaceBuildParameter{
+			{Name: parameterName, Value: "v100"},
+		},
+	})
+	var apiErr *codersdk.Error
+	require.ErrorAs(t, err, &apiErr)
+	require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+	workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID, func(cwr *codersdk.CreateWorkspaceRequest) {
+		cwr.RichParameterValues = []codersdk.WorkspaceBuildParameter{
+			{Name: parameterName, Value: "h100"},
+		}
+	})
+	workspaceBuild := coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+	require.Equal(t, codersdk.WorkspaceStatusRunning, workspaceBuild.Status)
+}
+
 func TestWorkspaceLock(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,575 @@
+package coderd
+
+import (
+	"context"
+	"crypto/hmac"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+)
+
+const (
+	workspaceWebhookMaxBodySize = 1 << 20
+	// workspaceWebhookCommandTimeout bounds how long the command of an exec
+	// webhook may run.
+	workspaceWebhookCommandTimeout = 30 * time.Minute
+)
+
+// @Summary Get workspace webhooks
+// @ID get-workspace-webhooks
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Success 200 {array} codersdk.WorkspaceWebhook
+// @Router /workspaces/{workspace}/webhooks [get]
+func (api *API) workspaceWebhooks(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	workspace := httpmw.WorkspaceParam(r)
+
+	webhooks, err := api.Database.GetWorkspaceWebhooksByWorkspaceID(ctx, workspace.ID)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace webhooks.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiWebhooks := make([]codersdk.WorkspaceWebhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		apiWebhooks = append(apiWebhooks, api.convertWorkspaceWebhook(webhook))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiWebhooks)
+}
+
+// @Summary Create workspace webhook
+// @ID create-workspace-webhook
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Workspaces
+// @Param workspace path string true "Workspace ID" format(uuid)
+// @Param request body codersdk.CreateWorkspaceWebhookRequest true "Create workspace webhook request"
+// @Success 201 {object} codersdk.CreateWorkspaceWebhookResponse
+// @Router /workspaces/{workspace}/webhooks [post]
+func (api *API) postWorkspaceWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		workspace         = httpmw.WorkspaceParam(r)
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceWebhook](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	var req codersdk.CreateWorkspaceWebhookRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if req.Action == codersdk.WorkspaceWebhookActionExec {
+		if req.Command == "" {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Exec webhooks require a command.",
+				Validations: []codersdk.ValidationError{
+					{Field: "command", Detail: "Required for exec webhooks."},
+				},
+			})
+			return
+		}
+		// The command runs as the creator of the webhook, so they must be
+		// allowed to run commands in the workspace themselves.
+		if !api.Authorize(r, rbac.ActionCreate, workspace.ExecutionRBAC()) {
+			httpapi.Forbidden(rw)
+			return
+		}
+	} else if req.Command != "" || req.AgentName != "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Only exec webhooks accept a command or an agent, not %q webhooks.", req.Action),
+		})
+		return
+	}
+
+	secret, err := cryptorand.String(32)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error generating webhook secret.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	webhook, err := api.Database.InsertWorkspaceWebhook(ctx, database.InsertWorkspaceWebhookParams{
+		ID:          uuid.New(),
+		WorkspaceID: workspace.ID,
+		CreatedBy:   apiKey.UserID,
+		CreatedAt:   database.Now(),
+		Name:        req.Name,
+		Action:      database.WorkspaceWebhookAction(req.Action),
+		AgentName:   req.AgentName,
+		Command:     req.Command,
+		Secret:      secret,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: fmt.Sprintf("A webhook named %q already exists on this workspace.", req.Name),
+			Validations: []codersdk.ValidationError{
+				{Field: "name", Detail: "This value is already in use and should be unique."},
+			},
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating workspace webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = webhook
+
+	httpapi.Write(ctx, rw, http.StatusCreated, codersdk.CreateWorkspaceWebhookResponse{
+		Webhook: api.convertWorkspaceWebhook(webhook),
+		Secret:  secret,
+	})
+}
+
+// @Summary Delete workspace webhook
+// @ID delete-workspace-webhook
+// @Security CoderSessionToken
+// @Tags Workspaces
+// @Param workspacewebhook path string true "Workspace webhook ID" format(uuid)
+// @Success 204
+// @Router /workspacewebhooks/{workspacewebhook} [delete]
+func (api *API) deleteWorkspaceWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceWebhook](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionDelete,
+		})
+	)
+	defer commitAudit()
+
+	webhookID, ok := httpmw.ParseUUIDParam(rw, r, "workspacewebhook")
+	if !ok {
+		return
+	}
+
+	webhook, err := api.Database.GetWorkspaceWebhookByID(ctx, webhookID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = webhook
+
+	err = api.Database.DeleteWorkspaceWebhookByID(ctx, webhook.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deleting workspace webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Trigger workspace webhook
+// @Description Runs the action of the webhook. Requests aren't authenticated with a session
+// @Description token, but must be signed with the webhook secret using the
+// @Description Coder-Webhook-Timestamp and Coder-Webhook-Signature headers. Each request
+// @Description must have a later timestamp than the last accepted one.
+// @ID trigger-workspace-webhook
+// @Produce json
+// @Tags Workspaces
+// @Param workspacewebhook path string true "Workspace webhook ID" format(uuid)
+// @Success 202 {object} codersdk.TriggerWorkspaceWebhookResponse
+// @Router /workspacewebhooks/{workspacewebhook}/trigger [post]
+func (api *API) triggerWorkspaceWebhook(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceWebhook](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	webhookID, ok := httpmw.ParseUUIDParam(rw, r, "workspacewebhook")
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(rw, r.Body, workspaceWebhookMaxBodySize))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to read request body.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	invalidSignature := func() {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Invalid webhook signature.",
+			Detail: fmt.Sprintf("Requests must be signed with the webhook secret using the %q and %q headers.",
+				codersdk.WorkspaceWebhookTimestampHeader, codersdk.WorkspaceWebhookSignatureHeader),
+		})
+	}
+
+	//nolint:gocritic // The request can't be authorized until its signature
+	// is verified against the secret of the webhook.
+	systemCtx := dbauthz.AsSystemRestricted(ctx)
+	webhook, err := api.Database.GetWorkspaceWebhookByID(systemCtx, webhookID)
+	if httpapi.Is404Error(err) {
+		// Unknown webhooks look like invalid signatures, so unsigned
+		// requests can't find out which webhooks exist.
+		invalidSignature()
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	signedAt, ok := verifyWorkspaceWebhookSignature(webhook.Secret, r.Header, body, time.Now())
+	if !ok {
+		invalidSignature()
+		return
+	}
+
+	// Record the request before running the action, so concurrent replays
+	// of it can't both run.
+	triggeredAt := sql.NullTime{Time: signedAt, Valid: true}
+	_, err = api.Database.UpdateWorkspaceWebhookLastTriggeredAt(systemCtx, database.UpdateWorkspaceWebhookLastTriggeredAtParams{
+		ID:              webhook.ID,
+		LastTriggeredAt: triggeredAt,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.Write(ctx, rw, http.StatusUnauthorized, codersdk.Response{
+			Message: "Webhook request was already used.",
+			Detail: fmt.Sprintf("The %q header must be later than the one of the last accepted request.",
+				codersdk.WorkspaceWebhookTimestampHeader),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error updating workspace webhook.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// Only audit signed requests, so anyone who knows the URL can't flood the
+	// audit log.
+	aReq.Old = webhook
+	aReq.UserID = webhook.CreatedBy
+
+	// The action runs with the permissions of the creator of the webhook, so
+	// a webhook can never do more than its creator could themselves.
+	creator, err := api.Database.GetUserByID(systemCtx, webhook.CreatedBy)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhook creator.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if creator.Deleted || creator.Status == database.UserStatusSuspended {
+		httpapi.Write(ctx, rw, http.StatusForbidden, codersdk.Response{
+			Message: "The creator of this webhook is no longer active.",
+		})
+		return
+	}
+	roles, err := api.Database.GetAuthorizationUserRoles(systemCtx, creator.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching webhook creator roles.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	subject := rbac.Subject{
+		ID:     creator.ID.String(),
+		Roles:  rbac.RoleNames(roles.Roles),
+		Groups: roles.Groups,
+		Scope:  rbac.ScopeAll,
+	}.WithCachedASTValue()
+	//nolint:gocritic // Acting as the creator of the webhook.
+	ctx = dbauthz.As(ctx, subject)
+	authorize := func(action rbac.Action, object rbac.Objecter) bool {
+		return api.HTTPAuth.Authorizer.Authorize(ctx, subject, action, object.RBACObject()) == nil
+	}
+
+	workspace, err := api.Database.GetWorkspaceByID(ctx, webhook.WorkspaceID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if workspace.Deleted {
+		httpapi.Write(ctx, rw, http.StatusGone, codersdk.Response{
+			Message: "The workspace of this webhook has been deleted.",
+		})
+		return
+	}
+
+	var resp codersdk.TriggerWorkspaceWebhookResponse
+	if webhook.Action == database.WorkspaceWebhookActionExec {
+		if !api.triggerWorkspaceWebhookExec(ctx, rw, webhook, workspace, authorize) {
+			return
+		}
+	} else {
+		build, ok := api.triggerWorkspaceWebhookBuild(ctx, rw, webhook, workspace, authorize)
+		if !ok {
+			return
+		}
+		resp.WorkspaceBuildID = &build.ID
+	}
+
+	newWebhook := webhook
+	newWebhook.LastTriggeredAt = triggeredAt
+	aReq.New = newWebhook
+
+	httpapi.Write(ctx, rw, http.StatusAccepted, resp)
+}
+
+// triggerWorkspaceWebhookBuild starts the build for a start, stop or update
+// webhook. It writes an error response and returns false on failure.
+func (api *API) triggerWorkspaceWebhookBuild(ctx context.Context, rw http.ResponseWriter, webhook database.WorkspaceWebhook, workspace database.Workspace, authorize func(rbac.Action, rbac.Objecter) bool) (database.WorkspaceBuild, bool) {
+	transition := database.WorkspaceTransitionStart
+	if webhook.Action == database.WorkspaceWebhookActionStop {
+		transition = database.WorkspaceTransitionStop
+	}
+	builder := wsbuilder.New(workspace, transition).
+		Initiator(webhook.CreatedBy).
+		Reason(database.BuildReasonWebhook).
+		DeploymentValues(api.Options.DeploymentValues).
+		ParameterOptions(api.parameterOptions)
+	if webhook.Action == database.WorkspaceWebhookActionUpdate ||
+		(transition == database.WorkspaceTransitionStart && workspace.AutomaticUpdates == database.AutomaticUpdatesAlways) {
+		builder = builder.ActiveVersion()
+	}
+
+	workspaceBuild, provisionerJob, err := builder.Build(ctx, api.Database, authorize)
+	var buildErr wsbuilder.BuildError
+	if xerrors.As(err, &buildErr) {
+		var authErr dbauthz.NotAuthorizedError
+		if xerrors.As(err, &authErr) {
+			buildErr.Status = http.StatusForbidden
+		}
+
+		if buildErr.Status == http.StatusInternalServerError {
+			api.Logger.Error(ctx, "workspace build error", slog.Error(buildErr.Wrapped))
+		}
+
+		httpapi.Write(ctx, rw, buildErr.Status, codersdk.Response{
+			Message: buildErr.Message,
+			Detail:  buildErr.Error(),
+		})
+		return database.WorkspaceBuild{}, false
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Error posting new build",
+			Detail:  err.Error(),
+		})
+		return database.WorkspaceBuild{}, false
+	}
+	err = provisionerdserver.PostJob(api.Pubsub, *provisionerJob)
+	if err != nil {
+		// Client probably doesn't care about this error, so just log it.
+		api.Logger.Error(ctx, "failed to post provisioner job to pubsub", slog.Error(err))
+	}
+	api.publishWorkspaceUpdate(ctx, workspace.ID)
+
+	return *workspaceBuild, true
+}
+
+// triggerWorkspaceWebhookExec starts the command of an exec webhook on its
+// agent. The request doesn't wait for the command to finish. It writes an error
+// response and returns false on failure.
+func (api *API) triggerWorkspaceWebhookExec(ctx context.Context, rw http.ResponseWriter, webhook database.WorkspaceWebhook, workspace database.Workspace, authorize func(rbac.Action, rbac.Objecter) bool) bool {
+	if !authorize(rbac.ActionCreate, workspace.ExecutionRBAC()) {
+		httpapi.Forbidden(rw)
+		return false
+	}
+
+	agents, err := api.Database.GetWorkspaceAgentsInLatestBuildByWorkspaceID(ctx, workspace.ID)
+	if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching workspace agents.",
+			Detail:  err.Error(),
+		})
+		return false
+	}
+	var (
+		agent database.WorkspaceAgent
+		found bool
+	)
+	for _, a := range agents {
+		if webhook.AgentName == "" && len(agents) == 1 || a.Name == webhook.AgentName {
+			agent = a
+			found = true
+			break
+		}
+	}
+	if !found {
+		message := fmt.Sprintf("The workspace has no agent named %q.", webhook.AgentName)
+		if webhook.AgentName == "" {
+			message = fmt.Sprintf("The webhook has no agent and the workspace has %d agents, so the agent to run the command on is ambiguous.", len(agents))
+		}
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: message,
+		})
+		return false
+	}
+	if status := agent.Status(api.AgentInactiveDisconnectTimeout).Status; status != database.WorkspaceAgentStatusConnected {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Agent state is %q, it must be in the %q state.", status, database.WorkspaceAgentStatusConnected),
+		})
+		return false
+	}
+
+	api.WebsocketWaitMutex.Lock()
+	api.WebsocketWaitGroup.Add(1)
+	api.WebsocketWaitMutex.Unlock()
+	go func() {
+		defer api.WebsocketWaitGroup.Done()
+		api.runWorkspaceWebhookCommand(webhook, agent.ID)
+	}()
+	return true
+}
+
+// runWorkspaceWebhookCommand runs the command of an exec webhook on the agent
+// and logs the outcome.
+func (api *API) runWorkspaceWebhookCommand(webhook database.WorkspaceWebhook, agentID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(api.ctx, workspaceWebhookCommandTimeout)
+	defer cancel()
+	logger := api.Logger.With(slog.F("workspace_webhook_id", webhook.ID), slog.F("agent_id", agentID))
+
+	agentConn, release, err := api.agentProvider.AgentConn(ctx, agentID)
+	if err != nil {
+		logger.Warn(ctx, "dial workspace agent for webhook", slog.Error(err))
+		return
+	}
+	defer release()
+	sshClient, err := agentConn.SSHClient(ctx)
+	if err != nil {
+		logger.Warn(ctx, "ssh to workspace agent for webhook", slog.Error(err))
+		return
+	}
+	defer sshClient.Close()
+	session, err := sshClient.NewSession()
+	if err != nil {
+		logger.Warn(ctx, "create ssh session for webhook", slog.Error(err))
+		return
+	}
+	defer session.Close()
+	go func() {
+		// Stop the command when it times out or coderd shuts down.
+		<-ctx.Done()
+		_ = session.Close()
+	}()
+
+	err = session.Run(webhook.Command)
+	if err != nil {
+		logger.Warn(ctx, "workspace webhook command failed", slog.Error(err))
+		return
+	}
+	logger.Debug(ctx, "workspace webhook command succeeded")
+}
+
+// verifyWorkspaceWebhookSignature reports whether the headers carry a valid
+// signature of body that was made recently, and returns the time it was
+// signed at.
+func verifyWorkspaceWebhookSignature(secret string, header http.Header, body []byte, now time.Time) (time.Time, bool) {
+	timestamp, err := strconv.ParseInt(header.Get(codersdk.WorkspaceWebhookTimestampHeader), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	signedAt := time.Unix(timestamp, 0)
+	age := now.Sub(signedAt)
+	if age > codersdk.WorkspaceWebhookTimestampTolerance || age < -codersdk.WorkspaceWebhookTimestampTolerance {
+		return time.Time{}, false
+	}
+	expected := codersdk.SignWorkspaceWebhook(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get(codersdk.WorkspaceWebhookSignatureHeader))) {
+		return time.Time{}, false
+	}
+	return signedAt, true
+}
+
+func (api *API) convertWorkspaceWebhook(webhook database.WorkspaceWebhook) codersdk.WorkspaceWebhook {
+	var lastTriggeredAt *time.Time
+	if webhook.LastTriggeredAt.Valid {
+		lastTriggeredAt = &webhook.LastTriggeredAt.Time
+	}
+	return codersdk.WorkspaceWebhook{
+		ID:              webhook.ID,
+		WorkspaceID:     webhook.WorkspaceID,
+		CreatedBy:       webhook.CreatedBy,
+		CreatedAt:       webhook.CreatedAt,
+		Name:            webhook.Name,
+		Action:          codersdk.WorkspaceWebhookAction(webhook.Action),
+		AgentName:       webhook.AgentName,
+		Command:         webhook.Command,
+		LastTriggeredAt: lastTriggeredAt,
+		URL:             api.AccessURL.JoinPath("/api/v2/workspacewebhooks", webhook.ID.String(), "trigger").String(),
+	}
+}
@@ -0,0 +1,248 @@
+package coderd_test
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog"
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/agent"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
+	"github.com/coder/coder/v2/provisioner/echo"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestWorkspaceWebhooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		otherClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		created, err := client.CreateWorkspaceWebhook(ctx, workspace.ID, codersdk.CreateWorkspaceWebhookRequest{
+			Name:   "ci",
+			Action: codersdk.WorkspaceWebhookActionStart,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, created.Secret)
+		require.Equal(t, "ci", created.Webhook.Name)
+		require.Equal(t, user.UserID, created.Webhook.CreatedBy)
+		require.Equal(t, client.URL.JoinPath("/api/v2/workspacewebhooks", created.Webhook.ID.String(), "trigger").String(), created.Webhook.URL)
+
+		webhooks, err := client.WorkspaceWebhooks(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.WorkspaceWebhook{created.Webhook}, webhooks)
+
+		_, err = client.CreateWorkspaceWebhook(ctx, workspace.ID, codersdk.CreateWorkspaceWebhookRequest{
+			Name:   "ci",
+			Action: codersdk.WorkspaceWebhookActionStop,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		_, err = client.CreateWorkspaceWebhook(ctx, workspace.ID, codersdk.CreateWorkspaceWebhookRequest{
+			Name:   "exec",
+			Action: codersdk.WorkspaceWebhookActionExec,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		_, err = client.CreateWorkspaceWebhook(ctx, workspace.ID, codersdk.CreateWorkspaceWebhookRequest{
+			Name:    "start",
+			Action:  codersdk.WorkspaceWebhookActionStart,
+			Command: "true",
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+
+		// Other users can't see or delete the webhooks of the workspace.
+		_, err = otherClient.WorkspaceWebhooks(ctx, workspace.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+		err = otherClient.DeleteWorkspaceWebhook(ctx, created.Webhook.ID)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		err = client.DeleteWorkspaceWebhook(ctx, created.Webhook.ID)
+		require.NoError(t, err)
+		webhooks, err = client.WorkspaceWebhooks(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Empty(t, webhooks)
+	})
+
+	t.Run("TriggerBuild", func(t *testing.T) {
+		t.Parallel()
+		auditor := audit.NewMock()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true, Auditor: auditor})
+		user := coderdtest.CreateFirstUser(t, client)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		created, err := client.CreateWorkspaceWebhook(ctx, workspace.ID, codersdk.CreateWorkspaceWebhookRequest{
+			Name:   "ci",
+			Action: codersdk.WorkspaceWebhookActionStop,
+		})
+		require.NoError(t, err)
+
+		// Trigger requests don't need a session token.
+		anonClient := codersdk.New(client.URL)
+		body := []byte(`{"pipeline":"green"}`)
+
+		_, err = anonClient.TriggerWorkspaceWebhook(ctx, created.Webhook.ID, "wrong-secret", body)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		// Requests signed too long ago are rejected.
+		stale := time.Now().Add(-codersdk.WorkspaceWebhookTimestampTolerance - time.Minute).Unix()
+		res, err := anonClient.Request(ctx, http.MethodPost, created.Webhook.URL, body, func(r *http.Request) {
+			r.Header.Set(codersdk.WorkspaceWebhookTimestampHeader, strconv.FormatInt(stale, 10))
+			r.Header.Set(codersdk.WorkspaceWebhookSignatureHeader, codersdk.SignWorkspaceWebhook(created.Secret, stale, body))
+		})
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+		// Unknown webhooks can't be told apart from invalid signatures.
+		_, err = anonClient.TriggerWorkspaceWebhook(ctx, uuid.New(), created.Secret, body)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusUnauthorized, apiErr.StatusCode())
+
+		auditor.ResetLogs()
+		resp, err := anonClient.TriggerWorkspaceWebhook(ctx, created.Webhook.ID, created.Secret, body)
+		require.NoError(t, err)
+		require.NotNil(t, resp.WorkspaceBuildID)
+
+		// A captured request can't be replayed.
+		webhooks, err := client.WorkspaceWebhooks(ctx, workspace.ID)
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		require.NotNil(t, webhooks[0].LastTriggeredAt)
+		signedAt := webhooks[0].LastTriggeredAt.Unix()
+		res, err = anonClient.Request(ctx, http.MethodPost, created.Webhook.URL, body, func(r *http.Request) {
+			r.Header.Set(codersdk.WorkspaceWebhookTimestampHeader, strconv.FormatInt(signedAt, 10))
+			r.Header.Set(codersdk.WorkspaceWebhookSignatureHeader, codersdk.SignWorkspaceWebhook(created.Secret, signedAt, body))
+		})
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+		build := coderdtest.AwaitWorkspaceBuildJob(t, client, *resp.WorkspaceBuildID)
+		require.Equal(t, codersdk.WorkspaceTransitionStop, build.Transition)
+		require.Equal(t, codersdk.BuildReasonWebhook, build.Reason)
+		require.Equal(t, user.UserID, build.InitiatorID)
+
+		require.Eventually(t, func() bool {
+			for _, log := range auditor.AuditLogs() {
+				if log.ResourceType == database.ResourceTypeWorkspaceWebhook && log.ResourceID == created.Webhook.ID {
+					return assert.Equal(t, database.AuditActionWrite, log.Action) &&
+						assert.Equal(t, user.UserID, log.UserID)
+				}
+			}
+			return false
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+
+	t.Run("SuspendedCreator", func(t *testing.T) {
+		t.Parallel()
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		created, err := memberClient.CreateWorkspaceWebhook(ctx, workspace.ID, codersdk.CreateWorkspaceWebhookRequest{
+			Name:   "ci",
+			Action: codersdk.WorkspaceWebhookActionStop,
+		})
+		require.NoError(t, err)
+		_, err = client.UpdateUserStatus(ctx, member.ID.String(), codersdk.UserStatusSuspended)
+		require.NoError(t, err)
+
+		_, err = codersdk.New(client.URL).TriggerWorkspaceWebhook(ctx, created.Webhook.ID, created.Secret, nil)
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+
+	t.Run("TriggerExec", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("The command relies on a POSIX shell")
+		}
+		client := coderdtest.New(t, &coderdtest.Options{IncludeProvisionerDaemon: true})
+		user := coderdtest.CreateFirstUser(t, client)
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		agentCloser := agent.New(agent.Options{
+			Client: agentClient,
+			Logger: slogtest.Make(t, nil).Named("agent").Leveled(slog.LevelDebug),
+		})
+		t.Cleanup(func() {
+			_ = agentCloser.Close()
+		})
+		coderdtest.AwaitWorkspaceAgents(t, client, workspace.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		marker := filepath.Join(t.TempDir(), "triggered")
+		created, err := client.CreateWorkspaceWebhook(ctx, workspace.ID, codersdk.CreateWorkspaceWebhookRequest{
+			Name:    "ci",
+			Action:  codersdk.WorkspaceWebhookActionExec,
+			Command: "touch " + marker,
+		})
+		require.NoError(t, err)
+
+		resp, err := codersdk.New(client.URL).TriggerWorkspaceWebhook(ctx, created.Webhook.ID, created.Secret, nil)
+		require.NoError(t, err)
+		require.Nil(t, resp.WorkspaceBuildID)
+
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(marker)
+			return err == nil
+		}, testutil.WaitLong, testutil.IntervalFast)
+	})
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/db2sdk"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/parameteroptions"
 	"github.com/coder/coder/v2/coderd/provisionerdserver"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/tracing"
@@ -43,7 +44,10 @@ type Builder struct {
 
 	richParameterValues []codersdk.WorkspaceBuildParameter
 	initiator           uuid.UUID
+	initiatorTokenName  string
 	reason              database.BuildReason
+	parameterOptions    *parameteroptions.Fetcher
+	cloneSource         *database.Workspace
 
 	// used during build, makes function arguments less verbose
 	ctx   context.Context
@@ -142,6 +146,14 @@ func (b Builder) Initiator(u uuid.UUID) Builder {
 	return b
 }
 
+// InitiatorTokenName records the name of the API token the initiator used to
+// start the build.
+func (b Builder) InitiatorTokenName(name string) Builder {
+	// nolint: revive
+	b.initiatorTokenName = name
+	return b
+}
+
 func (b Builder) Reason(r database.BuildReason) Builder {
 	// nolint: revive
 	b.reason = r
@@ -154,6 +166,23 @@ func (b Builder) RichParameterValues(p []codersdk.WorkspaceBuildParameter) Build
 	return b
 }
 
+// ParameterOptions sets the fetcher used to get the options of parameters served by template-defined endpoints.  If it
+// is not set, new values of these parameters are not checked against their options.
+func (b Builder) ParameterOptions(f *parameteroptions.Fetcher) Builder {
+	// nolint: revive
+	b.parameterOptions = f
+	return b
+}
+
+// CloneSource makes the build run the clone hooks of the template, which copy
+// the data of the source workspace into the workspace. Only the first build of
+// a cloned workspace should set it.
+func (b Builder) CloneSource(source database.Workspace) Builder {
+	// nolint: revive
+	b.cloneSource = &source
+	return b
+}
+
 // SetLastWorkspaceBuildInTx prepopulates the Builder's cache with the last workspace build.  This allows us
 // to avoid a repeated database query when the Builder's caller also needs the workspace build, e.g. auto-start &
 // auto-stop.
@@ -178,6 +207,10 @@ func (b Builder) SetLastWorkspaceBuildJobInTx(job *database.ProvisionerJob) Buil
 	return b
 }
 
+// ErrActiveBuild is wrapped by the BuildError returned when the workspace
+// already has an active build.
+var ErrActiveBuild = xerrors.New("A workspace build is already active.")
+
 type BuildError struct {
 	// Status is a suitable HTTP status code
 	Status  int
@@ -248,7 +281,11 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 			return nil, nil, err
 		}
 	}
-	err := b.checkTemplateVersionMatchesTemplate()
+	err := b.checkActiveVersionRequirement(authFunc)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = b.checkTemplateVersionMatchesTemplate()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -283,10 +320,15 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 	}
 
 	workspaceBuildID := uuid.New()
-	input, err := json.Marshal(provisionerdserver.WorkspaceProvisionJob{
+	jobInput := provisionerdserver.WorkspaceProvisionJob{
 		WorkspaceBuildID: workspaceBuildID,
 		LogLevel:         b.logLevel,
-	})
+	}
+	if b.cloneSource != nil {
+		jobInput.CloneSourceWorkspaceID = uuid.NullUUID{UUID: b.cloneSource.ID, Valid: true}
+		jobInput.CloneSourceWorkspaceName = b.cloneSource.Name
+	}
+	input, err := json.Marshal(jobInput)
 	if err != nil {
 		return nil, nil, BuildError{
 			http.StatusInternalServerError,
@@ -338,17 +380,18 @@ func (b *Builder) buildTx(authFunc func(action rbac.Action, object rbac.Objecter
 	var workspaceBuild database.WorkspaceBuild
 	err = b.store.InTx(func(store database.Store) error {
 		err = store.InsertWorkspaceBuild(b.ctx, database.InsertWorkspaceBuildParams{
-			ID:                workspaceBuildID,
-			CreatedAt:         now,
-			UpdatedAt:         now,
-			WorkspaceID:       b.workspace.ID,
-			TemplateVersionID: templateVersionID,
-			BuildNumber:       buildNum,
-			ProvisionerState:  state,
-			InitiatorID:       b.initiator,
-			Transition:        b.trans,
-			JobID:             provisionerJob.ID,
-			Reason:            b.reason,
+			ID:                 workspaceBuildID,
+			CreatedAt:          now,
+			UpdatedAt:          now,
+			WorkspaceID:        b.workspace.ID,
+			TemplateVersionID:  templateVersionID,
+			BuildNumber:        buildNum,
+			ProvisionerState:   state,
+			InitiatorID:        b.initiator,
+			Transition:         b.trans,
+			JobID:              provisionerJob.ID,
+			Reason:             b.reason,
+			InitiatorTokenName: b.initiatorTokenName,
 		})
 		if err != nil {
 			return BuildError{http.StatusInternalServerError, "insert workspace build", err}
@@ -512,14 +555,33 @@ func (b *Builder) getParameters() (names, values []string, err error) {
 	resolver := codersdk.ParameterResolver{
 		Rich: db2sdk.WorkspaceBuildParameters(lastBuildParameters),
 	}
+	tvps := make([]codersdk.TemplateVersionParameter, 0, len(templateVersionParameters))
 	for _, templateVersionParameter := range templateVersionParameters {
 		tvp, err := db2sdk.TemplateVersionParameter(templateVersionParameter)
 		if err != nil {
 			return nil, nil, BuildError{http.StatusInternalServerError, "failed to convert template version parameter", err}
 		}
+		// Dynamic options are only checked for new values, so previous values
+		// remain usable if the options change.
+		if templateVersionParameter.OptionsURL != "" && b.parameterOptions != nil && b.findNewBuildParameterValue(tvp.Name) != nil {
+			tvp.Options, err = b.parameterOptions.Fetch(b.ctx, templateVersionParameter.OptionsURL)
+			if err != nil {
+				return nil, nil, BuildError{http.StatusInternalServerError, fmt.Sprintf("failed to fetch options of parameter %q", tvp.Name), err}
+			}
+		}
+		tvps = append(tvps, tvp)
+	}
+	// Parameters are resolved after the parameters they depend on, but are
+	// still returned in the order of the template version.
+	sorted, err := codersdk.SortParametersByDependencies(tvps)
+	if err != nil {
+		return nil, nil, BuildError{http.StatusInternalServerError, "failed to sort template version parameters", err}
+	}
+	resolved := make(map[string]string, len(sorted))
+	for _, tvp := range sorted {
 		value, err := resolver.ValidateResolve(
 			tvp,
-			b.findNewBuildParameterValue(templateVersionParameter.Name),
+			b.findNewBuildParameterValue(tvp.Name),
 		)
 		if err != nil {
 			// At this point, we've queried all the data we need from the database,
@@ -527,8 +589,11 @@ func (b *Builder) getParameters() (names, values []string, err error) {
 			// validation, immutable parameters, etc.)
 			return nil, nil, BuildError{http.StatusBadRequest, err.Error(), err}
 		}
-		names = append(names, templateVersionParameter.Name)
-		values = append(values, value)
+		resolved[tvp.Name] = value
+	}
+	for _, tvp := range tvps {
+		names = append(names, tvp.Name)
+		values = append(values, resolved[tvp.Name])
 	}
 	return names, values, nil
 }
@@ -653,13 +718,19 @@ func (b *Builder) authorize(authFunc func(action rbac.Action, object rbac.Object
 	}
 
 	// If custom state, deny request since user could be corrupting or leaking
-	// cloud state.
+	// cloud state. This matches pulling the state of a build.
 	if b.state.explicit != nil || b.state.orphan {
-		if !authFunc(rbac.ActionUpdate, template.RBACObject()) {
+		if !authFunc(rbac.ActionPushVersion, template.RBACObject()) {
 			return BuildError{http.StatusForbidden, "Only template managers may provide custom state", xerrors.New("Only template managers may provide custom state")}
 		}
 	}
 
+	// Copying the data of a workspace gives access to it, like connecting to
+	// it does.
+	if b.cloneSource != nil && !authFunc(rbac.ActionCreate, b.cloneSource.ExecutionRBAC()) {
+		return BuildError{http.StatusForbidden, "Only users who can connect to a workspace may copy its data", xerrors.New("Only users who can connect to a workspace may copy its data")}
+	}
+
 	if b.logLevel != "" && !authFunc(rbac.ActionRead, rbac.ResourceDeploymentValues) {
 		return BuildError{
 			http.StatusBadRequest,
@@ -678,6 +749,36 @@ func (b *Builder) authorize(authFunc func(action rbac.Action, object rbac.Object
 	return nil
 }
 
+// checkActiveVersionRequirement enforces templates that require outdated
+// workspaces to be started with the active version once the grace period has
+// passed. Builds that don't ask for a specific version are moved to the active
+// version, while builds asking for another version are rejected. Users who can
+// push versions of the template are exempt so they can still test them.
+func (b *Builder) checkActiveVersionRequirement(authFunc func(action rbac.Action, object rbac.Objecter) bool) error {
+	if b.trans != database.WorkspaceTransitionStart {
+		return nil
+	}
+	template, err := b.getTemplate()
+	if err != nil {
+		return BuildError{http.StatusInternalServerError, "failed to fetch template", err}
+	}
+	if !template.ActiveVersionRequired(database.Now()) {
+		return nil
+	}
+	if authFunc != nil && authFunc(rbac.ActionPushVersion, template.RBACObject()) {
+		return nil
+	}
+	if b.version.specific != nil {
+		if *b.version.specific != template.ActiveVersionID {
+			msg := "The template requires workspaces to be started with its active version."
+			return BuildError{http.StatusForbidden, msg, xerrors.New(msg)}
+		}
+		return nil
+	}
+	b.version = versionTarget{active: true}
+	return nil
+}
+
 func (b *Builder) checkTemplateVersionMatchesTemplate() error {
 	template, err := b.getTemplate()
 	if err != nil {
@@ -752,11 +853,10 @@ func (b *Builder) checkRunningBuild() error {
 		return BuildError{http.StatusInternalServerError, "failed to fetch prior build", err}
 	}
 	if db2sdk.ProvisionerJobStatus(*job).Active() {
-		msg := "A workspace build is already active."
 		return BuildError{
 			http.StatusConflict,
-			msg,
-			xerrors.New(msg),
+			ErrActiveBuild.Error(),
+			ErrActiveBuild,
 		}
 	}
 	return nil
@@ -114,6 +114,7 @@ func TestBuilder_Initiator(t *testing.T) {
 		withInTx,
 		expectBuild(func(bld database.InsertWorkspaceBuildParams) {
 			asrt.Equal(otherUserID, bld.InitiatorID)
+			asrt.Equal("ci", bld.InitiatorTokenName)
 		}),
 		expectBuildParameters(func(params database.InsertWorkspaceBuildParametersParams) {
 		}),
@@ -121,7 +122,7 @@ func TestBuilder_Initiator(t *testing.T) {
 	)
 
 	ws := database.Workspace{ID: workspaceID, TemplateID: templateID, OwnerID: userID}
-	uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).Initiator(otherUserID)
+	uut := wsbuilder.New(ws, database.WorkspaceTransitionStart).Initiator(otherUserID).InitiatorTokenName("ci")
 	_, _, err := uut.Build(ctx, mDB, nil)
 	req.NoError(err)
 }
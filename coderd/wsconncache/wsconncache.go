@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/atomic"
 	"golang.org/x/sync/singleflight"
 	"golang.org/x/xerrors"
@@ -70,16 +72,85 @@ func (a *AgentProvider) Close() error {
 // out because it creates a unique Tailnet for each agent.
 // See: https://github.com/coder/coder/issues/8218
 func New(dialer Dialer, inactiveTimeout time.Duration) *Cache {
-	if inactiveTimeout == 0 {
-		inactiveTimeout = 5 * time.Minute
+	return NewWithOptions(dialer, Options{InactiveTimeout: inactiveTimeout})
+}
+
+type Options struct {
+	// InactiveTimeout is how long connections are kept after they were last
+	// released. Defaults to 5 minutes.
+	InactiveTimeout time.Duration
+	// MaxSize is the maximum number of cached connections. The least recently
+	// used connections that aren't in use are evicted when it's exceeded.
+	// Zero means unlimited.
+	MaxSize int
+	// MaxAge is how long connections are reused after they were established.
+	// Older connections are replaced when they're acquired, and closed once
+	// they're no longer in use. Zero means unlimited.
+	MaxAge time.Duration
+	// Registerer registers the metrics of the cache.
+	Registerer prometheus.Registerer
+}
+
+// NewWithOptions creates a new workspace connection cache.
+//
+// Deprecated: Use coderd.NewServerTailnet instead.
+func NewWithOptions(dialer Dialer, opts Options) *Cache {
+	if opts.InactiveTimeout == 0 {
+		opts.InactiveTimeout = 5 * time.Minute
 	}
-	return &Cache{
+	if opts.Registerer == nil {
+		opts.Registerer = prometheus.NewRegistry()
+	}
+	c := &Cache{
 		closed:          make(chan struct{}),
 		dialer:          dialer,
-		inactiveTimeout: inactiveTimeout,
+		inactiveTimeout: opts.InactiveTimeout,
+		maxSize:         opts.MaxSize,
+		maxAge:          opts.MaxAge,
 	}
+	factory := promauto.With(opts.Registerer)
+	c.hits = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "agent_conn_cache",
+		Name:      "hits_total",
+		Help:      "The number of agent connections served from the cache.",
+	})
+	c.misses = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "agent_conn_cache",
+		Name:      "misses_total",
+		Help:      "The number of agent connections that weren't cached and were dialed.",
+	})
+	c.dialDuration = factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "coderd",
+		Subsystem: "agent_conn_cache",
+		Name:      "dial_duration_seconds",
+		Help:      "The time it takes to dial agent connections.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	})
+	c.evictions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "coderd",
+		Subsystem: "agent_conn_cache",
+		Name:      "evictions_total",
+		Help:      "The number of agent connections evicted from the cache, by reason.",
+	}, []string{"reason"})
+	factory.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "coderd",
+		Subsystem: "agent_conn_cache",
+		Name:      "connections",
+		Help:      "The number of cached agent connections.",
+	}, func() float64 {
+		return float64(c.Len())
+	})
+	return c
 }
 
+const (
+	evictionReasonInactive = "inactive"
+	evictionReasonMaxSize  = "max_size"
+	evictionReasonMaxAge   = "max_age"
+)
+
 // Dialer creates a new agent connection by ID.
 type Dialer func(id uuid.UUID) (*codersdk.WorkspaceAgentConn, error)
 
@@ -92,6 +163,11 @@ type Conn struct {
 	timeout       *time.Timer
 	timeoutCancel context.CancelFunc
 	transport     *http.Transport
+	createdAt     time.Time
+	// lastUsed and evicted are protected by timeoutMutex. Evicted
+	// connections are closed once they're no longer in use.
+	lastUsed time.Time
+	evicted  bool
 }
 
 func (c *Conn) HTTPTransport() *http.Transport {
@@ -119,6 +195,13 @@ type Cache struct {
 	connMap         sync.Map
 	dialer          Dialer
 	inactiveTimeout time.Duration
+	maxSize         int
+	maxAge          time.Duration
+
+	hits         prometheus.Counter
+	misses       prometheus.Counter
+	dialDuration prometheus.Histogram
+	evictions    *prometheus.CounterVec
 }
 
 // Acquire gets or establishes a connection with the dialer using the ID provided.
@@ -129,8 +212,18 @@ type Cache struct {
 // After the time expires, the connection will be cleared from the cache.
 func (c *Cache) Acquire(id uuid.UUID) (*Conn, func(), error) {
 	rawConn, found := c.connMap.Load(id.String())
+	if found && c.maxAge > 0 {
+		if conn, _ := rawConn.(*Conn); time.Since(conn.createdAt) > c.maxAge {
+			c.evict(id.String(), conn, evictionReasonMaxAge)
+			found = false
+		}
+	}
+	if found {
+		c.hits.Inc()
+	}
 	// If the connection isn't found, establish a new one!
 	if !found {
+		c.misses.Inc()
 		var err error
 		// A singleflight group is used to allow for concurrent requests to the
 		// same identifier to resolve.
@@ -144,7 +237,9 @@ func (c *Cache) Acquire(id uuid.UUID) (*Conn, func(), error) {
 			}
 			c.closeGroup.Add(1)
 			c.closeMutex.Unlock()
+			start := time.Now()
 			agentConn, err := c.dialer(id)
+			c.dialDuration.Observe(time.Since(start).Seconds())
 			if err != nil {
 				c.closeGroup.Done()
 				return nil, xerrors.Errorf("dial: %w", err)
@@ -172,6 +267,7 @@ func (c *Cache) Acquire(id uuid.UUID) (*Conn, func(), error) {
 				WorkspaceAgentConn: agentConn,
 				timeoutCancel:      timeoutCancelFunc,
 				transport:          transport,
+				createdAt:          time.Now(),
 			}
 			go func() {
 				defer c.closeGroup.Done()
@@ -180,7 +276,9 @@ func (c *Cache) Acquire(id uuid.UUID) (*Conn, func(), error) {
 				case <-c.closed:
 				case <-conn.Closed():
 				}
-				c.connMap.Delete(id.String())
+				// A newer connection to the agent may have replaced this
+				// one.
+				c.connMap.CompareAndDelete(id.String(), conn)
 				c.connGroup.Forget(id.String())
 				transport.CloseIdleConnections()
 				_ = conn.Close()
@@ -195,11 +293,21 @@ func (c *Cache) Acquire(id uuid.UUID) (*Conn, func(), error) {
 
 	conn, _ := rawConn.(*Conn)
 	conn.timeoutMutex.Lock()
-	defer conn.timeoutMutex.Unlock()
+	if conn.evicted && conn.locks.Load() == 0 {
+		// The connection was evicted and is closing, use a new one.
+		conn.timeoutMutex.Unlock()
+		return c.Acquire(id)
+	}
 	if conn.timeout != nil {
 		conn.timeout.Stop()
 	}
 	conn.locks.Inc()
+	conn.lastUsed = time.Now()
+	conn.timeoutMutex.Unlock()
+
+	// The acquired connection is in use, so it's never evicted to make room.
+	c.evictLeastRecentlyUsed()
+
 	return conn, func() {
 		conn.timeoutMutex.Lock()
 		defer conn.timeoutMutex.Unlock()
@@ -207,12 +315,84 @@ func (c *Cache) Acquire(id uuid.UUID) (*Conn, func(), error) {
 			conn.timeout.Stop()
 		}
 		conn.locks.Dec()
+		conn.lastUsed = time.Now()
 		if conn.locks.Load() == 0 {
-			conn.timeout = time.AfterFunc(c.inactiveTimeout, conn.timeoutCancel)
+			if conn.evicted {
+				conn.timeoutCancel()
+				return
+			}
+			conn.timeout = time.AfterFunc(c.inactiveTimeout, func() {
+				c.evictions.WithLabelValues(evictionReasonInactive).Inc()
+				conn.timeoutCancel()
+			})
 		}
 	}, nil
 }
 
+// evict removes the connection from the cache, and closes it once it's no
+// longer in use.
+func (c *Cache) evict(key string, conn *Conn, reason string) {
+	if !c.connMap.CompareAndDelete(key, conn) {
+		// The connection was already evicted or closed.
+		return
+	}
+	c.connGroup.Forget(key)
+	c.evictions.WithLabelValues(reason).Inc()
+
+	conn.timeoutMutex.Lock()
+	defer conn.timeoutMutex.Unlock()
+	conn.evicted = true
+	if conn.locks.Load() == 0 {
+		if conn.timeout != nil {
+			conn.timeout.Stop()
+		}
+		conn.timeoutCancel()
+	}
+}
+
+// evictLeastRecentlyUsed evicts the least recently used connections that
+// aren't in use until the cache holds at most maxSize connections.
+func (c *Cache) evictLeastRecentlyUsed() {
+	if c.maxSize <= 0 {
+		return
+	}
+	for c.Len() > c.maxSize {
+		var (
+			oldestKey  string
+			oldestConn *Conn
+			oldestUsed time.Time
+		)
+		c.connMap.Range(func(key, value interface{}) bool {
+			conn, _ := value.(*Conn)
+			conn.timeoutMutex.Lock()
+			idle := conn.locks.Load() == 0
+			lastUsed := conn.lastUsed
+			conn.timeoutMutex.Unlock()
+			if idle && (oldestConn == nil || lastUsed.Before(oldestUsed)) {
+				oldestKey, _ = key.(string)
+				oldestConn = conn
+				oldestUsed = lastUsed
+			}
+			return true
+		})
+		if oldestConn == nil {
+			// Every connection is in use.
+			return
+		}
+		c.evict(oldestKey, oldestConn, evictionReasonMaxSize)
+	}
+}
+
+// Len returns the number of cached agent connections.
+func (c *Cache) Len() int {
+	n := 0
+	c.connMap.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
 func (c *Cache) Close() error {
 	c.closeMutex.Lock()
 	defer c.closeMutex.Unlock()
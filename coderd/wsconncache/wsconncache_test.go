@@ -86,6 +86,46 @@ func TestCache(t *testing.T) {
 		release()
 		<-conn.Closed()
 	})
+	t.Run("MaxSize", func(t *testing.T) {
+		t.Parallel()
+		cache := wsconncache.NewWithOptions(func(id uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
+			return setupAgent(t, agentsdk.Manifest{}, 0), nil
+		}, wsconncache.Options{MaxSize: 1})
+		defer func() {
+			_ = cache.Close()
+		}()
+		first, release, err := cache.Acquire(uuid.New())
+		require.NoError(t, err)
+		release()
+		_, release, err = cache.Acquire(uuid.New())
+		require.NoError(t, err)
+		defer release()
+		// The least recently used connection is closed to make room.
+		<-first.Closed()
+		require.Equal(t, 1, cache.Len())
+	})
+	t.Run("MaxAge", func(t *testing.T) {
+		t.Parallel()
+		called := atomic.NewInt32(0)
+		cache := wsconncache.NewWithOptions(func(id uuid.UUID) (*codersdk.WorkspaceAgentConn, error) {
+			called.Add(1)
+			return setupAgent(t, agentsdk.Manifest{}, 0), nil
+		}, wsconncache.Options{MaxAge: time.Millisecond})
+		defer func() {
+			_ = cache.Close()
+		}()
+		first, releaseFirst, err := cache.Acquire(uuid.Nil)
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond)
+		second, releaseSecond, err := cache.Acquire(uuid.Nil)
+		require.NoError(t, err)
+		defer releaseSecond()
+		require.False(t, first == second)
+		require.Equal(t, int32(2), called.Load())
+		// The stale connection is closed once it's released.
+		releaseFirst()
+		<-first.Closed()
+	})
 	t.Run("HTTPTransport", func(t *testing.T) {
 		t.Parallel()
 		random, err := net.Listen("tcp", "127.0.0.1:0")
@@ -281,3 +321,11 @@ func (*client) PatchLogs(_ context.Context, _ agentsdk.PatchLogs) error {
 func (*client) GetServiceBanner(_ context.Context) (codersdk.ServiceBannerConfig, error) {
 	return codersdk.ServiceBannerConfig{}, nil
 }
+
+func (*client) BinaryChecksum(_ context.Context, _, _ string) (string, error) {
+	return "", nil
+}
+
+func (*client) RotateAuthToken(_ context.Context) (agentsdk.RotateAuthTokenResponse, error) {
+	return agentsdk.RotateAuthTokenResponse{}, nil
+}
@@ -10,6 +10,8 @@ import (
 	"net/http/cookiejar"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"cloud.google.com/go/compute/metadata"
@@ -35,6 +37,11 @@ func New(serverURL *url.URL) *Client {
 // scoped to a workspace agent.
 type Client struct {
 	SDK *codersdk.Client
+
+	// keepaliveInterval and disconnectTimeout are learned from the manifest
+	// and tune the pings of the websockets to coderd.
+	keepaliveInterval atomic.Int64
+	disconnectTimeout atomic.Int64
 }
 
 func (c *Client) SetSessionToken(token string) {
@@ -62,6 +69,22 @@ func (c *Client) GitSSHKey(ctx context.Context) (GitSSHKey, error) {
 	return gitSSHKey, json.NewDecoder(res.Body).Decode(&gitSSHKey)
 }
 
+// FeatureFlags returns the feature flags evaluated for the agent.
+func (c *Client) FeatureFlags(ctx context.Context) (codersdk.FeatureFlags, error) {
+	res, err := c.SDK.Request(ctx, http.MethodGet, "/api/v2/workspaceagents/me/feature-flags", nil)
+	if err != nil {
+		return codersdk.FeatureFlags{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return codersdk.FeatureFlags{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var flags codersdk.FeatureFlags
+	return flags, json.NewDecoder(res.Body).Decode(&flags)
+}
+
 // In the future, we may want to support sending back multiple values for
 // performance.
 type PostMetadataRequest = codersdk.WorkspaceAgentMetadataResult
@@ -98,6 +121,34 @@ type Manifest struct {
 	ShutdownScriptTimeout    time.Duration                                `json:"shutdown_script_timeout"`
 	DisableDirectConnections bool                                         `json:"disable_direct_connections"`
 	Metadata                 []codersdk.WorkspaceAgentMetadataDescription `json:"metadata"`
+	// RequireBinaryVerification stops the agent from running the startup
+	// script if its binary doesn't match the checksum served by the
+	// deployment.
+	RequireBinaryVerification bool `json:"require_binary_verification"`
+	// DNSSearchDomains are prepended to the search list of the resolver
+	// configuration of the agent, and DNSNameservers replace its
+	// nameservers.
+	DNSSearchDomains []string `json:"dns_search_domains,omitempty"`
+	DNSNameservers   []string `json:"dns_nameservers,omitempty"`
+	// KeepaliveInterval is how often the agent pings its websockets to
+	// coderd, and DisconnectTimeout how long it waits for a pong before it
+	// reconnects. Zero values keep the defaults.
+	KeepaliveInterval time.Duration `json:"keepalive_interval,omitempty"`
+	DisconnectTimeout time.Duration `json:"disconnect_timeout,omitempty"`
+	// SSHHostKey is the PEM encoded private host key of the SSH server. It
+	// is persisted for the workspace and agent name, so it doesn't change
+	// when the workspace is rebuilt.
+	SSHHostKey string `json:"ssh_host_key,omitempty"`
+	// SSHHostCertificate is the certificate of the host key signed by the
+	// SSH certificate authority of the deployment, in the authorized_keys
+	// format.
+	SSHHostCertificate string `json:"ssh_host_certificate,omitempty"`
+	// AuthTokenRotationInterval is how often the agent replaces its auth
+	// token with RotateAuthToken. Rotation is disabled if zero.
+	AuthTokenRotationInterval time.Duration `json:"auth_token_rotation_interval,omitempty"`
+	// PortPolicies restrict the ports that the agent lists and forwards. A
+	// port must be allowed by all of them.
+	PortPolicies []codersdk.PortPolicy `json:"port_policies,omitempty"`
 }
 
 // Manifest fetches manifest for the currently authenticated workspace agent.
@@ -119,6 +170,8 @@ func (c *Client) Manifest(ctx context.Context) (Manifest, error) {
 	if err != nil {
 		return Manifest{}, err
 	}
+	c.keepaliveInterval.Store(int64(agentMeta.KeepaliveInterval))
+	c.disconnectTimeout.Store(int64(agentMeta.DisconnectTimeout))
 	return agentMeta, nil
 }
 
@@ -193,7 +246,7 @@ func (c *Client) DERPMapUpdates(ctx context.Context) (<-chan DERPMapUpdate, io.C
 
 	ctx, cancelFunc := context.WithCancel(ctx)
 	ctx, wsNetConn := websocketNetConn(ctx, conn, websocket.MessageBinary)
-	pingClosed := pingWebSocket(ctx, c.SDK.Logger(), conn, "derp map")
+	pingClosed := c.pingWebSocket(ctx, conn, "derp map")
 
 	var (
 		updates       = make(chan DERPMapUpdate)
@@ -279,7 +332,7 @@ func (c *Client) Listen(ctx context.Context) (net.Conn, error) {
 
 	ctx, cancelFunc := context.WithCancel(ctx)
 	ctx, wsNetConn := websocketNetConn(ctx, conn, websocket.MessageBinary)
-	pingClosed := pingWebSocket(ctx, c.SDK.Logger(), conn, "coordinate")
+	pingClosed := c.pingWebSocket(ctx, conn, "coordinate")
 
 	return &closeNetConn{
 		Conn: wsNetConn,
@@ -466,9 +519,14 @@ func (c *Client) ReportStats(ctx context.Context, log slog.Logger, statsChan <-c
 	ctx, cancel := context.WithCancel(ctx)
 	exited := make(chan struct{})
 
+	// clockOffset is measured with every report, and sent with the next
+	// one.
+	var clockOffset time.Duration
 	postStat := func(stat *Stats) {
 		var nextInterval time.Duration
 		for r := retry.New(100*time.Millisecond, time.Minute); r.Wait(ctx); {
+			stat.ClockOffsetMS = clockOffset.Milliseconds()
+			sent := time.Now()
 			resp, err := c.PostStats(ctx, stat)
 			if err != nil {
 				if !xerrors.Is(err, context.Canceled) {
@@ -476,6 +534,9 @@ func (c *Client) ReportStats(ctx context.Context, log slog.Logger, statsChan <-c
 				}
 				continue
 			}
+			if !resp.ServerTime.IsZero() {
+				clockOffset = ClockOffset(sent, time.Now(), resp.ServerTime)
+			}
 
 			nextInterval = resp.ReportInterval
 			break
@@ -545,8 +606,47 @@ type Stats struct {
 	// that are normal, non-tagged SSH sessions.
 	SessionCountSSH int64 `json:"session_count_ssh"`
 
+	// ConnectionBandwidth attributes the transferred bytes to the type of
+	// connection and the peer on the other end.
+	ConnectionBandwidth []ConnectionBandwidth `json:"connection_bandwidth,omitempty"`
+
 	// Metrics collected by the agent
 	Metrics []AgentMetric `json:"metrics"`
+
+	// ClockOffsetMS is the offset of the agent clock from the clock of
+	// coderd in milliseconds, as measured by the previous report. Positive
+	// values mean the agent clock is ahead.
+	ClockOffsetMS int64 `json:"clock_offset_ms"`
+
+	// Activity is the last time each kind of user activity was observed
+	// since the previous report, in the agent clock. Kinds without activity
+	// are omitted.
+	Activity map[codersdk.AgentActivitySource]time.Time `json:"activity,omitempty"`
+}
+
+// ConnectionType is the kind of service a connection to the agent is for.
+type ConnectionType string
+
+const (
+	ConnectionTypeSSH             ConnectionType = "ssh"
+	ConnectionTypeReconnectingPTY ConnectionType = "reconnecting_pty"
+	ConnectionTypeSpeedtest       ConnectionType = "speedtest"
+	// ConnectionTypePortForward is used for workspace apps and forwarded
+	// ports.
+	ConnectionTypePortForward ConnectionType = "port_forward"
+)
+
+// ConnectionBandwidth is the traffic of connections of the same type from a
+// single peer since the previous report.
+type ConnectionBandwidth struct {
+	Type ConnectionType `json:"type" enums:"ssh,reconnecting_pty,speedtest,port_forward"`
+	// Peer is the tailnet IP address of the remote end of the connections.
+	Peer string `json:"peer"`
+	// Port is the agent port the connections were made to. It's only set
+	// for port forwarded connections.
+	Port    uint16 `json:"port,omitempty"`
+	RxBytes int64  `json:"rx_bytes"`
+	TxBytes int64  `json:"tx_bytes"`
 }
 
 type AgentMetricType string
@@ -572,6 +672,18 @@ type StatsResponse struct {
 	// ReportInterval is the duration after which the agent should send stats
 	// again.
 	ReportInterval time.Duration `json:"report_interval"`
+	// ServerTime is the time the report was received, which agents use to
+	// measure the offset of their clock.
+	ServerTime time.Time `json:"server_time" format:"date-time"`
+}
+
+// ClockOffset estimates the offset of the local clock from the clock of the
+// server, given the local times a request was sent and its response received,
+// and the server time in the response. Like NTP, it assumes the server time
+// was taken halfway through the round trip.
+func ClockOffset(sent, received, serverTime time.Time) time.Duration {
+	midpoint := sent.Add(received.Sub(sent) / 2)
+	return midpoint.Sub(serverTime)
 }
 
 func (c *Client) PostStats(ctx context.Context, stats *Stats) (StatsResponse, error) {
@@ -615,6 +727,9 @@ type PostStartupRequest struct {
 	Version           string                    `json:"version"`
 	ExpandedDirectory string                    `json:"expanded_directory"`
 	Subsystems        []codersdk.AgentSubsystem `json:"subsystems"`
+	// HostFacts describe the host the agent runs on. The keys are the
+	// codersdk.AgentHostFact constants.
+	HostFacts map[string]string `json:"host_facts,omitempty"`
 }
 
 func (c *Client) PostStartup(ctx context.Context, req PostStartupRequest) error {
@@ -629,6 +744,35 @@ func (c *Client) PostStartup(ctx context.Context, req PostStartupRequest) error
 	return nil
 }
 
+type RotateAuthTokenResponse struct {
+	AuthToken uuid.UUID `json:"auth_token" format:"uuid"`
+	// PreviousAuthTokenExpiresAt is when the token used for the request
+	// stops being accepted.
+	PreviousAuthTokenExpiresAt time.Time `json:"previous_auth_token_expires_at" format:"date-time"`
+}
+
+// RotateAuthToken replaces the auth token of the agent and authenticates the
+// client with the new one. Callers must persist the token if the agent should
+// be able to authenticate after the previous one expired.
+func (c *Client) RotateAuthToken(ctx context.Context) (RotateAuthTokenResponse, error) {
+	res, err := c.SDK.Request(ctx, http.MethodPost, "/api/v2/workspaceagents/me/rotate-token", nil)
+	if err != nil {
+		return RotateAuthTokenResponse{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return RotateAuthTokenResponse{}, codersdk.ReadBodyAsError(res)
+	}
+
+	var resp RotateAuthTokenResponse
+	err = json.NewDecoder(res.Body).Decode(&resp)
+	if err != nil {
+		return RotateAuthTokenResponse{}, xerrors.Errorf("decode rotate auth token response: %w", err)
+	}
+	c.SetSessionToken(resp.AuthToken.String())
+	return resp, nil
+}
+
 type Log struct {
 	CreatedAt time.Time                        `json:"created_at"`
 	Output    string                           `json:"output"`
@@ -672,6 +816,29 @@ func (c *Client) GetServiceBanner(ctx context.Context) (codersdk.ServiceBannerCo
 	return cfg.ServiceBanner, json.NewDecoder(res.Body).Decode(&cfg)
 }
 
+// BinaryChecksum returns the SHA256 checksum of the coder binary the
+// deployment serves for the given platform.
+func (c *Client) BinaryChecksum(ctx context.Context, goos, goarch string) (string, error) {
+	res, err := c.SDK.Request(ctx, http.MethodGet, fmt.Sprintf("/bin/coder.sha256?os=%s&arch=%s", goos, goarch), nil)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", codersdk.ReadBodyAsError(res)
+	}
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<10))
+	if err != nil {
+		return "", xerrors.Errorf("read checksum: %w", err)
+	}
+	// The checksum is in the format of sha256sum, followed by the file name.
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 {
+		return "", xerrors.New("empty checksum")
+	}
+	return fields[0], nil
+}
+
 type GitAuthResponse struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
@@ -766,14 +933,23 @@ func (c *closeNetConn) Close() error {
 	return c.Conn.Close()
 }
 
-func pingWebSocket(ctx context.Context, logger slog.Logger, conn *websocket.Conn, name string) <-chan struct{} {
+func (c *Client) pingWebSocket(ctx context.Context, conn *websocket.Conn, name string) <-chan struct{} {
 	// Ping once every 30 seconds to ensure that the websocket is alive. If we
 	// don't get a response within 30s we kill the websocket and reconnect.
+	// Deployments can tune both through the manifest.
 	// See: https://github.com/coder/coder/pull/5824
+	tick := 30 * time.Second
+	if interval := time.Duration(c.keepaliveInterval.Load()); interval > 0 {
+		tick = interval
+	}
+	timeout := tick
+	if t := time.Duration(c.disconnectTimeout.Load()); t > 0 {
+		timeout = t
+	}
+	logger := c.SDK.Logger()
 	closed := make(chan struct{})
 	go func() {
 		defer close(closed)
-		tick := 30 * time.Second
 		ticker := time.NewTicker(tick)
 		defer ticker.Stop()
 		defer func() {
@@ -784,7 +960,7 @@ func pingWebSocket(ctx context.Context, logger slog.Logger, conn *websocket.Conn
 			case <-ctx.Done():
 				return
 			case start := <-ticker.C:
-				ctx, cancel := context.WithTimeout(ctx, tick)
+				ctx, cancel := context.WithTimeout(ctx, timeout)
 
 				err := conn.Ping(ctx)
 				if err != nil {
@@ -14,18 +14,24 @@ import (
 type ResourceType string
 
 const (
-	ResourceTypeTemplate        ResourceType = "template"
-	ResourceTypeTemplateVersion ResourceType = "template_version"
-	ResourceTypeUser            ResourceType = "user"
-	ResourceTypeWorkspace       ResourceType = "workspace"
-	ResourceTypeWorkspaceBuild  ResourceType = "workspace_build"
-	ResourceTypeGitSSHKey       ResourceType = "git_ssh_key"
-	ResourceTypeAPIKey          ResourceType = "api_key"
-	ResourceTypeGroup           ResourceType = "group"
-	ResourceTypeLicense         ResourceType = "license"
-	ResourceTypeConvertLogin    ResourceType = "convert_login"
-	ResourceTypeWorkspaceProxy  ResourceType = "workspace_proxy"
-	ResourceTypeOrganization    ResourceType = "organization"
+	ResourceTypeTemplate                 ResourceType = "template"
+	ResourceTypeTemplateVersion          ResourceType = "template_version"
+	ResourceTypeUser                     ResourceType = "user"
+	ResourceTypeWorkspace                ResourceType = "workspace"
+	ResourceTypeWorkspaceBuild           ResourceType = "workspace_build"
+	ResourceTypeGitSSHKey                ResourceType = "git_ssh_key"
+	ResourceTypeAPIKey                   ResourceType = "api_key"
+	ResourceTypeGroup                    ResourceType = "group"
+	ResourceTypeLicense                  ResourceType = "license"
+	ResourceTypeConvertLogin             ResourceType = "convert_login"
+	ResourceTypeWorkspaceProxy           ResourceType = "workspace_proxy"
+	ResourceTypeOrganization             ResourceType = "organization"
+	ResourceTypeWorkspaceWebhook         ResourceType = "workspace_webhook"
+	ResourceTypeWorkspaceApproval        ResourceType = "workspace_approval"
+	ResourceTypeEnvironmentVariable      ResourceType = "environment_variable"
+	ResourceTypeTemplateAccessRequest    ResourceType = "template_access_request"
+	ResourceTypeTemplateVersionPromotion ResourceType = "template_version_promotion"
+	ResourceTypeWorkspaceAgentAuthToken  ResourceType = "workspace_agent_auth_token"
 )
 
 func (r ResourceType) FriendlyString() string {
@@ -56,6 +62,18 @@ func (r ResourceType) FriendlyString() string {
 		return "workspace proxy"
 	case ResourceTypeOrganization:
 		return "organization"
+	case ResourceTypeWorkspaceWebhook:
+		return "workspace webhook"
+	case ResourceTypeWorkspaceApproval:
+		return "workspace approval"
+	case ResourceTypeEnvironmentVariable:
+		return "environment variable"
+	case ResourceTypeTemplateAccessRequest:
+		return "template access request"
+	case ResourceTypeTemplateVersionPromotion:
+		return "template version promotion"
+	case ResourceTypeWorkspaceAgentAuthToken:
+		return "workspace agent token"
 	default:
 		return "unknown"
 	}
@@ -72,6 +90,8 @@ const (
 	AuditActionLogin    AuditAction = "login"
 	AuditActionLogout   AuditAction = "logout"
 	AuditActionRegister AuditAction = "register"
+	AuditActionOpen     AuditAction = "open"
+	AuditActionConnect  AuditAction = "connect"
 )
 
 func (a AuditAction) Friendly() string {
@@ -92,6 +112,10 @@ func (a AuditAction) Friendly() string {
 		return "logged out"
 	case AuditActionRegister:
 		return "registered"
+	case AuditActionOpen:
+		return "opened"
+	case AuditActionConnect:
+		return "connected to"
 	default:
 		return "unknown"
 	}
@@ -0,0 +1,87 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditLogArchivalStatus string
+
+const (
+	AuditLogArchivalStatusRunning   AuditLogArchivalStatus = "running"
+	AuditLogArchivalStatusSucceeded AuditLogArchivalStatus = "succeeded"
+	AuditLogArchivalStatusFailed    AuditLogArchivalStatus = "failed"
+)
+
+// AuditLogArchival is a run that archived audit logs older than the cutoff to
+// the destination and deleted them from the database.
+type AuditLogArchival struct {
+	ID uuid.UUID `json:"id" format:"uuid"`
+	// InitiatorID is the user that triggered the run. It's empty for runs
+	// started by the retention policy.
+	InitiatorID *uuid.UUID             `json:"initiator_id,omitempty" format:"uuid"`
+	Status      AuditLogArchivalStatus `json:"status" enums:"running,succeeded,failed"`
+	Cutoff      time.Time              `json:"cutoff" format:"date-time"`
+	// Destination is empty when audit logs were deleted without being
+	// archived.
+	Destination   string     `json:"destination"`
+	ArchivedCount int64      `json:"archived_count"`
+	Error         string     `json:"error,omitempty"`
+	StartedAt     time.Time  `json:"started_at" format:"date-time"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty" format:"date-time"`
+}
+
+type CreateAuditLogArchivalRequest struct {
+	// Cutoff archives audit logs older than this time. It defaults to the
+	// configured audit log retention.
+	Cutoff time.Time `json:"cutoff,omitempty" format:"date-time"`
+}
+
+// CreateAuditLogArchival starts a run that archives and deletes audit logs.
+// The run continues in the background, poll AuditLogArchival for its result.
+func (c *Client) CreateAuditLogArchival(ctx context.Context, req CreateAuditLogArchivalRequest) (AuditLogArchival, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/audit/archivals", req)
+	if err != nil {
+		return AuditLogArchival{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusAccepted {
+		return AuditLogArchival{}, ReadBodyAsError(res)
+	}
+	var archival AuditLogArchival
+	return archival, json.NewDecoder(res.Body).Decode(&archival)
+}
+
+// AuditLogArchivals returns the most recent audit log archival runs, newest
+// first.
+func (c *Client) AuditLogArchivals(ctx context.Context) ([]AuditLogArchival, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/audit/archivals", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var archivals []AuditLogArchival
+	return archivals, json.NewDecoder(res.Body).Decode(&archivals)
+}
+
+// AuditLogArchival returns an audit log archival run.
+func (c *Client) AuditLogArchival(ctx context.Context, id uuid.UUID) (AuditLogArchival, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/audit/archivals/%s", id), nil)
+	if err != nil {
+		return AuditLogArchival{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return AuditLogArchival{}, ReadBodyAsError(res)
+	}
+	var archival AuditLogArchival
+	return archival, json.NewDecoder(res.Body).Decode(&archival)
+}
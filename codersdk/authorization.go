@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+
+	"github.com/google/uuid"
 )
 
 type AuthorizationResponse map[string]bool
@@ -17,6 +19,8 @@ type AuthorizationRequest struct {
 	// multiple permission checks to be run in a single request.
 	// The key ensures that each permission check has the same key in the
 	// response.
+	// A request can hold up to 1000 checks, which can reference up to 100
+	// distinct objects by resource ID.
 	Checks map[string]AuthorizationCheck `json:"checks"`
 }
 
@@ -32,7 +36,7 @@ type AuthorizationCheck struct {
 	// Omitting the 'OrganizationID' could produce the incorrect value, as
 	// workspaces have both `user` and `organization` owners.
 	Object AuthorizationObject `json:"object"`
-	Action string              `json:"action" enums:"create,read,update,delete"`
+	Action string              `json:"action" enums:"create,read,update,delete,push_version,manage_acl"`
 }
 
 // AuthorizationObject can represent a "set" of objects, such as: all workspaces in an organization, all workspaces owned by me,
@@ -70,3 +74,82 @@ func (c *Client) AuthCheck(ctx context.Context, req AuthorizationRequest) (Autho
 	var resp AuthorizationResponse
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
+
+// EffectivePermissionsRequest selects the user, object and action to explain.
+type EffectivePermissionsRequest struct {
+	// User is the ID or username of the user.
+	User string
+	// Object is a resource type, optionally followed by a colon and the ID of
+	// a single resource. Eg: "template:<template_id>".
+	Object string
+	// Action defaults to "read".
+	Action string
+}
+
+// asRequestOption returns a function that can be used in (*Client).Request.
+// It modifies the request query parameters.
+func (req EffectivePermissionsRequest) asRequestOption() RequestOption {
+	return func(r *http.Request) {
+		q := r.URL.Query()
+		q.Set("user", req.User)
+		q.Set("object", req.Object)
+		if req.Action != "" {
+			q.Set("action", req.Action)
+		}
+		r.URL.RawQuery = q.Encode()
+	}
+}
+
+type EffectivePermissionSource string
+
+const (
+	EffectivePermissionSourceSite     EffectivePermissionSource = "site"
+	EffectivePermissionSourceOrg      EffectivePermissionSource = "org"
+	EffectivePermissionSourceOwner    EffectivePermissionSource = "owner"
+	EffectivePermissionSourceUserACL  EffectivePermissionSource = "user_acl"
+	EffectivePermissionSourceGroupACL EffectivePermissionSource = "group_acl"
+)
+
+// EffectivePermissionEntry is a role permission or an ACL entry that grants
+// or denies the action.
+type EffectivePermissionEntry struct {
+	Source EffectivePermissionSource `json:"source" enums:"site,org,owner,user_acl,group_acl"`
+	// Role is the name of the role for role permissions.
+	Role string `json:"role,omitempty"`
+	// GroupID and GroupName are set for group ACL entries.
+	GroupID   *uuid.UUID `json:"group_id,omitempty" format:"uuid"`
+	GroupName string     `json:"group_name,omitempty"`
+	// Allow is false for permissions that deny the action.
+	Allow bool `json:"allow"`
+}
+
+// EffectivePermissions explains which roles and ACL entries grant or deny an
+// action on an object to a user.
+type EffectivePermissions struct {
+	UserID   uuid.UUID           `json:"user_id" format:"uuid"`
+	Username string              `json:"username"`
+	Object   AuthorizationObject `json:"object"`
+	Action   string              `json:"action" enums:"create,read,update,delete,push_version,manage_acl"`
+	// Allowed is the decision of the authorizer.
+	Allowed bool `json:"allowed"`
+	// OrganizationMember is false if the object belongs to an organization
+	// the user is not a member of. Owner permissions and group ACL entries
+	// don't apply to non-members.
+	OrganizationMember bool                       `json:"organization_member"`
+	Entries            []EffectivePermissionEntry `json:"entries"`
+}
+
+// EffectivePermissions explains why a user can or can't perform an action on
+// an object. It is meant for debugging permissions as an administrator.
+func (c *Client) EffectivePermissions(ctx context.Context, req EffectivePermissionsRequest) (EffectivePermissions, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/authcheck/effective", nil, req.asRequestOption())
+	if err != nil {
+		return EffectivePermissions{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return EffectivePermissions{}, ReadBodyAsError(res)
+	}
+	var resp EffectivePermissions
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -0,0 +1,42 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// BinaryManifest lists the coder binaries that the deployment serves, so
+// bootstrap scripts and tooling can pick the one for a host.
+type BinaryManifest struct {
+	Version  string           `json:"version"`
+	Binaries []BinaryArtifact `json:"binaries"`
+}
+
+type BinaryArtifact struct {
+	// Name is the file name of the binary, e.g. "coder-linux-arm64".
+	Name string `json:"name"`
+	OS   string `json:"os" enums:"linux,darwin,windows"`
+	Arch string `json:"arch" enums:"amd64,arm64,armv7"`
+	// URL is the path the binary is served from, relative to the access URL.
+	URL    string `json:"url"`
+	SHA1   string `json:"sha1"`
+	SHA256 string `json:"sha256"`
+	// SignatureURL is the path of the detached signature of the binary, if
+	// the deployment ships one.
+	SignatureURL string `json:"signature_url,omitempty"`
+}
+
+// BinaryManifest returns the coder binaries served by the deployment.
+func (c *Client) BinaryManifest(ctx context.Context) (BinaryManifest, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/bin/manifest.json", nil)
+	if err != nil {
+		return BinaryManifest{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return BinaryManifest{}, ReadBodyAsError(res)
+	}
+	var manifest BinaryManifest
+	return manifest, json.NewDecoder(res.Body).Decode(&manifest)
+}
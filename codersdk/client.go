@@ -12,8 +12,10 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
@@ -56,6 +58,9 @@ const (
 	// apps.
 	//nolint:gosec
 	SignedAppTokenQueryParameter = "coder_signed_app_token_23db1dde"
+	// ProxyAffinityCookie is the name of the cookie that pins a client's app
+	// traffic to a single workspace proxy replica.
+	ProxyAffinityCookie = "coder_proxy_affinity"
 
 	// BypassRatelimitHeader is the custom header to use to bypass ratelimits.
 	// Only owners can bypass rate limits. This is typically used for scale testing.
@@ -377,6 +382,9 @@ func ReadBodyAsError(res *http.Response) error {
 		}
 		return xerrors.Errorf("decode body: %w", err)
 	}
+	if m.FeatureUnavailable != nil && m.FeatureUnavailable.Guidance != "" {
+		helpMessage = m.FeatureUnavailable.Guidance
+	}
 	if m.Message == "" {
 		if len(resp) > 1024 {
 			resp = append(resp[:1024], []byte("...")...)
@@ -385,11 +393,17 @@ func ReadBodyAsError(res *http.Response) error {
 		m.Detail = string(resp)
 	}
 
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+
 	return &Error{
 		Response:   m,
 		statusCode: res.StatusCode,
 		method:     requestMethod,
 		url:        requestURL,
+		retryAfter: retryAfter,
 		Helper:     helpMessage,
 	}
 }
@@ -402,6 +416,7 @@ type Error struct {
 	statusCode int
 	method     string
 	url        string
+	retryAfter time.Duration
 
 	Helper string
 }
@@ -410,6 +425,12 @@ func (e *Error) StatusCode() int {
 	return e.statusCode
 }
 
+// RetryAfter returns how long the server asked the client to wait before
+// retrying, or zero if it didn't.
+func (e *Error) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
 func (e *Error) Friendly() string {
 	var sb strings.Builder
 	_, _ = fmt.Fprintf(&sb, "%s. %s", strings.TrimSuffix(e.Message, "."), e.Helper)
@@ -470,6 +491,9 @@ type Response struct {
 	// shown on a form field in the UI. These can also be used to add additional
 	// context if there is a set of errors in the primary 'Message'.
 	Validations []ValidationError `json:"validations,omitempty"`
+	// FeatureUnavailable is set when the request needs a feature the
+	// deployment isn't entitled to, or hasn't enabled.
+	FeatureUnavailable *FeatureUnavailable `json:"feature_unavailable,omitempty"`
 }
 
 // ValidationError represents a scoped error to a user input.
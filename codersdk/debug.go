@@ -0,0 +1,99 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// DERPUsageReport is the traffic relayed by the embedded DERP server of the
+// replica that served the request.
+type DERPUsageReport struct {
+	RegionID int `json:"region_id"`
+	// ReceivedBytes and SentBytes are the bytes exchanged with clients since
+	// the replica started.
+	ReceivedBytes int64 `json:"received_bytes"`
+	SentBytes     int64 `json:"sent_bytes"`
+	// Connections is the number of open client connections.
+	Connections int64 `json:"connections"`
+	// Clients is the traffic per client address, largest first.
+	Clients []DERPClientUsage `json:"clients"`
+	// Mesh is the traffic exchanged with other DERP servers of the region.
+	Mesh []DERPMeshUsage `json:"mesh"`
+}
+
+type DERPClientUsage struct {
+	Address       string    `json:"address"`
+	ReceivedBytes int64     `json:"received_bytes"`
+	SentBytes     int64     `json:"sent_bytes"`
+	Connections   int64     `json:"connections"`
+	LastSeenAt    time.Time `json:"last_seen_at" format:"date-time"`
+}
+
+type DERPMeshUsage struct {
+	Address       string `json:"address"`
+	ReceivedBytes int64  `json:"received_bytes"`
+	SentBytes     int64  `json:"sent_bytes"`
+}
+
+// DERPUsage returns the traffic relayed by the DERP server of the replica
+// that serves the request.
+func (c *Client) DERPUsage(ctx context.Context) (DERPUsageReport, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/debug/derp/usage", nil)
+	if err != nil {
+		return DERPUsageReport{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return DERPUsageReport{}, ReadBodyAsError(res)
+	}
+
+	var report DERPUsageReport
+	return report, json.NewDecoder(res.Body).Decode(&report)
+}
+
+// DebugConnectionsReport summarizes the connections of each replica, so
+// operators can confirm load is balanced and detect connection leaks.
+type DebugConnectionsReport struct {
+	// ExpectedReplicas is the number of replicas that were asked for their
+	// connections. Replicas that didn't respond in time are missing from
+	// Replicas.
+	ExpectedReplicas int                  `json:"expected_replicas"`
+	Replicas         []ReplicaConnections `json:"replicas"`
+}
+
+type ReplicaConnections struct {
+	ReplicaID uuid.UUID `json:"replica_id" format:"uuid"`
+	Hostname  string    `json:"hostname"`
+	// Agents and Clients are the workspace agents and clients coordinating
+	// through the replica.
+	Agents  int64 `json:"agents"`
+	Clients int64 `json:"clients"`
+	// ServerTailnetAgents is the number of agents the replica keeps a
+	// connection to for proxying workspace apps.
+	ServerTailnetAgents int64 `json:"server_tailnet_agents"`
+	// ConnectionCacheEntries is the number of cached agent connections of
+	// agents that don't support the server tailnet.
+	ConnectionCacheEntries int64 `json:"connection_cache_entries"`
+}
+
+// DebugConnections returns the connections of every replica.
+func (c *Client) DebugConnections(ctx context.Context) (DebugConnectionsReport, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/debug/connections", nil)
+	if err != nil {
+		return DebugConnectionsReport{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return DebugConnectionsReport{}, ReadBodyAsError(res)
+	}
+
+	var report DebugConnectionsReport
+	return report, json.NewDecoder(res.Body).Decode(&report)
+}
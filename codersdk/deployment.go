@@ -1,9 +1,11 @@
 package codersdk
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -15,6 +17,7 @@ import (
 	"golang.org/x/xerrors"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/google/uuid"
 
 	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/cli/clibase"
@@ -94,16 +97,54 @@ type Feature struct {
 	Enabled     bool        `json:"enabled"`
 	Limit       *int64      `json:"limit,omitempty"`
 	Actual      *int64      `json:"actual,omitempty"`
+	// GracePeriodEndsAt is when the feature stops working if its entitlement
+	// is in the grace period of an expired license.
+	GracePeriodEndsAt *time.Time `json:"grace_period_ends_at,omitempty" format:"date-time"`
 }
 
+// FeatureUnavailable describes why a request to a feature-gated endpoint was
+// rejected.
+type FeatureUnavailable struct {
+	Feature     FeatureName `json:"feature"`
+	Entitlement Entitlement `json:"entitlement"`
+	Enabled     bool        `json:"enabled"`
+	// Guidance explains how to get access to the feature, e.g. by upgrading
+	// the license or changing the deployment configuration.
+	Guidance string `json:"guidance"`
+}
+
+// AsFeatureUnavailable returns details about the missing feature if err was
+// caused by a request to a feature-gated endpoint.
+func AsFeatureUnavailable(err error) (*FeatureUnavailable, bool) {
+	apiErr, ok := AsError(err)
+	if !ok || apiErr.FeatureUnavailable == nil {
+		return nil, false
+	}
+	return apiErr.FeatureUnavailable, true
+}
+
+// EntitlementsSeverity is the severity of the most severe warning or error of
+// the entitlements.
+type EntitlementsSeverity string
+
+const (
+	EntitlementsSeverityNone    EntitlementsSeverity = "none"
+	EntitlementsSeverityWarning EntitlementsSeverity = "warning"
+	EntitlementsSeverityError   EntitlementsSeverity = "error"
+)
+
 type Entitlements struct {
 	Features         map[FeatureName]Feature `json:"features"`
 	Warnings         []string                `json:"warnings"`
 	Errors           []string                `json:"errors"`
+	Severity         EntitlementsSeverity    `json:"severity" enums:"none,warning,error"`
 	HasLicense       bool                    `json:"has_license"`
 	Trial            bool                    `json:"trial"`
 	RequireTelemetry bool                    `json:"require_telemetry"`
-	RefreshedAt      time.Time               `json:"refreshed_at" format:"date-time"`
+	// GracePeriodEndsAt is the earliest time an enabled feature stops working
+	// because the grace period of its expired license ends.
+	GracePeriodEndsAt *time.Time `json:"grace_period_ends_at,omitempty" format:"date-time"`
+	RefreshedAt       time.Time  `json:"refreshed_at" format:"date-time"`
 }
 
 func (c *Client) Entitlements(ctx context.Context) (Entitlements, error) {
@@ -119,6 +160,61 @@ func (c *Client) Entitlements(ctx context.Context) (Entitlements, error) {
 	return ent, json.NewDecoder(res.Body).Decode(&ent)
 }
 
+// EntitlementsHistoryRequest selects the UTC days to return entitlement
+// snapshots for. Both dates are inclusive. A zero EndDate means today, a zero
+// StartDate means 90 days before EndDate.
+type EntitlementsHistoryRequest struct {
+	StartDate time.Time `json:"start_date" format:"date-time"`
+	EndDate   time.Time `json:"end_date" format:"date-time"`
+}
+
+// EntitlementsHistory is the entitlement state of the deployment over time,
+// as snapshotted once a day.
+type EntitlementsHistory struct {
+	StartDate time.Time             `json:"start_date" format:"date-time"`
+	EndDate   time.Time             `json:"end_date" format:"date-time"`
+	Snapshots []EntitlementSnapshot `json:"snapshots"`
+}
+
+type EntitlementSnapshot struct {
+	// Date is the start of the UTC day the snapshot was taken on.
+	Date       time.Time               `json:"date" format:"date-time"`
+	HasLicense bool                    `json:"has_license"`
+	Trial      bool                    `json:"trial"`
+	Features   map[FeatureName]Feature `json:"features"`
+	// UserCount is the number of users that aren't deleted, including
+	// suspended users.
+	UserCount       int64 `json:"user_count"`
+	ActiveUserCount int64 `json:"active_user_count"`
+	// ReplicaCount is the number of primary replicas of the deployment.
+	ReplicaCount int       `json:"replica_count"`
+	Warnings     []string  `json:"warnings"`
+	Errors       []string  `json:"errors"`
+	CreatedAt    time.Time `json:"created_at" format:"date-time"`
+}
+
+// EntitlementsHistory returns the daily snapshots of the entitlements, as
+// evidence of license adherence over time.
+func (c *Client) EntitlementsHistory(ctx context.Context, req EntitlementsHistoryRequest) (EntitlementsHistory, error) {
+	var qp []string
+	if !req.StartDate.IsZero() {
+		qp = append(qp, fmt.Sprintf("start_date=%s", req.StartDate.Format(LicenseUsageDateLayout)))
+	}
+	if !req.EndDate.IsZero() {
+		qp = append(qp, fmt.Sprintf("end_date=%s", req.EndDate.Format(LicenseUsageDateLayout)))
+	}
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/entitlements/history?%s", strings.Join(qp, "&")), nil)
+	if err != nil {
+		return EntitlementsHistory{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return EntitlementsHistory{}, ReadBodyAsError(res)
+	}
+	var history EntitlementsHistory
+	return history, json.NewDecoder(res.Body).Decode(&history)
+}
+
 // DeploymentValues is the central configuration values the coder server.
 type DeploymentValues struct {
 	Verbose             clibase.Bool `json:"verbose,omitempty"`
@@ -152,9 +248,15 @@ type DeploymentValues struct {
 	AgentFallbackTroubleshootingURL clibase.URL                     `json:"agent_fallback_troubleshooting_url,omitempty" typescript:",notnull"`
 	BrowserOnly                     clibase.Bool                    `json:"browser_only,omitempty" typescript:",notnull"`
 	SCIMAPIKey                      clibase.String                  `json:"scim_api_key,omitempty" typescript:",notnull"`
+	SCIMDeprovisionStopWorkspaces   clibase.Bool                    `json:"scim_deprovision_stop_workspaces,omitempty" typescript:",notnull"`
+	SCIMDeprovisionDeleteAfter      clibase.Duration                `json:"scim_deprovision_delete_after,omitempty" typescript:",notnull"`
+	AuditSinks                      AuditSinksConfig                `json:"audit_sinks,omitempty" typescript:",notnull"`
+	AuditArchival                   AuditArchivalConfig             `json:"audit_archival,omitempty" typescript:",notnull"`
+	ExternalAuthz                   ExternalAuthzConfig             `json:"external_authz,omitempty" typescript:",notnull"`
 	Provisioner                     ProvisionerConfig               `json:"provisioner,omitempty" typescript:",notnull"`
 	RateLimit                       RateLimitConfig                 `json:"rate_limit,omitempty" typescript:",notnull"`
 	Experiments                     clibase.StringArray             `json:"experiments,omitempty" typescript:",notnull"`
+	FeatureFlags                    clibase.StringArray             `json:"feature_flags,omitempty" typescript:",notnull"`
 	UpdateCheck                     clibase.Bool                    `json:"update_check,omitempty" typescript:",notnull"`
 	MaxTokenLifetime                clibase.Duration                `json:"max_token_lifetime,omitempty" typescript:",notnull"`
 	Swagger                         SwaggerConfig                   `json:"swagger,omitempty" typescript:",notnull"`
@@ -170,8 +272,63 @@ type DeploymentValues struct {
 	WgtunnelHost                    clibase.String                  `json:"wgtunnel_host,omitempty" typescript:",notnull"`
 	DisableOwnerWorkspaceExec       clibase.Bool                    `json:"disable_owner_workspace_exec,omitempty" typescript:",notnull"`
 	ProxyHealthStatusInterval       clibase.Duration                `json:"proxy_health_status_interval,omitempty" typescript:",notnull"`
+	ProxyGeoRules                   clibase.StringArray             `json:"proxy_geo_rules,omitempty" typescript:",notnull"`
+	ProxyVersionMismatchPolicy      clibase.String                  `json:"proxy_version_mismatch_policy,omitempty" typescript:",notnull"`
 	EnableTerraformDebugMode        clibase.Bool                    `json:"enable_terraform_debug_mode,omitempty" typescript:",notnull"`
 	UserQuietHoursSchedule          UserQuietHoursScheduleConfig    `json:"user_quiet_hours_schedule,omitempty" typescript:",notnull"`
+	StatusRequireAuth               clibase.Bool                    `json:"status_require_auth,omitempty" typescript:",notnull"`
+	EntitlementsRefreshJitter       clibase.Duration                `json:"entitlements_refresh_jitter,omitempty" typescript:",notnull"`
+	LicenseActivationURL            clibase.URL                     `json:"license_activation_url,omitempty" typescript:",notnull"`
+
+	// WorkspaceHooks are called around workspace start and stop builds.
+	WorkspaceHooks clibase.Struct[[]WorkspaceHookConfig] `json:"workspace_hooks,omitempty" typescript:",notnull"`
+	// WorkspaceApprovalWebhook is called when a workspace build awaits
+	// approval. See WorkspaceApprovalWebhookPayload.
+	WorkspaceApprovalWebhook WorkspaceApprovalWebhookConfig `json:"workspace_approval_webhook,omitempty" typescript:",notnull"`
+
+	// AppIdentityHeaders and PortForwardIdentityHeaders are the sharing
+	// levels of declared apps and port-forwarded apps whose requests carry
+	// the identity of the requesting user.
+	AppIdentityHeaders         clibase.StringArray `json:"app_identity_headers,omitempty" typescript:",notnull"`
+	PortForwardIdentityHeaders clibase.StringArray `json:"port_forward_identity_headers,omitempty" typescript:",notnull"`
+	// PortForwardPolicy restricts which ports of workspace agents can be
+	// forwarded. Templates can restrict them further.
+	PortForwardPolicy clibase.Struct[PortPolicy] `json:"port_forward_policy,omitempty" typescript:",notnull"`
+
+	// MaxPTYSessionsPerUser and MaxPTYSessionsPerWorkspace limit concurrent
+	// web terminal sessions. Zero means no limit.
+	MaxPTYSessionsPerUser      clibase.Int64 `json:"max_pty_sessions_per_user,omitempty" typescript:",notnull"`
+	MaxPTYSessionsPerWorkspace clibase.Int64 `json:"max_pty_sessions_per_workspace,omitempty" typescript:",notnull"`
+
+	// AgentDNSSearchDomains and AgentDNSNameservers are written to the
+	// resolver configuration of workspace agents.
+	AgentDNSSearchDomains clibase.StringArray `json:"agent_dns_search_domains,omitempty" typescript:",notnull"`
+	AgentDNSNameservers   clibase.StringArray `json:"agent_dns_nameservers,omitempty" typescript:",notnull"`
+
+	// AgentBinarySigningCertificate is the PEM-encoded certificate whose key
+	// signs the agent binaries. Bootstrap scripts of templates that require
+	// agent binary verification check the detached signatures against it.
+	AgentBinarySigningCertificate clibase.String `json:"agent_binary_signing_certificate,omitempty" typescript:",notnull"`
+
+	// AgentConnectionCache bounds the cached agent connections of workspaces
+	// that don't support the server tailnet.
+	AgentConnectionCache AgentConnectionCacheConfig `json:"agent_connection_cache,omitempty" typescript:",notnull"`
+
+	// AgentAdmissionRate is the number of agents per second each replica
+	// admits when they (re)connect.
+	AgentAdmissionRate clibase.Int64 `json:"agent_admission_rate,omitempty" typescript:",notnull"`
+
+	// AgentTokenRotationInterval is how often workspace agents replace their
+	// auth token. Rotation is disabled if zero.
+	AgentTokenRotationInterval clibase.Duration `json:"agent_token_rotation_interval,omitempty" typescript:",notnull"`
+
+	// TailnetKeepalive tunes how often agent and coordinator connections are
+	// checked and how long they may be silent before they're considered lost.
+	TailnetKeepalive TailnetKeepaliveConfig `json:"tailnet_keepalive,omitempty" typescript:",notnull"`
+
+	// SecretManager configures the secret managers that secret:// references
+	// in template variables are read from.
+	SecretManager SecretManagerConfig `json:"secret_manager,omitempty" typescript:",notnull"`
 
 	Config      clibase.YAMLConfigPath `json:"config,omitempty" typescript:",notnull"`
 	WriteConfig clibase.Bool           `json:"write_config,omitempty" typescript:",notnull"`
@@ -335,6 +492,8 @@ type ProvisionerConfig struct {
 	DaemonPollJitter    clibase.Duration `json:"daemon_poll_jitter" typescript:",notnull"`
 	ForceCancelInterval clibase.Duration `json:"force_cancel_interval" typescript:",notnull"`
 	DaemonPSK           clibase.String   `json:"daemon_psk" typescript:",notnull"`
+	HungJobTimeout      clibase.Duration `json:"hung_job_timeout" typescript:",notnull"`
+	HungJobRequeueLimit clibase.Int64    `json:"hung_job_requeue_limit" typescript:",notnull"`
 }
 
 type RateLimitConfig struct {
@@ -359,6 +518,56 @@ type DangerousConfig struct {
 	AllowAllCors                clibase.Bool `json:"allow_all_cors" typescript:",notnull"`
 }
 
+// AuditSinksConfig configures the external systems audit logs are streamed
+// to, in addition to the database.
+type AuditSinksConfig struct {
+	SyslogAddress     clibase.String `json:"syslog_address" typescript:",notnull"`
+	SplunkHECURL      clibase.URL    `json:"splunk_hec_url" typescript:",notnull"`
+	SplunkHECToken    clibase.String `json:"splunk_hec_token" typescript:",notnull"`
+	KafkaRESTProxyURL clibase.URL    `json:"kafka_rest_proxy_url" typescript:",notnull"`
+	KafkaTopic        clibase.String `json:"kafka_topic" typescript:",notnull"`
+}
+
+// AuditArchivalConfig configures how long audit logs are kept in the database
+// and where they're archived before they're deleted.
+type AuditArchivalConfig struct {
+	Retention   clibase.Duration `json:"retention" typescript:",notnull"`
+	Destination clibase.URL      `json:"destination" typescript:",notnull"`
+}
+
+type AgentConnectionCacheConfig struct {
+	MaxSize clibase.Int64    `json:"max_size" typescript:",notnull"`
+	MaxAge  clibase.Duration `json:"max_age" typescript:",notnull"`
+}
+
+type TailnetKeepaliveConfig struct {
+	AgentInterval                clibase.Duration `json:"agent_interval" typescript:",notnull"`
+	AgentDisconnectTimeout       clibase.Duration `json:"agent_disconnect_timeout" typescript:",notnull"`
+	CoordinatorHeartbeatInterval clibase.Duration `json:"coordinator_heartbeat_interval" typescript:",notnull"`
+	CoordinatorMissedHeartbeats  clibase.Int64    `json:"coordinator_missed_heartbeats" typescript:",notnull"`
+}
+
+type SecretManagerConfig struct {
+	VaultAddress clibase.URL    `json:"vault_address" typescript:",notnull"`
+	VaultToken   clibase.String `json:"vault_token" typescript:",notnull"`
+	AWSRegion    clibase.String `json:"aws_region" typescript:",notnull"`
+	GCP          clibase.Bool   `json:"gcp" typescript:",notnull"`
+}
+
+type WorkspaceApprovalWebhookConfig struct {
+	URL    clibase.URL    `json:"url" typescript:",notnull"`
+	Secret clibase.String `json:"secret" typescript:",notnull"`
+}
+
+// ExternalAuthzConfig configures an Open Policy Agent policy that makes
+// authorization decisions instead of the built-in roles.
+type ExternalAuthzConfig struct {
+	URL       clibase.URL      `json:"url" typescript:",notnull"`
+	PolicyDir clibase.String   `json:"policy_dir" typescript:",notnull"`
+	Fallback  clibase.String   `json:"fallback" typescript:",notnull"`
+	CacheTTL  clibase.Duration `json:"cache_ttl" typescript:",notnull"`
+}
+
 type UserQuietHoursScheduleConfig struct {
 	DefaultSchedule clibase.String `json:"default_schedule" typescript:",notnull"`
 	// TODO: add WindowDuration and the ability to postpone max_deadline by this
@@ -1288,6 +1497,26 @@ when required by your organization's security policy.`,
 			Group:       &deploymentGroupProvisioning,
 			YAML:        "daemonPSK",
 		},
+		{
+			Name:        "Hung Job Timeout",
+			Description: "Time without any logs or heartbeats from a running provisioner job before it is considered hung.",
+			Flag:        "provisioner-hung-job-timeout",
+			Env:         "CODER_PROVISIONER_HUNG_JOB_TIMEOUT",
+			Default:     (5 * time.Minute).String(),
+			Value:       &c.Provisioner.HungJobTimeout,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "hungJobTimeout",
+		},
+		{
+			Name:        "Hung Job Requeue Limit",
+			Description: "Number of times a hung provisioner job is put back in the queue for another provisioner daemon before it is marked as failed. Set to 0 to always mark hung jobs as failed.",
+			Flag:        "provisioner-hung-job-requeue-limit",
+			Env:         "CODER_PROVISIONER_HUNG_JOB_REQUEUE_LIMIT",
+			Default:     "0",
+			Value:       &c.Provisioner.HungJobRequeueLimit,
+			Group:       &deploymentGroupProvisioning,
+			YAML:        "hungJobRequeueLimit",
+		},
 		// RateLimit settings
 		{
 			Name:        "Disable All Rate Limits",
@@ -1408,6 +1637,14 @@ when required by your organization's security policy.`,
 			YAML:        "experiments",
 			Annotations: clibase.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "Feature Flags",
+			Description: "Client and agent feature flags to roll out. Enter a flag name to enable it for everyone, or 'name:percentage' to enable it for a percentage of users and agents. Clients poll these flags, so they can be changed without upgrading clients or agents.",
+			Flag:        "feature-flags",
+			Env:         "CODER_FEATURE_FLAGS",
+			Value:       &c.FeatureFlags,
+			YAML:        "featureFlags",
+		},
 		{
 			Name:        "Update Check",
 			Description: "Periodically check for new releases of Coder and inform the owner. The check is performed once per day.",
@@ -1558,6 +1795,106 @@ when required by your organization's security policy.`,
 			Value:       &c.AgentFallbackTroubleshootingURL,
 			YAML:        "agentFallbackTroubleshootingURL",
 		},
+		{
+			Name:        "Agent DNS Search Domains",
+			Description: "Search domains that workspace agents prepend to the search list of their resolver configuration, so short names of internal hosts resolve in workspaces. Only applied by Linux agents that can write /etc/resolv.conf.",
+			Flag:        "agent-dns-search-domains",
+			Env:         "CODER_AGENT_DNS_SEARCH_DOMAINS",
+			Value:       &c.AgentDNSSearchDomains,
+			YAML:        "agentDNSSearchDomains",
+		},
+		{
+			Name:        "Agent DNS Nameservers",
+			Description: "IP addresses of nameservers that replace the ones in the resolver configuration of workspace agents, for networks where internal hosts are only resolved by internal nameservers. Only applied by Linux agents that can write /etc/resolv.conf.",
+			Flag:        "agent-dns-nameservers",
+			Env:         "CODER_AGENT_DNS_NAMESERVERS",
+			Value:       &c.AgentDNSNameservers,
+			YAML:        "agentDNSNameservers",
+		},
+		{
+			Name:        "Agent Binary Signing Certificate",
+			Description: "PEM-encoded certificate of the RSA key that signs the agent binaries with SHA-256. Workspaces of templates that require agent binary verification refuse to start an agent whose detached signature does not match it.",
+			Flag:        "agent-binary-signing-certificate",
+			Env:         "CODER_AGENT_BINARY_SIGNING_CERTIFICATE",
+			Value:       &c.AgentBinarySigningCertificate,
+			YAML:        "agentBinarySigningCertificate",
+		},
+		{
+			Name:        "Agent Connection Cache Max Size",
+			Description: "The maximum number of cached connections to workspace agents that don't support the server tailnet. The least recently used connections are closed when it's exceeded. Set to 0 for no limit.",
+			Flag:        "agent-connection-cache-max-size",
+			Env:         "CODER_AGENT_CONNECTION_CACHE_MAX_SIZE",
+			Default:     "0",
+			Value:       &c.AgentConnectionCache.MaxSize,
+			YAML:        "agentConnectionCacheMaxSize",
+			Annotations: clibase.Annotations{}.Mark(annotationExternalProxies, "true"),
+		},
+		{
+			Name:        "Agent Connection Cache Max Age",
+			Description: "How long cached connections to workspace agents that don't support the server tailnet are reused before they're replaced. Set to 0 for no limit.",
+			Flag:        "agent-connection-cache-max-age",
+			Env:         "CODER_AGENT_CONNECTION_CACHE_MAX_AGE",
+			Default:     "0",
+			Value:       &c.AgentConnectionCache.MaxAge,
+			YAML:        "agentConnectionCacheMaxAge",
+			Annotations: clibase.Annotations{}.Mark(annotationExternalProxies, "true"),
+		},
+		{
+			Name:        "Agent Admission Rate",
+			Description: "The number of workspace agents per second each replica admits when they connect or reconnect. Agents connecting faster, e.g. after a restart, are told when to retry, so reconnects are spread out instead of overloading the database. Set to 0 for no limit.",
+			Flag:        "agent-admission-rate",
+			Env:         "CODER_AGENT_ADMISSION_RATE",
+			Default:     "100",
+			Value:       &c.AgentAdmissionRate,
+			YAML:        "agentAdmissionRate",
+		},
+		{
+			Name:        "Agent Keepalive Interval",
+			Description: "How often coderd and workspace agents ping the connections between them. Shorter intervals notice lost connections sooner, e.g. of roaming clients, at the cost of more traffic.",
+			Flag:        "agent-keepalive-interval",
+			Env:         "CODER_AGENT_KEEPALIVE_INTERVAL",
+			Default:     (15 * time.Second).String(),
+			Value:       &c.TailnetKeepalive.AgentInterval,
+			YAML:        "agentKeepaliveInterval",
+		},
+		{
+			Name:        "Agent Disconnect Timeout",
+			Description: "How long a connection between coderd and a workspace agent may go without a successful ping before the agent is considered disconnected. Longer timeouts tolerate high latency links, e.g. satellite. Must be longer than the agent keepalive interval.",
+			Flag:        "agent-disconnect-timeout",
+			Env:         "CODER_AGENT_DISCONNECT_TIMEOUT",
+			Default:     (30 * time.Second).String(),
+			Value:       &c.TailnetKeepalive.AgentDisconnectTimeout,
+			YAML:        "agentDisconnectTimeout",
+		},
+		{
+			Name:        "Agent Token Rotation Interval",
+			Description: "How often workspace agents replace their auth token with a new one. The previous token stays valid for an hour, so requests in flight don't fail. Rotation is disabled if set to 0.",
+			Flag:        "agent-token-rotation-interval",
+			Env:         "CODER_AGENT_TOKEN_ROTATION_INTERVAL",
+			Default:     "0",
+			Value:       &c.AgentTokenRotationInterval,
+			YAML:        "agentTokenRotationInterval",
+		},
+		{
+			Name:        "Tailnet Coordinator Heartbeat Interval",
+			Description: "How often the coordinator of each replica sends a heartbeat to the other replicas in high availability deployments.",
+			Flag:        "tailnet-coordinator-heartbeat-interval",
+			Env:         "CODER_TAILNET_COORDINATOR_HEARTBEAT_INTERVAL",
+			Default:     (2 * time.Second).String(),
+			Value:       &c.TailnetKeepalive.CoordinatorHeartbeatInterval,
+			YAML:        "tailnetCoordinatorHeartbeatInterval",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
+		{
+			Name:        "Tailnet Coordinator Missed Heartbeats",
+			Description: "How many heartbeats the coordinator of a replica may miss before the other replicas consider it gone and stop routing connections through it.",
+			Flag:        "tailnet-coordinator-missed-heartbeats",
+			Env:         "CODER_TAILNET_COORDINATOR_MISSED_HEARTBEATS",
+			Default:     "3",
+			Value:       &c.TailnetKeepalive.CoordinatorMissedHeartbeats,
+			YAML:        "tailnetCoordinatorMissedHeartbeats",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+		},
 		{
 			Name:        "Browser Only",
 			Description: "Whether Coder only allows connections to workspaces via the browser.",
@@ -1576,6 +1913,145 @@ when required by your organization's security policy.`,
 			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
 			Value:       &c.SCIMAPIKey,
 		},
+		{
+			Name:        "SCIM Deprovision Stop Workspaces",
+			Description: "Stop the workspaces of users the identity provider deactivates through SCIM. Deactivated users are always suspended.",
+			Flag:        "scim-deprovision-stop-workspaces",
+			Env:         "CODER_SCIM_DEPROVISION_STOP_WORKSPACES",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.SCIMDeprovisionStopWorkspaces,
+			YAML:        "scimDeprovisionStopWorkspaces",
+		},
+		{
+			Name:        "SCIM Deprovision Delete After",
+			Description: "Delete the workspaces of users the identity provider deactivated through SCIM this long ago, then delete the users. Set to 0 to never delete deactivated users.",
+			Flag:        "scim-deprovision-delete-after",
+			Env:         "CODER_SCIM_DEPROVISION_DELETE_AFTER",
+			Default:     "0",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.SCIMDeprovisionDeleteAfter,
+			YAML:        "scimDeprovisionDeleteAfter",
+		},
+		{
+			Name:        "Audit Syslog Address",
+			Description: "Stream audit logs to a syslog server as RFC 5424 messages, e.g. udp://localhost:514 or tcp://syslog.example.com:601.",
+			Flag:        "audit-syslog-address",
+			Env:         "CODER_AUDIT_SYSLOG_ADDRESS",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditSinks.SyslogAddress,
+			YAML:        "auditSyslogAddress",
+		},
+		{
+			Name:        "Audit Splunk HEC URL",
+			Description: "Stream audit logs to a Splunk HTTP Event Collector. If the URL has no path, /services/collector/event is used. Requires --audit-splunk-hec-token.",
+			Flag:        "audit-splunk-hec-url",
+			Env:         "CODER_AUDIT_SPLUNK_HEC_URL",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditSinks.SplunkHECURL,
+			YAML:        "auditSplunkHECURL",
+		},
+		{
+			Name:        "Audit Splunk HEC Token",
+			Description: "The token used to authenticate with the Splunk HTTP Event Collector.",
+			Flag:        "audit-splunk-hec-token",
+			Env:         "CODER_AUDIT_SPLUNK_HEC_TOKEN",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true").Mark(annotationSecretKey, "true"),
+			Value:       &c.AuditSinks.SplunkHECToken,
+		},
+		{
+			Name:        "Audit Kafka REST Proxy URL",
+			Description: "Stream audit logs to Kafka through a Kafka REST Proxy that supports the v2 API. Requires --audit-kafka-topic.",
+			Flag:        "audit-kafka-rest-proxy-url",
+			Env:         "CODER_AUDIT_KAFKA_REST_PROXY_URL",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditSinks.KafkaRESTProxyURL,
+			YAML:        "auditKafkaRESTProxyURL",
+		},
+		{
+			Name:        "Audit Kafka Topic",
+			Description: "The Kafka topic audit logs are produced to.",
+			Flag:        "audit-kafka-topic",
+			Env:         "CODER_AUDIT_KAFKA_TOPIC",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditSinks.KafkaTopic,
+			YAML:        "auditKafkaTopic",
+		},
+		{
+			Name:        "Audit Logs Retention",
+			Description: "Archive and delete audit logs older than this. Set to 0 to keep audit logs forever.",
+			Flag:        "audit-logs-retention",
+			Env:         "CODER_AUDIT_LOGS_RETENTION",
+			Default:     "0",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditArchival.Retention,
+			YAML:        "auditLogsRetention",
+		},
+		{
+			Name:        "Audit Logs Archive Destination",
+			Description: "Where audit logs are archived as gzipped newline-delimited JSON before they're deleted, e.g. file:///var/lib/coder/audit or s3://bucket/prefix. S3 credentials and the region are read from the standard AWS environment variables and configuration files, the region and an endpoint for S3-compatible services can also be set with the region and endpoint query parameters. If unset, expired audit logs are deleted without being archived.",
+			Flag:        "audit-logs-archive-destination",
+			Env:         "CODER_AUDIT_LOGS_ARCHIVE_DESTINATION",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.AuditArchival.Destination,
+			YAML:        "auditLogsArchiveDestination",
+		},
+		{
+			Name:        "External Authorization URL",
+			Description: "The URL of the decision document of an Open Policy Agent server that authorizes requests, e.g. http://localhost:8181/v1/data/coder/authz/allow. The policy receives the subject, action, object and the decision of the built-in roles as input.",
+			Flag:        "external-authz-url",
+			Env:         "CODER_EXTERNAL_AUTHZ_URL",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.ExternalAuthz.URL,
+			YAML:        "externalAuthzURL",
+		},
+		{
+			Name:        "External Authorization Policy Directory",
+			Description: "A directory of .rego files evaluated in process to authorize requests. Policies allow requests with the data.coder.authz.allow rule. Can't be used with --external-authz-url.",
+			Flag:        "external-authz-policy-dir",
+			Env:         "CODER_EXTERNAL_AUTHZ_POLICY_DIR",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.ExternalAuthz.PolicyDir,
+			YAML:        "externalAuthzPolicyDir",
+		},
+		{
+			Name:        "External Authorization Fallback",
+			Description: "How requests are authorized when the external policy can't be evaluated. Either \"deny\" to deny all requests, or \"builtin\" to apply the built-in roles.",
+			Flag:        "external-authz-fallback",
+			Env:         "CODER_EXTERNAL_AUTHZ_FALLBACK",
+			Default:     "builtin",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.ExternalAuthz.Fallback,
+			YAML:        "externalAuthzFallback",
+		},
+		{
+			Name:        "External Authorization Cache TTL",
+			Description: "How long decisions of the external policy are reused for identical requests. Set to 0 to disable caching.",
+			Flag:        "external-authz-cache-ttl",
+			Env:         "CODER_EXTERNAL_AUTHZ_CACHE_TTL",
+			Default:     (10 * time.Second).String(),
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.ExternalAuthz.CacheTTL,
+			YAML:        "externalAuthzCacheTTL",
+		},
+		{
+			Name:        "Entitlements Refresh Jitter",
+			Description: "The maximum random delay before a replica refreshes entitlements itself when it hasn't received them from the replica leading refreshes. Spreads license queries of large high availability deployments.",
+			Flag:        "entitlements-refresh-jitter",
+			Env:         "CODER_ENTITLEMENTS_REFRESH_JITTER",
+			Default:     time.Minute.String(),
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.EntitlementsRefreshJitter,
+			YAML:        "entitlementsRefreshJitter",
+		},
+		{
+			Name:        "License Activation URL",
+			Description: "The URL of the activation server that exchanges activation keys for licenses with `coder licenses activate`. Licenses activated online are renewed from it before they expire. Online activation is disabled if unset.",
+			Flag:        "license-activation-url",
+			Env:         "CODER_LICENSE_ACTIVATION_URL",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.LicenseActivationURL,
+			YAML:        "licenseActivationURL",
+		},
 
 		{
 			Name:        "Disable Path Apps",
@@ -1587,6 +2063,49 @@ when required by your organization's security policy.`,
 			YAML:        "disablePathApps",
 			Annotations: clibase.Annotations{}.Mark(annotationExternalProxies, "true"),
 		},
+		{
+			Name:        "App Identity Headers",
+			Description: "Sharing levels of workspace apps whose requests carry the identity of the requesting user in the Coder-App-User-* headers and a signed Coder-App-Identity token. Valid values are owner, authenticated and public.",
+			Flag:        "app-identity-headers",
+			Env:         "CODER_APP_IDENTITY_HEADERS",
+			Value:       &c.AppIdentityHeaders,
+			YAML:        "appIdentityHeaders",
+		},
+		{
+			Name:        "Port Forward Identity Headers",
+			Description: "Sharing levels of port-forwarded apps whose requests carry the identity headers of --app-identity-headers. Port-forwarded apps are shared at the owner level. Valid values are owner, authenticated and public.",
+			Flag:        "port-forward-identity-headers",
+			Env:         "CODER_PORT_FORWARD_IDENTITY_HEADERS",
+			Value:       &c.PortForwardIdentityHeaders,
+			YAML:        "portForwardIdentityHeaders",
+		},
+		{
+			Name:        "Port Forward Policy",
+			Description: "Rules that restrict which ports of workspace agents can be forwarded, and how far apps served from them can be shared. Templates can restrict them further.",
+			YAML:        "portForwardPolicy",
+			Value:       &c.PortForwardPolicy,
+			// The port forward policy is hidden until it is defined in the
+			// YAML.
+			Hidden: true,
+		},
+		{
+			Name:        "Max PTY Sessions Per User",
+			Description: "The maximum number of concurrent web terminal sessions of each user. Reconnecting to a session doesn't count as another one. Administrators can override the limit of single users. Set to 0 for no limit.",
+			Flag:        "max-pty-sessions-per-user",
+			Env:         "CODER_MAX_PTY_SESSIONS_PER_USER",
+			Default:     "0",
+			Value:       &c.MaxPTYSessionsPerUser,
+			YAML:        "maxPTYSessionsPerUser",
+		},
+		{
+			Name:        "Max PTY Sessions Per Workspace",
+			Description: "The maximum number of concurrent web terminal sessions of each workspace, across all users. Set to 0 for no limit.",
+			Flag:        "max-pty-sessions-per-workspace",
+			Env:         "CODER_MAX_PTY_SESSIONS_PER_WORKSPACE",
+			Default:     "0",
+			Value:       &c.MaxPTYSessionsPerWorkspace,
+			YAML:        "maxPTYSessionsPerWorkspace",
+		},
 		{
 			Name:        "Disable Owner Workspace Access",
 			Description: "Remove the permission for the 'owner' role to have workspace execution on all workspaces. This prevents the 'owner' from ssh, apps, and terminal access based on the 'owner' role. They still have their user permissions to access their own workspaces.",
@@ -1679,6 +2198,63 @@ Write out the current server config as YAML to stdout.`,
 			// YAML.
 			Hidden: true,
 		},
+		{
+			Name:        "Workspace Hooks",
+			Description: "Webhooks that are called around workspace start and stop builds.",
+			YAML:        "workspaceHooks",
+			Value:       &c.WorkspaceHooks,
+			// The workspace hooks are hidden until they are defined in the
+			// YAML.
+			Hidden: true,
+		},
+		{
+			Name:        "Workspace Approval Webhook URL",
+			Description: "URL that approval requests of workspace builds are posted to, so change-management systems like ServiceNow or Jira can decide them through the callback URL of the request.",
+			Flag:        "workspace-approval-webhook-url",
+			Env:         "CODER_WORKSPACE_APPROVAL_WEBHOOK_URL",
+			Value:       &c.WorkspaceApprovalWebhook.URL,
+			YAML:        "workspaceApprovalWebhookURL",
+		},
+		{
+			Name:        "Workspace Approval Webhook Secret",
+			Description: "Secret that signs the requests of the workspace approval webhook, and that callbacks deciding approval requests must be signed with. Callbacks are refused when it's empty.",
+			Flag:        "workspace-approval-webhook-secret",
+			Env:         "CODER_WORKSPACE_APPROVAL_WEBHOOK_SECRET",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.WorkspaceApprovalWebhook.Secret,
+		},
+		{
+			Name:        "Secret Manager Vault Address",
+			Description: "Address of a HashiCorp Vault server that template variables can reference secrets of, as secret://vault/<mount>/<path>#<field>. Secrets are read from KV version 2 secrets engines when builds need them.",
+			Flag:        "secret-manager-vault-address",
+			Env:         "CODER_SECRET_MANAGER_VAULT_ADDRESS",
+			Value:       &c.SecretManager.VaultAddress,
+			YAML:        "secretManagerVaultAddress",
+		},
+		{
+			Name:        "Secret Manager Vault Token",
+			Description: "Token that Coder authenticates to the Vault server with. It needs read access to the referenced secrets.",
+			Flag:        "secret-manager-vault-token",
+			Env:         "CODER_SECRET_MANAGER_VAULT_TOKEN",
+			Annotations: clibase.Annotations{}.Mark(annotationSecretKey, "true"),
+			Value:       &c.SecretManager.VaultToken,
+		},
+		{
+			Name:        "Secret Manager AWS Region",
+			Description: "Region of the AWS Secrets Manager that template variables can reference secrets of, as secret://aws/<name or ARN>#<field>. Credentials are read from the standard AWS environment variables and configuration files.",
+			Flag:        "secret-manager-aws-region",
+			Env:         "CODER_SECRET_MANAGER_AWS_REGION",
+			Value:       &c.SecretManager.AWSRegion,
+			YAML:        "secretManagerAWSRegion",
+		},
+		{
+			Name:        "Secret Manager GCP Enable",
+			Description: "Allow template variables to reference secrets of Google Cloud Secret Manager, as secret://gcp/projects/<project>/secrets/<secret>#<field>. Credentials are read from the application default credentials.",
+			Flag:        "secret-manager-gcp-enable",
+			Env:         "CODER_SECRET_MANAGER_GCP_ENABLE",
+			Value:       &c.SecretManager.GCP,
+			YAML:        "secretManagerGCPEnable",
+		},
 		{
 			// Env handling is done in cli.ReadGitAuthFromEnvironment
 			Name:        "Git Auth Providers",
@@ -1709,6 +2285,37 @@ Write out the current server config as YAML to stdout.`,
 			Group:       &deploymentGroupNetworkingHTTP,
 			YAML:        "proxyHealthInterval",
 		},
+		{
+			Name:        "Proxy Geo Rules",
+			Description: "Prefer workspace proxies for clients in the given networks, e.g. 10.0.0.0/8=sydney. Rules are evaluated in order, and the proxies they match are preferred over the ones ranked by the latency users reported.",
+			Flag:        "proxy-geo-rules",
+			Env:         "CODER_PROXY_GEO_RULES",
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.ProxyGeoRules,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "proxyGeoRules",
+		},
+		{
+			Name:        "Proxy Version Mismatch Policy",
+			Description: "How workspace proxies running a different version than coderd are treated. Either \"warn\" to use them and report the mismatch in their status, or \"block\" to refuse their registration and stop sending users to them.",
+			Flag:        "proxy-version-mismatch-policy",
+			Env:         "CODER_PROXY_VERSION_MISMATCH_POLICY",
+			Default:     string(ProxyVersionMismatchBlock),
+			Annotations: clibase.Annotations{}.Mark(annotationEnterpriseKey, "true"),
+			Value:       &c.ProxyVersionMismatchPolicy,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "proxyVersionMismatchPolicy",
+		},
+		{
+			Name:        "Require Authentication For Status",
+			Description: "Require a session token to read the deployment status endpoint (/api/v2/deployment/status). By default the endpoint is public so it can be used by external status pages and load balancers.",
+			Flag:        "status-require-auth",
+			Env:         "CODER_STATUS_REQUIRE_AUTH",
+			Default:     "false",
+			Value:       &c.StatusRequireAuth,
+			Group:       &deploymentGroupNetworkingHTTP,
+			YAML:        "statusRequireAuth",
+		},
 		{
 			Name:        "Default Quiet Hours Schedule",
 			Description: "The default daily cron schedule applied to users that haven't set a custom quiet hours schedule themselves. The quiet hours schedule determines when workspaces will be force stopped due to the template's max TTL, and will round the max TTL up to be within the user's quiet hours window (or default). The format is the same as the standard cron format, but the day-of-month, month and day-of-week must be *. Only one hour and minute can be specified (ranges or comma separated values are not supported).",
@@ -1733,8 +2340,29 @@ type LinkConfig struct {
 	Icon   string `json:"icon" yaml:"icon"`
 }
 
+// RedactedValue replaces the value of secrets that are set in redacted
+// deployment configs.
+const RedactedValue = "[REDACTED]"
+
 // WithoutSecrets returns a copy of the config without secret values.
 func (c *DeploymentValues) WithoutSecrets() (*DeploymentValues, error) {
+	return c.replaceSecrets(func(string) string {
+		return ""
+	})
+}
+
+// Redacted returns a copy of the config where secrets that are set are
+// replaced with RedactedValue, so it's visible which secrets are configured.
+func (c *DeploymentValues) Redacted() (*DeploymentValues, error) {
+	return c.replaceSecrets(func(v string) string {
+		if v == "" {
+			return ""
+		}
+		return RedactedValue
+	})
+}
+
+func (c *DeploymentValues) replaceSecrets(replace func(string) string) (*DeploymentValues, error) {
 	var ff DeploymentValues
 
 	// Create copy via JSON.
@@ -1755,7 +2383,7 @@ func (c *DeploymentValues) WithoutSecrets() (*DeploymentValues, error) {
 		// This only works with string values for now.
 		switch v := opt.Value.(type) {
 		case *clibase.String:
-			err := v.Set("")
+			err := v.Set(replace(v.Value()))
 			if err != nil {
 				panic(err)
 			}
@@ -1767,6 +2395,68 @@ func (c *DeploymentValues) WithoutSecrets() (*DeploymentValues, error) {
 	return &ff, nil
 }
 
+// DeploymentConfigDiff lists the deployment options that aren't set to their
+// default value.
+type DeploymentConfigDiff struct {
+	Options []DeploymentOptionDiff `json:"options"`
+}
+
+// DeploymentOptionDiff is a deployment option that is set to a value other than
+// its default. Secret values are redacted.
+type DeploymentOptionDiff struct {
+	Name string `json:"name"`
+	Flag string `json:"flag,omitempty"`
+	Env  string `json:"env,omitempty"`
+	// YAML is the full path of the option in the YAML config.
+	YAML    string      `json:"yaml,omitempty"`
+	Value   interface{} `json:"value"`
+	Default interface{} `json:"default"`
+}
+
+// Diff returns the options that differ from their defaults, with secrets
+// redacted.
+func (c *DeploymentValues) Diff() (DeploymentConfigDiff, error) {
+	redacted, err := c.Redacted()
+	if err != nil {
+		return DeploymentConfigDiff{}, xerrors.Errorf("redact: %w", err)
+	}
+	var defaults DeploymentValues
+	defaultOpts := defaults.Options()
+	err = defaultOpts.SetDefaults()
+	if err != nil {
+		return DeploymentConfigDiff{}, xerrors.Errorf("set defaults: %w", err)
+	}
+
+	diff := DeploymentConfigDiff{
+		Options: []DeploymentOptionDiff{},
+	}
+	// Both option sets are built by Options, so they are in the same order.
+	for i, opt := range redacted.Options() {
+		// Values are compared by their JSON, which leaves out fields
+		// that are never returned by the API.
+		value, err := json.Marshal(opt.Value)
+		if err != nil {
+			return DeploymentConfigDiff{}, xerrors.Errorf("marshal %q: %w", opt.Name, err)
+		}
+		def, err := json.Marshal(defaultOpts[i].Value)
+		if err != nil {
+			return DeploymentConfigDiff{}, xerrors.Errorf("marshal default %q: %w", opt.Name, err)
+		}
+		if bytes.Equal(value, def) {
+			continue
+		}
+		diff.Options = append(diff.Options, DeploymentOptionDiff{
+			Name:    opt.Name,
+			Flag:    opt.Flag,
+			Env:     opt.Env,
+			YAML:    opt.YAMLPath(),
+			Value:   json.RawMessage(value),
+			Default: json.RawMessage(def),
+		})
+	}
+	return diff, nil
+}
+
 // DeploymentConfig returns the deployment config for the coder server.
 func (c *Client) DeploymentConfig(ctx context.Context) (*DeploymentConfig, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/config", nil)
@@ -1787,6 +2477,44 @@ func (c *Client) DeploymentConfig(ctx context.Context) (*DeploymentConfig, error
 	return resp, json.NewDecoder(res.Body).Decode(resp)
 }
 
+// DeploymentConfigRedacted returns the deployment config of the coder server
+// where secrets that are set are replaced with RedactedValue.
+func (c *Client) DeploymentConfigRedacted(ctx context.Context) (*DeploymentConfig, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/config/redacted", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	conf := &DeploymentValues{}
+	resp := &DeploymentConfig{
+		Values:  conf,
+		Options: conf.Options(),
+	}
+	return resp, json.NewDecoder(res.Body).Decode(resp)
+}
+
+// DeploymentConfigDiff returns the deployment options that aren't set to their
+// default value.
+func (c *Client) DeploymentConfigDiff(ctx context.Context) (DeploymentConfigDiff, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/config/diff", nil)
+	if err != nil {
+		return DeploymentConfigDiff{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return DeploymentConfigDiff{}, ReadBodyAsError(res)
+	}
+
+	var diff DeploymentConfigDiff
+	return diff, json.NewDecoder(res.Body).Decode(&diff)
+}
+
 func (c *Client) DeploymentStats(ctx context.Context) (DeploymentStats, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/stats", nil)
 	if err != nil {
@@ -1802,6 +2530,47 @@ func (c *Client) DeploymentStats(ctx context.Context) (DeploymentStats, error) {
 	return df, json.NewDecoder(res.Body).Decode(&df)
 }
 
+// DeploymentStatus is a minimal summary of the health of the deployment that
+// is safe to expose to external status pages and load balancers. A response
+// implies the API is up. The status is refreshed at most every few seconds,
+// CheckedAt is the time it was last computed.
+type DeploymentStatus struct {
+	// Healthy is true when the database is reachable.
+	Healthy   bool                     `json:"healthy"`
+	Database  DeploymentStatusDatabase `json:"database"`
+	Proxies   DeploymentStatusProxies  `json:"proxies"`
+	CheckedAt time.Time                `json:"checked_at" format:"date-time"`
+}
+
+type DeploymentStatusDatabase struct {
+	Reachable bool  `json:"reachable"`
+	LatencyMS int64 `json:"latency_ms"`
+}
+
+// DeploymentStatusProxies counts the workspace proxies of the deployment,
+// excluding the primary. Both counts are zero without workspace proxies.
+type DeploymentStatusProxies struct {
+	Total   int `json:"total"`
+	Healthy int `json:"healthy"`
+}
+
+// DeploymentStatus returns the status of the deployment. The status is
+// returned even if the deployment is unhealthy.
+func (c *Client) DeploymentStatus(ctx context.Context) (DeploymentStatus, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/status", nil)
+	if err != nil {
+		return DeploymentStatus{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusServiceUnavailable {
+		return DeploymentStatus{}, ReadBodyAsError(res)
+	}
+
+	var status DeploymentStatus
+	return status, json.NewDecoder(res.Body).Decode(&status)
+}
+
 type AppearanceConfig struct {
 	LogoURL       string              `json:"logo_url"`
 	ServiceBanner ServiceBannerConfig `json:"service_banner"`
@@ -1973,6 +2742,60 @@ func (c *Client) Experiments(ctx context.Context) (Experiments, error) {
 	return exp, json.NewDecoder(res.Body).Decode(&exp)
 }
 
+// RuntimeExperiment enables an experiment for the targeted organizations,
+// groups and users. Unlike --experiments, runtime experiments can be changed
+// without restarting the deployment.
+type RuntimeExperiment struct {
+	Experiment Experiment `json:"experiment"`
+	// Everyone enables the experiment for every user of the deployment.
+	Everyone        bool        `json:"everyone"`
+	OrganizationIDs []uuid.UUID `json:"organization_ids" format:"uuid"`
+	GroupIDs        []uuid.UUID `json:"group_ids" format:"uuid"`
+	UserIDs         []uuid.UUID `json:"user_ids" format:"uuid"`
+}
+
+// ExperimentsConfig lists every experiment enabled on the deployment.
+type ExperimentsConfig struct {
+	// Deployment experiments are enabled for everyone with --experiments.
+	// They can only be changed by restarting the deployment.
+	Deployment Experiments         `json:"deployment"`
+	Runtime    []RuntimeExperiment `json:"runtime"`
+}
+
+type UpdateRuntimeExperimentsRequest struct {
+	Runtime []RuntimeExperiment `json:"runtime"`
+}
+
+// ExperimentsConfig returns the experiments enabled on the deployment, and
+// who runtime experiments are enabled for.
+func (c *Client) ExperimentsConfig(ctx context.Context) (ExperimentsConfig, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/experiments/config", nil)
+	if err != nil {
+		return ExperimentsConfig{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ExperimentsConfig{}, ReadBodyAsError(res)
+	}
+	var cfg ExperimentsConfig
+	return cfg, json.NewDecoder(res.Body).Decode(&cfg)
+}
+
+// UpdateRuntimeExperiments replaces the runtime experiments of the
+// deployment.
+func (c *Client) UpdateRuntimeExperiments(ctx context.Context, req UpdateRuntimeExperimentsRequest) (ExperimentsConfig, error) {
+	res, err := c.Request(ctx, http.MethodPut, "/api/v2/experiments/config", req)
+	if err != nil {
+		return ExperimentsConfig{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ExperimentsConfig{}, ReadBodyAsError(res)
+	}
+	var cfg ExperimentsConfig
+	return cfg, json.NewDecoder(res.Body).Decode(&cfg)
+}
+
 type DAUsResponse struct {
 	Entries      []DAUEntry `json:"entries"`
 	TZHourOffset int        `json:"tz_hour_offset"`
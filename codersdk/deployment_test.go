@@ -57,6 +57,15 @@ func TestDeploymentValues_HighlyConfigurable(t *testing.T) {
 		"SCIM API Key": {
 			yaml: true,
 		},
+		"Audit Splunk HEC Token": {
+			yaml: true,
+		},
+		"Workspace Approval Webhook Secret": {
+			yaml: true,
+		},
+		"Secret Manager Vault Token": {
+			yaml: true,
+		},
 		// These complex objects should be configured through YAML.
 		"Support Links": {
 			flag: true,
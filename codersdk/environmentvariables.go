@@ -0,0 +1,108 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ManagedEnvironmentVariable is injected into every agent of the workspaces
+// it applies to. Variables without a template apply to every workspace in the
+// organization, and variables on a template override organization variables
+// of the same name. Environment variables set by the agent in the template
+// take precedence over both.
+type ManagedEnvironmentVariable struct {
+	ID             uuid.UUID  `json:"id" format:"uuid"`
+	OrganizationID uuid.UUID  `json:"organization_id" format:"uuid"`
+	TemplateID     *uuid.UUID `json:"template_id,omitempty" format:"uuid"`
+	CreatedAt      time.Time  `json:"created_at" format:"date-time"`
+	UpdatedAt      time.Time  `json:"updated_at" format:"date-time"`
+	Name           string     `json:"name"`
+	// Value is omitted for secret variables.
+	Value  string `json:"value,omitempty"`
+	Secret bool   `json:"secret"`
+}
+
+type CreateManagedEnvironmentVariableRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Value string `json:"value"`
+	// Secret variables are still injected into agents, but their value is
+	// never returned by the API.
+	Secret bool `json:"secret"`
+}
+
+type UpdateManagedEnvironmentVariableRequest struct {
+	Value  string `json:"value"`
+	Secret bool   `json:"secret"`
+}
+
+func (c *Client) OrganizationEnvironmentVariables(ctx context.Context, organizationID uuid.UUID) ([]ManagedEnvironmentVariable, error) {
+	return c.managedEnvironmentVariables(ctx, fmt.Sprintf("/api/v2/organizations/%s/environmentvariables", organizationID))
+}
+
+func (c *Client) CreateOrganizationEnvironmentVariable(ctx context.Context, organizationID uuid.UUID, req CreateManagedEnvironmentVariableRequest) (ManagedEnvironmentVariable, error) {
+	return c.createManagedEnvironmentVariable(ctx, fmt.Sprintf("/api/v2/organizations/%s/environmentvariables", organizationID), req)
+}
+
+func (c *Client) TemplateEnvironmentVariables(ctx context.Context, templateID uuid.UUID) ([]ManagedEnvironmentVariable, error) {
+	return c.managedEnvironmentVariables(ctx, fmt.Sprintf("/api/v2/templates/%s/environmentvariables", templateID))
+}
+
+func (c *Client) CreateTemplateEnvironmentVariable(ctx context.Context, templateID uuid.UUID, req CreateManagedEnvironmentVariableRequest) (ManagedEnvironmentVariable, error) {
+	return c.createManagedEnvironmentVariable(ctx, fmt.Sprintf("/api/v2/templates/%s/environmentvariables", templateID), req)
+}
+
+func (c *Client) UpdateEnvironmentVariable(ctx context.Context, id uuid.UUID, req UpdateManagedEnvironmentVariableRequest) (ManagedEnvironmentVariable, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/environmentvariables/%s", id), req)
+	if err != nil {
+		return ManagedEnvironmentVariable{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ManagedEnvironmentVariable{}, ReadBodyAsError(res)
+	}
+	var variable ManagedEnvironmentVariable
+	return variable, json.NewDecoder(res.Body).Decode(&variable)
+}
+
+func (c *Client) DeleteEnvironmentVariable(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/environmentvariables/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+func (c *Client) managedEnvironmentVariables(ctx context.Context, path string) ([]ManagedEnvironmentVariable, error) {
+	res, err := c.Request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var variables []ManagedEnvironmentVariable
+	return variables, json.NewDecoder(res.Body).Decode(&variables)
+}
+
+func (c *Client) createManagedEnvironmentVariable(ctx context.Context, path string, req CreateManagedEnvironmentVariableRequest) (ManagedEnvironmentVariable, error) {
+	res, err := c.Request(ctx, http.MethodPost, path, req)
+	if err != nil {
+		return ManagedEnvironmentVariable{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return ManagedEnvironmentVariable{}, ReadBodyAsError(res)
+	}
+	var variable ManagedEnvironmentVariable
+	return variable, json.NewDecoder(res.Body).Decode(&variable)
+}
@@ -0,0 +1,39 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// FeatureFlags are the client and agent feature flags configured on the
+// deployment, evaluated for the caller. Clients must treat a flag that's
+// missing from the response as disabled, so flags can be added to and removed
+// from a deployment without upgrading clients or agents.
+type FeatureFlags struct {
+	// Flags maps each configured flag to whether it's enabled for the
+	// caller. Flags rolled out to a percentage of the deployment are
+	// enabled consistently for the same user or agent.
+	Flags map[string]bool `json:"flags"`
+}
+
+// Enabled returns whether the flag is enabled. Flags that aren't
+// configured on the deployment are disabled.
+func (f FeatureFlags) Enabled(flag string) bool {
+	return f.Flags[flag]
+}
+
+// FeatureFlags returns the feature flags evaluated for the authenticated
+// user.
+func (c *Client) FeatureFlags(ctx context.Context) (FeatureFlags, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/feature-flags", nil)
+	if err != nil {
+		return FeatureFlags{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return FeatureFlags{}, ReadBodyAsError(res)
+	}
+	var flags FeatureFlags
+	return flags, json.NewDecoder(res.Body).Decode(&flags)
+}
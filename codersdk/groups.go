@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -22,6 +24,9 @@ type CreateGroupRequest struct {
 	DisplayName    string `json:"display_name"`
 	AvatarURL      string `json:"avatar_url"`
 	QuotaAllowance int    `json:"quota_allowance"`
+	// Metadata is arbitrary key-value metadata of the group, such as the
+	// team or cost center it represents.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 type Group struct {
@@ -33,6 +38,13 @@ type Group struct {
 	AvatarURL      string      `json:"avatar_url"`
 	QuotaAllowance int         `json:"quota_allowance"`
 	Source         GroupSource `json:"source"`
+	// QuotaOverride replaces the summed quota allowances of members of the
+	// group when set.
+	QuotaOverride *int              `json:"quota_override,omitempty"`
+	Metadata      map[string]string `json:"metadata"`
+	// Version changes whenever the group or its members change. Pass it to
+	// PatchGroupIfMatch to only patch the group if it's unchanged.
+	Version string `json:"version"`
 }
 
 func (g Group) IsEveryone() bool {
@@ -115,12 +127,30 @@ type PatchGroupRequest struct {
 	DisplayName    *string  `json:"display_name"`
 	AvatarURL      *string  `json:"avatar_url"`
 	QuotaAllowance *int     `json:"quota_allowance"`
+	// QuotaOverride sets the quota override of the group. A negative value
+	// removes the override.
+	QuotaOverride *int `json:"quota_override"`
+	// Metadata replaces the metadata of the group when set. An empty map
+	// removes all metadata.
+	Metadata *map[string]string `json:"metadata"`
 }
 
 func (c *Client) PatchGroup(ctx context.Context, group uuid.UUID, req PatchGroupRequest) (Group, error) {
+	return c.PatchGroupIfMatch(ctx, group, "", req)
+}
+
+// PatchGroupIfMatch patches the group only if its version matches. It fails
+// with http.StatusPreconditionFailed if the group changed in the meantime.
+// An empty version patches the group unconditionally.
+func (c *Client) PatchGroupIfMatch(ctx context.Context, group uuid.UUID, version string, req PatchGroupRequest) (Group, error) {
 	res, err := c.Request(ctx, http.MethodPatch,
 		fmt.Sprintf("/api/v2/groups/%s", group.String()),
 		req,
+		func(r *http.Request) {
+			if version != "" {
+				r.Header.Set("If-Match", strconv.Quote(version))
+			}
+		},
 	)
 	if err != nil {
 		return Group{}, xerrors.Errorf("make request: %w", err)
@@ -149,3 +179,96 @@ func (c *Client) DeleteGroup(ctx context.Context, group uuid.UUID) error {
 	}
 	return nil
 }
+
+// UploadGroupAvatar uploads an image as the avatar of the group. The avatar
+// URL of the group is changed to serve it.
+func (c *Client) UploadGroupAvatar(ctx context.Context, group uuid.UUID, contentType string, rd io.Reader) (Group, error) {
+	res, err := c.Request(ctx, http.MethodPut,
+		fmt.Sprintf("/api/v2/groups/%s/avatar", group.String()),
+		rd,
+		func(r *http.Request) {
+			r.Header.Set("Content-Type", contentType)
+		},
+	)
+	if err != nil {
+		return Group{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Group{}, ReadBodyAsError(res)
+	}
+	var resp Group
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// GroupAvatar fetches the uploaded avatar of the group and its content type.
+func (c *Client) GroupAvatar(ctx context.Context, group uuid.UUID) ([]byte, string, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/groups/%s/avatar", group.String()),
+		nil,
+	)
+	if err != nil {
+		return nil, "", xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, "", ReadBodyAsError(res)
+	}
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", xerrors.Errorf("read body: %w", err)
+	}
+	return data, res.Header.Get("Content-Type"), nil
+}
+
+// GroupDefaultTemplate is a template every member of a group gets access to.
+type GroupDefaultTemplate struct {
+	TemplateID uuid.UUID `json:"template_id" format:"uuid"`
+	// CreateWorkspace creates a starter workspace from the template for
+	// every member of the group, including members who join later. Members
+	// get at most one starter workspace per template.
+	CreateWorkspace bool `json:"create_workspace"`
+}
+
+type UpdateGroupDefaultTemplatesRequest struct {
+	DefaultTemplates []GroupDefaultTemplate `json:"default_templates"`
+}
+
+// GroupDefaultTemplates returns the default templates of the group.
+func (c *Client) GroupDefaultTemplates(ctx context.Context, group uuid.UUID) ([]GroupDefaultTemplate, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/groups/%s/default-templates", group.String()),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []GroupDefaultTemplate
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// UpdateGroupDefaultTemplates replaces the default templates of the group. The
+// group is granted use of every default template.
+func (c *Client) UpdateGroupDefaultTemplates(ctx context.Context, group uuid.UUID, req UpdateGroupDefaultTemplatesRequest) ([]GroupDefaultTemplate, error) {
+	res, err := c.Request(ctx, http.MethodPut,
+		fmt.Sprintf("/api/v2/groups/%s/default-templates", group.String()),
+		req,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var resp []GroupDefaultTemplate
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -86,6 +86,70 @@ func (c *Client) UserLatencyInsights(ctx context.Context, req UserLatencyInsight
 	return result, json.NewDecoder(resp.Body).Decode(&result)
 }
 
+// UserBandwidthInsightsResponse is the response from the user bandwidth
+// insights endpoint.
+type UserBandwidthInsightsResponse struct {
+	Report UserBandwidthInsightsReport `json:"report"`
+}
+
+// UserBandwidthInsightsReport is the report from the user bandwidth insights
+// endpoint.
+type UserBandwidthInsightsReport struct {
+	StartTime   time.Time       `json:"start_time" format:"date-time"`
+	EndTime     time.Time       `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID     `json:"template_ids" format:"uuid"`
+	Users       []UserBandwidth `json:"users"`
+}
+
+// UserBandwidth shows the traffic of the workspaces of a user for one type of
+// connection. Port forwarded connections are reported per agent port, so
+// traffic of workspace apps can be told apart.
+type UserBandwidth struct {
+	TemplateIDs    []uuid.UUID `json:"template_ids" format:"uuid"`
+	UserID         uuid.UUID   `json:"user_id" format:"uuid"`
+	Username       string      `json:"username"`
+	AvatarURL      string      `json:"avatar_url" format:"uri"`
+	ConnectionType string      `json:"connection_type" enums:"ssh,reconnecting_pty,speedtest,port_forward"`
+	Port           uint16      `json:"port,omitempty"`
+	// Peers is the number of distinct tailnet peers the traffic was
+	// exchanged with.
+	Peers   int64 `json:"peers"`
+	RxBytes int64 `json:"rx_bytes"`
+	TxBytes int64 `json:"tx_bytes"`
+}
+
+type UserBandwidthInsightsRequest struct {
+	StartTime   time.Time   `json:"start_time" format:"date-time"`
+	EndTime     time.Time   `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
+}
+
+func (c *Client) UserBandwidthInsights(ctx context.Context, req UserBandwidthInsightsRequest) (UserBandwidthInsightsResponse, error) {
+	var qp []string
+	qp = append(qp, fmt.Sprintf("start_time=%s", req.StartTime.Format(insightsTimeLayout)))
+	qp = append(qp, fmt.Sprintf("end_time=%s", req.EndTime.Format(insightsTimeLayout)))
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp = append(qp, fmt.Sprintf("template_ids=%s", strings.Join(templateIDs, ",")))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/user-bandwidth?%s", strings.Join(qp, "&"))
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return UserBandwidthInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return UserBandwidthInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result UserBandwidthInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
 // TemplateInsightsResponse is the response from the template insights endpoint.
 type TemplateInsightsResponse struct {
 	Report          TemplateInsightsReport           `json:"report"`
@@ -185,3 +249,292 @@ func (c *Client) TemplateInsights(ctx context.Context, req TemplateInsightsReque
 	var result TemplateInsightsResponse
 	return result, json.NewDecoder(resp.Body).Decode(&result)
 }
+
+// TemplateBuildSLOInsightsResponse is the response from the template build SLO
+// insights endpoint.
+type TemplateBuildSLOInsightsResponse struct {
+	GeneratedAt              time.Time          `json:"generated_at" format:"date-time"`
+	SuccessObjective         float64            `json:"success_objective" example:"0.99"`
+	DurationObjectiveSeconds float64            `json:"duration_objective_seconds" example:"600"`
+	Templates                []TemplateBuildSLO `json:"templates"`
+}
+
+// TemplateBuildSLO shows the build SLO compliance of a template over each of
+// the requested rolling windows.
+type TemplateBuildSLO struct {
+	TemplateID uuid.UUID                `json:"template_id" format:"uuid"`
+	Windows    []TemplateBuildSLOWindow `json:"windows"`
+}
+
+// TemplateBuildSLOWindow shows the build SLO compliance of a template over a
+// single rolling window ending at the time the report was generated.
+//
+// The burn rates express how fast the error budget is being consumed relative
+// to the objective: a burn rate of 1 means the budget will be exactly used up
+// over the window, anything above means the objective is at risk. Pairing a
+// short and a long window with burn rate thresholds gives the usual
+// multi-window burn-rate alerts.
+type TemplateBuildSLOWindow struct {
+	WindowSeconds                 int64     `json:"window_seconds" example:"3600"`
+	StartTime                     time.Time `json:"start_time" format:"date-time"`
+	EndTime                       time.Time `json:"end_time" format:"date-time"`
+	TotalBuilds                   int64     `json:"total_builds" example:"120"`
+	SuccessfulBuilds              int64     `json:"successful_builds" example:"118"`
+	FailedBuilds                  int64     `json:"failed_builds" example:"2"`
+	BuildsWithinDurationObjective int64     `json:"builds_within_duration_objective" example:"110"`
+	// SuccessRate and DurationObjectiveCompliance are 1 when there were no
+	// builds in the window.
+	SuccessRate                 float64 `json:"success_rate" example:"0.983"`
+	DurationObjectiveCompliance float64 `json:"duration_objective_compliance" example:"0.916"`
+	SuccessBurnRate             float64 `json:"success_burn_rate" example:"1.67"`
+	DurationBurnRate            float64 `json:"duration_burn_rate" example:"8.33"`
+	// BuildDurationP50Seconds and BuildDurationP95Seconds are -1 when there
+	// were no builds in the window.
+	BuildDurationP50Seconds float64 `json:"build_duration_p50_seconds" example:"42.5"`
+	BuildDurationP95Seconds float64 `json:"build_duration_p95_seconds" example:"310.2"`
+}
+
+type TemplateBuildSLOInsightsRequest struct {
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
+	// Windows defaults to 5m, 30m, 1h, 6h, 24h and 72h when empty.
+	Windows []time.Duration `json:"windows"`
+	// SuccessObjective is the target fraction of successful builds, it
+	// defaults to 0.99 when zero.
+	SuccessObjective float64 `json:"success_objective"`
+	// DurationObjective is the maximum duration a successful build may take
+	// to count towards the duration objective, it defaults to 10 minutes
+	// when zero.
+	DurationObjective time.Duration `json:"duration_objective"`
+}
+
+func (c *Client) TemplateBuildSLOInsights(ctx context.Context, req TemplateBuildSLOInsightsRequest) (TemplateBuildSLOInsightsResponse, error) {
+	var qp []string
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp = append(qp, fmt.Sprintf("template_ids=%s", strings.Join(templateIDs, ",")))
+	}
+	if len(req.Windows) > 0 {
+		var windows []string
+		for _, w := range req.Windows {
+			windows = append(windows, w.String())
+		}
+		qp = append(qp, fmt.Sprintf("windows=%s", strings.Join(windows, ",")))
+	}
+	if req.SuccessObjective != 0 {
+		qp = append(qp, fmt.Sprintf("success_objective=%v", req.SuccessObjective))
+	}
+	if req.DurationObjective != 0 {
+		qp = append(qp, fmt.Sprintf("duration_objective=%s", req.DurationObjective))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/template-build-slos?%s", strings.Join(qp, "&"))
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return TemplateBuildSLOInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return TemplateBuildSLOInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result TemplateBuildSLOInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// AgentFirstConnectInsightsResponse is the response from the agent first
+// connect insights endpoint.
+type AgentFirstConnectInsightsResponse struct {
+	Report AgentFirstConnectInsightsReport `json:"report"`
+}
+
+// AgentFirstConnectInsightsReport is the report from the agent first connect
+// insights endpoint.
+type AgentFirstConnectInsightsReport struct {
+	StartTime   time.Time                   `json:"start_time" format:"date-time"`
+	EndTime     time.Time                   `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID                 `json:"template_ids" format:"uuid"`
+	Templates   []TemplateAgentFirstConnect `json:"templates"`
+}
+
+// TemplateAgentFirstConnect shows how long the agents of a template took to
+// connect for the first time after their workspace build completed. Only
+// agents of successful start builds are included.
+type TemplateAgentFirstConnect struct {
+	TemplateID      uuid.UUID `json:"template_id" format:"uuid"`
+	TotalAgents     int64     `json:"total_agents" example:"40"`
+	ConnectedAgents int64     `json:"connected_agents" example:"37"`
+	// NeverConnectedAgents counts the agents that didn't connect before their
+	// connection timeout, which usually points to a broken startup script or
+	// image.
+	NeverConnectedAgents int64 `json:"never_connected_agents" example:"2"`
+	// The percentiles are -1 when no agent connected.
+	FirstConnectP50Seconds float64 `json:"first_connect_p50_seconds" example:"12.4"`
+	FirstConnectP95Seconds float64 `json:"first_connect_p95_seconds" example:"48.1"`
+	FirstConnectP99Seconds float64 `json:"first_connect_p99_seconds" example:"95.7"`
+}
+
+type AgentFirstConnectInsightsRequest struct {
+	StartTime   time.Time   `json:"start_time" format:"date-time"`
+	EndTime     time.Time   `json:"end_time" format:"date-time"`
+	TemplateIDs []uuid.UUID `json:"template_ids" format:"uuid"`
+}
+
+func (c *Client) AgentFirstConnectInsights(ctx context.Context, req AgentFirstConnectInsightsRequest) (AgentFirstConnectInsightsResponse, error) {
+	var qp []string
+	qp = append(qp, fmt.Sprintf("start_time=%s", req.StartTime.Format(insightsTimeLayout)))
+	qp = append(qp, fmt.Sprintf("end_time=%s", req.EndTime.Format(insightsTimeLayout)))
+	if len(req.TemplateIDs) > 0 {
+		var templateIDs []string
+		for _, id := range req.TemplateIDs {
+			templateIDs = append(templateIDs, id.String())
+		}
+		qp = append(qp, fmt.Sprintf("template_ids=%s", strings.Join(templateIDs, ",")))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/agent-first-connect?%s", strings.Join(qp, "&"))
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return AgentFirstConnectInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return AgentFirstConnectInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result AgentFirstConnectInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// NetcheckInsightsResponse is the response from the netcheck insights
+// endpoint.
+type NetcheckInsightsResponse struct {
+	Report NetcheckInsightsReport `json:"report"`
+}
+
+// NetcheckInsightsReport summarizes the connectivity preflights reported by
+// clients, to correlate connection issues with the networks of users.
+type NetcheckInsightsReport struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	EndTime   time.Time `json:"end_time" format:"date-time"`
+	// UserID is set if the report is limited to the preflights of a user.
+	UserID       *uuid.UUID                   `json:"user_id,omitempty" format:"uuid"`
+	TotalReports int64                        `json:"total_reports" example:"120"`
+	NATTypes     []NetcheckNATTypeInsight     `json:"nat_types"`
+	BlockedPorts []NetcheckBlockedPortInsight `json:"blocked_ports"`
+	// Reports holds the most recent preflights, newest first.
+	Reports []NetcheckReport `json:"reports"`
+}
+
+// NetcheckNATTypeInsight counts the preflights that detected a type of NAT.
+type NetcheckNATTypeInsight struct {
+	NATType NATType `json:"nat_type" enums:"unknown,udp_blocked,easy,hard"`
+	Reports int64   `json:"reports" example:"30"`
+	Users   int64   `json:"users" example:"12"`
+}
+
+// NetcheckBlockedPortInsight counts the preflights that couldn't reach a port
+// of the STUN or DERP servers.
+type NetcheckBlockedPortInsight struct {
+	Port    string `json:"port" example:"udp/3478"`
+	Reports int64  `json:"reports" example:"8"`
+	Users   int64  `json:"users" example:"3"`
+}
+
+type NetcheckInsightsRequest struct {
+	StartTime time.Time `json:"start_time" format:"date-time"`
+	EndTime   time.Time `json:"end_time" format:"date-time"`
+	UserID    uuid.UUID `json:"user_id" format:"uuid"`
+}
+
+func (c *Client) NetcheckInsights(ctx context.Context, req NetcheckInsightsRequest) (NetcheckInsightsResponse, error) {
+	var qp []string
+	qp = append(qp, fmt.Sprintf("start_time=%s", req.StartTime.Format(insightsTimeLayout)))
+	qp = append(qp, fmt.Sprintf("end_time=%s", req.EndTime.Format(insightsTimeLayout)))
+	if req.UserID != uuid.Nil {
+		qp = append(qp, fmt.Sprintf("user_id=%s", req.UserID))
+	}
+
+	reqURL := fmt.Sprintf("/api/v2/insights/netcheck?%s", strings.Join(qp, "&"))
+	resp, err := c.Request(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return NetcheckInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return NetcheckInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result NetcheckInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
+
+// TemplateBuildInsightsResponse is the response from the build insights
+// endpoint of a template. The insights are rolled up periodically, so the
+// builds that completed after UpdatedAt of a version aren't included yet.
+type TemplateBuildInsightsResponse struct {
+	TemplateID uuid.UUID `json:"template_id" format:"uuid"`
+	// Versions are ordered from the newest to the oldest version. Versions
+	// without completed builds are omitted.
+	Versions []TemplateVersionBuildInsights `json:"versions"`
+}
+
+// TemplateVersionBuildInsights shows how long the workspace builds of a
+// template version take and why they fail. Canceled builds are excluded.
+type TemplateVersionBuildInsights struct {
+	TemplateVersionID   uuid.UUID `json:"template_version_id" format:"uuid"`
+	TemplateVersionName string    `json:"template_version_name"`
+	CreatedAt           time.Time `json:"created_at" format:"date-time"`
+	Active              bool      `json:"active"`
+	TotalBuilds         int64     `json:"total_builds" example:"120"`
+	FailedBuilds        int64     `json:"failed_builds" example:"6"`
+	FailureRate         float64   `json:"failure_rate" example:"0.05"`
+	// DurationP50Seconds and DurationP95Seconds are computed over the
+	// successful builds, they are -1 when there were none.
+	DurationP50Seconds float64 `json:"duration_p50_seconds" example:"42.5"`
+	DurationP95Seconds float64 `json:"duration_p95_seconds" example:"310.2"`
+	// Failures are ordered from the most to the least frequent error code.
+	Failures []TemplateBuildFailureInsight `json:"failures"`
+	// Comparison is omitted for the oldest version with completed builds.
+	Comparison *TemplateVersionBuildComparison `json:"comparison,omitempty"`
+	UpdatedAt  time.Time                       `json:"updated_at" format:"date-time"`
+}
+
+// TemplateBuildFailureInsight counts the failed builds of a template version
+// by the error code of their provisioner job.
+type TemplateBuildFailureInsight struct {
+	// ErrorCode is empty for failures that the provisioner didn't classify.
+	ErrorCode JobErrorCode `json:"error_code" enums:"MISSING_TEMPLATE_PARAMETER,REQUIRED_TEMPLATE_VARIABLES,BUILD_TIMEOUT,RESOURCE_LIMIT_EXCEEDED"`
+	Builds    int64        `json:"builds" example:"4"`
+	// Rate is the fraction of all builds of the version that failed with the
+	// error code.
+	Rate float64 `json:"rate" example:"0.033"`
+}
+
+// TemplateVersionBuildComparison compares the builds of a template version to
+// those of the previous version with completed builds. Positive changes mean
+// the version fails more often or builds slower.
+type TemplateVersionBuildComparison struct {
+	PreviousTemplateVersionID   uuid.UUID `json:"previous_template_version_id" format:"uuid"`
+	PreviousTemplateVersionName string    `json:"previous_template_version_name"`
+	FailureRateChange           float64   `json:"failure_rate_change" example:"-0.02"`
+	// DurationP50ChangeSeconds and DurationP95ChangeSeconds are 0 when either
+	// version has no successful builds.
+	DurationP50ChangeSeconds float64 `json:"duration_p50_change_seconds" example:"-3.5"`
+	DurationP95ChangeSeconds float64 `json:"duration_p95_change_seconds" example:"12.1"`
+}
+
+func (c *Client) TemplateBuildInsights(ctx context.Context, templateID uuid.UUID) (TemplateBuildInsightsResponse, error) {
+	resp, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/insights/builds", templateID), nil)
+	if err != nil {
+		return TemplateBuildInsightsResponse{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return TemplateBuildInsightsResponse{}, ReadBodyAsError(resp)
+	}
+	var result TemplateBuildInsightsResponse
+	return result, json.NewDecoder(resp.Body).Decode(&result)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,12 +14,22 @@ import (
 
 const (
 	LicenseExpiryClaim = "license_expires"
+
+	// LicenseUsageDateLayout is the layout of the dates accepted by the
+	// license usage endpoint.
+	LicenseUsageDateLayout = "2006-01-02"
 )
 
 type AddLicenseRequest struct {
 	License string `json:"license" validate:"required"`
 }
 
+// ActivateLicenseRequest exchanges an activation key for a license with the
+// activation server of the deployment.
+type ActivateLicenseRequest struct {
+	ActivationKey string `json:"activation_key" validate:"required"`
+}
+
 type License struct {
 	ID         int32     `json:"id"`
 	UUID       uuid.UUID `json:"uuid" format:"uuid"`
@@ -107,6 +118,23 @@ func (c *Client) AddLicense(ctx context.Context, r AddLicenseRequest) (License,
 	return l, d.Decode(&l)
 }
 
+// ActivateLicense activates a license online. Licenses activated online are
+// renewed automatically before they expire.
+func (c *Client) ActivateLicense(ctx context.Context, r ActivateLicenseRequest) (License, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/licenses/activate", r)
+	if err != nil {
+		return License{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return License{}, ReadBodyAsError(res)
+	}
+	var l License
+	d := json.NewDecoder(res.Body)
+	d.UseNumber()
+	return l, d.Decode(&l)
+}
+
 func (c *Client) Licenses(ctx context.Context) ([]License, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/licenses", nil)
 	if err != nil {
@@ -133,3 +161,66 @@ func (c *Client) DeleteLicense(ctx context.Context, id int32) error {
 	}
 	return nil
 }
+
+// LicenseUsageRequest selects the UTC days to report license usage for. Both
+// dates are inclusive. A zero EndDate means today, a zero StartDate means 90
+// days before EndDate.
+type LicenseUsageRequest struct {
+	StartDate time.Time `json:"start_date" format:"date-time"`
+	EndDate   time.Time `json:"end_date" format:"date-time"`
+}
+
+// LicenseUsage is the historical consumption of licensed features that have a
+// measured usage, such as the number of active users.
+type LicenseUsage struct {
+	StartDate time.Time             `json:"start_date" format:"date-time"`
+	EndDate   time.Time             `json:"end_date" format:"date-time"`
+	Features  []LicenseFeatureUsage `json:"features"`
+}
+
+type LicenseFeatureUsage struct {
+	Feature FeatureName `json:"feature"`
+	// Actual and Limit are the current usage and licensed limit, as reported
+	// by the entitlements.
+	Actual *int64 `json:"actual,omitempty"`
+	Limit  *int64 `json:"limit,omitempty"`
+	// PeakActual is the highest usage recorded in the requested range.
+	PeakActual int64 `json:"peak_actual"`
+	// DaysExceeded is the number of days in the requested range on which
+	// usage exceeded the licensed limit.
+	DaysExceeded int64             `json:"days_exceeded"`
+	Days         []LicenseUsageDay `json:"days"`
+}
+
+type LicenseUsageDay struct {
+	// Date is the start of the UTC day.
+	Date         time.Time `json:"date" format:"date-time"`
+	PeakActual   int64     `json:"peak_actual"`
+	LatestActual int64     `json:"latest_actual"`
+	Limit        *int64    `json:"limit,omitempty"`
+	// ExceededAt is the first time during the day that usage exceeded the
+	// licensed limit.
+	ExceededAt *time.Time `json:"exceeded_at,omitempty" format:"date-time"`
+}
+
+// LicenseUsage returns the daily usage of licensed features, for true-up
+// reconciliation against the licensed limits.
+func (c *Client) LicenseUsage(ctx context.Context, req LicenseUsageRequest) (LicenseUsage, error) {
+	var qp []string
+	if !req.StartDate.IsZero() {
+		qp = append(qp, fmt.Sprintf("start_date=%s", req.StartDate.Format(LicenseUsageDateLayout)))
+	}
+	if !req.EndDate.IsZero() {
+		qp = append(qp, fmt.Sprintf("end_date=%s", req.EndDate.Format(LicenseUsageDateLayout)))
+	}
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/licenses/usage?%s", strings.Join(qp, "&")), nil)
+	if err != nil {
+		return LicenseUsage{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return LicenseUsage{}, ReadBodyAsError(res)
+	}
+	var usage LicenseUsage
+	return usage, json.NewDecoder(res.Body).Decode(&usage)
+}
@@ -0,0 +1,184 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"tailscale.com/net/netcheck"
+	"tailscale.com/net/portmapper"
+	"tailscale.com/tailcfg"
+	tslogger "tailscale.com/types/logger"
+
+	"github.com/coder/coder/v2/buildinfo"
+)
+
+// NATType describes how the NAT of a client maps its connections, which
+// decides whether direct connections to workspaces are likely to succeed.
+type NATType string
+
+const (
+	// NATTypeUnknown is reported when the client couldn't reach enough STUN
+	// servers to tell how its connections are mapped.
+	NATTypeUnknown NATType = "unknown"
+	// NATTypeUDPBlocked is reported when no STUN round trip completed, so
+	// connections can only be relayed through DERP over TCP.
+	NATTypeUDPBlocked NATType = "udp_blocked"
+	// NATTypeEasy NATs use the same public port for every destination, so
+	// direct connections usually succeed.
+	NATTypeEasy NATType = "easy"
+	// NATTypeHard NATs use a different public port for every destination, so
+	// connections are usually relayed through DERP.
+	NATTypeHard NATType = "hard"
+)
+
+// PostNetcheckReportRequest is the result of a connectivity preflight run by
+// a client against the DERP and STUN servers of the deployment.
+type PostNetcheckReportRequest struct {
+	ClientVersion string  `json:"client_version"`
+	ClientOS      string  `json:"client_os"`
+	NATType       NATType `json:"nat_type" enums:"unknown,udp_blocked,easy,hard"`
+	UDP           bool    `json:"udp"`
+	IPv4          bool    `json:"ipv4"`
+	IPv6          bool    `json:"ipv6"`
+	// MappingVariesByDestIP and HairPinning are omitted when the client
+	// couldn't tell.
+	MappingVariesByDestIP *bool `json:"mapping_varies_by_dest_ip,omitempty"`
+	HairPinning           *bool `json:"hair_pinning,omitempty"`
+	// PreferredDERP is the ID of the DERP region with the lowest latency, or
+	// zero if no region was reached.
+	PreferredDERP int `json:"preferred_derp"`
+	// RegionLatencyMS holds the latencies to the DERP regions the client
+	// reached, keyed by region ID.
+	RegionLatencyMS map[int]float64 `json:"region_latency_ms"`
+	// BlockedPorts lists the ports of STUN and DERP servers the client
+	// couldn't reach, e.g. "udp/3478".
+	BlockedPorts []string `json:"blocked_ports"`
+}
+
+// NetcheckReport is a connectivity preflight reported by a user.
+type NetcheckReport struct {
+	ID        uuid.UUID `json:"id" format:"uuid"`
+	UserID    uuid.UUID `json:"user_id" format:"uuid"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+	PostNetcheckReportRequest
+}
+
+// RunNetcheck checks the connectivity of the client to the STUN and DERP
+// servers of the DERP map. It can take a few seconds, so the context should
+// have a deadline.
+func RunNetcheck(ctx context.Context, derpMap *tailcfg.DERPMap, logf tslogger.Logf) (PostNetcheckReportRequest, error) {
+	if logf == nil {
+		logf = tslogger.Discard
+	}
+	client := &netcheck.Client{
+		PortMapper: portmapper.NewClient(tslogger.WithPrefix(logf, "portmap: "), nil, nil, nil),
+		Logf:       tslogger.WithPrefix(logf, "netcheck: "),
+	}
+	report, err := client.GetReport(ctx, derpMap)
+	if err != nil {
+		return PostNetcheckReportRequest{}, xerrors.Errorf("get netcheck report: %w", err)
+	}
+	return ConvertNetcheckReport(derpMap, report), nil
+}
+
+// ConvertNetcheckReport converts a netcheck report into the report sent to
+// coderd. Ports are considered blocked when no STUN round trip completed, or
+// when a DERP region wasn't reached at all.
+func ConvertNetcheckReport(derpMap *tailcfg.DERPMap, report *netcheck.Report) PostNetcheckReportRequest {
+	req := PostNetcheckReportRequest{
+		ClientVersion:   buildinfo.Version(),
+		ClientOS:        runtime.GOOS,
+		NATType:         NATTypeUnknown,
+		UDP:             report.UDP,
+		IPv4:            report.IPv4,
+		IPv6:            report.IPv6,
+		PreferredDERP:   report.PreferredDERP,
+		RegionLatencyMS: make(map[int]float64, len(report.RegionLatency)),
+		BlockedPorts:    []string{},
+	}
+	if v, ok := report.MappingVariesByDestIP.Get(); ok {
+		req.MappingVariesByDestIP = &v
+		if v {
+			req.NATType = NATTypeHard
+		} else {
+			req.NATType = NATTypeEasy
+		}
+	}
+	if !report.UDP {
+		req.NATType = NATTypeUDPBlocked
+	}
+	if v, ok := report.HairPinning.Get(); ok {
+		req.HairPinning = &v
+	}
+	for regionID, latency := range report.RegionLatency {
+		req.RegionLatencyMS[regionID] = float64(latency.Microseconds()) / 1000
+	}
+
+	blocked := map[string]struct{}{}
+	if derpMap != nil {
+		for regionID, region := range derpMap.Regions {
+			_, reached := report.RegionLatency[regionID]
+			for _, node := range region.Nodes {
+				if !report.UDP && node.STUNPort >= 0 {
+					port := node.STUNPort
+					if port == 0 {
+						port = 3478
+					}
+					blocked[fmt.Sprintf("udp/%d", port)] = struct{}{}
+				}
+				if !reached && !node.STUNOnly {
+					port := node.DERPPort
+					if port == 0 {
+						port = 443
+						if node.ForceHTTP {
+							port = 80
+						}
+					}
+					blocked[fmt.Sprintf("tcp/%d", port)] = struct{}{}
+				}
+			}
+		}
+	}
+	for port := range blocked {
+		req.BlockedPorts = append(req.BlockedPorts, port)
+	}
+	sort.Strings(req.BlockedPorts)
+	return req
+}
+
+// NetcheckPreflight checks the connectivity of the client to the DERP and STUN
+// servers of the deployment, and reports the result to coderd so connection
+// issues of the user can be correlated with their network.
+func (c *Client) NetcheckPreflight(ctx context.Context, logf tslogger.Logf) (NetcheckReport, error) {
+	connInfo, err := c.WorkspaceAgentConnectionInfoGeneric(ctx)
+	if err != nil {
+		return NetcheckReport{}, xerrors.Errorf("get connection info: %w", err)
+	}
+	req, err := RunNetcheck(ctx, connInfo.DERPMap, logf)
+	if err != nil {
+		return NetcheckReport{}, err
+	}
+	return c.PostNetcheckReport(ctx, req)
+}
+
+// PostNetcheckReport stores a connectivity preflight for the authenticated
+// user.
+func (c *Client) PostNetcheckReport(ctx context.Context, req PostNetcheckReportRequest) (NetcheckReport, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/netcheck", req)
+	if err != nil {
+		return NetcheckReport{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return NetcheckReport{}, ReadBodyAsError(res)
+	}
+	var report NetcheckReport
+	return report, json.NewDecoder(res.Body).Decode(&report)
+}
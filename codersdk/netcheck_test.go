@@ -0,0 +1,80 @@
+package codersdk_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"tailscale.com/net/netcheck"
+	"tailscale.com/tailcfg"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestConvertNetcheckReport(t *testing.T) {
+	t.Parallel()
+
+	derpMap := &tailcfg.DERPMap{
+		Regions: map[int]*tailcfg.DERPRegion{
+			1: {
+				RegionID: 1,
+				Nodes:    []*tailcfg.DERPNode{{Name: "1a"}},
+			},
+			2: {
+				RegionID: 2,
+				Nodes: []*tailcfg.DERPNode{
+					{Name: "2a", DERPPort: 8443, STUNPort: 3479},
+					{Name: "2b", STUNOnly: true},
+				},
+			},
+		},
+	}
+
+	t.Run("HardNAT", func(t *testing.T) {
+		t.Parallel()
+
+		report := codersdk.ConvertNetcheckReport(derpMap, &netcheck.Report{
+			UDP:                   true,
+			IPv4:                  true,
+			MappingVariesByDestIP: "true",
+			PreferredDERP:         1,
+			RegionLatency: map[int]time.Duration{
+				1: 12500 * time.Microsecond,
+			},
+		})
+		require.Equal(t, codersdk.NATTypeHard, report.NATType)
+		require.NotNil(t, report.MappingVariesByDestIP)
+		require.True(t, *report.MappingVariesByDestIP)
+		require.Nil(t, report.HairPinning)
+		require.Equal(t, map[int]float64{1: 12.5}, report.RegionLatencyMS)
+		// Region 2 wasn't reached, so its DERP port is blocked.
+		require.Equal(t, []string{"tcp/8443"}, report.BlockedPorts)
+	})
+
+	t.Run("UDPBlocked", func(t *testing.T) {
+		t.Parallel()
+
+		report := codersdk.ConvertNetcheckReport(derpMap, &netcheck.Report{
+			RegionLatency: map[int]time.Duration{
+				1: 40 * time.Millisecond,
+				2: 30 * time.Millisecond,
+			},
+		})
+		require.Equal(t, codersdk.NATTypeUDPBlocked, report.NATType)
+		require.Equal(t, []string{"udp/3478", "udp/3479"}, report.BlockedPorts)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+
+		report := codersdk.ConvertNetcheckReport(derpMap, &netcheck.Report{
+			UDP: true,
+			RegionLatency: map[int]time.Duration{
+				1: 40 * time.Millisecond,
+				2: 30 * time.Millisecond,
+			},
+		})
+		require.Equal(t, codersdk.NATTypeUnknown, report.NATType)
+		require.Empty(t, report.BlockedPorts)
+	})
+}
@@ -30,6 +30,16 @@ type Organization struct {
 	Name      string    `json:"name" validate:"required"`
 	CreatedAt time.Time `json:"created_at" validate:"required" format:"date-time"`
 	UpdatedAt time.Time `json:"updated_at" validate:"required" format:"date-time"`
+	// MaxConcurrentProvisionerJobs limits how many provisioner jobs of the
+	// organization run at once. Further jobs wait in the queue. 0 means no
+	// limit.
+	MaxConcurrentProvisionerJobs int32 `json:"max_concurrent_provisioner_jobs"`
+}
+
+// UpdateOrganizationRequest updates the settings of an organization. Nil
+// fields are left unchanged.
+type UpdateOrganizationRequest struct {
+	MaxConcurrentProvisionerJobs *int32 `json:"max_concurrent_provisioner_jobs,omitempty"`
 }
 
 type OrganizationMember struct {
@@ -132,6 +142,12 @@ type CreateWorkspaceRequest struct {
 	// ParameterValues allows for additional parameters to be provided
 	// during the initial provision.
 	RichParameterValues []WorkspaceBuildParameter `json:"rich_parameter_values,omitempty"`
+	// AutomaticUpdates defaults to never.
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates,omitempty" enums:"always,never"`
+	// ProvisionerState is an existing Terraform state the first build starts
+	// from, to adopt infrastructure that was created outside of Coder. Only
+	// template managers may provide it.
+	ProvisionerState []byte `json:"state,omitempty"`
 }
 
 func (c *Client) Organization(ctx context.Context, id uuid.UUID) (Organization, error) {
@@ -149,6 +165,22 @@ func (c *Client) Organization(ctx context.Context, id uuid.UUID) (Organization,
 	return organization, json.NewDecoder(res.Body).Decode(&organization)
 }
 
+// UpdateOrganization updates the settings of an organization.
+func (c *Client) UpdateOrganization(ctx context.Context, id uuid.UUID, req UpdateOrganizationRequest) (Organization, error) {
+	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/organizations/%s", id.String()), req)
+	if err != nil {
+		return Organization{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Organization{}, ReadBodyAsError(res)
+	}
+
+	var organization Organization
+	return organization, json.NewDecoder(res.Body).Decode(&organization)
+}
+
 // ProvisionerDaemons returns provisioner daemons available.
 func (c *Client) ProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, error) {
 	res, err := c.Request(ctx, http.MethodGet,
@@ -169,6 +201,50 @@ func (c *Client) ProvisionerDaemons(ctx context.Context) ([]ProvisionerDaemon, e
 	return daemons, json.NewDecoder(res.Body).Decode(&daemons)
 }
 
+// PendingProvisionerJobs returns the provisioner jobs of an organization waiting
+// to be acquired and the provisioner daemons that may acquire each of them.
+func (c *Client) PendingProvisionerJobs(ctx context.Context, organizationID uuid.UUID) ([]PendingProvisionerJob, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerdaemons/pending-jobs", organizationID.String()),
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var jobs []PendingProvisionerJob
+	return jobs, json.NewDecoder(res.Body).Decode(&jobs)
+}
+
+// ProvisionerJobQueue returns the queue depth and wait times of the
+// provisioner jobs of an organization.
+func (c *Client) ProvisionerJobQueue(ctx context.Context, organizationID uuid.UUID, req ProvisionerJobQueueRequest) (ProvisionerJobQueue, error) {
+	opts := []RequestOption{WithQueryParam("provisioner", string(req.Provisioner))}
+	for key, value := range req.Tags {
+		opts = append(opts, WithQueryParam("tag", fmt.Sprintf("%s=%s", key, value)))
+	}
+	res, err := c.Request(ctx, http.MethodGet,
+		fmt.Sprintf("/api/v2/organizations/%s/provisionerdaemons/queue", organizationID.String()),
+		nil, opts...,
+	)
+	if err != nil {
+		return ProvisionerJobQueue{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return ProvisionerJobQueue{}, ReadBodyAsError(res)
+	}
+
+	var queue ProvisionerJobQueue
+	return queue, json.NewDecoder(res.Body).Decode(&queue)
+}
+
 // CreateTemplateVersion processes source-code and optionally associates the version with a template.
 // Executing without a template is useful for validating source-code.
 func (c *Client) CreateTemplateVersion(ctx context.Context, organizationID uuid.UUID, req CreateTemplateVersionRequest) (TemplateVersion, error) {
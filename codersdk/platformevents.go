@@ -0,0 +1,177 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/coder/coder/v2/coderd/tracing"
+)
+
+// PlatformEventType identifies a domain event. The set of types and the shape
+// of their data is stable; new types may be added over time, so consumers
+// should ignore types they don't recognize.
+type PlatformEventType string
+
+const (
+	// User events carry PlatformEventUser data.
+	PlatformEventTypeUserCreated       PlatformEventType = "user.created"
+	PlatformEventTypeUserStatusChanged PlatformEventType = "user.status_changed"
+	PlatformEventTypeUserDeleted       PlatformEventType = "user.deleted"
+	// Workspace events carry PlatformEventWorkspace data.
+	PlatformEventTypeWorkspaceCreated PlatformEventType = "workspace.created"
+	PlatformEventTypeWorkspaceDeleted PlatformEventType = "workspace.deleted"
+	// Workspace build events carry PlatformEventWorkspaceBuild data.
+	PlatformEventTypeWorkspaceBuildStarted   PlatformEventType = "workspace_build.started"
+	PlatformEventTypeWorkspaceBuildSucceeded PlatformEventType = "workspace_build.succeeded"
+	PlatformEventTypeWorkspaceBuildFailed    PlatformEventType = "workspace_build.failed"
+	PlatformEventTypeWorkspaceBuildCanceled  PlatformEventType = "workspace_build.canceled"
+	// Workspace approval events carry PlatformEventWorkspaceApproval data.
+	PlatformEventTypeWorkspaceApprovalRequested PlatformEventType = "workspace_approval.requested"
+	PlatformEventTypeWorkspaceApprovalDecided   PlatformEventType = "workspace_approval.decided"
+	// Template access request events carry PlatformEventTemplateAccessRequest
+	// data.
+	PlatformEventTypeTemplateAccessRequestCreated PlatformEventType = "template_access_request.created"
+	PlatformEventTypeTemplateAccessRequestDecided PlatformEventType = "template_access_request.decided"
+	// Template version promotion events carry
+	// PlatformEventTemplateVersionPromotion data.
+	PlatformEventTypeTemplateVersionPromotionRequested PlatformEventType = "template_version_promotion.requested"
+	PlatformEventTypeTemplateVersionPromotionDecided   PlatformEventType = "template_version_promotion.decided"
+	// Entitlement events carry PlatformEventEntitlements data. Every replica
+	// computes entitlements independently, so in a high availability
+	// deployment the same change may be reported once per replica.
+	PlatformEventTypeEntitlementsChanged PlatformEventType = "entitlements.changed"
+)
+
+// PlatformEvent is a single event from the platform event stream.
+type PlatformEvent struct {
+	// ID is a monotonically increasing cursor. Pass the ID of the last event
+	// processed as "after" to resume the stream.
+	ID         int64             `json:"id"`
+	Type       PlatformEventType `json:"type"`
+	CreatedAt  time.Time         `json:"created_at" format:"date-time"`
+	ResourceID uuid.UUID         `json:"resource_id" format:"uuid"`
+	Data       json.RawMessage   `json:"data" swaggertype:"object"`
+}
+
+type PlatformEventUser struct {
+	ID       uuid.UUID  `json:"id" format:"uuid"`
+	Username string     `json:"username"`
+	Email    string     `json:"email"`
+	Status   UserStatus `json:"status"`
+}
+
+type PlatformEventWorkspace struct {
+	ID             uuid.UUID `json:"id" format:"uuid"`
+	Name           string    `json:"name"`
+	OwnerID        uuid.UUID `json:"owner_id" format:"uuid"`
+	OrganizationID uuid.UUID `json:"organization_id" format:"uuid"`
+	TemplateID     uuid.UUID `json:"template_id" format:"uuid"`
+}
+
+type PlatformEventWorkspaceBuild struct {
+	ID          uuid.UUID           `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID           `json:"workspace_id" format:"uuid"`
+	BuildNumber int32               `json:"build_number"`
+	Transition  WorkspaceTransition `json:"transition" enums:"start,stop,delete"`
+	Reason      BuildReason         `json:"reason"`
+	InitiatorID uuid.UUID           `json:"initiator_id" format:"uuid"`
+	// Error is set for failed builds.
+	Error string `json:"error,omitempty"`
+}
+
+type PlatformEventWorkspaceApproval struct {
+	ID          uuid.UUID               `json:"id" format:"uuid"`
+	WorkspaceID uuid.UUID               `json:"workspace_id" format:"uuid"`
+	TemplateID  uuid.UUID               `json:"template_id" format:"uuid"`
+	RequestedBy uuid.UUID               `json:"requested_by" format:"uuid"`
+	Status      WorkspaceApprovalStatus `json:"status" enums:"pending,approved,rejected"`
+	DecidedBy   *uuid.UUID              `json:"decided_by,omitempty" format:"uuid"`
+	Reason      string                  `json:"reason,omitempty"`
+}
+
+type PlatformEventTemplateAccessRequest struct {
+	ID          uuid.UUID                   `json:"id" format:"uuid"`
+	TemplateID  uuid.UUID                   `json:"template_id" format:"uuid"`
+	RequestedBy uuid.UUID                   `json:"requested_by" format:"uuid"`
+	Status      TemplateAccessRequestStatus `json:"status" enums:"pending,approved,denied"`
+	DecidedBy   *uuid.UUID                  `json:"decided_by,omitempty" format:"uuid"`
+	Reason      string                      `json:"reason,omitempty"`
+}
+
+type PlatformEventTemplateVersionPromotion struct {
+	ID                uuid.UUID                      `json:"id" format:"uuid"`
+	TemplateID        uuid.UUID                      `json:"template_id" format:"uuid"`
+	TemplateVersionID uuid.UUID                      `json:"template_version_id" format:"uuid"`
+	RequestedBy       uuid.UUID                      `json:"requested_by" format:"uuid"`
+	Status            TemplateVersionPromotionStatus `json:"status" enums:"pending,approved,rejected"`
+	DecidedBy         *uuid.UUID                     `json:"decided_by,omitempty" format:"uuid"`
+	Reason            string                         `json:"reason,omitempty"`
+}
+
+type PlatformEventEntitlements struct {
+	HasLicense bool                    `json:"has_license"`
+	Trial      bool                    `json:"trial"`
+	Features   map[FeatureName]Feature `json:"features"`
+}
+
+// PlatformEventsNotifyChannel is the pubsub channel notified whenever a
+// platform event is recorded.
+const PlatformEventsNotifyChannel = "platform_events"
+
+// WatchPlatformEvents streams platform events recorded after the given
+// cursor. A cursor of zero replays every retained event.
+func (c *Client) WatchPlatformEvents(ctx context.Context, after int64) (<-chan PlatformEvent, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	//nolint:bodyclose
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/platform-events?after=%d", after), nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	nextEvent := ServerSentEventReader(ctx, res.Body)
+
+	events := make(chan PlatformEvent, 256)
+	go func() {
+		defer close(events)
+		defer res.Body.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				sse, err := nextEvent()
+				if err != nil {
+					return
+				}
+				if sse.Type != ServerSentEventTypeData {
+					continue
+				}
+				var event PlatformEvent
+				b, ok := sse.Data.([]byte)
+				if !ok {
+					return
+				}
+				err = json.Unmarshal(b, &event)
+				if err != nil {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case events <- event:
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,143 @@
+package codersdk
+
+import (
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+type PortPolicyAction string
+
+const (
+	PortPolicyActionAllow PortPolicyAction = "allow"
+	PortPolicyActionDeny  PortPolicyAction = "deny"
+)
+
+// PortPolicy restricts which ports of workspace agents can be forwarded,
+// and how far apps served from them can be shared. An allowlist denies by
+// default and allows ports with rules, a denylist does the opposite.
+//
+// The zero value allows all ports.
+type PortPolicy struct {
+	// Rules are matched in order. The first rule that contains a port
+	// decides whether it is allowed.
+	Rules []PortPolicyRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+	// Default is the action for ports that no rule contains. Defaults to
+	// "allow".
+	Default PortPolicyAction `json:"default,omitempty" yaml:"default,omitempty" enums:"allow,deny"`
+}
+
+type PortPolicyRule struct {
+	// Ports is a single port like "8080" or an inclusive range like
+	// "8000-8999".
+	Ports  string           `json:"ports" yaml:"ports"`
+	Action PortPolicyAction `json:"action" yaml:"action" enums:"allow,deny"`
+	// MaxSharingLevel caps the sharing level of apps served from allowed
+	// ports of the rule. Defaults to no cap.
+	MaxSharingLevel WorkspaceAppSharingLevel `json:"max_sharing_level,omitempty" yaml:"max_sharing_level,omitempty" enums:"owner,authenticated,public"`
+}
+
+// Validate returns an error describing the first invalid rule of the
+// policy.
+func (p PortPolicy) Validate() error {
+	switch p.Default {
+	case "", PortPolicyActionAllow, PortPolicyActionDeny:
+	default:
+		return xerrors.Errorf("unknown default action %q", p.Default)
+	}
+	for i, rule := range p.Rules {
+		if _, _, err := rule.portRange(); err != nil {
+			return xerrors.Errorf("rule %d: %w", i, err)
+		}
+		switch rule.Action {
+		case PortPolicyActionAllow, PortPolicyActionDeny:
+		default:
+			return xerrors.Errorf("rule %d: unknown action %q", i, rule.Action)
+		}
+		if rule.MaxSharingLevel == "" {
+			continue
+		}
+		if rule.Action == PortPolicyActionDeny {
+			return xerrors.Errorf("rule %d: denied ports can't have a max sharing level", i)
+		}
+		if sharingLevelRank(rule.MaxSharingLevel) < 0 {
+			return xerrors.Errorf("rule %d: unknown sharing level %q", i, rule.MaxSharingLevel)
+		}
+	}
+	return nil
+}
+
+// Check returns whether the port is allowed, and the most permissive
+// sharing level of apps served from it.
+func (p PortPolicy) Check(port uint16) (bool, WorkspaceAppSharingLevel) {
+	for _, rule := range p.Rules {
+		low, high, err := rule.portRange()
+		if err != nil || port < low || port > high {
+			continue
+		}
+		if rule.Action != PortPolicyActionAllow {
+			return false, ""
+		}
+		if rule.MaxSharingLevel == "" {
+			return true, WorkspaceAppSharingLevelPublic
+		}
+		return true, rule.MaxSharingLevel
+	}
+	if p.Default == PortPolicyActionDeny {
+		return false, ""
+	}
+	return true, WorkspaceAppSharingLevelPublic
+}
+
+func (r PortPolicyRule) portRange() (uint16, uint16, error) {
+	lowStr, highStr, isRange := strings.Cut(r.Ports, "-")
+	low, err := strconv.ParseUint(strings.TrimSpace(lowStr), 10, 16)
+	if err != nil {
+		return 0, 0, xerrors.Errorf("invalid ports %q", r.Ports)
+	}
+	if !isRange {
+		return uint16(low), uint16(low), nil
+	}
+	high, err := strconv.ParseUint(strings.TrimSpace(highStr), 10, 16)
+	if err != nil || high < low {
+		return 0, 0, xerrors.Errorf("invalid ports %q", r.Ports)
+	}
+	return uint16(low), uint16(high), nil
+}
+
+// CheckPortPolicies returns whether the port is allowed by all policies,
+// and the most permissive sharing level of apps served from it that all of
+// them allow.
+func CheckPortPolicies(port uint16, policies ...PortPolicy) (bool, WorkspaceAppSharingLevel) {
+	maxSharingLevel := WorkspaceAppSharingLevelPublic
+	for _, policy := range policies {
+		allowed, sharingLevel := policy.Check(port)
+		if !allowed {
+			return false, ""
+		}
+		maxSharingLevel = MinSharingLevel(maxSharingLevel, sharingLevel)
+	}
+	return true, maxSharingLevel
+}
+
+// MinSharingLevel returns the more restrictive of the sharing levels.
+func MinSharingLevel(a, b WorkspaceAppSharingLevel) WorkspaceAppSharingLevel {
+	if sharingLevelRank(b) < sharingLevelRank(a) {
+		return b
+	}
+	return a
+}
+
+func sharingLevelRank(level WorkspaceAppSharingLevel) int {
+	switch level {
+	case WorkspaceAppSharingLevelOwner:
+		return 0
+	case WorkspaceAppSharingLevelAuthenticated:
+		return 1
+	case WorkspaceAppSharingLevelPublic:
+		return 2
+	default:
+		return -1
+	}
+}
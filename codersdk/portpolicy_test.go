@@ -0,0 +1,167 @@
+package codersdk_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestPortPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		Name   string
+		Policy codersdk.PortPolicy
+		Error  string
+	}{{
+		Name: "Empty",
+	}, {
+		Name: "Valid",
+		Policy: codersdk.PortPolicy{
+			Default: codersdk.PortPolicyActionDeny,
+			Rules: []codersdk.PortPolicyRule{{
+				Ports:  "22",
+				Action: codersdk.PortPolicyActionDeny,
+			}, {
+				Ports:           "8000-8999",
+				Action:          codersdk.PortPolicyActionAllow,
+				MaxSharingLevel: codersdk.WorkspaceAppSharingLevelAuthenticated,
+			}},
+		},
+	}, {
+		Name:   "UnknownDefault",
+		Policy: codersdk.PortPolicy{Default: "block"},
+		Error:  `unknown default action "block"`,
+	}, {
+		Name: "InvalidPort",
+		Policy: codersdk.PortPolicy{Rules: []codersdk.PortPolicyRule{{
+			Ports:  "70000",
+			Action: codersdk.PortPolicyActionAllow,
+		}}},
+		Error: `rule 0: invalid ports "70000"`,
+	}, {
+		Name: "ReversedRange",
+		Policy: codersdk.PortPolicy{Rules: []codersdk.PortPolicyRule{{
+			Ports:  "9000-8000",
+			Action: codersdk.PortPolicyActionAllow,
+		}}},
+		Error: `rule 0: invalid ports "9000-8000"`,
+	}, {
+		Name: "MissingAction",
+		Policy: codersdk.PortPolicy{Rules: []codersdk.PortPolicyRule{{
+			Ports: "8080",
+		}}},
+		Error: `rule 0: unknown action ""`,
+	}, {
+		Name: "DeniedSharingLevel",
+		Policy: codersdk.PortPolicy{Rules: []codersdk.PortPolicyRule{{
+			Ports:           "8080",
+			Action:          codersdk.PortPolicyActionDeny,
+			MaxSharingLevel: codersdk.WorkspaceAppSharingLevelOwner,
+		}}},
+		Error: "rule 0: denied ports can't have a max sharing level",
+	}, {
+		Name: "UnknownSharingLevel",
+		Policy: codersdk.PortPolicy{Rules: []codersdk.PortPolicyRule{{
+			Ports:           "8080",
+			Action:          codersdk.PortPolicyActionAllow,
+			MaxSharingLevel: "everyone",
+		}}},
+		Error: `rule 0: unknown sharing level "everyone"`,
+	}} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			err := tc.Policy.Validate()
+			if tc.Error == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.Error)
+		})
+	}
+}
+
+func TestCheckPortPolicies(t *testing.T) {
+	t.Parallel()
+
+	deployment := codersdk.PortPolicy{
+		Rules: []codersdk.PortPolicyRule{{
+			Ports:  "22",
+			Action: codersdk.PortPolicyActionDeny,
+		}, {
+			Ports:           "3000-3999",
+			Action:          codersdk.PortPolicyActionAllow,
+			MaxSharingLevel: codersdk.WorkspaceAppSharingLevelAuthenticated,
+		}},
+	}
+	template := codersdk.PortPolicy{
+		Default: codersdk.PortPolicyActionDeny,
+		Rules: []codersdk.PortPolicyRule{{
+			Ports:  "22",
+			Action: codersdk.PortPolicyActionAllow,
+		}, {
+			Ports:           "3000",
+			Action:          codersdk.PortPolicyActionAllow,
+			MaxSharingLevel: codersdk.WorkspaceAppSharingLevelOwner,
+		}, {
+			Ports:  "3000-8080",
+			Action: codersdk.PortPolicyActionAllow,
+		}},
+	}
+
+	for _, tc := range []struct {
+		Name         string
+		Port         uint16
+		Policies     []codersdk.PortPolicy
+		Allowed      bool
+		SharingLevel codersdk.WorkspaceAppSharingLevel
+	}{{
+		Name:         "NoPolicies",
+		Port:         22,
+		Allowed:      true,
+		SharingLevel: codersdk.WorkspaceAppSharingLevelPublic,
+	}, {
+		Name:         "EmptyPolicy",
+		Port:         22,
+		Policies:     []codersdk.PortPolicy{{}},
+		Allowed:      true,
+		SharingLevel: codersdk.WorkspaceAppSharingLevelPublic,
+	}, {
+		Name:     "DeniedByDeployment",
+		Port:     22,
+		Policies: []codersdk.PortPolicy{deployment, template},
+	}, {
+		Name:     "DeniedByTemplateDefault",
+		Port:     9000,
+		Policies: []codersdk.PortPolicy{deployment, template},
+	}, {
+		Name:         "FirstRuleWins",
+		Port:         3000,
+		Policies:     []codersdk.PortPolicy{template},
+		Allowed:      true,
+		SharingLevel: codersdk.WorkspaceAppSharingLevelOwner,
+	}, {
+		Name:         "MostRestrictiveSharingLevel",
+		Port:         3001,
+		Policies:     []codersdk.PortPolicy{deployment, template},
+		Allowed:      true,
+		SharingLevel: codersdk.WorkspaceAppSharingLevelAuthenticated,
+	}, {
+		Name:         "AllowedByDeploymentDefault",
+		Port:         8080,
+		Policies:     []codersdk.PortPolicy{deployment, template},
+		Allowed:      true,
+		SharingLevel: codersdk.WorkspaceAppSharingLevelPublic,
+	}} {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			allowed, sharingLevel := codersdk.CheckPortPolicies(tc.Port, tc.Policies...)
+			require.Equal(t, tc.Allowed, allowed)
+			require.Equal(t, tc.SharingLevel, sharingLevel)
+		})
+	}
+}
@@ -44,6 +44,52 @@ type ProvisionerDaemon struct {
 	Tags         map[string]string `json:"tags"`
 }
 
+// PendingProvisionerJob is a provisioner job waiting to be acquired, along
+// with the provisioner daemons whose tags satisfy it.
+type PendingProvisionerJob struct {
+	ID          uuid.UUID         `json:"id" format:"uuid"`
+	CreatedAt   time.Time         `json:"created_at" format:"date-time"`
+	Provisioner ProvisionerType   `json:"provisioner" enums:"echo,terraform"`
+	Tags        map[string]string `json:"tags"`
+	// MatchingDaemons are the provisioner daemons that may acquire the job.
+	// If it's empty, the job won't start until a daemon with matching tags
+	// connects.
+	MatchingDaemons []ProvisionerDaemon `json:"matching_daemons"`
+}
+
+// ProvisionerJobQueueRequest selects the provisioner jobs to include in the
+// queue stats. Empty fields match every job.
+type ProvisionerJobQueueRequest struct {
+	Provisioner ProvisionerType `json:"provisioner,omitempty"`
+	// Tags are the tags of a pool of provisioner daemons. Only jobs daemons
+	// with these tags may acquire are included.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// ProvisionerJobQueue summarizes the provisioner jobs waiting for a
+// provisioner daemon. It's meant to drive autoscaling of external
+// provisioner daemons.
+type ProvisionerJobQueue struct {
+	// Depth is the number of jobs waiting to be acquired.
+	Depth int64 `json:"depth"`
+	// OldestJobAgeSeconds is how long the oldest pending job has been waiting.
+	OldestJobAgeSeconds float64 `json:"oldest_job_age_seconds"`
+	// JobAgeSeconds are percentiles of how long the pending jobs have been
+	// waiting.
+	JobAgeSeconds ProvisionerJobQueuePercentiles `json:"job_age_seconds"`
+	// AverageWaitSeconds is the average time the jobs started within the
+	// wait window waited to be acquired.
+	AverageWaitSeconds float64 `json:"average_wait_seconds"`
+	StartedJobs        int64   `json:"started_jobs"`
+	WaitWindowSeconds  float64 `json:"wait_window_seconds"`
+}
+
+type ProvisionerJobQueuePercentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+}
+
 // ProvisionerJobStatus represents the at-time state of a job.
 type ProvisionerJobStatus string
 
@@ -71,6 +117,33 @@ type JobErrorCode string
 const (
 	MissingTemplateParameter  JobErrorCode = "MISSING_TEMPLATE_PARAMETER"
 	RequiredTemplateVariables JobErrorCode = "REQUIRED_TEMPLATE_VARIABLES"
+	BuildTimeout              JobErrorCode = "BUILD_TIMEOUT"
+	ResourceLimitExceeded     JobErrorCode = "RESOURCE_LIMIT_EXCEEDED"
+)
+
+// ProvisionerJobCancelOutcome is how a canceled job stopped.
+type ProvisionerJobCancelOutcome string
+
+const (
+	// ProvisionerJobCancelOutcomeGraceful means the provisioner stopped at a
+	// safe point.
+	ProvisionerJobCancelOutcomeGraceful ProvisionerJobCancelOutcome = "graceful"
+	// ProvisionerJobCancelOutcomeForced means the provisioner was killed after
+	// the force cancel interval. The state captured until then is kept.
+	ProvisionerJobCancelOutcomeForced ProvisionerJobCancelOutcome = "forced"
+)
+
+// ProvisionerJobQueueLimit is the concurrency limit that holds back a pending
+// job.
+type ProvisionerJobQueueLimit string
+
+const (
+	// ProvisionerJobQueueLimitTemplate means the template of the job already
+	// runs as many jobs as it allows.
+	ProvisionerJobQueueLimitTemplate ProvisionerJobQueueLimit = "template"
+	// ProvisionerJobQueueLimitOrganization means the organization of the job
+	// already runs as many jobs as it allows.
+	ProvisionerJobQueueLimitOrganization ProvisionerJobQueueLimit = "organization"
 )
 
 // ProvisionerJob describes the job executed by the provisioning daemon.
@@ -81,13 +154,18 @@ type ProvisionerJob struct {
 	CompletedAt   *time.Time           `json:"completed_at,omitempty" format:"date-time"`
 	CanceledAt    *time.Time           `json:"canceled_at,omitempty" format:"date-time"`
 	Error         string               `json:"error,omitempty"`
-	ErrorCode     JobErrorCode         `json:"error_code,omitempty" enums:"MISSING_TEMPLATE_PARAMETER,REQUIRED_TEMPLATE_VARIABLES"`
+	ErrorCode     JobErrorCode         `json:"error_code,omitempty" enums:"MISSING_TEMPLATE_PARAMETER,REQUIRED_TEMPLATE_VARIABLES,BUILD_TIMEOUT,RESOURCE_LIMIT_EXCEEDED"`
 	Status        ProvisionerJobStatus `json:"status" enums:"pending,running,succeeded,canceling,canceled,failed"`
 	WorkerID      *uuid.UUID           `json:"worker_id,omitempty" format:"uuid"`
 	FileID        uuid.UUID            `json:"file_id" format:"uuid"`
 	Tags          map[string]string    `json:"tags"`
 	QueuePosition int                  `json:"queue_position"`
 	QueueSize     int                  `json:"queue_size"`
+	// QueueLimitedBy is set while a pending job waits for a concurrency limit
+	// of its template or organization.
+	QueueLimitedBy ProvisionerJobQueueLimit `json:"queue_limited_by,omitempty" enums:"template,organization"`
+	// CancelOutcome is set once a canceled job has stopped.
+	CancelOutcome ProvisionerJobCancelOutcome `json:"cancel_outcome,omitempty" enums:"graceful,forced"`
 }
 
 // ProvisionerJobLog represents the provisioner log entry annotated with source and level.
@@ -0,0 +1,42 @@
+package codersdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type UpdateUserPTYSessionOverrideRequest struct {
+	// MaxPTYSessions is the maximum number of concurrent web terminal
+	// sessions of the user. 0 means the user has no limit.
+	MaxPTYSessions int `json:"max_pty_sessions" validate:"min=0"`
+}
+
+// UpdateUserPTYSessionOverride sets a limit of concurrent web terminal
+// sessions for the user that takes precedence over the limit of the
+// deployment.
+func (c *Client) UpdateUserPTYSessionOverride(ctx context.Context, user string, req UpdateUserPTYSessionOverrideRequest) error {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/users/%s/pty-session-override", user), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// DeleteUserPTYSessionOverride removes the web terminal session limit
+// override of the user.
+func (c *Client) DeleteUserPTYSessionOverride(ctx context.Context, user string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/users/%s/pty-session-override", user), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ const (
 	ResourceWorkspaceApplicationConnect RBACResource = "application_connect"
 	ResourceAuditLog                    RBACResource = "audit_log"
 	ResourceTemplate                    RBACResource = "template"
+	ResourceTemplateVersionPromotion    RBACResource = "template_version_promotion"
 	ResourceGroup                       RBACResource = "group"
 	ResourceFile                        RBACResource = "file"
 	ResourceProvisionerDaemon           RBACResource = "provisioner_daemon"
@@ -24,6 +25,7 @@ const (
 	ResourceDeploymentStats             RBACResource = "deployment_stats"
 	ResourceReplicas                    RBACResource = "replicas"
 	ResourceDebugInfo                   RBACResource = "debug_info"
+	ResourcePlatformEvent               RBACResource = "platform_event"
 	ResourceSystem                      RBACResource = "system"
 )
 
@@ -42,6 +44,7 @@ var (
 		ResourceWorkspaceApplicationConnect,
 		ResourceAuditLog,
 		ResourceTemplate,
+		ResourceTemplateVersionPromotion,
 		ResourceGroup,
 		ResourceFile,
 		ResourceProvisionerDaemon,
@@ -57,6 +60,7 @@ var (
 		ResourceDeploymentStats,
 		ResourceReplicas,
 		ResourceDebugInfo,
+		ResourcePlatformEvent,
 		ResourceSystem,
 	}
 
@@ -3,6 +3,7 @@ package codersdk
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -25,6 +26,9 @@ type Replica struct {
 	Error string `json:"error"`
 	// DatabaseLatency is the latency in microseconds to the database.
 	DatabaseLatency int32 `json:"database_latency"`
+	// DrainingAt is when the replica started draining. A draining replica
+	// doesn't accept new tailnet connections or provisioner jobs.
+	DrainingAt *time.Time `json:"draining_at,omitempty" format:"date-time"`
 }
 
 // Replicas fetches the list of replicas.
@@ -42,3 +46,21 @@ func (c *Client) Replicas(ctx context.Context) ([]Replica, error) {
 	var replicas []Replica
 	return replicas, json.NewDecoder(res.Body).Decode(&replicas)
 }
+
+// DrainReplica marks a replica as draining. It stops accepting new tailnet
+// connections and provisioner jobs, and can be stopped once existing
+// connections finish.
+func (c *Client) DrainReplica(ctx context.Context, id uuid.UUID) (Replica, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/replicas/%s/drain", id), nil)
+	if err != nil {
+		return Replica{}, xerrors.Errorf("execute request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Replica{}, ReadBodyAsError(res)
+	}
+
+	var replica Replica
+	return replica, json.NewDecoder(res.Body).Decode(&replica)
+}
@@ -2,6 +2,7 @@ package codersdk
 
 import (
 	"strconv"
+	"strings"
 
 	"golang.org/x/xerrors"
 
@@ -143,6 +144,105 @@ func parameterValuesAsArray(options []TemplateVersionParameterOption) []string {
 	return arr
 }
 
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateParameterDependencies checks that the dependencies of the template
+// version parameters reference other parameters of the same template version,
+// only refer to values and options these parameters offer, and don't form
+// cycles.
+func ValidateParameterDependencies(params []TemplateVersionParameter) error {
+	byName := make(map[string]TemplateVersionParameter, len(params))
+	for _, p := range params {
+		byName[p.Name] = p
+	}
+	for _, p := range params {
+		for _, dep := range p.Dependencies {
+			parent, ok := byName[dep.Parameter]
+			if !ok {
+				return xerrors.Errorf("parameter %q depends on unknown parameter %q", p.Name, dep.Parameter)
+			}
+			if len(parent.Options) > 0 {
+				parentValues := parameterValuesAsArray(parent.Options)
+				for _, v := range dep.Values {
+					if !containsValue(parentValues, v) {
+						return xerrors.Errorf("parameter %q depends on value %q which is not an option of parameter %q", p.Name, v, parent.Name)
+					}
+				}
+			}
+			// Dynamic options are only known when the parameter is resolved.
+			if len(dep.Options) > 0 && !p.DynamicOptions {
+				if len(p.Options) == 0 {
+					return xerrors.Errorf("parameter %q restricts options in a dependency but doesn't have any options", p.Name)
+				}
+				values := parameterValuesAsArray(p.Options)
+				for _, o := range dep.Options {
+					if !containsValue(values, o) {
+						return xerrors.Errorf("parameter %q restricts its options to %q which is not one of its options", p.Name, o)
+					}
+				}
+			}
+		}
+	}
+	_, err := SortParametersByDependencies(params)
+	return err
+}
+
+// SortParametersByDependencies returns the parameters ordered so that every
+// parameter comes after the parameters it depends on. Otherwise, parameters
+// keep their relative order.
+func SortParametersByDependencies(params []TemplateVersionParameter) ([]TemplateVersionParameter, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	index := make(map[string]int, len(params))
+	for i, p := range params {
+		index[p.Name] = i
+	}
+	state := make([]int, len(params))
+	sorted := make([]TemplateVersionParameter, 0, len(params))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			return xerrors.Errorf("parameter %q has cyclic dependencies", params[i].Name)
+		}
+		state[i] = visiting
+		for _, dep := range params[i].Dependencies {
+			j, ok := index[dep.Parameter]
+			if !ok {
+				return xerrors.Errorf("parameter %q depends on unknown parameter %q", params[i].Name, dep.Parameter)
+			}
+			err := visit(j)
+			if err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		sorted = append(sorted, params[i])
+		return nil
+	}
+	for i := range params {
+		err := visit(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
+
 func validationEnabled(param TemplateVersionParameter) bool {
 	return len(param.ValidationRegex) > 0 ||
 		param.ValidationMin != nil ||
@@ -158,11 +258,26 @@ func validationEnabled(param TemplateVersionParameter) bool {
 // @typescript-ignore ParameterResolver
 type ParameterResolver struct {
 	Rich []WorkspaceBuildParameter
+
+	// applicable holds the values of the applicable parameters resolved so
+	// far, which the dependencies of later parameters are matched against.
+	applicable map[string]string
 }
 
 // ValidateResolve checks the provided value, v, against the parameter, p, and the previous build.  If v is nil, it also
 // resolves the correct value.  It returns the value of the parameter, if valid, and an error if invalid.
+//
+// Parameters with dependencies must be resolved after the parameters they depend on, see SortParametersByDependencies.
+// A parameter none of whose dependencies match doesn't apply to the build and always resolves to its default value.
 func (r *ParameterResolver) ValidateResolve(p TemplateVersionParameter, v *WorkspaceBuildParameter) (value string, err error) {
+	dep, applicable := r.matchDependency(p)
+	if !applicable {
+		if v != nil && v.Value != p.DefaultValue {
+			return "", xerrors.Errorf("Parameter %q doesn't apply to the selected values of %s.", p.Name, dependencyNames(p))
+		}
+		return p.DefaultValue, nil
+	}
+
 	prevV := r.findLastValue(p)
 	if !p.Mutable && v != nil && prevV != nil {
 		return "", xerrors.Errorf("Parameter %q is not mutable, so it can't be updated after creating a workspace.", p.Name)
@@ -187,9 +302,47 @@ func (r *ParameterResolver) ValidateResolve(p TemplateVersionParameter, v *Works
 	if err != nil {
 		return "", err
 	}
+	if dep != nil && len(dep.Options) > 0 && !containsValue(dep.Options, resolvedValue.Value) {
+		return "", xerrors.Errorf("Parameter %q must match one of options: %s", p.Name, dep.Options)
+	}
+	if r.applicable == nil {
+		r.applicable = make(map[string]string)
+	}
+	r.applicable[p.Name] = resolvedValue.Value
 	return resolvedValue.Value, nil
 }
 
+// matchDependency returns the first dependency of p that matches the values of the parameters resolved so far. It
+// returns false if p has dependencies, but none of them match.
+func (r *ParameterResolver) matchDependency(p TemplateVersionParameter) (*TemplateVersionParameterDependency, bool) {
+	if len(p.Dependencies) == 0 {
+		return nil, true
+	}
+	for i, dep := range p.Dependencies {
+		// Parameters that don't apply to the build make their dependents
+		// inapplicable too.
+		value, ok := r.applicable[dep.Parameter]
+		if !ok {
+			continue
+		}
+		if len(dep.Values) == 0 || containsValue(dep.Values, value) {
+			return &p.Dependencies[i], true
+		}
+	}
+	return nil, false
+}
+
+func dependencyNames(p TemplateVersionParameter) string {
+	var names []string
+	for _, dep := range p.Dependencies {
+		name := strconv.Quote(dep.Parameter)
+		if !containsValue(names, name) {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
 // findLastValue finds the value from the previous build and returns it, or nil if the parameter had no value in the
 // last build.
 func (r *ParameterResolver) findLastValue(p TemplateVersionParameter) *WorkspaceBuildParameter {
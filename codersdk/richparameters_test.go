@@ -376,3 +376,200 @@ func TestParameterResolver_ValidateResolve_Ephemeral_UseEmptyDefault(t *testing.
 	require.NoError(t, err)
 	require.Equal(t, "", v)
 }
+
+func TestParameterResolver_ValidateResolve_Dependencies(t *testing.T) {
+	t.Parallel()
+
+	region := codersdk.TemplateVersionParameter{
+		Name:         "region",
+		Type:         "string",
+		Mutable:      true,
+		DefaultValue: "us",
+		Options: []codersdk.TemplateVersionParameterOption{
+			{Name: "US", Value: "us"},
+			{Name: "EU", Value: "eu"},
+			{Name: "Local", Value: "local"},
+		},
+	}
+	zone := codersdk.TemplateVersionParameter{
+		Name:         "zone",
+		Type:         "string",
+		Mutable:      true,
+		DefaultValue: "us-east",
+		Options: []codersdk.TemplateVersionParameterOption{
+			{Name: "US East", Value: "us-east"},
+			{Name: "US West", Value: "us-west"},
+			{Name: "EU West", Value: "eu-west"},
+		},
+		Dependencies: []codersdk.TemplateVersionParameterDependency{
+			{Parameter: "region", Values: []string{"us"}, Options: []string{"us-east", "us-west"}},
+			{Parameter: "region", Values: []string{"eu"}, Options: []string{"eu-west"}},
+		},
+	}
+	disk := codersdk.TemplateVersionParameter{
+		Name:         "disk",
+		Type:         "number",
+		Mutable:      true,
+		DefaultValue: "10",
+		Dependencies: []codersdk.TemplateVersionParameterDependency{
+			{Parameter: "zone"},
+		},
+	}
+
+	resolve := func(regionValue string, values ...codersdk.WorkspaceBuildParameter) ([]string, error) {
+		uut := codersdk.ParameterResolver{}
+		var resolved []string
+		for _, p := range []codersdk.TemplateVersionParameter{region, zone, disk} {
+			var v *codersdk.WorkspaceBuildParameter
+			if p.Name == region.Name {
+				v = &codersdk.WorkspaceBuildParameter{Name: p.Name, Value: regionValue}
+			}
+			for i := range values {
+				if values[i].Name == p.Name {
+					v = &values[i]
+				}
+			}
+			value, err := uut.ValidateResolve(p, v)
+			if err != nil {
+				return nil, err
+			}
+			resolved = append(resolved, value)
+		}
+		return resolved, nil
+	}
+
+	t.Run("MatchingOption", func(t *testing.T) {
+		t.Parallel()
+		v, err := resolve("eu", codersdk.WorkspaceBuildParameter{Name: "zone", Value: "eu-west"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"eu", "eu-west", "10"}, v)
+	})
+
+	t.Run("OptionOfOtherDependency", func(t *testing.T) {
+		t.Parallel()
+		_, err := resolve("eu", codersdk.WorkspaceBuildParameter{Name: "zone", Value: "us-west"})
+		require.Error(t, err)
+	})
+
+	t.Run("DefaultNotAllowed", func(t *testing.T) {
+		t.Parallel()
+		// The default value of zone is not one of the options allowed in the EU.
+		_, err := resolve("eu")
+		require.Error(t, err)
+	})
+
+	t.Run("NotApplicable", func(t *testing.T) {
+		t.Parallel()
+		// Neither zone nor disk, which depends on zone, apply to the local
+		// region, so they resolve to their defaults.
+		v, err := resolve("local")
+		require.NoError(t, err)
+		require.Equal(t, []string{"local", "us-east", "10"}, v)
+	})
+
+	t.Run("NotApplicableValueProvided", func(t *testing.T) {
+		t.Parallel()
+		_, err := resolve("local", codersdk.WorkspaceBuildParameter{Name: "disk", Value: "20"})
+		require.Error(t, err)
+	})
+}
+
+func TestValidateParameterDependencies(t *testing.T) {
+	t.Parallel()
+
+	options := []codersdk.TemplateVersionParameterOption{
+		{Name: "A", Value: "a"},
+		{Name: "B", Value: "b"},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		params []codersdk.TemplateVersionParameter
+		valid  bool
+	}{
+		{
+			name: "Valid",
+			params: []codersdk.TemplateVersionParameter{
+				{Name: "second", Options: options, Dependencies: []codersdk.TemplateVersionParameterDependency{
+					{Parameter: "first", Values: []string{"a"}, Options: []string{"b"}},
+				}},
+				{Name: "first", Options: options},
+			},
+			valid: true,
+		},
+		{
+			name: "UnknownParameter",
+			params: []codersdk.TemplateVersionParameter{
+				{Name: "first", Dependencies: []codersdk.TemplateVersionParameterDependency{
+					{Parameter: "unknown"},
+				}},
+			},
+		},
+		{
+			name: "UnknownValue",
+			params: []codersdk.TemplateVersionParameter{
+				{Name: "first", Options: options},
+				{Name: "second", Dependencies: []codersdk.TemplateVersionParameterDependency{
+					{Parameter: "first", Values: []string{"c"}},
+				}},
+			},
+		},
+		{
+			name: "UnknownOption",
+			params: []codersdk.TemplateVersionParameter{
+				{Name: "first"},
+				{Name: "second", Options: options, Dependencies: []codersdk.TemplateVersionParameterDependency{
+					{Parameter: "first", Options: []string{"c"}},
+				}},
+			},
+		},
+		{
+			name: "NoOptions",
+			params: []codersdk.TemplateVersionParameter{
+				{Name: "first"},
+				{Name: "second", Dependencies: []codersdk.TemplateVersionParameterDependency{
+					{Parameter: "first", Options: []string{"a"}},
+				}},
+			},
+		},
+		{
+			name: "Cycle",
+			params: []codersdk.TemplateVersionParameter{
+				{Name: "first", Dependencies: []codersdk.TemplateVersionParameterDependency{
+					{Parameter: "second"},
+				}},
+				{Name: "second", Dependencies: []codersdk.TemplateVersionParameterDependency{
+					{Parameter: "first"},
+				}},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := codersdk.ValidateParameterDependencies(tc.params)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSortParametersByDependencies(t *testing.T) {
+	t.Parallel()
+
+	sorted, err := codersdk.SortParametersByDependencies([]codersdk.TemplateVersionParameter{
+		{Name: "c", Dependencies: []codersdk.TemplateVersionParameterDependency{{Parameter: "b"}}},
+		{Name: "a"},
+		{Name: "b", Dependencies: []codersdk.TemplateVersionParameterDependency{{Parameter: "a"}}},
+		{Name: "d"},
+	})
+	require.NoError(t, err)
+	var names []string
+	for _, p := range sorted {
+		names = append(names, p.Name)
+	}
+	require.Equal(t, []string{"a", "b", "c", "d"}, names)
+}
@@ -0,0 +1,66 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SSHCertificateAuthority is the certificate authority that signs SSH
+// certificates of users and workspace agents.
+type SSHCertificateAuthority struct {
+	// PublicKey is in the authorized_keys format, so it can be used for
+	// TrustedUserCAKeys of sshd and @cert-authority entries in known_hosts.
+	PublicKey string `json:"public_key"`
+}
+
+type CreateSSHCertificateRequest struct {
+	// PublicKey is the SSH public key to sign in the authorized_keys format.
+	PublicKey string `json:"public_key" validate:"required"`
+}
+
+// SSHCertificate is a short-lived SSH certificate of a user.
+type SSHCertificate struct {
+	// Certificate is in the authorized_keys format, ssh picks it up when it's
+	// stored next to the private key with the "-cert.pub" suffix.
+	Certificate string    `json:"certificate"`
+	Principals  []string  `json:"principals"`
+	ValidAfter  time.Time `json:"valid_after" format:"date-time"`
+	ValidBefore time.Time `json:"valid_before" format:"date-time"`
+}
+
+// SSHCertificateAuthority returns the certificate authority of the
+// deployment. It doesn't require authentication.
+func (c *Client) SSHCertificateAuthority(ctx context.Context) (SSHCertificateAuthority, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/deployment/ssh/ca", nil)
+	if err != nil {
+		return SSHCertificateAuthority{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return SSHCertificateAuthority{}, ReadBodyAsError(res)
+	}
+
+	var ca SSHCertificateAuthority
+	return ca, json.NewDecoder(res.Body).Decode(&ca)
+}
+
+// CreateSSHCertificate signs the public key for the user. Servers trusting the
+// certificate authority accept it for the username of the user.
+func (c *Client) CreateSSHCertificate(ctx context.Context, user string, req CreateSSHCertificateRequest) (SSHCertificate, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/users/%s/ssh/certificate", user), req)
+	if err != nil {
+		return SSHCertificate{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return SSHCertificate{}, ReadBodyAsError(res)
+	}
+
+	var cert SSHCertificate
+	return cert, json.NewDecoder(res.Body).Decode(&cert)
+}
@@ -0,0 +1,95 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TemplateAccessRequestStatus string
+
+const (
+	TemplateAccessRequestStatusPending  TemplateAccessRequestStatus = "pending"
+	TemplateAccessRequestStatusApproved TemplateAccessRequestStatus = "approved"
+	TemplateAccessRequestStatusDenied   TemplateAccessRequestStatus = "denied"
+)
+
+// TemplateAccessRequest is a request of a user to use a template they don't
+// have access to. Approving the request grants the user the "use" role on the
+// template.
+type TemplateAccessRequest struct {
+	ID          uuid.UUID                   `json:"id" format:"uuid"`
+	TemplateID  uuid.UUID                   `json:"template_id" format:"uuid"`
+	RequestedBy uuid.UUID                   `json:"requested_by" format:"uuid"`
+	CreatedAt   time.Time                   `json:"created_at" format:"date-time"`
+	Message     string                      `json:"message,omitempty"`
+	Status      TemplateAccessRequestStatus `json:"status" enums:"pending,approved,denied"`
+	DecidedBy   *uuid.UUID                  `json:"decided_by,omitempty" format:"uuid"`
+	DecidedAt   *time.Time                  `json:"decided_at,omitempty" format:"date-time"`
+	Reason      string                      `json:"reason,omitempty"`
+}
+
+type CreateTemplateAccessRequest struct {
+	// Message is an optional explanation of why access is needed.
+	Message string `json:"message,omitempty"`
+}
+
+type DecideTemplateAccessRequest struct {
+	Status TemplateAccessRequestStatus `json:"status" validate:"required,oneof=approved denied" enums:"approved,denied"`
+	// Reason is an optional explanation of the decision.
+	Reason string `json:"reason,omitempty"`
+}
+
+// RequestTemplateAccess asks the admins of a template for permission to use
+// it.
+func (c *Client) RequestTemplateAccess(ctx context.Context, templateID uuid.UUID, req CreateTemplateAccessRequest) (TemplateAccessRequest, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/access-requests", templateID), req)
+	if err != nil {
+		return TemplateAccessRequest{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TemplateAccessRequest{}, ReadBodyAsError(res)
+	}
+	var request TemplateAccessRequest
+	return request, json.NewDecoder(res.Body).Decode(&request)
+}
+
+// TemplateAccessRequests returns the access requests of a template, newest
+// first. An empty status returns requests of every status.
+func (c *Client) TemplateAccessRequests(ctx context.Context, templateID uuid.UUID, status TemplateAccessRequestStatus) ([]TemplateAccessRequest, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/templates/%s/access-requests", templateID), nil, func(r *http.Request) {
+		if status != "" {
+			q := r.URL.Query()
+			q.Set("status", string(status))
+			r.URL.RawQuery = q.Encode()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var requests []TemplateAccessRequest
+	return requests, json.NewDecoder(res.Body).Decode(&requests)
+}
+
+// DecideTemplateAccessRequest approves or denies a pending access request.
+func (c *Client) DecideTemplateAccessRequest(ctx context.Context, id uuid.UUID, req DecideTemplateAccessRequest) (TemplateAccessRequest, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templateaccessrequests/%s/decision", id), req)
+	if err != nil {
+		return TemplateAccessRequest{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateAccessRequest{}, ReadBodyAsError(res)
+	}
+	var request TemplateAccessRequest
+	return request, json.NewDecoder(res.Body).Decode(&request)
+}
@@ -50,6 +50,47 @@ type Template struct {
 	FailureTTLMillis    int64 `json:"failure_ttl_ms"`
 	InactivityTTLMillis int64 `json:"inactivity_ttl_ms"`
 	LockedTTLMillis     int64 `json:"locked_ttl_ms"`
+
+	// RequireActiveVersion forces outdated workspaces to be started with the
+	// active version once RequireActiveVersionGracePeriodMillis has passed
+	// since the active version changed.
+	RequireActiveVersion                  bool  `json:"require_active_version"`
+	RequireActiveVersionGracePeriodMillis int64 `json:"require_active_version_grace_period_ms"`
+
+	// MaxBuildDurationMillis, ProvisionerMemoryLimitBytes and
+	// ProvisionerCPULimitMillis limit the provisioner jobs of the template.
+	// Jobs exceeding them are canceled. 0 means no limit.
+	MaxBuildDurationMillis      int64 `json:"max_build_duration_ms"`
+	ProvisionerMemoryLimitBytes int64 `json:"provisioner_memory_limit_bytes"`
+	ProvisionerCPULimitMillis   int64 `json:"provisioner_cpu_limit_ms"`
+	// MaxConcurrentProvisionerJobs limits how many provisioner jobs of the
+	// template run at once. Further jobs wait in the queue. 0 means no limit.
+	MaxConcurrentProvisionerJobs int32 `json:"max_concurrent_provisioner_jobs"`
+	// MaxSessionIdleMillis disconnects web terminal and app sessions of the
+	// workspaces of the template once they have been idle for this long.
+	// Users are warned before they are disconnected. 0 means sessions are
+	// never disconnected.
+	MaxSessionIdleMillis int64 `json:"max_session_idle_ms"`
+	// PortPolicy restricts which ports of the agents of the template's
+	// workspaces can be forwarded, on top of the deployment's port policy.
+	PortPolicy PortPolicy `json:"port_policy"`
+
+	// RequireWorkspaceApproval holds the first build of new workspaces until
+	// it is approved. See WorkspaceApproval.
+	RequireWorkspaceApproval bool `json:"require_workspace_approval"`
+	// RequireAgentBinaryVerification stops agents from starting when their
+	// binary doesn't match the checksum served by the deployment or the
+	// signature of the agent binary signing certificate.
+	RequireAgentBinaryVerification bool `json:"require_agent_binary_verification"`
+	// RequirePromotionApproval only lets new versions become active through
+	// a promotion approved by a template approver. See
+	// TemplateVersionPromotion.
+	RequirePromotionApproval bool `json:"require_promotion_approval"`
+
+	// AutostopActivitySources is enterprise-only. It lists the kinds of
+	// activity that postpone the autostop of workspaces. When empty, any
+	// connection to a workspace postpones it.
+	AutostopActivitySources []AgentActivitySource `json:"autostop_activity_sources"`
 }
 
 // WeekdaysToBitmap converts a list of weekdays to a bitmap in accordance with
@@ -136,11 +177,20 @@ type (
 	}
 )
 
+// TemplateRole is the level of access a user or group is granted on a
+// template. Each role includes the permissions of the roles listed before it.
 type TemplateRole string
 
 const (
+	// TemplateRoleUse can read the template and create workspaces from it.
+	TemplateRoleUse TemplateRole = "use"
+	// TemplateRolePush can also push new template versions and promote them.
+	TemplateRolePush TemplateRole = "push"
+	// TemplateRoleSettings can also change the settings of the template.
+	TemplateRoleSettings TemplateRole = "settings"
+	// TemplateRoleAdmin can also manage who has access to the template and
+	// delete it.
 	TemplateRoleAdmin   TemplateRole = "admin"
-	TemplateRoleUse     TemplateRole = "use"
 	TemplateRoleDeleted TemplateRole = ""
 )
 
@@ -151,12 +201,12 @@ type TemplateACL struct {
 
 type TemplateGroup struct {
 	Group
-	Role TemplateRole `json:"role" enums:"admin,use"`
+	Role TemplateRole `json:"role" enums:"admin,settings,push,use"`
 }
 
 type TemplateUser struct {
 	User
-	Role TemplateRole `json:"role" enums:"admin,use"`
+	Role TemplateRole `json:"role" enums:"admin,settings,push,use"`
 }
 
 type UpdateTemplateACL struct {
@@ -167,6 +217,28 @@ type UpdateTemplateACL struct {
 	GroupPerms map[string]TemplateRole `json:"group_perms,omitempty" example:"<user_id>>:admin,8bd26b20-f3e8-48be-a903-46bb920cf671:use"`
 }
 
+// TemplateACLChange is a change of the role of a single user or group on a
+// template.
+type TemplateACLChange struct {
+	ID uuid.UUID `json:"id" format:"uuid"`
+	// OldRole is empty if the user or group was added.
+	OldRole TemplateRole `json:"old_role" enums:"admin,settings,push,use,"`
+	// NewRole is empty if the user or group was removed.
+	NewRole TemplateRole `json:"new_role" enums:"admin,settings,push,use,"`
+}
+
+// TemplateACLDiff lists the users and groups whose role on a template was
+// changed by an update of the template ACL. Unchanged entries are omitted.
+type TemplateACLDiff struct {
+	Users  []TemplateACLChange `json:"users"`
+	Groups []TemplateACLChange `json:"groups"`
+}
+
+type UpdateTemplateACLResponse struct {
+	Message string          `json:"message"`
+	Diff    TemplateACLDiff `json:"diff"`
+}
+
 // ACLAvailable is a list of users and groups that can be added to a template
 // ACL.
 type ACLAvailable struct {
@@ -192,6 +264,31 @@ type UpdateTemplateMeta struct {
 	FailureTTLMillis             int64                       `json:"failure_ttl_ms,omitempty"`
 	InactivityTTLMillis          int64                       `json:"inactivity_ttl_ms,omitempty"`
 	LockedTTLMillis              int64                       `json:"locked_ttl_ms,omitempty"`
+	// RequireActiveVersion and RequireActiveVersionGracePeriodMillis are left
+	// unchanged when nil.
+	RequireActiveVersion                  *bool  `json:"require_active_version,omitempty"`
+	RequireActiveVersionGracePeriodMillis *int64 `json:"require_active_version_grace_period_ms,omitempty"`
+	// MaxBuildDurationMillis, ProvisionerMemoryLimitBytes and
+	// ProvisionerCPULimitMillis are left unchanged when nil.
+	MaxBuildDurationMillis      *int64 `json:"max_build_duration_ms,omitempty"`
+	ProvisionerMemoryLimitBytes *int64 `json:"provisioner_memory_limit_bytes,omitempty"`
+	ProvisionerCPULimitMillis   *int64 `json:"provisioner_cpu_limit_ms,omitempty"`
+	// MaxConcurrentProvisionerJobs is left unchanged when nil.
+	MaxConcurrentProvisionerJobs *int32 `json:"max_concurrent_provisioner_jobs,omitempty"`
+	// MaxSessionIdleMillis is left unchanged when nil.
+	MaxSessionIdleMillis *int64 `json:"max_session_idle_ms,omitempty"`
+	// PortPolicy is left unchanged when nil.
+	PortPolicy *PortPolicy `json:"port_policy,omitempty"`
+	// RequireWorkspaceApproval is left unchanged when nil.
+	RequireWorkspaceApproval *bool `json:"require_workspace_approval,omitempty"`
+	// RequireAgentBinaryVerification is left unchanged when nil.
+	RequireAgentBinaryVerification *bool `json:"require_agent_binary_verification,omitempty"`
+	// RequirePromotionApproval is left unchanged when nil.
+	RequirePromotionApproval *bool `json:"require_promotion_approval,omitempty"`
+	// AutostopActivitySources is left unchanged when nil. It can only be set
+	// if your license includes the advanced template scheduling feature. If
+	// you attempt to set this value while unlicensed, it will be ignored.
+	AutostopActivitySources *[]AgentActivitySource `json:"autostop_activity_sources,omitempty"`
 	// UpdateWorkspaceLastUsedAt updates the last_used_at field of workspaces
 	// spawned from the template. This is useful for preventing workspaces being
 	// immediately locked when updating the inactivity_ttl field to a new, shorter
@@ -255,16 +352,19 @@ func (c *Client) UpdateTemplateMeta(ctx context.Context, templateID uuid.UUID, r
 	return updated, json.NewDecoder(res.Body).Decode(&updated)
 }
 
-func (c *Client) UpdateTemplateACL(ctx context.Context, templateID uuid.UUID, req UpdateTemplateACL) error {
+// UpdateTemplateACL applies the changes to the template ACL and returns the
+// roles that were actually changed.
+func (c *Client) UpdateTemplateACL(ctx context.Context, templateID uuid.UUID, req UpdateTemplateACL) (UpdateTemplateACLResponse, error) {
 	res, err := c.Request(ctx, http.MethodPatch, fmt.Sprintf("/api/v2/templates/%s/acl", templateID), req)
 	if err != nil {
-		return err
+		return UpdateTemplateACLResponse{}, err
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		return ReadBodyAsError(res)
+		return UpdateTemplateACLResponse{}, ReadBodyAsError(res)
 	}
-	return nil
+	var resp UpdateTemplateACLResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
 // TemplateACLAvailable returns available users + groups that can be assigned template perms
@@ -308,6 +408,27 @@ func (c *Client) UpdateActiveTemplateVersion(ctx context.Context, template uuid.
 	return nil
 }
 
+// NotifyOutdatedWorkspacesResponse lists the workspaces whose owners were
+// notified that a newer template version is available.
+type NotifyOutdatedWorkspacesResponse struct {
+	WorkspaceIDs []uuid.UUID `json:"workspace_ids" format:"uuid"`
+}
+
+// NotifyOutdatedWorkspaces notifies the owners of the workspaces that don't
+// use the active version of the template.
+func (c *Client) NotifyOutdatedWorkspaces(ctx context.Context, template uuid.UUID) (NotifyOutdatedWorkspacesResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/notify-outdated", template), nil)
+	if err != nil {
+		return NotifyOutdatedWorkspacesResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return NotifyOutdatedWorkspacesResponse{}, ReadBodyAsError(res)
+	}
+	var resp NotifyOutdatedWorkspacesResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 // TemplateVersionsByTemplateRequest defines the request parameters for
 // TemplateVersionsByTemplate.
 type TemplateVersionsByTemplateRequest struct {
@@ -0,0 +1,108 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TemplateVersionPromotionStatus string
+
+const (
+	TemplateVersionPromotionStatusPending  TemplateVersionPromotionStatus = "pending"
+	TemplateVersionPromotionStatusApproved TemplateVersionPromotionStatus = "approved"
+	TemplateVersionPromotionStatusRejected TemplateVersionPromotionStatus = "rejected"
+)
+
+// TemplateVersionPromotion is a request to make a template version the active
+// version of its template. The version becomes active once a template
+// approver approves the promotion.
+type TemplateVersionPromotion struct {
+	ID                uuid.UUID                      `json:"id" format:"uuid"`
+	TemplateID        uuid.UUID                      `json:"template_id" format:"uuid"`
+	TemplateVersionID uuid.UUID                      `json:"template_version_id" format:"uuid"`
+	RequestedBy       uuid.UUID                      `json:"requested_by" format:"uuid"`
+	CreatedAt         time.Time                      `json:"created_at" format:"date-time"`
+	Message           string                         `json:"message,omitempty"`
+	Status            TemplateVersionPromotionStatus `json:"status" enums:"pending,approved,rejected"`
+	DecidedBy         *uuid.UUID                     `json:"decided_by,omitempty" format:"uuid"`
+	DecidedAt         *time.Time                     `json:"decided_at,omitempty" format:"date-time"`
+	Reason            string                         `json:"reason,omitempty"`
+}
+
+type CreateTemplateVersionPromotionRequest struct {
+	// Message is an optional description of the changes in the version for
+	// the approvers.
+	Message string `json:"message,omitempty"`
+}
+
+type DecideTemplateVersionPromotionRequest struct {
+	Status TemplateVersionPromotionStatus `json:"status" validate:"required,oneof=approved rejected" enums:"approved,rejected"`
+	// Reason is an optional explanation of the decision, such as the number
+	// of the change ticket that approved it.
+	Reason string `json:"reason,omitempty"`
+}
+
+// TemplateVersionPromotionFilter filters the promotions returned by
+// TemplateVersionPromotions. Zero values match every promotion.
+type TemplateVersionPromotionFilter struct {
+	TemplateID uuid.UUID                      `json:"template_id,omitempty" format:"uuid"`
+	Status     TemplateVersionPromotionStatus `json:"status,omitempty"`
+}
+
+// PromoteTemplateVersion requests the template version to become the active
+// version of the template once a template approver approves it.
+func (c *Client) PromoteTemplateVersion(ctx context.Context, templateID, versionID uuid.UUID, req CreateTemplateVersionPromotionRequest) (TemplateVersionPromotion, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templates/%s/versions/%s/promote", templateID, versionID), req)
+	if err != nil {
+		return TemplateVersionPromotion{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return TemplateVersionPromotion{}, ReadBodyAsError(res)
+	}
+	var promotion TemplateVersionPromotion
+	return promotion, json.NewDecoder(res.Body).Decode(&promotion)
+}
+
+// TemplateVersionPromotions returns the promotions visible to the user,
+// newest first.
+func (c *Client) TemplateVersionPromotions(ctx context.Context, filter TemplateVersionPromotionFilter) ([]TemplateVersionPromotion, error) {
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/templateversionpromotions", nil, func(r *http.Request) {
+		q := r.URL.Query()
+		if filter.TemplateID != uuid.Nil {
+			q.Set("template_id", filter.TemplateID.String())
+		}
+		if filter.Status != "" {
+			q.Set("status", string(filter.Status))
+		}
+		r.URL.RawQuery = q.Encode()
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var promotions []TemplateVersionPromotion
+	return promotions, json.NewDecoder(res.Body).Decode(&promotions)
+}
+
+// DecideTemplateVersionPromotion approves or rejects a pending promotion.
+func (c *Client) DecideTemplateVersionPromotion(ctx context.Context, id uuid.UUID, req DecideTemplateVersionPromotionRequest) (TemplateVersionPromotion, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templateversionpromotions/%s/decision", id), req)
+	if err != nil {
+		return TemplateVersionPromotion{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionPromotion{}, ReadBodyAsError(res)
+	}
+	var promotion TemplateVersionPromotion
+	return promotion, json.NewDecoder(res.Body).Decode(&promotion)
+}
@@ -65,6 +65,12 @@ type TemplateVersionParameter struct {
 	ValidationMonotonic  ValidationMonotonicOrder         `json:"validation_monotonic,omitempty" enums:"increasing,decreasing"`
 	Required             bool                             `json:"required"`
 	Ephemeral            bool                             `json:"ephemeral"`
+	// Dependencies make the parameter applicable only when one of them
+	// matches. A parameter without dependencies is always applicable.
+	Dependencies []TemplateVersionParameterDependency `json:"dependencies,omitempty"`
+	// DynamicOptions is true if the options are fetched from a
+	// template-defined endpoint whenever the parameter is resolved.
+	DynamicOptions bool `json:"dynamic_options"`
 }
 
 // TemplateVersionParameterOption represents a selectable option for a template parameter.
@@ -75,6 +81,19 @@ type TemplateVersionParameterOption struct {
 	Icon        string `json:"icon"`
 }
 
+// TemplateVersionParameterDependency restricts a parameter based on the value
+// of another parameter of the same template version.
+type TemplateVersionParameterDependency struct {
+	// Parameter is the name of the parameter this one depends on.
+	Parameter string `json:"parameter"`
+	// Values lists the values of Parameter for which the dependency matches.
+	// An empty list matches any value.
+	Values []string `json:"values,omitempty"`
+	// Options restricts the option values that can be chosen while the
+	// dependency matches. An empty list allows all options.
+	Options []string `json:"options,omitempty"`
+}
+
 // TemplateVersionVariable represents a managed template variable.
 type TemplateVersionVariable struct {
 	Name         string `json:"name"`
@@ -254,6 +273,61 @@ func (c *Client) CancelTemplateVersionDryRun(ctx context.Context, version, job u
 	return nil
 }
 
+// TemplateVersionStateAnalysisRequest contains a Terraform state to map to
+// the resources of a template version.
+type TemplateVersionStateAnalysisRequest struct {
+	// State is the content of a state file as written by "terraform state
+	// pull".
+	State []byte `json:"state" validate:"required"`
+}
+
+type TemplateVersionStateResourceStatus string
+
+const (
+	// TemplateVersionStateResourceMatched resources are in the state and the
+	// template, and are adopted by the workspace.
+	TemplateVersionStateResourceMatched TemplateVersionStateResourceStatus = "matched"
+	// TemplateVersionStateResourceMissing resources are only in the template,
+	// and are created by the first build.
+	TemplateVersionStateResourceMissing TemplateVersionStateResourceStatus = "missing"
+	// TemplateVersionStateResourceUnmanaged resources are only in the state,
+	// and are destroyed by the first build.
+	TemplateVersionStateResourceUnmanaged TemplateVersionStateResourceStatus = "unmanaged"
+)
+
+// TemplateVersionStateAnalysis describes what happens to the resources of a
+// Terraform state when a workspace is created from it.
+type TemplateVersionStateAnalysis struct {
+	Resources []TemplateVersionStateResource `json:"resources"`
+	Warnings  []string                       `json:"warnings"`
+}
+
+type TemplateVersionStateResource struct {
+	Type   string                             `json:"type"`
+	Name   string                             `json:"name"`
+	Status TemplateVersionStateResourceStatus `json:"status" enums:"matched,missing,unmanaged"`
+	// Instances is the number of instances of the resource in the state.
+	Instances int `json:"instances"`
+	// Agents are the names of the agents the template runs on the resource.
+	Agents []string `json:"agents,omitempty"`
+}
+
+// TemplateVersionStateAnalysis maps the resources of a Terraform state to the
+// resources of a template version. Use it to check which resources a workspace
+// created with the state adopts.
+func (c *Client) TemplateVersionStateAnalysis(ctx context.Context, version uuid.UUID, req TemplateVersionStateAnalysisRequest) (TemplateVersionStateAnalysis, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/templateversions/%s/state-analysis", version), req)
+	if err != nil {
+		return TemplateVersionStateAnalysis{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return TemplateVersionStateAnalysis{}, ReadBodyAsError(res)
+	}
+	var analysis TemplateVersionStateAnalysis
+	return analysis, json.NewDecoder(res.Body).Decode(&analysis)
+}
+
 func (c *Client) PreviousTemplateVersion(ctx context.Context, organization uuid.UUID, templateName, versionName string) (TemplateVersion, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/templates/%s/versions/%s/previous", organization, templateName, versionName), nil)
 	if err != nil {
@@ -279,3 +353,48 @@ func (c *Client) UpdateTemplateVersion(ctx context.Context, versionID uuid.UUID,
 	var version TemplateVersion
 	return version, json.NewDecoder(res.Body).Decode(&version)
 }
+
+type TemplateVersionDiagnosticSeverity string
+
+const (
+	TemplateVersionDiagnosticSeverityError   TemplateVersionDiagnosticSeverity = "error"
+	TemplateVersionDiagnosticSeverityWarning TemplateVersionDiagnosticSeverity = "warning"
+)
+
+// ValidateTemplateVersionRequest validates an uploaded template archive
+// without creating a template version.
+type ValidateTemplateVersionRequest struct {
+	FileID uuid.UUID `json:"file_id" validate:"required" format:"uuid"`
+}
+
+// ValidateTemplateVersionResponse is the result of statically checking a
+// template archive. Valid is false if any diagnostic is an error.
+type ValidateTemplateVersionResponse struct {
+	Valid       bool                        `json:"valid"`
+	Diagnostics []TemplateVersionDiagnostic `json:"diagnostics"`
+}
+
+// TemplateVersionDiagnostic is a problem found in a template. Filename and
+// line are omitted when the problem isn't tied to a location.
+type TemplateVersionDiagnostic struct {
+	Severity TemplateVersionDiagnosticSeverity `json:"severity" enums:"error,warning"`
+	Summary  string                            `json:"summary"`
+	Detail   string                            `json:"detail"`
+	Filename string                            `json:"filename,omitempty"`
+	Line     int                               `json:"line,omitempty"`
+}
+
+// ValidateTemplateVersion checks an uploaded template archive for errors
+// without creating a template version.
+func (c *Client) ValidateTemplateVersion(ctx context.Context, req ValidateTemplateVersionRequest) (ValidateTemplateVersionResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, "/api/v2/templateversions/validate", req)
+	if err != nil {
+		return ValidateTemplateVersionResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return ValidateTemplateVersionResponse{}, ReadBodyAsError(res)
+	}
+	var resp ValidateTemplateVersionResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/netip"
+	"strconv"
 	"strings"
 	"time"
 
@@ -184,6 +186,42 @@ type OIDCAuthMethod struct {
 }
 
 // HasFirstUser returns whether the first user has been created.
+// UserConnectionLogEntry is a connection a user made to a workspace agent.
+type UserConnectionLogEntry struct {
+	ID                     uuid.UUID                `json:"id" format:"uuid"`
+	WorkspaceID            uuid.UUID                `json:"workspace_id" format:"uuid"`
+	WorkspaceName          string                   `json:"workspace_name"`
+	WorkspaceOwnerUsername string                   `json:"workspace_owner_username"`
+	AgentName              string                   `json:"agent_name"`
+	ClientType             WorkspaceAgentClientType `json:"client_type"`
+	ClientVersion          string                   `json:"client_version"`
+	IP                     netip.Addr               `json:"ip"`
+	ConnectedAt            time.Time                `json:"connected_at" format:"date-time"`
+	DisconnectedAt         *time.Time               `json:"disconnected_at,omitempty" format:"date-time"`
+}
+
+// UserConnectionLogRequest filters the entries returned by UserConnectionLog.
+type UserConnectionLogRequest struct {
+	// After only returns connections established after this time, if set.
+	After time.Time
+	// Limit is the maximum number of connections to return. Zero uses the
+	// server default.
+	Limit int
+}
+
+func (r UserConnectionLogRequest) asRequestOption() RequestOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		if !r.After.IsZero() {
+			q.Set("after", r.After.Format(time.RFC3339Nano))
+		}
+		if r.Limit > 0 {
+			q.Set("limit", strconv.Itoa(r.Limit))
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
 func (c *Client) HasFirstUser(ctx context.Context) (bool, error) {
 	res, err := c.Request(ctx, http.MethodGet, "/api/v2/users/first", nil)
 	if err != nil {
@@ -498,6 +536,21 @@ func (c *Client) OrganizationByName(ctx context.Context, user string, name strin
 	return org, json.NewDecoder(res.Body).Decode(&org)
 }
 
+// UserConnectionLog returns the most recent connections the user made to
+// workspace agents, ordered from newest to oldest.
+func (c *Client) UserConnectionLog(ctx context.Context, user string, req UserConnectionLogRequest) ([]UserConnectionLogEntry, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/connection-log", user), nil, req.asRequestOption())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var entries []UserConnectionLogEntry
+	return entries, json.NewDecoder(res.Body).Decode(&entries)
+}
+
 // CreateOrganization creates an organization and adds the provided user as an admin.
 func (c *Client) CreateOrganization(ctx context.Context, req CreateOrganizationRequest) (Organization, error) {
 	res, err := c.Request(ctx, http.MethodPost, "/api/v2/organizations", req)
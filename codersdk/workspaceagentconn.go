@@ -200,6 +200,9 @@ type WorkspaceAgentReconnectingPTYInit struct {
 	Height  uint16
 	Width   uint16
 	Command string
+	// Resume only attaches to an existing session. The connection is closed
+	// instead of starting a new session if it has ended.
+	Resume bool
 }
 
 // ReconnectingPTYRequest is sent from the client to the server
@@ -218,6 +221,31 @@ func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID,
 	ctx, span := tracing.StartSpan(ctx)
 	defer span.End()
 
+	return c.reconnectingPTY(ctx, WorkspaceAgentReconnectingPTYInit{
+		ID:      id,
+		Height:  height,
+		Width:   width,
+		Command: command,
+	})
+}
+
+// ResumeReconnectingPTY attaches to an existing reconnecting terminal session.
+// Unlike ReconnectingPTY, the agent closes the returned net.Conn instead of
+// starting a new session if the session has ended. Agents that predate
+// resuming start a new session.
+func (c *WorkspaceAgentConn) ResumeReconnectingPTY(ctx context.Context, id uuid.UUID, height, width uint16) (net.Conn, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+
+	return c.reconnectingPTY(ctx, WorkspaceAgentReconnectingPTYInit{
+		ID:     id,
+		Height: height,
+		Width:  width,
+		Resume: true,
+	})
+}
+
+func (c *WorkspaceAgentConn) reconnectingPTY(ctx context.Context, init WorkspaceAgentReconnectingPTYInit) (net.Conn, error) {
 	if !c.AwaitReachable(ctx) {
 		return nil, xerrors.Errorf("workspace agent not reachable in time: %v", ctx.Err())
 	}
@@ -226,12 +254,7 @@ func (c *WorkspaceAgentConn) ReconnectingPTY(ctx context.Context, id uuid.UUID,
 	if err != nil {
 		return nil, err
 	}
-	data, err := json.Marshal(WorkspaceAgentReconnectingPTYInit{
-		ID:      id,
-		Height:  height,
-		Width:   width,
-		Command: command,
-	})
+	data, err := json.Marshal(init)
 	if err != nil {
 		_ = conn.Close()
 		return nil, err
@@ -361,6 +384,53 @@ func (c *WorkspaceAgentConn) ListeningPorts(ctx context.Context) (WorkspaceAgent
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+type WorkspaceAgentReconnectingPTYSessionsResponse struct {
+	Sessions []WorkspaceAgentReconnectingPTYSession `json:"sessions"`
+}
+
+// WorkspaceAgentReconnectingPTYSession is a terminal session running in the
+// agent. Connections with its ID attach to it until it ends, which happens
+// when its command exits or nothing has been attached to it for a while.
+type WorkspaceAgentReconnectingPTYSession struct {
+	ID        uuid.UUID `json:"id" format:"uuid"`
+	Command   string    `json:"command"`
+	CreatedAt time.Time `json:"created_at" format:"date-time"`
+}
+
+// ReconnectingPTYSessions lists the terminal sessions running in the agent.
+func (c *WorkspaceAgentConn) ReconnectingPTYSessions(ctx context.Context) (WorkspaceAgentReconnectingPTYSessionsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/reconnecting-ptys", nil)
+	if err != nil {
+		return WorkspaceAgentReconnectingPTYSessionsResponse{}, xerrors.Errorf("do request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceAgentReconnectingPTYSessionsResponse{}, ReadBodyAsError(res)
+	}
+
+	var resp WorkspaceAgentReconnectingPTYSessionsResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+// SupportBundle returns a zip archive of diagnostics gathered by the agent,
+// like its logs, manifest, listening ports and a netcheck report. The caller
+// must close the returned reader.
+func (c *WorkspaceAgentConn) SupportBundle(ctx context.Context) (io.ReadCloser, error) {
+	ctx, span := tracing.StartSpan(ctx)
+	defer span.End()
+	res, err := c.apiRequest(ctx, http.MethodGet, "/api/v0/support-bundle", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("do request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
 // apiRequest makes a request to the workspace agent's HTTP API server.
 func (c *WorkspaceAgentConn) apiRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
 	ctx, span := tracing.StartSpan(ctx)
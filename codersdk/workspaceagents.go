@@ -15,11 +15,13 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 	"nhooyr.io/websocket"
 	"tailscale.com/tailcfg"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/tailnet"
 	"github.com/coder/retry"
@@ -164,11 +166,22 @@ type WorkspaceAgent struct {
 	ConnectionTimeoutSeconds int32                 `json:"connection_timeout_seconds"`
 	TroubleshootingURL       string                `json:"troubleshooting_url"`
 	// Deprecated: Use StartupScriptBehavior instead.
-	LoginBeforeReady             bool                 `json:"login_before_ready"`
-	ShutdownScript               string               `json:"shutdown_script,omitempty"`
-	ShutdownScriptTimeoutSeconds int32                `json:"shutdown_script_timeout_seconds"`
-	Subsystems                   []AgentSubsystem     `json:"subsystems"`
-	Health                       WorkspaceAgentHealth `json:"health"` // Health reports the health of the agent.
+	LoginBeforeReady             bool             `json:"login_before_ready"`
+	ShutdownScript               string           `json:"shutdown_script,omitempty"`
+	ShutdownScriptTimeoutSeconds int32            `json:"shutdown_script_timeout_seconds"`
+	Subsystems                   []AgentSubsystem `json:"subsystems"`
+	// HostFacts are reported by the agent on startup. See the
+	// AgentHostFact constants for the keys reported on Windows.
+	HostFacts map[string]string `json:"host_facts,omitempty"`
+	// ClockOffsetMS is the offset of the agent clock from the clock of
+	// coderd in milliseconds, as last reported by the agent. Positive values
+	// mean the agent clock is ahead. Agents with a large offset are
+	// unhealthy.
+	ClockOffsetMS int64                `json:"clock_offset_ms"`
+	Health        WorkspaceAgentHealth `json:"health"` // Health reports the health of the agent.
+	// DisplayOrder is the position of the agent among the agents of its
+	// resource, as defined by the template. Agents are sorted by it.
+	DisplayOrder int32 `json:"display_order"`
 }
 
 type WorkspaceAgentHealth struct {
@@ -217,12 +230,46 @@ func (c *Client) WorkspaceAgentConnectionInfo(ctx context.Context, agentID uuid.
 	return connInfo, json.NewDecoder(res.Body).Decode(&connInfo)
 }
 
+// WorkspaceAgentClientType identifies the kind of client connecting to a
+// workspace agent.
+type WorkspaceAgentClientType string
+
+// WorkspaceAgentClientType enums.
+const (
+	WorkspaceAgentClientTypeSSH         WorkspaceAgentClientType = "ssh"
+	WorkspaceAgentClientTypeVSCode      WorkspaceAgentClientType = "vscode"
+	WorkspaceAgentClientTypeJetBrains   WorkspaceAgentClientType = "jetbrains"
+	WorkspaceAgentClientTypePortForward WorkspaceAgentClientType = "port_forward"
+	WorkspaceAgentClientTypeCLI         WorkspaceAgentClientType = "cli"
+	WorkspaceAgentClientTypeUnknown     WorkspaceAgentClientType = "unknown"
+)
+
+// WorkspaceAgentClientTypes lists all the known client types.
+var WorkspaceAgentClientTypes = []WorkspaceAgentClientType{
+	WorkspaceAgentClientTypeSSH,
+	WorkspaceAgentClientTypeVSCode,
+	WorkspaceAgentClientTypeJetBrains,
+	WorkspaceAgentClientTypePortForward,
+	WorkspaceAgentClientTypeCLI,
+	WorkspaceAgentClientTypeUnknown,
+}
+
+// Valid returns true if the client type is known.
+func (t WorkspaceAgentClientType) Valid() bool {
+	return slices.Contains(WorkspaceAgentClientTypes, t)
+}
+
 // @typescript-ignore DialWorkspaceAgentOptions
 type DialWorkspaceAgentOptions struct {
 	Logger slog.Logger
 	// BlockEndpoints forced a direct connection through DERP. The Client may
 	// have DisableDirect set which will override this value.
 	BlockEndpoints bool
+	// ClientType and ClientVersion identify the client to the deployment so
+	// connections can be attributed to it. ClientType defaults to unknown and
+	// ClientVersion to the version of this binary.
+	ClientType    WorkspaceAgentClientType
+	ClientVersion string
 }
 
 func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, options *DialWorkspaceAgentOptions) (agentConn *WorkspaceAgentConn, err error) {
@@ -279,6 +326,18 @@ func (c *Client) DialWorkspaceAgent(ctx context.Context, agentID uuid.UUID, opti
 	if err != nil {
 		return nil, xerrors.Errorf("parse url: %w", err)
 	}
+	clientType := options.ClientType
+	if clientType == "" {
+		clientType = WorkspaceAgentClientTypeUnknown
+	}
+	clientVersion := options.ClientVersion
+	if clientVersion == "" {
+		clientVersion = buildinfo.Version()
+	}
+	q := coordinateURL.Query()
+	q.Set("client_type", string(clientType))
+	q.Set("client_version", clientVersion)
+	coordinateURL.RawQuery = q.Encode()
 	closedCoordinator := make(chan struct{})
 	firstCoordinator := make(chan error)
 	go func() {
@@ -550,6 +609,10 @@ func (c *Client) IssueReconnectingPTYSignedToken(ctx context.Context, req IssueR
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// ReconnectingPTYSessionEndedStatus is the websocket close status of terminal
+// connections that ask to resume a session that has ended.
+const ReconnectingPTYSessionEndedStatus websocket.StatusCode = 4404
+
 // @typescript-ignore:WorkspaceAgentReconnectingPTYOpts
 type WorkspaceAgentReconnectingPTYOpts struct {
 	AgentID   uuid.UUID
@@ -557,6 +620,10 @@ type WorkspaceAgentReconnectingPTYOpts struct {
 	Width     uint16
 	Height    uint16
 	Command   string
+	// Resume only attaches to the existing session with the Reconnect ID. If
+	// the session has ended, the connection is closed with
+	// ReconnectingPTYSessionEndedStatus instead of starting a new one.
+	Resume bool
 
 	// SignedToken is an optional signed token from the
 	// issue-reconnecting-pty-signed-token endpoint. If set, the session token
@@ -577,6 +644,9 @@ func (c *Client) WorkspaceAgentReconnectingPTY(ctx context.Context, opts Workspa
 	q.Set("width", strconv.Itoa(int(opts.Width)))
 	q.Set("height", strconv.Itoa(int(opts.Height)))
 	q.Set("command", opts.Command)
+	if opts.Resume {
+		q.Set("resume", "true")
+	}
 	// If we're using a signed token, set the query parameter.
 	if opts.SignedToken != "" {
 		q.Set(SignedAppTokenQueryParameter, opts.SignedToken)
@@ -627,6 +697,126 @@ func (c *Client) WorkspaceAgentListeningPorts(ctx context.Context, agentID uuid.
 	return listeningPorts, json.NewDecoder(res.Body).Decode(&listeningPorts)
 }
 
+// WorkspaceAgentSupportBundle asks the workspace agent to gather local
+// diagnostics for debugging connectivity issues of the workspace, and returns
+// them as a zip archive. The caller must close the returned reader.
+func (c *Client) WorkspaceAgentSupportBundle(ctx context.Context, agentID uuid.UUID) (io.ReadCloser, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/support-bundle", agentID), nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, ReadBodyAsError(res)
+	}
+	return res.Body, nil
+}
+
+// WorkspaceAgentMetadataHistoryRequest filters the values returned by
+// WorkspaceAgentMetadataHistory.
+type WorkspaceAgentMetadataHistoryRequest struct {
+	Key string
+	// After only returns values collected after this time, if set.
+	After time.Time
+	// Limit is the maximum number of values to return. Zero uses the
+	// server default.
+	Limit int
+}
+
+func (r WorkspaceAgentMetadataHistoryRequest) asRequestOption() RequestOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set("key", r.Key)
+		if !r.After.IsZero() {
+			q.Set("after", r.After.Format(time.RFC3339Nano))
+		}
+		if r.Limit > 0 {
+			q.Set("limit", strconv.Itoa(r.Limit))
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// WorkspaceAgentMetadataHistory returns the most recently collected values of
+// a metadata key, ordered from oldest to newest.
+func (c *Client) WorkspaceAgentMetadataHistory(ctx context.Context, agentID uuid.UUID, req WorkspaceAgentMetadataHistoryRequest) ([]WorkspaceAgentMetadataResult, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/metadata/history", agentID), nil, req.asRequestOption())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var history []WorkspaceAgentMetadataResult
+	return history, json.NewDecoder(res.Body).Decode(&history)
+}
+
+// WorkspaceAgentClientConnection is a client connection to a workspace agent
+// established through the coordinator.
+type WorkspaceAgentClientConnection struct {
+	ID uuid.UUID `json:"id" format:"uuid"`
+	// UserID is nil if the client is a workspace proxy.
+	UserID         *uuid.UUID               `json:"user_id,omitempty" format:"uuid"`
+	ClientType     WorkspaceAgentClientType `json:"client_type"`
+	ClientVersion  string                   `json:"client_version"`
+	ConnectedAt    time.Time                `json:"connected_at" format:"date-time"`
+	DisconnectedAt *time.Time               `json:"disconnected_at,omitempty" format:"date-time"`
+}
+
+// WorkspaceAgentClientConnectionsRequest filters the connections returned by
+// WorkspaceAgentClientConnections.
+type WorkspaceAgentClientConnectionsRequest struct {
+	// After only returns connections established after this time, if set.
+	After time.Time
+	// Limit is the maximum number of connections to return. Zero uses the
+	// server default.
+	Limit int
+}
+
+func (r WorkspaceAgentClientConnectionsRequest) asRequestOption() RequestOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		if !r.After.IsZero() {
+			q.Set("after", r.After.Format(time.RFC3339Nano))
+		}
+		if r.Limit > 0 {
+			q.Set("limit", strconv.Itoa(r.Limit))
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// WorkspaceAgentClientConnections returns the most recent client connections
+// to a workspace agent, ordered from newest to oldest.
+func (c *Client) WorkspaceAgentClientConnections(ctx context.Context, agentID uuid.UUID, req WorkspaceAgentClientConnectionsRequest) ([]WorkspaceAgentClientConnection, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaceagents/%s/connections", agentID), nil, req.asRequestOption())
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var connections []WorkspaceAgentClientConnection
+	return connections, json.NewDecoder(res.Body).Decode(&connections)
+}
+
+// RevokeWorkspaceAgentAuthToken invalidates every auth token of the agent,
+// e.g. when one leaked. The workspace must be rebuilt for the agent to get a
+// new token.
+func (c *Client) RevokeWorkspaceAgentAuthToken(ctx context.Context, agentID uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceagents/%s/revoke-token", agentID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 //nolint:revive // Follow is a control flag on the server as well.
 func (c *Client) WorkspaceAgentLogsAfter(ctx context.Context, agentID uuid.UUID, after int64, follow bool) (<-chan []WorkspaceAgentLog, io.Closer, error) {
 	var queryParams []string
@@ -768,6 +958,45 @@ func (s AgentSubsystem) Valid() bool {
 	}
 }
 
+// AgentActivitySource is a kind of user activity that agents observe in a
+// workspace.
+type AgentActivitySource string
+
+const (
+	// AgentActivitySourceSSH is input to SSH sessions.
+	AgentActivitySourceSSH AgentActivitySource = "ssh"
+	// AgentActivitySourceReconnectingPTY is input to web terminals.
+	AgentActivitySourceReconnectingPTY AgentActivitySource = "reconnecting_pty"
+	// AgentActivitySourceApp is traffic to the ports of workspace apps.
+	AgentActivitySourceApp AgentActivitySource = "app"
+	// AgentActivitySourcePortForward is traffic to any other forwarded port.
+	AgentActivitySourcePortForward AgentActivitySource = "port_forward"
+)
+
+func (s AgentActivitySource) Valid() bool {
+	switch s {
+	case AgentActivitySourceSSH, AgentActivitySourceReconnectingPTY, AgentActivitySourceApp, AgentActivitySourcePortForward:
+		return true
+	default:
+		return false
+	}
+}
+
+// Keys of the host facts reported by agents running on Windows.
+const (
+	// AgentHostFactOSName is the edition, e.g. "Windows Server 2022 Datacenter".
+	AgentHostFactOSName = "os_name"
+	// AgentHostFactOSVersion is the feature update, e.g. "21H2".
+	AgentHostFactOSVersion = "os_version"
+	// AgentHostFactOSBuild is the build and revision, e.g. "20348.1906".
+	AgentHostFactOSBuild = "os_build"
+	// AgentHostFactConPTY is "true" if the host supports pseudo consoles,
+	// which the web terminal and SSH sessions with a PTY require.
+	AgentHostFactConPTY = "conpty"
+	// AgentHostFactService is "true" if the agent runs as a Windows service.
+	AgentHostFactService = "service"
+)
+
 type WorkspaceAgentLogSource string
 
 const (
@@ -0,0 +1,114 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+type WorkspaceApprovalStatus string
+
+const (
+	WorkspaceApprovalStatusPending  WorkspaceApprovalStatus = "pending"
+	WorkspaceApprovalStatusApproved WorkspaceApprovalStatus = "approved"
+	WorkspaceApprovalStatusRejected WorkspaceApprovalStatus = "rejected"
+)
+
+// WorkspaceApproval is a request to approve the first build of a workspace
+// created from a template that requires approval. The build stays pending
+// until the request is approved, and fails if it is rejected.
+type WorkspaceApproval struct {
+	ID               uuid.UUID               `json:"id" format:"uuid"`
+	WorkspaceID      uuid.UUID               `json:"workspace_id" format:"uuid"`
+	WorkspaceBuildID uuid.UUID               `json:"workspace_build_id" format:"uuid"`
+	RequestedBy      uuid.UUID               `json:"requested_by" format:"uuid"`
+	CreatedAt        time.Time               `json:"created_at" format:"date-time"`
+	Status           WorkspaceApprovalStatus `json:"status" enums:"pending,approved,rejected"`
+	DecidedBy        *uuid.UUID              `json:"decided_by,omitempty" format:"uuid"`
+	DecidedAt        *time.Time              `json:"decided_at,omitempty" format:"date-time"`
+	Reason           string                  `json:"reason,omitempty"`
+}
+
+// WorkspaceApprovalWebhookPayload is posted to the workspace approval webhook
+// of the deployment when a build awaits approval, so change-management
+// systems like ServiceNow or Jira can track the request. It's signed with the
+// webhook secret like the requests that trigger workspace webhooks. The
+// system records its decision by posting a DecideWorkspaceApprovalRequest to
+// CallbackURL, signed the same way.
+type WorkspaceApprovalWebhookPayload struct {
+	Approval          WorkspaceApproval `json:"approval"`
+	WorkspaceName     string            `json:"workspace_name"`
+	WorkspaceOwner    string            `json:"workspace_owner"`
+	TemplateID        uuid.UUID         `json:"template_id" format:"uuid"`
+	TemplateName      string            `json:"template_name"`
+	RequesterUsername string            `json:"requester_username"`
+	CallbackURL       string            `json:"callback_url"`
+}
+
+type DecideWorkspaceApprovalRequest struct {
+	Status WorkspaceApprovalStatus `json:"status" validate:"required,oneof=approved rejected" enums:"approved,rejected"`
+	// Reason is an optional explanation of the decision, such as the number
+	// of the change ticket that approved it.
+	Reason string `json:"reason,omitempty"`
+}
+
+// WorkspaceApproval returns the most recent approval request of the workspace.
+func (c *Client) WorkspaceApproval(ctx context.Context, workspaceID uuid.UUID) (WorkspaceApproval, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/approval", workspaceID), nil)
+	if err != nil {
+		return WorkspaceApproval{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceApproval{}, ReadBodyAsError(res)
+	}
+	var approval WorkspaceApproval
+	return approval, json.NewDecoder(res.Body).Decode(&approval)
+}
+
+// DecideWorkspaceApproval approves or rejects a pending approval request.
+func (c *Client) DecideWorkspaceApproval(ctx context.Context, id uuid.UUID, req DecideWorkspaceApprovalRequest) (WorkspaceApproval, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceapprovals/%s/decision", id), req)
+	if err != nil {
+		return WorkspaceApproval{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceApproval{}, ReadBodyAsError(res)
+	}
+	var approval WorkspaceApproval
+	return approval, json.NewDecoder(res.Body).Decode(&approval)
+}
+
+// DecideWorkspaceApprovalCallback records the decision of the system behind
+// the workspace approval webhook. The request is signed with the webhook
+// secret instead of being authenticated with a session token.
+func (c *Client) DecideWorkspaceApprovalCallback(ctx context.Context, id uuid.UUID, secret string, req DecideWorkspaceApprovalRequest) (WorkspaceApproval, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return WorkspaceApproval{}, xerrors.Errorf("marshal request: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	signature := SignWorkspaceWebhook(secret, timestamp, body)
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaceapprovals/%s/callback", id), body,
+		func(r *http.Request) {
+			r.Header.Set(WorkspaceWebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+			r.Header.Set(WorkspaceWebhookSignatureHeader, signature)
+		},
+	)
+	if err != nil {
+		return WorkspaceApproval{}, xerrors.Errorf("decide workspace approval: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceApproval{}, ReadBodyAsError(res)
+	}
+	var approval WorkspaceApproval
+	return approval, json.NewDecoder(res.Body).Decode(&approval)
+}
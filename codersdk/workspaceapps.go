@@ -21,6 +21,36 @@ const (
 	WorkspaceAppSharingLevelPublic        WorkspaceAppSharingLevel = "public"
 )
 
+const (
+	// WorkspaceAppIdentityHeader carries a JWT identifying the user making a
+	// request to a workspace app. It is signed with EdDSA, and the keys that
+	// verify it are served at /api/v2/applications/jwks. Its claims are
+	// WorkspaceAppIdentityClaims.
+	WorkspaceAppIdentityHeader = "Coder-App-Identity"
+	// WorkspaceAppUserIDHeader, WorkspaceAppUsernameHeader and
+	// WorkspaceAppUserEmailHeader carry the claims of the identity token for
+	// apps that trust the network between them and Coder.
+	WorkspaceAppUserIDHeader    = "Coder-App-User-Id"
+	WorkspaceAppUsernameHeader  = "Coder-App-Username"
+	WorkspaceAppUserEmailHeader = "Coder-App-User-Email"
+)
+
+// WorkspaceAppIdentityClaims are the claims of the token in the
+// WorkspaceAppIdentityHeader. They follow the OpenID Connect standard claims.
+type WorkspaceAppIdentityClaims struct {
+	// Issuer is the access URL of the deployment.
+	Issuer string `json:"iss"`
+	// Subject is the ID of the user.
+	Subject string `json:"sub"`
+	// Audience is the base URL of the app the token was issued for.
+	Audience  string `json:"aud"`
+	IssuedAt  int64  `json:"iat"`
+	Expiry    int64  `json:"exp"`
+	Username  string `json:"preferred_username"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"picture,omitempty"`
+}
+
 type WorkspaceApp struct {
 	ID uuid.UUID `json:"id" format:"uuid"`
 	// URL is the address being proxied to inside the workspace.
@@ -46,6 +76,11 @@ type WorkspaceApp struct {
 	// Healthcheck specifies the configuration for checking app health.
 	Healthcheck Healthcheck        `json:"healthcheck"`
 	Health      WorkspaceAppHealth `json:"health"`
+	// DisplayOrder is the position of the app among the apps of its agent,
+	// as defined by the template. Apps are sorted by it.
+	DisplayOrder int32 `json:"display_order"`
+	// Hidden apps should not be displayed by clients.
+	Hidden bool `json:"hidden"`
 }
 
 type Healthcheck struct {
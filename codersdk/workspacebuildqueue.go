@@ -0,0 +1,77 @@
+package codersdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WorkspaceBuildConflictPolicy decides what happens to a build requested while
+// the workspace already has an active build.
+type WorkspaceBuildConflictPolicy string
+
+const (
+	// WorkspaceBuildConflictPolicyFail rejects the build with a conflict.
+	WorkspaceBuildConflictPolicyFail WorkspaceBuildConflictPolicy = "fail"
+	// WorkspaceBuildConflictPolicyQueue queues the build to start once the
+	// active build and the builds queued before it have finished.
+	WorkspaceBuildConflictPolicyQueue WorkspaceBuildConflictPolicy = "queue"
+	// WorkspaceBuildConflictPolicySupersede cancels the active build and
+	// replaces the builds queued for the workspace. The build starts as soon as
+	// the cancellation finishes.
+	WorkspaceBuildConflictPolicySupersede WorkspaceBuildConflictPolicy = "supersede"
+)
+
+// QueuedWorkspaceBuild is a build request waiting for the active build of its
+// workspace to finish.
+type QueuedWorkspaceBuild struct {
+	ID                uuid.UUID           `json:"id" format:"uuid"`
+	WorkspaceID       uuid.UUID           `json:"workspace_id" format:"uuid"`
+	InitiatorID       uuid.UUID           `json:"initiator_id" format:"uuid"`
+	CreatedAt         time.Time           `json:"created_at" format:"date-time"`
+	Transition        WorkspaceTransition `json:"transition" enums:"start,stop,delete"`
+	TemplateVersionID uuid.UUID           `json:"template_version_id,omitempty" format:"uuid"`
+	// Position is the position of the build in the queue of the workspace,
+	// starting at 1 for the build that starts next.
+	Position int `json:"position"`
+}
+
+// CreateOrQueueWorkspaceBuild creates a build like CreateWorkspaceBuild, but
+// also accepts builds that are queued because of request.IfActive. Either the
+// created build or the queued build is returned.
+func (c *Client) CreateOrQueueWorkspaceBuild(ctx context.Context, workspace uuid.UUID, request CreateWorkspaceBuildRequest) (*WorkspaceBuild, *QueuedWorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/builds", workspace), request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer res.Body.Close()
+	switch res.StatusCode {
+	case http.StatusCreated:
+		var workspaceBuild WorkspaceBuild
+		return &workspaceBuild, nil, json.NewDecoder(res.Body).Decode(&workspaceBuild)
+	case http.StatusAccepted:
+		var queued QueuedWorkspaceBuild
+		return nil, &queued, json.NewDecoder(res.Body).Decode(&queued)
+	default:
+		return nil, nil, ReadBodyAsError(res)
+	}
+}
+
+// WorkspaceBuildQueue returns the builds queued for the workspace, in the order
+// they start.
+func (c *Client) WorkspaceBuildQueue(ctx context.Context, workspace uuid.UUID) ([]QueuedWorkspaceBuild, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/builds/queue", workspace), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var queued []QueuedWorkspaceBuild
+	return queued, json.NewDecoder(res.Body).Decode(&queued)
+}
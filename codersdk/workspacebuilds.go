@@ -46,6 +46,25 @@ const (
 	// "autostop" is used when a build to stop a workspace is triggered by Autostop.
 	// The initiator id/username in this case is the workspace owner and can be ignored.
 	BuildReasonAutostop BuildReason = "autostop"
+	// "autolock" is used when a build to stop a workspace is triggered because
+	// the workspace became dormant after being inactive.
+	// The initiator id/username in this case is the workspace owner and can be ignored.
+	BuildReasonAutolock BuildReason = "autolock"
+	// "failedstop" is used when a build to stop a workspace is triggered because
+	// its last build failed longer ago than the failure TTL of the template.
+	// The initiator id/username in this case is the workspace owner and can be ignored.
+	BuildReasonFailedStop BuildReason = "failedstop"
+	// "autodelete" is used when a build to delete a workspace is triggered
+	// because it was locked for longer than the locked TTL of the template.
+	// The initiator id/username in this case is the workspace owner and can be ignored.
+	BuildReasonAutodelete BuildReason = "autodelete"
+	// "bulk" is used when a build is triggered by an administrator operating
+	// on many workspaces at once.
+	// Combined with the initiator id/username, it indicates which user initiated the build.
+	BuildReasonBulk BuildReason = "bulk"
+	// "webhook" is used when a build is triggered by a workspace webhook.
+	// The initiator id/username in this case is the user who created the webhook.
+	BuildReasonWebhook BuildReason = "webhook"
 )
 
 // WorkspaceBuild is an at-point representation of a workspace state.
@@ -65,12 +84,15 @@ type WorkspaceBuild struct {
 	InitiatorID         uuid.UUID           `json:"initiator_id" format:"uuid"`
 	InitiatorUsername   string              `json:"initiator_name"`
 	Job                 ProvisionerJob      `json:"job"`
-	Reason              BuildReason         `db:"reason" json:"reason" enums:"initiator,autostart,autostop"`
+	Reason              BuildReason         `db:"reason" json:"reason" enums:"initiator,autostart,autostop,autolock,failedstop,autodelete,bulk,webhook"`
 	Resources           []WorkspaceResource `json:"resources"`
 	Deadline            NullTime            `json:"deadline,omitempty" format:"date-time"`
 	MaxDeadline         NullTime            `json:"max_deadline,omitempty" format:"date-time"`
 	Status              WorkspaceStatus     `json:"status" enums:"pending,starting,running,stopping,stopped,failed,canceling,canceled,deleting,deleted"`
 	DailyCost           int32               `json:"daily_cost"`
+	// InitiatorTokenName is the name of the API token the initiator used to
+	// start the build, if any.
+	InitiatorTokenName string `json:"initiator_token_name,omitempty"`
 }
 
 // WorkspaceResource describes resources used to create a workspace, for instance:
@@ -87,6 +109,9 @@ type WorkspaceResource struct {
 	Agents     []WorkspaceAgent            `json:"agents,omitempty"`
 	Metadata   []WorkspaceResourceMetadata `json:"metadata,omitempty"`
 	DailyCost  int32                       `json:"daily_cost"`
+	// DisplayOrder is the position of the resource among the resources of
+	// the build, as defined by the template. Resources are sorted by it.
+	DisplayOrder int32 `json:"display_order"`
 }
 
 // WorkspaceResourceMetadata annotates the workspace resource with custom key-value pairs.
@@ -102,6 +127,42 @@ type WorkspaceBuildParameter struct {
 	Value string `json:"value"`
 }
 
+// BuildHookPhase is when a build hook runs relative to the build.
+type BuildHookPhase string
+
+const (
+	BuildHookPhasePre   BuildHookPhase = "pre"
+	BuildHookPhasePost  BuildHookPhase = "post"
+	BuildHookPhaseClone BuildHookPhase = "clone"
+)
+
+// WorkspaceBuildTimeline breaks a workspace build down into the stages of
+// its provisioner job and the build hooks declared by its template.
+type WorkspaceBuildTimeline struct {
+	Stages []WorkspaceBuildTimelineStage `json:"stages"`
+	Hooks  []WorkspaceBuildHook          `json:"hooks"`
+}
+
+// WorkspaceBuildTimelineStage is a stage of a workspace build, as reported
+// in the build logs. CompletedAt is nil while the stage is running.
+type WorkspaceBuildTimelineStage struct {
+	Name        string     `json:"name"`
+	StartedAt   time.Time  `json:"started_at" format:"date-time"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" format:"date-time"`
+}
+
+// WorkspaceBuildHook is the result of running a build hook. Hooks that
+// could not be started have an exit code of -1.
+type WorkspaceBuildHook struct {
+	Name        string         `json:"name"`
+	Phase       BuildHookPhase `json:"phase" enums:"pre,post,clone"`
+	StartedAt   time.Time      `json:"started_at" format:"date-time"`
+	CompletedAt time.Time      `json:"completed_at" format:"date-time"`
+	Attempts    int32          `json:"attempts"`
+	ExitCode    int32          `json:"exit_code"`
+	Error       string         `json:"error,omitempty"`
+}
+
 // WorkspaceBuild returns a single workspace build for a workspace.
 // If history is "", the latest version is returned.
 func (c *Client) WorkspaceBuild(ctx context.Context, id uuid.UUID) (WorkspaceBuild, error) {
@@ -148,6 +209,21 @@ func (c *Client) WorkspaceBuildState(ctx context.Context, build uuid.UUID) ([]by
 	return io.ReadAll(res.Body)
 }
 
+// WorkspaceBuildTimeline returns the stages and build hook results of a
+// workspace build.
+func (c *Client) WorkspaceBuildTimeline(ctx context.Context, build uuid.UUID) (WorkspaceBuildTimeline, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspacebuilds/%s/timeline", build), nil)
+	if err != nil {
+		return WorkspaceBuildTimeline{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceBuildTimeline{}, ReadBodyAsError(res)
+	}
+	var timeline WorkspaceBuildTimeline
+	return timeline, json.NewDecoder(res.Body).Decode(&timeline)
+}
+
 func (c *Client) WorkspaceBuildByUsernameAndWorkspaceNameAndBuildNumber(ctx context.Context, username string, workspaceName string, buildNumber string) (WorkspaceBuild, error) {
 	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/users/%s/workspace/%s/builds/%s", username, workspaceName, buildNumber), nil)
 	if err != nil {
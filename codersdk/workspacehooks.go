@@ -0,0 +1,64 @@
+package codersdk
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WorkspaceHookEvent string
+
+const (
+	// WorkspaceHookEventPreStart is sent before a start build is provisioned.
+	WorkspaceHookEventPreStart WorkspaceHookEvent = "pre_start"
+	// WorkspaceHookEventPostStart is sent after a start build succeeded.
+	WorkspaceHookEventPostStart WorkspaceHookEvent = "post_start"
+	// WorkspaceHookEventPreStop is sent before a stop or delete build is
+	// provisioned.
+	WorkspaceHookEventPreStop WorkspaceHookEvent = "pre_stop"
+	// WorkspaceHookEventPostStop is sent after a stop or delete build
+	// succeeded.
+	WorkspaceHookEventPostStop WorkspaceHookEvent = "post_stop"
+)
+
+type WorkspaceHookFailurePolicy string
+
+const (
+	// WorkspaceHookFailurePolicyFail fails the build when a pre hook fails.
+	WorkspaceHookFailurePolicyFail WorkspaceHookFailurePolicy = "fail"
+	// WorkspaceHookFailurePolicyIgnore logs failures and continues.
+	WorkspaceHookFailurePolicyIgnore WorkspaceHookFailurePolicy = "ignore"
+)
+
+// WorkspaceHookConfig is a webhook that Coder calls around workspace start and
+// stop builds, e.g. to warm caches, register DNS or release licenses.
+//
+// Requests are signed like workspace webhook triggers, using the
+// WorkspaceWebhookTimestampHeader and WorkspaceWebhookSignatureHeader headers.
+type WorkspaceHookConfig struct {
+	Name   string               `json:"name" yaml:"name"`
+	URL    string               `json:"url" yaml:"url"`
+	Events []WorkspaceHookEvent `json:"events" yaml:"events"`
+	// Timeout is how long a call may take. Defaults to 10 seconds.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+	// FailurePolicy decides whether builds fail when a pre hook fails.
+	// Defaults to "fail". Post hooks run after the build completed, so their
+	// failures are always logged and ignored.
+	FailurePolicy WorkspaceHookFailurePolicy `json:"failure_policy" yaml:"failure_policy" enums:"fail,ignore"`
+	// Secret signs the requests sent to the hook.
+	Secret string `json:"-" yaml:"secret"`
+}
+
+// WorkspaceHookPayload is the body of the requests sent to workspace hooks.
+type WorkspaceHookPayload struct {
+	Event              WorkspaceHookEvent  `json:"event"`
+	WorkspaceID        uuid.UUID           `json:"workspace_id" format:"uuid"`
+	WorkspaceName      string              `json:"workspace_name"`
+	WorkspaceOwnerID   uuid.UUID           `json:"workspace_owner_id" format:"uuid"`
+	WorkspaceOwnerName string              `json:"workspace_owner_name"`
+	TemplateID         uuid.UUID           `json:"template_id" format:"uuid"`
+	TemplateName       string              `json:"template_name"`
+	WorkspaceBuildID   uuid.UUID           `json:"workspace_build_id" format:"uuid"`
+	BuildNumber        int32               `json:"build_number"`
+	Transition         WorkspaceTransition `json:"transition"`
+}
@@ -26,13 +26,41 @@ const (
 	// ProxyUnregistered means the proxy has not registered a url yet. This means
 	// the proxy was created with the cli, but has not yet been started.
 	ProxyUnregistered ProxyHealthStatus = "unregistered"
+	// ProxyVersionMismatch means the proxy is responding, but runs a different
+	// version than the primary coderd. It's only used if the version mismatch
+	// policy is "warn".
+	ProxyVersionMismatch ProxyHealthStatus = "version_mismatch"
+)
+
+// ProxyVersionMismatchPolicy is how coderd treats workspace proxies that run a
+// different version than itself.
+type ProxyVersionMismatchPolicy string
+
+const (
+	// ProxyVersionMismatchWarn lets proxies with a different version register
+	// and serve users, but reports the mismatch in their status.
+	ProxyVersionMismatchWarn ProxyVersionMismatchPolicy = "warn"
+	// ProxyVersionMismatchBlock refuses to register proxies with a different
+	// version and stops sending users to the ones that were registered before
+	// coderd was upgraded.
+	ProxyVersionMismatchBlock ProxyVersionMismatchPolicy = "block"
 )
 
 type WorkspaceProxyStatus struct {
 	Status ProxyHealthStatus `json:"status" table:"status,default_sort"`
 	// Report provides more information about the health of the workspace proxy.
-	Report    ProxyHealthReport `json:"report,omitempty" table:"report"`
-	CheckedAt time.Time         `json:"checked_at" table:"checked_at" format:"date-time"`
+	Report ProxyHealthReport `json:"report,omitempty" table:"report"`
+	// DERPStatus is the health of the DERP server of the proxy, which is
+	// checked separately from its HTTP endpoints. Proxies are only added to
+	// the DERP map while their DERP server is healthy. It is empty for
+	// proxies without DERP and proxies that could not be reached.
+	DERPStatus ProxyHealthStatus `json:"derp_status,omitempty" table:"derp_status"`
+	// DERPError explains why the DERP server of the proxy is not healthy.
+	DERPError string `json:"derp_error,omitempty" table:"derp_error"`
+	// Version is the Coder version the proxy reported in its last health
+	// check. It is empty if the proxy could not be reached.
+	Version   string    `json:"version,omitempty" table:"version"`
+	CheckedAt time.Time `json:"checked_at" table:"checked_at" format:"date-time"`
 }
 
 // ProxyHealthReport is a report of the health of the workspace proxy.
@@ -51,6 +79,12 @@ type WorkspaceProxy struct {
 	DerpEnabled bool `json:"derp_enabled" table:"derp_enabled"`
 	DerpOnly    bool `json:"derp_only" table:"derp_only"`
 
+	// OrganizationIDs and GroupIDs limit the proxy to the members of these
+	// organizations and groups. The proxy is available to everyone when both
+	// are empty.
+	OrganizationIDs []uuid.UUID `json:"organization_ids" format:"uuid" table:"organization_ids"`
+	GroupIDs        []uuid.UUID `json:"group_ids" format:"uuid" table:"group_ids"`
+
 	// Status is the latest status check of the proxy. This will be empty for deleted
 	// proxies. This value can be used to determine if a workspace proxy is healthy
 	// and ready to use.
@@ -133,6 +167,31 @@ func (c *Client) PatchWorkspaceProxy(ctx context.Context, req PatchWorkspaceProx
 	return resp, json.NewDecoder(res.Body).Decode(&resp)
 }
 
+// UpdateWorkspaceProxyACL replaces the organizations and groups whose members
+// may use a workspace proxy. Leave both empty to make the proxy available to
+// everyone.
+type UpdateWorkspaceProxyACL struct {
+	OrganizationIDs []uuid.UUID `json:"organization_ids" format:"uuid"`
+	GroupIDs        []uuid.UUID `json:"group_ids" format:"uuid"`
+}
+
+func (c *Client) UpdateWorkspaceProxyACL(ctx context.Context, id uuid.UUID, req UpdateWorkspaceProxyACL) (WorkspaceProxy, error) {
+	res, err := c.Request(ctx, http.MethodPatch,
+		fmt.Sprintf("/api/v2/workspaceproxies/%s/acl", id.String()),
+		req,
+	)
+	if err != nil {
+		return WorkspaceProxy{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceProxy{}, ReadBodyAsError(res)
+	}
+	var resp WorkspaceProxy
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
 func (c *Client) DeleteWorkspaceProxyByName(ctx context.Context, name string) error {
 	res, err := c.Request(ctx, http.MethodDelete,
 		fmt.Sprintf("/api/v2/workspaceproxies/%s", name),
@@ -177,11 +236,15 @@ func (c *Client) WorkspaceProxyByID(ctx context.Context, id uuid.UUID) (Workspac
 }
 
 type RegionTypes interface {
-	Region | WorkspaceProxy
+	Region | WorkspaceProxy | RankedRegion
 }
 
 type RegionsResponse[R RegionTypes] struct {
 	Regions []R `json:"regions"`
+	// PreferredRegionIDs orders the healthy regions from the most to the
+	// least preferred for the requesting user. Clients should connect
+	// through the first region unless the user selected another one.
+	PreferredRegionIDs []uuid.UUID `json:"preferred_region_ids,omitempty" format:"uuid"`
 }
 
 type Region struct {
@@ -201,6 +264,31 @@ type Region struct {
 	// E.g. *--suffix.au.example.com
 	// Optional. Does not need to be on the same domain as PathAppURL.
 	WildcardHostname string `json:"wildcard_hostname" table:"wildcard_hostname"`
+
+	// HealthLatencyMS is the round trip time in milliseconds of the latest
+	// health check made by the primary to the region. It is zero for the
+	// primary and for regions that have not been reached yet.
+	HealthLatencyMS float64 `json:"health_latency_ms" table:"health latency ms"`
+}
+
+// RegionLatency is the latency a client measured to a region.
+type RegionLatency struct {
+	RegionID  uuid.UUID `json:"region_id" format:"uuid"`
+	LatencyMS float64   `json:"latency_ms"`
+}
+
+// RankRegionsRequest contains the latencies measured by a client so the
+// server can rank the regions for it.
+type RankRegionsRequest struct {
+	Latencies []RegionLatency `json:"latencies"`
+}
+
+// RankedRegion is a region annotated with the latency reported by the client.
+type RankedRegion struct {
+	Region `table:"region,recursive_inline"`
+	// ClientLatencyMS is the latency reported by the client for the region.
+	// It is omitted if the client did not report a latency for the region.
+	ClientLatencyMS *float64 `json:"client_latency_ms,omitempty" table:"client latency ms"`
 }
 
 func (c *Client) Regions(ctx context.Context) ([]Region, error) {
@@ -220,3 +308,60 @@ func (c *Client) Regions(ctx context.Context) ([]Region, error) {
 	var regions RegionsResponse[Region]
 	return regions.Regions, json.NewDecoder(res.Body).Decode(&regions)
 }
+
+// PreferredRegions returns the healthy regions ordered from the most to the
+// least preferred for the user, as decided by the server from its geo rules
+// and the latencies the user reported.
+func (c *Client) PreferredRegions(ctx context.Context) ([]Region, error) {
+	res, err := c.Request(ctx, http.MethodGet,
+		"/api/v2/regions",
+		nil,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var regions RegionsResponse[Region]
+	err = json.NewDecoder(res.Body).Decode(&regions)
+	if err != nil {
+		return nil, xerrors.Errorf("decode regions: %w", err)
+	}
+	byID := make(map[uuid.UUID]Region, len(regions.Regions))
+	for _, region := range regions.Regions {
+		byID[region.ID] = region
+	}
+	preferred := make([]Region, 0, len(regions.PreferredRegionIDs))
+	for _, id := range regions.PreferredRegionIDs {
+		if region, ok := byID[id]; ok {
+			preferred = append(preferred, region)
+		}
+	}
+	return preferred, nil
+}
+
+// RankRegions submits the latencies measured by the client and returns the
+// regions ordered from the most to the least preferred. Healthy regions come
+// first, ordered by the reported latency, followed by regions the client did
+// not report, ordered by the health check latency.
+func (c *Client) RankRegions(ctx context.Context, req RankRegionsRequest) ([]RankedRegion, error) {
+	res, err := c.Request(ctx, http.MethodPost,
+		"/api/v2/regions/latency",
+		req,
+	)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+
+	var regions RegionsResponse[RankedRegion]
+	return regions.Regions, json.NewDecoder(res.Body).Decode(&regions)
+}
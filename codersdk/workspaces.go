@@ -46,7 +46,36 @@ type Workspace struct {
 	// Health shows the health of the workspace and information about
 	// what is causing an unhealthy status.
 	Health WorkspaceHealth `json:"health"`
-}
+	// AutomaticUpdates determines if the workspace is started with the
+	// active template version when it is outdated.
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates" enums:"always,never"`
+	// UpdateDeadline is set when the workspace is outdated and its template
+	// requires the active version. From this time on, the workspace can only
+	// be started with the active version of the template.
+	UpdateDeadline *time.Time `json:"update_deadline,omitempty" format:"date-time"`
+	// ExternalMetadata is the metadata attached to the workspace by external
+	// integrations that the caller is allowed to see, sorted by namespace.
+	ExternalMetadata []WorkspaceExternalMetadata `json:"external_metadata"`
+	// PreviousNames are the names the workspace had before it was renamed,
+	// most recent first.
+	PreviousNames []WorkspacePreviousName `json:"previous_names"`
+}
+
+// WorkspacePreviousName is a name a workspace was renamed away from. Until
+// RedirectExpiresAt, app URLs and lookups by owner and name that use the
+// previous name resolve to the workspace.
+type WorkspacePreviousName struct {
+	Name              string    `json:"name"`
+	RenamedAt         time.Time `json:"renamed_at" format:"date-time"`
+	RedirectExpiresAt time.Time `json:"redirect_expires_at" format:"date-time"`
+}
+
+type AutomaticUpdates string
+
+const (
+	AutomaticUpdatesAlways AutomaticUpdates = "always"
+	AutomaticUpdatesNever  AutomaticUpdates = "never"
+)
 
 func (w Workspace) FullName() string {
 	return fmt.Sprintf("%s/%s", w.OwnerName, w.Name)
@@ -88,6 +117,14 @@ type CreateWorkspaceBuildRequest struct {
 
 	// Log level changes the default logging verbosity of a provider ("info" if empty).
 	LogLevel ProvisionerLogLevel `json:"log_level,omitempty" validate:"omitempty,oneof=debug"`
+	// Reason is recorded on the build to explain why it was started
+	// ("initiator" if empty). The other reasons are reserved for builds that
+	// Coder starts on its own.
+	Reason BuildReason `json:"reason,omitempty" validate:"omitempty,oneof=initiator bulk" enums:"initiator,bulk"`
+	// IfActive decides what happens if the workspace already has an active
+	// build ("fail" if empty). Queued builds are answered with 202 Accepted
+	// and a QueuedWorkspaceBuild.
+	IfActive WorkspaceBuildConflictPolicy `json:"if_active,omitempty" validate:"omitempty,oneof=fail queue supersede" enums:"fail,queue,supersede"`
 }
 
 type WorkspaceOptions struct {
@@ -136,13 +173,23 @@ type WorkspaceBuildsRequest struct {
 	WorkspaceID uuid.UUID
 	Pagination
 	Since time.Time
+	// Reason only returns builds started for this reason if set.
+	Reason BuildReason
+	// InitiatorID only returns builds initiated by this user if set.
+	InitiatorID uuid.UUID
 }
 
 func (c *Client) WorkspaceBuilds(ctx context.Context, req WorkspaceBuildsRequest) ([]WorkspaceBuild, error) {
+	var initiatorID string
+	if req.InitiatorID != uuid.Nil {
+		initiatorID = req.InitiatorID.String()
+	}
 	res, err := c.Request(
 		ctx, http.MethodGet,
 		fmt.Sprintf("/api/v2/workspaces/%s/builds", req.WorkspaceID),
 		nil, req.Pagination.asRequestOption(), WithQueryParam("since", req.Since.Format(time.RFC3339)),
+		WithQueryParam("reason", string(req.Reason)),
+		WithQueryParam("initiator_id", initiatorID),
 	)
 	if err != nil {
 		return nil, err
@@ -273,6 +320,27 @@ func (c *Client) UpdateWorkspaceTTL(ctx context.Context, id uuid.UUID, req Updat
 	return nil
 }
 
+// UpdateWorkspaceAutomaticUpdatesRequest is a request to update a workspace's
+// automatic updates setting.
+type UpdateWorkspaceAutomaticUpdatesRequest struct {
+	AutomaticUpdates AutomaticUpdates `json:"automatic_updates" validate:"required" enums:"always,never"`
+}
+
+// UpdateWorkspaceAutomaticUpdates sets the automatic updates setting for
+// workspace by id.
+func (c *Client) UpdateWorkspaceAutomaticUpdates(ctx context.Context, id uuid.UUID, req UpdateWorkspaceAutomaticUpdatesRequest) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/autoupdates", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("update workspace automatic updates: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
 // PutExtendWorkspaceRequest is a request to extend the deadline of
 // the active workspace build.
 type PutExtendWorkspaceRequest struct {
@@ -312,6 +380,74 @@ func (c *Client) UpdateWorkspaceLock(ctx context.Context, id uuid.UUID, req Upda
 	return nil
 }
 
+type WorkspaceExternalMetadataVisibility string
+
+const (
+	// WorkspaceExternalMetadataVisibilityPublic metadata is visible to anyone
+	// who can read the workspace.
+	WorkspaceExternalMetadataVisibilityPublic WorkspaceExternalMetadataVisibility = "public"
+	// WorkspaceExternalMetadataVisibilityPrivate metadata is only visible to
+	// users who can read all workspaces in the organization, such as
+	// organization admins, and not to the workspace owner.
+	WorkspaceExternalMetadataVisibilityPrivate WorkspaceExternalMetadataVisibility = "private"
+)
+
+// WorkspaceExternalMetadata is JSON attached to a workspace by an external
+// integration, such as a CMDB or a ticketing system, under its own namespace.
+type WorkspaceExternalMetadata struct {
+	Namespace  string                              `json:"namespace"`
+	Visibility WorkspaceExternalMetadataVisibility `json:"visibility" enums:"public,private"`
+	Data       json.RawMessage                     `json:"data" swaggertype:"object"`
+	UpdatedAt  time.Time                           `json:"updated_at" format:"date-time"`
+}
+
+// PutWorkspaceExternalMetadataRequest sets the metadata stored under a
+// namespace. A null Data removes the namespace from the workspace.
+type PutWorkspaceExternalMetadataRequest struct {
+	Namespace  string                              `json:"namespace" validate:"required"`
+	Visibility WorkspaceExternalMetadataVisibility `json:"visibility,omitempty" enums:"public,private"`
+	Data       json.RawMessage                     `json:"data" swaggertype:"object"`
+}
+
+// PutWorkspaceExternalMetadata attaches metadata to a workspace under the
+// namespace of the request, replacing any metadata already stored there.
+func (c *Client) PutWorkspaceExternalMetadata(ctx context.Context, id uuid.UUID, req PutWorkspaceExternalMetadataRequest) error {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/external-metadata", id.String())
+	res, err := c.Request(ctx, http.MethodPut, path, req)
+	if err != nil {
+		return xerrors.Errorf("put workspace external metadata: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// CloneWorkspaceRequest provides options for cloning a workspace.
+type CloneWorkspaceRequest struct {
+	Name string `json:"name" validate:"workspace_name,required"`
+	// CopyData runs the clone hooks of the template in the first build of the
+	// clone, to copy the data of the workspace like its persistent volumes.
+	CopyData bool `json:"copy_data"`
+}
+
+// CloneWorkspace creates a workspace for the authenticated user from the
+// template version and parameters of the latest build of the workspace.
+func (c *Client) CloneWorkspace(ctx context.Context, id uuid.UUID, req CloneWorkspaceRequest) (Workspace, error) {
+	path := fmt.Sprintf("/api/v2/workspaces/%s/clone", id.String())
+	res, err := c.Request(ctx, http.MethodPost, path, req)
+	if err != nil {
+		return Workspace{}, xerrors.Errorf("clone workspace: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return Workspace{}, ReadBodyAsError(res)
+	}
+	var workspace Workspace
+	return workspace, json.NewDecoder(res.Body).Decode(&workspace)
+}
+
 type WorkspaceFilter struct {
 	// Owner can be "me" or a username
 	Owner string `json:"owner,omitempty" typescript:"-"`
@@ -417,6 +553,145 @@ func (c *Client) WorkspaceQuota(ctx context.Context, userID string) (WorkspaceQu
 	return quota, json.NewDecoder(res.Body).Decode(&quota)
 }
 
+type WorkspaceQuotaBudgetSource string
+
+const (
+	// WorkspaceQuotaBudgetSourceOrganizationDefault is the quota allowance of
+	// the Everyone group of an organization.
+	WorkspaceQuotaBudgetSourceOrganizationDefault WorkspaceQuotaBudgetSource = "organization_default"
+	// WorkspaceQuotaBudgetSourceGroup is the quota allowance of a group.
+	// Allowances of all groups of a user are added up.
+	WorkspaceQuotaBudgetSourceGroup WorkspaceQuotaBudgetSource = "group"
+	// WorkspaceQuotaBudgetSourceGroupOverride is the quota override of a
+	// group. It replaces the summed allowances.
+	WorkspaceQuotaBudgetSourceGroupOverride WorkspaceQuotaBudgetSource = "group_override"
+	// WorkspaceQuotaBudgetSourceUserOverride is the quota override of the
+	// user. It takes precedence over everything else.
+	WorkspaceQuotaBudgetSourceUserOverride WorkspaceQuotaBudgetSource = "user_override"
+)
+
+// WorkspaceQuotaBudget is a budget that may contribute to the quota of a user.
+type WorkspaceQuotaBudget struct {
+	Source WorkspaceQuotaBudgetSource `json:"source" enums:"organization_default,group,group_override,user_override"`
+	// GroupID is the group the budget comes from. It's unset for user
+	// overrides.
+	GroupID   *uuid.UUID `json:"group_id,omitempty" format:"uuid"`
+	GroupName string     `json:"group_name,omitempty"`
+	Allowance int        `json:"allowance"`
+	// Applied is true if the budget counts towards the user's quota.
+	Applied bool `json:"applied"`
+}
+
+// WorkspaceQuotaConsumer is a workspace that consumes quota.
+type WorkspaceQuotaConsumer struct {
+	WorkspaceID   uuid.UUID `json:"workspace_id" format:"uuid"`
+	WorkspaceName string    `json:"workspace_name"`
+	DailyCost     int       `json:"daily_cost"`
+}
+
+// WorkspaceQuotaBreakdown explains how the quota of a user is resolved and
+// consumed.
+type WorkspaceQuotaBreakdown struct {
+	CreditsConsumed int                      `json:"credits_consumed"`
+	Budget          int                      `json:"budget"`
+	Budgets         []WorkspaceQuotaBudget   `json:"budgets"`
+	Workspaces      []WorkspaceQuotaConsumer `json:"workspaces"`
+}
+
+func (c *Client) WorkspaceQuotaBreakdown(ctx context.Context, userID string) (WorkspaceQuotaBreakdown, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspace-quota/%s/breakdown", userID), nil)
+	if err != nil {
+		return WorkspaceQuotaBreakdown{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return WorkspaceQuotaBreakdown{}, ReadBodyAsError(res)
+	}
+	var breakdown WorkspaceQuotaBreakdown
+	return breakdown, json.NewDecoder(res.Body).Decode(&breakdown)
+}
+
+type UpdateWorkspaceQuotaOverrideRequest struct {
+	QuotaAllowance int `json:"quota_allowance" validate:"min=0"`
+}
+
+// UpdateWorkspaceQuotaOverride sets a quota allowance for the user that takes
+// precedence over any quota the user receives from groups.
+func (c *Client) UpdateWorkspaceQuotaOverride(ctx context.Context, userID string, req UpdateWorkspaceQuotaOverrideRequest) error {
+	res, err := c.Request(ctx, http.MethodPut, fmt.Sprintf("/api/v2/workspace-quota/%s/override", userID), req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// DeleteWorkspaceQuotaOverride removes the quota override of the user.
+func (c *Client) DeleteWorkspaceQuotaOverride(ctx context.Context, userID string) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspace-quota/%s/override", userID), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// WorkspaceConnection is a tailnet client connected to an agent of a
+// workspace, as known to the coordinator.
+type WorkspaceConnection struct {
+	ID        uuid.UUID `json:"id" format:"uuid"`
+	AgentID   uuid.UUID `json:"agent_id" format:"uuid"`
+	AgentName string    `json:"agent_name"`
+	// Server is whether the client is coderd or a workspace proxy, which
+	// connect on behalf of users for workspace apps and the web terminal.
+	Server bool `json:"server"`
+	// ClientType, ClientVersion and UserID are recorded when clients other
+	// than servers start coordinating with the agent.
+	ClientType    WorkspaceAgentClientType `json:"client_type,omitempty"`
+	ClientVersion string                   `json:"client_version,omitempty"`
+	UserID        *uuid.UUID               `json:"user_id,omitempty" format:"uuid"`
+	// IP is the tailnet IP address of the client.
+	IP string `json:"ip"`
+	// DERPRegionID is the preferred DERP region of the client, which relays
+	// its traffic when the connection isn't direct.
+	DERPRegionID   int    `json:"derp_region_id"`
+	DERPRegionName string `json:"derp_region_name"`
+	// DERPLatencyMS is the round trip time from the client to its preferred
+	// DERP region, or -1 if the client didn't report it.
+	DERPLatencyMS float64 `json:"derp_latency_ms"`
+	// Direct is whether both the client and the agent advertise endpoints,
+	// so they can connect peer-to-peer. Otherwise, traffic is relayed through
+	// DERP.
+	Direct bool `json:"direct"`
+	// RxBytes and TxBytes are the bytes received and sent by the agent on
+	// connections from the client in the last hour, as reported by the agent.
+	RxBytes int64 `json:"rx_bytes"`
+	TxBytes int64 `json:"tx_bytes"`
+	// UpdatedAt is when the client last sent its node to the coordinator.
+	UpdatedAt time.Time `json:"updated_at" format:"date-time"`
+}
+
+// WorkspaceConnections returns the tailnet clients connected to the agents of
+// the workspace.
+func (c *Client) WorkspaceConnections(ctx context.Context, id uuid.UUID) ([]WorkspaceConnection, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/connections", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var connections []WorkspaceConnection
+	return connections, json.NewDecoder(res.Body).Decode(&connections)
+}
+
 // WorkspaceNotifyChannel is the PostgreSQL NOTIFY
 // channel to listen for updates on. The payload is empty,
 // because the size of a workspace payload can be very large.
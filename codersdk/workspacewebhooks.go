@@ -0,0 +1,157 @@
+package codersdk
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// WorkspaceWebhookSignatureHeader carries the signature of a trigger
+	// request, formatted as "sha256=<hex>". See SignWorkspaceWebhook.
+	WorkspaceWebhookSignatureHeader = "Coder-Webhook-Signature"
+	// WorkspaceWebhookTimestampHeader carries the time a trigger request was
+	// signed, in Unix seconds. Requests signed outside of
+	// WorkspaceWebhookTimestampTolerance, or not after the last accepted
+	// request of the webhook, are rejected to prevent replays.
+	WorkspaceWebhookTimestampHeader = "Coder-Webhook-Timestamp"
+	// WorkspaceWebhookTimestampTolerance is how far the timestamp of a trigger
+	// request may be from the time it is received.
+	WorkspaceWebhookTimestampTolerance = 5 * time.Minute
+)
+
+type WorkspaceWebhookAction string
+
+const (
+	// WorkspaceWebhookActionStart starts the workspace.
+	WorkspaceWebhookActionStart WorkspaceWebhookAction = "start"
+	// WorkspaceWebhookActionStop stops the workspace.
+	WorkspaceWebhookActionStop WorkspaceWebhookAction = "stop"
+	// WorkspaceWebhookActionUpdate starts the workspace on the active version
+	// of its template.
+	WorkspaceWebhookActionUpdate WorkspaceWebhookAction = "update"
+	// WorkspaceWebhookActionExec runs the command of the webhook on a
+	// workspace agent.
+	WorkspaceWebhookActionExec WorkspaceWebhookAction = "exec"
+)
+
+// WorkspaceWebhook lets an external system, such as a CI pipeline or a
+// ticketing system, trigger a fixed action on a workspace by sending a signed
+// request to its URL. The action runs with the permissions of the user who
+// created the webhook.
+type WorkspaceWebhook struct {
+	ID              uuid.UUID              `json:"id" format:"uuid"`
+	WorkspaceID     uuid.UUID              `json:"workspace_id" format:"uuid"`
+	CreatedBy       uuid.UUID              `json:"created_by" format:"uuid"`
+	CreatedAt       time.Time              `json:"created_at" format:"date-time"`
+	Name            string                 `json:"name"`
+	Action          WorkspaceWebhookAction `json:"action" enums:"start,stop,update,exec"`
+	AgentName       string                 `json:"agent_name,omitempty"`
+	Command         string                 `json:"command,omitempty"`
+	LastTriggeredAt *time.Time             `json:"last_triggered_at,omitempty" format:"date-time"`
+	// URL is where trigger requests are sent.
+	URL string `json:"url"`
+}
+
+type CreateWorkspaceWebhookRequest struct {
+	Name   string                 `json:"name" validate:"required,username"`
+	Action WorkspaceWebhookAction `json:"action" validate:"required,oneof=start stop update exec" enums:"start,stop,update,exec"`
+	// AgentName is the agent that runs the command of an exec webhook. It
+	// may be omitted if the workspace has a single agent.
+	AgentName string `json:"agent_name,omitempty"`
+	// Command is required for exec webhooks, and must be empty otherwise.
+	Command string `json:"command,omitempty"`
+}
+
+// CreateWorkspaceWebhookResponse includes the secret that trigger requests
+// must be signed with. The secret is only returned once.
+type CreateWorkspaceWebhookResponse struct {
+	Webhook WorkspaceWebhook `json:"webhook"`
+	Secret  string           `json:"secret"`
+}
+
+type TriggerWorkspaceWebhookResponse struct {
+	// WorkspaceBuildID is the build started by start, stop and update
+	// webhooks.
+	WorkspaceBuildID *uuid.UUID `json:"workspace_build_id,omitempty" format:"uuid"`
+}
+
+// SignWorkspaceWebhook returns the signature of a trigger request body sent at
+// the given Unix timestamp. It is the hex encoded HMAC-SHA256 of the timestamp,
+// a period and the body, keyed with the webhook secret.
+func SignWorkspaceWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *Client) WorkspaceWebhooks(ctx context.Context, workspaceID uuid.UUID) ([]WorkspaceWebhook, error) {
+	res, err := c.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/workspaces/%s/webhooks", workspaceID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, ReadBodyAsError(res)
+	}
+	var webhooks []WorkspaceWebhook
+	return webhooks, json.NewDecoder(res.Body).Decode(&webhooks)
+}
+
+func (c *Client) CreateWorkspaceWebhook(ctx context.Context, workspaceID uuid.UUID, req CreateWorkspaceWebhookRequest) (CreateWorkspaceWebhookResponse, error) {
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspaces/%s/webhooks", workspaceID), req)
+	if err != nil {
+		return CreateWorkspaceWebhookResponse{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusCreated {
+		return CreateWorkspaceWebhookResponse{}, ReadBodyAsError(res)
+	}
+	var resp CreateWorkspaceWebhookResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
+
+func (c *Client) DeleteWorkspaceWebhook(ctx context.Context, id uuid.UUID) error {
+	res, err := c.Request(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/workspacewebhooks/%s", id), nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent {
+		return ReadBodyAsError(res)
+	}
+	return nil
+}
+
+// TriggerWorkspaceWebhook signs body with the webhook secret and sends it to
+// the trigger URL of the webhook. The body is not interpreted by the server.
+func (c *Client) TriggerWorkspaceWebhook(ctx context.Context, id uuid.UUID, secret string, body []byte) (TriggerWorkspaceWebhookResponse, error) {
+	timestamp := time.Now().Unix()
+	signature := SignWorkspaceWebhook(secret, timestamp, body)
+	res, err := c.Request(ctx, http.MethodPost, fmt.Sprintf("/api/v2/workspacewebhooks/%s/trigger", id), body,
+		func(r *http.Request) {
+			r.Header.Set(WorkspaceWebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+			r.Header.Set(WorkspaceWebhookSignatureHeader, signature)
+		},
+	)
+	if err != nil {
+		return TriggerWorkspaceWebhookResponse{}, xerrors.Errorf("trigger workspace webhook: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusAccepted {
+		return TriggerWorkspaceWebhookResponse{}, ReadBodyAsError(res)
+	}
+	var resp TriggerWorkspaceWebhookResponse
+	return resp, json.NewDecoder(res.Body).Decode(&resp)
+}
@@ -0,0 +1,61 @@
+// Package archive exports audit logs to external storage before they're
+// pruned from the database.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+// Store is a destination for archived audit logs.
+type Store interface {
+	// Put writes an object to the store, replacing any object with the same
+	// key.
+	Put(ctx context.Context, key string, data []byte) error
+	// String describes the destination without any credentials, so it can
+	// be recorded with archival runs.
+	String() string
+}
+
+// New returns the store for the destination URL. file:///path writes objects
+// to a directory, s3://bucket/prefix uploads them to an S3 bucket.
+func New(ctx context.Context, u *url.URL) (Store, error) {
+	if u == nil {
+		return nil, xerrors.New("archive destination must be set")
+	}
+	switch u.Scheme {
+	case "file":
+		return NewFile(u.Path)
+	case "s3":
+		return NewS3(ctx, u)
+	default:
+		return nil, xerrors.Errorf("unsupported archive destination scheme %q", u.Scheme)
+	}
+}
+
+// Encode returns the audit logs as gzipped newline-delimited JSON, one
+// audit.Event per line.
+func Encode(logs []database.AuditLog) ([]byte, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gw)
+	for _, alog := range logs {
+		err := enc.Encode(audit.EventFromLog(alog))
+		if err != nil {
+			return nil, xerrors.Errorf("encode audit log %s: %w", alog.ID, err)
+		}
+	}
+	err := gw.Close()
+	if err != nil {
+		return nil, xerrors.Errorf("close gzip writer: %w", err)
+	}
+	return buf.Bytes(), nil
+}
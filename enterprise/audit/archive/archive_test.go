@@ -0,0 +1,111 @@
+package archive_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/archive"
+)
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	logs := []database.AuditLog{
+		{ID: uuid.New(), Action: database.AuditActionCreate, ResourceType: database.ResourceTypeUser},
+		{ID: uuid.New(), Action: database.AuditActionDelete, ResourceType: database.ResourceTypeWorkspace},
+	}
+	data, err := archive.Encode(logs)
+	require.NoError(t, err)
+
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	scanner := bufio.NewScanner(gr)
+	var events []audit.Event
+	for scanner.Scan() {
+		var event audit.Event
+		err := json.Unmarshal(scanner.Bytes(), &event)
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, events, 2)
+	require.Equal(t, logs[0].ID, events[0].ID)
+	require.Equal(t, "create", events[0].Action)
+	require.Equal(t, logs[1].ID, events[1].ID)
+	require.Equal(t, "workspace", events[1].ResourceType)
+}
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "audit")
+	store, err := archive.New(context.Background(), &url.URL{Scheme: "file", Path: dir})
+	require.NoError(t, err)
+	require.Equal(t, "file://"+filepath.ToSlash(dir), store.String())
+
+	err = store.Put(context.Background(), "object.ndjson.gz", []byte("hello"))
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(dir, "object.ndjson.gz"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temporary files must be removed")
+}
+
+//nolint:paralleltest // t.Setenv can't be used in parallel tests.
+func TestS3(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test-access-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test-secret-key")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	type upload struct {
+		path          string
+		authorization string
+		body          []byte
+	}
+	uploads := make(chan upload, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		uploads <- upload{
+			path:          r.URL.Path,
+			authorization: r.Header.Get("Authorization"),
+			body:          body,
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse("s3://bucket/prefix/?endpoint=" + url.QueryEscape(srv.URL))
+	require.NoError(t, err)
+	store, err := archive.New(context.Background(), u)
+	require.NoError(t, err)
+	require.Equal(t, "s3://bucket/prefix", store.String())
+
+	err = store.Put(context.Background(), "object.ndjson.gz", []byte("hello"))
+	require.NoError(t, err)
+	got := <-uploads
+	require.Equal(t, "/bucket/prefix/object.ndjson.gz", got.path)
+	require.True(t, strings.HasPrefix(got.authorization, "AWS4-HMAC-SHA256 Credential=test-access-key/"), got.authorization)
+	require.Contains(t, got.authorization, "/us-east-1/s3/aws4_request")
+	require.Equal(t, "hello", string(got.body))
+}
@@ -0,0 +1,55 @@
+package archive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/xerrors"
+)
+
+type fileStore struct {
+	dir string
+}
+
+// NewFile returns a store that writes objects to files in a directory. The
+// directory is created if it doesn't exist.
+func NewFile(dir string) (Store, error) {
+	if dir == "" {
+		return nil, xerrors.New("archive directory must be set")
+	}
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, xerrors.Errorf("create archive directory: %w", err)
+	}
+	return &fileStore{dir: dir}, nil
+}
+
+func (s *fileStore) Put(_ context.Context, key string, data []byte) error {
+	name := filepath.Join(s.dir, filepath.FromSlash(key))
+	// Write to a temporary file first so a partially written object is
+	// never mistaken for a complete one.
+	tmp, err := os.CreateTemp(s.dir, ".archive-*")
+	if err != nil {
+		return xerrors.Errorf("create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if err != nil {
+		_ = tmp.Close()
+		return xerrors.Errorf("write temporary file: %w", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return xerrors.Errorf("close temporary file: %w", err)
+	}
+	err = os.Rename(tmp.Name(), name)
+	if err != nil {
+		return xerrors.Errorf("rename temporary file: %w", err)
+	}
+	return nil
+}
+
+func (s *fileStore) String() string {
+	return "file://" + filepath.ToSlash(s.dir)
+}
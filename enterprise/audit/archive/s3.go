@@ -0,0 +1,119 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"golang.org/x/xerrors"
+)
+
+type s3Store struct {
+	client      *http.Client
+	signer      *v4.Signer
+	credentials aws.CredentialsProvider
+	region      string
+	bucket      string
+	prefix      string
+	// endpoint is the base URL objects are uploaded to. Custom endpoints
+	// use path-style addressing, so the bucket is part of the path.
+	endpoint url.URL
+}
+
+// NewS3 returns a store that uploads objects to an S3 bucket from a URL of
+// the form s3://bucket/prefix. Credentials and the region are loaded from the
+// default AWS configuration sources, such as the AWS_* environment
+// variables. The region and an endpoint for S3-compatible services may also
+// be set with the region and endpoint query parameters.
+func NewS3(ctx context.Context, u *url.URL) (Store, error) {
+	if u.Host == "" {
+		return nil, xerrors.New("s3 bucket must be set")
+	}
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("load aws config: %w", err)
+	}
+	region := u.Query().Get("region")
+	if region == "" {
+		region = cfg.Region
+	}
+	if region == "" {
+		return nil, xerrors.New("s3 region must be set")
+	}
+	if cfg.Credentials == nil {
+		return nil, xerrors.New("no aws credentials found")
+	}
+
+	store := &s3Store{
+		client:      &http.Client{Timeout: time.Minute},
+		signer:      v4.NewSigner(),
+		credentials: cfg.Credentials,
+		region:      region,
+		bucket:      u.Host,
+		prefix:      strings.Trim(u.Path, "/"),
+	}
+	if raw := u.Query().Get("endpoint"); raw != "" {
+		endpoint, err := url.Parse(raw)
+		if err != nil {
+			return nil, xerrors.Errorf("parse s3 endpoint: %w", err)
+		}
+		store.endpoint = *endpoint
+		store.endpoint.Path = path.Join("/", endpoint.Path, store.bucket)
+	} else {
+		store.endpoint = url.URL{
+			Scheme: "https",
+			Host:   fmt.Sprintf("%s.s3.%s.amazonaws.com", store.bucket, region),
+			Path:   "/",
+		}
+	}
+	return store, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, data []byte) error {
+	u := s.endpoint
+	u.Path = path.Join(u.Path, s.prefix, key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("Content-Type", "application/gzip")
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	creds, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return xerrors.Errorf("retrieve aws credentials: %w", err)
+	}
+	err = s.signer.SignHTTP(ctx, creds, req, payloadHash, "s3", s.region, time.Now())
+	if err != nil {
+		return xerrors.Errorf("sign request: %w", err)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("upload object: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return xerrors.Errorf("upload object: unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func (s *s3Store) String() string {
+	u := url.URL{Scheme: "s3", Host: s.bucket, Path: "/" + s.prefix}
+	return u.String()
+}
@@ -0,0 +1,101 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+type kafkaSink struct {
+	client   *http.Client
+	endpoint string
+}
+
+// NewKafka returns a sink that produces audit logs to a Kafka topic through a
+// Kafka REST Proxy (v2 API). Each audit log is a JSON record keyed by its ID.
+func NewKafka(proxyURL *url.URL, topic string) (audit.Sink, error) {
+	if proxyURL == nil || proxyURL.Host == "" {
+		return nil, xerrors.New("kafka REST proxy URL must be set")
+	}
+	if topic == "" {
+		return nil, xerrors.New("kafka topic must be set")
+	}
+	return &kafkaSink{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		endpoint: proxyURL.JoinPath("topics", topic).String(),
+	}, nil
+}
+
+func (*kafkaSink) Name() string {
+	return "kafka"
+}
+
+type kafkaRecord struct {
+	Key   string      `json:"key"`
+	Value audit.Event `json:"value"`
+}
+
+type kafkaProduceRequest struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		ErrorCode *int    `json:"error_code"`
+		Error     *string `json:"error"`
+	} `json:"offsets"`
+}
+
+func (s *kafkaSink) Send(ctx context.Context, event audit.Event) error {
+	body, err := json.Marshal(kafkaProduceRequest{
+		Records: []kafkaRecord{{Key: event.ID.String(), Value: event}},
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal kafka records: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("produce kafka record: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return xerrors.Errorf("kafka REST proxy responded with status %d: %s", res.StatusCode, msg)
+	}
+
+	// The proxy reports failures for individual records in the offsets.
+	var produced kafkaProduceResponse
+	err = json.NewDecoder(res.Body).Decode(&produced)
+	if err != nil {
+		return xerrors.Errorf("decode kafka response: %w", err)
+	}
+	for _, offset := range produced.Offsets {
+		if offset.Error != nil {
+			return xerrors.Errorf("produce kafka record: %s", *offset.Error)
+		}
+		if offset.ErrorCode != nil {
+			return xerrors.Errorf("produce kafka record: error code %d", *offset.ErrorCode)
+		}
+	}
+	return nil
+}
+
+func (*kafkaSink) Close() error {
+	return nil
+}
@@ -0,0 +1,70 @@
+package backends_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestKafkaSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		records := make(chan map[string]any, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/topics/coder-audit" || r.Header.Get("Content-Type") != "application/vnd.kafka.json.v2+json" {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			var req struct {
+				Records []map[string]any `json:"records"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			records <- req.Records[0]
+			_, _ = rw.Write([]byte(`{"offsets":[{"partition":0,"offset":1,"error_code":null,"error":null}]}`))
+		}))
+		defer srv.Close()
+
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		sink, err := backends.NewKafka(u, "coder-audit")
+		require.NoError(t, err)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		alog := audittest.RandomLog()
+		err = sink.Send(ctx, audit.EventFromLog(alog))
+		require.NoError(t, err)
+
+		record := <-records
+		require.Equal(t, alog.ID.String(), record["key"])
+	})
+
+	t.Run("RecordError", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			_, _ = rw.Write([]byte(`{"offsets":[{"partition":null,"offset":null,"error_code":50003,"error":"leader not available"}]}`))
+		}))
+		defer srv.Close()
+
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		sink, err := backends.NewKafka(u, "coder-audit")
+		require.NoError(t, err)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err = sink.Send(ctx, audit.EventFromLog(audittest.RandomLog()))
+		require.ErrorContains(t, err, "leader not available")
+	})
+}
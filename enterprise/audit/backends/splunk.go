@@ -0,0 +1,97 @@
+package backends
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+type splunkHECSink struct {
+	client   *http.Client
+	endpoint string
+	token    string
+	hostname string
+}
+
+// NewSplunkHEC returns a sink that sends audit logs to a Splunk HTTP Event
+// Collector. If the URL has no path, the default event endpoint
+// /services/collector/event is used.
+func NewSplunkHEC(u *url.URL, token string) (audit.Sink, error) {
+	if u == nil || u.Host == "" {
+		return nil, xerrors.New("splunk HEC URL must be set")
+	}
+	if token == "" {
+		return nil, xerrors.New("splunk HEC token must be set")
+	}
+	endpoint := *u
+	if strings.Trim(endpoint.Path, "/") == "" {
+		endpoint.Path = "/services/collector/event"
+	}
+
+	hostname, _ := os.Hostname()
+	return &splunkHECSink{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		endpoint: endpoint.String(),
+		token:    token,
+		hostname: hostname,
+	}, nil
+}
+
+func (*splunkHECSink) Name() string {
+	return "splunk_hec"
+}
+
+// splunkHECEvent is the envelope expected by the HEC event endpoint.
+type splunkHECEvent struct {
+	Time       float64     `json:"time"`
+	Host       string      `json:"host,omitempty"`
+	Source     string      `json:"source"`
+	SourceType string      `json:"sourcetype"`
+	Event      audit.Event `json:"event"`
+}
+
+func (s *splunkHECSink) Send(ctx context.Context, event audit.Event) error {
+	body, err := json.Marshal(splunkHECEvent{
+		Time:       float64(event.Time.UnixMilli()) / 1000,
+		Host:       s.hostname,
+		Source:     "coder",
+		SourceType: "coder:audit",
+		Event:      event,
+	})
+	if err != nil {
+		return xerrors.Errorf("marshal splunk event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Splunk "+s.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return xerrors.Errorf("send splunk event: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return xerrors.Errorf("splunk HEC responded with status %d: %s", res.StatusCode, msg)
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	return nil
+}
+
+func (*splunkHECSink) Close() error {
+	return nil
+}
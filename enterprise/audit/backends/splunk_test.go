@@ -0,0 +1,69 @@
+package backends_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSplunkHECSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		events := make(chan map[string]any, 1)
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/services/collector/event" || r.Header.Get("Authorization") != "Splunk token" {
+				rw.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			var event map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&event)
+			events <- event
+			_, _ = rw.Write([]byte(`{"text":"Success","code":0}`))
+		}))
+		defer srv.Close()
+
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		sink, err := backends.NewSplunkHEC(u, "token")
+		require.NoError(t, err)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		alog := audittest.RandomLog()
+		err = sink.Send(ctx, audit.EventFromLog(alog))
+		require.NoError(t, err)
+
+		event := <-events
+		require.Equal(t, "coder:audit", event["sourcetype"])
+		require.Equal(t, alog.ID.String(), event["event"].(map[string]any)["id"])
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		sink, err := backends.NewSplunkHEC(u, "token")
+		require.NoError(t, err)
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		err = sink.Send(ctx, audit.EventFromLog(audittest.RandomLog()))
+		require.ErrorContains(t, err, "503")
+	})
+}
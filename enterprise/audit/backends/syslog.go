@@ -0,0 +1,99 @@
+package backends
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/enterprise/audit"
+)
+
+// syslogPriority is the authpriv facility (10) at informational severity (6).
+const syslogPriority = 10*8 + 6
+
+type syslogSink struct {
+	network  string
+	address  string
+	hostname string
+
+	conn net.Conn
+}
+
+// NewSyslog returns a sink that writes audit logs as RFC 5424 messages to a
+// syslog server. The address must be a udp:// or tcp:// URL, e.g.
+// udp://localhost:514. Messages sent over TCP use octet-counting framing.
+func NewSyslog(address string) (audit.Sink, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, xerrors.Errorf("parse syslog address: %w", err)
+	}
+	if u.Scheme != "udp" && u.Scheme != "tcp" {
+		return nil, xerrors.Errorf("syslog address scheme must be udp or tcp, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, xerrors.Errorf("syslog address %q has no host", address)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &syslogSink{
+		network:  u.Scheme,
+		address:  u.Host,
+		hostname: hostname,
+	}, nil
+}
+
+func (*syslogSink) Name() string {
+	return "syslog"
+}
+
+func (s *syslogSink) Send(ctx context.Context, event audit.Event) error {
+	msg, err := json.Marshal(event)
+	if err != nil {
+		return xerrors.Errorf("marshal audit event: %w", err)
+	}
+
+	if s.conn == nil {
+		var dialer net.Dialer
+		s.conn, err = dialer.DialContext(ctx, s.network, s.address)
+		if err != nil {
+			return xerrors.Errorf("dial syslog server: %w", err)
+		}
+	}
+
+	line := fmt.Sprintf("<%d>1 %s %s coder - audit - %s",
+		syslogPriority, event.Time.UTC().Format(time.RFC3339Nano), s.hostname, msg)
+	if s.network == "tcp" {
+		line = fmt.Sprintf("%d %s", len(line), line)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(10 * time.Second)
+	}
+	_ = s.conn.SetWriteDeadline(deadline)
+	_, err = io.WriteString(s.conn, line)
+	if err != nil {
+		// Reconnect on the next attempt.
+		_ = s.conn.Close()
+		s.conn = nil
+		return xerrors.Errorf("write syslog message: %w", err)
+	}
+	return nil
+}
+
+func (s *syslogSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
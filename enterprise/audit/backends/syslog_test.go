@@ -0,0 +1,77 @@
+package backends_test
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/enterprise/audit/backends"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestSyslogSink(t *testing.T) {
+	t.Parallel()
+
+	t.Run("UDP", func(t *testing.T) {
+		t.Parallel()
+
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+
+		sink, err := backends.NewSyslog("udp://" + conn.LocalAddr().String())
+		require.NoError(t, err)
+		defer sink.Close()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		alog := audittest.RandomLog()
+		err = sink.Send(ctx, audit.EventFromLog(alog))
+		require.NoError(t, err)
+
+		buf := make([]byte, 64*1024)
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		msg := string(buf[:n])
+		require.True(t, strings.HasPrefix(msg, "<86>1 "), msg)
+		require.Contains(t, msg, " coder - audit - ")
+		require.Contains(t, msg, alog.ID.String())
+	})
+
+	t.Run("TCP", func(t *testing.T) {
+		t.Parallel()
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln.Close()
+
+		sink, err := backends.NewSyslog("tcp://" + ln.Addr().String())
+		require.NoError(t, err)
+		defer sink.Close()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		alog := audittest.RandomLog()
+		err = sink.Send(ctx, audit.EventFromLog(alog))
+		require.NoError(t, err)
+
+		conn, err := ln.Accept()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// Messages are framed with their length.
+		length, err := bufio.NewReader(conn).ReadString(' ')
+		require.NoError(t, err)
+		require.NotEqual(t, "0 ", length)
+	})
+
+	t.Run("InvalidScheme", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := backends.NewSyslog("http://localhost:514")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,224 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/retry"
+)
+
+// sinkQueueSize is the number of audit logs buffered for each sink before new
+// audit logs are dropped.
+const sinkQueueSize = 1024
+
+// sinkSendAttempts is the number of times delivery of an audit log to a sink
+// is attempted before it's dropped.
+const sinkSendAttempts = 5
+
+// Sinks stream audit logs to external systems, such as a SIEM. Unlike
+// backends, sinks are written to asynchronously, so a slow or unavailable
+// sink never delays the request that produced the audit log.
+type Sink interface {
+	// Name identifies the sink in logs.
+	Name() string
+	// Send delivers an event to the sink. It's only called from a single
+	// goroutine at a time.
+	Send(ctx context.Context, event Event) error
+	// Close releases any resources held by the sink.
+	Close() error
+}
+
+// Event is the representation of an audit log sent to sinks.
+type Event struct {
+	ID               uuid.UUID       `json:"id"`
+	Time             time.Time       `json:"time"`
+	UserID           uuid.UUID       `json:"user_id"`
+	OrganizationID   uuid.UUID       `json:"organization_id"`
+	IP               string          `json:"ip"`
+	UserAgent        string          `json:"user_agent"`
+	ResourceType     string          `json:"resource_type"`
+	ResourceID       uuid.UUID       `json:"resource_id"`
+	ResourceTarget   string          `json:"resource_target"`
+	ResourceIcon     string          `json:"resource_icon"`
+	Action           string          `json:"action"`
+	Diff             json.RawMessage `json:"diff"`
+	StatusCode       int32           `json:"status_code"`
+	AdditionalFields json.RawMessage `json:"additional_fields"`
+	RequestID        uuid.UUID       `json:"request_id"`
+}
+
+// EventFromLog converts an audit log to the event sent to sinks.
+func EventFromLog(alog database.AuditLog) Event {
+	event := Event{
+		ID:               alog.ID,
+		Time:             alog.Time,
+		UserID:           alog.UserID,
+		OrganizationID:   alog.OrganizationID,
+		UserAgent:        alog.UserAgent.String,
+		ResourceType:     string(alog.ResourceType),
+		ResourceID:       alog.ResourceID,
+		ResourceTarget:   alog.ResourceTarget,
+		ResourceIcon:     alog.ResourceIcon,
+		Action:           string(alog.Action),
+		Diff:             alog.Diff,
+		StatusCode:       alog.StatusCode,
+		AdditionalFields: alog.AdditionalFields,
+		RequestID:        alog.RequestID,
+	}
+	if alog.Ip.Valid {
+		event.IP = alog.Ip.IPNet.IP.String()
+	}
+	// Keep the JSON valid when these weren't set.
+	if len(event.Diff) == 0 {
+		event.Diff = json.RawMessage("{}")
+	}
+	if len(event.AdditionalFields) == 0 {
+		event.AdditionalFields = json.RawMessage("{}")
+	}
+	return event
+}
+
+// FanOut is a Backend that streams exported audit logs to a set of sinks.
+// Each sink has its own queue and worker, so one sink falling behind doesn't
+// affect the others.
+type FanOut struct {
+	logger  slog.Logger
+	ctx     context.Context
+	cancel  context.CancelFunc
+	workers []*sinkWorker
+	wg      sync.WaitGroup
+
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+type sinkWorker struct {
+	sink  Sink
+	queue chan Event
+}
+
+// NewFanOut starts a worker for each sink. Close must be called to flush
+// queued audit logs and close the sinks.
+func NewFanOut(logger slog.Logger, sinks ...Sink) *FanOut {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &FanOut{
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for _, sink := range sinks {
+		w := &sinkWorker{
+			sink:  sink,
+			queue: make(chan Event, sinkQueueSize),
+		}
+		f.workers = append(f.workers, w)
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			f.run(w)
+		}()
+	}
+	return f
+}
+
+func (*FanOut) Decision() FilterDecision {
+	return FilterDecisionExport
+}
+
+// Export queues the audit log for every sink. It never blocks; if a sink's
+// queue is full the audit log is dropped for that sink.
+func (f *FanOut) Export(ctx context.Context, alog database.AuditLog) error {
+	f.closeMu.RLock()
+	defer f.closeMu.RUnlock()
+	if f.closed {
+		return xerrors.New("audit sinks are closed")
+	}
+
+	event := EventFromLog(alog)
+	for _, w := range f.workers {
+		select {
+		case w.queue <- event:
+		default:
+			f.logger.Warn(ctx, "audit sink queue is full, dropping audit log",
+				slog.F("sink", w.sink.Name()),
+				slog.F("audit_log_id", alog.ID),
+			)
+		}
+	}
+	return nil
+}
+
+func (f *FanOut) run(w *sinkWorker) {
+	logger := f.logger.With(slog.F("sink", w.sink.Name()))
+	for event := range w.queue {
+		err := f.send(w.sink, event)
+		if err != nil {
+			logger.Error(f.ctx, "failed to send audit log to sink",
+				slog.F("audit_log_id", event.ID),
+				slog.Error(err),
+			)
+		}
+	}
+}
+
+// send delivers the event to the sink, retrying with backoff on failure.
+func (f *FanOut) send(sink Sink, event Event) error {
+	r := retry.New(100*time.Millisecond, 5*time.Second)
+	for attempt := 1; ; attempt++ {
+		err := sink.Send(f.ctx, event)
+		if err == nil {
+			return nil
+		}
+		if attempt >= sinkSendAttempts {
+			return xerrors.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+		if !r.Wait(f.ctx) {
+			return xerrors.Errorf("abandoned after %d attempts: %w", attempt, err)
+		}
+	}
+}
+
+// Close flushes queued audit logs and closes the sinks. Delivery is abandoned
+// once ctx is done.
+func (f *FanOut) Close(ctx context.Context) error {
+	f.closeMu.Lock()
+	if f.closed {
+		f.closeMu.Unlock()
+		return nil
+	}
+	f.closed = true
+	for _, w := range f.workers {
+		close(w.queue)
+	}
+	f.closeMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f.wg.Wait()
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		f.cancel()
+		<-done
+	}
+	f.cancel()
+
+	var errs error
+	for _, w := range f.workers {
+		err := w.sink.Close()
+		if err != nil {
+			errs = errors.Join(errs, xerrors.Errorf("close %s: %w", w.sink.Name(), err))
+		}
+	}
+	return errs
+}
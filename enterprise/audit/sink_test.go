@@ -0,0 +1,100 @@
+package audit_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/audittest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestFanOut(t *testing.T) {
+	t.Parallel()
+
+	t.Run("DeliversToAllSinks", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		first, second := &testSink{}, &testSink{}
+		fanOut := audit.NewFanOut(logger, first, second)
+
+		alog := audittest.RandomLog()
+		err := fanOut.Export(ctx, alog)
+		require.NoError(t, err)
+
+		// Close flushes the queues.
+		err = fanOut.Close(ctx)
+		require.NoError(t, err)
+		for _, sink := range []*testSink{first, second} {
+			events := sink.Events()
+			require.Len(t, events, 1)
+			require.Equal(t, alog.ID, events[0].ID)
+			require.Equal(t, "127.0.0.1", events[0].IP)
+			require.True(t, sink.closed)
+		}
+
+		err = fanOut.Export(ctx, alog)
+		require.Error(t, err)
+	})
+
+	t.Run("Retries", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		failing := &testSink{failures: 2}
+		healthy := &testSink{}
+		fanOut := audit.NewFanOut(logger, failing, healthy)
+
+		alog := audittest.RandomLog()
+		err := fanOut.Export(ctx, alog)
+		require.NoError(t, err)
+
+		err = fanOut.Close(ctx)
+		require.NoError(t, err)
+		require.Len(t, failing.Events(), 1)
+		require.Len(t, healthy.Events(), 1)
+	})
+}
+
+type testSink struct {
+	mu       sync.Mutex
+	failures int
+	events   []audit.Event
+	closed   bool
+}
+
+func (*testSink) Name() string {
+	return "fake"
+}
+
+func (s *testSink) Send(_ context.Context, event audit.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return xerrors.New("sink unavailable")
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *testSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *testSink) Events() []audit.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]audit.Event(nil), s.events...)
+}
@@ -16,15 +16,21 @@ import (
 // AuditableResources map (below) as our documentation - generated in scripts/auditdocgen/main.go -
 // depends upon it.
 var AuditActionMap = map[string][]codersdk.AuditAction{
-	"GitSSHKey":       {codersdk.AuditActionCreate},
-	"Template":        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"TemplateVersion": {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
-	"User":            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"Workspace":       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"WorkspaceBuild":  {codersdk.AuditActionStart, codersdk.AuditActionStop},
-	"Group":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
-	"APIKey":          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
-	"License":         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"GitSSHKey":                       {codersdk.AuditActionCreate},
+	"Template":                        {codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateVersion":                 {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"User":                            {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"Workspace":                       {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete, codersdk.AuditActionOpen, codersdk.AuditActionConnect},
+	"WorkspaceBuild":                  {codersdk.AuditActionStart, codersdk.AuditActionStop},
+	"Group":                           {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"APIKey":                          {codersdk.AuditActionLogin, codersdk.AuditActionLogout, codersdk.AuditActionRegister, codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"License":                         {codersdk.AuditActionCreate, codersdk.AuditActionDelete},
+	"WorkspaceWebhook":                {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"WorkspaceApproval":               {codersdk.AuditActionWrite},
+	"ManagedEnvironmentVariable":      {codersdk.AuditActionCreate, codersdk.AuditActionWrite, codersdk.AuditActionDelete},
+	"TemplateAccessRequest":           {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"TemplateVersionPromotion":        {codersdk.AuditActionCreate, codersdk.AuditActionWrite},
+	"WorkspaceAgentAuthTokenRotation": {codersdk.AuditActionCreate},
 }
 
 type Action string
@@ -58,32 +64,45 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"public_key":  ActionTrack,  // Public keys are ok to expose in a diff.
 	},
 	&database.Template{}: {
-		"id":                               ActionTrack,
-		"created_at":                       ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
-		"updated_at":                       ActionIgnore, // Changes, but is implicit and not helpful in a diff.
-		"organization_id":                  ActionIgnore, /// Never changes.
-		"deleted":                          ActionIgnore, // Changes, but is implicit when a delete event is fired.
-		"name":                             ActionTrack,
-		"display_name":                     ActionTrack,
-		"provisioner":                      ActionTrack,
-		"active_version_id":                ActionTrack,
-		"description":                      ActionTrack,
-		"icon":                             ActionTrack,
-		"default_ttl":                      ActionTrack,
-		"max_ttl":                          ActionTrack,
-		"restart_requirement_days_of_week": ActionTrack,
-		"restart_requirement_weeks":        ActionTrack,
-		"created_by":                       ActionTrack,
-		"created_by_username":              ActionIgnore,
-		"created_by_avatar_url":            ActionIgnore,
-		"group_acl":                        ActionTrack,
-		"user_acl":                         ActionTrack,
-		"allow_user_autostart":             ActionTrack,
-		"allow_user_autostop":              ActionTrack,
-		"allow_user_cancel_workspace_jobs": ActionTrack,
-		"failure_ttl":                      ActionTrack,
-		"inactivity_ttl":                   ActionTrack,
-		"locked_ttl":                       ActionTrack,
+		"id":                                  ActionTrack,
+		"created_at":                          ActionIgnore, // Never changes, but is implicit and not helpful in a diff.
+		"updated_at":                          ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"organization_id":                     ActionIgnore, /// Never changes.
+		"deleted":                             ActionIgnore, // Changes, but is implicit when a delete event is fired.
+		"name":                                ActionTrack,
+		"display_name":                        ActionTrack,
+		"provisioner":                         ActionTrack,
+		"active_version_id":                   ActionTrack,
+		"description":                         ActionTrack,
+		"icon":                                ActionTrack,
+		"default_ttl":                         ActionTrack,
+		"max_ttl":                             ActionTrack,
+		"restart_requirement_days_of_week":    ActionTrack,
+		"restart_requirement_weeks":           ActionTrack,
+		"created_by":                          ActionTrack,
+		"created_by_username":                 ActionIgnore,
+		"created_by_avatar_url":               ActionIgnore,
+		"group_acl":                           ActionTrack,
+		"user_acl":                            ActionTrack,
+		"allow_user_autostart":                ActionTrack,
+		"allow_user_autostop":                 ActionTrack,
+		"allow_user_cancel_workspace_jobs":    ActionTrack,
+		"failure_ttl":                         ActionTrack,
+		"inactivity_ttl":                      ActionTrack,
+		"locked_ttl":                          ActionTrack,
+		"require_active_version":              ActionTrack,
+		"require_active_version_grace_period": ActionTrack,
+		"active_version_updated_at":           ActionIgnore, // Changes, but is implicit when the active version changes.
+		"max_build_duration":                  ActionTrack,
+		"provisioner_memory_limit":            ActionTrack,
+		"max_concurrent_provisioner_jobs":     ActionTrack,
+		"max_session_idle":                    ActionTrack,
+		"port_policy":                         ActionTrack,
+		"provisioner_cpu_limit":               ActionTrack,
+		"require_workspace_approval":          ActionTrack,
+		"require_agent_binary_verification":   ActionTrack,
+		"autostop_activity_sources":           ActionTrack,
+		"require_promotion_approval":          ActionTrack,
 	},
 	&database.TemplateVersion{}: {
 		"id":                    ActionTrack,
@@ -129,6 +148,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"last_used_at":       ActionIgnore,
 		"locked_at":          ActionTrack,
 		"deleting_at":        ActionTrack,
+		"automatic_updates":  ActionTrack,
 	},
 	&database.WorkspaceBuild{}: {
 		"id":                      ActionIgnore,
@@ -145,6 +165,7 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"reason":                  ActionIgnore,
 		"daily_cost":              ActionIgnore,
 		"max_deadline":            ActionIgnore,
+		"initiator_token_name":    ActionIgnore,
 		"initiator_by_avatar_url": ActionIgnore,
 		"initiator_by_username":   ActionIgnore,
 	},
@@ -155,8 +176,11 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"organization_id": ActionIgnore, // Never changes.
 		"avatar_url":      ActionTrack,
 		"quota_allowance": ActionTrack,
+		"quota_override":  ActionTrack,
 		"members":         ActionTrack,
 		"source":          ActionIgnore,
+		"avatar_file_id":  ActionIgnore, // The avatar URL changes with it.
+		"metadata":        ActionTrack,
 	},
 	&database.APIKey{}: {
 		"id":               ActionIgnore,
@@ -182,11 +206,12 @@ var auditableResourcesTypes = map[any]map[string]Action{
 	// TODO: track an ID here when the below ticket is completed:
 	// https://github.com/coder/coder/pull/6012
 	&database.License{}: {
-		"id":          ActionIgnore,
-		"uploaded_at": ActionTrack,
-		"jwt":         ActionIgnore,
-		"exp":         ActionTrack,
-		"uuid":        ActionTrack,
+		"id":             ActionIgnore,
+		"uploaded_at":    ActionTrack,
+		"jwt":            ActionIgnore,
+		"exp":            ActionTrack,
+		"uuid":           ActionTrack,
+		"activation_key": ActionSecret, // The key can be exchanged for new licenses.
 	},
 	&database.WorkspaceProxy{}: {
 		"id":                  ActionTrack,
@@ -202,6 +227,73 @@ var auditableResourcesTypes = map[any]map[string]Action{
 		"derp_enabled":        ActionTrack,
 		"derp_only":           ActionTrack,
 		"region_id":           ActionTrack,
+		"organization_ids":    ActionTrack,
+		"group_ids":           ActionTrack,
+	},
+	&database.WorkspaceWebhook{}: {
+		"id":                ActionTrack,
+		"workspace_id":      ActionTrack,
+		"created_by":        ActionTrack,
+		"created_at":        ActionIgnore, // Never changes.
+		"name":              ActionTrack,
+		"action":            ActionTrack,
+		"agent_name":        ActionTrack,
+		"command":           ActionTrack,
+		"secret":            ActionSecret,
+		"last_triggered_at": ActionTrack,
+	},
+	&database.WorkspaceApproval{}: {
+		"id":           ActionTrack,
+		"workspace_id": ActionTrack,
+		"job_id":       ActionTrack,
+		"requested_by": ActionTrack,
+		"created_at":   ActionIgnore, // Never changes.
+		"status":       ActionTrack,
+		"decided_by":   ActionTrack,
+		"decided_at":   ActionTrack,
+		"reason":       ActionTrack,
+	},
+	&database.ManagedEnvironmentVariable{}: {
+		"id":              ActionTrack,
+		"organization_id": ActionTrack,
+		"template_id":     ActionTrack,
+		"created_at":      ActionIgnore, // Never changes.
+		"updated_at":      ActionIgnore, // Changes, but is implicit and not helpful in a diff.
+		"name":            ActionTrack,
+		"value":           ActionSecret,
+		"secret":          ActionTrack,
+	},
+	&database.TemplateAccessRequest{}: {
+		"id":           ActionTrack,
+		"template_id":  ActionTrack,
+		"requested_by": ActionTrack,
+		"created_at":   ActionIgnore, // Never changes.
+		"message":      ActionTrack,
+		"status":       ActionTrack,
+		"decided_by":   ActionTrack,
+		"decided_at":   ActionTrack,
+		"reason":       ActionTrack,
+	},
+	&database.TemplateVersionPromotion{}: {
+		"id":                  ActionTrack,
+		"organization_id":     ActionIgnore, // Never changes.
+		"template_id":         ActionTrack,
+		"template_version_id": ActionTrack,
+		"requested_by":        ActionTrack,
+		"created_at":          ActionIgnore, // Never changes.
+		"message":             ActionTrack,
+		"status":              ActionTrack,
+		"decided_by":          ActionTrack,
+		"decided_at":          ActionTrack,
+		"reason":              ActionTrack,
+	},
+	&database.WorkspaceAgentAuthTokenRotation{}: {
+		"id":                             ActionTrack,
+		"agent_id":                       ActionTrack,
+		"previous_auth_token":            ActionSecret, // The token may still be valid.
+		"previous_auth_token_expires_at": ActionTrack,
+		"revoked":                        ActionTrack,
+		"created_at":                     ActionIgnore, // Never changes.
 	},
 }
 
@@ -30,6 +30,7 @@ func (r *RootCmd) licenses() *clibase.Cmd {
 		},
 		Children: []*clibase.Cmd{
 			r.licenseAdd(),
+			r.licenseActivate(),
 			r.licensesList(),
 			r.licenseDelete(),
 		},
@@ -129,6 +130,31 @@ func (r *RootCmd) licenseAdd() *clibase.Cmd {
 	return cmd
 }
 
+func (r *RootCmd) licenseActivate() *clibase.Cmd {
+	client := new(codersdk.Client)
+	cmd := &clibase.Cmd{
+		Use:   "activate <activation-key>",
+		Short: "Activate a license online with an activation key",
+		Long: "The deployment exchanges the key for a license with its activation server, " +
+			"and renews the license from it before it expires.",
+		Middleware: clibase.Chain(
+			clibase.RequireNArgs(1),
+			r.InitClient(client),
+		),
+		Handler: func(inv *clibase.Invocation) error {
+			licResp, err := client.ActivateLicense(inv.Context(), codersdk.ActivateLicenseRequest{
+				ActivationKey: strings.TrimSpace(inv.Args[0]),
+			})
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(inv.Stdout, "License with ID %d activated\n", licResp.ID)
+			return nil
+		},
+	}
+	return cmd
+}
+
 func validJWT(s string) error {
 	if jwtRegexp.MatchString(s) {
 		return nil
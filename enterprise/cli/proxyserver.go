@@ -57,6 +57,7 @@ func (*RootCmd) proxyServer() *clibase.Cmd {
 		proxySessionToken clibase.String
 		primaryAccessURL  clibase.URL
 		derpOnly          clibase.Bool
+		appAssetCacheSize clibase.Int64
 	)
 	opts.Add(
 		// Options only for external workspace proxies
@@ -100,6 +101,16 @@ func (*RootCmd) proxyServer() *clibase.Cmd {
 			Group:       &externalProxyOptionGroup,
 			Hidden:      false,
 		},
+		clibase.Option{
+			Name:        "App Asset Cache Size",
+			Description: "Maximum size in bytes of the cache of immutable static assets served by workspace apps, such as the scripts of web IDEs. Only responses marked immutable with Cache-Control are cached. Set to 0 to disable caching.",
+			Flag:        "app-asset-cache-size",
+			Env:         "CODER_PROXY_APP_ASSET_CACHE_SIZE",
+			YAML:        "appAssetCacheSize",
+			Default:     "0",
+			Value:       &appAssetCacheSize,
+			Group:       &externalProxyOptionGroup,
+		},
 	)
 
 	cmd := &clibase.Cmd{
@@ -236,24 +247,27 @@ func (*RootCmd) proxyServer() *clibase.Cmd {
 			}
 
 			proxy, err := wsproxy.New(ctx, &wsproxy.Options{
-				Logger:                 logger,
-				Experiments:            coderd.ReadExperiments(logger, cfg.Experiments.Value()),
-				HTTPClient:             httpClient,
-				DashboardURL:           primaryAccessURL.Value(),
-				AccessURL:              cfg.AccessURL.Value(),
-				AppHostname:            appHostname,
-				AppHostnameRegex:       appHostnameRegex,
-				RealIPConfig:           realIPConfig,
-				Tracing:                tracer,
-				PrometheusRegistry:     prometheusRegistry,
-				APIRateLimit:           int(cfg.RateLimit.API.Value()),
-				SecureAuthCookie:       cfg.SecureAuthCookie.Value(),
-				DisablePathApps:        cfg.DisablePathApps.Value(),
-				ProxySessionToken:      proxySessionToken.Value(),
-				AllowAllCors:           cfg.Dangerous.AllowAllCors.Value(),
-				DERPEnabled:            cfg.DERP.Server.Enable.Value(),
-				DERPOnly:               derpOnly.Value(),
-				DERPServerRelayAddress: cfg.DERP.Server.RelayURL.String(),
+				Logger:                      logger,
+				Experiments:                 coderd.ReadExperiments(logger, cfg.Experiments.Value()),
+				HTTPClient:                  httpClient,
+				DashboardURL:                primaryAccessURL.Value(),
+				AccessURL:                   cfg.AccessURL.Value(),
+				AppHostname:                 appHostname,
+				AppHostnameRegex:            appHostnameRegex,
+				RealIPConfig:                realIPConfig,
+				Tracing:                     tracer,
+				PrometheusRegistry:          prometheusRegistry,
+				APIRateLimit:                int(cfg.RateLimit.API.Value()),
+				SecureAuthCookie:            cfg.SecureAuthCookie.Value(),
+				DisablePathApps:             cfg.DisablePathApps.Value(),
+				ProxySessionToken:           proxySessionToken.Value(),
+				AllowAllCors:                cfg.Dangerous.AllowAllCors.Value(),
+				DERPEnabled:                 cfg.DERP.Server.Enable.Value(),
+				DERPOnly:                    derpOnly.Value(),
+				DERPServerRelayAddress:      cfg.DERP.Server.RelayURL.String(),
+				AppAssetCacheSize:           int(appAssetCacheSize.Value()),
+				AgentConnectionCacheMaxSize: int(cfg.AgentConnectionCache.MaxSize.Value()),
+				AgentConnectionCacheMaxAge:  cfg.AgentConnectionCache.MaxAge.Value(),
 			})
 			if err != nil {
 				return xerrors.Errorf("create workspace proxy: %w", err)
@@ -7,17 +7,23 @@ import (
 	"database/sql"
 	"errors"
 	"io"
+	"net/http"
 	"net/url"
+	"time"
 
 	"golang.org/x/xerrors"
 	"tailscale.com/derp"
 	"tailscale.com/types/key"
 
 	"github.com/coder/coder/v2/cli/clibase"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/enterprise/audit"
+	"github.com/coder/coder/v2/enterprise/audit/archive"
 	"github.com/coder/coder/v2/enterprise/audit/backends"
 	"github.com/coder/coder/v2/enterprise/coderd"
+	"github.com/coder/coder/v2/enterprise/opa"
 	"github.com/coder/coder/v2/enterprise/trialer"
 	"github.com/coder/coder/v2/tailnet"
 
@@ -49,31 +55,168 @@ func (r *RootCmd) server() *clibase.Cmd {
 			}
 		}
 		options.DERPServer.SetMeshKey(meshKey)
-		options.Auditor = audit.NewAuditor(audit.DefaultFilter,
+		var auditLogArchive archive.Store
+		if options.DeploymentValues.AuditArchival.Destination.String() != "" {
+			auditLogArchive, err = archive.New(ctx, options.DeploymentValues.AuditArchival.Destination.Value())
+			if err != nil {
+				return nil, nil, xerrors.Errorf("audit-logs-archive-destination: %w", err)
+			}
+		}
+
+		externalAuthz, err := newExternalAuthorizer(ctx, options)
+		if err != nil {
+			return nil, nil, err
+		}
+		if externalAuthz != nil {
+			// The policy is enabled once the entitlements allow it.
+			externalAuthz.SetEnabled(false)
+			options.Authorizer = rbac.Cacher(externalAuthz)
+		}
+
+		auditBackends := []audit.Backend{
 			backends.NewPostgres(options.Database, true),
 			backends.NewSlog(options.Logger),
-		)
+		}
+		auditSinks, err := newAuditSinks(options.DeploymentValues.AuditSinks)
+		if err != nil {
+			return nil, nil, err
+		}
+		var auditFanOut *audit.FanOut
+		if len(auditSinks) > 0 {
+			auditFanOut = audit.NewFanOut(options.Logger.Named("audit_sinks"), auditSinks...)
+			auditBackends = append(auditBackends, auditFanOut)
+		}
+		options.Auditor = audit.NewAuditor(audit.DefaultFilter, auditBackends...)
 
 		options.TrialGenerator = trialer.New(options.Database, "https://v2-licensor.coder.com/trial", coderd.Keys)
 
+		proxyGeoRules, err := coderd.ParseProxyGeoRules(options.DeploymentValues.ProxyGeoRules.Value())
+		if err != nil {
+			return nil, nil, xerrors.Errorf("proxy-geo-rules: %w", err)
+		}
+		switch policy := codersdk.ProxyVersionMismatchPolicy(options.DeploymentValues.ProxyVersionMismatchPolicy.Value()); policy {
+		case codersdk.ProxyVersionMismatchWarn, codersdk.ProxyVersionMismatchBlock:
+		default:
+			return nil, nil, xerrors.Errorf("proxy-version-mismatch-policy must be %q or %q, got %q", codersdk.ProxyVersionMismatchWarn, codersdk.ProxyVersionMismatchBlock, policy)
+		}
+
 		o := &coderd.Options{
-			Options:                   options,
-			AuditLogging:              true,
-			BrowserOnly:               options.DeploymentValues.BrowserOnly.Value(),
-			SCIMAPIKey:                []byte(options.DeploymentValues.SCIMAPIKey.Value()),
-			RBAC:                      true,
-			DERPServerRelayAddress:    options.DeploymentValues.DERP.Server.RelayURL.String(),
-			DERPServerRegionID:        int(options.DeploymentValues.DERP.Server.RegionID.Value()),
-			ProxyHealthInterval:       options.DeploymentValues.ProxyHealthStatusInterval.Value(),
-			DefaultQuietHoursSchedule: options.DeploymentValues.UserQuietHoursSchedule.DefaultSchedule.Value(),
-			ProvisionerDaemonPSK:      options.DeploymentValues.Provisioner.DaemonPSK.Value(),
+			Options:                       options,
+			AuditLogging:                  true,
+			AuditLogRetention:             options.DeploymentValues.AuditArchival.Retention.Value(),
+			AuditLogArchive:               auditLogArchive,
+			BrowserOnly:                   options.DeploymentValues.BrowserOnly.Value(),
+			SCIMAPIKey:                    []byte(options.DeploymentValues.SCIMAPIKey.Value()),
+			SCIMDeprovisionStopWorkspaces: options.DeploymentValues.SCIMDeprovisionStopWorkspaces.Value(),
+			SCIMDeprovisionDeleteAfter:    options.DeploymentValues.SCIMDeprovisionDeleteAfter.Value(),
+			RBAC:                          true,
+			DERPServerRelayAddress:        options.DeploymentValues.DERP.Server.RelayURL.String(),
+			DERPServerRegionID:            int(options.DeploymentValues.DERP.Server.RegionID.Value()),
+			ProxyHealthInterval:           options.DeploymentValues.ProxyHealthStatusInterval.Value(),
+			DefaultQuietHoursSchedule:     options.DeploymentValues.UserQuietHoursSchedule.DefaultSchedule.Value(),
+			ProvisionerDaemonPSK:          options.DeploymentValues.Provisioner.DaemonPSK.Value(),
+			EntitlementsUpdateJitter:      options.DeploymentValues.EntitlementsRefreshJitter.Value(),
+			ProxyGeoRules:                 proxyGeoRules,
+			LicenseActivationURL:          options.DeploymentValues.LicenseActivationURL.String(),
+			ExternalAuthorizer:            externalAuthz,
 		}
 
 		api, err := coderd.New(ctx, o)
 		if err != nil {
+			if auditFanOut != nil {
+				_ = auditFanOut.Close(ctx)
+			}
 			return nil, nil, err
 		}
-		return api.AGPL, api, nil
+		if auditFanOut == nil {
+			return api.AGPL, api, nil
+		}
+		return api.AGPL, closerFunc(func() error {
+			err := api.Close()
+			// Give the sinks a moment to flush audit logs produced during
+			// shutdown.
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = auditFanOut.Close(ctx)
+			return err
+		}), nil
 	})
 	return cmd
 }
+
+// newAuditSinks returns the audit sinks enabled in the deployment config.
+func newAuditSinks(cfg codersdk.AuditSinksConfig) ([]audit.Sink, error) {
+	var sinks []audit.Sink
+	if cfg.SyslogAddress.String() != "" {
+		sink, err := backends.NewSyslog(cfg.SyslogAddress.String())
+		if err != nil {
+			return nil, xerrors.Errorf("audit-syslog-address: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.SplunkHECURL.String() != "" {
+		sink, err := backends.NewSplunkHEC(cfg.SplunkHECURL.Value(), cfg.SplunkHECToken.String())
+		if err != nil {
+			return nil, xerrors.Errorf("audit-splunk-hec-url: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.KafkaRESTProxyURL.String() != "" {
+		sink, err := backends.NewKafka(cfg.KafkaRESTProxyURL.Value(), cfg.KafkaTopic.String())
+		if err != nil {
+			return nil, xerrors.Errorf("audit-kafka-rest-proxy-url: %w", err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// externalAuthzTimeout bounds requests to the OPA server, since every
+// authorization waits for its decision.
+const externalAuthzTimeout = 5 * time.Second
+
+// newExternalAuthorizer returns an authorizer that delegates decisions to the
+// configured Open Policy Agent policy, or nil if none is configured.
+func newExternalAuthorizer(ctx context.Context, options *agplcoderd.Options) (*opa.Authorizer, error) {
+	cfg := options.DeploymentValues.ExternalAuthz
+	if cfg.URL.String() != "" && cfg.PolicyDir.String() != "" {
+		return nil, xerrors.New("external-authz-url and external-authz-policy-dir can't be used together")
+	}
+	fallback := opa.Fallback(cfg.Fallback.String())
+	switch fallback {
+	case opa.FallbackDeny, opa.FallbackBuiltin:
+	default:
+		return nil, xerrors.Errorf("external-authz-fallback must be %q or %q, got %q", opa.FallbackDeny, opa.FallbackBuiltin, fallback)
+	}
+
+	var policy opa.Policy
+	switch {
+	case cfg.URL.String() != "":
+		client := &http.Client{}
+		if options.HTTPClient != nil {
+			*client = *options.HTTPClient
+		}
+		client.Timeout = externalAuthzTimeout
+		policy = opa.NewServerPolicy(client, cfg.URL.Value())
+	case cfg.PolicyDir.String() != "":
+		var err error
+		policy, err = opa.NewRegoPolicy(ctx, cfg.PolicyDir.String())
+		if err != nil {
+			return nil, xerrors.Errorf("external-authz-policy-dir: %w", err)
+		}
+	default:
+		return nil, nil
+	}
+	return opa.NewAuthorizer(rbac.NewAuthorizer(options.PrometheusRegistry), policy, opa.Options{
+		Logger:   options.Logger.Named("external_authz"),
+		Registry: options.PrometheusRegistry,
+		Fallback: fallback,
+		CacheTTL: cfg.CacheTTL.Value(),
+	}), nil
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
@@ -177,5 +177,6 @@ func (api *API) putAppearance(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	api.publishWorkspaceProxyConfig(ctx)
 	httpapi.Write(r.Context(), rw, http.StatusOK, appearance)
 }
@@ -0,0 +1,330 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/audit/archive"
+)
+
+const (
+	// auditLogArchivalBatchSize is the number of audit logs written to each
+	// archived object and deleted in each transaction.
+	auditLogArchivalBatchSize = 1000
+	// auditLogArchivalsLimit is the number of most recent runs listed.
+	auditLogArchivalsLimit = 100
+)
+
+// @Summary Get audit log archivals
+// @Description Returns the most recent audit log archival runs, newest first.
+// @ID get-audit-log-archivals
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Success 200 {array} codersdk.AuditLogArchival
+// @Router /audit/archivals [get]
+func (api *API) auditLogArchivals(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	archivals, err := api.Database.GetAuditLogArchivals(ctx, auditLogArchivalsLimit)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching audit log archivals.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	res := make([]codersdk.AuditLogArchival, 0, len(archivals))
+	for _, archival := range archivals {
+		res = append(res, convertAuditLogArchival(archival))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, res)
+}
+
+// @Summary Get audit log archival by ID
+// @ID get-audit-log-archival-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param archival path string true "Audit log archival ID" format(uuid)
+// @Success 200 {object} codersdk.AuditLogArchival
+// @Router /audit/archivals/{archival} [get]
+func (api *API) auditLogArchival(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	archivalID, ok := httpmw.ParseUUIDParam(rw, r, "archival")
+	if !ok {
+		return
+	}
+
+	archival, err := api.Database.GetAuditLogArchivalByID(ctx, archivalID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching audit log archival.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertAuditLogArchival(archival))
+}
+
+// @Summary Create audit log archival
+// @Description Starts a run that archives audit logs older than the cutoff to
+// @Description the configured destination and deletes them. The run continues
+// @Description in the background.
+// @ID create-audit-log-archival
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param request body codersdk.CreateAuditLogArchivalRequest true "Create audit log archival request"
+// @Success 202 {object} codersdk.AuditLogArchival
+// @Router /audit/archivals [post]
+func (api *API) postAuditLogArchival(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx    = r.Context()
+		apiKey = httpmw.APIKey(r)
+	)
+	// Archiving deletes audit logs, which nobody but owners may do.
+	if !api.Authorize(r, rbac.ActionDelete, rbac.ResourceAuditLog) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.CreateAuditLogArchivalRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	now := time.Now()
+	cutoff := req.Cutoff
+	if cutoff.IsZero() {
+		if api.AuditLogRetention <= 0 {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "A cutoff is required when no audit log retention is configured.",
+			})
+			return
+		}
+		cutoff = now.Add(-api.AuditLogRetention)
+	}
+	if cutoff.After(now) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The cutoff must not be in the future.",
+		})
+		return
+	}
+
+	//nolint:gocritic // Only owners get here, and they can't create
+	// archivals, which are system resources, directly.
+	archival, err := api.insertAuditLogArchival(dbauthz.AsSystemRestricted(ctx), uuid.NullUUID{UUID: apiKey.UserID, Valid: true}, cutoff)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating audit log archival.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	go api.archiveAuditLogs(api.ctx, archival)
+
+	httpapi.Write(ctx, rw, http.StatusAccepted, convertAuditLogArchival(archival))
+}
+
+// runAuditLogArchivalLoop periodically archives the audit logs that are older
+// than the retention.
+func (api *API) runAuditLogArchivalLoop(ctx context.Context) {
+	ticker := time.NewTicker(api.AuditLogArchivalInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := api.archiveExpiredAuditLogs(ctx, time.Now())
+		if err != nil && !xerrors.Is(err, context.Canceled) {
+			api.Logger.Error(ctx, "archive expired audit logs", slog.Error(err))
+		}
+	}
+}
+
+// archiveExpiredAuditLogs archives the audit logs that are older than the
+// retention. No run is recorded when there's nothing to archive.
+func (api *API) archiveExpiredAuditLogs(ctx context.Context, now time.Time) error {
+	//nolint:gocritic // The system applies the retention policy without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	cutoff := now.Add(-api.AuditLogRetention)
+	expired, err := api.Database.GetAuditLogsOlderThan(ctx, database.GetAuditLogsOlderThanParams{
+		Before:   cutoff,
+		RowLimit: 1,
+	})
+	if err != nil {
+		return xerrors.Errorf("get expired audit logs: %w", err)
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	archival, err := api.insertAuditLogArchival(ctx, uuid.NullUUID{}, cutoff)
+	if err != nil {
+		return err
+	}
+	archival = api.archiveAuditLogs(ctx, archival)
+	if archival.Status == database.AuditLogArchivalStatusFailed {
+		return xerrors.New(archival.Error)
+	}
+	return nil
+}
+
+func (api *API) insertAuditLogArchival(ctx context.Context, initiatorID uuid.NullUUID, cutoff time.Time) (database.AuditLogArchival, error) {
+	var destination string
+	if api.AuditLogArchive != nil {
+		destination = api.AuditLogArchive.String()
+	}
+	archival, err := api.Database.InsertAuditLogArchival(ctx, database.InsertAuditLogArchivalParams{
+		ID:          uuid.New(),
+		InitiatorID: initiatorID,
+		Cutoff:      cutoff,
+		Destination: destination,
+		StartedAt:   database.Now(),
+	})
+	if err != nil {
+		return database.AuditLogArchival{}, xerrors.Errorf("insert audit log archival: %w", err)
+	}
+	return archival, nil
+}
+
+// archiveAuditLogs runs an archival to completion and records its result.
+func (api *API) archiveAuditLogs(ctx context.Context, archival database.AuditLogArchival) database.AuditLogArchival {
+	//nolint:gocritic // The system archives audit logs on behalf of the
+	// initiator, who was authorized when the run was created.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	logger := api.Logger.With(slog.F("archival_id", archival.ID), slog.F("cutoff", archival.Cutoff))
+
+	count, err := api.archiveAuditLogBatches(ctx, archival)
+	params := database.UpdateAuditLogArchivalByIDParams{
+		ID:            archival.ID,
+		Status:        database.AuditLogArchivalStatusSucceeded,
+		ArchivedCount: count,
+		FinishedAt:    sql.NullTime{Time: database.Now(), Valid: true},
+	}
+	if err != nil {
+		logger.Warn(ctx, "archive audit logs", slog.F("archived_count", count), slog.Error(err))
+		params.Status = database.AuditLogArchivalStatusFailed
+		params.Error = err.Error()
+	} else {
+		logger.Info(ctx, "archived audit logs", slog.F("archived_count", count))
+	}
+
+	// The result is recorded even if the run was interrupted by shutdown.
+	//nolint:gocritic // See above.
+	updateCtx, cancel := context.WithTimeout(dbauthz.AsSystemRestricted(context.Background()), 10*time.Second)
+	defer cancel()
+	updated, err := api.Database.UpdateAuditLogArchivalByID(updateCtx, params)
+	if err != nil {
+		logger.Error(ctx, "update audit log archival", slog.Error(err))
+		return archival
+	}
+	return updated
+}
+
+// archiveAuditLogBatches archives and deletes the audit logs older than the
+// cutoff, oldest first. Each batch is written to its own object before it's
+// deleted in the same transaction, so a failure leaves the batch in the
+// database. A batch whose deletion fails after it was written is archived
+// again by a later run.
+func (api *API) archiveAuditLogBatches(ctx context.Context, archival database.AuditLogArchival) (int64, error) {
+	var count int64
+	for batch := 0; ; batch++ {
+		var archived int
+		err := api.Database.InTx(func(tx database.Store) error {
+			archived = 0
+			// Runs of multiple replicas take turns, so no batch is archived
+			// by more than one of them.
+			err := tx.AcquireLock(ctx, database.GenLockID("audit-log-archival"))
+			if err != nil {
+				return xerrors.Errorf("acquire lock: %w", err)
+			}
+			logs, err := tx.GetAuditLogsOlderThan(ctx, database.GetAuditLogsOlderThanParams{
+				Before:   archival.Cutoff,
+				RowLimit: auditLogArchivalBatchSize,
+			})
+			if err != nil {
+				return xerrors.Errorf("get audit logs: %w", err)
+			}
+			if len(logs) == 0 {
+				return nil
+			}
+
+			if api.AuditLogArchive != nil {
+				data, err := archive.Encode(logs)
+				if err != nil {
+					return err
+				}
+				key := fmt.Sprintf("audit-logs-%s-%05d.ndjson.gz", archival.ID, batch)
+				err = api.AuditLogArchive.Put(ctx, key, data)
+				if err != nil {
+					return xerrors.Errorf("archive %s: %w", key, err)
+				}
+			}
+
+			ids := make([]uuid.UUID, 0, len(logs))
+			for _, alog := range logs {
+				ids = append(ids, alog.ID)
+			}
+			err = tx.DeleteAuditLogsByIDs(ctx, ids)
+			if err != nil {
+				return xerrors.Errorf("delete audit logs: %w", err)
+			}
+			archived = len(logs)
+			return nil
+		}, nil)
+		if err != nil {
+			return count, err
+		}
+		if archived == 0 {
+			return count, nil
+		}
+		count += int64(archived)
+	}
+}
+
+func convertAuditLogArchival(archival database.AuditLogArchival) codersdk.AuditLogArchival {
+	res := codersdk.AuditLogArchival{
+		ID:            archival.ID,
+		Status:        codersdk.AuditLogArchivalStatus(archival.Status),
+		Cutoff:        archival.Cutoff,
+		Destination:   archival.Destination,
+		ArchivedCount: archival.ArchivedCount,
+		Error:         archival.Error,
+		StartedAt:     archival.StartedAt,
+	}
+	if archival.InitiatorID.Valid {
+		res.InitiatorID = &archival.InitiatorID.UUID
+	}
+	if archival.FinishedAt.Valid {
+		res.FinishedAt = &archival.FinishedAt.Time
+	}
+	return res
+}
@@ -0,0 +1,167 @@
+package coderd_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/audit/archive"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestAuditLogArchivals(t *testing.T) {
+	t.Parallel()
+
+	auditLogLicense := &coderdenttest.LicenseOptions{
+		Features: license.Features{
+			codersdk.FeatureAuditLog: 1,
+		},
+	}
+
+	t.Run("Archive", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		store, err := archive.NewFile(dir)
+		require.NoError(t, err)
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			AuditLogging:    true,
+			AuditLogArchive: store,
+			LicenseOptions:  auditLogLicense,
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		for i := 0; i < 3; i++ {
+			err := client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+				Time: time.Now().Add(-48 * time.Hour),
+			})
+			require.NoError(t, err)
+		}
+		err = client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{})
+		require.NoError(t, err)
+		before, err := client.AuditLogs(ctx, codersdk.AuditLogsRequest{Pagination: codersdk.Pagination{Limit: 100}})
+		require.NoError(t, err)
+
+		archival, err := client.CreateAuditLogArchival(ctx, codersdk.CreateAuditLogArchivalRequest{
+			Cutoff: time.Now().Add(-24 * time.Hour),
+		})
+		require.NoError(t, err)
+		require.NotNil(t, archival.InitiatorID)
+		require.Equal(t, user.UserID, *archival.InitiatorID)
+		require.Equal(t, store.String(), archival.Destination)
+
+		require.Eventually(t, func() bool {
+			archival, err = client.AuditLogArchival(ctx, archival.ID)
+			return err == nil && archival.Status != codersdk.AuditLogArchivalStatusRunning
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.Equal(t, codersdk.AuditLogArchivalStatusSucceeded, archival.Status, archival.Error)
+		require.EqualValues(t, 3, archival.ArchivedCount)
+		require.NotNil(t, archival.FinishedAt)
+
+		after, err := client.AuditLogs(ctx, codersdk.AuditLogsRequest{Pagination: codersdk.Pagination{Limit: 100}})
+		require.NoError(t, err)
+		require.Equal(t, before.Count-3, after.Count)
+
+		objects, err := filepath.Glob(filepath.Join(dir, "audit-logs-"+archival.ID.String()+"-*.ndjson.gz"))
+		require.NoError(t, err)
+		require.Len(t, objects, 1)
+		f, err := os.Open(objects[0])
+		require.NoError(t, err)
+		defer f.Close()
+		gr, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		lines := 0
+		scanner := bufio.NewScanner(gr)
+		for scanner.Scan() {
+			lines++
+		}
+		require.NoError(t, scanner.Err())
+		require.Equal(t, 3, lines)
+
+		archivals, err := client.AuditLogArchivals(ctx)
+		require.NoError(t, err)
+		require.Len(t, archivals, 1)
+		require.Equal(t, archival.ID, archivals[0].ID)
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		err := dv.AuditArchival.Retention.Set("24h")
+		require.NoError(t, err)
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				DeploymentValues: dv,
+			},
+			AuditLogging:             true,
+			AuditLogArchivalInterval: testutil.IntervalFast,
+			LicenseOptions:           auditLogLicense,
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		err = client.CreateTestAuditLog(ctx, codersdk.CreateTestAuditLogRequest{
+			Time: time.Now().Add(-48 * time.Hour),
+		})
+		require.NoError(t, err)
+
+		var archivals []codersdk.AuditLogArchival
+		require.Eventually(t, func() bool {
+			archivals, err = client.AuditLogArchivals(ctx)
+			return err == nil && len(archivals) > 0 && archivals[0].Status != codersdk.AuditLogArchivalStatusRunning
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.Equal(t, codersdk.AuditLogArchivalStatusSucceeded, archivals[0].Status, archivals[0].Error)
+		require.Nil(t, archivals[0].InitiatorID)
+		require.Empty(t, archivals[0].Destination)
+		require.EqualValues(t, 1, archivals[0].ArchivedCount)
+
+		// Runs are only recorded when there are expired audit logs.
+		time.Sleep(5 * testutil.IntervalFast)
+		archivals, err = client.AuditLogArchivals(ctx)
+		require.NoError(t, err)
+		require.Len(t, archivals, 1)
+	})
+
+	t.Run("CutoffRequired", func(t *testing.T) {
+		t.Parallel()
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			AuditLogging:   true,
+			LicenseOptions: auditLogLicense,
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateAuditLogArchival(ctx, codersdk.CreateAuditLogArchivalRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("MemberForbidden", func(t *testing.T) {
+		t.Parallel()
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			AuditLogging:   true,
+			LicenseOptions: auditLogLicense,
+		})
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := member.CreateAuditLogArchival(ctx, codersdk.CreateAuditLogArchivalRequest{
+			Cutoff: time.Now().Add(-time.Hour),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+
+		_, err = member.AuditLogArchivals(ctx)
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
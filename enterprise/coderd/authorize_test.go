@@ -43,7 +43,7 @@ func TestCheckACLPermissions(t *testing.T) {
 	coderdtest.AwaitTemplateVersionJob(t, adminClient, version.ID)
 	template := coderdtest.CreateTemplate(t, adminClient, adminUser.OrganizationID, version.ID)
 
-	err = adminClient.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+	_, err = adminClient.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 		UserPerms: map[string]codersdk.TemplateRole{
 			memberUser.ID.String(): codersdk.TemplateRoleAdmin,
 		},
@@ -5,35 +5,45 @@ import (
 	"crypto/ed25519"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"math"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/xerrors"
 	"tailscale.com/tailcfg"
 
+	"github.com/ammario/tlru"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd"
 	agplaudit "github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/healthcheck"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
 	agplschedule "github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/cryptorand"
+	"github.com/coder/coder/v2/enterprise/audit/archive"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
 	"github.com/coder/coder/v2/enterprise/coderd/proxyhealth"
 	"github.com/coder/coder/v2/enterprise/coderd/schedule"
 	"github.com/coder/coder/v2/enterprise/derpmesh"
+	"github.com/coder/coder/v2/enterprise/opa"
 	"github.com/coder/coder/v2/enterprise/replicasync"
 	"github.com/coder/coder/v2/enterprise/tailnet"
 	"github.com/coder/coder/v2/provisionerd/proto"
@@ -47,6 +57,21 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	if options.EntitlementsUpdateInterval == 0 {
 		options.EntitlementsUpdateInterval = 10 * time.Minute
 	}
+	if options.SCIMDeprovisionInterval == 0 {
+		options.SCIMDeprovisionInterval = time.Minute
+	}
+	if options.GroupDefaultTemplatesInterval == 0 {
+		options.GroupDefaultTemplatesInterval = time.Minute
+	}
+	if options.AuditLogArchivalInterval == 0 {
+		options.AuditLogArchivalInterval = time.Hour
+	}
+	if options.LicenseRenewalInterval == 0 {
+		options.LicenseRenewalInterval = time.Hour
+	}
+	if options.EntitlementSnapshotInterval == 0 {
+		options.EntitlementSnapshotInterval = time.Hour
+	}
 	if options.Keys == nil {
 		options.Keys = Keys
 	}
@@ -67,10 +92,22 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 
 		AGPL:    coderd.New(options.Options),
 		Options: options,
+
+		entitlementsRefresh: make(chan struct{}, 1),
+		entitlementsRefreshDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "coderd",
+			Subsystem: "license",
+			Name:      "entitlements_refresh_duration_seconds",
+			Help:      "The time it took to compute entitlements from the licenses in seconds.",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}),
 		provisionerDaemonAuth: &provisionerDaemonAuth{
 			psk:        options.ProvisionerDaemonPSK,
 			authorizer: options.Authorizer,
 		},
+		groupAvatarFiles: tlru.New[uuid.UUID](func(file database.File) int {
+			return len(file.Data)
+		}, groupAvatarCacheSize),
 	}
 	defer func() {
 		if err != nil {
@@ -81,14 +118,32 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	api.AGPL.Options.SetUserGroups = api.setUserGroups
 	api.AGPL.Options.SetUserSiteRoles = api.setUserSiteRoles
 	api.AGPL.SiteHandler.AppearanceFetcher = api.fetchAppearanceConfig
-	api.AGPL.SiteHandler.RegionsFetcher = func(ctx context.Context) (any, error) {
+	api.AGPL.SiteHandler.RegionsFetcher = func(r *http.Request) (any, error) {
+		ctx := r.Context()
+		actor, ok := dbauthz.ActorFromContext(ctx)
+		if !ok {
+			return api.fetchRegions(ctx)
+		}
+		userID, err := uuid.Parse(actor.ID)
+		if err != nil {
+			return nil, xerrors.Errorf("parse actor ID: %w", err)
+		}
 		// If the user can read the workspace proxy resource, return that.
 		// If not, always default to the regions.
-		actor, ok := dbauthz.ActorFromContext(ctx)
-		if ok && api.Authorizer.Authorize(ctx, actor, rbac.ActionRead, rbac.ResourceWorkspaceProxy) == nil {
-			return api.fetchWorkspaceProxies(ctx)
+		if api.Authorizer.Authorize(ctx, actor, rbac.ActionRead, rbac.ResourceWorkspaceProxy) == nil {
+			proxies, err := api.fetchWorkspaceProxies(ctx)
+			if err != nil {
+				return nil, err
+			}
+			proxies.PreferredRegionIDs, err = api.preferredRegionIDs(ctx, userID, clientAddr(r), proxyRegions(proxies.Regions))
+			return proxies, err
 		}
-		return api.fetchRegions(ctx)
+		regions, err := api.fetchRegions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		regions.PreferredRegionIDs, err = api.preferredRegionIDs(ctx, userID, clientAddr(r), regions.Regions)
+		return regions, err
 	}
 
 	oauthConfigs := &httpmw.OAuth2Configs{
@@ -119,20 +174,34 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 
 	api.AGPL.APIHandler.Group(func(r chi.Router) {
 		r.Get("/entitlements", api.serveEntitlements)
+		r.With(apiKeyMiddleware).Get("/entitlements/history", api.entitlementsHistory)
 		// /regions overrides the AGPL /regions endpoint
 		r.Group(func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Get("/regions", api.regions)
+			r.Post("/regions/latency", api.rankRegions)
+		})
+		r.Route("/audit/archivals", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.requireFeatureMW(codersdk.FeatureAuditLog),
+			)
+			r.Get("/", api.auditLogArchivals)
+			r.Post("/", api.postAuditLogArchival)
+			r.Get("/{archival}", api.auditLogArchival)
 		})
 		r.Route("/replicas", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Get("/", api.replicas)
+			r.Post("/{replica}/drain", api.drainReplica)
 		})
 		r.Route("/licenses", func(r chi.Router) {
 			r.Use(apiKeyMiddleware)
 			r.Post("/refresh-entitlements", api.postRefreshEntitlements)
 			r.Post("/", api.postLicense)
+			r.Post("/activate", api.postActivateLicense)
 			r.Get("/", api.licenses)
+			r.Get("/usage", api.licenseUsage)
 			r.Delete("/{id}", api.deleteLicense)
 		})
 		r.Route("/applications/reconnecting-pty-signed-token", func(r chi.Router) {
@@ -149,12 +218,10 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			httpmw.RequireAPIKeyOrWorkspaceProxyAuth(),
 		).Get("/workspaceagents/{workspaceagent}/legacy", api.agentIsLegacy)
 		r.Route("/workspaceproxies", func(r chi.Router) {
-			r.Use(
-				api.moonsEnabledMW,
-			)
 			r.Group(func(r chi.Router) {
 				r.Use(
 					apiKeyMiddleware,
+					api.moonsEnabledMW,
 				)
 				r.Post("/", api.postWorkspaceProxy)
 				r.Get("/", api.workspaceProxies)
@@ -165,28 +232,34 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 						DB:       options.Database,
 						Optional: false,
 					}),
+					api.moonsEnabledMW,
 				)
 				r.Get("/coordinate", api.workspaceProxyCoordinate)
 				r.Post("/issue-signed-app-token", api.workspaceProxyIssueSignedAppToken)
+				r.Get("/jwks", api.workspaceProxyAppTokenKeys)
 				r.Post("/app-stats", api.workspaceProxyReportAppStats)
+				r.Post("/access-events", api.workspaceProxyReportAccessEvents)
 				r.Post("/register", api.workspaceProxyRegister)
 				r.Post("/deregister", api.workspaceProxyDeregister)
+				r.Get("/config/watch", api.watchWorkspaceProxyConfig)
 			})
 			r.Route("/{workspaceproxy}", func(r chi.Router) {
 				r.Use(
 					apiKeyMiddleware,
+					api.moonsEnabledMW,
 					httpmw.ExtractWorkspaceProxyParam(api.Database, deploymentID, api.AGPL.PrimaryWorkspaceProxy),
 				)
 
 				r.Get("/", api.workspaceProxy)
 				r.Patch("/", api.patchWorkspaceProxy)
 				r.Delete("/", api.deleteWorkspaceProxy)
+				r.Patch("/acl", api.patchWorkspaceProxyACL)
 			})
 		})
 		r.Route("/organizations/{organization}/groups", func(r chi.Router) {
 			r.Use(
 				apiKeyMiddleware,
-				api.templateRBACEnabledMW,
+				api.requireFeatureMW(codersdk.FeatureTemplateRBAC),
 				httpmw.ExtractOrganizationParam(api.Database),
 			)
 			r.Post("/", api.postGroupByOrganization)
@@ -209,31 +282,62 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		// We may in future decide to scope provisioner daemons to organizations, so we'll keep the API
 		// route as is.
 		r.Route("/organizations/{organization}/provisionerdaemons", func(r chi.Router) {
-			r.Use(
-				api.provisionerDaemonsEnabledMW,
-			)
-			r.With(apiKeyMiddleware).Get("/", api.provisionerDaemons)
+			r.With(
+				apiKeyMiddleware,
+				api.requireFeatureMW(codersdk.FeatureExternalProvisionerDaemons),
+			).Get("/", api.provisionerDaemons)
+			r.With(
+				apiKeyMiddleware,
+				api.requireFeatureMW(codersdk.FeatureExternalProvisionerDaemons),
+				httpmw.ExtractOrganizationParam(api.Database),
+			).Get("/pending-jobs", api.provisionerDaemonPendingJobs)
+			r.With(
+				apiKeyMiddleware,
+				api.requireFeatureMW(codersdk.FeatureExternalProvisionerDaemons),
+				httpmw.ExtractOrganizationParam(api.Database),
+			).Get("/queue", api.provisionerDaemonQueue)
+			// The feature is checked by the handler once the daemon is
+			// authenticated, since it may authenticate with a PSK.
 			r.With(apiKeyMiddlewareOptional).Get("/serve", api.provisionerDaemonServe)
 		})
 		r.Route("/templates/{template}/acl", func(r chi.Router) {
 			r.Use(
-				api.templateRBACEnabledMW,
 				apiKeyMiddleware,
+				api.requireFeatureMW(codersdk.FeatureTemplateRBAC),
 				httpmw.ExtractTemplateParam(api.Database),
 			)
 			r.Get("/available", api.templateAvailablePermissions)
 			r.Get("/", api.templateACL)
 			r.Patch("/", api.patchTemplateACL)
 		})
+		r.Route("/templates/{template}/access-requests", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.requireFeatureMW(codersdk.FeatureTemplateRBAC),
+			)
+			r.Post("/", api.postTemplateAccessRequest)
+			r.With(httpmw.ExtractTemplateParam(api.Database)).Get("/", api.templateAccessRequests)
+		})
+		r.Route("/templateaccessrequests/{templateaccessrequest}", func(r chi.Router) {
+			r.Use(
+				apiKeyMiddleware,
+				api.requireFeatureMW(codersdk.FeatureTemplateRBAC),
+			)
+			r.Post("/decision", api.postTemplateAccessRequestDecision)
+		})
 		r.Route("/groups/{group}", func(r chi.Router) {
 			r.Use(
-				api.templateRBACEnabledMW,
 				apiKeyMiddleware,
+				api.requireFeatureMW(codersdk.FeatureTemplateRBAC),
 				httpmw.ExtractGroupParam(api.Database),
 			)
 			r.Get("/", api.group)
 			r.Patch("/", api.patchGroup)
 			r.Delete("/", api.deleteGroup)
+			r.Get("/avatar", api.groupAvatar)
+			r.Put("/avatar", api.putGroupAvatar)
+			r.Get("/default-templates", api.groupDefaultTemplates)
+			r.Put("/default-templates", api.putGroupDefaultTemplates)
 		})
 		r.Route("/workspace-quota", func(r chi.Router) {
 			r.Use(
@@ -242,6 +346,9 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			r.Route("/{user}", func(r chi.Router) {
 				r.Use(httpmw.ExtractUserParam(options.Database, false))
 				r.Get("/", api.workspaceQuota)
+				r.Get("/breakdown", api.workspaceQuotaBreakdown)
+				r.Put("/override", api.putWorkspaceQuotaOverride)
+				r.Delete("/override", api.deleteWorkspaceQuotaOverride)
 			})
 		})
 		r.Route("/appearance", func(r chi.Router) {
@@ -265,8 +372,8 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		})
 		r.Route("/users/{user}/quiet-hours", func(r chi.Router) {
 			r.Use(
-				api.restartRequirementEnabledMW,
 				apiKeyMiddleware,
+				api.restartRequirementEnabledMW,
 				httpmw.ExtractUserParam(options.Database, false),
 			)
 
@@ -287,6 +394,13 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 				r.Get("/{id}", api.scimGetUser)
 				r.Patch("/{id}", api.scimPatchUser)
 			})
+			r.Route("/Groups", func(r chi.Router) {
+				r.Get("/", api.scimGetGroups)
+				r.Post("/", api.scimPostGroup)
+				r.Get("/{id}", api.scimGetGroup)
+				r.Patch("/{id}", api.scimPatchGroup)
+				r.Delete("/{id}", api.scimDeleteGroup)
+			})
 		})
 	}
 
@@ -322,7 +436,16 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 	if err != nil {
 		return nil, xerrors.Errorf("initialize replica: %w", err)
 	}
+	replicaCountFn := func() int {
+		return len(api.replicaManager.AllPrimary())
+	}
+	api.AGPL.ReplicaCountFn.Store(&replicaCountFn)
 	api.derpMesh = derpmesh.New(options.Logger.Named("derpmesh"), api.DERPServer, meshTLSConfig)
+	api.derpMesh.TrackUsage(api.AGPL.DERPUsage)
+	err = options.PrometheusRegistry.Register(api.derpMesh)
+	if err != nil {
+		options.Logger.Warn(ctx, "register DERP mesh metrics", slog.Error(err))
+	}
 
 	if api.AGPL.Experiments.Enabled(codersdk.ExperimentMoons) {
 		// Proxy health is a moon feature.
@@ -332,6 +455,8 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 			Logger:     options.Logger.Named("proxyhealth"),
 			Client:     api.HTTPClient,
 			Prometheus: api.PrometheusRegistry,
+
+			VersionMismatchPolicy: codersdk.ProxyVersionMismatchPolicy(options.DeploymentValues.ProxyVersionMismatchPolicy.Value()),
 		})
 		if err != nil {
 			return nil, xerrors.Errorf("initialize proxy health: %w", err)
@@ -344,13 +469,34 @@ func New(ctx context.Context, options *Options) (_ *API, err error) {
 		// Use proxy health to return the healthy workspace proxy hostnames.
 		f := api.ProxyHealth.ProxyHosts
 		api.AGPL.WorkspaceProxyHostsFn.Store(&f)
+
+		statusFn := api.workspaceProxiesStatus
+		api.AGPL.WorkspaceProxiesStatusFn.Store(&statusFn)
 	}
 
+	err = options.PrometheusRegistry.Register(api.entitlementsRefreshDuration)
+	if err != nil {
+		return nil, xerrors.Errorf("register entitlements metrics: %w", err)
+	}
 	err = api.updateEntitlements(ctx)
 	if err != nil {
 		return nil, xerrors.Errorf("update entitlements: %w", err)
 	}
 	go api.runEntitlementsLoop(ctx)
+	go api.runEntitlementSnapshotLoop(ctx)
+
+	if len(options.SCIMAPIKey) != 0 && api.scimDeprovisionPolicyEnabled() {
+		go api.runSCIMDeprovisionLoop(ctx)
+	}
+	go api.runGroupDefaultTemplatesLoop(ctx)
+
+	if api.AuditLogRetention > 0 {
+		go api.runAuditLogArchivalLoop(ctx)
+	}
+
+	if api.LicenseActivationURL != "" {
+		go api.runLicenseRenewalLoop(ctx)
+	}
 
 	return api, nil
 }
@@ -360,9 +506,22 @@ type Options struct {
 
 	RBAC         bool
 	AuditLogging bool
+	// Audit logs older than the retention are archived to AuditLogArchive,
+	// if set, and deleted. Audit logs are kept forever if it's zero.
+	AuditLogRetention        time.Duration
+	AuditLogArchive          archive.Store
+	AuditLogArchivalInterval time.Duration
 	// Whether to block non-browser connections.
 	BrowserOnly bool
 	SCIMAPIKey  []byte
+	// Applied to users the identity provider deactivates through SCIM, in
+	// addition to suspending them.
+	SCIMDeprovisionStopWorkspaces bool
+	SCIMDeprovisionDeleteAfter    time.Duration
+	SCIMDeprovisionInterval       time.Duration
+	// How often starter workspaces are created for new members of groups
+	// with default templates.
+	GroupDefaultTemplatesInterval time.Duration
 
 	// Used for high availability.
 	ReplicaSyncUpdateInterval time.Duration
@@ -373,11 +532,33 @@ type Options struct {
 	DefaultQuietHoursSchedule string // cron schedule, if empty user quiet hours schedules are disabled
 
 	EntitlementsUpdateInterval time.Duration
-	ProxyHealthInterval        time.Duration
-	Keys                       map[string]ed25519.PublicKey
+	// How often replicas check that the entitlements of the day have been
+	// snapshotted.
+	EntitlementSnapshotInterval time.Duration
+	ProxyHealthInterval         time.Duration
+	Keys                        map[string]ed25519.PublicKey
+
+	// LicenseActivationURL is the activation server that exchanges
+	// activation keys for licenses. Online activation is disabled if empty.
+	LicenseActivationURL   string
+	LicenseRenewalInterval time.Duration
+
+	// ProxyGeoRules prefer workspace proxies for clients in the matching
+	// networks over the ones ranked by latency.
+	ProxyGeoRules []ProxyGeoRule
+
+	// EntitlementsUpdateJitter is the maximum random delay before replicas
+	// that don't lead entitlement refreshes refresh themselves, so they don't
+	// all query the licenses at once.
+	EntitlementsUpdateJitter time.Duration
 
 	// optional pre-shared key for authentication of external provisioner daemons
 	ProvisionerDaemonPSK string
+
+	// ExternalAuthorizer is the authorizer of the external authorization
+	// policy, if one is configured. It's only enabled while the deployment is
+	// entitled to user role management.
+	ExternalAuthorizer *opa.Authorizer
 }
 
 type API struct {
@@ -399,8 +580,19 @@ type API struct {
 	entitlementsUpdateMu sync.Mutex
 	entitlementsMu       sync.RWMutex
 	entitlements         codersdk.Entitlements
+	// entitlementsRefresh requests an entitlements refresh from the
+	// entitlements loop.
+	entitlementsRefresh         chan struct{}
+	entitlementsRefreshDuration prometheus.Histogram
+	// entitlementsReplicas is the number of primary replicas when
+	// entitlements were last requested to refresh.
+	entitlementsReplicas atomic.Int64
 
 	provisionerDaemonAuth *provisionerDaemonAuth
+
+	// groupAvatarFiles caches uploaded group avatars by file ID. Files are
+	// immutable, so entries never need to be invalidated.
+	groupAvatarFiles *tlru.Cache[uuid.UUID, database.File]
 }
 
 func (api *API) Close() error {
@@ -417,10 +609,13 @@ func (api *API) Close() error {
 	return api.AGPL.Close()
 }
 
+// updateEntitlements computes the entitlements from the licenses and applies
+// them.
 func (api *API) updateEntitlements(ctx context.Context) error {
 	api.entitlementsUpdateMu.Lock()
 	defer api.entitlementsUpdateMu.Unlock()
 
+	start := time.Now()
 	entitlements, err := license.Entitlements(
 		ctx, api.Database,
 		api.Logger, len(api.replicaManager.AllPrimary()), len(api.GitAuthConfigs), api.Keys, map[codersdk.FeatureName]bool{
@@ -442,6 +637,19 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 		return err
 	}
 
+	err = api.recordLicenseUsage(ctx, entitlements)
+	if err != nil {
+		api.Logger.Warn(ctx, "failed to record license usage", slog.Error(err))
+	}
+	api.entitlementsRefreshDuration.Observe(time.Since(start).Seconds())
+
+	return api.applyEntitlements(ctx, entitlements)
+}
+
+// applyEntitlements enables and disables features according to the
+// entitlements. It must be called with entitlementsUpdateMu held.
+func (api *API) applyEntitlements(ctx context.Context, entitlements codersdk.Entitlements) error {
+	var err error
 	if entitlements.RequireTelemetry && !api.DeploymentValues.Telemetry.Enable.Value() {
 		// We can't fail because then the user couldn't remove the offending
 		// license w/o a restart.
@@ -451,6 +659,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 		api.entitlements.Errors = []string{
 			"License requires telemetry but telemetry is disabled",
 		}
+		api.entitlements.Severity = codersdk.EntitlementsSeverityError
 		api.Logger.Error(ctx, "license requires telemetry enabled")
 		return nil
 	}
@@ -463,6 +672,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 				`the dependency feature "advanced template scheduling". ` +
 				"Please contact support for a new license.",
 		}
+		api.entitlements.Severity = codersdk.EntitlementsSeverityError
 		api.Logger.Error(ctx, "license is entitled to template restart requirement but not advanced template scheduling")
 		return nil
 	}
@@ -513,6 +723,12 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 		}
 	}
 
+	if initial, changed, enabled := featureChanged(codersdk.FeatureUserRoleManagement); shouldUpdate(initial, changed, enabled) {
+		if api.ExternalAuthorizer != nil {
+			api.ExternalAuthorizer.SetEnabled(enabled)
+		}
+	}
+
 	if initial, changed, enabled := featureChanged(codersdk.FeatureAdvancedTemplateScheduling); shouldUpdate(initial, changed, enabled) {
 		if enabled {
 			templateStore := schedule.NewEnterpriseTemplateScheduleStore(api.AGPL.UserQuietHoursScheduleStore)
@@ -557,19 +773,34 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 
 	if initial, changed, enabled := featureChanged(codersdk.FeatureHighAvailability); shouldUpdate(initial, changed, enabled) {
 		var coordinator agpltailnet.Coordinator
+		status := *api.AGPL.TailnetCoordinatorStatus.Load()
+		status.HighAvailability = enabled
 		if enabled {
-			var haCoordinator agpltailnet.Coordinator
+			var (
+				haCoordinator agpltailnet.Coordinator
+				haType        healthcheck.CoordinatorType
+			)
 			if api.AGPL.Experiments.Enabled(codersdk.ExperimentTailnetPGCoordinator) {
-				haCoordinator, err = tailnet.NewPGCoord(api.ctx, api.Logger, api.Pubsub, api.Database)
+				keepalive := api.DeploymentValues.TailnetKeepalive
+				haCoordinator, err = tailnet.NewPGCoordWithHeartbeats(
+					api.ctx, api.Logger, api.Pubsub, api.Database,
+					keepalive.CoordinatorHeartbeatInterval.Value(), int(keepalive.CoordinatorMissedHeartbeats.Value()),
+				)
+				haType = healthcheck.CoordinatorTypePG
 			} else {
 				haCoordinator, err = tailnet.NewCoordinator(api.Logger, api.Pubsub)
+				haType = healthcheck.CoordinatorTypeHA
 			}
 			if err != nil {
 				api.Logger.Error(ctx, "unable to set up high availability coordinator", slog.Error(err))
 				// If we try to setup the HA coordinator and it fails, nothing
-				// is actually changing.
+				// is actually changing. Record it, so the healthcheck reports
+				// the replica is still running the in-memory coordinator.
+				status.FailedOverAt = time.Now()
+				status.FailoverError = err.Error()
 			} else {
 				coordinator = haCoordinator
+				status.Type = haType
 			}
 
 			api.replicaManager.SetCallback(func() {
@@ -578,15 +809,16 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 					addresses = append(addresses, replica.RelayAddress)
 				}
 				api.derpMesh.SetAddresses(addresses, false)
-				_ = api.updateEntitlements(ctx)
+				api.replicasChanged()
 			})
 		} else {
 			coordinator = agpltailnet.NewCoordinator(api.Logger)
+			status.Type = healthcheck.CoordinatorTypeMemory
 			api.derpMesh.SetAddresses([]string{}, false)
 			api.replicaManager.SetCallback(func() {
 				// If the amount of replicas change, so should our entitlements.
 				// This is to display a warning in the UI if the user is unlicensed.
-				_ = api.updateEntitlements(ctx)
+				api.replicasChanged()
 			})
 		}
 
@@ -598,6 +830,7 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 				api.Logger.Error(ctx, "close old tailnet coordinator", slog.Error(err))
 			}
 		}
+		api.AGPL.TailnetCoordinatorStatus.Store(&status)
 	}
 
 	if initial, changed, enabled := featureChanged(codersdk.FeatureWorkspaceProxy); shouldUpdate(initial, changed, enabled) {
@@ -609,14 +842,48 @@ func (api *API) updateEntitlements(ctx context.Context) error {
 		}
 	}
 
+	// The first computation on startup isn't a change.
+	if api.entitlements.Features != nil {
+		previous := platformEventEntitlements(api.entitlements)
+		current := platformEventEntitlements(entitlements)
+		if !reflect.DeepEqual(previous, current) {
+			api.AGPL.PlatformEvents.EntitlementsChanged(ctx, current)
+		}
+	}
+
+	// Workspace proxies serve the appearance, which depends on the license.
+	appearanceChanged := api.entitlements.Features != nil &&
+		api.entitlements.Features[codersdk.FeatureAppearance].Entitlement != entitlements.Features[codersdk.FeatureAppearance].Entitlement
+
 	api.entitlementsMu.Lock()
-	defer api.entitlementsMu.Unlock()
 	api.entitlements = entitlements
 	api.AGPL.SiteHandler.Entitlements.Store(&entitlements)
+	api.entitlementsMu.Unlock()
 
+	if appearanceChanged {
+		api.publishWorkspaceProxyConfig(ctx)
+	}
 	return nil
 }
 
+// platformEventEntitlements reduces entitlements to what is reported in
+// platform events. Feature usage is left out since it changes constantly.
+func platformEventEntitlements(entitlements codersdk.Entitlements) codersdk.PlatformEventEntitlements {
+	features := make(map[codersdk.FeatureName]codersdk.Feature, len(entitlements.Features))
+	for name, feature := range entitlements.Features {
+		features[name] = codersdk.Feature{
+			Entitlement: feature.Entitlement,
+			Enabled:     feature.Enabled,
+			Limit:       feature.Limit,
+		}
+	}
+	return codersdk.PlatformEventEntitlements{
+		HasLicense: entitlements.HasLicense,
+		Trial:      entitlements.Trial,
+		Features:   features,
+	}
+}
+
 // getProxyDERPStartingRegionID returns the starting region ID that should be
 // used for workspace proxies. A proxy's actual region ID is the return value
 // from this function + it's RegionID field.
@@ -667,8 +934,8 @@ var (
 	lastDerpConflictLog   time.Time
 )
 
-func derpMapper(logger slog.Logger, proxyHealth *proxyhealth.ProxyHealth) func(*tailcfg.DERPMap) *tailcfg.DERPMap {
-	return func(derpMap *tailcfg.DERPMap) *tailcfg.DERPMap {
+func derpMapper(logger slog.Logger, proxyHealth *proxyhealth.ProxyHealth) func(context.Context, *tailcfg.DERPMap) *tailcfg.DERPMap {
+	return func(ctx context.Context, derpMap *tailcfg.DERPMap) *tailcfg.DERPMap {
 		derpMap = derpMap.Clone()
 
 		// Find the starting region ID that we'll use for proxies. This must be
@@ -694,12 +961,19 @@ func derpMapper(logger slog.Logger, proxyHealth *proxyhealth.ProxyHealth) func(*
 			}
 		}
 
-		// Add all healthy proxies to the DERP map.
+		// Add all healthy proxies the actor may use to the DERP map.
 		statusMap := proxyHealth.HealthStatus()
 	statusLoop:
 		for _, status := range statusMap {
-			if status.Status != proxyhealth.Healthy || !status.Proxy.DerpEnabled {
-				// Only add healthy proxies with DERP enabled to the DERP map.
+			if !status.Usable() || !status.Proxy.DerpEnabled {
+				// Only add usable proxies with DERP enabled to the DERP map.
+				continue
+			}
+			if status.DERPStatus != proxyhealth.Healthy {
+				// The proxy serves apps, but clients can't relay through it.
+				continue
+			}
+			if !proxyAvailable(ctx, status.Proxy.OrganizationIDs, status.Proxy.GroupIDs) {
 				continue
 			}
 
@@ -803,8 +1077,7 @@ func (api *API) runEntitlementsLoop(ctx context.Context) {
 	eb := backoff.NewExponentialBackOff()
 	eb.MaxElapsedTime = 0 // retry indefinitely
 	b := backoff.WithContext(eb, ctx)
-	updates := make(chan struct{}, 1)
-	subscribed := false
+	broadcasts := make(chan codersdk.Entitlements, 1)
 
 	defer func() {
 		// If this function ends, it means the context was canceled and this
@@ -818,58 +1091,182 @@ func (api *API) runEntitlementsLoop(ctx context.Context) {
 		_ = api.Pubsub.Publish(PubsubEventLicenses, []byte("going away"))
 	}()
 	for {
+		cancel, err := api.subscribeEntitlements(broadcasts)
+		if err == nil {
+			// nolint: revive
+			defer cancel()
+			break
+		}
+		api.Logger.Warn(ctx, "failed to subscribe to license updates", slog.Error(err))
 		select {
 		case <-ctx.Done():
 			return
-		default:
-			// pass
-		}
-		if !subscribed {
-			cancel, err := api.Pubsub.Subscribe(PubsubEventLicenses, func(_ context.Context, _ []byte) {
-				// don't block.  If the channel is full, drop the event, as there is a resync
-				// scheduled already.
-				select {
-				case updates <- struct{}{}:
-					// pass
-				default:
-					// pass
-				}
-			})
+		case <-time.After(b.NextBackOff()):
+		}
+	}
+	b.Reset()
+	api.Logger.Debug(ctx, "successfully subscribed to pubsub")
+
+	// Entitlements were computed when the API was created.
+	timer := time.NewTimer(api.nextEntitlementsRefresh())
+	defer timer.Stop()
+	resetTimer := func(d time.Duration) {
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(d)
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			api.Logger.Debug(ctx, "syncing licensed entitlements")
+			err := api.updateEntitlements(ctx)
 			if err != nil {
-				api.Logger.Warn(ctx, "failed to subscribe to license updates", slog.Error(err))
-				select {
-				case <-ctx.Done():
-					return
-				case <-time.After(b.NextBackOff()):
-				}
+				api.Logger.Warn(ctx, "failed to get feature entitlements", slog.Error(err))
+				timer.Reset(b.NextBackOff())
 				continue
 			}
-			// nolint: revive
-			defer cancel()
-			subscribed = true
-			api.Logger.Debug(ctx, "successfully subscribed to pubsub")
+			b.Reset()
+			api.Logger.Debug(ctx, "synced licensed entitlements")
+			err = api.broadcastEntitlements()
+			if err != nil {
+				api.Logger.Warn(ctx, "failed to broadcast entitlements", slog.Error(err))
+			}
+			timer.Reset(api.nextEntitlementsRefresh())
+		case <-api.entitlementsRefresh:
+			api.Logger.Debug(ctx, "got entitlements refresh request")
+			// Every replica is asked at once. The leader refreshes right
+			// away and broadcasts the result, the others only refresh if
+			// they haven't received it after a jittered delay.
+			if api.replicaManager.Leader() {
+				resetTimer(0)
+			} else {
+				resetTimer(api.entitlementsJitter())
+			}
+		case entitlements := <-broadcasts:
+			api.Logger.Debug(ctx, "got entitlements from another replica")
+			api.entitlementsUpdateMu.Lock()
+			err := api.applyEntitlements(ctx, entitlements)
+			api.entitlementsUpdateMu.Unlock()
+			if err != nil {
+				api.Logger.Warn(ctx, "failed to apply entitlements from another replica", slog.Error(err))
+				continue
+			}
+			resetTimer(api.nextEntitlementsRefresh())
 		}
+	}
+}
 
-		api.Logger.Debug(ctx, "syncing licensed entitlements")
-		err := api.updateEntitlements(ctx)
+// subscribeEntitlements requests a refresh when licenses change and sends
+// the entitlements broadcast by other replicas to broadcasts.
+func (api *API) subscribeEntitlements(broadcasts chan codersdk.Entitlements) (func(), error) {
+	cancelLicenses, err := api.Pubsub.Subscribe(PubsubEventLicenses, func(_ context.Context, _ []byte) {
+		api.requestEntitlementsRefresh()
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("subscribe to licenses: %w", err)
+	}
+	cancelEntitlements, err := api.Pubsub.Subscribe(PubsubEventEntitlements, func(ctx context.Context, message []byte) {
+		var broadcast entitlementsBroadcast
+		err := json.Unmarshal(message, &broadcast)
 		if err != nil {
-			api.Logger.Warn(ctx, "failed to get feature entitlements", slog.Error(err))
-			time.Sleep(b.NextBackOff())
-			continue
+			api.Logger.Warn(ctx, "unable to parse entitlements broadcast", slog.Error(err))
+			return
 		}
-		b.Reset()
-		api.Logger.Debug(ctx, "synced licensed entitlements")
-
-		select {
-		case <-ctx.Done():
+		if broadcast.ReplicaID == api.replicaManager.ID() {
 			return
-		case <-time.After(api.EntitlementsUpdateInterval):
-			continue
-		case <-updates:
-			api.Logger.Debug(ctx, "got pubsub update")
-			continue
 		}
+		// Don't block. Only the latest entitlements matter, so replace
+		// entitlements that weren't applied yet.
+		for {
+			select {
+			case broadcasts <- broadcast.Entitlements:
+				return
+			default:
+			}
+			select {
+			case <-broadcasts:
+			default:
+			}
+		}
+	})
+	if err != nil {
+		cancelLicenses()
+		return nil, xerrors.Errorf("subscribe to entitlements: %w", err)
+	}
+	return func() {
+		cancelLicenses()
+		cancelEntitlements()
+	}, nil
+}
+
+// requestEntitlementsRefresh asks the entitlements loop to refresh the
+// entitlements. It never blocks.
+func (api *API) requestEntitlementsRefresh() {
+	select {
+	case api.entitlementsRefresh <- struct{}{}:
+	default:
+		// A refresh is already pending.
+	}
+}
+
+// replicasChanged requests an entitlements refresh if the number of primary
+// replicas changed, since it's counted by the entitlements.
+func (api *API) replicasChanged() {
+	api.AGPL.Draining.Store(api.replicaManager.Draining())
+	replicas := int64(len(api.replicaManager.AllPrimary()))
+	if api.entitlementsReplicas.Swap(replicas) != replicas {
+		api.requestEntitlementsRefresh()
+	}
+}
+
+type entitlementsBroadcast struct {
+	ReplicaID    uuid.UUID             `json:"replica_id"`
+	Entitlements codersdk.Entitlements `json:"entitlements"`
+}
+
+// broadcastEntitlements publishes the entitlements of this replica to the
+// other replicas, which apply them instead of computing their own.
+func (api *API) broadcastEntitlements() error {
+	api.entitlementsMu.RLock()
+	message, err := json.Marshal(entitlementsBroadcast{
+		ReplicaID:    api.replicaManager.ID(),
+		Entitlements: api.entitlements,
+	})
+	api.entitlementsMu.RUnlock()
+	if err != nil {
+		return xerrors.Errorf("marshal entitlements: %w", err)
+	}
+	return api.Pubsub.Publish(PubsubEventEntitlements, message)
+}
+
+// nextEntitlementsRefresh returns the delay until entitlements are computed
+// from the licenses again. Replicas other than the leader wait twice as long
+// and are reset whenever they receive the leader's entitlements, so they only
+// query the licenses if the leader stops refreshing.
+func (api *API) nextEntitlementsRefresh() time.Duration {
+	if api.replicaManager.Leader() {
+		return api.EntitlementsUpdateInterval
+	}
+	return 2*api.EntitlementsUpdateInterval + api.entitlementsJitter()
+}
+
+// entitlementsJitter returns a random delay of up to
+// EntitlementsUpdateJitter.
+func (api *API) entitlementsJitter() time.Duration {
+	if api.EntitlementsUpdateJitter <= 0 {
+		return 0
+	}
+	r, err := cryptorand.Float64()
+	if err != nil {
+		return api.EntitlementsUpdateJitter
 	}
+	return time.Duration(float64(api.EntitlementsUpdateJitter) * r)
 }
 
 func (api *API) Authorize(r *http.Request, action rbac.Action, object rbac.Objecter) bool {
@@ -2,6 +2,8 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"reflect"
 	"testing"
 	"time"
@@ -18,6 +20,8 @@ import (
 	agplaudit "github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbtestutil"
+	"github.com/coder/coder/v2/coderd/healthcheck"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/audit"
 	"github.com/coder/coder/v2/enterprise/coderd"
@@ -176,6 +180,73 @@ func TestEntitlements(t *testing.T) {
 			return entitlements.HasLicense
 		}, testutil.WaitShort, testutil.IntervalFast)
 	})
+	t.Run("Broadcast", func(t *testing.T) {
+		t.Parallel()
+		db, pubsub := dbtestutil.NewDB(t)
+		firstClient, _ := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Database: db,
+				Pubsub:   pubsub,
+			},
+			DontAddLicense: true,
+		})
+		// The second replica never refreshes on its own, so it can only
+		// learn about the license from the first.
+		secondClient, _, _, _ := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Database: db,
+				Pubsub:   pubsub,
+			},
+			EntitlementsUpdateInterval: time.Hour,
+			DontAddFirstUser:           true,
+			DontAddLicense:             true,
+		})
+		secondClient.SetSessionToken(firstClient.SessionToken())
+
+		entitlements, err := secondClient.Entitlements(context.Background())
+		require.NoError(t, err)
+		require.False(t, entitlements.HasLicense)
+
+		coderdenttest.AddLicense(t, firstClient, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureAuditLog: 1,
+			},
+		})
+		require.Eventually(t, func() bool {
+			entitlements, err := secondClient.Entitlements(context.Background())
+			assert.NoError(t, err)
+			return entitlements.HasLicense
+		}, testutil.WaitShort, testutil.IntervalFast)
+	})
+}
+
+func TestDebugCoordinator(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("high availability requires a real database")
+	}
+	client, _, api, _ := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureHighAvailability: 1,
+			},
+		},
+	})
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	res, err := client.Request(ctx, http.MethodGet, "/api/v2/debug/coordinator", nil)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var report healthcheck.CoordinatorReport
+	err = json.NewDecoder(res.Body).Decode(&report)
+	require.NoError(t, err)
+	assert.True(t, report.Healthy)
+	assert.Equal(t, api.AGPL.ID, report.ReplicaID)
+	assert.Equal(t, healthcheck.CoordinatorTypeHA, report.Type)
+	assert.True(t, report.HighAvailability)
+	assert.Nil(t, report.LastFailoverAt)
 }
 
 func TestAuditLogging(t *testing.T) {
@@ -19,6 +19,7 @@ import (
 
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/audit/archive"
 	"github.com/coder/coder/v2/enterprise/coderd"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
 )
@@ -45,18 +46,25 @@ func init() {
 
 type Options struct {
 	*coderdtest.Options
-	AuditLogging                bool
-	BrowserOnly                 bool
-	EntitlementsUpdateInterval  time.Duration
-	SCIMAPIKey                  []byte
-	UserWorkspaceQuota          int
-	ProxyHealthInterval         time.Duration
-	LicenseOptions              *LicenseOptions
-	NoDefaultQuietHoursSchedule bool
-	DontAddLicense              bool
-	DontAddFirstUser            bool
-	ReplicaSyncUpdateInterval   time.Duration
-	ProvisionerDaemonPSK        string
+	AuditLogging                  bool
+	AuditLogArchive               archive.Store
+	AuditLogArchivalInterval      time.Duration
+	BrowserOnly                   bool
+	EntitlementsUpdateInterval    time.Duration
+	SCIMAPIKey                    []byte
+	SCIMDeprovisionInterval       time.Duration
+	GroupDefaultTemplatesInterval time.Duration
+	UserWorkspaceQuota            int
+	ProxyHealthInterval           time.Duration
+	LicenseOptions                *LicenseOptions
+	NoDefaultQuietHoursSchedule   bool
+	DontAddLicense                bool
+	DontAddFirstUser              bool
+	ReplicaSyncUpdateInterval     time.Duration
+	ProvisionerDaemonPSK          string
+	LicenseActivationURL          string
+	LicenseRenewalInterval        time.Duration
+	EntitlementSnapshotInterval   time.Duration
 }
 
 // New constructs a codersdk client connected to an in-memory Enterprise API instance.
@@ -82,20 +90,33 @@ func NewWithAPI(t *testing.T, options *Options) (
 		err := oop.DeploymentValues.UserQuietHoursSchedule.DefaultSchedule.Set("0 0 * * *")
 		require.NoError(t, err)
 	}
+	proxyGeoRules, err := coderd.ParseProxyGeoRules(oop.DeploymentValues.ProxyGeoRules.Value())
+	require.NoError(t, err)
 	coderAPI, err := coderd.New(context.Background(), &coderd.Options{
-		RBAC:                       true,
-		AuditLogging:               options.AuditLogging,
-		BrowserOnly:                options.BrowserOnly,
-		SCIMAPIKey:                 options.SCIMAPIKey,
-		DERPServerRelayAddress:     oop.AccessURL.String(),
-		DERPServerRegionID:         oop.BaseDERPMap.RegionIDs()[0],
-		ReplicaSyncUpdateInterval:  options.ReplicaSyncUpdateInterval,
-		Options:                    oop,
-		EntitlementsUpdateInterval: options.EntitlementsUpdateInterval,
-		Keys:                       Keys,
-		ProxyHealthInterval:        options.ProxyHealthInterval,
-		DefaultQuietHoursSchedule:  oop.DeploymentValues.UserQuietHoursSchedule.DefaultSchedule.Value(),
-		ProvisionerDaemonPSK:       options.ProvisionerDaemonPSK,
+		RBAC:                          true,
+		AuditLogging:                  options.AuditLogging,
+		AuditLogRetention:             oop.DeploymentValues.AuditArchival.Retention.Value(),
+		AuditLogArchive:               options.AuditLogArchive,
+		AuditLogArchivalInterval:      options.AuditLogArchivalInterval,
+		BrowserOnly:                   options.BrowserOnly,
+		SCIMAPIKey:                    options.SCIMAPIKey,
+		SCIMDeprovisionStopWorkspaces: oop.DeploymentValues.SCIMDeprovisionStopWorkspaces.Value(),
+		SCIMDeprovisionDeleteAfter:    oop.DeploymentValues.SCIMDeprovisionDeleteAfter.Value(),
+		SCIMDeprovisionInterval:       options.SCIMDeprovisionInterval,
+		GroupDefaultTemplatesInterval: options.GroupDefaultTemplatesInterval,
+		DERPServerRelayAddress:        oop.AccessURL.String(),
+		DERPServerRegionID:            oop.BaseDERPMap.RegionIDs()[0],
+		ReplicaSyncUpdateInterval:     options.ReplicaSyncUpdateInterval,
+		Options:                       oop,
+		EntitlementsUpdateInterval:    options.EntitlementsUpdateInterval,
+		Keys:                          Keys,
+		ProxyHealthInterval:           options.ProxyHealthInterval,
+		DefaultQuietHoursSchedule:     oop.DeploymentValues.UserQuietHoursSchedule.DefaultSchedule.Value(),
+		ProvisionerDaemonPSK:          options.ProvisionerDaemonPSK,
+		ProxyGeoRules:                 proxyGeoRules,
+		LicenseActivationURL:          options.LicenseActivationURL,
+		LicenseRenewalInterval:        options.LicenseRenewalInterval,
+		EntitlementSnapshotInterval:   options.EntitlementSnapshotInterval,
 	})
 	require.NoError(t, err)
 	setHandler(coderAPI.AGPL.RootHandler)
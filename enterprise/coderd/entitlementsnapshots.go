@@ -0,0 +1,173 @@
+package coderd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get entitlements history
+// @ID get-entitlements-history
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param start_date query string false "First UTC day to return, formatted as YYYY-MM-DD"
+// @Param end_date query string false "Last UTC day to return, formatted as YYYY-MM-DD"
+// @Success 200 {object} codersdk.EntitlementsHistory
+// @Router /entitlements/history [get]
+func (api *API) entitlementsHistory(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.AGPL.Authorize(r, rbac.ActionRead, rbac.ResourceLicense) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	endDate := p.Time(vals, licenseUsageDate(time.Now()), "end_date", codersdk.LicenseUsageDateLayout)
+	startDate := p.Time(vals, endDate.AddDate(0, 0, -(licenseUsageDefaultDays-1)), "start_date", codersdk.LicenseUsageDateLayout)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+	if startDate.After(endDate) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter has invalid value.",
+			Validations: []codersdk.ValidationError{
+				{Field: "start_date", Detail: "Start date must not be after the end date."},
+			},
+		})
+		return
+	}
+
+	rows, err := api.Database.GetEntitlementSnapshots(ctx, database.GetEntitlementSnapshotsParams{
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching entitlement snapshots.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	snapshots := make([]codersdk.EntitlementSnapshot, 0, len(rows))
+	for _, row := range rows {
+		snapshot, err := convertEntitlementSnapshot(row)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Internal error reading entitlement snapshot.",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.EntitlementsHistory{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Snapshots: snapshots,
+	})
+}
+
+// runEntitlementSnapshotLoop snapshots the entitlements once a day. Every
+// replica takes part, the first snapshot of the day is the one that's kept.
+func (api *API) runEntitlementSnapshotLoop(ctx context.Context) {
+	ticker := time.NewTicker(api.EntitlementSnapshotInterval)
+	defer ticker.Stop()
+
+	var snapshotted time.Time
+	for {
+		now := time.Now()
+		if !licenseUsageDate(now).Equal(snapshotted) {
+			err := api.snapshotEntitlements(ctx, now)
+			if err == nil {
+				snapshotted = licenseUsageDate(now)
+			} else if !xerrors.Is(err, context.Canceled) {
+				api.Logger.Error(ctx, "snapshot entitlements", slog.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// snapshotEntitlements records the entitlements of the replica as the
+// snapshot of the day, unless one has been taken already.
+func (api *API) snapshotEntitlements(ctx context.Context, now time.Time) error {
+	//nolint:gocritic // The system snapshots entitlements without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	api.entitlementsMu.RLock()
+	entitlements := api.entitlements
+	api.entitlementsMu.RUnlock()
+
+	features, err := json.Marshal(entitlements.Features)
+	if err != nil {
+		return xerrors.Errorf("marshal features: %w", err)
+	}
+	userCount, err := api.Database.GetUserCount(ctx)
+	if err != nil {
+		return xerrors.Errorf("get user count: %w", err)
+	}
+	activeUserCount, err := api.Database.GetActiveUserCount(ctx)
+	if err != nil {
+		return xerrors.Errorf("get active user count: %w", err)
+	}
+
+	err = api.Database.InsertEntitlementSnapshot(ctx, database.InsertEntitlementSnapshotParams{
+		Date:            licenseUsageDate(now),
+		HasLicense:      entitlements.HasLicense,
+		Trial:           entitlements.Trial,
+		Features:        features,
+		UserCount:       userCount,
+		ActiveUserCount: activeUserCount,
+		ReplicaCount:    int32(len(api.replicaManager.AllPrimary())),
+		Warnings:        entitlements.Warnings,
+		Errors:          entitlements.Errors,
+		CreatedAt:       database.Now(),
+	})
+	if err != nil {
+		return xerrors.Errorf("insert entitlement snapshot: %w", err)
+	}
+	return nil
+}
+
+func convertEntitlementSnapshot(row database.EntitlementSnapshot) (codersdk.EntitlementSnapshot, error) {
+	snapshot := codersdk.EntitlementSnapshot{
+		Date:            row.Date,
+		HasLicense:      row.HasLicense,
+		Trial:           row.Trial,
+		UserCount:       row.UserCount,
+		ActiveUserCount: row.ActiveUserCount,
+		ReplicaCount:    int(row.ReplicaCount),
+		Warnings:        row.Warnings,
+		Errors:          row.Errors,
+		CreatedAt:       row.CreatedAt,
+	}
+	err := json.Unmarshal(row.Features, &snapshot.Features)
+	if err != nil {
+		return codersdk.EntitlementSnapshot{}, xerrors.Errorf("unmarshal features: %w", err)
+	}
+	return snapshot, nil
+}
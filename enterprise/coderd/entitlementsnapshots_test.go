@@ -0,0 +1,72 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestEntitlementsHistory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{DontAddLicense: true})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// The entitlements are snapshotted when the server starts.
+		var history codersdk.EntitlementsHistory
+		require.Eventually(t, func() bool {
+			var err error
+			history, err = client.EntitlementsHistory(ctx, codersdk.EntitlementsHistoryRequest{})
+			return err == nil && len(history.Snapshots) == 1
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.Equal(t, history.EndDate.AddDate(0, 0, -89), history.StartDate)
+		snapshot := history.Snapshots[0]
+		require.Equal(t, history.EndDate, snapshot.Date)
+		require.False(t, snapshot.HasLicense)
+		require.Equal(t, 1, snapshot.ReplicaCount)
+		require.Contains(t, snapshot.Features, codersdk.FeatureUserLimit)
+
+		// Days outside of the range are not returned.
+		history, err := client.EntitlementsHistory(ctx, codersdk.EntitlementsHistoryRequest{
+			StartDate: time.Now().AddDate(0, 0, -10),
+			EndDate:   time.Now().AddDate(0, 0, -5),
+		})
+		require.NoError(t, err)
+		require.Empty(t, history.Snapshots)
+	})
+
+	t.Run("InvalidRange", func(t *testing.T) {
+		t.Parallel()
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{DontAddLicense: true})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.EntitlementsHistory(ctx, codersdk.EntitlementsHistoryRequest{
+			StartDate: time.Now(),
+			EndDate:   time.Now().AddDate(0, 0, -1),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{DontAddLicense: true})
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.EntitlementsHistory(ctx, codersdk.EntitlementsHistoryRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -0,0 +1,70 @@
+package coderd
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// featureDisabledGuidance explains how to enable features that are licensed
+// but require additional deployment configuration.
+var featureDisabledGuidance = map[codersdk.FeatureName]string{
+	codersdk.FeatureTemplateRestartRequirement: "Specify a default user quiet hours schedule with --default-quiet-hours-schedule to use this feature.",
+	codersdk.FeatureSCIM:                       "Specify a SCIM API key with --scim-auth-header to use this feature.",
+}
+
+// requireFeatureMW rejects requests with a structured error unless the
+// feature is enabled.
+func (api *API) requireFeatureMW(name codersdk.FeatureName) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			if !api.featureAvailable(rw, r, name) {
+				return
+			}
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// featureAvailable returns true if the feature is enabled. Otherwise it
+// writes an error describing the current entitlement and how to get access
+// to the feature.
+func (api *API) featureAvailable(rw http.ResponseWriter, r *http.Request, name codersdk.FeatureName) bool {
+	api.entitlementsMu.RLock()
+	feature := api.entitlements.Features[name]
+	hasLicense := api.entitlements.HasLicense
+	api.entitlementsMu.RUnlock()
+
+	if feature.Enabled {
+		return true
+	}
+
+	var message, guidance string
+	if feature.Entitlement == codersdk.EntitlementNotEntitled {
+		message = fmt.Sprintf("%s is an Enterprise feature.", name.Humanize())
+		guidance = "Add an Enterprise license to use this feature. Contact sales at https://coder.com/contact or start a trial at https://coder.com/trial."
+		if hasLicense {
+			guidance = "Your license does not include this feature. Contact sales at https://coder.com/contact to upgrade."
+		}
+	} else {
+		message = fmt.Sprintf("%s is not enabled.", name.Humanize())
+		guidance = featureDisabledGuidance[name]
+		if guidance == "" {
+			guidance = "Ask your deployment administrator to enable this feature."
+		}
+	}
+
+	httpapi.Write(r.Context(), rw, http.StatusForbidden, codersdk.Response{
+		Message: message,
+		FeatureUnavailable: &codersdk.FeatureUnavailable{
+			Feature:     name,
+			Entitlement: feature.Entitlement,
+			Enabled:     feature.Enabled,
+			Guidance:    guidance,
+		},
+	})
+	return false
+}
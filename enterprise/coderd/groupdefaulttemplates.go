@@ -0,0 +1,396 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Get group default templates
+// @ID get-group-default-templates
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param group path string true "Group id"
+// @Success 200 {array} codersdk.GroupDefaultTemplate
+// @Router /groups/{group}/default-templates [get]
+func (api *API) groupDefaultTemplates(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		group = httpmw.GroupParam(r)
+	)
+
+	defaultTemplates, err := api.Database.GetGroupDefaultTemplates(ctx, group.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertGroupDefaultTemplates(defaultTemplates))
+}
+
+// @Summary Update group default templates
+// @ID update-group-default-templates
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param group path string true "Group id"
+// @Param request body codersdk.UpdateGroupDefaultTemplatesRequest true "Update group default templates request"
+// @Success 200 {array} codersdk.GroupDefaultTemplate
+// @Router /groups/{group}/default-templates [put]
+func (api *API) putGroupDefaultTemplates(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		group = httpmw.GroupParam(r)
+	)
+
+	var req codersdk.UpdateGroupDefaultTemplatesRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	var validErrs []codersdk.ValidationError
+	listed := make(map[uuid.UUID]bool, len(req.DefaultTemplates))
+	for _, defaultTemplate := range req.DefaultTemplates {
+		if listed[defaultTemplate.TemplateID] {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "default_templates",
+				Detail: fmt.Sprintf("Template %q is listed more than once.", defaultTemplate.TemplateID),
+			})
+			continue
+		}
+		listed[defaultTemplate.TemplateID] = true
+
+		template, err := api.Database.GetTemplateByID(ctx, defaultTemplate.TemplateID)
+		if httpapi.Is404Error(err) || (err == nil && (template.Deleted || template.OrganizationID != group.OrganizationID)) {
+			validErrs = append(validErrs, codersdk.ValidationError{
+				Field:  "default_templates",
+				Detail: fmt.Sprintf("Template %q doesn't exist in the organization of the group.", defaultTemplate.TemplateID),
+			})
+			continue
+		}
+		if err != nil {
+			httpapi.InternalServerError(rw, err)
+			return
+		}
+	}
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid default templates.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	var defaultTemplates []database.GroupDefaultTemplate
+	err := api.Database.InTx(func(tx database.Store) error {
+		defaultTemplates = nil
+		err := tx.DeleteGroupDefaultTemplates(ctx, group.ID)
+		if err != nil {
+			return xerrors.Errorf("delete default templates: %w", err)
+		}
+		now := database.Now()
+		for _, req := range req.DefaultTemplates {
+			defaultTemplate, err := tx.InsertGroupDefaultTemplate(ctx, database.InsertGroupDefaultTemplateParams{
+				GroupID:         group.ID,
+				TemplateID:      req.TemplateID,
+				CreateWorkspace: req.CreateWorkspace,
+				CreatedAt:       now,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert default template %q: %w", req.TemplateID, err)
+			}
+			defaultTemplates = append(defaultTemplates, defaultTemplate)
+
+			// Members get access through the group, so the ACL of the
+			// template doesn't change when members join or leave. A role
+			// the group already has is kept, it may grant more than use.
+			template, err := tx.GetTemplateByID(ctx, req.TemplateID)
+			if err != nil {
+				return xerrors.Errorf("get template %q: %w", req.TemplateID, err)
+			}
+			if _, ok := template.GroupACL[group.ID.String()]; ok {
+				continue
+			}
+			if template.GroupACL == nil {
+				template.GroupACL = database.TemplateACL{}
+			}
+			template.GroupACL[group.ID.String()] = convertSDKTemplateRole(codersdk.TemplateRoleUse)
+			err = tx.UpdateTemplateACLByID(ctx, database.UpdateTemplateACLByIDParams{
+				ID:       template.ID,
+				UserACL:  template.UserACL,
+				GroupACL: template.GroupACL,
+			})
+			if err != nil {
+				return xerrors.Errorf("grant group use of template %q: %w", req.TemplateID, err)
+			}
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertGroupDefaultTemplates(defaultTemplates))
+}
+
+func convertGroupDefaultTemplates(defaultTemplates []database.GroupDefaultTemplate) []codersdk.GroupDefaultTemplate {
+	converted := make([]codersdk.GroupDefaultTemplate, 0, len(defaultTemplates))
+	for _, defaultTemplate := range defaultTemplates {
+		converted = append(converted, codersdk.GroupDefaultTemplate{
+			TemplateID:      defaultTemplate.TemplateID,
+			CreateWorkspace: defaultTemplate.CreateWorkspace,
+		})
+	}
+	return converted
+}
+
+// runGroupDefaultTemplatesLoop periodically creates the starter workspaces of
+// members of groups with default templates.
+func (api *API) runGroupDefaultTemplatesLoop(ctx context.Context) {
+	ticker := time.NewTicker(api.GroupDefaultTemplatesInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := api.reconcileGroupDefaultTemplates(ctx)
+		if err != nil && !xerrors.Is(err, context.Canceled) {
+			api.Logger.Error(ctx, "reconcile group default templates", slog.Error(err))
+		}
+	}
+}
+
+// reconcileGroupDefaultTemplates creates a starter workspace for every member
+// of a group who didn't get one for a default template of the group yet.
+func (api *API) reconcileGroupDefaultTemplates(ctx context.Context) error {
+	api.entitlementsMu.RLock()
+	enabled := api.entitlements.Features[codersdk.FeatureTemplateRBAC].Enabled
+	api.entitlementsMu.RUnlock()
+	if !enabled {
+		return nil
+	}
+
+	//nolint:gocritic // The system creates starter workspaces without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	pending, err := api.Database.GetPendingGroupDefaultTemplateWorkspaces(ctx)
+	if err != nil {
+		return xerrors.Errorf("get pending starter workspaces: %w", err)
+	}
+	for _, starter := range pending {
+		err := api.createStarterWorkspace(ctx, starter)
+		if err != nil {
+			if xerrors.Is(err, context.Canceled) {
+				return err
+			}
+			api.Logger.Warn(ctx, "create starter workspace",
+				slog.F("group_id", starter.GroupID),
+				slog.F("template_id", starter.TemplateID),
+				slog.F("user_id", starter.UserID),
+				slog.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// createStarterWorkspace creates the starter workspace on behalf of the member,
+// so it's only created if they may use the template. Workspaces that can't be
+// created, for example because the template has required parameters, are
+// recorded without a workspace and not retried.
+func (api *API) createStarterWorkspace(ctx context.Context, starter database.GetPendingGroupDefaultTemplateWorkspacesRow) error {
+	roles, err := api.Database.GetAuthorizationUserRoles(ctx, starter.UserID)
+	if err != nil {
+		return xerrors.Errorf("get user roles: %w", err)
+	}
+	subject := rbac.Subject{
+		ID:     starter.UserID.String(),
+		Roles:  rbac.RoleNames(roles.Roles),
+		Groups: roles.Groups,
+		Scope:  rbac.ScopeAll,
+	}.WithCachedASTValue()
+	ownerCtx := dbauthz.As(ctx, subject)
+
+	templateSchedule, err := (*api.AGPL.TemplateScheduleStore.Load()).Get(ctx, api.Database, starter.TemplateID)
+	if err != nil {
+		return xerrors.Errorf("get template schedule: %w", err)
+	}
+
+	var (
+		workspace database.Workspace
+		job       *database.ProvisionerJob
+		locked    bool
+	)
+	err = api.Database.InTx(func(tx database.Store) error {
+		// The transaction may be retried, so only the job of the attempt
+		// that commits is posted.
+		job = nil
+
+		// Replicas reconcile concurrently. Only one of them creates the
+		// workspace, the others skip the member until the next tick.
+		locked, err = tx.TryAcquireLock(ctx, database.GenLockID(fmt.Sprintf("group-default-template:%s:%s", starter.TemplateID, starter.UserID)))
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		template, err := tx.GetTemplateByID(ownerCtx, starter.TemplateID)
+		if err != nil {
+			return xerrors.Errorf("get template: %w", err)
+		}
+		// Approvers wouldn't be notified of a request nobody made, so
+		// templates that require approval don't get starter workspaces.
+		if template.RequireWorkspaceApproval {
+			return wsbuilder.BuildError{
+				Status:  http.StatusBadRequest,
+				Message: "Template requires workspace approval.",
+				Wrapped: xerrors.New("template requires workspace approval"),
+			}
+		}
+		name, err := starterWorkspaceName(ctx, tx, starter.UserID, template.Name)
+		if err != nil {
+			return err
+		}
+
+		now := database.Now()
+		workspace, err = tx.InsertWorkspace(ownerCtx, database.InsertWorkspaceParams{
+			ID:             uuid.New(),
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			OwnerID:        starter.UserID,
+			OrganizationID: template.OrganizationID,
+			TemplateID:     template.ID,
+			Name:           name,
+			Ttl: sql.NullInt64{
+				Int64: int64(templateSchedule.DefaultTTL),
+				Valid: templateSchedule.DefaultTTL > 0,
+			},
+			AutomaticUpdates: database.AutomaticUpdatesNever,
+			LastUsedAt:       now,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert workspace: %w", err)
+		}
+
+		builder := wsbuilder.New(workspace, database.WorkspaceTransitionStart).
+			Reason(database.BuildReasonInitiator).
+			Initiator(starter.UserID).
+			ActiveVersion()
+		_, job, err = builder.Build(ownerCtx, tx, func(action rbac.Action, object rbac.Objecter) bool {
+			return api.AGPL.Authorizer.Authorize(ownerCtx, subject, action, object.RBACObject()) == nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.InsertGroupDefaultTemplateWorkspace(ctx, database.InsertGroupDefaultTemplateWorkspaceParams{
+			TemplateID:  starter.TemplateID,
+			UserID:      starter.UserID,
+			GroupID:     starter.GroupID,
+			WorkspaceID: uuid.NullUUID{UUID: workspace.ID, Valid: true},
+			CreatedAt:   now,
+		})
+	}, nil)
+	var bldErr wsbuilder.BuildError
+	if (xerrors.As(err, &bldErr) && bldErr.Status < http.StatusInternalServerError) || dbauthz.IsNotAuthorizedError(err) {
+		api.Logger.Warn(ctx, "starter workspace can't be created",
+			slog.F("group_id", starter.GroupID),
+			slog.F("template_id", starter.TemplateID),
+			slog.F("user_id", starter.UserID),
+			slog.Error(err),
+		)
+		err = api.Database.InsertGroupDefaultTemplateWorkspace(ctx, database.InsertGroupDefaultTemplateWorkspaceParams{
+			TemplateID: starter.TemplateID,
+			UserID:     starter.UserID,
+			GroupID:    starter.GroupID,
+			CreatedAt:  database.Now(),
+		})
+		if database.IsUniqueViolation(err) {
+			return nil
+		}
+		return err
+	}
+	if database.IsUniqueViolation(err) {
+		// Another replica created the workspace since it was listed.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return nil
+	}
+
+	api.Logger.Info(ctx, "created starter workspace",
+		slog.F("group_id", starter.GroupID),
+		slog.F("template_id", starter.TemplateID),
+		slog.F("user_id", starter.UserID),
+		slog.F("workspace_id", workspace.ID),
+	)
+	api.AGPL.PlatformEvents.Workspace(ctx, codersdk.PlatformEventTypeWorkspaceCreated, workspace)
+	err = provisionerdserver.PostJob(api.Pubsub, *job)
+	if err != nil {
+		api.Logger.Error(ctx, "post provisioner job to pubsub", slog.Error(err))
+	}
+	return nil
+}
+
+// starterWorkspaceName returns the name of the template, with a number added if
+// the user already has a workspace with that name.
+func starterWorkspaceName(ctx context.Context, db database.Store, userID uuid.UUID, templateName string) (string, error) {
+	for i := 1; i <= 10; i++ {
+		name := templateName
+		if i > 1 {
+			suffix := fmt.Sprintf("-%d", i)
+			// Workspace names are limited to 32 characters.
+			if len(name)+len(suffix) > 32 {
+				name = name[:32-len(suffix)]
+			}
+			name += suffix
+		}
+		_, err := db.GetWorkspaceByOwnerIDAndName(ctx, database.GetWorkspaceByOwnerIDAndNameParams{
+			OwnerID: userID,
+			Name:    name,
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			return name, nil
+		}
+		if err != nil {
+			return "", xerrors.Errorf("get workspace by name %q: %w", name, err)
+		}
+	}
+	return "", wsbuilder.BuildError{
+		Status:  http.StatusConflict,
+		Message: "No unused workspace name.",
+		Wrapped: xerrors.Errorf("user has workspaces named like template %q", templateName),
+	}
+}
@@ -0,0 +1,148 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestGroupDefaultTemplates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("GrantsUse", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "starters",
+		})
+		require.NoError(t, err)
+
+		defaultTemplates, err := client.UpdateGroupDefaultTemplates(ctx, group.ID, codersdk.UpdateGroupDefaultTemplatesRequest{
+			DefaultTemplates: []codersdk.GroupDefaultTemplate{{TemplateID: template.ID}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.GroupDefaultTemplate{{TemplateID: template.ID}}, defaultTemplates)
+
+		defaultTemplates, err = client.GroupDefaultTemplates(ctx, group.ID)
+		require.NoError(t, err)
+		require.Equal(t, []codersdk.GroupDefaultTemplate{{TemplateID: template.ID}}, defaultTemplates)
+
+		acl, err := client.TemplateACL(ctx, template.ID)
+		require.NoError(t, err)
+		var found bool
+		for _, aclGroup := range acl.Groups {
+			if aclGroup.ID == group.ID {
+				found = true
+				require.Equal(t, codersdk.TemplateRoleUse, aclGroup.Role)
+			}
+		}
+		require.True(t, found, "group not in template ACL")
+
+		// Clearing the list leaves the ACL as is.
+		defaultTemplates, err = client.UpdateGroupDefaultTemplates(ctx, group.ID, codersdk.UpdateGroupDefaultTemplatesRequest{})
+		require.NoError(t, err)
+		require.Empty(t, defaultTemplates)
+	})
+
+	t.Run("InvalidTemplate", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "starters",
+		})
+		require.NoError(t, err)
+
+		_, err = client.UpdateGroupDefaultTemplates(ctx, group.ID, codersdk.UpdateGroupDefaultTemplatesRequest{
+			DefaultTemplates: []codersdk.GroupDefaultTemplate{{TemplateID: uuid.New()}},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("StarterWorkspace", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			GroupDefaultTemplatesInterval: testutil.IntervalFast,
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "starters",
+		})
+		require.NoError(t, err)
+		_, err = client.UpdateGroupDefaultTemplates(ctx, group.ID, codersdk.UpdateGroupDefaultTemplatesRequest{
+			DefaultTemplates: []codersdk.GroupDefaultTemplate{{TemplateID: template.ID, CreateWorkspace: true}},
+		})
+		require.NoError(t, err)
+
+		memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{member.ID.String()},
+		})
+		require.NoError(t, err)
+
+		var workspaces codersdk.WorkspacesResponse
+		require.Eventually(t, func() bool {
+			workspaces, err = memberClient.Workspaces(ctx, codersdk.WorkspaceFilter{Owner: codersdk.Me})
+			return err == nil && len(workspaces.Workspaces) == 1
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.Equal(t, template.ID, workspaces.Workspaces[0].TemplateID)
+		require.Equal(t, template.Name, workspaces.Workspaces[0].Name)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, workspaces.Workspaces[0].LatestBuild.ID)
+
+		// The member only gets one starter workspace, even after they
+		// delete it.
+		build, err := memberClient.CreateWorkspaceBuild(ctx, workspaces.Workspaces[0].ID, codersdk.CreateWorkspaceBuildRequest{
+			Transition: codersdk.WorkspaceTransitionDelete,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitWorkspaceBuildJob(t, client, build.ID)
+		require.Never(t, func() bool {
+			workspaces, err = memberClient.Workspaces(ctx, codersdk.WorkspaceFilter{Owner: codersdk.Me})
+			return err == nil && len(workspaces.Workspaces) > 0
+		}, testutil.IntervalSlow, testutil.IntervalFast)
+	})
+}
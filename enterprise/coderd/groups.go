@@ -1,9 +1,18 @@
 package coderd
 
 import (
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -11,6 +20,7 @@ import (
 	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
@@ -53,6 +63,12 @@ func (api *API) postGroupByOrganization(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	metadata, err := marshalGroupMetadata(req.Metadata)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
 	group, err := api.Database.InsertGroup(ctx, database.InsertGroupParams{
 		ID:             uuid.New(),
 		Name:           req.Name,
@@ -60,6 +76,7 @@ func (api *API) postGroupByOrganization(rw http.ResponseWriter, r *http.Request)
 		OrganizationID: org.ID,
 		AvatarURL:      req.AvatarURL,
 		QuotaAllowance: int32(req.QuotaAllowance),
+		Metadata:       metadata,
 	})
 	if database.IsUniqueViolation(err) {
 		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
@@ -78,6 +95,20 @@ func (api *API) postGroupByOrganization(rw http.ResponseWriter, r *http.Request)
 	httpapi.Write(ctx, rw, http.StatusCreated, convertGroup(group, nil))
 }
 
+// errGroupModified is returned when a group changed since the version a patch
+// is based on.
+var errGroupModified = xerrors.New("group was modified")
+
+// groupMemberConflictError lists the members of a patch that can't be added
+// or removed because of the current members of the group.
+type groupMemberConflictError struct {
+	validations []codersdk.ValidationError
+}
+
+func (e groupMemberConflictError) Error() string {
+	return fmt.Sprintf("%d conflicting group members", len(e.validations))
+}
+
 // @Summary Update group by name
 // @ID update-group-by-name
 // @Security CoderSessionToken
@@ -85,6 +116,7 @@ func (api *API) postGroupByOrganization(rw http.ResponseWriter, r *http.Request)
 // @Produce json
 // @Tags Enterprise
 // @Param group path string true "Group name"
+// @Param If-Match header string false "Version of the group the patch is based on"
 // @Param request body codersdk.PatchGroupRequest true "Patch group request"
 // @Success 200 {object} codersdk.Group
 // @Router /groups/{group} [patch]
@@ -106,6 +138,7 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 	if !httpapi.Read(ctx, rw, r, &req) {
 		return
 	}
+	ifMatch := parseIfMatch(r.Header.Get("If-Match"))
 
 	// If the name matches the existing group name pretend we aren't
 	// updating the name at all.
@@ -120,9 +153,13 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if group.IsEveryone() && (req.DisplayName != nil && *req.DisplayName != "") {
+	// The quota allowance of the Everyone group is the default of the
+	// organization, an override would replace every allowance. Removing an
+	// override is still allowed.
+	if group.IsEveryone() && req.QuotaOverride != nil && *req.QuotaOverride >= 0 {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-			Message: fmt.Sprintf("Cannot update the Display Name for the %q group!", database.EveryoneGroup),
+			Message: fmt.Sprintf("Cannot set a quota override for the %q group!", database.EveryoneGroup),
+			Detail:  "Set its quota allowance to change the default quota of the organization.",
 		})
 		return
 	}
@@ -196,12 +233,55 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 			return xerrors.Errorf("get group by ID: %w", err)
 		}
 
+		if ifMatch != "" && ifMatch != "*" {
+			members, err := tx.GetGroupMembers(ctx, group.ID)
+			if err != nil {
+				return xerrors.Errorf("get group members: %w", err)
+			}
+			if groupVersion(group, members) != ifMatch {
+				return errGroupModified
+			}
+		}
+
+		// Check the members against the group in the transaction, so
+		// concurrent changes aren't silently applied twice or undone.
+		members, err := tx.GetGroupMembersAnyStatus(ctx, group.ID)
+		if err != nil {
+			return xerrors.Errorf("get group members with any status: %w", err)
+		}
+		isMember := make(map[uuid.UUID]bool, len(members))
+		for _, member := range members {
+			isMember[member.ID] = true
+		}
+		var conflicts []codersdk.ValidationError
+		for _, id := range req.AddUsers {
+			if isMember[uuid.MustParse(id)] {
+				conflicts = append(conflicts, codersdk.ValidationError{
+					Field:  "add_users",
+					Detail: fmt.Sprintf("User %q is already a member of the group.", id),
+				})
+			}
+		}
+		for _, id := range req.RemoveUsers {
+			if !isMember[uuid.MustParse(id)] {
+				conflicts = append(conflicts, codersdk.ValidationError{
+					Field:  "remove_users",
+					Detail: fmt.Sprintf("User %q is not a member of the group.", id),
+				})
+			}
+		}
+		if len(conflicts) > 0 {
+			return groupMemberConflictError{validations: conflicts}
+		}
+
 		updateGroupParams := database.UpdateGroupByIDParams{
 			ID:             group.ID,
 			AvatarURL:      group.AvatarURL,
 			Name:           group.Name,
 			DisplayName:    group.DisplayName,
 			QuotaAllowance: group.QuotaAllowance,
+			QuotaOverride:  group.QuotaOverride,
+			Metadata:       group.Metadata,
 		}
 
 		// TODO: Do we care about validating this?
@@ -214,9 +294,21 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 		if req.QuotaAllowance != nil {
 			updateGroupParams.QuotaAllowance = int32(*req.QuotaAllowance)
 		}
+		if req.QuotaOverride != nil {
+			updateGroupParams.QuotaOverride = sql.NullInt32{
+				Int32: int32(*req.QuotaOverride),
+				Valid: *req.QuotaOverride >= 0,
+			}
+		}
 		if req.DisplayName != nil {
 			updateGroupParams.DisplayName = *req.DisplayName
 		}
+		if req.Metadata != nil {
+			updateGroupParams.Metadata, err = marshalGroupMetadata(*req.Metadata)
+			if err != nil {
+				return xerrors.Errorf("marshal metadata: %w", err)
+			}
+		}
 
 		group, err = tx.UpdateGroupByID(ctx, updateGroupParams)
 		if err != nil {
@@ -252,6 +344,21 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 		return nil
 	})
 
+	if xerrors.Is(err, errGroupModified) {
+		httpapi.Write(ctx, rw, http.StatusPreconditionFailed, codersdk.Response{
+			Message: "The group was modified since it was fetched.",
+			Detail:  "Fetch the group again and retry with its current version.",
+		})
+		return
+	}
+	var conflictErr groupMemberConflictError
+	if xerrors.As(err, &conflictErr) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message:     "Group members conflict with the current members of the group.",
+			Validations: conflictErr.validations,
+		})
+		return
+	}
 	if database.IsUniqueViolation(err) {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Cannot add the same user to a group twice!",
@@ -279,7 +386,9 @@ func (api *API) patchGroup(rw http.ResponseWriter, r *http.Request) {
 
 	aReq.New = group.Auditable(patchedMembers)
 
-	httpapi.Write(ctx, rw, http.StatusOK, convertGroup(group, patchedMembers))
+	converted := convertGroup(group, patchedMembers)
+	rw.Header().Set("ETag", strconv.Quote(converted.Version))
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
 }
 
 // @Summary Delete group by name
@@ -330,6 +439,208 @@ func (api *API) deleteGroup(rw http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// groupAvatarMaxSize is the maximum size in bytes of an uploaded group avatar.
+const groupAvatarMaxSize = 1 << 20
+
+// groupAvatarCacheSize is the maximum total size in bytes of the group
+// avatars kept in memory.
+const groupAvatarCacheSize = 32 << 20
+
+// groupAvatarCacheTTL is how long a group avatar stays in memory after it
+// was last read from the database.
+const groupAvatarCacheTTL = time.Hour
+
+// groupAvatarMimeTypes are the image types accepted as group avatars. SVGs
+// aren't accepted since they may contain scripts.
+var groupAvatarMimeTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// @Summary Upload group avatar
+// @Description Swagger notice: Swagger 2.0 doesn't support file upload with a `content-type` different than `application/x-www-form-urlencoded`.
+// @ID upload-group-avatar
+// @Security CoderSessionToken
+// @Accept image/png
+// @Produce json
+// @Tags Enterprise
+// @Param group path string true "Group id"
+// @Param Content-Type header string true "Content-Type must be `image/png`, `image/jpeg`, `image/gif` or `image/webp`" default(image/png)
+// @Param file formData file true "Avatar to be uploaded"
+// @Success 200 {object} codersdk.Group
+// @Router /groups/{group}/avatar [put]
+func (api *API) putGroupAvatar(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		group             = httpmw.GroupParam(r)
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.AuditableGroup](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	// Check before storing the avatar, so no file is left behind for a
+	// group that can't be updated.
+	if !api.AGPL.Authorize(r, rbac.ActionUpdate, group) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(rw, r.Body, groupAvatarMaxSize)
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Failed to read avatar from request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	// The avatar is served with the detected type, so the Content-Type
+	// header of the upload can't make us serve anything but an image.
+	contentType := http.DetectContentType(data)
+	if !groupAvatarMimeTypes[contentType] {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Unsupported avatar content type %q.", contentType),
+			Detail:  "Avatars must be PNG, JPEG, GIF or WebP images.",
+		})
+		return
+	}
+
+	members, err := api.Database.GetGroupMembers(ctx, group.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.Old = group.Auditable(members)
+
+	// Avatars are stored like any other file, so uploading the same image
+	// again reuses the existing file.
+	hashBytes := sha256.Sum256(data)
+	hash := hex.EncodeToString(hashBytes[:])
+	file, err := api.Database.GetFileByHashAndCreator(ctx, database.GetFileByHashAndCreatorParams{
+		Hash:      hash,
+		CreatedBy: apiKey.UserID,
+	})
+	if xerrors.Is(err, sql.ErrNoRows) {
+		file, err = api.Database.InsertFile(ctx, database.InsertFileParams{
+			ID:        uuid.New(),
+			Hash:      hash,
+			CreatedBy: apiKey.UserID,
+			CreatedAt: database.Now(),
+			Mimetype:  contentType,
+			Data:      data,
+		})
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	group, err = api.Database.UpdateGroupAvatarByID(ctx, database.UpdateGroupAvatarByIDParams{
+		ID:           group.ID,
+		AvatarFileID: uuid.NullUUID{UUID: file.ID, Valid: true},
+		// The hash makes the URL change with the avatar, so clients can
+		// cache it indefinitely.
+		AvatarURL: fmt.Sprintf("/api/v2/groups/%s/avatar?hash=%s", group.ID, file.Hash),
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	aReq.New = group.Auditable(members)
+
+	converted := convertGroup(group, members)
+	rw.Header().Set("ETag", strconv.Quote(converted.Version))
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
+}
+
+// @Summary Get group avatar
+// @ID get-group-avatar
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param group path string true "Group id"
+// @Param hash query string false "Hash of the avatar, as in the avatar URL of the group"
+// @Success 200
+// @Router /groups/{group}/avatar [get]
+func (api *API) groupAvatar(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx   = r.Context()
+		group = httpmw.GroupParam(r)
+	)
+
+	if !group.AvatarFileID.Valid {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	file, ok, err := api.groupAvatarFile(ctx, group.AvatarFileID.UUID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+	if !ok {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	etag := strconv.Quote(file.Hash)
+	rw.Header().Set("ETag", etag)
+	if r.URL.Query().Get("hash") == file.Hash {
+		rw.Header().Set("Cache-Control", "private, max-age=31536000, immutable")
+	} else {
+		rw.Header().Set("Cache-Control", "private, no-cache")
+	}
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	rw.Header().Set("Content-Type", file.Mimetype)
+	rw.WriteHeader(http.StatusOK)
+	_, _ = rw.Write(file.Data)
+}
+
+// groupAvatarFile returns an avatar file from the cache, reading it from the
+// database on a miss.
+func (api *API) groupAvatarFile(ctx context.Context, id uuid.UUID) (database.File, bool, error) {
+	if file, _, ok := api.groupAvatarFiles.Get(id); ok {
+		return file, true, nil
+	}
+
+	// Anyone who can read the group can see its avatar, regardless of who
+	// uploaded it.
+	// nolint:gocritic // The group was already authorized.
+	file, err := api.Database.GetFileByID(dbauthz.AsSystemRestricted(ctx), id)
+	if httpapi.Is404Error(err) {
+		return database.File{}, false, nil
+	}
+	if err != nil {
+		return database.File{}, false, err
+	}
+	api.groupAvatarFiles.Set(id, file, groupAvatarCacheTTL)
+	return file, true, nil
+}
+
+// etagMatches returns whether the entity tag is listed in an If-None-Match
+// header.
+func etagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
 // @Summary Get group by organization and group name
 // @ID get-group-by-organization-and-group-name
 // @Security CoderSessionToken
@@ -363,7 +674,9 @@ func (api *API) group(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, convertGroup(group, users))
+	converted := convertGroup(group, users)
+	rw.Header().Set("ETag", strconv.Quote(converted.Version))
+	httpapi.Write(ctx, rw, http.StatusOK, converted)
 }
 
 // @Summary Get groups by organization
@@ -424,7 +737,7 @@ func convertGroup(g database.Group, users []database.User) codersdk.Group {
 		orgs[user.ID] = []uuid.UUID{g.OrganizationID}
 	}
 
-	return codersdk.Group{
+	group := codersdk.Group{
 		ID:             g.ID,
 		Name:           g.Name,
 		DisplayName:    g.DisplayName,
@@ -433,7 +746,51 @@ func convertGroup(g database.Group, users []database.User) codersdk.Group {
 		QuotaAllowance: int(g.QuotaAllowance),
 		Members:        convertUsers(users, orgs),
 		Source:         codersdk.GroupSource(g.Source),
+		Metadata:       map[string]string{},
+		Version:        groupVersion(g, users),
+	}
+	// The metadata is always stored as an object of strings.
+	_ = json.Unmarshal(g.Metadata, &group.Metadata)
+	if g.QuotaOverride.Valid {
+		override := int(g.QuotaOverride.Int32)
+		group.QuotaOverride = &override
+	}
+	return group
+}
+
+// groupVersion identifies the state of a group and its members. It changes
+// whenever either is updated, so clients can detect concurrent changes.
+func groupVersion(g database.Group, members []database.User) string {
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.ID.String())
+	}
+	sort.Strings(ids)
+
+	hash := sha256.New()
+	_, _ = fmt.Fprintf(hash, "%s\n%s\n%s\n%s\n%d\n%v\n%s\n", g.Name, g.DisplayName, g.AvatarURL, g.Source, g.QuotaAllowance, g.QuotaOverride, g.Metadata)
+	for _, id := range ids {
+		_, _ = io.WriteString(hash, id+"\n")
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// marshalGroupMetadata encodes the metadata of a group for the database.
+func marshalGroupMetadata(metadata map[string]string) (json.RawMessage, error) {
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	return json.Marshal(metadata)
+}
+
+// parseIfMatch returns the version of an If-Match header.
+func parseIfMatch(header string) string {
+	header = strings.TrimSpace(header)
+	header = strings.TrimPrefix(header, "W/")
+	if unquoted, err := strconv.Unquote(header); err == nil {
+		return unquoted
 	}
+	return header
 }
 
 func convertUser(user database.User, organizationIDs []uuid.UUID) codersdk.User {
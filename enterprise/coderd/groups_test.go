@@ -1,7 +1,10 @@
 package coderd_test
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -112,6 +115,39 @@ func TestCreateGroup(t *testing.T) {
 		require.True(t, ok)
 		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
 	})
+
+	t.Run("FeatureUnavailable", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.Error(t, err)
+		cerr, ok := codersdk.AsError(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusForbidden, cerr.StatusCode())
+
+		feature, ok := codersdk.AsFeatureUnavailable(err)
+		require.True(t, ok)
+		require.Equal(t, codersdk.FeatureTemplateRBAC, feature.Feature)
+		require.Equal(t, codersdk.EntitlementNotEntitled, feature.Entitlement)
+		require.False(t, feature.Enabled)
+		require.NotEmpty(t, feature.Guidance)
+		require.Contains(t, err.Error(), feature.Guidance)
+
+		// Unauthenticated callers don't learn the entitlements.
+		_, err = codersdk.New(client.URL).Group(ctx, uuid.New())
+		require.Error(t, err)
+		cerr, ok = codersdk.AsError(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusUnauthorized, cerr.StatusCode())
+		_, ok = codersdk.AsFeatureUnavailable(err)
+		require.False(t, ok)
+	})
 }
 
 func TestPatchGroup(t *testing.T) {
@@ -149,6 +185,48 @@ func TestPatchGroup(t *testing.T) {
 		require.Equal(t, 20, group.QuotaAllowance)
 	})
 
+	t.Run("Metadata", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name:     "hi",
+			Metadata: map[string]string{"team": "platform"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"team": "platform"}, group.Metadata)
+
+		group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			Metadata: ptr.Ref(map[string]string{"team": "infra", "cost_center": "42"}),
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"team": "infra", "cost_center": "42"}, group.Metadata)
+
+		// Metadata is kept unless it's set.
+		group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			DisplayName: ptr.Ref("Infra"),
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"team": "infra", "cost_center": "42"}, group.Metadata)
+
+		group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			Metadata: ptr.Ref(map[string]string{}),
+		})
+		require.NoError(t, err)
+		require.Empty(t, group.Metadata)
+
+		groups, err := client.GroupsByOrganization(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		for _, g := range groups {
+			require.NotNil(t, g.Metadata)
+		}
+	})
+
 	t.Run("DisplayNameUnchanged", func(t *testing.T) {
 		t.Parallel()
 
@@ -263,6 +341,90 @@ func TestPatchGroup(t *testing.T) {
 		require.Contains(t, group.Members, user4)
 	})
 
+	t.Run("IfMatch", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		_, user2 := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, user3 := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, group.Version)
+		stale := group.Version
+
+		group, err = client.PatchGroupIfMatch(ctx, group.ID, stale, codersdk.PatchGroupRequest{
+			AddUsers: []string{user2.ID.String()},
+		})
+		require.NoError(t, err)
+		require.Contains(t, group.Members, user2)
+		require.NotEqual(t, stale, group.Version)
+
+		// The group changed since the stale version was fetched.
+		_, err = client.PatchGroupIfMatch(ctx, group.ID, stale, codersdk.PatchGroupRequest{
+			AddUsers: []string{user3.ID.String()},
+		})
+		require.Error(t, err)
+		cerr, ok := codersdk.AsError(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusPreconditionFailed, cerr.StatusCode())
+
+		fetched, err := client.Group(ctx, group.ID)
+		require.NoError(t, err)
+		require.Equal(t, group.Version, fetched.Version)
+		require.NotContains(t, fetched.Members, user3)
+	})
+
+	t.Run("MemberConflict", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		_, user2 := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, user3 := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, user4 := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+		group, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{user2.ID.String()},
+		})
+		require.NoError(t, err)
+
+		// user2 is already a member and user3 isn't, so nothing is applied.
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers:    []string{user2.ID.String(), user4.ID.String()},
+			RemoveUsers: []string{user3.ID.String()},
+		})
+		require.Error(t, err)
+		cerr, ok := codersdk.AsError(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusConflict, cerr.StatusCode())
+		require.Len(t, cerr.Validations, 2)
+		require.Equal(t, "add_users", cerr.Validations[0].Field)
+		require.Contains(t, cerr.Validations[0].Detail, user2.ID.String())
+		require.Equal(t, "remove_users", cerr.Validations[1].Field)
+		require.Contains(t, cerr.Validations[1].Detail, user3.ID.String())
+
+		fetched, err := client.Group(ctx, group.ID)
+		require.NoError(t, err)
+		require.Equal(t, group.Version, fetched.Version)
+		require.NotContains(t, fetched.Members, user4)
+	})
+
 	t.Run("Audit", func(t *testing.T) {
 		t.Parallel()
 
@@ -442,7 +604,27 @@ func TestPatchGroup(t *testing.T) {
 			require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
 		})
 
-		t.Run("NoUpdateDisplayName", func(t *testing.T) {
+		t.Run("UpdateDisplaySettings", func(t *testing.T) {
+			t.Parallel()
+
+			client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			}})
+			ctx := testutil.Context(t, testutil.WaitLong)
+			group, err := client.PatchGroup(ctx, user.OrganizationID, codersdk.PatchGroupRequest{
+				DisplayName: ptr.Ref("All developers"),
+				AvatarURL:   ptr.Ref("https://example.com/everyone.png"),
+			})
+			require.NoError(t, err)
+			require.Equal(t, database.EveryoneGroup, group.Name)
+			require.Equal(t, "All developers", group.DisplayName)
+			require.Equal(t, "https://example.com/everyone.png", group.AvatarURL)
+			require.Len(t, group.Members, 1)
+		})
+
+		t.Run("NoQuotaOverride", func(t *testing.T) {
 			t.Parallel()
 
 			client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
@@ -452,7 +634,7 @@ func TestPatchGroup(t *testing.T) {
 			}})
 			ctx := testutil.Context(t, testutil.WaitLong)
 			_, err := client.PatchGroup(ctx, user.OrganizationID, codersdk.PatchGroupRequest{
-				DisplayName: ptr.Ref("hi"),
+				QuotaOverride: ptr.Ref(10),
 			})
 			require.Error(t, err)
 			cerr, ok := codersdk.AsError(err)
@@ -753,6 +935,138 @@ func TestGroups(t *testing.T) {
 	})
 }
 
+func TestGroupAvatar(t *testing.T) {
+	t.Parallel()
+
+	avatar := []byte("\x89PNG\r\n\x1a\nnot really an image")
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+
+		_, _, err = client.GroupAvatar(ctx, group.ID)
+		require.Error(t, err, "no avatar was uploaded")
+
+		group, err = client.UploadGroupAvatar(ctx, group.ID, "image/png", bytes.NewReader(avatar))
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(group.AvatarURL, fmt.Sprintf("/api/v2/groups/%s/avatar?hash=", group.ID)))
+
+		data, contentType, err := client.GroupAvatar(ctx, group.ID)
+		require.NoError(t, err)
+		require.Equal(t, avatar, data)
+		require.Equal(t, "image/png", contentType)
+
+		// Uploading the same avatar again keeps the URL.
+		again, err := client.UploadGroupAvatar(ctx, group.ID, "image/png", bytes.NewReader(avatar))
+		require.NoError(t, err)
+		require.Equal(t, group.AvatarURL, again.AvatarURL)
+
+		// Pointing the avatar URL elsewhere stops serving the upload.
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AvatarURL: ptr.Ref("https://example.com"),
+		})
+		require.NoError(t, err)
+		_, _, err = client.GroupAvatar(ctx, group.ID)
+		require.Error(t, err)
+		cerr, ok := codersdk.AsError(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusNotFound, cerr.StatusCode())
+	})
+
+	t.Run("SniffContentType", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+
+		// The header doesn't make a script an image.
+		_, err = client.UploadGroupAvatar(ctx, group.ID, "image/png", strings.NewReader("<html><script>alert(1)</script></html>"))
+		require.Error(t, err)
+		cerr, ok := codersdk.AsError(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+
+		// The avatar is served with the detected type.
+		_, err = client.UploadGroupAvatar(ctx, group.ID, "image/gif", bytes.NewReader(avatar))
+		require.NoError(t, err)
+		_, contentType, err := client.GroupAvatar(ctx, group.ID)
+		require.NoError(t, err)
+		require.Equal(t, "image/png", contentType)
+	})
+
+	t.Run("CacheHeaders", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+		group, err = client.UploadGroupAvatar(ctx, group.ID, "image/png", bytes.NewReader(avatar))
+		require.NoError(t, err)
+
+		res, err := client.Request(ctx, http.MethodGet, group.AvatarURL, nil)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Contains(t, res.Header.Get("Cache-Control"), "immutable")
+		etag := res.Header.Get("ETag")
+		require.NotEmpty(t, etag)
+
+		res, err = client.Request(ctx, http.MethodGet, fmt.Sprintf("/api/v2/groups/%s/avatar", group.ID), nil, func(r *http.Request) {
+			r.Header.Set("If-None-Match", etag)
+		})
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		require.Equal(t, http.StatusNotModified, res.StatusCode)
+		require.Equal(t, "private, no-cache", res.Header.Get("Cache-Control"))
+	})
+
+	t.Run("UnsupportedContentType", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+
+		_, err = client.UploadGroupAvatar(ctx, group.ID, "image/svg+xml", strings.NewReader("<svg/>"))
+		require.Error(t, err)
+		cerr, ok := codersdk.AsError(err)
+		require.True(t, ok)
+		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
+	})
+}
+
 func TestDeleteGroup(t *testing.T) {
 	t.Parallel()
 
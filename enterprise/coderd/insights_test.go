@@ -50,7 +50,7 @@ func TestTemplateInsightsWithTemplateAdminACL(t *testing.T) {
 			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
 			defer cancel()
 
-			err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			_, err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 				UserPerms: map[string]codersdk.TemplateRole{
 					regularUser.ID.String(): codersdk.TemplateRoleAdmin,
 				},
@@ -73,10 +73,14 @@ func Entitlements(
 		entitlements.HasLicense = true
 		entitlement := codersdk.EntitlementEntitled
 		entitlements.Trial = claims.Trial
+		var gracePeriodEndsAt *time.Time
 		if now.After(claims.LicenseExpires.Time) {
 			// if the grace period were over, the validation fails, so if we are after
 			// LicenseExpires we must be in grace period.
 			entitlement = codersdk.EntitlementGracePeriod
+			if claims.ExpiresAt != nil {
+				gracePeriodEndsAt = &claims.ExpiresAt.Time
+			}
 		}
 
 		// Add warning if license is expiring soon
@@ -110,15 +114,17 @@ func Entitlements(
 					limit = *priorLimit.Limit
 				}
 				entitlements.Features[codersdk.FeatureUserLimit] = codersdk.Feature{
-					Enabled:     true,
-					Entitlement: entitlement,
-					Limit:       &limit,
-					Actual:      &activeUserCount,
+					Enabled:           true,
+					Entitlement:       entitlement,
+					Limit:             &limit,
+					Actual:            &activeUserCount,
+					GracePeriodEndsAt: gracePeriodEndsAt,
 				}
 			default:
 				entitlements.Features[featureName] = codersdk.Feature{
-					Entitlement: entitlement,
-					Enabled:     enablements[featureName] || featureName.AlwaysEnable(),
+					Entitlement:       entitlement,
+					Enabled:           enablements[featureName] || featureName.AlwaysEnable(),
+					GracePeriodEndsAt: gracePeriodEndsAt,
 				}
 			}
 		}
@@ -137,6 +143,7 @@ func Entitlements(
 			}
 			feature := entitlements.Features[featureName]
 			feature.Entitlement = codersdk.EntitlementEntitled
+			feature.GracePeriodEndsAt = nil
 			entitlements.Features[featureName] = feature
 		}
 	}
@@ -224,12 +231,30 @@ func Entitlements(
 			feature.Enabled = false
 			entitlements.Features[featureName] = feature
 		}
+		if feature.Enabled && feature.GracePeriodEndsAt != nil &&
+			(entitlements.GracePeriodEndsAt == nil || feature.GracePeriodEndsAt.Before(*entitlements.GracePeriodEndsAt)) {
+			entitlements.GracePeriodEndsAt = feature.GracePeriodEndsAt
+		}
 	}
+	entitlements.Severity = Severity(entitlements)
 	entitlements.RefreshedAt = now
 
 	return entitlements, nil
 }
 
+// Severity returns the severity of the most severe warning or error of the
+// entitlements.
+func Severity(entitlements codersdk.Entitlements) codersdk.EntitlementsSeverity {
+	switch {
+	case len(entitlements.Errors) > 0:
+		return codersdk.EntitlementsSeverityError
+	case len(entitlements.Warnings) > 0:
+		return codersdk.EntitlementsSeverityWarning
+	default:
+		return codersdk.EntitlementsSeverityNone
+	}
+}
+
 const (
 	CurrentVersion        = 3
 	HeaderKeyID           = "kid"
@@ -33,6 +33,8 @@ func TestEntitlements(t *testing.T) {
 		require.NoError(t, err)
 		require.False(t, entitlements.HasLicense)
 		require.False(t, entitlements.Trial)
+		require.Equal(t, codersdk.EntitlementsSeverityNone, entitlements.Severity)
+		require.Nil(t, entitlements.GracePeriodEndsAt)
 		for _, featureName := range codersdk.FeatureNames {
 			require.False(t, entitlements.Features[featureName].Enabled)
 			require.Equal(t, codersdk.EntitlementNotEntitled, entitlements.Features[featureName].Entitlement)
@@ -89,6 +91,7 @@ func TestEntitlements(t *testing.T) {
 	t.Run("SingleLicenseGrace", func(t *testing.T) {
 		t.Parallel()
 		db := dbfake.New()
+		expiresAt := time.Now().Add(time.Hour)
 		db.InsertLicense(context.Background(), database.InsertLicenseParams{
 			JWT: coderdenttest.GenerateLicense(t, coderdenttest.LicenseOptions{
 				Features: license.Features{
@@ -97,7 +100,7 @@ func TestEntitlements(t *testing.T) {
 				},
 
 				GraceAt:   time.Now().Add(-time.Hour),
-				ExpiresAt: time.Now().Add(time.Hour),
+				ExpiresAt: expiresAt,
 			}),
 			Exp: time.Now().Add(time.Hour),
 		})
@@ -111,6 +114,11 @@ func TestEntitlements(t *testing.T) {
 			t, entitlements.Warnings,
 			fmt.Sprintf("%s is enabled but your license for this feature is expired.", codersdk.FeatureAuditLog.Humanize()),
 		)
+		require.Equal(t, codersdk.EntitlementsSeverityWarning, entitlements.Severity)
+		gracePeriodEndsAt := entitlements.Features[codersdk.FeatureAuditLog].GracePeriodEndsAt
+		require.NotNil(t, gracePeriodEndsAt)
+		require.WithinDuration(t, expiresAt, *gracePeriodEndsAt, time.Second)
+		require.Equal(t, gracePeriodEndsAt, entitlements.GracePeriodEndsAt)
 	})
 	t.Run("Expiration warning", func(t *testing.T) {
 		t.Parallel()
@@ -385,6 +393,7 @@ func TestEntitlements(t *testing.T) {
 		require.False(t, entitlements.HasLicense)
 		require.Len(t, entitlements.Errors, 1)
 		require.Equal(t, "You have multiple replicas but high availability is an Enterprise feature. You will be unable to connect to workspaces.", entitlements.Errors[0])
+		require.Equal(t, codersdk.EntitlementsSeverityError, entitlements.Severity)
 	})
 
 	t.Run("MultipleReplicasNotEntitled", func(t *testing.T) {
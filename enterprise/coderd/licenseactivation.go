@@ -0,0 +1,245 @@
+package coderd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// licenseRenewBefore is how long before their expiry licenses activated
+// online are renewed. Renewals are retried on every tick until then, so an
+// unreachable activation server doesn't lose the license right away.
+const licenseRenewBefore = 7 * 24 * time.Hour
+
+type licenseActivationRequest struct {
+	DeploymentID  string `json:"deployment_id"`
+	ActivationKey string `json:"activation_key"`
+}
+
+// fetchActivatedLicense exchanges the activation key for a license JWT with
+// the activation server.
+func (api *API) fetchActivatedLicense(ctx context.Context, activationKey string) (string, error) {
+	data, err := json.Marshal(licenseActivationRequest{
+		DeploymentID:  api.AGPL.DeploymentID,
+		ActivationKey: activationKey,
+	})
+	if err != nil {
+		return "", xerrors.Errorf("marshal: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, api.LicenseActivationURL, bytes.NewReader(data))
+	if err != nil {
+		return "", xerrors.Errorf("create activation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", xerrors.Errorf("perform activation request: %w", err)
+	}
+	defer res.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return "", xerrors.Errorf("read license: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", xerrors.Errorf("activation server responded with status %d: %s", res.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	return strings.TrimSpace(string(raw)), nil
+}
+
+// postActivateLicense exchanges an activation key for a license with the
+// activation server and adds it. The key is stored with the license, so it is
+// renewed before it expires.
+//
+// @Summary Activate license
+// @ID activate-license
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Organizations
+// @Param request body codersdk.ActivateLicenseRequest true "Activate license request"
+// @Success 201 {object} codersdk.License
+// @Router /licenses/activate [post]
+func (api *API) postActivateLicense(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.License](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	if !api.AGPL.Authorize(r, rbac.ActionCreate, rbac.ResourceLicense) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var activate codersdk.ActivateLicenseRequest
+	if !httpapi.Read(ctx, rw, r, &activate) {
+		return
+	}
+	if api.LicenseActivationURL == "" {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Online license activation is disabled.",
+			Detail:  "Set the license activation URL of the deployment to enable it.",
+		})
+		return
+	}
+
+	raw, err := api.fetchActivatedLicense(ctx, activate.ActivationKey)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: "Failed to activate license.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	rawClaims, params, err := parseLicense(raw, api.Keys)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadGateway, codersdk.Response{
+			Message: "The activation server returned an invalid license.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	params.ActivationKey = activate.ActivationKey
+	dl, err := api.Database.InsertLicense(ctx, params)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Unable to add license to database",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = dl
+
+	err = api.updateEntitlements(ctx)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to update entitlements",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	err = api.broadcastEntitlements()
+	if err != nil {
+		api.Logger.Error(context.Background(), "failed to publish license activation", slog.Error(err))
+		// don't fail the HTTP request, since we did write it successfully to the database
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertLicense(dl, rawClaims))
+}
+
+func (api *API) runLicenseRenewalLoop(ctx context.Context) {
+	ticker := time.NewTicker(api.LicenseRenewalInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := api.renewLicenses(ctx, time.Now())
+		if err != nil && !xerrors.Is(err, context.Canceled) {
+			api.Logger.Error(ctx, "renew licenses", slog.Error(err))
+		}
+	}
+}
+
+// renewLicenses replaces the licenses activated online that expire soon with
+// the ones the activation server issues for their keys.
+func (api *API) renewLicenses(ctx context.Context, now time.Time) error {
+	//nolint:gocritic // The system renews licenses without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	renewed := false
+	err := api.Database.InTx(func(tx database.Store) error {
+		// The transaction may be retried.
+		renewed = false
+
+		// Only one replica renews at a time, so the activation server isn't
+		// asked for the same license twice.
+		locked, err := tx.TryAcquireLock(ctx, database.GenLockID("license-renewal"))
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		licenses, err := tx.GetLicenses(ctx)
+		if err != nil {
+			return xerrors.Errorf("get licenses: %w", err)
+		}
+		for _, l := range licenses {
+			if l.ActivationKey == "" || l.Exp.After(now.Add(licenseRenewBefore)) {
+				continue
+			}
+			raw, err := api.fetchActivatedLicense(ctx, l.ActivationKey)
+			if err != nil {
+				api.Logger.Warn(ctx, "fetch renewed license", slog.F("license_id", l.ID), slog.Error(err))
+				continue
+			}
+			_, params, err := parseLicense(raw, api.Keys)
+			if err != nil {
+				api.Logger.Warn(ctx, "activation server returned an invalid license", slog.F("license_id", l.ID), slog.Error(err))
+				continue
+			}
+			// The activation server hands out the same license until it
+			// issued a new one.
+			if params.JWT == l.JWT || !params.Exp.After(l.Exp) {
+				continue
+			}
+			params.ActivationKey = l.ActivationKey
+			dl, err := tx.InsertLicense(ctx, params)
+			if err != nil {
+				return xerrors.Errorf("insert renewed license: %w", err)
+			}
+			_, err = tx.DeleteLicense(ctx, l.ID)
+			if err != nil {
+				return xerrors.Errorf("delete license %d: %w", l.ID, err)
+			}
+			api.Logger.Info(ctx, "renewed license",
+				slog.F("old_license_id", l.ID),
+				slog.F("license_id", dl.ID),
+				slog.F("expires_at", dl.Exp),
+			)
+			renewed = true
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+	if !renewed {
+		return nil
+	}
+
+	err = api.updateEntitlements(ctx)
+	if err != nil {
+		return xerrors.Errorf("update entitlements: %w", err)
+	}
+	err = api.broadcastEntitlements()
+	if err != nil {
+		api.Logger.Error(ctx, "failed to publish license renewal", slog.Error(err))
+	}
+	return nil
+}
@@ -17,20 +17,26 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
 )
 
 const (
 	PubsubEventLicenses = "licenses"
+	// PubsubEventEntitlements carries the entitlements computed by one
+	// replica to the others, so they don't all query the licenses.
+	PubsubEventEntitlements = "entitlements"
 )
 
 // key20220812 is the Coder license public key with id 2022-08-12 used to validate licenses signed
@@ -83,7 +89,7 @@ func (api *API) postLicense(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	rawClaims, err := license.ParseRaw(addLicense.License, api.Keys)
+	rawClaims, params, err := parseLicense(addLicense.License, api.Keys)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
 			Message: "Invalid license",
@@ -91,40 +97,7 @@ func (api *API) postLicense(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	exp, ok := rawClaims["exp"].(float64)
-	if !ok {
-		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-			Message: "Invalid license",
-			Detail:  "exp claim missing or not parsable",
-		})
-		return
-	}
-	expTime := time.Unix(int64(exp), 0)
-
-	claims, err := license.ParseClaims(addLicense.License, api.Keys)
-	if err != nil {
-		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-			Message: "Invalid license",
-			Detail:  err.Error(),
-		})
-		return
-	}
-
-	id, err := uuid.Parse(claims.ID)
-	if err != nil {
-		// If no uuid is in the license, we generate a random uuid.
-		// This is not ideal, and this should be fixed to require a uuid
-		// for all licenses. We require this patch to support older licenses.
-		// TODO: In the future (April 2023?) we should remove this and reissue
-		// old licenses with a uuid.
-		id = uuid.New()
-	}
-	dl, err := api.Database.InsertLicense(ctx, database.InsertLicenseParams{
-		UploadedAt: database.Now(),
-		JWT:        addLicense.License,
-		Exp:        expTime,
-		UUID:       id,
-	})
+	dl, err := api.Database.InsertLicense(ctx, params)
 	if err != nil {
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
 			Message: "Unable to add license to database",
@@ -142,7 +115,7 @@ func (api *API) postLicense(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	err = api.Pubsub.Publish(PubsubEventLicenses, []byte("add"))
+	err = api.broadcastEntitlements()
 	if err != nil {
 		api.Logger.Error(context.Background(), "failed to publish license add", slog.Error(err))
 		// don't fail the HTTP request, since we did write it successfully to the database
@@ -151,9 +124,44 @@ func (api *API) postLicense(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusCreated, convertLicense(dl, rawClaims))
 }
 
-// postRefreshEntitlements forces an `updateEntitlements` call and publishes
-// a message to the PubsubEventLicenses topic to force other replicas
-// to update their entitlements.
+// parseLicense validates the license JWT and returns its claims along with
+// the parameters to store it with.
+func parseLicense(raw string, keys map[string]ed25519.PublicKey) (jwt.MapClaims, database.InsertLicenseParams, error) {
+	rawClaims, err := license.ParseRaw(raw, keys)
+	if err != nil {
+		return nil, database.InsertLicenseParams{}, err
+	}
+	exp, ok := rawClaims["exp"].(float64)
+	if !ok {
+		return nil, database.InsertLicenseParams{}, xerrors.New("exp claim missing or not parsable")
+	}
+	expTime := time.Unix(int64(exp), 0)
+
+	claims, err := license.ParseClaims(raw, keys)
+	if err != nil {
+		return nil, database.InsertLicenseParams{}, err
+	}
+
+	id, err := uuid.Parse(claims.ID)
+	if err != nil {
+		// If no uuid is in the license, we generate a random uuid.
+		// This is not ideal, and this should be fixed to require a uuid
+		// for all licenses. We require this patch to support older licenses.
+		// TODO: In the future (April 2023?) we should remove this and reissue
+		// old licenses with a uuid.
+		id = uuid.New()
+	}
+	return rawClaims, database.InsertLicenseParams{
+		UploadedAt: database.Now(),
+		JWT:        raw,
+		Exp:        expTime,
+		UUID:       id,
+	}, nil
+}
+
+// postRefreshEntitlements forces an `updateEntitlements` call and broadcasts
+// the result on the PubsubEventEntitlements topic to update the entitlements
+// of other replicas.
 // Updates happen automatically on a timer, however that time is every 10 minutes,
 // and we want to be able to force an update immediately in some cases.
 //
@@ -205,7 +213,7 @@ func (api *API) postRefreshEntitlements(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = api.Pubsub.Publish(PubsubEventLicenses, []byte("refresh"))
+	err = api.broadcastEntitlements()
 	if err != nil {
 		api.Logger.Error(context.Background(), "failed to publish forced entitlement update", slog.Error(err))
 		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
@@ -261,6 +269,71 @@ func (api *API) licenses(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, sdkLicenses)
 }
 
+// licenseUsageDefaultDays is the number of days reported by the license usage
+// endpoint when no start date is given.
+const licenseUsageDefaultDays = 90
+
+// @Summary Get license usage
+// @ID get-license-usage
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param start_date query string false "First UTC day to report, formatted as YYYY-MM-DD"
+// @Param end_date query string false "Last UTC day to report, formatted as YYYY-MM-DD"
+// @Success 200 {object} codersdk.LicenseUsage
+// @Router /licenses/usage [get]
+func (api *API) licenseUsage(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.AGPL.Authorize(r, rbac.ActionRead, rbac.ResourceLicense) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	p := httpapi.NewQueryParamParser()
+	vals := r.URL.Query()
+	endDate := p.Time(vals, licenseUsageDate(time.Now()), "end_date", codersdk.LicenseUsageDateLayout)
+	startDate := p.Time(vals, endDate.AddDate(0, 0, -(licenseUsageDefaultDays-1)), "start_date", codersdk.LicenseUsageDateLayout)
+	p.ErrorExcessParams(vals)
+	if len(p.Errors) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Query parameters have invalid values.",
+			Validations: p.Errors,
+		})
+		return
+	}
+	if startDate.After(endDate) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Query parameter has invalid value.",
+			Validations: []codersdk.ValidationError{
+				{Field: "start_date", Detail: "Start date must not be after the end date."},
+			},
+		})
+		return
+	}
+
+	rows, err := api.Database.GetLicenseUsage(ctx, database.GetLicenseUsageParams{
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching license usage.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	api.entitlementsMu.RLock()
+	entitlements := api.entitlements
+	api.entitlementsMu.RUnlock()
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.LicenseUsage{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Features:  convertLicenseUsage(entitlements, rows),
+	})
+}
+
 // @Summary Delete license
 // @ID delete-license
 // @Security CoderSessionToken
@@ -326,7 +399,7 @@ func (api *API) deleteLicense(rw http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	err = api.Pubsub.Publish(PubsubEventLicenses, []byte("delete"))
+	err = api.broadcastEntitlements()
 	if err != nil {
 		api.Logger.Error(context.Background(), "failed to publish license delete", slog.Error(err))
 		// don't fail the HTTP request, since we did write it successfully to the database
@@ -334,6 +407,93 @@ func (api *API) deleteLicense(rw http.ResponseWriter, r *http.Request) {
 	rw.WriteHeader(http.StatusOK)
 }
 
+// recordLicenseUsage stores the usage of every feature that reports one, so
+// the daily peaks can be reconciled against the license later on.
+func (api *API) recordLicenseUsage(ctx context.Context, entitlements codersdk.Entitlements) error {
+	now := database.Now()
+	for _, name := range codersdk.FeatureNames {
+		feature := entitlements.Features[name]
+		if feature.Actual == nil {
+			continue
+		}
+		params := database.UpsertLicenseUsageParams{
+			Feature:   string(name),
+			Date:      licenseUsageDate(now),
+			Actual:    *feature.Actual,
+			UpdatedAt: now,
+		}
+		if feature.Limit != nil {
+			params.FeatureLimit = sql.NullInt64{Int64: *feature.Limit, Valid: true}
+			if *feature.Actual > *feature.Limit {
+				params.FirstExceededAt = sql.NullTime{Time: now, Valid: true}
+			}
+		}
+		// nolint:gocritic // Recording license usage is a system function.
+		err := api.Database.UpsertLicenseUsage(dbauthz.AsSystemRestricted(ctx), params)
+		if err != nil {
+			return xerrors.Errorf("record usage of %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// licenseUsageDate returns the UTC day usage at t is recorded on.
+func licenseUsageDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func convertLicenseUsage(entitlements codersdk.Entitlements, rows []database.LicenseUsage) []codersdk.LicenseFeatureUsage {
+	usage := make(map[codersdk.FeatureName]*codersdk.LicenseFeatureUsage)
+	get := func(name codersdk.FeatureName) *codersdk.LicenseFeatureUsage {
+		u, ok := usage[name]
+		if !ok {
+			feature := entitlements.Features[name]
+			u = &codersdk.LicenseFeatureUsage{
+				Feature: name,
+				Actual:  feature.Actual,
+				Limit:   feature.Limit,
+				Days:    []codersdk.LicenseUsageDay{},
+			}
+			usage[name] = u
+		}
+		return u
+	}
+	for name, feature := range entitlements.Features {
+		if feature.Actual != nil {
+			get(name)
+		}
+	}
+	for _, row := range rows {
+		u := get(codersdk.FeatureName(row.Feature))
+		day := codersdk.LicenseUsageDay{
+			Date:         row.Date,
+			PeakActual:   row.PeakActual,
+			LatestActual: row.LatestActual,
+		}
+		if row.FeatureLimit.Valid {
+			day.Limit = &row.FeatureLimit.Int64
+		}
+		if row.FirstExceededAt.Valid {
+			day.ExceededAt = &row.FirstExceededAt.Time
+			u.DaysExceeded++
+		}
+		if row.PeakActual > u.PeakActual {
+			u.PeakActual = row.PeakActual
+		}
+		u.Days = append(u.Days, day)
+	}
+
+	features := make([]codersdk.LicenseFeatureUsage, 0, len(usage))
+	for _, u := range usage {
+		features = append(features, *u)
+	}
+	slices.SortFunc(features, func(a, b codersdk.LicenseFeatureUsage) int {
+		return slice.Ascending(a.Feature, b.Feature)
+	})
+	return features
+}
+
 func convertLicense(dl database.License, c jwt.MapClaims) codersdk.License {
 	return codersdk.License{
 		ID:         dl.ID,
@@ -2,13 +2,18 @@ package coderd_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
 
+	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
 	"github.com/coder/coder/v2/enterprise/coderd/license"
@@ -67,6 +72,84 @@ func TestPostLicense(t *testing.T) {
 	})
 }
 
+func TestActivateLicense(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{DontAddLicense: true})
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.ActivateLicense(ctx, codersdk.ActivateLicenseRequest{
+			ActivationKey: "key",
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+
+	t.Run("Renews", func(t *testing.T) {
+		t.Parallel()
+		// Every license the activation server issues expires an hour after
+		// the previous one, and all of them are within the renewal window.
+		var issued atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			var req struct {
+				DeploymentID  string `json:"deployment_id"`
+				ActivationKey string `json:"activation_key"`
+			}
+			if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&req)) {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if req.ActivationKey != "key" || req.DeploymentID == "" {
+				rw.WriteHeader(http.StatusForbidden)
+				return
+			}
+			n := issued.Add(1)
+			_, _ = rw.Write([]byte(coderdenttest.GenerateLicense(t, coderdenttest.LicenseOptions{
+				AccountID: "activated",
+				ExpiresAt: time.Now().Add(time.Duration(n) * time.Hour),
+				Features: license.Features{
+					codersdk.FeatureAuditLog: 1,
+				},
+			})))
+		}))
+		t.Cleanup(srv.Close)
+
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{
+			DontAddLicense:         true,
+			LicenseActivationURL:   srv.URL,
+			LicenseRenewalInterval: testutil.IntervalFast,
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.ActivateLicense(ctx, codersdk.ActivateLicenseRequest{
+			ActivationKey: "wrong",
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadGateway, sdkErr.StatusCode())
+
+		activated, err := client.ActivateLicense(ctx, codersdk.ActivateLicenseRequest{
+			ActivationKey: "key",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "activated", activated.Claims["account_id"])
+
+		require.Eventually(t, func() bool {
+			licenses, err := client.Licenses(ctx)
+			if !assert.NoError(t, err) || len(licenses) != 1 {
+				return false
+			}
+			return licenses[0].ID != activated.ID && licenses[0].Claims["account_id"] == "activated"
+		}, testutil.WaitLong, testutil.IntervalFast)
+
+		entitlements, err := client.Entitlements(ctx)
+		require.NoError(t, err)
+		require.True(t, entitlements.HasLicense)
+	})
+}
+
 func TestGetLicense(t *testing.T) {
 	t.Parallel()
 	t.Run("Success", func(t *testing.T) {
@@ -186,3 +269,73 @@ func TestDeleteLicense(t *testing.T) {
 		assert.Len(t, licenses, 0)
 	})
 }
+
+func TestLicenseUsage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{DontAddLicense: true})
+		coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Adding the license refreshes entitlements, which records the usage.
+		coderdenttest.AddLicense(t, client, coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureUserLimit: 1,
+			},
+		})
+
+		usage, err := client.LicenseUsage(ctx, codersdk.LicenseUsageRequest{})
+		require.NoError(t, err)
+		require.Equal(t, usage.EndDate.AddDate(0, 0, -89), usage.StartDate)
+		require.Len(t, usage.Features, 1)
+		users := usage.Features[0]
+		require.Equal(t, codersdk.FeatureUserLimit, users.Feature)
+		require.NotNil(t, users.Actual)
+		require.EqualValues(t, 2, *users.Actual)
+		require.NotNil(t, users.Limit)
+		require.EqualValues(t, 1, *users.Limit)
+		require.EqualValues(t, 2, users.PeakActual)
+		require.EqualValues(t, 1, users.DaysExceeded)
+		require.Len(t, users.Days, 1)
+		require.Equal(t, usage.EndDate, users.Days[0].Date)
+		require.NotNil(t, users.Days[0].ExceededAt)
+
+		// Days outside of the range are not reported.
+		usage, err = client.LicenseUsage(ctx, codersdk.LicenseUsageRequest{
+			StartDate: time.Now().AddDate(0, 0, -10),
+			EndDate:   time.Now().AddDate(0, 0, -5),
+		})
+		require.NoError(t, err)
+		require.Len(t, usage.Features, 1)
+		require.Empty(t, usage.Features[0].Days)
+		require.Zero(t, usage.Features[0].PeakActual)
+	})
+
+	t.Run("InvalidRange", func(t *testing.T) {
+		t.Parallel()
+		client, _ := coderdenttest.New(t, &coderdenttest.Options{DontAddLicense: true})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.LicenseUsage(ctx, codersdk.LicenseUsageRequest{
+			StartDate: time.Now(),
+			EndDate:   time.Now().AddDate(0, 0, -1),
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		t.Parallel()
+		client, owner := coderdenttest.New(t, &coderdenttest.Options{DontAddLicense: true})
+		member, _ := coderdtest.CreateAnotherUser(t, client, owner.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.LicenseUsage(ctx, codersdk.LicenseUsageRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/yamux"
 	"github.com/moby/moby/pkg/namesgenerator"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 	"nhooyr.io/websocket"
 	"storj.io/drpc/drpcmux"
@@ -32,23 +33,6 @@ import (
 	"github.com/coder/coder/v2/provisionerd/proto"
 )
 
-func (api *API) provisionerDaemonsEnabledMW(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-		api.entitlementsMu.RLock()
-		epd := api.entitlements.Features[codersdk.FeatureExternalProvisionerDaemons].Enabled
-		api.entitlementsMu.RUnlock()
-
-		if !epd {
-			httpapi.Write(r.Context(), rw, http.StatusForbidden, codersdk.Response{
-				Message: "External provisioner daemons is an Enterprise feature. Contact sales!",
-			})
-			return
-		}
-
-		next.ServeHTTP(rw, r)
-	})
-}
-
 // @Summary Get provisioner daemons
 // @ID get-provisioner-daemons
 // @Security CoderSessionToken
@@ -88,6 +72,158 @@ func (api *API) provisionerDaemons(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, apiDaemons)
 }
 
+// @Summary Get pending provisioner jobs and matching daemons
+// @ID get-pending-provisioner-jobs-and-matching-daemons
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Success 200 {array} codersdk.PendingProvisionerJob
+// @Router /organizations/{organization}/provisionerdaemons/pending-jobs [get]
+func (api *API) provisionerDaemonPendingJobs(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		organization = httpmw.OrganizationParam(r)
+	)
+	jobs, err := api.Database.GetPendingProvisionerJobs(ctx)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching pending provisioner jobs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	daemons, err := api.Database.GetProvisionerDaemons(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching provisioner daemons.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiJobs := make([]codersdk.PendingProvisionerJob, 0, len(jobs))
+	for _, job := range jobs {
+		if job.OrganizationID != organization.ID {
+			continue
+		}
+		apiJob := codersdk.PendingProvisionerJob{
+			ID:              job.ID,
+			CreatedAt:       job.CreatedAt,
+			Provisioner:     codersdk.ProvisionerType(job.Provisioner),
+			Tags:            job.Tags,
+			MatchingDaemons: []codersdk.ProvisionerDaemon{},
+		}
+		for _, daemon := range daemons {
+			if !slices.Contains(daemon.Provisioners, job.Provisioner) {
+				continue
+			}
+			if !provisionerdserver.TagsSatisfy(job.Tags, daemon.Tags) {
+				continue
+			}
+			apiJob.MatchingDaemons = append(apiJob.MatchingDaemons, convertProvisionerDaemon(daemon))
+		}
+		apiJobs = append(apiJobs, apiJob)
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiJobs)
+}
+
+// @Summary Get provisioner job queue stats
+// @ID get-provisioner-job-queue-stats
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param organization path string true "Organization ID" format(uuid)
+// @Param provisioner query string false "Provisioner type" Enums(echo,terraform)
+// @Param tag query []string false "Provisioner daemon tags in the form key=value" collectionFormat(multi)
+// @Success 200 {object} codersdk.ProvisionerJobQueue
+// @Router /organizations/{organization}/provisionerdaemons/queue [get]
+func (api *API) provisionerDaemonQueue(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx          = r.Context()
+		apiKey       = httpmw.APIKey(r)
+		organization = httpmw.OrganizationParam(r)
+		filter       = provisionerdserver.QueueFilter{
+			OrganizationID: organization.ID,
+		}
+	)
+	switch provisioner := r.URL.Query().Get("provisioner"); provisioner {
+	case "":
+	case string(codersdk.ProvisionerTypeEcho), string(codersdk.ProvisionerTypeTerraform):
+		filter.Provisioner = database.ProvisionerType(provisioner)
+	default:
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Unknown provisioner type %q", provisioner),
+		})
+		return
+	}
+	if r.URL.Query().Has("tag") {
+		tags := map[string]string{}
+		for _, tag := range r.URL.Query()["tag"] {
+			parts := strings.SplitN(tag, "=", 2)
+			if len(parts) < 2 {
+				httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+					Message: fmt.Sprintf("Invalid format for tag %q. Key and value must be separated with =.", tag),
+				})
+				return
+			}
+			tags[parts[0]] = parts[1]
+		}
+		// Match the tags a daemon started by the user with these tags would
+		// have, so the scope may be omitted.
+		filter.Tags = provisionerdserver.MutateTags(apiKey.UserID, tags)
+	}
+
+	pending, err := api.Database.GetPendingProvisionerJobs(ctx)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching pending provisioner jobs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	now := database.Now()
+	started, err := api.Database.GetProvisionerJobsStartedAfter(ctx, now.Add(-provisionerdserver.QueueWaitWindow))
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching started provisioner jobs.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	unmatched := func(job database.ProvisionerJob) bool {
+		return !filter.Matches(job)
+	}
+	stats := provisionerdserver.ComputeQueueStats(now,
+		slices.DeleteFunc(pending, unmatched),
+		slices.DeleteFunc(started, unmatched),
+	)
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.ProvisionerJobQueue{
+		Depth:               int64(stats.Depth),
+		OldestJobAgeSeconds: stats.OldestJobAge.Seconds(),
+		JobAgeSeconds: codersdk.ProvisionerJobQueuePercentiles{
+			P50: stats.JobAgeP50.Seconds(),
+			P90: stats.JobAgeP90.Seconds(),
+			P99: stats.JobAgeP99.Seconds(),
+		},
+		AverageWaitSeconds: stats.AverageWait.Seconds(),
+		StartedJobs:        int64(stats.StartedJobs),
+		WaitWindowSeconds:  provisionerdserver.QueueWaitWindow.Seconds(),
+	})
+}
+
 type provisionerDaemonAuth struct {
 	psk        string
 	authorizer rbac.Authorizer
@@ -169,12 +305,39 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	err := provisionerdserver.ValidateDaemonTags(tags)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid provisioner daemon tags.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
 	tags, authorized := api.provisionerDaemonAuth.authorize(r, tags)
 	if !authorized {
 		httpapi.Write(ctx, rw, http.StatusForbidden,
 			codersdk.Response{Message: "You aren't allowed to create provisioner daemons"})
 		return
 	}
+	if !api.featureAvailable(rw, r, codersdk.FeatureExternalProvisionerDaemons) {
+		return
+	}
+	err = provisionerdserver.ResolveRegionTag(ctx, api.Database, tags)
+	if errors.Is(err, provisionerdserver.ErrUnknownRegion) {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("The %q tag must be the name or ID of a workspace proxy.", provisionerdserver.TagRegion),
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error resolving provisioner tags.",
+			Detail:  err.Error(),
+		})
+		return
+	}
 
 	provisioners := make([]database.ProvisionerType, 0)
 	for p := range provisionersMap {
@@ -247,12 +410,14 @@ func (api *API) provisionerDaemonServe(rw http.ResponseWriter, r *http.Request)
 		AccessURL:                   api.AccessURL,
 		GitAuthConfigs:              api.GitAuthConfigs,
 		OIDCConfig:                  api.OIDCConfig,
+		SecretManager:               api.SecretManager,
 		ID:                          daemon.ID,
 		Database:                    api.Database,
 		Pubsub:                      api.Pubsub,
 		Provisioners:                daemon.Provisioners,
 		Telemetry:                   api.Telemetry,
 		Auditor:                     &api.AGPL.Auditor,
+		Draining:                    &api.AGPL.Draining,
 		TemplateScheduleStore:       api.AGPL.TemplateScheduleStore,
 		UserQuietHoursScheduleStore: api.AGPL.UserQuietHoursScheduleStore,
 		Logger:                      api.Logger.Named(fmt.Sprintf("provisionerd-%s", daemon.Name)),
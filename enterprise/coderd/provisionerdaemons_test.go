@@ -305,4 +305,191 @@ func TestProvisionerDaemonServe(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, daemons, 0)
 	})
+
+	t.Run("Region", func(t *testing.T) {
+		t.Parallel()
+		dv := coderdtest.DeploymentValues(t)
+		dv.Experiments = []string{
+			string(codersdk.ExperimentMoons),
+			"*",
+		}
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				DeploymentValues: dv,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureExternalProvisionerDaemons: 1,
+					codersdk.FeatureWorkspaceProxy:             1,
+				},
+			},
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		proxy, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name: "sydney",
+		})
+		require.NoError(t, err)
+
+		_, err = client.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags: map[string]string{
+				provisionerdserver.TagRegion: "mars",
+			},
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusBadRequest, apiError.StatusCode())
+
+		// The proxy name is replaced with its ID.
+		srv, err := client.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags: map[string]string{
+				provisionerdserver.TagRegion: "sydney",
+			},
+		})
+		require.NoError(t, err)
+		srv.DRPCConn().Close()
+		daemons, err := client.ProvisionerDaemons(ctx)
+		require.NoError(t, err)
+		require.Len(t, daemons, 1)
+		require.Equal(t, proxy.Proxy.ID.String(), daemons[0].Tags[provisionerdserver.TagRegion])
+
+		// Templates can't be pinned to unknown regions either.
+		_, err = client.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			StorageMethod:   codersdk.ProvisionerStorageMethodFile,
+			FileID:          uuid.New(),
+			Provisioner:     codersdk.ProvisionerTypeEcho,
+			ProvisionerTags: map[string]string{provisionerdserver.TagRegion: "mars"},
+		})
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusBadRequest, apiError.StatusCode())
+	})
+
+	t.Run("TagExpressions", func(t *testing.T) {
+		t.Parallel()
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureExternalProvisionerDaemons: 1,
+			},
+		}})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		// Expressions are only allowed in job tags.
+		_, err := client.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+			Organization: user.OrganizationID,
+			Provisioners: []codersdk.ProvisionerType{
+				codersdk.ProvisionerTypeEcho,
+			},
+			Tags: map[string]string{
+				"cloud": "aws|gcp",
+			},
+		})
+		var apiError *codersdk.Error
+		require.ErrorAs(t, err, &apiError)
+		require.Equal(t, http.StatusBadRequest, apiError.StatusCode())
+
+		for _, cloud := range []string{"aws", "gcp"} {
+			srv, err := client.ServeProvisionerDaemon(ctx, codersdk.ServeProvisionerDaemonRequest{
+				Organization: user.OrganizationID,
+				Provisioners: []codersdk.ProvisionerType{
+					codersdk.ProvisionerTypeEcho,
+				},
+				Tags: map[string]string{
+					"cloud": cloud,
+				},
+			})
+			require.NoError(t, err)
+			srv.DRPCConn().Close()
+		}
+		daemons, err := client.ProvisionerDaemons(ctx)
+		require.NoError(t, err)
+		daemonIDs := map[string]uuid.UUID{}
+		for _, daemon := range daemons {
+			daemonIDs[daemon.Tags["cloud"]] = daemon.ID
+		}
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil, func(req *codersdk.CreateTemplateVersionRequest) {
+			req.ProvisionerTags = map[string]string{
+				"cloud": "aws|azure",
+			}
+		})
+
+		jobs, err := client.PendingProvisionerJobs(ctx, user.OrganizationID)
+		require.NoError(t, err)
+		var found bool
+		for _, job := range jobs {
+			if job.ID != version.Job.ID {
+				continue
+			}
+			found = true
+			require.Len(t, job.MatchingDaemons, 1)
+			require.Equal(t, daemonIDs["aws"], job.MatchingDaemons[0].ID)
+		}
+		require.True(t, found, "template version import job is pending")
+
+		// Members can't inspect the queue.
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, err = member.PendingProvisionerJobs(ctx, user.OrganizationID)
+		require.Error(t, err)
+	})
+}
+
+func TestProvisionerDaemonQueue(t *testing.T) {
+	t.Parallel()
+
+	client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+		Features: license.Features{
+			codersdk.FeatureExternalProvisionerDaemons: 1,
+		},
+	}})
+	ctx := testutil.Context(t, testutil.WaitLong)
+
+	// Without provisioner daemons, the import jobs stay pending.
+	coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil, func(req *codersdk.CreateTemplateVersionRequest) {
+		req.ProvisionerTags = map[string]string{
+			"cloud": "aws",
+		}
+	})
+
+	queue, err := client.ProvisionerJobQueue(ctx, user.OrganizationID, codersdk.ProvisionerJobQueueRequest{})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, queue.Depth)
+	require.GreaterOrEqual(t, queue.OldestJobAgeSeconds, queue.JobAgeSeconds.P50)
+	require.Zero(t, queue.StartedJobs)
+	require.Positive(t, queue.WaitWindowSeconds)
+
+	queue, err = client.ProvisionerJobQueue(ctx, user.OrganizationID, codersdk.ProvisionerJobQueueRequest{
+		Provisioner: codersdk.ProvisionerTypeEcho,
+		Tags: map[string]string{
+			"cloud": "gcp",
+		},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, queue.Depth, "only the untagged job may be acquired")
+
+	queue, err = client.ProvisionerJobQueue(ctx, user.OrganizationID, codersdk.ProvisionerJobQueueRequest{
+		Provisioner: codersdk.ProvisionerTypeTerraform,
+	})
+	require.NoError(t, err)
+	require.Zero(t, queue.Depth)
+
+	_, err = client.ProvisionerJobQueue(ctx, user.OrganizationID, codersdk.ProvisionerJobQueueRequest{
+		Provisioner: "pulumi",
+	})
+	var apiError *codersdk.Error
+	require.ErrorAs(t, err, &apiError)
+	require.Equal(t, http.StatusBadRequest, apiError.StatusCode())
+
+	// Members can't inspect the queue.
+	member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	_, err = member.ProvisionerJobQueue(ctx, user.OrganizationID, codersdk.ProvisionerJobQueueRequest{})
+	require.Error(t, err)
 }
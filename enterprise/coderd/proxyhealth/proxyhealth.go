@@ -3,6 +3,7 @@ package proxyhealth
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -15,12 +16,16 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/xerrors"
+	"tailscale.com/derp/derphttp"
+	"tailscale.com/types/key"
 
 	"cdr.dev/slog"
+	"github.com/coder/coder/v2/buildinfo"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/prometheusmetrics"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/tailnet"
 )
 
 type Status string
@@ -39,8 +44,20 @@ const (
 	// Unregistered means the proxy has not registered a url yet. This means
 	// the proxy was created with the cli, but has not yet been started.
 	Unregistered Status = "unregistered"
+	// VersionMismatch means the proxy access url is reachable and healthy, but
+	// the proxy runs a different version than this coderd.
+	VersionMismatch Status = "version_mismatch"
 )
 
+// VersionMismatched reports whether a proxy running the version is skewed
+// from this coderd. Development builds are only checked when running tests.
+func VersionMismatched(version string) bool {
+	if buildinfo.IsDev() && flag.Lookup("test.v") == nil {
+		return false
+	}
+	return version != buildinfo.Version()
+}
+
 type Options struct {
 	// Interval is the interval at which the proxy health is checked.
 	Interval   time.Duration
@@ -48,6 +65,9 @@ type Options struct {
 	Logger     slog.Logger
 	Client     *http.Client
 	Prometheus *prometheus.Registry
+	// VersionMismatchPolicy decides whether proxies with a mismatching
+	// version are used. They are blocked unless the policy is "warn".
+	VersionMismatchPolicy codersdk.ProxyVersionMismatchPolicy
 }
 
 // ProxyHealth runs a go routine that periodically checks the health of all
@@ -59,14 +79,18 @@ type ProxyHealth struct {
 	interval time.Duration
 	logger   slog.Logger
 	client   *http.Client
+	// blockVersionMismatch reports version mismatches as errors, which
+	// makes the proxies unusable.
+	blockVersionMismatch bool
 
 	// Cached values for quick access to the health of proxies.
 	cache      *atomic.Pointer[map[uuid.UUID]ProxyStatus]
 	proxyHosts *atomic.Pointer[[]string]
 
 	// PromMetrics
-	healthCheckDuration prometheus.Histogram
-	healthCheckResults  *prometheusmetrics.CachedGaugeVec
+	healthCheckDuration    prometheus.Histogram
+	healthCheckResults     *prometheusmetrics.CachedGaugeVec
+	derpHealthCheckResults *prometheusmetrics.CachedGaugeVec
 }
 
 func New(opts *Options) (*ProxyHealth, error) {
@@ -105,19 +129,31 @@ func New(opts *Options) (*ProxyHealth, error) {
 			Subsystem: "proxyhealth",
 			Name:      "health_check_results",
 			Help: "This endpoint returns a number to indicate the health status. " +
-				"-3 (unknown), -2 (Unreachable), -1 (Unhealthy), 0 (Unregistered), 1 (Healthy)",
+				"-3 (unknown), -2 (Unreachable), -1 (Unhealthy), 0 (Unregistered), 1 (Healthy), 2 (Version mismatch)",
 		}, []string{"proxy_id"}))
 	opts.Prometheus.MustRegister(healthCheckResults)
 
+	derpHealthCheckResults := prometheusmetrics.NewCachedGaugeVec(prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "coderd",
+			Subsystem: "proxyhealth",
+			Name:      "derp_health_check_results",
+			Help: "This endpoint returns a number to indicate the health status of the DERP server of the proxy. " +
+				"-3 (unknown), -2 (Unreachable), -1 (Unhealthy), 1 (Healthy)",
+		}, []string{"proxy_id"}))
+	opts.Prometheus.MustRegister(derpHealthCheckResults)
+
 	return &ProxyHealth{
-		db:                  opts.DB,
-		interval:            opts.Interval,
-		logger:              opts.Logger,
-		client:              client,
-		cache:               &atomic.Pointer[map[uuid.UUID]ProxyStatus]{},
-		proxyHosts:          &atomic.Pointer[[]string]{},
-		healthCheckDuration: healthCheckDuration,
-		healthCheckResults:  healthCheckResults,
+		db:                     opts.DB,
+		interval:               opts.Interval,
+		logger:                 opts.Logger,
+		client:                 client,
+		blockVersionMismatch:   opts.VersionMismatchPolicy != codersdk.ProxyVersionMismatchWarn,
+		cache:                  &atomic.Pointer[map[uuid.UUID]ProxyStatus]{},
+		proxyHosts:             &atomic.Pointer[[]string]{},
+		healthCheckDuration:    healthCheckDuration,
+		healthCheckResults:     healthCheckResults,
+		derpHealthCheckResults: derpHealthCheckResults,
 	}, nil
 }
 
@@ -145,7 +181,9 @@ func (p *ProxyHealth) Run(ctx context.Context) {
 func (p *ProxyHealth) storeProxyHealth(statuses map[uuid.UUID]ProxyStatus) {
 	var proxyHosts []string
 	for _, s := range statuses {
-		if s.ProxyHost != "" {
+		// DERP-only proxies don't serve apps, so browsers never connect to
+		// them directly.
+		if s.ProxyHost != "" && !s.Proxy.DerpOnly {
 			proxyHosts = append(proxyHosts, s.ProxyHost)
 		}
 	}
@@ -196,9 +234,36 @@ type ProxyStatus struct {
 	Status    Status
 	Report    codersdk.ProxyHealthReport
 	CheckedAt time.Time
+	// Latency is the round trip time of the health check request. It is
+	// zero if the proxy could not be reached.
+	Latency time.Duration
+	// DERPStatus is the result of connecting to the DERP server of the proxy
+	// and exchanging a ping with it. It is checked separately from the HTTP
+	// health, as a proxy can serve apps while its DERP server is broken.
+	// It is Unknown for proxies without DERP or that could not be reached.
+	DERPStatus Status
+	// DERPError explains why the DERP server is not healthy.
+	DERPError string
+	// Version is the Coder version the proxy responded with. It is empty if
+	// the proxy could not be reached.
+	Version string
 }
 
-// ProxyHosts returns the host:port of all healthy proxies.
+// Usable reports whether clients may be sent to the proxy. Proxies with a
+// mismatching version are usable unless the mismatch is reported as an error,
+// which the "block" version mismatch policy does.
+func (s ProxyStatus) Usable() bool {
+	switch s.Status {
+	case Healthy:
+		return true
+	case VersionMismatch:
+		return len(s.Report.Errors) == 0
+	default:
+		return false
+	}
+}
+
+// ProxyHosts returns the host:port of all healthy proxies that serve apps.
 // This can be computed from HealthStatus, but is cached to avoid the
 // caller needing to loop over all proxies to compute this on all
 // static web requests.
@@ -265,9 +330,13 @@ func (p *ProxyHealth) runOnce(ctx context.Context, now time.Time) (map[uuid.UUID
 			}
 			req = req.WithContext(gctx)
 
+			start := time.Now()
 			resp, err := p.client.Do(req)
 			if err == nil {
 				defer resp.Body.Close()
+				status.Latency = time.Since(start)
+				// Proxies add their version to every response.
+				status.Version = resp.Header.Get("X-Coder-Build-Version")
 			}
 			// A switch statement felt easier to categorize the different cases than
 			// if else statements or nested if statements.
@@ -286,6 +355,15 @@ func (p *ProxyHealth) runOnce(ctx context.Context, now time.Time) (map[uuid.UUID
 				}
 
 				status.Status = Healthy
+				if status.Version != "" && VersionMismatched(status.Version) {
+					status.Status = VersionMismatch
+					msg := fmt.Sprintf("proxy version %q does not match primary server version %q", status.Version, buildinfo.Version())
+					if p.blockVersionMismatch {
+						status.Report.Errors = append(status.Report.Errors, msg)
+					} else {
+						status.Report.Warnings = append(status.Report.Warnings, msg)
+					}
+				}
 			case err == nil && resp.StatusCode != http.StatusOK:
 				// Unhealthy as we did reach the proxy but it got an unexpected response.
 				status.Status = Unhealthy
@@ -308,10 +386,17 @@ func (p *ProxyHealth) runOnce(ctx context.Context, now time.Time) (map[uuid.UUID
 			}
 			status.ProxyHost = u.Host
 
+			status.DERPStatus = Unknown
+			if proxy.DerpEnabled && status.Status != Unreachable {
+				status.DERPStatus, status.DERPError = p.checkDERP(gctx, proxy.Url)
+			}
+
 			// Set the prometheus metric correctly.
 			switch status.Status {
 			case Healthy:
 				p.healthCheckResults.WithLabelValues(prometheusmetrics.VectorOperationSet, 1, proxy.ID.String())
+			case VersionMismatch:
+				p.healthCheckResults.WithLabelValues(prometheusmetrics.VectorOperationSet, 2, proxy.ID.String())
 			case Unhealthy:
 				p.healthCheckResults.WithLabelValues(prometheusmetrics.VectorOperationSet, -1, proxy.ID.String())
 			case Unreachable:
@@ -320,6 +405,16 @@ func (p *ProxyHealth) runOnce(ctx context.Context, now time.Time) (map[uuid.UUID
 				// Unknown
 				p.healthCheckResults.WithLabelValues(prometheusmetrics.VectorOperationSet, -3, proxy.ID.String())
 			}
+			switch status.DERPStatus {
+			case Healthy:
+				p.derpHealthCheckResults.WithLabelValues(prometheusmetrics.VectorOperationSet, 1, proxy.ID.String())
+			case Unhealthy:
+				p.derpHealthCheckResults.WithLabelValues(prometheusmetrics.VectorOperationSet, -1, proxy.ID.String())
+			case Unreachable:
+				p.derpHealthCheckResults.WithLabelValues(prometheusmetrics.VectorOperationSet, -2, proxy.ID.String())
+			default:
+				p.derpHealthCheckResults.WithLabelValues(prometheusmetrics.VectorOperationSet, -3, proxy.ID.String())
+			}
 
 			statusMu.Lock()
 			defer statusMu.Unlock()
@@ -333,6 +428,47 @@ func (p *ProxyHealth) runOnce(ctx context.Context, now time.Time) (map[uuid.UUID
 		return nil, xerrors.Errorf("group run: %w", err)
 	}
 	p.healthCheckResults.Commit()
+	p.derpHealthCheckResults.Commit()
 
 	return proxyStatus, nil
 }
+
+// checkDERP connects to the DERP server of the proxy, which covers the TLS
+// handshake and the DERP handshake, and then waits for the server to answer
+// a ping. A failed connection marks the DERP server unreachable, while a
+// missing answer marks it unhealthy.
+func (p *ProxyHealth) checkDERP(ctx context.Context, proxyURL string) (Status, string) {
+	ctx, cancel := context.WithTimeout(ctx, p.client.Timeout)
+	defer cancel()
+
+	derpURL := fmt.Sprintf("%s/derp", strings.TrimSuffix(proxyURL, "/"))
+	client, err := derphttp.NewClient(key.NewNode(), derpURL, tailnet.Logger(p.logger.Named("derp_check")))
+	if err != nil {
+		return Unhealthy, fmt.Sprintf("create derp client: %s", err.Error())
+	}
+	defer client.Close()
+	// Trust the same certificates as the HTTP health check.
+	if transport, ok := p.client.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		client.TLSConfig = transport.TLSClientConfig.Clone()
+	}
+
+	err = client.Connect(ctx)
+	if err != nil {
+		return Unreachable, fmt.Sprintf("connect to derp: %s", err.Error())
+	}
+
+	// Pongs are only handled while receiving.
+	go func() {
+		for {
+			_, err := client.Recv()
+			if err != nil {
+				return
+			}
+		}
+	}()
+	err = client.Ping(ctx)
+	if err != nil {
+		return Unhealthy, fmt.Sprintf("ping derp: %s", err.Error())
+	}
+	return Healthy, ""
+}
@@ -9,6 +9,9 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"golang.org/x/xerrors"
+	"tailscale.com/derp"
+	"tailscale.com/derp/derphttp"
+	"tailscale.com/types/key"
 
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/coderd/database"
@@ -17,6 +20,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/enterprise/coderd/proxyhealth"
+	"github.com/coder/coder/v2/tailnet"
 	"github.com/coder/coder/v2/testutil"
 )
 
@@ -145,6 +149,86 @@ func TestProxyHealth_Reachable(t *testing.T) {
 	}
 }
 
+func TestProxyHealth_VersionMismatch(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Coder-Build-Version", "v9.9.9")
+		httpapi.Write(context.Background(), w, http.StatusOK, codersdk.ProxyHealthReport{})
+	}))
+	t.Cleanup(srv.Close)
+
+	for _, tc := range []struct {
+		policy codersdk.ProxyVersionMismatchPolicy
+		usable bool
+	}{
+		{policy: codersdk.ProxyVersionMismatchWarn, usable: true},
+		{policy: codersdk.ProxyVersionMismatchBlock, usable: false},
+	} {
+		tc := tc
+		t.Run(string(tc.policy), func(t *testing.T) {
+			t.Parallel()
+			db := dbfake.New()
+			proxy := insertProxy(t, db, srv.URL)
+
+			ph, err := proxyhealth.New(&proxyhealth.Options{
+				Interval:              0,
+				DB:                    db,
+				Logger:                slogtest.Make(t, nil),
+				Client:                srv.Client(),
+				VersionMismatchPolicy: tc.policy,
+			})
+			require.NoError(t, err, "failed to create proxy health")
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+			defer cancel()
+
+			err = ph.ForceUpdate(ctx)
+			require.NoError(t, err, "failed to force update")
+			status := ph.HealthStatus()[proxy.ID]
+			require.Equal(t, proxyhealth.VersionMismatch, status.Status)
+			require.Equal(t, "v9.9.9", status.Version)
+			require.Equal(t, tc.usable, status.Usable())
+		})
+	}
+}
+
+func TestProxyHealth_ProxyHostsExcludeDERPOnly(t *testing.T) {
+	t.Parallel()
+	db := dbfake.New()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpapi.Write(context.Background(), w, http.StatusOK, codersdk.ProxyHealthReport{})
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	appProxy := insertProxy(t, db, srv.URL)
+	derpOnlyProxy, _ := dbgen.WorkspaceProxy(t, db, database.WorkspaceProxy{})
+	_, err := db.RegisterWorkspaceProxy(ctx, database.RegisterWorkspaceProxyParams{
+		Url:         srv.URL,
+		DerpEnabled: true,
+		DerpOnly:    true,
+		ID:          derpOnlyProxy.ID,
+	})
+	require.NoError(t, err, "failed to update proxy")
+
+	ph, err := proxyhealth.New(&proxyhealth.Options{
+		Interval: 0,
+		DB:       db,
+		Logger:   slogtest.Make(t, nil),
+		Client:   srv.Client(),
+	})
+	require.NoError(t, err, "failed to create proxy health")
+
+	err = ph.ForceUpdate(ctx)
+	require.NoError(t, err, "failed to force update")
+	require.Len(t, ph.ProxyHosts(), 1, "expect DERP-only proxy to be excluded")
+	require.Equal(t, ph.HealthStatus()[appProxy.ID].ProxyHost, ph.ProxyHosts()[0])
+}
+
 func TestProxyHealth_Unreachable(t *testing.T) {
 	t.Parallel()
 	db := dbfake.New()
@@ -180,3 +264,69 @@ func TestProxyHealth_Unreachable(t *testing.T) {
 		require.Equal(t, ph.HealthStatus()[p.ID].Status, proxyhealth.Unreachable, "expect unreachable proxy")
 	}
 }
+
+func TestProxyHealth_DERP(t *testing.T) {
+	t.Parallel()
+	db := dbfake.New()
+	logger := slogtest.Make(t, nil)
+
+	healthz := func(w http.ResponseWriter, r *http.Request) {
+		httpapi.Write(context.Background(), w, http.StatusOK, codersdk.ProxyHealthReport{})
+	}
+	derpServer := derp.NewServer(key.NewNode(), tailnet.Logger(logger.Named("derp")))
+	defer derpServer.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz-report", healthz)
+	mux.Handle("/derp", derphttp.Handler(derpServer))
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	// This proxy serves apps, but not DERP.
+	brokenMux := http.NewServeMux()
+	brokenMux.HandleFunc("/healthz-report", healthz)
+	brokenSrv := httptest.NewTLSServer(brokenMux)
+	defer brokenSrv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitShort)
+	defer cancel()
+
+	register := func(url string) database.WorkspaceProxy {
+		proxy, _ := dbgen.WorkspaceProxy(t, db, database.WorkspaceProxy{})
+		_, err := db.RegisterWorkspaceProxy(ctx, database.RegisterWorkspaceProxyParams{
+			Url:         url,
+			DerpEnabled: true,
+			ID:          proxy.ID,
+		})
+		require.NoError(t, err, "failed to update proxy")
+		return proxy
+	}
+	healthy := register(srv.URL)
+	broken := register(brokenSrv.URL)
+	noDERP := insertProxy(t, db, srv.URL)
+
+	// Both servers use the same certificate.
+	ph, err := proxyhealth.New(&proxyhealth.Options{
+		Interval: 0,
+		DB:       db,
+		Logger:   logger,
+		Client:   srv.Client(),
+	})
+	require.NoError(t, err, "failed to create proxy health")
+
+	err = ph.ForceUpdate(ctx)
+	require.NoError(t, err, "failed to force update")
+	statuses := ph.HealthStatus()
+
+	require.Equal(t, proxyhealth.Healthy, statuses[healthy.ID].Status)
+	require.Equal(t, proxyhealth.Healthy, statuses[healthy.ID].DERPStatus)
+	require.Empty(t, statuses[healthy.ID].DERPError)
+
+	// DERP health is reported separately from HTTP health.
+	require.Equal(t, proxyhealth.Healthy, statuses[broken.ID].Status)
+	require.Equal(t, proxyhealth.Unreachable, statuses[broken.ID].DERPStatus)
+	require.NotEmpty(t, statuses[broken.ID].DERPError)
+
+	require.Equal(t, proxyhealth.Healthy, statuses[noDERP.ID].Status)
+	require.Equal(t, proxyhealth.Unknown, statuses[noDERP.ID].DERPStatus)
+}
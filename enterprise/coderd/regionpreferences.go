@@ -0,0 +1,148 @@
+package coderd
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	agpl "github.com/coder/coder/v2/coderd"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// regionLatencyMaxAge is how long the latency a user reported for a region
+// is used to rank the regions for them.
+const regionLatencyMaxAge = 7 * 24 * time.Hour
+
+// ProxyGeoRule prefers a workspace proxy for clients in a network.
+type ProxyGeoRule struct {
+	Prefix    netip.Prefix
+	ProxyName string
+}
+
+// ParseProxyGeoRules parses rules in the form "10.0.0.0/8=sydney".
+func ParseProxyGeoRules(rules []string) ([]ProxyGeoRule, error) {
+	parsed := make([]ProxyGeoRule, 0, len(rules))
+	for _, rule := range rules {
+		prefix, name, ok := strings.Cut(rule, "=")
+		if !ok || name == "" {
+			return nil, xerrors.Errorf("invalid proxy geo rule %q: must be in the form CIDR=proxy-name", rule)
+		}
+		p, err := netip.ParsePrefix(strings.TrimSpace(prefix))
+		if err != nil {
+			return nil, xerrors.Errorf("invalid proxy geo rule %q: %w", rule, err)
+		}
+		parsed = append(parsed, ProxyGeoRule{
+			Prefix:    p.Masked(),
+			ProxyName: strings.TrimSpace(name),
+		})
+	}
+	return parsed, nil
+}
+
+// preferredRegionIDs orders the healthy regions from the most to the least
+// preferred for a user. Regions matched by the geo rules for the client
+// address come first, followed by the rest ranked by the latencies the user
+// reported recently.
+func (api *API) preferredRegionIDs(ctx context.Context, userID uuid.UUID, addr netip.Addr, regions []codersdk.Region) ([]uuid.UUID, error) {
+	rows, err := api.Database.GetUserRegionLatencies(ctx, database.GetUserRegionLatenciesParams{
+		UserID:       userID,
+		UpdatedAfter: database.Now().Add(-regionLatencyMaxAge),
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("get user region latencies: %w", err)
+	}
+	latencies := make([]codersdk.RegionLatency, 0, len(rows))
+	for _, row := range rows {
+		latencies = append(latencies, codersdk.RegionLatency{
+			RegionID:  row.RegionID,
+			LatencyMS: row.LatencyMS,
+		})
+	}
+
+	return preferRegions(regions, api.ProxyGeoRules, addr, latencies), nil
+}
+
+// storeRegionLatencies remembers the latencies the user reported for the
+// given regions, so later requests can rank the regions without a probe.
+// Latencies for unknown regions are ignored.
+func (api *API) storeRegionLatencies(r *http.Request, regions []codersdk.Region, latencies []codersdk.RegionLatency) error {
+	ctx := r.Context()
+	apiKey := httpmw.APIKey(r)
+
+	known := make(map[uuid.UUID]struct{}, len(regions))
+	for _, region := range regions {
+		known[region.ID] = struct{}{}
+	}
+	now := database.Now()
+	for _, latency := range latencies {
+		if _, ok := known[latency.RegionID]; !ok {
+			continue
+		}
+		err := api.Database.UpsertUserRegionLatency(ctx, database.UpsertUserRegionLatencyParams{
+			UserID:    apiKey.UserID,
+			RegionID:  latency.RegionID,
+			LatencyMS: latency.LatencyMS,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return xerrors.Errorf("upsert latency for region %s: %w", latency.RegionID, err)
+		}
+	}
+	return nil
+}
+
+func preferRegions(regions []codersdk.Region, rules []ProxyGeoRule, addr netip.Addr, latencies []codersdk.RegionLatency) []uuid.UUID {
+	byName := make(map[string]codersdk.Region, len(regions))
+	for _, region := range regions {
+		byName[region.Name] = region
+	}
+
+	preferred := make([]uuid.UUID, 0, len(regions))
+	seen := make(map[uuid.UUID]struct{}, len(regions))
+	if addr.IsValid() {
+		for _, rule := range rules {
+			if !rule.Prefix.Contains(addr) {
+				continue
+			}
+			region, ok := byName[rule.ProxyName]
+			if !ok || !region.Healthy {
+				continue
+			}
+			if _, ok := seen[region.ID]; ok {
+				continue
+			}
+			seen[region.ID] = struct{}{}
+			preferred = append(preferred, region.ID)
+		}
+	}
+
+	for _, region := range agpl.RankRegions(regions, latencies) {
+		if !region.Healthy {
+			// Unhealthy regions are ranked last.
+			break
+		}
+		if _, ok := seen[region.ID]; ok {
+			continue
+		}
+		seen[region.ID] = struct{}{}
+		preferred = append(preferred, region.ID)
+	}
+	return preferred
+}
+
+// clientAddr returns the address of the client, which the RealIP middleware
+// has already resolved from the trusted proxy headers.
+func clientAddr(r *http.Request) netip.Addr {
+	addr, err := netip.ParseAddr(r.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addr.Unmap()
+}
@@ -0,0 +1,34 @@
+package coderd_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/enterprise/coderd"
+)
+
+func TestParseProxyGeoRules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("OK", func(t *testing.T) {
+		t.Parallel()
+
+		rules, err := coderd.ParseProxyGeoRules([]string{"10.1.2.3/8=sydney", "2001:db8::/32 = frankfurt"})
+		require.NoError(t, err)
+		require.Equal(t, []coderd.ProxyGeoRule{
+			{Prefix: netip.MustParsePrefix("10.0.0.0/8"), ProxyName: "sydney"},
+			{Prefix: netip.MustParsePrefix("2001:db8::/32"), ProxyName: "frankfurt"},
+		}, rules)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, rule := range []string{"10.0.0.0/8", "10.0.0.0/8=", "10.0.0.0=sydney", "sydney=10.0.0.0/8"} {
+			_, err := coderd.ParseProxyGeoRules([]string{rule})
+			require.Error(t, err, rule)
+		}
+	})
+}
@@ -1,10 +1,14 @@
 package coderd
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/codersdk"
 )
@@ -32,7 +36,50 @@ func (api *API) replicas(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(r.Context(), rw, http.StatusOK, res)
 }
 
+// drainReplica marks a replica as draining. It stops accepting new tailnet
+// connections and provisioner jobs, so it can be stopped once existing
+// connections finish.
+//
+// @Summary Drain replica
+// @ID drain-replica
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param replica path string true "Replica ID" format(uuid)
+// @Success 200 {object} codersdk.Replica
+// @Router /replicas/{replica}/drain [post]
+func (api *API) drainReplica(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.AGPL.Authorize(r, rbac.ActionUpdate, rbac.ResourceReplicas) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	replicaID, ok := httpmw.ParseUUIDParam(rw, r, "replica")
+	if !ok {
+		return
+	}
+
+	replica, err := api.replicaManager.Drain(ctx, replicaID)
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error draining replica.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertReplica(replica))
+}
+
 func convertReplica(replica database.Replica) codersdk.Replica {
+	var drainingAt *time.Time
+	if replica.DrainingAt.Valid {
+		drainingAt = &replica.DrainingAt.Time
+	}
 	return codersdk.Replica{
 		ID:              replica.ID,
 		Hostname:        replica.Hostname,
@@ -41,5 +88,6 @@ func convertReplica(replica database.Replica) codersdk.Replica {
 		RegionID:        replica.RegionID,
 		Error:           replica.Error,
 		DatabaseLatency: replica.DatabaseLatency,
+		DrainingAt:      drainingAt,
 	}
 }
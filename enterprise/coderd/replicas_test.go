@@ -95,6 +95,46 @@ func TestReplicas(t *testing.T) {
 		}, testutil.WaitLong, testutil.IntervalFast)
 		_ = conn.Close()
 	})
+	t.Run("Drain", func(t *testing.T) {
+		t.Parallel()
+		db, pubsub := dbtestutil.NewDB(t)
+		firstClient, _ := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Database: db,
+				Pubsub:   pubsub,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureHighAvailability: 1,
+				},
+			},
+		})
+		_, _, secondAPI, _ := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				Database: db,
+				Pubsub:   pubsub,
+			},
+			DontAddLicense:   true,
+			DontAddFirstUser: true,
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		replica, err := firstClient.DrainReplica(ctx, secondAPI.AGPL.ID)
+		require.NoError(t, err)
+		require.NotNil(t, replica.DrainingAt)
+		require.Eventually(t, secondAPI.AGPL.Draining.Load, testutil.WaitShort, testutil.IntervalFast)
+
+		replicas, err := firstClient.Replicas(ctx)
+		require.NoError(t, err)
+		require.Len(t, replicas, 2)
+		for _, r := range replicas {
+			if r.ID == secondAPI.AGPL.ID {
+				require.NotNil(t, r.DrainingAt)
+			} else {
+				require.Nil(t, r.DrainingAt)
+			}
+		}
+	})
 	t.Run("ConnectAcrossMultipleTLS", func(t *testing.T) {
 		t.Parallel()
 		db, pubsub := dbtestutil.NewDB(t)
@@ -15,6 +15,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	agpl "github.com/coder/coder/v2/coderd/schedule"
 	"github.com/coder/coder/v2/coderd/tracing"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -83,9 +84,10 @@ func (s *EnterpriseTemplateScheduleStore) Get(ctx context.Context, db database.S
 			DaysOfWeek: uint8(tpl.RestartRequirementDaysOfWeek),
 			Weeks:      tpl.RestartRequirementWeeks,
 		},
-		FailureTTL:    time.Duration(tpl.FailureTTL),
-		InactivityTTL: time.Duration(tpl.InactivityTTL),
-		LockedTTL:     time.Duration(tpl.LockedTTL),
+		FailureTTL:              time.Duration(tpl.FailureTTL),
+		InactivityTTL:           time.Duration(tpl.InactivityTTL),
+		LockedTTL:               time.Duration(tpl.LockedTTL),
+		AutostopActivitySources: tpl.AutostopActivitySources,
 	}, nil
 }
 
@@ -101,6 +103,7 @@ func (s *EnterpriseTemplateScheduleStore) Set(ctx context.Context, db database.S
 		int64(opts.FailureTTL) == tpl.FailureTTL &&
 		int64(opts.InactivityTTL) == tpl.InactivityTTL &&
 		int64(opts.LockedTTL) == tpl.LockedTTL &&
+		slice.SameElements(opts.AutostopActivitySources, tpl.AutostopActivitySources) &&
 		opts.UserAutostartEnabled == tpl.AllowUserAutostart &&
 		opts.UserAutostopEnabled == tpl.AllowUserAutostop {
 		// Avoid updating the UpdatedAt timestamp if nothing will be changed.
@@ -111,6 +114,10 @@ func (s *EnterpriseTemplateScheduleStore) Set(ctx context.Context, db database.S
 	if err != nil {
 		return database.Template{}, err
 	}
+	if opts.AutostopActivitySources == nil {
+		// The column can't be null.
+		opts.AutostopActivitySources = []string{}
+	}
 
 	var template database.Template
 	err = db.InTx(func(tx database.Store) error {
@@ -129,6 +136,7 @@ func (s *EnterpriseTemplateScheduleStore) Set(ctx context.Context, db database.S
 			FailureTTL:                   int64(opts.FailureTTL),
 			InactivityTTL:                int64(opts.InactivityTTL),
 			LockedTTL:                    int64(opts.LockedTTL),
+			AutostopActivitySources:      opts.AutostopActivitySources,
 		})
 		if err != nil {
 			return xerrors.Errorf("update template schedule: %w", err)
@@ -1,10 +1,15 @@
 package coderd
 
 import (
+	"context"
 	"crypto/subtle"
 	"database/sql"
 	"encoding/json"
+	"math"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -12,6 +17,8 @@ import (
 	scimjson "github.com/imulab/go-scim/pkg/v2/json"
 	"github.com/imulab/go-scim/pkg/v2/service"
 	"github.com/imulab/go-scim/pkg/v2/spec"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
 	agpl "github.com/coder/coder/v2/coderd"
@@ -21,14 +28,23 @@ import (
 	"github.com/coder/coder/v2/codersdk"
 )
 
+// scimEnabledMW authenticates SCIM requests and rejects them unless SCIM is
+// enabled. Authentication happens first so unauthenticated callers can't
+// learn the entitlements of the deployment, and errors are SCIM errors so
+// identity providers can parse them.
 func (api *API) scimEnabledMW(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !api.scimVerifyAuthHeader(r) {
+			_ = handlerutil.WriteError(rw, xerrors.Errorf("scim: %w", &spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"}))
+			return
+		}
+
 		api.entitlementsMu.RLock()
 		scim := api.entitlements.Features[codersdk.FeatureSCIM].Enabled
 		api.entitlementsMu.RUnlock()
 
 		if !scim {
-			httpapi.RouteNotFound(rw)
+			_ = handlerutil.WriteError(rw, xerrors.Errorf("scim: %w", &spec.Error{Status: http.StatusForbidden, Type: "featureDisabled"}))
 			return
 		}
 
@@ -42,6 +58,21 @@ func (api *API) scimVerifyAuthHeader(r *http.Request) bool {
 	return len(api.SCIMAPIKey) != 0 && subtle.ConstantTimeCompare(hdr, api.SCIMAPIKey) == 1
 }
 
+// scimOrganizationID returns the organization that SCIM users and groups are
+// provisioned into. Once multi-organization support is added, we should enable
+// a configuration map of users and groups to organizations.
+func (api *API) scimOrganizationID(ctx context.Context) (uuid.UUID, error) {
+	//nolint:gocritic // needed for SCIM
+	organizations, err := api.Database.GetOrganizations(dbauthz.AsSystemRestricted(ctx))
+	if err != nil {
+		return uuid.Nil, xerrors.Errorf("get organizations: %w", err)
+	}
+	if len(organizations) == 0 {
+		return uuid.Nil, nil
+	}
+	return organizations[0].ID, nil
+}
+
 // scimGetUsers intentionally always returns no users. This is done to always force
 // Okta to try and create each user individually, this way we don't need to
 // implement fetching users twice.
@@ -169,12 +200,19 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 
 		if sUser.Active && dbUser.Status == database.UserStatusSuspended {
 			//nolint:gocritic
-			_, err = api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
-				ID: dbUser.ID,
-				// The user will get transitioned to Active after logging in.
-				Status:    database.UserStatusDormant,
-				UpdatedAt: database.Now(),
-			})
+			sysCtx := dbauthz.AsSystemRestricted(ctx)
+			err = api.Database.InTx(func(tx database.Store) error {
+				_, err := tx.UpdateUserStatus(sysCtx, database.UpdateUserStatusParams{
+					ID: dbUser.ID,
+					// The user will get transitioned to Active after logging in.
+					Status:    database.UserStatusDormant,
+					UpdatedAt: database.Now(),
+				})
+				if err != nil {
+					return err
+				}
+				return tx.DeleteUserDeprovision(sysCtx, dbUser.ID)
+			}, nil)
 			if err != nil {
 				_ = handlerutil.WriteError(rw, err)
 				return
@@ -199,21 +237,12 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 		sUser.UserName = httpapi.UsernameFrom(sUser.UserName)
 	}
 
-	var organizationID uuid.UUID
-	//nolint:gocritic
-	organizations, err := api.Database.GetOrganizations(dbauthz.AsSystemRestricted(ctx))
+	organizationID, err := api.scimOrganizationID(ctx)
 	if err != nil {
 		_ = handlerutil.WriteError(rw, err)
 		return
 	}
 
-	if len(organizations) > 0 {
-		// Add the user to the first organization. Once multi-organization
-		// support is added, we should enable a configuration map of user
-		// email to organization.
-		organizationID = organizations[0].ID
-	}
-
 	//nolint:gocritic // needed for SCIM
 	dbUser, _, err = api.AGPL.CreateUser(dbauthz.AsSystemRestricted(ctx), api.Database, agpl.CreateUserRequest{
 		CreateUserRequest: codersdk.CreateUserRequest{
@@ -234,7 +263,8 @@ func (api *API) scimPostUser(rw http.ResponseWriter, r *http.Request) {
 	httpapi.Write(ctx, rw, http.StatusOK, sUser)
 }
 
-// scimPatchUser supports suspending and activating users only.
+// scimPatchUser supports suspending and activating users only. Suspended
+// users are subject to the deprovisioning policy until they're reactivated.
 //
 // @Summary SCIM 2.0: Update user account
 // @ID scim-update-user-status
@@ -284,11 +314,26 @@ func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	//nolint:gocritic // needed for SCIM
-	_, err = api.Database.UpdateUserStatus(dbauthz.AsSystemRestricted(r.Context()), database.UpdateUserStatusParams{
-		ID:        dbUser.ID,
-		Status:    status,
-		UpdatedAt: database.Now(),
-	})
+	sysCtx := dbauthz.AsSystemRestricted(ctx)
+	err = api.Database.InTx(func(tx database.Store) error {
+		_, err := tx.UpdateUserStatus(sysCtx, database.UpdateUserStatusParams{
+			ID:        dbUser.ID,
+			Status:    status,
+			UpdatedAt: database.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		// Deactivated users are tracked so the deprovisioning policy can be
+		// applied to them, and stop being tracked once they're reactivated.
+		if status == database.UserStatusSuspended {
+			return tx.InsertUserDeprovision(sysCtx, database.InsertUserDeprovisionParams{
+				UserID:          dbUser.ID,
+				DeprovisionedAt: database.Now(),
+			})
+		}
+		return tx.DeleteUserDeprovision(sysCtx, dbUser.ID)
+	}, nil)
 	if err != nil {
 		_ = handlerutil.WriteError(rw, err)
 		return
@@ -296,3 +341,620 @@ func (api *API) scimPatchUser(rw http.ResponseWriter, r *http.Request) {
 
 	httpapi.Write(ctx, rw, http.StatusOK, sUser)
 }
+
+const (
+	scimGroupSchema     = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimListSchema      = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimGroupResourceID = "Group"
+)
+
+var (
+	// scimDisplayNameFilter matches the only filter identity providers use
+	// when looking up groups, e.g. `displayName eq "Engineering"`.
+	scimDisplayNameFilter = regexp.MustCompile(`(?i)^displayName\s+eq\s+"(.*)"$`)
+	// scimMemberPath matches a path that targets a single group member, e.g.
+	// `members[value eq "<user id>"]`.
+	scimMemberPath = regexp.MustCompile(`(?i)^members\[value\s+eq\s+"(.*)"\]$`)
+)
+
+// SCIMGroup is a group as exchanged with the identity provider. The display
+// name maps to the Coder group name, and members are referenced by the Coder
+// user ID returned when the user was provisioned.
+type SCIMGroup struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	DisplayName string            `json:"displayName"`
+	Members     []SCIMGroupMember `json:"members"`
+	Meta        struct {
+		ResourceType string `json:"resourceType"`
+	} `json:"meta"`
+}
+
+type SCIMGroupMember struct {
+	Value   string `json:"value" format:"uuid"`
+	Display string `json:"display,omitempty"`
+}
+
+type SCIMGroupList struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    []SCIMGroup `json:"Resources"`
+}
+
+// SCIMPatchRequest is a SCIM PatchOp message. Only the operations identity
+// providers use to manage groups are supported: replacing the display name,
+// and adding, removing or replacing members.
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+type SCIMPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value" swaggertype:"object"`
+}
+
+// scimGetGroups lists the groups of the organization. The only supported
+// filter is an exact match on the display name.
+//
+// @Summary SCIM 2.0: Get groups
+// @ID scim-get-groups
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param filter query string false "Filter, only 'displayName eq' is supported"
+// @Param startIndex query int false "1-based index of the first result"
+// @Param count query int false "Maximum number of results"
+// @Success 200 {object} coderd.SCIMGroupList
+// @Router /scim/v2/Groups [get]
+func (api *API) scimGetGroups(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	query := r.URL.Query()
+	startIndex, err := scimQueryInt(query.Get("startIndex"), 1)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	count, err := scimQueryInt(query.Get("count"), math.MaxInt32)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	// Out of range values are clamped as described in RFC 7644 3.4.2.4.
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = 0
+	}
+
+	organizationID, err := api.scimOrganizationID(ctx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	var groups []database.Group
+	if filter := query.Get("filter"); filter != "" {
+		match := scimDisplayNameFilter.FindStringSubmatch(filter)
+		if match == nil {
+			_ = handlerutil.WriteError(rw, xerrors.Errorf("unsupported filter %q: %w", filter, spec.ErrInvalidFilter))
+			return
+		}
+		//nolint:gocritic // needed for SCIM
+		group, err := api.Database.GetGroupByOrgAndName(dbauthz.AsSystemRestricted(ctx), database.GetGroupByOrgAndNameParams{
+			OrganizationID: organizationID,
+			Name:           strings.ReplaceAll(match[1], `\"`, `"`),
+		})
+		if err != nil && !xerrors.Is(err, sql.ErrNoRows) {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+		if err == nil {
+			groups = append(groups, group)
+		}
+	} else {
+		//nolint:gocritic // needed for SCIM
+		groups, err = api.Database.GetGroupsByOrganizationID(dbauthz.AsSystemRestricted(ctx), organizationID)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
+
+	// The "Everyone" group is managed by Coder and can't be changed by the
+	// identity provider, so it's hidden.
+	groups = slices.DeleteFunc(groups, database.Group.IsEveryone)
+	total := len(groups)
+	if startIndex > len(groups) {
+		groups = nil
+	} else {
+		groups = groups[startIndex-1:]
+	}
+	if count < len(groups) {
+		groups = groups[:count]
+	}
+
+	resources := make([]SCIMGroup, 0, len(groups))
+	for _, group := range groups {
+		sGroup, err := api.scimGroup(ctx, group)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+		resources = append(resources, sGroup)
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, SCIMGroupList{
+		Schemas:      []string{scimListSchema},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// @Summary SCIM 2.0: Get group by ID
+// @ID scim-get-group-by-id
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [get]
+func (api *API) scimGetGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	group, err := api.scimGroupParam(r)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	sGroup, err := api.scimGroup(ctx, group)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, sGroup)
+}
+
+// scimPostGroup creates a new group with the given members. Creating a group
+// with a name that is already taken fails with a uniqueness error, which
+// identity providers use to link the existing group instead.
+//
+// @Summary SCIM 2.0: Create new group
+// @ID scim-create-new-group
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param request body coderd.SCIMGroup true "New group"
+// @Success 201 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups [post]
+func (api *API) scimPostGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	var sGroup SCIMGroup
+	err := json.NewDecoder(r.Body).Decode(&sGroup)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("decode group: %w", spec.ErrInvalidSyntax))
+		return
+	}
+	if sGroup.DisplayName == "" || sGroup.DisplayName == database.EveryoneGroup {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("invalid display name %q: %w", sGroup.DisplayName, spec.ErrInvalidValue))
+		return
+	}
+
+	organizationID, err := api.scimOrganizationID(ctx)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	memberIDs, err := scimMemberIDs(sGroup.Members)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	err = api.scimVerifyMembers(ctx, organizationID, memberIDs)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	var group database.Group
+	//nolint:gocritic // needed for SCIM
+	err = api.Database.InTx(func(tx database.Store) error {
+		group, err = tx.InsertGroup(dbauthz.AsSystemRestricted(ctx), database.InsertGroupParams{
+			ID:             uuid.New(),
+			Name:           sGroup.DisplayName,
+			OrganizationID: organizationID,
+		})
+		if err != nil {
+			return xerrors.Errorf("insert group: %w", err)
+		}
+		for _, id := range memberIDs {
+			err = tx.InsertGroupMember(dbauthz.AsSystemRestricted(ctx), database.InsertGroupMemberParams{
+				GroupID: group.ID,
+				UserID:  id,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert group member %q: %w", id, err)
+			}
+		}
+		return nil
+	}, nil)
+	if database.IsUniqueViolation(err) {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("group %q already exists: %w", sGroup.DisplayName, spec.ErrUniqueness))
+		return
+	}
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	sGroup, err = api.scimGroup(ctx, group)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusCreated, sGroup)
+}
+
+// scimPatchGroup renames a group or changes its members.
+//
+// @Summary SCIM 2.0: Update group
+// @ID scim-update-group
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Param request body coderd.SCIMPatchRequest true "Patch group request"
+// @Success 200 {object} coderd.SCIMGroup
+// @Router /scim/v2/Groups/{id} [patch]
+func (api *API) scimPatchGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	var req SCIMPatchRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("decode patch request: %w", spec.ErrInvalidSyntax))
+		return
+	}
+
+	group, err := api.scimGroupParam(r)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	//nolint:gocritic // needed for SCIM
+	currentMembers, err := api.Database.GetGroupMembersAnyStatus(dbauthz.AsSystemRestricted(ctx), group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+	members := make(map[uuid.UUID]struct{}, len(currentMembers))
+	for _, member := range currentMembers {
+		members[member.ID] = struct{}{}
+	}
+
+	name := group.Name
+	for _, op := range req.Operations {
+		name, err = applySCIMGroupPatch(name, members, op)
+		if err != nil {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
+	if name == "" || name == database.EveryoneGroup {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("invalid display name %q: %w", name, spec.ErrInvalidValue))
+		return
+	}
+
+	if name != group.Name {
+		//nolint:gocritic // needed for SCIM
+		_, err = api.Database.GetGroupByOrgAndName(dbauthz.AsSystemRestricted(ctx), database.GetGroupByOrgAndNameParams{
+			OrganizationID: group.OrganizationID,
+			Name:           name,
+		})
+		if err == nil {
+			_ = handlerutil.WriteError(rw, xerrors.Errorf("group %q already exists: %w", name, spec.ErrUniqueness))
+			return
+		}
+		if !xerrors.Is(err, sql.ErrNoRows) {
+			_ = handlerutil.WriteError(rw, err)
+			return
+		}
+	}
+
+	var added, removed []uuid.UUID
+	for id := range members {
+		if !slices.ContainsFunc(currentMembers, func(u database.User) bool { return u.ID == id }) {
+			added = append(added, id)
+		}
+	}
+	for _, member := range currentMembers {
+		if _, ok := members[member.ID]; !ok {
+			removed = append(removed, member.ID)
+		}
+	}
+	err = api.scimVerifyMembers(ctx, group.OrganizationID, added)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	//nolint:gocritic // needed for SCIM
+	err = api.Database.InTx(func(tx database.Store) error {
+		if name != group.Name {
+			group, err = tx.UpdateGroupByID(dbauthz.AsSystemRestricted(ctx), database.UpdateGroupByIDParams{
+				ID:             group.ID,
+				Name:           name,
+				DisplayName:    group.DisplayName,
+				AvatarURL:      group.AvatarURL,
+				QuotaAllowance: group.QuotaAllowance,
+				QuotaOverride:  group.QuotaOverride,
+			})
+			if err != nil {
+				return xerrors.Errorf("update group: %w", err)
+			}
+		}
+		for _, id := range added {
+			err = tx.InsertGroupMember(dbauthz.AsSystemRestricted(ctx), database.InsertGroupMemberParams{
+				GroupID: group.ID,
+				UserID:  id,
+			})
+			if err != nil {
+				return xerrors.Errorf("insert group member %q: %w", id, err)
+			}
+		}
+		for _, id := range removed {
+			err = tx.DeleteGroupMemberFromGroup(dbauthz.AsSystemRestricted(ctx), database.DeleteGroupMemberFromGroupParams{
+				GroupID: group.ID,
+				UserID:  id,
+			})
+			if err != nil {
+				return xerrors.Errorf("delete group member %q: %w", id, err)
+			}
+		}
+		return nil
+	}, nil)
+	if database.IsUniqueViolation(err) {
+		_ = handlerutil.WriteError(rw, xerrors.Errorf("group %q already exists: %w", name, spec.ErrUniqueness))
+		return
+	}
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	sGroup, err := api.scimGroup(ctx, group)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, sGroup)
+}
+
+// @Summary SCIM 2.0: Delete group
+// @ID scim-delete-group
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param id path string true "Group ID" format(uuid)
+// @Success 204
+// @Router /scim/v2/Groups/{id} [delete]
+func (api *API) scimDeleteGroup(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if !api.scimVerifyAuthHeader(r) {
+		_ = handlerutil.WriteError(rw, spec.Error{Status: http.StatusUnauthorized, Type: "invalidAuthorization"})
+		return
+	}
+
+	group, err := api.scimGroupParam(r)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	//nolint:gocritic // needed for SCIM
+	err = api.Database.DeleteGroupByID(dbauthz.AsSystemRestricted(ctx), group.ID)
+	if err != nil {
+		_ = handlerutil.WriteError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// scimGroupParam returns the group from the "id" URL parameter. The "Everyone"
+// group is treated as if it doesn't exist.
+func (api *API) scimGroupParam(r *http.Request) (database.Group, error) {
+	id := chi.URLParam(r, "id")
+	groupID, err := uuid.Parse(id)
+	if err != nil {
+		return database.Group{}, xerrors.Errorf("group %q: %w", id, spec.ErrNotFound)
+	}
+
+	//nolint:gocritic // needed for SCIM
+	group, err := api.Database.GetGroupByID(dbauthz.AsSystemRestricted(r.Context()), groupID)
+	if xerrors.Is(err, sql.ErrNoRows) || (err == nil && group.IsEveryone()) {
+		return database.Group{}, xerrors.Errorf("group %q: %w", id, spec.ErrNotFound)
+	}
+	if err != nil {
+		return database.Group{}, xerrors.Errorf("get group: %w", err)
+	}
+	return group, nil
+}
+
+func (api *API) scimGroup(ctx context.Context, group database.Group) (SCIMGroup, error) {
+	//nolint:gocritic // needed for SCIM
+	members, err := api.Database.GetGroupMembersAnyStatus(dbauthz.AsSystemRestricted(ctx), group.ID)
+	if err != nil {
+		return SCIMGroup{}, xerrors.Errorf("get group members: %w", err)
+	}
+
+	sGroup := SCIMGroup{
+		Schemas:     []string{scimGroupSchema},
+		ID:          group.ID.String(),
+		DisplayName: group.Name,
+		Members:     make([]SCIMGroupMember, 0, len(members)),
+	}
+	sGroup.Meta.ResourceType = scimGroupResourceID
+	for _, member := range members {
+		sGroup.Members = append(sGroup.Members, SCIMGroupMember{
+			Value:   member.ID.String(),
+			Display: member.Username,
+		})
+	}
+	return sGroup, nil
+}
+
+// scimVerifyMembers checks that the users exist and are members of the
+// organization the group belongs to.
+func (api *API) scimVerifyMembers(ctx context.Context, organizationID uuid.UUID, userIDs []uuid.UUID) error {
+	for _, id := range userIDs {
+		//nolint:gocritic // needed for SCIM
+		_, err := api.Database.GetOrganizationMemberByUserID(dbauthz.AsSystemRestricted(ctx), database.GetOrganizationMemberByUserIDParams{
+			OrganizationID: organizationID,
+			UserID:         id,
+		})
+		if xerrors.Is(err, sql.ErrNoRows) {
+			return xerrors.Errorf("user %q is not a member of the organization: %w", id, spec.ErrInvalidValue)
+		}
+		if err != nil {
+			return xerrors.Errorf("get organization member: %w", err)
+		}
+	}
+	return nil
+}
+
+// applySCIMGroupPatch applies a single patch operation to the group name and
+// member set, returning the new name.
+func applySCIMGroupPatch(name string, members map[uuid.UUID]struct{}, op SCIMPatchOperation) (string, error) {
+	operation := strings.ToLower(op.Op)
+	if operation != "add" && operation != "remove" && operation != "replace" {
+		return "", xerrors.Errorf("unsupported operation %q: %w", op.Op, spec.ErrInvalidSyntax)
+	}
+
+	path := strings.TrimSpace(op.Path)
+	switch {
+	case path == "":
+		// Without a path the value holds the attributes to replace.
+		if operation == "remove" {
+			return "", xerrors.Errorf("remove requires a path: %w", spec.ErrNoTarget)
+		}
+		var value struct {
+			DisplayName *string           `json:"displayName"`
+			Members     []SCIMGroupMember `json:"members"`
+		}
+		err := json.Unmarshal(op.Value, &value)
+		if err != nil {
+			return "", xerrors.Errorf("decode value: %w", spec.ErrInvalidValue)
+		}
+		if value.DisplayName != nil {
+			name = *value.DisplayName
+		}
+		if value.Members != nil {
+			ids, err := scimMemberIDs(value.Members)
+			if err != nil {
+				return "", err
+			}
+			if operation == "replace" {
+				maps.Clear(members)
+			}
+			for _, id := range ids {
+				members[id] = struct{}{}
+			}
+		}
+	case strings.EqualFold(path, "displayName"):
+		if operation == "remove" {
+			return "", xerrors.Errorf("display name is required: %w", spec.ErrMutability)
+		}
+		err := json.Unmarshal(op.Value, &name)
+		if err != nil {
+			return "", xerrors.Errorf("decode display name: %w", spec.ErrInvalidValue)
+		}
+	case strings.EqualFold(path, "members"):
+		var value []SCIMGroupMember
+		if len(op.Value) > 0 {
+			err := json.Unmarshal(op.Value, &value)
+			if err != nil {
+				return "", xerrors.Errorf("decode members: %w", spec.ErrInvalidValue)
+			}
+		}
+		ids, err := scimMemberIDs(value)
+		if err != nil {
+			return "", err
+		}
+		// Removing "members" without a value removes every member.
+		if operation == "replace" || (operation == "remove" && len(ids) == 0) {
+			maps.Clear(members)
+		}
+		for _, id := range ids {
+			if operation == "remove" {
+				delete(members, id)
+			} else {
+				members[id] = struct{}{}
+			}
+		}
+	default:
+		match := scimMemberPath.FindStringSubmatch(path)
+		if match == nil || operation != "remove" {
+			return "", xerrors.Errorf("unsupported path %q: %w", op.Path, spec.ErrInvalidPath)
+		}
+		ids, err := scimMemberIDs([]SCIMGroupMember{{Value: match[1]}})
+		if err != nil {
+			return "", err
+		}
+		delete(members, ids[0])
+	}
+	return name, nil
+}
+
+func scimMemberIDs(members []SCIMGroupMember) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member.Value)
+		if err != nil {
+			return nil, xerrors.Errorf("member %q must be a user ID: %w", member.Value, spec.ErrInvalidValue)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func scimQueryInt(value string, def int) (int, error) {
+	if value == "" {
+		return def, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, xerrors.Errorf("invalid integer %q: %w", value, spec.ErrInvalidValue)
+	}
+	return i, nil
+}
@@ -8,9 +8,11 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/enterprise/coderd"
@@ -73,10 +75,23 @@ func TestScim(t *testing.T) {
 				},
 			})
 
+			// Unauthenticated callers can't tell whether SCIM is enabled.
 			res, err := client.Request(ctx, "POST", "/scim/v2/Users", struct{}{})
 			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+			res, err = client.Request(ctx, "POST", "/scim/v2/Users", struct{}{}, setScimAuth([]byte("hi")))
+			require.NoError(t, err)
 			defer res.Body.Close()
-			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
+			var scimErr struct {
+				Schemas []string `json:"schemas"`
+				Status  int      `json:"status"`
+			}
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&scimErr))
+			assert.Contains(t, scimErr.Schemas, "urn:ietf:params:scim:api:messages:2.0:Error")
 		})
 
 		t.Run("noAuth", func(t *testing.T) {
@@ -98,7 +113,7 @@ func TestScim(t *testing.T) {
 			res, err := client.Request(ctx, "POST", "/scim/v2/Users", struct{}{})
 			require.NoError(t, err)
 			defer res.Body.Close()
-			assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 		})
 
 		t.Run("OK", func(t *testing.T) {
@@ -268,11 +283,11 @@ func TestScim(t *testing.T) {
 				},
 			})
 
-			res, err := client.Request(ctx, "PATCH", "/scim/v2/Users/bob", struct{}{})
+			res, err := client.Request(ctx, "PATCH", "/scim/v2/Users/bob", struct{}{}, setScimAuth([]byte("hi")))
 			require.NoError(t, err)
 			_, _ = io.Copy(io.Discard, res.Body)
 			_ = res.Body.Close()
-			assert.Equal(t, http.StatusNotFound, res.StatusCode)
+			assert.Equal(t, http.StatusForbidden, res.StatusCode)
 		})
 
 		t.Run("noAuth", func(t *testing.T) {
@@ -295,7 +310,7 @@ func TestScim(t *testing.T) {
 			require.NoError(t, err)
 			_, _ = io.Copy(io.Discard, res.Body)
 			_ = res.Body.Close()
-			assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
 		})
 
 		t.Run("OK", func(t *testing.T) {
@@ -337,5 +352,246 @@ func TestScim(t *testing.T) {
 			require.Len(t, userRes.Users, 1)
 			assert.Equal(t, codersdk.UserStatusSuspended, userRes.Users[0].Status)
 		})
+
+		t.Run("StopWorkspaces", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			scimAPIKey := []byte("hi")
+			dv := coderdtest.DeploymentValues(t)
+			dv.SCIMDeprovisionStopWorkspaces = true
+			client, user := coderdenttest.New(t, &coderdenttest.Options{
+				Options: &coderdtest.Options{
+					DeploymentValues:         dv,
+					IncludeProvisionerDaemon: true,
+				},
+				SCIMAPIKey:              scimAPIKey,
+				SCIMDeprovisionInterval: testutil.IntervalFast,
+				LicenseOptions: &coderdenttest.LicenseOptions{
+					AccountID: "coolin",
+					Features: license.Features{
+						codersdk.FeatureSCIM: 1,
+					},
+				},
+			})
+
+			memberClient, member := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+			version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+			coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+			template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+			workspace := coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID)
+			coderdtest.AwaitWorkspaceBuildJob(t, client, workspace.LatestBuild.ID)
+
+			sUser := makeScimUser(t)
+			sUser.ID = member.ID.String()
+			sUser.Active = false
+			res, err := client.Request(ctx, "PATCH", "/scim/v2/Users/"+sUser.ID, sUser, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			require.Eventually(t, func() bool {
+				workspace, err = client.Workspace(ctx, workspace.ID)
+				return assert.NoError(t, err) && workspace.LatestBuild.Transition == codersdk.WorkspaceTransitionStop
+			}, testutil.WaitLong, testutil.IntervalFast)
+			assert.Equal(t, codersdk.BuildReasonAutostop, workspace.LatestBuild.Reason)
+		})
 	})
+
+	t.Run("groups", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("noAuth", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			client, _ := coderdenttest.New(t, &coderdenttest.Options{
+				SCIMAPIKey: []byte("hi"),
+				LicenseOptions: &coderdenttest.LicenseOptions{
+					AccountID: "coolin",
+					Features: license.Features{
+						codersdk.FeatureSCIM: 1,
+					},
+				},
+			})
+
+			res, err := client.Request(ctx, "GET", "/scim/v2/Groups", nil)
+			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		})
+
+		t.Run("OK", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			scimAPIKey := []byte("hi")
+			client, _ := coderdenttest.New(t, &coderdenttest.Options{
+				SCIMAPIKey: scimAPIKey,
+				LicenseOptions: &coderdenttest.LicenseOptions{
+					AccountID: "coolin",
+					Features: license.Features{
+						codersdk.FeatureSCIM:         1,
+						codersdk.FeatureTemplateRBAC: 1,
+					},
+				},
+			})
+
+			user1 := postScimUser(ctx, t, client, scimAPIKey)
+			user2 := postScimUser(ctx, t, client, scimAPIKey)
+
+			// Create a group with a single member.
+			var sGroup coderd.SCIMGroup
+			res, err := client.Request(ctx, "POST", "/scim/v2/Groups", coderd.SCIMGroup{
+				DisplayName: "engineering",
+				Members:     []coderd.SCIMGroupMember{{Value: user1.ID}},
+			}, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusCreated, res.StatusCode)
+			err = json.NewDecoder(res.Body).Decode(&sGroup)
+			require.NoError(t, err)
+			require.Equal(t, "engineering", sGroup.DisplayName)
+			require.Len(t, sGroup.Members, 1)
+
+			// The members are dormant until they log in, so they aren't
+			// listed by the groups API yet.
+			group, err := client.Group(ctx, uuid.MustParse(sGroup.ID))
+			require.NoError(t, err)
+			require.Equal(t, "engineering", group.Name)
+
+			// Creating the same group again is rejected.
+			res, err = client.Request(ctx, "POST", "/scim/v2/Groups", coderd.SCIMGroup{
+				DisplayName: "engineering",
+			}, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			require.Equal(t, http.StatusConflict, res.StatusCode)
+
+			// The group can be found by its display name, and the
+			// "Everyone" group isn't listed.
+			var list coderd.SCIMGroupList
+			res, err = client.Request(ctx, "GET", "/scim/v2/Groups", nil, setScimAuth(scimAPIKey), func(r *http.Request) {
+				q := r.URL.Query()
+				q.Set("filter", `displayName eq "engineering"`)
+				r.URL.RawQuery = q.Encode()
+			})
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			err = json.NewDecoder(res.Body).Decode(&list)
+			require.NoError(t, err)
+			require.Equal(t, 1, list.TotalResults)
+			require.Equal(t, sGroup.ID, list.Resources[0].ID)
+
+			res, err = client.Request(ctx, "GET", "/scim/v2/Groups", nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			err = json.NewDecoder(res.Body).Decode(&list)
+			require.NoError(t, err)
+			require.Equal(t, 1, list.TotalResults)
+
+			// Rename the group and swap its members the way Okta and
+			// Azure AD do.
+			res, err = client.Request(ctx, "PATCH", "/scim/v2/Groups/"+sGroup.ID, coderd.SCIMPatchRequest{
+				Operations: []coderd.SCIMPatchOperation{
+					{Op: "replace", Value: json.RawMessage(`{"id":"` + sGroup.ID + `","displayName":"platform"}`)},
+					{Op: "Add", Path: "members", Value: json.RawMessage(`[{"value":"` + user2.ID + `"}]`)},
+					{Op: "remove", Path: `members[value eq "` + user1.ID + `"]`},
+				},
+			}, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			err = json.NewDecoder(res.Body).Decode(&sGroup)
+			require.NoError(t, err)
+			require.Equal(t, "platform", sGroup.DisplayName)
+			require.Len(t, sGroup.Members, 1)
+			require.Equal(t, user2.ID, sGroup.Members[0].Value)
+
+			group, err = client.Group(ctx, group.ID)
+			require.NoError(t, err)
+			require.Equal(t, "platform", group.Name)
+
+			res, err = client.Request(ctx, "GET", "/scim/v2/Groups/"+sGroup.ID, nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, http.StatusOK, res.StatusCode)
+			err = json.NewDecoder(res.Body).Decode(&sGroup)
+			require.NoError(t, err)
+			require.Len(t, sGroup.Members, 1)
+			require.Equal(t, user2.ID, sGroup.Members[0].Value)
+
+			// Unknown users can't be added.
+			res, err = client.Request(ctx, "PATCH", "/scim/v2/Groups/"+sGroup.ID, coderd.SCIMPatchRequest{
+				Operations: []coderd.SCIMPatchOperation{
+					{Op: "add", Path: "members", Value: json.RawMessage(`[{"value":"` + uuid.NewString() + `"}]`)},
+				},
+			}, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+			// Delete the group.
+			res, err = client.Request(ctx, "DELETE", "/scim/v2/Groups/"+sGroup.ID, nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			_ = res.Body.Close()
+			require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+			res, err = client.Request(ctx, "GET", "/scim/v2/Groups/"+sGroup.ID, nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			require.Equal(t, http.StatusNotFound, res.StatusCode)
+		})
+
+		t.Run("Everyone", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+			defer cancel()
+
+			scimAPIKey := []byte("hi")
+			client, user := coderdenttest.New(t, &coderdenttest.Options{
+				SCIMAPIKey: scimAPIKey,
+				LicenseOptions: &coderdenttest.LicenseOptions{
+					AccountID: "coolin",
+					Features: license.Features{
+						codersdk.FeatureSCIM: 1,
+					},
+				},
+			})
+
+			// The "Everyone" group shares its ID with the organization.
+			res, err := client.Request(ctx, "DELETE", "/scim/v2/Groups/"+user.OrganizationID.String(), nil, setScimAuth(scimAPIKey))
+			require.NoError(t, err)
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+			require.Equal(t, http.StatusNotFound, res.StatusCode)
+		})
+	})
+}
+
+func postScimUser(ctx context.Context, t *testing.T, client *codersdk.Client, scimAPIKey []byte) coderd.SCIMUser {
+	t.Helper()
+
+	sUser := makeScimUser(t)
+	res, err := client.Request(ctx, "POST", "/scim/v2/Users", sUser, setScimAuth(scimAPIKey))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	err = json.NewDecoder(res.Body).Decode(&sUser)
+	require.NoError(t, err)
+	return sUser
 }
@@ -0,0 +1,210 @@
+package coderd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/provisionerdserver"
+	"github.com/coder/coder/v2/coderd/wsbuilder"
+)
+
+// scimDeprovisionPolicyEnabled returns whether anything beyond suspending the
+// user is configured for users the identity provider deactivates.
+func (api *API) scimDeprovisionPolicyEnabled() bool {
+	return api.SCIMDeprovisionStopWorkspaces || api.SCIMDeprovisionDeleteAfter > 0
+}
+
+// runSCIMDeprovisionLoop periodically applies the deprovisioning policy to
+// users the identity provider deactivated through SCIM.
+func (api *API) runSCIMDeprovisionLoop(ctx context.Context) {
+	ticker := time.NewTicker(api.SCIMDeprovisionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := api.reconcileSCIMDeprovisions(ctx, time.Now())
+		if err != nil && !xerrors.Is(err, context.Canceled) {
+			api.Logger.Error(ctx, "reconcile scim deprovisioned users", slog.Error(err))
+		}
+	}
+}
+
+// reconcileSCIMDeprovisions stops the workspaces of deprovisioned users and,
+// once the deletion delay has passed, deletes their workspaces and then the
+// users themselves. Every step is idempotent, so replicas can reconcile
+// concurrently and a step that couldn't start is retried on the next tick.
+func (api *API) reconcileSCIMDeprovisions(ctx context.Context, now time.Time) error {
+	//nolint:gocritic // The system applies the deprovisioning policy without user input.
+	ctx = dbauthz.AsSystemRestricted(ctx)
+
+	deprovisions, err := api.Database.GetUserDeprovisions(ctx)
+	if err != nil {
+		return xerrors.Errorf("get user deprovisions: %w", err)
+	}
+	for _, deprovision := range deprovisions {
+		err := api.reconcileSCIMDeprovision(ctx, deprovision, now)
+		if err != nil {
+			if xerrors.Is(err, context.Canceled) {
+				return err
+			}
+			api.Logger.Warn(ctx, "apply scim deprovisioning policy",
+				slog.F("user_id", deprovision.UserID),
+				slog.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+func (api *API) reconcileSCIMDeprovision(ctx context.Context, deprovision database.UserDeprovision, now time.Time) error {
+	user, err := api.Database.GetUserByID(ctx, deprovision.UserID)
+	if err != nil {
+		return xerrors.Errorf("get user: %w", err)
+	}
+	// The user was reactivated or deleted outside of SCIM, so the policy no
+	// longer applies.
+	if user.Deleted || user.Status != database.UserStatusSuspended {
+		return api.Database.DeleteUserDeprovision(ctx, user.ID)
+	}
+
+	deleteDue := api.SCIMDeprovisionDeleteAfter > 0 && !now.Before(deprovision.DeprovisionedAt.Add(api.SCIMDeprovisionDeleteAfter))
+	var (
+		transition database.WorkspaceTransition
+		reason     database.BuildReason
+	)
+	switch {
+	case deleteDue:
+		transition, reason = database.WorkspaceTransitionDelete, database.BuildReasonAutodelete
+	case api.SCIMDeprovisionStopWorkspaces:
+		transition, reason = database.WorkspaceTransitionStop, database.BuildReasonAutostop
+	default:
+		return nil
+	}
+
+	workspaces, err := api.Database.GetWorkspaces(ctx, database.GetWorkspacesParams{
+		OwnerID: user.ID,
+	})
+	if err != nil {
+		return xerrors.Errorf("get workspaces: %w", err)
+	}
+
+	if deleteDue && len(workspaces) == 0 {
+		err = api.Database.InTx(func(tx database.Store) error {
+			err := tx.UpdateUserDeletedByID(ctx, database.UpdateUserDeletedByIDParams{
+				ID:      user.ID,
+				Deleted: true,
+			})
+			if err != nil {
+				return xerrors.Errorf("delete user: %w", err)
+			}
+			return tx.DeleteUserDeprovision(ctx, user.ID)
+		}, nil)
+		if err != nil {
+			return err
+		}
+		api.Logger.Info(ctx, "deleted scim deprovisioned user",
+			slog.F("user_id", user.ID),
+			slog.F("deprovisioned_at", deprovision.DeprovisionedAt),
+		)
+		return nil
+	}
+
+	for _, workspace := range workspaces {
+		err := api.scimDeprovisionWorkspace(ctx, workspace.ID, transition, reason)
+		if err != nil {
+			if xerrors.Is(err, context.Canceled) {
+				return err
+			}
+			api.Logger.Warn(ctx, "transition workspace of scim deprovisioned user",
+				slog.F("user_id", user.ID),
+				slog.F("workspace_id", workspace.ID),
+				slog.F("transition", transition),
+				slog.Error(err),
+			)
+		}
+	}
+	return nil
+}
+
+// scimDeprovisionWorkspace transitions the workspace unless its latest build
+// already made, or is still making, the transition. Builds in progress are
+// left to finish and the workspace is checked again on the next tick.
+func (api *API) scimDeprovisionWorkspace(ctx context.Context, workspaceID uuid.UUID, transition database.WorkspaceTransition, reason database.BuildReason) error {
+	var job *database.ProvisionerJob
+	err := api.Database.InTx(func(tx database.Store) error {
+		// The transaction may be retried, so only the job of the attempt
+		// that commits is posted.
+		job = nil
+
+		// Replicas reconcile concurrently. Only one of them transitions the
+		// workspace, the others see the new build on their next tick.
+		locked, err := tx.TryAcquireLock(ctx, database.GenLockID(fmt.Sprintf("scim-deprovision:%s", workspaceID)))
+		if err != nil {
+			return xerrors.Errorf("acquire lock: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		workspace, err := tx.GetWorkspaceByID(ctx, workspaceID)
+		if err != nil {
+			return xerrors.Errorf("get workspace: %w", err)
+		}
+		latestBuild, err := tx.GetLatestWorkspaceBuildByWorkspaceID(ctx, workspace.ID)
+		if err != nil {
+			return xerrors.Errorf("get latest build: %w", err)
+		}
+		latestJob, err := tx.GetProvisionerJobByID(ctx, latestBuild.JobID)
+		if err != nil {
+			return xerrors.Errorf("get latest job: %w", err)
+		}
+		if !latestJob.CompletedAt.Valid {
+			return nil
+		}
+		switch latestBuild.Transition {
+		case transition, database.WorkspaceTransitionDelete:
+			// A failed build isn't retried so it doesn't fail again every
+			// tick. It's left for an administrator to resolve.
+			return nil
+		}
+
+		builder := wsbuilder.New(workspace, transition).
+			SetLastWorkspaceBuildInTx(&latestBuild).
+			SetLastWorkspaceBuildJobInTx(&latestJob).
+			Reason(reason)
+		_, job, err = builder.Build(ctx, tx, nil)
+		if err != nil {
+			return xerrors.Errorf("build: %w", err)
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return nil
+	}
+
+	api.Logger.Info(ctx, "scheduled workspace transition for scim deprovisioned user",
+		slog.F("workspace_id", workspaceID),
+		slog.F("transition", transition),
+	)
+	err = provisionerdserver.PostJob(api.Pubsub, *job)
+	if err != nil {
+		api.Logger.Error(ctx, "post provisioner job to pubsub", slog.Error(err))
+	}
+	return nil
+}
@@ -0,0 +1,296 @@
+package coderd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/coderd/audit"
+	"github.com/coder/coder/v2/coderd/database"
+	"github.com/coder/coder/v2/coderd/database/dbauthz"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/httpmw"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+// @Summary Request template access
+// @Description Asks the template admins for permission to use a template of
+// @Description one of your organizations.
+// @ID request-template-access
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param template path string true "Template ID" format(uuid)
+// @Param request body codersdk.CreateTemplateAccessRequest true "Create template access request"
+// @Success 201 {object} codersdk.TemplateAccessRequest
+// @Router /templates/{template}/access-requests [post]
+func (api *API) postTemplateAccessRequest(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateAccessRequest](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionCreate,
+		})
+	)
+	defer commitAudit()
+
+	templateID, ok := httpmw.ParseUUIDParam(rw, r, "template")
+	if !ok {
+		return
+	}
+
+	var req codersdk.CreateTemplateAccessRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	//nolint:gocritic // The requester can't read the template until the
+	// request is approved, so it's fetched as the system and only revealed
+	// to members of its organization.
+	template, err := api.Database.GetTemplateByID(dbauthz.AsSystemRestricted(ctx), templateID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	if template.Deleted || !api.Authorize(r, rbac.ActionRead, rbac.ResourceOrganization.WithID(template.OrganizationID).InOrg(template.OrganizationID)) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if api.Authorize(r, rbac.ActionRead, template) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "You already have access to this template.",
+		})
+		return
+	}
+
+	request, err := api.Database.InsertTemplateAccessRequest(ctx, database.InsertTemplateAccessRequestParams{
+		ID:          uuid.New(),
+		TemplateID:  template.ID,
+		RequestedBy: apiKey.UserID,
+		CreatedAt:   database.Now(),
+		Message:     req.Message,
+	})
+	if database.IsUniqueViolation(err) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "You already have a pending access request for this template.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error creating template access request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = request
+
+	api.AGPL.PlatformEvents.TemplateAccessRequest(ctx, codersdk.PlatformEventTypeTemplateAccessRequestCreated, request)
+
+	httpapi.Write(ctx, rw, http.StatusCreated, convertTemplateAccessRequest(request))
+}
+
+// @Summary Get template access requests
+// @Description Returns the access requests of the template, newest first.
+// @ID get-template-access-requests
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param template path string true "Template ID" format(uuid)
+// @Param status query string false "Filter by status" Enums(pending,approved,denied)
+// @Success 200 {array} codersdk.TemplateAccessRequest
+// @Router /templates/{template}/access-requests [get]
+func (api *API) templateAccessRequests(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		template = httpmw.TemplateParam(r)
+		status   = r.URL.Query().Get("status")
+	)
+
+	if status != "" && !database.TemplateAccessRequestStatus(status).Valid() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: fmt.Sprintf("Invalid status %q.", status),
+			Validations: []codersdk.ValidationError{
+				{Field: "status", Detail: "Must be one of pending, approved or denied."},
+			},
+		})
+		return
+	}
+
+	requests, err := api.Database.GetTemplateAccessRequestsByTemplateID(ctx, database.GetTemplateAccessRequestsByTemplateIDParams{
+		TemplateID: template.ID,
+		Status:     status,
+	})
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template access requests.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	apiRequests := make([]codersdk.TemplateAccessRequest, 0, len(requests))
+	for _, request := range requests {
+		apiRequests = append(apiRequests, convertTemplateAccessRequest(request))
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, apiRequests)
+}
+
+// @Summary Decide template access request
+// @Description Approves or denies a pending access request. Approving grants
+// @Description the requester the "use" role on the template.
+// @ID decide-template-access-request
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param templateaccessrequest path string true "Template access request ID" format(uuid)
+// @Param request body codersdk.DecideTemplateAccessRequest true "Decide template access request"
+// @Success 200 {object} codersdk.TemplateAccessRequest
+// @Router /templateaccessrequests/{templateaccessrequest}/decision [post]
+func (api *API) postTemplateAccessRequestDecision(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		apiKey            = httpmw.APIKey(r)
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.TemplateAccessRequest](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	defer commitAudit()
+
+	requestID, ok := httpmw.ParseUUIDParam(rw, r, "templateaccessrequest")
+	if !ok {
+		return
+	}
+
+	var req codersdk.DecideTemplateAccessRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	request, err := api.Database.GetTemplateAccessRequestByID(ctx, requestID)
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error fetching template access request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.Old = request
+
+	if request.Status != database.TemplateAccessRequestStatusPending {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The access request has already been decided.",
+		})
+		return
+	}
+
+	err = api.Database.InTx(func(tx database.Store) error {
+		request, err = tx.UpdateTemplateAccessRequestStatusByID(ctx, database.UpdateTemplateAccessRequestStatusByIDParams{
+			ID:        request.ID,
+			Status:    database.TemplateAccessRequestStatus(req.Status),
+			DecidedBy: uuid.NullUUID{UUID: apiKey.UserID, Valid: true},
+			DecidedAt: sql.NullTime{Time: database.Now(), Valid: true},
+			Reason:    req.Reason,
+		})
+		if err != nil {
+			return xerrors.Errorf("update template access request: %w", err)
+		}
+		if request.Status != database.TemplateAccessRequestStatusApproved {
+			return nil
+		}
+
+		template, err := tx.GetTemplateByID(ctx, request.TemplateID)
+		if err != nil {
+			return xerrors.Errorf("get template by ID: %w", err)
+		}
+		// Users that were granted a role on the template since they made the
+		// request keep it, approving never downgrades access.
+		if _, ok := template.UserACL[request.RequestedBy.String()]; ok {
+			return nil
+		}
+		if template.UserACL == nil {
+			template.UserACL = database.TemplateACL{}
+		}
+		template.UserACL[request.RequestedBy.String()] = convertSDKTemplateRole(codersdk.TemplateRoleUse)
+		err = tx.UpdateTemplateACLByID(ctx, database.UpdateTemplateACLByIDParams{
+			ID:       template.ID,
+			UserACL:  template.UserACL,
+			GroupACL: template.GroupACL,
+		})
+		if err != nil {
+			return xerrors.Errorf("update template ACL by ID: %w", err)
+		}
+		return nil
+	}, nil)
+	if dbauthz.IsNotAuthorizedError(err) {
+		httpapi.Forbidden(rw)
+		return
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		httpapi.Write(ctx, rw, http.StatusConflict, codersdk.Response{
+			Message: "The access request has already been decided.",
+		})
+		return
+	}
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error deciding template access request.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	aReq.New = request
+
+	api.AGPL.PlatformEvents.TemplateAccessRequest(ctx, codersdk.PlatformEventTypeTemplateAccessRequestDecided, request)
+
+	httpapi.Write(ctx, rw, http.StatusOK, convertTemplateAccessRequest(request))
+}
+
+func convertTemplateAccessRequest(request database.TemplateAccessRequest) codersdk.TemplateAccessRequest {
+	apiRequest := codersdk.TemplateAccessRequest{
+		ID:          request.ID,
+		TemplateID:  request.TemplateID,
+		RequestedBy: request.RequestedBy,
+		CreatedAt:   request.CreatedAt,
+		Message:     request.Message,
+		Status:      codersdk.TemplateAccessRequestStatus(request.Status),
+		Reason:      request.Reason,
+	}
+	if request.DecidedBy.Valid {
+		apiRequest.DecidedBy = &request.DecidedBy.UUID
+	}
+	if request.DecidedAt.Valid {
+		apiRequest.DecidedAt = &request.DecidedAt.Time
+	}
+	return apiRequest
+}
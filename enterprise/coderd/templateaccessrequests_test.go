@@ -0,0 +1,154 @@
+package coderd_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
+	"github.com/coder/coder/v2/enterprise/coderd/license"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestTemplateAccessRequests(t *testing.T) {
+	t.Parallel()
+
+	// setup returns a template that members of the organization can't use.
+	setup := func(t *testing.T) (*codersdk.Client, codersdk.CreateFirstUserResponse, codersdk.Template) {
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		_, err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			GroupPerms: map[string]codersdk.TemplateRole{
+				user.OrganizationID.String(): codersdk.TemplateRoleDeleted,
+			},
+		})
+		require.NoError(t, err)
+		return client, user, template
+	}
+
+	t.Run("Approve", func(t *testing.T) {
+		t.Parallel()
+		client, user, template := setup(t)
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := member.Template(ctx, template.ID)
+		require.Error(t, err)
+
+		request, err := member.RequestTemplateAccess(ctx, template.ID, codersdk.CreateTemplateAccessRequest{
+			Message: "For the data pipeline project.",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateAccessRequestStatusPending, request.Status)
+		require.Equal(t, memberUser.ID, request.RequestedBy)
+
+		// A single request can be pending at a time.
+		_, err = member.RequestTemplateAccess(ctx, template.ID, codersdk.CreateTemplateAccessRequest{})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		requests, err := client.TemplateAccessRequests(ctx, template.ID, codersdk.TemplateAccessRequestStatusPending)
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		require.Equal(t, request.ID, requests[0].ID)
+		require.Equal(t, "For the data pipeline project.", requests[0].Message)
+
+		decided, err := client.DecideTemplateAccessRequest(ctx, request.ID, codersdk.DecideTemplateAccessRequest{
+			Status: codersdk.TemplateAccessRequestStatusApproved,
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateAccessRequestStatusApproved, decided.Status)
+		require.NotNil(t, decided.DecidedBy)
+		require.Equal(t, user.UserID, *decided.DecidedBy)
+
+		_, err = member.Template(ctx, template.ID)
+		require.NoError(t, err)
+		acl, err := client.TemplateACL(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 1)
+		require.Equal(t, memberUser.ID, acl.Users[0].ID)
+		require.Equal(t, codersdk.TemplateRoleUse, acl.Users[0].Role)
+
+		// Requests can only be decided once.
+		_, err = client.DecideTemplateAccessRequest(ctx, request.ID, codersdk.DecideTemplateAccessRequest{
+			Status: codersdk.TemplateAccessRequestStatusDenied,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+
+		// Users that can use the template can't request access.
+		_, err = member.RequestTemplateAccess(ctx, template.ID, codersdk.CreateTemplateAccessRequest{})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusConflict, apiErr.StatusCode())
+	})
+
+	t.Run("Deny", func(t *testing.T) {
+		t.Parallel()
+		client, user, template := setup(t)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		request, err := member.RequestTemplateAccess(ctx, template.ID, codersdk.CreateTemplateAccessRequest{})
+		require.NoError(t, err)
+
+		decided, err := client.DecideTemplateAccessRequest(ctx, request.ID, codersdk.DecideTemplateAccessRequest{
+			Status: codersdk.TemplateAccessRequestStatusDenied,
+			Reason: "Use the shared template instead.",
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateAccessRequestStatusDenied, decided.Status)
+		require.Equal(t, "Use the shared template instead.", decided.Reason)
+
+		_, err = member.Template(ctx, template.ID)
+		require.Error(t, err)
+
+		pending, err := client.TemplateAccessRequests(ctx, template.ID, codersdk.TemplateAccessRequestStatusPending)
+		require.NoError(t, err)
+		require.Empty(t, pending)
+
+		// Denied users can ask again.
+		_, err = member.RequestTemplateAccess(ctx, template.ID, codersdk.CreateTemplateAccessRequest{})
+		require.NoError(t, err)
+		all, err := client.TemplateAccessRequests(ctx, template.ID, "")
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+	})
+
+	t.Run("MemberCannotDecide", func(t *testing.T) {
+		t.Parallel()
+		client, user, template := setup(t)
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		other, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		request, err := member.RequestTemplateAccess(ctx, template.ID, codersdk.CreateTemplateAccessRequest{})
+		require.NoError(t, err)
+
+		_, err = other.DecideTemplateAccessRequest(ctx, request.ID, codersdk.DecideTemplateAccessRequest{
+			Status: codersdk.TemplateAccessRequestStatusApproved,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+
+		_, err = member.DecideTemplateAccessRequest(ctx, request.ID, codersdk.DecideTemplateAccessRequest{
+			Status: codersdk.TemplateAccessRequestStatusApproved,
+		})
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusNotFound, apiErr.StatusCode())
+	})
+}
@@ -3,18 +3,23 @@ package coderd
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
 
 	"github.com/google/uuid"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 	"golang.org/x/xerrors"
 
+	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/audit"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbauthz"
 	"github.com/coder/coder/v2/coderd/httpapi"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/codersdk"
 )
 
@@ -32,9 +37,9 @@ func (api *API) templateAvailablePermissions(rw http.ResponseWriter, r *http.Req
 		template = httpmw.TemplateParam(r)
 	)
 
-	// Requires update permission on the template to list all avail users/groups
-	// for assignment.
-	if !api.Authorize(r, rbac.ActionUpdate, template) {
+	// Requires the permission to manage the ACL of the template to list all
+	// avail users/groups for assignment.
+	if !api.Authorize(r, rbac.ActionManageACL, template) {
 		httpapi.ResourceNotFound(rw)
 		return
 	}
@@ -153,19 +158,20 @@ func (api *API) templateACL(rw http.ResponseWriter, r *http.Request) {
 // @Tags Enterprise
 // @Param template path string true "Template ID" format(uuid)
 // @Param request body codersdk.UpdateTemplateACL true "Update template request"
-// @Success 200 {object} codersdk.Response
+// @Success 200 {object} codersdk.UpdateTemplateACLResponse
 // @Router /templates/{template}/acl [patch]
 func (api *API) patchTemplateACL(rw http.ResponseWriter, r *http.Request) {
 	var (
-		ctx               = r.Context()
-		template          = httpmw.TemplateParam(r)
-		auditor           = api.AGPL.Auditor.Load()
-		aReq, commitAudit = audit.InitRequest[database.Template](rw, &audit.RequestParams{
+		ctx         = r.Context()
+		template    = httpmw.TemplateParam(r)
+		auditor     = api.AGPL.Auditor.Load()
+		auditParams = &audit.RequestParams{
 			Audit:   *auditor,
 			Log:     api.Logger,
 			Request: r,
 			Action:  database.AuditActionWrite,
-		})
+		}
+		aReq, commitAudit = audit.InitRequest[database.Template](rw, auditParams)
 	)
 	defer commitAudit()
 	aReq.Old = template
@@ -187,12 +193,15 @@ func (api *API) patchTemplateACL(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var diff codersdk.TemplateACLDiff
 	err := api.Database.InTx(func(tx database.Store) error {
 		var err error
 		template, err = tx.GetTemplateByID(ctx, template.ID)
 		if err != nil {
 			return xerrors.Errorf("get template by ID: %w", err)
 		}
+		oldUserACL := maps.Clone(template.UserACL)
+		oldGroupACL := maps.Clone(template.GroupACL)
 
 		if len(req.UserPerms) > 0 {
 			for id, role := range req.UserPerms {
@@ -230,6 +239,10 @@ func (api *API) patchTemplateACL(rw http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			return xerrors.Errorf("get updated template by ID: %w", err)
 		}
+		diff = codersdk.TemplateACLDiff{
+			Users:  templateACLChanges(oldUserACL, template.UserACL),
+			Groups: templateACLChanges(oldGroupACL, template.GroupACL),
+		}
 		return nil
 	}, nil)
 	if err != nil {
@@ -238,12 +251,68 @@ func (api *API) patchTemplateACL(rw http.ResponseWriter, r *http.Request) {
 	}
 
 	aReq.New = template
+	// The audit diff of the raw ACL columns only shows rbac actions, so
+	// record the role changes of every principal as well.
+	auditParams.AdditionalFields, err = json.Marshal(templateACLAuditFields{
+		ACLChanges: diff,
+	})
+	if err != nil {
+		api.Logger.Warn(ctx, "marshal template ACL audit fields", slog.Error(err))
+		auditParams.AdditionalFields = nil
+	}
 
-	httpapi.Write(ctx, rw, http.StatusOK, codersdk.Response{
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.UpdateTemplateACLResponse{
 		Message: "Successfully updated template ACL list.",
+		Diff:    diff,
 	})
 }
 
+// templateACLAuditFields are the additional fields of the audit log of a
+// template ACL update.
+type templateACLAuditFields struct {
+	ACLChanges codersdk.TemplateACLDiff `json:"acl_changes"`
+}
+
+// templateACLChanges returns the principals whose role differs between the
+// old and new ACL, sorted by ID.
+func templateACLChanges(oldACL, newACL database.TemplateACL) []codersdk.TemplateACLChange {
+	ids := make(map[string]struct{}, len(oldACL)+len(newACL))
+	for id := range oldACL {
+		ids[id] = struct{}{}
+	}
+	for id := range newACL {
+		ids[id] = struct{}{}
+	}
+
+	changes := make([]codersdk.TemplateACLChange, 0)
+	for id := range ids {
+		var oldRole, newRole codersdk.TemplateRole
+		if actions, ok := oldACL[id]; ok {
+			oldRole = convertToTemplateRole(actions)
+		}
+		if actions, ok := newACL[id]; ok {
+			newRole = convertToTemplateRole(actions)
+		}
+		if oldRole == newRole {
+			continue
+		}
+		// Invalid IDs are rejected when updating the ACL.
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			continue
+		}
+		changes = append(changes, codersdk.TemplateACLChange{
+			ID:      parsed,
+			OldRole: oldRole,
+			NewRole: newRole,
+		})
+	}
+	slices.SortFunc(changes, func(a, b codersdk.TemplateACLChange) int {
+		return slice.Ascending(a.ID.String(), b.ID.String())
+	})
+	return changes
+}
+
 // nolint TODO fix stupid flag.
 func validateTemplateACLPerms(ctx context.Context, db database.Store, perms map[string]codersdk.TemplateRole, field string, isUser bool) []codersdk.ValidationError {
 	// Validate requires full read access to users and groups
@@ -305,20 +374,30 @@ func validateTemplateRole(role codersdk.TemplateRole) error {
 }
 
 func convertToTemplateRole(actions []rbac.Action) codersdk.TemplateRole {
-	switch {
-	case len(actions) == 1 && actions[0] == rbac.ActionRead:
-		return codersdk.TemplateRoleUse
-	case len(actions) == 1 && actions[0] == rbac.WildcardSymbol:
-		return codersdk.TemplateRoleAdmin
+	for _, role := range []codersdk.TemplateRole{
+		codersdk.TemplateRoleUse,
+		codersdk.TemplateRolePush,
+		codersdk.TemplateRoleSettings,
+		codersdk.TemplateRoleAdmin,
+	} {
+		if equalActions(actions, convertSDKTemplateRole(role)) {
+			return role
+		}
 	}
 
 	return ""
 }
 
+// convertSDKTemplateRole returns the actions granted on a template by the
+// given role.
 func convertSDKTemplateRole(role codersdk.TemplateRole) []rbac.Action {
 	switch role {
 	case codersdk.TemplateRoleAdmin:
 		return []rbac.Action{rbac.WildcardSymbol}
+	case codersdk.TemplateRoleSettings:
+		return []rbac.Action{rbac.ActionRead, rbac.ActionPushVersion, rbac.ActionUpdate}
+	case codersdk.TemplateRolePush:
+		return []rbac.Action{rbac.ActionRead, rbac.ActionPushVersion}
 	case codersdk.TemplateRoleUse:
 		return []rbac.Action{rbac.ActionRead}
 	}
@@ -326,20 +405,16 @@ func convertSDKTemplateRole(role codersdk.TemplateRole) []rbac.Action {
 	return nil
 }
 
-// TODO reduce the duplication across all of these.
-func (api *API) templateRBACEnabledMW(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-		api.entitlementsMu.RLock()
-		rbac := api.entitlements.Features[codersdk.FeatureTemplateRBAC].Enabled
-		api.entitlementsMu.RUnlock()
-
-		if !rbac {
-			httpapi.RouteNotFound(rw)
-			return
+func equalActions(a, b []rbac.Action) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, action := range a {
+		if !slices.Contains(b, action) {
+			return false
 		}
-
-		next.ServeHTTP(rw, r)
-	})
+	}
+	return true
 }
 
 func (api *API) moonsEnabledMW(next http.Handler) http.Handler {
@@ -351,13 +426,7 @@ func (api *API) moonsEnabledMW(next http.Handler) http.Handler {
 		}
 
 		// Entitlement must be enabled.
-		api.entitlementsMu.RLock()
-		proxy := api.entitlements.Features[codersdk.FeatureWorkspaceProxy].Enabled
-		api.entitlementsMu.RUnlock()
-		if !proxy {
-			httpapi.Write(r.Context(), rw, http.StatusForbidden, codersdk.Response{
-				Message: "External workspace proxies is an Enterprise feature. Contact sales!",
-			})
+		if !api.featureAvailable(rw, r, codersdk.FeatureWorkspaceProxy) {
 			return
 		}
 
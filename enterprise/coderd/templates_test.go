@@ -3,6 +3,7 @@ package coderd_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
 	"github.com/coder/coder/v2/enterprise/coderd/coderdenttest"
@@ -236,6 +238,57 @@ func TestTemplates(t *testing.T) {
 		require.Equal(t, lockedTTL, updated.LockedTTLMillis)
 	})
 
+	t.Run("SetAutostopActivitySources", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Context(t, testutil.WaitMedium)
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureAdvancedTemplateScheduling: 1,
+				},
+			},
+		})
+
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		require.Empty(t, template.AutostopActivitySources)
+
+		sources := []codersdk.AgentActivitySource{codersdk.AgentActivitySourceSSH, codersdk.AgentActivitySourceApp}
+		updated, err := client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Name:                         template.Name,
+			DisplayName:                  template.DisplayName,
+			Description:                  template.Description,
+			Icon:                         template.Icon,
+			AllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
+			DefaultTTLMillis:             template.DefaultTTLMillis,
+			AutostopActivitySources:      &sources,
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, sources, updated.AutostopActivitySources)
+
+		template, err = client.Template(ctx, template.ID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, sources, template.AutostopActivitySources)
+
+		_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Name:                         template.Name,
+			DisplayName:                  template.DisplayName,
+			Description:                  template.Description,
+			Icon:                         template.Icon,
+			AllowUserCancelWorkspaceJobs: template.AllowUserCancelWorkspaceJobs,
+			DefaultTTLMillis:             template.DefaultTTLMillis,
+			AutostopActivitySources:      &[]codersdk.AgentActivitySource{"keyboard"},
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusBadRequest, apiErr.StatusCode())
+	})
+
 	t.Run("UpdateLockedTTL", func(t *testing.T) {
 		t.Parallel()
 
@@ -441,7 +494,7 @@ func TestTemplateACL(t *testing.T) {
 
 		ctx := testutil.Context(t, testutil.WaitLong)
 
-		err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		_, err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 			UserPerms: map[string]codersdk.TemplateRole{
 				user2.ID.String(): codersdk.TemplateRoleUse,
 				user3.ID.String(): codersdk.TemplateRoleAdmin,
@@ -518,7 +571,7 @@ func TestTemplateACL(t *testing.T) {
 
 		allUsers := acl.Groups[0]
 
-		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		_, err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 			GroupPerms: map[string]codersdk.TemplateRole{
 				allUsers.ID.String(): codersdk.TemplateRoleDeleted,
 			},
@@ -555,7 +608,7 @@ func TestTemplateACL(t *testing.T) {
 
 		ctx := testutil.Context(t, testutil.WaitLong)
 
-		err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		_, err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 			UserPerms: map[string]codersdk.TemplateRole{
 				user1.ID.String(): codersdk.TemplateRoleUse,
 			},
@@ -593,7 +646,7 @@ func TestTemplateACL(t *testing.T) {
 
 		ctx := testutil.Context(t, testutil.WaitLong)
 
-		err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		_, err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 			UserPerms: map[string]codersdk.TemplateRole{
 				user1.ID.String(): codersdk.TemplateRoleUse,
 			},
@@ -635,7 +688,7 @@ func TestTemplateACL(t *testing.T) {
 		})
 		require.NoError(t, err)
 
-		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		_, err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 			GroupPerms: map[string]codersdk.TemplateRole{
 				group.ID.String(): codersdk.TemplateRoleUse,
 			},
@@ -679,7 +732,7 @@ func TestTemplateACL(t *testing.T) {
 
 		ctx := testutil.Context(t, testutil.WaitLong)
 
-		err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		_, err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 			UserPerms: map[string]codersdk.TemplateRole{
 				user1.ID.String(): codersdk.TemplateRoleUse,
 			},
@@ -700,7 +753,7 @@ func TestTemplateACL(t *testing.T) {
 		})
 		require.Error(t, err)
 
-		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		_, err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 			UserPerms: map[string]codersdk.TemplateRole{
 				user1.ID.String(): codersdk.TemplateRoleAdmin,
 			},
@@ -737,7 +790,7 @@ func TestUpdateTemplateACL(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
 		defer cancel()
 
-		err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		_, err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 			UserPerms: map[string]codersdk.TemplateRole{
 				user2.ID.String(): codersdk.TemplateRoleUse,
 				user3.ID.String(): codersdk.TemplateRoleAdmin,
@@ -793,13 +846,83 @@ func TestUpdateTemplateACL(t *testing.T) {
 				user.OrganizationID.String(): codersdk.TemplateRoleDeleted,
 			},
 		}
-		err := client.UpdateTemplateACL(ctx, template.ID, req)
+		_, err := client.UpdateTemplateACL(ctx, template.ID, req)
 		require.NoError(t, err)
 		numLogs++
 
 		require.Len(t, auditor.AuditLogs(), numLogs)
 		require.Equal(t, database.AuditActionWrite, auditor.AuditLogs()[numLogs-1].Action)
 		require.Equal(t, template.ID, auditor.AuditLogs()[numLogs-1].ResourceID)
+
+		var fields struct {
+			ACLChanges codersdk.TemplateACLDiff `json:"acl_changes"`
+		}
+		err = json.Unmarshal(auditor.AuditLogs()[numLogs-1].AdditionalFields, &fields)
+		require.NoError(t, err)
+		require.Empty(t, fields.ACLChanges.Users)
+		require.Equal(t, []codersdk.TemplateACLChange{{
+			ID:      user.OrganizationID,
+			OldRole: codersdk.TemplateRoleUse,
+			NewRole: codersdk.TemplateRoleDeleted,
+		}}, fields.ACLChanges.Groups)
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		t.Parallel()
+
+		client, user := coderdenttest.New(t, &coderdenttest.Options{LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		}})
+
+		_, user2 := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		_, user3 := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		resp, err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			UserPerms: map[string]codersdk.TemplateRole{
+				user2.ID.String(): codersdk.TemplateRoleUse,
+				user3.ID.String(): codersdk.TemplateRoleAdmin,
+			},
+		})
+		require.NoError(t, err)
+		require.Empty(t, resp.Diff.Groups)
+		require.Len(t, resp.Diff.Users, 2)
+		require.Contains(t, resp.Diff.Users, codersdk.TemplateACLChange{
+			ID:      user2.ID,
+			NewRole: codersdk.TemplateRoleUse,
+		})
+		require.Contains(t, resp.Diff.Users, codersdk.TemplateACLChange{
+			ID:      user3.ID,
+			NewRole: codersdk.TemplateRoleAdmin,
+		})
+
+		// Setting a role a user already has is not a change.
+		resp, err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			UserPerms: map[string]codersdk.TemplateRole{
+				user2.ID.String(): codersdk.TemplateRolePush,
+				user3.ID.String(): codersdk.TemplateRoleAdmin,
+			},
+			GroupPerms: map[string]codersdk.TemplateRole{
+				user.OrganizationID.String(): codersdk.TemplateRoleDeleted,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, codersdk.TemplateACLDiff{
+			Users: []codersdk.TemplateACLChange{{
+				ID:      user2.ID,
+				OldRole: codersdk.TemplateRoleUse,
+				NewRole: codersdk.TemplateRolePush,
+			}},
+			Groups: []codersdk.TemplateACLChange{{
+				ID:      user.OrganizationID,
+				OldRole: codersdk.TemplateRoleUse,
+			}},
+		}, resp.Diff)
 	})
 
 	t.Run("DeleteUser", func(t *testing.T) {
@@ -825,7 +948,7 @@ func TestUpdateTemplateACL(t *testing.T) {
 		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
 		defer cancel()
 
-		err := client.UpdateTemplateACL(ctx, template.ID, req)
+		_, err := client.UpdateTemplateACL(ctx, template.ID, req)
 		require.NoError(t, err)
 
 		acl, err := client.TemplateACL(ctx, template.ID)
@@ -846,7 +969,7 @@ func TestUpdateTemplateACL(t *testing.T) {
 			},
 		}
 
-		err = client.UpdateTemplateACL(ctx, template.ID, req)
+		_, err = client.UpdateTemplateACL(ctx, template.ID, req)
 		require.NoError(t, err)
 
 		acl, err = client.TemplateACL(ctx, template.ID)
@@ -882,7 +1005,7 @@ func TestUpdateTemplateACL(t *testing.T) {
 
 		ctx := testutil.Context(t, testutil.WaitLong)
 
-		err := client.UpdateTemplateACL(ctx, template.ID, req)
+		_, err := client.UpdateTemplateACL(ctx, template.ID, req)
 		require.Error(t, err)
 		cerr, _ := codersdk.AsError(err)
 		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
@@ -907,7 +1030,7 @@ func TestUpdateTemplateACL(t *testing.T) {
 
 		ctx := testutil.Context(t, testutil.WaitLong)
 
-		err := client.UpdateTemplateACL(ctx, template.ID, req)
+		_, err := client.UpdateTemplateACL(ctx, template.ID, req)
 		require.Error(t, err)
 		cerr, _ := codersdk.AsError(err)
 		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
@@ -933,7 +1056,7 @@ func TestUpdateTemplateACL(t *testing.T) {
 
 		ctx := testutil.Context(t, testutil.WaitLong)
 
-		err := client.UpdateTemplateACL(ctx, template.ID, req)
+		_, err := client.UpdateTemplateACL(ctx, template.ID, req)
 		require.Error(t, err)
 		cerr, _ := codersdk.AsError(err)
 		require.Equal(t, http.StatusBadRequest, cerr.StatusCode())
@@ -959,7 +1082,7 @@ func TestUpdateTemplateACL(t *testing.T) {
 
 		ctx := testutil.Context(t, testutil.WaitLong)
 
-		err := client.UpdateTemplateACL(ctx, template.ID, req)
+		_, err := client.UpdateTemplateACL(ctx, template.ID, req)
 		require.NoError(t, err)
 
 		req = codersdk.UpdateTemplateACL{
@@ -968,7 +1091,7 @@ func TestUpdateTemplateACL(t *testing.T) {
 			},
 		}
 
-		err = client2.UpdateTemplateACL(ctx, template.ID, req)
+		_, err = client2.UpdateTemplateACL(ctx, template.ID, req)
 		require.Error(t, err)
 		cerr, _ := codersdk.AsError(err)
 		require.Equal(t, http.StatusInternalServerError, cerr.StatusCode())
@@ -995,7 +1118,7 @@ func TestUpdateTemplateACL(t *testing.T) {
 
 		ctx := testutil.Context(t, testutil.WaitLong)
 
-		err := client.UpdateTemplateACL(ctx, template.ID, req)
+		_, err := client.UpdateTemplateACL(ctx, template.ID, req)
 		require.NoError(t, err)
 
 		// Should be able to see user 3
@@ -1015,7 +1138,7 @@ func TestUpdateTemplateACL(t *testing.T) {
 			},
 		}
 
-		err = client2.UpdateTemplateACL(ctx, template.ID, req)
+		_, err = client2.UpdateTemplateACL(ctx, template.ID, req)
 		require.NoError(t, err)
 
 		acl, err := client2.TemplateACL(ctx, template.ID)
@@ -1079,7 +1202,7 @@ func TestUpdateTemplateACL(t *testing.T) {
 		require.Len(t, acl.Groups, 1)
 
 		// Update the template to only allow access to the 'test' group.
-		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		_, err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 			GroupPerms: map[string]codersdk.TemplateRole{
 				// The allUsers group shares the same ID as the organization.
 				user.OrganizationID.String(): codersdk.TemplateRoleDeleted,
@@ -1145,7 +1268,7 @@ func TestUpdateTemplateACL(t *testing.T) {
 
 		allUsers := acl.Groups[0]
 
-		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		_, err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 			GroupPerms: map[string]codersdk.TemplateRole{
 				allUsers.ID.String(): codersdk.TemplateRoleDeleted,
 			},
@@ -1165,6 +1288,189 @@ func TestUpdateTemplateACL(t *testing.T) {
 		require.True(t, ok)
 		require.Equal(t, http.StatusNotFound, cerr.StatusCode())
 	})
+
+	t.Run("PushCannotUpdatePerms", func(t *testing.T) {
+		t.Parallel()
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{IncludeProvisionerDaemon: true},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+
+		client1, user1 := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_, err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			UserPerms: map[string]codersdk.TemplateRole{
+				user1.ID.String(): codersdk.TemplateRolePush,
+			},
+		})
+		require.NoError(t, err)
+
+		acl, err := client.TemplateACL(ctx, template.ID)
+		require.NoError(t, err)
+		require.Len(t, acl.Users, 1)
+		require.Equal(t, codersdk.TemplateRolePush, acl.Users[0].Role)
+
+		// Members with the push role can push and promote versions.
+		data, err := echo.Tar(nil)
+		require.NoError(t, err)
+		file, err := client1.Upload(ctx, codersdk.ContentTypeTar, bytes.NewReader(data))
+		require.NoError(t, err)
+		pushed, err := client1.CreateTemplateVersion(ctx, user.OrganizationID, codersdk.CreateTemplateVersionRequest{
+			TemplateID:    template.ID,
+			FileID:        file.ID,
+			StorageMethod: codersdk.ProvisionerStorageMethodFile,
+			Provisioner:   codersdk.ProvisionerTypeEcho,
+		})
+		require.NoError(t, err)
+		coderdtest.AwaitTemplateVersionJob(t, client1, pushed.ID)
+		err = client1.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+			ID: pushed.ID,
+		})
+		require.NoError(t, err)
+
+		// But cannot change the settings or the sharing of the template.
+		_, err = client1.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "pushed",
+		})
+		require.Error(t, err)
+		_, err = client1.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			UserPerms: map[string]codersdk.TemplateRole{
+				user1.ID.String(): codersdk.TemplateRoleAdmin,
+			},
+		})
+		require.Error(t, err)
+
+		_, err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			UserPerms: map[string]codersdk.TemplateRole{
+				user1.ID.String(): codersdk.TemplateRoleSettings,
+			},
+		})
+		require.NoError(t, err)
+
+		// Members with the settings role can change the settings, but still
+		// cannot change the sharing of the template.
+		_, err = client1.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+			Description: "settings",
+		})
+		require.NoError(t, err)
+		_, err = client1.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+			UserPerms: map[string]codersdk.TemplateRole{
+				user1.ID.String(): codersdk.TemplateRoleAdmin,
+			},
+		})
+		require.Error(t, err)
+	})
+}
+
+// TestTemplateRoleVersionActions checks the endpoints that act on template
+// versions and state against the template roles. Only roles that may push
+// versions may use them.
+func TestTemplateRoleVersionActions(t *testing.T) {
+	t.Parallel()
+
+	client, user := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{IncludeProvisionerDaemon: true},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureTemplateRBAC: 1,
+			},
+		},
+	})
+
+	oldVersion := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, oldVersion.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, oldVersion.ID)
+	newVersion := coderdtest.UpdateTemplateVersion(t, client, user.OrganizationID, nil, template.ID)
+	coderdtest.AwaitTemplateVersionJob(t, client, newVersion.ID)
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	err := client.UpdateActiveTemplateVersion(ctx, template.ID, codersdk.UpdateActiveTemplateVersion{
+		ID: newVersion.ID,
+	})
+	require.NoError(t, err)
+	_, err = client.UpdateTemplateMeta(ctx, template.ID, codersdk.UpdateTemplateMeta{
+		RequireActiveVersion:                  ptr.Ref(true),
+		RequireActiveVersionGracePeriodMillis: ptr.Ref(int64(0)),
+	})
+	require.NoError(t, err)
+
+	for _, c := range []struct {
+		role    codersdk.TemplateRole
+		allowed bool
+	}{
+		{role: codersdk.TemplateRoleUse, allowed: false},
+		{role: codersdk.TemplateRolePush, allowed: true},
+		{role: codersdk.TemplateRoleSettings, allowed: true},
+		{role: codersdk.TemplateRoleAdmin, allowed: true},
+	} {
+		c := c
+		t.Run(string(c.role), func(t *testing.T) {
+			t.Parallel()
+
+			member, memberUser := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+			ctx := testutil.Context(t, testutil.WaitLong)
+			_, err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+				UserPerms: map[string]codersdk.TemplateRole{
+					memberUser.ID.String(): c.role,
+				},
+			})
+			require.NoError(t, err)
+
+			requireAllowed := func(err error, deniedStatus int) {
+				t.Helper()
+				if c.allowed {
+					require.NoError(t, err)
+					return
+				}
+				var apiErr *codersdk.Error
+				require.ErrorAs(t, err, &apiErr)
+				require.Equal(t, deniedStatus, apiErr.StatusCode())
+			}
+
+			workspace := coderdtest.CreateWorkspace(t, member, user.OrganizationID, template.ID)
+			coderdtest.AwaitWorkspaceBuildJob(t, member, workspace.LatestBuild.ID)
+
+			// Pulling the state of a build.
+			_, err = member.WorkspaceBuildState(ctx, workspace.LatestBuild.ID)
+			requireAllowed(err, http.StatusNotFound)
+
+			// Pushing custom state.
+			workspace = coderdtest.MustTransitionWorkspace(t, member, workspace.ID, database.WorkspaceTransitionStart, database.WorkspaceTransitionStop)
+			build, err := member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+				Transition:       codersdk.WorkspaceTransitionStop,
+				ProvisionerState: []byte(" "),
+			})
+			requireAllowed(err, http.StatusForbidden)
+			if err == nil {
+				coderdtest.AwaitWorkspaceBuildJob(t, member, build.ID)
+			}
+
+			// Starting with an inactive version although the template requires
+			// the active one.
+			build, err = member.CreateWorkspaceBuild(ctx, workspace.ID, codersdk.CreateWorkspaceBuildRequest{
+				Transition:        codersdk.WorkspaceTransitionStart,
+				TemplateVersionID: oldVersion.ID,
+			})
+			requireAllowed(err, http.StatusForbidden)
+			if err == nil {
+				require.Equal(t, oldVersion.ID, build.TemplateVersionID)
+				coderdtest.AwaitWorkspaceBuildJob(t, member, build.ID)
+			}
+
+			// Notifying the owners of outdated workspaces.
+			_, err = member.NotifyOutdatedWorkspaces(ctx, template.ID)
+			requireAllowed(err, http.StatusForbidden)
+		})
+	}
 }
 
 func TestReadFileWithTemplateUpdate(t *testing.T) {
@@ -1201,7 +1507,7 @@ func TestReadFileWithTemplateUpdate(t *testing.T) {
 		_, _, err = member.Download(ctx, resp.ID)
 		require.Error(t, err, "not in acl yet")
 
-		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		_, err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 			UserPerms: map[string]codersdk.TemplateRole{
 				memberData.ID.String(): codersdk.TemplateRoleAdmin,
 			},
@@ -1271,7 +1577,7 @@ func TestTemplateAccess(t *testing.T) {
 		version := coderdtest.CreateTemplateVersion(t, client, orgID, nil)
 		template := coderdtest.CreateTemplate(t, client, orgID, version.ID)
 
-		err := client.UpdateTemplateACL(ctx, template.ID, acl)
+		_, err := client.UpdateTemplateACL(ctx, template.ID, acl)
 		require.NoError(t, err, "failed to update template acl")
 
 		return template
@@ -20,20 +20,7 @@ func (api *API) restartRequirementEnabledMW(next http.Handler) http.Handler {
 		}
 
 		// Entitlement must be enabled.
-		api.entitlementsMu.RLock()
-		entitled := api.entitlements.Features[codersdk.FeatureTemplateRestartRequirement].Entitlement != codersdk.EntitlementNotEntitled
-		enabled := api.entitlements.Features[codersdk.FeatureTemplateRestartRequirement].Enabled
-		api.entitlementsMu.RUnlock()
-		if !entitled {
-			httpapi.Write(r.Context(), rw, http.StatusForbidden, codersdk.Response{
-				Message: "Template restart requirement is an Enterprise feature. Contact sales!",
-			})
-			return
-		}
-		if !enabled {
-			httpapi.Write(r.Context(), rw, http.StatusForbidden, codersdk.Response{
-				Message: "Template restart requirement feature is not enabled. Please specify a default user quiet hours schedule to use this feature.",
-			})
+		if !api.featureAvailable(rw, r, codersdk.FeatureTemplateRestartRequirement) {
 			return
 		}
 
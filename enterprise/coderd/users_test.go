@@ -182,6 +182,12 @@ func TestUserQuietHours(t *testing.T) {
 		var sdkErr *codersdk.Error
 		require.ErrorAs(t, err, &sdkErr)
 		require.Equal(t, http.StatusForbidden, sdkErr.StatusCode())
+
+		feature, ok := codersdk.AsFeatureUnavailable(err)
+		require.True(t, ok)
+		require.Equal(t, codersdk.FeatureTemplateRestartRequirement, feature.Feature)
+		require.Equal(t, codersdk.EntitlementEntitled, feature.Entitlement)
+		require.False(t, feature.Enabled)
 	})
 
 	t.Run("NoFeatureFlag", func(t *testing.T) {
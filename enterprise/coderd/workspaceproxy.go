@@ -4,7 +4,6 @@ import (
 	"context"
 	"crypto/sha256"
 	"database/sql"
-	"flag"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -24,6 +23,7 @@ import (
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/coderd/rbac"
 	"github.com/coder/coder/v2/coderd/telemetry"
+	"github.com/coder/coder/v2/coderd/util/slice"
 	"github.com/coder/coder/v2/coderd/workspaceapps"
 	"github.com/coder/coder/v2/codersdk"
 	"github.com/coder/coder/v2/cryptorand"
@@ -43,16 +43,62 @@ func (api *API) forceWorkspaceProxyHealthUpdate(ctx context.Context) {
 // NOTE: this doesn't need a swagger definition since AGPL already has one, and
 // this route overrides the AGPL one.
 func (api *API) regions(rw http.ResponseWriter, r *http.Request) {
-	regions, err := api.fetchRegions(r.Context())
+	ctx := r.Context()
+	regions, err := api.fetchRegions(ctx)
 	if err != nil {
 		httpapi.InternalServerError(rw, err)
 		return
 	}
 
-	httpapi.Write(r.Context(), rw, http.StatusOK, regions)
+	regions.PreferredRegionIDs, err = api.preferredRegionIDs(ctx, httpmw.APIKey(r).UserID, clientAddr(r), regions.Regions)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, regions)
+}
+
+// NOTE: this doesn't need a swagger definition since AGPL already has one, and
+// this route overrides the AGPL one.
+func (api *API) rankRegions(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var req codersdk.RankRegionsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+	if err := agpl.ValidateRegionLatencies(req.Latencies); err != nil {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "Invalid region latencies.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	regions, err := api.fetchRegions(ctx)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	// Remember the latencies so the regions can be ranked for the user
+	// before the next probe completes.
+	err = api.storeRegionLatencies(r, regions.Regions, req.Latencies)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, codersdk.RegionsResponse[codersdk.RankedRegion]{
+		Regions:            agpl.RankRegions(regions.Regions, req.Latencies),
+		PreferredRegionIDs: preferRegions(regions.Regions, api.ProxyGeoRules, clientAddr(r), req.Latencies),
+	})
 }
 
 func (api *API) fetchRegions(ctx context.Context) (codersdk.RegionsResponse[codersdk.Region], error) {
+	// Proxies scoped to organizations or groups are only listed for their
+	// members.
+	actorCtx := ctx
 	//nolint:gocritic // this intentionally requests resources that users
 	// cannot usually access in order to give them a full list of available
 	// regions. Regions are just a data subset of proxies.
@@ -62,19 +108,70 @@ func (api *API) fetchRegions(ctx context.Context) (codersdk.RegionsResponse[code
 		return codersdk.RegionsResponse[codersdk.Region]{}, err
 	}
 
-	regions := make([]codersdk.Region, 0, len(proxies.Regions))
-	for i := range proxies.Regions {
+	available := make([]codersdk.WorkspaceProxy, 0, len(proxies.Regions))
+	for _, proxy := range proxies.Regions {
+		if proxyAvailable(actorCtx, proxy.OrganizationIDs, proxy.GroupIDs) {
+			available = append(available, proxy)
+		}
+	}
+	return codersdk.RegionsResponse[codersdk.Region]{
+		Regions: proxyRegions(available),
+	}, nil
+}
+
+// proxyAvailable returns whether the actor in the context may use a workspace
+// proxy limited to the given organizations and groups. Proxies without
+// organizations or groups are available to everyone. Every proxy is
+// available to system actors and contexts without an actor, so the primary
+// and other replicas keep a complete view.
+func proxyAvailable(ctx context.Context, organizationIDs, groupIDs []uuid.UUID) bool {
+	if len(organizationIDs) == 0 && len(groupIDs) == 0 {
+		return true
+	}
+	actor, ok := dbauthz.ActorFromContext(ctx)
+	if !ok || actor.ID == uuid.Nil.String() {
+		return true
+	}
+	roles := actor.SafeRoleNames()
+	for _, organizationID := range organizationIDs {
+		if slice.Contains(roles, rbac.RoleOrgMember(organizationID)) {
+			return true
+		}
+	}
+	for _, groupID := range groupIDs {
+		if slice.Contains(actor.Groups, groupID.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyRegions returns the regions of the proxies that serve workspace
+// connections.
+func proxyRegions(proxies []codersdk.WorkspaceProxy) []codersdk.Region {
+	regions := make([]codersdk.Region, 0, len(proxies))
+	for i := range proxies {
 		// Ignore deleted and DERP-only proxies.
-		if proxies.Regions[i].Deleted || proxies.Regions[i].DerpOnly {
+		if proxies[i].Deleted || proxies[i].DerpOnly {
 			continue
 		}
 		// Append the inner region data.
-		regions = append(regions, proxies.Regions[i].Region)
+		regions = append(regions, proxies[i].Region)
 	}
+	return regions
+}
 
-	return codersdk.RegionsResponse[codersdk.Region]{
-		Regions: regions,
-	}, nil
+// workspaceProxiesStatus counts the workspace proxies by their latest health
+// check for the deployment status.
+func (api *API) workspaceProxiesStatus() codersdk.DeploymentStatusProxies {
+	var status codersdk.DeploymentStatusProxies
+	for _, proxy := range api.ProxyHealth.HealthStatus() {
+		status.Total++
+		if proxy.Usable() {
+			status.Healthy++
+		}
+	}
+	return status
 }
 
 // @Summary Update workspace proxy
@@ -228,6 +325,108 @@ func (api *API) patchPrimaryWorkspaceProxy(req codersdk.PatchWorkspaceProxy, rw
 	return updatedProxy, true
 }
 
+// @Summary Update workspace proxy ACL
+// @Description Limits the workspace proxy to the members of the given
+// @Description organizations and groups. The proxy is only listed in their
+// @Description regions and its DERP region is only added to their DERP maps.
+// @Description Leave both lists empty to make the proxy available to everyone.
+// @ID update-workspace-proxy-acl
+// @Security CoderSessionToken
+// @Accept json
+// @Produce json
+// @Tags Enterprise
+// @Param workspaceproxy path string true "Proxy ID or name" format(uuid)
+// @Param request body codersdk.UpdateWorkspaceProxyACL true "Update workspace proxy ACL request"
+// @Success 200 {object} codersdk.WorkspaceProxy
+// @Router /workspaceproxies/{workspaceproxy}/acl [patch]
+func (api *API) patchWorkspaceProxyACL(rw http.ResponseWriter, r *http.Request) {
+	var (
+		ctx               = r.Context()
+		proxy             = httpmw.WorkspaceProxyParam(r)
+		auditor           = api.AGPL.Auditor.Load()
+		aReq, commitAudit = audit.InitRequest[database.WorkspaceProxy](rw, &audit.RequestParams{
+			Audit:   *auditor,
+			Log:     api.Logger,
+			Request: r,
+			Action:  database.AuditActionWrite,
+		})
+	)
+	aReq.Old = proxy
+	defer commitAudit()
+
+	var req codersdk.UpdateWorkspaceProxyACL
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	if proxy.IsPrimary() {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message: "The primary proxy is always available to everyone.",
+		})
+		return
+	}
+
+	validErrs := validateWorkspaceProxyACL(ctx, api.Database, req)
+	if len(validErrs) > 0 {
+		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+			Message:     "Invalid request to update workspace proxy ACL.",
+			Validations: validErrs,
+		})
+		return
+	}
+
+	if req.OrganizationIDs == nil {
+		req.OrganizationIDs = []uuid.UUID{}
+	}
+	if req.GroupIDs == nil {
+		req.GroupIDs = []uuid.UUID{}
+	}
+	updatedProxy, err := api.Database.UpdateWorkspaceProxyACL(ctx, database.UpdateWorkspaceProxyACLParams{
+		ID:              proxy.ID,
+		OrganizationIDs: req.OrganizationIDs,
+		GroupIDs:        req.GroupIDs,
+	})
+	if httpapi.Is404Error(err) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	aReq.New = updatedProxy
+	status, ok := api.ProxyHealth.HealthStatus()[updatedProxy.ID]
+	if !ok {
+		// The proxy should have some status, but just in case.
+		status.Status = proxyhealth.Unknown
+	}
+	httpapi.Write(ctx, rw, http.StatusOK, convertProxy(updatedProxy, status))
+
+	// Update the proxy cache so the DERP maps pick up the change.
+	go api.forceWorkspaceProxyHealthUpdate(api.ctx)
+}
+
+func validateWorkspaceProxyACL(ctx context.Context, db database.Store, req codersdk.UpdateWorkspaceProxyACL) []codersdk.ValidationError {
+	// Validate requires full read access to organizations and groups.
+	// nolint:gocritic
+	ctx = dbauthz.AsSystemRestricted(ctx)
+	var validErrs []codersdk.ValidationError
+	for _, id := range req.OrganizationIDs {
+		_, err := db.GetOrganizationByID(ctx, id)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "organization_ids", Detail: fmt.Sprintf("Failed to find organization with ID %q: %v", id, err.Error())})
+		}
+	}
+	for _, id := range req.GroupIDs {
+		_, err := db.GetGroupByID(ctx, id)
+		if err != nil {
+			validErrs = append(validErrs, codersdk.ValidationError{Field: "group_ids", Detail: fmt.Sprintf("Failed to find group with ID %q: %v", id, err.Error())})
+		}
+	}
+	return validErrs
+}
+
 // @Summary Delete workspace proxy
 // @ID delete-workspace-proxy
 // @Security CoderSessionToken
@@ -423,6 +622,12 @@ func (api *API) workspaceProxies(rw http.ResponseWriter, r *http.Request) {
 		httpapi.InternalServerError(rw, err)
 		return
 	}
+
+	proxies.PreferredRegionIDs, err = api.preferredRegionIDs(ctx, httpmw.APIKey(r).UserID, clientAddr(r), proxyRegions(proxies.Regions))
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
 	httpapi.Write(ctx, rw, http.StatusOK, proxies)
 }
 
@@ -457,6 +662,7 @@ func (api *API) fetchWorkspaceProxies(ctx context.Context) (codersdk.RegionsResp
 // @x-apidocgen {"skip": true}
 func (api *API) workspaceProxyIssueSignedAppToken(rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	proxy := httpmw.WorkspaceProxy(r)
 
 	// NOTE: this endpoint will return JSON on success, but will (usually)
 	// return a self-contained HTML error page on failure. The external proxy
@@ -492,11 +698,38 @@ func (api *API) workspaceProxyIssueSignedAppToken(rw http.ResponseWriter, r *htt
 		return
 	}
 
+	// Bind the token to the proxy and the app so it cannot be replayed against
+	// other proxies or against other apps.
+	token.Audience = proxy.ID.String()
+	scope := workspaceapps.NewTokenScope(token.Request, time.Now(), workspaceapps.ProxyTokenExpiry)
+	token.Scope = &scope
+	token.Expiry = time.Time{}
+	tokenStr, err = api.AGPL.AppSecurityKey.SignProxyToken(*token)
+	if err != nil {
+		httpapi.InternalServerError(rw, xerrors.Errorf("sign proxy token: %w", err))
+		return
+	}
+
 	httpapi.Write(ctx, rw, http.StatusCreated, wsproxysdk.IssueSignedAppTokenResponse{
 		SignedTokenStr: tokenStr,
 	})
 }
 
+// @Summary Get workspace proxy app token keys
+// @ID get-workspace-proxy-app-token-keys
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Success 200
+// @Router /workspaceproxies/me/jwks [get]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceProxyAppTokenKeys(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	_ = httpmw.WorkspaceProxy(r) // Ensure the proxy is authenticated.
+
+	httpapi.Write(ctx, rw, http.StatusOK, api.AGPL.AppSecurityKey.ProxyTokenKeys())
+}
+
 // @Summary Report workspace app stats
 // @ID report-workspace-app-stats
 // @Security CoderSessionToken
@@ -527,6 +760,38 @@ func (api *API) workspaceProxyReportAppStats(rw http.ResponseWriter, r *http.Req
 	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
 }
 
+// @Summary Report workspace access events
+// @ID report-workspace-access-events
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Enterprise
+// @Param request body wsproxysdk.ReportAccessEventsRequest true "Report access events request"
+// @Success 204
+// @Router /workspaceproxies/me/access-events [post]
+// @x-apidocgen {"skip": true}
+func (api *API) workspaceProxyReportAccessEvents(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	proxy := httpmw.WorkspaceProxy(r)
+
+	var req wsproxysdk.ReportAccessEventsRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	api.Logger.Debug(ctx, "report access events",
+		slog.F("proxy_id", proxy.ID),
+		slog.F("count", len(req.Events)),
+	)
+
+	if err := api.AGPL.AuditWorkspaceAccess(ctx, req.Events...); err != nil {
+		api.Logger.Error(ctx, "report access events failed", slog.Error(err))
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	httpapi.Write(ctx, rw, http.StatusNoContent, nil)
+}
+
 // workspaceProxyRegister is used to register a new workspace proxy. When a proxy
 // comes online, it will announce itself to this endpoint. This updates its values
 // in the database and returns a signed token that can be used to authenticate
@@ -570,15 +835,21 @@ func (api *API) workspaceProxyRegister(rw http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Version check should be forced in non-dev builds and when running in
-	// tests.
-	shouldForceVersion := !buildinfo.IsDev() || flag.Lookup("test.v") != nil
-	if shouldForceVersion && req.Version != buildinfo.Version() {
-		httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
-			Message: "Version mismatch.",
-			Detail:  fmt.Sprintf("Proxy version %q does not match primary server version %q", req.Version, buildinfo.Version()),
-		})
-		return
+	// Proxies with a mismatching version are only allowed to register if the
+	// policy is to warn about them. The health check reports the mismatch.
+	if proxyhealth.VersionMismatched(req.Version) {
+		if codersdk.ProxyVersionMismatchPolicy(api.DeploymentValues.ProxyVersionMismatchPolicy.Value()) != codersdk.ProxyVersionMismatchWarn {
+			httpapi.Write(ctx, rw, http.StatusBadRequest, codersdk.Response{
+				Message: "Version mismatch.",
+				Detail:  fmt.Sprintf("Proxy version %q does not match primary server version %q", req.Version, buildinfo.Version()),
+			})
+			return
+		}
+		api.Logger.Warn(ctx, "workspace proxy version does not match primary server version",
+			slog.F("proxy_id", proxy.ID),
+			slog.F("proxy_version", req.Version),
+			slog.F("primary_version", buildinfo.Version()),
+		)
 	}
 
 	if err := validateProxyURL(req.AccessURL); err != nil {
@@ -715,17 +986,120 @@ func (api *API) workspaceProxyRegister(rw http.ResponseWriter, r *http.Request)
 		siblingsRes = append(siblingsRes, convertReplica(replica))
 	}
 
+	config, err := api.workspaceProxyConfig(ctx)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
 	// aReq.New = updatedProxy
 	httpapi.Write(ctx, rw, http.StatusCreated, wsproxysdk.RegisterWorkspaceProxyResponse{
 		AppSecurityKey:  api.AppSecurityKey.String(),
 		DERPMeshKey:     api.DERPServer.MeshKey(),
 		DERPRegionID:    regionID,
 		SiblingReplicas: siblingsRes,
+		ProxyID:         proxy.ID,
+		Config:          config,
 	})
 
 	go api.forceWorkspaceProxyHealthUpdate(api.ctx)
 }
 
+// workspaceProxyConfigChannel is notified when the configuration pushed to
+// workspace proxies may have changed.
+const workspaceProxyConfigChannel = "workspace_proxy_config"
+
+// workspaceProxyConfig returns the settings of the primary that workspace
+// proxies apply.
+func (api *API) workspaceProxyConfig(ctx context.Context) (wsproxysdk.ProxyConfig, error) {
+	appearance, err := api.fetchAppearanceConfig(ctx)
+	if err != nil {
+		return wsproxysdk.ProxyConfig{}, xerrors.Errorf("fetch appearance config: %w", err)
+	}
+	return wsproxysdk.ProxyConfig{
+		DisablePathApps: api.DeploymentValues.DisablePathApps.Value(),
+		SessionDuration: api.DeploymentValues.SessionDuration.Value(),
+		Appearance:      appearance,
+	}, nil
+}
+
+// publishWorkspaceProxyConfig pushes the current configuration to the
+// workspace proxies watching it on all replicas.
+func (api *API) publishWorkspaceProxyConfig(ctx context.Context) {
+	err := api.Pubsub.Publish(workspaceProxyConfigChannel, []byte{})
+	if err != nil {
+		api.Logger.Warn(ctx, "publish workspace proxy config update", slog.Error(err))
+	}
+}
+
+// watchWorkspaceProxyConfig streams the configuration to the proxy, so
+// changes apply without waiting for the proxy to register again.
+//
+// @Summary Watch workspace proxy config
+// @ID watch-workspace-proxy-config
+// @Security CoderSessionToken
+// @Produce text/event-stream
+// @Tags Enterprise
+// @Success 200 {object} wsproxysdk.ProxyConfig
+// @Router /workspaceproxies/me/config/watch [get]
+// @x-apidocgen {"skip": true}
+func (api *API) watchWorkspaceProxyConfig(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sendEvent, senderClosed, err := httpapi.ServerSentEventSender(rw, r)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Internal error setting up server-sent events.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	// Prevent handler from returning until the sender is closed.
+	defer func() {
+		<-senderClosed
+	}()
+
+	sendUpdate := func(_ context.Context, _ []byte) {
+		config, err := api.workspaceProxyConfig(ctx)
+		if err != nil {
+			_ = sendEvent(ctx, codersdk.ServerSentEvent{
+				Type: codersdk.ServerSentEventTypeError,
+				Data: codersdk.Response{
+					Message: "Internal error fetching workspace proxy config.",
+					Detail:  err.Error(),
+				},
+			})
+			return
+		}
+		_ = sendEvent(ctx, codersdk.ServerSentEvent{
+			Type: codersdk.ServerSentEventTypeData,
+			Data: config,
+		})
+	}
+
+	cancelSubscribe, err := api.Pubsub.Subscribe(workspaceProxyConfigChannel, sendUpdate)
+	if err != nil {
+		_ = sendEvent(ctx, codersdk.ServerSentEvent{
+			Type: codersdk.ServerSentEventTypeError,
+			Data: codersdk.Response{
+				Message: "Internal error subscribing to workspace proxy config events.",
+				Detail:  err.Error(),
+			},
+		})
+		return
+	}
+	defer cancelSubscribe()
+
+	// Send the current config, since it may have changed since the proxy
+	// registered.
+	sendUpdate(ctx, nil)
+
+	select {
+	case <-ctx.Done():
+	case <-senderClosed:
+	}
+}
+
 // @Summary Deregister workspace proxy
 // @ID deregister-workspace-proxy
 // @Security CoderSessionToken
@@ -870,7 +1244,21 @@ func (api *API) reconnectingPTYSignedToken(rw http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	_, tokenStr, ok := api.AGPL.WorkspaceAppsProvider.Issue(ctx, rw, r, workspaceapps.IssueTokenRequest{
+	// The token is only valid on the proxy serving the URL.
+	//nolint:gocritic // System needs to be able to look up proxies.
+	proxy, err := api.Database.GetWorkspaceProxyByHostname(dbauthz.AsSystemRestricted(ctx), database.GetWorkspaceProxyByHostnameParams{
+		Hostname:       u.Hostname(),
+		AllowAccessUrl: true,
+	})
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get workspace proxy for URL.",
+			Detail:  err.Error(),
+		})
+		return
+	}
+
+	token, _, ok := api.AGPL.WorkspaceAppsProvider.Issue(ctx, rw, r, workspaceapps.IssueTokenRequest{
 		AppRequest: workspaceapps.Request{
 			AccessMethod:  workspaceapps.AccessMethodTerminal,
 			BasePath:      u.Path,
@@ -890,6 +1278,14 @@ func (api *API) reconnectingPTYSignedToken(rw http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	token.Audience = proxy.ID.String()
+	token.Expiry = time.Time{}
+	tokenStr, err := api.AGPL.AppSecurityKey.SignProxyToken(*token)
+	if err != nil {
+		httpapi.InternalServerError(rw, xerrors.Errorf("sign proxy token: %w", err))
+		return
+	}
+
 	httpapi.Write(ctx, rw, http.StatusOK, codersdk.IssueReconnectingPTYSignedTokenResponse{
 		SignedToken: tokenStr,
 	})
@@ -919,9 +1315,10 @@ func convertRegion(proxy database.WorkspaceProxy, status proxyhealth.ProxyStatus
 		Name:             proxy.Name,
 		DisplayName:      proxy.DisplayName,
 		IconURL:          proxy.Icon,
-		Healthy:          status.Status == proxyhealth.Healthy,
+		Healthy:          status.Usable(),
 		PathAppURL:       proxy.Url,
 		WildcardHostname: proxy.WildcardHostname,
+		HealthLatencyMS:  float64(status.Latency) / float64(time.Millisecond),
 	}
 }
 
@@ -941,17 +1338,26 @@ func convertProxy(p database.WorkspaceProxy, status proxyhealth.ProxyStatus) cod
 	if status.Status == "" {
 		status.Status = proxyhealth.Unknown
 	}
+	var derpStatus codersdk.ProxyHealthStatus
+	if status.DERPStatus != "" && status.DERPStatus != proxyhealth.Unknown {
+		derpStatus = codersdk.ProxyHealthStatus(status.DERPStatus)
+	}
 	return codersdk.WorkspaceProxy{
-		Region:      convertRegion(p, status),
-		DerpEnabled: p.DerpEnabled,
-		DerpOnly:    p.DerpOnly,
-		CreatedAt:   p.CreatedAt,
-		UpdatedAt:   p.UpdatedAt,
-		Deleted:     p.Deleted,
+		Region:          convertRegion(p, status),
+		DerpEnabled:     p.DerpEnabled,
+		DerpOnly:        p.DerpOnly,
+		OrganizationIDs: p.OrganizationIDs,
+		GroupIDs:        p.GroupIDs,
+		CreatedAt:       p.CreatedAt,
+		UpdatedAt:       p.UpdatedAt,
+		Deleted:         p.Deleted,
 		Status: codersdk.WorkspaceProxyStatus{
-			Status:    codersdk.ProxyHealthStatus(status.Status),
-			Report:    status.Report,
-			CheckedAt: status.CheckedAt,
+			Status:     codersdk.ProxyHealthStatus(status.Status),
+			Report:     status.Report,
+			DERPStatus: derpStatus,
+			DERPError:  status.DERPError,
+			Version:    status.Version,
+			CheckedAt:  status.CheckedAt,
 		},
 	}
 }
@@ -13,11 +13,13 @@ import (
 	"github.com/moby/moby/pkg/namesgenerator"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"tailscale.com/tailcfg"
 
 	"cdr.dev/slog"
 	"cdr.dev/slog/sloggers/slogtest"
 	"github.com/coder/coder/v2/agent"
 	"github.com/coder/coder/v2/buildinfo"
+	"github.com/coder/coder/v2/cli/clibase"
 	"github.com/coder/coder/v2/coderd/coderdtest"
 	"github.com/coder/coder/v2/coderd/database"
 	"github.com/coder/coder/v2/coderd/database/dbtestutil"
@@ -145,6 +147,296 @@ func TestRegions(t *testing.T) {
 		require.True(t, regions[1].Healthy)
 		require.Equal(t, proxy.Url, regions[1].PathAppURL)
 		require.Equal(t, proxy.WildcardHostname, regions[1].WildcardHostname)
+		require.Positive(t, regions[1].HealthLatencyMS)
+
+		// The proxy is counted in the deployment status.
+		status, err := codersdk.New(client.URL).DeploymentStatus(ctx)
+		require.NoError(t, err)
+		require.True(t, status.Healthy)
+		require.Equal(t, codersdk.DeploymentStatusProxies{Total: 1, Healthy: 1}, status.Proxies)
+
+		// Without reported latencies, the primary is preferred as it has
+		// no health check latency.
+		preferred, err := client.PreferredRegions(ctx)
+		require.NoError(t, err)
+		require.Len(t, preferred, 2)
+		require.Equal(t, regions[0].ID, preferred[0].ID)
+
+		// The proxy is ranked first when the client reports it as faster.
+		ranked, err := client.RankRegions(ctx, codersdk.RankRegionsRequest{
+			Latencies: []codersdk.RegionLatency{
+				{RegionID: regions[0].ID, LatencyMS: 120},
+				{RegionID: regions[1].ID, LatencyMS: 15},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, ranked, 2)
+		require.Equal(t, proxy.ID, ranked[0].ID)
+		require.NotNil(t, ranked[0].ClientLatencyMS)
+		require.EqualValues(t, 15, *ranked[0].ClientLatencyMS)
+		require.Equal(t, regions[0].ID, ranked[1].ID)
+
+		// The reported latencies are remembered for the user.
+		preferred, err = client.PreferredRegions(ctx)
+		require.NoError(t, err)
+		require.Len(t, preferred, 2)
+		require.Equal(t, proxy.ID, preferred[0].ID)
+		require.Equal(t, regions[0].ID, preferred[1].ID)
+
+		_, err = client.RankRegions(ctx, codersdk.RankRegionsRequest{
+			Latencies: []codersdk.RegionLatency{{RegionID: regions[1].ID, LatencyMS: -1}},
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+
+	t.Run("ACL", func(t *testing.T) {
+		t.Parallel()
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.Experiments = []string{
+			string(codersdk.ExperimentMoons),
+			"*",
+		}
+
+		db, pubsub := dbtestutil.NewDB(t)
+
+		client, closer, api, user := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				AppHostname:      appHostname,
+				Database:         db,
+				Pubsub:           pubsub,
+				DeploymentValues: dv,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureWorkspaceProxy: 1,
+					codersdk.FeatureTemplateRBAC:   1,
+				},
+			},
+		})
+		t.Cleanup(func() {
+			_ = closer.Close()
+		})
+		member, memberUser := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		const proxyName = "scoped"
+		_ = coderdenttest.NewWorkspaceProxy(t, api, client, &coderdenttest.ProxyOptions{
+			Name:        proxyName,
+			AppHostname: appHostname + ".proxy",
+		})
+		proxy, err := db.GetWorkspaceProxyByName(ctx, proxyName)
+		require.NoError(t, err)
+
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "proxy-users",
+		})
+		require.NoError(t, err)
+
+		updated, err := client.UpdateWorkspaceProxyACL(ctx, proxy.ID, codersdk.UpdateWorkspaceProxyACL{
+			GroupIDs: []uuid.UUID{group.ID},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []uuid.UUID{group.ID}, updated.GroupIDs)
+		require.Empty(t, updated.OrganizationIDs)
+
+		err = api.ProxyHealth.ForceUpdate(ctx)
+		require.NoError(t, err)
+
+		// The proxy is hidden from users outside of the group.
+		regions, err := member.Regions(ctx)
+		require.NoError(t, err)
+		require.Len(t, regions, 1)
+		require.Equal(t, "primary", regions[0].Name)
+
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{memberUser.ID.String()},
+		})
+		require.NoError(t, err)
+
+		regions, err = member.Regions(ctx)
+		require.NoError(t, err)
+		require.Len(t, regions, 2)
+		require.Equal(t, proxy.ID, regions[1].ID)
+
+		// Organization members may use proxies scoped to their organization.
+		_, err = client.UpdateWorkspaceProxyACL(ctx, proxy.ID, codersdk.UpdateWorkspaceProxyACL{
+			OrganizationIDs: []uuid.UUID{user.OrganizationID},
+		})
+		require.NoError(t, err)
+		regions, err = client.Regions(ctx)
+		require.NoError(t, err)
+		require.Len(t, regions, 2)
+
+		// Unknown groups are rejected.
+		_, err = client.UpdateWorkspaceProxyACL(ctx, proxy.ID, codersdk.UpdateWorkspaceProxyACL{
+			GroupIDs: []uuid.UUID{uuid.New()},
+		})
+		var sdkErr *codersdk.Error
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+
+		// The primary is always available to everyone.
+		deploymentID, err := db.GetDeploymentID(ctx)
+		require.NoError(t, err)
+		_, err = client.UpdateWorkspaceProxyACL(ctx, uuid.MustParse(deploymentID), codersdk.UpdateWorkspaceProxyACL{
+			GroupIDs: []uuid.UUID{group.ID},
+		})
+		require.ErrorAs(t, err, &sdkErr)
+		require.Equal(t, http.StatusBadRequest, sdkErr.StatusCode())
+	})
+
+	t.Run("AgentDERPMap", func(t *testing.T) {
+		t.Parallel()
+
+		dv := coderdtest.DeploymentValues(t)
+		dv.Experiments = []string{
+			string(codersdk.ExperimentMoons),
+			"*",
+		}
+
+		client, closer, api, user := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				AppHostname:              appHostname,
+				DeploymentValues:         dv,
+				IncludeProvisionerDaemon: true,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureWorkspaceProxy: 1,
+					codersdk.FeatureTemplateRBAC:   1,
+				},
+			},
+		})
+		t.Cleanup(func() {
+			_ = closer.Close()
+		})
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		const proxyName = "scoped"
+		_ = coderdenttest.NewWorkspaceProxy(t, api, client, &coderdenttest.ProxyOptions{
+			Name:        proxyName,
+			AppHostname: appHostname + ".proxy",
+		})
+		proxy, err := client.WorkspaceProxyByName(ctx, proxyName)
+		require.NoError(t, err)
+
+		// Only the admin may use the proxy, the workspace owner may not.
+		group, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name: "proxy-users",
+		})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+			AddUsers: []string{user.UserID.String()},
+		})
+		require.NoError(t, err)
+		_, err = client.UpdateWorkspaceProxyACL(ctx, proxy.ID, codersdk.UpdateWorkspaceProxyACL{
+			GroupIDs: []uuid.UUID{group.ID},
+		})
+		require.NoError(t, err)
+		err = api.ProxyHealth.ForceUpdate(ctx)
+		require.NoError(t, err)
+
+		authToken := uuid.NewString()
+		version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, &echo.Responses{
+			Parse:          echo.ParseComplete,
+			ProvisionPlan:  echo.ProvisionComplete,
+			ProvisionApply: echo.ProvisionApplyWithAgent(authToken),
+		})
+		coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+		template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+		workspace := coderdtest.CreateWorkspace(t, member, user.OrganizationID, template.ID)
+		build := coderdtest.AwaitWorkspaceBuildJob(t, member, workspace.LatestBuild.ID)
+		agentID := build.Resources[0].Agents[0].ID
+
+		hasProxyRegion := func(derpMap *tailcfg.DERPMap) bool {
+			for _, region := range derpMap.Regions {
+				if region.RegionCode == "coder_"+proxyName {
+					return true
+				}
+			}
+			return false
+		}
+
+		// The owner's DERP map leaves out the proxy.
+		connInfo, err := member.WorkspaceAgentConnectionInfo(ctx, agentID)
+		require.NoError(t, err)
+		require.False(t, hasProxyRegion(connInfo.DERPMap))
+
+		// The admin connects through the proxy.
+		connInfo, err = client.WorkspaceAgentConnectionInfo(ctx, agentID)
+		require.NoError(t, err)
+		require.True(t, hasProxyRegion(connInfo.DERPMap))
+
+		// The agent must be reachable through every region a client may use,
+		// so it gets the proxy even though its owner can't use it.
+		agentClient := agentsdk.New(client.URL)
+		agentClient.SetSessionToken(authToken)
+		manifest, err := agentClient.Manifest(ctx)
+		require.NoError(t, err)
+		require.True(t, hasProxyRegion(manifest.DERPMap))
+	})
+
+	t.Run("GeoRules", func(t *testing.T) {
+		t.Parallel()
+
+		const proxyName = "local"
+		dv := coderdtest.DeploymentValues(t)
+		dv.Experiments = []string{
+			string(codersdk.ExperimentMoons),
+			"*",
+		}
+		// Test clients connect from the loopback network.
+		err := dv.ProxyGeoRules.Set("10.0.0.0/8=elsewhere,127.0.0.0/8=" + proxyName)
+		require.NoError(t, err)
+
+		client, closer, api, _ := coderdenttest.NewWithAPI(t, &coderdenttest.Options{
+			Options: &coderdtest.Options{
+				AppHostname:      appHostname,
+				DeploymentValues: dv,
+			},
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureWorkspaceProxy: 1,
+				},
+			},
+		})
+		t.Cleanup(func() {
+			_ = closer.Close()
+		})
+		ctx := testutil.Context(t, testutil.WaitLong)
+
+		_ = coderdenttest.NewWorkspaceProxy(t, api, client, &coderdenttest.ProxyOptions{
+			Name:        proxyName,
+			AppHostname: appHostname + ".proxy",
+		})
+		proxy, err := client.WorkspaceProxyByName(ctx, proxyName)
+		require.NoError(t, err)
+		err = api.ProxyHealth.ForceUpdate(ctx)
+		require.NoError(t, err)
+
+		// The proxy matched by the geo rules is preferred even though the
+		// user reported a lower latency for the primary.
+		_, err = client.RankRegions(ctx, codersdk.RankRegionsRequest{
+			Latencies: []codersdk.RegionLatency{
+				{RegionID: proxy.ID, LatencyMS: 200},
+			},
+		})
+		require.NoError(t, err)
+		preferred, err := client.PreferredRegions(ctx)
+		require.NoError(t, err)
+		require.Len(t, preferred, 2)
+		require.Equal(t, proxyName, preferred[0].Name)
+		require.Equal(t, "primary", preferred[1].Name)
+
+		// Administrators receive the same preference with the proxies.
+		proxies, err := client.WorkspaceProxies(ctx)
+		require.NoError(t, err)
+		require.Len(t, proxies.PreferredRegionIDs, 2)
+		require.Equal(t, preferred[0].ID, proxies.PreferredRegionIDs[0])
 	})
 
 	t.Run("RequireAuth", func(t *testing.T) {
@@ -263,12 +555,15 @@ func TestWorkspaceProxyCRUD(t *testing.T) {
 func TestProxyRegisterDeregister(t *testing.T) {
 	t.Parallel()
 
-	setup := func(t *testing.T) (*codersdk.Client, database.Store) {
+	setup := func(t *testing.T, mutators ...func(dv *codersdk.DeploymentValues)) (*codersdk.Client, database.Store) {
 		dv := coderdtest.DeploymentValues(t)
 		dv.Experiments = []string{
 			string(codersdk.ExperimentMoons),
 			"*",
 		}
+		for _, mut := range mutators {
+			mut(dv)
+		}
 
 		db, pubsub := dbtestutil.NewDB(t)
 		client, _ := coderdenttest.New(t, &coderdenttest.Options{
@@ -443,6 +738,35 @@ func TestProxyRegisterDeregister(t *testing.T) {
 		require.Contains(t, sdkErr.Response.Message, "Version mismatch")
 	})
 
+	t.Run("WarnMismatchingVersion", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := setup(t, func(dv *codersdk.DeploymentValues) {
+			dv.ProxyVersionMismatchPolicy = clibase.String(codersdk.ProxyVersionMismatchWarn)
+		})
+
+		ctx := testutil.Context(t, testutil.WaitLong)
+		createRes, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+			Name: "hi",
+		})
+		require.NoError(t, err)
+
+		proxyClient := wsproxysdk.New(client.URL)
+		proxyClient.SetSessionToken(createRes.ProxyToken)
+
+		_, err = proxyClient.RegisterWorkspaceProxy(ctx, wsproxysdk.RegisterWorkspaceProxyRequest{
+			AccessURL:           "https://proxy.coder.test",
+			WildcardHostname:    "*.proxy.coder.test",
+			DerpEnabled:         true,
+			ReplicaID:           uuid.New(),
+			ReplicaHostname:     "mars",
+			ReplicaError:        "",
+			ReplicaRelayAddress: "http://127.0.0.1:8080",
+			Version:             "v0.0.0",
+		})
+		require.NoError(t, err)
+	})
+
 	t.Run("ReregisterUpdateReplica", func(t *testing.T) {
 		t.Parallel()
 
@@ -640,6 +964,71 @@ func TestProxyRegisterDeregister(t *testing.T) {
 	})
 }
 
+func TestWorkspaceProxyConfigPush(t *testing.T) {
+	t.Parallel()
+
+	dv := coderdtest.DeploymentValues(t)
+	dv.Experiments = []string{
+		string(codersdk.ExperimentMoons),
+		"*",
+	}
+	dv.DisablePathApps = true
+	client, _ := coderdenttest.New(t, &coderdenttest.Options{
+		Options: &coderdtest.Options{
+			DeploymentValues: dv,
+		},
+		LicenseOptions: &coderdenttest.LicenseOptions{
+			Features: license.Features{
+				codersdk.FeatureWorkspaceProxy: 1,
+				codersdk.FeatureAppearance:     1,
+			},
+		},
+	})
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	createRes, err := client.CreateWorkspaceProxy(ctx, codersdk.CreateWorkspaceProxyRequest{
+		Name: "hello",
+	})
+	require.NoError(t, err)
+	proxyClient := wsproxysdk.New(client.URL)
+	_ = proxyClient.SetSessionToken(createRes.ProxyToken)
+
+	registerRes, err := proxyClient.RegisterWorkspaceProxy(ctx, wsproxysdk.RegisterWorkspaceProxyRequest{
+		AccessURL:           "https://proxy.coder.test",
+		WildcardHostname:    "*.proxy.coder.test",
+		DerpEnabled:         true,
+		ReplicaID:           uuid.New(),
+		ReplicaHostname:     "mars",
+		ReplicaRelayAddress: "http://127.0.0.1:8080",
+		Version:             buildinfo.Version(),
+	})
+	require.NoError(t, err)
+	require.True(t, registerRes.Config.DisablePathApps)
+	require.Equal(t, dv.SessionDuration.Value(), registerRes.Config.SessionDuration)
+
+	configs, err := proxyClient.WatchWorkspaceProxyConfig(ctx)
+	require.NoError(t, err)
+	var config wsproxysdk.ProxyConfig
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for initial config")
+	case config = <-configs:
+	}
+	require.Empty(t, config.Appearance.LogoURL)
+
+	err = client.UpdateAppearance(ctx, codersdk.UpdateAppearanceConfig{
+		LogoURL: "https://example.com/logo.png",
+	})
+	require.NoError(t, err)
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for pushed config")
+	case config = <-configs:
+	}
+	require.Equal(t, "https://example.com/logo.png", config.Appearance.LogoURL)
+	require.True(t, config.DisablePathApps)
+}
+
 func TestIssueSignedAppToken(t *testing.T) {
 	t.Parallel()
 
@@ -724,8 +1113,17 @@ func TestIssueSignedAppToken(t *testing.T) {
 		proxyClient.SetSessionToken(proxyRes.ProxyToken)
 
 		ctx := testutil.Context(t, testutil.WaitLong)
-		_, err := proxyClient.IssueSignedAppToken(ctx, goodRequest)
+		res, err := proxyClient.IssueSignedAppToken(ctx, goodRequest)
 		require.NoError(t, err)
+
+		// The token is bound to the proxy that requested it.
+		keys, err := proxyClient.AppTokenKeys(ctx)
+		require.NoError(t, err)
+		token, err := workspaceapps.VerifyProxyToken(keys, proxyRes.Proxy.ID.String(), res.SignedTokenStr)
+		require.NoError(t, err)
+		require.Equal(t, proxyRes.Proxy.ID.String(), token.Audience)
+		_, err = workspaceapps.VerifyProxyToken(keys, uuid.NewString(), res.SignedTokenStr)
+		require.Error(t, err)
 	})
 
 	t.Run("OKHTML", func(t *testing.T) {
@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 
@@ -18,6 +19,85 @@ import (
 	"github.com/coder/coder/v2/provisionerd/proto"
 )
 
+// quotaBudget is the quota budget of a user, resolved from the budgets that
+// apply to them.
+type quotaBudget struct {
+	budget  int64
+	budgets []codersdk.WorkspaceQuotaBudget
+}
+
+// resolveQuotaBudget resolves the quota budget of a user. Budgets form a
+// hierarchy, from most to least specific:
+//   - A user override replaces everything else.
+//   - Otherwise, the largest override of the user's groups applies.
+//   - Otherwise, the allowances of the user's groups are added to the
+//     organization default, which is the allowance of the Everyone group.
+func resolveQuotaBudget(ctx context.Context, db database.Store, userID uuid.UUID) (quotaBudget, error) {
+	var resolved quotaBudget
+
+	userOverride, err := db.GetUserQuotaOverride(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return quotaBudget{}, xerrors.Errorf("get user quota override: %w", err)
+	}
+	hasUserOverride := err == nil
+	if hasUserOverride {
+		resolved.budget = int64(userOverride.QuotaAllowance)
+		resolved.budgets = append(resolved.budgets, codersdk.WorkspaceQuotaBudget{
+			Source:    codersdk.WorkspaceQuotaBudgetSourceUserOverride,
+			Allowance: int(userOverride.QuotaAllowance),
+			Applied:   true,
+		})
+	}
+
+	groups, err := db.GetQuotaBudgetsForUser(ctx, userID)
+	if err != nil {
+		return quotaBudget{}, xerrors.Errorf("get quota budgets: %w", err)
+	}
+
+	groupOverride := -1
+	var sum int64
+	for _, group := range groups {
+		group := group
+		source := codersdk.WorkspaceQuotaBudgetSourceGroup
+		if group.ID == group.OrganizationID {
+			source = codersdk.WorkspaceQuotaBudgetSourceOrganizationDefault
+		}
+		resolved.budgets = append(resolved.budgets, codersdk.WorkspaceQuotaBudget{
+			Source:    source,
+			GroupID:   &group.ID,
+			GroupName: group.Name,
+			Allowance: int(group.QuotaAllowance),
+		})
+		sum += int64(group.QuotaAllowance)
+
+		if !group.QuotaOverride.Valid {
+			continue
+		}
+		resolved.budgets = append(resolved.budgets, codersdk.WorkspaceQuotaBudget{
+			Source:    codersdk.WorkspaceQuotaBudgetSourceGroupOverride,
+			GroupID:   &group.ID,
+			GroupName: group.Name,
+			Allowance: int(group.QuotaOverride.Int32),
+		})
+		if groupOverride < 0 || int(group.QuotaOverride.Int32) > resolved.budgets[groupOverride].Allowance {
+			groupOverride = len(resolved.budgets) - 1
+		}
+	}
+
+	switch {
+	case hasUserOverride:
+	case groupOverride >= 0:
+		resolved.budgets[groupOverride].Applied = true
+		resolved.budget = int64(resolved.budgets[groupOverride].Allowance)
+	default:
+		for i := range resolved.budgets {
+			resolved.budgets[i].Applied = true
+		}
+		resolved.budget = sum
+	}
+	return resolved, nil
+}
+
 type committer struct {
 	Log      slog.Logger
 	Database database.Store
@@ -53,10 +133,11 @@ func (c *committer) CommitQuota(
 			return err
 		}
 
-		budget, err = s.GetQuotaAllowanceForUser(ctx, workspace.OwnerID)
+		resolved, err := resolveQuotaBudget(ctx, s, workspace.OwnerID)
 		if err != nil {
 			return err
 		}
+		budget = resolved.budget
 
 		// If the new build will reduce overall quota consumption, then we
 		// allow it even if the user is over quota.
@@ -135,8 +216,7 @@ func (api *API) workspaceQuota(rw http.ResponseWriter, r *http.Request) {
 	// There are no groups and thus no allowance if RBAC isn't licensed.
 	var quotaAllowance int64 = -1
 	if licensed {
-		var err error
-		quotaAllowance, err = api.Database.GetQuotaAllowanceForUser(r.Context(), user.ID)
+		resolved, err := resolveQuotaBudget(r.Context(), api.Database, user.ID)
 		if err != nil {
 			httpapi.Write(r.Context(), rw, http.StatusInternalServerError, codersdk.Response{
 				Message: "Failed to get allowance",
@@ -144,6 +224,7 @@ func (api *API) workspaceQuota(rw http.ResponseWriter, r *http.Request) {
 			})
 			return
 		}
+		quotaAllowance = resolved.budget
 	}
 
 	quotaConsumed, err := api.Database.GetQuotaConsumedForUser(r.Context(), user.ID)
@@ -160,3 +241,125 @@ func (api *API) workspaceQuota(rw http.ResponseWriter, r *http.Request) {
 		Budget:          int(quotaAllowance),
 	})
 }
+
+// @Summary Get workspace quota breakdown by user
+// @ID get-workspace-quota-breakdown-by-user
+// @Security CoderSessionToken
+// @Produce json
+// @Tags Enterprise
+// @Param user path string true "User ID, name, or me"
+// @Success 200 {object} codersdk.WorkspaceQuotaBreakdown
+// @Router /workspace-quota/{user}/breakdown [get]
+func (api *API) workspaceQuotaBreakdown(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	if !api.AGPL.Authorize(r, rbac.ActionRead, user) {
+		httpapi.ResourceNotFound(rw)
+		return
+	}
+
+	api.entitlementsMu.RLock()
+	licensed := api.entitlements.Features[codersdk.FeatureTemplateRBAC].Enabled
+	api.entitlementsMu.RUnlock()
+
+	breakdown := codersdk.WorkspaceQuotaBreakdown{
+		Budget:     -1,
+		Budgets:    []codersdk.WorkspaceQuotaBudget{},
+		Workspaces: []codersdk.WorkspaceQuotaConsumer{},
+	}
+	if licensed {
+		resolved, err := resolveQuotaBudget(ctx, api.Database, user.ID)
+		if err != nil {
+			httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+				Message: "Failed to get allowance",
+				Detail:  err.Error(),
+			})
+			return
+		}
+		breakdown.Budget = int(resolved.budget)
+		if resolved.budgets != nil {
+			breakdown.Budgets = resolved.budgets
+		}
+	}
+
+	workspaces, err := api.Database.GetQuotaConsumedWorkspacesForUser(ctx, user.ID)
+	if err != nil {
+		httpapi.Write(ctx, rw, http.StatusInternalServerError, codersdk.Response{
+			Message: "Failed to get consumed",
+			Detail:  err.Error(),
+		})
+		return
+	}
+	for _, workspace := range workspaces {
+		breakdown.CreditsConsumed += int(workspace.DailyCost)
+		breakdown.Workspaces = append(breakdown.Workspaces, codersdk.WorkspaceQuotaConsumer{
+			WorkspaceID:   workspace.WorkspaceID,
+			WorkspaceName: workspace.WorkspaceName,
+			DailyCost:     int(workspace.DailyCost),
+		})
+	}
+
+	httpapi.Write(ctx, rw, http.StatusOK, breakdown)
+}
+
+// @Summary Update workspace quota override by user
+// @ID update-workspace-quota-override-by-user
+// @Security CoderSessionToken
+// @Accept json
+// @Tags Enterprise
+// @Param user path string true "User ID, name, or me"
+// @Param request body codersdk.UpdateWorkspaceQuotaOverrideRequest true "Update workspace quota override request"
+// @Success 204
+// @Router /workspace-quota/{user}/override [put]
+func (api *API) putWorkspaceQuotaOverride(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	if !api.AGPL.Authorize(r, rbac.ActionUpdate, user) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	var req codersdk.UpdateWorkspaceQuotaOverrideRequest
+	if !httpapi.Read(ctx, rw, r, &req) {
+		return
+	}
+
+	_, err := api.Database.UpsertUserQuotaOverride(ctx, database.UpsertUserQuotaOverrideParams{
+		UserID:         user.ID,
+		QuotaAllowance: int32(req.QuotaAllowance),
+		CreatedAt:      database.Now(),
+	})
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Delete workspace quota override by user
+// @ID delete-workspace-quota-override-by-user
+// @Security CoderSessionToken
+// @Tags Enterprise
+// @Param user path string true "User ID, name, or me"
+// @Success 204
+// @Router /workspace-quota/{user}/override [delete]
+func (api *API) deleteWorkspaceQuotaOverride(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := httpmw.UserParam(r)
+
+	if !api.AGPL.Authorize(r, rbac.ActionUpdate, user) {
+		httpapi.Forbidden(rw)
+		return
+	}
+
+	err := api.Database.DeleteUserQuotaOverride(ctx, user.ID)
+	if err != nil {
+		httpapi.InternalServerError(rw, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
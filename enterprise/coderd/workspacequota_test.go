@@ -2,6 +2,7 @@ package coderd_test
 
 import (
 	"context"
+	"net/http"
 	"sync"
 	"testing"
 
@@ -257,4 +258,100 @@ func TestWorkspaceQuota(t *testing.T) {
 		verifyQuota(ctx, t, client, 4, 4)
 		require.Equal(t, codersdk.WorkspaceStatusRunning, build.Status)
 	})
+
+	// Overrides verifies that group and user overrides replace the summed
+	// group allowances.
+	t.Run("Overrides", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitLong)
+		defer cancel()
+		client, user := coderdenttest.New(t, &coderdenttest.Options{
+			LicenseOptions: &coderdenttest.LicenseOptions{
+				Features: license.Features{
+					codersdk.FeatureTemplateRBAC: 1,
+				},
+			},
+		})
+
+		_, err := client.PatchGroup(ctx, user.OrganizationID, codersdk.PatchGroupRequest{
+			QuotaAllowance: ptr.Ref(1),
+		})
+		require.NoError(t, err)
+		group1, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name:           "test-1",
+			QuotaAllowance: 2,
+		})
+		require.NoError(t, err)
+		group2, err := client.CreateGroup(ctx, user.OrganizationID, codersdk.CreateGroupRequest{
+			Name:           "test-2",
+			QuotaAllowance: 3,
+		})
+		require.NoError(t, err)
+		for _, group := range []codersdk.Group{group1, group2} {
+			_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+				AddUsers: []string{user.UserID.String()},
+			})
+			require.NoError(t, err)
+		}
+		verifyQuota(ctx, t, client, 0, 6)
+
+		// The largest group override wins over the summed allowances.
+		_, err = client.PatchGroup(ctx, group1.ID, codersdk.PatchGroupRequest{
+			QuotaOverride: ptr.Ref(10),
+		})
+		require.NoError(t, err)
+		_, err = client.PatchGroup(ctx, group2.ID, codersdk.PatchGroupRequest{
+			QuotaOverride: ptr.Ref(8),
+		})
+		require.NoError(t, err)
+		verifyQuota(ctx, t, client, 0, 10)
+
+		breakdown, err := client.WorkspaceQuotaBreakdown(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, 10, breakdown.Budget)
+		var applied []codersdk.WorkspaceQuotaBudget
+		for _, budget := range breakdown.Budgets {
+			if budget.Applied {
+				applied = append(applied, budget)
+			}
+		}
+		require.Len(t, applied, 1)
+		require.Equal(t, codersdk.WorkspaceQuotaBudgetSourceGroupOverride, applied[0].Source)
+		require.Equal(t, group1.ID, *applied[0].GroupID)
+
+		// A user override takes precedence over group overrides.
+		err = client.UpdateWorkspaceQuotaOverride(ctx, codersdk.Me, codersdk.UpdateWorkspaceQuotaOverrideRequest{
+			QuotaAllowance: 2,
+		})
+		require.NoError(t, err)
+		verifyQuota(ctx, t, client, 0, 2)
+
+		breakdown, err = client.WorkspaceQuotaBreakdown(ctx, codersdk.Me)
+		require.NoError(t, err)
+		require.Equal(t, codersdk.WorkspaceQuotaBudgetSourceUserOverride, breakdown.Budgets[0].Source)
+		require.True(t, breakdown.Budgets[0].Applied)
+
+		err = client.DeleteWorkspaceQuotaOverride(ctx, codersdk.Me)
+		require.NoError(t, err)
+		verifyQuota(ctx, t, client, 0, 10)
+
+		// Removing the group overrides restores the summed allowances.
+		for _, group := range []codersdk.Group{group1, group2} {
+			_, err = client.PatchGroup(ctx, group.ID, codersdk.PatchGroupRequest{
+				QuotaOverride: ptr.Ref(-1),
+			})
+			require.NoError(t, err)
+		}
+		verifyQuota(ctx, t, client, 0, 6)
+
+		// Members can't grant themselves more quota.
+		member, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+		err = member.UpdateWorkspaceQuotaOverride(ctx, codersdk.Me, codersdk.UpdateWorkspaceQuotaOverrideRequest{
+			QuotaAllowance: 100,
+		})
+		var apiErr *codersdk.Error
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, http.StatusForbidden, apiErr.StatusCode())
+	})
 }
@@ -61,7 +61,7 @@ func TestCreateWorkspace(t *testing.T) {
 		require.Len(t, acl.Groups, 1)
 		require.Len(t, acl.Users, 0)
 
-		err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+		_, err = client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 			GroupPerms: map[string]codersdk.TemplateRole{
 				acl.Groups[0].ID.String(): codersdk.TemplateRoleDeleted,
 			},
@@ -136,6 +136,8 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		// failure TTL.
 		require.Len(t, stats.Transitions, 1)
 		require.Equal(t, stats.Transitions[ws.ID], database.WorkspaceTransitionStop)
+		ws = coderdtest.MustWorkspace(t, client, ws.ID)
+		require.Equal(t, codersdk.BuildReasonFailedStop, ws.LatestBuild.Reason)
 	})
 
 	t.Run("FailureTTLTooEarly", func(t *testing.T) {
@@ -278,6 +280,7 @@ func TestWorkspaceAutobuild(t *testing.T) {
 		// The workspace should be locked.
 		ws = coderdtest.MustWorkspace(t, client, ws.ID)
 		require.NotNil(t, ws.LockedAt)
+		require.Equal(t, codersdk.BuildReasonAutolock, ws.LatestBuild.Reason)
 		lastUsedAt := ws.LastUsedAt
 
 		err := client.UpdateWorkspaceLock(ctx, ws.ID, codersdk.UpdateWorkspaceLock{Lock: false})
@@ -715,7 +718,7 @@ func TestWorkspacesWithoutTemplatePerms(t *testing.T) {
 	defer cancel()
 
 	// Remove everyone access
-	err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
+	_, err := client.UpdateTemplateACL(ctx, template.ID, codersdk.UpdateTemplateACL{
 		GroupPerms: map[string]codersdk.TemplateRole{
 			first.OrganizationID.String(): codersdk.TemplateRoleDeleted,
 		},
@@ -6,7 +6,10 @@ import (
 	"net"
 	"net/url"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/xerrors"
 	"tailscale.com/derp"
 	"tailscale.com/derp/derphttp"
@@ -17,6 +20,30 @@ import (
 	"cdr.dev/slog"
 )
 
+const (
+	// pingInterval is how often meshed DERP servers are pinged to measure
+	// the round trip time.
+	pingInterval = 15 * time.Second
+	pingTimeout  = 5 * time.Second
+)
+
+var (
+	meshPeersDesc = prometheus.NewDesc("coderd_derp_mesh_peers",
+		"The number of DERP servers in the mesh.",
+		nil, nil)
+	meshPeerConnectedDesc = prometheus.NewDesc("coderd_derp_mesh_peer_connected",
+		"Whether the last ping to a meshed DERP server succeeded.",
+		[]string{"mesh_address"}, nil)
+	meshPeerRTTDesc = prometheus.NewDesc("coderd_derp_mesh_peer_rtt_seconds",
+		"The round trip time of the last successful ping to a meshed DERP server.",
+		[]string{"mesh_address"}, nil)
+	meshForwardedPacketsDesc = prometheus.NewDesc("coderd_derp_mesh_forwarded_packets_total",
+		"The number of packets forwarded to a meshed DERP server.",
+		[]string{"mesh_address"}, nil)
+)
+
+var _ prometheus.Collector = (*Mesh)(nil)
+
 // New constructs a new mesh for DERP servers.
 func New(logger slog.Logger, server *derp.Server, tlsConfig *tls.Config) *Mesh {
 	return &Mesh{
@@ -25,7 +52,7 @@ func New(logger slog.Logger, server *derp.Server, tlsConfig *tls.Config) *Mesh {
 		tlsConfig: tlsConfig,
 		ctx:       context.Background(),
 		closed:    make(chan struct{}),
-		active:    make(map[string]context.CancelFunc),
+		active:    make(map[string]*peer),
 	}
 }
 
@@ -37,7 +64,38 @@ type Mesh struct {
 
 	mutex  sync.Mutex
 	closed chan struct{}
-	active map[string]context.CancelFunc
+	active map[string]*peer
+	usage  *tailnet.DERPUsage
+}
+
+// peer is a DERP server the mesh forwards packets to.
+type peer struct {
+	close func()
+
+	connected atomic.Bool
+	rtt       atomic.Int64
+	forwarded atomic.Int64
+}
+
+// forwarder counts the packets forwarded to a peer.
+type forwarder struct {
+	*derphttp.Client
+	peer *peer
+}
+
+func (f *forwarder) ForwardPacket(src, dst key.NodePublic, payload []byte) error {
+	err := f.Client.ForwardPacket(src, dst, payload)
+	if err == nil {
+		f.peer.forwarded.Add(1)
+	}
+	return err
+}
+
+// TrackUsage counts the traffic of mesh clients added afterwards in usage.
+func (m *Mesh) TrackUsage(usage *tailnet.DERPUsage) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.usage = usage
 }
 
 // SetAddresses performs a diff of the incoming addresses and adds
@@ -106,37 +164,79 @@ func (m *Mesh) addAddress(address string, connect bool) (bool, error) {
 	}
 	client.TLSConfig = m.tlsConfig
 	client.MeshKey = m.server.MeshKey()
-	client.SetURLDialer(func(ctx context.Context, network, addr string) (net.Conn, error) {
-		var dialer net.Dialer
-		return dialer.DialContext(ctx, network, addr)
-	})
+	if m.usage != nil {
+		client.SetURLDialer(m.usage.MeshDialer(address))
+	} else {
+		client.SetURLDialer(func(ctx context.Context, network, addr string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, addr)
+		})
+	}
 	if connect {
 		_ = client.Connect(m.ctx)
 	}
 	ctx, cancelFunc := context.WithCancel(m.ctx)
-	closed := make(chan struct{})
-	closeFunc := func() {
-		cancelFunc()
-		_ = client.Close()
-		<-closed
+	var wg sync.WaitGroup
+	p := &peer{
+		close: func() {
+			cancelFunc()
+			_ = client.Close()
+			wg.Wait()
+		},
 	}
-	m.active[address] = closeFunc
+	m.active[address] = p
+	fwd := &forwarder{Client: client, peer: p}
+	wg.Add(2)
 	go func() {
-		defer close(closed)
+		defer wg.Done()
 		client.RunWatchConnectionLoop(ctx, m.server.PublicKey(), tailnet.Logger(m.logger.Named("loop")), func(np key.NodePublic) {
-			m.server.AddPacketForwarder(np, client)
+			m.server.AddPacketForwarder(np, fwd)
 		}, func(np key.NodePublic) {
-			m.server.RemovePacketForwarder(np, client)
+			m.server.RemovePacketForwarder(np, fwd)
 		})
 	}()
+	go func() {
+		defer wg.Done()
+		m.pingLoop(ctx, address, client, p)
+	}()
 	return true, nil
 }
 
+// pingLoop measures the round trip time to a peer until the context is
+// canceled. The watch connection loop handles the responses.
+func (m *Mesh) pingLoop(ctx context.Context, address string, client *derphttp.Client, p *peer) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+		start := time.Now()
+		err := client.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if p.connected.Swap(false) {
+				m.logger.Warn(ctx, "failed to ping mesh address", slog.F("address", address), slog.Error(err))
+			}
+		} else {
+			p.rtt.Store(int64(time.Since(start)))
+			p.connected.Store(true)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // removeAddress stops meshing with a given address.
 func (m *Mesh) removeAddress(address string) bool {
-	cancelFunc, isActive := m.active[address]
+	p, isActive := m.active[address]
 	if isActive {
-		cancelFunc()
+		p.close()
 		delete(m.active, address)
 	}
 	return isActive
@@ -150,12 +250,41 @@ func (m *Mesh) Close() error {
 		return nil
 	}
 	close(m.closed)
-	for _, cancelFunc := range m.active {
-		cancelFunc()
+	for _, p := range m.active {
+		p.close()
 	}
 	return nil
 }
 
+func (*Mesh) Describe(descs chan<- *prometheus.Desc) {
+	descs <- meshPeersDesc
+	descs <- meshPeerConnectedDesc
+	descs <- meshPeerRTTDesc
+	descs <- meshForwardedPacketsDesc
+}
+
+func (m *Mesh) Collect(metrics chan<- prometheus.Metric) {
+	m.mutex.Lock()
+	peers := make(map[string]*peer, len(m.active))
+	for address, p := range m.active {
+		peers[address] = p
+	}
+	m.mutex.Unlock()
+
+	metrics <- prometheus.MustNewConstMetric(meshPeersDesc, prometheus.GaugeValue, float64(len(peers)))
+	for address, p := range peers {
+		var connected float64
+		if p.connected.Load() {
+			connected = 1
+		}
+		metrics <- prometheus.MustNewConstMetric(meshPeerConnectedDesc, prometheus.GaugeValue, connected, address)
+		if rtt := p.rtt.Load(); rtt > 0 {
+			metrics <- prometheus.MustNewConstMetric(meshPeerRTTDesc, prometheus.GaugeValue, time.Duration(rtt).Seconds(), address)
+		}
+		metrics <- prometheus.MustNewConstMetric(meshForwardedPacketsDesc, prometheus.CounterValue, float64(p.forwarded.Load()), address)
+	}
+}
+
 func (m *Mesh) isClosed() bool {
 	select {
 	case <-m.closed:
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
@@ -238,6 +239,117 @@ func TestDERPMesh(t *testing.T) {
 		cancelFunc()
 		<-closed
 	})
+	t.Run("TrackUsage", func(t *testing.T) {
+		t.Parallel()
+		usage := tailnet.NewDERPUsage(999)
+		firstServer, firstServerURL := startDERPWithUsage(t, tlsConfig, usage)
+		secondServer, secondServerURL := startDERP(t, tlsConfig)
+		firstMesh := derpmesh.New(slogtest.Make(t, nil).Named("first").Leveled(slog.LevelDebug), firstServer, tlsConfig)
+		firstMesh.TrackUsage(usage)
+		firstMesh.SetAddresses([]string{secondServerURL}, true)
+		secondMesh := derpmesh.New(slogtest.Make(t, nil).Named("second").Leveled(slog.LevelDebug), secondServer, tlsConfig)
+		secondMesh.SetAddresses([]string{firstServerURL}, true)
+		defer firstMesh.Close()
+		defer secondMesh.Close()
+
+		first := key.NewNode()
+		second := key.NewNode()
+		firstClient, err := derphttp.NewClient(first, secondServerURL, tailnet.Logger(slogtest.Make(t, nil)))
+		require.NoError(t, err)
+		firstClient.TLSConfig = tlsConfig
+		secondClient, err := derphttp.NewClient(second, firstServerURL, tailnet.Logger(slogtest.Make(t, nil)))
+		require.NoError(t, err)
+		secondClient.TLSConfig = tlsConfig
+		err = secondClient.Connect(context.Background())
+		require.NoError(t, err)
+
+		closed := make(chan struct{})
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		defer cancelFunc()
+		sent := []byte("hello world")
+		go func() {
+			defer close(closed)
+			ticker := time.NewTicker(50 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+				err = firstClient.Send(second.Public(), sent)
+				require.NoError(t, err)
+			}
+		}()
+
+		got := recvData(t, secondClient)
+		require.Equal(t, sent, got)
+		cancelFunc()
+		<-closed
+
+		received, sentBytes, connections := usage.Totals()
+		require.Greater(t, received, int64(len(sent)))
+		require.Greater(t, sentBytes, int64(len(sent)))
+		require.Positive(t, connections)
+
+		clients := usage.Clients()
+		require.Len(t, clients, 1)
+		require.Equal(t, "127.0.0.1", clients[0].Address)
+		require.Positive(t, clients[0].Connections)
+
+		mesh := usage.Mesh()
+		require.Len(t, mesh, 1)
+		require.Equal(t, secondServerURL+"/derp", mesh[0].Address)
+		require.Positive(t, mesh[0].ReceivedBytes)
+		require.Positive(t, mesh[0].SentBytes)
+
+		registry := prometheus.NewRegistry()
+		require.NoError(t, registry.Register(usage))
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		names := make([]string, 0, len(families))
+		for _, family := range families {
+			names = append(names, family.GetName())
+		}
+		require.ElementsMatch(t, []string{
+			"coderd_derp_mesh_received_bytes_total",
+			"coderd_derp_mesh_sent_bytes_total",
+			"coderd_derp_server_connections",
+			"coderd_derp_server_received_bytes_total",
+			"coderd_derp_server_sent_bytes_total",
+		}, names)
+	})
+	t.Run("Metrics", func(t *testing.T) {
+		t.Parallel()
+		firstServer, _ := startDERP(t, tlsConfig)
+		defer firstServer.Close()
+		_, secondServerURL := startDERP(t, tlsConfig)
+		mesh := derpmesh.New(slogtest.Make(t, nil).Named("first").Leveled(slog.LevelDebug), firstServer, tlsConfig)
+		mesh.SetAddresses([]string{secondServerURL}, true)
+		defer mesh.Close()
+
+		registry := prometheus.NewRegistry()
+		require.NoError(t, registry.Register(mesh))
+		metrics := map[string]float64{}
+		require.Eventually(t, func() bool {
+			families, err := registry.Gather()
+			assert.NoError(t, err)
+			for _, family := range families {
+				for _, metric := range family.GetMetric() {
+					switch family.GetName() {
+					case "coderd_derp_mesh_peers", "coderd_derp_mesh_peer_connected", "coderd_derp_mesh_peer_rtt_seconds":
+						metrics[family.GetName()] = metric.GetGauge().GetValue()
+					case "coderd_derp_mesh_forwarded_packets_total":
+						metrics[family.GetName()] = metric.GetCounter().GetValue()
+					}
+				}
+			}
+			return metrics["coderd_derp_mesh_peer_connected"] == 1
+		}, testutil.WaitLong, testutil.IntervalFast)
+		require.Equal(t, float64(1), metrics["coderd_derp_mesh_peers"])
+		require.Positive(t, metrics["coderd_derp_mesh_peer_rtt_seconds"])
+		require.Contains(t, metrics, "coderd_derp_mesh_forwarded_packets_total")
+	})
 }
 
 func recvData(t *testing.T, client *derphttp.Client) []byte {
@@ -270,3 +382,17 @@ func startDERP(t *testing.T, tlsConfig *tls.Config) (*derp.Server, string) {
 	t.Cleanup(server.Close)
 	return d, server.URL
 }
+
+func startDERPWithUsage(t *testing.T, tlsConfig *tls.Config, usage *tailnet.DERPUsage) (*derp.Server, string) {
+	logf := tailnet.Logger(slogtest.Make(t, nil))
+	d := derp.NewServer(key.NewNode(), logf)
+	d.SetMeshKey("some-key")
+	server := httptest.NewUnstartedServer(usage.Handler(derphttp.Handler(d)))
+	server.TLS = tlsConfig
+	server.StartTLS()
+	t.Cleanup(func() {
+		_ = d.Close()
+	})
+	t.Cleanup(server.Close)
+	return d, server.URL
+}
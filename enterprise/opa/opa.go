@@ -0,0 +1,239 @@
+// Package opa delegates authorization decisions to Open Policy Agent
+// policies, so deployments can express policies beyond the built-in roles.
+package opa
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/ammario/tlru"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/coderd/rbac/regosql"
+)
+
+// Fallback decides authorization when the policy can't be evaluated, e.g.
+// because the OPA server is unreachable. System subjects always fall back to
+// the built-in roles.
+type Fallback string
+
+const (
+	// FallbackDeny denies all requests.
+	FallbackDeny Fallback = "deny"
+	// FallbackBuiltin applies the decision of the built-in roles.
+	FallbackBuiltin Fallback = "builtin"
+)
+
+// Input is the input document of the policy. The policy decides whether the
+// subject may perform the action on the object.
+type Input struct {
+	Subject Subject     `json:"subject"`
+	Action  rbac.Action `json:"action"`
+	Object  rbac.Object `json:"object"`
+	// BuiltinAllowed is the decision of the built-in roles, so policies can
+	// restrict or extend it instead of replacing it.
+	BuiltinAllowed bool `json:"builtin_allowed"`
+}
+
+type Subject struct {
+	ID     string   `json:"id"`
+	Roles  []string `json:"roles"`
+	Groups []string `json:"groups"`
+	Scope  string   `json:"scope"`
+}
+
+// Policy makes authorization decisions.
+type Policy interface {
+	// Allow returns whether the input is allowed. An error is returned if
+	// the policy can't be evaluated.
+	Allow(ctx context.Context, input Input) (bool, error)
+}
+
+type Options struct {
+	Logger   slog.Logger
+	Registry prometheus.Registerer
+	// Fallback defaults to FallbackBuiltin.
+	Fallback Fallback
+	// CacheTTL is how long decisions are reused for identical inputs.
+	// Caching is disabled if it's zero.
+	CacheTTL time.Duration
+}
+
+// Authorizer makes authorization decisions with a policy. It's enabled when
+// created, and can be disabled to only apply the built-in roles, e.g. when the
+// license isn't entitled to it.
+type Authorizer struct {
+	enabled  atomic.Bool
+	builtin  rbac.Authorizer
+	policy   Policy
+	logger   slog.Logger
+	fallback Fallback
+	cacheTTL time.Duration
+	cache    *tlru.Cache[[32]byte, bool]
+
+	decisions *prometheus.CounterVec
+}
+
+// NewAuthorizer returns an authorizer whose decisions are made by the policy.
+// The decision of the built-in authorizer is passed to the policy as input.
+//
+// Queries that filter objects in the database can't consult the policy, so
+// their SQL filter only applies the built-in roles. Paginated lists fetch all
+// rows the built-in roles allow, authorize them with the policy one at a
+// time, and paginate and count the rest. In lists the policy can therefore
+// only narrow access: objects it allows beyond the built-in roles are never
+// listed.
+//
+// System subjects, like the provisioner daemons and the autobuild executor,
+// are only authorized by the built-in roles, so the deployment keeps working
+// when the policy can't be evaluated or denies them by mistake.
+func NewAuthorizer(builtin rbac.Authorizer, policy Policy, opts Options) *Authorizer {
+	if opts.Fallback == "" {
+		opts.Fallback = FallbackBuiltin
+	}
+	if opts.Registry == nil {
+		opts.Registry = prometheus.NewRegistry()
+	}
+	a := &Authorizer{
+		builtin:  builtin,
+		policy:   policy,
+		logger:   opts.Logger,
+		fallback: opts.Fallback,
+		cacheTTL: opts.CacheTTL,
+		cache:    tlru.New[[32]byte](tlru.ConstantCost[bool], 64*1024),
+		decisions: promauto.With(opts.Registry).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "coderd",
+			Subsystem: "authz",
+			Name:      "external_decisions_total",
+			Help:      "The number of authorization decisions made by the external policy, by result.",
+		}, []string{"result"}),
+	}
+	a.enabled.Store(true)
+	return a
+}
+
+// SetEnabled enables or disables the policy. Disabled authorizers only apply
+// the built-in roles.
+func (a *Authorizer) SetEnabled(enabled bool) {
+	a.enabled.Store(enabled)
+}
+
+// appliesTo returns whether the policy decides the requests of the subject.
+func (a *Authorizer) appliesTo(subject rbac.Subject) bool {
+	// The system subjects of dbauthz don't belong to a user.
+	return a.enabled.Load() && subject.ID != uuid.Nil.String()
+}
+
+func (a *Authorizer) Authorize(ctx context.Context, subject rbac.Subject, action rbac.Action, object rbac.Object) error {
+	builtinErr := a.builtin.Authorize(ctx, subject, action, object)
+	if !a.appliesTo(subject) {
+		return builtinErr
+	}
+	return a.decide(ctx, subject, action, object, builtinErr)
+}
+
+// decide consults the policy, given the result of the built-in authorizer.
+func (a *Authorizer) decide(ctx context.Context, subject rbac.Subject, action rbac.Action, object rbac.Object, builtinErr error) error {
+	if builtinErr != nil && !rbac.IsUnauthorizedError(builtinErr) {
+		return builtinErr
+	}
+
+	input := Input{
+		Subject: Subject{
+			ID:     subject.ID,
+			Roles:  subject.SafeRoleNames(),
+			Groups: subject.Groups,
+			Scope:  subject.SafeScopeName(),
+		},
+		Action:         action,
+		Object:         object,
+		BuiltinAllowed: builtinErr == nil,
+	}
+	allowed, err := a.allow(ctx, input)
+	if err != nil {
+		a.decisions.WithLabelValues("error").Inc()
+		a.logger.Warn(ctx, "evaluate external authorization policy",
+			slog.F("fallback", a.fallback),
+			slog.Error(err),
+		)
+		if a.fallback == FallbackBuiltin {
+			return builtinErr
+		}
+		return rbac.ForbiddenWithInternal(xerrors.Errorf("evaluate external policy: %w", err), subject, action, object, nil)
+	}
+	if !allowed {
+		a.decisions.WithLabelValues("denied").Inc()
+		return rbac.ForbiddenWithInternal(xerrors.New("external policy disallows request"), subject, action, object, nil)
+	}
+	a.decisions.WithLabelValues("allowed").Inc()
+	return nil
+}
+
+func (a *Authorizer) allow(ctx context.Context, input Input) (bool, error) {
+	if a.cacheTTL <= 0 {
+		return a.policy.Allow(ctx, input)
+	}
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return false, xerrors.Errorf("marshal input: %w", err)
+	}
+	key := sha256.Sum256(raw)
+	if allowed, _, ok := a.cache.Get(key); ok {
+		return allowed, nil
+	}
+	allowed, err := a.policy.Allow(ctx, input)
+	if err != nil {
+		// Failures aren't cached, so the policy is consulted again as soon
+		// as it's available.
+		return false, err
+	}
+	a.cache.Set(key, allowed, a.cacheTTL)
+	return allowed, nil
+}
+
+func (a *Authorizer) Prepare(ctx context.Context, subject rbac.Subject, action rbac.Action, objectType string) (rbac.PreparedAuthorized, error) {
+	prepared, err := a.builtin.Prepare(ctx, subject, action, objectType)
+	if err != nil {
+		return nil, err
+	}
+	if !a.appliesTo(subject) {
+		return prepared, nil
+	}
+	return &preparedAuthorizer{
+		authorizer: a,
+		prepared:   prepared,
+		subject:    subject,
+		action:     action,
+	}, nil
+}
+
+type preparedAuthorizer struct {
+	authorizer *Authorizer
+	prepared   rbac.PreparedAuthorized
+	subject    rbac.Subject
+	action     rbac.Action
+}
+
+func (p *preparedAuthorizer) Authorize(ctx context.Context, object rbac.Object) error {
+	builtinErr := p.prepared.Authorize(ctx, object)
+	return p.authorizer.decide(ctx, p.subject, p.action, object, builtinErr)
+}
+
+// CompileToSQL only applies the built-in roles, see NewAuthorizer.
+func (p *preparedAuthorizer) CompileToSQL(ctx context.Context, cfg regosql.ConvertConfig) (string, error) {
+	return p.prepared.CompileToSQL(ctx, cfg)
+}
+
+// RequiresPostFilter is always true, because the policy can deny rows the
+// SQL filter allows.
+func (*preparedAuthorizer) RequiresPostFilter() bool {
+	return true
+}
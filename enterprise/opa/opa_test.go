@@ -0,0 +1,252 @@
+package opa_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/coderd/rbac"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/enterprise/opa"
+	"github.com/coder/coder/v2/testutil"
+)
+
+// denyWorkspaceDeletion denies deleting workspaces to everyone, and allows
+// auditors to read workspaces, which they can't with the built-in roles.
+const denyWorkspaceDeletion = `package coder.authz
+
+default allow = false
+
+allow {
+	input.builtin_allowed
+	not deletes_workspace
+}
+
+allow {
+	input.subject.roles[_] == "auditor"
+	input.object.type == "workspace"
+	input.action == "read"
+}
+
+deletes_workspace {
+	input.object.type == "workspace"
+	input.action == "delete"
+}
+`
+
+func TestRegoPolicy(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "policy.rego"), []byte(denyWorkspaceDeletion), 0o600)
+	require.NoError(t, err)
+	policy, err := opa.NewRegoPolicy(context.Background(), dir)
+	require.NoError(t, err)
+	authz := opa.NewAuthorizer(rbac.NewAuthorizer(prometheus.NewRegistry()), policy, opa.Options{
+		Logger: slogtest.Make(t, nil),
+	})
+
+	ctx := context.Background()
+	owner := rbac.Subject{ID: uuid.NewString(), Roles: rbac.RoleNames{rbac.RoleOwner()}, Scope: rbac.ScopeAll}
+	auditor := rbac.Subject{ID: uuid.NewString(), Roles: rbac.RoleNames{rbac.RoleMember(), "auditor"}, Scope: rbac.ScopeAll}
+	workspace := rbac.ResourceWorkspace.WithID(uuid.New()).InOrg(uuid.New()).WithOwner(uuid.NewString())
+
+	require.NoError(t, authz.Authorize(ctx, owner, rbac.ActionRead, workspace))
+	err = authz.Authorize(ctx, owner, rbac.ActionDelete, workspace)
+	require.True(t, rbac.IsUnauthorizedError(err), err)
+	require.NoError(t, authz.Authorize(ctx, auditor, rbac.ActionRead, workspace))
+	err = authz.Authorize(ctx, auditor, rbac.ActionUpdate, workspace)
+	require.True(t, rbac.IsUnauthorizedError(err), err)
+
+	prepared, err := authz.Prepare(ctx, owner, rbac.ActionDelete, rbac.ResourceWorkspace.Type)
+	require.NoError(t, err)
+	err = prepared.Authorize(ctx, workspace)
+	require.True(t, rbac.IsUnauthorizedError(err), err)
+	// The SQL filter only applies the built-in roles.
+	require.True(t, rbac.RequiresPostFilter(prepared))
+}
+
+func TestListsApplyPolicy(t *testing.T) {
+	t.Parallel()
+
+	// Doesn't let owners read the workspaces of other users.
+	const ownWorkspaces = `package coder.authz
+
+default allow = false
+
+allow {
+	input.builtin_allowed
+	not reads_other_workspace
+}
+
+reads_other_workspace {
+	input.subject.roles[_] == "owner"
+	input.object.type == "workspace"
+	input.object.owner != input.subject.id
+}
+`
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "policy.rego"), []byte(ownWorkspaces), 0o600)
+	require.NoError(t, err)
+	policy, err := opa.NewRegoPolicy(context.Background(), dir)
+	require.NoError(t, err)
+
+	client := coderdtest.New(t, &coderdtest.Options{
+		IncludeProvisionerDaemon: true,
+		Authorizer: opa.NewAuthorizer(rbac.NewAuthorizer(prometheus.NewRegistry()), policy, opa.Options{
+			Logger: slogtest.Make(t, nil),
+		}),
+	})
+	user := coderdtest.CreateFirstUser(t, client)
+	memberClient, _ := coderdtest.CreateAnotherUser(t, client, user.OrganizationID)
+	version := coderdtest.CreateTemplateVersion(t, client, user.OrganizationID, nil)
+	coderdtest.AwaitTemplateVersionJob(t, client, version.ID)
+	template := coderdtest.CreateTemplate(t, client, user.OrganizationID, version.ID)
+	ownerWorkspaces := map[uuid.UUID]bool{}
+	for i := 0; i < 3; i++ {
+		ownerWorkspaces[coderdtest.CreateWorkspace(t, client, user.OrganizationID, template.ID).ID] = true
+		_ = coderdtest.CreateWorkspace(t, memberClient, user.OrganizationID, template.ID)
+	}
+
+	ctx := testutil.Context(t, testutil.WaitLong)
+	res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{})
+	require.NoError(t, err)
+	require.Len(t, res.Workspaces, 3)
+	require.Equal(t, 3, res.Count)
+	for _, workspace := range res.Workspaces {
+		require.True(t, ownerWorkspaces[workspace.ID])
+	}
+
+	// Pages are paginated after the policy was applied, so they're full and
+	// count all authorized workspaces.
+	seen := map[uuid.UUID]bool{}
+	for offset := 0; offset < 3; offset++ {
+		res, err := client.Workspaces(ctx, codersdk.WorkspaceFilter{Offset: offset, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, res.Workspaces, 1)
+		require.Equal(t, 3, res.Count)
+		require.True(t, ownerWorkspaces[res.Workspaces[0].ID])
+		seen[res.Workspaces[0].ID] = true
+	}
+	require.Len(t, seen, 3)
+	res, err = client.Workspaces(ctx, codersdk.WorkspaceFilter{Offset: 3, Limit: 1})
+	require.NoError(t, err)
+	require.Empty(t, res.Workspaces)
+}
+
+func TestServerPolicy(t *testing.T) {
+	t.Parallel()
+
+	owner := rbac.Subject{ID: uuid.NewString(), Roles: rbac.RoleNames{rbac.RoleOwner()}, Scope: rbac.ScopeAll}
+	template := rbac.ResourceTemplate.WithID(uuid.New()).InOrg(uuid.New())
+
+	setup := func(t *testing.T, handler http.HandlerFunc, opts opa.Options) *opa.Authorizer {
+		srv := httptest.NewServer(handler)
+		t.Cleanup(srv.Close)
+		u, err := url.Parse(srv.URL + "/v1/data/coder/authz/allow")
+		require.NoError(t, err)
+		opts.Logger = slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		return opa.NewAuthorizer(rbac.NewAuthorizer(prometheus.NewRegistry()), opa.NewServerPolicy(http.DefaultClient, u), opts)
+	}
+
+	t.Run("Decision", func(t *testing.T) {
+		t.Parallel()
+		var inputs atomic.Int64
+		authz := setup(t, func(rw http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Input opa.Input `json:"input"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&req)
+			if err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			inputs.Add(1)
+			allowed := req.Input.BuiltinAllowed && req.Input.Action != rbac.ActionUpdate
+			_ = json.NewEncoder(rw).Encode(map[string]bool{"result": allowed})
+		}, opa.Options{CacheTTL: time.Minute})
+
+		ctx := context.Background()
+		require.NoError(t, authz.Authorize(ctx, owner, rbac.ActionRead, template))
+		require.NoError(t, authz.Authorize(ctx, owner, rbac.ActionRead, template))
+		require.EqualValues(t, 1, inputs.Load(), "decisions must be cached")
+		err := authz.Authorize(ctx, owner, rbac.ActionUpdate, template)
+		require.True(t, rbac.IsUnauthorizedError(err), err)
+	})
+
+	t.Run("Undefined", func(t *testing.T) {
+		t.Parallel()
+		authz := setup(t, func(rw http.ResponseWriter, r *http.Request) {
+			_, _ = rw.Write([]byte("{}"))
+		}, opa.Options{})
+
+		err := authz.Authorize(context.Background(), owner, rbac.ActionRead, template)
+		require.True(t, rbac.IsUnauthorizedError(err), err)
+	})
+
+	for _, fallback := range []opa.Fallback{opa.FallbackDeny, opa.FallbackBuiltin} {
+		fallback := fallback
+		t.Run("Fallback"+string(fallback), func(t *testing.T) {
+			t.Parallel()
+			authz := setup(t, func(rw http.ResponseWriter, r *http.Request) {
+				rw.WriteHeader(http.StatusServiceUnavailable)
+			}, opa.Options{Fallback: fallback})
+
+			err := authz.Authorize(context.Background(), owner, rbac.ActionRead, template)
+			if fallback == opa.FallbackBuiltin {
+				require.NoError(t, err)
+				return
+			}
+			require.True(t, rbac.IsUnauthorizedError(err), err)
+			require.ErrorContains(t, xerrors.Unwrap(err), "unexpected status 503")
+		})
+	}
+
+	t.Run("SystemSubject", func(t *testing.T) {
+		t.Parallel()
+		authz := setup(t, func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+		}, opa.Options{Fallback: opa.FallbackDeny})
+
+		// System subjects keep working when the policy is unavailable.
+		system := rbac.Subject{ID: uuid.Nil.String(), Roles: rbac.RoleNames{rbac.RoleOwner()}, Scope: rbac.ScopeAll}
+		require.NoError(t, authz.Authorize(context.Background(), system, rbac.ActionRead, template))
+		prepared, err := authz.Prepare(context.Background(), system, rbac.ActionRead, rbac.ResourceTemplate.Type)
+		require.NoError(t, err)
+		require.NoError(t, prepared.Authorize(context.Background(), template))
+		require.False(t, rbac.RequiresPostFilter(prepared))
+
+		err = authz.Authorize(context.Background(), owner, rbac.ActionRead, template)
+		require.True(t, rbac.IsUnauthorizedError(err), err)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+		var inputs atomic.Int64
+		authz := setup(t, func(rw http.ResponseWriter, r *http.Request) {
+			inputs.Add(1)
+			_ = json.NewEncoder(rw).Encode(map[string]bool{"result": false})
+		}, opa.Options{})
+
+		authz.SetEnabled(false)
+		require.NoError(t, authz.Authorize(context.Background(), owner, rbac.ActionRead, template))
+		require.Zero(t, inputs.Load())
+		authz.SetEnabled(true)
+		err := authz.Authorize(context.Background(), owner, rbac.ActionRead, template)
+		require.True(t, rbac.IsUnauthorizedError(err), err)
+	})
+}
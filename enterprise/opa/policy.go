@@ -0,0 +1,116 @@
+package opa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/open-policy-agent/opa/rego"
+	"golang.org/x/xerrors"
+)
+
+// Query is the rule policies define to allow requests.
+const Query = "data.coder.authz.allow"
+
+type serverPolicy struct {
+	client *http.Client
+	url    string
+}
+
+// NewServerPolicy returns a policy evaluated by an OPA server. The URL is the
+// document of the decision in the OPA data API, e.g.
+// http://localhost:8181/v1/data/coder/authz/allow.
+func NewServerPolicy(client *http.Client, u *url.URL) Policy {
+	return &serverPolicy{
+		client: client,
+		url:    u.String(),
+	}
+}
+
+func (p *serverPolicy) Allow(ctx context.Context, input Input) (bool, error) {
+	body, err := json.Marshal(struct {
+		Input Input `json:"input"`
+	}{Input: input})
+	if err != nil {
+		return false, xerrors.Errorf("marshal input: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return false, xerrors.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := p.client.Do(req)
+	if err != nil {
+		return false, xerrors.Errorf("query opa: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return false, xerrors.Errorf("query opa: unexpected status %d: %s", res.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	// The result is missing if the document is undefined, which OPA does for
+	// rules without a default that don't match.
+	var decision struct {
+		Result *bool `json:"result"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&decision)
+	if err != nil {
+		return false, xerrors.Errorf("decode decision: %w", err)
+	}
+	return decision.Result != nil && *decision.Result, nil
+}
+
+type regoPolicy struct {
+	query rego.PreparedEvalQuery
+}
+
+// NewRegoPolicy returns a policy evaluated in process from the .rego files in
+// a directory. Policies allow requests with the data.coder.authz.allow rule.
+func NewRegoPolicy(ctx context.Context, dir string) (Policy, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.rego"))
+	if err != nil {
+		return nil, xerrors.Errorf("find policies: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, xerrors.Errorf("no .rego files found in %q", dir)
+	}
+	options := []func(*rego.Rego){rego.Query(Query)}
+	for _, file := range files {
+		module, err := os.ReadFile(file)
+		if err != nil {
+			return nil, xerrors.Errorf("read policy: %w", err)
+		}
+		options = append(options, rego.Module(filepath.Base(file), string(module)))
+	}
+	query, err := rego.New(options...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("compile policies: %w", err)
+	}
+	return &regoPolicy{query: query}, nil
+}
+
+func (p *regoPolicy) Allow(ctx context.Context, input Input) (bool, error) {
+	// Round trip the input through JSON so policies see the same document
+	// as they would on an OPA server.
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return false, xerrors.Errorf("marshal input: %w", err)
+	}
+	var doc interface{}
+	err = json.Unmarshal(raw, &doc)
+	if err != nil {
+		return false, xerrors.Errorf("unmarshal input: %w", err)
+	}
+	results, err := p.query.Eval(ctx, rego.EvalInput(doc))
+	if err != nil {
+		return false, xerrors.Errorf("evaluate policy: %w", err)
+	}
+	return results.Allowed(), nil
+}
@@ -140,6 +140,51 @@ func (m *Manager) PublishUpdate() error {
 	return m.pubsub.Publish(PubsubEvent, []byte(m.id.String()))
 }
 
+// Drain marks the replica with the given ID as draining. The replica stops
+// accepting new tailnet connections and provisioner jobs once it syncs, which
+// is immediate since all replicas are notified. Draining an already draining
+// replica keeps the original time.
+func (m *Manager) Drain(ctx context.Context, id uuid.UUID) (database.Replica, error) {
+	// nolint:gocritic // Reading replicas is a system function.
+	replica, err := m.db.GetReplicaByID(dbauthz.AsSystemRestricted(ctx), id)
+	if err != nil {
+		return database.Replica{}, xerrors.Errorf("get replica: %w", err)
+	}
+	if !replica.Primary {
+		// Workspace proxy replicas aren't synced by the manager.
+		return database.Replica{}, xerrors.Errorf("replica is not primary: %w", sql.ErrNoRows)
+	}
+	if !replica.DrainingAt.Valid {
+		// nolint:gocritic // Updating a replica is a system function.
+		replica, err = m.db.UpdateReplicaDrainingAt(dbauthz.AsSystemRestricted(ctx), database.UpdateReplicaDrainingAtParams{
+			ID: id,
+			DrainingAt: sql.NullTime{
+				Time:  database.Now(),
+				Valid: true,
+			},
+		})
+		if err != nil {
+			return database.Replica{}, xerrors.Errorf("update replica: %w", err)
+		}
+	}
+	err = m.syncReplicas(ctx)
+	if err != nil {
+		return database.Replica{}, xerrors.Errorf("sync replicas: %w", err)
+	}
+	err = m.PublishUpdate()
+	if err != nil {
+		return database.Replica{}, xerrors.Errorf("publish replica update: %w", err)
+	}
+	return replica, nil
+}
+
+// Draining returns whether this replica is draining.
+func (m *Manager) Draining() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.self.DrainingAt.Valid
+}
+
 // updateInterval is used to determine a replicas state.
 // If the replica was updated > the time, it's considered healthy.
 // If the replica was updated < the time, it's considered stale.
@@ -359,6 +404,24 @@ func (m *Manager) AllPrimary() []database.Replica {
 	return replicas
 }
 
+// Leader returns whether this replica is the oldest primary replica. Work
+// that only one replica should do, like refreshing entitlements, is led by
+// it.
+func (m *Manager) Leader() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, replica := range m.peers {
+		if !replica.Primary {
+			continue
+		}
+		if replica.CreatedAt.Before(m.self.CreatedAt) ||
+			(replica.CreatedAt.Equal(m.self.CreatedAt) && replica.ID.String() < m.self.ID.String()) {
+			return false
+		}
+	}
+	return true
+}
+
 // InRegion returns every replica in the given DERP region excluding itself.
 func (m *Manager) InRegion(regionID int32) []database.Replica {
 	m.mutex.Lock()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"database/sql"
 	"net/http"
 	"net/http/httptest"
 	"sync"
@@ -47,6 +48,7 @@ func TestReplica(t *testing.T) {
 		server, err := replicasync.New(ctx, slogtest.Make(t, nil), db, pubsub, nil)
 		require.NoError(t, err)
 		<-closeChan
+		require.True(t, server.Leader())
 		_ = server.Close()
 		require.NoError(t, err)
 	})
@@ -80,6 +82,8 @@ func TestReplica(t *testing.T) {
 		require.Len(t, server.Regional(), 1)
 		require.Equal(t, peer.ID, server.Regional()[0].ID)
 		require.Empty(t, server.Self().Error)
+		// The peer is older, so it leads.
+		require.False(t, server.Leader())
 		_ = server.Close()
 	})
 	t.Run("ConnectsToPeerReplicaTLS", func(t *testing.T) {
@@ -187,6 +191,41 @@ func TestReplica(t *testing.T) {
 		}, testutil.WaitShort, testutil.IntervalFast)
 		_ = server.Close()
 	})
+	t.Run("Drain", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancelCtx := context.WithCancel(context.Background())
+		defer cancelCtx()
+		db := dbfake.New()
+		pubsub := pubsub.NewInMemory()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+		first, err := replicasync.New(ctx, slogtest.Make(t, nil), db, pubsub, &replicasync.Options{
+			RelayAddress: srv.URL,
+		})
+		require.NoError(t, err)
+		defer first.Close()
+		second, err := replicasync.New(ctx, slogtest.Make(t, nil), db, pubsub, &replicasync.Options{
+			RelayAddress: srv.URL,
+		})
+		require.NoError(t, err)
+		defer second.Close()
+
+		replica, err := first.Drain(ctx, second.ID())
+		require.NoError(t, err)
+		require.True(t, replica.DrainingAt.Valid)
+		require.False(t, first.Draining())
+		require.Eventually(t, second.Draining, testutil.WaitShort, testutil.IntervalFast)
+
+		// Draining again keeps the original time.
+		again, err := second.Drain(ctx, second.ID())
+		require.NoError(t, err)
+		require.Equal(t, replica.DrainingAt.Time, again.DrainingAt.Time)
+
+		_, err = first.Drain(ctx, uuid.New())
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
 	t.Run("DeletesOld", func(t *testing.T) {
 		t.Parallel()
 		db, pubsub := dbtestutil.NewDB(t)
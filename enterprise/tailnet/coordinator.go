@@ -142,6 +142,12 @@ func (c *haCoordinator) Node(id uuid.UUID) *agpl.Node {
 	return node
 }
 
+func (c *haCoordinator) AgentPeers(_ context.Context, agentID uuid.UUID) ([]agpl.AgentPeer, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return agpl.AgentPeersFromLocal(agentID, c.agentToConnectionSockets, c.nodes), nil
+}
+
 func (c *haCoordinator) clientLogger(id, agent uuid.UUID) slog.Logger {
 	return c.log.With(slog.F("client_id", id), slog.F("agent_id", agent))
 }
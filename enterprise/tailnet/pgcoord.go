@@ -102,6 +102,22 @@ var pgCoordSubject = rbac.Subject{
 // NewPGCoord creates a high-availability coordinator that stores state in the PostgreSQL database and
 // receives notifications of updates via the pubsub.
 func NewPGCoord(ctx context.Context, logger slog.Logger, ps pubsub.Pubsub, store database.Store) (agpl.Coordinator, error) {
+	return NewPGCoordWithHeartbeats(ctx, logger, ps, store, HeartbeatPeriod, MissedHeartbeats)
+}
+
+// NewPGCoordWithHeartbeats creates a PG coordinator that sends a heartbeat every heartbeatPeriod, and
+// considers other coordinators gone once they missed missedHeartbeats heartbeats. Zero values use
+// HeartbeatPeriod and MissedHeartbeats.
+func NewPGCoordWithHeartbeats(
+	ctx context.Context, logger slog.Logger, ps pubsub.Pubsub, store database.Store,
+	heartbeatPeriod time.Duration, missedHeartbeats int,
+) (agpl.Coordinator, error) {
+	if heartbeatPeriod <= 0 {
+		heartbeatPeriod = HeartbeatPeriod
+	}
+	if missedHeartbeats <= 0 {
+		missedHeartbeats = MissedHeartbeats
+	}
 	ctx, cancel := context.WithCancel(dbauthz.As(ctx, pgCoordSubject))
 	id := uuid.New()
 	logger = logger.Named("pgcoord").With(slog.F("coordinator_id", id))
@@ -120,7 +136,7 @@ func NewPGCoord(ctx context.Context, logger slog.Logger, ps pubsub.Pubsub, store
 		bindings:       bCh,
 		newConnections: cCh,
 		id:             id,
-		querier:        newQuerier(ctx, logger, ps, store, id, cCh, numQuerierWorkers, fHB),
+		querier:        newQuerier(ctx, logger, ps, store, id, cCh, numQuerierWorkers, fHB, heartbeatPeriod, missedHeartbeats),
 		closed:         make(chan struct{}),
 	}
 	logger.Info(ctx, "starting coordinator")
@@ -152,6 +168,27 @@ func (c *pgCoord) Node(id uuid.UUID) *agpl.Node {
 	return bestN
 }
 
+// AgentPeers queries the database, since clients of the agent may be
+// connected to other coordinators.
+func (c *pgCoord) AgentPeers(_ context.Context, agentID uuid.UUID) ([]agpl.AgentPeer, error) {
+	mappings, err := c.querier.queryClientsOfAgent(agentID)
+	if err != nil {
+		return nil, xerrors.Errorf("query clients of agent: %w", err)
+	}
+	mappings = c.querier.heartbeats.filter(mappings)
+	peers := make([]agpl.AgentPeer, 0, len(mappings))
+	for _, m := range mappings {
+		peers = append(peers, agpl.AgentPeer{
+			ID:   m.client,
+			Node: m.node,
+		})
+	}
+	slices.SortFunc(peers, func(a, b agpl.AgentPeer) int {
+		return slice.Ascending(a.ID.String(), b.ID.String())
+	})
+	return peers, nil
+}
+
 func (c *pgCoord) ServeClient(conn net.Conn, id uuid.UUID, agent uuid.UUID) error {
 	defer func() {
 		err := conn.Close()
@@ -613,7 +650,7 @@ func newQuerier(
 	ctx context.Context, logger slog.Logger,
 	ps pubsub.Pubsub, store database.Store,
 	self uuid.UUID, newConnections chan *connIO, numWorkers int,
-	firstHeartbeat chan<- struct{},
+	firstHeartbeat chan<- struct{}, heartbeatPeriod time.Duration, missedHeartbeats int,
 ) *querier {
 	updates := make(chan hbUpdate)
 	q := &querier{
@@ -623,7 +660,7 @@ func newQuerier(
 		store:          store,
 		newConnections: newConnections,
 		workQ:          newWorkQ[mKey](ctx),
-		heartbeats:     newHeartbeats(ctx, logger, ps, store, self, updates, firstHeartbeat),
+		heartbeats:     newHeartbeats(ctx, logger, ps, store, self, updates, firstHeartbeat, heartbeatPeriod, missedHeartbeats),
 		mappers:        make(map[mKey]*countedMapper),
 		conns:          make(map[*connIO]struct{}),
 		updates:        updates,
@@ -1188,6 +1225,8 @@ type heartbeats struct {
 	update           chan<- hbUpdate
 	firstHeartbeat   chan<- struct{}
 	failedHeartbeats int
+	period           time.Duration
+	missed           int
 
 	lock         sync.RWMutex
 	coordinators map[uuid.UUID]time.Time
@@ -1201,7 +1240,7 @@ func newHeartbeats(
 	ctx context.Context, logger slog.Logger,
 	ps pubsub.Pubsub, store database.Store,
 	self uuid.UUID, update chan<- hbUpdate,
-	firstHeartbeat chan<- struct{},
+	firstHeartbeat chan<- struct{}, period time.Duration, missed int,
 ) *heartbeats {
 	h := &heartbeats{
 		ctx:            ctx,
@@ -1211,6 +1250,8 @@ func newHeartbeats(
 		self:           self,
 		update:         update,
 		firstHeartbeat: firstHeartbeat,
+		period:         period,
+		missed:         missed,
 		coordinators:   make(map[uuid.UUID]time.Time),
 		cleanupPeriod:  cleanupPeriod,
 	}
@@ -1298,13 +1339,18 @@ func (h *heartbeats) recvBeat(id uuid.UUID) {
 
 	if h.timer == nil {
 		// this can only happen for the very first beat
-		h.timer = time.AfterFunc(MissedHeartbeats*HeartbeatPeriod, h.checkExpiry)
+		h.timer = time.AfterFunc(h.timeout(), h.checkExpiry)
 		h.logger.Debug(h.ctx, "set initial heartbeat timeout")
 		return
 	}
 	h.resetExpiryTimerWithLock()
 }
 
+// timeout is how long another coordinator may go without a heartbeat before it's considered gone.
+func (h *heartbeats) timeout() time.Duration {
+	return time.Duration(h.missed) * h.period
+}
+
 func (h *heartbeats) resetExpiryTimerWithLock() {
 	var oldestTime time.Time
 	for _, t := range h.coordinators {
@@ -1312,7 +1358,7 @@ func (h *heartbeats) resetExpiryTimerWithLock() {
 			oldestTime = t
 		}
 	}
-	d := time.Until(oldestTime.Add(MissedHeartbeats * HeartbeatPeriod))
+	d := time.Until(oldestTime.Add(h.timeout()))
 	h.logger.Debug(h.ctx, "computed oldest heartbeat", slog.F("oldest", oldestTime), slog.F("time_to_expiry", d))
 	// only reschedule if it's in the future.
 	if d > 0 {
@@ -1329,7 +1375,7 @@ func (h *heartbeats) checkExpiry() {
 	for id, t := range h.coordinators {
 		lastHB := now.Sub(t)
 		h.logger.Debug(h.ctx, "last heartbeat from coordinator", slog.F("other_coordinator_id", id), slog.F("last_heartbeat", lastHB))
-		if lastHB > MissedHeartbeats*HeartbeatPeriod {
+		if lastHB > h.timeout() {
 			expired = true
 			delete(h.coordinators, id)
 			h.logger.Info(h.ctx, "coordinator failed heartbeat check", slog.F("other_coordinator_id", id), slog.F("last_heartbeat", lastHB))
@@ -1350,7 +1396,7 @@ func (h *heartbeats) sendBeats() {
 	h.sendBeat()
 	close(h.firstHeartbeat) // signal binder it can start writing
 	defer h.sendDelete()
-	tkr := time.NewTicker(HeartbeatPeriod)
+	tkr := time.NewTicker(h.period)
 	defer tkr.Stop()
 	for {
 		select {
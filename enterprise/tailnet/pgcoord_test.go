@@ -312,6 +312,42 @@ func TestPGCoordinatorSingle_SendsHeartbeats(t *testing.T) {
 	}, testutil.WaitMedium, testutil.IntervalMedium)
 }
 
+func TestPGCoordinatorSingle_CustomHeartbeatPeriod(t *testing.T) {
+	t.Parallel()
+	if !dbtestutil.WillUsePostgres() {
+		t.Skip("test only with postgres")
+	}
+	store, ps := dbtestutil.NewDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.WaitSuperLong)
+	defer cancel()
+	logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+
+	const period = 5 * time.Second
+	mu := sync.Mutex{}
+	heartbeats := []time.Time{}
+	unsub, err := ps.SubscribeWithErr(tailnet.EventHeartbeats, func(_ context.Context, msg []byte, err error) {
+		assert.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		heartbeats = append(heartbeats, time.Now())
+	})
+	require.NoError(t, err)
+	defer unsub()
+
+	coordinator, err := tailnet.NewPGCoordWithHeartbeats(ctx, logger, ps, store, period, 2)
+	require.NoError(t, err)
+	defer coordinator.Close()
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(heartbeats) < 2 {
+			return false
+		}
+		return assert.Greater(t, heartbeats[1].Sub(heartbeats[0]), period*9/10)
+	}, testutil.WaitLong, testutil.IntervalMedium)
+}
+
 // TestPGCoordinatorDual_Mainline tests with 2 coordinators, one agent connected to each, and 2 clients per agent.
 //
 //	            +---------+
@@ -0,0 +1,178 @@
+package wsproxy
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/httpapi"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/site"
+)
+
+// affinityForwardedHeader is set on requests forwarded to a sibling replica so
+// the sibling serves them itself instead of forwarding them again.
+const affinityForwardedHeader = "X-Coder-Proxy-Affinity-Forwarded"
+
+// affinityRouter keeps a client's app traffic on the workspace proxy replica
+// that first served it. Load balancers are free to send requests to any
+// replica, so apps that keep state on the connection (e.g. WebSockets that
+// reconnect) break when the load balancer switches replicas mid-session.
+//
+// The first replica to serve an app request sets a signed cookie naming
+// itself. Any other replica receiving a request with that cookie forwards the
+// request to the named replica over its relay address.
+type affinityRouter struct {
+	logger           slog.Logger
+	replicaID        uuid.UUID
+	hostnameRegex    *regexp.Regexp
+	secureCookie     bool
+	dashboardURL     *url.URL
+	forwardTransport http.RoundTripper
+
+	mu       sync.RWMutex
+	key      workspaceapps.SecurityKey
+	siblings map[uuid.UUID]*url.URL
+}
+
+func newAffinityRouter(logger slog.Logger, replicaID uuid.UUID, opts *Options, tlsConfig *tls.Config) *affinityRouter {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &affinityRouter{
+		logger:           logger,
+		replicaID:        replicaID,
+		hostnameRegex:    opts.AppHostnameRegex,
+		secureCookie:     opts.SecureAuthCookie,
+		dashboardURL:     opts.DashboardURL,
+		forwardTransport: transport,
+		siblings:         map[uuid.UUID]*url.URL{},
+	}
+}
+
+// update sets the key cookies are signed with and replaces the replicas
+// requests can be forwarded to. Replicas without a valid relay address are
+// skipped, and requests pinned to them are served locally instead.
+func (a *affinityRouter) update(ctx context.Context, key workspaceapps.SecurityKey, replicas []codersdk.Replica) {
+	siblings := make(map[uuid.UUID]*url.URL, len(replicas))
+	for _, replica := range replicas {
+		if replica.RelayAddress == "" {
+			continue
+		}
+		relayURL, err := url.Parse(replica.RelayAddress)
+		if err != nil || relayURL.Host == "" {
+			a.logger.Warn(ctx, "invalid relay address for sibling replica",
+				slog.F("replica_id", replica.ID),
+				slog.F("relay_address", replica.RelayAddress),
+				slog.Error(err),
+			)
+			continue
+		}
+		siblings[replica.ID] = relayURL
+	}
+
+	a.mu.Lock()
+	a.key = key
+	a.siblings = siblings
+	a.mu.Unlock()
+}
+
+// Middleware forwards app requests pinned to a sibling replica and pins all
+// other app requests to this replica.
+func (a *affinityRouter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if !a.isAppRequest(r) {
+			next.ServeHTTP(rw, r)
+			return
+		}
+		if r.Header.Get(affinityForwardedHeader) != "" {
+			// Another replica already routed this request here.
+			r.Header.Del(affinityForwardedHeader)
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		a.mu.RLock()
+		key, siblings := a.key, a.siblings
+		a.mu.RUnlock()
+
+		if cookie, err := r.Cookie(codersdk.ProxyAffinityCookie); err == nil {
+			replicaID, err := key.VerifyAffinity(cookie.Value)
+			if err == nil && replicaID == a.replicaID {
+				next.ServeHTTP(rw, r)
+				return
+			}
+			if relayURL, ok := siblings[replicaID]; err == nil && ok {
+				a.forward(rw, r, key, replicaID, relayURL)
+				return
+			}
+		}
+
+		// Either there is no cookie, or the replica it names is gone, so
+		// this replica takes over the session.
+		a.setCookie(rw, key)
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func (a *affinityRouter) forward(rw http.ResponseWriter, r *http.Request, key workspaceapps.SecurityKey, replicaID uuid.UUID, relayURL *url.URL) {
+	proxy := httputil.NewSingleHostReverseProxy(relayURL)
+	proxy.Transport = a.forwardTransport
+	director := proxy.Director
+	proxy.Director = func(req *http.Request) {
+		director(req)
+		// Keep the original host so the sibling can resolve subdomain apps.
+		req.Host = r.Host
+		req.Header.Set(affinityForwardedHeader, a.replicaID.String())
+	}
+	proxy.ErrorHandler = func(rw http.ResponseWriter, r *http.Request, err error) {
+		a.logger.Warn(r.Context(), "failed to forward app request to pinned replica",
+			slog.F("replica_id", replicaID),
+			slog.F("relay_address", relayURL.String()),
+			slog.Error(err),
+		)
+		// Pin the client to this replica so the next attempt doesn't hit the
+		// unreachable replica again.
+		a.setCookie(rw, key)
+		site.RenderStaticErrorPage(rw, r, site.ErrorPageData{
+			Status:       http.StatusBadGateway,
+			Title:        "Bad Gateway",
+			Description:  "The workspace proxy replica serving this session could not be reached. Retrying will use another replica.",
+			RetryEnabled: true,
+			DashboardURL: a.dashboardURL.String(),
+		})
+	}
+	proxy.ServeHTTP(rw, r)
+}
+
+func (a *affinityRouter) setCookie(rw http.ResponseWriter, key workspaceapps.SecurityKey) {
+	http.SetCookie(rw, &http.Cookie{
+		Name:     codersdk.ProxyAffinityCookie,
+		Value:    key.SignAffinity(a.replicaID),
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   a.secureCookie,
+	})
+}
+
+// isAppRequest returns true if the request is for a subdomain app, a path app
+// or a terminal, which are the only requests tied to a session.
+func (a *affinityRouter) isAppRequest(r *http.Request) bool {
+	if a.hostnameRegex != nil {
+		if _, ok := httpapi.ExecuteHostnamePattern(a.hostnameRegex, r.Host); ok {
+			return true
+		}
+	}
+	return strings.HasPrefix(r.URL.Path, "/@") ||
+		strings.HasPrefix(r.URL.Path, "/%40") ||
+		strings.HasPrefix(r.URL.Path, "/api/v2/workspaceagents/")
+}
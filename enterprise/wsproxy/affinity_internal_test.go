@@ -0,0 +1,172 @@
+package wsproxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/coderdtest"
+	"github.com/coder/coder/v2/codersdk"
+)
+
+func TestAffinityRouter(t *testing.T) {
+	t.Parallel()
+
+	const appPath = "/@user/workspace.agent/apps/app/"
+
+	// newReplica returns a router and a server that answers with the given
+	// name for every request that passes through the router.
+	newReplica := func(t *testing.T, name string) (*affinityRouter, *httptest.Server) {
+		router := newAffinityRouter(slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}), uuid.New(), &Options{
+			DashboardURL: &url.URL{Scheme: "http", Host: "dashboard.test"},
+		}, nil)
+		router.update(context.Background(), coderdtest.AppSecurityKey, nil)
+		srv := httptest.NewServer(router.Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			require.Empty(t, r.Header.Get(affinityForwardedHeader))
+			_, _ = rw.Write([]byte(name))
+		})))
+		t.Cleanup(srv.Close)
+		return router, srv
+	}
+
+	do := func(t *testing.T, srv *httptest.Server, path string, cookie *http.Cookie) *http.Response {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+path, nil)
+		require.NoError(t, err)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		res, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = res.Body.Close()
+		})
+		return res
+	}
+
+	affinityCookie := func(res *http.Response) *http.Cookie {
+		for _, cookie := range res.Cookies() {
+			if cookie.Name == codersdk.ProxyAffinityCookie {
+				return cookie
+			}
+		}
+		return nil
+	}
+
+	body := func(t *testing.T, res *http.Response) string {
+		data, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("Pins", func(t *testing.T) {
+		t.Parallel()
+		routerA, srvA := newReplica(t, "a")
+
+		res := do(t, srvA, appPath, nil)
+		require.Equal(t, "a", body(t, res))
+		cookie := affinityCookie(res)
+		require.NotNil(t, cookie)
+		replicaID, err := coderdtest.AppSecurityKey.VerifyAffinity(cookie.Value)
+		require.NoError(t, err)
+		require.Equal(t, routerA.replicaID, replicaID)
+
+		// Requests pinned to the replica serving them don't reset the cookie.
+		res = do(t, srvA, appPath, cookie)
+		require.Equal(t, "a", body(t, res))
+		require.Nil(t, affinityCookie(res))
+	})
+
+	t.Run("Forwards", func(t *testing.T) {
+		t.Parallel()
+		routerA, srvA := newReplica(t, "a")
+		routerB, srvB := newReplica(t, "b")
+		routerA.update(context.Background(), coderdtest.AppSecurityKey, []codersdk.Replica{{
+			ID:           routerB.replicaID,
+			RelayAddress: srvB.URL,
+		}})
+
+		res := do(t, srvB, appPath, nil)
+		require.Equal(t, "b", body(t, res))
+		cookie := affinityCookie(res)
+		require.NotNil(t, cookie)
+
+		res = do(t, srvA, appPath, cookie)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "b", body(t, res))
+		require.Nil(t, affinityCookie(res))
+	})
+
+	t.Run("UnknownReplica", func(t *testing.T) {
+		t.Parallel()
+		routerA, srvA := newReplica(t, "a")
+
+		// The replica the cookie names is gone, so the request is served
+		// locally and the client is pinned to this replica.
+		res := do(t, srvA, appPath, &http.Cookie{
+			Name:  codersdk.ProxyAffinityCookie,
+			Value: coderdtest.AppSecurityKey.SignAffinity(uuid.New()),
+		})
+		require.Equal(t, "a", body(t, res))
+		cookie := affinityCookie(res)
+		require.NotNil(t, cookie)
+		replicaID, err := coderdtest.AppSecurityKey.VerifyAffinity(cookie.Value)
+		require.NoError(t, err)
+		require.Equal(t, routerA.replicaID, replicaID)
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		t.Parallel()
+		routerA, srvA := newReplica(t, "a")
+		routerB, srvB := newReplica(t, "b")
+		routerA.update(context.Background(), coderdtest.AppSecurityKey, []codersdk.Replica{{
+			ID:           routerB.replicaID,
+			RelayAddress: srvB.URL,
+		}})
+
+		// Clients can't pick a replica by forging the cookie.
+		res := do(t, srvA, appPath, &http.Cookie{
+			Name:  codersdk.ProxyAffinityCookie,
+			Value: routerB.replicaID.String() + ".forged",
+		})
+		require.Equal(t, "a", body(t, res))
+		require.NotNil(t, affinityCookie(res))
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		t.Parallel()
+		routerA, srvA := newReplica(t, "a")
+		routerB, srvB := newReplica(t, "b")
+		routerA.update(context.Background(), coderdtest.AppSecurityKey, []codersdk.Replica{{
+			ID:           routerB.replicaID,
+			RelayAddress: srvB.URL,
+		}})
+		srvB.Close()
+
+		res := do(t, srvA, appPath, &http.Cookie{
+			Name:  codersdk.ProxyAffinityCookie,
+			Value: coderdtest.AppSecurityKey.SignAffinity(routerB.replicaID),
+		})
+		require.Equal(t, http.StatusBadGateway, res.StatusCode)
+		cookie := affinityCookie(res)
+		require.NotNil(t, cookie)
+		replicaID, err := coderdtest.AppSecurityKey.VerifyAffinity(cookie.Value)
+		require.NoError(t, err)
+		require.Equal(t, routerA.replicaID, replicaID)
+	})
+
+	t.Run("NotApp", func(t *testing.T) {
+		t.Parallel()
+		_, srvA := newReplica(t, "a")
+
+		res := do(t, srvA, "/healthz", nil)
+		require.Equal(t, "a", body(t, res))
+		require.Nil(t, affinityCookie(res))
+	})
+}
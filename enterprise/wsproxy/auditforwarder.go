@@ -0,0 +1,148 @@
+package wsproxy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/enterprise/wsproxy/wsproxysdk"
+)
+
+const (
+	// auditForwarderBufferSize is the number of access events buffered while
+	// the primary is unreachable. Once full, the oldest events are dropped.
+	auditForwarderBufferSize = 10_000
+	// auditForwarderBatchSize is the maximum number of access events sent to
+	// the primary in a single request.
+	auditForwarderBatchSize = 100
+	// auditForwarderInterval is how often buffered access events are sent to
+	// the primary. Failed batches are retried on the next interval.
+	auditForwarderInterval = 5 * time.Second
+)
+
+var _ workspaceapps.AccessAuditor = (*auditForwarder)(nil)
+
+// auditForwarder buffers workspace app and terminal access events and
+// forwards them to the primary in batches, so they're recorded in the audit
+// log even if the primary is briefly unreachable.
+type auditForwarder struct {
+	logger   slog.Logger
+	report   func(context.Context, []workspaceapps.AccessEvent) error
+	interval time.Duration
+
+	mu     sync.Mutex
+	events []workspaceapps.AccessEvent
+
+	flush  chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newAuditForwarder(logger slog.Logger, client *wsproxysdk.Client) *auditForwarder {
+	return startAuditForwarder(logger, auditForwarderInterval, func(ctx context.Context, events []workspaceapps.AccessEvent) error {
+		return client.ReportAccessEvents(ctx, wsproxysdk.ReportAccessEventsRequest{
+			Events: events,
+		})
+	})
+}
+
+func startAuditForwarder(logger slog.Logger, interval time.Duration, report func(context.Context, []workspaceapps.AccessEvent) error) *auditForwarder {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &auditForwarder{
+		logger:   logger,
+		report:   report,
+		interval: interval,
+		flush:    make(chan struct{}, 1),
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go f.run()
+	return f
+}
+
+// AuditAccess buffers the event for forwarding. It never blocks.
+func (f *auditForwarder) AuditAccess(ctx context.Context, event workspaceapps.AccessEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.events) >= auditForwarderBufferSize {
+		f.logger.Warn(ctx, "access event buffer is full, dropping oldest event",
+			slog.F("event_id", f.events[0].ID),
+		)
+		f.events = f.events[1:]
+	}
+	f.events = append(f.events, event)
+
+	if len(f.events) >= auditForwarderBatchSize {
+		select {
+		case f.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (f *auditForwarder) run() {
+	defer close(f.done)
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-f.flush:
+		}
+		err := f.forward(f.ctx)
+		if err != nil && f.ctx.Err() == nil {
+			f.logger.Warn(f.ctx, "forward access events to primary, will retry", slog.Error(err))
+		}
+	}
+}
+
+// forward sends all buffered events to the primary. Events of a batch that
+// fails to send are returned to the buffer.
+func (f *auditForwarder) forward(ctx context.Context) error {
+	for {
+		f.mu.Lock()
+		n := len(f.events)
+		if n == 0 {
+			f.mu.Unlock()
+			return nil
+		}
+		if n > auditForwarderBatchSize {
+			n = auditForwarderBatchSize
+		}
+		batch := f.events[:n:n]
+		f.events = f.events[n:]
+		f.mu.Unlock()
+
+		err := f.report(ctx, batch)
+		if err != nil {
+			f.mu.Lock()
+			events := make([]workspaceapps.AccessEvent, 0, len(batch)+len(f.events))
+			events = append(events, batch...)
+			events = append(events, f.events...)
+			if len(events) > auditForwarderBufferSize {
+				events = events[len(events)-auditForwarderBufferSize:]
+			}
+			f.events = events
+			f.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// Close stops forwarding and makes a final attempt to send buffered events.
+func (f *auditForwarder) Close() error {
+	f.cancel()
+	<-f.done
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return f.forward(ctx)
+}
@@ -0,0 +1,83 @@
+package wsproxy
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
+
+	"cdr.dev/slog/sloggers/slogtest"
+	"github.com/coder/coder/v2/coderd/workspaceapps"
+	"github.com/coder/coder/v2/testutil"
+)
+
+func TestAuditForwarder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("RetriesUntilDelivered", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mu        sync.Mutex
+			available bool
+			delivered []workspaceapps.AccessEvent
+		)
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		f := startAuditForwarder(logger, 10*time.Millisecond, func(_ context.Context, events []workspaceapps.AccessEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if !available {
+				return xerrors.New("primary unavailable")
+			}
+			delivered = append(delivered, events...)
+			return nil
+		})
+		t.Cleanup(func() { _ = f.Close() })
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		first := workspaceapps.AccessEvent{ID: uuid.New()}
+		second := workspaceapps.AccessEvent{ID: uuid.New()}
+		f.AuditAccess(ctx, first)
+		f.AuditAccess(ctx, second)
+
+		// Let a few attempts fail before the primary comes back.
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		require.Empty(t, delivered)
+		available = true
+		mu.Unlock()
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(delivered) == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+		mu.Lock()
+		defer mu.Unlock()
+		require.Equal(t, first.ID, delivered[0].ID)
+		require.Equal(t, second.ID, delivered[1].ID)
+	})
+
+	t.Run("FlushesOnClose", func(t *testing.T) {
+		t.Parallel()
+
+		var delivered []workspaceapps.AccessEvent
+		logger := slogtest.Make(t, &slogtest.Options{IgnoreErrors: true})
+		f := startAuditForwarder(logger, time.Hour, func(_ context.Context, events []workspaceapps.AccessEvent) error {
+			delivered = append(delivered, events...)
+			return nil
+		})
+
+		ctx := testutil.Context(t, testutil.WaitShort)
+		for i := 0; i < auditForwarderBatchSize*2+1; i++ {
+			f.AuditAccess(ctx, workspaceapps.AccessEvent{ID: uuid.New()})
+		}
+		err := f.Close()
+		require.NoError(t, err)
+		require.Len(t, delivered, auditForwarderBatchSize*2+1)
+	})
+}
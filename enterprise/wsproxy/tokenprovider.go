@@ -4,6 +4,12 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 
 	"cdr.dev/slog"
 
@@ -11,6 +17,10 @@ import (
 	"github.com/coder/coder/v2/enterprise/wsproxy/wsproxysdk"
 )
 
+// appTokenKeysRefreshInterval is how often the keys verifying signed app
+// tokens are fetched from the primary.
+const appTokenKeysRefreshInterval = 10 * time.Minute
+
 var _ workspaceapps.SignedTokenProvider = (*TokenProvider)(nil)
 
 type TokenProvider struct {
@@ -18,13 +28,25 @@ type TokenProvider struct {
 	AccessURL    *url.URL
 	AppHostname  string
 
+	// ProxyID is the ID of this proxy. Signed app tokens issued to other
+	// proxies are rejected.
+	ProxyID     uuid.UUID
 	Client      *wsproxysdk.Client
 	SecurityKey workspaceapps.SecurityKey
 	Logger      slog.Logger
+
+	keysMu        sync.Mutex
+	keys          jose.JSONWebKeySet
+	keysFetchedAt time.Time
 }
 
 func (p *TokenProvider) FromRequest(r *http.Request) (*workspaceapps.SignedToken, bool) {
-	return workspaceapps.FromRequest(r, p.SecurityKey)
+	keys, err := p.appTokenKeys(r.Context())
+	if err != nil {
+		p.Logger.Warn(r.Context(), "fetch app token keys", slog.Error(err))
+		return nil, false
+	}
+	return workspaceapps.ProxyTokenFromRequest(r, keys, p.ProxyID.String())
 }
 
 func (p *TokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *http.Request, issueReq workspaceapps.IssueTokenRequest) (*workspaceapps.SignedToken, string, bool) {
@@ -42,17 +64,45 @@ func (p *TokenProvider) Issue(ctx context.Context, rw http.ResponseWriter, r *ht
 	}
 
 	// Check that it verifies properly and matches the string.
-	token, err := p.SecurityKey.VerifySignedToken(resp.SignedTokenStr)
+	keys, err := p.appTokenKeys(ctx)
+	if err != nil {
+		workspaceapps.WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "failed to fetch app token keys")
+		return nil, "", false
+	}
+	token, err := workspaceapps.VerifyProxyToken(keys, p.ProxyID.String(), resp.SignedTokenStr)
 	if err != nil {
 		workspaceapps.WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "failed to verify newly generated signed token")
 		return nil, "", false
 	}
 
-	// Check that it matches the request.
-	if !token.MatchesRequest(appReq) {
+	// Check that it matches the request and is scoped to its path.
+	if !token.MatchesRequest(appReq) || !token.Scope.AllowsPath(r.URL.Path) {
 		workspaceapps.WriteWorkspaceApp500(p.Logger, p.DashboardURL, rw, r, &appReq, err, "newly generated signed token does not match request")
 		return nil, "", false
 	}
 
 	return &token, resp.SignedTokenStr, true
 }
+
+// appTokenKeys returns the keys verifying signed app tokens, fetching them
+// from the primary if they are missing or stale.
+func (p *TokenProvider) appTokenKeys(ctx context.Context) (jose.JSONWebKeySet, error) {
+	p.keysMu.Lock()
+	defer p.keysMu.Unlock()
+
+	if len(p.keys.Keys) > 0 && time.Since(p.keysFetchedAt) < appTokenKeysRefreshInterval {
+		return p.keys, nil
+	}
+
+	keys, err := p.Client.AppTokenKeys(ctx)
+	if err != nil {
+		if len(p.keys.Keys) > 0 {
+			// Keep using the previous keys until the primary is reachable.
+			return p.keys, nil
+		}
+		return jose.JSONWebKeySet{}, xerrors.Errorf("get app token keys: %w", err)
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	return p.keys, nil
+}
@@ -11,6 +11,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -36,6 +37,7 @@ import (
 	"github.com/coder/coder/v2/enterprise/wsproxy/wsproxysdk"
 	"github.com/coder/coder/v2/site"
 	"github.com/coder/coder/v2/tailnet"
+	"github.com/coder/retry"
 )
 
 type Options struct {
@@ -74,6 +76,15 @@ type Options struct {
 	// provide access to workspace apps/terminal.
 	DERPOnly bool
 
+	// AppAssetCacheSize is the maximum size in bytes of the cache of immutable
+	// static assets served by workspace apps. Caching is disabled if zero.
+	AppAssetCacheSize int
+
+	// AgentConnectionCacheMaxSize and AgentConnectionCacheMaxAge bound the
+	// cache of connections to workspace agents. Zero means no limit.
+	AgentConnectionCacheMaxSize int
+	AgentConnectionCacheMaxAge  time.Duration
+
 	ProxySessionToken string
 	// AllowAllCors will set all CORs headers to '*'.
 	// By default, CORs is set to accept external requests
@@ -119,7 +130,15 @@ type Server struct {
 	SDKClient *wsproxysdk.Client
 
 	// DERP
-	derpMesh *derpmesh.Mesh
+	derpMesh  *derpmesh.Mesh
+	derpUsage *tailnet.DERPUsage
+	// affinity keeps a client's app traffic on a single replica.
+	affinity *affinityRouter
+	// auditForwarder forwards app and terminal accesses to the primary.
+	auditForwarder *auditForwarder
+	// config is the latest configuration received from the primary.
+	config     atomic.Pointer[wsproxysdk.ProxyConfig]
+	configDone chan struct{}
 
 	// Used for graceful shutdown. Required for the dialer.
 	ctx           context.Context
@@ -198,11 +217,13 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		derpMesh:           derpmesh.New(opts.Logger.Named("net.derpmesh"), derpServer, meshTLSConfig),
 		ctx:                ctx,
 		cancel:             cancel,
+		configDone:         make(chan struct{}),
 	}
 
 	// Register the workspace proxy with the primary coderd instance and start a
 	// goroutine to periodically re-register.
 	replicaID := uuid.New()
+	s.affinity = newAffinityRouter(s.Logger.Named("affinity"), replicaID, opts, meshTLSConfig)
 	osHostname, err := os.Hostname()
 	if err != nil {
 		return nil, xerrors.Errorf("get OS hostname: %w", err)
@@ -228,6 +249,16 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		return nil, xerrors.Errorf("register proxy: %w", err)
 	}
 	s.registerDone = registerDone
+	s.derpUsage = tailnet.NewDERPUsage(int(regResp.DERPRegionID))
+	err = s.PrometheusRegistry.Register(s.derpUsage)
+	if err != nil {
+		s.Logger.Warn(ctx, "register DERP usage metrics", slog.Error(err))
+	}
+	s.derpMesh.TrackUsage(s.derpUsage)
+	err = s.PrometheusRegistry.Register(s.derpMesh)
+	if err != nil {
+		s.Logger.Warn(ctx, "register DERP mesh metrics", slog.Error(err))
+	}
 	err = s.handleRegister(ctx, regResp)
 	if err != nil {
 		return nil, xerrors.Errorf("handle register: %w", err)
@@ -245,13 +276,18 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 	}
 
 	var agentProvider workspaceapps.AgentProvider
+	agentConnCacheOptions := wsconncache.Options{
+		MaxSize:    opts.AgentConnectionCacheMaxSize,
+		MaxAge:     opts.AgentConnectionCacheMaxAge,
+		Registerer: s.PrometheusRegistry,
+	}
 	if opts.Experiments.Enabled(codersdk.ExperimentSingleTailnet) {
 		stn, err := coderd.NewServerTailnet(ctx,
 			s.Logger,
 			nil,
 			connInfo.DERPMap,
 			s.DialCoordinator,
-			wsconncache.New(s.DialWorkspaceAgent, 0),
+			wsconncache.NewWithOptions(s.DialWorkspaceAgent, agentConnCacheOptions),
 			s.TracerProvider,
 		)
 		if err != nil {
@@ -260,7 +296,7 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		agentProvider = stn
 	} else {
 		agentProvider = &wsconncache.AgentProvider{
-			Cache: wsconncache.New(s.DialWorkspaceAgent, 0),
+			Cache: wsconncache.NewWithOptions(s.DialWorkspaceAgent, agentConnCacheOptions),
 		}
 	}
 
@@ -273,6 +309,12 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		opts.StatsCollectorOptions.Reporter = &appStatsReporter{Client: client}
 	}
 
+	var assetCache *workspaceapps.AssetCache
+	if opts.AppAssetCacheSize > 0 {
+		assetCache = workspaceapps.NewAssetCache(opts.AppAssetCacheSize)
+	}
+
+	s.auditForwarder = newAuditForwarder(workspaceAppsLogger.Named("audit_forwarder"), client)
 	s.AppServer = &workspaceapps.Server{
 		Logger:        workspaceAppsLogger,
 		DashboardURL:  opts.DashboardURL,
@@ -284,6 +326,7 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 			DashboardURL: opts.DashboardURL,
 			AccessURL:    opts.AccessURL,
 			AppHostname:  opts.AppHostname,
+			ProxyID:      regResp.ProxyID,
 			Client:       client,
 			SecurityKey:  secKey,
 			Logger:       s.Logger.Named("proxy_token_provider"),
@@ -293,12 +336,17 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		DisablePathApps:  opts.DisablePathApps,
 		SecureAuthCookie: opts.SecureAuthCookie,
 
+		DynamicOptions: s.appServerOptions,
+
 		AgentProvider:  agentProvider,
 		StatsCollector: workspaceapps.NewStatsCollector(opts.StatsCollectorOptions),
+		AssetCache:     assetCache,
+		AccessAuditor:  s.auditForwarder,
 	}
 
 	derpHandler := derphttp.Handler(derpServer)
 	derpHandler, s.derpCloseFunc = tailnet.WithWebsocketSupport(derpServer, derpHandler)
+	derpHandler = s.derpUsage.Handler(derpHandler)
 
 	// The primary coderd dashboard needs to make some GET requests to
 	// the workspace proxies to check latency.
@@ -319,6 +367,9 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 		prometheusMW,
 		corsMW,
 
+		// Forward app requests pinned to another replica before they are
+		// handled here.
+		s.affinity.Middleware,
 		// HandleSubdomain is a middleware that handles all requests to the
 		// subdomain-based workspace apps.
 		s.AppServer.HandleSubdomain(apiRateLimiter),
@@ -392,6 +443,7 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 	}
 
 	r.Get("/api/v2/buildinfo", s.buildInfo)
+	r.Get("/api/v2/appearance", s.appearance)
 	r.Get("/healthz", func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("OK")) })
 	// TODO: @emyrk should this be authenticated or debounced?
 	r.Get("/healthz-report", s.healthReport)
@@ -414,6 +466,7 @@ func New(ctx context.Context, opts *Options) (*Server, error) {
 	rootRouter.Mount("/", r)
 	s.Handler = rootRouter
 
+	go s.watchConfig()
 	return s, nil
 }
 
@@ -427,6 +480,7 @@ func (s *Server) Close() error {
 		err = multierror.Append(err, xerrors.New("timed out waiting for registerDone"))
 	case <-s.registerDone:
 	}
+	<-s.configDone
 	s.derpCloseFunc()
 	appServerErr := s.AppServer.Close()
 	if appServerErr != nil {
@@ -436,6 +490,10 @@ func (s *Server) Close() error {
 	if agentProviderErr != nil {
 		err = multierror.Append(err, agentProviderErr)
 	}
+	auditForwarderErr := s.auditForwarder.Close()
+	if auditForwarderErr != nil {
+		err = multierror.Append(err, auditForwarderErr)
+	}
 	s.SDKClient.SDKClient.HTTPClient.CloseIdleConnections()
 	return err
 }
@@ -449,16 +507,70 @@ func (*Server) mutateRegister(_ *wsproxysdk.RegisterWorkspaceProxyRequest) {
 	// package in the primary and update req.ReplicaError accordingly.
 }
 
-func (s *Server) handleRegister(_ context.Context, res wsproxysdk.RegisterWorkspaceProxyResponse) error {
+func (s *Server) handleRegister(ctx context.Context, res wsproxysdk.RegisterWorkspaceProxyResponse) error {
 	addresses := make([]string, len(res.SiblingReplicas))
 	for i, replica := range res.SiblingReplicas {
 		addresses[i] = replica.RelayAddress
 	}
 	s.derpMesh.SetAddresses(addresses, false)
 
+	secKey, err := workspaceapps.KeyFromString(res.AppSecurityKey)
+	if err != nil {
+		return xerrors.Errorf("parse app security key: %w", err)
+	}
+	s.affinity.update(ctx, secKey, res.SiblingReplicas)
+	s.setConfig(res.Config)
+
 	return nil
 }
 
+// watchConfig applies the configuration pushed by the primary until the
+// server is closed. Registration also returns the configuration, so pushes
+// missed while reconnecting are applied on the next registration at the
+// latest.
+func (s *Server) watchConfig() {
+	defer close(s.configDone)
+	for r := retry.New(time.Second, 30*time.Second); r.Wait(s.ctx); {
+		configs, err := s.SDKClient.WatchWorkspaceProxyConfig(s.ctx)
+		if err != nil {
+			if s.ctx.Err() == nil {
+				s.Logger.Warn(s.ctx, "watch config of primary", slog.Error(err))
+			}
+			continue
+		}
+		r.Reset()
+		for config := range configs {
+			s.setConfig(config)
+		}
+	}
+}
+
+func (s *Server) setConfig(config wsproxysdk.ProxyConfig) {
+	old := s.config.Swap(&config)
+	if old != nil && !reflect.DeepEqual(*old, config) {
+		s.Logger.Info(s.ctx, "applied updated config from primary")
+	}
+}
+
+func (s *Server) appServerOptions() workspaceapps.DynamicOptions {
+	config := s.config.Load()
+	if config == nil {
+		return workspaceapps.DynamicOptions{}
+	}
+	return workspaceapps.DynamicOptions{
+		DisablePathApps:     config.DisablePathApps,
+		SessionCookieMaxAge: config.SessionDuration,
+	}
+}
+
+func (s *Server) appearance(rw http.ResponseWriter, r *http.Request) {
+	var appearance codersdk.AppearanceConfig
+	if config := s.config.Load(); config != nil {
+		appearance = config.Appearance
+	}
+	httpapi.Write(r.Context(), rw, http.StatusOK, appearance)
+}
+
 func (s *Server) handleRegisterFailure(err error) {
 	if s.ctx.Err() != nil {
 		return
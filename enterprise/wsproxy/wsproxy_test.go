@@ -1,6 +1,7 @@
 package wsproxy_test
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"testing"
@@ -383,8 +384,8 @@ func TestDERPEndToEnd(t *testing.T) {
 	// Swap out the DERPMapper for a fake one that only returns the proxy. This
 	// allows us to force the agent to pick the proxy as its preferred region.
 	oldDERPMapper := *api.AGPL.DERPMapper.Load()
-	newDERPMapper := func(derpMap *tailcfg.DERPMap) *tailcfg.DERPMap {
-		derpMap = oldDERPMapper(derpMap)
+	newDERPMapper := func(ctx context.Context, derpMap *tailcfg.DERPMap) *tailcfg.DERPMap {
+		derpMap = oldDERPMapper(ctx, derpMap)
 		// Strip everything but the proxy, which is region ID 10001.
 		derpMap.Regions = map[int]*tailcfg.DERPRegion{
 			10001: derpMap.Regions[10001],
@@ -459,6 +460,8 @@ func TestWorkspaceProxyWorkspaceApps_Wsconncache(t *testing.T) {
 		deploymentValues.DisablePathApps = clibase.Bool(opts.DisablePathApps)
 		deploymentValues.Dangerous.AllowPathAppSharing = clibase.Bool(opts.DangerousAllowPathAppSharing)
 		deploymentValues.Dangerous.AllowPathAppSiteOwnerAccess = clibase.Bool(opts.DangerousAllowPathAppSiteOwnerAccess)
+		deploymentValues.AppIdentityHeaders = opts.AppIdentityHeaders
+		deploymentValues.PortForwardIdentityHeaders = opts.PortForwardIdentityHeaders
 		deploymentValues.Experiments = []string{
 			string(codersdk.ExperimentMoons),
 			"*",
@@ -517,6 +520,8 @@ func TestWorkspaceProxyWorkspaceApps_SingleTailnet(t *testing.T) {
 		deploymentValues.DisablePathApps = clibase.Bool(opts.DisablePathApps)
 		deploymentValues.Dangerous.AllowPathAppSharing = clibase.Bool(opts.DangerousAllowPathAppSharing)
 		deploymentValues.Dangerous.AllowPathAppSiteOwnerAccess = clibase.Bool(opts.DangerousAllowPathAppSiteOwnerAccess)
+		deploymentValues.AppIdentityHeaders = opts.AppIdentityHeaders
+		deploymentValues.PortForwardIdentityHeaders = opts.PortForwardIdentityHeaders
 		deploymentValues.Experiments = []string{
 			string(codersdk.ExperimentMoons),
 			string(codersdk.ExperimentSingleTailnet),
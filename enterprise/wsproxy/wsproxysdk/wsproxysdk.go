@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/go-jose/go-jose/v3"
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 	"nhooyr.io/websocket"
@@ -152,6 +153,23 @@ func (c *Client) IssueSignedAppTokenHTML(ctx context.Context, rw http.ResponseWr
 	return res, true
 }
 
+// AppTokenKeys returns the public keys that verify the signed app tokens
+// issued to the proxy, as a JSON Web Key Set.
+func (c *Client) AppTokenKeys(ctx context.Context) (jose.JSONWebKeySet, error) {
+	resp, err := c.Request(ctx, http.MethodGet, "/api/v2/workspaceproxies/me/jwks", nil)
+	if err != nil {
+		return jose.JSONWebKeySet{}, xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return jose.JSONWebKeySet{}, codersdk.ReadBodyAsError(resp)
+	}
+
+	var keys jose.JSONWebKeySet
+	return keys, json.NewDecoder(resp.Body).Decode(&keys)
+}
+
 type ReportAppStatsRequest struct {
 	Stats []workspaceapps.StatsReport `json:"stats"`
 }
@@ -171,6 +189,26 @@ func (c *Client) ReportAppStats(ctx context.Context, req ReportAppStatsRequest)
 	return nil
 }
 
+type ReportAccessEventsRequest struct {
+	Events []workspaceapps.AccessEvent `json:"events"`
+}
+
+// ReportAccessEvents reports the given workspace app and terminal accesses to
+// the primary coder server to be recorded in the audit log.
+func (c *Client) ReportAccessEvents(ctx context.Context, req ReportAccessEventsRequest) error {
+	resp, err := c.Request(ctx, http.MethodPost, "/api/v2/workspaceproxies/me/access-events", req)
+	if err != nil {
+		return xerrors.Errorf("make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return codersdk.ReadBodyAsError(resp)
+	}
+
+	return nil
+}
+
 type RegisterWorkspaceProxyRequest struct {
 	// AccessURL that hits the workspace proxy api.
 	AccessURL string `json:"access_url"`
@@ -212,6 +250,27 @@ type RegisterWorkspaceProxyResponse struct {
 	// SiblingReplicas is a list of all other replicas of the proxy that have
 	// not timed out.
 	SiblingReplicas []codersdk.Replica `json:"sibling_replicas"`
+	// ProxyID is the ID of the registered proxy. Signed app tokens issued to
+	// the proxy use it as their audience.
+	ProxyID uuid.UUID `json:"proxy_id"`
+	// Config is the primary's current configuration for proxies. Changes are
+	// also pushed to proxies that watch it with WatchWorkspaceProxyConfig.
+	Config ProxyConfig `json:"config"`
+}
+
+// ProxyConfig is the subset of the primary's settings that workspace proxies
+// apply to the requests they serve.
+type ProxyConfig struct {
+	// DisablePathApps is true if path-based apps are disabled on the primary.
+	// Proxies refuse path-based apps if either they or the primary disable
+	// them.
+	DisablePathApps bool `json:"disable_path_apps"`
+	// SessionDuration is the lifetime of session tokens issued by the
+	// primary. Proxies expire app session cookies after it.
+	SessionDuration time.Duration `json:"session_duration"`
+	// Appearance is the deployment's appearance config, which proxies serve
+	// to clients loading the dashboard through them.
+	Appearance codersdk.AppearanceConfig `json:"appearance"`
 }
 
 func (c *Client) RegisterWorkspaceProxy(ctx context.Context, req RegisterWorkspaceProxyRequest) (RegisterWorkspaceProxyResponse, error) {
@@ -408,6 +467,54 @@ func (c *Client) RegisterWorkspaceProxyLoop(ctx context.Context, opts RegisterWo
 	return originalRes, done, nil
 }
 
+// WatchWorkspaceProxyConfig streams the primary's configuration for proxies.
+// The current configuration is sent immediately, and again every time it
+// changes. The channel is closed when the context is canceled or the
+// connection is lost.
+func (c *Client) WatchWorkspaceProxyConfig(ctx context.Context) (<-chan ProxyConfig, error) {
+	//nolint:bodyclose
+	res, err := c.Request(ctx, http.MethodGet, "/api/v2/workspaceproxies/me/config/watch", nil)
+	if err != nil {
+		return nil, xerrors.Errorf("make request: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, codersdk.ReadBodyAsError(res)
+	}
+	nextEvent := codersdk.ServerSentEventReader(ctx, res.Body)
+
+	configs := make(chan ProxyConfig, 1)
+	go func() {
+		defer close(configs)
+		defer res.Body.Close()
+
+		for {
+			sse, err := nextEvent()
+			if err != nil {
+				return
+			}
+			if sse.Type != codersdk.ServerSentEventTypeData {
+				continue
+			}
+			b, ok := sse.Data.([]byte)
+			if !ok {
+				return
+			}
+			var config ProxyConfig
+			err = json.Unmarshal(b, &config)
+			if err != nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case configs <- config:
+			}
+		}
+	}()
+	return configs, nil
+}
+
 type CoordinateMessageType int
 
 const (
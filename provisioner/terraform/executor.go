@@ -13,6 +13,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/hashicorp/go-version"
 	tfjson "github.com/hashicorp/terraform-json"
@@ -31,6 +32,7 @@ type executor struct {
 	// cachePath and workdir must not be used by multiple processes at once.
 	cachePath string
 	workdir   string
+	limits    resourceLimits
 }
 
 func (e *executor) basicEnv() []string {
@@ -81,8 +83,9 @@ func (e *executor) execWriteOutput(ctx, killCtx context.Context, args, env []str
 	// We want logs to be written in the correct order, so we wrap all logging
 	// in a sync.Mutex.
 	mut := &sync.Mutex{}
-	cmd.Stdout = syncWriter{mut, stdOutWriter}
-	cmd.Stderr = syncWriter{mut, stdErrWriter}
+	var outOfMemory atomic.Bool
+	cmd.Stdout = syncWriter{mut, outOfMemoryWriter{stdOutWriter, &outOfMemory}}
+	cmd.Stderr = syncWriter{mut, outOfMemoryWriter{stdErrWriter, &outOfMemory}}
 
 	e.server.logger.Debug(ctx, "executing terraform command",
 		slog.F("binary_path", e.binaryPath),
@@ -92,9 +95,16 @@ func (e *executor) execWriteOutput(ctx, killCtx context.Context, args, env []str
 	if err != nil {
 		return err
 	}
+	err = e.limits.apply(cmd.Process.Pid)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
 	interruptCommandOnCancel(ctx, killCtx, cmd)
 
-	return cmd.Wait()
+	err = cmd.Wait()
+	return e.limits.checkExceeded(err, cmd.ProcessState, outOfMemory.Load())
 }
 
 // execParseJSON must only be called while the lock is held.
@@ -120,11 +130,18 @@ func (e *executor) execParseJSON(ctx, killCtx context.Context, args, env []strin
 	if err != nil {
 		return err
 	}
+	err = e.limits.apply(cmd.Process.Pid)
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
 	interruptCommandOnCancel(ctx, killCtx, cmd)
 
 	err = cmd.Wait()
 	if err != nil {
 		errString, _ := io.ReadAll(stdErr)
+		err = e.limits.checkExceeded(err, cmd.ProcessState, reportsOutOfMemory(errString))
 		return xerrors.Errorf("%s: %w", errString, err)
 	}
 
@@ -0,0 +1,51 @@
+package terraform
+
+import (
+	"bytes"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// resourceLimits are the limits applied to each process run by the executor.
+// Zero values mean no limit.
+type resourceLimits struct {
+	// memoryBytes limits the address space of each process.
+	memoryBytes int64
+	// cpuTime limits the CPU time of each process.
+	cpuTime time.Duration
+}
+
+func resourceLimitsFromConfig(config *proto.Provision_Config) resourceLimits {
+	return resourceLimits{
+		memoryBytes: config.MemoryLimitBytes,
+		cpuTime:     time.Duration(config.CpuLimitMs) * time.Millisecond,
+	}
+}
+
+func (l resourceLimits) enabled() bool {
+	return l.memoryBytes > 0 || l.cpuTime > 0
+}
+
+// reportsOutOfMemory returns whether the output reports a failed memory
+// allocation, which is how processes hitting the memory limit usually fail.
+func reportsOutOfMemory(output []byte) bool {
+	return bytes.Contains(output, []byte("out of memory")) ||
+		bytes.Contains(output, []byte("cannot allocate memory"))
+}
+
+// outOfMemoryWriter records whether the output written through it reports a
+// failed memory allocation.
+type outOfMemoryWriter struct {
+	io.Writer
+	detected *atomic.Bool
+}
+
+func (w outOfMemoryWriter) Write(p []byte) (int, error) {
+	if reportsOutOfMemory(p) {
+		w.detected.Store(true)
+	}
+	return w.Writer.Write(p)
+}
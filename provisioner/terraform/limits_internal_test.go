@@ -0,0 +1,54 @@
+//go:build linux
+
+package terraform
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+func TestResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CPUExceeded", func(t *testing.T) {
+		t.Parallel()
+
+		limits := resourceLimits{cpuTime: time.Second}
+		cmd := exec.Command("sh", "-c", "while :; do :; done")
+		require.NoError(t, cmd.Start())
+		require.NoError(t, limits.apply(cmd.Process.Pid))
+
+		err := limits.checkExceeded(cmd.Wait(), cmd.ProcessState, false)
+		require.ErrorContains(t, err, provisionersdk.ResourceLimitExceededError)
+	})
+
+	t.Run("OutOfMemory", func(t *testing.T) {
+		t.Parallel()
+
+		limits := resourceLimits{memoryBytes: 64 << 20}
+		cmd := exec.Command("sh", "-c", "exit 1")
+		require.NoError(t, cmd.Start())
+		require.NoError(t, limits.apply(cmd.Process.Pid))
+
+		err := limits.checkExceeded(cmd.Wait(), cmd.ProcessState, reportsOutOfMemory([]byte("fatal error: runtime: out of memory")))
+		require.ErrorContains(t, err, provisionersdk.ResourceLimitExceededError)
+	})
+
+	t.Run("OtherFailure", func(t *testing.T) {
+		t.Parallel()
+
+		limits := resourceLimits{memoryBytes: 64 << 20, cpuTime: time.Minute}
+		cmd := exec.Command("sh", "-c", "exit 1")
+		require.NoError(t, cmd.Start())
+		require.NoError(t, limits.apply(cmd.Process.Pid))
+
+		err := limits.checkExceeded(cmd.Wait(), cmd.ProcessState, false)
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), provisionersdk.ResourceLimitExceededError)
+	})
+}
@@ -0,0 +1,62 @@
+//go:build linux
+
+package terraform
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+// apply sets the limits on the running process with the given pid. Processes
+// started by it inherit the limits.
+func (l resourceLimits) apply(pid int) error {
+	if l.memoryBytes > 0 {
+		limit := uint64(l.memoryBytes)
+		err := unix.Prlimit(pid, unix.RLIMIT_AS, &unix.Rlimit{Cur: limit, Max: limit}, nil)
+		if err != nil {
+			return xerrors.Errorf("set memory limit: %w", err)
+		}
+	}
+	if l.cpuTime > 0 {
+		// RLIMIT_CPU has a resolution of seconds. The process receives SIGXCPU
+		// at the soft limit, and is killed a second later.
+		seconds := uint64((l.cpuTime + time.Second - 1) / time.Second)
+		err := unix.Prlimit(pid, unix.RLIMIT_CPU, &unix.Rlimit{Cur: seconds, Max: seconds + 1}, nil)
+		if err != nil {
+			return xerrors.Errorf("set cpu limit: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkExceeded wraps err with provisionersdk.ResourceLimitExceededError if
+// the process failed because it exceeded the limits.
+func (l resourceLimits) checkExceeded(err error, state *os.ProcessState, outOfMemory bool) error {
+	if err == nil || state == nil {
+		return err
+	}
+	var exceeded bool
+	if l.memoryBytes > 0 && outOfMemory {
+		exceeded = true
+	}
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && l.cpuTime > 0 && status.Signaled() {
+		switch status.Signal() {
+		case syscall.SIGXCPU:
+			exceeded = true
+		case syscall.SIGKILL:
+			// The process may also have been killed because the job was
+			// canceled.
+			exceeded = state.UserTime()+state.SystemTime() >= l.cpuTime
+		}
+	}
+	if !exceeded {
+		return err
+	}
+	return xerrors.Errorf("%s: %w", provisionersdk.ResourceLimitExceededError, err)
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package terraform
+
+import "os"
+
+// apply is a no-op, resource limits are only supported on Linux.
+func (resourceLimits) apply(int) error {
+	return nil
+}
+
+func (resourceLimits) checkExceeded(err error, _ *os.ProcessState, _ bool) error {
+	return err
+}
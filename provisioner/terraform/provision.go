@@ -71,6 +71,11 @@ func (s *server) Provision(stream proto.DRPCProvisioner_ProvisionStream) error {
 		}
 	}()
 
+	sink := streamLogSink{
+		logger: s.logger.Named("execution_logs"),
+		stream: stream,
+	}
+
 	go func() {
 		for {
 			request, err := stream.Recv()
@@ -81,17 +86,28 @@ func (s *server) Provision(stream proto.DRPCProvisioner_ProvisionStream) error {
 				// We only process cancellation requests here.
 				continue
 			}
-			cancel()
-			return
+			// Acknowledge the cancellation so users know the provisioner is
+			// tearing down rather than stuck.
+			if request.GetCancel().GetForce() {
+				sink.Log(&proto.Log{
+					Level:  proto.LogLevel_WARN,
+					Output: "Cancellation forced, terminating Terraform. The state captured so far is kept.",
+				})
+				cancel()
+				kill()
+				return
+			}
+			if ctx.Err() == nil {
+				sink.Log(&proto.Log{
+					Level:  proto.LogLevel_INFO,
+					Output: "Cancellation received, waiting for Terraform to stop at a safe point.",
+				})
+				cancel()
+			}
 		}
 	}()
 
-	sink := streamLogSink{
-		logger: s.logger.Named("execution_logs"),
-		stream: stream,
-	}
-
-	e := s.executor(config.Directory)
+	e := s.executor(config.Directory, resourceLimitsFromConfig(config))
 	if err = e.checkMinVersion(ctx); err != nil {
 		return err
 	}
@@ -214,7 +230,10 @@ func provisionEnv(config *proto.Provision_Config, richParams []*proto.RichParame
 		"CODER_WORKSPACE_OWNER_ID="+config.Metadata.WorkspaceOwnerId,
 		"CODER_WORKSPACE_OWNER_SESSION_TOKEN="+config.Metadata.WorkspaceOwnerSessionToken,
 	)
-	for key, value := range provisionersdk.AgentScriptEnv() {
+	for key, value := range provisionersdk.AgentScriptEnv(provisionersdk.AgentScriptOptions{
+		RequireBinaryVerification: config.Metadata.RequireAgentBinaryVerification,
+		SigningCertificate:        config.Metadata.AgentBinarySigningCertificate,
+	}) {
 		env = append(env, key+"="+value)
 	}
 	for _, param := range richParams {
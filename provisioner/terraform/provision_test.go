@@ -106,13 +106,13 @@ func TestProvision_Cancel(t *testing.T) {
 			name:          "Cancel init",
 			mode:          "init",
 			startSequence: []string{"init_start"},
-			wantLog:       []string{"interrupt", "exit"},
+			wantLog:       []string{"Cancellation received, waiting for Terraform to stop at a safe point.", "interrupt", "exit"},
 		},
 		{
 			name:          "Cancel apply",
 			mode:          "apply",
 			startSequence: []string{"init", "apply_start"},
-			wantLog:       []string{"interrupt", "exit"},
+			wantLog:       []string{"Cancellation received, waiting for Terraform to stop at a safe point.", "interrupt", "exit"},
 		},
 	}
 	for _, tt := range tests {
@@ -46,6 +46,7 @@ type agentAttributes struct {
 	ShutdownScript               string          `mapstructure:"shutdown_script"`
 	ShutdownScriptTimeoutSeconds int32           `mapstructure:"shutdown_script_timeout"`
 	Metadata                     []agentMetadata `mapstructure:"metadata"`
+	Order                        int64           `mapstructure:"order"`
 }
 
 // A mapping of attributes on the "coder_app" resource.
@@ -64,6 +65,8 @@ type agentAppAttributes struct {
 	Share       string                     `mapstructure:"share"`
 	Subdomain   bool                       `mapstructure:"subdomain"`
 	Healthcheck []appHealthcheckAttributes `mapstructure:"healthcheck"`
+	Order       int64                      `mapstructure:"order"`
+	Hidden      bool                       `mapstructure:"hidden"`
 }
 
 // A mapping of attributes on the "healthcheck" resource.
@@ -79,6 +82,7 @@ type resourceMetadataAttributes struct {
 	Hide       bool                   `mapstructure:"hide"`
 	Icon       string                 `mapstructure:"icon"`
 	DailyCost  int32                  `mapstructure:"daily_cost"`
+	Order      int64                  `mapstructure:"order"`
 	Items      []resourceMetadataItem `mapstructure:"item"`
 }
 
@@ -198,6 +202,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 				ShutdownScript:               attrs.ShutdownScript,
 				ShutdownScriptTimeoutSeconds: attrs.ShutdownScriptTimeoutSeconds,
 				Metadata:                     metadata,
+				Order:                        attrs.Order,
 			}
 			switch attrs.Auth {
 			case "token":
@@ -373,6 +378,8 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 						Subdomain:    attrs.Subdomain,
 						SharingLevel: sharingLevel,
 						Healthcheck:  healthcheck,
+						Order:        attrs.Order,
+						Hidden:       attrs.Hidden,
 					})
 				}
 			}
@@ -384,6 +391,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 	resourceHidden := map[string]bool{}
 	resourceIcon := map[string]string{}
 	resourceCost := map[string]int32{}
+	resourceOrder := map[string]int64{}
 
 	metadataTargetLabels := map[string]bool{}
 	for _, resources := range tfResourcesByLabel {
@@ -441,6 +449,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 			resourceHidden[targetLabel] = attrs.Hide
 			resourceIcon[targetLabel] = attrs.Icon
 			resourceCost[targetLabel] = attrs.DailyCost
+			resourceOrder[targetLabel] = attrs.Order
 			for _, item := range attrs.Items {
 				resourceMetadata[targetLabel] = append(resourceMetadata[targetLabel],
 					&proto.Resource_Metadata{
@@ -477,6 +486,7 @@ func ConvertState(modules []*tfjson.StateModule, rawGraph string) (*State, error
 				Icon:         resourceIcon[label],
 				DailyCost:    resourceCost[label],
 				InstanceType: applyInstanceType(resource),
+				Order:        resourceOrder[label],
 			})
 		}
 	}
@@ -121,12 +121,13 @@ func (s *server) startTrace(ctx context.Context, name string, opts ...trace.Span
 	))...)
 }
 
-func (s *server) executor(workdir string) *executor {
+func (s *server) executor(workdir string, limits resourceLimits) *executor {
 	return &executor{
 		server:     s,
 		mut:        s.execMut,
 		binaryPath: s.binaryPath,
 		cachePath:  s.cachePath,
 		workdir:    workdir,
+		limits:     limits,
 	}
 }
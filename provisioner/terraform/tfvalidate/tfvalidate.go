@@ -0,0 +1,303 @@
+// Package tfvalidate statically checks Terraform templates for mistakes that
+// would otherwise only surface once a template version is imported by a
+// provisioner. It does not run Terraform, so it's cheap enough to call on
+// every change in the template editor.
+package tfvalidate
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/xerrors"
+
+	"github.com/coder/coder/v2/provisioner"
+)
+
+// maxFileSize matches the limit used when provisioners extract an archive.
+const maxFileSize = 10 << 20
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a single problem found in a template. Filename and Line are
+// empty when the problem isn't tied to a location.
+type Diagnostic struct {
+	Severity Severity
+	Summary  string
+	Detail   string
+	Filename string
+	Line     int
+}
+
+// Archive validates the Terraform files at the root of a tar archive. An
+// error is only returned if the archive can't be read.
+func Archive(r io.Reader) ([]Diagnostic, error) {
+	files := make(map[string][]byte)
+	reader := tar.NewReader(r)
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("read archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		// Terraform only loads files in the root module directory.
+		name := path.Clean(header.Name)
+		if strings.Contains(name, "/") || !isTerraformFile(name) {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(reader, maxFileSize))
+		if err != nil {
+			return nil, xerrors.Errorf("read %q: %w", name, err)
+		}
+		files[name] = data
+	}
+	return Files(files), nil
+}
+
+// Files validates a set of Terraform files keyed by file name.
+func Files(files map[string][]byte) []Diagnostic {
+	if len(files) == 0 {
+		return []Diagnostic{{
+			Severity: SeverityError,
+			Summary:  "No Terraform files",
+			Detail:   "The template must contain at least one .tf or .tf.json file in its root directory.",
+		}}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var (
+		parser = hclparse.NewParser()
+		module = tfconfig.NewModule("")
+		diags  hcl.Diagnostics
+		bodies []*hclsyntax.Body
+	)
+	for _, name := range names {
+		var (
+			file      *hcl.File
+			fileDiags hcl.Diagnostics
+		)
+		if strings.HasSuffix(name, ".json") {
+			file, fileDiags = parser.ParseJSON(files[name], name)
+		} else {
+			file, fileDiags = parser.ParseHCL(files[name], name)
+		}
+		diags = append(diags, fileDiags...)
+		if file == nil {
+			continue
+		}
+		diags = append(diags, tfconfig.LoadModuleFromFile(file, module)...)
+		// Override files partially redefine existing blocks, so they are
+		// only loaded into the module.
+		if body, ok := file.Body.(*hclsyntax.Body); ok && !isOverrideFile(name) {
+			bodies = append(bodies, body)
+		}
+	}
+
+	result := convertDiagnostics(diags)
+	if diags.HasErrors() {
+		// Linting a template that doesn't parse only adds noise.
+		return result
+	}
+
+	hasAgent := false
+	for _, resource := range module.ManagedResources {
+		if resource.Type == "coder_agent" {
+			hasAgent = true
+			break
+		}
+	}
+	// The agent may be declared in a module, which isn't inspected.
+	if !hasAgent && len(module.ModuleCalls) == 0 {
+		result = append(result, Diagnostic{
+			Severity: SeverityWarning,
+			Summary:  "No coder_agent resource",
+			Detail:   "Workspaces created from this template won't run an agent, so users can't connect to them or use apps. Ignore this if the template is intentionally agentless.",
+		})
+	}
+
+	return append(result, lintResources(bodies)...)
+}
+
+// deprecatedAttributes lists deprecated resource attributes and the
+// replacement to suggest.
+var deprecatedAttributes = []struct {
+	resourceType string
+	attribute    string
+	replacement  string
+}{
+	{"coder_agent", "login_before_ready", "startup_script_behavior"},
+	{"coder_app", "name", "display_name"},
+	{"coder_app", "relative_path", "subdomain"},
+}
+
+func lintResources(bodies []*hclsyntax.Body) []Diagnostic {
+	var (
+		result    []Diagnostic
+		resources = make(map[string]hcl.Range)
+		appSlugs  = make(map[string]hcl.Range)
+	)
+	for _, body := range bodies {
+		for _, block := range body.Blocks {
+			if block.Type != "resource" || len(block.Labels) != 2 {
+				continue
+			}
+			resourceType, resourceName := block.Labels[0], block.Labels[1]
+			address := resourceType + "." + resourceName
+			if previous, ok := resources[address]; ok {
+				result = append(result, diagnostic(SeverityError, "Duplicate resource",
+					fmt.Sprintf("A %s resource named %q was already declared at %s.", resourceType, resourceName, previous), block.DefRange()))
+				continue
+			}
+			resources[address] = block.DefRange()
+
+			for _, deprecated := range deprecatedAttributes {
+				if deprecated.resourceType != resourceType {
+					continue
+				}
+				if attr, ok := block.Body.Attributes[deprecated.attribute]; ok {
+					result = append(result, diagnostic(SeverityWarning, "Deprecated attribute",
+						fmt.Sprintf("%q is deprecated on %s, use %q instead.", deprecated.attribute, resourceType, deprecated.replacement), attr.NameRange))
+				}
+			}
+
+			if resourceType == "coder_app" {
+				result = append(result, lintApp(block, appSlugs)...)
+			}
+		}
+	}
+	return result
+}
+
+func lintApp(block *hclsyntax.Block, slugs map[string]hcl.Range) []Diagnostic {
+	var (
+		result     []Diagnostic
+		attributes = block.Body.Attributes
+		_, hasURL  = attributes["url"]
+		name       = block.Labels[1]
+	)
+
+	if _, ok := attributes["agent_id"]; !ok {
+		result = append(result, diagnostic(SeverityError, "Missing agent_id",
+			fmt.Sprintf("The app %q must set agent_id to the agent it runs on.", name), block.DefRange()))
+	}
+
+	slug := name
+	if attr, ok := attributes["slug"]; ok {
+		value, isLiteral := literalString(attr.Expr)
+		if !isLiteral {
+			// The slug is computed, so it can't be checked here.
+			slug = ""
+		} else {
+			slug = value
+		}
+	} else {
+		result = append(result, diagnostic(SeverityWarning, "Missing slug",
+			fmt.Sprintf("The app %q doesn't set a slug, so the resource name is used instead. Newer versions of the coder provider require a slug.", name), block.DefRange()))
+	}
+	if slug != "" {
+		if !provisioner.AppSlugRegex.MatchString(slug) {
+			result = append(result, diagnostic(SeverityError, "Invalid app slug",
+				fmt.Sprintf("The slug %q must be lowercase alphanumeric and may contain single hyphens between characters.", slug), block.DefRange()))
+		} else if previous, ok := slugs[slug]; ok {
+			result = append(result, diagnostic(SeverityError, "Duplicate app slug",
+				fmt.Sprintf("The slug %q is already used by the app at %s. Slugs must be unique per template.", slug, previous), block.DefRange()))
+		} else {
+			slugs[slug] = block.DefRange()
+		}
+	}
+
+	if command, ok := attributes["command"]; ok && hasURL {
+		result = append(result, diagnostic(SeverityError, "Conflicting app configuration",
+			fmt.Sprintf("The app %q sets both url and command. Set url for web apps, or command for terminal apps.", name), command.NameRange))
+	}
+	if subdomain, ok := attributes["subdomain"]; ok && !hasURL {
+		value, diags := subdomain.Expr.Value(nil)
+		if !diags.HasErrors() && value.Type() == cty.Bool && value.IsKnown() && value.True() {
+			result = append(result, diagnostic(SeverityWarning, "Subdomain without url",
+				fmt.Sprintf("The app %q sets subdomain but has no url, so it has no effect.", name), subdomain.NameRange))
+		}
+	}
+	for _, nested := range block.Body.Blocks {
+		if nested.Type == "healthcheck" && !hasURL {
+			result = append(result, diagnostic(SeverityError, "Healthcheck without url",
+				fmt.Sprintf("The app %q has a healthcheck but no url. Healthchecks are only supported for web apps.", name), nested.DefRange()))
+		}
+	}
+	return result
+}
+
+// literalString returns the value of a string expression that doesn't
+// reference anything.
+func literalString(expr hclsyntax.Expression) (string, bool) {
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() || !value.IsKnown() || value.IsNull() || value.Type() != cty.String {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+func diagnostic(severity Severity, summary, detail string, rng hcl.Range) Diagnostic {
+	return Diagnostic{
+		Severity: severity,
+		Summary:  summary,
+		Detail:   detail,
+		Filename: rng.Filename,
+		Line:     rng.Start.Line,
+	}
+}
+
+func convertDiagnostics(diags hcl.Diagnostics) []Diagnostic {
+	result := make([]Diagnostic, 0, len(diags))
+	for _, diag := range diags {
+		d := Diagnostic{
+			Severity: SeverityError,
+			Summary:  diag.Summary,
+			Detail:   diag.Detail,
+		}
+		if diag.Severity == hcl.DiagWarning {
+			d.Severity = SeverityWarning
+		}
+		if diag.Subject != nil {
+			d.Filename = diag.Subject.Filename
+			d.Line = diag.Subject.Start.Line
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+func isTerraformFile(name string) bool {
+	if strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+		return false
+	}
+	return strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json")
+}
+
+func isOverrideFile(name string) bool {
+	base := strings.TrimSuffix(strings.TrimSuffix(name, ".json"), ".tf")
+	return base == "override" || strings.HasSuffix(base, "_override")
+}
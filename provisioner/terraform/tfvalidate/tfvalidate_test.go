@@ -0,0 +1,206 @@
+package tfvalidate_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/coder/coder/v2/provisioner/terraform/tfvalidate"
+	"github.com/coder/coder/v2/provisionersdk"
+)
+
+const agent = `
+resource "coder_agent" "main" {
+  os   = "linux"
+  arch = "amd64"
+}
+`
+
+func TestFiles(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name  string
+		files map[string]string
+		// expected is the summary of each diagnostic, in order.
+		expected []string
+	}{
+		{
+			name:  "Valid",
+			files: map[string]string{"main.tf": agent},
+		},
+		{
+			name:     "Empty",
+			expected: []string{"No Terraform files"},
+		},
+		{
+			name: "SyntaxError",
+			files: map[string]string{"main.tf": `
+resource "coder_agent" "main" {
+  os =
+}
+`},
+			expected: []string{"Invalid expression"},
+		},
+		{
+			name: "MissingAgent",
+			files: map[string]string{"main.tf": `
+resource "null_resource" "main" {}
+`},
+			expected: []string{"No coder_agent resource"},
+		},
+		{
+			name: "AgentInModule",
+			files: map[string]string{"main.tf": `
+module "workspace" {
+  source = "./workspace"
+}
+`},
+		},
+		{
+			name: "DuplicateResource",
+			files: map[string]string{
+				"main.tf":  agent,
+				"other.tf": agent,
+			},
+			expected: []string{"Duplicate resource"},
+		},
+		{
+			name: "DeprecatedAttributes",
+			files: map[string]string{"main.tf": `
+resource "coder_agent" "main" {
+  os                 = "linux"
+  arch               = "amd64"
+  login_before_ready = false
+}
+
+resource "coder_app" "code" {
+  agent_id      = coder_agent.main.id
+  slug          = "code"
+  name          = "Code"
+  url           = "http://localhost:8080"
+  relative_path = true
+}
+`},
+			expected: []string{"Deprecated attribute", "Deprecated attribute", "Deprecated attribute"},
+		},
+		{
+			name: "MisconfiguredApps",
+			files: map[string]string{"main.tf": agent + `
+resource "coder_app" "missing_agent" {
+  slug = "missing-agent"
+  url  = "http://localhost:8080"
+}
+
+resource "coder_app" "invalid_slug" {
+  agent_id = coder_agent.main.id
+  slug     = "Invalid_Slug"
+  url      = "http://localhost:8080"
+}
+
+resource "coder_app" "duplicate_slug" {
+  agent_id = coder_agent.main.id
+  slug     = "missing-agent"
+  url      = "http://localhost:8080"
+}
+
+resource "coder_app" "conflicting" {
+  agent_id = coder_agent.main.id
+  slug     = "conflicting"
+  url      = "http://localhost:8080"
+  command  = "bash"
+}
+
+resource "coder_app" "terminal" {
+  agent_id  = coder_agent.main.id
+  slug      = "terminal"
+  command   = "bash"
+  subdomain = true
+  healthcheck {
+    url       = "http://localhost:8080/healthz"
+    interval  = 5
+    threshold = 6
+  }
+}
+
+resource "coder_app" "computed_slug" {
+  agent_id = coder_agent.main.id
+  slug     = "app-${coder_agent.main.id}"
+  url      = "http://localhost:8080"
+}
+`},
+			expected: []string{
+				"Missing agent_id",
+				"Invalid app slug",
+				"Duplicate app slug",
+				"Conflicting app configuration",
+				"Subdomain without url",
+				"Healthcheck without url",
+			},
+		},
+		{
+			name: "MissingSlug",
+			files: map[string]string{"main.tf": agent + `
+resource "coder_app" "code" {
+  agent_id = coder_agent.main.id
+  url      = "http://localhost:8080"
+}
+`},
+			expected: []string{"Missing slug"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			files := make(map[string][]byte, len(tc.files))
+			for name, content := range tc.files {
+				files[name] = []byte(content)
+			}
+			diags := tfvalidate.Files(files)
+			summaries := make([]string, 0, len(diags))
+			for _, diag := range diags {
+				summaries = append(summaries, diag.Summary)
+			}
+			if tc.expected == nil {
+				tc.expected = []string{}
+			}
+			require.Equal(t, tc.expected, summaries)
+		})
+	}
+}
+
+func TestArchive(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`
+resource "coder_app" "code" {
+  slug = "code"
+  url  = "http://localhost:8080"
+}
+`), 0o600)
+	require.NoError(t, err)
+	// Files outside of the root module aren't validated.
+	err = os.Mkdir(filepath.Join(dir, "module"), 0o700)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "module", "main.tf"), []byte(`invalid`), 0o600)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = provisionersdk.Tar(&buf, dir, 1<<20)
+	require.NoError(t, err)
+
+	diags, err := tfvalidate.Archive(&buf)
+	require.NoError(t, err)
+	require.Len(t, diags, 2)
+	require.Equal(t, tfvalidate.SeverityWarning, diags[0].Severity)
+	require.Equal(t, "No coder_agent resource", diags[0].Summary)
+	require.Equal(t, tfvalidate.SeverityError, diags[1].Severity)
+	require.Equal(t, "Missing agent_id", diags[1].Summary)
+	require.Equal(t, "main.tf", diags[1].Filename)
+	require.Equal(t, 2, diags[1].Line)
+}
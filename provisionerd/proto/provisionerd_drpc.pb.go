@@ -39,6 +39,7 @@ type DRPCProvisionerDaemonClient interface {
 	DRPCConn() drpc.Conn
 
 	AcquireJob(ctx context.Context, in *Empty) (*AcquiredJob, error)
+	AcquireJobWithWait(ctx context.Context, in *AcquireJobWithWaitRequest) (*AcquiredJob, error)
 	CommitQuota(ctx context.Context, in *CommitQuotaRequest) (*CommitQuotaResponse, error)
 	UpdateJob(ctx context.Context, in *UpdateJobRequest) (*UpdateJobResponse, error)
 	FailJob(ctx context.Context, in *FailedJob) (*Empty, error)
@@ -64,6 +65,15 @@ func (c *drpcProvisionerDaemonClient) AcquireJob(ctx context.Context, in *Empty)
 	return out, nil
 }
 
+func (c *drpcProvisionerDaemonClient) AcquireJobWithWait(ctx context.Context, in *AcquireJobWithWaitRequest) (*AcquiredJob, error) {
+	out := new(AcquiredJob)
+	err := c.cc.Invoke(ctx, "/provisionerd.ProvisionerDaemon/AcquireJobWithWait", drpcEncoding_File_provisionerd_proto_provisionerd_proto{}, in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *drpcProvisionerDaemonClient) CommitQuota(ctx context.Context, in *CommitQuotaRequest) (*CommitQuotaResponse, error) {
 	out := new(CommitQuotaResponse)
 	err := c.cc.Invoke(ctx, "/provisionerd.ProvisionerDaemon/CommitQuota", drpcEncoding_File_provisionerd_proto_provisionerd_proto{}, in, out)
@@ -102,6 +112,7 @@ func (c *drpcProvisionerDaemonClient) CompleteJob(ctx context.Context, in *Compl
 
 type DRPCProvisionerDaemonServer interface {
 	AcquireJob(context.Context, *Empty) (*AcquiredJob, error)
+	AcquireJobWithWait(context.Context, *AcquireJobWithWaitRequest) (*AcquiredJob, error)
 	CommitQuota(context.Context, *CommitQuotaRequest) (*CommitQuotaResponse, error)
 	UpdateJob(context.Context, *UpdateJobRequest) (*UpdateJobResponse, error)
 	FailJob(context.Context, *FailedJob) (*Empty, error)
@@ -114,6 +125,10 @@ func (s *DRPCProvisionerDaemonUnimplementedServer) AcquireJob(context.Context, *
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
 
+func (s *DRPCProvisionerDaemonUnimplementedServer) AcquireJobWithWait(context.Context, *AcquireJobWithWaitRequest) (*AcquiredJob, error) {
+	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
+}
+
 func (s *DRPCProvisionerDaemonUnimplementedServer) CommitQuota(context.Context, *CommitQuotaRequest) (*CommitQuotaResponse, error) {
 	return nil, drpcerr.WithCode(errors.New("Unimplemented"), drpcerr.Unimplemented)
 }
@@ -132,7 +147,7 @@ func (s *DRPCProvisionerDaemonUnimplementedServer) CompleteJob(context.Context,
 
 type DRPCProvisionerDaemonDescription struct{}
 
-func (DRPCProvisionerDaemonDescription) NumMethods() int { return 5 }
+func (DRPCProvisionerDaemonDescription) NumMethods() int { return 6 }
 
 func (DRPCProvisionerDaemonDescription) Method(n int) (string, drpc.Encoding, drpc.Receiver, interface{}, bool) {
 	switch n {
@@ -146,6 +161,15 @@ func (DRPCProvisionerDaemonDescription) Method(n int) (string, drpc.Encoding, dr
 					)
 			}, DRPCProvisionerDaemonServer.AcquireJob, true
 	case 1:
+		return "/provisionerd.ProvisionerDaemon/AcquireJobWithWait", drpcEncoding_File_provisionerd_proto_provisionerd_proto{},
+			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
+				return srv.(DRPCProvisionerDaemonServer).
+					AcquireJobWithWait(
+						ctx,
+						in1.(*AcquireJobWithWaitRequest),
+					)
+			}, DRPCProvisionerDaemonServer.AcquireJobWithWait, true
+	case 2:
 		return "/provisionerd.ProvisionerDaemon/CommitQuota", drpcEncoding_File_provisionerd_proto_provisionerd_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCProvisionerDaemonServer).
@@ -154,7 +178,7 @@ func (DRPCProvisionerDaemonDescription) Method(n int) (string, drpc.Encoding, dr
 						in1.(*CommitQuotaRequest),
 					)
 			}, DRPCProvisionerDaemonServer.CommitQuota, true
-	case 2:
+	case 3:
 		return "/provisionerd.ProvisionerDaemon/UpdateJob", drpcEncoding_File_provisionerd_proto_provisionerd_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCProvisionerDaemonServer).
@@ -163,7 +187,7 @@ func (DRPCProvisionerDaemonDescription) Method(n int) (string, drpc.Encoding, dr
 						in1.(*UpdateJobRequest),
 					)
 			}, DRPCProvisionerDaemonServer.UpdateJob, true
-	case 3:
+	case 4:
 		return "/provisionerd.ProvisionerDaemon/FailJob", drpcEncoding_File_provisionerd_proto_provisionerd_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCProvisionerDaemonServer).
@@ -172,7 +196,7 @@ func (DRPCProvisionerDaemonDescription) Method(n int) (string, drpc.Encoding, dr
 						in1.(*FailedJob),
 					)
 			}, DRPCProvisionerDaemonServer.FailJob, true
-	case 4:
+	case 5:
 		return "/provisionerd.ProvisionerDaemon/CompleteJob", drpcEncoding_File_provisionerd_proto_provisionerd_proto{},
 			func(srv interface{}, ctx context.Context, in1, in2 interface{}) (drpc.Message, error) {
 				return srv.(DRPCProvisionerDaemonServer).
@@ -206,6 +230,22 @@ func (x *drpcProvisionerDaemon_AcquireJobStream) SendAndClose(m *AcquiredJob) er
 	return x.CloseSend()
 }
 
+type DRPCProvisionerDaemon_AcquireJobWithWaitStream interface {
+	drpc.Stream
+	SendAndClose(*AcquiredJob) error
+}
+
+type drpcProvisionerDaemon_AcquireJobWithWaitStream struct {
+	drpc.Stream
+}
+
+func (x *drpcProvisionerDaemon_AcquireJobWithWaitStream) SendAndClose(m *AcquiredJob) error {
+	if err := x.MsgSend(m, drpcEncoding_File_provisionerd_proto_provisionerd_proto{}); err != nil {
+		return err
+	}
+	return x.CloseSend()
+}
+
 type DRPCProvisionerDaemon_CommitQuotaStream interface {
 	drpc.Stream
 	SendAndClose(*CommitQuotaResponse) error
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,6 +20,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 	"golang.org/x/xerrors"
+	"storj.io/drpc/drpcerr"
 
 	"cdr.dev/slog"
 	"github.com/coder/coder/v2/coderd/tracing"
@@ -55,7 +57,11 @@ type Options struct {
 	JobPollInterval     time.Duration
 	JobPollJitter       time.Duration
 	JobPollDebounce     time.Duration
-	Provisioners        Provisioners
+	// JobAcquireWait is how long coderd holds a request for a job open
+	// waiting for one to be posted. JobPollInterval is only used if coderd
+	// does not support waiting for jobs.
+	JobAcquireWait time.Duration
+	Provisioners   Provisioners
 	// WorkDirectory must not be used by multiple processes at once.
 	WorkDirectory string
 }
@@ -71,6 +77,9 @@ func New(clientDialer Dialer, opts *Options) *Server {
 	if opts.JobPollJitter == 0 {
 		opts.JobPollJitter = time.Second
 	}
+	if opts.JobAcquireWait == 0 {
+		opts.JobAcquireWait = 30 * time.Second
+	}
 	if opts.UpdateInterval == 0 {
 		opts.UpdateInterval = 5 * time.Second
 	}
@@ -115,6 +124,9 @@ type Server struct {
 
 	clientDialer Dialer
 	clientValue  atomic.Pointer[proto.DRPCProvisionerDaemonClient]
+	// legacyAcquire is set when coderd does not support waiting for jobs,
+	// so jobs are polled for instead.
+	legacyAcquire atomic.Bool
 
 	// Locked when closing the daemon, shutting down, or starting a new job.
 	mutex        sync.Mutex
@@ -127,6 +139,8 @@ type Server struct {
 
 type Metrics struct {
 	Runner runner.Metrics
+	// AcquireWait is how long requests for a job waited on coderd.
+	AcquireWait *prometheus.HistogramVec
 }
 
 func NewMetrics(reg prometheus.Registerer) Metrics {
@@ -169,6 +183,21 @@ func NewMetrics(reg prometheus.Registerer) Metrics {
 				Help:      "The number of workspaces started, updated, or deleted.",
 			}, []string{"workspace_owner", "workspace_name", "template_name", "template_version", "workspace_transition", "status"}),
 		},
+		AcquireWait: auto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "coderd",
+			Subsystem: "provisionerd",
+			Name:      "job_acquire_wait_seconds",
+			Help:      "The time provisioner daemons waited on coderd for a job in seconds.",
+			Buckets: []float64{
+				0.01, // 10ms
+				0.1,
+				1, // 1s
+				5,
+				10,
+				30,
+				60, // 1min
+			},
+		}, []string{"result"}),
 	}
 }
 
@@ -250,8 +279,7 @@ func (p *Server) connect(ctx context.Context) {
 			case <-client.DRPCConn().Closed():
 				return
 			case <-timer.C:
-				p.acquireJob(ctx)
-				timer.Reset(p.nextInterval())
+				timer.Reset(p.acquireJob(ctx))
 			}
 		}
 	}()
@@ -292,55 +320,76 @@ var (
 	lastAcquireMutex sync.RWMutex
 )
 
-// Locks a job in the database, and runs it!
-func (p *Server) acquireJob(ctx context.Context) {
+// Locks a job in the database, and runs it! It returns how long to wait
+// before acquiring the next job.
+func (p *Server) acquireJob(ctx context.Context) time.Duration {
 	p.mutex.Lock()
-	defer p.mutex.Unlock()
-	if p.isClosed() {
-		return
-	}
-	if p.isRunningJob() {
-		return
+	if p.isClosed() || p.isRunningJob() {
+		p.mutex.Unlock()
+		return p.nextInterval()
 	}
 	if p.isShutdown() {
+		p.mutex.Unlock()
 		p.opts.Logger.Debug(context.Background(), "skipping acquire; provisionerd is shutting down")
-		return
+		return p.nextInterval()
 	}
+	// The mutex isn't held while waiting for a job, so the daemon can be
+	// shut down or closed in the meantime.
+	p.mutex.Unlock()
 
-	// This prevents loads of provisioner daemons from consistently sending
-	// requests when no jobs are available.
-	//
-	// The debounce only occurs when no job is returned, so if loads of jobs are
-	// added at once, they will start after at most this duration.
-	lastAcquireMutex.RLock()
-	if !lastAcquire.IsZero() && time.Since(lastAcquire) < p.opts.JobPollDebounce {
-		lastAcquireMutex.RUnlock()
-		return
-	}
-	lastAcquireMutex.RUnlock()
-
-	var err error
 	client, ok := p.client()
 	if !ok {
-		return
+		return p.nextInterval()
 	}
 
-	job, err := client.AcquireJob(ctx, &proto.Empty{})
+	var (
+		job *proto.AcquiredJob
+		err error
+	)
+	legacy := p.legacyAcquire.Load()
+	if legacy {
+		job, err = p.pollJob(ctx, client)
+	} else {
+		job, err = p.waitForJob(ctx, client)
+		if isUnimplemented(err) {
+			p.opts.Logger.Info(ctx, "coderd does not support waiting for jobs, polling instead")
+			p.legacyAcquire.Store(true)
+			return 0
+		}
+	}
 	if err != nil {
 		if errors.Is(err, context.Canceled) ||
 			errors.Is(err, yamux.ErrSessionShutdown) ||
 			errors.Is(err, fasthttputil.ErrInmemoryListenerClosed) {
-			return
+			return p.nextInterval()
 		}
 
 		p.opts.Logger.Warn(ctx, "provisionerd was unable to acquire job", slog.Error(err))
-		return
+		return p.nextInterval()
 	}
 	if job.JobId == "" {
-		lastAcquireMutex.Lock()
-		lastAcquire = time.Now()
-		lastAcquireMutex.Unlock()
-		return
+		if legacy {
+			return p.nextInterval()
+		}
+		// The wait elapsed without a job being posted, so wait again.
+		return 0
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.isClosed() || p.isShutdown() {
+		// The daemon stopped while the job was being acquired. Fail the
+		// job so it isn't left locked to this daemon.
+		failCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		err := p.FailJob(failCtx, &proto.FailedJob{
+			JobId: job.JobId,
+			Error: "provisioner daemon was shutdown gracefully",
+		})
+		if err != nil {
+			p.opts.Logger.Warn(ctx, "fail job acquired during shutdown", slog.F("job_id", job.JobId), slog.Error(err))
+		}
+		return p.nextInterval()
 	}
 
 	if len(job.TraceMetadata) > 0 {
@@ -395,7 +444,7 @@ func (p *Server) acquireJob(ctx context.Context) {
 		if err != nil {
 			p.opts.Logger.Error(ctx, "provisioner job failed", slog.F("job_id", job.JobId), slog.Error(err))
 		}
-		return
+		return p.nextInterval()
 	}
 
 	p.activeJob = runner.New(
@@ -417,6 +466,61 @@ func (p *Server) acquireJob(ctx context.Context) {
 	)
 
 	go p.activeJob.Run()
+	return p.nextInterval()
+}
+
+// waitForJob asks coderd for a job, waiting up to JobAcquireWait for one to be
+// posted.
+func (p *Server) waitForJob(ctx context.Context, client proto.DRPCProvisionerDaemonClient) (*proto.AcquiredJob, error) {
+	start := time.Now()
+	job, err := client.AcquireJobWithWait(ctx, &proto.AcquireJobWithWaitRequest{
+		WaitMs: p.opts.JobAcquireWait.Milliseconds(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := "acquired"
+	if job.JobId == "" {
+		result = "empty"
+	}
+	p.opts.Metrics.AcquireWait.WithLabelValues(result).Observe(time.Since(start).Seconds())
+	return job, nil
+}
+
+// pollJob asks coderd for a job without waiting. It is used with versions of
+// coderd that don't support waiting for jobs.
+func (p *Server) pollJob(ctx context.Context, client proto.DRPCProvisionerDaemonClient) (*proto.AcquiredJob, error) {
+	// This prevents loads of provisioner daemons from consistently sending
+	// requests when no jobs are available.
+	//
+	// The debounce only occurs when no job is returned, so if loads of jobs are
+	// added at once, they will start after at most this duration.
+	lastAcquireMutex.RLock()
+	if !lastAcquire.IsZero() && time.Since(lastAcquire) < p.opts.JobPollDebounce {
+		lastAcquireMutex.RUnlock()
+		return &proto.AcquiredJob{}, nil
+	}
+	lastAcquireMutex.RUnlock()
+
+	job, err := client.AcquireJob(ctx, &proto.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	if job.JobId == "" {
+		lastAcquireMutex.Lock()
+		lastAcquire = time.Now()
+		lastAcquireMutex.Unlock()
+	}
+	return job, nil
+}
+
+// isUnimplemented returns whether err was returned because coderd doesn't
+// implement the RPC.
+func isUnimplemented(err error) bool {
+	if err == nil {
+		return false
+	}
+	return drpcerr.Code(err) == drpcerr.Unimplemented || strings.Contains(err.Error(), "unknown rpc")
 }
 
 func retryable(err error) bool {
@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"go.uber.org/atomic"
 	"go.uber.org/goleak"
 	"golang.org/x/xerrors"
+	"storj.io/drpc/drpcerr"
 	"storj.io/drpc/drpcmux"
 	"storj.io/drpc/drpcserver"
 
@@ -107,6 +109,71 @@ func TestProvisionerd(t *testing.T) {
 		require.NoError(t, closer.Close())
 	})
 
+	t.Run("AcquireJobWithWait", func(t *testing.T) {
+		// When the server supports waiting for jobs, the daemon should use
+		// it instead of polling.
+		t.Parallel()
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		var (
+			didComplete   atomic.Bool
+			didAcquireJob atomic.Bool
+			didPoll       atomic.Bool
+			completeChan  = make(chan struct{})
+			completeOnce  sync.Once
+		)
+
+		closer := createProvisionerd(t, func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+			return createProvisionerDaemonClient(t, done, provisionerDaemonTestServer{
+				acquireJob: func(ctx context.Context, _ *proto.Empty) (*proto.AcquiredJob, error) {
+					didPoll.Store(true)
+					return &proto.AcquiredJob{}, nil
+				},
+				acquireJobWithWait: func(ctx context.Context, req *proto.AcquireJobWithWaitRequest) (*proto.AcquiredJob, error) {
+					assert.Positive(t, req.WaitMs)
+					if !didAcquireJob.CAS(false, true) {
+						completeOnce.Do(func() { close(completeChan) })
+						return &proto.AcquiredJob{}, nil
+					}
+
+					return &proto.AcquiredJob{
+						JobId:       "test",
+						Provisioner: "someprovisioner",
+						TemplateSourceArchive: createTar(t, map[string]string{
+							"test.txt": "content",
+						}),
+						Type: &proto.AcquiredJob_WorkspaceBuild_{
+							WorkspaceBuild: &proto.AcquiredJob_WorkspaceBuild{
+								Metadata: &sdkproto.Provision_Metadata{},
+							},
+						},
+					}, nil
+				},
+				updateJob: noopUpdateJob,
+				completeJob: func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error) {
+					didComplete.Store(true)
+					return &proto.Empty{}, nil
+				},
+			}), nil
+		}, provisionerd.Provisioners{
+			"someprovisioner": createProvisionerClient(t, done, provisionerTestServer{
+				provision: func(stream sdkproto.DRPCProvisioner_ProvisionStream) error {
+					return stream.Send(&sdkproto.Provision_Response{
+						Type: &sdkproto.Provision_Response_Complete{
+							Complete: &sdkproto.Provision_Complete{},
+						},
+					})
+				},
+			}),
+		})
+		require.Condition(t, closedWithin(completeChan, testutil.WaitShort))
+		require.NoError(t, closer.Close())
+		assert.True(t, didComplete.Load(), "should complete the job")
+		assert.False(t, didPoll.Load(), "should not poll for jobs")
+	})
+
 	t.Run("CloseCancelsJob", func(t *testing.T) {
 		t.Parallel()
 		done := make(chan struct{})
@@ -662,6 +729,339 @@ func TestProvisionerd(t *testing.T) {
 		assert.True(t, didFail.Load(), "should fail the job")
 	})
 
+	t.Run("WorkspaceBuildHooks", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("build hooks run with sh")
+		}
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		var (
+			mutex         sync.Mutex
+			results       []*proto.BuildHookResult
+			hookOutput    []string
+			didComplete   atomic.Bool
+			didAcquireJob atomic.Bool
+			completeChan  = make(chan struct{})
+			completeOnce  sync.Once
+		)
+
+		closer := createProvisionerd(t, func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+			return createProvisionerDaemonClient(t, done, provisionerDaemonTestServer{
+				acquireJob: func(ctx context.Context, _ *proto.Empty) (*proto.AcquiredJob, error) {
+					if !didAcquireJob.CAS(false, true) {
+						completeOnce.Do(func() { close(completeChan) })
+						return &proto.AcquiredJob{}, nil
+					}
+
+					return &proto.AcquiredJob{
+						JobId:       "test",
+						Provisioner: "someprovisioner",
+						TemplateSourceArchive: createTar(t, map[string]string{
+							runner.BuildHooksFile: `pre:
+  - name: register-dns
+    run: echo "registering $CODER_WORKSPACE_NAME"
+post:
+  - name: notify
+    run: exit 3
+    retries: 1
+    retry_delay: 10ms
+  - name: stop-only
+    run: exit 1
+    transitions: [stop]
+clone:
+  - name: copy-home
+    run: echo "copying $CODER_CLONE_SOURCE_WORKSPACE_NAME"
+`,
+						}),
+						Type: &proto.AcquiredJob_WorkspaceBuild_{
+							WorkspaceBuild: &proto.AcquiredJob_WorkspaceBuild{
+								Metadata: &sdkproto.Provision_Metadata{
+									WorkspaceName:       "dev",
+									WorkspaceTransition: sdkproto.WorkspaceTransition_START,
+								},
+								CloneSourceWorkspaceId:   "8f1b7e0c-5a2d-4c3e-9b6f-1d2e3f4a5b6c",
+								CloneSourceWorkspaceName: "broken",
+							},
+						},
+					}, nil
+				},
+				updateJob: func(ctx context.Context, update *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error) {
+					mutex.Lock()
+					defer mutex.Unlock()
+					results = append(results, update.BuildHookResults...)
+					for _, log := range update.Logs {
+						if log.Source == proto.LogSource_PROVISIONER && log.Output != "" {
+							hookOutput = append(hookOutput, log.Output)
+						}
+					}
+					return &proto.UpdateJobResponse{}, nil
+				},
+				completeJob: func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error) {
+					didComplete.Store(true)
+					return &proto.Empty{}, nil
+				},
+			}), nil
+		}, provisionerd.Provisioners{
+			"someprovisioner": createProvisionerClient(t, done, provisionerTestServer{
+				provision: func(stream sdkproto.DRPCProvisioner_ProvisionStream) error {
+					return stream.Send(&sdkproto.Provision_Response{
+						Type: &sdkproto.Provision_Response_Complete{
+							Complete: &sdkproto.Provision_Complete{},
+						},
+					})
+				},
+			}),
+		})
+		require.Condition(t, closedWithin(completeChan, testutil.WaitShort))
+		require.NoError(t, closer.Close())
+		// Post-build hooks don't fail the build.
+		assert.True(t, didComplete.Load(), "should complete the job")
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		require.Contains(t, hookOutput, "registering dev")
+		require.Contains(t, hookOutput, "copying broken")
+		require.Len(t, results, 3)
+		assert.Equal(t, "register-dns", results[0].Name)
+		assert.Equal(t, runner.BuildHookPhasePre, results[0].Phase)
+		assert.EqualValues(t, 1, results[0].Attempts)
+		assert.Empty(t, results[0].Error)
+		// Clone hooks run before the post-build hooks.
+		assert.Equal(t, "copy-home", results[1].Name)
+		assert.Equal(t, runner.BuildHookPhaseClone, results[1].Phase)
+		assert.Empty(t, results[1].Error)
+		assert.Equal(t, "notify", results[2].Name)
+		assert.Equal(t, runner.BuildHookPhasePost, results[2].Phase)
+		assert.EqualValues(t, 2, results[2].Attempts)
+		assert.EqualValues(t, 3, results[2].ExitCode)
+		assert.Equal(t, "exited with code 3", results[2].Error)
+	})
+
+	t.Run("WorkspaceBuildPreHookFails", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == "windows" {
+			t.Skip("build hooks run with sh")
+		}
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		var (
+			failedJob     atomic.Pointer[proto.FailedJob]
+			didProvision  atomic.Bool
+			didAcquireJob atomic.Bool
+			completeChan  = make(chan struct{})
+			completeOnce  sync.Once
+		)
+
+		closer := createProvisionerd(t, func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+			return createProvisionerDaemonClient(t, done, provisionerDaemonTestServer{
+				acquireJob: func(ctx context.Context, _ *proto.Empty) (*proto.AcquiredJob, error) {
+					if !didAcquireJob.CAS(false, true) {
+						completeOnce.Do(func() { close(completeChan) })
+						return &proto.AcquiredJob{}, nil
+					}
+
+					return &proto.AcquiredJob{
+						JobId:       "test",
+						Provisioner: "someprovisioner",
+						TemplateSourceArchive: createTar(t, map[string]string{
+							runner.BuildHooksFile: `pre:
+  - name: warm-cache
+    run: exit 1
+`,
+						}),
+						Type: &proto.AcquiredJob_WorkspaceBuild_{
+							WorkspaceBuild: &proto.AcquiredJob_WorkspaceBuild{
+								Metadata: &sdkproto.Provision_Metadata{},
+							},
+						},
+					}, nil
+				},
+				updateJob: noopUpdateJob,
+				failJob: func(ctx context.Context, job *proto.FailedJob) (*proto.Empty, error) {
+					failedJob.Store(job)
+					return &proto.Empty{}, nil
+				},
+			}), nil
+		}, provisionerd.Provisioners{
+			"someprovisioner": createProvisionerClient(t, done, provisionerTestServer{
+				provision: func(stream sdkproto.DRPCProvisioner_ProvisionStream) error {
+					didProvision.Store(true)
+					return stream.Send(&sdkproto.Provision_Response{
+						Type: &sdkproto.Provision_Response_Complete{
+							Complete: &sdkproto.Provision_Complete{},
+						},
+					})
+				},
+			}),
+		})
+		require.Condition(t, closedWithin(completeChan, testutil.WaitShort))
+		require.NoError(t, closer.Close())
+		require.NotNil(t, failedJob.Load(), "should fail the job")
+		assert.Equal(t, `pre-build hook "warm-cache" failed: exited with code 1`, failedJob.Load().Error)
+		assert.False(t, didProvision.Load(), "should not provision")
+	})
+
+	t.Run("WorkspaceBuildTimeout", func(t *testing.T) {
+		t.Parallel()
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		var (
+			didAcquireJob atomic.Bool
+			failedJob     atomic.Pointer[proto.FailedJob]
+			completeChan  = make(chan struct{})
+			completeOnce  sync.Once
+		)
+
+		closer := createProvisionerd(t, func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+			return createProvisionerDaemonClient(t, done, provisionerDaemonTestServer{
+				acquireJob: func(ctx context.Context, _ *proto.Empty) (*proto.AcquiredJob, error) {
+					if !didAcquireJob.CAS(false, true) {
+						return &proto.AcquiredJob{}, nil
+					}
+
+					return &proto.AcquiredJob{
+						JobId:       "test",
+						Provisioner: "someprovisioner",
+						TemplateSourceArchive: createTar(t, map[string]string{
+							"test.txt": "content",
+						}),
+						Type: &proto.AcquiredJob_WorkspaceBuild_{
+							WorkspaceBuild: &proto.AcquiredJob_WorkspaceBuild{
+								Metadata: &sdkproto.Provision_Metadata{},
+							},
+						},
+						MaxDurationMs: 100,
+					}, nil
+				},
+				updateJob: noopUpdateJob,
+				failJob: func(ctx context.Context, job *proto.FailedJob) (*proto.Empty, error) {
+					failedJob.Store(job)
+					completeOnce.Do(func() { close(completeChan) })
+					return &proto.Empty{}, nil
+				},
+			}), nil
+		}, provisionerd.Provisioners{
+			"someprovisioner": createProvisionerClient(t, done, provisionerTestServer{
+				provision: func(stream sdkproto.DRPCProvisioner_ProvisionStream) error {
+					// Block until the job is canceled for running too long.
+					for {
+						msg, err := stream.Recv()
+						if err != nil {
+							return err
+						}
+						if msg.GetCancel() != nil {
+							break
+						}
+					}
+					return stream.Send(&sdkproto.Provision_Response{
+						Type: &sdkproto.Provision_Response_Complete{
+							Complete: &sdkproto.Provision_Complete{
+								Error: "canceled",
+							},
+						},
+					})
+				},
+			}),
+		})
+		require.Condition(t, closedWithin(completeChan, testutil.WaitShort))
+		require.NoError(t, closer.Close())
+		job := failedJob.Load()
+		require.NotNil(t, job)
+		require.Equal(t, runner.BuildTimeoutErrorCode, job.ErrorCode)
+		require.NotNil(t, job.GetWorkspaceBuild())
+	})
+
+	t.Run("WorkspaceBuildForceCancel", func(t *testing.T) {
+		t.Parallel()
+		done := make(chan struct{})
+		t.Cleanup(func() {
+			close(done)
+		})
+		var (
+			didAcquireJob atomic.Bool
+			failedJob     atomic.Pointer[proto.FailedJob]
+			completeChan  = make(chan struct{})
+			completeOnce  sync.Once
+		)
+
+		server := provisionerd.New(func(ctx context.Context) (proto.DRPCProvisionerDaemonClient, error) {
+			return createProvisionerDaemonClient(t, done, provisionerDaemonTestServer{
+				acquireJob: func(ctx context.Context, _ *proto.Empty) (*proto.AcquiredJob, error) {
+					if !didAcquireJob.CAS(false, true) {
+						return &proto.AcquiredJob{}, nil
+					}
+
+					return &proto.AcquiredJob{
+						JobId:       "test",
+						Provisioner: "someprovisioner",
+						TemplateSourceArchive: createTar(t, map[string]string{
+							"test.txt": "content",
+						}),
+						Type: &proto.AcquiredJob_WorkspaceBuild_{
+							WorkspaceBuild: &proto.AcquiredJob_WorkspaceBuild{
+								Metadata: &sdkproto.Provision_Metadata{},
+							},
+						},
+					}, nil
+				},
+				updateJob: func(ctx context.Context, update *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error) {
+					return &proto.UpdateJobResponse{
+						Canceled: true,
+					}, nil
+				},
+				failJob: func(ctx context.Context, job *proto.FailedJob) (*proto.Empty, error) {
+					failedJob.Store(job)
+					completeOnce.Do(func() { close(completeChan) })
+					return &proto.Empty{}, nil
+				},
+			}), nil
+		}, &provisionerd.Options{
+			Logger:              slogtest.Make(t, &slogtest.Options{IgnoreErrors: true}).Named("provisionerd").Leveled(slog.LevelDebug),
+			JobPollInterval:     50 * time.Millisecond,
+			UpdateInterval:      50 * time.Millisecond,
+			ForceCancelInterval: 100 * time.Millisecond,
+			Provisioners: provisionerd.Provisioners{
+				"someprovisioner": createProvisionerClient(t, done, provisionerTestServer{
+					provision: func(stream sdkproto.DRPCProvisioner_ProvisionStream) error {
+						// Ignore the graceful cancellation, and only stop once
+						// the cancellation is forced.
+						for {
+							msg, err := stream.Recv()
+							if err != nil {
+								return err
+							}
+							if msg.GetCancel().GetForce() {
+								break
+							}
+						}
+						return stream.Send(&sdkproto.Provision_Response{
+							Type: &sdkproto.Provision_Response_Complete{
+								Complete: &sdkproto.Provision_Complete{
+									State: []byte("partial"),
+									Error: "killed",
+								},
+							},
+						})
+					},
+				}),
+			},
+			WorkDirectory: t.TempDir(),
+		})
+		require.Condition(t, closedWithin(completeChan, testutil.WaitShort))
+		require.NoError(t, server.Close())
+		job := failedJob.Load()
+		require.NotNil(t, job)
+		require.Equal(t, proto.CancelOutcome_FORCED, job.CancelOutcome)
+		require.Equal(t, []byte("partial"), job.GetWorkspaceBuild().GetState())
+	})
+
 	t.Run("Shutdown", func(t *testing.T) {
 		t.Parallel()
 		done := make(chan struct{})
@@ -797,6 +1197,7 @@ func TestProvisionerd(t *testing.T) {
 					return resp, nil
 				},
 				failJob: func(ctx context.Context, job *proto.FailedJob) (*proto.Empty, error) {
+					assert.Equal(t, proto.CancelOutcome_GRACEFUL, job.CancelOutcome)
 					completed.Do(func() {
 						close(completeChan)
 					})
@@ -1215,17 +1616,27 @@ func (p *provisionerTestServer) Provision(stream sdkproto.DRPCProvisioner_Provis
 // Fulfills the protobuf interface for a ProvisionerDaemon with
 // passable functions for dynamic functionality.
 type provisionerDaemonTestServer struct {
-	acquireJob  func(ctx context.Context, _ *proto.Empty) (*proto.AcquiredJob, error)
-	commitQuota func(ctx context.Context, com *proto.CommitQuotaRequest) (*proto.CommitQuotaResponse, error)
-	updateJob   func(ctx context.Context, update *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error)
-	failJob     func(ctx context.Context, job *proto.FailedJob) (*proto.Empty, error)
-	completeJob func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error)
+	acquireJob func(ctx context.Context, _ *proto.Empty) (*proto.AcquiredJob, error)
+	// acquireJobWithWait is optional. When unset, the daemon falls back to
+	// polling acquireJob.
+	acquireJobWithWait func(ctx context.Context, req *proto.AcquireJobWithWaitRequest) (*proto.AcquiredJob, error)
+	commitQuota        func(ctx context.Context, com *proto.CommitQuotaRequest) (*proto.CommitQuotaResponse, error)
+	updateJob          func(ctx context.Context, update *proto.UpdateJobRequest) (*proto.UpdateJobResponse, error)
+	failJob            func(ctx context.Context, job *proto.FailedJob) (*proto.Empty, error)
+	completeJob        func(ctx context.Context, job *proto.CompletedJob) (*proto.Empty, error)
 }
 
 func (p *provisionerDaemonTestServer) AcquireJob(ctx context.Context, empty *proto.Empty) (*proto.AcquiredJob, error) {
 	return p.acquireJob(ctx, empty)
 }
 
+func (p *provisionerDaemonTestServer) AcquireJobWithWait(ctx context.Context, req *proto.AcquireJobWithWaitRequest) (*proto.AcquiredJob, error) {
+	if p.acquireJobWithWait == nil {
+		return nil, drpcerr.WithCode(xerrors.New("Unimplemented"), drpcerr.Unimplemented)
+	}
+	return p.acquireJobWithWait(ctx, req)
+}
+
 func (p *provisionerDaemonTestServer) CommitQuota(ctx context.Context, com *proto.CommitQuotaRequest) (*proto.CommitQuotaResponse, error) {
 	if p.commitQuota == nil {
 		return &proto.CommitQuotaResponse{
@@ -0,0 +1,344 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/afero"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+
+	"cdr.dev/slog"
+	"github.com/coder/coder/v2/provisionerd/proto"
+	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
+)
+
+// BuildHooksFile is the location we look for the pre-build, post-build and
+// clone hooks of template versions.
+const BuildHooksFile = "build-hooks.yaml"
+
+const (
+	// BuildHookPhasePre hooks run before the workspace is planned.
+	BuildHookPhasePre = "pre"
+	// BuildHookPhasePost hooks run after the workspace was applied.
+	BuildHookPhasePost = "post"
+	// BuildHookPhaseClone hooks run after the first build of a cloned
+	// workspace was applied, before the post-build hooks, to copy the data
+	// of the workspace it was cloned from.
+	BuildHookPhaseClone = "clone"
+
+	defaultBuildHookTimeout    = 5 * time.Minute
+	defaultBuildHookRetryDelay = 5 * time.Second
+	maxBuildHookRetries        = 10
+	// buildHookWaitDelay is how long we wait for the output of a killed
+	// hook, e.g. when it left a background process running.
+	buildHookWaitDelay = 10 * time.Second
+)
+
+// BuildHooks are the hooks a template runs around workspace builds.
+type BuildHooks struct {
+	Pre   []BuildHook `yaml:"pre"`
+	Post  []BuildHook `yaml:"post"`
+	Clone []BuildHook `yaml:"clone"`
+}
+
+// BuildHook is a script the provisioner daemon runs before or after a
+// workspace build, e.g. to register DNS records or notify a webhook.
+type BuildHook struct {
+	Name string `yaml:"name"`
+	// Run is a shell script, run with sh in the template directory.
+	Run string `yaml:"run"`
+	// Transitions are the workspace transitions (start, stop or delete) the
+	// hook runs for. Empty means all of them.
+	Transitions []string `yaml:"transitions"`
+	// Timeout is how long an attempt may run. It defaults to 5 minutes.
+	Timeout time.Duration `yaml:"timeout"`
+	// Retries is how many times a failed hook is retried.
+	Retries int `yaml:"retries"`
+	// RetryDelay is how long to wait between attempts. It defaults to 5
+	// seconds.
+	RetryDelay time.Duration `yaml:"retry_delay"`
+	// ContinueOnError lets the build continue when a pre-build hook fails.
+	// Post-build and clone hooks never fail the build, since the workspace
+	// has already changed when they run.
+	ContinueOnError bool `yaml:"continue_on_error"`
+}
+
+// ParseBuildHooks parses and validates the contents of a BuildHooksFile.
+func ParseBuildHooks(data []byte) (BuildHooks, error) {
+	var hooks BuildHooks
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err := decoder.Decode(&hooks)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return BuildHooks{}, err
+	}
+	for _, phase := range []struct {
+		name  string
+		hooks []BuildHook
+	}{
+		{BuildHookPhasePre, hooks.Pre},
+		{BuildHookPhasePost, hooks.Post},
+		{BuildHookPhaseClone, hooks.Clone},
+	} {
+		names := map[string]struct{}{}
+		for i, hook := range phase.hooks {
+			if hook.Name == "" {
+				return BuildHooks{}, xerrors.Errorf("%s hook %d: name is required", phase.name, i)
+			}
+			if _, ok := names[hook.Name]; ok {
+				return BuildHooks{}, xerrors.Errorf("%s hook %q: name must be unique", phase.name, hook.Name)
+			}
+			names[hook.Name] = struct{}{}
+			if strings.TrimSpace(hook.Run) == "" {
+				return BuildHooks{}, xerrors.Errorf("%s hook %q: run is required", phase.name, hook.Name)
+			}
+			for _, transition := range hook.Transitions {
+				switch transition {
+				case "start", "stop", "delete":
+				default:
+					return BuildHooks{}, xerrors.Errorf("%s hook %q: transition %q must be one of start, stop or delete", phase.name, hook.Name, transition)
+				}
+			}
+			if hook.Timeout < 0 || hook.RetryDelay < 0 {
+				return BuildHooks{}, xerrors.Errorf("%s hook %q: timeout and retry_delay must not be negative", phase.name, hook.Name)
+			}
+			if hook.Retries < 0 || hook.Retries > maxBuildHookRetries {
+				return BuildHooks{}, xerrors.Errorf("%s hook %q: retries must be between 0 and %d", phase.name, hook.Name, maxBuildHookRetries)
+			}
+			if hook.ContinueOnError && phase.name != BuildHookPhasePre {
+				return BuildHooks{}, xerrors.Errorf("%s hook %q: continue_on_error is only supported by pre hooks, %s hooks never fail the build", phase.name, hook.Name, phase.name)
+			}
+			if len(hook.Transitions) > 0 && phase.name == BuildHookPhaseClone {
+				return BuildHooks{}, xerrors.Errorf("clone hook %q: transitions are not supported, clone hooks only run for the first start of a cloned workspace", hook.Name)
+			}
+		}
+	}
+	return hooks, nil
+}
+
+// runsFor returns true if the hook runs for builds of the transition.
+func (h BuildHook) runsFor(transition string) bool {
+	if len(h.Transitions) == 0 {
+		return true
+	}
+	for _, t := range h.Transitions {
+		if t == transition {
+			return true
+		}
+	}
+	return false
+}
+
+// readBuildHooks reads the hooks of the template. Templates without a
+// BuildHooksFile have none.
+func (r *Runner) readBuildHooks() (BuildHooks, error) {
+	data, err := afero.ReadFile(r.filesystem, path.Join(r.workDirectory, BuildHooksFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return BuildHooks{}, nil
+		}
+		return BuildHooks{}, xerrors.Errorf("read %s: %w", BuildHooksFile, err)
+	}
+	hooks, err := ParseBuildHooks(data)
+	if err != nil {
+		return BuildHooks{}, xerrors.Errorf("parse %s: %w", BuildHooksFile, err)
+	}
+	return hooks, nil
+}
+
+// buildTransition returns the transition of the workspace build as named
+// in build hooks.
+func (r *Runner) buildTransition() string {
+	switch r.job.GetWorkspaceBuild().GetMetadata().GetWorkspaceTransition() {
+	case sdkproto.WorkspaceTransition_STOP:
+		return "stop"
+	case sdkproto.WorkspaceTransition_DESTROY:
+		return "delete"
+	default:
+		return "start"
+	}
+}
+
+// runBuildHooks runs the hooks of the phase for the workspace build and
+// reports their results. A failed pre-build hook fails the build unless it
+// continues on error.
+func (r *Runner) runBuildHooks(ctx context.Context, phase string, hooks []BuildHook) *proto.FailedJob {
+	transition := r.buildTransition()
+	for _, hook := range hooks {
+		if !hook.runsFor(transition) {
+			continue
+		}
+		result := r.runBuildHook(ctx, phase, hook)
+		r.flushQueuedLogs(ctx)
+		_, err := r.update(ctx, &proto.UpdateJobRequest{
+			JobId:            r.job.JobId,
+			BuildHookResults: []*proto.BuildHookResult{result},
+		})
+		if err != nil && !errors.Is(err, errUpdateSkipped) {
+			r.logger.Error(ctx, "report build hook result", slog.F("hook", hook.Name), slog.Error(err))
+		}
+		if result.Error == "" {
+			continue
+		}
+		if phase == BuildHookPhasePre && !hook.ContinueOnError {
+			return r.failedWorkspaceBuildf("pre-build hook %q failed: %s", hook.Name, result.Error)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) runBuildHook(ctx context.Context, phase string, hook BuildHook) *proto.BuildHookResult {
+	stage := fmt.Sprintf("Running %s-build hook %s", phase, hook.Name)
+	if phase == BuildHookPhaseClone {
+		stage = fmt.Sprintf("Running clone hook %s", hook.Name)
+	}
+	r.queueLog(ctx, &proto.Log{
+		Source:    proto.LogSource_PROVISIONER_DAEMON,
+		Level:     sdkproto.LogLevel_INFO,
+		Stage:     stage,
+		CreatedAt: time.Now().UnixMilli(),
+	})
+
+	retryDelay := hook.RetryDelay
+	if retryDelay == 0 {
+		retryDelay = defaultBuildHookRetryDelay
+	}
+	result := &proto.BuildHookResult{
+		Name:      hook.Name,
+		Phase:     phase,
+		StartedAt: time.Now().UnixMilli(),
+	}
+	attempts := hook.Retries + 1
+	for attempt := 1; attempt <= attempts; attempt++ {
+		result.Attempts = int32(attempt)
+		exitCode, err := r.execBuildHook(ctx, stage, phase, hook, attempt)
+		result.ExitCode = int32(exitCode)
+		if err == nil {
+			result.Error = ""
+			break
+		}
+		result.Error = err.Error()
+		if attempt == attempts || r.notCanceled.Err() != nil {
+			r.queueLog(ctx, &proto.Log{
+				Source:    proto.LogSource_PROVISIONER_DAEMON,
+				Level:     sdkproto.LogLevel_ERROR,
+				Stage:     stage,
+				CreatedAt: time.Now().UnixMilli(),
+				Output:    fmt.Sprintf("Hook %s.", err),
+			})
+			break
+		}
+		r.queueLog(ctx, &proto.Log{
+			Source:    proto.LogSource_PROVISIONER_DAEMON,
+			Level:     sdkproto.LogLevel_WARN,
+			Stage:     stage,
+			CreatedAt: time.Now().UnixMilli(),
+			Output:    fmt.Sprintf("Hook %s, retrying in %s (attempt %d of %d)...", err, retryDelay, attempt+1, attempts),
+		})
+		timer := time.NewTimer(retryDelay)
+		select {
+		case <-r.notCanceled.Done():
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+	result.CompletedAt = time.Now().UnixMilli()
+	return result
+}
+
+// execBuildHook runs an attempt of the hook and returns its exit code.
+// Canceling the job kills the hook.
+func (r *Runner) execBuildHook(ctx context.Context, stage, phase string, hook BuildHook, attempt int) (int, error) {
+	timeout := hook.Timeout
+	if timeout == 0 {
+		timeout = defaultBuildHookTimeout
+	}
+	hookCtx, cancel := context.WithTimeout(r.notCanceled, timeout)
+	defer cancel()
+
+	stdout, stdoutDone := r.buildHookLogWriter(ctx, stage, sdkproto.LogLevel_INFO)
+	stderr, stderrDone := r.buildHookLogWriter(ctx, stage, sdkproto.LogLevel_WARN)
+	// #nosec G204 -- Hooks are part of the template, like its Terraform.
+	cmd := exec.CommandContext(hookCtx, "sh", "-c", hook.Run)
+	cmd.Dir = r.workDirectory
+	cmd.Env = append(os.Environ(), r.buildHookEnv(phase, attempt)...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = buildHookWaitDelay
+	err := cmd.Run()
+	_ = stdout.Close()
+	_ = stderr.Close()
+	<-stdoutDone
+	<-stderrDone
+
+	switch {
+	case err == nil:
+		return 0, nil
+	case r.notCanceled.Err() != nil:
+		return -1, xerrors.New("was canceled")
+	case errors.Is(hookCtx.Err(), context.DeadlineExceeded):
+		return -1, xerrors.Errorf("timed out after %s", timeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), xerrors.Errorf("exited with code %d", exitErr.ExitCode())
+	}
+	return -1, xerrors.Errorf("failed to run: %w", err)
+}
+
+// buildHookEnv returns the environment variables hooks get in addition to
+// the environment of the provisioner daemon.
+func (r *Runner) buildHookEnv(phase string, attempt int) []string {
+	build := r.job.GetWorkspaceBuild()
+	metadata := build.GetMetadata()
+	return []string{
+		"CODER_URL=" + metadata.GetCoderUrl(),
+		"CODER_WORKSPACE_ID=" + metadata.GetWorkspaceId(),
+		"CODER_WORKSPACE_NAME=" + metadata.GetWorkspaceName(),
+		"CODER_WORKSPACE_OWNER=" + metadata.GetWorkspaceOwner(),
+		"CODER_WORKSPACE_OWNER_EMAIL=" + metadata.GetWorkspaceOwnerEmail(),
+		"CODER_WORKSPACE_BUILD_ID=" + build.GetWorkspaceBuildId(),
+		"CODER_WORKSPACE_TRANSITION=" + r.buildTransition(),
+		"CODER_TEMPLATE_NAME=" + metadata.GetTemplateName(),
+		"CODER_TEMPLATE_VERSION=" + metadata.GetTemplateVersion(),
+		"CODER_BUILD_HOOK_PHASE=" + phase,
+		"CODER_BUILD_HOOK_ATTEMPT=" + strconv.Itoa(attempt),
+		"CODER_CLONE_SOURCE_WORKSPACE_ID=" + build.GetCloneSourceWorkspaceId(),
+		"CODER_CLONE_SOURCE_WORKSPACE_NAME=" + build.GetCloneSourceWorkspaceName(),
+	}
+}
+
+// buildHookLogWriter returns a writer that queues each line written to it
+// as a log of the stage. The returned channel is closed once the writer was
+// closed and all lines were queued.
+func (r *Runner) buildHookLogWriter(ctx context.Context, stage string, level sdkproto.LogLevel) (io.WriteCloser, <-chan struct{}) {
+	reader, writer := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			r.queueLog(ctx, &proto.Log{
+				Source:    proto.LogSource_PROVISIONER,
+				Level:     level,
+				CreatedAt: time.Now().UnixMilli(),
+				Output:    scanner.Text(),
+				Stage:     stage,
+			})
+		}
+		// Keep the hook from blocking on lines too long to scan.
+		_, _ = io.Copy(io.Discard, reader)
+	}()
+	return writer, done
+}
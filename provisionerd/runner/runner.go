@@ -29,6 +29,7 @@ import (
 	"github.com/coder/coder/v2/coderd/tracing"
 	"github.com/coder/coder/v2/coderd/util/ptr"
 	"github.com/coder/coder/v2/provisionerd/proto"
+	"github.com/coder/coder/v2/provisionersdk"
 	sdkproto "github.com/coder/coder/v2/provisionersdk/proto"
 )
 
@@ -38,15 +39,27 @@ const (
 
 	RequiredTemplateVariablesErrorCode = "REQUIRED_TEMPLATE_VARIABLES"
 	requiredTemplateVariablesErrorText = "required template variables"
+
+	BuildTimeoutErrorCode = "BUILD_TIMEOUT"
+	buildTimeoutErrorText = "build exceeded the maximum duration"
+
+	ResourceLimitExceededErrorCode = "RESOURCE_LIMIT_EXCEEDED"
 )
 
 var errorCodes = map[string]string{
 	MissingParameterErrorCode:          missingParameterErrorText,
 	RequiredTemplateVariablesErrorCode: requiredTemplateVariablesErrorText,
+	BuildTimeoutErrorCode:              buildTimeoutErrorText,
+	ResourceLimitExceededErrorCode:     provisionersdk.ResourceLimitExceededError,
 }
 
 var errUpdateSkipped = xerrors.New("update skipped; job complete or failed")
 
+// forceCancelTimeout is the longest we wait for the provisioner to report the
+// state it captured after a forced cancellation before failing the job
+// without it. Shorter force cancel intervals shorten this wait too.
+const forceCancelTimeout = 30 * time.Second
+
 type Runner struct {
 	tracer              trace.Tracer
 	metrics             Metrics
@@ -61,12 +74,20 @@ type Runner struct {
 	updateInterval      time.Duration
 	forceCancelInterval time.Duration
 	logBufferInterval   time.Duration
+	// timedOut is set when the job is canceled for exceeding its maximum
+	// duration.
+	timedOut atomic.Bool
 
 	// closed when the Runner is finished sending any updates/failed/complete.
 	done chan struct{}
 	// active as long as we are not canceled
 	notCanceled context.Context
 	cancel      context.CancelFunc
+	// active as long as we haven't forced the provisioner to stop after a
+	// graceful cancellation timed out
+	notForceCanceled context.Context
+	forceCancel      context.CancelFunc
+	forceCanceled    atomic.Bool
 	// active as long as we haven't been force stopped
 	notStopped context.Context
 	stop       context.CancelFunc
@@ -128,6 +149,7 @@ func New(
 	// we need to create our contexts here in case a call to Cancel() comes immediately.
 	forceStopContext, forceStopFunc := context.WithCancel(ctx)
 	gracefulContext, cancelFunc := context.WithCancel(forceStopContext)
+	forceCancelContext, forceCancelFunc := context.WithCancel(forceStopContext)
 
 	logger := opts.Logger.With(slog.F("job_id", job.JobId))
 	if build := job.GetWorkspaceBuild(); build != nil {
@@ -164,6 +186,8 @@ func New(
 		stop:                forceStopFunc,
 		notCanceled:         gracefulContext,
 		cancel:              cancelFunc,
+		notForceCanceled:    forceCancelContext,
+		forceCancel:         forceCancelFunc,
 	}
 }
 
@@ -209,6 +233,9 @@ func (r *Runner) Run() {
 
 	go r.doCleanFinish(ctx)
 	go r.heartbeatRoutine(ctx)
+	if r.job.MaxDurationMs > 0 {
+		go r.timeoutRoutine(ctx, time.Duration(r.job.MaxDurationMs)*time.Millisecond)
+	}
 	for r.failedJob == nil && r.completedJob == nil {
 		r.cond.Wait()
 	}
@@ -417,6 +444,19 @@ func (r *Runner) doCleanFinish(ctx context.Context) {
 	}()
 
 	completedJob, failedJob = r.do(ctx)
+	if failedJob != nil && r.timedOut.Load() {
+		// The failure is the result of canceling the job, so report why it
+		// was canceled instead.
+		timeoutJob := r.failedJobf("%s of %s", buildTimeoutErrorText, time.Duration(r.job.MaxDurationMs)*time.Millisecond)
+		timeoutJob.Type = failedJob.Type
+		failedJob = timeoutJob
+	}
+	if failedJob != nil && r.notCanceled.Err() != nil {
+		failedJob.CancelOutcome = proto.CancelOutcome_GRACEFUL
+		if r.forceCanceled.Load() {
+			failedJob.CancelOutcome = proto.CancelOutcome_FORCED
+		}
+	}
 }
 
 // do actually does the work of running the job
@@ -556,27 +596,102 @@ func (r *Runner) heartbeatRoutine(ctx context.Context) {
 			continue
 		}
 		r.logger.Info(ctx, "attempting graceful cancellation")
-		r.Cancel()
-		// Mark the job as failed after a minute of pending cancellation.
-		timer := time.NewTimer(r.forceCancelInterval)
-		select {
-		case <-timer.C:
-			r.logger.Debug(ctx, "cancel timed out")
-			err := r.Fail(ctx, r.failedJobf("Cancel timed out"))
-			if err != nil {
-				r.logger.Warn(ctx, "failed to call FailJob", slog.Error(err))
-			}
-			return
-		case <-r.Done():
-			timer.Stop()
-			return
-		case <-r.notStopped.Done():
-			timer.Stop()
-			return
+		r.cancelWithTimeout(ctx, "Cancel timed out")
+		return
+	}
+}
+
+// cancelWithTimeout gracefully cancels the job, and forces the provisioner to
+// stop if the job doesn't finish within the force cancel interval. If the
+// provisioner doesn't report the state it captured soon after that, the job is
+// failed with timeoutMessage.
+func (r *Runner) cancelWithTimeout(ctx context.Context, timeoutMessage string) {
+	r.Cancel()
+	timer := time.NewTimer(r.forceCancelInterval)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-r.Done():
+		return
+	case <-r.notStopped.Done():
+		return
+	}
+
+	r.logger.Info(ctx, "graceful cancellation timed out; forcing cancellation")
+	r.forceCanceled.Store(true)
+	r.forceCancel()
+
+	timeout := forceCancelTimeout
+	if r.forceCancelInterval < timeout {
+		timeout = r.forceCancelInterval
+	}
+	timer.Reset(timeout)
+	select {
+	case <-timer.C:
+		r.logger.Debug(ctx, "cancel timed out")
+		failedJob := r.failedJobf("%s", timeoutMessage)
+		failedJob.CancelOutcome = proto.CancelOutcome_FORCED
+		err := r.Fail(ctx, failedJob)
+		if err != nil {
+			r.logger.Warn(ctx, "failed to call FailJob", slog.Error(err))
 		}
+	case <-r.Done():
+	case <-r.notStopped.Done():
 	}
 }
 
+// forwardCancel sends a cancel request to the provisioner when the job is
+// canceled, and a forced one when the graceful cancellation times out.
+func (r *Runner) forwardCancel(stream sdkproto.DRPCProvisioner_ProvisionClient) {
+	select {
+	case <-r.notStopped.Done():
+		return
+	case <-r.notCanceled.Done():
+		_ = stream.Send(&sdkproto.Provision_Request{
+			Type: &sdkproto.Provision_Request_Cancel{
+				Cancel: &sdkproto.Provision_Cancel{},
+			},
+		})
+	}
+	<-r.notForceCanceled.Done()
+	if r.notStopped.Err() != nil {
+		return
+	}
+	_ = stream.Send(&sdkproto.Provision_Request{
+		Type: &sdkproto.Provision_Request_Cancel{
+			Cancel: &sdkproto.Provision_Cancel{
+				Force: true,
+			},
+		},
+	})
+}
+
+// timeoutRoutine cancels the job once it has run for longer than maxDuration.
+func (r *Runner) timeoutRoutine(ctx context.Context, maxDuration time.Duration) {
+	ctx, span := r.startTrace(ctx, tracing.FuncName())
+	defer span.End()
+
+	timer := time.NewTimer(maxDuration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-r.notCanceled.Done():
+		return
+	}
+
+	r.logger.Info(ctx, "job exceeded the maximum duration; attempting graceful cancellation",
+		slog.F("max_duration", maxDuration))
+	r.timedOut.Store(true)
+	r.queueLog(ctx, &proto.Log{
+		Source:    proto.LogSource_PROVISIONER_DAEMON,
+		Level:     sdkproto.LogLevel_ERROR,
+		Stage:     "Build timed out",
+		Output:    fmt.Sprintf("The build exceeded the maximum duration of %s and is being canceled.", maxDuration),
+		CreatedAt: time.Now().UnixMilli(),
+	})
+	r.cancelWithTimeout(ctx, fmt.Sprintf("%s of %s; cancel timed out", buildTimeoutErrorText, maxDuration))
+}
+
 // ReadmeFile is the location we look for to extract documentation from template
 // versions.
 const ReadmeFile = "README.md"
@@ -616,6 +731,12 @@ func (r *Runner) runTemplateImport(ctx context.Context) (*proto.CompletedJob, *p
 	ctx, span := r.startTrace(ctx, tracing.FuncName())
 	defer span.End()
 
+	// Fail invalid build hooks now rather than in every workspace build.
+	_, err := r.readBuildHooks()
+	if err != nil {
+		return nil, r.failedJobf("%s", err)
+	}
+
 	// Parse parameters and update the job with the parameter specs
 	r.queueLog(ctx, &proto.Log{
 		Source:    proto.LogSource_PROVISIONER_DAEMON,
@@ -759,24 +880,15 @@ func (r *Runner) runTemplateImportProvisionWithRichParameters(ctx context.Contex
 		return nil, xerrors.Errorf("provision: %w", err)
 	}
 	defer stream.Close()
-	go func() {
-		select {
-		case <-r.notStopped.Done():
-			return
-		case <-r.notCanceled.Done():
-			_ = stream.Send(&sdkproto.Provision_Request{
-				Type: &sdkproto.Provision_Request_Cancel{
-					Cancel: &sdkproto.Provision_Cancel{},
-				},
-			})
-		}
-	}()
+	go r.forwardCancel(stream)
 	err = stream.Send(&sdkproto.Provision_Request{
 		Type: &sdkproto.Provision_Request_Plan{
 			Plan: &sdkproto.Provision_Plan{
 				Config: &sdkproto.Provision_Config{
-					Directory: r.workDirectory,
-					Metadata:  metadata,
+					Directory:        r.workDirectory,
+					Metadata:         metadata,
+					MemoryLimitBytes: r.job.ProvisionerMemoryLimitBytes,
+					CpuLimitMs:       r.job.ProvisionerCpuLimitMs,
 				},
 				RichParameterValues: richParameterValues,
 				VariableValues:      variableValues,
@@ -894,18 +1006,7 @@ func (r *Runner) buildWorkspace(ctx context.Context, stage string, req *sdkproto
 		return nil, r.failedWorkspaceBuildf("provision: %s", err)
 	}
 	defer stream.Close()
-	go func() {
-		select {
-		case <-r.notStopped.Done():
-			return
-		case <-r.notCanceled.Done():
-			_ = stream.Send(&sdkproto.Provision_Request{
-				Type: &sdkproto.Provision_Request_Cancel{
-					Cancel: &sdkproto.Provision_Cancel{},
-				},
-			})
-		}
-	}()
+	go r.forwardCancel(stream)
 
 	err = stream.Send(req)
 	if err != nil {
@@ -940,8 +1041,9 @@ func (r *Runner) buildWorkspace(ctx context.Context, stage string, req *sdkproto
 				)
 
 				return nil, &proto.FailedJob{
-					JobId: r.job.JobId,
-					Error: msgType.Complete.Error,
+					JobId:     r.job.JobId,
+					Error:     msgType.Complete.Error,
+					ErrorCode: errorCode(msgType.Complete.Error),
 					Type: &proto.FailedJob_WorkspaceBuild_{
 						WorkspaceBuild: &proto.FailedJob_WorkspaceBuild{
 							State: msgType.Complete.State,
@@ -1041,6 +1143,18 @@ func (r *Runner) runWorkspaceBuild(ctx context.Context) (*proto.CompletedJob, *p
 		State:     r.job.GetWorkspaceBuild().State,
 
 		ProvisionerLogLevel: r.job.GetWorkspaceBuild().LogLevel,
+		MemoryLimitBytes:    r.job.ProvisionerMemoryLimitBytes,
+		CpuLimitMs:          r.job.ProvisionerCpuLimitMs,
+	}
+
+	hooks, err := r.readBuildHooks()
+	if err != nil {
+		return nil, r.failedWorkspaceBuildf("%s", err)
+	}
+	failed := r.runBuildHooks(ctx, BuildHookPhasePre, hooks.Pre)
+	r.flushQueuedLogs(ctx)
+	if failed != nil {
+		return nil, failed
 	}
 
 	completedPlan, failed := r.buildWorkspace(ctx, "Planning infrastructure", &sdkproto.Provision_Request{
@@ -1085,6 +1199,26 @@ func (r *Runner) runWorkspaceBuild(ctx context.Context) (*proto.CompletedJob, *p
 	}
 	r.flushQueuedLogs(ctx)
 
+	if source := r.job.GetWorkspaceBuild().GetCloneSourceWorkspaceName(); source != "" {
+		if len(hooks.Clone) == 0 {
+			r.queueLog(ctx, &proto.Log{
+				Source:    proto.LogSource_PROVISIONER_DAEMON,
+				Level:     sdkproto.LogLevel_WARN,
+				Stage:     "Copying workspace data",
+				CreatedAt: time.Now().UnixMilli(),
+				Output:    fmt.Sprintf("The template has no clone hooks, the data of workspace %q was not copied.", source),
+			})
+		}
+		// Like post-build hooks, clone hooks can't fail the build.
+		_ = r.runBuildHooks(ctx, BuildHookPhaseClone, hooks.Clone)
+		r.flushQueuedLogs(ctx)
+	}
+
+	// Post-build hooks can't fail the build, since the workspace has
+	// already changed.
+	_ = r.runBuildHooks(ctx, BuildHookPhasePost, hooks.Post)
+	r.flushQueuedLogs(ctx)
+
 	return &proto.CompletedJob{
 		JobId: r.job.JobId,
 		Type: &proto.CompletedJob_WorkspaceBuild_{
@@ -1104,19 +1238,21 @@ func (r *Runner) failedWorkspaceBuildf(format string, args ...interface{}) *prot
 
 func (r *Runner) failedJobf(format string, args ...interface{}) *proto.FailedJob {
 	message := fmt.Sprintf(format, args...)
-	var code string
+	return &proto.FailedJob{
+		JobId:     r.job.JobId,
+		Error:     message,
+		ErrorCode: errorCode(message),
+	}
+}
 
+// errorCode returns the code of the first known error found in message.
+func errorCode(message string) string {
 	for c, m := range errorCodes {
 		if strings.Contains(message, m) {
-			code = c
-			break
+			return c
 		}
 	}
-	return &proto.FailedJob{
-		JobId:     r.job.JobId,
-		Error:     message,
-		ErrorCode: code,
-	}
+	return ""
 }
 
 func (r *Runner) startTrace(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
@@ -3,6 +3,7 @@ package provisionersdk
 import (
 	_ "embed"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -18,8 +19,9 @@ var (
 
 	// A mapping of operating-system ($GOOS) to architecture ($GOARCH)
 	// to agent install and run script. ${DOWNLOAD_URL} is replaced
-	// with strings.ReplaceAll() when being consumed. ${ARCH} is replaced
-	// with the architecture when being provided.
+	// with strings.ReplaceAll() when being consumed. ${ARCH},
+	// ${REQUIRE_BINARY_VERIFICATION} and ${SIGNING_CERTIFICATE} are replaced
+	// when being provided.
 	agentScripts = map[string]map[string]string{
 		"windows": {
 			"amd64": windowsScript,
@@ -37,14 +39,26 @@ var (
 	}
 )
 
+// AgentScriptOptions configure the scripts returned by AgentScriptEnv.
+type AgentScriptOptions struct {
+	// RequireBinaryVerification makes the scripts refuse to run an agent
+	// binary whose checksum or signature is missing or doesn't match.
+	RequireBinaryVerification bool
+	// SigningCertificate is the PEM-encoded certificate whose key signs
+	// agent binaries.
+	SigningCertificate string
+}
+
 // AgentScriptEnv returns a key-pair of scripts that are consumed
 // by the Coder Terraform Provider. See:
 // https://github.com/coder/terraform-provider-coder/blob/main/internal/provider/provider.go#L97
-func AgentScriptEnv() map[string]string {
+func AgentScriptEnv(opts AgentScriptOptions) map[string]string {
 	env := map[string]string{}
 	for operatingSystem, scripts := range agentScripts {
 		for architecture, script := range scripts {
 			script := strings.ReplaceAll(script, "${ARCH}", architecture)
+			script = strings.ReplaceAll(script, "${REQUIRE_BINARY_VERIFICATION}", strconv.FormatBool(opts.RequireBinaryVerification))
+			script = strings.ReplaceAll(script, "${SIGNING_CERTIFICATE}", strings.TrimSpace(opts.SigningCertificate))
 			env[fmt.Sprintf("CODER_AGENT_SCRIPT_%s_%s", operatingSystem, architecture)] = script
 		}
 	}
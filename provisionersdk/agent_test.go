@@ -7,7 +7,16 @@
 package provisionersdk_test
 
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -15,7 +24,9 @@ import (
 	"os/exec"
 	"runtime"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/go-chi/render"
 	"github.com/stretchr/testify/require"
@@ -27,39 +38,167 @@ func TestAgentScript(t *testing.T) {
 	t.Parallel()
 	t.Run("Run", func(t *testing.T) {
 		t.Parallel()
-		srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
-			echoPath, err := exec.LookPath("echo")
-			require.NoError(t, err)
-			content, err := os.ReadFile(echoPath)
+		srv := newBinaryServer(t, nil)
+		output, err := runAgentScript(t, srv.URL, provisionersdk.AgentScriptOptions{})
+		require.NoError(t, err)
+		// Because we use the "echo" binary, we should expect the arguments provided
+		// as the response to executing our script.
+		require.Equal(t, "agent", lastLine(output))
+		require.True(t, srv.checksumRequested.Load(), "script must verify the binary")
+	})
+
+	t.Run("RequireVerification", func(t *testing.T) {
+		t.Parallel()
+		requireOpenSSL(t)
+		key, cert := newSigningCertificate(t)
+		srv := newBinaryServer(t, func(content []byte) []byte {
+			digest := sha256.Sum256(content)
+			signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
 			require.NoError(t, err)
-			render.Status(r, http.StatusOK)
-			render.Data(rw, r, content)
-		}))
-		defer srv.Close()
-		srvURL, err := url.Parse(srv.URL)
+			return signature
+		})
+		output, err := runAgentScript(t, srv.URL, provisionersdk.AgentScriptOptions{
+			RequireBinaryVerification: true,
+			SigningCertificate:        cert,
+		})
 		require.NoError(t, err)
+		require.Equal(t, "agent", lastLine(output))
+	})
 
-		script, exists := provisionersdk.AgentScriptEnv()[fmt.Sprintf("CODER_AGENT_SCRIPT_%s_%s", runtime.GOOS, runtime.GOARCH)]
-		if !exists {
-			t.Skip("Agent not supported...")
+	t.Run("RequireVerificationBadSignature", func(t *testing.T) {
+		t.Parallel()
+		requireOpenSSL(t)
+		_, cert := newSigningCertificate(t)
+		// Signed by a different key than the one of the certificate.
+		otherKey, _ := newSigningCertificate(t)
+		srv := newBinaryServer(t, func(content []byte) []byte {
+			digest := sha256.Sum256(content)
+			signature, err := rsa.SignPKCS1v15(rand.Reader, otherKey, crypto.SHA256, digest[:])
+			require.NoError(t, err)
+			return signature
+		})
+		output, err := runAgentScript(t, srv.URL, provisionersdk.AgentScriptOptions{
+			RequireBinaryVerification: true,
+			SigningCertificate:        cert,
+		})
+		require.Error(t, err)
+		require.Contains(t, output, "refusing to run a coder agent that could not be verified")
+		require.NotEqual(t, "agent", lastLine(output))
+	})
+
+	t.Run("RequireVerificationNoSignature", func(t *testing.T) {
+		t.Parallel()
+		requireOpenSSL(t)
+		_, cert := newSigningCertificate(t)
+		srv := newBinaryServer(t, nil)
+		output, err := runAgentScript(t, srv.URL, provisionersdk.AgentScriptOptions{
+			RequireBinaryVerification: true,
+			SigningCertificate:        cert,
+		})
+		require.Error(t, err)
+		require.Contains(t, output, "no signature available for the coder agent")
+	})
+}
+
+type binaryServer struct {
+	*httptest.Server
+	checksumRequested atomic.Bool
+}
+
+// newBinaryServer serves the "echo" binary as the agent, with its checksum
+// and, when sign is set, its signature.
+func newBinaryServer(t *testing.T, sign func(content []byte) []byte) *binaryServer {
+	t.Helper()
+	echoPath, err := exec.LookPath("echo")
+	require.NoError(t, err)
+	content, err := os.ReadFile(echoPath)
+	require.NoError(t, err)
+	checksum := sha256.Sum256(content)
+	var signature []byte
+	if sign != nil {
+		signature = sign(content)
+	}
+
+	srv := &binaryServer{}
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		// The script asks for the binary of the host rather than
+		// naming it.
+		if r.URL.Query().Get("os") != runtime.GOOS || r.URL.Query().Get("arch") == "" {
+			t.Errorf("unexpected binary request %q", r.URL.String())
+			rw.WriteHeader(http.StatusNotFound)
 			return
 		}
-		script = strings.ReplaceAll(script, "${ACCESS_URL}", srvURL.String()+"/")
-		script = strings.ReplaceAll(script, "${AUTH_TYPE}", "token")
-		// In certain distributions "echo" is a part of coreutils, and determines
-		// it's functionality based on the exec path name.
-		script = strings.ReplaceAll(script, "BINARY_NAME=coder", "BINARY_NAME=echo")
-		// This is intentionally ran in single quotes to mimic how a customer may
-		// embed our script. Our scripts should not include any single quotes.
-		// nolint:gosec
-		output, err := exec.Command("sh", "-c", "sh -c '"+script+"'").CombinedOutput()
-		t.Log(string(output))
-		require.NoError(t, err)
-		// Ignore debug output from `set -x`, we're only interested in the last line.
-		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-		lastLine := lines[len(lines)-1]
-		// Because we use the "echo" binary, we should expect the arguments provided
-		// as the response to executing our script.
-		require.Equal(t, "agent", lastLine)
-	})
+		switch r.URL.Path {
+		case "/bin/coder":
+			render.Status(r, http.StatusOK)
+			render.Data(rw, r, content)
+		case "/bin/coder.sha256":
+			srv.checksumRequested.Store(true)
+			_, _ = fmt.Fprintf(rw, "%s  coder-%s-amd64\n", hex.EncodeToString(checksum[:]), runtime.GOOS)
+		case "/bin/coder.sig":
+			if signature == nil {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = rw.Write(signature)
+		default:
+			t.Errorf("unexpected binary request %q", r.URL.String())
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func runAgentScript(t *testing.T, accessURL string, opts provisionersdk.AgentScriptOptions) (string, error) {
+	t.Helper()
+	srvURL, err := url.Parse(accessURL)
+	require.NoError(t, err)
+
+	script, exists := provisionersdk.AgentScriptEnv(opts)[fmt.Sprintf("CODER_AGENT_SCRIPT_%s_%s", runtime.GOOS, runtime.GOARCH)]
+	if !exists {
+		t.Skip("Agent not supported...")
+	}
+	script = strings.ReplaceAll(script, "${ACCESS_URL}", srvURL.String()+"/")
+	script = strings.ReplaceAll(script, "${AUTH_TYPE}", "token")
+	// In certain distributions "echo" is a part of coreutils, and determines
+	// it's functionality based on the exec path name.
+	script = strings.ReplaceAll(script, "BINARY_NAME=coder", "BINARY_NAME=echo")
+	// Don't wait for a day to preserve the logs of failures.
+	script = strings.ReplaceAll(script, "sleep 86400", "true")
+	// This is intentionally ran in single quotes to mimic how a customer may
+	// embed our script. Our scripts should not include any single quotes.
+	// nolint:gosec
+	output, err := exec.Command("sh", "-c", "sh -c '"+script+"'").CombinedOutput()
+	t.Log(string(output))
+	return string(output), err
+}
+
+// lastLine ignores debug output from `set -x`, we're only interested in the
+// last line.
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return lines[len(lines)-1]
+}
+
+func requireOpenSSL(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("openssl"); err != nil {
+		t.Skip("openssl is required to verify signatures")
+	}
+}
+
+func newSigningCertificate(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "coder"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
 }
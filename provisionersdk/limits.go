@@ -0,0 +1,5 @@
+package provisionersdk
+
+// ResourceLimitExceededError is included in the error of a provision that
+// failed because a process exceeded the memory or CPU limits of the job.
+const ResourceLimitExceededError = "provisioner resource limit exceeded"
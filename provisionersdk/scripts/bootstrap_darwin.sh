@@ -10,16 +10,71 @@ waitonexit() {
 trap waitonexit EXIT
 BINARY_DIR=$(mktemp -d -t coder.XXXXXX)
 BINARY_NAME=coder
-BINARY_URL=${ACCESS_URL}bin/coder-darwin-${ARCH}
+# Let Coder pick the binary for the architecture of the host, which may differ
+# from the one declared in the template, e.g. on Apple silicon.
+HOST_ARCH=$(uname -m 2>/dev/null || echo "${ARCH}")
+BINARY_URL="${ACCESS_URL}bin/coder?os=darwin&arch=${HOST_ARCH}"
+CHECKSUM_URL="${ACCESS_URL}bin/coder.sha256?os=darwin&arch=${HOST_ARCH}"
+SIGNATURE_URL="${ACCESS_URL}bin/coder.sig?os=darwin&arch=${HOST_ARCH}"
+REQUIRE_VERIFICATION=${REQUIRE_BINARY_VERIFICATION}
 cd "$BINARY_DIR"
+# Compare the binary with the checksum served by Coder, so a tampered mirror
+# or proxy cannot substitute the agent. Deployments that do not serve
+# checksums are trusted as before, unless the template requires the agent to
+# be verified.
+verify() {
+	if ! curl -fsSL "${CHECKSUM_URL}" -o "${BINARY_NAME}.sha256"; then
+		if [ "${REQUIRE_VERIFICATION}" = true ]; then
+			echo "error: no checksum available, but the template requires the coder agent to be verified"
+			return 1
+		fi
+		echo "warning: no checksum available for the coder agent, skipping verification"
+		return 0
+	fi
+	expected=$(cut -d" " -f1 "${BINARY_NAME}.sha256")
+	actual=$(shasum -a 256 "${BINARY_NAME}" | cut -d" " -f1)
+	if [ "${expected}" != "${actual}" ]; then
+		echo "error: checksum of the downloaded coder agent does not match"
+		return 1
+	fi
+	if [ "${REQUIRE_VERIFICATION}" = true ]; then
+		verify_signature
+	fi
+}
+# The signature is checked against the certificate configured for the
+# deployment when the workspace was built, not one served with the binary.
+verify_signature() {
+	cat >signing.pem <<EOF
+${SIGNING_CERTIFICATE}
+EOF
+	if ! openssl x509 -pubkey -noout -in signing.pem >signing.pub; then
+		echo "error: the deployment has no valid agent binary signing certificate"
+		return 1
+	fi
+	if ! curl -fsSL "${SIGNATURE_URL}" -o "${BINARY_NAME}.sig"; then
+		echo "error: no signature available for the coder agent"
+		return 1
+	fi
+	if ! openssl dgst -sha256 -verify signing.pub -signature "${BINARY_NAME}.sig" "${BINARY_NAME}"; then
+		echo "error: signature of the downloaded coder agent does not match"
+		return 1
+	fi
+}
 # Attempt to download the coder agent.
 # This could fail for a number of reasons, many of which are likely transient.
 # So just keep trying!
 while :; do
-	curl -fsSL --compressed "${BINARY_URL}" -o "${BINARY_NAME}" && break
-	status=$?
-	echo "error: failed to download coder agent using curl"
-	echo "curl exit code: ${status}"
+	if curl -fsSL --compressed "${BINARY_URL}" -o "${BINARY_NAME}"; then
+		verify && break
+		if [ "${REQUIRE_VERIFICATION}" = true ]; then
+			echo "error: refusing to run a coder agent that could not be verified"
+			exit 1
+		fi
+	else
+		status=$?
+		echo "error: failed to download coder agent using curl"
+		echo "curl exit code: ${status}"
+	fi
 	echo "Trying again in 30 seconds..."
 	sleep 30
 done
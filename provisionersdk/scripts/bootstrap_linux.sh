@@ -10,30 +10,99 @@ waitonexit() {
 trap waitonexit EXIT
 BINARY_DIR="${BINARY_DIR:-$(mktemp -d -t coder.XXXXXX)}"
 BINARY_NAME=coder
-BINARY_URL=${ACCESS_URL}bin/coder-linux-${ARCH}
+# Let Coder pick the binary for the architecture of the host, which may differ
+# from the one declared in the template, e.g. on mixed amd64 and arm64 nodes.
+HOST_ARCH=$(uname -m 2>/dev/null || echo "${ARCH}")
+BINARY_URL="${ACCESS_URL}bin/coder?os=linux&arch=${HOST_ARCH}"
+CHECKSUM_URL="${ACCESS_URL}bin/coder.sha256?os=linux&arch=${HOST_ARCH}"
+SIGNATURE_URL="${ACCESS_URL}bin/coder.sig?os=linux&arch=${HOST_ARCH}"
+REQUIRE_VERIFICATION=${REQUIRE_BINARY_VERIFICATION}
 cd "$BINARY_DIR"
-# Attempt to download the coder agent.
-# This could fail for a number of reasons, many of which are likely transient.
-# So just keep trying!
-while :; do
-	# Try a number of different download tools, as we don not know what we
-	# will have available.
-	status=""
+# Try a number of different download tools, as we do not know what we will
+# have available.
+fetch() {
 	if command -v curl >/dev/null 2>&1; then
-		curl -fsSL --compressed "${BINARY_URL}" -o "${BINARY_NAME}" && break
-		status=$?
+		curl -fsSL --compressed "$1" -o "$2"
 	elif command -v wget >/dev/null 2>&1; then
-		wget -q "${BINARY_URL}" -O "${BINARY_NAME}" && break
-		status=$?
+		wget -q "$1" -O "$2"
 	elif command -v busybox >/dev/null 2>&1; then
-		busybox wget -q "${BINARY_URL}" -O "${BINARY_NAME}" && break
-		status=$?
+		busybox wget -q "$1" -O "$2"
 	else
 		echo "error: no download tool found, please install curl, wget or busybox wget"
 		exit 127
 	fi
-	echo "error: failed to download coder agent"
-	echo "       command returned: ${status}"
+}
+# Compare the binary with the checksum served by Coder, so a tampered mirror
+# or proxy cannot substitute the agent. Deployments that do not serve
+# checksums are trusted as before, unless the template requires the agent to
+# be verified.
+unverified() {
+	if [ "${REQUIRE_VERIFICATION}" = true ]; then
+		echo "error: $1, but the template requires the coder agent to be verified"
+		return 1
+	fi
+	echo "warning: $1, skipping verification of the coder agent"
+}
+verify() {
+	if ! fetch "${CHECKSUM_URL}" "${BINARY_NAME}.sha256"; then
+		unverified "no checksum available"
+		return
+	fi
+	expected=$(cut -d" " -f1 "${BINARY_NAME}.sha256")
+	if command -v sha256sum >/dev/null 2>&1; then
+		actual=$(sha256sum "${BINARY_NAME}" | cut -d" " -f1)
+	elif command -v shasum >/dev/null 2>&1; then
+		actual=$(shasum -a 256 "${BINARY_NAME}" | cut -d" " -f1)
+	else
+		unverified "no sha256 tool found"
+		return
+	fi
+	if [ "${expected}" != "${actual}" ]; then
+		echo "error: checksum of the downloaded coder agent does not match"
+		return 1
+	fi
+	if [ "${REQUIRE_VERIFICATION}" = true ]; then
+		verify_signature
+	fi
+}
+# The signature is checked against the certificate configured for the
+# deployment when the workspace was built, not one served with the binary.
+verify_signature() {
+	if ! command -v openssl >/dev/null 2>&1; then
+		echo "error: openssl is required to verify the signature of the coder agent"
+		return 1
+	fi
+	cat >signing.pem <<EOF
+${SIGNING_CERTIFICATE}
+EOF
+	if ! openssl x509 -pubkey -noout -in signing.pem >signing.pub; then
+		echo "error: the deployment has no valid agent binary signing certificate"
+		return 1
+	fi
+	if ! fetch "${SIGNATURE_URL}" "${BINARY_NAME}.sig"; then
+		echo "error: no signature available for the coder agent"
+		return 1
+	fi
+	if ! openssl dgst -sha256 -verify signing.pub -signature "${BINARY_NAME}.sig" "${BINARY_NAME}"; then
+		echo "error: signature of the downloaded coder agent does not match"
+		return 1
+	fi
+}
+# Attempt to download the coder agent.
+# This could fail for a number of reasons, many of which are likely transient.
+# So just keep trying!
+while :; do
+	if fetch "${BINARY_URL}" "${BINARY_NAME}"; then
+		verify && break
+		if [ "${REQUIRE_VERIFICATION}" = true ]; then
+			echo "error: refusing to run a coder agent that could not be verified"
+			exit 1
+		fi
+	else
+		status=$?
+		echo "error: failed to download coder agent"
+		echo "       command returned: ${status}"
+	fi
 	echo "Trying again in 30 seconds..."
 	sleep 30
 done
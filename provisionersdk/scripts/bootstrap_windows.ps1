@@ -4,18 +4,79 @@ trap {
 	Start-Sleep -Seconds 86400
 }
 
+$REQUIRE_VERIFICATION = "${REQUIRE_BINARY_VERIFICATION}" -eq "true"
+
+# The signature is checked against the certificate configured for the
+# deployment when the workspace was built, not one served with the binary.
+# Returns why the signature could not be verified, if it couldn't.
+function Test-AgentSignature($Path, $SignatureUrl) {
+	$PEM = @"
+${SIGNING_CERTIFICATE}
+"@
+	try {
+		$CERT = [System.Security.Cryptography.X509Certificates.X509Certificate2]::new([System.Text.Encoding]::ASCII.GetBytes($PEM))
+		$RSA = [System.Security.Cryptography.X509Certificates.RSACertificateExtensions]::GetRSAPublicKey($CERT)
+	} catch {
+		$RSA = $null
+	}
+	if (-not $RSA) {
+		return "the deployment has no valid agent binary signing certificate"
+	}
+	try {
+		Invoke-WebRequest -Uri $SignatureUrl -OutFile "$Path.sig"
+	} catch {
+		return "no signature available for the coder agent"
+	}
+	$DATA = [System.IO.File]::ReadAllBytes($Path)
+	$SIGNATURE = [System.IO.File]::ReadAllBytes("$Path.sig")
+	if (-not $RSA.VerifyData($DATA, $SIGNATURE, [System.Security.Cryptography.HashAlgorithmName]::SHA256, [System.Security.Cryptography.RSASignaturePadding]::Pkcs1)) {
+		return "signature of the downloaded coder agent does not match"
+	}
+	return $null
+}
+
 # Attempt to download the coder agent.
 # This could fail for a number of reasons, many of which are likely transient.
 # So just keep trying!
+$VERIFICATION_ERROR = $null
 while ($true) {
 	try {
 		$ProgressPreference = "SilentlyContinue"
 		# On Windows, VS Code Remote requires a parent process of the
 		# executing shell to be named "sshd", otherwise it fails. See:
 		# https://github.com/microsoft/vscode-remote-release/issues/5699
-		$BINARY_URL="${ACCESS_URL}/bin/coder-windows-${ARCH}.exe"
+		# Let Coder pick the binary for the architecture of the host, which
+		# may differ from the one declared in the template.
+		$HOST_ARCH = if ($env:PROCESSOR_ARCHITECTURE) { $env:PROCESSOR_ARCHITECTURE } else { "${ARCH}" }
+		$BINARY_URL="${ACCESS_URL}/bin/coder?os=windows&arch=${HOST_ARCH}"
 		Write-Output "Fetching coder agent from ${BINARY_URL}"
 		Invoke-WebRequest -Uri "${BINARY_URL}" -OutFile $env:TEMP\sshd.exe
+		# Compare the binary with the checksum served by Coder, so a tampered
+		# mirror or proxy cannot substitute the agent. Deployments that do not
+		# serve checksums are trusted as before, unless the template requires
+		# the agent to be verified.
+		try {
+			$CHECKSUM = Invoke-RestMethod -Uri "${ACCESS_URL}/bin/coder.sha256?os=windows&arch=${HOST_ARCH}"
+		} catch {
+			if ($REQUIRE_VERIFICATION) {
+				$VERIFICATION_ERROR = "no checksum available for the coder agent"
+				break
+			}
+			Write-Output "warning: no checksum available for the coder agent, skipping verification"
+			break
+		}
+		$EXPECTED = ("$CHECKSUM".Trim() -split " ")[0]
+		$ACTUAL = (Get-FileHash -Algorithm SHA256 -Path $env:TEMP\sshd.exe).Hash
+		if ($EXPECTED -ne $ACTUAL) {
+			if ($REQUIRE_VERIFICATION) {
+				$VERIFICATION_ERROR = "checksum of the downloaded coder agent does not match"
+				break
+			}
+			throw "checksum of the downloaded coder agent does not match"
+		}
+		if ($REQUIRE_VERIFICATION) {
+			$VERIFICATION_ERROR = Test-AgentSignature "$env:TEMP\sshd.exe" "${ACCESS_URL}/bin/coder.sig?os=windows&arch=${HOST_ARCH}"
+		}
 		break
 	} catch {
 		Write-Output "error: unhandled exception fetching coder agent:"
@@ -25,6 +86,10 @@ while ($true) {
 	}
 }
 
+if ($VERIFICATION_ERROR) {
+	throw "refusing to run a coder agent that could not be verified: $VERIFICATION_ERROR"
+}
+
 # If the below fails, retrying probably will not help.
 Set-MpPreference -DisableRealtimeMonitoring $true -ExclusionPath $env:TEMP\sshd.exe
 $env:CODER_AGENT_AUTH = "${AUTH_TYPE}"
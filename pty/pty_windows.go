@@ -5,6 +5,7 @@ package pty
 import (
 	"context"
 	"io"
+	"math"
 	"os"
 	"os/exec"
 	"sync"
@@ -54,12 +55,14 @@ func newPty(opt ...Option) (*ptyWindows, error) {
 		return nil, err
 	}
 
-	consoleSize := uintptr(80) + (uintptr(80) << 16)
-	if opts.sshReq != nil {
-		consoleSize = uintptr(opts.sshReq.Window.Width) + (uintptr(opts.sshReq.Window.Height) << 16)
+	// CreatePseudoConsole fails with E_INVALIDARG for a zero size, which some
+	// SSH clients send when they don't know their window size yet.
+	height, width := uint16(80), uint16(80)
+	if opts.sshReq != nil && opts.sshReq.Window.Height > 0 && opts.sshReq.Window.Width > 0 {
+		height, width = uint16(opts.sshReq.Window.Height), uint16(opts.sshReq.Window.Width)
 	}
 	ret, _, err := procCreatePseudoConsole.Call(
-		consoleSize,
+		consoleSize(height, width),
 		uintptr(pty.inputRead.Fd()),
 		uintptr(pty.outputWrite.Fd()),
 		0,
@@ -132,17 +135,36 @@ func (p *ptyWindows) Resize(height uint16, width uint16) error {
 	if p.closed || p.console == windows.InvalidHandle {
 		return ErrClosed
 	}
-	// Taken from: https://github.com/microsoft/hcsshim/blob/54a5ad86808d761e3e396aff3e2022840f39f9a8/internal/winapi/zsyscall_windows.go#L144
-	ret, _, err := procResizePseudoConsole.Call(uintptr(p.console), uintptr(*((*uint32)(unsafe.Pointer(&windows.Coord{
-		Y: int16(height),
-		X: int16(width),
-	})))))
-	if windows.Handle(ret) != windows.S_OK {
-		return err
+	// ConPTY rejects a zero size, and would render nothing into it anyway.
+	// Terminals send one while they are hidden or being laid out.
+	if height == 0 || width == 0 {
+		return nil
+	}
+	ret, _, err := procResizePseudoConsole.Call(uintptr(p.console), consoleSize(height, width))
+	if winerrorFailed(ret) {
+		return xerrors.Errorf("resize pseudo console (%d): %w", int32(ret), err)
 	}
 	return nil
 }
 
+// consoleSize packs a size into the COORD that the pseudo console functions
+// take by value. Sizes are clamped to the int16 range of COORD, since larger
+// ones would wrap around to negative sizes.
+//
+// Taken from: https://github.com/microsoft/hcsshim/blob/54a5ad86808d761e3e396aff3e2022840f39f9a8/internal/winapi/zsyscall_windows.go#L144
+func consoleSize(height uint16, width uint16) uintptr {
+	if height > math.MaxInt16 {
+		height = math.MaxInt16
+	}
+	if width > math.MaxInt16 {
+		width = math.MaxInt16
+	}
+	return uintptr(*((*uint32)(unsafe.Pointer(&windows.Coord{
+		Y: int16(height),
+		X: int16(width),
+	}))))
+}
+
 // closeConsoleNoLock closes the console handle, and sets it to
 // windows.InvalidHandle. It must be called with p.closeMutex held.
 func (p *ptyWindows) closeConsoleNoLock() error {
@@ -396,7 +396,9 @@ const createTemplateVersionTar = async (
                   command: "",
                   displayName: "example",
                   external: false,
+                  hidden: false,
                   icon: "",
+                  order: 0,
                   sharingLevel: AppSharingLevel.PUBLIC,
                   slug: "example",
                   subdomain: false,
@@ -416,6 +418,7 @@ const createTemplateVersionTar = async (
               motdFile: "",
               name: "dev",
               operatingSystem: "linux",
+              order: 0,
               shutdownScript: "",
               shutdownScriptTimeoutSeconds: 0,
               startupScript: "",
@@ -435,6 +438,7 @@ const createTemplateVersionTar = async (
           instanceType: "",
           metadata: [],
           name: "dev",
+          order: 0,
           type: "echo",
           ...resource,
         } as Resource
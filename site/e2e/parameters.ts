@@ -19,6 +19,8 @@ const emptyParameter: RichParameter = {
   displayName: "",
   order: 0,
   ephemeral: false,
+  dependencies: [],
+  optionsUrl: "",
 }
 
 // firstParameter is mutable string with a default value (parameter value not required).
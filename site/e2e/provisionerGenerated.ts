@@ -49,6 +49,25 @@ export interface RichParameterOption {
   icon: string
 }
 
+/**
+ * RichParameterDependency restricts a parameter based on the value of
+ * another parameter.
+ */
+export interface RichParameterDependency {
+  /** parameter is the name of the parameter this one depends on. */
+  parameter: string
+  /**
+   * values lists the values of the parameter for which the dependency
+   * matches. An empty list matches any value.
+   */
+  values: string[]
+  /**
+   * options restricts the option values that can be chosen while the
+   * dependency matches. An empty list allows all options.
+   */
+  options: string[]
+}
+
 /** RichParameter represents a variable that is exposed. */
 export interface RichParameter {
   name: string
@@ -68,6 +87,17 @@ export interface RichParameter {
   displayName: string
   order: number
   ephemeral: boolean
+  /**
+   * dependencies make the parameter applicable only when one of them
+   * matches. A parameter without dependencies is always applicable.
+   */
+  dependencies: RichParameterDependency[]
+  /**
+   * options_url is an endpoint returning the options of the parameter,
+   * which are fetched whenever the parameter is resolved. It replaces the
+   * static options.
+   */
+  optionsUrl: string
 }
 
 /** RichParameterValue holds the key/value mapping of a parameter. */
@@ -119,6 +149,11 @@ export interface Agent {
   shutdownScriptTimeoutSeconds: number
   metadata: Agent_Metadata[]
   startupScriptBehavior: string
+  /**
+   * order is the position of the agent when displayed among the other
+   * agents of its resource, lowest first.
+   */
+  order: number
 }
 
 export interface Agent_Metadata {
@@ -149,6 +184,13 @@ export interface App {
   healthcheck: Healthcheck | undefined
   sharingLevel: AppSharingLevel
   external: boolean
+  /**
+   * order is the position of the app when displayed among the other apps
+   * of its agent, lowest first.
+   */
+  order: number
+  /** hidden apps are not displayed by clients. */
+  hidden: boolean
 }
 
 /** Healthcheck represents configuration for checking for app readiness. */
@@ -168,6 +210,11 @@ export interface Resource {
   icon: string
   instanceType: string
   dailyCost: number
+  /**
+   * order is the position of the resource when displayed among the other
+   * resources of the build, lowest first.
+   */
+  order: number
 }
 
 export interface Resource_Metadata {
@@ -211,6 +258,14 @@ export interface Provision_Metadata {
   templateVersion: string
   workspaceOwnerOidcAccessToken: string
   workspaceOwnerSessionToken: string
+  /**
+   * require_agent_binary_verification makes the agent bootstrap scripts
+   * refuse to run an agent binary whose checksum or signature does not
+   * match. agent_binary_signing_certificate is the PEM-encoded
+   * certificate the signatures are checked against.
+   */
+  requireAgentBinaryVerification: boolean
+  agentBinarySigningCertificate: string
 }
 
 /**
@@ -222,6 +277,12 @@ export interface Provision_Config {
   state: Uint8Array
   metadata: Provision_Metadata | undefined
   provisionerLogLevel: string
+  /**
+   * memory_limit_bytes and cpu_limit_ms limit the resources of the
+   * processes run by the provisioner. 0 means no limit.
+   */
+  memoryLimitBytes: number
+  cpuLimitMs: number
 }
 
 export interface Provision_Plan {
@@ -236,7 +297,14 @@ export interface Provision_Apply {
   plan: Uint8Array
 }
 
-export interface Provision_Cancel {}
+export interface Provision_Cancel {
+  /**
+   * force kills the running provisioner process instead of waiting for
+   * it to stop at a safe point. The provision still completes with the
+   * state captured so far.
+   */
+  force: boolean
+}
 
 export interface Provision_Request {
   plan?: Provision_Plan | undefined
@@ -312,6 +380,24 @@ export const RichParameterOption = {
   },
 }
 
+export const RichParameterDependency = {
+  encode(
+    message: RichParameterDependency,
+    writer: _m0.Writer = _m0.Writer.create(),
+  ): _m0.Writer {
+    if (message.parameter !== "") {
+      writer.uint32(10).string(message.parameter)
+    }
+    for (const v of message.values) {
+      writer.uint32(18).string(v!)
+    }
+    for (const v of message.options) {
+      writer.uint32(26).string(v!)
+    }
+    return writer
+  },
+}
+
 export const RichParameter = {
   encode(
     message: RichParameter,
@@ -365,6 +451,12 @@ export const RichParameter = {
     if (message.ephemeral === true) {
       writer.uint32(136).bool(message.ephemeral)
     }
+    for (const v of message.dependencies) {
+      RichParameterDependency.encode(v!, writer.uint32(146).fork()).ldelim()
+    }
+    if (message.optionsUrl !== "") {
+      writer.uint32(154).string(message.optionsUrl)
+    }
     return writer
   },
 }
@@ -500,6 +592,9 @@ export const Agent = {
     if (message.startupScriptBehavior !== "") {
       writer.uint32(154).string(message.startupScriptBehavior)
     }
+    if (message.order !== 0) {
+      writer.uint32(160).int64(message.order)
+    }
     return writer
   },
 }
@@ -572,6 +667,12 @@ export const App = {
     if (message.external === true) {
       writer.uint32(72).bool(message.external)
     }
+    if (message.order !== 0) {
+      writer.uint32(80).int64(message.order)
+    }
+    if (message.hidden === true) {
+      writer.uint32(88).bool(message.hidden)
+    }
     return writer
   },
 }
@@ -623,6 +724,9 @@ export const Resource = {
     if (message.dailyCost !== 0) {
       writer.uint32(64).int32(message.dailyCost)
     }
+    if (message.order !== 0) {
+      writer.uint32(72).int64(message.order)
+    }
     return writer
   },
 }
@@ -737,6 +841,12 @@ export const Provision_Metadata = {
     if (message.workspaceOwnerSessionToken !== "") {
       writer.uint32(90).string(message.workspaceOwnerSessionToken)
     }
+    if (message.requireAgentBinaryVerification === true) {
+      writer.uint32(96).bool(message.requireAgentBinaryVerification)
+    }
+    if (message.agentBinarySigningCertificate !== "") {
+      writer.uint32(106).string(message.agentBinarySigningCertificate)
+    }
     return writer
   },
 }
@@ -761,6 +871,12 @@ export const Provision_Config = {
     if (message.provisionerLogLevel !== "") {
       writer.uint32(34).string(message.provisionerLogLevel)
     }
+    if (message.memoryLimitBytes !== 0) {
+      writer.uint32(40).int64(message.memoryLimitBytes)
+    }
+    if (message.cpuLimitMs !== 0) {
+      writer.uint32(48).int64(message.cpuLimitMs)
+    }
     return writer
   },
 }
@@ -803,9 +919,12 @@ export const Provision_Apply = {
 
 export const Provision_Cancel = {
   encode(
-    _: Provision_Cancel,
+    message: Provision_Cancel,
     writer: _m0.Writer = _m0.Writer.create(),
   ): _m0.Writer {
+    if (message.force === true) {
+      writer.uint32(8).bool(message.force)
+    }
     return writer
   },
 }
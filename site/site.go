@@ -5,12 +5,14 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha1" //#nosec // Not used for cryptography.
+	"crypto/sha256"
 	"database/sql"
 	_ "embed"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 	"html"
 	htmltemplate "html/template"
 	"io"
@@ -19,6 +21,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -83,7 +86,8 @@ func New(opts *Options) *Handler {
 		panic(fmt.Sprintf("Failed to parse html files: %v", err))
 	}
 
-	binHashCache := newBinHashCache(opts.BinFS, opts.BinHashes)
+	binHashCache := newBinHashCache(opts.BinFS, opts.BinHashes, sha1.New)
+	binSHA256Cache := newBinHashCache(opts.BinFS, nil, sha256.New)
 
 	mux := http.NewServeMux()
 	mux.Handle("/bin/", http.StripPrefix("/bin", http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
@@ -105,6 +109,24 @@ func New(opts *Options) *Handler {
 			http.NotFound(rw, r)
 			return
 		}
+		switch name {
+		case "manifest.json":
+			serveBinManifest(rw, r, opts.BinFS, binHashCache, binSHA256Cache)
+			return
+		case "coder":
+			redirectToBin(rw, r, opts.BinFS, "")
+			return
+		case "coder.sha256":
+			redirectToBin(rw, r, opts.BinFS, ".sha256")
+			return
+		case "coder.sig":
+			redirectToBin(rw, r, opts.BinFS, ".sig")
+			return
+		}
+		if bin, ok := strings.CutSuffix(name, ".sha256"); ok && binNameRegex.MatchString(bin) {
+			serveBinChecksum(rw, r, bin, binSHA256Cache)
+			return
+		}
 		hash, err := binHashCache.getHash(name)
 		if xerrors.Is(err, os.ErrNotExist) {
 			http.NotFound(rw, r)
@@ -149,8 +171,9 @@ type Handler struct {
 
 	AppearanceFetcher func(ctx context.Context) (codersdk.AppearanceConfig, error)
 	// RegionsFetcher will attempt to fetch the more detailed WorkspaceProxy data, but will fall back to the
-	// regions if the user does not have the correct permissions.
-	RegionsFetcher func(ctx context.Context) (any, error)
+	// regions if the user does not have the correct permissions. The request context carries the
+	// authorization of the user.
+	RegionsFetcher func(r *http.Request) (any, error)
 
 	Entitlements atomic.Pointer[codersdk.Entitlements]
 	Experiments  atomic.Pointer[codersdk.Experiments]
@@ -368,7 +391,7 @@ func (h *Handler) renderHTMLWithState(r *http.Request, filePath string, state ht
 				wg.Add(1)
 				go func() {
 					defer wg.Done()
-					regions, err := h.RegionsFetcher(ctx)
+					regions, err := h.RegionsFetcher(r.WithContext(ctx))
 					if err == nil {
 						regions, err := json.Marshal(regions)
 						if err == nil {
@@ -775,7 +798,8 @@ func RenderStaticErrorPage(rw http.ResponseWriter, r *http.Request, data ErrorPa
 }
 
 type binHashCache struct {
-	binFS http.FileSystem
+	binFS   http.FileSystem
+	newHash func() hash.Hash
 
 	hashes map[string]string
 	mut    sync.RWMutex
@@ -783,13 +807,14 @@ type binHashCache struct {
 	sem    chan struct{}
 }
 
-func newBinHashCache(binFS http.FileSystem, binHashes map[string]string) *binHashCache {
+func newBinHashCache(binFS http.FileSystem, binHashes map[string]string, newHash func() hash.Hash) *binHashCache {
 	b := &binHashCache{
-		binFS:  binFS,
-		hashes: make(map[string]string, len(binHashes)),
-		mut:    sync.RWMutex{},
-		sf:     singleflight.Group{},
-		sem:    make(chan struct{}, 4),
+		binFS:   binFS,
+		newHash: newHash,
+		hashes:  make(map[string]string, len(binHashes)),
+		mut:     sync.RWMutex{},
+		sf:      singleflight.Group{},
+		sem:     make(chan struct{}, 4),
 	}
 	// Make a copy since we're gonna be mutating it.
 	for k, v := range binHashes {
@@ -818,7 +843,7 @@ func (b *binHashCache) getHash(name string) (string, error) {
 		}
 		defer f.Close()
 
-		h := sha1.New() //#nosec // Not used for cryptography.
+		h := b.newHash()
 		_, err = io.Copy(h, f)
 		if err != nil {
 			return "", err
@@ -837,3 +862,131 @@ func (b *binHashCache) getHash(name string) (string, error) {
 	//nolint:forcetypeassert
 	return strings.ToLower(v.(string)), nil
 }
+
+// binNameRegex matches the names of coder binaries, e.g. coder-linux-arm64 or
+// coder-windows-amd64.exe.
+var binNameRegex = regexp.MustCompile(`^coder-(linux|darwin|windows)-([a-z0-9]+)(\.exe)?$`)
+
+// listBins returns the coder binaries served from binFS.
+func listBins(binFS http.FileSystem) ([]codersdk.BinaryArtifact, error) {
+	dir, err := binFS.Open("/")
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	files, err := dir.Readdir(-1)
+	if err != nil {
+		return nil, err
+	}
+	bins := make([]codersdk.BinaryArtifact, 0, len(files))
+	for _, f := range files {
+		match := binNameRegex.FindStringSubmatch(f.Name())
+		if f.IsDir() || match == nil {
+			continue
+		}
+		bins = append(bins, codersdk.BinaryArtifact{
+			Name: f.Name(),
+			OS:   match[1],
+			Arch: match[2],
+			URL:  "/bin/" + f.Name(),
+		})
+	}
+	slices.SortFunc(bins, func(a, b codersdk.BinaryArtifact) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return bins, nil
+}
+
+func serveBinManifest(rw http.ResponseWriter, r *http.Request, binFS http.FileSystem, sha1Hashes, sha256Hashes *binHashCache) {
+	bins, err := listBins(binFS)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for i := range bins {
+		bins[i].SHA1, err = sha1Hashes.getHash(bins[i].Name)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		bins[i].SHA256, err = sha256Hashes.getHash(bins[i].Name)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// Detached signatures are shipped next to the binaries by release
+		// builds, but not by development builds.
+		if sig, err := binFS.Open(bins[i].Name + ".sig"); err == nil {
+			_ = sig.Close()
+			bins[i].SignatureURL = bins[i].URL + ".sig"
+		}
+	}
+	httpapi.Write(r.Context(), rw, http.StatusOK, codersdk.BinaryManifest{
+		Version:  buildinfo.Version(),
+		Binaries: bins,
+	})
+}
+
+// serveBinChecksum serves the SHA256 checksum of a binary in the format of
+// sha256sum, so it can be checked with "sha256sum -c".
+func serveBinChecksum(rw http.ResponseWriter, r *http.Request, name string, hashes *binHashCache) {
+	hash, err := hashes.getHash(name)
+	if xerrors.Is(err, os.ErrNotExist) {
+		http.NotFound(rw, r)
+		return
+	}
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = fmt.Fprintf(rw, "%s  %s\n", hash, name)
+}
+
+// redirectToBin redirects to the binary for the os and arch query parameters,
+// or to the file with the given suffix next to it, e.g. its checksum.
+// Bootstrap scripts pass the output of uname, so common aliases are accepted,
+// e.g. "Linux" and "aarch64".
+func redirectToBin(rw http.ResponseWriter, r *http.Request, binFS http.FileSystem, suffix string) {
+	goos := normalizeBinOS(r.URL.Query().Get("os"))
+	goarch := normalizeBinArch(r.URL.Query().Get("arch"))
+	bins, err := listBins(binFS)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	available := make([]string, 0, len(bins))
+	for _, bin := range bins {
+		if bin.OS == goos && bin.Arch == goarch {
+			http.Redirect(rw, r, bin.URL+suffix, http.StatusFound)
+			return
+		}
+		available = append(available, bin.OS+"/"+bin.Arch)
+	}
+	http.Error(rw, fmt.Sprintf("No coder binary for %q/%q. Available: %s.",
+		r.URL.Query().Get("os"), r.URL.Query().Get("arch"), strings.Join(available, ", ")), http.StatusNotFound)
+}
+
+func normalizeBinOS(goos string) string {
+	goos = strings.ToLower(goos)
+	switch {
+	case strings.HasPrefix(goos, "mingw"), strings.HasPrefix(goos, "msys"), strings.HasPrefix(goos, "cygwin"):
+		return "windows"
+	default:
+		return goos
+	}
+}
+
+func normalizeBinArch(goarch string) string {
+	goarch = strings.ToLower(goarch)
+	switch {
+	case goarch == "x86_64", goarch == "x64":
+		return "amd64"
+	case goarch == "aarch64", strings.HasPrefix(goarch, "armv8"):
+		return "arm64"
+	case goarch == "armhf", goarch == "arm", strings.HasPrefix(goarch, "armv7"):
+		return "armv7"
+	default:
+		return goarch
+	}
+}
@@ -3,6 +3,9 @@ package site_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha1" //#nosec // Not used for cryptography.
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"html"
@@ -10,6 +13,7 @@ import (
 	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -28,6 +32,7 @@ import (
 	"github.com/coder/coder/v2/coderd/database/dbgen"
 	"github.com/coder/coder/v2/coderd/httpmw"
 	"github.com/coder/coder/v2/codersdk"
+	"github.com/coder/coder/v2/codersdk/agentsdk"
 	"github.com/coder/coder/v2/site"
 	"github.com/coder/coder/v2/testutil"
 )
@@ -447,6 +452,10 @@ func TestServingBin(t *testing.T) {
 				{url: "/bin/coder-linux-amd64", wantStatus: http.StatusOK, wantBody: []byte("embed")},
 				{url: "/bin/coder_linux_amd64", wantStatus: http.StatusOK, wantBody: []byte("embed")},
 				{url: "/bin/GITKEEP", wantStatus: http.StatusOK, wantBody: []byte("")},
+				// Bootstrap scripts ask for the binary of the host with the
+				// output of uname.
+				{url: "/bin/coder?os=Linux&arch=x86_64", wantStatus: http.StatusOK, wantBody: []byte("embed")},
+				{url: "/bin/coder?os=linux&arch=aarch64", wantStatus: http.StatusNotFound},
 			},
 		},
 	}
@@ -511,6 +520,84 @@ func TestServingBin(t *testing.T) {
 	}
 }
 
+func TestServingBinManifest(t *testing.T) {
+	t.Parallel()
+
+	binFS := http.FS(fstest.MapFS{
+		"coder-linux-amd64":       &fstest.MapFile{Data: []byte("linux-amd64")},
+		"coder-darwin-arm64":      &fstest.MapFile{Data: []byte("darwin-arm64")},
+		"coder-windows-amd64.exe": &fstest.MapFile{Data: []byte("windows-amd64")},
+		"coder-linux-amd64.sig":   &fstest.MapFile{Data: []byte("signature")},
+		"coder.sha1":              &fstest.MapFile{Data: []byte("not a binary")},
+	})
+	srv := httptest.NewServer(site.New(&site.Options{
+		BinFS: binFS,
+		BinHashes: map[string]string{
+			"coder-linux-amd64": "linux-hash",
+		},
+		SiteFS: fstest.MapFS{"index.html": &fstest.MapFile{}},
+	}))
+	defer srv.Close()
+
+	ctx := testutil.Context(t, testutil.WaitShort)
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	manifest, err := codersdk.New(srvURL).BinaryManifest(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []codersdk.BinaryArtifact{
+		{Name: "coder-darwin-arm64", OS: "darwin", Arch: "arm64", URL: "/bin/coder-darwin-arm64", SHA1: sha1Hex("darwin-arm64"), SHA256: sha256Hex("darwin-arm64")},
+		{Name: "coder-linux-amd64", OS: "linux", Arch: "amd64", URL: "/bin/coder-linux-amd64", SHA1: "linux-hash", SHA256: sha256Hex("linux-amd64"), SignatureURL: "/bin/coder-linux-amd64.sig"},
+		{Name: "coder-windows-amd64.exe", OS: "windows", Arch: "amd64", URL: "/bin/coder-windows-amd64.exe", SHA1: sha1Hex("windows-amd64"), SHA256: sha256Hex("windows-amd64")},
+	}, manifest.Binaries)
+
+	// Checksums are served next to the binaries, and can be negotiated like
+	// the binaries themselves.
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/bin/coder-darwin-arm64.sha256", nil)
+	require.NoError(t, err)
+	res, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, sha256Hex("darwin-arm64")+"  coder-darwin-arm64\n", string(body))
+
+	checksum, err := agentsdk.New(srvURL).BinaryChecksum(ctx, "Linux", "x86_64")
+	require.NoError(t, err)
+	require.Equal(t, sha256Hex("linux-amd64"), checksum)
+
+	_, err = agentsdk.New(srvURL).BinaryChecksum(ctx, "linux", "arm64")
+	require.Error(t, err)
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/bin/coder-linux-arm64.sha256", nil)
+	require.NoError(t, err)
+	res, err = srv.Client().Do(req)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	// Signatures are negotiated like checksums.
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/bin/coder.sig?os=linux&arch=amd64", nil)
+	require.NoError(t, err)
+	res, err = srv.Client().Do(req)
+	require.NoError(t, err)
+	body, err = io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "signature", string(body))
+}
+
+func sha1Hex(s string) string {
+	h := sha1.Sum([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func sha256Hex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
 func TestExtractOrReadBinFS(t *testing.T) {
 	t.Parallel()
 	t.Run("DoubleExtractDoesNotModifyFiles", func(t *testing.T) {
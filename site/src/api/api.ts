@@ -821,6 +821,7 @@ export const getEntitlements = async (): Promise<TypesGen.Entitlements> => {
       return {
         errors: [],
         features: withDefaultFeatures({}),
+        severity: "none",
         has_license: false,
         require_telemetry: false,
         trial: false,
@@ -911,7 +912,7 @@ export const getTemplateACL = async (
 export const updateTemplateACL = async (
   templateId: string,
   data: TypesGen.UpdateTemplateACL,
-): Promise<TypesGen.TemplateACL> => {
+): Promise<TypesGen.UpdateTemplateACLResponse> => {
   const response = await axios.patch(
     `/api/v2/templates/${templateId}/acl`,
     data,
@@ -1435,5 +1436,6 @@ export const getHealth = () => {
     access_url: { healthy: boolean }
     websocket: { healthy: boolean }
     database: { healthy: boolean }
+    coordinator: { healthy: boolean }
   }>("/api/v2/debug/health")
 }
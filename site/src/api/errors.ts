@@ -1,4 +1,5 @@
 import axios, { AxiosError, AxiosResponse } from "axios"
+import { FeatureUnavailable } from "./typesGenerated"
 
 const Language = {
   errorsByCode: {
@@ -17,6 +18,7 @@ export interface ApiErrorResponse {
   message: string
   detail?: string
   validations?: FieldError[]
+  feature_unavailable?: FeatureUnavailable
 }
 
 export type ApiError = AxiosError<ApiErrorResponse> & {
@@ -34,6 +36,14 @@ export const isApiValidationError = (error: unknown): error is ApiError => {
   return isApiError(error) && hasApiFieldErrors(error)
 }
 
+export const isFeatureUnavailableError = (
+  error: unknown,
+): error is ApiError => {
+  return (
+    isApiError(error) && error.response.data.feature_unavailable !== undefined
+  )
+}
+
 export const hasError = (error: unknown) =>
   error !== undefined && error !== null
 
@@ -27,11 +27,42 @@ export interface APIKeyWithOwner extends APIKey {
   readonly username: string
 }
 
+// From codersdk/licenses.go
+export interface ActivateLicenseRequest {
+  readonly activation_key: string
+}
+
 // From codersdk/licenses.go
 export interface AddLicenseRequest {
   readonly license: string
 }
 
+// From codersdk/deployment.go
+export interface AgentConnectionCacheConfig {
+  readonly max_size: number
+  readonly max_age: number
+}
+
+// From codersdk/insights.go
+export interface AgentFirstConnectInsightsReport {
+  readonly start_time: string
+  readonly end_time: string
+  readonly template_ids: string[]
+  readonly templates: TemplateAgentFirstConnect[]
+}
+
+// From codersdk/insights.go
+export interface AgentFirstConnectInsightsRequest {
+  readonly start_time: string
+  readonly end_time: string
+  readonly template_ids: string[]
+}
+
+// From codersdk/insights.go
+export interface AgentFirstConnectInsightsResponse {
+  readonly report: AgentFirstConnectInsightsReport
+}
+
 // From codersdk/templates.go
 export interface AgentStatsReportResponse {
   readonly num_comms: number
@@ -56,6 +87,12 @@ export interface AssignableRoles extends Role {
   readonly assignable: boolean
 }
 
+// From codersdk/deployment.go
+export interface AuditArchivalConfig {
+  readonly retention: number
+  readonly destination: string
+}
+
 // From codersdk/audit.go
 export type AuditDiff = Record<string, AuditDiffField>
 
@@ -94,6 +131,19 @@ export interface AuditLog {
   readonly user?: User
 }
 
+// From codersdk/auditlogarchivals.go
+export interface AuditLogArchival {
+  readonly id: string
+  readonly initiator_id?: string
+  readonly status: AuditLogArchivalStatus
+  readonly cutoff: string
+  readonly destination: string
+  readonly archived_count: number
+  readonly error?: string
+  readonly started_at: string
+  readonly finished_at?: string
+}
+
 // From codersdk/audit.go
 export interface AuditLogResponse {
   readonly audit_logs: AuditLog[]
@@ -105,6 +155,15 @@ export interface AuditLogsRequest extends Pagination {
   readonly q?: string
 }
 
+// From codersdk/deployment.go
+export interface AuditSinksConfig {
+  readonly syslog_address: string
+  readonly splunk_hec_url: string
+  readonly splunk_hec_token: string
+  readonly kafka_rest_proxy_url: string
+  readonly kafka_topic: string
+}
+
 // From codersdk/users.go
 export interface AuthMethod {
   readonly enabled: boolean
@@ -139,6 +198,23 @@ export interface AuthorizationRequest {
 // From codersdk/authorization.go
 export type AuthorizationResponse = Record<string, boolean>
 
+// From codersdk/binaries.go
+export interface BinaryArtifact {
+  readonly name: string
+  readonly os: string
+  readonly arch: string
+  readonly url: string
+  readonly sha1: string
+  readonly sha256: string
+  readonly signature_url?: string
+}
+
+// From codersdk/binaries.go
+export interface BinaryManifest {
+  readonly version: string
+  readonly binaries: BinaryArtifact[]
+}
+
 // From codersdk/deployment.go
 export interface BuildInfoResponse {
   readonly external_url: string
@@ -147,6 +223,12 @@ export interface BuildInfoResponse {
   readonly workspace_proxy: boolean
 }
 
+// From codersdk/workspaces.go
+export interface CloneWorkspaceRequest {
+  readonly name: string
+  readonly copy_data: boolean
+}
+
 // From codersdk/insights.go
 export interface ConnectionLatency {
   readonly p50: number
@@ -159,6 +241,11 @@ export interface ConvertLoginRequest {
   readonly password: string
 }
 
+// From codersdk/auditlogarchivals.go
+export interface CreateAuditLogArchivalRequest {
+  readonly cutoff?: string
+}
+
 // From codersdk/users.go
 export interface CreateFirstUserRequest {
   readonly email: string
@@ -179,6 +266,14 @@ export interface CreateGroupRequest {
   readonly display_name: string
   readonly avatar_url: string
   readonly quota_allowance: number
+  readonly metadata?: Record<string, string>
+}
+
+// From codersdk/environmentvariables.go
+export interface CreateManagedEnvironmentVariableRequest {
+  readonly name: string
+  readonly value: string
+  readonly secret: boolean
 }
 
 // From codersdk/users.go
@@ -186,6 +281,16 @@ export interface CreateOrganizationRequest {
   readonly name: string
 }
 
+// From codersdk/sshcertificates.go
+export interface CreateSSHCertificateRequest {
+  readonly public_key: string
+}
+
+// From codersdk/templateaccessrequests.go
+export interface CreateTemplateAccessRequest {
+  readonly message?: string
+}
+
 // From codersdk/organizations.go
 export interface CreateTemplateRequest {
   readonly name: string
@@ -212,6 +317,11 @@ export interface CreateTemplateVersionDryRunRequest {
   readonly user_variable_values?: VariableValue[]
 }
 
+// From codersdk/templateversionpromotions.go
+export interface CreateTemplateVersionPromotionRequest {
+  readonly message?: string
+}
+
 // From codersdk/organizations.go
 export interface CreateTemplateVersionRequest {
   readonly name?: string
@@ -261,6 +371,8 @@ export interface CreateWorkspaceBuildRequest {
   readonly orphan?: boolean
   readonly rich_parameter_values?: WorkspaceBuildParameter[]
   readonly log_level?: ProvisionerLogLevel
+  readonly reason?: BuildReason
+  readonly if_active?: WorkspaceBuildConflictPolicy
 }
 
 // From codersdk/workspaceproxy.go
@@ -277,6 +389,22 @@ export interface CreateWorkspaceRequest {
   readonly autostart_schedule?: string
   readonly ttl_ms?: number
   readonly rich_parameter_values?: WorkspaceBuildParameter[]
+  readonly automatic_updates?: AutomaticUpdates
+  readonly state?: string
+}
+
+// From codersdk/workspacewebhooks.go
+export interface CreateWorkspaceWebhookRequest {
+  readonly name: string
+  readonly action: WorkspaceWebhookAction
+  readonly agent_name?: string
+  readonly command?: string
+}
+
+// From codersdk/workspacewebhooks.go
+export interface CreateWorkspaceWebhookResponse {
+  readonly webhook: WorkspaceWebhook
+  readonly secret: string
 }
 
 // From codersdk/deployment.go
@@ -302,6 +430,15 @@ export interface DERP {
   readonly config: DERPConfig
 }
 
+// From codersdk/debug.go
+export interface DERPClientUsage {
+  readonly address: string
+  readonly received_bytes: number
+  readonly sent_bytes: number
+  readonly connections: number
+  readonly last_seen_at: string
+}
+
 // From codersdk/deployment.go
 export interface DERPConfig {
   readonly block_direct: boolean
@@ -309,6 +446,13 @@ export interface DERPConfig {
   readonly path: string
 }
 
+// From codersdk/debug.go
+export interface DERPMeshUsage {
+  readonly address: string
+  readonly received_bytes: number
+  readonly sent_bytes: number
+}
+
 // From codersdk/workspaceagents.go
 export interface DERPRegion {
   readonly preferred: boolean
@@ -326,6 +470,16 @@ export interface DERPServerConfig {
   readonly relay_url: string
 }
 
+// From codersdk/debug.go
+export interface DERPUsageReport {
+  readonly region_id: number
+  readonly received_bytes: number
+  readonly sent_bytes: number
+  readonly connections: number
+  readonly clients: DERPClientUsage[]
+  readonly mesh: DERPMeshUsage[]
+}
+
 // From codersdk/deployment.go
 export interface DangerousConfig {
   readonly allow_path_app_sharing: boolean
@@ -333,6 +487,49 @@ export interface DangerousConfig {
   readonly allow_all_cors: boolean
 }
 
+// From codersdk/debug.go
+export interface DebugConnectionsReport {
+  readonly expected_replicas: number
+  readonly replicas: ReplicaConnections[]
+}
+
+// From codersdk/templateaccessrequests.go
+export interface DecideTemplateAccessRequest {
+  readonly status: TemplateAccessRequestStatus
+  readonly reason?: string
+}
+
+// From codersdk/templateversionpromotions.go
+export interface DecideTemplateVersionPromotionRequest {
+  readonly status: TemplateVersionPromotionStatus
+  readonly reason?: string
+}
+
+// From codersdk/workspaceapprovals.go
+export interface DecideWorkspaceApprovalRequest {
+  readonly status: WorkspaceApprovalStatus
+  readonly reason?: string
+}
+
+// From codersdk/deployment.go
+export interface DeploymentConfigDiff {
+  readonly options: DeploymentOptionDiff[]
+}
+
+// From codersdk/deployment.go
+export interface DeploymentOptionDiff {
+  readonly name: string
+  readonly flag?: string
+  readonly env?: string
+  readonly yaml?: string
+  // Empty interface{} type, cannot resolve the type.
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- interface{}
+  readonly value: any
+  // Empty interface{} type, cannot resolve the type.
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- interface{}
+  readonly default: any
+}
+
 // From codersdk/deployment.go
 export interface DeploymentStats {
   readonly aggregated_from: string
@@ -342,6 +539,26 @@ export interface DeploymentStats {
   readonly session_count: SessionCountDeploymentStats
 }
 
+// From codersdk/deployment.go
+export interface DeploymentStatus {
+  readonly healthy: boolean
+  readonly database: DeploymentStatusDatabase
+  readonly proxies: DeploymentStatusProxies
+  readonly checked_at: string
+}
+
+// From codersdk/deployment.go
+export interface DeploymentStatusDatabase {
+  readonly reachable: boolean
+  readonly latency_ms: number
+}
+
+// From codersdk/deployment.go
+export interface DeploymentStatusProxies {
+  readonly total: number
+  readonly healthy: number
+}
+
 // From codersdk/deployment.go
 export interface DeploymentValues {
   readonly verbose?: boolean
@@ -377,10 +594,17 @@ export interface DeploymentValues {
   readonly agent_fallback_troubleshooting_url?: string
   readonly browser_only?: boolean
   readonly scim_api_key?: string
+  readonly scim_deprovision_stop_workspaces?: boolean
+  readonly scim_deprovision_delete_after?: number
+  readonly audit_sinks?: AuditSinksConfig
+  readonly audit_archival?: AuditArchivalConfig
+  readonly external_authz?: ExternalAuthzConfig
   readonly provisioner?: ProvisionerConfig
   readonly rate_limit?: RateLimitConfig
   // This is likely an enum in an external package ("github.com/coder/coder/v2/cli/clibase.StringArray")
   readonly experiments?: string[]
+  // This is likely an enum in an external package ("github.com/coder/coder/v2/cli/clibase.StringArray")
+  readonly feature_flags?: string[]
   readonly update_check?: boolean
   readonly max_token_lifetime?: number
   readonly swagger?: SwaggerConfig
@@ -398,8 +622,36 @@ export interface DeploymentValues {
   readonly wgtunnel_host?: string
   readonly disable_owner_workspace_exec?: boolean
   readonly proxy_health_status_interval?: number
+  readonly proxy_geo_rules?: string[]
+  readonly proxy_version_mismatch_policy?: string
   readonly enable_terraform_debug_mode?: boolean
   readonly user_quiet_hours_schedule?: UserQuietHoursScheduleConfig
+  readonly status_require_auth?: boolean
+  readonly entitlements_refresh_jitter?: number
+  readonly license_activation_url?: string
+  // Named type "github.com/coder/coder/v2/cli/clibase.Struct[[]github.com/coder/coder/v2/codersdk.WorkspaceHookConfig]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly workspace_hooks?: any
+  readonly workspace_approval_webhook?: WorkspaceApprovalWebhookConfig
+  // This is likely an enum in an external package ("github.com/coder/coder/v2/cli/clibase.StringArray")
+  readonly app_identity_headers?: string[]
+  // This is likely an enum in an external package ("github.com/coder/coder/v2/cli/clibase.StringArray")
+  readonly port_forward_identity_headers?: string[]
+  // Named type "github.com/coder/coder/v2/cli/clibase.Struct[github.com/coder/coder/v2/codersdk.PortPolicy]" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly port_forward_policy?: any
+  readonly max_pty_sessions_per_user?: number
+  readonly max_pty_sessions_per_workspace?: number
+  // This is likely an enum in an external package ("github.com/coder/coder/v2/cli/clibase.StringArray")
+  readonly agent_dns_search_domains?: string[]
+  // This is likely an enum in an external package ("github.com/coder/coder/v2/cli/clibase.StringArray")
+  readonly agent_dns_nameservers?: string[]
+  readonly agent_binary_signing_certificate?: string
+  readonly agent_connection_cache?: AgentConnectionCacheConfig
+  readonly agent_admission_rate?: number
+  readonly agent_token_rotation_interval?: number
+  readonly tailnet_keepalive?: TailnetKeepaliveConfig
+  readonly secret_manager?: SecretManagerConfig
   // This is likely an enum in an external package ("github.com/coder/coder/v2/cli/clibase.YAMLConfigPath")
   readonly config?: string
   readonly write_config?: boolean
@@ -408,26 +660,110 @@ export interface DeploymentValues {
   readonly address?: any
 }
 
+// From codersdk/authorization.go
+export interface EffectivePermissionEntry {
+  readonly source: EffectivePermissionSource
+  readonly role?: string
+  readonly group_id?: string
+  readonly group_name?: string
+  readonly allow: boolean
+}
+
+// From codersdk/authorization.go
+export interface EffectivePermissions {
+  readonly user_id: string
+  readonly username: string
+  readonly object: AuthorizationObject
+  readonly action: string
+  readonly allowed: boolean
+  readonly organization_member: boolean
+  readonly entries: EffectivePermissionEntry[]
+}
+
+// From codersdk/authorization.go
+export interface EffectivePermissionsRequest {
+  readonly User: string
+  readonly Object: string
+  readonly Action: string
+}
+
+// From codersdk/deployment.go
+export interface EntitlementSnapshot {
+  readonly date: string
+  readonly has_license: boolean
+  readonly trial: boolean
+  readonly features: Record<FeatureName, Feature>
+  readonly user_count: number
+  readonly active_user_count: number
+  readonly replica_count: number
+  readonly warnings: string[]
+  readonly errors: string[]
+  readonly created_at: string
+}
+
 // From codersdk/deployment.go
 export interface Entitlements {
   readonly features: Record<FeatureName, Feature>
   readonly warnings: string[]
   readonly errors: string[]
+  readonly severity: EntitlementsSeverity
   readonly has_license: boolean
   readonly trial: boolean
   readonly require_telemetry: boolean
+  readonly grace_period_ends_at?: string
   readonly refreshed_at: string
 }
 
+// From codersdk/deployment.go
+export interface EntitlementsHistory {
+  readonly start_date: string
+  readonly end_date: string
+  readonly snapshots: EntitlementSnapshot[]
+}
+
+// From codersdk/deployment.go
+export interface EntitlementsHistoryRequest {
+  readonly start_date: string
+  readonly end_date: string
+}
+
 // From codersdk/deployment.go
 export type Experiments = Experiment[]
 
+// From codersdk/deployment.go
+export interface ExperimentsConfig {
+  readonly deployment: Experiments
+  readonly runtime: RuntimeExperiment[]
+}
+
+// From codersdk/deployment.go
+export interface ExternalAuthzConfig {
+  readonly url: string
+  readonly policy_dir: string
+  readonly fallback: string
+  readonly cache_ttl: number
+}
+
 // From codersdk/deployment.go
 export interface Feature {
   readonly entitlement: Entitlement
   readonly enabled: boolean
   readonly limit?: number
   readonly actual?: number
+  readonly grace_period_ends_at?: string
+}
+
+// From codersdk/featureflags.go
+export interface FeatureFlags {
+  readonly flags: Record<string, boolean>
+}
+
+// From codersdk/deployment.go
+export interface FeatureUnavailable {
+  readonly feature: FeatureName
+  readonly entitlement: Entitlement
+  readonly enabled: boolean
+  readonly guidance: string
 }
 
 // From codersdk/apikey.go
@@ -516,6 +852,15 @@ export interface Group {
   readonly avatar_url: string
   readonly quota_allowance: number
   readonly source: GroupSource
+  readonly quota_override?: number
+  readonly metadata: Record<string, string>
+  readonly version: string
+}
+
+// From codersdk/groups.go
+export interface GroupDefaultTemplate {
+  readonly template_id: string
+  readonly create_workspace: boolean
 }
 
 // From codersdk/workspaceapps.go
@@ -546,6 +891,38 @@ export interface License {
   readonly claims: Record<string, any>
 }
 
+// From codersdk/licenses.go
+export interface LicenseFeatureUsage {
+  readonly feature: FeatureName
+  readonly actual?: number
+  readonly limit?: number
+  readonly peak_actual: number
+  readonly days_exceeded: number
+  readonly days: LicenseUsageDay[]
+}
+
+// From codersdk/licenses.go
+export interface LicenseUsage {
+  readonly start_date: string
+  readonly end_date: string
+  readonly features: LicenseFeatureUsage[]
+}
+
+// From codersdk/licenses.go
+export interface LicenseUsageDay {
+  readonly date: string
+  readonly peak_actual: number
+  readonly latest_actual: number
+  readonly limit?: number
+  readonly exceeded_at?: string
+}
+
+// From codersdk/licenses.go
+export interface LicenseUsageRequest {
+  readonly start_date: string
+  readonly end_date: string
+}
+
 // From codersdk/deployment.go
 export interface LinkConfig {
   readonly name: string
@@ -573,6 +950,18 @@ export interface LoginWithPasswordResponse {
   readonly session_token: string
 }
 
+// From codersdk/environmentvariables.go
+export interface ManagedEnvironmentVariable {
+  readonly id: string
+  readonly organization_id: string
+  readonly template_id?: string
+  readonly created_at: string
+  readonly updated_at: string
+  readonly name: string
+  readonly value?: string
+  readonly secret: boolean
+}
+
 // From codersdk/users.go
 export interface MinimalUser {
   readonly id: string
@@ -580,6 +969,55 @@ export interface MinimalUser {
   readonly avatar_url: string
 }
 
+// From codersdk/insights.go
+export interface NetcheckBlockedPortInsight {
+  readonly port: string
+  readonly reports: number
+  readonly users: number
+}
+
+// From codersdk/insights.go
+export interface NetcheckInsightsReport {
+  readonly start_time: string
+  readonly end_time: string
+  readonly user_id?: string
+  readonly total_reports: number
+  readonly nat_types: NetcheckNATTypeInsight[]
+  readonly blocked_ports: NetcheckBlockedPortInsight[]
+  readonly reports: NetcheckReport[]
+}
+
+// From codersdk/insights.go
+export interface NetcheckInsightsRequest {
+  readonly start_time: string
+  readonly end_time: string
+  readonly user_id: string
+}
+
+// From codersdk/insights.go
+export interface NetcheckInsightsResponse {
+  readonly report: NetcheckInsightsReport
+}
+
+// From codersdk/insights.go
+export interface NetcheckNATTypeInsight {
+  readonly nat_type: NATType
+  readonly reports: number
+  readonly users: number
+}
+
+// From codersdk/netcheck.go
+export interface NetcheckReport extends PostNetcheckReportRequest {
+  readonly id: string
+  readonly user_id: string
+  readonly created_at: string
+}
+
+// From codersdk/templates.go
+export interface NotifyOutdatedWorkspacesResponse {
+  readonly workspace_ids: string[]
+}
+
 // From codersdk/deployment.go
 export interface OAuth2Config {
   readonly github: OAuth2GithubConfig
@@ -655,6 +1093,7 @@ export interface Organization {
   readonly name: string
   readonly created_at: string
   readonly updated_at: string
+  readonly max_concurrent_provisioner_jobs: number
 }
 
 // From codersdk/organizations.go
@@ -681,6 +1120,8 @@ export interface PatchGroupRequest {
   readonly display_name?: string
   readonly avatar_url?: string
   readonly quota_allowance?: number
+  readonly quota_override?: number
+  readonly metadata?: Record<string, string>
 }
 
 // From codersdk/templateversions.go
@@ -698,6 +1139,119 @@ export interface PatchWorkspaceProxy {
   readonly regenerate_token: boolean
 }
 
+// From codersdk/provisionerdaemons.go
+export interface PendingProvisionerJob {
+  readonly id: string
+  readonly created_at: string
+  readonly provisioner: ProvisionerType
+  readonly tags: Record<string, string>
+  readonly matching_daemons: ProvisionerDaemon[]
+}
+
+// From codersdk/platformevents.go
+export interface PlatformEvent {
+  readonly id: number
+  readonly type: PlatformEventType
+  readonly created_at: string
+  readonly resource_id: string
+  readonly data: Record<string, string>
+}
+
+// From codersdk/platformevents.go
+export interface PlatformEventEntitlements {
+  readonly has_license: boolean
+  readonly trial: boolean
+  readonly features: Record<FeatureName, Feature>
+}
+
+// From codersdk/platformevents.go
+export interface PlatformEventTemplateAccessRequest {
+  readonly id: string
+  readonly template_id: string
+  readonly requested_by: string
+  readonly status: TemplateAccessRequestStatus
+  readonly decided_by?: string
+  readonly reason?: string
+}
+
+// From codersdk/platformevents.go
+export interface PlatformEventTemplateVersionPromotion {
+  readonly id: string
+  readonly template_id: string
+  readonly template_version_id: string
+  readonly requested_by: string
+  readonly status: TemplateVersionPromotionStatus
+  readonly decided_by?: string
+  readonly reason?: string
+}
+
+// From codersdk/platformevents.go
+export interface PlatformEventUser {
+  readonly id: string
+  readonly username: string
+  readonly email: string
+  readonly status: UserStatus
+}
+
+// From codersdk/platformevents.go
+export interface PlatformEventWorkspace {
+  readonly id: string
+  readonly name: string
+  readonly owner_id: string
+  readonly organization_id: string
+  readonly template_id: string
+}
+
+// From codersdk/platformevents.go
+export interface PlatformEventWorkspaceApproval {
+  readonly id: string
+  readonly workspace_id: string
+  readonly template_id: string
+  readonly requested_by: string
+  readonly status: WorkspaceApprovalStatus
+  readonly decided_by?: string
+  readonly reason?: string
+}
+
+// From codersdk/platformevents.go
+export interface PlatformEventWorkspaceBuild {
+  readonly id: string
+  readonly workspace_id: string
+  readonly build_number: number
+  readonly transition: WorkspaceTransition
+  readonly reason: BuildReason
+  readonly initiator_id: string
+  readonly error?: string
+}
+
+// From codersdk/netcheck.go
+export interface PostNetcheckReportRequest {
+  readonly client_version: string
+  readonly client_os: string
+  readonly nat_type: NATType
+  readonly udp: boolean
+  readonly ipv4: boolean
+  readonly ipv6: boolean
+  readonly mapping_varies_by_dest_ip?: boolean
+  readonly hair_pinning?: boolean
+  readonly preferred_derp: number
+  readonly region_latency_ms: Record<number, number>
+  readonly blocked_ports: string[]
+}
+
+// From codersdk/portpolicy.go
+export interface PortPolicy {
+  readonly rules?: PortPolicyRule[]
+  readonly default?: PortPolicyAction
+}
+
+// From codersdk/portpolicy.go
+export interface PortPolicyRule {
+  readonly ports: string
+  readonly action: PortPolicyAction
+  readonly max_sharing_level?: WorkspaceAppSharingLevel
+}
+
 // From codersdk/deployment.go
 export interface PprofConfig {
   readonly enable: boolean
@@ -724,6 +1278,8 @@ export interface ProvisionerConfig {
   readonly daemon_poll_jitter: number
   readonly force_cancel_interval: number
   readonly daemon_psk: string
+  readonly hung_job_timeout: number
+  readonly hung_job_requeue_limit: number
 }
 
 // From codersdk/provisionerdaemons.go
@@ -751,6 +1307,8 @@ export interface ProvisionerJob {
   readonly tags: Record<string, string>
   readonly queue_position: number
   readonly queue_size: number
+  readonly queue_limited_by?: ProvisionerJobQueueLimit
+  readonly cancel_outcome?: ProvisionerJobCancelOutcome
 }
 
 // From codersdk/provisionerdaemons.go
@@ -763,6 +1321,29 @@ export interface ProvisionerJobLog {
   readonly output: string
 }
 
+// From codersdk/provisionerdaemons.go
+export interface ProvisionerJobQueue {
+  readonly depth: number
+  readonly oldest_job_age_seconds: number
+  readonly job_age_seconds: ProvisionerJobQueuePercentiles
+  readonly average_wait_seconds: number
+  readonly started_jobs: number
+  readonly wait_window_seconds: number
+}
+
+// From codersdk/provisionerdaemons.go
+export interface ProvisionerJobQueuePercentiles {
+  readonly p50: number
+  readonly p90: number
+  readonly p99: number
+}
+
+// From codersdk/provisionerdaemons.go
+export interface ProvisionerJobQueueRequest {
+  readonly provisioner?: ProvisionerType
+  readonly tags?: Record<string, string>
+}
+
 // From codersdk/workspaceproxy.go
 export interface ProxyHealthReport {
   readonly errors: string[]
@@ -774,6 +1355,34 @@ export interface PutExtendWorkspaceRequest {
   readonly deadline: string
 }
 
+// From codersdk/workspaces.go
+export interface PutWorkspaceExternalMetadataRequest {
+  readonly namespace: string
+  readonly visibility?: WorkspaceExternalMetadataVisibility
+  readonly data: Record<string, string>
+}
+
+// From codersdk/workspacebuildqueue.go
+export interface QueuedWorkspaceBuild {
+  readonly id: string
+  readonly workspace_id: string
+  readonly initiator_id: string
+  readonly created_at: string
+  readonly transition: WorkspaceTransition
+  readonly template_version_id?: string
+  readonly position: number
+}
+
+// From codersdk/workspaceproxy.go
+export interface RankRegionsRequest {
+  readonly latencies: RegionLatency[]
+}
+
+// From codersdk/workspaceproxy.go
+export interface RankedRegion extends Region {
+  readonly client_latency_ms?: number
+}
+
 // From codersdk/deployment.go
 export interface RateLimitConfig {
   readonly disable_all: boolean
@@ -789,11 +1398,19 @@ export interface Region {
   readonly healthy: boolean
   readonly path_app_url: string
   readonly wildcard_hostname: string
+  readonly health_latency_ms: number
+}
+
+// From codersdk/workspaceproxy.go
+export interface RegionLatency {
+  readonly region_id: string
+  readonly latency_ms: number
 }
 
 // From codersdk/workspaceproxy.go
 export interface RegionsResponse<R extends RegionTypes> {
   readonly regions: R[]
+  readonly preferred_region_ids?: string[]
 }
 
 // From codersdk/replicas.go
@@ -805,6 +1422,17 @@ export interface Replica {
   readonly region_id: number
   readonly error: string
   readonly database_latency: number
+  readonly draining_at?: string
+}
+
+// From codersdk/debug.go
+export interface ReplicaConnections {
+  readonly replica_id: string
+  readonly hostname: string
+  readonly agents: number
+  readonly clients: number
+  readonly server_tailnet_agents: number
+  readonly connection_cache_entries: number
 }
 
 // From codersdk/client.go
@@ -812,6 +1440,7 @@ export interface Response {
   readonly message: string
   readonly detail?: string
   readonly validations?: ValidationError[]
+  readonly feature_unavailable?: FeatureUnavailable
 }
 
 // From codersdk/roles.go
@@ -820,6 +1449,28 @@ export interface Role {
   readonly display_name: string
 }
 
+// From codersdk/deployment.go
+export interface RuntimeExperiment {
+  readonly experiment: Experiment
+  readonly everyone: boolean
+  readonly organization_ids: string[]
+  readonly group_ids: string[]
+  readonly user_ids: string[]
+}
+
+// From codersdk/sshcertificates.go
+export interface SSHCertificate {
+  readonly certificate: string
+  readonly principals: string[]
+  readonly valid_after: string
+  readonly valid_before: string
+}
+
+// From codersdk/sshcertificates.go
+export interface SSHCertificateAuthority {
+  readonly public_key: string
+}
+
 // From codersdk/deployment.go
 export interface SSHConfig {
   readonly DeploymentName: string
@@ -833,6 +1484,14 @@ export interface SSHConfigResponse {
   readonly ssh_config_options: Record<string, string>
 }
 
+// From codersdk/deployment.go
+export interface SecretManagerConfig {
+  readonly vault_address: string
+  readonly vault_token: string
+  readonly aws_region: string
+  readonly gcp: boolean
+}
+
 // From codersdk/serversentevents.go
 export interface ServerSentEvent {
   readonly type: ServerSentEventType
@@ -886,6 +1545,14 @@ export interface TLSConfig {
   readonly client_key_file: string
 }
 
+// From codersdk/deployment.go
+export interface TailnetKeepaliveConfig {
+  readonly agent_interval: number
+  readonly agent_disconnect_timeout: number
+  readonly coordinator_heartbeat_interval: number
+  readonly coordinator_missed_heartbeats: number
+}
+
 // From codersdk/deployment.go
 export interface TelemetryConfig {
   readonly enable: boolean
@@ -918,6 +1585,18 @@ export interface Template {
   readonly failure_ttl_ms: number
   readonly inactivity_ttl_ms: number
   readonly locked_ttl_ms: number
+  readonly require_active_version: boolean
+  readonly require_active_version_grace_period_ms: number
+  readonly max_build_duration_ms: number
+  readonly provisioner_memory_limit_bytes: number
+  readonly provisioner_cpu_limit_ms: number
+  readonly max_concurrent_provisioner_jobs: number
+  readonly max_session_idle_ms: number
+  readonly port_policy: PortPolicy
+  readonly require_workspace_approval: boolean
+  readonly require_agent_binary_verification: boolean
+  readonly require_promotion_approval: boolean
+  readonly autostop_activity_sources: AgentActivitySource[]
 }
 
 // From codersdk/templates.go
@@ -926,6 +1605,43 @@ export interface TemplateACL {
   readonly group: TemplateGroup[]
 }
 
+// From codersdk/templates.go
+export interface TemplateACLChange {
+  readonly id: string
+  readonly old_role: TemplateRole
+  readonly new_role: TemplateRole
+}
+
+// From codersdk/templates.go
+export interface TemplateACLDiff {
+  readonly users: TemplateACLChange[]
+  readonly groups: TemplateACLChange[]
+}
+
+// From codersdk/templateaccessrequests.go
+export interface TemplateAccessRequest {
+  readonly id: string
+  readonly template_id: string
+  readonly requested_by: string
+  readonly created_at: string
+  readonly message?: string
+  readonly status: TemplateAccessRequestStatus
+  readonly decided_by?: string
+  readonly decided_at?: string
+  readonly reason?: string
+}
+
+// From codersdk/insights.go
+export interface TemplateAgentFirstConnect {
+  readonly template_id: string
+  readonly total_agents: number
+  readonly connected_agents: number
+  readonly never_connected_agents: number
+  readonly first_connect_p50_seconds: number
+  readonly first_connect_p95_seconds: number
+  readonly first_connect_p99_seconds: number
+}
+
 // From codersdk/insights.go
 export interface TemplateAppUsage {
   readonly template_ids: string[]
@@ -936,6 +1652,58 @@ export interface TemplateAppUsage {
   readonly seconds: number
 }
 
+// From codersdk/insights.go
+export interface TemplateBuildFailureInsight {
+  readonly error_code: JobErrorCode
+  readonly builds: number
+  readonly rate: number
+}
+
+// From codersdk/insights.go
+export interface TemplateBuildInsightsResponse {
+  readonly template_id: string
+  readonly versions: TemplateVersionBuildInsights[]
+}
+
+// From codersdk/insights.go
+export interface TemplateBuildSLO {
+  readonly template_id: string
+  readonly windows: TemplateBuildSLOWindow[]
+}
+
+// From codersdk/insights.go
+export interface TemplateBuildSLOInsightsRequest {
+  readonly template_ids: string[]
+  readonly windows: number[]
+  readonly success_objective: number
+  readonly duration_objective: number
+}
+
+// From codersdk/insights.go
+export interface TemplateBuildSLOInsightsResponse {
+  readonly generated_at: string
+  readonly success_objective: number
+  readonly duration_objective_seconds: number
+  readonly templates: TemplateBuildSLO[]
+}
+
+// From codersdk/insights.go
+export interface TemplateBuildSLOWindow {
+  readonly window_seconds: number
+  readonly start_time: string
+  readonly end_time: string
+  readonly total_builds: number
+  readonly successful_builds: number
+  readonly failed_builds: number
+  readonly builds_within_duration_objective: number
+  readonly success_rate: number
+  readonly duration_objective_compliance: number
+  readonly success_burn_rate: number
+  readonly duration_burn_rate: number
+  readonly build_duration_p50_seconds: number
+  readonly build_duration_p95_seconds: number
+}
+
 // From codersdk/templates.go
 export type TemplateBuildTimeStats = Record<
   WorkspaceTransition,
@@ -1034,6 +1802,40 @@ export interface TemplateVersion {
   readonly warnings?: TemplateVersionWarning[]
 }
 
+// From codersdk/insights.go
+export interface TemplateVersionBuildComparison {
+  readonly previous_template_version_id: string
+  readonly previous_template_version_name: string
+  readonly failure_rate_change: number
+  readonly duration_p50_change_seconds: number
+  readonly duration_p95_change_seconds: number
+}
+
+// From codersdk/insights.go
+export interface TemplateVersionBuildInsights {
+  readonly template_version_id: string
+  readonly template_version_name: string
+  readonly created_at: string
+  readonly active: boolean
+  readonly total_builds: number
+  readonly failed_builds: number
+  readonly failure_rate: number
+  readonly duration_p50_seconds: number
+  readonly duration_p95_seconds: number
+  readonly failures: TemplateBuildFailureInsight[]
+  readonly comparison?: TemplateVersionBuildComparison
+  readonly updated_at: string
+}
+
+// From codersdk/templateversions.go
+export interface TemplateVersionDiagnostic {
+  readonly severity: TemplateVersionDiagnosticSeverity
+  readonly summary: string
+  readonly detail: string
+  readonly filename?: string
+  readonly line?: number
+}
+
 // From codersdk/templateversions.go
 export interface TemplateVersionGitAuth {
   readonly id: string
@@ -1060,14 +1862,63 @@ export interface TemplateVersionParameter {
   readonly validation_monotonic?: ValidationMonotonicOrder
   readonly required: boolean
   readonly ephemeral: boolean
+  readonly dependencies?: TemplateVersionParameterDependency[]
+  readonly dynamic_options: boolean
+}
+
+// From codersdk/templateversions.go
+export interface TemplateVersionParameterDependency {
+  readonly parameter: string
+  readonly values?: string[]
+  readonly options?: string[]
 }
 
 // From codersdk/templateversions.go
 export interface TemplateVersionParameterOption {
   readonly name: string
-  readonly description: string
-  readonly value: string
-  readonly icon: string
+  readonly description: string
+  readonly value: string
+  readonly icon: string
+}
+
+// From codersdk/templateversionpromotions.go
+export interface TemplateVersionPromotion {
+  readonly id: string
+  readonly template_id: string
+  readonly template_version_id: string
+  readonly requested_by: string
+  readonly created_at: string
+  readonly message?: string
+  readonly status: TemplateVersionPromotionStatus
+  readonly decided_by?: string
+  readonly decided_at?: string
+  readonly reason?: string
+}
+
+// From codersdk/templateversionpromotions.go
+export interface TemplateVersionPromotionFilter {
+  readonly template_id?: string
+  readonly status?: TemplateVersionPromotionStatus
+}
+
+// From codersdk/templateversions.go
+export interface TemplateVersionStateAnalysis {
+  readonly resources: TemplateVersionStateResource[]
+  readonly warnings: string[]
+}
+
+// From codersdk/templateversions.go
+export interface TemplateVersionStateAnalysisRequest {
+  readonly state: string
+}
+
+// From codersdk/templateversions.go
+export interface TemplateVersionStateResource {
+  readonly type: string
+  readonly name: string
+  readonly status: TemplateVersionStateResourceStatus
+  readonly instances: number
+  readonly agents?: string[]
 }
 
 // From codersdk/templateversions.go
@@ -1109,6 +1960,11 @@ export interface TransitionStats {
   readonly P95?: number
 }
 
+// From codersdk/workspacewebhooks.go
+export interface TriggerWorkspaceWebhookResponse {
+  readonly workspace_build_id?: string
+}
+
 // From codersdk/templates.go
 export interface UpdateActiveTemplateVersion {
   readonly id: string
@@ -1127,17 +1983,44 @@ export interface UpdateCheckResponse {
   readonly url: string
 }
 
+// From codersdk/groups.go
+export interface UpdateGroupDefaultTemplatesRequest {
+  readonly default_templates: GroupDefaultTemplate[]
+}
+
+// From codersdk/environmentvariables.go
+export interface UpdateManagedEnvironmentVariableRequest {
+  readonly value: string
+  readonly secret: boolean
+}
+
+// From codersdk/organizations.go
+export interface UpdateOrganizationRequest {
+  readonly max_concurrent_provisioner_jobs?: number
+}
+
 // From codersdk/users.go
 export interface UpdateRoles {
   readonly roles: string[]
 }
 
+// From codersdk/deployment.go
+export interface UpdateRuntimeExperimentsRequest {
+  readonly runtime: RuntimeExperiment[]
+}
+
 // From codersdk/templates.go
 export interface UpdateTemplateACL {
   readonly user_perms?: Record<string, TemplateRole>
   readonly group_perms?: Record<string, TemplateRole>
 }
 
+// From codersdk/templates.go
+export interface UpdateTemplateACLResponse {
+  readonly message: string
+  readonly diff: TemplateACLDiff
+}
+
 // From codersdk/templates.go
 export interface UpdateTemplateMeta {
   readonly name?: string
@@ -1153,10 +2036,27 @@ export interface UpdateTemplateMeta {
   readonly failure_ttl_ms?: number
   readonly inactivity_ttl_ms?: number
   readonly locked_ttl_ms?: number
+  readonly require_active_version?: boolean
+  readonly require_active_version_grace_period_ms?: number
+  readonly max_build_duration_ms?: number
+  readonly provisioner_memory_limit_bytes?: number
+  readonly provisioner_cpu_limit_ms?: number
+  readonly max_concurrent_provisioner_jobs?: number
+  readonly max_session_idle_ms?: number
+  readonly port_policy?: PortPolicy
+  readonly require_workspace_approval?: boolean
+  readonly require_agent_binary_verification?: boolean
+  readonly require_promotion_approval?: boolean
+  readonly autostop_activity_sources?: AgentActivitySource[]
   readonly update_workspace_last_used_at: boolean
   readonly update_workspace_locked_at: boolean
 }
 
+// From codersdk/ptysessions.go
+export interface UpdateUserPTYSessionOverrideRequest {
+  readonly max_pty_sessions: number
+}
+
 // From codersdk/users.go
 export interface UpdateUserPasswordRequest {
   readonly old_password: string
@@ -1173,6 +2073,11 @@ export interface UpdateUserQuietHoursScheduleRequest {
   readonly schedule: string
 }
 
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceAutomaticUpdatesRequest {
+  readonly automatic_updates: AutomaticUpdates
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceAutostartRequest {
   readonly schedule?: string
@@ -1183,12 +2088,23 @@ export interface UpdateWorkspaceLock {
   readonly lock: boolean
 }
 
+// From codersdk/workspaceproxy.go
+export interface UpdateWorkspaceProxyACL {
+  readonly organization_ids: string[]
+  readonly group_ids: string[]
+}
+
 // From codersdk/workspaceproxy.go
 export interface UpdateWorkspaceProxyResponse {
   readonly proxy: WorkspaceProxy
   readonly proxy_token: string
 }
 
+// From codersdk/workspaces.go
+export interface UpdateWorkspaceQuotaOverrideRequest {
+  readonly quota_allowance: number
+}
+
 // From codersdk/workspaces.go
 export interface UpdateWorkspaceRequest {
   readonly name?: string
@@ -1218,6 +2134,61 @@ export interface User {
   readonly login_type: LoginType
 }
 
+// From codersdk/insights.go
+export interface UserBandwidth {
+  readonly template_ids: string[]
+  readonly user_id: string
+  readonly username: string
+  readonly avatar_url: string
+  readonly connection_type: string
+  readonly port?: number
+  readonly peers: number
+  readonly rx_bytes: number
+  readonly tx_bytes: number
+}
+
+// From codersdk/insights.go
+export interface UserBandwidthInsightsReport {
+  readonly start_time: string
+  readonly end_time: string
+  readonly template_ids: string[]
+  readonly users: UserBandwidth[]
+}
+
+// From codersdk/insights.go
+export interface UserBandwidthInsightsRequest {
+  readonly start_time: string
+  readonly end_time: string
+  readonly template_ids: string[]
+}
+
+// From codersdk/insights.go
+export interface UserBandwidthInsightsResponse {
+  readonly report: UserBandwidthInsightsReport
+}
+
+// From codersdk/users.go
+export interface UserConnectionLogEntry {
+  readonly id: string
+  readonly workspace_id: string
+  readonly workspace_name: string
+  readonly workspace_owner_username: string
+  readonly agent_name: string
+  readonly client_type: WorkspaceAgentClientType
+  readonly client_version: string
+  // Named type "net/netip.Addr" unknown, using "any"
+  // eslint-disable-next-line @typescript-eslint/no-explicit-any -- External type
+  readonly ip: any
+  readonly connected_at: string
+  readonly disconnected_at?: string
+}
+
+// From codersdk/users.go
+export interface UserConnectionLogRequest {
+  readonly After: string
+  readonly Limit: number
+}
+
 // From codersdk/insights.go
 export interface UserLatency {
   readonly template_ids: string[]
@@ -1277,6 +2248,17 @@ export interface UsersRequest extends Pagination {
   readonly q?: string
 }
 
+// From codersdk/templateversions.go
+export interface ValidateTemplateVersionRequest {
+  readonly file_id: string
+}
+
+// From codersdk/templateversions.go
+export interface ValidateTemplateVersionResponse {
+  readonly valid: boolean
+  readonly diagnostics: TemplateVersionDiagnostic[]
+}
+
 // From codersdk/client.go
 export interface ValidationError {
   readonly field: string
@@ -1311,6 +2293,10 @@ export interface Workspace {
   readonly deleting_at?: string
   readonly locked_at?: string
   readonly health: WorkspaceHealth
+  readonly automatic_updates: AutomaticUpdates
+  readonly update_deadline?: string
+  readonly external_metadata: WorkspaceExternalMetadata[]
+  readonly previous_names: WorkspacePreviousName[]
 }
 
 // From codersdk/workspaceagents.go
@@ -1347,7 +2333,26 @@ export interface WorkspaceAgent {
   readonly shutdown_script?: string
   readonly shutdown_script_timeout_seconds: number
   readonly subsystems: AgentSubsystem[]
+  readonly host_facts?: Record<string, string>
+  readonly clock_offset_ms: number
   readonly health: WorkspaceAgentHealth
+  readonly display_order: number
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentClientConnection {
+  readonly id: string
+  readonly user_id?: string
+  readonly client_type: WorkspaceAgentClientType
+  readonly client_version: string
+  readonly connected_at: string
+  readonly disconnected_at?: string
+}
+
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentClientConnectionsRequest {
+  readonly After: string
+  readonly Limit: number
 }
 
 // From codersdk/workspaceagents.go
@@ -1391,6 +2396,13 @@ export interface WorkspaceAgentMetadataDescription {
   readonly timeout: number
 }
 
+// From codersdk/workspaceagents.go
+export interface WorkspaceAgentMetadataHistoryRequest {
+  readonly Key: string
+  readonly After: string
+  readonly Limit: number
+}
+
 // From codersdk/workspaceagents.go
 export interface WorkspaceAgentMetadataResult {
   readonly collected_at: string
@@ -1399,6 +2411,18 @@ export interface WorkspaceAgentMetadataResult {
   readonly error: string
 }
 
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentReconnectingPTYSession {
+  readonly id: string
+  readonly command: string
+  readonly created_at: string
+}
+
+// From codersdk/workspaceagentconn.go
+export interface WorkspaceAgentReconnectingPTYSessionsResponse {
+  readonly sessions: WorkspaceAgentReconnectingPTYSession[]
+}
+
 // From codersdk/workspaceapps.go
 export interface WorkspaceApp {
   readonly id: string
@@ -1412,6 +2436,50 @@ export interface WorkspaceApp {
   readonly sharing_level: WorkspaceAppSharingLevel
   readonly healthcheck: Healthcheck
   readonly health: WorkspaceAppHealth
+  readonly display_order: number
+  readonly hidden: boolean
+}
+
+// From codersdk/workspaceapps.go
+export interface WorkspaceAppIdentityClaims {
+  readonly iss: string
+  readonly sub: string
+  readonly aud: string
+  readonly iat: number
+  readonly exp: number
+  readonly preferred_username: string
+  readonly email: string
+  readonly picture?: string
+}
+
+// From codersdk/workspaceapprovals.go
+export interface WorkspaceApproval {
+  readonly id: string
+  readonly workspace_id: string
+  readonly workspace_build_id: string
+  readonly requested_by: string
+  readonly created_at: string
+  readonly status: WorkspaceApprovalStatus
+  readonly decided_by?: string
+  readonly decided_at?: string
+  readonly reason?: string
+}
+
+// From codersdk/deployment.go
+export interface WorkspaceApprovalWebhookConfig {
+  readonly url: string
+  readonly secret: string
+}
+
+// From codersdk/workspaceapprovals.go
+export interface WorkspaceApprovalWebhookPayload {
+  readonly approval: WorkspaceApproval
+  readonly workspace_name: string
+  readonly workspace_owner: string
+  readonly template_id: string
+  readonly template_name: string
+  readonly requester_username: string
+  readonly callback_url: string
 }
 
 // From codersdk/workspacebuilds.go
@@ -1436,6 +2504,18 @@ export interface WorkspaceBuild {
   readonly max_deadline?: string
   readonly status: WorkspaceStatus
   readonly daily_cost: number
+  readonly initiator_token_name?: string
+}
+
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildHook {
+  readonly name: string
+  readonly phase: BuildHookPhase
+  readonly started_at: string
+  readonly completed_at: string
+  readonly attempts: number
+  readonly exit_code: number
+  readonly error?: string
 }
 
 // From codersdk/workspacebuilds.go
@@ -1444,10 +2524,44 @@ export interface WorkspaceBuildParameter {
   readonly value: string
 }
 
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildTimeline {
+  readonly stages: WorkspaceBuildTimelineStage[]
+  readonly hooks: WorkspaceBuildHook[]
+}
+
+// From codersdk/workspacebuilds.go
+export interface WorkspaceBuildTimelineStage {
+  readonly name: string
+  readonly started_at: string
+  readonly completed_at?: string
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceBuildsRequest extends Pagination {
   readonly WorkspaceID: string
   readonly Since: string
+  readonly Reason: BuildReason
+  readonly InitiatorID: string
+}
+
+// From codersdk/workspaces.go
+export interface WorkspaceConnection {
+  readonly id: string
+  readonly agent_id: string
+  readonly agent_name: string
+  readonly server: boolean
+  readonly client_type?: WorkspaceAgentClientType
+  readonly client_version?: string
+  readonly user_id?: string
+  readonly ip: string
+  readonly derp_region_id: number
+  readonly derp_region_name: string
+  readonly derp_latency_ms: number
+  readonly direct: boolean
+  readonly rx_bytes: number
+  readonly tx_bytes: number
+  readonly updated_at: string
 }
 
 // From codersdk/deployment.go
@@ -1468,6 +2582,14 @@ export interface WorkspaceDeploymentStats {
   readonly tx_bytes: number
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceExternalMetadata {
+  readonly namespace: string
+  readonly visibility: WorkspaceExternalMetadataVisibility
+  readonly data: Record<string, string>
+  readonly updated_at: string
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceFilter {
   readonly q?: string
@@ -1479,15 +2601,47 @@ export interface WorkspaceHealth {
   readonly failing_agents: string[]
 }
 
+// From codersdk/workspacehooks.go
+export interface WorkspaceHookConfig {
+  readonly name: string
+  readonly url: string
+  readonly events: WorkspaceHookEvent[]
+  readonly timeout: number
+  readonly failure_policy: WorkspaceHookFailurePolicy
+}
+
+// From codersdk/workspacehooks.go
+export interface WorkspaceHookPayload {
+  readonly event: WorkspaceHookEvent
+  readonly workspace_id: string
+  readonly workspace_name: string
+  readonly workspace_owner_id: string
+  readonly workspace_owner_name: string
+  readonly template_id: string
+  readonly template_name: string
+  readonly workspace_build_id: string
+  readonly build_number: number
+  readonly transition: WorkspaceTransition
+}
+
 // From codersdk/workspaces.go
 export interface WorkspaceOptions {
   readonly include_deleted?: boolean
 }
 
+// From codersdk/workspaces.go
+export interface WorkspacePreviousName {
+  readonly name: string
+  readonly renamed_at: string
+  readonly redirect_expires_at: string
+}
+
 // From codersdk/workspaceproxy.go
 export interface WorkspaceProxy extends Region {
   readonly derp_enabled: boolean
   readonly derp_only: boolean
+  readonly organization_ids: string[]
+  readonly group_ids: string[]
   readonly status?: WorkspaceProxyStatus
   readonly created_at: string
   readonly updated_at: string
@@ -1504,6 +2658,9 @@ export interface WorkspaceProxyBuildInfo {
 export interface WorkspaceProxyStatus {
   readonly status: ProxyHealthStatus
   readonly report?: ProxyHealthReport
+  readonly derp_status?: ProxyHealthStatus
+  readonly derp_error?: string
+  readonly version?: string
   readonly checked_at: string
 }
 
@@ -1513,6 +2670,30 @@ export interface WorkspaceQuota {
   readonly budget: number
 }
 
+// From codersdk/workspaces.go
+export interface WorkspaceQuotaBreakdown {
+  readonly credits_consumed: number
+  readonly budget: number
+  readonly budgets: WorkspaceQuotaBudget[]
+  readonly workspaces: WorkspaceQuotaConsumer[]
+}
+
+// From codersdk/workspaces.go
+export interface WorkspaceQuotaBudget {
+  readonly source: WorkspaceQuotaBudgetSource
+  readonly group_id?: string
+  readonly group_name?: string
+  readonly allowance: number
+  readonly applied: boolean
+}
+
+// From codersdk/workspaces.go
+export interface WorkspaceQuotaConsumer {
+  readonly workspace_id: string
+  readonly workspace_name: string
+  readonly daily_cost: number
+}
+
 // From codersdk/workspacebuilds.go
 export interface WorkspaceResource {
   readonly id: string
@@ -1526,6 +2707,7 @@ export interface WorkspaceResource {
   readonly agents?: WorkspaceAgent[]
   readonly metadata?: WorkspaceResourceMetadata[]
   readonly daily_cost: number
+  readonly display_order: number
 }
 
 // From codersdk/workspacebuilds.go
@@ -1535,6 +2717,20 @@ export interface WorkspaceResourceMetadata {
   readonly sensitive: boolean
 }
 
+// From codersdk/workspacewebhooks.go
+export interface WorkspaceWebhook {
+  readonly id: string
+  readonly workspace_id: string
+  readonly created_by: string
+  readonly created_at: string
+  readonly name: string
+  readonly action: WorkspaceWebhookAction
+  readonly agent_name?: string
+  readonly command?: string
+  readonly last_triggered_at?: string
+  readonly url: string
+}
+
 // From codersdk/workspaces.go
 export interface WorkspacesRequest extends Pagination {
   readonly q?: string
@@ -1550,6 +2746,19 @@ export interface WorkspacesResponse {
 export type APIKeyScope = "all" | "application_connect"
 export const APIKeyScopes: APIKeyScope[] = ["all", "application_connect"]
 
+// From codersdk/workspaceagents.go
+export type AgentActivitySource =
+  | "app"
+  | "port_forward"
+  | "reconnecting_pty"
+  | "ssh"
+export const AgentActivitySources: AgentActivitySource[] = [
+  "app",
+  "port_forward",
+  "reconnecting_pty",
+  "ssh",
+]
+
 // From codersdk/workspaceagents.go
 export type AgentSubsystem = "envbox" | "envbuilder" | "exectrace"
 export const AgentSubsystems: AgentSubsystem[] = [
@@ -1560,31 +2769,79 @@ export const AgentSubsystems: AgentSubsystem[] = [
 
 // From codersdk/audit.go
 export type AuditAction =
+  | "connect"
   | "create"
   | "delete"
   | "login"
   | "logout"
+  | "open"
   | "register"
   | "start"
   | "stop"
   | "write"
 export const AuditActions: AuditAction[] = [
+  "connect",
   "create",
   "delete",
   "login",
   "logout",
+  "open",
   "register",
   "start",
   "stop",
   "write",
 ]
 
+// From codersdk/auditlogarchivals.go
+export type AuditLogArchivalStatus = "failed" | "running" | "succeeded"
+export const AuditLogArchivalStatuses: AuditLogArchivalStatus[] = [
+  "failed",
+  "running",
+  "succeeded",
+]
+
+// From codersdk/workspaces.go
+export type AutomaticUpdates = "always" | "never"
+export const AutomaticUpdateses: AutomaticUpdates[] = ["always", "never"]
+
+// From codersdk/workspacebuilds.go
+export type BuildHookPhase = "clone" | "post" | "pre"
+export const BuildHookPhases: BuildHookPhase[] = ["clone", "post", "pre"]
+
 // From codersdk/workspacebuilds.go
-export type BuildReason = "autostart" | "autostop" | "initiator"
+export type BuildReason =
+  | "autodelete"
+  | "autolock"
+  | "autostart"
+  | "autostop"
+  | "bulk"
+  | "failedstop"
+  | "initiator"
+  | "webhook"
 export const BuildReasons: BuildReason[] = [
+  "autodelete",
+  "autolock",
   "autostart",
   "autostop",
+  "bulk",
+  "failedstop",
   "initiator",
+  "webhook",
+]
+
+// From codersdk/authorization.go
+export type EffectivePermissionSource =
+  | "group_acl"
+  | "org"
+  | "owner"
+  | "site"
+  | "user_acl"
+export const EffectivePermissionSources: EffectivePermissionSource[] = [
+  "group_acl",
+  "org",
+  "owner",
+  "site",
+  "user_acl",
 ]
 
 // From codersdk/deployment.go
@@ -1595,6 +2852,14 @@ export const Entitlements: Entitlement[] = [
   "not_entitled",
 ]
 
+// From codersdk/deployment.go
+export type EntitlementsSeverity = "error" | "none" | "warning"
+export const EntitlementsSeverities: EntitlementsSeverity[] = [
+  "error",
+  "none",
+  "warning",
+]
+
 // From codersdk/deployment.go
 export type Experiment =
   | "deployment_health_page"
@@ -1664,11 +2929,15 @@ export const InsightsReportIntervals: InsightsReportInterval[] = ["day"]
 
 // From codersdk/provisionerdaemons.go
 export type JobErrorCode =
+  | "BUILD_TIMEOUT"
   | "MISSING_TEMPLATE_PARAMETER"
   | "REQUIRED_TEMPLATE_VARIABLES"
+  | "RESOURCE_LIMIT_EXCEEDED"
 export const JobErrorCodes: JobErrorCode[] = [
+  "BUILD_TIMEOUT",
   "MISSING_TEMPLATE_PARAMETER",
   "REQUIRED_TEMPLATE_VARIABLES",
+  "RESOURCE_LIMIT_EXCEEDED",
 ]
 
 // From codersdk/provisionerdaemons.go
@@ -1690,6 +2959,65 @@ export const LoginTypes: LoginType[] = [
   "token",
 ]
 
+// From codersdk/netcheck.go
+export type NATType = "easy" | "hard" | "udp_blocked" | "unknown"
+export const NATTypes: NATType[] = ["easy", "hard", "udp_blocked", "unknown"]
+
+// From codersdk/platformevents.go
+export type PlatformEventType =
+  | "entitlements.changed"
+  | "template_access_request.created"
+  | "template_access_request.decided"
+  | "template_version_promotion.decided"
+  | "template_version_promotion.requested"
+  | "user.created"
+  | "user.deleted"
+  | "user.status_changed"
+  | "workspace.created"
+  | "workspace.deleted"
+  | "workspace_approval.decided"
+  | "workspace_approval.requested"
+  | "workspace_build.canceled"
+  | "workspace_build.failed"
+  | "workspace_build.started"
+  | "workspace_build.succeeded"
+export const PlatformEventTypes: PlatformEventType[] = [
+  "entitlements.changed",
+  "template_access_request.created",
+  "template_access_request.decided",
+  "template_version_promotion.decided",
+  "template_version_promotion.requested",
+  "user.created",
+  "user.deleted",
+  "user.status_changed",
+  "workspace.created",
+  "workspace.deleted",
+  "workspace_approval.decided",
+  "workspace_approval.requested",
+  "workspace_build.canceled",
+  "workspace_build.failed",
+  "workspace_build.started",
+  "workspace_build.succeeded",
+]
+
+// From codersdk/portpolicy.go
+export type PortPolicyAction = "allow" | "deny"
+export const PortPolicyActions: PortPolicyAction[] = ["allow", "deny"]
+
+// From codersdk/provisionerdaemons.go
+export type ProvisionerJobCancelOutcome = "forced" | "graceful"
+export const ProvisionerJobCancelOutcomes: ProvisionerJobCancelOutcome[] = [
+  "forced",
+  "graceful",
+]
+
+// From codersdk/provisionerdaemons.go
+export type ProvisionerJobQueueLimit = "organization" | "template"
+export const ProvisionerJobQueueLimits: ProvisionerJobQueueLimit[] = [
+  "organization",
+  "template",
+]
+
 // From codersdk/provisionerdaemons.go
 export type ProvisionerJobStatus =
   | "canceled"
@@ -1725,11 +3053,20 @@ export type ProxyHealthStatus =
   | "unhealthy"
   | "unreachable"
   | "unregistered"
+  | "version_mismatch"
 export const ProxyHealthStatuses: ProxyHealthStatus[] = [
   "ok",
   "unhealthy",
   "unreachable",
   "unregistered",
+  "version_mismatch",
+]
+
+// From codersdk/workspaceproxy.go
+export type ProxyVersionMismatchPolicy = "block" | "warn"
+export const ProxyVersionMismatchPolicys: ProxyVersionMismatchPolicy[] = [
+  "block",
+  "warn",
 ]
 
 // From codersdk/rbacresources.go
@@ -1747,10 +3084,12 @@ export type RBACResource =
   | "license"
   | "organization"
   | "organization_member"
+  | "platform_event"
   | "provisioner_daemon"
   | "replicas"
   | "system"
   | "template"
+  | "template_version_promotion"
   | "user"
   | "user_data"
   | "workspace"
@@ -1770,10 +3109,12 @@ export const RBACResources: RBACResource[] = [
   "license",
   "organization",
   "organization_member",
+  "platform_event",
   "provisioner_daemon",
   "replicas",
   "system",
   "template",
+  "template_version_promotion",
   "user",
   "user_data",
   "workspace",
@@ -1785,29 +3126,41 @@ export const RBACResources: RBACResource[] = [
 export type ResourceType =
   | "api_key"
   | "convert_login"
+  | "environment_variable"
   | "git_ssh_key"
   | "group"
   | "license"
   | "organization"
   | "template"
+  | "template_access_request"
   | "template_version"
+  | "template_version_promotion"
   | "user"
   | "workspace"
+  | "workspace_agent_auth_token"
+  | "workspace_approval"
   | "workspace_build"
   | "workspace_proxy"
+  | "workspace_webhook"
 export const ResourceTypes: ResourceType[] = [
   "api_key",
   "convert_login",
+  "environment_variable",
   "git_ssh_key",
   "group",
   "license",
   "organization",
   "template",
+  "template_access_request",
   "template_version",
+  "template_version_promotion",
   "user",
   "workspace",
+  "workspace_agent_auth_token",
+  "workspace_approval",
   "workspace_build",
   "workspace_proxy",
+  "workspace_webhook",
 ]
 
 // From codersdk/serversentevents.go
@@ -1818,13 +3171,45 @@ export const ServerSentEventTypes: ServerSentEventType[] = [
   "ping",
 ]
 
+// From codersdk/templateaccessrequests.go
+export type TemplateAccessRequestStatus = "approved" | "denied" | "pending"
+export const TemplateAccessRequestStatuses: TemplateAccessRequestStatus[] = [
+  "approved",
+  "denied",
+  "pending",
+]
+
 // From codersdk/insights.go
 export type TemplateAppsType = "app" | "builtin"
 export const TemplateAppsTypes: TemplateAppsType[] = ["app", "builtin"]
 
 // From codersdk/templates.go
-export type TemplateRole = "" | "admin" | "use"
-export const TemplateRoles: TemplateRole[] = ["", "admin", "use"]
+export type TemplateRole = "" | "admin" | "push" | "settings" | "use"
+export const TemplateRoles: TemplateRole[] = [
+  "",
+  "admin",
+  "push",
+  "settings",
+  "use",
+]
+
+// From codersdk/templateversions.go
+export type TemplateVersionDiagnosticSeverity = "error" | "warning"
+export const TemplateVersionDiagnosticSeveritys: TemplateVersionDiagnosticSeverity[] =
+  ["error", "warning"]
+
+// From codersdk/templateversionpromotions.go
+export type TemplateVersionPromotionStatus = "approved" | "pending" | "rejected"
+export const TemplateVersionPromotionStatuses: TemplateVersionPromotionStatus[] =
+  ["approved", "pending", "rejected"]
+
+// From codersdk/templateversions.go
+export type TemplateVersionStateResourceStatus =
+  | "matched"
+  | "missing"
+  | "unmanaged"
+export const TemplateVersionStateResourceStatuses: TemplateVersionStateResourceStatus[] =
+  ["matched", "missing", "unmanaged"]
 
 // From codersdk/templateversions.go
 export type TemplateVersionWarning = "UNSUPPORTED_WORKSPACES"
@@ -1843,6 +3228,23 @@ export const ValidationMonotonicOrders: ValidationMonotonicOrder[] = [
   "increasing",
 ]
 
+// From codersdk/workspaceagents.go
+export type WorkspaceAgentClientType =
+  | "cli"
+  | "jetbrains"
+  | "port_forward"
+  | "ssh"
+  | "unknown"
+  | "vscode"
+export const WorkspaceAgentClientTypes: WorkspaceAgentClientType[] = [
+  "cli",
+  "jetbrains",
+  "port_forward",
+  "ssh",
+  "unknown",
+  "vscode",
+]
+
 // From codersdk/workspaceagents.go
 export type WorkspaceAgentLifecycle =
   | "created"
@@ -1922,6 +3324,60 @@ export const WorkspaceAppSharingLevels: WorkspaceAppSharingLevel[] = [
   "public",
 ]
 
+// From codersdk/workspaceapprovals.go
+export type WorkspaceApprovalStatus = "approved" | "pending" | "rejected"
+export const WorkspaceApprovalStatuses: WorkspaceApprovalStatus[] = [
+  "approved",
+  "pending",
+  "rejected",
+]
+
+// From codersdk/workspacebuildqueue.go
+export type WorkspaceBuildConflictPolicy = "fail" | "queue" | "supersede"
+export const WorkspaceBuildConflictPolicys: WorkspaceBuildConflictPolicy[] = [
+  "fail",
+  "queue",
+  "supersede",
+]
+
+// From codersdk/workspaces.go
+export type WorkspaceExternalMetadataVisibility = "private" | "public"
+export const WorkspaceExternalMetadataVisibilitys: WorkspaceExternalMetadataVisibility[] =
+  ["private", "public"]
+
+// From codersdk/workspacehooks.go
+export type WorkspaceHookEvent =
+  | "post_start"
+  | "post_stop"
+  | "pre_start"
+  | "pre_stop"
+export const WorkspaceHookEvents: WorkspaceHookEvent[] = [
+  "post_start",
+  "post_stop",
+  "pre_start",
+  "pre_stop",
+]
+
+// From codersdk/workspacehooks.go
+export type WorkspaceHookFailurePolicy = "fail" | "ignore"
+export const WorkspaceHookFailurePolicys: WorkspaceHookFailurePolicy[] = [
+  "fail",
+  "ignore",
+]
+
+// From codersdk/workspaces.go
+export type WorkspaceQuotaBudgetSource =
+  | "group"
+  | "group_override"
+  | "organization_default"
+  | "user_override"
+export const WorkspaceQuotaBudgetSources: WorkspaceQuotaBudgetSource[] = [
+  "group",
+  "group_override",
+  "organization_default",
+  "user_override",
+]
+
 // From codersdk/workspacebuilds.go
 export type WorkspaceStatus =
   | "canceled"
@@ -1955,8 +3411,17 @@ export const WorkspaceTransitions: WorkspaceTransition[] = [
   "stop",
 ]
 
+// From codersdk/workspacewebhooks.go
+export type WorkspaceWebhookAction = "exec" | "start" | "stop" | "update"
+export const WorkspaceWebhookActions: WorkspaceWebhookAction[] = [
+  "exec",
+  "start",
+  "stop",
+  "update",
+]
+
 // From codersdk/workspaceproxy.go
-export type RegionTypes = Region | WorkspaceProxy
+export type RegionTypes = Region | WorkspaceProxy | RankedRegion
 
 // The code below is generated from coderd/healthcheck.
 
@@ -1970,6 +3435,19 @@ export interface HealthcheckAccessURLReport {
   readonly error?: string
 }
 
+// From healthcheck/coordinator.go
+export interface HealthcheckCoordinatorReport {
+  readonly healthy: boolean
+  readonly replica_id: string
+  readonly type: HealthcheckCoordinatorType
+  readonly high_availability: boolean
+  readonly pubsub_latency: string
+  readonly pubsub_latency_ms: number
+  readonly last_failover_at?: string
+  readonly last_failover_error?: string
+  readonly error?: string
+}
+
 // From healthcheck/derp.go
 export interface HealthcheckDERPNodeReport {
   readonly healthy: boolean
@@ -2036,6 +3514,7 @@ export interface HealthcheckReport {
   readonly access_url: HealthcheckAccessURLReport
   readonly websocket: HealthcheckWebsocketReport
   readonly database: HealthcheckDatabaseReport
+  readonly coordinator: HealthcheckCoordinatorReport
   readonly coder_version: string
 }
 
@@ -2046,3 +3525,11 @@ export interface HealthcheckWebsocketReport {
   readonly code: number
   readonly error?: string
 }
+
+// From healthcheck/coordinator.go
+export type HealthcheckCoordinatorType = "ha" | "memory" | "pg"
+export const HealthcheckCoordinatorTypes: HealthcheckCoordinatorType[] = [
+  "ha",
+  "memory",
+  "pg",
+]
@@ -67,6 +67,23 @@ export const NotReachableBadge: FC = () => {
   )
 }
 
+export const VersionMismatchBadge: FC<{ version?: string }> = ({
+  version,
+}) => {
+  const styles = useStyles()
+  return (
+    <Tooltip
+      title={`Workspace Proxy runs version ${
+        version ?? "unknown"
+      }, which does not match the version of this deployment.`}
+    >
+      <span className={combineClasses([styles.badge, styles.warnBadge])}>
+        Version Mismatch
+      </span>
+    </Tooltip>
+  )
+}
+
 export const DisabledBadge: FC = () => {
   const styles = useStyles()
   return (
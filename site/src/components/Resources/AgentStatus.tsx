@@ -250,39 +250,43 @@ const OffLifecycle: React.FC = () => {
 const ConnectedStatus: React.FC<{
   agent: WorkspaceAgent
 }> = ({ agent }) => {
-  switch (agent.startup_script_behavior) {
-    case "non-blocking":
-      return <ReadyLifecycle />
-    case "blocking":
-      return (
-        <ChooseOne>
-          <Cond condition={agent.lifecycle_state === "ready"}>
-            <ReadyLifecycle />
-          </Cond>
-          <Cond condition={agent.lifecycle_state === "start_timeout"}>
-            <StartTimeoutLifecycle agent={agent} />
-          </Cond>
-          <Cond condition={agent.lifecycle_state === "start_error"}>
-            <StartErrorLifecycle agent={agent} />
-          </Cond>
-          <Cond condition={agent.lifecycle_state === "shutting_down"}>
-            <ShuttingDownLifecycle />
-          </Cond>
-          <Cond condition={agent.lifecycle_state === "shutdown_timeout"}>
-            <ShutdownTimeoutLifecycle agent={agent} />
-          </Cond>
-          <Cond condition={agent.lifecycle_state === "shutdown_error"}>
-            <ShutdownErrorLifecycle agent={agent} />
-          </Cond>
-          <Cond condition={agent.lifecycle_state === "off"}>
-            <OffLifecycle />
-          </Cond>
-          <Cond>
-            <StartingLifecycle />
-          </Cond>
-        </ChooseOne>
-      )
+  // Agents with a non-blocking startup script can be used while they are
+  // starting, but failing to start is still worth pointing out, so users can
+  // find the troubleshooting link.
+  const isStarting =
+    agent.lifecycle_state === "created" || agent.lifecycle_state === "starting"
+  if (agent.startup_script_behavior === "non-blocking" && isStarting) {
+    return <ReadyLifecycle />
   }
+
+  return (
+    <ChooseOne>
+      <Cond condition={agent.lifecycle_state === "ready"}>
+        <ReadyLifecycle />
+      </Cond>
+      <Cond condition={agent.lifecycle_state === "start_timeout"}>
+        <StartTimeoutLifecycle agent={agent} />
+      </Cond>
+      <Cond condition={agent.lifecycle_state === "start_error"}>
+        <StartErrorLifecycle agent={agent} />
+      </Cond>
+      <Cond condition={agent.lifecycle_state === "shutting_down"}>
+        <ShuttingDownLifecycle />
+      </Cond>
+      <Cond condition={agent.lifecycle_state === "shutdown_timeout"}>
+        <ShutdownTimeoutLifecycle agent={agent} />
+      </Cond>
+      <Cond condition={agent.lifecycle_state === "shutdown_error"}>
+        <ShutdownErrorLifecycle agent={agent} />
+      </Cond>
+      <Cond condition={agent.lifecycle_state === "off"}>
+        <OffLifecycle />
+      </Cond>
+      <Cond>
+        <StartingLifecycle />
+      </Cond>
+    </ChooseOne>
+  )
 }
 
 const DisconnectedStatus: React.FC = () => {
@@ -29,6 +29,7 @@ const createTemplateVersionParameter = (
     description_plaintext: "",
     required: true,
     ephemeral: false,
+    dynamic_options: false,
     ...partial,
   }
 }
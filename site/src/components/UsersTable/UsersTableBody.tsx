@@ -21,7 +21,13 @@ const isOwnerRole = (role: TypesGen.Role): boolean => {
   return role.name === "owner"
 }
 
-const roleOrder = ["owner", "user-admin", "template-admin", "auditor"]
+const roleOrder = [
+  "owner",
+  "user-admin",
+  "template-admin",
+  "template-approver",
+  "auditor",
+]
 
 const sortRoles = (roles: TypesGen.Role[]) => {
   return roles.slice(0).sort((a, b) => {
@@ -282,6 +282,12 @@ export const Workspace: FC<React.PropsWithChildren<WorkspaceProps>> = ({
                   Position in queue:{" "}
                   <strong>{workspace.latest_build.job.queue_position}</strong>
                 </div>
+                {workspace.latest_build.job.queue_limited_by && (
+                  <div>
+                    The build waits until fewer jobs of its{" "}
+                    {workspace.latest_build.job.queue_limited_by} are running.
+                  </div>
+                )}
               </AlertDetail>
             </Alert>
           </Maybe>
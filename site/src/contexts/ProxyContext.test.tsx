@@ -122,6 +122,17 @@ describe("ProxyContextGetURLs", () => {
       )
     },
   )
+
+  it("uses the first healthy preferred region", () => {
+    const preferred = getPreferredProxy(
+      MockWorkspaceProxies,
+      undefined,
+      {},
+      false,
+      [MockUnhealthyWildWorkspaceProxy.id, MockHealthyWildWorkspaceProxy.id],
+    )
+    expect(preferred.proxy?.id).toBe(MockHealthyWildWorkspaceProxy.id)
+  })
 })
 
 const TestingComponent = () => {
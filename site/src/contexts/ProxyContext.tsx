@@ -1,6 +1,6 @@
 import { useQuery } from "@tanstack/react-query"
 import { getWorkspaceProxies, getWorkspaceProxyRegions } from "api/api"
-import { Region, WorkspaceProxy } from "api/typesGenerated"
+import { Region, RegionsResponse, WorkspaceProxy } from "api/typesGenerated"
 import { useDashboard } from "components/Dashboard/DashboardProvider"
 import {
   createContext,
@@ -111,9 +111,9 @@ export const ProxyProvider: FC<PropsWithChildren> = ({ children }) => {
       try {
         const obj = JSON.parse(rawContent as string)
         if ("regions" in obj) {
-          return obj.regions as Region[]
+          return obj as RegionsResponse<Region>
         }
-        return obj as Region[]
+        return { regions: obj as Region[] }
       } catch (ex) {
         // Ignore this and fetch as normal!
       }
@@ -121,16 +121,15 @@ export const ProxyProvider: FC<PropsWithChildren> = ({ children }) => {
   })
 
   const permissions = usePermissions()
-  const query = async (): Promise<Region[]> => {
+  const query = async (): Promise<RegionsResponse<Region>> => {
     const endpoint = permissions.editWorkspaceProxies
       ? getWorkspaceProxies
       : getWorkspaceProxyRegions
-    const resp = await endpoint()
-    return resp.regions
+    return endpoint()
   }
 
   const {
-    data: proxiesResp,
+    data: regionsResp,
     error: proxiesError,
     isLoading: proxiesLoading,
     isFetched: proxiesFetched,
@@ -140,6 +139,10 @@ export const ProxyProvider: FC<PropsWithChildren> = ({ children }) => {
     staleTime: initialData ? Infinity : undefined,
     initialData,
   })
+  const proxiesResp = regionsResp?.regions
+  // preferredRegionIDs is the order coderd prefers the regions in for this
+  // user, based on the geo rules and the latencies reported before.
+  const preferredRegionIDs = regionsResp?.preferred_region_ids
 
   // Every time we get a new proxiesResponse, update the latency check
   // to each workspace proxy.
@@ -159,16 +162,17 @@ export const ProxyProvider: FC<PropsWithChildren> = ({ children }) => {
         // Do not auto select based on latencies, as inconsistent latencies can cause this
         // to behave poorly.
         false,
+        preferredRegionIDs,
       ),
     )
-  }, [proxiesResp, proxyLatencies])
+  }, [proxiesResp, proxyLatencies, preferredRegionIDs])
 
   // This useEffect ensures the proxy to be used is updated whenever the state changes.
   // This includes proxies being loaded, latencies being calculated, and the user selecting a proxy.
   useEffect(() => {
     updateProxy()
     // eslint-disable-next-line react-hooks/exhaustive-deps -- Only update if the source data changes
-  }, [proxiesResp, proxyLatencies])
+  }, [proxiesResp, proxyLatencies, preferredRegionIDs])
 
   return (
     <ProxyContext.Provider
@@ -226,12 +230,15 @@ export const useProxy = (): ProxyContextValue => {
  * @param selectedProxy Is the proxy saved in local storage. If this is undefined, default behavior is used.
  * @param latencies If provided, this is used to determine the best proxy to default to.
  *                  If not, `primary` is always the best default.
+ * @param preferredRegionIDs Is the order coderd prefers the proxies in. If provided, the first
+ *                           healthy proxy in it is used instead of `primary`.
  */
 export const getPreferredProxy = (
   proxies: Region[],
   selectedProxy?: Region,
   latencies?: Record<string, ProxyLatencyReport>,
   autoSelectBasedOnLatency = true,
+  preferredRegionIDs?: string[],
 ): PreferredProxy => {
   // If a proxy is selected, make sure it is in the list of proxies. If it is not
   // we should default to the primary.
@@ -244,6 +251,14 @@ export const getPreferredProxy = (
     // By default, use the primary proxy.
     selectedProxy = proxies.find((proxy) => proxy.name === "primary")
 
+    // Prefer the proxy coderd picked for this user, if any.
+    const preferred = preferredRegionIDs
+      ?.map((id) => proxies.find((proxy) => proxy.id === id))
+      .find((proxy) => proxy?.healthy)
+    if (preferred) {
+      selectedProxy = preferred
+    }
+
     // If we have latencies, then attempt to use the best proxy by latency instead.
     const best = selectByLatency(proxies, latencies)
     if (autoSelectBasedOnLatency && best) {
@@ -81,6 +81,7 @@ export const Parameters: Story = {
           },
         ],
         ephemeral: false,
+        dynamic_options: false,
       },
     ],
   },
@@ -59,35 +59,31 @@ const UpdateGroupForm: FC<{
             label="Name"
             disabled={isEveryoneGroup(group)}
           />
-          {isEveryoneGroup(group) ? (
-            <></>
-          ) : (
-            <>
-              <TextField
-                {...getFieldHelpers(
-                  "display_name",
-                  "Optional: keep empty to default to the name.",
-                )}
-                onChange={onChangeTrimmed(form)}
-                autoComplete="display_name"
-                autoFocus
-                fullWidth
-                label="Display Name"
-                disabled={isEveryoneGroup(group)}
-              />
-              <LazyIconField
-                {...getFieldHelpers("avatar_url")}
-                onChange={onChangeTrimmed(form)}
-                fullWidth
-                label={t("form.fields.icon")}
-                onPickEmoji={(value) => form.setFieldValue("avatar_url", value)}
-              />
-            </>
-          )}
+          <TextField
+            {...getFieldHelpers(
+              "display_name",
+              "Optional: keep empty to default to the name.",
+            )}
+            onChange={onChangeTrimmed(form)}
+            autoComplete="display_name"
+            autoFocus
+            fullWidth
+            label="Display Name"
+          />
+          <LazyIconField
+            {...getFieldHelpers("avatar_url")}
+            onChange={onChangeTrimmed(form)}
+            fullWidth
+            label={t("form.fields.icon")}
+            onPickEmoji={(value) => form.setFieldValue("avatar_url", value)}
+          />
           <TextField
             {...getFieldHelpers(
               "quota_allowance",
-              `This group gives ${form.values.quota_allowance} quota credits to each
+              isEveryoneGroup(group)
+                ? `Every member of the organization receives ${form.values.quota_allowance}
+            quota credits by default.`
+                : `This group gives ${form.values.quota_allowance} quota credits to each
             of its members.`,
             )}
             onChange={onChangeTrimmed(form)}
@@ -24,6 +24,7 @@ const sections = {
   access_url: "Access URL",
   websocket: "Websocket",
   database: "Database",
+  coordinator: "Coordinator",
 } as const
 
 export default function HealthPage() {
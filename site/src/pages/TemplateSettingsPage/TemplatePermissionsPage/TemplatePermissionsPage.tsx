@@ -66,7 +66,7 @@ export const TemplatePermissionsPage: FC<
             organizationId={organizationId}
             templateID={template.id}
             templateACL={templateACL}
-            canUpdatePermissions={Boolean(permissions?.canUpdateTemplate)}
+            canUpdatePermissions={Boolean(permissions?.canUpdatePermissions)}
             onAddUser={(user, role, reset) => {
               send("ADD_USER", { user, role, onDone: reset })
             }}
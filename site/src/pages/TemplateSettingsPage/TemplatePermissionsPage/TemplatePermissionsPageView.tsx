@@ -104,6 +104,12 @@ const AddTemplateUserOrGroup: React.FC<AddTemplateUserOrGroupProps> = ({
           <MenuItem key="use" value="use">
             Use
           </MenuItem>
+          <MenuItem key="push" value="push">
+            Push
+          </MenuItem>
+          <MenuItem key="settings" value="settings">
+            Settings
+          </MenuItem>
           <MenuItem key="admin" value="admin">
             Admin
           </MenuItem>
@@ -139,6 +145,22 @@ const RoleSelect: FC<SelectProps> = (props) => {
           </div>
         </div>
       </MenuItem>
+      <MenuItem key="push" value="push" className={styles.menuItem}>
+        <div>
+          <div>Push</div>
+          <div className={styles.menuItemSecondary}>
+            Can also push and promote new versions of this template.
+          </div>
+        </div>
+      </MenuItem>
+      <MenuItem key="settings" value="settings" className={styles.menuItem}>
+        <div>
+          <div>Settings</div>
+          <div className={styles.menuItemSecondary}>
+            Can also change the settings of this template.
+          </div>
+        </div>
+      </MenuItem>
       <MenuItem key="admin" value="admin" className={styles.menuItem}>
         <div>
           <div>Admin</div>
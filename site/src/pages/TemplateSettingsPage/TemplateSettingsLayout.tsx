@@ -20,6 +20,13 @@ const templatePermissions = (templateId: string) =>
       },
       action: "update",
     },
+    canUpdatePermissions: {
+      object: {
+        resource_type: "template",
+        resource_id: templateId,
+      },
+      action: "manage_acl",
+    },
   }) as const
 
 const fetchTemplateSettings = async (orgId: string, name: string) => {
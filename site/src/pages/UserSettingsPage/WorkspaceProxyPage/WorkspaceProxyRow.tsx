@@ -9,6 +9,7 @@ import {
   NotHealthyBadge,
   NotReachableBadge,
   NotRegisteredBadge,
+  VersionMismatchBadge,
 } from "components/DeploySettingsLayout/Badges"
 import { ProxyLatencyReport } from "contexts/useProxyLatency"
 import { getLatencyColor } from "utils/latency"
@@ -183,6 +184,8 @@ const DetailedProxyStatus: FC<{
       return <NotReachableBadge />
     case "unregistered":
       return <NotRegisteredBadge />
+    case "version_mismatch":
+      return <VersionMismatchBadge version={proxy.status.version} />
     default:
       return <NotHealthyBadge />
   }
@@ -14,6 +14,7 @@ export const MockOrganization: TypesGen.Organization = {
   name: "Test Organization",
   created_at: "",
   updated_at: "",
+  max_concurrent_provisioner_jobs: 0,
 }
 
 export const MockTemplateDAUResponse: TypesGen.DAUsResponse = {
@@ -79,8 +80,11 @@ export const MockPrimaryWorkspaceProxy: TypesGen.WorkspaceProxy = {
   healthy: true,
   path_app_url: "https://coder.com",
   wildcard_hostname: "*.coder.com",
+  health_latency_ms: 0,
   derp_enabled: true,
   derp_only: false,
+  organization_ids: [],
+  group_ids: [],
   created_at: new Date().toISOString(),
   updated_at: new Date().toISOString(),
   deleted: false,
@@ -98,8 +102,11 @@ export const MockHealthyWildWorkspaceProxy: TypesGen.WorkspaceProxy = {
   healthy: true,
   path_app_url: "https://external.com",
   wildcard_hostname: "*.external.com",
+  health_latency_ms: 32,
   derp_enabled: true,
   derp_only: false,
+  organization_ids: [],
+  group_ids: [],
   created_at: new Date().toISOString(),
   updated_at: new Date().toISOString(),
   deleted: false,
@@ -117,8 +124,11 @@ export const MockUnhealthyWildWorkspaceProxy: TypesGen.WorkspaceProxy = {
   healthy: false,
   path_app_url: "https://unhealthy.coder.com",
   wildcard_hostname: "*unhealthy..coder.com",
+  health_latency_ms: 0,
   derp_enabled: true,
   derp_only: true,
+  organization_ids: [],
+  group_ids: [],
   created_at: new Date().toISOString(),
   updated_at: new Date().toISOString(),
   deleted: false,
@@ -144,8 +154,11 @@ export const MockWorkspaceProxies: TypesGen.WorkspaceProxy[] = [
     healthy: true,
     path_app_url: "https://cowboy.coder.com",
     wildcard_hostname: "",
+    health_latency_ms: 54,
     derp_enabled: false,
     derp_only: false,
+    organization_ids: [],
+    group_ids: [],
     created_at: new Date().toISOString(),
     updated_at: new Date().toISOString(),
     deleted: false,
@@ -449,6 +462,18 @@ export const MockTemplate: TypesGen.Template = {
   locked_ttl_ms: 0,
   allow_user_autostart: false,
   allow_user_autostop: false,
+  require_active_version: false,
+  require_active_version_grace_period_ms: 0,
+  max_build_duration_ms: 0,
+  provisioner_memory_limit_bytes: 0,
+  provisioner_cpu_limit_ms: 0,
+  max_concurrent_provisioner_jobs: 0,
+  max_session_idle_ms: 0,
+  port_policy: {},
+  require_workspace_approval: false,
+  require_agent_binary_verification: false,
+  require_promotion_approval: false,
+  autostop_activity_sources: [],
 }
 
 export const MockTemplateVersionFiles: TemplateVersionFiles = {
@@ -860,6 +885,9 @@ export const MockWorkspace: TypesGen.Workspace = {
     healthy: true,
     failing_agents: [],
   },
+  automatic_updates: "never",
+  external_metadata: [],
+  previous_names: [],
 }
 
 export const MockStoppedWorkspace: TypesGen.Workspace = {
@@ -978,6 +1006,7 @@ export const MockTemplateVersionParameter1: TypesGen.TemplateVersionParameter =
     options: [],
     required: true,
     ephemeral: false,
+    dynamic_options: false,
   }
 
 export const MockTemplateVersionParameter2: TypesGen.TemplateVersionParameter =
@@ -995,6 +1024,7 @@ export const MockTemplateVersionParameter2: TypesGen.TemplateVersionParameter =
     validation_monotonic: "increasing",
     required: true,
     ephemeral: false,
+    dynamic_options: false,
   }
 
 export const MockTemplateVersionParameter3: TypesGen.TemplateVersionParameter =
@@ -1011,6 +1041,7 @@ export const MockTemplateVersionParameter3: TypesGen.TemplateVersionParameter =
     validation_regex: "^[a-z]{3}$",
     required: true,
     ephemeral: false,
+    dynamic_options: false,
   }
 
 export const MockTemplateVersionParameter4: TypesGen.TemplateVersionParameter =
@@ -1025,6 +1056,7 @@ export const MockTemplateVersionParameter4: TypesGen.TemplateVersionParameter =
     options: [],
     required: true,
     ephemeral: false,
+    dynamic_options: false,
   }
 
 export const MockTemplateVersionParameter5: TypesGen.TemplateVersionParameter =
@@ -1042,6 +1074,7 @@ export const MockTemplateVersionParameter5: TypesGen.TemplateVersionParameter =
     validation_monotonic: "decreasing",
     required: true,
     ephemeral: false,
+    dynamic_options: false,
   }
 
 export const MockTemplateVersionVariable1: TypesGen.TemplateVersionVariable = {
@@ -1446,6 +1479,7 @@ export const mockApiError = ({
 export const MockEntitlements: TypesGen.Entitlements = {
   errors: [],
   warnings: [],
+  severity: "none",
   has_license: false,
   features: withDefaultFeatures({}),
   require_telemetry: false,
@@ -1456,6 +1490,7 @@ export const MockEntitlements: TypesGen.Entitlements = {
 export const MockEntitlementsWithWarnings: TypesGen.Entitlements = {
   errors: [],
   warnings: ["You are over your active user limit.", "And another thing."],
+  severity: "warning",
   has_license: true,
   trial: false,
   require_telemetry: false,
@@ -1481,6 +1516,7 @@ export const MockEntitlementsWithWarnings: TypesGen.Entitlements = {
 export const MockEntitlementsWithAuditLog: TypesGen.Entitlements = {
   errors: [],
   warnings: [],
+  severity: "none",
   has_license: true,
   require_telemetry: false,
   trial: false,
@@ -1496,6 +1532,7 @@ export const MockEntitlementsWithAuditLog: TypesGen.Entitlements = {
 export const MockEntitlementsWithScheduling: TypesGen.Entitlements = {
   errors: [],
   warnings: [],
+  severity: "none",
   has_license: true,
   require_telemetry: false,
   trial: false,
@@ -1676,6 +1713,8 @@ export const MockGroup: TypesGen.Group = {
   members: [MockUser, MockUser2],
   quota_allowance: 5,
   source: "user",
+  metadata: {},
+  version: "3f8a1c2b9d4e5f60",
 }
 
 export const MockTemplateACL: TypesGen.TemplateACL = {
@@ -2285,6 +2324,17 @@ export const MockHealth = {
     latency: 92570,
     error: null,
   },
+  coordinator: {
+    healthy: true,
+    replica_id: "b2ea5c6e-3ed4-4b9d-9b3a-0f7b1a6c3e1d",
+    type: "ha",
+    high_availability: true,
+    pubsub_latency: "1.2ms",
+    pubsub_latency_ms: 1,
+    last_failover_at: null,
+    last_failover_error: null,
+    error: null,
+  },
   coder_version: "v0.27.1-devel+c575292",
 }
 
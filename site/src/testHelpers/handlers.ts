@@ -181,6 +181,7 @@ export const handlers = [
     const permissions = [
       ...Object.keys(permissionsToCheck),
       "canUpdateTemplate",
+      "canUpdatePermissions",
       "updateWorkspace",
     ]
     const response = permissions.reduce((obj, permission) => {
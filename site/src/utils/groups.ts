@@ -9,6 +9,8 @@ export const everyOneGroup = (organizationId: string): Group => ({
   avatar_url: "",
   quota_allowance: 0,
   source: "user",
+  metadata: {},
+  version: "",
 })
 
 /**
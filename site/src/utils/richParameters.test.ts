@@ -40,6 +40,7 @@ test("selectInitialRichParametersValues return default value when default build
       ],
       required: false,
       ephemeral: false,
+      dynamic_options: false,
     },
   ]
 
@@ -275,7 +275,11 @@ const getPendingWorkspaceStatusText = (
   if (!provisionerJob || provisionerJob.queue_size === 0) {
     return t("workspaceStatus.pending", { ns: "common" })
   }
-  return "Position in queue: " + provisionerJob.queue_position
+  const position = "Position in queue: " + provisionerJob.queue_position
+  if (provisionerJob.queue_limited_by) {
+    return `${position} (waiting for the ${provisionerJob.queue_limited_by} concurrency limit)`
+  }
+  return position
 }
 
 const LoadingIcon = () => {
@@ -94,6 +94,10 @@ type Options struct {
 	BlockEndpoints bool
 	Logger         slog.Logger
 	ListenPort     uint16
+	// ForwardTCPFilter reports whether TCP connections to a port that no
+	// listener of the conn accepts may be forwarded to the local host. All
+	// ports may be forwarded if nil.
+	ForwardTCPFilter func(port uint16) bool
 }
 
 // NodeID creates a Tailscale NodeID from the last 8 bytes of a UUID. It ensures
@@ -277,6 +281,7 @@ func NewConn(options *Options) (conn *Conn, err error) {
 	dialContext, dialCancel := context.WithCancel(context.Background())
 	server := &Conn{
 		blockEndpoints:           options.BlockEndpoints,
+		forwardTCPFilter:         options.ForwardTCPFilter,
 		dialContext:              dialContext,
 		dialCancel:               dialCancel,
 		closed:                   make(chan struct{}),
@@ -389,6 +394,8 @@ type Conn struct {
 	closed         chan struct{}
 	logger         slog.Logger
 	blockEndpoints bool
+	// forwardTCPFilter is ForwardTCPFilter of the options.
+	forwardTCPFilter func(port uint16) bool
 
 	dialer           *tsdial.Dialer
 	tunDevice        *tstun.Wrapper
@@ -911,6 +918,13 @@ func (c *Conn) forwardTCP(_, dst netip.AddrPort) (handler func(net.Conn), opts [
 	ln, ok := c.listeners[listenKey{"tcp", "", fmt.Sprint(dst.Port())}]
 	c.mutex.Unlock()
 	if !ok {
+		if c.forwardTCPFilter != nil && !c.forwardTCPFilter(dst.Port()) {
+			// Intercept the connection to close it instead of forwarding it
+			// to the local host.
+			return func(conn net.Conn) {
+				_ = conn.Close()
+			}, nil, true
+		}
 		return nil, nil, false
 	}
 	// See: https://github.com/tailscale/tailscale/blob/c7cea825aea39a00aca71ea02bab7266afc03e7c/wgengine/netstack/netstack.go#L888
@@ -42,6 +42,9 @@ type Coordinator interface {
 	// incoming connections and publishes node updates.
 	// Name is just used for debug information. It can be left blank.
 	ServeAgent(conn net.Conn, id uuid.UUID, name string) error
+	// AgentPeers returns the clients coordinating with the agent with the
+	// specified ID that sent their node.
+	AgentPeers(ctx context.Context, agentID uuid.UUID) ([]AgentPeer, error)
 	// Close closes the coordinator.
 	Close() error
 
@@ -79,6 +82,16 @@ type Node struct {
 	Endpoints []string `json:"endpoints"`
 }
 
+// AgentPeer is a client coordinating with an agent.
+type AgentPeer struct {
+	// ID is the ID of the client connection.
+	ID uuid.UUID
+	// Multi is whether the client coordinates with many agents over a single
+	// connection, like coderd and workspace proxies do for workspace apps.
+	Multi bool
+	Node  *Node
+}
+
 // ServeCoordinator matches the RW structure of a coordinator to exchange node messages.
 func ServeCoordinator(conn net.Conn, updateNodes func(node []*Node) error) (func(node *Node), <-chan error) {
 	errChan := make(chan error, 1)
@@ -236,6 +249,42 @@ func (c *core) node(id uuid.UUID) *Node {
 	return c.nodes[id]
 }
 
+func (c *coordinator) AgentPeers(_ context.Context, agentID uuid.UUID) ([]AgentPeer, error) {
+	return c.core.agentPeers(agentID), nil
+}
+
+func (c *core) agentPeers(agentID uuid.UUID) []AgentPeer {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return AgentPeersFromLocal(agentID, c.agentToConnectionSockets, c.nodes)
+}
+
+// AgentPeersFromLocal returns the clients of the agent that sent their node,
+// from the in-memory state of a coordinator.
+func AgentPeersFromLocal(
+	agentID uuid.UUID,
+	agentToConnectionSocketsMap map[uuid.UUID]map[uuid.UUID]Queue,
+	nodesMap map[uuid.UUID]*Node,
+) []AgentPeer {
+	peers := make([]AgentPeer, 0, len(agentToConnectionSocketsMap[agentID]))
+	for id, conn := range agentToConnectionSocketsMap[agentID] {
+		node, ok := nodesMap[id]
+		if !ok {
+			continue
+		}
+		_, multi := conn.(*MultiAgent)
+		peers = append(peers, AgentPeer{
+			ID:    id,
+			Multi: multi,
+			Node:  node,
+		})
+	}
+	slices.SortFunc(peers, func(a, b AgentPeer) int {
+		return slice.Ascending(a.ID.String(), b.ID.String())
+	})
+	return peers
+}
+
 func (c *coordinator) NodeCount() int {
 	return c.core.nodeCount()
 }
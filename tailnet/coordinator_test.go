@@ -268,6 +268,63 @@ func TestCoordinator(t *testing.T) {
 		<-agentErrChan1
 		<-closeAgentChan1
 	})
+
+	t.Run("AgentPeers", func(t *testing.T) {
+		t.Parallel()
+		logger := slogtest.Make(t, nil).Leveled(slog.LevelDebug)
+		coordinator := tailnet.NewCoordinator(logger)
+		ctx := testutil.Context(t, testutil.WaitShort)
+		agentID := uuid.New()
+
+		client, server := net.Pipe()
+		sendNode, errChan := tailnet.ServeCoordinator(client, func(node []*tailnet.Node) error {
+			return nil
+		})
+		clientID := uuid.New()
+		closeChan := make(chan struct{})
+		go func() {
+			err := coordinator.ServeClient(server, clientID, agentID)
+			assert.NoError(t, err)
+			close(closeChan)
+		}()
+
+		multiID := uuid.New()
+		multi := coordinator.ServeMultiAgent(multiID)
+		defer multi.Close()
+		require.NoError(t, multi.SubscribeAgent(agentID))
+
+		// Clients are only peers once they sent their node.
+		peers, err := coordinator.AgentPeers(ctx, agentID)
+		require.NoError(t, err)
+		require.Empty(t, peers)
+
+		sendNode(&tailnet.Node{PreferredDERP: 1})
+		require.NoError(t, multi.UpdateSelf(&tailnet.Node{PreferredDERP: 2}))
+		require.Eventually(t, func() bool {
+			peers, err = coordinator.AgentPeers(ctx, agentID)
+			return assert.NoError(t, err) && len(peers) == 2
+		}, testutil.WaitShort, testutil.IntervalFast)
+		for _, peer := range peers {
+			switch peer.ID {
+			case clientID:
+				require.False(t, peer.Multi)
+				require.Equal(t, 1, peer.Node.PreferredDERP)
+			case multiID:
+				require.True(t, peer.Multi)
+				require.Equal(t, 2, peer.Node.PreferredDERP)
+			default:
+				t.Fatalf("unexpected peer %s", peer.ID)
+			}
+		}
+
+		require.NoError(t, client.Close())
+		require.NoError(t, server.Close())
+		<-errChan
+		<-closeChan
+		peers, err = coordinator.AgentPeers(ctx, agentID)
+		require.NoError(t, err)
+		require.Len(t, peers, 1)
+	})
 }
 
 // TestCoordinator_AgentUpdateWhileClientConnects tests for regression on
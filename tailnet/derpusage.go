@@ -0,0 +1,353 @@
+package tailnet
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// derpUsageMaxClients is the number of client addresses tracked
+	// individually. Traffic of further clients is only counted in the totals.
+	derpUsageMaxClients = 10_000
+	// derpUsageClientTTL is how long a client address without connections is
+	// remembered for once the tracker is full.
+	derpUsageClientTTL = 24 * time.Hour
+)
+
+var (
+	derpReceivedBytesDesc = prometheus.NewDesc("coderd_derp_server_received_bytes_total",
+		"The number of bytes received by the embedded DERP server from clients.",
+		[]string{"region_id"}, nil)
+	derpSentBytesDesc = prometheus.NewDesc("coderd_derp_server_sent_bytes_total",
+		"The number of bytes sent by the embedded DERP server to clients.",
+		[]string{"region_id"}, nil)
+	derpConnectionsDesc = prometheus.NewDesc("coderd_derp_server_connections",
+		"The number of open client connections to the embedded DERP server.",
+		[]string{"region_id"}, nil)
+	derpMeshReceivedBytesDesc = prometheus.NewDesc("coderd_derp_mesh_received_bytes_total",
+		"The number of bytes received from a meshed DERP server.",
+		[]string{"region_id", "mesh_address"}, nil)
+	derpMeshSentBytesDesc = prometheus.NewDesc("coderd_derp_mesh_sent_bytes_total",
+		"The number of bytes sent to a meshed DERP server.",
+		[]string{"region_id", "mesh_address"}, nil)
+)
+
+var _ prometheus.Collector = (*DERPUsage)(nil)
+
+// DERPUsage accounts for the bytes relayed by an embedded DERP server, in
+// total, per client address and per meshed DERP server. It's a Prometheus
+// collector exporting the totals and the mesh usage. Per-client usage is
+// only available from Clients, to keep the cardinality of the metrics
+// bounded.
+type DERPUsage struct {
+	regionID string
+
+	received    atomic.Int64
+	sent        atomic.Int64
+	connections atomic.Int64
+
+	mu      sync.Mutex
+	clients map[string]*derpClientUsage
+	mesh    map[string]*derpMeshUsage
+}
+
+type derpClientUsage struct {
+	received    atomic.Int64
+	sent        atomic.Int64
+	connections atomic.Int64
+	lastSeen    atomic.Int64
+}
+
+type derpMeshUsage struct {
+	received atomic.Int64
+	sent     atomic.Int64
+}
+
+// DERPClientUsage is the traffic relayed for a single client address.
+type DERPClientUsage struct {
+	Address       string
+	ReceivedBytes int64
+	SentBytes     int64
+	Connections   int64
+	LastSeen      time.Time
+}
+
+// DERPMeshUsage is the traffic exchanged with a single meshed DERP server.
+type DERPMeshUsage struct {
+	Address       string
+	ReceivedBytes int64
+	SentBytes     int64
+}
+
+// NewDERPUsage creates a tracker for the DERP server of the given region.
+func NewDERPUsage(regionID int) *DERPUsage {
+	return &DERPUsage{
+		regionID: strconv.Itoa(regionID),
+		clients:  map[string]*derpClientUsage{},
+		mesh:     map[string]*derpMeshUsage{},
+	}
+}
+
+// RegionID returns the DERP region of the tracked server.
+func (u *DERPUsage) RegionID() int {
+	id, _ := strconv.Atoi(u.regionID)
+	return id
+}
+
+// Totals returns the bytes received from and sent to clients, and the number
+// of open client connections.
+func (u *DERPUsage) Totals() (received, sent, connections int64) {
+	return u.received.Load(), u.sent.Load(), u.connections.Load()
+}
+
+// Handler wraps a DERP handler to count the bytes of the connections it
+// hijacks.
+func (u *DERPUsage) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		hijacker, ok := rw.(http.Hijacker)
+		if !ok {
+			next.ServeHTTP(rw, r)
+			return
+		}
+		next.ServeHTTP(&derpUsageResponseWriter{
+			ResponseWriter: rw,
+			hijacker:       hijacker,
+			usage:          u,
+			address:        derpClientAddress(r.RemoteAddr),
+		}, r)
+	})
+}
+
+// MeshDialer returns a dialer for the mesh client of the given address that
+// counts the bytes exchanged with it.
+func (u *DERPUsage) MeshDialer(address string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	u.mu.Lock()
+	mesh, ok := u.mesh[address]
+	if !ok {
+		mesh = &derpMeshUsage{}
+		u.mesh[address] = mesh
+	}
+	u.mu.Unlock()
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &countingConn{
+			Conn: conn,
+			onRead: func(n int) {
+				mesh.received.Add(int64(n))
+			},
+			onWrite: func(n int) {
+				mesh.sent.Add(int64(n))
+			},
+		}, nil
+	}
+}
+
+// Clients returns the usage of every tracked client address, largest first.
+func (u *DERPUsage) Clients() []DERPClientUsage {
+	u.mu.Lock()
+	clients := make([]DERPClientUsage, 0, len(u.clients))
+	for address, client := range u.clients {
+		clients = append(clients, DERPClientUsage{
+			Address:       address,
+			ReceivedBytes: client.received.Load(),
+			SentBytes:     client.sent.Load(),
+			Connections:   client.connections.Load(),
+			LastSeen:      time.Unix(0, client.lastSeen.Load()),
+		})
+	}
+	u.mu.Unlock()
+
+	sort.Slice(clients, func(i, j int) bool {
+		a, b := clients[i], clients[j]
+		if a.ReceivedBytes+a.SentBytes != b.ReceivedBytes+b.SentBytes {
+			return a.ReceivedBytes+a.SentBytes > b.ReceivedBytes+b.SentBytes
+		}
+		return a.Address < b.Address
+	})
+	return clients
+}
+
+// Mesh returns the usage of every meshed DERP server, sorted by address.
+func (u *DERPUsage) Mesh() []DERPMeshUsage {
+	u.mu.Lock()
+	mesh := make([]DERPMeshUsage, 0, len(u.mesh))
+	for address, m := range u.mesh {
+		mesh = append(mesh, DERPMeshUsage{
+			Address:       address,
+			ReceivedBytes: m.received.Load(),
+			SentBytes:     m.sent.Load(),
+		})
+	}
+	u.mu.Unlock()
+
+	sort.Slice(mesh, func(i, j int) bool {
+		return mesh[i].Address < mesh[j].Address
+	})
+	return mesh
+}
+
+func (u *DERPUsage) Describe(descs chan<- *prometheus.Desc) {
+	descs <- derpReceivedBytesDesc
+	descs <- derpSentBytesDesc
+	descs <- derpConnectionsDesc
+	descs <- derpMeshReceivedBytesDesc
+	descs <- derpMeshSentBytesDesc
+}
+
+func (u *DERPUsage) Collect(metrics chan<- prometheus.Metric) {
+	metrics <- prometheus.MustNewConstMetric(derpReceivedBytesDesc, prometheus.CounterValue, float64(u.received.Load()), u.regionID)
+	metrics <- prometheus.MustNewConstMetric(derpSentBytesDesc, prometheus.CounterValue, float64(u.sent.Load()), u.regionID)
+	metrics <- prometheus.MustNewConstMetric(derpConnectionsDesc, prometheus.GaugeValue, float64(u.connections.Load()), u.regionID)
+	for _, mesh := range u.Mesh() {
+		metrics <- prometheus.MustNewConstMetric(derpMeshReceivedBytesDesc, prometheus.CounterValue, float64(mesh.ReceivedBytes), u.regionID, mesh.Address)
+		metrics <- prometheus.MustNewConstMetric(derpMeshSentBytesDesc, prometheus.CounterValue, float64(mesh.SentBytes), u.regionID, mesh.Address)
+	}
+}
+
+// client returns the usage of the client address, or nil if the tracker is
+// full.
+func (u *DERPUsage) client(address string) *derpClientUsage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	client, ok := u.clients[address]
+	if ok {
+		return client
+	}
+	if len(u.clients) >= derpUsageMaxClients {
+		cutoff := time.Now().Add(-derpUsageClientTTL).UnixNano()
+		for address, client := range u.clients {
+			if client.connections.Load() == 0 && client.lastSeen.Load() < cutoff {
+				delete(u.clients, address)
+			}
+		}
+		if len(u.clients) >= derpUsageMaxClients {
+			return nil
+		}
+	}
+	client = &derpClientUsage{}
+	u.clients[address] = client
+	return client
+}
+
+func (u *DERPUsage) countConn(conn net.Conn, address string) net.Conn {
+	client := u.client(address)
+	u.connections.Add(1)
+	if client != nil {
+		client.connections.Add(1)
+		client.lastSeen.Store(time.Now().UnixNano())
+	}
+	return &countingConn{
+		Conn: conn,
+		onRead: func(n int) {
+			u.received.Add(int64(n))
+			if client != nil {
+				client.received.Add(int64(n))
+				client.lastSeen.Store(time.Now().UnixNano())
+			}
+		},
+		onWrite: func(n int) {
+			u.sent.Add(int64(n))
+			if client != nil {
+				client.sent.Add(int64(n))
+			}
+		},
+		onClose: func() {
+			u.connections.Add(-1)
+			if client != nil {
+				client.connections.Add(-1)
+				client.lastSeen.Store(time.Now().UnixNano())
+			}
+		},
+	}
+}
+
+func derpClientAddress(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+type derpUsageResponseWriter struct {
+	http.ResponseWriter
+	hijacker http.Hijacker
+	usage    *DERPUsage
+	address  string
+}
+
+// Hijack counts the bytes of the hijacked connection. Data already buffered
+// by the HTTP server is counted as received and handed to the new reader.
+func (w *derpUsageResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := w.hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	counted := w.usage.countConn(conn, w.address)
+
+	var reader io.Reader = counted
+	if n := brw.Reader.Buffered(); n > 0 {
+		buffered, err := brw.Reader.Peek(n)
+		if err != nil {
+			_ = conn.Close()
+			return nil, nil, xerrors.Errorf("read buffered data: %w", err)
+		}
+		w.usage.received.Add(int64(n))
+		if client := w.usage.client(w.address); client != nil {
+			client.received.Add(int64(n))
+		}
+		reader = io.MultiReader(bytes.NewReader(bytes.Clone(buffered)), counted)
+	}
+	return counted, bufio.NewReadWriter(bufio.NewReader(reader), bufio.NewWriter(counted)), nil
+}
+
+// countingConn reports the bytes read from and written to a connection.
+type countingConn struct {
+	net.Conn
+	onRead  func(n int)
+	onWrite func(n int)
+	onClose func()
+
+	closeOnce sync.Once
+}
+
+func (c *countingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.onRead(n)
+	}
+	return n, err
+}
+
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.onWrite(n)
+	}
+	return n, err
+}
+
+func (c *countingConn) Close() error {
+	if c.onClose != nil {
+		c.closeOnce.Do(c.onClose)
+	}
+	return c.Conn.Close()
+}